@@ -1,8 +1,18 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/config"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers"
 	"github.com/hsn0918/kubernetes-mcp/pkg/health"
@@ -11,16 +21,90 @@ import (
 )
 
 func NewServerCommand(cfg *config.Config) *cobra.Command {
+	// 配置加载器在解析命令行参数后创建，SIGHUP时用于重新加载配置文件
+	var loader *config.Loader
+
 	serverCmd := &cobra.Command{
 		Use:   "server",
 		Short: "Start the MCP server",
 		Long:  `Start the Model Capable Protocol (MCP) server for Kubernetes operations.`,
+		// 解析完命令行参数后再加载配置文件和创建客户端，使kubeconfig、客户端限流和缓存等参数生效
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// 配置错误和连接失败都不是参数用法错误，不打印帮助信息
+			cmd.SilenceUsage = true
+
+			// 命令行显式指定的参数优先于环境变量和配置文件
+			path := cfg.ConfigFile
+			if path == "" {
+				path = os.Getenv(config.ConfigFileEnv)
+			}
+			loader = config.NewLoader(cfg, path, cmd.Flags().Changed)
+			loaded, err := loader.Load()
+			if err != nil {
+				return err
+			}
+			*cfg = *loaded
+			config.Store(cfg)
+
+			// 子命令的钩子会覆盖根命令的PersistentPreRun，这里同样更新日志配置
+			logger.InitializeDefaultLogger(cfg.LogLevel, cfg.LogFormat)
+			if cfg.ConfigFile != "" {
+				logger.GetLogger().Info("Loaded config file", "path", cfg.ConfigFile)
+			}
+			return kubernetes.InitializeDefaultClient(cfg)
+		},
 	}
 
 	// 添加共享标志到父命令
+	serverCmd.PersistentFlags().StringVar(&cfg.ConfigFile, "config", cfg.ConfigFile, "YAML config file, also read from $"+config.ConfigFileEnv+"; command line flags override environment variables ("+config.EnvPrefix+"<FLAG_NAME>), which override the file; send SIGHUP to reload log level, read-only mode, allowed namespaces and output budgets")
 	serverCmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "Log level (debug, info, warn, error)")
 	serverCmd.PersistentFlags().StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "Log format (console, json)")
+	serverCmd.PersistentFlags().StringVar(&cfg.HealthListenAddr, "health-listen-addr", cfg.HealthListenAddr, "Address of the /healthz and /readyz listener (e.g. :8081), overrides --health-port; empty uses --health-port for sse and streamable and disables the listener for stdio")
+	serverCmd.PersistentFlags().DurationVar(&cfg.ReadinessPingInterval, "readiness-ping-interval", cfg.ReadinessPingInterval, "Interval of the background Kubernetes API ping used by /readyz, failures back off exponentially, 0 disables the ping")
+	serverCmd.PersistentFlags().DurationVar(&cfg.ReadinessStaleAfter, "readiness-stale-after", cfg.ReadinessStaleAfter, "/readyz reports not ready when the Kubernetes API has not been reachable for this long")
+	serverCmd.PersistentFlags().DurationVar(&cfg.ShutdownDrainTimeout, "shutdown-drain-timeout", cfg.ShutdownDrainTimeout, "On SIGTERM or SIGINT, how long to wait for in-flight tool calls before exiting")
 	serverCmd.PersistentFlags().StringVar(&cfg.Kubeconfig, "kubeconfig", cfg.Kubeconfig, "Path to kubeconfig file")
+	serverCmd.PersistentFlags().Float32Var(&cfg.ClientQPS, "client-qps", cfg.ClientQPS, "Sustained requests per second the Kubernetes client may send before client-side throttling")
+	serverCmd.PersistentFlags().IntVar(&cfg.ClientBurst, "client-burst", cfg.ClientBurst, "Maximum burst of requests the Kubernetes client may send above --client-qps")
+	serverCmd.PersistentFlags().DurationVar(&cfg.ClientRequestTimeout, "client-request-timeout", cfg.ClientRequestTimeout, "Timeout of a single Kubernetes API request, including watches, log streams and exec sessions, 0 disables the timeout; keep it at least --max-tool-timeout-seconds")
+	serverCmd.PersistentFlags().BoolVar(&cfg.LogClientThrottling, "log-client-throttling", cfg.LogClientThrottling, "Log Kubernetes API requests delayed by client-side rate limiting")
+	serverCmd.PersistentFlags().BoolVar(&cfg.ReadOnly, "read-only", cfg.ReadOnly, "Refuse tool calls that change the cluster (create, update, delete, apply, scale, exec-based copies and so on) unless the tool supports server-side dry run and the call passes dryRun=true")
+	serverCmd.PersistentFlags().StringVar(&cfg.AllowedNamespaces, "allowed-namespaces", cfg.AllowedNamespaces, "Comma separated namespaces the server may access, empty allows all; every API request is checked, including calls that omit the namespace, manifests applied by APPLY_* and MCP resources, and listings across all namespaces are refused")
+	serverCmd.PersistentFlags().BoolVar(&cfg.PreflightAuthz, "preflight-authz", cfg.PreflightAuthz, "Check permissions with SelfSubjectAccessReview before mutating operations")
+	serverCmd.PersistentFlags().BoolVar(&cfg.AllowSecretValues, "allow-secret-values", cfg.AllowSecretValues, "Allow GET_SECRET_KEYS to return secret values when the caller passes revealValues=true")
+	serverCmd.PersistentFlags().BoolVar(&cfg.AllowExec, "allow-exec", cfg.AllowExec, "Allow tools that execute commands inside containers (LIST_POD_FILES, READ_POD_FILE, COPY_TO_POD, COPY_FROM_POD, DEBUG_POD, CHECK_DNS lookups)")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxCopyBytes, "max-copy-bytes", cfg.MaxCopyBytes, "Maximum size in bytes of a single file copied with COPY_TO_POD or COPY_FROM_POD")
+	serverCmd.PersistentFlags().StringVar(&cfg.BackupDir, "backup-dir", cfg.BackupDir, "Server-local directory for BACKUP_NAMESPACE output that exceeds the inline limit")
+	serverCmd.PersistentFlags().IntVar(&cfg.BackupInlineLimit, "backup-inline-limit", cfg.BackupInlineLimit, "Maximum size in bytes of a backup manifest returned inline")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxListItems, "max-list-items", cfg.MaxListItems, "Hard maximum number of items returned by a single LIST tool call")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxResultBytes, "max-result-bytes", cfg.MaxResultBytes, "Maximum size in bytes of a single tool result, larger results are truncated by content (list items, YAML head, log tail), 0 disables the limit")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxRetries, "max-retries", cfg.MaxRetries, "Maximum number of retries for transient API errors and opted-in update conflicts")
+	serverCmd.PersistentFlags().DurationVar(&cfg.RetryInitialBackoff, "retry-initial-backoff", cfg.RetryInitialBackoff, "Wait before the first retry, doubled on each subsequent retry")
+	serverCmd.PersistentFlags().DurationVar(&cfg.RetryMaxBackoff, "retry-max-backoff", cfg.RetryMaxBackoff, "Upper bound for a single retry wait")
+	serverCmd.PersistentFlags().IntVar(&cfg.ToolTimeoutSeconds, "tool-timeout-seconds", cfg.ToolTimeoutSeconds, "Default timeout in seconds for a single tool call, 0 disables the default timeout")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxToolTimeoutSeconds, "max-tool-timeout-seconds", cfg.MaxToolTimeoutSeconds, "Hard cap in seconds for the timeoutSeconds argument of a tool call")
+	serverCmd.PersistentFlags().DurationVar(&cfg.DiscoveryCacheTTL, "discovery-cache-ttl", cfg.DiscoveryCacheTTL, "How long API discovery results are cached, 0 disables caching")
+	serverCmd.PersistentFlags().DurationVar(&cfg.NamespaceCacheTTL, "namespace-cache-ttl", cfg.NamespaceCacheTTL, "How long the namespace list is cached, 0 disables caching")
+	serverCmd.PersistentFlags().DurationVar(&cfg.NodeCacheTTL, "node-cache-ttl", cfg.NodeCacheTTL, "How long the node list is cached, 0 disables caching")
+	serverCmd.PersistentFlags().DurationVar(&cfg.CRDCacheTTL, "crd-cache-ttl", cfg.CRDCacheTTL, "How long the CRD list is cached, 0 disables caching")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxConcurrentTools, "max-concurrent-tools", cfg.MaxConcurrentTools, "Maximum number of tool calls executing at once, 0 disables the limit")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxConcurrentExpensiveTools, "max-concurrent-expensive-tools", cfg.MaxConcurrentExpensiveTools, "Maximum number of expensive tool calls (search, backup, watch, metrics, logs) executing at once, 0 disables the limit")
+	serverCmd.PersistentFlags().DurationVar(&cfg.ConcurrencyQueueTimeout, "concurrency-queue-timeout", cfg.ConcurrencyQueueTimeout, "How long a tool call waits for a free slot before returning a server busy error")
+	serverCmd.PersistentFlags().StringVar(&cfg.NamespacePresetsFile, "namespace-presets", cfg.NamespacePresetsFile, "YAML file of ResourceQuota/LimitRange presets for CREATE_NAMESPACE, merged over the built-in small/medium/large presets")
+	serverCmd.PersistentFlags().StringVar(&cfg.KustomizeRoot, "kustomize-root", cfg.KustomizeRoot, "Server-local directory under which APPLY_KUSTOMIZATION and RENDER_KUSTOMIZATION may build kustomizations by path, empty disables building from a path")
+	serverCmd.PersistentFlags().StringVar(&cfg.ManifestURLPrefixes, "manifest-url-prefixes", cfg.ManifestURLPrefixes, "Comma separated https URL prefixes APPLY_FROM_URL may fetch manifests from (e.g. https://raw.githubusercontent.com/my-org/), matched on exact scheme and host and on whole path segments, empty disables fetching manifests from URLs")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxManifestURLBytes, "max-manifest-url-bytes", cfg.MaxManifestURLBytes, "Maximum size in bytes of a manifest fetched by APPLY_FROM_URL")
+	serverCmd.PersistentFlags().StringVar(&cfg.ResourceKinds, "resource-kinds", cfg.ResourceKinds, "Comma separated kinds exposed as MCP resources under k8s://{namespace}/{kind}/{name}")
+	serverCmd.PersistentFlags().StringVar(&cfg.ResourceNamespaces, "resource-namespaces", cfg.ResourceNamespaces, "Comma separated namespaces exposed as MCP resources, empty exposes all namespaces")
+	serverCmd.PersistentFlags().IntVar(&cfg.ResourceMaxBytes, "resource-max-bytes", cfg.ResourceMaxBytes, "Maximum size in bytes of a single MCP resource read, object YAML or pod logs")
+	serverCmd.PersistentFlags().StringVar(&cfg.EnabledToolGroups, "enabled-tool-groups", cfg.EnabledToolGroups, "Comma separated tool groups to register (core, apps, batch, networking.k8s.io, rbac.authorization.k8s.io, storage.k8s.io, apiextensions.k8s.io, policy, autoscaling, tool, prompt, metrics, resource), empty registers all groups")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxNotes, "max-notes", cfg.MaxNotes, "Maximum number of SAVE_NOTE notes kept per MCP session, the oldest note is evicted beyond this")
+	serverCmd.PersistentFlags().IntVar(&cfg.MaxNoteBytes, "max-note-bytes", cfg.MaxNoteBytes, "Maximum size in bytes of a single session note")
+	serverCmd.PersistentFlags().StringVar(&cfg.NotesFile, "notes-file", cfg.NotesFile, "File to persist session notes across restarts, empty keeps notes in memory only")
+	serverCmd.PersistentFlags().IntVar(&cfg.ToolHistorySize, "tool-history-size", cfg.ToolHistorySize, "Number of recent tool calls kept in memory per MCP session for GET_TOOL_HISTORY, 0 disables recording")
+	serverCmd.PersistentFlags().StringVar(&cfg.DisabledTools, "disabled-tools", cfg.DisabledTools, "Comma separated tools or tool groups not to register, supports globs such as GET_* or DELETE_*")
+	serverCmd.PersistentFlags().StringVar(&cfg.ToolNaming, "tool-naming", cfg.ToolNaming, "Naming of the generic resource tools: flat (default) registers a single set for all groups (LIST_RESOURCES, GET_RESOURCE, ...), prefixed registers one set per API group (LIST_CORE_RESOURCES, LIST_APPS_RESOURCES, ...)")
+	serverCmd.PersistentFlags().BoolVar(&cfg.LegacyToolAliases, "legacy-tool-aliases", cfg.LegacyToolAliases, "With --tool-naming=flat, also register the per-group tool names (LIST_CORE_RESOURCES, ...) as deprecated aliases of the flat tools for clients that still call them; set --legacy-tool-aliases=false to register only the flat names")
 
 	// 创建传输子命令
 	transportCmd := &cobra.Command{
@@ -36,27 +120,7 @@ func NewServerCommand(cfg *config.Config) *cobra.Command {
 		Long:  `Use Server-Sent Events (SSE) as the transport mechanism for the MCP server.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg.Transport = "sse"
-			log := logger.GetLogger()
-			health.StartHealthServer(cfg.HealthPort, log)
-
-			log.Info("Starting MCP server", "transport", cfg.Transport, "port", cfg.Port)
-			// 创建处理程序提供者
-			handlerProvider := handlers.NewHandlerProvider()
-
-			// 创建服务器
-			serverFactory := server.NewServerFactory(handlerProvider)
-			server, err := serverFactory.CreateServer(cfg)
-			if err != nil {
-				return err
-			}
-			health.SetReady()
-			err = server.Start()
-			if err != nil {
-				health.SetNotReady()
-				return err
-			}
-			// 启动服务器
-			return nil
+			return serve(cmd.Context(), cfg, loader)
 		},
 	}
 
@@ -67,27 +131,7 @@ func NewServerCommand(cfg *config.Config) *cobra.Command {
 		Long:  `Use StreamableHTTP as the transport mechanism for the MCP server. This mode supports streaming operations and progress notifications.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg.Transport = "streamable"
-			log := logger.GetLogger()
-			health.StartHealthServer(cfg.HealthPort, log)
-
-			log.Info("Starting MCP server", "transport", cfg.Transport, "port", cfg.Port)
-			// 创建处理程序提供者
-			handlerProvider := handlers.NewHandlerProvider()
-
-			// 创建服务器
-			serverFactory := server.NewServerFactory(handlerProvider)
-			server, err := serverFactory.CreateServer(cfg)
-			if err != nil {
-				return err
-			}
-			health.SetReady()
-			err = server.Start()
-			if err != nil {
-				health.SetNotReady()
-				return err
-			}
-			// 启动服务器
-			return nil
+			return serve(cmd.Context(), cfg, loader)
 		},
 	}
 
@@ -98,24 +142,7 @@ func NewServerCommand(cfg *config.Config) *cobra.Command {
 		Long:  `Use standard input/output as the transport mechanism for the MCP server.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg.Transport = "stdio"
-			log := logger.GetLogger()
-
-			log.Info("Starting MCP server", "transport", cfg.Transport)
-			// 创建处理程序提供者
-			handlerProvider := handlers.NewHandlerProvider()
-
-			// 创建服务器
-			serverFactory := server.NewServerFactory(handlerProvider)
-			server, err := serverFactory.CreateServer(cfg)
-			if err != nil {
-				return err
-			}
-			err = server.Start()
-			if err != nil {
-				return err
-			}
-			// 启动服务器
-			return nil
+			return serve(cmd.Context(), cfg, loader)
 		},
 	}
 
@@ -140,3 +167,129 @@ func NewServerCommand(cfg *config.Config) *cobra.Command {
 
 	return serverCmd
 }
+
+// serve 启动MCP服务器、健康检查监听和Kubernetes API探测，收到SIGHUP时重新加载配置文件，收到SIGTERM或SIGINT后优雅关闭：
+// 先标记未就绪并拒绝新的工具调用，在ShutdownDrainTimeout内等待进行中的调用结束，再关闭监听
+func serve(ctx context.Context, cfg *config.Config, loader *config.Loader) error {
+	log := logger.GetLogger()
+
+	healthAddr := cfg.HealthListenAddr
+	if healthAddr == "" && cfg.Transport != "stdio" {
+		healthAddr = ":" + strconv.Itoa(cfg.HealthPort)
+	}
+	var healthServer *http.Server
+	if healthAddr != "" {
+		healthServer = health.StartHealthServer(healthAddr, log)
+	}
+
+	if cfg.Transport == "stdio" {
+		log.Info("Starting MCP server", "transport", cfg.Transport)
+	} else {
+		log.Info("Starting MCP server", "transport", cfg.Transport, "port", cfg.Port)
+	}
+	// 创建处理程序提供者
+	handlerProvider := handlers.NewHandlerProvider(cfg)
+
+	// 创建服务器
+	serverFactory := server.NewServerFactory(handlerProvider)
+	mcpServer, err := serverFactory.CreateServer(cfg)
+	if err != nil {
+		return err
+	}
+
+	signalCtx, stopSignals := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stopSignals()
+
+	pingCtx, stopPing := context.WithCancel(context.Background())
+	defer stopPing()
+	if healthServer != nil && cfg.ReadinessPingInterval > 0 {
+		health.StartAPIPinger(pingCtx, pingKubernetesAPI, cfg.ReadinessPingInterval, cfg.ReadinessStaleAfter, log)
+	}
+
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	defer signal.Stop(reloadCh)
+	go func() {
+		for {
+			select {
+			case <-reloadCh:
+				reloadConfig(loader, log)
+			case <-pingCtx.Done():
+				return
+			}
+		}
+	}()
+
+	// 启动服务器
+	health.SetReady()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- mcpServer.Start()
+	}()
+
+	select {
+	case err = <-errCh:
+		// 服务器自行退出（如stdio的输入关闭），没有需要等待的调用
+		health.SetNotReady()
+	case <-signalCtx.Done():
+		log.Info("Shutdown signal received, draining in-flight tool calls", "timeout", cfg.ShutdownDrainTimeout.String())
+		health.SetNotReady()
+		drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownDrainTimeout)
+		defer cancel()
+		if stopErr := mcpServer.Stop(drainCtx); stopErr != nil {
+			log.Warn("MCP server did not stop cleanly", "error", stopErr)
+		}
+		// Start在监听关闭后返回，此时的错误只是关闭造成的
+		select {
+		case <-errCh:
+		case <-drainCtx.Done():
+		}
+	}
+
+	if healthServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if shutdownErr := healthServer.Shutdown(shutdownCtx); shutdownErr != nil {
+			log.Warn("Health check server did not stop cleanly", "error", shutdownErr)
+		}
+	}
+	log.Info("MCP server stopped")
+	return err
+}
+
+// reloadConfig 重新读取配置文件，应用日志级别、只读模式、命名空间白名单和输出上限。
+// 读取或校验失败时保持当前配置，其他设置改变时只记录需要重启
+func reloadConfig(loader *config.Loader, log logger.Logger) {
+	if loader == nil || loader.Path() == "" {
+		log.Warn("SIGHUP received but no config file is in use, nothing to reload")
+		return
+	}
+	next, restartRequired, err := loader.Reload(config.Current())
+	if err != nil {
+		log.Error("Failed to reload config file, keeping the current configuration", "path", loader.Path(), "error", err)
+		return
+	}
+	config.Store(next)
+	logger.SetLevel(next.LogLevel)
+	handlers.ApplyReloadedConfig(next)
+	log.Info("Config file reloaded",
+		"path", loader.Path(),
+		"logLevel", next.LogLevel,
+		"readOnly", next.ReadOnly,
+		"allowedNamespaces", next.AllowedNamespaces,
+		"maxResultBytes", next.MaxResultBytes,
+		"maxListItems", next.MaxListItems,
+	)
+	if len(restartRequired) > 0 {
+		log.Warn("Changed settings take effect only after a restart", "settings", restartRequired)
+	}
+}
+
+// pingKubernetesAPI 通过Discovery客户端请求/version探测Kubernetes API是否可达，不经过Discovery缓存
+func pingKubernetesAPI(ctx context.Context) error {
+	client := kubernetes.GetClient()
+	if client == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	return client.ClientSet().Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Error()
+}
@@ -4,16 +4,10 @@ import (
 	"fmt"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/version"
 	"github.com/spf13/cobra"
 )
 
-// 版本信息
-var (
-	Version   = "0.1.0"
-	Commit    = "none"
-	BuildDate = "unknown"
-)
-
 // Logo ASCII 艺术字
 const logo = `
  ██ ▄█▀ █    ██  ▄▄▄▄   ▓█████  ███▄ ▄███▓ ▄████▄   ██▓███
@@ -40,14 +34,14 @@ func NewVersionCommand() *cobra.Command {
 
 			// 打印版本信息
 			versionInfo := fmt.Sprintf("Kubernetes-MCP version %s (commit: %s, build date: %s)\n",
-				Version, Commit, BuildDate)
+				version.Version, version.Commit, version.BuildDate)
 
 			fmt.Print(versionInfo)
 
 			log.Info("Version info displayed",
-				"version", Version,
-				"commit", Commit,
-				"buildDate", BuildDate,
+				"version", version.Version,
+				"commit", version.Commit,
+				"buildDate", version.BuildDate,
 			)
 		},
 	}
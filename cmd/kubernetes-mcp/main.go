@@ -4,7 +4,6 @@ import (
 	"os"
 
 	"github.com/hsn0918/kubernetes-mcp/cmd/kubernetes-mcp/app"
-	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/config"
 	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
 )
@@ -17,12 +16,6 @@ func main() {
 	logger.InitializeDefaultLogger(cfg.LogLevel, cfg.LogFormat)
 	log := logger.GetLogger()
 
-	// 初始化客户端
-	if err := kubernetes.InitializeDefaultClient(cfg); err != nil {
-		log.Error("Failed to initialize Kubernetes client", "error", err)
-		os.Exit(1)
-	}
-
 	// 创建命令行应用
 	rootCmd := app.NewRootCommand(cfg)
 
@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+)
+
+// 缓存键
+const (
+	// KeyNamespaces 集群中的命名空间列表
+	KeyNamespaces = "namespaces"
+	// KeyNodes 集群中的节点列表
+	KeyNodes = "nodes"
+	// KeyCRDs 集群中的CRD列表
+	KeyCRDs = "crds"
+)
+
+// KeyForKind 返回修改指定类型的对象后需要失效的缓存键，与缓存无关的类型返回空
+func KeyForKind(kind string) string {
+	switch kind {
+	case "Namespace":
+		return KeyNamespaces
+	case "Node":
+		return KeyNodes
+	case "CustomResourceDefinition":
+		return KeyCRDs
+	}
+	return ""
+}
+
+// Stats 单个缓存项的状态和命中统计
+type Stats struct {
+	Key    string `json:"key"`
+	TTL    string `json:"ttl"`
+	Cached bool   `json:"cached"`
+	// Age 缓存值自加载以来的时长，未缓存时为空
+	Age    string `json:"age,omitempty"`
+	Hits   int64  `json:"hits"`
+	Misses int64  `json:"misses"`
+}
+
+// entry 单个缓存项
+type entry struct {
+	value    any
+	loaded   bool
+	loadedAt time.Time
+	// generation 每次失效时递增，加载期间缓存项被失效时丢弃加载结果，避免写入过期的值
+	generation uint64
+	hits       int64
+	misses     int64
+}
+
+// Cache 会话内基本不变的查询结果（命名空间、节点、CRD列表）的过期缓存，可被多个处理程序并发使用。
+// 每个键可以配置独立的过期时间，过期时间不大于0时不缓存该键
+type Cache struct {
+	mu         sync.Mutex
+	defaultTTL time.Duration
+	ttls       map[string]time.Duration
+	entries    map[string]*entry
+	log        logger.Logger
+}
+
+// New 创建缓存，ttls为各键的过期时间，未配置的键使用defaultTTL
+func New(defaultTTL time.Duration, ttls map[string]time.Duration) *Cache {
+	c := &Cache{
+		defaultTTL: defaultTTL,
+		ttls:       ttls,
+		entries:    make(map[string]*entry),
+		log:        logger.GetLogger(),
+	}
+	// 预先创建已配置的键，尚未访问时也能在统计中看到
+	for key := range ttls {
+		c.entry(key)
+	}
+	return c
+}
+
+// TTL 返回键的过期时间
+func (c *Cache) TTL(key string) time.Duration {
+	if ttl, ok := c.ttls[key]; ok {
+		return ttl
+	}
+	return c.defaultTTL
+}
+
+// Get 返回键的缓存值，缓存不存在或已过期时调用load加载并缓存。
+// 并发访问同一个键时可能同时加载，结果相同；返回的值被多个调用方共享，调用方不得修改
+func Get[T any](ctx context.Context, c *Cache, key string, load func(context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+	current := c.entry(key)
+	if current.loaded && time.Since(current.loadedAt) <= c.TTL(key) {
+		current.hits++
+		value, hits := current.value.(T), current.hits
+		c.mu.Unlock()
+		c.log.Debug("Cache hit", "key", key, "hits", hits)
+		return value, nil
+	}
+	current.misses++
+	generation, misses := current.generation, current.misses
+	c.mu.Unlock()
+	c.log.Debug("Cache miss", "key", key, "misses", misses)
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current.generation == generation && c.TTL(key) > 0 {
+		current.value = value
+		current.loaded = true
+		current.loadedAt = time.Now()
+	}
+	return value, nil
+}
+
+// Invalidate 使名称匹配pattern的缓存项失效，pattern为path.Match形式的通配符，为空时匹配所有键。
+// 返回失效前有缓存值的键
+func (c *Cache) Invalidate(pattern string) ([]string, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid cache key pattern %q: %w", pattern, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var invalidated []string
+	for key, current := range c.entries {
+		if matched, _ := path.Match(pattern, key); !matched {
+			continue
+		}
+		if current.loaded {
+			invalidated = append(invalidated, key)
+		}
+		current.generation++
+		current.loaded = false
+		current.value = nil
+	}
+	sort.Strings(invalidated)
+	if len(invalidated) > 0 {
+		c.log.Debug("Cache invalidated", "pattern", pattern, "keys", invalidated)
+	}
+	return invalidated, nil
+}
+
+// InvalidateKind 创建、修改或删除指定类型的对象后使对应的缓存项失效，与缓存无关的类型不做处理
+func (c *Cache) InvalidateKind(kind string) {
+	if key := KeyForKind(kind); key != "" {
+		_, _ = c.Invalidate(key)
+	}
+}
+
+// Stats 返回所有已配置或访问过的键的状态，按键排序
+func (c *Cache) Stats() []Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make([]Stats, 0, len(c.entries))
+	for key, current := range c.entries {
+		item := Stats{
+			Key:    key,
+			TTL:    c.TTL(key).String(),
+			Cached: current.loaded && time.Since(current.loadedAt) <= c.TTL(key),
+			Hits:   current.hits,
+			Misses: current.misses,
+		}
+		if item.Cached {
+			item.Age = time.Since(current.loadedAt).Round(time.Second).String()
+		}
+		stats = append(stats, item)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+// KeyStats 返回单个键的状态
+func (c *Cache) KeyStats(key string) Stats {
+	for _, item := range c.Stats() {
+		if item.Key == key {
+			return item
+		}
+	}
+	return Stats{Key: key, TTL: c.TTL(key).String()}
+}
+
+// entry 返回键对应的缓存项，不存在时创建，调用方需持有锁
+func (c *Cache) entry(key string) *entry {
+	current, ok := c.entries[key]
+	if !ok {
+		current = &entry{}
+		c.entries[key] = current
+	}
+	return current
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter 返回一个每次调用递增并返回调用次数的加载函数
+func counter() (func(context.Context) (int, error), *atomic.Int64) {
+	var loads atomic.Int64
+	return func(context.Context) (int, error) {
+		return int(loads.Add(1)), nil
+	}, &loads
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, map[string]time.Duration{KeyNodes: 0, KeyCRDs: time.Millisecond})
+	load, loads := counter()
+
+	for range 3 {
+		if value, err := Get(ctx, c, KeyNamespaces, load); err != nil || value != 1 {
+			t.Fatalf("Get = %d, %v; want the first loaded value", value, err)
+		}
+	}
+	if stats := c.KeyStats(KeyNamespaces); stats.Hits != 2 || stats.Misses != 1 || !stats.Cached {
+		t.Fatalf("stats = %+v, want 2 hits and 1 miss", stats)
+	}
+
+	// 过期时间为0的键不缓存
+	Get(ctx, c, KeyNodes, load)
+	Get(ctx, c, KeyNodes, load)
+	if stats := c.KeyStats(KeyNodes); stats.Misses != 2 || stats.Cached {
+		t.Fatalf("uncached key stats = %+v", stats)
+	}
+
+	Get(ctx, c, KeyCRDs, load)
+	time.Sleep(5 * time.Millisecond)
+	before := loads.Load()
+	Get(ctx, c, KeyCRDs, load)
+	if loads.Load() != before+1 {
+		t.Fatal("expired value was served")
+	}
+}
+
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, nil)
+	failures := 0
+	load := func(context.Context) ([]string, error) {
+		failures++
+		return nil, errors.New("connection refused")
+	}
+
+	for range 2 {
+		if _, err := Get(ctx, c, KeyNamespaces, load); err == nil {
+			t.Fatal("error was not returned")
+		}
+	}
+	if failures != 2 {
+		t.Fatalf("failed load was cached: %d loads", failures)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, map[string]time.Duration{KeyCRDs: time.Hour})
+	load, _ := counter()
+	Get(ctx, c, KeyNamespaces, load)
+	Get(ctx, c, KeyNodes, load)
+
+	if invalidated, err := c.Invalidate("n*"); err != nil || !slices.Equal(invalidated, []string{KeyNamespaces, KeyNodes}) {
+		t.Fatalf("Invalidate = %v, %v", invalidated, err)
+	}
+	if invalidated, _ := c.Invalidate(""); len(invalidated) != 0 {
+		t.Fatalf("invalidated %v, want nothing cached", invalidated)
+	}
+	if _, err := c.Invalidate("["); err == nil {
+		t.Fatal("invalid pattern was accepted")
+	}
+
+	Get(ctx, c, KeyNodes, load)
+	c.InvalidateKind("Node")
+	c.InvalidateKind("Pod")
+	if c.KeyStats(KeyNodes).Cached {
+		t.Fatal("changing a Node did not invalidate the node list")
+	}
+	if keys := c.Stats(); len(keys) != 3 || keys[0].Key != KeyCRDs {
+		t.Fatalf("stats = %+v, want the configured and accessed keys in order", keys)
+	}
+}
+
+func TestInvalidateDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, nil)
+	started, release := make(chan struct{}), make(chan struct{})
+
+	done := make(chan int)
+	go func() {
+		value, _ := Get(ctx, c, KeyNamespaces, func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		done <- value
+	}()
+	<-started
+	c.Invalidate(KeyNamespaces)
+	close(release)
+	if value := <-done; value != 1 {
+		t.Fatalf("in-flight Get = %d, want its own loaded value", value)
+	}
+
+	// 加载期间被失效的结果不写入缓存
+	value, _ := Get(ctx, c, KeyNamespaces, func(context.Context) (int, error) { return 2, nil })
+	if value != 2 {
+		t.Fatalf("Get after invalidation = %d, want a fresh load", value)
+	}
+}
+
+func TestConcurrentGetAndInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Hour, nil)
+	load, loads := counter()
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				key := []string{KeyNamespaces, KeyNodes, KeyCRDs}[(i+j)%3]
+				if value, err := Get(ctx, c, key, load); err != nil || value < 1 {
+					t.Errorf("Get(%s) = %d, %v", key, value, err)
+					return
+				}
+				if j%50 == 0 {
+					c.Invalidate(key)
+				}
+				c.Stats()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var hits, misses int64
+	for _, stats := range c.Stats() {
+		hits += stats.Hits
+		misses += stats.Misses
+	}
+	if hits+misses != 16*200 || misses != loads.Load() {
+		t.Fatalf("hits %d, misses %d, loads %d; want every Get counted once and one load per miss", hits, misses, loads.Load())
+	}
+}
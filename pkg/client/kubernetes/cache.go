@@ -0,0 +1,210 @@
+package kubernetes
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/cache"
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+)
+
+// CacheStats 记录 Discovery 与查询缓存的命中情况。
+type CacheStats struct {
+	// DiscoveryLoadedAt Discovery缓存上次从 API Server 加载的时间，尚未加载时为零值
+	DiscoveryLoadedAt time.Time `json:"discoveryLoadedAt"`
+	DiscoveryHits     int64     `json:"discoveryHits"`
+	DiscoveryMisses   int64     `json:"discoveryMisses"`
+	NamespaceHits     int64     `json:"namespaceHits"`
+	NamespaceMisses   int64     `json:"namespaceMisses"`
+	// Entries 命名空间、节点、CRD 等查询缓存各项的状态
+	Entries []cache.Stats `json:"entries"`
+}
+
+// cachedDiscoveryClient 在内存缓存的 Discovery 客户端之上增加过期时间和命中统计。
+// 缓存超过 ttl 后在下一次访问时自动失效并重新从 API Server 获取。
+type cachedDiscoveryClient struct {
+	discovery.CachedDiscoveryInterface
+	ttl      time.Duration
+	log      logger.Logger
+	mu       sync.Mutex
+	loadedAt time.Time
+	hits     atomic.Int64
+	misses   atomic.Int64
+}
+
+// 编译时断言，确保 cachedDiscoveryClient 实现了 CachedDiscoveryInterface 接口。
+var _ discovery.CachedDiscoveryInterface = &cachedDiscoveryClient{}
+
+// newCachedDiscoveryClient 使用内存缓存包装 Discovery 客户端。
+func newCachedDiscoveryClient(delegate discovery.DiscoveryInterface, ttl time.Duration) *cachedDiscoveryClient {
+	return &cachedDiscoveryClient{
+		CachedDiscoveryInterface: memory.NewMemCacheClient(delegate),
+		ttl:                      ttl,
+		log:                      logger.GetLogger(),
+	}
+}
+
+// track 在访问缓存前检查过期时间，并记录本次访问是否命中缓存。
+func (c *cachedDiscoveryClient) track(op string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.CachedDiscoveryInterface.Fresh() && time.Since(c.loadedAt) > c.ttl {
+		c.log.Debug("Discovery cache expired", "age", time.Since(c.loadedAt).Round(time.Second), "ttl", c.ttl)
+		c.CachedDiscoveryInterface.Invalidate()
+	}
+	if c.CachedDiscoveryInterface.Fresh() {
+		c.log.Debug("Discovery cache hit", "op", op, "hits", c.hits.Add(1))
+		return
+	}
+	c.loadedAt = time.Now()
+	c.log.Debug("Discovery cache miss", "op", op, "misses", c.misses.Add(1))
+}
+
+// ServerGroups 返回缓存的 API 组列表。
+func (c *cachedDiscoveryClient) ServerGroups() (*metav1.APIGroupList, error) {
+	c.track("ServerGroups")
+	return c.CachedDiscoveryInterface.ServerGroups()
+}
+
+// ServerGroupsAndResources 返回缓存的 API 组及其资源列表。
+func (c *cachedDiscoveryClient) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	c.track("ServerGroupsAndResources")
+	return c.CachedDiscoveryInterface.ServerGroupsAndResources()
+}
+
+// ServerResourcesForGroupVersion 返回缓存的指定 GroupVersion 的资源列表。
+func (c *cachedDiscoveryClient) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	c.track("ServerResourcesForGroupVersion")
+	return c.CachedDiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+}
+
+// ServerPreferredResources 返回缓存的各 API 组首选版本的资源列表。
+func (c *cachedDiscoveryClient) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	c.track("ServerPreferredResources")
+	return c.CachedDiscoveryInterface.ServerPreferredResources()
+}
+
+// ServerPreferredNamespacedResources 返回缓存的各 API 组首选版本的命名空间级资源列表。
+func (c *cachedDiscoveryClient) ServerPreferredNamespacedResources() ([]*metav1.APIResourceList, error) {
+	c.track("ServerPreferredNamespacedResources")
+	return c.CachedDiscoveryInterface.ServerPreferredNamespacedResources()
+}
+
+// Invalidate 使缓存立即失效。
+func (c *cachedDiscoveryClient) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.CachedDiscoveryInterface.Invalidate()
+	c.loadedAt = time.Time{}
+}
+
+// loadedTime 返回缓存上次加载的时间，缓存无效时返回零值
+func (c *cachedDiscoveryClient) loadedTime() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.CachedDiscoveryInterface.Fresh() {
+		return time.Time{}
+	}
+	return c.loadedAt
+}
+
+// crdGVR CustomResourceDefinition 资源的 GVR。
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// ListNamespaces 返回集群中的命名空间，按名称排序，在缓存有效期内复用上一次的结果。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) ListNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
+	return cache.Get(ctx, k.cache, cache.KeyNamespaces, func(ctx context.Context) ([]corev1.Namespace, error) {
+		nsList := &corev1.NamespaceList{}
+		if err := k.client.List(ctx, nsList); err != nil {
+			return nil, err
+		}
+		sort.Slice(nsList.Items, func(i, j int) bool {
+			return nsList.Items[i].Name < nsList.Items[j].Name
+		})
+		return nsList.Items, nil
+	})
+}
+
+// ListNamespaceNames 返回集群中的命名空间名称，在缓存有效期内复用上一次的结果。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) ListNamespaceNames(ctx context.Context) ([]string, error) {
+	namespaces, err := k.ListNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+// ListNodes 返回集群中的节点，按名称排序，在缓存有效期内复用上一次的结果。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	return cache.Get(ctx, k.cache, cache.KeyNodes, func(ctx context.Context) ([]corev1.Node, error) {
+		nodeList := &corev1.NodeList{}
+		if err := k.client.List(ctx, nodeList); err != nil {
+			return nil, err
+		}
+		sort.Slice(nodeList.Items, func(i, j int) bool {
+			return nodeList.Items[i].Name < nodeList.Items[j].Name
+		})
+		return nodeList.Items, nil
+	})
+}
+
+// ListCRDs 返回集群中的 CRD，在缓存有效期内复用上一次的结果。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) ListCRDs(ctx context.Context) ([]unstructured.Unstructured, error) {
+	return cache.Get(ctx, k.cache, cache.KeyCRDs, func(ctx context.Context) ([]unstructured.Unstructured, error) {
+		list, err := k.dynamicClient.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+}
+
+// Cache 返回命名空间、节点、CRD 列表共用的缓存。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) Cache() *cache.Cache {
+	return k.cache
+}
+
+// InvalidateCaches 使 Discovery 缓存和所有查询缓存立即失效。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) InvalidateCaches() {
+	k.discoveryClient.Invalidate()
+	_, _ = k.cache.Invalidate("")
+}
+
+// CacheStats 返回缓存命中统计。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) CacheStats() CacheStats {
+	namespaces := k.cache.KeyStats(cache.KeyNamespaces)
+	return CacheStats{
+		DiscoveryLoadedAt: k.discoveryClient.loadedTime(),
+		DiscoveryHits:     k.discoveryClient.hits.Load(),
+		DiscoveryMisses:   k.discoveryClient.misses.Load(),
+		NamespaceHits:     namespaces.Hits,
+		NamespaceMisses:   namespaces.Misses,
+		Entries:           k.cache.Stats(),
+	}
+}
@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/cache"
+)
+
+// newFakeDiscovery 返回只提供v1 pods的fake Discovery客户端，调用记录在Actions中
+func newFakeDiscovery() *fakediscovery.FakeDiscovery {
+	fake := &k8stesting.Fake{}
+	fake.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+	}}
+	return &fakediscovery.FakeDiscovery{Fake: fake}
+}
+
+func TestCachedDiscoveryClient(t *testing.T) {
+	live := newFakeDiscovery()
+	discovery := newCachedDiscoveryClient(live, time.Hour)
+
+	if _, err := discovery.ServerResourcesForGroupVersion("v1"); err != nil {
+		t.Fatal(err)
+	}
+	calls := len(live.Actions())
+	if calls == 0 {
+		t.Fatal("first lookup did not reach the live client")
+	}
+	if !discovery.loadedTime().After(time.Time{}) {
+		t.Fatal("load time not recorded")
+	}
+
+	for range 3 {
+		resources, err := discovery.ServerResourcesForGroupVersion("v1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resources.APIResources) != 1 {
+			t.Fatalf("resources = %+v", resources.APIResources)
+		}
+	}
+	if len(live.Actions()) != calls {
+		t.Fatalf("cached lookups made %d live calls", len(live.Actions())-calls)
+	}
+	if discovery.hits.Load() != 3 || discovery.misses.Load() != 1 {
+		t.Fatalf("hits %d, misses %d; want 3 and 1", discovery.hits.Load(), discovery.misses.Load())
+	}
+
+	discovery.Invalidate()
+	if !discovery.loadedTime().IsZero() {
+		t.Fatal("load time kept after invalidation")
+	}
+	if _, err := discovery.ServerResourcesForGroupVersion("v1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(live.Actions()) == calls || discovery.misses.Load() != 2 {
+		t.Fatal("lookup after invalidation did not reach the live client")
+	}
+}
+
+func TestCachedDiscoveryClientExpires(t *testing.T) {
+	live := newFakeDiscovery()
+	discovery := newCachedDiscoveryClient(live, time.Millisecond)
+
+	if _, err := discovery.ServerGroups(); err != nil {
+		t.Fatal(err)
+	}
+	calls := len(live.Actions())
+	time.Sleep(5 * time.Millisecond)
+	if _, err := discovery.ServerGroups(); err != nil {
+		t.Fatal(err)
+	}
+	if len(live.Actions()) == calls || discovery.misses.Load() != 2 {
+		t.Fatalf("expired cache was served; misses = %d", discovery.misses.Load())
+	}
+}
+
+func TestListNamespacesUsesCache(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lists := 0
+	crClient := crfake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				return c.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	k := &k8sClientImpl{
+		client:          crClient,
+		discoveryClient: newCachedDiscoveryClient(newFakeDiscovery(), time.Hour),
+		cache:           cache.New(time.Hour, nil),
+	}
+	ctx := context.Background()
+
+	for range 3 {
+		names, err := k.ListNamespaceNames(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 2 || names[0] != "team-a" {
+			t.Fatalf("namespaces = %v, want sorted team-a, team-b", names)
+		}
+	}
+	if lists != 1 {
+		t.Fatalf("listed namespaces from the live client %d times, want 1", lists)
+	}
+	if stats := k.CacheStats(); stats.NamespaceHits != 2 || stats.NamespaceMisses != 1 {
+		t.Fatalf("namespace hits %d, misses %d; want 2 and 1", stats.NamespaceHits, stats.NamespaceMisses)
+	}
+
+	k.InvalidateCaches()
+	if _, err := k.ListNamespaces(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if lists != 2 {
+		t.Fatalf("list after invalidation did not reach the live client")
+	}
+}
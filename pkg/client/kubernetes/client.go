@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/hsn0918/kubernetes-mcp/pkg/cache"
 	"github.com/hsn0918/kubernetes-mcp/pkg/config"
 	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
@@ -37,13 +41,32 @@ type Client interface {
 	GetDynamicClient() dynamic.Interface
 	// GetDiscoveryClient 提供访问 client-go discovery 客户端的方法。
 	// Discovery 客户端用于发现 Kubernetes API Server 支持的 API 组、版本和资源。
+	// 返回的客户端带有内存缓存，缓存在配置的有效期后自动失效。
 	GetDiscoveryClient() discovery.DiscoveryInterface
+	// ListNamespaceNames 返回集群中的命名空间名称，在缓存有效期内复用上一次的结果。
+	ListNamespaceNames(ctx context.Context) ([]string, error)
+	// ListNamespaces 返回集群中的命名空间，在缓存有效期内复用上一次的结果。返回的对象被共享，调用方不得修改。
+	ListNamespaces(ctx context.Context) ([]corev1.Namespace, error)
+	// ListNodes 返回集群中的节点，在缓存有效期内复用上一次的结果。返回的对象被共享，调用方不得修改。
+	ListNodes(ctx context.Context) ([]corev1.Node, error)
+	// ListCRDs 返回集群中的 CRD，在缓存有效期内复用上一次的结果。返回的对象被共享，调用方不得修改。
+	ListCRDs(ctx context.Context) ([]unstructured.Unstructured, error)
+	// Cache 返回命名空间、节点、CRD 列表共用的缓存，修改这些对象后通过它使对应的缓存项失效。
+	Cache() *cache.Cache
+	// InvalidateCaches 使 Discovery 缓存和所有查询缓存立即失效，下次访问时重新从 API Server 获取。
+	// 在安装或删除 CRD、创建或删除命名空间后调用，可以立即看到变化。
+	InvalidateCaches()
+	// CacheStats 返回 Discovery 与查询缓存的命中统计。
+	CacheStats() CacheStats
 	// GetMetricsClient 提供访问 client-go metrics 客户端的方法。
 	// Metrics 客户端用于获取 Kubernetes 资源的度量信息。
 	GetMetricsClient() metricsv.Interface
 	// GetConfig 获取用于创建此客户端的原始 clientcmd 配置。
 	// 这对于需要访问底层配置细节（如上下文、集群信息等）的场景很有用。
 	GetConfig() clientcmd.ClientConfig
+	// GetRESTConfig 获取创建各客户端时使用的 REST 配置。
+	// 执行容器命令等需要直接建立流式连接的操作使用此配置。
+	GetRESTConfig() *rest.Config
 }
 
 // k8sClientImpl 是 Client 接口的具体实现。
@@ -55,12 +78,16 @@ type k8sClientImpl struct {
 	clientset kubernetes.Interface
 	// 动态客户端，用于处理 CRD 或非结构化数据。
 	dynamicClient dynamic.Interface
-	// Discovery 客户端，用于 API 发现。
-	discoveryClient discovery.DiscoveryInterface
+	// 带缓存的 Discovery 客户端，用于 API 发现。
+	discoveryClient *cachedDiscoveryClient
+	// 命名空间、节点、CRD 列表的查询缓存。
+	cache *cache.Cache
 	// Metrics 客户端，用于获取 Kubernetes 资源的度量信息。
 	metricsClient metricsv.Interface
 	// 加载的原始 kubeconfig 配置信息。
 	rawConfig clientcmd.ClientConfig
+	// 创建各客户端时使用的 REST 配置。
+	restConfig *rest.Config
 }
 
 // 编译时断言，确保 k8sClientImpl 实现了 Client 接口。
@@ -250,9 +277,23 @@ func NewClient(appCfg *config.Config) (Client, error) {
 		return nil, fmt.Errorf("failed to add client-go scheme: %w", err)
 	}
 	// TODO: 在这里可以添加应用程序自定义资源 (CRD) 的类型到 Scheme
-	restConfig.QPS = 500
-	restConfig.Burst = 1000
-	log.Debug("Set client QPS and Burst", "qps", restConfig.QPS, "burst", restConfig.Burst)
+	configureRestClient(restConfig, appCfg)
+	scope := installNamespaceGuard(restConfig)
+	log.Info("Configured Kubernetes client",
+		"qps", restConfig.QPS,
+		"burst", restConfig.Burst,
+		"timeout", restConfig.Timeout.String(),
+		"userAgent", restConfig.UserAgent,
+	)
+	if appCfg.ClientRequestTimeout > 0 && appCfg.ClientRequestTimeout < time.Duration(appCfg.MaxToolTimeoutSeconds)*time.Second {
+		log.Warn("Client request timeout is shorter than the maximum tool timeout, long watches and log streams may be cut off",
+			"clientRequestTimeout", appCfg.ClientRequestTimeout,
+			"maxToolTimeoutSeconds", appCfg.MaxToolTimeoutSeconds,
+		)
+	}
+	if appCfg.LogClientThrottling {
+		enableThrottlingLog(log)
+	}
 
 	runtimeClient, err := client.New(restConfig, client.Options{
 		Scheme: scheme,
@@ -278,7 +319,7 @@ func NewClient(appCfg *config.Config) (Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not create discovery client: %w", err)
 	}
-	log.Debug("Discovery client created successfully")
+	log.Debug("Discovery client created successfully", "cacheTTL", appCfg.DiscoveryCacheTTL)
 	// DynamicClient 用于操作非结构化数据（例如 CRD）
 	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
@@ -290,13 +331,33 @@ func NewClient(appCfg *config.Config) (Client, error) {
 		return nil, fmt.Errorf("could not create metrics client: %w", err)
 	}
 	// 6. 创建并返回 k8sClientImpl 实例
+	cachedDiscovery := newCachedDiscoveryClient(discoveryClient, appCfg.DiscoveryCacheTTL)
+	// 命名空间白名单按发现结果判断资源是否为命名空间级别，不计入缓存统计
+	scope.namespaced = func(groupVersion, resource string) (bool, error) {
+		list, err := cachedDiscovery.CachedDiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			return false, err
+		}
+		for _, apiResource := range list.APIResources {
+			if apiResource.Name == resource {
+				return apiResource.Namespaced, nil
+			}
+		}
+		return false, fmt.Errorf("resource %s is not served by %s", resource, groupVersion)
+	}
 	impl := &k8sClientImpl{
 		client:          runtimeClient,
 		clientset:       clientset,
 		rawConfig:       rawConfig, // 注意这里保存的是 ClientConfig 接口，可能是 nil
-		discoveryClient: discoveryClient,
-		dynamicClient:   dynamicClient,
-		metricsClient:   metricsClient,
+		restConfig:      restConfig,
+		discoveryClient: cachedDiscovery,
+		cache: cache.New(appCfg.DiscoveryCacheTTL, map[string]time.Duration{
+			cache.KeyNamespaces: appCfg.NamespaceCacheTTL,
+			cache.KeyNodes:      appCfg.NodeCacheTTL,
+			cache.KeyCRDs:       appCfg.CRDCacheTTL,
+		}),
+		dynamicClient: dynamicClient,
+		metricsClient: metricsClient,
 	}
 
 	log.Info("Kubernetes client initialized successfully")
@@ -351,6 +412,13 @@ func (k *k8sClientImpl) GetMetricsClient() metricsv.Interface {
 func (k *k8sClientImpl) GetConfig() clientcmd.ClientConfig {
 	return k.rawConfig
 }
+
+// GetRESTConfig 返回 k8sClientImpl 实例中存储的 REST 配置。
+// 这是 Client 接口的实现方法。
+func (k *k8sClientImpl) GetRESTConfig() *rest.Config {
+	return k.restConfig
+}
+
 func (k *k8sClientImpl) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
 	return k.client.Apply(ctx, obj, opts...)
 }
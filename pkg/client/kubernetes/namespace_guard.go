@@ -0,0 +1,160 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+)
+
+// namespaceScope 判断资源是否为命名空间级别，创建发现客户端后设置
+type namespaceScope struct {
+	// basePath API Server地址中的路径前缀，例如通过代理访问时的"/k8s/clusters/c-1"
+	basePath   string
+	namespaced func(groupVersion, resource string) (bool, error)
+}
+
+// namespaceGuard 在发往API Server的每个请求上执行命名空间白名单：
+// 路径中的命名空间必须在白名单中，命名空间级别资源跨所有命名空间的列表和监听被拒绝。
+// 在传输层检查，工具省略namespace参数时使用的默认命名空间、跨命名空间扫描和MCP资源读取都受到约束
+type namespaceGuard struct {
+	next  http.RoundTripper
+	scope *namespaceScope
+}
+
+// installNamespaceGuard 为REST配置创建的所有传输（包括exec使用的SPDY连接）加上命名空间白名单检查
+func installNamespaceGuard(restConfig *rest.Config) *namespaceScope {
+	scope := &namespaceScope{}
+	if u, _, err := rest.DefaultServerUrlFor(restConfig); err == nil {
+		scope.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &namespaceGuard{next: rt, scope: scope}
+	})
+	return scope
+}
+
+// RoundTrip 实现http.RoundTripper接口，使用请求上下文中的配置快照，白名单为空时直接放行
+func (g *namespaceGuard) RoundTrip(req *http.Request) (*http.Response, error) {
+	allowed := config.FromContext(req.Context()).AllowedNamespaceList()
+	if len(allowed) == 0 {
+		return g.next.RoundTrip(req)
+	}
+	req, reason := g.check(req, allowed)
+	if reason != "" {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return forbiddenResponse(req, reason), nil
+	}
+	return g.next.RoundTrip(req)
+}
+
+// check 返回拒绝请求的原因，允许时返回空字符串；读取了请求体时返回替换了请求体的请求副本
+func (g *namespaceGuard) check(req *http.Request, allowed []string) (*http.Request, string) {
+	path := strings.TrimPrefix(req.URL.Path, g.scope.basePath)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var groupVersion string
+	var rest []string
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		groupVersion, rest = segments[1], segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		groupVersion, rest = segments[1]+"/"+segments[2], segments[3:]
+	default:
+		// 发现、版本和健康检查等非资源请求
+		return req, ""
+	}
+	if len(rest) > 0 && rest[0] == "watch" {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return req, ""
+	}
+	allowedList := strings.Join(allowed, ", ")
+
+	if rest[0] == "namespaces" {
+		if len(rest) >= 2 {
+			if slices.Contains(allowed, rest[1]) {
+				return req, ""
+			}
+			return req, fmt.Sprintf("namespace %q is not in the allowed namespaces of this server (%s)", rest[1], allowedList)
+		}
+		// 列出命名空间对象本身不涉及其中的资源；创建命名空间时按对象名称检查
+		if req.Method != http.MethodPost {
+			return req, ""
+		}
+		req, name := requestObjectName(req)
+		if slices.Contains(allowed, name) {
+			return req, ""
+		}
+		return req, fmt.Sprintf("creating namespace %q is not allowed, this server is restricted to namespaces: %s", name, allowedList)
+	}
+
+	if g.scope.namespaced == nil {
+		return req, fmt.Sprintf("cannot determine the scope of %s while the server is restricted to namespaces: %s", rest[0], allowedList)
+	}
+	namespaced, err := g.scope.namespaced(groupVersion, rest[0])
+	if err != nil {
+		return req, fmt.Sprintf("cannot determine the scope of %s in %s while the server is restricted to namespaces (%s): %v", rest[0], groupVersion, allowedList, err)
+	}
+	if namespaced {
+		return req, fmt.Sprintf("accessing %s across all namespaces is not allowed, this server is restricted to namespaces: %s; pass one of them as the namespace", rest[0], allowedList)
+	}
+	return req, ""
+}
+
+// requestObjectName 读取请求体中对象的metadata.name，返回带有可重复读取请求体的请求副本
+func requestObjectName(req *http.Request) (*http.Request, string) {
+	if req.Body == nil {
+		return req, ""
+	}
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(data))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	if err != nil {
+		return clone, ""
+	}
+	var object struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	_ = json.Unmarshal(data, &object)
+	return clone, object.Metadata.Name
+}
+
+// forbiddenResponse 构造403响应，客户端将其解析为Forbidden类型的StatusError
+func forbiddenResponse(req *http.Request, message string) *http.Response {
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   metav1.StatusReasonForbidden,
+		Code:     http.StatusForbidden,
+	}
+	body, _ := json.Marshal(status)
+	return &http.Response{
+		Status:        "403 Forbidden",
+		StatusCode:    http.StatusForbidden,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+)
+
+// recordingTransport 记录被放行的请求并返回200
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
+
+// testScope pods、configmaps和deployments为命名空间级别，nodes和clusterroles为集群级别
+func testScope(basePath string) *namespaceScope {
+	namespaced := map[string]bool{
+		"v1/pods":                     true,
+		"v1/configmaps":               true,
+		"apps/v1/deployments":         true,
+		"metrics.k8s.io/v1beta1/pods": true,
+		"v1/nodes":                    false,
+		"rbac.authorization.k8s.io/v1/clusterroles": false,
+	}
+	return &namespaceScope{
+		basePath: basePath,
+		namespaced: func(groupVersion, resource string) (bool, error) {
+			value, ok := namespaced[groupVersion+"/"+resource]
+			if !ok {
+				return false, fmt.Errorf("resource %s is not served by %s", resource, groupVersion)
+			}
+			return value, nil
+		},
+	}
+}
+
+func TestNamespaceGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		basePath string
+		allowed  string
+		refused  string
+	}{
+		{name: "no allowlist", method: http.MethodGet, path: "/api/v1/pods", allowed: ""},
+		{name: "allowed namespace", method: http.MethodGet, path: "/api/v1/namespaces/team-a/pods", allowed: "team-a,team-b"},
+		{name: "other namespace", method: http.MethodDelete, path: "/api/v1/namespaces/kube-system/pods/x", allowed: "team-a", refused: `namespace "kube-system"`},
+		{name: "namespace object", method: http.MethodDelete, path: "/api/v1/namespaces/prod", allowed: "team-a", refused: `namespace "prod"`},
+		{name: "group resource", method: http.MethodPatch, path: "/apis/apps/v1/namespaces/team-b/deployments/web", allowed: "team-a,team-b"},
+		{name: "all namespaces list", method: http.MethodGet, path: "/api/v1/pods", allowed: "team-a", refused: "across all namespaces"},
+		{name: "all namespaces watch", method: http.MethodGet, path: "/apis/apps/v1/deployments", allowed: "team-a", refused: "across all namespaces"},
+		{name: "legacy watch path", method: http.MethodGet, path: "/api/v1/watch/namespaces/prod/pods", allowed: "team-a", refused: `namespace "prod"`},
+		{name: "all namespaces metrics", method: http.MethodGet, path: "/apis/metrics.k8s.io/v1beta1/pods", allowed: "team-a", refused: "across all namespaces"},
+		{name: "cluster scoped", method: http.MethodGet, path: "/api/v1/nodes", allowed: "team-a"},
+		{name: "cluster scoped group", method: http.MethodGet, path: "/apis/rbac.authorization.k8s.io/v1/clusterroles/admin", allowed: "team-a"},
+		{name: "unknown scope", method: http.MethodGet, path: "/apis/example.com/v1/widgets", allowed: "team-a", refused: "cannot determine the scope"},
+		{name: "list namespaces", method: http.MethodGet, path: "/api/v1/namespaces", allowed: "team-a"},
+		{name: "create allowed namespace", method: http.MethodPost, path: "/api/v1/namespaces", body: `{"metadata":{"name":"team-a"}}`, allowed: "team-a"},
+		{name: "create other namespace", method: http.MethodPost, path: "/api/v1/namespaces", body: `{"metadata":{"name":"prod"}}`, allowed: "team-a", refused: `creating namespace "prod"`},
+		{name: "discovery", method: http.MethodGet, path: "/apis/apps/v1", allowed: "team-a"},
+		{name: "version", method: http.MethodGet, path: "/version", allowed: "team-a"},
+		{name: "base path", method: http.MethodGet, path: "/k8s/clusters/c-1/api/v1/namespaces/prod/configmaps", basePath: "/k8s/clusters/c-1", allowed: "team-a", refused: `namespace "prod"`},
+		{name: "exec in allowed namespace", method: http.MethodPost, path: "/api/v1/namespaces/team-a/pods/x/exec", allowed: "team-a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &recordingTransport{}
+			guard := &namespaceGuard{next: next, scope: testScope(tt.basePath)}
+			ctx := config.NewContext(context.Background(), &config.Config{AllowedNamespaces: tt.allowed})
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, err := http.NewRequestWithContext(ctx, tt.method, "https://cluster.example"+tt.path, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := guard.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip returned error: %v", err)
+			}
+			data, _ := io.ReadAll(resp.Body)
+			if tt.refused == "" {
+				if resp.StatusCode != http.StatusOK || len(next.requests) != 1 {
+					t.Fatalf("request was refused: %d %s", resp.StatusCode, data)
+				}
+				if tt.body != "" {
+					// 读取名称后请求体必须原样转发
+					forwarded, _ := io.ReadAll(next.requests[0].Body)
+					if string(forwarded) != tt.body {
+						t.Fatalf("forwarded body = %q, want %q", forwarded, tt.body)
+					}
+				}
+				return
+			}
+			if resp.StatusCode != http.StatusForbidden || len(next.requests) != 0 {
+				t.Fatalf("request was not refused: status %d, forwarded %d", resp.StatusCode, len(next.requests))
+			}
+			var status metav1.Status
+			if err := json.Unmarshal(data, &status); err != nil {
+				t.Fatalf("refusal is not a Status: %v", err)
+			}
+			if status.Reason != metav1.StatusReasonForbidden || !strings.Contains(status.Message, tt.refused) {
+				t.Fatalf("refusal %s does not mention %q", data, tt.refused)
+			}
+		})
+	}
+}
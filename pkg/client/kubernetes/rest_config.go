@@ -0,0 +1,52 @@
+package kubernetes
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/metrics"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/version"
+)
+
+// throttleLogThreshold 限流等待超过该时长的请求才记录日志，避免刷屏
+const throttleLogThreshold = 50 * time.Millisecond
+
+// configureRestClient 按应用配置设置客户端限流、请求超时和User-Agent，便于集群管理员在审计日志中识别本服务的请求
+func configureRestClient(restConfig *rest.Config, appCfg *config.Config) {
+	restConfig.QPS = appCfg.ClientQPS
+	restConfig.Burst = appCfg.ClientBurst
+	restConfig.Timeout = appCfg.ClientRequestTimeout
+	restConfig.UserAgent = "kubernetes-mcp/" + version.Version
+}
+
+// throttlingLogger 记录请求在client-go客户端限流器中的等待，实现metrics.LatencyMetric
+type throttlingLogger struct {
+	log       logger.Logger
+	throttled atomic.Int64
+}
+
+// Observe 实现metrics.LatencyMetric接口
+func (t *throttlingLogger) Observe(_ context.Context, verb string, u url.URL, latency time.Duration) {
+	if latency < throttleLogThreshold {
+		return
+	}
+	t.log.Info("Request delayed by client-side throttling",
+		"verb", verb,
+		"path", u.Path,
+		"wait", latency,
+		"throttledRequests", t.throttled.Add(1),
+	)
+}
+
+// enableThrottlingLog 注册限流等待的记录器。client-go的指标只能注册一次，需在创建客户端时调用
+func enableThrottlingLog(log logger.Logger) {
+	metrics.Register(metrics.RegisterOpts{
+		RateLimiterLatency: &throttlingLogger{log: log},
+	})
+}
@@ -1,12 +1,28 @@
 package config
 
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
 // Config 应用程序配置
 type Config struct {
+	// 配置文件路径，为空时只使用默认值、环境变量和命令行参数
+	ConfigFile string
 	// 服务器配置
 	Transport  string
 	Port       int
 	HealthPort int
 	BaseURL    string
+	// 健康检查配置：/healthz和/readyz的监听地址（如":8081"），为空时SSE和StreamableHTTP模式使用HealthPort，stdio模式不启动
+	HealthListenAddr string
+	// 健康检查配置：后台通过Discovery探测Kubernetes API的间隔，0表示不探测
+	ReadinessPingInterval time.Duration
+	// 健康检查配置：最近一次探测成功超过该时间后/readyz返回未就绪
+	ReadinessStaleAfter time.Duration
+	// 关闭配置：收到SIGTERM后等待进行中的工具调用结束的最长时间
+	ShutdownDrainTimeout time.Duration
 	// CORS配置
 	AllowOrigins string
 	// 日志配置
@@ -14,18 +30,160 @@ type Config struct {
 	LogFormat string
 	// Kubernetes配置
 	Kubeconfig string
+	// Kubernetes客户端配置：客户端限流的每秒请求数和突发请求数
+	ClientQPS   float32
+	ClientBurst int
+	// Kubernetes客户端配置：单个API请求的超时时间，对监听、日志流和exec同样生效，0表示不限制
+	ClientRequestTimeout time.Duration
+	// Kubernetes客户端配置：是否记录客户端限流造成的请求等待
+	LogClientThrottling bool
+	// 安全配置：只读模式，拒绝所有变更集群的工具调用（支持服务端试运行的工具的dryRun调用除外），可通过SIGHUP重新加载
+	ReadOnly bool
+	// 安全配置：允许访问的命名空间，逗号分隔，为空表示不限制，可通过SIGHUP重新加载。
+	// 除工具参数外，客户端对API Server的每个请求也按此检查，跨所有命名空间的列表请求被拒绝
+	AllowedNamespaces string
+	// 安全配置：变更操作前先通过SelfSubjectAccessReview检查权限
+	PreflightAuthz bool
+	// 安全配置：是否允许GET_SECRET_KEYS在调用方要求时返回Secret的值
+	AllowSecretValues bool
+	// 安全配置：是否允许在容器中执行命令（LIST_POD_FILES、READ_POD_FILE、COPY_TO_POD等）
+	AllowExec bool
+	// 安全配置：COPY_TO_POD和COPY_FROM_POD单个文件的最大字节数
+	MaxCopyBytes int
+	// 备份配置：BACKUP_NAMESPACE结果超过内联阈值时写入的服务器本地目录
+	BackupDir string
+	// 备份配置：备份清单内联返回的最大字节数
+	BackupInlineLimit int
+	// 列表配置：LIST工具单次返回的最大资源数量
+	MaxListItems int
+	// 输出配置：单个工具响应的最大字节数，超过时按内容截断，0表示不限制
+	MaxResultBytes int
+	// 重试配置：暂时性API错误与更新冲突的最大重试次数
+	MaxRetries int
+	// 重试配置：第一次重试前的等待时间，之后按指数退避
+	RetryInitialBackoff time.Duration
+	// 重试配置：单次退避等待时间的上限
+	RetryMaxBackoff time.Duration
+	// 超时配置：工具调用未指定timeoutSeconds时的默认超时秒数
+	ToolTimeoutSeconds int
+	// 超时配置：调用方可指定的最大超时秒数
+	MaxToolTimeoutSeconds int
+	// 缓存配置：Discovery缓存的有效期，0表示不缓存
+	DiscoveryCacheTTL time.Duration
+	// 缓存配置：命名空间列表缓存的有效期，0表示不缓存
+	NamespaceCacheTTL time.Duration
+	// 缓存配置：节点列表缓存的有效期，0表示不缓存
+	NodeCacheTTL time.Duration
+	// 缓存配置：CRD列表缓存的有效期，0表示不缓存
+	CRDCacheTTL time.Duration
+	// 并发配置：同时执行的工具调用上限，0表示不限制
+	MaxConcurrentTools int
+	// 并发配置：同时执行的高开销工具（搜索、备份、监听、指标、日志等）调用上限，0表示不限制
+	MaxConcurrentExpensiveTools int
+	// 并发配置：超过并发上限的调用最多排队等待的时间
+	ConcurrencyQueueTimeout time.Duration
+	// 命名空间配置：CREATE_NAMESPACE使用的ResourceQuota/LimitRange模板文件，为空时只使用内置模板
+	NamespacePresetsFile string
+	// 清单配置：APPLY_KUSTOMIZATION和RENDER_KUSTOMIZATION可读取的服务器本地根目录，为空时禁止按目录构建
+	KustomizeRoot string
+	// 清单配置：APPLY_FROM_URL允许获取的URL前缀，逗号分隔，为空时禁止从URL获取清单
+	ManifestURLPrefixes string
+	// 清单配置：APPLY_FROM_URL获取的清单的最大字节数
+	MaxManifestURLBytes int
+	// MCP资源配置：以资源形式公开的资源类型，逗号分隔
+	ResourceKinds string
+	// MCP资源配置：以资源形式公开的命名空间，逗号分隔，为空表示所有命名空间
+	ResourceNamespaces string
+	// MCP资源配置：读取单个资源返回的最大字节数
+	ResourceMaxBytes int
+	// 工具配置：只注册这些工具组（interfaces.APIGroup）的工具，逗号分隔，为空表示全部注册
+	EnabledToolGroups string
+	// 工具配置：不注册的工具，逗号分隔，支持"GET_*"形式的通配符或工具组名称
+	DisabledTools string
+	// 工具配置：通用资源工具的命名方式，flat（默认）为只注册一组（如LIST_RESOURCES），prefixed为按API组分别注册（如LIST_APPS_RESOURCES）
+	ToolNaming string
+	// 工具配置：flat命名时是否同时注册按API组命名的旧工具名作为已弃用的别名，默认注册，使升级后已有客户端调用的工具名仍然可用
+	LegacyToolAliases bool
+	// 笔记配置：每个会话最多保存的笔记数量
+	MaxNotes int
+	// 笔记配置：单条笔记的最大字节数
+	MaxNoteBytes int
+	// 笔记配置：持久化会话笔记的文件路径，为空时只保存在内存中
+	NotesFile string
+	// 调用记录配置：每个会话在内存中保留的最近工具调用数，0表示不记录
+	ToolHistorySize int
 }
 
 // NewDefaultConfig 创建默认配置
 func NewDefaultConfig() *Config {
 	return &Config{
-		Transport:    "sse",
-		Port:         8080,
-		HealthPort:   8081,
-		BaseURL:      "",
-		AllowOrigins: "*",
-		LogLevel:     "info",
-		LogFormat:    "console",
-		Kubeconfig:   "",
+		Transport:                   "sse",
+		Port:                        8080,
+		HealthPort:                  8081,
+		BaseURL:                     "",
+		HealthListenAddr:            "",
+		ReadinessPingInterval:       10 * time.Second,
+		ReadinessStaleAfter:         30 * time.Second,
+		ShutdownDrainTimeout:        30 * time.Second,
+		AllowOrigins:                "*",
+		LogLevel:                    "info",
+		LogFormat:                   "console",
+		Kubeconfig:                  "",
+		ClientQPS:                   50,
+		ClientBurst:                 100,
+		ClientRequestTimeout:        10 * time.Minute,
+		LogClientThrottling:         false,
+		PreflightAuthz:              false,
+		AllowSecretValues:           false,
+		AllowExec:                   false,
+		MaxCopyBytes:                1024 * 1024,
+		BackupDir:                   "",
+		BackupInlineLimit:           256 * 1024,
+		MaxListItems:                500,
+		MaxResultBytes:              100 * 1024,
+		MaxRetries:                  3,
+		RetryInitialBackoff:         200 * time.Millisecond,
+		RetryMaxBackoff:             5 * time.Second,
+		ToolTimeoutSeconds:          60,
+		MaxToolTimeoutSeconds:       600,
+		DiscoveryCacheTTL:           5 * time.Minute,
+		NamespaceCacheTTL:           5 * time.Minute,
+		NodeCacheTTL:                30 * time.Second,
+		CRDCacheTTL:                 5 * time.Minute,
+		MaxConcurrentTools:          32,
+		MaxConcurrentExpensiveTools: 4,
+		ConcurrencyQueueTimeout:     5 * time.Second,
+		MaxManifestURLBytes:         1024 * 1024,
+		ResourceKinds:               "pods,deployments,services,configmaps,events",
+		ResourceNamespaces:          "",
+		ResourceMaxBytes:            1024 * 1024,
+		MaxNotes:                    50,
+		MaxNoteBytes:                4096,
+		ToolHistorySize:             100,
+		ToolNaming:                  "flat",
+		LegacyToolAliases:           true,
+	}
+}
+
+// Clone 返回配置的副本
+func (c *Config) Clone() *Config {
+	clone := *c
+	return &clone
+}
+
+// AllowedNamespaceList 返回命名空间白名单，未配置时返回nil，表示不限制
+func (c *Config) AllowedNamespaceList() []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(c.AllowedNamespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
 	}
+	return namespaces
+}
+
+// NamespaceAllowed 判断命名空间是否在白名单中，未配置白名单时总是允许
+func (c *Config) NamespaceAllowed(namespace string) bool {
+	allowed := c.AllowedNamespaceList()
+	return len(allowed) == 0 || slices.Contains(allowed, namespace)
 }
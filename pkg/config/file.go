@@ -0,0 +1,361 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// 配置文件与环境变量
+const (
+	// ConfigFileEnv 指定配置文件路径的环境变量，--config优先
+	ConfigFileEnv = "KUBERNETES_MCP_CONFIG"
+	// EnvPrefix 覆盖单项设置的环境变量前缀，后接大写并以'_'分隔的命令行参数名，例如KUBERNETES_MCP_LOG_LEVEL
+	EnvPrefix = "KUBERNETES_MCP_"
+)
+
+// setting 一项可在配置文件中设置的配置
+type setting struct {
+	// key 配置文件中的键，形式为"分组.名称"
+	key string
+	// flag 对应的命令行参数，命令行显式指定时配置文件和环境变量不生效
+	flag string
+	// list 值为逗号分隔的列表，配置文件中也可以写成YAML数组
+	list bool
+	// reloadable 是否可以在运行时通过SIGHUP重新加载
+	reloadable bool
+	// field 返回Config中对应字段的指针
+	field func(*Config) any
+}
+
+// settings 配置文件支持的设置
+var settings = []setting{
+	{key: "log.level", flag: "log-level", reloadable: true, field: func(c *Config) any { return &c.LogLevel }},
+	{key: "log.format", flag: "log-format", field: func(c *Config) any { return &c.LogFormat }},
+	{key: "kubernetes.kubeconfig", flag: "kubeconfig", field: func(c *Config) any { return &c.Kubeconfig }},
+	{key: "kubernetes.qps", flag: "client-qps", field: func(c *Config) any { return &c.ClientQPS }},
+	{key: "kubernetes.burst", flag: "client-burst", field: func(c *Config) any { return &c.ClientBurst }},
+	{key: "kubernetes.requestTimeout", flag: "client-request-timeout", field: func(c *Config) any { return &c.ClientRequestTimeout }},
+	{key: "security.readOnly", flag: "read-only", reloadable: true, field: func(c *Config) any { return &c.ReadOnly }},
+	{key: "security.allowedNamespaces", flag: "allowed-namespaces", list: true, reloadable: true, field: func(c *Config) any { return &c.AllowedNamespaces }},
+	{key: "security.allowExec", flag: "allow-exec", field: func(c *Config) any { return &c.AllowExec }},
+	{key: "security.allowSecretValues", flag: "allow-secret-values", field: func(c *Config) any { return &c.AllowSecretValues }},
+	{key: "security.preflightAuthz", flag: "preflight-authz", field: func(c *Config) any { return &c.PreflightAuthz }},
+	{key: "tools.enabledGroups", flag: "enabled-tool-groups", list: true, field: func(c *Config) any { return &c.EnabledToolGroups }},
+	{key: "tools.disabled", flag: "disabled-tools", list: true, field: func(c *Config) any { return &c.DisabledTools }},
+	{key: "tools.naming", flag: "tool-naming", field: func(c *Config) any { return &c.ToolNaming }},
+	{key: "tools.legacyAliases", flag: "legacy-tool-aliases", field: func(c *Config) any { return &c.LegacyToolAliases }},
+	{key: "output.maxResultBytes", flag: "max-result-bytes", reloadable: true, field: func(c *Config) any { return &c.MaxResultBytes }},
+	{key: "output.maxListItems", flag: "max-list-items", reloadable: true, field: func(c *Config) any { return &c.MaxListItems }},
+	{key: "output.maxCopyBytes", flag: "max-copy-bytes", reloadable: true, field: func(c *Config) any { return &c.MaxCopyBytes }},
+	{key: "output.backupInlineLimit", flag: "backup-inline-limit", reloadable: true, field: func(c *Config) any { return &c.BackupInlineLimit }},
+	{key: "timeouts.toolSeconds", flag: "tool-timeout-seconds", field: func(c *Config) any { return &c.ToolTimeoutSeconds }},
+	{key: "timeouts.maxToolSeconds", flag: "max-tool-timeout-seconds", field: func(c *Config) any { return &c.MaxToolTimeoutSeconds }},
+	{key: "timeouts.shutdownDrain", flag: "shutdown-drain-timeout", field: func(c *Config) any { return &c.ShutdownDrainTimeout }},
+}
+
+// EnvName 返回覆盖命令行参数对应设置的环境变量名
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Loader 按命令行参数、环境变量、配置文件、默认值的优先级加载配置
+type Loader struct {
+	// path 配置文件路径，为空时不读取配置文件
+	path string
+	// baseline 默认值加上命令行参数，每次加载都从它开始，使配置文件中删除的设置恢复原值
+	baseline *Config
+	// explicit 判断命令行参数是否被显式指定
+	explicit func(flag string) bool
+}
+
+// NewLoader 创建配置加载器，baseline为解析命令行参数后、应用配置文件前的配置
+func NewLoader(baseline *Config, path string, explicit func(flag string) bool) *Loader {
+	if explicit == nil {
+		explicit = func(string) bool { return false }
+	}
+	return &Loader{path: path, baseline: baseline.Clone(), explicit: explicit}
+}
+
+// Path 返回配置文件路径
+func (l *Loader) Path() string {
+	return l.path
+}
+
+// Load 依次应用配置文件和环境变量，命令行显式指定的设置不被覆盖，然后校验结果
+func (l *Loader) Load() (*Config, error) {
+	cfg := l.baseline.Clone()
+	cfg.ConfigFile = l.path
+	if l.path != "" {
+		values, err := readFile(l.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range settings {
+			value, ok := values[s.key]
+			if !ok || l.explicit(s.flag) {
+				continue
+			}
+			if err := setValue(s, s.field(cfg), value); err != nil {
+				return nil, fmt.Errorf("config file %s: %s: %w", l.path, s.key, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		name := EnvName(s.flag)
+		value, ok := os.LookupEnv(name)
+		if !ok || l.explicit(s.flag) {
+			continue
+		}
+		if err := setString(s.field(cfg), value); err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// Reload 重新读取配置文件，只将可在运行时修改的设置应用到current的副本上。
+// 返回新配置以及值已改变但需要重启才能生效的设置；读取或校验失败时返回错误，current保持不变
+func (l *Loader) Reload(current *Config) (*Config, []string, error) {
+	loaded, err := l.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	next := current.Clone()
+	var restartRequired []string
+	for _, s := range settings {
+		want := reflect.ValueOf(s.field(loaded)).Elem()
+		have := reflect.ValueOf(s.field(next)).Elem()
+		if want.Equal(have) {
+			continue
+		}
+		if !s.reloadable {
+			restartRequired = append(restartRequired, s.key)
+			continue
+		}
+		have.Set(want)
+	}
+	return next, restartRequired, nil
+}
+
+// Validate 校验配置中取值范围受限的设置
+func (c *Config) Validate() error {
+	var errs []error
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log level %q is invalid: must be debug, info, warn or error", c.LogLevel))
+	}
+	switch strings.ToLower(c.LogFormat) {
+	case "console", "json":
+	default:
+		errs = append(errs, fmt.Errorf("log format %q is invalid: must be console or json", c.LogFormat))
+	}
+	if c.ClientQPS <= 0 {
+		errs = append(errs, fmt.Errorf("client QPS must be positive, got %v", c.ClientQPS))
+	}
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"client burst", c.ClientBurst},
+		{"max result bytes", c.MaxResultBytes},
+		{"max list items", c.MaxListItems},
+		{"max copy bytes", c.MaxCopyBytes},
+		{"backup inline limit", c.BackupInlineLimit},
+		{"tool timeout seconds", c.ToolTimeoutSeconds},
+		{"max tool timeout seconds", c.MaxToolTimeoutSeconds},
+	} {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %d", limit.name, limit.value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readFile 读取配置文件，返回以"分组.名称"为键的设置值，未知的分组或键作为错误返回
+func readFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	known := make(map[string]bool, len(settings))
+	sections := make(map[string]bool)
+	for _, s := range settings {
+		known[s.key] = true
+		sections[strings.SplitN(s.key, ".", 2)[0]] = true
+	}
+
+	values := make(map[string]any)
+	var unknown []string
+	for section, raw := range document {
+		if !sections[section] {
+			unknown = append(unknown, unknownKey(section, sections))
+			continue
+		}
+		if raw == nil {
+			continue
+		}
+		entries, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("config file %s: %s: expected a mapping of settings, got %s", path, section, describeValue(raw))
+		}
+		for name, value := range entries {
+			key := section + "." + name
+			if !known[key] {
+				unknown = append(unknown, unknownKey(key, known))
+				continue
+			}
+			values[key] = value
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("config file %s: %s", path, strings.Join(unknown, "; "))
+	}
+	return values, nil
+}
+
+// unknownKey 描述未知的键，大小写或分隔符不同的已知键作为建议给出
+func unknownKey(key string, known map[string]bool) string {
+	normalized := normalizeKey(key)
+	for candidate := range known {
+		if normalizeKey(candidate) == normalized {
+			return fmt.Sprintf("unknown key %q, did you mean %q?", key, candidate)
+		}
+	}
+	return fmt.Sprintf("unknown key %q", key)
+}
+
+// normalizeKey 转换为小写并去掉'-'和'_'
+func normalizeKey(key string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+}
+
+// setValue 将配置文件中的值写入字段，类型不符时返回错误
+func setValue(s setting, field any, value any) error {
+	switch target := field.(type) {
+	case *string:
+		switch v := value.(type) {
+		case string:
+			*target = v
+		case []any:
+			if !s.list {
+				return fmt.Errorf("expected a string, got a list")
+			}
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				text, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("expected a list of strings, got an item of type %s", describeValue(item))
+				}
+				items = append(items, text)
+			}
+			*target = strings.Join(items, ",")
+		case nil:
+			*target = ""
+		default:
+			if s.list {
+				return fmt.Errorf("expected a list of strings, got %s", describeValue(value))
+			}
+			return fmt.Errorf("expected a string, got %s", describeValue(value))
+		}
+	case *bool:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected true or false, got %s", describeValue(value))
+		}
+		*target = v
+	case *int:
+		v, ok := value.(float64)
+		if !ok || v != math.Trunc(v) || v > math.MaxInt32 || v < math.MinInt32 {
+			return fmt.Errorf("expected an integer, got %s", describeValue(value))
+		}
+		*target = int(v)
+	case *float32:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("expected a number, got %s", describeValue(value))
+		}
+		*target = float32(v)
+	case *time.Duration:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a duration such as \"30s\" or \"5m\", got %s", describeValue(value))
+		}
+		return setString(target, text)
+	default:
+		return fmt.Errorf("unsupported setting type %T", field)
+	}
+	return nil
+}
+
+// setString 将环境变量等字符串形式的值解析后写入字段
+func setString(field any, value string) error {
+	switch target := field.(type) {
+	case *string:
+		*target = value
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		*target = v
+	case *int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*target = v
+	case *float32:
+		v, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		*target = float32(v)
+	case *time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, use a value such as \"30s\" or \"5m\"", value)
+		}
+		*target = v
+	default:
+		return fmt.Errorf("unsupported setting type %T", field)
+	}
+	return nil
+}
+
+// describeValue 描述YAML值的类型，用于错误消息
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %t", v)
+	case float64:
+		return fmt.Sprintf("number %v", v)
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "a mapping"
+	}
+	return fmt.Sprintf("%T", value)
+}
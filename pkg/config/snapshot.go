@@ -0,0 +1,41 @@
+package config
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// current 当前生效的配置，启动时设置，SIGHUP重新加载时整体替换
+var current atomic.Pointer[Config]
+
+// Store 将配置的副本设为当前生效的配置
+func Store(cfg *Config) {
+	current.Store(cfg.Clone())
+}
+
+// Current 返回当前生效配置的快照。快照在重新加载时被整体替换而不会被修改，
+// 调用方不得修改返回的配置；尚未设置时返回默认配置
+func Current() *Config {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+	return NewDefaultConfig()
+}
+
+// contextKey 在上下文中保存配置快照的键
+type contextKey struct{}
+
+// NewContext 返回携带配置快照的上下文，使一次工具调用中读取到的配置保持一致
+func NewContext(ctx context.Context, cfg *Config) context.Context {
+	return context.WithValue(ctx, contextKey{}, cfg)
+}
+
+// FromContext 返回上下文中的配置快照，没有时返回当前生效的配置
+func FromContext(ctx context.Context) *Config {
+	if ctx != nil {
+		if cfg, ok := ctx.Value(contextKey{}).(*Config); ok && cfg != nil {
+			return cfg
+		}
+	}
+	return Current()
+}
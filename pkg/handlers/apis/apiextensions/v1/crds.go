@@ -0,0 +1,346 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// crdGVR CustomResourceDefinition 资源的GVR
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// ListCRDs 列出集群中的CRD
+func (h *ResourceHandlerImpl) ListCRDs(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	filter, _ := arguments["filter"].(string)
+	countResources := true
+	if v, ok := arguments["countResources"].(bool); ok {
+		countResources = v
+	}
+
+	h.handler.Log.Info("Listing CRDs",
+		"filter", filter,
+		"countResources", countResources,
+	)
+
+	items, err := h.handler.Client.ListCRDs(ctx)
+	if err != nil {
+		h.handler.Log.Error("Failed to list CRDs", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list CRDs"), nil
+	}
+
+	lowerFilter := strings.ToLower(filter)
+	crds := make([]models.CRDInfo, 0, len(items))
+	for i := range items {
+		info := newCRDInfo(&items[i])
+		if lowerFilter != "" &&
+			!strings.Contains(strings.ToLower(info.Name), lowerFilter) &&
+			!strings.Contains(strings.ToLower(info.Kind), lowerFilter) {
+			continue
+		}
+
+		if countResources {
+			count, err := h.countCustomResources(ctx, info)
+			if err != nil {
+				h.handler.Log.Warn("Failed to count custom resources", "crd", info.Name, "error", err)
+				info.CountError = err.Error()
+			} else {
+				info.ResourceCount = &count
+			}
+		}
+		crds = append(crds, info)
+	}
+
+	sort.Slice(crds, func(i, j int) bool {
+		return crds[i].Name < crds[j].Name
+	})
+
+	response := models.CRDListResponse{
+		Count:       len(crds),
+		Filter:      filter,
+		CRDs:        crds,
+		RetrievedAt: time.Now(),
+	}
+
+	h.handler.Log.Info("CRDs listed successfully", "count", len(crds))
+
+	return utils.RenderResult(request, response), nil
+}
+
+// GetCRDSchema 获取CRD指定版本的OpenAPI v3模式
+func (h *ResourceHandlerImpl) GetCRDSchema(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	version, _ := arguments["version"].(string)
+	field, _ := arguments["field"].(string)
+	recursive, _ := arguments["recursive"].(bool)
+
+	h.handler.Log.Info("Getting CRD schema",
+		"name", name,
+		"version", version,
+		"field", field,
+		"recursive", recursive,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+
+	crd, err := h.handler.Client.GetDynamicClient().Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get CRD", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get CRD %s", name)), nil
+	}
+	info := newCRDInfo(crd)
+
+	// 未指定版本时使用存储版本
+	if version == "" {
+		version = storageVersion(info)
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	var openAPISchema map[string]interface{}
+	found := false
+	for _, v := range versions {
+		versionMap, ok := v.(map[string]interface{})
+		if !ok || versionMap["name"] != version {
+			continue
+		}
+		found = true
+		openAPISchema, _, _ = unstructured.NestedMap(versionMap, "schema", "openAPIV3Schema")
+		break
+	}
+	if !found {
+		available := make([]string, 0, len(info.Versions))
+		for _, v := range info.Versions {
+			available = append(available, v.Name)
+		}
+		return utils.NewErrorToolResult(fmt.Sprintf("version %s not found in CRD %s, available versions: %s",
+			version, name, strings.Join(available, ", "))), nil
+	}
+	if openAPISchema == nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("CRD %s version %s has no OpenAPI v3 schema", name, version)), nil
+	}
+
+	// 按字段路径定位子模式
+	target := openAPISchema
+	if field != "" {
+		target, err = lookupSchemaField(openAPISchema, field)
+		if err != nil {
+			return utils.NewErrorToolResult(err.Error()), nil
+		}
+	}
+
+	response := models.CRDSchemaResponse{
+		Kind:       info.Kind,
+		APIVersion: info.Group + "/" + version,
+		Resource:   info.Plural,
+		Scope:      info.Scope,
+		ShortNames: info.ShortNames,
+		Recursive:  recursive,
+		Fields:     schemaFields(target, recursive),
+	}
+	response.Description, _ = target["description"].(string)
+	if field != "" {
+		response.Field = field
+		response.FieldType = schemaTypeName(target)
+	}
+
+	return utils.RenderResult(request, response), nil
+}
+
+// countCustomResources 统计CRD对应的自定义资源数量
+// 通过 limit=1 的列表请求和返回的 remainingItemCount 计算总数，避免拉取全部对象
+func (h *ResourceHandlerImpl) countCustomResources(ctx context.Context, info models.CRDInfo) (int64, error) {
+	version := storageVersion(info)
+	if version == "" {
+		return 0, fmt.Errorf("no served version")
+	}
+
+	list, err := h.handler.Client.GetDynamicClient().Resource(schema.GroupVersionResource{
+		Group:    info.Group,
+		Version:  version,
+		Resource: info.Plural,
+	}).List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return 0, err
+	}
+
+	count := int64(len(list.Items))
+	if list.GetRemainingItemCount() != nil {
+		count += *list.GetRemainingItemCount()
+	}
+	return count, nil
+}
+
+// newCRDInfo 从非结构化CRD对象中提取摘要信息
+func newCRDInfo(crd *unstructured.Unstructured) models.CRDInfo {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+	shortNames, _, _ := unstructured.NestedStringSlice(crd.Object, "spec", "names", "shortNames")
+
+	info := models.CRDInfo{
+		Name:         crd.GetName(),
+		Group:        group,
+		Kind:         kind,
+		Plural:       plural,
+		Scope:        scope,
+		ShortNames:   shortNames,
+		CreationTime: crd.GetCreationTimestamp().Time,
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		versionMap, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := versionMap["name"].(string)
+		served, _ := versionMap["served"].(bool)
+		storage, _ := versionMap["storage"].(bool)
+		deprecated, _ := versionMap["deprecated"].(bool)
+		info.Versions = append(info.Versions, models.CRDVersionInfo{
+			Name:       name,
+			Served:     served,
+			Storage:    storage,
+			Deprecated: deprecated,
+		})
+	}
+	return info
+}
+
+// storageVersion 返回CRD的存储版本，存储版本未被服务时退回到第一个可用版本
+func storageVersion(info models.CRDInfo) string {
+	fallback := ""
+	for _, v := range info.Versions {
+		if !v.Served {
+			continue
+		}
+		if v.Storage {
+			return v.Name
+		}
+		if fallback == "" {
+			fallback = v.Name
+		}
+	}
+	return fallback
+}
+
+// lookupSchemaField 按点分隔的字段路径定位子模式，数组字段自动进入items
+func lookupSchemaField(root map[string]interface{}, path string) (map[string]interface{}, error) {
+	current := root
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		properties := schemaProperties(current)
+		next, ok := properties[part].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %q does not exist in path %s", part, path)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// schemaProperties 返回模式的子字段，数组类型返回其元素的子字段
+func schemaProperties(s map[string]interface{}) map[string]interface{} {
+	if items, ok := s["items"].(map[string]interface{}); ok && s["type"] == "array" {
+		s = items
+	}
+	properties, _ := s["properties"].(map[string]interface{})
+	return properties
+}
+
+// schemaTypeName 返回模式的类型描述，格式与 kubectl explain 一致
+func schemaTypeName(s map[string]interface{}) string {
+	typeName, _ := s["type"].(string)
+	switch typeName {
+	case "array":
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			return "[]" + schemaTypeName(items)
+		}
+		return "[]Object"
+	case "object":
+		if additional, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + schemaTypeName(additional)
+		}
+		return "Object"
+	case "":
+		if preserve, _ := s["x-kubernetes-preserve-unknown-fields"].(bool); preserve {
+			return "Object"
+		}
+		if intOrString, _ := s["x-kubernetes-int-or-string"].(bool); intOrString {
+			return "IntOrString"
+		}
+		return "Object"
+	default:
+		return typeName
+	}
+}
+
+// schemaFields 按名称排序返回模式的子字段
+// 递归模式返回完整的字段树但不包含说明，非递归模式只返回一层字段及其说明
+func schemaFields(s map[string]interface{}, recursive bool) []models.CRDSchemaField {
+	properties := schemaProperties(s)
+	required := make(map[string]bool)
+	target := s
+	if items, ok := s["items"].(map[string]interface{}); ok && s["type"] == "array" {
+		target = items
+	}
+	if requiredFields, ok := target["required"].([]interface{}); ok {
+		for _, r := range requiredFields {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]models.CRDSchemaField, 0, len(names))
+	for _, name := range names {
+		child, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		schemaField := models.CRDSchemaField{
+			Name:     name,
+			Type:     schemaTypeName(child),
+			Required: required[name],
+		}
+		if recursive {
+			schemaField.Fields = schemaFields(child, recursive)
+		} else {
+			schemaField.Description, _ = child["description"].(string)
+		}
+		fields = append(fields, schemaField)
+	}
+	return fields
+}
@@ -5,10 +5,15 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	LIST_CRDS      = "LIST_CRDS"
+	GET_CRD_SCHEMA = "GET_CRD_SCHEMA"
 )
 
 // ResourceHandlerImpl APIExtensions资源处理程序实现
@@ -32,14 +37,56 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 使用父类的处理方法
-	return h.baseHandler.Handle(ctx, request)
+	// 根据工具名称分派到具体的处理方法
+	switch request.Method {
+	case LIST_CRDS:
+		return h.ListCRDs(ctx, request)
+	case GET_CRD_SCHEMA:
+		return h.GetCRDSchema(ctx, request)
+	default:
+		// 其他方法使用父类的处理方法
+		return h.baseHandler.Handle(ctx, request)
+	}
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
-	// 使用父类的注册方法
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
+	// 注册父类的工具
 	h.baseHandler.Register(server)
+
+	// 额外注册CRD查询工具
+	server.AddTool(mcp.NewTool(LIST_CRDS,
+		mcp.WithDescription("列出集群中的自定义资源定义（CRD）。返回每个CRD的API组、版本（是否served/storage）、作用域和短名称，并统计每个CRD现有的自定义资源数量，帮助了解集群中安装了哪些Operator以及哪些CRD实际在使用。"),
+		mcp.WithString("filter",
+			mcp.Description("过滤关键字（可选）。按CRD名称或Kind进行不区分大小写的子串匹配，例如'cert-manager'或'Certificate'。"),
+		),
+		mcp.WithBoolean("countResources",
+			mcp.Description("是否统计每个CRD的自定义资源数量。每个CRD需要一次额外的API请求，CRD较多时可关闭以加快响应。默认为true。"),
+			mcp.DefaultBool(true),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListCRDs)
+
+	server.AddTool(mcp.NewTool(GET_CRD_SCHEMA,
+		mcp.WithDescription("获取指定CRD版本的OpenAPI v3模式，可按字段路径缩小范围。输出格式与EXPLAIN_RESOURCE一致，包括字段类型、是否必填和字段说明，适用于编写或校验自定义资源清单。"),
+		mcp.WithString("name",
+			mcp.Description("CRD名称，格式为<plural>.<group>，例如'certificates.cert-manager.io'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("version",
+			mcp.Description("CRD版本（可选），例如'v1'。不指定时使用存储版本。"),
+		),
+		mcp.WithString("field",
+			mcp.Description("字段路径（可选），使用点号分隔，例如'spec.secretTemplate'。数组字段会自动展开到元素类型。"),
+		),
+		mcp.WithBoolean("recursive",
+			mcp.Description("是否递归显示所有子字段。递归模式只显示字段名和类型，不显示字段说明。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetCRDSchema)
 }
 
 // GetScope 实现ToolHandler接口
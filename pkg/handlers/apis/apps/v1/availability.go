@@ -0,0 +1,343 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// zoneLabel 节点所在可用区的标准标签
+	zoneLabel = "topology.kubernetes.io/zone"
+
+	// 每个发现按严重程度扣除的可用性评分
+	criticalPenalty = 15
+	warningPenalty  = 5
+	infoPenalty     = 1
+)
+
+// auditedWorkload 参与可用性审计的工作负载
+type auditedWorkload struct {
+	kind     string
+	name     string
+	replicas int32
+	selector *metav1.LabelSelector
+	template corev1.PodTemplateSpec
+}
+
+// CheckAvailability 审计命名空间中工作负载的可用性风险：单副本、缺少或无效的PodDisruptionBudget、缺少分布约束以及Pod集中在单个节点或可用区
+func (h *ResourceHandlerImpl) CheckAvailability(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.handler.Log.Info("Checking availability", "namespace", namespace, "labelSelector", labelSelector)
+
+	clientset := h.handler.Client.ClientSet()
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+	var workloads []auditedWorkload
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, listOptions)
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list deployments"), nil
+	}
+	for _, deployment := range deployments.Items {
+		workloads = append(workloads, auditedWorkload{
+			kind:     "Deployment",
+			name:     deployment.Name,
+			replicas: derefInt32(deployment.Spec.Replicas),
+			selector: deployment.Spec.Selector,
+			template: deployment.Spec.Template,
+		})
+	}
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list statefulsets"), nil
+	}
+	for _, statefulSet := range statefulSets.Items {
+		workloads = append(workloads, auditedWorkload{
+			kind:     "StatefulSet",
+			name:     statefulSet.Name,
+			replicas: derefInt32(statefulSet.Spec.Replicas),
+			selector: statefulSet.Spec.Selector,
+			template: statefulSet.Spec.Template,
+		})
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+	activePods := make([]corev1.Pod, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			activePods = append(activePods, pod)
+		}
+	}
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list pod disruption budgets"), nil
+	}
+
+	report := &models.AvailabilityReport{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		Workloads:     []models.WorkloadAvailability{},
+		Findings:      []models.AvailabilityFinding{},
+		RetrievedAt:   time.Now(),
+	}
+
+	// 节点可用区用于判断Pod是否集中在单个可用区，无法读取节点时跳过该检查
+	nodeZones := make(map[string]string)
+	clusterZones := make(map[string]bool)
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list nodes, skipping zone checks", "error", err)
+		report.Notes = append(report.Notes, fmt.Sprintf("无法列出节点，跳过可用区检查: %v", err))
+	} else {
+		for _, node := range nodes.Items {
+			if zone := node.Labels[zoneLabel]; zone != "" {
+				nodeZones[node.Name] = zone
+				clusterZones[zone] = true
+			}
+		}
+	}
+
+	// 只审计覆盖了被审计工作负载的PDB；未指定labelSelector时审计全部PDB
+	auditedPDBs := make(map[string]bool)
+	for _, workload := range workloads {
+		report.Workloads = append(report.Workloads, h.auditWorkload(workload, namespace, activePods, pdbs.Items, nodeZones, len(clusterZones), report, auditedPDBs))
+	}
+	for _, pdb := range pdbs.Items {
+		if labelSelector != "" && !auditedPDBs[pdb.Name] {
+			continue
+		}
+		auditPDB(pdb, activePods, report)
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		if models.SeverityRank(report.Findings[i].Severity) != models.SeverityRank(report.Findings[j].Severity) {
+			return models.SeverityRank(report.Findings[i].Severity) < models.SeverityRank(report.Findings[j].Severity)
+		}
+		return report.Findings[i].Object < report.Findings[j].Object
+	})
+	report.Score = 100
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case models.SeverityCritical:
+			report.Summary.Critical++
+			report.Score -= criticalPenalty
+		case models.SeverityWarning:
+			report.Summary.Warning++
+			report.Score -= warningPenalty
+		default:
+			report.Summary.Info++
+			report.Score -= infoPenalty
+		}
+	}
+	if report.Score < 0 {
+		report.Score = 0
+	}
+
+	h.handler.Log.Info("Availability check completed", "namespace", namespace, "workloads", len(workloads), "findings", len(report.Findings), "score", report.Score)
+	return utils.RenderResult(request, report), nil
+}
+
+// auditWorkload 检查单个工作负载的副本数、PDB覆盖、分布约束以及Pod的节点和可用区分布
+func (h *ResourceHandlerImpl) auditWorkload(
+	workload auditedWorkload,
+	namespace string,
+	pods []corev1.Pod,
+	pdbs []policyv1.PodDisruptionBudget,
+	nodeZones map[string]string,
+	clusterZones int,
+	report *models.AvailabilityReport,
+	auditedPDBs map[string]bool,
+) models.WorkloadAvailability {
+	object := workload.kind + "/" + workload.name
+	result := models.WorkloadAvailability{
+		Kind:     workload.kind,
+		Name:     workload.name,
+		Replicas: workload.replicas,
+		Spread:   hasSpreadConstraints(workload.template.Spec),
+	}
+	addFinding := func(severity, check, message, suggestion, manifest string) {
+		report.Findings = append(report.Findings, models.AvailabilityFinding{
+			Severity:   severity,
+			Check:      check,
+			Object:     object,
+			Message:    message,
+			Suggestion: suggestion,
+			Manifest:   manifest,
+		})
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(workload.selector)
+	if err != nil {
+		addFinding(models.SeverityWarning, "selector", fmt.Sprintf("invalid selector: %v", err), "Fix the workload selector.", "")
+		return result
+	}
+	nodes := make(map[string]bool)
+	zones := make(map[string]bool)
+	for _, pod := range pods {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		result.RunningPods++
+		if pod.Spec.NodeName != "" {
+			nodes[pod.Spec.NodeName] = true
+			if zone := nodeZones[pod.Spec.NodeName]; zone != "" {
+				zones[zone] = true
+			}
+		}
+	}
+	result.Nodes = len(nodes)
+	result.Zones = len(zones)
+
+	for _, pdb := range pdbs {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		pdbSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !pdbSelector.Matches(labels.Set(workload.template.Labels)) {
+			continue
+		}
+		result.PodDisruptionBudgets = append(result.PodDisruptionBudgets, pdb.Name)
+		auditedPDBs[pdb.Name] = true
+	}
+
+	if workload.replicas == 0 {
+		return result
+	}
+	if workload.replicas == 1 {
+		addFinding(models.SeverityWarning, "single-replica",
+			fmt.Sprintf("%s runs a single replica, any pod restart, eviction or node drain causes downtime", object),
+			fmt.Sprintf("Scale %s to at least 2 replicas and add a PodDisruptionBudget, unless the application cannot run more than one instance.", object), "")
+		return result
+	}
+
+	if len(result.PodDisruptionBudgets) == 0 {
+		manifest, err := pdbManifest(workload, namespace)
+		if err != nil {
+			h.handler.Log.Warn("Failed to build PDB manifest", "workload", object, "error", err)
+		}
+		addFinding(models.SeverityWarning, "missing-pdb",
+			fmt.Sprintf("%s has %d replicas but no PodDisruptionBudget, a node drain may evict all of its pods at once", object, workload.replicas),
+			"Apply the PodDisruptionBudget below, which allows one pod of the workload to be disrupted at a time.", manifest)
+	}
+	if !result.Spread {
+		addFinding(models.SeverityInfo, "no-spread",
+			fmt.Sprintf("%s has no pod anti-affinity or topology spread constraints, the scheduler may place its replicas on the same node", object),
+			fmt.Sprintf("Add a topologySpreadConstraints entry with topologyKey kubernetes.io/hostname (and %s for multi-zone clusters) and whenUnsatisfiable ScheduleAnyway, selecting the workload's pod labels.", zoneLabel), "")
+	}
+	if result.RunningPods > 1 && result.Nodes == 1 {
+		addFinding(models.SeverityCritical, "single-node",
+			fmt.Sprintf("all %d running pods of %s are on a single node, losing that node takes the workload down", result.RunningPods, object),
+			"Add pod anti-affinity or topology spread constraints on kubernetes.io/hostname and restart the workload so the pods are rescheduled across nodes.", "")
+	} else if result.RunningPods > 1 && result.Zones == 1 && clusterZones > 1 {
+		addFinding(models.SeverityWarning, "single-zone",
+			fmt.Sprintf("all %d running pods of %s are in one zone although the cluster spans %d zones", result.RunningPods, object, clusterZones),
+			fmt.Sprintf("Add topology spread constraints on %s so replicas survive the loss of a zone.", zoneLabel), "")
+	}
+	return result
+}
+
+// auditPDB 检查PodDisruptionBudget是否匹配到Pod以及当前是否允许任何中断
+func auditPDB(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod, report *models.AvailabilityReport) {
+	object := "PodDisruptionBudget/" + pdb.Name
+	matched := 0
+	if pdb.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err == nil {
+			for _, pod := range pods {
+				if selector.Matches(labels.Set(pod.Labels)) {
+					matched++
+				}
+			}
+		}
+	}
+
+	if matched == 0 {
+		report.Findings = append(report.Findings, models.AvailabilityFinding{
+			Severity:   models.SeverityWarning,
+			Check:      "pdb-no-pods",
+			Object:     object,
+			Message:    fmt.Sprintf("%s selects no running pods, it protects nothing", object),
+			Suggestion: "Fix the PDB selector to match the workload's pod labels, or delete the PDB if the workload no longer exists.",
+		})
+		return
+	}
+	if pdb.Status.DisruptionsAllowed == 0 {
+		report.Findings = append(report.Findings, models.AvailabilityFinding{
+			Severity: models.SeverityCritical,
+			Check:    "pdb-blocks-disruption",
+			Object:   object,
+			Message: fmt.Sprintf("%s allows 0 disruptions (%d of %d desired pods healthy, %d pods matched), node drains and evictions of these pods will block",
+				object, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy, matched),
+			Suggestion: "Increase the workload's replicas, fix unhealthy pods, or relax minAvailable/maxUnavailable so at least one pod can be evicted.",
+		})
+	}
+}
+
+// hasSpreadConstraints 判断Pod模板是否配置了Pod反亲和性或拓扑分布约束
+func hasSpreadConstraints(spec corev1.PodSpec) bool {
+	if len(spec.TopologySpreadConstraints) > 0 {
+		return true
+	}
+	if spec.Affinity == nil || spec.Affinity.PodAntiAffinity == nil {
+		return false
+	}
+	antiAffinity := spec.Affinity.PodAntiAffinity
+	return len(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) > 0 ||
+		len(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) > 0
+}
+
+// pdbManifest 按工作负载的选择器生成maxUnavailable为1的PodDisruptionBudget清单
+func pdbManifest(workload auditedWorkload, namespace string) (string, error) {
+	maxUnavailable := intstr.FromInt32(1)
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{APIVersion: "policy/v1", Kind: "PodDisruptionBudget"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workload.name,
+			Namespace: namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       workload.selector.DeepCopy(),
+		},
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pdb)
+	if err != nil {
+		return "", err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	utils.CleanForExport(obj)
+	return utils.MarshalManifest([]*unstructured.Unstructured{obj})
+}
+
+// derefInt32 返回副本数，未设置时为1
+func derefInt32(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -2,14 +2,8 @@ package v1
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
@@ -17,6 +11,16 @@ import (
 	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
+const (
+	CHECK_AVAILABILITY = "CHECK_AVAILABILITY"
+	SET_IMAGE          = "SET_IMAGE"
+	SET_ENV            = "SET_ENV"
+
+	SET_STATEFULSET_PARTITION = "SET_STATEFULSET_PARTITION"
+	GET_STATEFULSET_PVCS      = "GET_STATEFULSET_PVCS"
+	DELETE_STATEFULSET_PVC    = "DELETE_STATEFULSET_PVC"
+)
+
 // ResourceHandlerImpl Apps资源处理程序实现
 type ResourceHandlerImpl struct {
 	handler     base.Handler
@@ -38,18 +42,201 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 检查是否是LIST_APPS_RESOURCES方法，使用我们的特殊实现
-	if request.Method == fmt.Sprintf("LIST_%s_RESOURCES", h.baseHandler.GetResourcePrefix()) {
-		return h.ListResources(ctx, request)
+	if request.Method == CHECK_AVAILABILITY {
+		return h.CheckAvailability(ctx, request)
+	}
+	if request.Method == SET_IMAGE {
+		return h.SetImage(ctx, request)
+	}
+	if request.Method == SET_ENV {
+		return h.SetEnv(ctx, request)
+	}
+	if request.Method == SET_STATEFULSET_PARTITION {
+		return h.SetStatefulSetPartition(ctx, request)
+	}
+	if request.Method == GET_STATEFULSET_PVCS {
+		return h.GetStatefulSetPVCs(ctx, request)
+	}
+	if request.Method == DELETE_STATEFULSET_PVC {
+		return h.DeleteStatefulSetPVC(ctx, request)
 	}
 	// 其他方法使用父类的处理方法
 	return h.baseHandler.Handle(ctx, request)
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
 	// 使用父类的注册方法
 	h.baseHandler.Register(server)
+
+	// 注册可用性审计工具
+	server.AddTool(mcp.NewTool(CHECK_AVAILABILITY,
+		mcp.WithDescription("审计命名空间中Deployment和StatefulSet的可用性风险：单副本工作负载、多副本但没有PodDisruptionBudget的工作负载、选择器匹配不到Pod或当前不允许任何中断（会阻塞节点排空）的PDB、没有Pod反亲和性或拓扑分布约束的工作负载，以及所有Pod集中在单个节点或单个可用区的工作负载。每个发现带有严重程度（critical/warning/info）和具体建议，缺少PDB时附带按工作负载选择器生成、可直接应用的PDB清单。返回0-100的可用性评分（每个critical扣15分、warning扣5分、info扣1分）便于排定优先级。只读操作。"),
+		mcp.WithString("namespace",
+			mcp.Description("要审计的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），只审计匹配的工作负载及覆盖它们的PDB，例如'app=nginx'。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckAvailability)
+
+	// 注册镜像更新工具
+	server.AddTool(mcp.NewTool(SET_IMAGE,
+		mcp.WithDescription("更新Deployment、StatefulSet、DaemonSet或CronJob中某个容器的镜像，等同于kubectl set image。使用只包含该容器image字段的strategic merge patch，不会修改其他容器或字段，比UPDATE_RESOURCE更安全。返回原镜像，并写入kubernetes.io/change-cause注解以便在滚动历史中查看变更原因。wait=true时阻塞直到滚动更新完成或超时。"),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型。"),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet", "CronJob"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称（可选）。工作负载只有一个容器时可以省略；也可以指定初始化容器的名称。"),
+		),
+		mcp.WithString("image",
+			mcp.Description("新镜像，例如'nginx:1.27'或'ghcr.io/org/app@sha256:...'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("changeCause",
+			mcp.Description("写入kubernetes.io/change-cause注解的变更原因（可选）。默认为'set image <容器>=<镜像>'。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否只在服务端试运行而不实际修改。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("wait",
+			mcp.Description("是否等待滚动更新完成。默认为false。CronJob没有滚动更新，新镜像在下一次调度时生效。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("waitTimeoutSeconds",
+			mcp.Description("wait=true时的最长等待时间（秒）。默认为120秒，最大为600秒。"),
+			mcp.DefaultNumber(defaultRolloutWaitSeconds),
+			mcp.Min(1),
+			mcp.Max(maxRolloutWaitSeconds),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SetImage)
+
+	// 注册环境变量修改工具
+	server.AddTool(mcp.NewTool(SET_ENV,
+		mcp.WithDescription("添加、更新或删除工作负载中某个容器的环境变量，等同于kubectl set env。按变量名与现有env合并：同名变量被替换，新变量追加到末尾，remove中的变量被删除，其余变量保持不变；补丁只修改该容器的env字段。返回修改后的完整环境变量，名称看起来敏感（如PASSWORD、TOKEN）的值会被脱敏。"),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型。"),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet", "CronJob"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称（可选）。工作负载只有一个容器时可以省略；找不到时返回可用的容器列表。"),
+		),
+		mcp.WithObject("set",
+			mcp.Description("要添加或更新的变量，变量名到值的映射，值均为字符串，例如{\"LOG_LEVEL\": \"debug\"}。"),
+		),
+		mcp.WithArray("setFrom",
+			mcp.Description("从Secret或ConfigMap引用值的变量列表，例如[{\"name\": \"DB_PASSWORD\", \"secretKeyRef\": {\"name\": \"db\", \"key\": \"password\"}}]，每项恰好包含secretKeyRef或configMapKeyRef之一。"),
+			mcp.Items(map[string]interface{}{"type": "object"}),
+		),
+		mcp.WithString("remove",
+			mcp.Description("要删除的变量名，多个用逗号分隔。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否只在服务端试运行而不实际修改。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SetEnv)
+
+	// 注册StatefulSet分区发布工具
+	server.AddTool(mcp.NewTool(SET_STATEFULSET_PARTITION,
+		mcp.WithDescription("修改StatefulSet滚动更新的分区（spec.updateStrategy.rollingUpdate.partition），用于分阶段发布：只有序号不小于分区的Pod会更新到新版本，其余Pod保持旧版本，逐步调小分区即可逐个推进，调回副本数即可暂停。返回修改前后的分区、会更新的Pod序号以及当前版本和更新版本。目标必须是使用RollingUpdate策略的StatefulSet，同名的其他工作负载会被拒绝。"),
+		mcp.WithString("name",
+			mcp.Description("StatefulSet名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("partition",
+			mcp.Description("新的分区。0表示更新所有Pod，不小于副本数表示不更新任何Pod。"),
+			mcp.Min(0),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否只在服务端试运行而不实际修改。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SetStatefulSetPartition)
+
+	// 注册StatefulSet PVC列表工具
+	server.AddTool(mcp.NewTool(GET_STATEFULSET_PVCS,
+		mcp.WithDescription("按序号列出StatefulSet由volumeClaimTemplates生成的PVC（<模板名>-<StatefulSet名>-<序号>），包括阶段、请求和实际容量、StorageClass、绑定的PV、是否正在删除以及对应Pod的阶段。缩容后保留下来、序号超出当前副本数的PVC标记为orphaned，尚未创建的PVC标记为不存在。同时返回PVC保留策略（whenDeleted/whenScaled）。只读操作。"),
+		mcp.WithString("name",
+			mcp.Description("StatefulSet名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetStatefulSetPVCs)
+
+	// 注册StatefulSet PVC删除工具
+	server.AddTool(mcp.NewTool(DELETE_STATEFULSET_PVC,
+		mcp.WithDescription("删除StatefulSet某个序号的PVC，使该序号的Pod重新创建时供应新的卷，用于卷数据损坏等场景。对应Pod仍在运行时PVC会一直处于Terminating，因此要求Pod已不存在或正在删除；deletePod=true时先删除PVC再删除Pod，控制器会用新的PVC重建Pod。返回PVC容量、绑定的PV及其回收策略（Delete表示数据随之销毁）。实际删除需要confirm=true，未确认时返回将要删除的内容。"),
+		mcp.WithString("name",
+			mcp.Description("StatefulSet名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("ordinal",
+			mcp.Description("Pod序号，例如Pod 'web-3'的序号为3。"),
+			mcp.Min(0),
+			mcp.Required(),
+		),
+		mcp.WithString("template",
+			mcp.Description("volumeClaimTemplate名称（可选）。StatefulSet只有一个模板时可以省略。"),
+		),
+		mcp.WithBoolean("deletePod",
+			mcp.Description("对应Pod仍在运行时，是否在删除PVC后删除该Pod以便用新卷重建。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("确认删除PVC。默认为false，此时只返回将要删除的内容。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否只在服务端试运行而不实际删除，不需要confirm。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DeleteStatefulSetPVC)
 }
 
 // GetScope 实现ToolHandler接口
@@ -62,131 +249,12 @@ func (h *ResourceHandlerImpl) GetAPIGroup() interfaces.APIGroup {
 	return h.handler.GetAPIGroup()
 }
 
-// ListResources 重写父类的列表方法，添加Apps特有的信息展示
+// ListResources 实现ResourceHandler接口
 func (h *ResourceHandlerImpl) ListResources(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	arguments := request.GetArguments()
-	kind, _ := arguments["kind"].(string)
-	apiVersion, _ := arguments["apiVersion"].(string)
-	namespace, _ := arguments["namespace"].(string)
-
-	h.handler.Log.Info("Listing Apps resources",
-		"kind", kind,
-		"apiVersion", apiVersion,
-		"namespace", namespace,
-	)
-
-	// 解析GroupVersionKind
-	gvk := utils.ParseGVK(apiVersion, kind)
-
-	// 创建列表对象
-	list := &unstructured.UnstructuredList{}
-	list.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   gvk.Group,
-		Version: gvk.Version,
-		Kind:    kind + "List",
-	})
-
-	// 列出资源
-	err := h.handler.Client.List(ctx, list, &clientpkg.ListOptions{Namespace: namespace})
-	if err != nil {
-		h.handler.Log.Error("Failed to list Apps resources",
-			"kind", kind,
-			"namespace", namespace,
-			"error", err,
-		)
-		return nil, fmt.Errorf("failed to list Apps resources: %v", err)
-	}
-
-	// 构建响应，为 Apps 资源特别定制
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Found %d %s resources in namespace %s:\n\n", len(list.Items), kind, namespace))
-
-	// 显示 Apps 资源的特定信息，例如 Deployments 的副本数
-	for _, item := range list.Items {
-		name := item.GetName()
-		labels := item.GetLabels()
-
-		result.WriteString(fmt.Sprintf("- %s\n", name))
-
-		// 获取特定于资源类型的详细信息
-		switch kind {
-		case "Deployment":
-			// 显示副本数和状态
-			spec, found, _ := unstructured.NestedMap(item.Object, "spec")
-			if found {
-				replicas, exists, _ := unstructured.NestedInt64(spec, "replicas")
-				if exists {
-					result.WriteString(fmt.Sprintf("  Replicas: %d\n", replicas))
-				}
-			}
-
-			status, found, _ := unstructured.NestedMap(item.Object, "status")
-			if found {
-				availableReplicas, exists, _ := unstructured.NestedInt64(status, "availableReplicas")
-				if exists {
-					result.WriteString(fmt.Sprintf("  Available: %d\n", availableReplicas))
-				}
-
-				readyReplicas, exists, _ := unstructured.NestedInt64(status, "readyReplicas")
-				if exists {
-					result.WriteString(fmt.Sprintf("  Ready: %d\n", readyReplicas))
-				}
-			}
-
-		case "StatefulSet":
-			// 显示副本数和状态
-			spec, found, _ := unstructured.NestedMap(item.Object, "spec")
-			if found {
-				replicas, exists, _ := unstructured.NestedInt64(spec, "replicas")
-				if exists {
-					result.WriteString(fmt.Sprintf("  Replicas: %d\n", replicas))
-				}
-			}
-
-		case "DaemonSet":
-			// 显示节点调度情况
-			status, found, _ := unstructured.NestedMap(item.Object, "status")
-			if found {
-				numberReady, exists, _ := unstructured.NestedInt64(status, "numberReady")
-				if exists {
-					result.WriteString(fmt.Sprintf("  Ready: %d\n", numberReady))
-				}
-
-				desiredNumberScheduled, exists, _ := unstructured.NestedInt64(status, "desiredNumberScheduled")
-				if exists {
-					result.WriteString(fmt.Sprintf("  Desired: %d\n", desiredNumberScheduled))
-				}
-			}
-		}
-
-		// 显示通用标签信息
-		if len(labels) > 0 {
-			result.WriteString("  Labels:\n")
-			for k, v := range labels {
-				result.WriteString(fmt.Sprintf("    %s: %s\n", k, v))
-			}
-		}
-
-		result.WriteString("\n")
-	}
-
-	h.handler.Log.Info("Apps resources listed successfully",
-		"kind", kind,
-		"namespace", namespace,
-		"count", len(list.Items),
-	)
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	return h.baseHandler.ListResources(ctx, request)
 }
 
 // GetResource 实现ResourceHandler接口
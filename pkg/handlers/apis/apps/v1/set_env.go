@@ -0,0 +1,266 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// SetEnv 添加、更新或删除工作负载中某个容器的环境变量
+// 按名称与现有env合并：同名变量被替换，新变量追加到末尾，remove中的变量被删除，其余变量保持原有顺序
+func (h *ResourceHandlerImpl) SetEnv(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	containerName, _ := arguments["container"].(string)
+	removeStr, _ := arguments["remove"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
+
+	h.handler.Log.Info("Setting env",
+		"kind", kind,
+		"name", name,
+		"namespace", namespace,
+		"container", containerName,
+		"remove", removeStr,
+		"dryRun", dryRun,
+	)
+
+	target, ok := workloadTargets[kind]
+	if !ok {
+		return utils.NewErrorToolResult(fmt.Sprintf("unsupported kind %q: must be one of Deployment, StatefulSet, DaemonSet, CronJob", kind)), nil
+	}
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	changes, remove, err := parseEnvChanges(arguments, removeStr)
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: err.Error(),
+		}), nil
+	}
+
+	resource := h.handler.Client.GetDynamicClient().Resource(target.gvr).Namespace(namespace)
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get workload", "kind", kind, "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+	}
+
+	podSpecPath := target.podSpecPath()
+	listField, index, container, errResult := findContainer(obj, podSpecPath, containerName)
+	if errResult != nil {
+		return errResult, nil
+	}
+	containerName, _ = container["name"].(string)
+
+	var current corev1.Container
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(container, &current); err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to decode container %s: %v", containerName, err)), nil
+	}
+
+	response := models.SetEnvResult{
+		Kind:          kind,
+		Name:          name,
+		Namespace:     namespace,
+		Container:     containerName,
+		InitContainer: listField == "initContainers",
+		DryRun:        dryRun,
+	}
+	env := mergeEnv(current.Env, changes, remove, &response)
+	response.Changed = len(response.Added)+len(response.Updated)+len(response.Removed) > 0
+	response.Env = envViews(env)
+	if !response.Changed {
+		return utils.RenderResult(request, response), nil
+	}
+
+	if denied := h.handler.PreflightCheck(ctx, "patch", target.gvr, namespace, name); denied != nil {
+		return denied, nil
+	}
+
+	// JSON补丁只替换目标容器的env，test操作确保下标对应的仍是同一个容器
+	containerPath := "/" + strings.Join(podSpecPath, "/") + fmt.Sprintf("/%s/%d", listField, index)
+	patch := []map[string]interface{}{
+		{"op": "test", "path": containerPath + "/name", "value": containerName},
+		{"op": "add", "path": containerPath + "/env", "value": env},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to encode patch: %v", err)), nil
+	}
+
+	patchOptions := metav1.PatchOptions{}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err := resource.Patch(ctx, name, types.JSONPatchType, data, patchOptions); err != nil {
+		h.handler.Log.Error("Failed to patch workload env", "kind", kind, "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to update env of %s %s", kind, name)), nil
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// parseEnvChanges 解析set、setFrom和remove参数，返回按名称排列的变更和要删除的变量名
+func parseEnvChanges(arguments map[string]interface{}, removeStr string) ([]corev1.EnvVar, []string, error) {
+	set, err := utils.StringMapArgument(arguments, "set")
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := make(map[string]string)
+	var changes []corev1.EnvVar
+	for envName, value := range set {
+		seen[envName] = "set"
+		changes = append(changes, corev1.EnvVar{Name: envName, Value: value})
+	}
+
+	rawSetFrom, _ := arguments["setFrom"].([]interface{})
+	for i, raw := range rawSetFrom {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("setFrom[%d] must be an object", i)
+		}
+		var envVar corev1.EnvVar
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item, &envVar); err != nil {
+			return nil, nil, fmt.Errorf("setFrom[%d]: %v", i, err)
+		}
+		// 同时接受{"name","secretKeyRef"}的简写形式和{"name","valueFrom":{...}}的完整形式
+		if envVar.ValueFrom == nil {
+			envVar.ValueFrom = &corev1.EnvVarSource{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item, envVar.ValueFrom); err != nil {
+				return nil, nil, fmt.Errorf("setFrom[%d]: %v", i, err)
+			}
+		}
+		if err := validateEnvSource(envVar); err != nil {
+			return nil, nil, fmt.Errorf("setFrom[%d]: %v", i, err)
+		}
+		if previous, ok := seen[envVar.Name]; ok {
+			return nil, nil, fmt.Errorf("variable %s is given in both %s and setFrom", envVar.Name, previous)
+		}
+		seen[envVar.Name] = "setFrom"
+		changes = append(changes, envVar)
+	}
+
+	var remove []string
+	for _, envName := range strings.Split(removeStr, ",") {
+		if envName = strings.TrimSpace(envName); envName == "" {
+			continue
+		}
+		if previous, ok := seen[envName]; ok {
+			return nil, nil, fmt.Errorf("variable %s is given in both %s and remove", envName, previous)
+		}
+		remove = append(remove, envName)
+	}
+
+	if len(changes) == 0 && len(remove) == 0 {
+		return nil, nil, fmt.Errorf("at least one of set, setFrom or remove is required")
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes, remove, nil
+}
+
+// validateEnvSource 校验setFrom中的变量恰好引用一个Secret或ConfigMap的键
+func validateEnvSource(envVar corev1.EnvVar) error {
+	if envVar.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	secretRef, configMapRef := envVar.ValueFrom.SecretKeyRef, envVar.ValueFrom.ConfigMapKeyRef
+	switch {
+	case secretRef != nil && configMapRef != nil:
+		return fmt.Errorf("variable %s must reference either secretKeyRef or configMapKeyRef, not both", envVar.Name)
+	case secretRef != nil:
+		if secretRef.Name == "" || secretRef.Key == "" {
+			return fmt.Errorf("variable %s: secretKeyRef requires name and key", envVar.Name)
+		}
+	case configMapRef != nil:
+		if configMapRef.Name == "" || configMapRef.Key == "" {
+			return fmt.Errorf("variable %s: configMapKeyRef requires name and key", envVar.Name)
+		}
+	default:
+		return fmt.Errorf("variable %s must reference secretKeyRef or configMapKeyRef", envVar.Name)
+	}
+	return nil
+}
+
+// mergeEnv 将变更按名称合并到现有环境变量中，并在response中记录新增、更新、删除和不存在的变量
+func mergeEnv(existing, changes []corev1.EnvVar, remove []string, response *models.SetEnvResult) []corev1.EnvVar {
+	removeSet := make(map[string]bool, len(remove))
+	for _, envName := range remove {
+		removeSet[envName] = true
+	}
+	pending := make(map[string]corev1.EnvVar, len(changes))
+	for _, change := range changes {
+		pending[change.Name] = change
+	}
+
+	merged := make([]corev1.EnvVar, 0, len(existing)+len(changes))
+	for _, envVar := range existing {
+		if removeSet[envVar.Name] {
+			response.Removed = append(response.Removed, envVar.Name)
+			delete(removeSet, envVar.Name)
+			continue
+		}
+		if change, ok := pending[envVar.Name]; ok {
+			if !reflect.DeepEqual(change, envVar) {
+				response.Updated = append(response.Updated, envVar.Name)
+				envVar = change
+			}
+			delete(pending, envVar.Name)
+		}
+		merged = append(merged, envVar)
+	}
+	for _, change := range changes {
+		if _, ok := pending[change.Name]; ok {
+			response.Added = append(response.Added, change.Name)
+			merged = append(merged, change)
+		}
+	}
+	for _, envName := range remove {
+		if removeSet[envName] {
+			response.NotFound = append(response.NotFound, envName)
+		}
+	}
+	return merged
+}
+
+// envViews 将环境变量转换为展示结构，名称看起来敏感的字面值会被脱敏
+func envViews(env []corev1.EnvVar) []models.EnvVarView {
+	views := make([]models.EnvVarView, 0, len(env))
+	for _, envVar := range env {
+		view := models.EnvVarView{Name: envVar.Name, Value: envVar.Value}
+		if envVar.Value != "" && utils.IsSensitiveName(envVar.Name) {
+			view.Value = utils.RedactValue(envVar.Value)
+			view.Redacted = true
+		}
+		if source := envVar.ValueFrom; source != nil {
+			switch {
+			case source.SecretKeyRef != nil:
+				view.ValueFrom = fmt.Sprintf("secret %s/%s", source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+			case source.ConfigMapKeyRef != nil:
+				view.ValueFrom = fmt.Sprintf("configMap %s/%s", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+			case source.FieldRef != nil:
+				view.ValueFrom = "field " + source.FieldRef.FieldPath
+			case source.ResourceFieldRef != nil:
+				view.ValueFrom = "resource " + source.ResourceFieldRef.Resource
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// changeCauseAnnotation 记录变更原因的注解，会显示在rollout history中
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+	// 等待滚动更新完成的默认时长和最大时长（秒）
+	defaultRolloutWaitSeconds = 120
+	maxRolloutWaitSeconds     = 600
+	// maxImageNameLength 镜像名称（不含标签和摘要）的最大长度
+	maxImageNameLength = 255
+)
+
+// imageReferencePattern 镜像引用格式：[registry[:port]/]path[:tag][@digest]
+var imageReferencePattern = regexp.MustCompile(
+	`^(?:(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+		`(?::[\w][\w.-]{0,127})?` +
+		`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+
+// SetImage 通过只包含目标容器image字段的strategic merge patch更新工作负载的容器镜像，
+// 记录change-cause注解，可选等待滚动更新完成
+func (h *ResourceHandlerImpl) SetImage(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	containerName, _ := arguments["container"].(string)
+	image, _ := arguments["image"].(string)
+	image = strings.TrimSpace(image)
+	changeCause, _ := arguments["changeCause"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
+	wait, _ := arguments["wait"].(bool)
+	waitSeconds := defaultRolloutWaitSeconds
+	if value, ok := arguments["waitTimeoutSeconds"].(float64); ok && value > 0 {
+		waitSeconds = min(int(value), maxRolloutWaitSeconds)
+	}
+
+	h.handler.Log.Info("Setting image",
+		"kind", kind,
+		"name", name,
+		"namespace", namespace,
+		"container", containerName,
+		"image", image,
+		"dryRun", dryRun,
+		"wait", wait,
+	)
+
+	target, ok := workloadTargets[kind]
+	if !ok {
+		return utils.NewErrorToolResult(fmt.Sprintf("unsupported kind %q: must be one of Deployment, StatefulSet, DaemonSet, CronJob", kind)), nil
+	}
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	if err := validateImageReference(image); err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: err.Error(),
+			Hint:    "Use the form [registry[:port]/]repository[:tag][@sha256:digest], e.g. 'nginx:1.27' or 'ghcr.io/org/app@sha256:...'.",
+		}), nil
+	}
+
+	resource := h.handler.Client.GetDynamicClient().Resource(target.gvr).Namespace(namespace)
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get workload", "kind", kind, "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+	}
+
+	podSpecPath := target.podSpecPath()
+	listField, _, container, errResult := findContainer(obj, podSpecPath, containerName)
+	if errResult != nil {
+		return errResult, nil
+	}
+	oldImage, _ := container["image"].(string)
+	containerName, _ = container["name"].(string)
+
+	if changeCause == "" {
+		changeCause = fmt.Sprintf("set image %s=%s", containerName, image)
+	}
+	response := models.SetImageResult{
+		Kind:          kind,
+		Name:          name,
+		Namespace:     namespace,
+		Container:     containerName,
+		InitContainer: listField == "initContainers",
+		OldImage:      oldImage,
+		NewImage:      image,
+		Changed:       oldImage != image,
+		DryRun:        dryRun,
+	}
+	if !response.Changed {
+		return utils.RenderResult(request, response), nil
+	}
+	response.ChangeCause = changeCause
+
+	if denied := h.handler.PreflightCheck(ctx, "patch", target.gvr, namespace, name); denied != nil {
+		return denied, nil
+	}
+
+	// 补丁只包含目标容器的name和image，按name合并到容器列表中，其他容器和字段保持不变
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{changeCauseAnnotation: changeCause},
+		},
+	}
+	podSpec := map[string]interface{}{
+		listField: []interface{}{map[string]interface{}{"name": containerName, "image": image}},
+	}
+	if err := unstructured.SetNestedField(patch, podSpec, podSpecPath...); err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to build patch: %v", err)), nil
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to encode patch: %v", err)), nil
+	}
+
+	patchOptions := metav1.PatchOptions{}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err := resource.Patch(ctx, name, types.StrategicMergePatchType, data, patchOptions); err != nil {
+		h.handler.Log.Error("Failed to patch workload image", "kind", kind, "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to update image of %s %s", kind, name)), nil
+	}
+
+	if !wait || dryRun {
+		return utils.RenderResult(request, response), nil
+	}
+	if kind == "CronJob" {
+		response.Rollout = &models.RolloutWaitResult{
+			Complete: true,
+			Message:  "CronJob has no rollout; the new image is used by the next scheduled job",
+			Waited:   "0s",
+		}
+		return utils.RenderResult(request, response), nil
+	}
+
+	start := time.Now()
+	complete, message, err := utils.WaitForRollout(ctx, time.Duration(waitSeconds)*time.Second, resource, name)
+	response.Rollout = &models.RolloutWaitResult{
+		Complete: complete,
+		Message:  message,
+		Waited:   time.Since(start).Round(time.Second).String(),
+	}
+	if err != nil {
+		response.Rollout.Message = err.Error()
+		response.Rollout.Hint = "The image was updated but the rollout is not progressing; check the pods with DIAGNOSE_POD or roll back the image."
+	} else if !complete {
+		response.Rollout.TimedOut = true
+		response.Rollout.Hint = "The rollout is still in progress; call again with a longer waitTimeoutSeconds or inspect the pods."
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// validateImageReference 校验镜像引用的格式
+func validateImageReference(image string) error {
+	if image == "" {
+		return fmt.Errorf("missing required parameter: image")
+	}
+	if !imageReferencePattern.MatchString(image) {
+		return fmt.Errorf("invalid image reference %q", image)
+	}
+	imageName, _, _ := strings.Cut(image, "@")
+	if index := strings.LastIndex(imageName, ":"); index > strings.LastIndex(imageName, "/") {
+		imageName = imageName[:index]
+	}
+	if len(imageName) > maxImageNameLength {
+		return fmt.Errorf("image name %q exceeds %d characters", imageName, maxImageNameLength)
+	}
+	return nil
+}
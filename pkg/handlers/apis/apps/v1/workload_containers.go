@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// workloadTarget 支持修改容器的资源类型及其Pod模板的路径
+type workloadTarget struct {
+	gvr          schema.GroupVersionResource
+	templatePath []string
+}
+
+var workloadTargets = map[string]workloadTarget{
+	"Deployment": {
+		gvr:          schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		templatePath: []string{"spec", "template"},
+	},
+	"StatefulSet": {
+		gvr:          schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		templatePath: []string{"spec", "template"},
+	},
+	"DaemonSet": {
+		gvr:          schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		templatePath: []string{"spec", "template"},
+	},
+	"CronJob": {
+		gvr:          schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"},
+		templatePath: []string{"spec", "jobTemplate", "spec", "template"},
+	},
+}
+
+// podSpecPath 返回Pod模板中spec的路径
+func (t workloadTarget) podSpecPath() []string {
+	return append(append([]string{}, t.templatePath...), "spec")
+}
+
+// findContainer 在Pod模板中查找要修改的容器，返回容器所在的列表字段、在列表中的下标和容器本身
+// 未指定容器名称时要求模板中只有一个容器；找不到时返回的错误结果中列出可用的容器
+func findContainer(
+	obj *unstructured.Unstructured,
+	podSpecPath []string,
+	containerName string,
+) (string, int, map[string]interface{}, *mcp.CallToolResult) {
+	containers, _, _ := unstructured.NestedSlice(obj.Object, append(append([]string{}, podSpecPath...), "containers")...)
+	if containerName == "" {
+		if len(containers) != 1 {
+			return "", 0, nil, utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: fmt.Sprintf("%s %s has %d containers, the container parameter is required", obj.GetKind(), obj.GetName(), len(containers)),
+				Details: containerNames(containers),
+			})
+		}
+		container, _ := containers[0].(map[string]interface{})
+		return "containers", 0, container, nil
+	}
+
+	var names []string
+	for _, field := range []string{"containers", "initContainers"} {
+		items, _, _ := unstructured.NestedSlice(obj.Object, append(append([]string{}, podSpecPath...), field)...)
+		for index, item := range items {
+			container, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if container["name"] == containerName {
+				return field, index, container, nil
+			}
+		}
+		names = append(names, containerNames(items)...)
+	}
+	return "", 0, nil, utils.NewToolErrorResult(models.ToolError{
+		Code:    utils.ErrorCodeNotFound,
+		Message: fmt.Sprintf("container %q not found in %s %s", containerName, obj.GetKind(), obj.GetName()),
+		Details: names,
+	})
+}
+
+// containerNames 返回容器列表中的名称
+func containerNames(containers []interface{}) []string {
+	names := make([]string, 0, len(containers))
+	for _, item := range containers {
+		if container, ok := item.(map[string]interface{}); ok {
+			if name, ok := container["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
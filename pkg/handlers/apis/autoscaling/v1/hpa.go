@@ -0,0 +1,588 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// defaultHPAEvents 每个HPA默认返回的事件数量
+	defaultHPAEvents = 5
+	// maxHPAEvents 每个HPA最多返回的事件数量
+	maxHPAEvents = 50
+)
+
+var hpaGVR = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+
+// GetHPAStatus 列出HPA的副本数、指标当前值与目标值、扩缩容条件和近期事件，并检查常见的配置问题
+func (h *ResourceHandlerImpl) GetHPAStatus(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	maxEvents := defaultHPAEvents
+	if value, ok := arguments["maxEvents"].(float64); ok {
+		maxEvents = int(value)
+	}
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.handler.Log.Info("Getting HPA status",
+		"name", name,
+		"namespace", namespace,
+		"labelSelector", labelSelector,
+		"maxEvents", maxEvents,
+	)
+
+	if name != "" && namespace == "" {
+		namespace = h.baseHandler.GetNamespaceWithDefault(namespace)
+	}
+	if maxEvents < 0 || maxEvents > maxHPAEvents {
+		return utils.NewErrorToolResult(fmt.Sprintf("maxEvents must be between 0 and %d", maxHPAEvents)), nil
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to parse label selector: %v", err)), nil
+	}
+
+	// 列出命名空间中的所有HPA以检测指向同一工作负载的多个HPA，名称和标签选择器在本地过滤
+	hpaList, err := h.handler.Client.ClientSet().AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list HPAs", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list horizontalpodautoscalers"), nil
+	}
+	targets := make(map[string][]string)
+	for _, hpa := range hpaList.Items {
+		key := scaleTargetKey(&hpa)
+		targets[key] = append(targets[key], hpa.Name)
+	}
+
+	response := models.HPAStatusList{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		Items:         []models.HPAStatus{},
+		RetrievedAt:   time.Now(),
+	}
+
+	var selected []autoscalingv2.HorizontalPodAutoscaler
+	for _, hpa := range hpaList.Items {
+		if name != "" && hpa.Name != name {
+			continue
+		}
+		if !selector.Matches(labels.Set(hpa.Labels)) {
+			continue
+		}
+		selected = append(selected, hpa)
+	}
+	if name != "" && len(selected) == 0 {
+		return utils.NewErrorToolResult(fmt.Sprintf("HorizontalPodAutoscaler %s not found in namespace %s", name, namespace)), nil
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].Namespace != selected[j].Namespace {
+			return selected[i].Namespace < selected[j].Namespace
+		}
+		return selected[i].Name < selected[j].Name
+	})
+
+	events := make(map[string][]corev1.Event)
+	if maxEvents > 0 && len(selected) > 0 {
+		eventList, err := h.handler.Client.ClientSet().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("involvedObject.kind", "HorizontalPodAutoscaler").String(),
+		})
+		if err != nil {
+			h.handler.Log.Warn("Failed to list HPA events", "namespace", namespace, "error", err)
+			response.Errors = append(response.Errors, fmt.Sprintf("failed to list HPA events: %v", err))
+		} else {
+			for _, event := range eventList.Items {
+				key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+				events[key] = append(events[key], event)
+			}
+		}
+	}
+
+	// 同一工作负载只读取一次
+	containers := make(map[string][]corev1.Container)
+	for i := range selected {
+		hpa := &selected[i]
+		item := hpaStatus(hpa, events[hpa.Namespace+"/"+hpa.Name], maxEvents)
+
+		if others := targets[scaleTargetKey(hpa)]; len(others) > 1 {
+			item.Problems = append(item.Problems, fmt.Sprintf("%s/%s is targeted by %d HPAs (%s); they will fight over the replica count",
+				item.ScaleTarget.Kind, item.ScaleTarget.Name, len(others), strings.Join(others, ", ")))
+		}
+
+		key := scaleTargetKey(hpa)
+		workload, ok := containers[key]
+		if !ok {
+			workload, err = h.scaleTargetContainers(ctx, hpa)
+			if err != nil {
+				item.Problems = append(item.Problems, fmt.Sprintf("cannot read scale target %s/%s: %v",
+					item.ScaleTarget.Kind, item.ScaleTarget.Name, err))
+			}
+			containers[key] = workload
+		}
+		item.Problems = append(item.Problems, missingRequestProblems(hpa, workload)...)
+
+		if len(item.Problems) > 0 {
+			response.WithProblems++
+		}
+		response.Items = append(response.Items, item)
+	}
+	response.Count = len(response.Items)
+
+	h.handler.Log.Info("HPA status retrieved", "count", response.Count, "withProblems", response.WithProblems)
+	return utils.RenderResult(request, response), nil
+}
+
+// SetHPABounds 修改HPA的minReplicas和maxReplicas
+func (h *ResourceHandlerImpl) SetHPABounds(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	dryRun, _ := arguments["dryRun"].(bool)
+	minArg, hasMin := arguments["minReplicas"].(float64)
+	maxArg, hasMax := arguments["maxReplicas"].(float64)
+
+	h.handler.Log.Info("Setting HPA bounds",
+		"name", name,
+		"namespace", namespace,
+		"minReplicas", minArg,
+		"maxReplicas", maxArg,
+		"dryRun", dryRun,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("HorizontalPodAutoscaler name is required"), nil
+	}
+	if !hasMin && !hasMax {
+		return utils.NewErrorToolResult("at least one of minReplicas or maxReplicas is required"), nil
+	}
+	if hasMin && (minArg < 0 || minArg != float64(int32(minArg))) {
+		return utils.NewErrorToolResult(fmt.Sprintf("minReplicas must be a non-negative integer, got %v", minArg)), nil
+	}
+	if hasMax && (maxArg < 1 || maxArg != float64(int32(maxArg))) {
+		return utils.NewErrorToolResult(fmt.Sprintf("maxReplicas must be a positive integer, got %v", maxArg)), nil
+	}
+
+	hpas := h.handler.Client.ClientSet().AutoscalingV2().HorizontalPodAutoscalers(namespace)
+	hpa, err := hpas.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get HPA", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get horizontalpodautoscaler %s", name)), nil
+	}
+
+	previousMin := derefInt32(hpa.Spec.MinReplicas, 1)
+	previousMax := hpa.Spec.MaxReplicas
+	newMin, newMax := previousMin, previousMax
+	if hasMin {
+		newMin = int32(minArg)
+	}
+	if hasMax {
+		newMax = int32(maxArg)
+	}
+	if newMin > newMax {
+		return utils.NewErrorToolResult(fmt.Sprintf("minReplicas (%d) must not be greater than maxReplicas (%d)", newMin, newMax)), nil
+	}
+
+	response := models.SetHPABoundsResponse{
+		Name:                hpa.Name,
+		Namespace:           hpa.Namespace,
+		PreviousMinReplicas: previousMin,
+		PreviousMaxReplicas: previousMax,
+		MinReplicas:         newMin,
+		MaxReplicas:         newMax,
+		CurrentReplicas:     hpa.Status.CurrentReplicas,
+		Changed:             newMin != previousMin || newMax != previousMax,
+		DryRun:              dryRun,
+	}
+
+	if response.Changed {
+		if denied := h.handler.PreflightCheck(ctx, "patch", hpaGVR, namespace, name); denied != nil {
+			return denied, nil
+		}
+
+		// 只修改发生变化的字段
+		spec := map[string]int32{}
+		if newMin != previousMin {
+			spec["minReplicas"] = newMin
+		}
+		if newMax != previousMax {
+			spec["maxReplicas"] = newMax
+		}
+		patch, err := json.Marshal(map[string]any{"spec": spec})
+		if err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("failed to build patch: %v", err)), nil
+		}
+		patchOptions := metav1.PatchOptions{}
+		if dryRun {
+			patchOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		if _, err := hpas.Patch(ctx, name, types.MergePatchType, patch, patchOptions); err != nil {
+			h.handler.Log.Error("Failed to patch HPA", "name", name, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to patch horizontalpodautoscaler %s", name)), nil
+		}
+	}
+
+	current := hpa.Status.CurrentReplicas
+	switch {
+	case current > newMax:
+		response.Warnings = append(response.Warnings, fmt.Sprintf("current replicas (%d) exceed maxReplicas; the HPA will scale %s/%s down to %d",
+			current, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name, newMax))
+	case current < newMin:
+		response.Warnings = append(response.Warnings, fmt.Sprintf("current replicas (%d) are below minReplicas; the HPA will scale %s/%s up to %d",
+			current, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name, newMin))
+	}
+	if newMin == 0 {
+		response.Warnings = append(response.Warnings, "minReplicas 0 requires the HPAScaleToZero feature gate and at least one Object or External metric")
+	}
+
+	h.handler.Log.Info("HPA bounds set", "name", name, "namespace", namespace, "changed", response.Changed, "dryRun", dryRun)
+	return utils.RenderResult(request, response), nil
+}
+
+// scaleTargetContainers 读取HPA目标工作负载的Pod模板中的容器
+func (h *ResourceHandlerImpl) scaleTargetContainers(
+	ctx context.Context,
+	hpa *autoscalingv2.HorizontalPodAutoscaler,
+) ([]corev1.Container, error) {
+	ref := hpa.Spec.ScaleTargetRef
+	apiVersion := ref.APIVersion
+	if apiVersion == "" {
+		apiVersion = "apps/v1"
+	}
+	gvr, _, err := utils.ResolveGVR(h.handler.Client, apiVersion, ref.Kind)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := h.handler.Client.GetDynamicClient().Resource(gvr).Namespace(hpa.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	items, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found {
+		// 没有Pod模板的自定义资源无法检查资源请求
+		return nil, nil
+	}
+	containers := make([]corev1.Container, 0, len(items))
+	for _, item := range items {
+		content, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		var container corev1.Container
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &container); err != nil {
+			return nil, fmt.Errorf("failed to decode container: %w", err)
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// hpaStatus 构建HPA的状态概要
+func hpaStatus(hpa *autoscalingv2.HorizontalPodAutoscaler, events []corev1.Event, maxEvents int) models.HPAStatus {
+	ref := hpa.Spec.ScaleTargetRef
+	item := models.HPAStatus{
+		Name:      hpa.Name,
+		Namespace: hpa.Namespace,
+		ScaleTarget: models.HPAScaleTarget{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+		},
+		MinReplicas:     derefInt32(hpa.Spec.MinReplicas, 1),
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         []models.HPAMetric{},
+	}
+	if hpa.Status.LastScaleTime != nil {
+		lastScale := hpa.Status.LastScaleTime.Time
+		item.LastScaleTime = &lastScale
+	}
+
+	current := make(map[string]autoscalingv2.MetricStatus, len(hpa.Status.CurrentMetrics))
+	for _, status := range hpa.Status.CurrentMetrics {
+		current[metricStatusKey(status)] = status
+	}
+	for _, spec := range hpa.Spec.Metrics {
+		metric, target := hpaMetric(spec)
+		if status, ok := current[metricSpecKey(spec)]; ok {
+			metric.Current = metricValueString(metricStatusValue(status), target.Type)
+		}
+		item.Metrics = append(item.Metrics, metric)
+	}
+
+	for _, condition := range hpa.Status.Conditions {
+		item.Conditions = append(item.Conditions, models.HPACondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+		switch {
+		case condition.Type == autoscalingv2.AbleToScale && condition.Status == corev1.ConditionFalse:
+			item.Problems = append(item.Problems, fmt.Sprintf("cannot scale: %s (%s)", condition.Reason, condition.Message))
+		case condition.Type == autoscalingv2.ScalingActive && condition.Status == corev1.ConditionFalse:
+			item.Problems = append(item.Problems, fmt.Sprintf("scaling is inactive: %s (%s)", condition.Reason, condition.Message))
+		case condition.Type == autoscalingv2.ScalingLimited && condition.Status == corev1.ConditionTrue && condition.Reason == "TooManyReplicas":
+			item.Problems = append(item.Problems, fmt.Sprintf("desired replicas are capped at maxReplicas (%d): %s", item.MaxReplicas, condition.Message))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return utils.EventLastSeen(events[i]).After(utils.EventLastSeen(events[j]))
+	})
+	for _, event := range events {
+		if len(item.Events) >= maxEvents {
+			break
+		}
+		item.Events = append(item.Events, models.HPAEvent{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    event.Count,
+			LastSeen: utils.EventLastSeen(event),
+		})
+	}
+	return item
+}
+
+// hpaMetric 将指标规格转换为输出结构，同时返回其目标
+func hpaMetric(spec autoscalingv2.MetricSpec) (models.HPAMetric, autoscalingv2.MetricTarget) {
+	metric := models.HPAMetric{Type: string(spec.Type)}
+	var target autoscalingv2.MetricTarget
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			metric.Name = string(spec.Resource.Name)
+			target = spec.Resource.Target
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			metric.Name = string(spec.ContainerResource.Name)
+			metric.Container = spec.ContainerResource.Container
+			target = spec.ContainerResource.Target
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			metric.Name = spec.Pods.Metric.Name
+			metric.Selector = metricSelectorString(spec.Pods.Metric.Selector)
+			target = spec.Pods.Target
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			metric.Name = spec.Object.Metric.Name
+			metric.Object = spec.Object.DescribedObject.Kind + "/" + spec.Object.DescribedObject.Name
+			metric.Selector = metricSelectorString(spec.Object.Metric.Selector)
+			target = spec.Object.Target
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			metric.Name = spec.External.Metric.Name
+			metric.Selector = metricSelectorString(spec.External.Metric.Selector)
+			target = spec.External.Target
+		}
+	}
+	metric.TargetType = string(target.Type)
+	metric.Target = metricValueString(autoscalingv2.MetricValueStatus{
+		Value:              target.Value,
+		AverageValue:       target.AverageValue,
+		AverageUtilization: target.AverageUtilization,
+	}, target.Type)
+	return metric, target
+}
+
+// metricSpecKey 返回用于匹配指标规格与指标状态的键
+func metricSpecKey(spec autoscalingv2.MetricSpec) string {
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			return "Resource/" + string(spec.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			return "ContainerResource/" + spec.ContainerResource.Container + "/" + string(spec.ContainerResource.Name)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			return "Pods/" + spec.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			return "Object/" + spec.Object.DescribedObject.Kind + "/" + spec.Object.DescribedObject.Name + "/" + spec.Object.Metric.Name
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			return "External/" + spec.External.Metric.Name
+		}
+	}
+	return string(spec.Type)
+}
+
+// metricStatusKey 返回指标状态对应的键，与metricSpecKey一致
+func metricStatusKey(status autoscalingv2.MetricStatus) string {
+	switch status.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if status.Resource != nil {
+			return "Resource/" + string(status.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if status.ContainerResource != nil {
+			return "ContainerResource/" + status.ContainerResource.Container + "/" + string(status.ContainerResource.Name)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if status.Pods != nil {
+			return "Pods/" + status.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if status.Object != nil {
+			return "Object/" + status.Object.DescribedObject.Kind + "/" + status.Object.DescribedObject.Name + "/" + status.Object.Metric.Name
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if status.External != nil {
+			return "External/" + status.External.Metric.Name
+		}
+	}
+	return string(status.Type)
+}
+
+// metricStatusValue 返回指标状态中的当前值
+func metricStatusValue(status autoscalingv2.MetricStatus) autoscalingv2.MetricValueStatus {
+	switch status.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if status.Resource != nil {
+			return status.Resource.Current
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if status.ContainerResource != nil {
+			return status.ContainerResource.Current
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if status.Pods != nil {
+			return status.Pods.Current
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if status.Object != nil {
+			return status.Object.Current
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if status.External != nil {
+			return status.External.Current
+		}
+	}
+	return autoscalingv2.MetricValueStatus{}
+}
+
+// metricValueString 按目标类型格式化指标值，利用率以百分比表示
+func metricValueString(value autoscalingv2.MetricValueStatus, targetType autoscalingv2.MetricTargetType) string {
+	switch targetType {
+	case autoscalingv2.UtilizationMetricType:
+		if value.AverageUtilization != nil {
+			return fmt.Sprintf("%d%%", *value.AverageUtilization)
+		}
+	case autoscalingv2.AverageValueMetricType:
+		if value.AverageValue != nil {
+			return value.AverageValue.String()
+		}
+	case autoscalingv2.ValueMetricType:
+		if value.Value != nil {
+			return value.Value.String()
+		}
+	}
+	return ""
+}
+
+// metricSelectorString 返回指标的标签选择器字符串
+func metricSelectorString(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return ""
+	}
+	return parsed.String()
+}
+
+// missingRequestProblems 检查按利用率扩缩容的资源指标所需的容器资源请求
+// 任一容器缺少对应资源的请求时，HPA无法计算利用率
+func missingRequestProblems(hpa *autoscalingv2.HorizontalPodAutoscaler, containers []corev1.Container) []string {
+	if len(containers) == 0 {
+		return nil
+	}
+	var problems []string
+	for _, spec := range hpa.Spec.Metrics {
+		var resourceName corev1.ResourceName
+		var container string
+		switch {
+		case spec.Type == autoscalingv2.ResourceMetricSourceType && spec.Resource != nil &&
+			spec.Resource.Target.Type == autoscalingv2.UtilizationMetricType:
+			resourceName = spec.Resource.Name
+		case spec.Type == autoscalingv2.ContainerResourceMetricSourceType && spec.ContainerResource != nil &&
+			spec.ContainerResource.Target.Type == autoscalingv2.UtilizationMetricType:
+			resourceName = spec.ContainerResource.Name
+			container = spec.ContainerResource.Container
+		default:
+			continue
+		}
+		var missing []string
+		for _, c := range containers {
+			if container != "" && c.Name != container {
+				continue
+			}
+			if _, ok := c.Resources.Requests[resourceName]; !ok {
+				missing = append(missing, c.Name)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("container(s) %s of %s/%s set no %s request; %s utilization can never be computed",
+				strings.Join(missing, ", "), hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name, resourceName, resourceName))
+		}
+	}
+	return problems
+}
+
+// scaleTargetKey 返回HPA目标工作负载的唯一键
+func scaleTargetKey(hpa *autoscalingv2.HorizontalPodAutoscaler) string {
+	ref := hpa.Spec.ScaleTargetRef
+	group := ""
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil {
+		group = gv.Group
+	}
+	return hpa.Namespace + "/" + group + "/" + ref.Kind + "/" + ref.Name
+}
+
+// derefInt32 返回指针的值，为nil时返回默认值
+func derefInt32(value *int32, fallback int32) int32 {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
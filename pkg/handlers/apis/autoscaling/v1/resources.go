@@ -5,10 +5,15 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	GET_HPA_STATUS = "GET_HPA_STATUS"
+	SET_HPA_BOUNDS = "SET_HPA_BOUNDS"
 )
 
 // ResourceHandlerImpl Autoscaling资源处理程序实现
@@ -32,14 +37,67 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 使用父类的处理方法
-	return h.baseHandler.Handle(ctx, request)
+	// 根据工具名称分派到具体的处理方法
+	switch request.Method {
+	case GET_HPA_STATUS:
+		return h.GetHPAStatus(ctx, request)
+	case SET_HPA_BOUNDS:
+		return h.SetHPABounds(ctx, request)
+	default:
+		// 其他方法使用父类的处理方法
+		return h.baseHandler.Handle(ctx, request)
+	}
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
-	// 使用父类的注册方法
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
+	// 注册父类的工具
 	h.baseHandler.Register(server)
+
+	// 注册HPA状态工具
+	server.AddTool(mcp.NewTool(GET_HPA_STATUS,
+		mcp.WithDescription("列出HorizontalPodAutoscaler的扩缩容状态：当前与期望副本数、各指标（autoscaling/v2的Resource、ContainerResource、Pods、Object、External类型）的当前值与目标值、AbleToScale、ScalingActive、ScalingLimited条件及原因、近期扩缩容事件。同时检查常见配置问题：目标工作负载的容器未设置资源请求导致利用率无法计算、多个HPA指向同一工作负载。"),
+		mcp.WithString("namespace",
+			mcp.Description("HPA所在的命名空间（可选），不指定时列出所有命名空间；指定name时默认为'default'命名空间。"),
+		),
+		mcp.WithString("name",
+			mcp.Description("HPA名称（可选），不指定时列出命名空间中的所有HPA。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Kubernetes标签选择器（可选），只返回标签匹配的HPA。"),
+		),
+		mcp.WithNumber("maxEvents",
+			mcp.Description("每个HPA最多返回的近期事件数量，0表示不查询事件。默认为5，最大为50。"),
+			mcp.DefaultNumber(defaultHPAEvents),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetHPAStatus)
+
+	// 注册HPA副本数上下限修改工具
+	server.AddTool(mcp.NewTool(SET_HPA_BOUNDS,
+		mcp.WithDescription("修改HPA的minReplicas和/或maxReplicas，只通过补丁修改发生变化的字段。校验minReplicas不大于maxReplicas且maxReplicas大于0；当前副本数超出新的上下限时在结果中提示HPA将随之扩缩容。"),
+		mcp.WithString("name",
+			mcp.Description("HPA名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("HPA所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("minReplicas",
+			mcp.Description("新的最小副本数（可选），不指定时保持不变。"),
+		),
+		mcp.WithNumber("maxReplicas",
+			mcp.Description("新的最大副本数（可选），不指定时保持不变，必须大于0。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SetHPABounds)
 }
 
 // GetScope 实现ToolHandler接口
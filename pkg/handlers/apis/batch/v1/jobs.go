@@ -0,0 +1,439 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 失败Job默认获取的日志行数
+	defaultJobTailLines = 50
+	// 读取Job日志的字节上限
+	maxJobLogBytes = 256 * 1024
+	// 手动触发的Job名称最大长度，Job名称会作为标签值使用
+	maxJobNameLength = 63
+	// kubectl create job --from 使用的实例化注解
+	cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+)
+
+var (
+	jobsGVR     = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	cronJobsGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+)
+
+// TriggerCronJob 根据CronJob的jobTemplate手动创建一个Job，等同于 kubectl create job --from=cronjob/x
+func (h *ResourceHandlerImpl) TriggerCronJob(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	jobName, _ := arguments["jobName"].(string)
+	force, _ := arguments["force"].(bool)
+	dryRun, _ := arguments["dryRun"].(bool)
+
+	h.handler.Log.Info("Triggering cronjob",
+		"name", name,
+		"namespace", namespace,
+		"jobName", jobName,
+		"force", force,
+		"dryRun", dryRun,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("CronJob name is required"), nil
+	}
+
+	batchClient := h.handler.Client.ClientSet().BatchV1()
+	cronJob, err := batchClient.CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get cronjob", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get cronjob %s", name)), nil
+	}
+
+	activeJobs := activeJobNames(cronJob)
+	if cronJob.Spec.ConcurrencyPolicy == batchv1.ForbidConcurrent && len(activeJobs) > 0 && !force {
+		return utils.NewErrorToolResult(fmt.Sprintf(
+			"CronJob '%s' has concurrencyPolicy Forbid and %d active job(s): %s; pass force=true to trigger anyway",
+			name, len(activeJobs), strings.Join(activeJobs, ", "),
+		)), nil
+	}
+
+	now := time.Now()
+	if jobName == "" {
+		jobName = manualJobName(cronJob.Name, now)
+	}
+
+	if denied := h.handler.PreflightCheck(ctx, "create", jobsGVR, namespace, jobName); denied != nil {
+		return denied, nil
+	}
+
+	job := jobFromCronJob(cronJob, jobName)
+	createOptions := metav1.CreateOptions{}
+	if dryRun {
+		createOptions.DryRun = []string{metav1.DryRunAll}
+	}
+	created, err := batchClient.Jobs(namespace).Create(ctx, job, createOptions)
+	if err != nil {
+		h.handler.Log.Error("Failed to create job from cronjob", "cronJob", name, "job", jobName, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to create job %s", jobName)), nil
+	}
+
+	response := models.TriggerCronJobResponse{
+		CronJob:     cronJob.Name,
+		Namespace:   namespace,
+		JobName:     created.Name,
+		DryRun:      dryRun,
+		Forced:      force && len(activeJobs) > 0,
+		ActiveJobs:  activeJobs,
+		Labels:      created.Labels,
+		Annotations: created.Annotations,
+		CreatedAt:   now,
+	}
+
+	h.handler.Log.Info("Job created from cronjob", "cronJob", name, "job", created.Name, "dryRun", dryRun)
+	return utils.RenderResult(request, response), nil
+}
+
+// SuspendCronJob 暂停CronJob的调度
+func (h *ResourceHandlerImpl) SuspendCronJob(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return h.setCronJobSuspend(ctx, request, true)
+}
+
+// ResumeCronJob 恢复CronJob的调度
+func (h *ResourceHandlerImpl) ResumeCronJob(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return h.setCronJobSuspend(ctx, request, false)
+}
+
+// setCronJobSuspend 通过patch设置CronJob的spec.suspend字段
+func (h *ResourceHandlerImpl) setCronJobSuspend(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	suspend bool,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	dryRun, _ := arguments["dryRun"].(bool)
+
+	h.handler.Log.Info("Setting cronjob suspend",
+		"name", name,
+		"namespace", namespace,
+		"suspend", suspend,
+		"dryRun", dryRun,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("CronJob name is required"), nil
+	}
+
+	batchClient := h.handler.Client.ClientSet().BatchV1()
+	cronJob, err := batchClient.CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get cronjob", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get cronjob %s", name)), nil
+	}
+
+	current := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+	if current != suspend {
+		if denied := h.handler.PreflightCheck(ctx, "patch", cronJobsGVR, namespace, name); denied != nil {
+			return denied, nil
+		}
+
+		patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+		patchOptions := metav1.PatchOptions{}
+		if dryRun {
+			patchOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		cronJob, err = batchClient.CronJobs(namespace).Patch(ctx, name, types.MergePatchType, patch, patchOptions)
+		if err != nil {
+			h.handler.Log.Error("Failed to patch cronjob", "name", name, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to patch cronjob %s", name)), nil
+		}
+	}
+
+	response := models.CronJobSuspendResponse{
+		CronJob:    cronJob.Name,
+		Namespace:  cronJob.Namespace,
+		Schedule:   cronJob.Spec.Schedule,
+		Suspend:    suspend,
+		Changed:    current != suspend,
+		DryRun:     dryRun,
+		ActiveJobs: activeJobNames(cronJob),
+	}
+	if cronJob.Status.LastScheduleTime != nil {
+		lastSchedule := cronJob.Status.LastScheduleTime.Time
+		response.LastScheduleTime = &lastSchedule
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// GetJobStatus 汇总Job的完成情况、状态条件和Pod，失败时附带最近一个Pod的日志
+func (h *ResourceHandlerImpl) GetJobStatus(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	tailLines := defaultJobTailLines
+	if v, ok := arguments["tailLines"].(float64); ok && v > 0 {
+		tailLines = int(v)
+	}
+
+	h.handler.Log.Info("Getting job status", "name", name, "namespace", namespace, "tailLines", tailLines)
+
+	if name == "" {
+		return utils.NewErrorToolResult("Job name is required"), nil
+	}
+
+	job, err := h.handler.Client.ClientSet().BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get job", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get job %s", name)), nil
+	}
+
+	response := models.JobStatusResponse{
+		Name:         job.Name,
+		Namespace:    job.Namespace,
+		Status:       jobStatus(job),
+		Completions:  derefInt32(job.Spec.Completions, 1),
+		Parallelism:  derefInt32(job.Spec.Parallelism, 1),
+		BackoffLimit: derefInt32(job.Spec.BackoffLimit, 6),
+		Succeeded:    job.Status.Succeeded,
+		Failed:       job.Status.Failed,
+		Active:       job.Status.Active,
+		Ready:        derefInt32(job.Status.Ready, 0),
+		Pods:         []models.JobPodInfo{},
+		RetrievedAt:  time.Now(),
+	}
+	if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+		response.CronJob = owner.Name
+	}
+	if job.Status.StartTime != nil {
+		startTime := job.Status.StartTime.Time
+		response.StartTime = &startTime
+		end := time.Now()
+		if job.Status.CompletionTime != nil {
+			completionTime := job.Status.CompletionTime.Time
+			response.CompletionTime = &completionTime
+			end = completionTime
+		}
+		response.Duration = end.Sub(startTime).Round(time.Second).String()
+	}
+	for _, condition := range job.Status.Conditions {
+		response.Conditions = append(response.Conditions, models.JobCondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+			Time:    condition.LastTransitionTime.Time,
+		})
+	}
+
+	// 通过Job的选择器查找其创建的Pod
+	var pods []corev1.Pod
+	if job.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err == nil {
+			podList, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: selector.String(),
+			})
+			if err != nil {
+				h.handler.Log.Warn("Failed to list job pods", "job", name, "error", err)
+			} else {
+				pods = podList.Items
+			}
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.After(pods[j].CreationTimestamp.Time)
+	})
+	for i := range pods {
+		response.Pods = append(response.Pods, jobPodInfo(&pods[i]))
+	}
+
+	// 失败时读取最近一个Pod的日志
+	if (response.Status == "Failed" || job.Status.Failed > 0) && len(pods) > 0 {
+		pod := &pods[0]
+		container := failedContainer(pod)
+		response.LogPod = pod.Name
+		response.LogContainer = container
+		logs, err := h.readPodLogs(ctx, pod, container, tailLines)
+		if err != nil {
+			response.LogError = err.Error()
+		} else {
+			response.Logs = logs
+		}
+	}
+
+	return utils.RenderResult(request, response), nil
+}
+
+// readPodLogs 读取容器最后若干行日志
+func (h *ResourceHandlerImpl) readPodLogs(
+	ctx context.Context,
+	pod *corev1.Pod,
+	container string,
+	tailLines int,
+) ([]string, error) {
+	tail := int64(tailLines)
+	stream, err := h.handler.Client.ClientSet().CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+		TailLines:  &tail,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := io.CopyN(buf, stream, maxJobLogBytes); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
+// jobFromCronJob 根据CronJob的jobTemplate构建Job，复制模板中的标签和注解并设置所有者引用
+func jobFromCronJob(cronJob *batchv1.CronJob, jobName string) *batchv1.Job {
+	annotations := map[string]string{cronJobInstantiateAnnotation: "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	labels := make(map[string]string, len(cronJob.Spec.JobTemplate.Labels))
+	for k, v := range cronJob.Spec.JobTemplate.Labels {
+		labels[k] = v
+	}
+
+	controller := true
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   cronJob.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "batch/v1",
+				Kind:       "CronJob",
+				Name:       cronJob.Name,
+				UID:        cronJob.UID,
+				Controller: &controller,
+			}},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+// manualJobName 生成带时间戳后缀的Job名称，必要时截断CronJob名称以满足长度限制
+func manualJobName(cronJobName string, now time.Time) string {
+	suffix := fmt.Sprintf("-manual-%d", now.Unix())
+	if len(cronJobName)+len(suffix) > maxJobNameLength {
+		cronJobName = strings.TrimRight(cronJobName[:maxJobNameLength-len(suffix)], "-.")
+	}
+	return cronJobName + suffix
+}
+
+// activeJobNames 返回CronJob当前活跃的Job名称
+func activeJobNames(cronJob *batchv1.CronJob) []string {
+	names := make([]string, 0, len(cronJob.Status.Active))
+	for _, ref := range cronJob.Status.Active {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// jobStatus 根据状态条件和计数返回Job的整体状态
+func jobStatus(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return "Failed"
+		case batchv1.JobSuspended:
+			return "Suspended"
+		}
+	}
+	if job.Status.Active > 0 {
+		return "Running"
+	}
+	return "Pending"
+}
+
+// jobPodInfo 构建Job中Pod的概要信息
+func jobPodInfo(pod *corev1.Pod) models.JobPodInfo {
+	info := models.JobPodInfo{
+		Name:      pod.Name,
+		Phase:     string(pod.Status.Phase),
+		NodeName:  pod.Spec.NodeName,
+		Reason:    pod.Status.Reason,
+		CreatedAt: pod.CreationTimestamp.Time,
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		info.RestartCount += status.RestartCount
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			exitCode := terminated.ExitCode
+			info.ExitCode = &exitCode
+			if info.Reason == "" {
+				info.Reason = terminated.Reason
+			}
+		}
+	}
+	return info
+}
+
+// failedContainer 返回Pod中以非零退出码终止的容器，没有时返回第一个容器
+func failedContainer(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			return status.Name
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// derefInt32 返回指针指向的值，指针为空时返回默认值
+func derefInt32(v *int32, defaultValue int32) int32 {
+	if v == nil {
+		return defaultValue
+	}
+	return *v
+}
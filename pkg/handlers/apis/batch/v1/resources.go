@@ -5,10 +5,17 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	TRIGGER_CRONJOB = "TRIGGER_CRONJOB"
+	SUSPEND_CRONJOB = "SUSPEND_CRONJOB"
+	RESUME_CRONJOB  = "RESUME_CRONJOB"
+	GET_JOB_STATUS  = "GET_JOB_STATUS"
 )
 
 // ResourceHandlerImpl Batch资源处理程序实现
@@ -32,14 +39,108 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 使用父类的处理方法
-	return h.baseHandler.Handle(ctx, request)
+	// 根据工具名称分派到具体的处理方法
+	switch request.Method {
+	case TRIGGER_CRONJOB:
+		return h.TriggerCronJob(ctx, request)
+	case SUSPEND_CRONJOB:
+		return h.SuspendCronJob(ctx, request)
+	case RESUME_CRONJOB:
+		return h.ResumeCronJob(ctx, request)
+	case GET_JOB_STATUS:
+		return h.GetJobStatus(ctx, request)
+	default:
+		// 其他方法使用父类的处理方法
+		return h.baseHandler.Handle(ctx, request)
+	}
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
-	// 使用父类的注册方法
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
+	// 注册父类的工具
 	h.baseHandler.Register(server)
+
+	// 注册CronJob手动触发工具
+	server.AddTool(mcp.NewTool(TRIGGER_CRONJOB,
+		mcp.WithDescription("根据CronJob的jobTemplate立即创建一个Job，等同于kubectl create job --from=cronjob/<name>。Job名称带有时间戳后缀，并复制模板中的标签和注解。如果CronJob的concurrencyPolicy为Forbid且存在活跃的Job，默认拒绝执行，需要force=true才会触发。"),
+		mcp.WithString("name",
+			mcp.Description("CronJob名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("CronJob所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("jobName",
+			mcp.Description("创建的Job名称（可选）。默认为'<cronjob>-manual-<时间戳>'。"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("concurrencyPolicy为Forbid且存在活跃Job时是否仍然触发。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。启用后只校验不创建Job。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.TriggerCronJob)
+
+	// 注册CronJob暂停与恢复工具
+	server.AddTool(mcp.NewTool(SUSPEND_CRONJOB,
+		mcp.WithDescription("暂停CronJob，将spec.suspend设置为true。暂停后不再创建新的Job，已在运行的Job不受影响。"),
+		mcp.WithString("name",
+			mcp.Description("CronJob名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("CronJob所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SuspendCronJob)
+
+	server.AddTool(mcp.NewTool(RESUME_CRONJOB,
+		mcp.WithDescription("恢复被暂停的CronJob，将spec.suspend设置为false。"),
+		mcp.WithString("name",
+			mcp.Description("CronJob名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("CronJob所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ResumeCronJob)
+
+	// 注册Job状态工具
+	server.AddTool(mcp.NewTool(GET_JOB_STATUS,
+		mcp.WithDescription("汇总Job的运行状态：完成数、失败数、活跃Pod数、状态条件以及各Pod的概要。Job失败时附带最近一个Pod中失败容器的日志末尾，用于快速定位批处理任务失败原因。"),
+		mcp.WithString("name",
+			mcp.Description("Job名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Job所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Description("Job失败时获取的日志行数。默认为50行。"),
+			mcp.DefaultNumber(defaultJobTailLines),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetJobStatus)
 }
 
 // GetScope 实现ToolHandler接口
@@ -0,0 +1,699 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 选择器不匹配时最多列出的相近Pod数量
+	maxNearMissPods = 5
+	// IngressClass 中标记默认类的注解
+	defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+	// 旧版本中指定Ingress类的注解
+	legacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+// AnalyzeService 检查Service的选择器、Endpoints、端口映射、对外暴露状态以及NetworkPolicy限制
+func (h *ResourceHandlerImpl) AnalyzeService(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+
+	h.handler.Log.Info("Analyzing service", "name", name, "namespace", namespace)
+
+	if name == "" {
+		return utils.NewErrorToolResult("Service name is required"), nil
+	}
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	service, err := coreClient.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get service", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get service %s", name)), nil
+	}
+
+	analysis := &models.ServiceAnalysis{
+		Name:        service.Name,
+		Namespace:   service.Namespace,
+		Type:        string(service.Spec.Type),
+		ClusterIP:   service.Spec.ClusterIP,
+		Selector:    service.Spec.Selector,
+		Ports:       []models.ServicePortCheck{},
+		Findings:    []models.Finding{},
+		RetrievedAt: time.Now(),
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityInfo,
+			Check:    "type",
+			Message:  fmt.Sprintf("ExternalName service resolves to %s via DNS, no pods or endpoints are involved", service.Spec.ExternalName),
+		})
+		return utils.RenderResult(request, analysis), nil
+	}
+
+	// --- 选择器与Pod ---
+	var matchedPods []corev1.Pod
+	if len(service.Spec.Selector) == 0 {
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityInfo,
+			Check:    "selector",
+			Message:  "Service has no selector, its endpoints must be managed manually",
+		})
+	} else {
+		pods, err := coreClient.Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			h.handler.Log.Warn("Failed to list pods", "namespace", namespace, "error", err)
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityWarning,
+				Check:    "selector",
+				Message:  fmt.Sprintf("failed to list pods: %v", err),
+			})
+		} else {
+			matchedPods = h.checkSelector(service, pods.Items, analysis)
+		}
+	}
+
+	// --- Endpoints ---
+	h.checkEndpoints(ctx, service, analysis)
+
+	// --- 端口映射 ---
+	for _, port := range service.Spec.Ports {
+		analysis.Ports = append(analysis.Ports, checkServicePort(port, matchedPods, analysis))
+	}
+
+	// --- 对外暴露 ---
+	checkExposure(service, analysis)
+
+	// --- NetworkPolicy ---
+	if len(matchedPods) > 0 {
+		policies, err := h.handler.Client.ClientSet().NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			h.handler.Log.Warn("Failed to list network policies", "namespace", namespace, "error", err)
+		} else {
+			checkNetworkPolicies(policies.Items, service, matchedPods, analysis)
+		}
+	}
+
+	h.handler.Log.Info("Service analysis completed", "name", name, "findings", len(analysis.Findings))
+	return utils.RenderResult(request, analysis), nil
+}
+
+// AnalyzeIngress 检查Ingress引用的Service和端口是否存在，以及Ingress类对应的控制器是否就绪
+func (h *ResourceHandlerImpl) AnalyzeIngress(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+
+	h.handler.Log.Info("Analyzing ingress", "name", name, "namespace", namespace)
+
+	if name == "" {
+		return utils.NewErrorToolResult("Ingress name is required"), nil
+	}
+
+	networkingClient := h.handler.Client.ClientSet().NetworkingV1()
+	ingress, err := networkingClient.Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get ingress", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get ingress %s", name)), nil
+	}
+
+	analysis := &models.IngressAnalysis{
+		Name:        ingress.Name,
+		Namespace:   ingress.Namespace,
+		Backends:    []models.IngressBackendCheck{},
+		Findings:    []models.Finding{},
+		RetrievedAt: time.Now(),
+	}
+
+	// --- Ingress类与控制器 ---
+	h.checkIngressClass(ctx, ingress, analysis)
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		analysis.ExternalAddresses = append(analysis.ExternalAddresses, lbAddress(lb.IP, lb.Hostname))
+	}
+	if len(analysis.ExternalAddresses) == 0 {
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "controller",
+			Message:  "Ingress has no address in status, no ingress controller appears to have admitted it",
+		})
+	}
+
+	// --- 后端Service ---
+	if backend := ingress.Spec.DefaultBackend; backend != nil {
+		analysis.Backends = append(analysis.Backends, h.checkIngressBackend(ctx, namespace, "", "", *backend, analysis))
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			analysis.Backends = append(analysis.Backends, h.checkIngressBackend(ctx, namespace, rule.Host, path.Path, path.Backend, analysis))
+		}
+	}
+	if len(analysis.Backends) == 0 {
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "backend",
+			Message:  "Ingress defines no rules and no default backend",
+		})
+	}
+
+	// --- TLS证书 ---
+	for _, tls := range ingress.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+		_, err := h.handler.Client.ClientSet().CoreV1().Secrets(namespace).Get(ctx, tls.SecretName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityCritical,
+				Check:    "tls",
+				Message:  fmt.Sprintf("TLS secret %s does not exist", tls.SecretName),
+				Details:  tls.Hosts,
+			})
+		}
+	}
+
+	h.handler.Log.Info("Ingress analysis completed", "name", name, "findings", len(analysis.Findings))
+
+	return utils.RenderResult(request, analysis), nil
+}
+
+// checkSelector 返回选择器匹配的Pod，没有匹配时列出标签相近的Pod及其不匹配的标签
+func (h *ResourceHandlerImpl) checkSelector(
+	service *corev1.Service,
+	pods []corev1.Pod,
+	analysis *models.ServiceAnalysis,
+) []corev1.Pod {
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+
+	var matched []corev1.Pod
+	var nearMisses []string
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pod)
+			if isPodReady(&pod) {
+				analysis.ReadyPods++
+			}
+			continue
+		}
+
+		// 至少匹配一个标签的Pod可能是标签写错导致的不匹配
+		var mismatched []string
+		matchedAny := false
+		for key, want := range service.Spec.Selector {
+			if got, ok := pod.Labels[key]; ok && got == want {
+				matchedAny = true
+			} else if ok {
+				mismatched = append(mismatched, fmt.Sprintf("%s=%s (selector wants %s)", key, got, want))
+			} else {
+				mismatched = append(mismatched, fmt.Sprintf("%s missing (selector wants %s)", key, want))
+			}
+		}
+		if matchedAny && len(nearMisses) < maxNearMissPods {
+			sort.Strings(mismatched)
+			nearMisses = append(nearMisses, fmt.Sprintf("pod %s: %s", pod.Name, strings.Join(mismatched, ", ")))
+		}
+	}
+	analysis.MatchingPods = len(matched)
+
+	switch {
+	case len(matched) == 0:
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "selector",
+			Message:  fmt.Sprintf("selector %s matches no pods", selector.String()),
+			Details:  nearMisses,
+		})
+	case analysis.ReadyPods == 0:
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "readiness",
+			Message:  fmt.Sprintf("%d pod(s) match the selector but none are Ready", len(matched)),
+		})
+	case analysis.ReadyPods < len(matched):
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "readiness",
+			Message:  fmt.Sprintf("only %d of %d matching pod(s) are Ready", analysis.ReadyPods, len(matched)),
+		})
+	}
+	return matched
+}
+
+// checkEndpoints 统计Service的EndpointSlice中就绪与未就绪的地址
+func (h *ResourceHandlerImpl) checkEndpoints(ctx context.Context, service *corev1.Service, analysis *models.ServiceAnalysis) {
+	ready, notReady, err := h.countEndpoints(ctx, service.Namespace, service.Name)
+	if err != nil {
+		h.handler.Log.Warn("Failed to list endpoint slices", "service", service.Name, "error", err)
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "endpoints",
+			Message:  fmt.Sprintf("failed to list endpoint slices: %v", err),
+		})
+		return
+	}
+	analysis.ReadyEndpoints = ready
+	analysis.NotReadyEndpoints = notReady
+
+	if ready == 0 {
+		message := "Service has no ready endpoints, connections to it will be refused or time out"
+		if notReady > 0 {
+			message = fmt.Sprintf("Service has %d endpoint(s) but none are ready", notReady)
+		}
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "endpoints",
+			Message:  message,
+		})
+	}
+}
+
+// countEndpoints 统计Service的EndpointSlice中就绪与未就绪的地址数量
+func (h *ResourceHandlerImpl) countEndpoints(ctx context.Context, namespace, service string) (int, int, error) {
+	slices, err := h.handler.Client.ClientSet().DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service}).String(),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ready, notReady := 0, 0
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// 未设置Ready时按就绪处理
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready += len(endpoint.Addresses)
+			} else {
+				notReady += len(endpoint.Addresses)
+			}
+		}
+	}
+	return ready, notReady, nil
+}
+
+// checkServicePort 检查Service端口的targetPort是否对应Pod中声明的containerPort
+func checkServicePort(port corev1.ServicePort, pods []corev1.Pod, analysis *models.ServiceAnalysis) models.ServicePortCheck {
+	check := models.ServicePortCheck{
+		Name:            port.Name,
+		Protocol:        string(port.Protocol),
+		Port:            port.Port,
+		TargetPort:      port.TargetPort.String(),
+		NodePort:        port.NodePort,
+		AllowedByPolicy: true,
+	}
+	if check.Protocol == "" {
+		check.Protocol = string(corev1.ProtocolTCP)
+	}
+
+	targetPort := port.TargetPort
+	if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+		// 未设置targetPort时默认与port相同
+		targetPort = intstr.FromInt32(port.Port)
+		check.TargetPort = targetPort.String()
+	}
+	if targetPort.Type == intstr.Int {
+		check.ContainerPort = targetPort.IntVal
+	}
+	if len(pods) == 0 {
+		return check
+	}
+
+	for _, pod := range pods {
+		if containerPort, ok := findContainerPort(&pod, targetPort); ok {
+			check.DeclaredByPods = true
+			check.ContainerPort = containerPort.ContainerPort
+			break
+		}
+	}
+
+	if !check.DeclaredByPods {
+		finding := models.Finding{
+			Check:   "targetPort",
+			Details: declaredPorts(&pods[0]),
+		}
+		if targetPort.Type == intstr.String {
+			// 命名端口无法解析时kube-proxy不会生成任何端点
+			finding.Severity = models.SeverityCritical
+			finding.Message = fmt.Sprintf("port %d targets named port %q which no selected pod declares", port.Port, targetPort.StrVal)
+		} else {
+			finding.Severity = models.SeverityWarning
+			finding.Message = fmt.Sprintf("port %d targets container port %d which the selected pods do not declare, make sure the application listens on it", port.Port, targetPort.IntVal)
+		}
+		analysis.Findings = append(analysis.Findings, finding)
+	}
+	return check
+}
+
+// checkExposure 检查NodePort和LoadBalancer类型Service的对外暴露状态
+func checkExposure(service *corev1.Service, analysis *models.ServiceAnalysis) {
+	for _, lb := range service.Status.LoadBalancer.Ingress {
+		analysis.ExternalAddresses = append(analysis.ExternalAddresses, lbAddress(lb.IP, lb.Hostname))
+	}
+	analysis.ExternalAddresses = append(analysis.ExternalAddresses, service.Spec.ExternalIPs...)
+
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		if len(service.Status.LoadBalancer.Ingress) == 0 {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityWarning,
+				Check:    "loadBalancer",
+				Message:  "LoadBalancer has no external address yet, the cloud provider or load balancer controller has not provisioned it",
+			})
+		}
+		fallthrough
+	case corev1.ServiceTypeNodePort:
+		for _, port := range service.Spec.Ports {
+			if port.NodePort == 0 {
+				analysis.Findings = append(analysis.Findings, models.Finding{
+					Severity: models.SeverityWarning,
+					Check:    "nodePort",
+					Message:  fmt.Sprintf("port %d has no node port allocated", port.Port),
+				})
+			}
+		}
+		if service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "externalTrafficPolicy",
+				Message:  "externalTrafficPolicy is Local, only nodes running a ready pod will accept external traffic",
+			})
+		}
+	}
+}
+
+// checkNetworkPolicies 检查命名空间中选中Service后端Pod的NetworkPolicy是否会阻止入站流量
+func checkNetworkPolicies(
+	policies []networkingv1.NetworkPolicy,
+	service *corev1.Service,
+	pods []corev1.Pod,
+	analysis *models.ServiceAnalysis,
+) {
+	var selecting []networkingv1.NetworkPolicy
+	for _, policy := range policies {
+		if !policyAffectsIngress(&policy) {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil {
+			continue
+		}
+		for _, pod := range pods {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				selecting = append(selecting, policy)
+				analysis.NetworkPolicies = append(analysis.NetworkPolicies, policy.Name)
+				break
+			}
+		}
+	}
+	if len(selecting) == 0 {
+		return
+	}
+
+	// 多个策略的允许规则取并集：只要有一条规则允许该端口，流量即可进入
+	for i, port := range service.Spec.Ports {
+		pod := &pods[0]
+		allowed := false
+		var sources []string
+		for _, policy := range selecting {
+			for _, rule := range policy.Spec.Ingress {
+				if ruleAllowsPort(rule, pod, port) {
+					allowed = true
+					sources = append(sources, fmt.Sprintf("%s: %s", policy.Name, describePeers(rule.From)))
+				}
+			}
+		}
+		analysis.Ports[i].AllowedByPolicy = allowed
+
+		if !allowed {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityCritical,
+				Check:    "networkPolicy",
+				Message:  fmt.Sprintf("NetworkPolicies select the backend pods and none allow ingress on port %d (targetPort %s)", port.Port, port.TargetPort.String()),
+				Details:  analysis.NetworkPolicies,
+			})
+		} else {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "networkPolicy",
+				Message:  fmt.Sprintf("ingress on port %d is restricted by NetworkPolicy, only the listed sources can connect", port.Port),
+				Details:  sources,
+			})
+		}
+	}
+}
+
+// policyAffectsIngress 判断NetworkPolicy是否限制入站流量，未设置policyTypes时默认包含Ingress
+func policyAffectsIngress(policy *networkingv1.NetworkPolicy) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return true
+	}
+	for _, policyType := range policy.Spec.PolicyTypes {
+		if policyType == networkingv1.PolicyTypeIngress {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleAllowsPort 判断入站规则是否允许到达Service端口对应的容器端口
+func ruleAllowsPort(rule networkingv1.NetworkPolicyIngressRule, pod *corev1.Pod, port corev1.ServicePort) bool {
+	if len(rule.Ports) == 0 {
+		return true
+	}
+
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	targetPort := port.TargetPort
+	if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+		targetPort = intstr.FromInt32(port.Port)
+	}
+	containerPort, declared := findContainerPort(pod, targetPort)
+	if !declared && targetPort.Type == intstr.Int {
+		containerPort = corev1.ContainerPort{ContainerPort: targetPort.IntVal}
+	}
+	return policyPortsMatch(rule.Ports, containerPort, protocol)
+}
+
+// describePeers 返回入站规则来源的简要描述
+func describePeers(peers []networkingv1.NetworkPolicyPeer) string {
+	if len(peers) == 0 {
+		return "all sources"
+	}
+	descriptions := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		var parts []string
+		if peer.IPBlock != nil {
+			parts = append(parts, "ipBlock "+peer.IPBlock.CIDR)
+		}
+		if peer.NamespaceSelector != nil {
+			parts = append(parts, "namespaces "+metav1.FormatLabelSelector(peer.NamespaceSelector))
+		}
+		if peer.PodSelector != nil {
+			parts = append(parts, "pods "+metav1.FormatLabelSelector(peer.PodSelector))
+		}
+		descriptions = append(descriptions, strings.Join(parts, " and "))
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// checkIngressClass 检查Ingress使用的IngressClass是否存在
+func (h *ResourceHandlerImpl) checkIngressClass(ctx context.Context, ingress *networkingv1.Ingress, analysis *models.IngressAnalysis) {
+	className := ""
+	if ingress.Spec.IngressClassName != nil {
+		className = *ingress.Spec.IngressClassName
+	} else if legacy, ok := ingress.Annotations[legacyIngressClassAnnotation]; ok {
+		className = legacy
+	}
+
+	classes, err := h.handler.Client.ClientSet().NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list ingress classes", "error", err)
+		analysis.IngressClass = className
+		return
+	}
+
+	if className == "" {
+		for _, class := range classes.Items {
+			if class.Annotations[defaultIngressClassAnnotation] == "true" {
+				className = class.Name
+				break
+			}
+		}
+		if className == "" {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityCritical,
+				Check:    "ingressClass",
+				Message:  "Ingress specifies no ingress class and the cluster has no default IngressClass",
+			})
+			return
+		}
+	}
+	analysis.IngressClass = className
+
+	for _, class := range classes.Items {
+		if class.Name == className {
+			analysis.Controller = class.Spec.Controller
+			return
+		}
+	}
+
+	names := make([]string, 0, len(classes.Items))
+	for _, class := range classes.Items {
+		names = append(names, class.Name)
+	}
+	analysis.Findings = append(analysis.Findings, models.Finding{
+		Severity: models.SeverityCritical,
+		Check:    "ingressClass",
+		Message:  fmt.Sprintf("IngressClass %s does not exist, no controller will serve this ingress", className),
+		Details:  names,
+	})
+}
+
+// checkIngressBackend 检查Ingress后端引用的Service和端口是否存在及是否有就绪端点
+func (h *ResourceHandlerImpl) checkIngressBackend(
+	ctx context.Context,
+	namespace, host, path string,
+	backend networkingv1.IngressBackend,
+	analysis *models.IngressAnalysis,
+) models.IngressBackendCheck {
+	check := models.IngressBackendCheck{Host: host, Path: path}
+	location := host + path
+	if location == "" {
+		location = "default backend"
+	}
+
+	if backend.Service == nil {
+		if backend.Resource != nil {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "backend",
+				Message:  fmt.Sprintf("%s routes to resource %s/%s, which is not checked", location, backend.Resource.Kind, backend.Resource.Name),
+			})
+		}
+		return check
+	}
+
+	check.Service = backend.Service.Name
+	if backend.Service.Port.Name != "" {
+		check.Port = backend.Service.Port.Name
+	} else {
+		check.Port = fmt.Sprintf("%d", backend.Service.Port.Number)
+	}
+
+	service, err := h.handler.Client.ClientSet().CoreV1().Services(namespace).Get(ctx, backend.Service.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityCritical,
+				Check:    "backend",
+				Message:  fmt.Sprintf("%s routes to Service %s which does not exist", location, backend.Service.Name),
+			})
+		}
+		return check
+	}
+	check.ServiceExists = true
+
+	for _, port := range service.Spec.Ports {
+		if (backend.Service.Port.Name != "" && port.Name == backend.Service.Port.Name) ||
+			(backend.Service.Port.Name == "" && port.Port == backend.Service.Port.Number) {
+			check.PortExists = true
+			break
+		}
+	}
+	if !check.PortExists {
+		analysis.Findings = append(analysis.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "backend",
+			Message:  fmt.Sprintf("%s routes to port %s which Service %s does not expose", location, check.Port, service.Name),
+		})
+	}
+
+	ready, _, err := h.countEndpoints(ctx, namespace, service.Name)
+	if err == nil {
+		check.ReadyEndpoints = ready
+		if ready == 0 {
+			analysis.Findings = append(analysis.Findings, models.Finding{
+				Severity: models.SeverityCritical,
+				Check:    "backend",
+				Message:  fmt.Sprintf("%s routes to Service %s which has no ready endpoints, run ANALYZE_SERVICE for details", location, service.Name),
+			})
+		}
+	}
+	return check
+}
+
+// findContainerPort 在Pod的容器中查找与targetPort对应的端口声明
+func findContainerPort(pod *corev1.Pod, targetPort intstr.IntOrString) (corev1.ContainerPort, bool) {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if targetPort.Type == intstr.String && port.Name == targetPort.StrVal {
+				return port, true
+			}
+			if targetPort.Type == intstr.Int && port.ContainerPort == targetPort.IntVal {
+				return port, true
+			}
+		}
+	}
+	return corev1.ContainerPort{}, false
+}
+
+// declaredPorts 列出Pod中声明的所有容器端口
+func declaredPorts(pod *corev1.Pod) []string {
+	var ports []string
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name != "" {
+				ports = append(ports, fmt.Sprintf("%s: %s %d/%s", container.Name, port.Name, port.ContainerPort, port.Protocol))
+			} else {
+				ports = append(ports, fmt.Sprintf("%s: %d/%s", container.Name, port.ContainerPort, port.Protocol))
+			}
+		}
+	}
+	return ports
+}
+
+// isPodReady 判断Pod的Ready条件是否为True
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// lbAddress 返回负载均衡入口的地址
+func lbAddress(ip, hostname string) string {
+	if ip != "" {
+		return ip
+	}
+	return hostname
+}
@@ -0,0 +1,507 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// maxSimulatedTargets Service作为目标时最多评估的后端Pod数量
+const maxSimulatedTargets = 20
+
+// policySubject 参与NetworkPolicy评估的一端
+type policySubject struct {
+	description string
+	namespace   string
+	nsLabels    labels.Set
+	podLabels   labels.Set
+}
+
+// SimulateNetworkPolicy 评估源命名空间的出站策略和目标命名空间的入站策略，判断流量是否被允许并给出评估过程
+func (h *ResourceHandlerImpl) SimulateNetworkPolicy(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	sourceNamespaceArg, _ := arguments["sourceNamespace"].(string)
+	sourceNamespace := h.baseHandler.GetNamespaceWithDefault(sourceNamespaceArg)
+	sourcePod, _ := arguments["sourcePod"].(string)
+	sourceLabels, _ := arguments["sourceLabels"].(string)
+	destinationNamespace, _ := arguments["destinationNamespace"].(string)
+	if destinationNamespace == "" {
+		destinationNamespace = sourceNamespace
+	}
+	destinationPod, _ := arguments["destinationPod"].(string)
+	destinationService, _ := arguments["destinationService"].(string)
+	protocolArg, _ := arguments["protocol"].(string)
+	port := ""
+	switch value := arguments["port"].(type) {
+	case string:
+		port = value
+	case float64:
+		port = strconv.Itoa(int(value))
+	}
+
+	h.handler.Log.Info("Simulating network policy",
+		"sourceNamespace", sourceNamespace,
+		"sourcePod", sourcePod,
+		"sourceLabels", sourceLabels,
+		"destinationNamespace", destinationNamespace,
+		"destinationPod", destinationPod,
+		"destinationService", destinationService,
+		"port", port,
+		"protocol", protocolArg,
+	)
+
+	if (sourcePod == "") == (sourceLabels == "") {
+		return utils.NewErrorToolResult("specify exactly one of sourcePod or sourceLabels"), nil
+	}
+	if (destinationPod == "") == (destinationService == "") {
+		return utils.NewErrorToolResult("specify exactly one of destinationPod or destinationService"), nil
+	}
+	if port == "" {
+		return utils.NewErrorToolResult("missing required parameter: port"), nil
+	}
+	protocol := corev1.Protocol(strings.ToUpper(protocolArg))
+	switch protocol {
+	case "":
+		protocol = corev1.ProtocolTCP
+	case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+	default:
+		return utils.NewErrorToolResult(fmt.Sprintf("unsupported protocol %q, must be TCP, UDP or SCTP", protocolArg)), nil
+	}
+
+	client := h.handler.Client.ClientSet()
+	response := models.NetworkPolicySimulation{
+		Source:      models.PolicyEndpoint{Namespace: sourceNamespace, Pod: sourcePod},
+		Destination: models.PolicyEndpoint{Namespace: destinationNamespace, Pod: destinationPod, Service: destinationService},
+		Port:        port,
+		Protocol:    string(protocol),
+		Targets:     []models.PolicyTargetEvaluation{},
+		Notes:       []string{"the verdict assumes the cluster network plugin enforces NetworkPolicy; without an enforcing plugin all traffic is allowed"},
+	}
+
+	// --- 源 ---
+	source := policySubject{namespace: sourceNamespace}
+	if sourcePod != "" {
+		pod, err := client.CoreV1().Pods(sourceNamespace).Get(ctx, sourcePod, metav1.GetOptions{})
+		if err != nil {
+			h.handler.Log.Error("Failed to get source pod", "pod", sourcePod, "namespace", sourceNamespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get source pod %s", sourcePod)), nil
+		}
+		source.podLabels = labels.Set(pod.Labels)
+		source.description = fmt.Sprintf("pod %s/%s", sourceNamespace, sourcePod)
+		if pod.Spec.HostNetwork {
+			response.Notes = append(response.Notes, "the source pod uses the host network; most network plugins treat its traffic as node traffic, so pod selectors may not apply")
+		}
+	} else {
+		selected, err := labels.ConvertSelectorToLabelsMap(sourceLabels)
+		if err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("invalid sourceLabels %q, expected key=value pairs: %v", sourceLabels, err)), nil
+		}
+		source.podLabels = selected
+		source.description = fmt.Sprintf("pods labelled %s in %s", sourceLabels, sourceNamespace)
+	}
+	response.Source.Labels = source.podLabels
+
+	namespaceLabels := make(map[string]labels.Set)
+	for _, namespace := range []string{sourceNamespace, destinationNamespace} {
+		if _, ok := namespaceLabels[namespace]; ok {
+			continue
+		}
+		ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			h.handler.Log.Error("Failed to get namespace", "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get namespace %s", namespace)), nil
+		}
+		namespaceLabels[namespace] = labels.Set(ns.Labels)
+	}
+	source.nsLabels = namespaceLabels[sourceNamespace]
+
+	// --- 目标 ---
+	var pods []corev1.Pod
+	var servicePort *corev1.ServicePort
+	if destinationPod != "" {
+		pod, err := client.CoreV1().Pods(destinationNamespace).Get(ctx, destinationPod, metav1.GetOptions{})
+		if err != nil {
+			h.handler.Log.Error("Failed to get destination pod", "pod", destinationPod, "namespace", destinationNamespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get destination pod %s", destinationPod)), nil
+		}
+		pods = append(pods, *pod)
+		response.Destination.Labels = pod.Labels
+	} else {
+		service, err := client.CoreV1().Services(destinationNamespace).Get(ctx, destinationService, metav1.GetOptions{})
+		if err != nil {
+			h.handler.Log.Error("Failed to get destination service", "service", destinationService, "namespace", destinationNamespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get destination service %s", destinationService)), nil
+		}
+		if len(service.Spec.Selector) == 0 {
+			return utils.NewErrorToolResult(fmt.Sprintf("service %s has no selector; its endpoints are managed manually, specify destinationPod instead", destinationService)), nil
+		}
+		servicePort = findServicePort(service, port)
+		if servicePort == nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("service %s has no port %s; available ports: %s",
+				destinationService, port, strings.Join(servicePortNames(service), ", "))), nil
+		}
+		if servicePort.Protocol != "" && servicePort.Protocol != protocol {
+			response.Notes = append(response.Notes, fmt.Sprintf("service port %s uses protocol %s, evaluating %s instead of %s",
+				port, servicePort.Protocol, servicePort.Protocol, protocol))
+			protocol = servicePort.Protocol
+			response.Protocol = string(protocol)
+		}
+		response.Destination.Labels = service.Spec.Selector
+		podList, err := client.CoreV1().Pods(destinationNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+		})
+		if err != nil {
+			h.handler.Log.Error("Failed to list service pods", "service", destinationService, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list pods of service %s", destinationService)), nil
+		}
+		if len(podList.Items) == 0 {
+			return utils.NewErrorToolResult(fmt.Sprintf("service %s selects no pods, there is no destination to evaluate", destinationService)), nil
+		}
+		pods = podList.Items
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		if len(pods) > maxSimulatedTargets {
+			response.Notes = append(response.Notes, fmt.Sprintf("service %s selects %d pods, only the first %d were evaluated",
+				destinationService, len(pods), maxSimulatedTargets))
+			pods = pods[:maxSimulatedTargets]
+		}
+		response.Notes = append(response.Notes, "NetworkPolicy is enforced after the service address is translated, so the policies are evaluated against each backend pod and its target port")
+	}
+
+	// --- NetworkPolicy ---
+	policiesByNamespace := make(map[string][]networkingv1.NetworkPolicy)
+	for _, namespace := range []string{sourceNamespace, destinationNamespace} {
+		if _, ok := policiesByNamespace[namespace]; ok {
+			continue
+		}
+		list, err := client.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			h.handler.Log.Error("Failed to list network policies", "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list network policies in %s", namespace)), nil
+		}
+		policiesByNamespace[namespace] = list.Items
+	}
+
+	ipBlockSkipped := false
+	allowed, denied := 0, 0
+	for i := range pods {
+		pod := &pods[i]
+		target := models.PolicyTargetEvaluation{Pod: pod.Name}
+		containerPort, ok := resolveTargetPort(pod, port, servicePort, protocol)
+		if !ok {
+			target.Verdict = models.PolicyVerdictDenied
+			target.Ingress.Summary = fmt.Sprintf("pod %s declares no container port named %s, the connection cannot be established", pod.Name, targetPortName(port, servicePort))
+			denied++
+			response.Targets = append(response.Targets, target)
+			continue
+		}
+		target.Port = containerPort.ContainerPort
+		target.PortName = containerPort.Name
+
+		destination := policySubject{
+			description: fmt.Sprintf("pod %s/%s", destinationNamespace, pod.Name),
+			namespace:   destinationNamespace,
+			nsLabels:    namespaceLabels[destinationNamespace],
+			podLabels:   labels.Set(pod.Labels),
+		}
+		var skipped bool
+		target.Egress, skipped = evaluatePolicyDirection(policiesByNamespace[sourceNamespace], networkingv1.PolicyTypeEgress,
+			source, destination, containerPort, protocol)
+		ipBlockSkipped = ipBlockSkipped || skipped
+		target.Ingress, skipped = evaluatePolicyDirection(policiesByNamespace[destinationNamespace], networkingv1.PolicyTypeIngress,
+			destination, source, containerPort, protocol)
+		ipBlockSkipped = ipBlockSkipped || skipped
+
+		if target.Egress.Allowed && target.Ingress.Allowed {
+			target.Verdict = models.PolicyVerdictAllowed
+			allowed++
+		} else {
+			target.Verdict = models.PolicyVerdictDenied
+			denied++
+		}
+		response.Targets = append(response.Targets, target)
+	}
+	if ipBlockSkipped {
+		response.Notes = append(response.Notes, "ipBlock peers were skipped because pod IPs are dynamic; they only match traffic by address")
+	}
+
+	switch {
+	case denied == 0:
+		response.Verdict = models.PolicyVerdictAllowed
+	case allowed == 0:
+		response.Verdict = models.PolicyVerdictDenied
+	default:
+		response.Verdict = models.PolicyVerdictPartial
+	}
+
+	h.handler.Log.Info("Network policy simulated", "verdict", response.Verdict, "targets", len(response.Targets))
+	return utils.RenderResult(request, response), nil
+}
+
+// evaluatePolicyDirection 评估一个方向上选中subject的NetworkPolicy是否允许与peer之间的流量
+// 入站时subject为目标、peer为源；出站时subject为源、peer为目标。返回值表示是否跳过了ipBlock规则
+func evaluatePolicyDirection(
+	policies []networkingv1.NetworkPolicy,
+	direction networkingv1.PolicyType,
+	subject, peer policySubject,
+	port corev1.ContainerPort,
+	protocol corev1.Protocol,
+) (models.PolicyDirectionResult, bool) {
+	directionName := strings.ToLower(string(direction))
+	result := models.PolicyDirectionResult{}
+	ipBlockSkipped := false
+	var firstMatch string
+
+	for _, policy := range policies {
+		if !policyAffectsDirection(&policy, direction) {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil || !selector.Matches(subject.podLabels) {
+			continue
+		}
+		result.Isolated = true
+		evaluation := models.PolicyEvaluation{Policy: policy.Name, Namespace: policy.Namespace}
+
+		type rule struct {
+			ports []networkingv1.NetworkPolicyPort
+			peers []networkingv1.NetworkPolicyPeer
+		}
+		var rules []rule
+		if direction == networkingv1.PolicyTypeIngress {
+			for _, r := range policy.Spec.Ingress {
+				rules = append(rules, rule{ports: r.Ports, peers: r.From})
+			}
+		} else {
+			for _, r := range policy.Spec.Egress {
+				rules = append(rules, rule{ports: r.Ports, peers: r.To})
+			}
+		}
+
+		for index, r := range rules {
+			trace := models.PolicyRuleTrace{Index: index}
+			peers := describePeers(r.peers)
+			if len(r.peers) == 0 && direction == networkingv1.PolicyTypeEgress {
+				peers = "all destinations"
+			}
+			peerMatched, skipped := peersMatch(r.peers, policy.Namespace, peer)
+			ipBlockSkipped = ipBlockSkipped || skipped
+			switch {
+			case !policyPortsMatch(r.ports, port, protocol):
+				trace.Reason = fmt.Sprintf("port %s is not in %s", describeContainerPort(port, protocol), describePolicyPorts(r.ports))
+			case !peerMatched:
+				trace.Reason = fmt.Sprintf("%s does not match %s", peer.description, peers)
+			default:
+				trace.Matched = true
+				trace.Reason = fmt.Sprintf("allows %s on %s", peers, describePolicyPorts(r.ports))
+				evaluation.Allowed = true
+				if firstMatch == "" {
+					firstMatch = fmt.Sprintf("%s %s rule %d", policy.Name, directionName, index)
+				}
+			}
+			if skipped && !trace.Matched {
+				trace.Reason += " (ipBlock peers skipped)"
+			}
+			evaluation.Rules = append(evaluation.Rules, trace)
+		}
+		result.Policies = append(result.Policies, evaluation)
+	}
+
+	// 多个策略的允许规则取并集：只要有一条规则允许，流量即被允许
+	switch {
+	case !result.Isolated:
+		result.Allowed = true
+		result.Summary = fmt.Sprintf("no NetworkPolicy selects %s for %s, all %s traffic is allowed by default", subject.description, directionName, directionName)
+	case firstMatch != "":
+		result.Allowed = true
+		result.Summary = fmt.Sprintf("allowed by %s", firstMatch)
+	default:
+		result.Summary = fmt.Sprintf("%d NetworkPolicies select %s for %s and none of their rules allow this traffic",
+			len(result.Policies), subject.description, directionName)
+	}
+	return result, ipBlockSkipped
+}
+
+// policyAffectsDirection 判断NetworkPolicy是否限制指定方向的流量
+// 未设置policyTypes时总是包含Ingress，只有存在egress规则时才包含Egress
+func policyAffectsDirection(policy *networkingv1.NetworkPolicy, direction networkingv1.PolicyType) bool {
+	if direction == networkingv1.PolicyTypeIngress {
+		return policyAffectsIngress(policy)
+	}
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return len(policy.Spec.Egress) > 0
+	}
+	for _, policyType := range policy.Spec.PolicyTypes {
+		if policyType == networkingv1.PolicyTypeEgress {
+			return true
+		}
+	}
+	return false
+}
+
+// peersMatch 判断规则的对端列表是否匹配peer，列表为空时匹配所有对端；第二个返回值表示是否跳过了ipBlock
+func peersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, peer policySubject) (bool, bool) {
+	if len(peers) == 0 {
+		return true, false
+	}
+	skipped := false
+	for _, candidate := range peers {
+		if candidate.IPBlock != nil {
+			skipped = true
+			continue
+		}
+		if candidate.NamespaceSelector == nil && candidate.PodSelector == nil {
+			continue
+		}
+		// 只设置podSelector时匹配策略所在命名空间中的Pod
+		if candidate.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(candidate.NamespaceSelector)
+			if err != nil || !selector.Matches(peer.nsLabels) {
+				continue
+			}
+		} else if peer.namespace != policyNamespace {
+			continue
+		}
+		if candidate.PodSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(candidate.PodSelector)
+			if err != nil || !selector.Matches(peer.podLabels) {
+				continue
+			}
+		}
+		return true, skipped
+	}
+	return false, skipped
+}
+
+// policyPortsMatch 判断规则的端口列表是否包含目标容器端口，列表为空时匹配所有端口
+// 命名端口按容器端口名称匹配，数字端口支持endPort指定的范围
+func policyPortsMatch(ports []networkingv1.NetworkPolicyPort, port corev1.ContainerPort, protocol corev1.Protocol) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, policyPort := range ports {
+		policyProtocol := corev1.ProtocolTCP
+		if policyPort.Protocol != nil {
+			policyProtocol = *policyPort.Protocol
+		}
+		if policyProtocol != protocol {
+			continue
+		}
+		if policyPort.Port == nil {
+			return true
+		}
+		if policyPort.Port.Type == intstr.String {
+			if port.Name != "" && policyPort.Port.StrVal == port.Name {
+				return true
+			}
+			continue
+		}
+		end := policyPort.Port.IntVal
+		if policyPort.EndPort != nil {
+			end = *policyPort.EndPort
+		}
+		if port.ContainerPort >= policyPort.Port.IntVal && port.ContainerPort <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveTargetPort 解析流量到达目标Pod时的容器端口
+// 目标为Service时按其targetPort解析；数字端口即使Pod未声明也可以连接，命名端口必须由Pod声明
+func resolveTargetPort(pod *corev1.Pod, port string, servicePort *corev1.ServicePort, protocol corev1.Protocol) (corev1.ContainerPort, bool) {
+	var target intstr.IntOrString
+	if servicePort != nil {
+		target = servicePort.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt32(servicePort.Port)
+		}
+	} else {
+		target = intstr.Parse(port)
+	}
+	containerPort, declared := findContainerPort(pod, target)
+	if declared {
+		return containerPort, true
+	}
+	if target.Type == intstr.String {
+		return corev1.ContainerPort{}, false
+	}
+	return corev1.ContainerPort{ContainerPort: target.IntVal, Protocol: protocol}, true
+}
+
+// findServicePort 按端口号或名称查找Service端口
+func findServicePort(service *corev1.Service, port string) *corev1.ServicePort {
+	for i := range service.Spec.Ports {
+		servicePort := &service.Spec.Ports[i]
+		if servicePort.Name == port || strconv.Itoa(int(servicePort.Port)) == port {
+			return servicePort
+		}
+	}
+	return nil
+}
+
+// servicePortNames 列出Service的端口，用于错误提示
+func servicePortNames(service *corev1.Service) []string {
+	names := make([]string, 0, len(service.Spec.Ports))
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name != "" {
+			names = append(names, fmt.Sprintf("%s (%d)", servicePort.Name, servicePort.Port))
+		} else {
+			names = append(names, strconv.Itoa(int(servicePort.Port)))
+		}
+	}
+	return names
+}
+
+// targetPortName 返回未能解析的目标端口名称
+func targetPortName(port string, servicePort *corev1.ServicePort) string {
+	if servicePort != nil {
+		return servicePort.TargetPort.String()
+	}
+	return port
+}
+
+// describeContainerPort 返回容器端口的简要描述
+func describeContainerPort(port corev1.ContainerPort, protocol corev1.Protocol) string {
+	if port.Name != "" {
+		return fmt.Sprintf("%d/%s (%s)", port.ContainerPort, protocol, port.Name)
+	}
+	return fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+}
+
+// describePolicyPorts 返回规则端口列表的简要描述
+func describePolicyPorts(ports []networkingv1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "all ports"
+	}
+	descriptions := make([]string, 0, len(ports))
+	for _, policyPort := range ports {
+		protocol := corev1.ProtocolTCP
+		if policyPort.Protocol != nil {
+			protocol = *policyPort.Protocol
+		}
+		switch {
+		case policyPort.Port == nil:
+			descriptions = append(descriptions, "all "+string(protocol)+" ports")
+		case policyPort.EndPort != nil:
+			descriptions = append(descriptions, fmt.Sprintf("%s-%d/%s", policyPort.Port.String(), *policyPort.EndPort, protocol))
+		default:
+			descriptions = append(descriptions, fmt.Sprintf("%s/%s", policyPort.Port.String(), protocol))
+		}
+	}
+	return "ports " + strings.Join(descriptions, ", ")
+}
@@ -5,10 +5,17 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	ANALYZE_SERVICE         = "ANALYZE_SERVICE"
+	ANALYZE_INGRESS         = "ANALYZE_INGRESS"
+	LIST_ROUTES             = "LIST_ROUTES"
+	SIMULATE_NETWORK_POLICY = "SIMULATE_NETWORK_POLICY"
 )
 
 // ResourceHandlerImpl Networking资源处理程序实现
@@ -32,14 +39,104 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 使用父类的处理方法
-	return h.baseHandler.Handle(ctx, request)
+	// 根据工具名称分派到具体的处理方法
+	switch request.Method {
+	case ANALYZE_SERVICE:
+		return h.AnalyzeService(ctx, request)
+	case ANALYZE_INGRESS:
+		return h.AnalyzeIngress(ctx, request)
+	case LIST_ROUTES:
+		return h.ListRoutes(ctx, request)
+	case SIMULATE_NETWORK_POLICY:
+		return h.SimulateNetworkPolicy(ctx, request)
+	default:
+		// 其他方法使用父类的处理方法
+		return h.baseHandler.Handle(ctx, request)
+	}
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
-	// 使用父类的注册方法
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
+	// 注册父类的工具
 	h.baseHandler.Register(server)
+
+	// 注册Service连通性分析工具
+	server.AddTool(mcp.NewTool(ANALYZE_SERVICE,
+		mcp.WithDescription("分析Service的连通性问题。检查选择器是否匹配到就绪的Pod（不匹配时列出标签不一致的Pod）、EndpointSlice是否有就绪地址、targetPort是否对应Pod中声明的containerPort、NodePort/LoadBalancer的分配状态，以及命名空间中是否有NetworkPolicy会阻止到后端Pod的入站流量。返回按严重程度（critical/warning/info）标记的问题列表。"),
+		mcp.WithString("name",
+			mcp.Description("Service名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Service所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.AnalyzeService)
+
+	// 注册Ingress连通性分析工具
+	server.AddTool(mcp.NewTool(ANALYZE_INGRESS,
+		mcp.WithDescription("分析Ingress的连通性问题。检查IngressClass及其控制器是否存在、Ingress是否已被控制器分配地址、每条规则引用的Service和端口是否存在并有就绪端点，以及TLS证书Secret是否存在。返回按严重程度（critical/warning/info）标记的问题列表。"),
+		mcp.WithString("name",
+			mcp.Description("Ingress名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Ingress所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.AnalyzeIngress)
+
+	// 注册路由表工具
+	server.AddTool(mcp.NewTool(LIST_ROUTES,
+		mcp.WithDescription("汇总集群的HTTP路由表，回答“哪个URL映射到哪个Service”。包括所有Ingress规则（主机名、路径、pathType、后端Service和端口、TLS Secret、IngressClass）；集群安装了Gateway API时（通过API发现检测）还包括Gateway的监听器和HTTPRoute的主机名与backendRefs，未指定主机名的HTTPRoute继承所挂载监听器的主机名。标记后端Service、端口或TLS Secret不存在的路由，以及不同命名空间中声明相同主机名和路径的冲突。结果按主机名分组，未指定主机名的路由归入'*'。"),
+		mcp.WithString("namespace",
+			mcp.Description("只列出该命名空间中的Ingress、Gateway和HTTPRoute。为空时列出所有命名空间。"),
+		),
+		mcp.WithString("host",
+			mcp.Description("主机名过滤条件，只返回主机名包含该字符串的路由（不区分大小写），例如：'example.com'。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListRoutes)
+
+	// 注册NetworkPolicy模拟工具
+	server.AddTool(mcp.NewTool(SIMULATE_NETWORK_POLICY,
+		mcp.WithDescription("模拟从源Pod到目标Pod或Service的流量是否被NetworkPolicy允许。评估源命名空间中选中源Pod的出站策略和目标命名空间中选中目标Pod的入站策略，处理namespaceSelector与podSelector的组合、命名端口和endPort端口范围；没有策略选中Pod时按默认允许所有流量处理；ipBlock规则因Pod IP是动态的而跳过并注明。目标为Service时按其targetPort评估每个后端Pod。返回判定结果以及每个策略、每条规则的评估过程，说明具体是哪条规则允许或为何被拒绝。"),
+		mcp.WithString("sourceNamespace",
+			mcp.Description("源Pod所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("sourcePod",
+			mcp.Description("源Pod名称，与sourceLabels二选一。"),
+		),
+		mcp.WithString("sourceLabels",
+			mcp.Description("源Pod的标签，与sourcePod二选一，格式为'app=web,tier=frontend'，用于评估尚未创建的Pod。"),
+		),
+		mcp.WithString("destinationNamespace",
+			mcp.Description("目标所在的命名空间。默认与源命名空间相同。"),
+		),
+		mcp.WithString("destinationPod",
+			mcp.Description("目标Pod名称，与destinationService二选一。"),
+		),
+		mcp.WithString("destinationService",
+			mcp.Description("目标Service名称，与destinationPod二选一。"),
+		),
+		mcp.WithString("port",
+			mcp.Description("目标端口号或端口名称。目标为Service时为Service端口，目标为Pod时为容器端口。"),
+			mcp.Required(),
+		),
+		mcp.WithString("protocol",
+			mcp.Description("协议。默认为TCP。"),
+			mcp.Enum("TCP", "UDP", "SCTP"),
+			mcp.DefaultString("TCP"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SimulateNetworkPolicy)
 }
 
 // GetScope 实现ToolHandler接口
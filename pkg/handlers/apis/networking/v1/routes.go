@@ -0,0 +1,610 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// Gateway API的API组
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+	// 未指定主机名的路由归入的分组
+	anyHost = "*"
+	// Ingress默认后端在路由表中的路径
+	defaultBackendPath = "(default backend)"
+)
+
+// gatewayAPIVersions 按优先级探测的Gateway API版本
+var gatewayAPIVersions = []string{"v1", "v1beta1"}
+
+// routeCollector 汇总路由表时的状态，缓存已查询过的Service、Secret和Gateway
+type routeCollector struct {
+	h          *ResourceHandlerImpl
+	hostFilter string
+	table      *models.RoutingTable
+	// routes 按主机名分组的路由
+	routes   map[string][]models.RouteEntry
+	services map[string]*corev1.Service
+	secrets  map[string]bool
+	gateways map[string]*unstructured.Unstructured
+	// gatewayGVR Gateway资源，集群未安装Gateway API时为空
+	gatewayGVR schema.GroupVersionResource
+}
+
+// ListRoutes 汇总Ingress、Gateway和HTTPRoute，按主机名列出路径到后端Service的映射
+func (h *ResourceHandlerImpl) ListRoutes(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	hostFilter, _ := arguments["host"].(string)
+
+	h.handler.Log.Info("Listing routes", "namespace", namespace, "host", hostFilter)
+
+	collector := &routeCollector{
+		h:          h,
+		hostFilter: strings.ToLower(hostFilter),
+		table: &models.RoutingTable{
+			Namespace:   namespace,
+			HostFilter:  hostFilter,
+			Hosts:       []models.RouteHostGroup{},
+			RetrievedAt: time.Now(),
+		},
+		routes:   make(map[string][]models.RouteEntry),
+		services: make(map[string]*corev1.Service),
+		secrets:  make(map[string]bool),
+		gateways: make(map[string]*unstructured.Unstructured),
+	}
+
+	ingresses, err := h.handler.Client.ClientSet().NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list ingresses", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list ingresses"), nil
+	}
+	collector.collectIngresses(ctx, ingresses.Items)
+
+	if version := h.gatewayAPIVersion(); version != "" {
+		collector.table.GatewayAPIVersion = gatewayAPIGroup + "/" + version
+		collector.gatewayGVR = schema.GroupVersionResource{Group: gatewayAPIGroup, Version: version, Resource: "gateways"}
+		collector.collectGateways(ctx, namespace)
+		collector.collectHTTPRoutes(ctx, namespace, version)
+	}
+
+	collector.finish()
+	h.handler.Log.Info("Routes listed", "routes", collector.table.RouteCount, "problems", collector.table.ProblemCount)
+	return utils.RenderResult(request, collector.table), nil
+}
+
+// gatewayAPIVersion 通过API发现检查集群是否安装了Gateway API，返回提供Gateway和HTTPRoute的版本
+func (h *ResourceHandlerImpl) gatewayAPIVersion() string {
+	for _, version := range gatewayAPIVersions {
+		resources, err := h.handler.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(gatewayAPIGroup + "/" + version)
+		if err != nil {
+			continue
+		}
+		found := 0
+		for _, resource := range resources.APIResources {
+			if resource.Name == "gateways" || resource.Name == "httproutes" {
+				found++
+			}
+		}
+		if found == 2 {
+			return version
+		}
+	}
+	return ""
+}
+
+// collectIngresses 将Ingress的每条规则路径和默认后端加入路由表
+func (c *routeCollector) collectIngresses(ctx context.Context, ingresses []networkingv1.Ingress) {
+	defaultClass := ""
+	classes, err := c.h.handler.Client.ClientSet().NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, class := range classes.Items {
+			if class.Annotations[defaultIngressClassAnnotation] == "true" {
+				defaultClass = class.Name
+				break
+			}
+		}
+	}
+
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		className := defaultClass
+		if ingress.Spec.IngressClassName != nil {
+			className = *ingress.Spec.IngressClassName
+		} else if legacy, ok := ingress.Annotations[legacyIngressClassAnnotation]; ok {
+			className = legacy
+		}
+
+		newEntry := func(host, path, pathType string, backend networkingv1.IngressBackend) models.RouteEntry {
+			entry := models.RouteEntry{
+				Kind:         "Ingress",
+				Namespace:    ingress.Namespace,
+				Name:         ingress.Name,
+				Path:         path,
+				PathType:     pathType,
+				Backends:     []models.RouteBackend{},
+				IngressClass: className,
+			}
+			if backend.Service != nil {
+				entry.Backends = append(entry.Backends, c.checkBackend(ctx, &entry, ingress.Namespace,
+					backend.Service.Name, backend.Service.Port.Name, int64(backend.Service.Port.Number), nil))
+			}
+			if secret := ingressTLSSecret(ingress, host); secret != "" {
+				entry.TLSSecret = secret
+				c.checkSecret(ctx, &entry.Problems, ingress.Namespace, secret)
+			}
+			return entry
+		}
+
+		if backend := ingress.Spec.DefaultBackend; backend != nil && c.matchesFilter(anyHost) {
+			c.add(anyHost, newEntry("", defaultBackendPath, "", *backend))
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			host := rule.Host
+			if host == "" {
+				host = anyHost
+			}
+			if !c.matchesFilter(host) {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				pathValue := path.Path
+				if pathValue == "" {
+					pathValue = "/"
+				}
+				pathType := ""
+				if path.PathType != nil {
+					pathType = string(*path.PathType)
+				}
+				c.add(host, newEntry(rule.Host, pathValue, pathType, path.Backend))
+			}
+		}
+	}
+}
+
+// collectGateways 列出Gateway及其监听器，检查监听器引用的TLS Secret是否存在
+func (c *routeCollector) collectGateways(ctx context.Context, namespace string) {
+	list, err := c.h.handler.Client.GetDynamicClient().Resource(c.gatewayGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.h.handler.Log.Warn("Failed to list gateways", "namespace", namespace, "error", err)
+		c.table.Errors = append(c.table.Errors, fmt.Sprintf("failed to list gateways: %v", err))
+		return
+	}
+	for i := range list.Items {
+		gateway := &list.Items[i]
+		c.gateways[gateway.GetNamespace()+"/"+gateway.GetName()] = gateway
+
+		summary := models.GatewaySummary{
+			Namespace: gateway.GetNamespace(),
+			Name:      gateway.GetName(),
+			Listeners: []models.GatewayListener{},
+		}
+		summary.GatewayClass, _, _ = unstructured.NestedString(gateway.Object, "spec", "gatewayClassName")
+		addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+		for _, address := range addresses {
+			if fields, ok := address.(map[string]interface{}); ok {
+				if value, _ := fields["value"].(string); value != "" {
+					summary.Addresses = append(summary.Addresses, value)
+				}
+			}
+		}
+		for _, listener := range gatewayListeners(gateway) {
+			for _, secret := range listener.TLSSecrets {
+				secretNamespace, secretName := splitNamespacedName(secret, gateway.GetNamespace())
+				c.checkSecret(ctx, &summary.Problems, secretNamespace, secretName)
+			}
+			summary.Listeners = append(summary.Listeners, listener)
+		}
+		c.table.Gateways = append(c.table.Gateways, summary)
+	}
+}
+
+// collectHTTPRoutes 将HTTPRoute的每个主机名和匹配路径加入路由表，未指定主机名时继承所挂载监听器的主机名
+func (c *routeCollector) collectHTTPRoutes(ctx context.Context, namespace, version string) {
+	gvr := schema.GroupVersionResource{Group: gatewayAPIGroup, Version: version, Resource: "httproutes"}
+	list, err := c.h.handler.Client.GetDynamicClient().Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.h.handler.Log.Warn("Failed to list HTTPRoutes", "namespace", namespace, "error", err)
+		c.table.Errors = append(c.table.Errors, fmt.Sprintf("failed to list HTTPRoutes: %v", err))
+		return
+	}
+
+	for i := range list.Items {
+		route := &list.Items[i]
+		routeNamespace := route.GetNamespace()
+
+		var parents []string
+		var listeners []models.GatewayListener
+		var parentNamespaces []string
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		for _, ref := range parentRefs {
+			fields, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := fields["kind"].(string); kind != "" && kind != "Gateway" {
+				continue
+			}
+			name, _ := fields["name"].(string)
+			parentNamespace, _ := fields["namespace"].(string)
+			if parentNamespace == "" {
+				parentNamespace = routeNamespace
+			}
+			sectionName, _ := fields["sectionName"].(string)
+			parent := parentNamespace + "/" + name
+			if sectionName != "" {
+				parent += "/" + sectionName
+			}
+			parents = append(parents, parent)
+
+			gateway := c.getGateway(ctx, parentNamespace, name)
+			if gateway == nil {
+				continue
+			}
+			for _, listener := range gatewayListeners(gateway) {
+				if sectionName == "" || listener.Name == sectionName {
+					listeners = append(listeners, listener)
+					parentNamespaces = append(parentNamespaces, parentNamespace)
+				}
+			}
+		}
+
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		if len(hostnames) == 0 {
+			seen := make(map[string]bool)
+			for _, listener := range listeners {
+				host := listener.Hostname
+				if host == "" {
+					host = anyHost
+				}
+				if !seen[host] {
+					seen[host] = true
+					hostnames = append(hostnames, host)
+				}
+			}
+		}
+		if len(hostnames) == 0 {
+			hostnames = []string{anyHost}
+		}
+
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		for _, host := range hostnames {
+			if !c.matchesFilter(host) {
+				continue
+			}
+			tlsNamespace, tlsSecret := "", ""
+			for j, listener := range listeners {
+				if len(listener.TLSSecrets) > 0 && (listener.Hostname == "" || hostMatches(listener.Hostname, host)) {
+					tlsNamespace, tlsSecret = splitNamespacedName(listener.TLSSecrets[0], parentNamespaces[j])
+					break
+				}
+			}
+
+			for _, rule := range rules {
+				fields, ok := rule.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, match := range httpRoutePaths(fields) {
+					entry := models.RouteEntry{
+						Kind:      "HTTPRoute",
+						Namespace: routeNamespace,
+						Name:      route.GetName(),
+						Path:      match[1],
+						PathType:  match[0],
+						Backends:  []models.RouteBackend{},
+						Gateways:  parents,
+					}
+					backendRefs, _, _ := unstructured.NestedSlice(fields, "backendRefs")
+					for _, backendRef := range backendRefs {
+						refFields, ok := backendRef.(map[string]interface{})
+						if !ok {
+							continue
+						}
+						// 只检查指向Service的后端
+						if kind, _ := refFields["kind"].(string); kind != "" && kind != "Service" {
+							continue
+						}
+						name, _ := refFields["name"].(string)
+						backendNamespace, _ := refFields["namespace"].(string)
+						if backendNamespace == "" {
+							backendNamespace = routeNamespace
+						}
+						port, _, _ := unstructured.NestedInt64(refFields, "port")
+						var weight *int64
+						if value, found, _ := unstructured.NestedInt64(refFields, "weight"); found {
+							weight = &value
+						}
+						backend := c.checkBackend(ctx, &entry, backendNamespace, name, "", port, weight)
+						if backendNamespace != routeNamespace {
+							backend.Namespace = backendNamespace
+						}
+						entry.Backends = append(entry.Backends, backend)
+					}
+					if tlsSecret != "" {
+						entry.TLSSecret = tlsSecret
+						if tlsNamespace != routeNamespace {
+							entry.TLSSecret = tlsNamespace + "/" + tlsSecret
+						}
+						c.checkSecret(ctx, &entry.Problems, tlsNamespace, tlsSecret)
+					}
+					c.add(host, entry)
+				}
+			}
+		}
+	}
+}
+
+// checkBackend 检查后端Service和端口是否存在，不存在时在路由上记录问题
+func (c *routeCollector) checkBackend(
+	ctx context.Context,
+	entry *models.RouteEntry,
+	namespace, name, portName string,
+	portNumber int64,
+	weight *int64,
+) models.RouteBackend {
+	backend := models.RouteBackend{Service: name, Weight: weight}
+	if portName != "" {
+		backend.Port = portName
+	} else if portNumber > 0 {
+		backend.Port = fmt.Sprintf("%d", portNumber)
+	}
+
+	service, known := c.getService(ctx, namespace, name)
+	if !known {
+		return backend
+	}
+	if service == nil {
+		entry.Problems = append(entry.Problems, fmt.Sprintf("backend Service %s/%s does not exist", namespace, name))
+		return backend
+	}
+	backend.ServiceExists = true
+	if backend.Port == "" {
+		backend.PortExists = len(service.Spec.Ports) == 1
+	}
+	for _, port := range service.Spec.Ports {
+		if (portName != "" && port.Name == portName) || (portName == "" && int64(port.Port) == portNumber) {
+			backend.PortExists = true
+			break
+		}
+	}
+	if !backend.PortExists {
+		entry.Problems = append(entry.Problems, fmt.Sprintf("backend port %s is not exposed by Service %s/%s", backend.Port, namespace, name))
+	}
+	return backend
+}
+
+// getService 返回缓存的Service，Service不存在时返回nil；查询失败时第二个返回值为false
+func (c *routeCollector) getService(ctx context.Context, namespace, name string) (*corev1.Service, bool) {
+	key := namespace + "/" + name
+	if service, ok := c.services[key]; ok {
+		return service, true
+	}
+	service, err := c.h.handler.Client.ClientSet().CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			c.table.Errors = append(c.table.Errors, fmt.Sprintf("failed to get Service %s: %v", key, err))
+			return nil, false
+		}
+		service = nil
+	}
+	c.services[key] = service
+	return service, true
+}
+
+// checkSecret 检查TLS Secret是否存在，不存在时记录问题
+func (c *routeCollector) checkSecret(ctx context.Context, problems *[]string, namespace, name string) {
+	key := namespace + "/" + name
+	exists, ok := c.secrets[key]
+	if !ok {
+		_, err := c.h.handler.Client.ClientSet().CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			exists = true
+		case errors.IsNotFound(err):
+			exists = false
+		default:
+			// 无权读取Secret时不判断是否存在
+			c.table.Errors = append(c.table.Errors, fmt.Sprintf("failed to get Secret %s: %v", key, err))
+			exists = true
+		}
+		c.secrets[key] = exists
+	}
+	if !exists {
+		*problems = append(*problems, fmt.Sprintf("TLS secret %s does not exist", key))
+	}
+}
+
+// getGateway 返回HTTPRoute挂载的Gateway，命名空间过滤范围外的Gateway按需获取
+func (c *routeCollector) getGateway(ctx context.Context, namespace, name string) *unstructured.Unstructured {
+	key := namespace + "/" + name
+	if gateway, ok := c.gateways[key]; ok {
+		return gateway
+	}
+	gateway, err := c.h.handler.Client.GetDynamicClient().Resource(c.gatewayGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		gateway = nil
+	}
+	c.gateways[key] = gateway
+	return gateway
+}
+
+// matchesFilter 检查主机名是否包含host过滤条件
+func (c *routeCollector) matchesFilter(host string) bool {
+	return c.hostFilter == "" || strings.Contains(strings.ToLower(host), c.hostFilter)
+}
+
+// add 将路由加入对应主机名的分组
+func (c *routeCollector) add(host string, entry models.RouteEntry) {
+	if !c.matchesFilter(host) {
+		return
+	}
+	c.routes[host] = append(c.routes[host], entry)
+}
+
+// finish 按主机名排序路由表，统计问题并找出跨命名空间的重复声明
+func (c *routeCollector) finish() {
+	hosts := make([]string, 0, len(c.routes))
+	for host := range c.routes {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	for _, host := range hosts {
+		routes := c.routes[host]
+		sort.SliceStable(routes, func(i, j int) bool {
+			if routes[i].Path != routes[j].Path {
+				return routes[i].Path < routes[j].Path
+			}
+			if routes[i].Namespace != routes[j].Namespace {
+				return routes[i].Namespace < routes[j].Namespace
+			}
+			return routes[i].Name < routes[j].Name
+		})
+
+		claims := make(map[string][]models.RouteEntry)
+		var paths []string
+		for _, route := range routes {
+			c.table.RouteCount++
+			c.table.ProblemCount += len(route.Problems)
+			if _, ok := claims[route.Path]; !ok {
+				paths = append(paths, route.Path)
+			}
+			claims[route.Path] = append(claims[route.Path], route)
+		}
+		for _, path := range paths {
+			namespaces := make(map[string]bool)
+			var names []string
+			for _, route := range claims[path] {
+				namespaces[route.Namespace] = true
+				name := fmt.Sprintf("%s %s/%s", route.Kind, route.Namespace, route.Name)
+				if len(names) == 0 || names[len(names)-1] != name {
+					names = append(names, name)
+				}
+			}
+			if len(namespaces) > 1 {
+				c.table.Conflicts = append(c.table.Conflicts, models.RouteConflict{Host: host, Path: path, Claims: names})
+			}
+		}
+		c.table.Hosts = append(c.table.Hosts, models.RouteHostGroup{Host: host, Routes: routes})
+	}
+	for _, gateway := range c.table.Gateways {
+		c.table.ProblemCount += len(gateway.Problems)
+	}
+}
+
+// gatewayListeners 读取Gateway的监听器，TLS Secret在Gateway命名空间外时格式为namespace/name
+func gatewayListeners(gateway *unstructured.Unstructured) []models.GatewayListener {
+	raw, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	listeners := make([]models.GatewayListener, 0, len(raw))
+	for _, item := range raw {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		listener := models.GatewayListener{}
+		listener.Name, _ = fields["name"].(string)
+		listener.Hostname, _ = fields["hostname"].(string)
+		listener.Protocol, _ = fields["protocol"].(string)
+		listener.Port, _, _ = unstructured.NestedInt64(fields, "port")
+		refs, _, _ := unstructured.NestedSlice(fields, "tls", "certificateRefs")
+		for _, ref := range refs {
+			refFields, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := refFields["kind"].(string); kind != "" && kind != "Secret" {
+				continue
+			}
+			name, _ := refFields["name"].(string)
+			if namespace, _ := refFields["namespace"].(string); namespace != "" && namespace != gateway.GetNamespace() {
+				name = namespace + "/" + name
+			}
+			listener.TLSSecrets = append(listener.TLSSecrets, name)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// httpRoutePaths 返回HTTPRoute规则匹配的路径类型和路径，规则没有matches时匹配所有路径
+func httpRoutePaths(rule map[string]interface{}) [][2]string {
+	matches, _, _ := unstructured.NestedSlice(rule, "matches")
+	var paths [][2]string
+	for _, match := range matches {
+		fields, ok := match.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pathType, _, _ := unstructured.NestedString(fields, "path", "type")
+		if pathType == "" {
+			pathType = "PathPrefix"
+		}
+		value, _, _ := unstructured.NestedString(fields, "path", "value")
+		if value == "" {
+			value = "/"
+		}
+		paths = append(paths, [2]string{pathType, value})
+	}
+	if len(paths) == 0 {
+		paths = append(paths, [2]string{"PathPrefix", "/"})
+	}
+	return paths
+}
+
+// ingressTLSSecret 返回Ingress中覆盖指定主机名的TLS Secret
+func ingressTLSSecret(ingress *networkingv1.Ingress, host string) string {
+	for _, tls := range ingress.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+		if len(tls.Hosts) == 0 {
+			return tls.SecretName
+		}
+		for _, tlsHost := range tls.Hosts {
+			if hostMatches(tlsHost, host) {
+				return tls.SecretName
+			}
+		}
+	}
+	return ""
+}
+
+// hostMatches 检查主机名是否匹配模式，模式支持"*.example.com"形式的通配符
+func hostMatches(pattern, host string) bool {
+	if pattern == host {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(host, suffix) && !strings.Contains(strings.TrimSuffix(host, suffix), ".")
+	}
+	return false
+}
+
+// splitNamespacedName 拆分namespace/name形式的引用，没有命名空间时使用默认命名空间
+func splitNamespacedName(value, defaultNamespace string) (string, string) {
+	if namespace, name, ok := strings.Cut(value, "/"); ok {
+		return namespace, name
+	}
+	return defaultNamespace, value
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
@@ -37,7 +36,7 @@ func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRe
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
 	// 使用父类的注册方法
 	h.baseHandler.Register(server)
 }
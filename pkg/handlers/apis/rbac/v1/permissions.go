@@ -0,0 +1,287 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// CheckPermission 检查MCP服务器当前身份是否拥有指定权限
+func (h *ResourceHandlerImpl) CheckPermission(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	attrs, err := parseResourceAttributes(request.GetArguments())
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+
+	h.handler.Log.Info("Checking permission",
+		"verb", attrs.Verb,
+		"group", attrs.Group,
+		"resource", attrs.Resource,
+		"subresource", attrs.Subresource,
+		"namespace", attrs.Namespace,
+		"name", attrs.Name,
+	)
+
+	result, err := h.handler.CheckAccess(ctx, attrs)
+	if err != nil {
+		h.handler.Log.Error("Failed to create SelfSubjectAccessReview", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to check permission"), nil
+	}
+
+	return utils.RenderResult(request, result), nil
+}
+
+// WhoCan 遍历Role/ClusterRole及其绑定，列出拥有指定权限的主体
+func (h *ResourceHandlerImpl) WhoCan(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	attrs, err := parseResourceAttributes(request.GetArguments())
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+
+	h.handler.Log.Info("Finding subjects with permission",
+		"verb", attrs.Verb,
+		"group", attrs.Group,
+		"resource", attrs.Resource,
+		"subresource", attrs.Subresource,
+		"namespace", attrs.Namespace,
+		"name", attrs.Name,
+	)
+
+	rbacClient := h.handler.Client.ClientSet().RbacV1()
+	clusterRoles, err := rbacClient.ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list cluster roles"), nil
+	}
+	clusterRoleBindings, err := rbacClient.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list cluster role bindings"), nil
+	}
+
+	response := models.WhoCanResponse{
+		Verb:        attrs.Verb,
+		Group:       attrs.Group,
+		Resource:    attrs.Resource,
+		Subresource: attrs.Subresource,
+		Namespace:   attrs.Namespace,
+		Name:        attrs.Name,
+		Subjects:    []models.WhoCanSubject{},
+	}
+
+	// 预先计算每个ClusterRole是否授予该权限（包含聚合角色）
+	clusterRoleGrants := make(map[string]bool, len(clusterRoles.Items))
+	for i := range clusterRoles.Items {
+		role := &clusterRoles.Items[i]
+		clusterRoleGrants[role.Name] = rulesAllow(effectiveClusterRoleRules(role, clusterRoles.Items), attrs)
+	}
+
+	subjects := make(map[string]*models.WhoCanSubject)
+	addGrant := func(subject rbacv1.Subject, grant models.RoleGrant) {
+		key := subject.Kind + "/" + subject.Namespace + "/" + subject.Name
+		s, ok := subjects[key]
+		if !ok {
+			s = &models.WhoCanSubject{
+				Kind:      subject.Kind,
+				Name:      subject.Name,
+				Namespace: subject.Namespace,
+			}
+			subjects[key] = s
+		}
+		s.Grants = append(s.Grants, grant)
+	}
+
+	// ClusterRoleBinding 在所有命名空间生效
+	for _, binding := range clusterRoleBindings.Items {
+		if binding.RoleRef.Kind != "ClusterRole" || !clusterRoleGrants[binding.RoleRef.Name] {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			addGrant(subject, models.RoleGrant{
+				BindingKind: "ClusterRoleBinding",
+				BindingName: binding.Name,
+				RoleKind:    binding.RoleRef.Kind,
+				RoleName:    binding.RoleRef.Name,
+			})
+		}
+	}
+
+	// RoleBinding 只在所在命名空间生效，集群级检查时跳过
+	if attrs.Namespace != "" {
+		roles, err := rbacClient.Roles(attrs.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("failed to list roles: %v", err))
+		}
+		roleGrants := make(map[string]bool)
+		if roles != nil {
+			for _, role := range roles.Items {
+				roleGrants[role.Name] = rulesAllow(role.Rules, attrs)
+			}
+		}
+
+		roleBindings, err := rbacClient.RoleBindings(attrs.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("failed to list role bindings: %v", err))
+		} else {
+			for _, binding := range roleBindings.Items {
+				granted := false
+				switch binding.RoleRef.Kind {
+				case "ClusterRole":
+					granted = clusterRoleGrants[binding.RoleRef.Name]
+				case "Role":
+					granted = roleGrants[binding.RoleRef.Name]
+				}
+				if !granted {
+					continue
+				}
+				for _, subject := range binding.Subjects {
+					// RoleBinding 中未指定命名空间的ServiceAccount属于绑定所在的命名空间
+					if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" {
+						subject.Namespace = binding.Namespace
+					}
+					addGrant(subject, models.RoleGrant{
+						BindingKind:      "RoleBinding",
+						BindingName:      binding.Name,
+						BindingNamespace: binding.Namespace,
+						RoleKind:         binding.RoleRef.Kind,
+						RoleName:         binding.RoleRef.Name,
+					})
+				}
+			}
+		}
+	}
+
+	for _, s := range subjects {
+		response.Subjects = append(response.Subjects, *s)
+	}
+	sort.Slice(response.Subjects, func(i, j int) bool {
+		a, b := response.Subjects[i], response.Subjects[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	response.Count = len(response.Subjects)
+	response.RetrievedAt = time.Now()
+
+	h.handler.Log.Info("Subjects with permission found", "count", response.Count)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// parseResourceAttributes 从工具参数中解析权限属性
+// resource 支持 kubectl 风格的写法，例如 "deployments.apps"、"pods/log"
+func parseResourceAttributes(arguments map[string]interface{}) (authorizationv1.ResourceAttributes, error) {
+	verb, _ := arguments["verb"].(string)
+	resource, _ := arguments["resource"].(string)
+	group, _ := arguments["group"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	name, _ := arguments["name"].(string)
+
+	if verb == "" || resource == "" {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf("missing required parameters: verb and resource")
+	}
+
+	subresource := ""
+	if idx := strings.Index(resource, "/"); idx >= 0 {
+		resource, subresource = resource[:idx], resource[idx+1:]
+	}
+	if idx := strings.Index(resource, "."); idx >= 0 && group == "" {
+		resource, group = resource[:idx], resource[idx+1:]
+	}
+
+	return authorizationv1.ResourceAttributes{
+		Verb:        strings.ToLower(verb),
+		Group:       group,
+		Resource:    strings.ToLower(resource),
+		Subresource: subresource,
+		Namespace:   namespace,
+		Name:        name,
+	}, nil
+}
+
+// effectiveClusterRoleRules 返回ClusterRole的有效规则，聚合角色会合并所有匹配选择器的ClusterRole规则
+func effectiveClusterRoleRules(role *rbacv1.ClusterRole, all []rbacv1.ClusterRole) []rbacv1.PolicyRule {
+	if role.AggregationRule == nil {
+		return role.Rules
+	}
+
+	rules := append([]rbacv1.PolicyRule{}, role.Rules...)
+	for _, labelSelector := range role.AggregationRule.ClusterRoleSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		if err != nil {
+			continue
+		}
+		for i := range all {
+			candidate := &all[i]
+			if candidate.Name == role.Name || !selector.Matches(labels.Set(candidate.Labels)) {
+				continue
+			}
+			rules = append(rules, candidate.Rules...)
+		}
+	}
+	return rules
+}
+
+// rulesAllow 判断规则列表是否授予指定权限，支持通配符
+func rulesAllow(rules []rbacv1.PolicyRule, attrs authorizationv1.ResourceAttributes) bool {
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource = attrs.Resource + "/" + attrs.Subresource
+	}
+
+	for _, rule := range rules {
+		if !matchesAny(rule.Verbs, attrs.Verb) || !matchesAny(rule.APIGroups, attrs.Group) {
+			continue
+		}
+		if !matchesResource(rule.Resources, resource, attrs.Subresource) {
+			continue
+		}
+		if len(rule.ResourceNames) > 0 && (attrs.Name == "" || !matchesAny(rule.ResourceNames, attrs.Name)) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// matchesAny 判断值是否在列表中，"*" 匹配任意值
+func matchesAny(values []string, value string) bool {
+	for _, v := range values {
+		if v == rbacv1.VerbAll || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource 判断资源是否在规则资源列表中，支持 "*" 和 "*/subresource"
+func matchesResource(resources []string, resource, subresource string) bool {
+	for _, r := range resources {
+		if r == rbacv1.ResourceAll || r == resource {
+			return true
+		}
+		if subresource != "" && r == "*/"+subresource {
+			return true
+		}
+	}
+	return false
+}
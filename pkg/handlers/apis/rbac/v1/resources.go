@@ -5,10 +5,18 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	CHECK_PERMISSION = "CHECK_PERMISSION"
+	WHO_CAN          = "WHO_CAN"
+
+	SCAN_WORKLOAD_SECURITY = "SCAN_WORKLOAD_SECURITY"
+	AUDIT_SERVICE_ACCOUNTS = "AUDIT_SERVICE_ACCOUNTS"
 )
 
 // ResourceHandlerImpl RBAC资源处理程序实现
@@ -32,14 +40,109 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 使用父类的处理方法
-	return h.baseHandler.Handle(ctx, request)
+	// 根据工具名称分派到具体的处理方法
+	switch request.Method {
+	case CHECK_PERMISSION:
+		return h.CheckPermission(ctx, request)
+	case WHO_CAN:
+		return h.WhoCan(ctx, request)
+	case SCAN_WORKLOAD_SECURITY:
+		return h.ScanWorkloadSecurity(ctx, request)
+	case AUDIT_SERVICE_ACCOUNTS:
+		return h.AuditServiceAccounts(ctx, request)
+	default:
+		// 其他方法使用父类的处理方法
+		return h.baseHandler.Handle(ctx, request)
+	}
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
-	// 使用父类的注册方法
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
+	// 注册父类的工具
 	h.baseHandler.Register(server)
+
+	// 额外注册权限检查工具
+	server.AddTool(mcp.NewTool(CHECK_PERMISSION,
+		mcp.WithDescription("检查MCP服务器当前使用的身份是否拥有指定权限（基于SelfSubjectAccessReview）。建议在执行创建、更新、删除等操作前先检查，以便明确说明缺少哪些权限。结果会短暂缓存。"),
+		mcp.WithString("verb",
+			mcp.Description("操作动词，例如：'get'、'list'、'create'、'update'、'patch'、'delete'、'watch'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("resource",
+			mcp.Description("资源名称（复数形式），支持kubectl风格写法，例如：'pods'、'deployments.apps'、'pods/log'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("group",
+			mcp.Description("API组（可选），例如：'apps'。核心资源留空。也可以在resource中以'<resource>.<group>'形式指定。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间（可选）。不指定时检查集群范围的权限。"),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称（可选），用于检查针对特定对象的权限。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckPermission)
+
+	server.AddTool(mcp.NewTool(WHO_CAN,
+		mcp.WithDescription("查询哪些主体（用户、组、ServiceAccount）拥有指定权限。遍历Role/ClusterRole及其绑定，支持聚合ClusterRole以及通配符动词和资源，并返回每个主体获得权限的绑定路径。适用于权限审计和排查访问问题。"),
+		mcp.WithString("verb",
+			mcp.Description("操作动词，例如：'get'、'list'、'create'、'update'、'patch'、'delete'、'watch'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("resource",
+			mcp.Description("资源名称（复数形式），支持kubectl风格写法，例如：'pods'、'deployments.apps'、'pods/log'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("group",
+			mcp.Description("API组（可选），例如：'apps'。核心资源留空。也可以在resource中以'<resource>.<group>'形式指定。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间（可选）。不指定时检查集群范围的权限。"),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称（可选），用于检查针对特定对象的权限。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.WhoCan)
+
+	// 注册工作负载安全扫描工具
+	server.AddTool(mcp.NewTool(SCAN_WORKLOAD_SECURITY,
+		mcp.WithDescription("按加固规则扫描工作负载（Deployment、StatefulSet、DaemonSet、CronJob、Job）和独立Pod的Pod规格：特权容器（SEC001）、hostPID（SEC002）、hostNetwork（SEC003）、hostIPC（SEC004）、hostPath卷（SEC005）、未设置runAsNonRoot（SEC006）、allowPrivilegeEscalation未设为false（SEC007）、缺少CPU或内存限制（SEC008）、自动挂载的ServiceAccount令牌拥有高风险RBAC权限（SEC009，结合ClusterRoleBinding和RoleBinding判断，例如读取Secret、创建Pod、通配符权限）、镜像按标签而非摘要引用（SEC010）。每个发现包含规则编号、严重程度、对象和具体的字段路径。只读操作。"),
+		mcp.WithString("namespace",
+			mcp.Description("要扫描的命名空间。留空表示扫描所有命名空间。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），只扫描匹配的工作负载和Pod，例如'app=nginx'。"),
+		),
+		mcp.WithString("minSeverity",
+			mcp.Description("最低严重程度，低于该级别的规则不参与扫描。默认为info（全部规则）。"),
+			mcp.Enum("critical", "warning", "info"),
+			mcp.DefaultString("info"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ScanWorkloadSecurity)
+
+	// 注册ServiceAccount审计工具
+	server.AddTool(mcp.NewTool(AUDIT_SERVICE_ACCOUNTS,
+		mcp.WithDescription("按ServiceAccount审计：列出使用它的工作负载及是否挂载令牌、automountServiceAccountToken设置、通过RoleBinding和ClusterRoleBinding绑定的角色（按动词和资源汇总权限范围：full、write、read）、引用的imagePullSecrets（检查是否存在且类型为kubernetes.io/dockerconfigjson）。标记高风险组合，例如default ServiceAccount绑定cluster-admin或通配符规则、被工作负载挂载的令牌拥有读取Secret等高风险权限、未被使用的ServiceAccount。每个发现带严重程度。只读操作。"),
+		mcp.WithString("namespace",
+			mcp.Description("要审计的命名空间。留空表示审计所有命名空间。"),
+		),
+		mcp.WithString("minSeverity",
+			mcp.Description("最低严重程度，只返回不低于该级别的发现。默认为info。"),
+			mcp.Enum("critical", "warning", "info"),
+			mcp.DefaultString("info"),
+		),
+		mcp.WithBoolean("includeClean",
+			mcp.Description("是否同时返回没有发现的ServiceAccount。默认为false。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.AuditServiceAccounts)
 }
 
 // GetScope 实现ToolHandler接口
@@ -0,0 +1,223 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// securityTarget 被扫描的Pod规格及其上下文
+type securityTarget struct {
+	// kind和name 拥有该Pod规格的对象，独立Pod为Pod本身
+	kind      string
+	name      string
+	namespace string
+	// specPath Pod规格在对象中的字段路径，例如：spec.template.spec
+	specPath string
+	spec     *corev1.PodSpec
+	// automountToken ServiceAccount令牌是否会挂载到Pod中
+	automountToken bool
+	// tokenGrants ServiceAccount拥有的高风险权限，nil表示无法读取RBAC
+	tokenGrants []string
+}
+
+// securityViolation 规则在目标中发现的单个问题
+type securityViolation struct {
+	path    string
+	message string
+}
+
+// securityRule 工作负载安全规则，check返回目标中违反规则的字段
+type securityRule struct {
+	id          string
+	name        string
+	severity    string
+	description string
+	check       func(target *securityTarget) []securityViolation
+}
+
+// securityRules 工作负载安全扫描的规则表，新增规则只需在此追加一项
+var securityRules = []securityRule{
+	{
+		id:          "SEC001",
+		name:        "privileged-container",
+		severity:    models.SeverityCritical,
+		description: "容器以特权模式运行，可访问宿主机的全部设备",
+		check: containerCheck(func(c *corev1.Container) (string, string) {
+			if c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+				return "securityContext.privileged", "container runs privileged"
+			}
+			return "", ""
+		}),
+	},
+	{
+		id:          "SEC002",
+		name:        "host-pid",
+		severity:    models.SeverityCritical,
+		description: "Pod共享宿主机的进程命名空间，可查看并向宿主机进程发送信号",
+		check: podCheck("hostPID", func(spec *corev1.PodSpec) bool {
+			return spec.HostPID
+		}, "pod shares the host PID namespace"),
+	},
+	{
+		id:          "SEC003",
+		name:        "host-network",
+		severity:    models.SeverityWarning,
+		description: "Pod使用宿主机网络，可监听宿主机端口并绕过NetworkPolicy",
+		check: podCheck("hostNetwork", func(spec *corev1.PodSpec) bool {
+			return spec.HostNetwork
+		}, "pod uses the host network namespace"),
+	},
+	{
+		id:          "SEC004",
+		name:        "host-ipc",
+		severity:    models.SeverityWarning,
+		description: "Pod共享宿主机的IPC命名空间",
+		check: podCheck("hostIPC", func(spec *corev1.PodSpec) bool {
+			return spec.HostIPC
+		}, "pod shares the host IPC namespace"),
+	},
+	{
+		id:          "SEC005",
+		name:        "host-path-volume",
+		severity:    models.SeverityWarning,
+		description: "Pod挂载宿主机目录，可能读取或篡改节点上的文件",
+		check: func(target *securityTarget) []securityViolation {
+			var violations []securityViolation
+			for i, volume := range target.spec.Volumes {
+				if volume.HostPath != nil {
+					violations = append(violations, securityViolation{
+						path:    fmt.Sprintf("%s.volumes[%d].hostPath", target.specPath, i),
+						message: fmt.Sprintf("volume %s mounts host path %s", volume.Name, volume.HostPath.Path),
+					})
+				}
+			}
+			return violations
+		},
+	},
+	{
+		id:          "SEC006",
+		name:        "run-as-non-root-missing",
+		severity:    models.SeverityWarning,
+		description: "未设置runAsNonRoot，容器可能以root用户运行",
+		check: func(target *securityTarget) []securityViolation {
+			podNonRoot := target.spec.SecurityContext != nil && target.spec.SecurityContext.RunAsNonRoot != nil && *target.spec.SecurityContext.RunAsNonRoot
+			return containerCheck(func(c *corev1.Container) (string, string) {
+				// 容器级别的设置覆盖Pod级别
+				if c.SecurityContext != nil && c.SecurityContext.RunAsNonRoot != nil {
+					if *c.SecurityContext.RunAsNonRoot {
+						return "", ""
+					}
+					return "securityContext.runAsNonRoot", "container sets runAsNonRoot to false"
+				}
+				if podNonRoot {
+					return "", ""
+				}
+				return "securityContext.runAsNonRoot", "runAsNonRoot is not set on the container or the pod"
+			})(target)
+		},
+	},
+	{
+		id:          "SEC007",
+		name:        "privilege-escalation-allowed",
+		severity:    models.SeverityWarning,
+		description: "未将allowPrivilegeEscalation设置为false，进程可通过setuid等方式获得更高权限",
+		check: containerCheck(func(c *corev1.Container) (string, string) {
+			if c.SecurityContext == nil || c.SecurityContext.AllowPrivilegeEscalation == nil || *c.SecurityContext.AllowPrivilegeEscalation {
+				return "securityContext.allowPrivilegeEscalation", "allowPrivilegeEscalation is not set to false"
+			}
+			return "", ""
+		}),
+	},
+	{
+		id:          "SEC008",
+		name:        "resource-limits-missing",
+		severity:    models.SeverityWarning,
+		description: "容器未设置CPU或内存限制，可能耗尽节点资源影响其他工作负载",
+		check: containerCheck(func(c *corev1.Container) (string, string) {
+			var missing []string
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if _, ok := c.Resources.Limits[name]; !ok {
+					missing = append(missing, string(name))
+				}
+			}
+			if len(missing) == 0 {
+				return "", ""
+			}
+			return "resources.limits", fmt.Sprintf("no %s limit", strings.Join(missing, " or "))
+		}),
+	},
+	{
+		id:          "SEC009",
+		name:        "token-with-broad-rbac",
+		severity:    models.SeverityCritical,
+		description: "Pod自动挂载的ServiceAccount令牌拥有高风险权限，容器被攻破后可用于横向移动",
+		check: func(target *securityTarget) []securityViolation {
+			if !target.automountToken || len(target.tokenGrants) == 0 {
+				return nil
+			}
+			serviceAccount := target.spec.ServiceAccountName
+			if serviceAccount == "" {
+				serviceAccount = "default"
+			}
+			return []securityViolation{{
+				path: target.specPath + ".automountServiceAccountToken",
+				message: fmt.Sprintf("service account %s token is mounted and grants: %s",
+					serviceAccount, strings.Join(target.tokenGrants, "; ")),
+			}}
+		},
+	},
+	{
+		id:          "SEC010",
+		name:        "image-not-pinned",
+		severity:    models.SeverityInfo,
+		description: "镜像按标签而非摘要拉取，标签可能被覆盖为不同的内容",
+		check: containerCheck(func(c *corev1.Container) (string, string) {
+			if strings.Contains(c.Image, "@sha256:") {
+				return "", ""
+			}
+			return "image", fmt.Sprintf("image %s is referenced by tag instead of digest", c.Image)
+		}),
+	},
+}
+
+// podCheck 构建检查Pod级别布尔字段的规则
+func podCheck(field string, violated func(spec *corev1.PodSpec) bool, message string) func(target *securityTarget) []securityViolation {
+	return func(target *securityTarget) []securityViolation {
+		if !violated(target.spec) {
+			return nil
+		}
+		return []securityViolation{{path: target.specPath + "." + field, message: message}}
+	}
+}
+
+// containerCheck 构建逐个检查初始化容器和容器的规则
+// check返回相对于容器的字段路径和问题描述，路径为空表示没有问题
+func containerCheck(check func(c *corev1.Container) (string, string)) func(target *securityTarget) []securityViolation {
+	return func(target *securityTarget) []securityViolation {
+		var violations []securityViolation
+		for _, group := range []struct {
+			field      string
+			containers []corev1.Container
+		}{
+			{"initContainers", target.spec.InitContainers},
+			{"containers", target.spec.Containers},
+		} {
+			for i := range group.containers {
+				container := &group.containers[i]
+				path, message := check(container)
+				if path == "" {
+					continue
+				}
+				violations = append(violations, securityViolation{
+					path:    fmt.Sprintf("%s.%s[%d].%s", target.specPath, group.field, i, path),
+					message: fmt.Sprintf("container %s: %s", container.Name, message),
+				})
+			}
+		}
+		return violations
+	}
+}
@@ -0,0 +1,418 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// broadPermissions ServiceAccount令牌被视为高风险的权限
+var broadPermissions = []struct {
+	label string
+	attrs authorizationv1.ResourceAttributes
+}{
+	{"full access to all resources", authorizationv1.ResourceAttributes{Verb: "*", Group: "*", Resource: "*"}},
+	{"get secrets", authorizationv1.ResourceAttributes{Verb: "get", Resource: "secrets"}},
+	{"list secrets", authorizationv1.ResourceAttributes{Verb: "list", Resource: "secrets"}},
+	{"create pods", authorizationv1.ResourceAttributes{Verb: "create", Resource: "pods"}},
+	{"exec into pods", authorizationv1.ResourceAttributes{Verb: "create", Resource: "pods", Subresource: "exec"}},
+	{"create service account tokens", authorizationv1.ResourceAttributes{Verb: "create", Resource: "serviceaccounts", Subresource: "token"}},
+	{"escalate roles", authorizationv1.ResourceAttributes{Verb: "escalate", Group: rbacv1.GroupName, Resource: "clusterroles"}},
+	{"bind roles", authorizationv1.ResourceAttributes{Verb: "bind", Group: rbacv1.GroupName, Resource: "clusterroles"}},
+	{"impersonate users", authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "users"}},
+}
+
+// serviceAccountAuditor 计算ServiceAccount的高风险权限和令牌挂载设置，按命名空间缓存RBAC对象
+type serviceAccountAuditor struct {
+	h                   *ResourceHandlerImpl
+	clusterRoles        []rbacv1.ClusterRole
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+	// rbacAvailable 为false时无法读取集群级RBAC，跳过令牌权限检查
+	rbacAvailable   bool
+	roles           map[string][]rbacv1.Role
+	roleBindings    map[string][]rbacv1.RoleBinding
+	serviceAccounts map[string]map[string]corev1.ServiceAccount
+	grants          map[string][]string
+	warnings        []string
+}
+
+// ScanWorkloadSecurity 按规则表检查工作负载和独立Pod的Pod规格，返回带规则编号、严重程度和字段路径的问题列表
+func (h *ResourceHandlerImpl) ScanWorkloadSecurity(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	minSeverity, _ := arguments["minSeverity"].(string)
+	if minSeverity == "" {
+		minSeverity = models.SeverityInfo
+	}
+
+	h.handler.Log.Info("Scanning workload security",
+		"namespace", namespace,
+		"labelSelector", labelSelector,
+		"minSeverity", minSeverity,
+	)
+
+	switch minSeverity {
+	case models.SeverityCritical, models.SeverityWarning, models.SeverityInfo:
+	default:
+		return utils.NewErrorToolResult(fmt.Sprintf("invalid minSeverity %q, must be critical, warning or info", minSeverity)), nil
+	}
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("invalid labelSelector %q: %v", labelSelector, err)), nil
+	}
+
+	targets, err := h.securityTargets(ctx, namespace, labelSelector)
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list workloads"), nil
+	}
+
+	report := &models.SecurityScanReport{
+		Namespace:      namespace,
+		LabelSelector:  labelSelector,
+		MinSeverity:    minSeverity,
+		ScannedObjects: len(targets),
+		ByRule:         map[string]int{},
+		Findings:       []models.SecurityFinding{},
+		Rules:          []models.SecurityRuleInfo{},
+		RetrievedAt:    time.Now(),
+	}
+	var rules []securityRule
+	for _, rule := range securityRules {
+		if models.SeverityRank(rule.severity) > models.SeverityRank(minSeverity) {
+			continue
+		}
+		rules = append(rules, rule)
+		report.Rules = append(report.Rules, models.SecurityRuleInfo{
+			ID:          rule.id,
+			Name:        rule.name,
+			Severity:    rule.severity,
+			Description: rule.description,
+		})
+	}
+
+	auditor := h.newServiceAccountAuditor(ctx)
+	for i := range targets {
+		target := &targets[i]
+		target.automountToken, target.tokenGrants = auditor.token(ctx, target.namespace, target.spec)
+		for _, rule := range rules {
+			for _, violation := range rule.check(target) {
+				report.Findings = append(report.Findings, models.SecurityFinding{
+					RuleID:    rule.id,
+					Rule:      rule.name,
+					Severity:  rule.severity,
+					Object:    target.kind + "/" + target.name,
+					Namespace: target.namespace,
+					Path:      violation.path,
+					Message:   violation.message,
+				})
+			}
+		}
+	}
+	report.Warnings = auditor.warnings
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if models.SeverityRank(a.Severity) != models.SeverityRank(b.Severity) {
+			return models.SeverityRank(a.Severity) < models.SeverityRank(b.Severity)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Object != b.Object {
+			return a.Object < b.Object
+		}
+		return a.RuleID < b.RuleID
+	})
+	for _, finding := range report.Findings {
+		report.ByRule[finding.RuleID]++
+		switch finding.Severity {
+		case models.SeverityCritical:
+			report.Summary.Critical++
+		case models.SeverityWarning:
+			report.Summary.Warning++
+		default:
+			report.Summary.Info++
+		}
+	}
+
+	h.handler.Log.Info("Workload security scan completed", "objects", len(targets), "findings", len(report.Findings))
+	return utils.RenderResult(request, report), nil
+}
+
+// securityTargets 列出要扫描的Pod规格：Deployment、StatefulSet、DaemonSet、CronJob、不属于CronJob的Job以及没有控制器的独立Pod
+func (h *ResourceHandlerImpl) securityTargets(ctx context.Context, namespace, labelSelector string) ([]securityTarget, error) {
+	clientset := h.handler.Client.ClientSet()
+	options := metav1.ListOptions{LabelSelector: labelSelector}
+	var targets []securityTarget
+	add := func(kind string, meta metav1.ObjectMeta, specPath string, spec *corev1.PodSpec) {
+		targets = append(targets, securityTarget{
+			kind:      kind,
+			name:      meta.Name,
+			namespace: meta.Namespace,
+			specPath:  specPath,
+			spec:      spec,
+		})
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		item := &deployments.Items[i]
+		add("Deployment", item.ObjectMeta, "spec.template.spec", &item.Spec.Template.Spec)
+	}
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		item := &statefulSets.Items[i]
+		add("StatefulSet", item.ObjectMeta, "spec.template.spec", &item.Spec.Template.Spec)
+	}
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSets.Items {
+		item := &daemonSets.Items[i]
+		add("DaemonSet", item.ObjectMeta, "spec.template.spec", &item.Spec.Template.Spec)
+	}
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cronJobs.Items {
+		item := &cronJobs.Items[i]
+		add("CronJob", item.ObjectMeta, "spec.jobTemplate.spec.template.spec", &item.Spec.JobTemplate.Spec.Template.Spec)
+	}
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs.Items {
+		item := &jobs.Items[i]
+		if metav1.GetControllerOf(item) != nil {
+			continue
+		}
+		add("Job", item.ObjectMeta, "spec.template.spec", &item.Spec.Template.Spec)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		item := &pods.Items[i]
+		if metav1.GetControllerOf(item) != nil {
+			continue
+		}
+		add("Pod", item.ObjectMeta, "spec", &item.Spec)
+	}
+	return targets, nil
+}
+
+// newServiceAccountAuditor 读取集群级RBAC对象，无权读取时令牌权限检查被跳过
+func (h *ResourceHandlerImpl) newServiceAccountAuditor(ctx context.Context) *serviceAccountAuditor {
+	auditor := &serviceAccountAuditor{
+		h:               h,
+		roles:           make(map[string][]rbacv1.Role),
+		roleBindings:    make(map[string][]rbacv1.RoleBinding),
+		serviceAccounts: make(map[string]map[string]corev1.ServiceAccount),
+		grants:          make(map[string][]string),
+	}
+	rbacClient := h.handler.Client.ClientSet().RbacV1()
+	clusterRoles, err := rbacClient.ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		auditor.warnings = append(auditor.warnings, fmt.Sprintf("failed to list cluster roles, skipping %s: %v", securityRuleID("token-with-broad-rbac"), err))
+		return auditor
+	}
+	clusterRoleBindings, err := rbacClient.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		auditor.warnings = append(auditor.warnings, fmt.Sprintf("failed to list cluster role bindings, skipping %s: %v", securityRuleID("token-with-broad-rbac"), err))
+		return auditor
+	}
+	auditor.clusterRoles = clusterRoles.Items
+	auditor.clusterRoleBindings = clusterRoleBindings.Items
+	auditor.rbacAvailable = true
+	return auditor
+}
+
+// token 返回Pod是否挂载ServiceAccount令牌以及该ServiceAccount的高风险权限
+func (a *serviceAccountAuditor) token(ctx context.Context, namespace string, spec *corev1.PodSpec) (bool, []string) {
+	name, automount := a.automount(ctx, namespace, spec)
+	if !automount || !a.rbacAvailable {
+		return automount, nil
+	}
+
+	key := namespace + "/" + name
+	if grants, ok := a.grants[key]; ok {
+		return automount, grants
+	}
+	grants := a.serviceAccountGrants(ctx, namespace, name)
+	a.grants[key] = grants
+	return automount, grants
+}
+
+// automount 返回Pod使用的ServiceAccount名称以及是否挂载其令牌
+func (a *serviceAccountAuditor) automount(ctx context.Context, namespace string, spec *corev1.PodSpec) (string, bool) {
+	name := spec.ServiceAccountName
+	if name == "" {
+		name = "default"
+	}
+
+	// Pod上的设置优先于ServiceAccount上的设置，两者都未设置时默认挂载
+	if spec.AutomountServiceAccountToken != nil {
+		return name, *spec.AutomountServiceAccountToken
+	}
+	if serviceAccount, ok := a.serviceAccount(ctx, namespace, name); ok && serviceAccount.AutomountServiceAccountToken != nil {
+		return name, *serviceAccount.AutomountServiceAccountToken
+	}
+	return name, true
+}
+
+// serviceAccount 返回命名空间中的ServiceAccount，按命名空间缓存
+func (a *serviceAccountAuditor) serviceAccount(ctx context.Context, namespace, name string) (corev1.ServiceAccount, bool) {
+	accounts, ok := a.serviceAccounts[namespace]
+	if !ok {
+		accounts = make(map[string]corev1.ServiceAccount)
+		list, err := a.h.handler.Client.ClientSet().CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			a.warnings = append(a.warnings, fmt.Sprintf("failed to list service accounts in %s: %v", namespace, err))
+		} else {
+			for _, account := range list.Items {
+				accounts[account.Name] = account
+			}
+		}
+		a.serviceAccounts[namespace] = accounts
+	}
+	account, ok := accounts[name]
+	return account, ok
+}
+
+// serviceAccountGrants 返回通过ClusterRoleBinding和所在命名空间的RoleBinding授予ServiceAccount的高风险权限
+func (a *serviceAccountAuditor) serviceAccountGrants(ctx context.Context, namespace, name string) []string {
+	var grants []string
+	describe := func(rules []rbacv1.PolicyRule, via string) {
+		for _, permission := range broadPermissions {
+			if rulesAllow(rules, permission.attrs) {
+				grants = append(grants, fmt.Sprintf("%s (%s)", permission.label, via))
+				if permission.attrs.Verb == "*" {
+					// 完全访问已包含其余所有权限
+					return
+				}
+			}
+		}
+	}
+	for _, binding := range a.clusterRoleBindings {
+		if binding.RoleRef.Kind != "ClusterRole" || !bindsServiceAccount(binding.Subjects, "", namespace, name) {
+			continue
+		}
+		rules, _ := a.clusterRoleRules(binding.RoleRef.Name)
+		describe(rules, fmt.Sprintf("cluster-wide via ClusterRoleBinding %s", binding.Name))
+	}
+
+	for _, binding := range a.namespaceRoleBindings(ctx, namespace) {
+		if !bindsServiceAccount(binding.Subjects, binding.Namespace, namespace, name) {
+			continue
+		}
+		rules, _ := a.roleBindingRules(binding)
+		describe(rules, fmt.Sprintf("in namespace %s via RoleBinding %s", namespace, binding.Name))
+	}
+	return grants
+}
+
+// clusterRoleRules 返回ClusterRole的有效规则，第二个返回值表示角色是否存在
+func (a *serviceAccountAuditor) clusterRoleRules(roleName string) ([]rbacv1.PolicyRule, bool) {
+	for i := range a.clusterRoles {
+		if a.clusterRoles[i].Name == roleName {
+			return effectiveClusterRoleRules(&a.clusterRoles[i], a.clusterRoles), true
+		}
+	}
+	return nil, false
+}
+
+// roleBindingRules 返回RoleBinding引用的Role或ClusterRole的规则，第二个返回值表示角色是否存在
+// 调用前需通过namespaceRoleBindings加载绑定所在命名空间的Role
+func (a *serviceAccountAuditor) roleBindingRules(binding rbacv1.RoleBinding) ([]rbacv1.PolicyRule, bool) {
+	switch binding.RoleRef.Kind {
+	case "ClusterRole":
+		return a.clusterRoleRules(binding.RoleRef.Name)
+	case "Role":
+		for _, role := range a.roles[binding.Namespace] {
+			if role.Name == binding.RoleRef.Name {
+				return role.Rules, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// namespaceRoleBindings 返回命名空间中的RoleBinding，同时加载该命名空间的Role，按命名空间缓存
+func (a *serviceAccountAuditor) namespaceRoleBindings(ctx context.Context, namespace string) []rbacv1.RoleBinding {
+	roleBindings, ok := a.roleBindings[namespace]
+	if ok {
+		return roleBindings
+	}
+	rbacClient := a.h.handler.Client.ClientSet().RbacV1()
+	if list, err := rbacClient.RoleBindings(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		a.warnings = append(a.warnings, fmt.Sprintf("failed to list role bindings in %s: %v", namespace, err))
+	} else {
+		roleBindings = list.Items
+	}
+	if list, err := rbacClient.Roles(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		a.warnings = append(a.warnings, fmt.Sprintf("failed to list roles in %s: %v", namespace, err))
+	} else {
+		a.roles[namespace] = list.Items
+	}
+	a.roleBindings[namespace] = roleBindings
+	return roleBindings
+}
+
+// bindsServiceAccount 判断绑定的主体是否包含指定ServiceAccount，包括所有ServiceAccount所属的内置组
+// bindingNamespace为RoleBinding所在命名空间，未指定命名空间的ServiceAccount主体属于该命名空间
+func bindsServiceAccount(subjects []rbacv1.Subject, bindingNamespace, namespace, name string) bool {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			subjectNamespace := subject.Namespace
+			if subjectNamespace == "" {
+				subjectNamespace = bindingNamespace
+			}
+			if subject.Name == name && subjectNamespace == namespace {
+				return true
+			}
+		case rbacv1.GroupKind:
+			if subject.Name == "system:serviceaccounts" || subject.Name == "system:serviceaccounts:"+namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// securityRuleID 返回规则名称对应的规则编号
+func securityRuleID(name string) string {
+	for _, rule := range securityRules {
+		if rule.name == name {
+			return rule.id
+		}
+	}
+	return name
+}
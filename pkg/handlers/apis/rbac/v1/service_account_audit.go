@@ -0,0 +1,404 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 角色规则的权限范围
+const (
+	breadthFull  = "full"
+	breadthWrite = "write"
+	breadthRead  = "read"
+	breadthNone  = "none"
+)
+
+// maxSummaryResources 规则摘要中最多列出的资源数量
+const maxSummaryResources = 20
+
+// readOnlyVerbs 只读动词
+var readOnlyVerbs = map[string]bool{"get": true, "list": true, "watch": true}
+
+// AuditServiceAccounts 以ServiceAccount为中心审计其使用者、令牌挂载、绑定的角色及权限范围、镜像拉取Secret，并给出带严重程度的发现
+func (h *ResourceHandlerImpl) AuditServiceAccounts(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	includeClean, _ := arguments["includeClean"].(bool)
+	minSeverity, _ := arguments["minSeverity"].(string)
+	if minSeverity == "" {
+		minSeverity = models.SeverityInfo
+	}
+
+	h.handler.Log.Info("Auditing service accounts",
+		"namespace", namespace,
+		"minSeverity", minSeverity,
+		"includeClean", includeClean,
+	)
+
+	switch minSeverity {
+	case models.SeverityCritical, models.SeverityWarning, models.SeverityInfo:
+	default:
+		return utils.NewErrorToolResult(fmt.Sprintf("invalid minSeverity %q, must be critical, warning or info", minSeverity)), nil
+	}
+
+	clientset := h.handler.Client.ClientSet()
+	accounts, err := clientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list service accounts", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list service accounts"), nil
+	}
+
+	report := &models.ServiceAccountAuditReport{
+		Namespace:       namespace,
+		MinSeverity:     minSeverity,
+		Scanned:         len(accounts.Items),
+		ServiceAccounts: []models.ServiceAccountAudit{},
+		RetrievedAt:     time.Now(),
+	}
+
+	auditor := h.newServiceAccountAuditor(ctx)
+
+	// 按ServiceAccount归类使用它的工作负载
+	workloads := make(map[string][]models.ServiceAccountWorkload)
+	targets, err := h.securityTargets(ctx, namespace, "")
+	workloadsListed := err == nil
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("failed to list workloads, usage is not reported: %v", err))
+	}
+	for i := range targets {
+		target := &targets[i]
+		name, mountsToken := auditor.automount(ctx, target.namespace, target.spec)
+		key := target.namespace + "/" + name
+		workloads[key] = append(workloads[key], models.ServiceAccountWorkload{
+			Kind:        target.kind,
+			Name:        target.name,
+			MountsToken: mountsToken,
+		})
+	}
+
+	secrets := make(map[string]*corev1.Secret)
+	for i := range accounts.Items {
+		account := &accounts.Items[i]
+		audit := models.ServiceAccountAudit{
+			Name:           account.Name,
+			Namespace:      account.Namespace,
+			AutomountToken: "default",
+			Workloads:      workloads[account.Namespace+"/"+account.Name],
+		}
+		automount := true
+		if account.AutomountServiceAccountToken != nil {
+			automount = *account.AutomountServiceAccountToken
+			audit.AutomountToken = fmt.Sprintf("%t", automount)
+		}
+		mountedBy := 0
+		for _, workload := range audit.Workloads {
+			if workload.MountsToken {
+				mountedBy++
+			}
+		}
+
+		var findings []models.Finding
+		if auditor.rbacAvailable {
+			audit.Bindings = auditor.serviceAccountBindings(ctx, account.Namespace, account.Name)
+			findings = append(findings, bindingFindings(account, audit.Bindings, automount, mountedBy)...)
+		}
+
+		for _, ref := range account.ImagePullSecrets {
+			check := models.ImagePullSecretCheck{Name: ref.Name}
+			key := account.Namespace + "/" + ref.Name
+			secret, cached := secrets[key]
+			if !cached {
+				secret, err = clientset.CoreV1().Secrets(account.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+				if err != nil {
+					if !apierrors.IsNotFound(err) {
+						report.Warnings = append(report.Warnings, fmt.Sprintf("failed to get secret %s: %v", key, err))
+					}
+					secret = nil
+				}
+				secrets[key] = secret
+			}
+			if secret != nil {
+				check.Exists = true
+				check.Type = string(secret.Type)
+			}
+			audit.ImagePullSecrets = append(audit.ImagePullSecrets, check)
+			switch {
+			case !check.Exists:
+				findings = append(findings, models.Finding{
+					Severity: models.SeverityWarning,
+					Check:    "missing-pull-secret",
+					Message:  fmt.Sprintf("imagePullSecret %s does not exist; pods using this service account cannot pull private images with it", ref.Name),
+				})
+			case secret.Type != corev1.SecretTypeDockerConfigJson:
+				findings = append(findings, models.Finding{
+					Severity: models.SeverityWarning,
+					Check:    "pull-secret-type",
+					Message:  fmt.Sprintf("imagePullSecret %s has type %s, expected %s", ref.Name, secret.Type, corev1.SecretTypeDockerConfigJson),
+				})
+			}
+		}
+
+		switch {
+		case account.Name == "default" && len(audit.Workloads) > 0:
+			findings = append(findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "default-sa-in-use",
+				Message:  fmt.Sprintf("%d workload(s) run as the default service account; give each workload its own service account", len(audit.Workloads)),
+				Details:  workloadNames(audit.Workloads),
+			})
+		case account.Name != "default" && len(audit.Workloads) == 0 && workloadsListed:
+			findings = append(findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "unused",
+				Message:  "no workload uses this service account",
+			})
+		}
+
+		for _, finding := range findings {
+			if models.SeverityRank(finding.Severity) > models.SeverityRank(minSeverity) {
+				continue
+			}
+			audit.Findings = append(audit.Findings, finding)
+			switch finding.Severity {
+			case models.SeverityCritical:
+				report.Summary.Critical++
+			case models.SeverityWarning:
+				report.Summary.Warning++
+			default:
+				report.Summary.Info++
+			}
+		}
+		sort.SliceStable(audit.Findings, func(i, j int) bool {
+			return models.SeverityRank(audit.Findings[i].Severity) < models.SeverityRank(audit.Findings[j].Severity)
+		})
+		if len(audit.Findings) == 0 && !includeClean {
+			continue
+		}
+		report.ServiceAccounts = append(report.ServiceAccounts, audit)
+	}
+	report.Warnings = append(report.Warnings, auditor.warnings...)
+
+	// 按最严重的发现排序，其次按命名空间和名称
+	sort.SliceStable(report.ServiceAccounts, func(i, j int) bool {
+		a, b := report.ServiceAccounts[i], report.ServiceAccounts[j]
+		if rankA, rankB := worstSeverityRank(a.Findings), worstSeverityRank(b.Findings); rankA != rankB {
+			return rankA < rankB
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	h.handler.Log.Info("Service account audit completed",
+		"scanned", report.Scanned,
+		"reported", len(report.ServiceAccounts),
+		"critical", report.Summary.Critical,
+	)
+	return utils.RenderResult(request, report), nil
+}
+
+// serviceAccountBindings 返回通过ClusterRoleBinding和所在命名空间的RoleBinding绑定到ServiceAccount的角色及其权限摘要
+func (a *serviceAccountAuditor) serviceAccountBindings(ctx context.Context, namespace, name string) []models.ServiceAccountBinding {
+	var bindings []models.ServiceAccountBinding
+	for _, binding := range a.clusterRoleBindings {
+		if binding.RoleRef.Kind != "ClusterRole" || !bindsServiceAccount(binding.Subjects, "", namespace, name) {
+			continue
+		}
+		rules, found := a.clusterRoleRules(binding.RoleRef.Name)
+		bindings = append(bindings, models.ServiceAccountBinding{
+			BindingKind: "ClusterRoleBinding",
+			BindingName: binding.Name,
+			RoleKind:    binding.RoleRef.Kind,
+			RoleName:    binding.RoleRef.Name,
+			RoleMissing: !found,
+			Summary:     summarizeRules(rules),
+		})
+	}
+	for _, binding := range a.namespaceRoleBindings(ctx, namespace) {
+		if !bindsServiceAccount(binding.Subjects, binding.Namespace, namespace, name) {
+			continue
+		}
+		rules, found := a.roleBindingRules(binding)
+		bindings = append(bindings, models.ServiceAccountBinding{
+			BindingKind:      "RoleBinding",
+			BindingName:      binding.Name,
+			BindingNamespace: binding.Namespace,
+			RoleKind:         binding.RoleRef.Kind,
+			RoleName:         binding.RoleRef.Name,
+			RoleMissing:      !found,
+			Summary:          summarizeRules(rules),
+		})
+	}
+	return bindings
+}
+
+// bindingFindings 根据绑定的权限范围和令牌挂载情况给出发现
+// 默认ServiceAccount被所有未指定serviceAccountName的Pod使用，其高风险权限比其他ServiceAccount更严重
+func bindingFindings(account *corev1.ServiceAccount, bindings []models.ServiceAccountBinding, automount bool, mountedBy int) []models.Finding {
+	isDefault := account.Name == "default"
+	var findings []models.Finding
+	for _, binding := range bindings {
+		via := fmt.Sprintf("%s %s -> %s %s", binding.BindingKind, binding.BindingName, binding.RoleKind, binding.RoleName)
+		clusterWide := binding.BindingKind == "ClusterRoleBinding"
+		switch {
+		case binding.RoleMissing:
+			findings = append(findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "missing-role",
+				Message:  fmt.Sprintf("%s references a role that does not exist", via),
+			})
+		case binding.Summary.Breadth == breadthFull || binding.RoleName == "cluster-admin":
+			severity := models.SeverityWarning
+			scope := "in namespace " + binding.BindingNamespace
+			if clusterWide {
+				scope = "cluster-wide"
+			}
+			if clusterWide || isDefault {
+				severity = models.SeverityCritical
+			}
+			message := fmt.Sprintf("has full access %s via %s", scope, via)
+			if isDefault {
+				message = fmt.Sprintf("default service account has full access %s via %s; every pod without serviceAccountName inherits it", scope, via)
+			}
+			findings = append(findings, models.Finding{Severity: severity, Check: "cluster-admin", Message: message})
+		case binding.Summary.WildcardVerbs || binding.Summary.WildcardResources:
+			severity := models.SeverityWarning
+			if isDefault && clusterWide {
+				severity = models.SeverityCritical
+			}
+			findings = append(findings, models.Finding{
+				Severity: severity,
+				Check:    "wildcard-rules",
+				Message:  fmt.Sprintf("%s grants wildcard %s", via, wildcardKinds(binding.Summary)),
+				Details:  binding.Summary.HighRisk,
+			})
+		case len(binding.Summary.HighRisk) > 0:
+			severity := models.SeverityInfo
+			switch {
+			case isDefault && automount:
+				severity = models.SeverityCritical
+			case mountedBy > 0:
+				severity = models.SeverityWarning
+			}
+			message := fmt.Sprintf("%s grants high-risk permissions", via)
+			if mountedBy > 0 {
+				message = fmt.Sprintf("%s grants high-risk permissions to the token mounted by %d workload(s)", via, mountedBy)
+			}
+			findings = append(findings, models.Finding{
+				Severity: severity,
+				Check:    "high-risk-permission",
+				Message:  message,
+				Details:  binding.Summary.HighRisk,
+			})
+		}
+	}
+	return findings
+}
+
+// summarizeRules 汇总规则的动词、资源和高风险权限
+func summarizeRules(rules []rbacv1.PolicyRule) models.RoleRuleSummary {
+	summary := models.RoleRuleSummary{Breadth: breadthNone}
+	verbs := make(map[string]bool)
+	resources := make(map[string]bool)
+	for _, rule := range rules {
+		if len(rule.NonResourceURLs) > 0 && len(rule.Resources) == 0 {
+			continue
+		}
+		for _, verb := range rule.Verbs {
+			verbs[verb] = true
+			if verb == rbacv1.VerbAll {
+				summary.WildcardVerbs = true
+			}
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				if resource == rbacv1.ResourceAll {
+					summary.WildcardResources = true
+				}
+				if group != "" {
+					resource = group + "/" + resource
+				}
+				resources[resource] = true
+			}
+		}
+	}
+	for verb := range verbs {
+		summary.Verbs = append(summary.Verbs, verb)
+	}
+	sort.Strings(summary.Verbs)
+	for resource := range resources {
+		summary.Resources = append(summary.Resources, resource)
+	}
+	sort.Strings(summary.Resources)
+	summary.ResourceCount = len(summary.Resources)
+	if len(summary.Resources) > maxSummaryResources {
+		summary.Resources = summary.Resources[:maxSummaryResources]
+	}
+
+	switch {
+	case rulesAllow(rules, broadPermissions[0].attrs):
+		summary.Breadth = breadthFull
+	case len(verbs) > 0:
+		summary.Breadth = breadthRead
+		for verb := range verbs {
+			if !readOnlyVerbs[verb] {
+				summary.Breadth = breadthWrite
+				break
+			}
+		}
+	}
+	for _, permission := range broadPermissions[1:] {
+		if rulesAllow(rules, permission.attrs) {
+			summary.HighRisk = append(summary.HighRisk, permission.label)
+		}
+	}
+	return summary
+}
+
+// wildcardKinds 描述摘要中的通配符类型
+func wildcardKinds(summary models.RoleRuleSummary) string {
+	switch {
+	case summary.WildcardVerbs && summary.WildcardResources:
+		return "verbs and resources"
+	case summary.WildcardVerbs:
+		return "verbs on " + strings.Join(summary.Resources, ", ")
+	default:
+		return "resources for verbs " + strings.Join(summary.Verbs, ", ")
+	}
+}
+
+// workloadNames 返回工作负载的Kind/name列表
+func workloadNames(workloads []models.ServiceAccountWorkload) []string {
+	names := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		names = append(names, workload.Kind+"/"+workload.Name)
+	}
+	return names
+}
+
+// worstSeverityRank 返回发现中最严重的严重程度权重，没有发现时排在最后
+func worstSeverityRank(findings []models.Finding) int {
+	worst := models.SeverityRank(models.SeverityInfo) + 1
+	for _, finding := range findings {
+		if rank := models.SeverityRank(finding.Severity); rank < worst {
+			worst = rank
+		}
+	}
+	return worst
+}
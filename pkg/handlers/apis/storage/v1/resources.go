@@ -5,10 +5,14 @@ import (
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	GET_STORAGE_STATUS = "GET_STORAGE_STATUS"
 )
 
 // ResourceHandlerImpl Storage资源处理程序实现
@@ -32,14 +36,33 @@ func NewResourceHandler(client kubernetes.Client) interfaces.ResourceHandler {
 
 // Handle 实现接口方法
 func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// 使用父类的处理方法
-	return h.baseHandler.Handle(ctx, request)
+	// 根据工具名称分派到具体的处理方法
+	switch request.Method {
+	case GET_STORAGE_STATUS:
+		return h.GetStorageStatus(ctx, request)
+	default:
+		// 其他方法使用父类的处理方法
+		return h.baseHandler.Handle(ctx, request)
+	}
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
-	// 使用父类的注册方法
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
+	// 注册父类的工具
 	h.baseHandler.Register(server)
+
+	// 注册存储健康状态工具
+	server.AddTool(mcp.NewTool(GET_STORAGE_STATUS,
+		mcp.WithDescription("获取PVC、PV和StorageClass的存储健康状态。列出PVC的阶段、请求容量与实际绑定容量、StorageClass、卷模式以及挂载它的Pod，Pending的PVC附带供给失败事件；列出PV的回收策略以及是否处于Released但未回收的状态；按StorageClass汇总用量。同时关联FailedAttachVolume/FailedMount事件，检测因卷挂载失败而卡在ContainerCreating的Pod。返回包含findings问题列表的JSON。"),
+		mcp.WithString("namespace",
+			mcp.Description("要检查的命名空间。为空时检查所有命名空间，此时也会列出集群中的全部PV。"),
+		),
+		mcp.WithString("storageClass",
+			mcp.Description("只包含使用该StorageClass的PVC和PV（可选）。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetStorageStatus)
 }
 
 // GetScope 实现ToolHandler接口
@@ -0,0 +1,453 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// StorageClass 中标记默认类的注解
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// 每个PVC最多保留的事件数量
+	maxPVCEvents = 5
+)
+
+// volumeEventReasons 与存储供给、挂载相关的事件原因
+var volumeEventReasons = map[string]bool{
+	"ProvisioningFailed":     true,
+	"FailedBinding":          true,
+	"ExternalProvisioning":   true,
+	"WaitForFirstConsumer":   true,
+	"FailedAttachVolume":     true,
+	"FailedMount":            true,
+	"FailedMapVolume":        true,
+	"VolumeResizeFailed":     true,
+	"FileSystemResizeFailed": true,
+}
+
+// GetStorageStatus 汇总PVC、PV和StorageClass的状态，并关联供给失败和卷挂载失败事件
+func (h *ResourceHandlerImpl) GetStorageStatus(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	storageClass, _ := arguments["storageClass"].(string)
+
+	h.handler.Log.Info("Getting storage status", "namespace", namespace, "storageClass", storageClass)
+
+	clientSet := h.handler.Client.ClientSet()
+	coreClient := clientSet.CoreV1()
+
+	pvcs, err := coreClient.PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list persistent volume claims", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list persistent volume claims"), nil
+	}
+	pvs, err := coreClient.PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list persistent volumes", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list persistent volumes"), nil
+	}
+
+	response := &models.StorageStatusResponse{
+		Namespace:      namespace,
+		StorageClass:   storageClass,
+		PVCs:           []models.PVCStatus{},
+		PVs:            []models.PVStatus{},
+		StorageClasses: []models.StorageClassUsage{},
+		Findings:       []models.Finding{},
+		RetrievedAt:    time.Now(),
+	}
+
+	// Pod和事件只用于关联分析，获取失败时不影响主体结果
+	var pods []corev1.Pod
+	if podList, err := coreClient.Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		h.handler.Log.Warn("Failed to list pods", "namespace", namespace, "error", err)
+	} else {
+		pods = podList.Items
+	}
+	eventsByObject := map[string][]models.DiagnosisEvent{}
+	if eventList, err := coreClient.Events(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		h.handler.Log.Warn("Failed to list events", "namespace", namespace, "error", err)
+	} else {
+		eventsByObject = volumeEvents(eventList.Items)
+	}
+
+	mountedBy := map[string][]string{}
+	for _, pod := range pods {
+		for _, claim := range podClaimNames(&pod) {
+			key := pod.Namespace + "/" + claim
+			mountedBy[key] = append(mountedBy[key], pod.Name)
+		}
+	}
+
+	// --- PVC ---
+	usage := map[string]*classTotals{}
+	claimsInScope := map[string]bool{}
+	for _, pvc := range pvcs.Items {
+		className := pvcStorageClass(&pvc)
+		if storageClass != "" && className != storageClass {
+			continue
+		}
+		key := pvc.Namespace + "/" + pvc.Name
+		claimsInScope[key] = true
+
+		status := models.PVCStatus{
+			Name:         pvc.Name,
+			Namespace:    pvc.Namespace,
+			Phase:        string(pvc.Status.Phase),
+			StorageClass: className,
+			VolumeName:   pvc.Spec.VolumeName,
+			MountedBy:    mountedBy[key],
+			Events:       lastEvents(eventsByObject["PersistentVolumeClaim/"+key], maxPVCEvents),
+		}
+		if pvc.Spec.VolumeMode != nil {
+			status.VolumeMode = string(*pvc.Spec.VolumeMode)
+		}
+		for _, mode := range pvc.Spec.AccessModes {
+			status.AccessModes = append(status.AccessModes, string(mode))
+		}
+
+		totals := usage[className]
+		if totals == nil {
+			totals = &classTotals{}
+			usage[className] = totals
+		}
+		totals.pvcCount++
+		if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			status.Requested = requested.String()
+			totals.requested.Add(requested)
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			status.Capacity = capacity.String()
+			totals.bound.Add(capacity)
+		}
+
+		response.PVCs = append(response.PVCs, status)
+		response.Findings = append(response.Findings, pvcFindings(&pvc, &status)...)
+	}
+
+	// --- PV ---
+	for _, pv := range pvs.Items {
+		if storageClass != "" && pv.Spec.StorageClassName != storageClass {
+			continue
+		}
+		claimRef := ""
+		if pv.Spec.ClaimRef != nil {
+			claimRef = pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+		}
+		// 指定命名空间时只包含绑定到该命名空间PVC的PV
+		if namespace != "" && (pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != namespace) {
+			continue
+		}
+
+		status := models.PVStatus{
+			Name:          pv.Name,
+			Phase:         string(pv.Status.Phase),
+			StorageClass:  pv.Spec.StorageClassName,
+			ReclaimPolicy: string(pv.Spec.PersistentVolumeReclaimPolicy),
+			ClaimRef:      claimRef,
+			Reason:        pv.Status.Reason,
+		}
+		if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+			status.Capacity = capacity.String()
+		}
+
+		totals := usage[pv.Spec.StorageClassName]
+		if totals == nil {
+			totals = &classTotals{}
+			usage[pv.Spec.StorageClassName] = totals
+		}
+		totals.pvCount++
+
+		switch pv.Status.Phase {
+		case corev1.VolumeReleased:
+			status.ReleasedNotReclaimed = true
+			response.Findings = append(response.Findings, models.Finding{
+				Severity: models.SeverityWarning,
+				Check:    "pvReleased",
+				Message: fmt.Sprintf("PV %s is Released from claim %s with reclaim policy %s and must be reclaimed manually before it can be bound again",
+					pv.Name, claimRef, pv.Spec.PersistentVolumeReclaimPolicy),
+			})
+		case corev1.VolumeFailed:
+			response.Findings = append(response.Findings, models.Finding{
+				Severity: models.SeverityCritical,
+				Check:    "pvFailed",
+				Message:  fmt.Sprintf("PV %s failed automatic reclamation: %s", pv.Name, pv.Status.Message),
+			})
+		}
+
+		response.PVs = append(response.PVs, status)
+	}
+
+	// --- StorageClass ---
+	classes, err := clientSet.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list storage classes", "error", err)
+	}
+	response.StorageClasses = storageClassUsages(classes, usage, storageClass)
+
+	// --- 卷挂载失败 ---
+	response.Findings = append(response.Findings, volumeAttachFindings(pods, eventsByObject, claimsInScope, storageClass != "")...)
+
+	sort.SliceStable(response.Findings, func(i, j int) bool {
+		return models.SeverityRank(response.Findings[i].Severity) < models.SeverityRank(response.Findings[j].Severity)
+	})
+
+	h.handler.Log.Info("Storage status collected",
+		"pvcs", len(response.PVCs),
+		"pvs", len(response.PVs),
+		"findings", len(response.Findings),
+	)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// classTotals 按StorageClass累计的PVC和PV用量
+type classTotals struct {
+	pvcCount  int
+	pvCount   int
+	requested resource.Quantity
+	bound     resource.Quantity
+}
+
+// pvcStorageClass 返回PVC使用的StorageClass名称，兼容旧版注解
+func pvcStorageClass(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName != nil {
+		return *pvc.Spec.StorageClassName
+	}
+	return pvc.Annotations[corev1.BetaStorageClassAnnotation]
+}
+
+// podClaimNames 返回Pod挂载的PVC名称，包括通用临时卷生成的PVC
+func podClaimNames(pod *corev1.Pod) []string {
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		case volume.Ephemeral != nil:
+			claims = append(claims, pod.Name+"-"+volume.Name)
+		}
+	}
+	return claims
+}
+
+// volumeEvents 按 Kind/命名空间/名称 索引与存储相关的事件
+func volumeEvents(events []corev1.Event) map[string][]models.DiagnosisEvent {
+	result := map[string][]models.DiagnosisEvent{}
+	for _, event := range events {
+		if !volumeEventReasons[event.Reason] {
+			continue
+		}
+		eventTime := utils.EventLastSeen(event)
+		key := fmt.Sprintf("%s/%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name)
+		result[key] = append(result[key], models.DiagnosisEvent{
+			Time:    eventTime,
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+	for key := range result {
+		sort.Slice(result[key], func(i, j int) bool {
+			return result[key][i].Time.Before(result[key][j].Time)
+		})
+	}
+	return result
+}
+
+// lastEvents 返回最近的n条事件
+func lastEvents(events []models.DiagnosisEvent, n int) []models.DiagnosisEvent {
+	if len(events) > n {
+		return events[len(events)-n:]
+	}
+	return events
+}
+
+// pvcFindings 根据PVC的阶段和关联事件生成问题列表
+func pvcFindings(pvc *corev1.PersistentVolumeClaim, status *models.PVCStatus) []models.Finding {
+	var findings []models.Finding
+	key := pvc.Namespace + "/" + pvc.Name
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimPending:
+		finding := models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "pvcPending",
+			Message:  fmt.Sprintf("PVC %s is Pending", key),
+		}
+		if len(status.Events) > 0 {
+			latest := status.Events[len(status.Events)-1]
+			finding.Message = fmt.Sprintf("PVC %s is Pending: %s: %s", key, latest.Reason, latest.Message)
+			if latest.Reason == "WaitForFirstConsumer" && len(status.MountedBy) == 0 {
+				// 延迟绑定的PVC在没有Pod使用前保持Pending属于正常现象
+				finding.Severity = models.SeverityInfo
+			}
+		} else if status.StorageClass == "" {
+			finding.Message = fmt.Sprintf("PVC %s is Pending and has no storage class, it can only bind to a matching pre-provisioned PV", key)
+		}
+		findings = append(findings, finding)
+	case corev1.ClaimLost:
+		findings = append(findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "pvcLost",
+			Message:  fmt.Sprintf("PVC %s lost its bound volume %s, the data is no longer reachable", key, pvc.Spec.VolumeName),
+		})
+	case corev1.ClaimBound:
+		if len(status.MountedBy) == 0 {
+			findings = append(findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "pvcUnused",
+				Message:  fmt.Sprintf("PVC %s is Bound but not mounted by any pod", key),
+			})
+		}
+	}
+
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue && condition.Type != corev1.PersistentVolumeClaimResizing {
+			findings = append(findings, models.Finding{
+				Severity: models.SeverityWarning,
+				Check:    "pvcCondition",
+				Message:  fmt.Sprintf("PVC %s has condition %s: %s", key, condition.Type, condition.Message),
+			})
+		}
+	}
+	return findings
+}
+
+// volumeAttachFindings 检测因卷挂载或挂接失败而卡在ContainerCreating的Pod
+func volumeAttachFindings(
+	pods []corev1.Pod,
+	eventsByObject map[string][]models.DiagnosisEvent,
+	claimsInScope map[string]bool,
+	filtered bool,
+) []models.Finding {
+	var findings []models.Finding
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending || !isContainerCreating(&pod) {
+			continue
+		}
+
+		claims := podClaimNames(&pod)
+		if filtered {
+			inScope := false
+			for _, claim := range claims {
+				if claimsInScope[pod.Namespace+"/"+claim] {
+					inScope = true
+					break
+				}
+			}
+			if !inScope {
+				continue
+			}
+		}
+
+		var failures []models.DiagnosisEvent
+		for _, event := range eventsByObject[fmt.Sprintf("Pod/%s/%s", pod.Namespace, pod.Name)] {
+			if event.Reason == "FailedAttachVolume" || event.Reason == "FailedMount" || event.Reason == "FailedMapVolume" {
+				failures = append(failures, event)
+			}
+		}
+		if len(failures) == 0 {
+			continue
+		}
+
+		latest := failures[len(failures)-1]
+		details := make([]string, 0, len(claims))
+		for _, claim := range claims {
+			details = append(details, "pvc "+claim)
+		}
+		findings = append(findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "volumeAttach",
+			Message: fmt.Sprintf("pod %s/%s is stuck in ContainerCreating: %s (x%d): %s",
+				pod.Namespace, pod.Name, latest.Reason, latest.Count, latest.Message),
+			Details: details,
+		})
+	}
+	return findings
+}
+
+// isContainerCreating 判断Pod是否有容器处于ContainerCreating等待状态
+func isContainerCreating(pod *corev1.Pod) bool {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	if len(statuses) == 0 {
+		// 容器状态尚未上报时，已调度的Pod同样处于创建阶段
+		return pod.Spec.NodeName != ""
+	}
+	for _, status := range statuses {
+		if status.State.Waiting != nil &&
+			(status.State.Waiting.Reason == "ContainerCreating" || status.State.Waiting.Reason == "PodInitializing") {
+			return true
+		}
+	}
+	return false
+}
+
+// storageClassUsages 汇总每个StorageClass的PVC和PV用量
+func storageClassUsages(
+	classes *storagev1.StorageClassList,
+	usage map[string]*classTotals,
+	filter string,
+) []models.StorageClassUsage {
+	result := []models.StorageClassUsage{}
+	seen := map[string]bool{}
+
+	appendUsage := func(item models.StorageClassUsage) {
+		if totals := usage[item.Name]; totals != nil {
+			item.PVCCount = totals.pvcCount
+			item.PVCount = totals.pvCount
+			item.Requested = totals.requested.String()
+			item.Bound = totals.bound.String()
+		} else {
+			item.Requested = "0"
+			item.Bound = "0"
+		}
+		seen[item.Name] = true
+		result = append(result, item)
+	}
+
+	if classes != nil {
+		for _, class := range classes.Items {
+			if filter != "" && class.Name != filter {
+				continue
+			}
+			item := models.StorageClassUsage{
+				Name:        class.Name,
+				Provisioner: class.Provisioner,
+				Default:     class.Annotations[defaultStorageClassAnnotation] == "true",
+			}
+			if class.ReclaimPolicy != nil {
+				item.ReclaimPolicy = string(*class.ReclaimPolicy)
+			}
+			appendUsage(item)
+		}
+	}
+
+	// PVC或PV引用了不存在的StorageClass（包括空类名）时也需要汇总
+	var missing []string
+	for name := range usage {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		appendUsage(models.StorageClassUsage{Name: name})
+	}
+	return result
+}
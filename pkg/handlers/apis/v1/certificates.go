@@ -0,0 +1,308 @@
+package v1
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认的证书到期提醒天数
+	defaultCertificateWarnDays = 30
+	// cert-manager的API版本
+	certManagerGroupVersion = "cert-manager.io/v1"
+)
+
+// certificateGVR cert-manager Certificate资源
+var certificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// CheckCertificates 解析kubernetes.io/tls类型Secret中的证书，报告到期时间、引用它的Ingress主机名是否被覆盖以及cert-manager Certificate的状态
+func (h *ResourceHandlerImpl) CheckCertificates(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	expiringOnly, _ := arguments["expiringOnly"].(bool)
+	warnDays := defaultCertificateWarnDays
+	if value, ok := arguments["warnDays"].(float64); ok && value >= 0 {
+		warnDays = int(value)
+	}
+
+	h.handler.Log.Info("Checking certificates", "namespace", namespace, "warnDays", warnDays, "expiringOnly", expiringOnly)
+
+	secrets, err := h.handler.Client.ClientSet().CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String(),
+	})
+	if err != nil {
+		h.handler.Log.Error("Failed to list TLS secrets", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list TLS secrets"), nil
+	}
+
+	now := time.Now()
+	report := models.CertificateReport{
+		Namespace:      namespace,
+		WarnDays:       warnDays,
+		CountsByStatus: make(map[string]int),
+		Items:          []models.CertificateExpiry{},
+		RetrievedAt:    now,
+	}
+
+	usages := h.ingressTLSUsages(ctx, namespace, &report)
+	certificates := h.certManagerCertificates(ctx, namespace, &report)
+
+	var items []models.CertificateExpiry
+	leaves := make(map[string]*x509.Certificate)
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		key := secret.Namespace + "/" + secret.Name
+		// 只读取tls.crt，私钥不会被解析或返回
+		item, leaf := summarizeTLSSecret(secret.Namespace, secret.Name, secret.Data[corev1.TLSCertKey], now, warnDays)
+		leaves[key] = leaf
+		items = append(items, item)
+	}
+
+	// cert-manager Certificate引用的Secret尚未创建时也报告
+	for key, status := range certificates {
+		if _, ok := leaves[key]; ok {
+			continue
+		}
+		secretNamespace, secretName, _ := strings.Cut(key, "/")
+		items = append(items, models.CertificateExpiry{
+			Namespace:   secretNamespace,
+			SecretName:  secretName,
+			Status:      models.CertificateMissing,
+			CertManager: status,
+			Problems:    []string{fmt.Sprintf("Secret %s referenced by Certificate %s does not exist", key, status.Name)},
+		})
+	}
+
+	for i := range items {
+		item := &items[i]
+		key := item.Namespace + "/" + item.SecretName
+		if status, ok := certificates[key]; ok && item.CertManager == nil {
+			item.CertManager = status
+			if status.Ready != string(metav1.ConditionTrue) {
+				item.Problems = append(item.Problems, fmt.Sprintf("Certificate %s is not Ready: %s", status.Name, status.Reason))
+			}
+		}
+		for _, usage := range usages[key] {
+			if leaf := leaves[key]; leaf != nil {
+				for _, host := range usage.Hosts {
+					if leaf.VerifyHostname(host) != nil {
+						usage.UnmatchedHosts = append(usage.UnmatchedHosts, host)
+					}
+				}
+				if len(usage.UnmatchedHosts) > 0 {
+					item.Problems = append(item.Problems, fmt.Sprintf("certificate does not cover hosts %s of Ingress %s", strings.Join(usage.UnmatchedHosts, ", "), usage.Ingress))
+				}
+			}
+			item.Ingresses = append(item.Ingresses, usage)
+		}
+
+		report.Scanned++
+		report.CountsByStatus[item.Status]++
+		if expiringOnly && item.Status == models.CertificateValid {
+			continue
+		}
+		report.Items = append(report.Items, *item)
+	}
+
+	// 按到期时间从近到远排序，无法确定到期时间的排在最后
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i].DaysUntilExpiry, report.Items[j].DaysUntilExpiry
+		switch {
+		case a != nil && b != nil && *a != *b:
+			return *a < *b
+		case a != nil && b != nil:
+			return report.Items[i].Certificate.NotAfter.Before(report.Items[j].Certificate.NotAfter)
+		case a != nil || b != nil:
+			return a != nil
+		}
+		if report.Items[i].Namespace != report.Items[j].Namespace {
+			return report.Items[i].Namespace < report.Items[j].Namespace
+		}
+		return report.Items[i].SecretName < report.Items[j].SecretName
+	})
+
+	h.handler.Log.Info("Certificate check completed", "scanned", report.Scanned, "expired", report.CountsByStatus[models.CertificateExpired],
+		"expiring", report.CountsByStatus[models.CertificateExpiring])
+	return utils.RenderResult(request, report), nil
+}
+
+// ingressTLSUsages 返回按"namespace/secret"索引的引用TLS Secret的Ingress及其主机名
+func (h *ResourceHandlerImpl) ingressTLSUsages(
+	ctx context.Context,
+	namespace string,
+	report *models.CertificateReport,
+) map[string][]models.CertificateIngressUsage {
+	usages := make(map[string][]models.CertificateIngressUsage)
+	ingresses, err := h.handler.Client.ClientSet().NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list ingresses", "namespace", namespace, "error", err)
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to list ingresses: %v", err))
+		return usages
+	}
+	for _, ingress := range ingresses.Items {
+		for _, tls := range ingress.Spec.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+			hosts := tls.Hosts
+			// TLS条目未列出主机名时按规则中的主机名检查
+			if len(hosts) == 0 {
+				for _, rule := range ingress.Spec.Rules {
+					if rule.Host != "" {
+						hosts = append(hosts, rule.Host)
+					}
+				}
+			}
+			key := ingress.Namespace + "/" + tls.SecretName
+			usages[key] = append(usages[key], models.CertificateIngressUsage{Ingress: ingress.Name, Hosts: hosts})
+		}
+	}
+	return usages
+}
+
+// certManagerCertificates 集群安装了cert-manager时返回按"namespace/secretName"索引的Certificate状态
+func (h *ResourceHandlerImpl) certManagerCertificates(
+	ctx context.Context,
+	namespace string,
+	report *models.CertificateReport,
+) map[string]*models.CertManagerStatus {
+	certificates := make(map[string]*models.CertManagerStatus)
+	resources, err := h.handler.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(certManagerGroupVersion)
+	if err != nil {
+		return certificates
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == certificateGVR.Resource {
+			report.CertManager = true
+		}
+	}
+	if !report.CertManager {
+		return certificates
+	}
+
+	list, err := h.handler.Client.GetDynamicClient().Resource(certificateGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list cert-manager certificates", "namespace", namespace, "error", err)
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to list cert-manager certificates: %v", err))
+		return certificates
+	}
+	for _, certificate := range list.Items {
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		if secretName == "" {
+			continue
+		}
+		status := &models.CertManagerStatus{Name: certificate.GetName(), Ready: string(metav1.ConditionUnknown)}
+		issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+		issuerName, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+		if issuerName != "" {
+			if issuerKind == "" {
+				issuerKind = "Issuer"
+			}
+			status.Issuer = issuerKind + "/" + issuerName
+		}
+		conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+		for _, entry := range conditions {
+			condition, ok := entry.(map[string]interface{})
+			if !ok || condition["type"] != "Ready" {
+				continue
+			}
+			status.Ready, _ = condition["status"].(string)
+			status.Reason, _ = condition["reason"].(string)
+			status.Message, _ = condition["message"].(string)
+		}
+		status.NotAfter = nestedTime(certificate.Object, "status", "notAfter")
+		status.RenewalTime = nestedTime(certificate.Object, "status", "renewalTime")
+		certificates[certificate.GetNamespace()+"/"+secretName] = status
+	}
+	return certificates
+}
+
+// summarizeTLSSecret 解析tls.crt中的证书链并根据叶子证书计算到期状态
+func summarizeTLSSecret(namespace, name string, data []byte, now time.Time, warnDays int) (models.CertificateExpiry, *x509.Certificate) {
+	item := models.CertificateExpiry{Namespace: namespace, SecretName: name}
+
+	var chain []*x509.Certificate
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			item.Status = models.CertificateInvalid
+			item.Problems = append(item.Problems, fmt.Sprintf("failed to parse certificate %d in %s: %v", len(chain)+1, corev1.TLSCertKey, err))
+			return item, nil
+		}
+		chain = append(chain, certificate)
+	}
+	if len(chain) == 0 {
+		item.Status = models.CertificateInvalid
+		item.Problems = append(item.Problems, fmt.Sprintf("%s contains no PEM encoded certificate", corev1.TLSCertKey))
+		return item, nil
+	}
+
+	leaf := chain[0]
+	item.Certificate = &models.X509Summary{
+		Subject:      leaf.Subject.String(),
+		Issuer:       leaf.Issuer.String(),
+		DNSNames:     leaf.DNSNames,
+		SerialNumber: leaf.SerialNumber.String(),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		SelfSigned:   leaf.Subject.String() == leaf.Issuer.String(),
+		IsCA:         leaf.IsCA,
+		ChainLength:  len(chain),
+	}
+	for _, ip := range leaf.IPAddresses {
+		item.Certificate.IPAddresses = append(item.Certificate.IPAddresses, ip.String())
+	}
+
+	days := int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24))
+	item.DaysUntilExpiry = &days
+	switch {
+	case !now.Before(leaf.NotAfter):
+		item.Status = models.CertificateExpired
+		item.Problems = append(item.Problems, fmt.Sprintf("certificate expired at %s", leaf.NotAfter.Format(time.RFC3339)))
+	case days < warnDays:
+		item.Status = models.CertificateExpiring
+	default:
+		item.Status = models.CertificateValid
+	}
+	if now.Before(leaf.NotBefore) {
+		item.Problems = append(item.Problems, fmt.Sprintf("certificate is not valid before %s", leaf.NotBefore.Format(time.RFC3339)))
+	}
+	return item, leaf
+}
+
+// nestedTime 读取RFC3339格式的时间字段
+func nestedTime(obj map[string]interface{}, fields ...string) *time.Time {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// ConfigMap单个值默认返回的最大字节数，超过部分会被截断
+const defaultConfigMapMaxValueBytes = 4096
+
+// GetSecretKeys 列出Secret中的键名、值长度和可选的指纹，默认不返回值
+func (h *ResourceHandlerImpl) GetSecretKeys(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	fingerprint, _ := arguments["fingerprint"].(bool)
+	revealValues, _ := arguments["revealValues"].(bool)
+
+	// 防止泄露：只有配置允许且调用方明确要求时才返回值
+	allowed := base.GetOptions().AllowSecretValues
+	h.handler.Log.Info("Getting secret keys",
+		"name", name,
+		"namespace", namespace,
+		"fingerprint", fingerprint,
+		"revealValues", revealValues,
+		"allowSecretValues", allowed,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("Secret name is required"), nil
+	}
+
+	secret, err := h.handler.Client.ClientSet().CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get secret", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get secret %s", name)), nil
+	}
+
+	reveal := revealValues && allowed
+	response := models.SecretKeysResponse{
+		Name:           secret.Name,
+		Namespace:      secret.Namespace,
+		Type:           string(secret.Type),
+		Immutable:      secret.Immutable != nil && *secret.Immutable,
+		Keys:           make([]models.SecretKeyInfo, 0, len(secret.Data)),
+		ValuesRevealed: reveal,
+		RetrievedAt:    time.Now(),
+	}
+	if revealValues && !allowed {
+		response.Note = "secret values are not returned because the server was started without --allow-secret-values"
+	}
+
+	for key, value := range secret.Data {
+		info := models.SecretKeyInfo{
+			Key:  key,
+			Size: len(value),
+		}
+		if fingerprint {
+			sum := sha256.Sum256(value)
+			info.SHA256 = hex.EncodeToString(sum[:])
+		}
+		if reveal {
+			info.Value = string(value)
+		}
+		response.Keys = append(response.Keys, info)
+	}
+	sort.Slice(response.Keys, func(i, j int) bool {
+		return response.Keys[i].Key < response.Keys[j].Key
+	})
+	response.Count = len(response.Keys)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// GetConfigMap 获取ConfigMap内容，过长的值会被截断，二进制数据只返回大小
+func (h *ResourceHandlerImpl) GetConfigMap(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	maxValueBytes := defaultConfigMapMaxValueBytes
+	if v, ok := arguments["maxValueBytes"].(float64); ok && v > 0 {
+		maxValueBytes = int(v)
+	}
+
+	h.handler.Log.Info("Getting configmap",
+		"name", name,
+		"namespace", namespace,
+		"maxValueBytes", maxValueBytes,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("ConfigMap name is required"), nil
+	}
+
+	configMap, err := h.handler.Client.ClientSet().CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get configmap", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get configmap %s", name)), nil
+	}
+
+	response := models.ConfigMapResponse{
+		Name:          configMap.Name,
+		Namespace:     configMap.Namespace,
+		Immutable:     configMap.Immutable != nil && *configMap.Immutable,
+		Data:          make(map[string]string, len(configMap.Data)),
+		MaxValueBytes: maxValueBytes,
+		RetrievedAt:   time.Now(),
+	}
+
+	for key, value := range configMap.Data {
+		if len(value) > maxValueBytes {
+			value = fmt.Sprintf("%s\n... (truncated, %d bytes total)", value[:maxValueBytes], len(value))
+			response.TruncatedKeys = append(response.TruncatedKeys, key)
+		}
+		response.Data[key] = value
+	}
+	sort.Strings(response.TruncatedKeys)
+
+	for key, value := range configMap.BinaryData {
+		response.BinaryData = append(response.BinaryData, models.ConfigMapBinaryInfo{
+			Key:  key,
+			Size: len(value),
+		})
+	}
+	sort.Slice(response.BinaryData, func(i, j int) bool {
+		return response.BinaryData[i].Key < response.BinaryData[j].Key
+	})
+
+	return utils.RenderResult(request, response), nil
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func TestGetSecretKeys(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: testutil.DefaultNamespace},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("hunter2"), "user": []byte("admin")},
+	}
+	tests := []struct {
+		name         string
+		allowValues  bool
+		arguments    map[string]any
+		wantValue    string
+		wantSHA256   bool
+		wantRevealed bool
+		wantNote     bool
+	}{
+		{name: "keys only"},
+		{name: "fingerprint", arguments: map[string]any{"fingerprint": true}, wantSHA256: true},
+		{name: "reveal without permission", arguments: map[string]any{"revealValues": true}, wantNote: true},
+		{name: "reveal with permission", allowValues: true, arguments: map[string]any{"revealValues": true}, wantValue: "hunter2", wantRevealed: true},
+		{name: "permission without request", allowValues: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := base.GetOptions()
+			base.UpdateOptions(func(o *base.Options) { o.AllowSecretValues = tt.allowValues })
+			t.Cleanup(func() { base.SetOptions(previous) })
+
+			arguments := map[string]any{"name": "db"}
+			for key, value := range tt.arguments {
+				arguments[key] = value
+			}
+			handler := NewResourceHandler(testutil.NewFakeClient(secret))
+			result, err := handler.Handle(context.Background(), testutil.NewToolRequest(GET_SECRET_KEYS, arguments))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var response models.SecretKeysResponse
+			if err := testutil.DecodeResult(result, &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if response.Count != 2 || response.Keys[0].Key != "password" || response.Keys[0].Size != 7 {
+				t.Fatalf("keys = %+v, want password (7 bytes) and user", response.Keys)
+			}
+			password := response.Keys[0]
+			if password.Value != tt.wantValue || response.ValuesRevealed != tt.wantRevealed {
+				t.Fatalf("value = %q, revealed = %v; want %q, %v", password.Value, response.ValuesRevealed, tt.wantValue, tt.wantRevealed)
+			}
+			if (password.SHA256 != "") != tt.wantSHA256 {
+				t.Fatalf("sha256 = %q, want present = %v", password.SHA256, tt.wantSHA256)
+			}
+			if (response.Note != "") != tt.wantNote {
+				t.Fatalf("note = %q, want present = %v", response.Note, tt.wantNote)
+			}
+		})
+	}
+}
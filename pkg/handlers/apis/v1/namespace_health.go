@@ -0,0 +1,501 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 命名空间健康摘要的参数默认值和上限
+const (
+	defaultHealthCheckTimeoutSeconds = 5
+	maxHealthCheckTimeoutSeconds     = 30
+	// healthEventWindow 只统计该时间内发生过的Warning事件
+	healthEventWindow = time.Hour
+	// maxHealthEventObjects 每组Warning事件中列出的对象数量
+	maxHealthEventObjects = 5
+)
+
+// namespaceHealthCheck 一项命名空间健康检查
+type namespaceHealthCheck struct {
+	name string
+	run  func(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error)
+}
+
+// NamespaceHealthSummary 并发执行一组开销较小的检查，汇总命名空间中当前存在的问题
+func (h *NamespaceHandlerImpl) NamespaceHealthSummary(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	timeoutSeconds := defaultHealthCheckTimeoutSeconds
+	if value, ok := arguments["checkTimeoutSeconds"].(float64); ok && value > 0 {
+		timeoutSeconds = min(int(value), maxHealthCheckTimeoutSeconds)
+	}
+
+	h.Log.Info("Summarizing namespace health", "namespace", namespace, "checkTimeoutSeconds", timeoutSeconds)
+
+	if namespace == "" {
+		return utils.NewErrorToolResult("missing required parameter: namespace"), nil
+	}
+	if _, err := h.cachedNamespace(ctx, namespace); err != nil {
+		h.Log.Error("Failed to get namespace", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get namespace %s", namespace)), nil
+	}
+
+	checks := []namespaceHealthCheck{
+		{"pods", h.checkPods},
+		{"workloads", h.checkWorkloadReplicas},
+		{"events", h.checkWarningEvents},
+		{"jobs", h.checkFailedJobs},
+		{"pvcs", h.checkPendingClaims},
+		{"hpas", h.checkHPAsAtMax},
+		{"services", h.checkServiceEndpoints},
+	}
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	results := make([]models.NamespaceHealthCheck, len(checks))
+	findings := make([][]models.NamespaceHealthFinding, len(checks))
+
+	// 检查之间互不影响，单项失败或超时只记录在该检查的状态中
+	var group errgroup.Group
+	for i, check := range checks {
+		group.Go(func() error {
+			start := time.Now()
+			found, err := runHealthCheck(ctx, timeout, namespace, check)
+			result := models.NamespaceHealthCheck{
+				Name:     check.name,
+				Status:   models.HealthCheckOK,
+				Findings: len(found),
+				Duration: time.Since(start).Round(time.Millisecond).String(),
+			}
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				result.Status = models.HealthCheckTimeout
+				result.Error = fmt.Sprintf("check did not finish within %s", timeout)
+			case err != nil:
+				result.Status = models.HealthCheckFailed
+				result.Error = err.Error()
+			}
+			results[i] = result
+			findings[i] = found
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	summary := &models.NamespaceHealthSummary{
+		Namespace:   namespace,
+		Findings:    []models.NamespaceHealthFinding{},
+		Checks:      results,
+		RetrievedAt: time.Now(),
+	}
+	var incomplete []string
+	for i, result := range results {
+		if result.Status != models.HealthCheckOK {
+			h.Log.Warn("Namespace health check incomplete", "namespace", namespace, "check", result.Name, "status", result.Status, "error", result.Error)
+			incomplete = append(incomplete, fmt.Sprintf("%s (%s)", result.Name, result.Status))
+			continue
+		}
+		summary.Findings = append(summary.Findings, findings[i]...)
+	}
+	if len(incomplete) > 0 {
+		summary.Notes = append(summary.Notes, fmt.Sprintf("results are partial, these checks did not complete: %s", strings.Join(incomplete, ", ")))
+	}
+
+	sort.SliceStable(summary.Findings, func(i, j int) bool {
+		a, b := summary.Findings[i], summary.Findings[j]
+		if rankA, rankB := models.SeverityRank(a.Severity), models.SeverityRank(b.Severity); rankA != rankB {
+			return rankA < rankB
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		return a.Object.Name < b.Object.Name
+	})
+	for _, finding := range summary.Findings {
+		switch finding.Severity {
+		case models.SeverityCritical:
+			summary.Summary.Critical++
+		case models.SeverityWarning:
+			summary.Summary.Warning++
+		default:
+			summary.Summary.Info++
+		}
+	}
+	switch {
+	case summary.Summary.Critical > 0 || summary.Summary.Warning > 0:
+		summary.Verdict = models.VerdictDegraded
+	case len(incomplete) > 0:
+		summary.Verdict = models.VerdictUnknown
+	default:
+		summary.Verdict = models.VerdictHealthy
+	}
+
+	h.Log.Info("Namespace health summary completed",
+		"namespace", namespace,
+		"verdict", summary.Verdict,
+		"findings", len(summary.Findings),
+	)
+	return utils.RenderResult(request, summary), nil
+}
+
+// runHealthCheck 在独立的超时内执行检查，超时后立即返回，不等待仍在进行的请求
+func runHealthCheck(
+	ctx context.Context,
+	timeout time.Duration,
+	namespace string,
+	check namespaceHealthCheck,
+) ([]models.NamespaceHealthFinding, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		findings []models.NamespaceHealthFinding
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		found, err := check.run(checkCtx, namespace)
+		done <- outcome{found, err}
+	}()
+	select {
+	case result := <-done:
+		return result.findings, result.err
+	case <-checkCtx.Done():
+		return nil, checkCtx.Err()
+	}
+}
+
+// checkPods 检查未运行或未就绪的Pod
+func (h *NamespaceHandlerImpl) checkPods(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	pods, err := h.Client.ClientSet().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var findings []models.NamespaceHealthFinding
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		object := models.NamespaceHealthObjectRef{Kind: "Pod", Name: pod.Name, Namespace: namespace}
+		next := suggestTool("DIAGNOSE_POD", namespace, pod.Name, map[string]any{"name": pod.Name, "namespace": namespace})
+
+		// 容器处于等待状态的原因比Pod阶段更能说明问题
+		if reason := podWaitingReason(pod); reason != "" {
+			severity := models.SeverityWarning
+			switch reason {
+			case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError", "InvalidImageName":
+				severity = models.SeverityCritical
+			}
+			findings = append(findings, models.NamespaceHealthFinding{
+				Severity: severity,
+				Check:    "pod-not-ready",
+				Object:   object,
+				Message:  fmt.Sprintf("container is waiting: %s", reason),
+				Next:     next,
+			})
+			continue
+		}
+		switch {
+		case pod.Status.Phase == corev1.PodFailed:
+			message := "pod failed"
+			if pod.Status.Reason != "" {
+				message = fmt.Sprintf("pod failed: %s", pod.Status.Reason)
+			}
+			findings = append(findings, models.NamespaceHealthFinding{
+				Severity: models.SeverityWarning,
+				Check:    "pod-failed",
+				Object:   object,
+				Message:  message,
+				Next:     next,
+			})
+		case pod.Status.Phase == corev1.PodPending:
+			message := "pod is pending"
+			if !pod.CreationTimestamp.IsZero() {
+				message = fmt.Sprintf("pod has been pending for %s", time.Since(pod.CreationTimestamp.Time).Round(time.Second))
+			}
+			findings = append(findings, models.NamespaceHealthFinding{
+				Severity: models.SeverityWarning,
+				Check:    "pod-pending",
+				Object:   object,
+				Message:  message,
+				Next:     suggestTool("ANALYZE_PENDING_PODS", namespace, pod.Name, map[string]any{"name": pod.Name, "namespace": namespace}),
+			})
+		case pod.Status.Phase == corev1.PodRunning && !podReady(pod):
+			findings = append(findings, models.NamespaceHealthFinding{
+				Severity: models.SeverityWarning,
+				Check:    "pod-not-ready",
+				Object:   object,
+				Message:  "pod is running but not ready",
+				Next:     next,
+			})
+		case pod.Status.Phase != corev1.PodRunning:
+			findings = append(findings, models.NamespaceHealthFinding{
+				Severity: models.SeverityInfo,
+				Check:    "pod-not-ready",
+				Object:   object,
+				Message:  fmt.Sprintf("pod phase is %s", pod.Status.Phase),
+				Next:     next,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// podWaitingReason 返回第一个处于等待状态的容器（含初始化容器）的原因，ContainerCreating和PodInitializing除外
+func podWaitingReason(pod *corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil || waiting.Reason == "" || waiting.Reason == "ContainerCreating" || waiting.Reason == "PodInitializing" {
+			continue
+		}
+		return waiting.Reason
+	}
+	return ""
+}
+
+// checkWorkloadReplicas 检查就绪副本数低于期望值的Deployment、StatefulSet和DaemonSet
+func (h *NamespaceHandlerImpl) checkWorkloadReplicas(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	clientSet := h.Client.ClientSet()
+	var findings []models.NamespaceHealthFinding
+	add := func(kind, name string, ready, desired int32) {
+		if ready >= desired {
+			return
+		}
+		severity := models.SeverityWarning
+		if ready == 0 {
+			severity = models.SeverityCritical
+		}
+		findings = append(findings, models.NamespaceHealthFinding{
+			Severity: severity,
+			Check:    "workload-replicas",
+			Object:   models.NamespaceHealthObjectRef{Kind: kind, Name: name, Namespace: namespace},
+			Message:  fmt.Sprintf("%d/%d replicas ready", ready, desired),
+			Next: suggestTool("GET_EVENTS", namespace, kind+"/"+name, map[string]any{
+				"kind":         kind,
+				"name":         name,
+				"namespace":    namespace,
+				"includeOwned": true,
+			}),
+		})
+	}
+
+	deployments, err := clientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments.Items {
+		add("Deployment", d.Name, d.Status.ReadyReplicas, derefReplicas(d.Spec.Replicas))
+	}
+	statefulSets, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulSets.Items {
+		add("StatefulSet", s.Name, s.Status.ReadyReplicas, derefReplicas(s.Spec.Replicas))
+	}
+	daemonSets, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range daemonSets.Items {
+		add("DaemonSet", d.Name, d.Status.NumberReady, d.Status.DesiredNumberScheduled)
+	}
+	return findings, nil
+}
+
+// derefReplicas 返回副本数，未设置时为默认值1
+func derefReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// checkWarningEvents 按原因汇总最近一小时内的Warning事件
+func (h *NamespaceHandlerImpl) checkWarningEvents(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	events, err := h.Client.ClientSet().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err != nil {
+		return nil, err
+	}
+
+	type eventGroup struct {
+		count   int32
+		objects map[string]bool
+		latest  corev1.Event
+	}
+	groups := make(map[string]*eventGroup)
+	cutoff := time.Now().Add(-healthEventWindow)
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning || utils.EventLastSeen(event).Before(cutoff) {
+			continue
+		}
+		group, ok := groups[event.Reason]
+		if !ok {
+			group = &eventGroup{objects: make(map[string]bool)}
+			groups[event.Reason] = group
+		}
+		group.count += max(event.Count, 1)
+		group.objects[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name] = true
+		if utils.EventLastSeen(event).After(utils.EventLastSeen(group.latest)) {
+			group.latest = event
+		}
+	}
+
+	var findings []models.NamespaceHealthFinding
+	for reason, group := range groups {
+		objects := sortedSet(group.objects)
+		involved := group.latest.InvolvedObject
+		message := fmt.Sprintf("%d %s event(s) in the last hour on %d object(s): %s; latest: %s",
+			group.count, reason, len(objects), strings.Join(firstN(objects, maxHealthEventObjects), ", "), group.latest.Message)
+		findings = append(findings, models.NamespaceHealthFinding{
+			Severity: models.SeverityWarning,
+			Check:    "warning-events",
+			Object:   models.NamespaceHealthObjectRef{Kind: involved.Kind, Name: involved.Name, Namespace: namespace},
+			Message:  message,
+			Next: suggestTool("GET_EVENTS", namespace, involved.Kind+"/"+involved.Name, map[string]any{
+				"kind":      involved.Kind,
+				"name":      involved.Name,
+				"namespace": namespace,
+			}),
+		})
+	}
+	return findings, nil
+}
+
+// checkFailedJobs 检查状态条件为Failed的Job
+func (h *NamespaceHandlerImpl) checkFailedJobs(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	jobs, err := h.Client.ClientSet().BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var findings []models.NamespaceHealthFinding
+	for _, job := range jobs.Items {
+		for _, condition := range job.Status.Conditions {
+			if condition.Type != "Failed" || condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			findings = append(findings, models.NamespaceHealthFinding{
+				Severity: models.SeverityWarning,
+				Check:    "job-failed",
+				Object:   models.NamespaceHealthObjectRef{Kind: "Job", Name: job.Name, Namespace: namespace},
+				Message:  strings.TrimSpace(fmt.Sprintf("job failed: %s %s", condition.Reason, condition.Message)),
+				Next:     suggestTool("GET_JOB_STATUS", namespace, job.Name, map[string]any{"name": job.Name, "namespace": namespace}),
+			})
+			break
+		}
+	}
+	return findings, nil
+}
+
+// checkPendingClaims 检查Pending或Lost的PVC
+func (h *NamespaceHandlerImpl) checkPendingClaims(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	claims, err := h.Client.ClientSet().CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var findings []models.NamespaceHealthFinding
+	for _, claim := range claims.Items {
+		severity := models.SeverityWarning
+		switch claim.Status.Phase {
+		case corev1.ClaimPending:
+		case corev1.ClaimLost:
+			severity = models.SeverityCritical
+		default:
+			continue
+		}
+		findings = append(findings, models.NamespaceHealthFinding{
+			Severity: severity,
+			Check:    "pvc-not-bound",
+			Object:   models.NamespaceHealthObjectRef{Kind: "PersistentVolumeClaim", Name: claim.Name, Namespace: namespace},
+			Message:  fmt.Sprintf("claim is %s", claim.Status.Phase),
+			Next:     suggestTool("GET_STORAGE_STATUS", namespace, "", map[string]any{"namespace": namespace}),
+		})
+	}
+	return findings, nil
+}
+
+// checkHPAsAtMax 检查当前副本数已达到上限的HPA
+func (h *NamespaceHandlerImpl) checkHPAsAtMax(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	hpas, err := h.Client.ClientSet().AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var findings []models.NamespaceHealthFinding
+	for _, hpa := range hpas.Items {
+		if hpa.Status.CurrentReplicas < hpa.Spec.MaxReplicas {
+			continue
+		}
+		findings = append(findings, models.NamespaceHealthFinding{
+			Severity: models.SeverityWarning,
+			Check:    "hpa-at-max",
+			Object:   models.NamespaceHealthObjectRef{Kind: "HorizontalPodAutoscaler", Name: hpa.Name, Namespace: namespace},
+			Message: fmt.Sprintf("running at maxReplicas %d (desired %d) for %s/%s",
+				hpa.Spec.MaxReplicas, hpa.Status.DesiredReplicas, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+			Next: suggestTool("GET_HPA_STATUS", namespace, hpa.Name, map[string]any{"name": hpa.Name, "namespace": namespace}),
+		})
+	}
+	return findings, nil
+}
+
+// checkServiceEndpoints 检查有选择器但没有就绪端点的Service
+func (h *NamespaceHandlerImpl) checkServiceEndpoints(ctx context.Context, namespace string) ([]models.NamespaceHealthFinding, error) {
+	clientSet := h.Client.ClientSet()
+	services, err := clientSet.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	slices, err := clientSet.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ready := make(map[string]int)
+	for _, slice := range slices.Items {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				ready[service] += len(endpoint.Addresses)
+			}
+		}
+	}
+
+	var findings []models.NamespaceHealthFinding
+	for _, service := range services.Items {
+		// 没有选择器的Service由用户自行管理端点，ExternalName没有端点
+		if len(service.Spec.Selector) == 0 || service.Spec.Type == corev1.ServiceTypeExternalName || ready[service.Name] > 0 {
+			continue
+		}
+		findings = append(findings, models.NamespaceHealthFinding{
+			Severity: models.SeverityWarning,
+			Check:    "service-no-endpoints",
+			Object:   models.NamespaceHealthObjectRef{Kind: "Service", Name: service.Name, Namespace: namespace},
+			Message:  "service has no ready endpoints",
+			Next:     suggestTool("ANALYZE_SERVICE", namespace, service.Name, map[string]any{"name": service.Name, "namespace": namespace}),
+		})
+	}
+	return findings, nil
+}
+
+// suggestTool 构造建议的工具调用，target为空时描述为作用于整个命名空间
+func suggestTool(tool, namespace, target string, arguments map[string]any) *models.SuggestedToolCall {
+	description := fmt.Sprintf("run %s on %s/%s", tool, namespace, target)
+	if target == "" {
+		description = fmt.Sprintf("run %s in namespace %s", tool, namespace)
+	}
+	return &models.SuggestedToolCall{Tool: tool, Arguments: arguments, Description: description}
+}
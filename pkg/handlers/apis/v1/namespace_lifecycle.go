@@ -0,0 +1,435 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 命名空间生命周期相关的默认值
+const (
+	// presetQuotaName 按模板创建的ResourceQuota名称
+	presetQuotaName = "default-quota"
+	// presetLimitRangeName 按模板创建的LimitRange名称
+	presetLimitRangeName = "default-limits"
+	// defaultNamespaceDeleteWait 等待命名空间删除完成的默认秒数
+	defaultNamespaceDeleteWait = 60
+	// maxNamespaceContentObjects 删除报告中列出的对象名称上限
+	maxNamespaceContentObjects = 50
+)
+
+var (
+	namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+	// protectedNamespaces 系统命名空间，任何情况下都不允许删除
+	protectedNamespaces = map[string]bool{
+		"default":         true,
+		"kube-system":     true,
+		"kube-public":     true,
+		"kube-node-lease": true,
+	}
+)
+
+// CreateNamespace 创建命名空间，可选地按模板创建ResourceQuota和LimitRange
+func (h *NamespaceHandlerImpl) CreateNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	presetName, _ := arguments["preset"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
+
+	h.Log.Info("Creating namespace", "name", name, "preset", presetName, "dryRun", dryRun)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	labels, err := utils.StringMapArgument(arguments, "labels")
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	annotations, err := utils.StringMapArgument(arguments, "annotations")
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+
+	var preset models.NamespacePreset
+	if presetName != "" {
+		presets := base.GetOptions().NamespacePresets
+		var ok bool
+		if preset, ok = presets[presetName]; !ok {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: fmt.Sprintf("unknown namespace preset %q", presetName),
+				Hint:    fmt.Sprintf("Available presets: %s.", strings.Join(sortedPresetNames(presets), ", ")),
+			}), nil
+		}
+	}
+
+	if denied := h.PreflightCheck(ctx, "create", namespacesGVR, "", name); denied != nil {
+		return denied, nil
+	}
+
+	createOptions := metav1.CreateOptions{}
+	if dryRun {
+		createOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	clientSet := h.Client.ClientSet()
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+	if _, err := clientSet.CoreV1().Namespaces().Create(ctx, ns, createOptions); err != nil {
+		h.Log.Error("Failed to create namespace", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to create namespace %s", name)), nil
+	}
+
+	response := models.NamespaceCreateResult{
+		Name:        name,
+		Labels:      labels,
+		Annotations: annotations,
+		Preset:      presetName,
+		DryRun:      dryRun,
+	}
+
+	// 演练模式下命名空间并未真正创建，无法在其中演练创建配额对象
+	if dryRun {
+		if preset.ResourceQuota != nil {
+			response.ResourceQuota = presetQuotaName
+		}
+		if preset.LimitRange != nil {
+			response.LimitRange = presetLimitRangeName
+		}
+		return utils.RenderResult(request, response), nil
+	}
+
+	// 模板对象创建失败不回滚命名空间，在结果中报告错误
+	if preset.ResourceQuota != nil {
+		quota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: presetQuotaName, Namespace: name},
+			Spec:       *preset.ResourceQuota.DeepCopy(),
+		}
+		if _, err := clientSet.CoreV1().ResourceQuotas(name).Create(ctx, quota, createOptions); err != nil {
+			h.Log.Error("Failed to create preset resource quota", "namespace", name, "error", err)
+			response.Errors = append(response.Errors, fmt.Sprintf("resourcequota %s: %v", presetQuotaName, err))
+		} else {
+			response.ResourceQuota = presetQuotaName
+		}
+	}
+	if preset.LimitRange != nil {
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: presetLimitRangeName, Namespace: name},
+			Spec:       *preset.LimitRange.DeepCopy(),
+		}
+		if _, err := clientSet.CoreV1().LimitRanges(name).Create(ctx, limitRange, createOptions); err != nil {
+			h.Log.Error("Failed to create preset limit range", "namespace", name, "error", err)
+			response.Errors = append(response.Errors, fmt.Sprintf("limitrange %s: %v", presetLimitRangeName, err))
+		} else {
+			response.LimitRange = presetLimitRangeName
+		}
+	}
+
+	h.Client.Cache().InvalidateKind("Namespace")
+	return utils.RenderResult(request, response), nil
+}
+
+// LabelNamespace 合并或删除命名空间标签
+func (h *NamespaceHandlerImpl) LabelNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	removeStr, _ := arguments["remove"].(string)
+
+	h.Log.Info("Labeling namespace", "name", name, "remove", removeStr)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	set, err := utils.StringMapArgument(arguments, "labels")
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	var remove []string
+	for _, key := range strings.Split(removeStr, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			remove = append(remove, key)
+		}
+	}
+	if len(set) == 0 && len(remove) == 0 {
+		return utils.NewErrorToolResult("at least one of labels or remove is required"), nil
+	}
+
+	patch, err := utils.MetadataMergePatch("labels", set, remove)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to build label patch: %v", err)), nil
+	}
+
+	if denied := h.PreflightCheck(ctx, "patch", namespacesGVR, "", name); denied != nil {
+		return denied, nil
+	}
+
+	ns, err := h.Client.ClientSet().CoreV1().Namespaces().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		h.Log.Error("Failed to label namespace", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to label namespace %s", name)), nil
+	}
+
+	response := models.NamespaceLabelResult{
+		Name:    name,
+		Set:     set,
+		Removed: remove,
+		Labels:  ns.Labels,
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// DeleteNamespace 删除命名空间
+// 删除前列出剩余的工作负载和PVC，非空时除非force=true否则拒绝删除；
+// 可选等待删除完成，超时仍处于Terminating时报告阻塞删除的终结器和条件
+func (h *NamespaceHandlerImpl) DeleteNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	force, _ := arguments["force"].(bool)
+	wait, _ := arguments["wait"].(bool)
+	waitSeconds := defaultNamespaceDeleteWait
+	if value, ok := arguments["waitTimeoutSeconds"].(float64); ok && value > 0 {
+		waitSeconds = int(value)
+	}
+
+	h.Log.Info("Deleting namespace", "name", name, "force", force, "wait", wait)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	if protectedNamespaces[name] {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeRefused,
+			Message: fmt.Sprintf("namespace %s is a system namespace and cannot be deleted", name),
+		}), nil
+	}
+
+	clientSet := h.Client.ClientSet()
+	ns, err := clientSet.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.Log.Error("Failed to get namespace", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get namespace %s", name)), nil
+	}
+
+	response := models.NamespaceDeleteResult{
+		Name:     name,
+		Contents: h.namespaceContents(ctx, name),
+	}
+
+	// 已经处于Terminating的命名空间不再重复删除，直接报告阻塞原因
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		describeTermination(&response, ns)
+		response.Stuck = len(response.Blockers) > 0
+		return utils.RenderResult(request, response), nil
+	}
+
+	if response.Contents.Total > 0 && !force {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeRefused,
+			Message: fmt.Sprintf("namespace %s still contains %d workloads or volumes", name, response.Contents.Total),
+			Hint:    "Review the remaining objects in details; pass force=true to delete the namespace and everything in it.",
+			Details: response.Contents,
+		}), nil
+	}
+
+	if denied := h.PreflightCheck(ctx, "delete", namespacesGVR, "", name); denied != nil {
+		return denied, nil
+	}
+
+	if err := clientSet.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		h.Log.Error("Failed to delete namespace", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to delete namespace %s", name)), nil
+	}
+	response.Deleted = true
+	response.Phase = string(corev1.NamespaceTerminating)
+	h.Client.Cache().InvalidateKind("Namespace")
+
+	if !wait {
+		return utils.RenderResult(request, response), nil
+	}
+
+	// 轮询直到命名空间消失、等待超时或调用超时
+	start := time.Now()
+	deadline := time.NewTimer(time.Duration(waitSeconds) * time.Second)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	progress := utils.ProgressFromContext(ctx)
+poll:
+	for {
+		current, err := clientSet.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			response.Phase = "Deleted"
+			response.Waited = time.Since(start).Round(time.Second).String()
+			progress.Done(ctx, fmt.Sprintf("namespace %s deleted after %s", name, response.Waited))
+			return utils.RenderResult(request, response), nil
+		}
+		if err == nil {
+			ns = current
+			progress.Report(ctx, fmt.Sprintf("namespace %s is still %s", name, ns.Status.Phase))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			break poll
+		case <-ctx.Done():
+			break poll
+		}
+	}
+
+	response.Waited = time.Since(start).Round(time.Second).String()
+	describeTermination(&response, ns)
+	response.Stuck = true
+	h.Log.Warn("Namespace still terminating after wait", "name", name, "waited", response.Waited)
+	progress.Done(ctx, fmt.Sprintf("namespace %s still terminating after %s", name, response.Waited))
+	return utils.RenderResult(request, response), nil
+}
+
+// namespaceContents 列出命名空间中剩余的工作负载和PVC
+func (h *NamespaceHandlerImpl) namespaceContents(ctx context.Context, namespace string) models.NamespaceContents {
+	contents := models.NamespaceContents{Counts: make(map[string]int)}
+	clientSet := h.Client.ClientSet()
+	listOptions := metav1.ListOptions{}
+
+	add := func(kind string, names []string, err error) {
+		if err != nil {
+			contents.Errors = append(contents.Errors, fmt.Sprintf("%s: %v", kind, err))
+			return
+		}
+		if len(names) == 0 {
+			return
+		}
+		contents.Counts[kind] = len(names)
+		contents.Total += len(names)
+		for _, name := range names {
+			if len(contents.Objects) >= maxNamespaceContentObjects {
+				contents.Truncated = true
+				return
+			}
+			contents.Objects = append(contents.Objects, kind+"/"+name)
+		}
+	}
+
+	if list, err := clientSet.AppsV1().Deployments(namespace).List(ctx, listOptions); err != nil {
+		add("Deployment", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("Deployment", names, nil)
+	}
+	if list, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, listOptions); err != nil {
+		add("StatefulSet", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("StatefulSet", names, nil)
+	}
+	if list, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, listOptions); err != nil {
+		add("DaemonSet", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("DaemonSet", names, nil)
+	}
+	if list, err := clientSet.BatchV1().Jobs(namespace).List(ctx, listOptions); err != nil {
+		add("Job", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("Job", names, nil)
+	}
+	if list, err := clientSet.BatchV1().CronJobs(namespace).List(ctx, listOptions); err != nil {
+		add("CronJob", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("CronJob", names, nil)
+	}
+	if list, err := clientSet.CoreV1().Pods(namespace).List(ctx, listOptions); err != nil {
+		add("Pod", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("Pod", names, nil)
+	}
+	if list, err := clientSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, listOptions); err != nil {
+		add("PersistentVolumeClaim", nil, err)
+	} else {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		add("PersistentVolumeClaim", names, nil)
+	}
+
+	return contents
+}
+
+// describeTermination 填充命名空间删除的阶段、终结器和处于True状态的阻塞条件
+func describeTermination(response *models.NamespaceDeleteResult, ns *corev1.Namespace) {
+	response.Phase = string(ns.Status.Phase)
+	for _, finalizer := range ns.Spec.Finalizers {
+		response.Finalizers = append(response.Finalizers, string(finalizer))
+	}
+	response.Finalizers = append(response.Finalizers, ns.Finalizers...)
+	for _, condition := range ns.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		response.Blockers = append(response.Blockers, models.NamespaceBlocker{
+			Type:    string(condition.Type),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+}
+
+// sortedPresetNames 返回排序后的模板名称
+func sortedPresetNames(presets map[string]models.NamespacePreset) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,13 +2,13 @@ package v1
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
@@ -19,9 +19,18 @@ import (
 
 // 定义常量
 const (
-	LIST_NAMESPACES = "LIST_NAMESPACES"
+	LIST_NAMESPACES    = "LIST_NAMESPACES"
+	DESCRIBE_NAMESPACE = "DESCRIBE_NAMESPACE"
+	CREATE_NAMESPACE   = "CREATE_NAMESPACE"
+	LABEL_NAMESPACE    = "LABEL_NAMESPACE"
+	DELETE_NAMESPACE   = "DELETE_NAMESPACE"
+
+	NAMESPACE_HEALTH_SUMMARY = "NAMESPACE_HEALTH_SUMMARY"
 )
 
+// 命名空间概览中返回的最近Warning事件数量
+const maxNamespaceWarningEvents = 20
+
 // NamespaceHandlerImpl 命名空间处理程序实现
 type NamespaceHandlerImpl struct {
 	base.Handler
@@ -43,13 +52,23 @@ func (h *NamespaceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolR
 	switch request.Method {
 	case LIST_NAMESPACES:
 		return h.ListNamespaces(ctx, request)
+	case DESCRIBE_NAMESPACE:
+		return h.DescribeNamespace(ctx, request)
+	case CREATE_NAMESPACE:
+		return h.CreateNamespace(ctx, request)
+	case LABEL_NAMESPACE:
+		return h.LabelNamespace(ctx, request)
+	case DELETE_NAMESPACE:
+		return h.DeleteNamespace(ctx, request)
+	case NAMESPACE_HEALTH_SUMMARY:
+		return h.NamespaceHealthSummary(ctx, request)
 	default:
 		return utils.NewErrorToolResult(fmt.Sprintf("unknown namespace method: %s", request.Method)), nil
 	}
 }
 
 // Register 实现接口方法
-func (h *NamespaceHandlerImpl) Register(server *server.MCPServer) {
+func (h *NamespaceHandlerImpl) Register(server interfaces.ToolRegistrar) {
 	h.Log.Info("Registering namespace handlers",
 		"scope", h.Scope,
 		"apiGroup", h.Group,
@@ -68,7 +87,101 @@ func (h *NamespaceHandlerImpl) Register(server *server.MCPServer) {
 			mcp.Description("是否显示命名空间的所有标签。启用后将在输出中包含完整的标签列表，有助于命名空间分类和管理。默认为false。"),
 			mcp.DefaultBool(false),
 		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.ListNamespaces)
+
+	// 注册命名空间概览工具
+	server.AddTool(mcp.NewTool(DESCRIBE_NAMESPACE,
+		mcp.WithDescription("获取指定命名空间的整体概览。汇总各类工作负载的数量及就绪情况、按阶段统计的Pod、Service和Ingress、ResourceQuota使用量与硬限制、LimitRange默认值、最近的Warning事件，以及命名空间的实际CPU/内存使用量。当用户反馈某个命名空间出现问题时，建议首先调用此工具。"),
+		mcp.WithString("namespace",
+			mcp.Description("要查看的命名空间名称。"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("includeObjects",
+			mcp.Description("是否列出对象名称及状态。默认为false，只返回数量统计。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DescribeNamespace)
+
+	// 注册命名空间问题汇总工具
+	server.AddTool(mcp.NewTool(NAMESPACE_HEALTH_SUMMARY,
+		mcp.WithDescription("回答“命名空间里现在哪些东西坏了”。并发执行一组开销较小的检查并合并为按严重程度排序的问题列表：未运行或未就绪的Pod、就绪副本数低于期望值的工作负载、最近一小时按原因分组的Warning事件、失败的Job、Pending的PVC、已达到maxReplicas的HPA、没有就绪端点的Service。每个问题包含严重程度、涉及的对象以及建议的下一步工具调用（例如对某个Pod运行DIAGNOSE_POD）。每项检查有独立的超时时间，超时的检查不会阻塞汇总，结果中会注明哪些检查未完成。只读操作。"),
+		mcp.WithString("namespace",
+			mcp.Description("要检查的命名空间名称。"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("checkTimeoutSeconds",
+			mcp.Description(fmt.Sprintf("每项检查的超时秒数，默认%d，最大%d。", defaultHealthCheckTimeoutSeconds, maxHealthCheckTimeoutSeconds)),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.NamespaceHealthSummary)
+
+	// 注册创建命名空间工具
+	server.AddTool(mcp.NewTool(CREATE_NAMESPACE,
+		mcp.WithDescription("创建命名空间，无需编写YAML。可设置标签和注解，并可按服务器配置的模板（内置small、medium、large）同时创建ResourceQuota和LimitRange。模板对象创建失败时命名空间仍会保留，错误在结果中列出。"),
+		mcp.WithString("name",
+			mcp.Description("要创建的命名空间名称。"),
+			mcp.Required(),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("命名空间标签，键值均为字符串，例如：{\"team\": \"payments\"}。"),
+		),
+		mcp.WithObject("annotations",
+			mcp.Description("命名空间注解，键值均为字符串。"),
+		),
+		mcp.WithString("preset",
+			mcp.Description("ResourceQuota/LimitRange模板名称（可选），例如：'small'、'medium'、'large'，或服务器模板文件中定义的名称。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否只在服务端演练而不实际创建。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CreateNamespace)
+
+	// 注册修改命名空间标签工具
+	server.AddTool(mcp.NewTool(LABEL_NAMESPACE,
+		mcp.WithDescription("合并或删除命名空间标签。labels中的键会被添加或覆盖，remove中的键会被删除，其余标签保持不变。返回修改后的完整标签。"),
+		mcp.WithString("name",
+			mcp.Description("命名空间名称。"),
+			mcp.Required(),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("要添加或覆盖的标签，键值均为字符串。"),
+		),
+		mcp.WithString("remove",
+			mcp.Description("要删除的标签键，多个用逗号分隔。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.LabelNamespace)
+
+	// 注册安全删除命名空间工具
+	server.AddTool(mcp.NewTool(DELETE_NAMESPACE,
+		mcp.WithDescription("安全删除命名空间。删除前先列出剩余的工作负载、Pod和PVC，命名空间非空时除非force=true否则拒绝删除；系统命名空间始终拒绝删除。可等待删除完成，超时后仍处于Terminating时报告阻塞删除的终结器和状态条件（例如剩余内容或无法访问的API组）。"),
+		mcp.WithString("name",
+			mcp.Description("要删除的命名空间名称。"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("命名空间中仍有工作负载或PVC时是否继续删除。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("wait",
+			mcp.Description("是否等待命名空间删除完成。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("waitTimeoutSeconds",
+			mcp.Description("等待删除完成的最长秒数，默认60。同时受本次调用的超时时间限制。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DeleteNamespace)
 }
 
 // ListNamespaces 列出所有命名空间
@@ -83,7 +196,7 @@ func (h *NamespaceHandlerImpl) ListNamespaces(
 	err := h.Client.List(ctx, namespaces)
 	if err != nil {
 		h.Log.Error("Failed to list namespaces", "error", err)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to list namespaces: %v", err)), nil
+		return utils.NewKubeErrorResult(err, "failed to list namespaces"), nil
 	}
 
 	// 构建命名空间信息列表
@@ -113,19 +226,243 @@ func (h *NamespaceHandlerImpl) ListNamespaces(
 	}
 
 	// 序列化为JSON
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+
+	h.Log.Info("Namespaces listed successfully", "count", len(namespaces.Items))
+
+	return utils.RenderResult(request, response), nil
+}
+
+// cachedNamespace 从缓存的命名空间列表中查找命名空间，缓存中没有（例如缓存之后才创建）时直接从API Server获取
+func (h *NamespaceHandlerImpl) cachedNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	if namespaces, err := h.Client.ListNamespaces(ctx); err == nil {
+		for i := range namespaces {
+			if namespaces[i].Name == name {
+				return namespaces[i].DeepCopy(), nil
+			}
+		}
+	}
+	return h.Client.ClientSet().CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
+// DescribeNamespace 汇总命名空间内的工作负载、配额、事件和资源使用情况
+func (h *NamespaceHandlerImpl) DescribeNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	includeObjects, _ := arguments["includeObjects"].(bool)
+
+	h.Log.Info("Describing namespace", "namespace", namespace, "includeObjects", includeObjects)
+
+	if namespace == "" {
+		return utils.NewErrorToolResult("missing required parameter: namespace"), nil
+	}
+
+	clientSet := h.Client.ClientSet()
+	ns, err := h.cachedNamespace(ctx, namespace)
 	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON序列化失败: %v", err)), nil
+		h.Log.Error("Failed to get namespace", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get namespace %s", namespace)), nil
 	}
 
-	h.Log.Info("Namespaces listed successfully", "count", len(namespaces.Items))
+	response := models.NamespaceDescription{
+		Name:          ns.Name,
+		Status:        string(ns.Status.Phase),
+		Labels:        ns.Labels,
+		CreationTime:  ns.CreationTimestamp.Time,
+		Workloads:     []models.WorkloadSummary{},
+		PodsByPhase:   make(map[string]int),
+		WarningEvents: []models.DiagnosisEvent{},
+		RetrievedAt:   time.Now(),
+	}
+
+	// 单项查询失败不影响整体结果，记录错误后继续
+	recordError := func(what string, err error) {
+		h.Log.Warn("Failed to collect namespace data", "namespace", namespace, "item", what, "error", err)
+		response.Errors = append(response.Errors, fmt.Sprintf("%s: %v", what, err))
+	}
+	listOptions := metav1.ListOptions{}
+
+	// --- 工作负载 ---
+	if deployments, err := clientSet.AppsV1().Deployments(namespace).List(ctx, listOptions); err != nil {
+		recordError("deployments", err)
+	} else {
+		summary := models.WorkloadSummary{Kind: "Deployment"}
+		for _, d := range deployments.Items {
+			desired := int32(1)
+			if d.Spec.Replicas != nil {
+				desired = *d.Spec.Replicas
+			}
+			addWorkload(&summary, d.Name, d.Status.ReadyReplicas >= desired,
+				fmt.Sprintf("%d/%d ready", d.Status.ReadyReplicas, desired), includeObjects)
+		}
+		response.Workloads = append(response.Workloads, summary)
+	}
+
+	if statefulSets, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, listOptions); err != nil {
+		recordError("statefulsets", err)
+	} else {
+		summary := models.WorkloadSummary{Kind: "StatefulSet"}
+		for _, s := range statefulSets.Items {
+			desired := int32(1)
+			if s.Spec.Replicas != nil {
+				desired = *s.Spec.Replicas
+			}
+			addWorkload(&summary, s.Name, s.Status.ReadyReplicas >= desired,
+				fmt.Sprintf("%d/%d ready", s.Status.ReadyReplicas, desired), includeObjects)
+		}
+		response.Workloads = append(response.Workloads, summary)
+	}
+
+	if daemonSets, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, listOptions); err != nil {
+		recordError("daemonsets", err)
+	} else {
+		summary := models.WorkloadSummary{Kind: "DaemonSet"}
+		for _, d := range daemonSets.Items {
+			addWorkload(&summary, d.Name, d.Status.NumberReady >= d.Status.DesiredNumberScheduled,
+				fmt.Sprintf("%d/%d ready", d.Status.NumberReady, d.Status.DesiredNumberScheduled), includeObjects)
+		}
+		response.Workloads = append(response.Workloads, summary)
+	}
+
+	if jobs, err := clientSet.BatchV1().Jobs(namespace).List(ctx, listOptions); err != nil {
+		recordError("jobs", err)
+	} else {
+		summary := models.WorkloadSummary{Kind: "Job"}
+		for _, j := range jobs.Items {
+			// 失败的Job视为未就绪，运行中和已完成的Job视为正常
+			addWorkload(&summary, j.Name, j.Status.Failed == 0,
+				fmt.Sprintf("%d active, %d succeeded, %d failed", j.Status.Active, j.Status.Succeeded, j.Status.Failed), includeObjects)
+		}
+		response.Workloads = append(response.Workloads, summary)
+	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	if cronJobs, err := clientSet.BatchV1().CronJobs(namespace).List(ctx, listOptions); err != nil {
+		recordError("cronjobs", err)
+	} else {
+		summary := models.WorkloadSummary{Kind: "CronJob"}
+		for _, c := range cronJobs.Items {
+			suspended := c.Spec.Suspend != nil && *c.Spec.Suspend
+			status := fmt.Sprintf("%d active", len(c.Status.Active))
+			if suspended {
+				status = "suspended"
+			}
+			addWorkload(&summary, c.Name, !suspended, status, includeObjects)
+		}
+		response.Workloads = append(response.Workloads, summary)
+	}
+
+	// --- Pod ---
+	if pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, listOptions); err != nil {
+		recordError("pods", err)
+	} else {
+		for _, p := range pods.Items {
+			response.PodsByPhase[string(p.Status.Phase)]++
+			if includeObjects {
+				status := string(p.Status.Phase)
+				if p.Status.Reason != "" {
+					status = p.Status.Reason
+				}
+				for _, cs := range p.Status.ContainerStatuses {
+					if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+						status = cs.State.Waiting.Reason
+						break
+					}
+				}
+				response.Pods = append(response.Pods, models.ObjectStatus{Name: p.Name, Status: status})
+			}
+		}
+	}
+
+	// --- Service 和 Ingress ---
+	if services, err := clientSet.CoreV1().Services(namespace).List(ctx, listOptions); err != nil {
+		recordError("services", err)
+	} else {
+		response.ServiceCount = len(services.Items)
+		if includeObjects {
+			for _, s := range services.Items {
+				response.Services = append(response.Services, models.ObjectStatus{Name: s.Name, Status: string(s.Spec.Type)})
+			}
+		}
+	}
+
+	if ingresses, err := clientSet.NetworkingV1().Ingresses(namespace).List(ctx, listOptions); err != nil {
+		recordError("ingresses", err)
+	} else {
+		response.IngressCount = len(ingresses.Items)
+		if includeObjects {
+			for _, ing := range ingresses.Items {
+				hosts := make([]string, 0, len(ing.Spec.Rules))
+				for _, rule := range ing.Spec.Rules {
+					if rule.Host != "" {
+						hosts = append(hosts, rule.Host)
+					}
+				}
+				response.Ingresses = append(response.Ingresses, models.ObjectStatus{Name: ing.Name, Status: strings.Join(hosts, ",")})
+			}
+		}
+	}
+
+	// --- 配额与限制 ---
+	if quotas, err := clientSet.CoreV1().ResourceQuotas(namespace).List(ctx, listOptions); err != nil {
+		recordError("resourcequotas", err)
+	} else {
+		response.ResourceQuotas = quotaUsages(quotas.Items)
+	}
+	if limitRanges, err := clientSet.CoreV1().LimitRanges(namespace).List(ctx, listOptions); err != nil {
+		recordError("limitranges", err)
+	} else {
+		response.LimitRanges = limitRangeInfos(limitRanges.Items)
+	}
+
+	// --- 最近的Warning事件 ---
+	if events, err := clientSet.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"}); err != nil {
+		recordError("events", err)
+	} else {
+		warnings := warningEvents(events.Items)
+		if len(warnings) > maxNamespaceWarningEvents {
+			warnings = warnings[len(warnings)-maxNamespaceWarningEvents:]
+		}
+		response.WarningEvents = warnings
+	}
+
+	// --- 资源使用情况 ---
+	podMetrics, err := utils.GetPodsMetrics(ctx, h.Client, namespace)
+	if err != nil {
+		h.Log.Warn("Failed to get pod metrics", "namespace", namespace, "error", err)
+		response.MetricsError = err.Error()
+	} else {
+		usage := &models.NamespaceUsage{PodsWithMetrics: len(podMetrics)}
+		for _, m := range podMetrics {
+			usage.CPUMillicores += m.TotalCPU
+			usage.MemoryMB += m.TotalMemory
+		}
+		if clusterMetrics, err := utils.GetClusterResourceMetrics(ctx, h.Client, namespace); err == nil {
+			if clusterMetrics.CPUAllocatable > 0 {
+				usage.ClusterCPUPercent = float64(usage.CPUMillicores) / float64(clusterMetrics.CPUAllocatable) * 100
+			}
+			if clusterMetrics.MemoryAllocatable > 0 {
+				usage.ClusterMemoryPercent = float64(usage.MemoryMB) / float64(clusterMetrics.MemoryAllocatable) * 100
+			}
+		}
+		response.Usage = usage
+	}
+
+	h.Log.Info("Namespace described successfully", "namespace", namespace)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// addWorkload 将单个工作负载计入汇总
+func addWorkload(summary *models.WorkloadSummary, name string, ready bool, status string, includeObjects bool) {
+	summary.Total++
+	if ready {
+		summary.Ready++
+	} else {
+		summary.NotReady++
+	}
+	if includeObjects {
+		summary.Objects = append(summary.Objects, models.ObjectStatus{Name: name, Status: status})
+	}
 }
@@ -0,0 +1,340 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// defaultNodeHealthEvents 每个节点默认返回的事件数量
+	defaultNodeHealthEvents = 10
+	// maxNodeHealthEvents 每个节点最多返回的事件数量
+	maxNodeHealthEvents = 50
+	// nodeHealthEventWindow 只统计该时间范围内的告警事件
+	nodeHealthEventWindow = time.Hour
+	// nodePodCapacityWarnPercent Pod数量达到该比例时给出警告
+	nodePodCapacityWarnPercent = 90
+	// nodeUsageWarnPercent CPU或内存使用率达到该比例时给出警告
+	nodeUsageWarnPercent = 90.0
+)
+
+// nodeHealthConditions 按顺序输出的节点条件
+var nodeHealthConditions = []corev1.NodeConditionType{
+	corev1.NodeReady,
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// GetNodeHealth 汇总一个或所有节点的条件、系统信息、资源容量、Pod数量、污点、近期事件和资源使用量，并给出健康判定
+func (h *NodeHandlerImpl) GetNodeHealth(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	unhealthyOnly, _ := arguments["unhealthyOnly"].(bool)
+	maxEvents := defaultNodeHealthEvents
+	if value, ok := arguments["maxEvents"].(float64); ok {
+		maxEvents = int(value)
+	}
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.Log.Info("Getting node health",
+		"name", name,
+		"labelSelector", labelSelector,
+		"unhealthyOnly", unhealthyOnly,
+		"maxEvents", maxEvents,
+	)
+
+	if name != "" && labelSelector != "" {
+		return utils.NewErrorToolResult("specify either name or labelSelector, not both"), nil
+	}
+	if maxEvents < 0 || maxEvents > maxNodeHealthEvents {
+		return utils.NewErrorToolResult(fmt.Sprintf("maxEvents must be between 0 and %d", maxNodeHealthEvents)), nil
+	}
+
+	var nodes []corev1.Node
+	if name != "" {
+		node, err := h.Client.ClientSet().CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			h.Log.Error("Failed to get node", "name", name, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get node %s", name)), nil
+		}
+		nodes = append(nodes, *node)
+	} else {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("failed to parse label selector: %v", err)), nil
+		}
+		// 节点列表来自缓存，标签选择器在本地求值
+		allNodes, err := h.Client.ListNodes(ctx)
+		if err != nil {
+			h.Log.Error("Failed to list nodes", "error", err)
+			return utils.NewKubeErrorResult(err, "failed to list nodes"), nil
+		}
+		for _, node := range allNodes {
+			if selector.Matches(labels.Set(node.Labels)) {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	response := models.NodeHealthReport{
+		CountsByVerdict: make(map[string]int),
+		Nodes:           []models.NodeHealth{},
+		RetrievedAt:     time.Now(),
+	}
+
+	// 统计调度到节点上的Pod，获取单个节点时只列出该节点上的Pod
+	podOptions := metav1.ListOptions{}
+	if name != "" {
+		podOptions.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", name).String()
+	}
+	var pods []corev1.Pod
+	podList, err := h.Client.ClientSet().CoreV1().Pods("").List(ctx, podOptions)
+	if err != nil {
+		h.Log.Error("Failed to list pods", "error", err)
+		response.Errors = append(response.Errors, fmt.Sprintf("failed to list pods: %v", err))
+	} else {
+		pods = podList.Items
+	}
+	allocations := utils.ComputeNodeAllocations(nodes, pods)
+
+	// 节点事件记录在default命名空间中，这里不限定命名空间以兼容其他上报方式
+	eventSelector := fields.Set{"involvedObject.kind": "Node"}
+	if name != "" {
+		eventSelector["involvedObject.name"] = name
+	}
+	eventsByNode := make(map[string][]corev1.Event)
+	if maxEvents > 0 {
+		events, err := h.Client.ClientSet().CoreV1().Events("").List(ctx, metav1.ListOptions{
+			FieldSelector: eventSelector.AsSelector().String(),
+		})
+		if err != nil {
+			h.Log.Error("Failed to list node events", "error", err)
+			response.Errors = append(response.Errors, fmt.Sprintf("failed to list node events: %v", err))
+		} else {
+			for _, event := range events.Items {
+				if event.InvolvedObject.Kind != "Node" {
+					continue
+				}
+				eventsByNode[event.InvolvedObject.Name] = append(eventsByNode[event.InvolvedObject.Name], event)
+			}
+		}
+	}
+
+	// metrics-server不可用时不返回使用量，不视为错误
+	usageByNode := make(map[string]models.NodeMetricInfo)
+	var metricsOptions []utils.MetricsOption
+	if name != "" {
+		metricsOptions = append(metricsOptions, utils.WithNodeNameFilter(name))
+	}
+	if metrics, err := utils.GetNodesMetrics(ctx, h.Client, metricsOptions...); err != nil {
+		h.Log.Debug("Node metrics unavailable", "error", err)
+	} else {
+		response.MetricsAvailable = true
+		for _, metric := range metrics {
+			usageByNode[metric.Name] = metric
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	for i := range nodes {
+		node := &nodes[i]
+		var usage *models.NodeMetricInfo
+		if metric, ok := usageByNode[node.Name]; ok {
+			usage = &metric
+		}
+		item := summarizeNodeHealth(node, allocations[node.Name], eventsByNode[node.Name], usage, maxEvents, response.RetrievedAt)
+		response.Scanned++
+		response.CountsByVerdict[item.Verdict]++
+		if unhealthyOnly && item.Verdict == models.VerdictHealthy {
+			continue
+		}
+		response.Nodes = append(response.Nodes, item)
+	}
+
+	h.Log.Info("Node health retrieved",
+		"scanned", response.Scanned,
+		"healthy", response.CountsByVerdict[models.VerdictHealthy],
+		"metricsAvailable", response.MetricsAvailable,
+	)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// summarizeNodeHealth 汇总单个节点的健康信息
+// Ready不为True、任一压力条件为True或网络不可用时判定为degraded，没有Ready条件时判定为unknown；
+// 被cordon、Pod数量或资源使用率接近上限、近期有告警事件只作为警告
+func summarizeNodeHealth(
+	node *corev1.Node,
+	allocation *utils.NodeAllocation,
+	events []corev1.Event,
+	usage *models.NodeMetricInfo,
+	maxEvents int,
+	now time.Time,
+) models.NodeHealth {
+	info := node.Status.NodeInfo
+	item := models.NodeHealth{
+		Name:          node.Name,
+		Verdict:       models.VerdictHealthy,
+		Unschedulable: node.Spec.Unschedulable,
+		Conditions:    []models.NodeConditionStatus{},
+		SystemInfo: models.NodeSystemInfo{
+			KubeletVersion:          info.KubeletVersion,
+			KernelVersion:           info.KernelVersion,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+			OSImage:                 info.OSImage,
+			OperatingSystem:         info.OperatingSystem,
+			Architecture:            info.Architecture,
+		},
+		Capacity:    resourceListStrings(node.Status.Capacity),
+		Allocatable: resourceListStrings(node.Status.Allocatable),
+		Pods:        models.NodePodCount{Capacity: node.Status.Allocatable.Pods().Value()},
+		Taints:      toModelTaints(node.Spec.Taints),
+	}
+
+	conditions := make(map[corev1.NodeConditionType]corev1.NodeCondition, len(node.Status.Conditions))
+	for _, condition := range node.Status.Conditions {
+		conditions[condition.Type] = condition
+	}
+	hasReady := false
+	for _, conditionType := range nodeHealthConditions {
+		condition, ok := conditions[conditionType]
+		if !ok {
+			continue
+		}
+		problem := false
+		switch conditionType {
+		case corev1.NodeReady:
+			hasReady = true
+			if condition.Status != corev1.ConditionTrue {
+				problem = true
+				if condition.Status == corev1.ConditionUnknown {
+					item.Reasons = append(item.Reasons, fmt.Sprintf("kubelet stopped posting node status (Ready=Unknown for %s)",
+						duration.HumanDuration(now.Sub(condition.LastTransitionTime.Time))))
+				} else {
+					item.Reasons = append(item.Reasons, fmt.Sprintf("node is not ready: %s", conditionText(condition)))
+				}
+			}
+		default:
+			if condition.Status == corev1.ConditionTrue {
+				problem = true
+				item.Reasons = append(item.Reasons, fmt.Sprintf("%s: %s", condition.Type, conditionText(condition)))
+			}
+		}
+		status := models.NodeConditionStatus{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+			Problem:            problem,
+		}
+		if !condition.LastTransitionTime.IsZero() {
+			status.Age = duration.HumanDuration(now.Sub(condition.LastTransitionTime.Time))
+		}
+		item.Conditions = append(item.Conditions, status)
+	}
+	switch {
+	case len(item.Reasons) > 0:
+		item.Verdict = models.VerdictDegraded
+	case !hasReady:
+		item.Verdict = models.VerdictUnknown
+		item.Reasons = append(item.Reasons, "node has no Ready condition")
+	}
+
+	if node.Spec.Unschedulable {
+		item.Warnings = append(item.Warnings, "node is cordoned (spec.unschedulable=true)")
+	}
+	if allocation != nil {
+		item.Requested = resourceListStrings(allocation.Requested)
+		item.Pods.Scheduled = allocation.Requested.Pods().Value()
+		delete(item.Requested, string(corev1.ResourcePods))
+	}
+	if item.Pods.Capacity > 0 && item.Pods.Scheduled*100 >= item.Pods.Capacity*nodePodCapacityWarnPercent {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("%d of %d pod slots in use", item.Pods.Scheduled, item.Pods.Capacity))
+	}
+
+	if usage != nil {
+		item.Usage = &models.NodeUsage{
+			CPU:           utils.NewCPUQuantity(usage.CPUUsage, utils.UnitTypeHuman),
+			Memory:        utils.NewByteQuantity(usage.MemoryUsageBytes, utils.UnitTypeHuman),
+			CPUPercent:    usage.CPUPercent,
+			MemoryPercent: usage.MemoryPercent,
+			Timestamp:     usage.Timestamp,
+		}
+		if usage.CPUPercent >= nodeUsageWarnPercent {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("CPU usage at %.1f%% of allocatable", usage.CPUPercent))
+		}
+		if usage.MemoryPercent >= nodeUsageWarnPercent {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("memory usage at %.1f%% of allocatable", usage.MemoryPercent))
+		}
+	}
+
+	// 事件按最近发生时间倒序，告警事件在时间窗口内按原因汇总为警告
+	sort.Slice(events, func(i, j int) bool {
+		return utils.EventLastSeen(events[i]).After(utils.EventLastSeen(events[j]))
+	})
+	warningReasons := make(map[string]bool)
+	for _, event := range events {
+		lastSeen := utils.EventLastSeen(event)
+		if event.Type == corev1.EventTypeWarning && now.Sub(lastSeen) <= nodeHealthEventWindow {
+			warningReasons[event.Reason] = true
+		}
+		if len(item.Events) < maxEvents {
+			item.Events = append(item.Events, models.NodeEvent{
+				Type:     event.Type,
+				Reason:   event.Reason,
+				Message:  event.Message,
+				Count:    event.Count,
+				LastSeen: lastSeen,
+				Age:      duration.HumanDuration(now.Sub(lastSeen)),
+			})
+		}
+	}
+	for _, reason := range sortedSet(warningReasons) {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("warning event %s in the last %s", reason, duration.HumanDuration(nodeHealthEventWindow)))
+	}
+
+	return item
+}
+
+// conditionText 返回条件的原因和消息
+func conditionText(condition corev1.NodeCondition) string {
+	switch {
+	case condition.Reason != "" && condition.Message != "":
+		return fmt.Sprintf("%s (%s)", condition.Reason, condition.Message)
+	case condition.Reason != "":
+		return condition.Reason
+	case condition.Message != "":
+		return condition.Message
+	}
+	return fmt.Sprintf("status %s", condition.Status)
+}
+
+// resourceListStrings 将资源列表转换为资源名到数量字符串的映射
+func resourceListStrings(list corev1.ResourceList) map[string]string {
+	result := make(map[string]string, len(list))
+	for name, quantity := range list {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
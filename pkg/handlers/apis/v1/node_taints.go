@@ -0,0 +1,311 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/cache"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// nodesGVR 节点资源，用于权限预检
+var nodesGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+// validTaintEffects 允许的污点效果
+var validTaintEffects = map[corev1.TaintEffect]bool{
+	corev1.TaintEffectNoSchedule:       true,
+	corev1.TaintEffectPreferNoSchedule: true,
+	corev1.TaintEffectNoExecute:        true,
+}
+
+// TaintNode 为节点添加污点，已存在相同key和effect的污点时根据overwrite替换或报错
+func (h *NodeHandlerImpl) TaintNode(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	key, _ := arguments["key"].(string)
+	value, _ := arguments["value"].(string)
+	effect, _ := arguments["effect"].(string)
+	overwrite, _ := arguments["overwrite"].(bool)
+
+	h.Log.Info("Tainting node", "name", name, "key", key, "value", value, "effect", effect, "overwrite", overwrite)
+
+	if name == "" || key == "" || effect == "" {
+		return utils.NewErrorToolResult("missing required parameters: name, key and effect"), nil
+	}
+	if message := validateTaint(key, value, corev1.TaintEffect(effect)); message != "" {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: message,
+		}), nil
+	}
+
+	if denied := h.PreflightCheck(ctx, "patch", nodesGVR, "", name); denied != nil {
+		return denied, nil
+	}
+
+	taint := corev1.Taint{Key: key, Value: value, Effect: corev1.TaintEffect(effect)}
+	response := models.NodeTaintResult{
+		Node:  name,
+		Taint: &models.Taint{Key: key, Value: value, Effect: effect},
+	}
+	var refused *models.ToolError
+	retries, err := h.patchNodeTaints(ctx, name, func(taints []corev1.Taint) ([]corev1.Taint, bool) {
+		refused = nil
+		for i, existing := range taints {
+			if existing.Key != taint.Key || existing.Effect != taint.Effect {
+				continue
+			}
+			if existing.Value == taint.Value {
+				response.Action = "unchanged"
+				return taints, false
+			}
+			if !overwrite {
+				refused = &models.ToolError{
+					Code: utils.ErrorCodeConflict,
+					Message: fmt.Sprintf("node %s already has taint %s with value %q",
+						name, formatTaint(existing), existing.Value),
+					Hint: "Pass overwrite=true to replace the existing taint value.",
+				}
+				return taints, false
+			}
+			response.Action = "replaced"
+			updated := append([]corev1.Taint(nil), taints...)
+			updated[i].Value = taint.Value
+			return updated, true
+		}
+		response.Action = "added"
+		if taint.Effect == corev1.TaintEffectNoExecute {
+			now := metav1.Now()
+			taint.TimeAdded = &now
+		}
+		return append(append([]corev1.Taint(nil), taints...), taint), true
+	}, &response)
+	if err != nil {
+		h.Log.Error("Failed to taint node", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to taint node %s", name)), nil
+	}
+	if refused != nil {
+		return utils.NewToolErrorResult(*refused), nil
+	}
+
+	return utils.WithRetryMeta(utils.RenderResult(request, response), retries), nil
+}
+
+// UntaintNode 删除节点上指定key（以及可选effect）的污点
+func (h *NodeHandlerImpl) UntaintNode(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	key, _ := arguments["key"].(string)
+	effect, _ := arguments["effect"].(string)
+
+	h.Log.Info("Removing node taint", "name", name, "key", key, "effect", effect)
+
+	if name == "" || key == "" {
+		return utils.NewErrorToolResult("missing required parameters: name and key"), nil
+	}
+	if effect != "" && !validTaintEffects[corev1.TaintEffect(effect)] {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("invalid taint effect %q, must be one of NoSchedule, PreferNoSchedule, NoExecute", effect),
+		}), nil
+	}
+
+	if denied := h.PreflightCheck(ctx, "patch", nodesGVR, "", name); denied != nil {
+		return denied, nil
+	}
+
+	response := models.NodeTaintResult{Node: name, Action: "removed"}
+	retries, err := h.patchNodeTaints(ctx, name, func(taints []corev1.Taint) ([]corev1.Taint, bool) {
+		response.Removed = nil
+		remaining := make([]corev1.Taint, 0, len(taints))
+		for _, taint := range taints {
+			if taint.Key == key && (effect == "" || string(taint.Effect) == effect) {
+				response.Removed = append(response.Removed, toModelTaint(taint))
+				continue
+			}
+			remaining = append(remaining, taint)
+		}
+		return remaining, len(response.Removed) > 0
+	}, &response)
+	if err != nil {
+		h.Log.Error("Failed to remove node taint", "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to remove taint from node %s", name)), nil
+	}
+	if len(response.Removed) == 0 {
+		target := key
+		if effect != "" {
+			target += ":" + effect
+		}
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeNotFound,
+			Message: fmt.Sprintf("node %s has no taint %s", name, target),
+			Details: response.Taints,
+		}), nil
+	}
+
+	return utils.WithRetryMeta(utils.RenderResult(request, response), retries), nil
+}
+
+// ListNodeTaints 按污点分组列出集群中的节点，同时列出被标记为不可调度的节点
+func (h *NodeHandlerImpl) ListNodeTaints(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.Log.Info("Listing node taints", "labelSelector", labelSelector)
+
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to parse label selector: %v", err)), nil
+	}
+	// 节点列表来自缓存，标签选择器在本地求值
+	allNodes, err := h.Client.ListNodes(ctx)
+	if err != nil {
+		h.Log.Error("Failed to list nodes", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list nodes"), nil
+	}
+	var nodes []corev1.Node
+	for _, node := range allNodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	response := models.NodeTaintSummary{
+		TotalNodes:  len(nodes),
+		Groups:      []models.NodeTaintGroup{},
+		RetrievedAt: time.Now(),
+	}
+	groups := make(map[string]*models.NodeTaintGroup)
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			response.Cordoned = append(response.Cordoned, node.Name)
+		}
+		if len(node.Spec.Taints) == 0 {
+			response.Untainted++
+			continue
+		}
+		for _, taint := range node.Spec.Taints {
+			groupKey := formatTaint(taint) + "=" + taint.Value
+			group, ok := groups[groupKey]
+			if !ok {
+				group = &models.NodeTaintGroup{Taint: toModelTaint(taint)}
+				groups[groupKey] = group
+			}
+			group.Nodes = append(group.Nodes, node.Name)
+			group.Count++
+		}
+	}
+	for _, group := range groups {
+		sort.Strings(group.Nodes)
+		response.Groups = append(response.Groups, *group)
+	}
+	// 影响节点最多的污点排在前面
+	sort.Slice(response.Groups, func(i, j int) bool {
+		if response.Groups[i].Count != response.Groups[j].Count {
+			return response.Groups[i].Count > response.Groups[j].Count
+		}
+		return response.Groups[i].Taint.Key < response.Groups[j].Taint.Key
+	})
+	sort.Strings(response.Cordoned)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// patchNodeTaints 读取节点污点并通过JSON补丁只替换spec.taints
+// 补丁同时写入读取时的resourceVersion，节点在读取后被并发修改时服务端返回冲突并重新读取后重试，避免覆盖其他更新；
+// mutate返回false时不发送补丁。response.Taints被设置为操作完成后节点上的污点
+func (h *NodeHandlerImpl) patchNodeTaints(
+	ctx context.Context,
+	name string,
+	mutate func([]corev1.Taint) ([]corev1.Taint, bool),
+	response *models.NodeTaintResult,
+) (int, error) {
+	nodes := h.Client.ClientSet().CoreV1().Nodes()
+	return utils.RetryOnConflict(ctx, base.GetOptions().Retry, func() error {
+		node, err := nodes.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		taints, changed := mutate(node.Spec.Taints)
+		if !changed {
+			response.Taints = toModelTaints(node.Spec.Taints)
+			return nil
+		}
+
+		patch, err := json.Marshal([]map[string]interface{}{
+			{"op": "replace", "path": "/metadata/resourceVersion", "value": node.ResourceVersion},
+			{"op": "add", "path": "/spec/taints", "value": taints},
+		})
+		if err != nil {
+			return err
+		}
+		patched, err := nodes.Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+		_, _ = h.Client.Cache().Invalidate(cache.KeyNodes)
+		response.Taints = toModelTaints(patched.Spec.Taints)
+		return nil
+	})
+}
+
+// validateTaint 校验污点的key、value和effect，合法时返回空字符串
+func validateTaint(key, value string, effect corev1.TaintEffect) string {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Sprintf("invalid taint key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if value != "" {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Sprintf("invalid taint value %q: %s", value, strings.Join(errs, "; "))
+		}
+	}
+	if !validTaintEffects[effect] {
+		return fmt.Sprintf("invalid taint effect %q, must be one of NoSchedule, PreferNoSchedule, NoExecute", effect)
+	}
+	return ""
+}
+
+// formatTaint 以kubectl的"key:effect"形式描述污点
+func formatTaint(taint corev1.Taint) string {
+	return taint.Key + ":" + string(taint.Effect)
+}
+
+// toModelTaint 将污点转换为输出模型
+func toModelTaint(taint corev1.Taint) models.Taint {
+	return models.Taint{Key: taint.Key, Value: taint.Value, Effect: string(taint.Effect)}
+}
+
+// toModelTaints 将污点列表转换为输出模型
+func toModelTaints(taints []corev1.Taint) []models.Taint {
+	result := make([]models.Taint, 0, len(taints))
+	for _, taint := range taints {
+		result = append(result, toModelTaint(taint))
+	}
+	return result
+}
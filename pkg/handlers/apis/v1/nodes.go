@@ -2,13 +2,11 @@ package v1
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
@@ -20,7 +18,11 @@ import (
 
 // 定义常量
 const (
-	LIST_NODES = "LIST_NODES"
+	LIST_NODES       = "LIST_NODES"
+	NODE_TAINT       = "NODE_TAINT"
+	NODE_UNTAINT     = "NODE_UNTAINT"
+	LIST_NODE_TAINTS = "LIST_NODE_TAINTS"
+	GET_NODE_HEALTH  = "GET_NODE_HEALTH"
 )
 
 // NodeHandlerImpl 节点处理程序实现
@@ -44,13 +46,21 @@ func (h *NodeHandlerImpl) Handle(ctx context.Context, request mcp.CallToolReques
 	switch request.Method {
 	case LIST_NODES:
 		return h.ListNodes(ctx, request)
+	case NODE_TAINT:
+		return h.TaintNode(ctx, request)
+	case NODE_UNTAINT:
+		return h.UntaintNode(ctx, request)
+	case LIST_NODE_TAINTS:
+		return h.ListNodeTaints(ctx, request)
+	case GET_NODE_HEALTH:
+		return h.GetNodeHealth(ctx, request)
 	default:
 		return utils.NewErrorToolResult(fmt.Sprintf("unknown node method: %s", request.Method)), nil
 	}
 }
 
 // Register 实现接口方法
-func (h *NodeHandlerImpl) Register(server *server.MCPServer) {
+func (h *NodeHandlerImpl) Register(server interfaces.ToolRegistrar) {
 	h.Log.Info("Registering node handlers",
 		"scope", h.Scope,
 		"apiGroup", h.Group,
@@ -69,7 +79,85 @@ func (h *NodeHandlerImpl) Register(server *server.MCPServer) {
 			mcp.Description("是否显示节点的所有标签。启用后将在输出中包含完整的标签列表，有助于标签管理和节点分类。默认为false。"),
 			mcp.DefaultBool(false),
 		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.ListNodes)
+
+	// 注册添加节点污点工具
+	server.AddTool(mcp.NewTool(NODE_TAINT,
+		mcp.WithDescription("为节点添加污点，等同于'kubectl taint nodes <name> key=value:effect'。节点上已存在相同key和effect的污点时，overwrite=true则替换其值，否则报错。只通过JSON补丁修改spec.taints，节点被并发修改时自动重新读取后重试，不会覆盖其他更新。"),
+		mcp.WithString("name",
+			mcp.Description("节点名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("key",
+			mcp.Description("污点的key，例如：'nvidia.com/gpu'、'dedicated'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("value",
+			mcp.Description("污点的value（可选）。"),
+		),
+		mcp.WithString("effect",
+			mcp.Description("污点效果：NoSchedule（不调度新Pod）、PreferNoSchedule（尽量不调度）或NoExecute（驱逐不容忍该污点的现有Pod）。"),
+			mcp.Required(),
+			mcp.Enum("NoSchedule", "PreferNoSchedule", "NoExecute"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("已存在相同key和effect的污点时是否替换其值。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.TaintNode)
+
+	// 注册删除节点污点工具
+	server.AddTool(mcp.NewTool(NODE_UNTAINT,
+		mcp.WithDescription("删除节点上的污点，等同于'kubectl taint nodes <name> key[:effect]-'。不指定effect时删除该key的所有污点。节点上不存在匹配的污点时报错并返回当前污点。"),
+		mcp.WithString("name",
+			mcp.Description("节点名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("key",
+			mcp.Description("要删除的污点key。"),
+			mcp.Required(),
+		),
+		mcp.WithString("effect",
+			mcp.Description("要删除的污点效果（可选）：NoSchedule、PreferNoSchedule或NoExecute。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.UntaintNode)
+
+	// 注册节点污点概览工具
+	server.AddTool(mcp.NewTool(LIST_NODE_TAINTS,
+		mcp.WithDescription("按污点分组列出集群中的节点，并列出被cordon（不可调度）的节点和没有污点的节点数量。用于回答\"哪些节点专用于GPU\"、\"哪些节点被cordon或打了污点\"等容量规划问题。"),
+		mcp.WithString("labelSelector",
+			mcp.Description("Kubernetes标签选择器（可选），只统计匹配的节点，例如：'node.kubernetes.io/instance-type=g4dn.xlarge'。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListNodeTaints)
+
+	// 注册节点健康检查工具
+	server.AddTool(mcp.NewTool(GET_NODE_HEALTH,
+		mcp.WithDescription("检查一个或所有节点的健康状况：Ready、MemoryPressure、DiskPressure、PIDPressure、NetworkUnavailable条件及其变化时间，kubelet、内核、容器运行时版本和操作系统镜像，容量与可分配资源，已调度Pod数与Pod容量，污点，节点近期事件（kubelet重启、镜像回收、驱逐告警等），metrics-server可用时的CPU和内存使用量，并给出健康判定（healthy、degraded或unknown）及原因。排查节点问题时应先调用此工具获取实际数据。"),
+		mcp.WithString("name",
+			mcp.Description("节点名称（可选），不指定时检查所有节点。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Kubernetes标签选择器（可选），只检查匹配的节点，不能与name同时使用。"),
+		),
+		mcp.WithBoolean("unhealthyOnly",
+			mcp.Description("是否只返回判定不是healthy的节点，统计数量仍包含所有节点。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("maxEvents",
+			mcp.Description("每个节点最多返回的近期事件数量，0表示不查询事件。默认为10，最大为50。"),
+			mcp.DefaultNumber(defaultNodeHealthEvents),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetNodeHealth)
 }
 
 // ListNodes 列出所有节点
@@ -79,20 +167,17 @@ func (h *NodeHandlerImpl) ListNodes(
 ) (*mcp.CallToolResult, error) {
 	h.Log.Info("Listing nodes")
 
-	// 创建节点列表
-	nodes := &corev1.NodeList{}
-
-	// 获取所有节点
-	err := h.Client.List(ctx, nodes)
+	// 获取所有节点，在缓存有效期内复用上一次的结果
+	nodes, err := h.Client.ListNodes(ctx)
 	if err != nil {
 		h.Log.Error("Failed to list nodes", "error", err)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to list nodes: %v", err)), nil
+		return utils.NewKubeErrorResult(err, "failed to list nodes"), nil
 	}
 
 	// 构建JSON响应
-	nodeInfos := make([]models.NodeInfo, 0, len(nodes.Items))
+	nodeInfos := make([]models.NodeInfo, 0, len(nodes))
 
-	for _, node := range nodes.Items {
+	for _, node := range nodes {
 		// 获取节点状态
 		var status string
 		for _, condition := range node.Status.Conditions {
@@ -178,19 +263,8 @@ func (h *NodeHandlerImpl) ListNodes(
 	}
 
 	// 序列化为JSON
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON序列化失败: %v", err)), nil
-	}
 
-	h.Log.Info("Nodes listed successfully", "count", len(nodes.Items))
+	h.Log.Info("Nodes listed successfully", "count", len(nodes))
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, response), nil
 }
@@ -0,0 +1,324 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 模拟放置的默认副本数和最大副本数
+	defaultScheduleReplicas = 1
+	maxScheduleReplicas     = 10000
+	// scheduleCapacityNote 调度容量模拟的近似说明
+	scheduleCapacityNote = "近似模拟：只考虑节点可分配资源减去现有Pod的请求、污点与容忍、nodeSelector和必需的节点亲和性以及每个节点的最大Pod数；" +
+		"不考虑Pod亲和性/反亲和性、拓扑分布约束、主机端口、卷拓扑、抢占和调度打分，副本按剩余容量最多的节点依次放置，实际调度结果可能不同"
+)
+
+// blockerConstraints 节点排除原因与约束类别的对应关系
+var blockerConstraints = map[string]string{
+	schedulingNodeAffinity:  models.CapacityConstraintSelectors,
+	schedulingTaint:         models.CapacityConstraintTaints,
+	schedulingUnschedulable: models.CapacityConstraintNodes,
+	schedulingNodeNotReady:  models.CapacityConstraintNodes,
+}
+
+// CanSchedule 模拟集群能否再容纳N个副本
+// Pod规格来自工作负载的Pod模板或内联的资源请求、nodeSelector和容忍，按节点实时的剩余可分配资源逐个放置副本
+func (h *ResourceHandlerImpl) CanSchedule(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	replicas := defaultScheduleReplicas
+	if value, ok := arguments["replicas"].(float64); ok && value > 0 {
+		replicas = min(int(value), maxScheduleReplicas)
+	}
+
+	h.handler.Log.Info("Simulating scheduling capacity", "kind", kind, "name", name, "namespace", namespace, "replicas", replicas)
+
+	var pod *corev1.Pod
+	var source string
+	var err error
+	if kind != "" {
+		if name == "" {
+			return utils.NewErrorToolResult("name is required when kind is given"), nil
+		}
+		source = fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+		pod, err = h.workloadPod(ctx, kind, namespace, name)
+		if err != nil {
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+		}
+	} else {
+		source = "inline"
+		pod, err = inlinePod(arguments)
+		if err != nil {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: err.Error(),
+				Hint:    "Pass kind and name to use a workload's pod template, or cpu/memory requests with optional nodeSelector and tolerations.",
+			}), nil
+		}
+	}
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	nodes, err := coreClient.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list nodes", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list nodes"), nil
+	}
+	scheduled, err := coreClient.Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed,spec.nodeName!=",
+	})
+	if err != nil {
+		h.handler.Log.Error("Failed to list scheduled pods", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+	allocations := utils.ComputeNodeAllocations(nodes.Items, scheduled.Items)
+
+	report := simulatePlacement(pod, allocations, replicas)
+	report.Source = source
+	report.RetrievedAt = time.Now()
+	return utils.RenderResult(request, report), nil
+}
+
+// workloadPod 返回工作负载Pod模板对应的Pod
+func (h *ResourceHandlerImpl) workloadPod(ctx context.Context, kind, namespace, name string) (*corev1.Pod, error) {
+	clientset := h.handler.Client.ClientSet()
+	var template corev1.PodTemplateSpec
+	switch kind {
+	case "Deployment":
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		template = deployment.Spec.Template
+	case "StatefulSet":
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		template = statefulSet.Spec.Template
+	case "ReplicaSet":
+		replicaSet, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		template = replicaSet.Spec.Template
+	case "Job":
+		job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		template = job.Spec.Template
+	case "CronJob":
+		cronJob, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		template = cronJob.Spec.JobTemplate.Spec.Template
+	case "Pod":
+		return clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("unsupported kind %q: must be one of Deployment, StatefulSet, ReplicaSet, Job, CronJob, Pod", kind)
+	}
+	return &corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}, nil
+}
+
+// inlinePod 根据内联的cpu、memory请求以及nodeSelector和tolerations构造Pod
+func inlinePod(arguments map[string]interface{}) (*corev1.Pod, error) {
+	requests := corev1.ResourceList{}
+	for argument, resourceName := range map[string]corev1.ResourceName{"cpu": corev1.ResourceCPU, "memory": corev1.ResourceMemory} {
+		value, _ := arguments[argument].(string)
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s request %q: %v", argument, value, err)
+		}
+		requests[resourceName] = quantity
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("either kind and name or at least one of cpu and memory is required")
+	}
+
+	nodeSelector, err := utils.StringMapArgument(arguments, "nodeSelector")
+	if err != nil {
+		return nil, err
+	}
+	var tolerations []corev1.Toleration
+	rawTolerations, _ := arguments["tolerations"].([]interface{})
+	for i, raw := range rawTolerations {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("tolerations[%d] must be an object", i)
+		}
+		var toleration corev1.Toleration
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item, &toleration); err != nil {
+			return nil, fmt.Errorf("tolerations[%d]: %v", i, err)
+		}
+		tolerations = append(tolerations, toleration)
+	}
+
+	return &corev1.Pod{Spec: corev1.PodSpec{
+		Containers:   []corev1.Container{{Name: "inline", Resources: corev1.ResourceRequirements{Requests: requests}}},
+		NodeSelector: nodeSelector,
+		Tolerations:  tolerations,
+	}}, nil
+}
+
+// simulatePlacement 计算每个可用节点能容纳的副本数，并把副本依次放到剩余容量最多的节点上
+func simulatePlacement(pod *corev1.Pod, allocations map[string]*utils.NodeAllocation, replicas int) models.ScheduleCapacityReport {
+	requests := utils.PodRequests(pod)
+	report := models.ScheduleCapacityReport{
+		Requests: make(map[string]string, len(requests)),
+		Replicas: replicas,
+		Nodes:    []models.NodeCapacity{},
+		Note:     scheduleCapacityNote,
+	}
+	for resourceName, quantity := range requests {
+		report.Requests[string(resourceName)] = utils.FormatResourceQuantity(resourceName, quantity)
+	}
+
+	nodeNames := make([]string, 0, len(allocations))
+	for nodeName := range allocations {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	blockerCounts := make(map[string]int)
+	limitCounts := make(map[string]int)
+	var eligible []*utils.NodeAllocation
+	var capacities []int
+	for _, nodeName := range nodeNames {
+		allocation := allocations[nodeName]
+		blockers, untolerated := nodeBlockers(pod, allocation.Node)
+		if len(blockers) > 0 {
+			report.ExcludedNodes = append(report.ExcludedNodes, models.ExcludedNode{
+				Node:        nodeName,
+				Reasons:     blockers,
+				Untolerated: untolerated,
+			})
+			for _, blocker := range blockers {
+				blockerCounts[blockerConstraints[blocker]]++
+			}
+			continue
+		}
+		capacity, limitedBy := replicasThatFit(requests, allocation)
+		eligible = append(eligible, allocation)
+		capacities = append(capacities, capacity)
+		limitCounts[limitedBy]++
+		report.MaxReplicas += capacity
+		report.Nodes = append(report.Nodes, models.NodeCapacity{Node: nodeName, Capacity: capacity, LimitedBy: limitedBy})
+	}
+	report.EligibleNodes = len(eligible)
+
+	// 每次放到剩余容量最多的节点上，近似调度器的LeastAllocated打分
+	for placed := 0; placed < replicas; placed++ {
+		best := -1
+		for i := range report.Nodes {
+			remaining := capacities[i] - report.Nodes[i].Placed
+			if remaining > 0 && (best < 0 || remaining > capacities[best]-report.Nodes[best].Placed) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		report.Nodes[best].Placed++
+	}
+	for i := range report.Nodes {
+		report.Nodes[i].RemainingAfter = remainingAfter(requests, eligible[i], report.Nodes[i].Placed)
+	}
+	report.Fits = report.MaxReplicas >= replicas
+
+	switch {
+	case len(eligible) == 0 && len(blockerCounts) > 0:
+		report.BindingConstraint = mostCommon(blockerCounts)
+	case len(eligible) == 0:
+		report.BindingConstraint = models.CapacityConstraintNone
+	default:
+		report.BindingConstraint = mostCommon(limitCounts)
+	}
+
+	sort.SliceStable(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Placed > report.Nodes[j].Placed
+	})
+	return report
+}
+
+// replicasThatFit 返回节点还能容纳的副本数以及限制副本数的资源
+func replicasThatFit(requests corev1.ResourceList, allocation *utils.NodeAllocation) (int, string) {
+	podsFree := allocation.Free(corev1.ResourcePods)
+	capacity := max(podsFree.Value(), 0)
+	limitedBy := models.CapacityConstraintPods
+
+	names := make([]string, 0, len(requests))
+	for resourceName := range requests {
+		names = append(names, string(resourceName))
+	}
+	sort.Strings(names)
+	for _, resourceName := range names {
+		request := requests[corev1.ResourceName(resourceName)]
+		if request.IsZero() {
+			continue
+		}
+		free := allocation.Free(corev1.ResourceName(resourceName))
+		fit := int64(0)
+		if free.Sign() > 0 {
+			fit = free.MilliValue() / request.MilliValue()
+		}
+		if fit < capacity {
+			capacity = fit
+			limitedBy = resourceName
+		}
+	}
+	return int(capacity), limitedBy
+}
+
+// remainingAfter 返回节点放置placed个副本后剩余的cpu、memory、pods以及Pod请求的其他资源
+func remainingAfter(requests corev1.ResourceList, allocation *utils.NodeAllocation, placed int) map[string]string {
+	names := map[corev1.ResourceName]bool{corev1.ResourceCPU: true, corev1.ResourceMemory: true, corev1.ResourcePods: true}
+	for resourceName := range requests {
+		names[resourceName] = true
+	}
+	remaining := make(map[string]string, len(names))
+	for resourceName := range names {
+		free := allocation.Free(resourceName)
+		request := podSlot()
+		if resourceName != corev1.ResourcePods {
+			request = requests[resourceName]
+		}
+		for i := 0; i < placed; i++ {
+			free.Sub(request)
+		}
+		remaining[string(resourceName)] = utils.FormatResourceQuantity(resourceName, free)
+	}
+	return remaining
+}
+
+// mostCommon 返回计数最大的键，计数相同时按键名排序
+func mostCommon(counts map[string]int) string {
+	best := ""
+	for key, count := range counts {
+		if best == "" || count > counts[best] || (count == counts[best] && key < best) {
+			best = key
+		}
+	}
+	return best
+}
@@ -0,0 +1,213 @@
+package v1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// isSidecarContainer 判断初始化容器是否为原生边车容器（restartPolicy为Always）
+func isSidecarContainer(container corev1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// sidecarContainerNames 返回Pod中原生边车容器的名称
+func sidecarContainerNames(pod *corev1.Pod) map[string]bool {
+	names := make(map[string]bool)
+	for _, container := range pod.Spec.InitContainers {
+		if isSidecarContainer(container) {
+			names[container.Name] = true
+		}
+	}
+	return names
+}
+
+// injectedSidecarNames 服务网格、密钥代理等注入到主容器列表中的常见边车容器，自动选择日志容器时跳过
+var injectedSidecarNames = map[string]bool{
+	"istio-proxy":   true,
+	"linkerd-proxy": true,
+	"vault-agent":   true,
+	"daprd":         true,
+}
+
+// appContainerNames 返回主容器中除注入的边车容器以外的应用容器；全部是边车容器时返回所有主容器
+func appContainerNames(pod *corev1.Pod) []string {
+	var apps, all []string
+	for _, container := range pod.Spec.Containers {
+		all = append(all, container.Name)
+		if !injectedSidecarNames[container.Name] {
+			apps = append(apps, container.Name)
+		}
+	}
+	if len(apps) == 0 {
+		return all
+	}
+	return apps
+}
+
+// podContainerRole 返回容器在Pod中的角色，容器不存在时返回空字符串
+func podContainerRole(pod *corev1.Pod, name string) string {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == name {
+			if isSidecarContainer(container) {
+				return models.ContainerRoleSidecar
+			}
+			return models.ContainerRoleInit
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return models.ContainerRoleMain
+		}
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		if container.Name == name {
+			return models.ContainerRoleEphemeral
+		}
+	}
+	return ""
+}
+
+// podContainerSummaries 按初始化容器、主容器、临时容器的顺序返回Pod中所有容器的角色和状态
+func podContainerSummaries(pod *corev1.Pod) []models.ContainerStatusSummary {
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, list := range [][]corev1.ContainerStatus{
+		pod.Status.InitContainerStatuses,
+		pod.Status.ContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	} {
+		for _, status := range list {
+			statuses[status.Name] = status
+		}
+	}
+
+	var names []string
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		names = append(names, container.Name)
+	}
+
+	summaries := make([]models.ContainerStatusSummary, 0, len(names))
+	for _, name := range names {
+		summary := models.ContainerStatusSummary{
+			Name:  name,
+			Role:  podContainerRole(pod, name),
+			State: "NotStarted",
+		}
+		if status, ok := statuses[name]; ok {
+			summary.State, summary.Reason = containerStateReason(status.State)
+			summary.Ready = status.Ready
+			summary.RestartCount = status.RestartCount
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// describeContainers 将容器列表格式化为"name (role)"的形式，用于错误提示
+func describeContainers(summaries []models.ContainerStatusSummary) string {
+	parts := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		parts = append(parts, fmt.Sprintf("%s (%s)", summary.Name, summary.Role))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// containerStateReason 返回容器状态名称和原因
+func containerStateReason(state corev1.ContainerState) (string, string) {
+	switch {
+	case state.Running != nil:
+		return "Running", ""
+	case state.Waiting != nil:
+		return "Waiting", state.Waiting.Reason
+	case state.Terminated != nil:
+		return "Terminated", state.Terminated.Reason
+	}
+	return "Unknown", ""
+}
+
+// podInitProgress 返回Pod的初始化进度，状态格式与kubectl get pods一致。
+// Pod已完成初始化、尚未调度或已成功结束时返回nil
+func podInitProgress(pod *corev1.Pod) *models.InitContainerProgress {
+	total := len(pod.Spec.InitContainers)
+	if total == 0 || pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded {
+		return nil
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodInitialized && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for _, status := range pod.Status.InitContainerStatuses {
+		statuses[status.Name] = status
+	}
+
+	for i, container := range pod.Spec.InitContainers {
+		sidecar := isSidecarContainer(container)
+		status, hasStatus := statuses[container.Name]
+		if hasStatus {
+			terminated := status.State.Terminated
+			// 普通初始化容器成功退出、边车容器已启动都视为完成
+			if !sidecar && terminated != nil && terminated.ExitCode == 0 {
+				continue
+			}
+			if sidecar && status.Started != nil && *status.Started {
+				continue
+			}
+		}
+
+		progress := &models.InitContainerProgress{
+			Status:          fmt.Sprintf("Init:%d/%d", i, total),
+			Completed:       i,
+			Total:           total,
+			Blocking:        container.Name,
+			BlockingSidecar: sidecar,
+			State:           "NotStarted",
+		}
+		if !hasStatus {
+			return progress
+		}
+		progress.State, progress.Reason = containerStateReason(status.State)
+		progress.RestartCount = status.RestartCount
+		switch {
+		case status.State.Terminated != nil:
+			terminated := status.State.Terminated
+			exitCode := terminated.ExitCode
+			progress.ExitCode = &exitCode
+			progress.Message = terminated.Message
+			switch {
+			case terminated.Reason != "":
+				progress.Status = "Init:" + terminated.Reason
+			case terminated.Signal != 0:
+				progress.Status = fmt.Sprintf("Init:Signal:%d", terminated.Signal)
+			default:
+				progress.Status = fmt.Sprintf("Init:ExitCode:%d", terminated.ExitCode)
+			}
+		case status.State.Waiting != nil:
+			progress.Message = status.State.Waiting.Message
+			if reason := status.State.Waiting.Reason; reason != "" && reason != "PodInitializing" {
+				progress.Status = "Init:" + reason
+			}
+		}
+		if progress.ExitCode == nil {
+			if last := status.LastTerminationState.Terminated; last != nil {
+				exitCode := last.ExitCode
+				progress.ExitCode = &exitCode
+			}
+		}
+		return progress
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// initPod 返回一个已调度的Pod：初始化容器migrate，边车容器proxy，主容器web
+func initPod(initStatuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: testutil.DefaultNamespace},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			InitContainers: []corev1.Container{
+				{Name: "migrate", Image: "migrate:1"},
+				{Name: "proxy", Image: "envoy:1", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+			},
+			Containers:          []corev1.Container{{Name: "web", Image: "web:1"}},
+			EphemeralContainers: []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger"}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: initStatuses},
+	}
+}
+
+func terminated(name string, exitCode int32, reason string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason}}}
+}
+
+func TestPodContainerSummaries(t *testing.T) {
+	pod := initPod(terminated("migrate", 0, "Completed"))
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "web", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}}
+
+	var got []string
+	for _, summary := range podContainerSummaries(pod) {
+		got = append(got, summary.Name+"/"+summary.Role+"/"+summary.State)
+	}
+	want := []string{"migrate/init/Terminated", "proxy/sidecar/NotStarted", "web/main/Running", "debugger/ephemeral/NotStarted"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("summaries = %v, want %v", got, want)
+	}
+	if role := podContainerRole(pod, "missing"); role != "" {
+		t.Fatalf("role of a missing container = %q", role)
+	}
+}
+
+func TestAppContainerNames(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "istio-proxy"}, {Name: "web"}}}}
+	if names := appContainerNames(pod); !slices.Equal(names, []string{"web"}) {
+		t.Fatalf("app containers = %v, want injected sidecars skipped", names)
+	}
+	pod.Spec.Containers = pod.Spec.Containers[:1]
+	if names := appContainerNames(pod); !slices.Equal(names, []string{"istio-proxy"}) {
+		t.Fatalf("app containers = %v, want all containers when every one is a sidecar", names)
+	}
+}
+
+func TestPodInitProgress(t *testing.T) {
+	crashing := terminated("migrate", 1, "Error")
+	crashing.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s"}}
+	crashing.LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+	crashing.RestartCount = 4
+	sidecarStarting := corev1.ContainerStatus{Name: "proxy", Started: ptr.To(false), State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		status   string
+		blocking string
+		sidecar  bool
+		exitCode *int32
+	}{
+		{name: "not started", pod: initPod(), status: "Init:0/2", blocking: "migrate"},
+		{name: "crash looping", pod: initPod(crashing), status: "Init:CrashLoopBackOff", blocking: "migrate", exitCode: ptr.To[int32](1)},
+		{name: "failed", pod: initPod(terminated("migrate", 2, "")), status: "Init:ExitCode:2", blocking: "migrate", exitCode: ptr.To[int32](2)},
+		{name: "sidecar starting", pod: initPod(terminated("migrate", 0, "Completed"), sidecarStarting), status: "Init:1/2", blocking: "proxy", sidecar: true},
+		{name: "sidecar started", pod: initPod(terminated("migrate", 0, "Completed"), corev1.ContainerStatus{Name: "proxy", Started: ptr.To(true)})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := podInitProgress(tt.pod)
+			if tt.status == "" {
+				if progress != nil {
+					t.Fatalf("progress = %+v, want initialization complete", progress)
+				}
+				return
+			}
+			if progress == nil {
+				t.Fatal("no init progress")
+			}
+			if progress.Status != tt.status || progress.Blocking != tt.blocking || progress.BlockingSidecar != tt.sidecar || progress.Total != 2 {
+				t.Fatalf("progress = %+v, want %s blocked by %s", progress, tt.status, tt.blocking)
+			}
+			if (progress.ExitCode == nil) != (tt.exitCode == nil) || (tt.exitCode != nil && *progress.ExitCode != *tt.exitCode) {
+				t.Fatalf("exit code = %v, want %v", progress.ExitCode, tt.exitCode)
+			}
+		})
+	}
+
+	unscheduled := initPod()
+	unscheduled.Spec.NodeName = ""
+	initialized := initPod()
+	initialized.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodInitialized, Status: corev1.ConditionTrue}}
+	for _, pod := range []*corev1.Pod{unscheduled, initialized} {
+		if progress := podInitProgress(pod); progress != nil {
+			t.Fatalf("progress = %+v, want nil for unscheduled or initialized pods", progress)
+		}
+	}
+}
+
+func callPodTool(t *testing.T, pod *corev1.Pod, tool string, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	result, err := NewResourceHandler(testutil.NewFakeClient(pod)).Handle(context.Background(), testutil.NewToolRequest(tool, arguments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestGetPodLogsInitContainers(t *testing.T) {
+	crashing := terminated("migrate", 1, "Error")
+	pod := initPod(crashing)
+
+	var response models.PodLogsResponse
+	if err := testutil.DecodeResult(callPodTool(t, pod, GET_POD_LOGS, map[string]any{"name": "web-1"}), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Container != "migrate" || response.ContainerRole != models.ContainerRoleInit || !strings.Contains(response.Note, "Init:Error") {
+		t.Fatalf("container %s (%s), note %q; want the blocking init container", response.Container, response.ContainerRole, response.Note)
+	}
+	if len(response.AvailableContainers) != 4 {
+		t.Fatalf("available containers = %+v", response.AvailableContainers)
+	}
+
+	if err := testutil.DecodeResult(callPodTool(t, pod, GET_POD_LOGS, map[string]any{"name": "web-1", "container": "proxy"}), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.ContainerRole != models.ContainerRoleSidecar {
+		t.Fatalf("role of proxy = %s, want sidecar", response.ContainerRole)
+	}
+
+	toolErr, err := testutil.DecodeToolError(callPodTool(t, pod, GET_POD_LOGS, map[string]any{"name": "web-1", "container": "proxi"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Code != utils.ErrorCodeNotFound || !strings.Contains(toolErr.Hint, `"proxy"`) || !strings.Contains(toolErr.Hint, "migrate (init)") {
+		t.Fatalf("error = %+v, want a suggestion and the container roles", toolErr)
+	}
+}
+
+func TestDiagnosePodInitContainers(t *testing.T) {
+	var report models.PodDiagnosisReport
+	if err := testutil.DecodeResult(callPodTool(t, initPod(terminated("migrate", 1, "Error")), DIAGNOSE_POD, map[string]any{"name": "web-1"}), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.InitProgress == nil || report.InitProgress.Status != "Init:Error" || report.InitProgress.Blocking != "migrate" {
+		t.Fatalf("init progress = %+v", report.InitProgress)
+	}
+	var initContainers []string
+	for _, container := range report.InitContainers {
+		initContainers = append(initContainers, container.Name)
+		if container.Name == "proxy" && !container.Sidecar {
+			t.Error("proxy is not marked as a sidecar")
+		}
+	}
+	if !slices.Equal(initContainers, []string{"migrate", "proxy"}) || len(report.Containers) != 1 {
+		t.Fatalf("init containers %v, containers %+v", initContainers, report.Containers)
+	}
+	if len(report.ProbableCauses) == 0 || report.ProbableCauses[0].Container != "migrate" {
+		t.Fatalf("probable causes = %+v, want the blocking init container first", report.ProbableCauses)
+	}
+}
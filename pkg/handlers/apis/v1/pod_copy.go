@@ -0,0 +1,266 @@
+package v1
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// defaultCopyFileMode 未指定mode时上传文件的权限
+	defaultCopyFileMode = 0o644
+	// tarOverheadBytes 单个文件的tar归档在文件内容之外的头部和填充
+	tarOverheadBytes = 64 * 1024
+)
+
+// CopyToPod 将base64编码的内容作为单个文件写入容器，与kubectl cp相同，通过exec会话向tar xf -发送tar归档
+func (h *ResourceHandlerImpl) CopyToPod(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	container, _ := arguments["container"].(string)
+	remotePath, _ := arguments["remotePath"].(string)
+	encoded, _ := arguments["content"].(string)
+	modeArg, _ := arguments["mode"].(string)
+	maxBytes := base.GetOptions().MaxCopyBytes
+
+	h.handler.Log.Info("Copying file to pod",
+		"name", name,
+		"namespace", namespace,
+		"container", container,
+		"remotePath", remotePath,
+		"encodedBytes", len(encoded),
+		"mode", modeArg,
+	)
+
+	if err := validateCopyPath(remotePath); err != nil {
+		return utils.NewToolErrorResult(models.ToolError{Code: utils.ErrorCodeInvalid, Message: err.Error()}), nil
+	}
+	remotePath = path.Clean(remotePath)
+	// 先按编码长度估算，避免解码明显超限的内容
+	if base64.StdEncoding.DecodedLen(len(encoded)) > maxBytes+2 {
+		return copySizeExceeded(base64.StdEncoding.DecodedLen(len(encoded)), maxBytes), nil
+	}
+	content, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("content is not valid base64: %v", err),
+		}), nil
+	}
+	if len(content) > maxBytes {
+		return copySizeExceeded(len(content), maxBytes), nil
+	}
+	mode := int64(defaultCopyFileMode)
+	if modeArg != "" {
+		mode, err = strconv.ParseInt(modeArg, 8, 32)
+		if err != nil || mode < 0 || mode > 0o7777 {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: fmt.Sprintf("mode %q must be an octal permission such as 0644 or 0755", modeArg),
+			}), nil
+		}
+	}
+
+	container, errResult := h.execPreflight(ctx, namespace, name, container)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	archive, err := singleFileTar(path.Base(remotePath), content, mode)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to build tar archive: %v", err)), nil
+	}
+	// tar在目标目录中解出文件，已存在的同名文件会被替换
+	result, err := h.execInContainer(ctx, namespace, name, container,
+		[]string{"tar", "xf", "-", "-C", path.Dir(remotePath)}, archive, maxExecStderrBytes)
+	if err != nil {
+		return execErrorResult(err, name, container, "tar"), nil
+	}
+	if result.exitCode != 0 {
+		return execCommandFailed(result, remotePath), nil
+	}
+
+	return utils.RenderResult(request, models.PodFileUpload{
+		Pod:         name,
+		Namespace:   namespace,
+		Container:   container,
+		Path:        remotePath,
+		Bytes:       len(content),
+		Mode:        fmt.Sprintf("%04o", mode),
+		UsedBusybox: result.usedBusybox,
+	}), nil
+}
+
+// CopyFromPod 从容器中复制单个文件，通过exec会话读取tar cf -输出的归档，内容以base64返回
+func (h *ResourceHandlerImpl) CopyFromPod(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	container, _ := arguments["container"].(string)
+	remotePath, _ := arguments["remotePath"].(string)
+	maxBytes := base.GetOptions().MaxCopyBytes
+
+	h.handler.Log.Info("Copying file from pod",
+		"name", name,
+		"namespace", namespace,
+		"container", container,
+		"remotePath", remotePath,
+	)
+
+	if err := validateCopyPath(remotePath); err != nil {
+		return utils.NewToolErrorResult(models.ToolError{Code: utils.ErrorCodeInvalid, Message: err.Error()}), nil
+	}
+	remotePath = path.Clean(remotePath)
+	container, errResult := h.execPreflight(ctx, namespace, name, container)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// 先检查文件类型和大小，避免通过exec会话传输目录或超过上限的大文件
+	listed, err := h.execInContainer(ctx, namespace, name, container, []string{"ls", "-ld", "--", remotePath}, nil, listFilesOutputLimit)
+	if err != nil {
+		return execErrorResult(err, name, container, "ls"), nil
+	}
+	if listed.exitCode != 0 {
+		return execCommandFailed(listed, remotePath), nil
+	}
+	if entry, ok := parseLsLine(strings.TrimSpace(string(listed.stdout))); ok {
+		if entry.Type != "file" {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: fmt.Sprintf("%s is a %s; only regular files can be copied", remotePath, entry.Type),
+				Hint:    "Use LIST_POD_FILES to find the files inside a directory and copy them one by one.",
+			}), nil
+		}
+		if entry.Size > int64(maxBytes) {
+			return copySizeExceeded(int(entry.Size), maxBytes), nil
+		}
+	}
+
+	result, err := h.execInContainer(ctx, namespace, name, container,
+		[]string{"tar", "cf", "-", "-C", path.Dir(remotePath), path.Base(remotePath)}, nil, maxBytes+tarOverheadBytes)
+	if err != nil {
+		return execErrorResult(err, name, container, "tar"), nil
+	}
+	if result.exitCode != 0 {
+		return execCommandFailed(result, remotePath), nil
+	}
+	if result.truncated {
+		return copySizeExceeded(len(result.stdout), maxBytes), nil
+	}
+
+	header, content, err := readSingleFileTar(result.stdout, maxBytes)
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("failed to read %s from the tar stream: %v", remotePath, err),
+		}), nil
+	}
+
+	return utils.RenderResult(request, models.PodFileDownload{
+		Pod:         name,
+		Namespace:   namespace,
+		Container:   container,
+		Path:        remotePath,
+		Bytes:       len(content),
+		Mode:        fmt.Sprintf("%04o", header.Mode&0o7777),
+		Modified:    header.ModTime,
+		Encoding:    "base64",
+		Content:     base64.StdEncoding.EncodeToString(content),
+		UsedBusybox: result.usedBusybox,
+	}), nil
+}
+
+// validateCopyPath 校验容器中的文件路径，不能是根目录
+func validateCopyPath(remotePath string) error {
+	if remotePath == "" {
+		return fmt.Errorf("missing required parameter: remotePath")
+	}
+	if err := validateContainerPath(remotePath); err != nil {
+		return err
+	}
+	if path.Clean(remotePath) == "/" {
+		return fmt.Errorf("remotePath must name a file, not /")
+	}
+	return nil
+}
+
+// copySizeExceeded 返回文件超过复制上限的错误
+func copySizeExceeded(size, maxBytes int) *mcp.CallToolResult {
+	return utils.NewToolErrorResult(models.ToolError{
+		Code:    utils.ErrorCodeRefused,
+		Message: fmt.Sprintf("file is %d bytes, larger than the copy limit of %d bytes", size, maxBytes),
+		Hint:    "Use READ_POD_FILE to read the beginning of the file, or raise --max-copy-bytes on the server.",
+	})
+}
+
+// singleFileTar 构建只包含一个文件的tar归档
+func singleFileTar(name string, content []byte, mode int64) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     mode,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+	}
+	if err := writer.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readSingleFileTar 读取tar归档中的第一个普通文件，文件超过maxBytes时返回错误
+func readSingleFileTar(data []byte, maxBytes int) (*tar.Header, []byte, error) {
+	reader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("archive contains no regular file")
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > int64(maxBytes) {
+			return nil, nil, fmt.Errorf("file is %d bytes, larger than the copy limit of %d bytes", header.Size, maxBytes)
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		return header, content, nil
+	}
+}
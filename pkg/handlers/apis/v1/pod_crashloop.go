@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认的重启次数阈值
+	defaultCrashLoopRestartThreshold = 3
+	// 默认返回的最大容器数量
+	defaultCrashLoopMaxResults = 50
+	// 附带日志时默认的容器数量
+	defaultCrashLoopLogsTopN = 3
+	// 附带日志的容器数量上限
+	maxCrashLoopLogsTopN = 10
+	// 每个容器附带的上一个实例日志行数
+	crashLoopLogsTailLines = 20
+)
+
+// crashLoopWaitingReasons 表示容器无法正常运行的等待原因
+var crashLoopWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"CreateContainerConfigError": true,
+}
+
+// FindCrashLoopingPods 查找重启次数超过阈值或处于异常等待状态的容器，按重启次数降序排列
+func (h *ResourceHandlerImpl) FindCrashLoopingPods(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	includeLogsTail, _ := arguments["includeLogsTail"].(bool)
+	restartThreshold := int32(defaultCrashLoopRestartThreshold)
+	if v, ok := arguments["restartThreshold"].(float64); ok && v >= 0 {
+		restartThreshold = int32(v)
+	}
+	maxResults := defaultCrashLoopMaxResults
+	if v, ok := arguments["maxResults"].(float64); ok && v > 0 {
+		maxResults = int(v)
+	}
+	logsTopN := defaultCrashLoopLogsTopN
+	if v, ok := arguments["logsTopN"].(float64); ok && v > 0 {
+		logsTopN = min(int(v), maxCrashLoopLogsTopN)
+	}
+
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
+	}
+
+	reqLogger := h.handler.Log.With("namespace", namespace, "labelSelector", labelSelector)
+	reqLogger.Info("Finding crash looping pods", "restartThreshold", restartThreshold, "includeLogsTail", includeLogsTail)
+
+	pods, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		reqLogger.Error("Failed to list pods", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+
+	report := models.CrashLoopReport{
+		Namespace:        namespace,
+		LabelSelector:    labelSelector,
+		RestartThreshold: restartThreshold,
+		ScannedPods:      len(pods.Items),
+		Containers:       []models.CrashLoopContainer{},
+		RetrievedAt:      time.Now().In(location),
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		report.Containers = append(report.Containers, crashLoopContainers(pod, pod.Status.InitContainerStatuses, true, restartThreshold)...)
+		report.Containers = append(report.Containers, crashLoopContainers(pod, pod.Status.ContainerStatuses, false, restartThreshold)...)
+	}
+
+	sort.SliceStable(report.Containers, func(i, j int) bool {
+		a, b := report.Containers[i], report.Containers[j]
+		if a.RestartCount != b.RestartCount {
+			return a.RestartCount > b.RestartCount
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Pod < b.Pod
+	})
+	report.Count = len(report.Containers)
+	for i := range report.Containers {
+		if lastRestart := report.Containers[i].LastRestartAt; lastRestart != nil {
+			*lastRestart = lastRestart.In(location)
+		}
+	}
+	if len(report.Containers) > maxResults {
+		report.Containers = report.Containers[:maxResults]
+		report.Truncated = true
+	}
+
+	// 只为最严重的若干个容器读取上一个实例的日志，控制响应大小
+	if includeLogsTail {
+		for i := range report.Containers[:min(logsTopN, len(report.Containers))] {
+			entry := &report.Containers[i]
+			if entry.RestartCount == 0 {
+				continue
+			}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: entry.Pod, Namespace: entry.Namespace}}
+			logs, err := h.readContainerLogs(ctx, pod, entry.Container, true, crashLoopLogsTailLines)
+			if err != nil {
+				entry.LogError = err.Error()
+				continue
+			}
+			entry.PreviousLogs = logs
+		}
+	}
+
+	reqLogger.Info("Crash looping pods found", "count", report.Count, "scannedPods", report.ScannedPods)
+
+	return utils.RenderResult(request, report), nil
+}
+
+// crashLoopContainers 返回Pod中重启次数达到阈值或处于异常等待状态的容器
+func crashLoopContainers(
+	pod *corev1.Pod,
+	statuses []corev1.ContainerStatus,
+	init bool,
+	restartThreshold int32,
+) []models.CrashLoopContainer {
+	var result []models.CrashLoopContainer
+	var sidecars map[string]bool
+	if init {
+		sidecars = sidecarContainerNames(pod)
+	}
+	for _, status := range statuses {
+		var waitingReason, waitingMessage string
+		if status.State.Waiting != nil {
+			waitingReason = status.State.Waiting.Reason
+			waitingMessage = status.State.Waiting.Message
+		}
+		overThreshold := restartThreshold > 0 && status.RestartCount >= restartThreshold
+		if !overThreshold && !crashLoopWaitingReasons[waitingReason] {
+			continue
+		}
+
+		entry := models.CrashLoopContainer{
+			Pod:            pod.Name,
+			Namespace:      pod.Namespace,
+			Container:      status.Name,
+			Init:           init,
+			Sidecar:        sidecars[status.Name],
+			NodeName:       pod.Spec.NodeName,
+			RestartCount:   status.RestartCount,
+			WaitingReason:  waitingReason,
+			WaitingMessage: waitingMessage,
+		}
+		if last := status.LastTerminationState.Terminated; last != nil {
+			exitCode := last.ExitCode
+			entry.LastExitCode = &exitCode
+			entry.LastReason = last.Reason
+			if !last.FinishedAt.IsZero() {
+				finishedAt := last.FinishedAt.Time
+				entry.LastRestartAt = &finishedAt
+				entry.LastRestartAgo = utils.FormatTimeAgo(finishedAt)
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
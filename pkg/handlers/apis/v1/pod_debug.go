@@ -0,0 +1,252 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// defaultDebugImage 未指定镜像时临时调试容器使用的镜像
+	defaultDebugImage = "busybox"
+	// 等待临时容器启动的默认时长和最大时长（秒）
+	defaultDebugWaitSeconds = 60
+	maxDebugWaitSeconds     = 300
+	// debugCommandOutputLimit 在临时容器中执行命令时保留的标准输出上限
+	debugCommandOutputLimit = 64 * 1024
+)
+
+var ephemeralContainersGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods/ephemeralcontainers"}
+
+// DebugPod 通过ephemeralcontainers子资源向Pod添加临时调试容器（相当于kubectl debug），等待其启动后返回容器名称，
+// 可选在其中执行一条命令并一并返回输出。指定targetContainer时与该容器共享进程命名空间
+func (h *ResourceHandlerImpl) DebugPod(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	image, _ := arguments["image"].(string)
+	if image == "" {
+		image = defaultDebugImage
+	}
+	targetContainer, _ := arguments["targetContainer"].(string)
+	waitSeconds := defaultDebugWaitSeconds
+	if value, ok := arguments["waitTimeoutSeconds"].(float64); ok && value > 0 {
+		waitSeconds = min(int(value), maxDebugWaitSeconds)
+	}
+	command, err := stringSliceArgument(arguments, "command")
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{Code: utils.ErrorCodeInvalid, Message: err.Error()}), nil
+	}
+
+	h.handler.Log.Info("Adding ephemeral debug container",
+		"name", name,
+		"namespace", namespace,
+		"image", image,
+		"targetContainer", targetContainer,
+		"command", command,
+	)
+
+	// 与exec相同需要--allow-exec，并要求Pod处于运行状态；指定了targetContainer时校验其存在
+	if _, errResult := h.execPreflight(ctx, namespace, name, targetContainer); errResult != nil {
+		return errResult, nil
+	}
+	if denied := h.handler.PreflightCheck(ctx, "patch", ephemeralContainersGVR, namespace, name); denied != nil {
+		return denied, nil
+	}
+
+	podClient := h.handler.Client.ClientSet().CoreV1().Pods(namespace)
+	pod, err := podClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get pod", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name)), nil
+	}
+
+	// 与kubectl debug相同保持标准输入打开，镜像默认的shell不会立即退出，后续可以继续在其中执行命令
+	containerName := debugContainerName(pod)
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     containerName,
+			Image:                    image,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			Stdin:                    true,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: targetContainer,
+	})
+	if _, err := podClient.UpdateEphemeralContainers(ctx, name, pod, metav1.UpdateOptions{}); err != nil {
+		// Pod已经确认存在，404或405说明API Server不提供ephemeralcontainers子资源
+		if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeUnsupported,
+				Message: fmt.Sprintf("the cluster does not support ephemeral containers: %v", err),
+				Reason:  string(apierrors.ReasonForError(err)),
+				Hint:    "Ephemeral containers require Kubernetes 1.23 or later; use LIST_POD_FILES or READ_POD_FILE on an image that contains a shell instead.",
+			}), nil
+		}
+		h.handler.Log.Error("Failed to add ephemeral container", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to add ephemeral container to pod %s", name)), nil
+	}
+
+	response := models.DebugContainerResult{
+		Pod:             name,
+		Namespace:       namespace,
+		Container:       containerName,
+		Image:           image,
+		TargetContainer: targetContainer,
+	}
+	start := time.Now()
+	status := h.waitForEphemeralContainer(ctx, namespace, name, containerName, time.Duration(waitSeconds)*time.Second)
+	response.Waited = time.Since(start).Round(time.Second).String()
+	response.State, response.StateReason, response.StateMessage = containerStateSummary(status)
+	utils.ProgressFromContext(ctx).Done(ctx, describeContainerState(containerName, response.State, response.StateReason))
+	if response.State != "running" {
+		response.Hint = "The ephemeral container did not start; check its state with DIAGNOSE_POD. Ephemeral containers cannot be removed, so retrying adds another one."
+		return utils.RenderResult(request, response), nil
+	}
+	response.Running = true
+
+	if len(command) > 0 {
+		response.Command = command
+		result, err := h.execInContainer(ctx, namespace, name, containerName, command, nil, debugCommandOutputLimit)
+		if err != nil {
+			return execErrorResult(err, name, containerName, command[0]), nil
+		}
+		response.Stdout = string(result.stdout)
+		response.Stderr = result.stderr
+		response.ExitCode = &result.exitCode
+		response.Truncated = result.truncated
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// waitForEphemeralContainer 轮询临时容器的状态，直到运行、终止、镜像拉取失败或超时，返回最后一次观察到的状态
+func (h *ResourceHandlerImpl) waitForEphemeralContainer(
+	ctx context.Context,
+	namespace, pod, container string,
+	timeout time.Duration,
+) *corev1.ContainerStatus {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var status *corev1.ContainerStatus
+	progress := utils.ProgressFromContext(ctx)
+	for {
+		current, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+		if err == nil {
+			for i := range current.Status.EphemeralContainerStatuses {
+				if current.Status.EphemeralContainerStatuses[i].Name == container {
+					status = &current.Status.EphemeralContainerStatuses[i]
+				}
+			}
+		}
+		if status != nil {
+			if status.State.Running != nil || status.State.Terminated != nil {
+				return status
+			}
+			if waiting := status.State.Waiting; waiting != nil && isImagePullFailure(waiting.Reason) {
+				return status
+			}
+		}
+		state, reason, _ := containerStateSummary(status)
+		progress.Report(ctx, describeContainerState(container, state, reason))
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return status
+		case <-ctx.Done():
+			return status
+		}
+	}
+}
+
+// containerStateSummary 返回容器状态（running、terminated、waiting，尚无状态时为pending）及原因
+func containerStateSummary(status *corev1.ContainerStatus) (string, string, string) {
+	switch {
+	case status == nil:
+		return "pending", "", ""
+	case status.State.Running != nil:
+		return "running", "", ""
+	case status.State.Terminated != nil:
+		terminated := status.State.Terminated
+		return "terminated", terminated.Reason, strings.TrimSpace(terminated.Message)
+	case status.State.Waiting != nil:
+		return "waiting", status.State.Waiting.Reason, status.State.Waiting.Message
+	}
+	return "pending", "", ""
+}
+
+// describeContainerState 以"ephemeral container debugger is waiting (ContainerCreating)"形式描述容器状态，用于进度通知
+func describeContainerState(container, state, reason string) string {
+	if reason == "" {
+		return fmt.Sprintf("ephemeral container %s is %s", container, state)
+	}
+	return fmt.Sprintf("ephemeral container %s is %s (%s)", container, state, reason)
+}
+
+// isImagePullFailure 判断等待原因是否为镜像拉取失败
+func isImagePullFailure(reason string) bool {
+	switch reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+		return true
+	}
+	return false
+}
+
+// debugContainerName 生成与Pod中已有容器不重名的调试容器名称
+func debugContainerName(pod *corev1.Pod) string {
+	existing := make(map[string]bool)
+	for _, container := range pod.Spec.Containers {
+		existing[container.Name] = true
+	}
+	for _, container := range pod.Spec.InitContainers {
+		existing[container.Name] = true
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		existing[container.Name] = true
+	}
+	for {
+		name := "debugger-" + utilrand.String(5)
+		if !existing[name] {
+			return name
+		}
+	}
+}
+
+// stringSliceArgument 读取字符串数组参数，元素必须都是字符串
+func stringSliceArgument(arguments map[string]interface{}, key string) ([]string, error) {
+	raw, ok := arguments[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,566 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 诊断时每个容器默认获取的日志行数
+	defaultDiagnoseTailLines = 50
+	// 诊断时每个容器读取日志的字节上限
+	maxDiagnoseLogBytes = 256 * 1024
+	// 每个容器输出的日志错误摘要数量
+	maxDiagnoseTopErrors = 5
+	// Pod卡在初始化阶段时，作为证据附带的阻塞容器日志行数
+	initBlockedLogLines = 10
+)
+
+// DiagnosePod 汇总Pod状态、事件、日志和命名空间约束，并推断可能的故障原因
+func (h *ResourceHandlerImpl) DiagnosePod(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	tailLines := defaultDiagnoseTailLines
+	if v, ok := arguments["tailLines"].(float64); ok && v > 0 {
+		tailLines = int(v)
+	}
+
+	if name == "" {
+		return utils.NewErrorToolResult("Pod name is required"), nil
+	}
+
+	reqLogger := h.handler.Log.With("pod", name, "namespace", namespace)
+	reqLogger.Info("Starting pod diagnosis", "tailLines", tailLines)
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	pod, err := coreClient.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		reqLogger.Error("Failed to get pod", "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name)), nil
+	}
+
+	report := &models.PodDiagnosisReport{
+		Name:           pod.Name,
+		Namespace:      pod.Namespace,
+		Phase:          string(pod.Status.Phase),
+		Reason:         pod.Status.Reason,
+		Message:        pod.Status.Message,
+		QOSClass:       string(pod.Status.QOSClass),
+		ServiceAccount: pod.Spec.ServiceAccountName,
+		Conditions:     make(map[string]string),
+		WarningEvents:  []models.DiagnosisEvent{},
+		RetrievedAt:    time.Now(),
+	}
+	if pod.Status.StartTime != nil {
+		startTime := pod.Status.StartTime.Time
+		report.StartTime = &startTime
+	}
+	for _, condition := range pod.Status.Conditions {
+		report.Conditions[string(condition.Type)] = string(condition.Status)
+	}
+
+	// --- 事件 ---
+	events, err := coreClient.Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": name,
+		}.String(),
+	})
+	if err != nil {
+		reqLogger.Warn("Failed to list pod events", "error", err)
+	} else {
+		report.WarningEvents = warningEvents(events.Items)
+	}
+
+	// --- 调度信息 ---
+	report.Scheduling = models.PodSchedulingInfo{
+		NodeName:          pod.Spec.NodeName,
+		NodeSelector:      pod.Spec.NodeSelector,
+		HasAffinity:       pod.Spec.Affinity != nil,
+		TolerationCount:   len(pod.Spec.Tolerations),
+		PriorityClassName: pod.Spec.PriorityClassName,
+	}
+	for _, event := range report.WarningEvents {
+		if event.Reason == "FailedScheduling" {
+			report.Scheduling.UnschedulableReasons = append(report.Scheduling.UnschedulableReasons, event.Message)
+		}
+	}
+
+	// --- 容器状态与日志 ---
+	report.InitProgress = podInitProgress(pod)
+	report.InitContainers = h.diagnoseContainers(ctx, pod, pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true, tailLines)
+	report.Containers = h.diagnoseContainers(ctx, pod, pod.Spec.Containers, pod.Status.ContainerStatuses, false, tailLines)
+
+	// --- 命名空间约束 ---
+	if quotas, err := coreClient.ResourceQuotas(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		reqLogger.Warn("Failed to list resource quotas", "error", err)
+	} else {
+		report.ResourceQuotas = quotaUsages(quotas.Items)
+	}
+	if limitRanges, err := coreClient.LimitRanges(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		reqLogger.Warn("Failed to list limit ranges", "error", err)
+	} else {
+		report.LimitRanges = limitRangeInfos(limitRanges.Items)
+	}
+
+	// --- 规则推断 ---
+	report.ProbableCauses = detectProbableCauses(pod, report)
+
+	reqLogger.Info("Pod diagnosis completed", "probableCauses", len(report.ProbableCauses))
+
+	return utils.RenderResult(request, report), nil
+}
+
+// diagnoseContainers 收集容器状态和日志，并使用日志分析器统计错误
+func (h *ResourceHandlerImpl) diagnoseContainers(
+	ctx context.Context,
+	pod *corev1.Pod,
+	containers []corev1.Container,
+	statuses []corev1.ContainerStatus,
+	init bool,
+	tailLines int,
+) []models.ContainerDiagnosis {
+	statusByName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, status := range statuses {
+		statusByName[status.Name] = status
+	}
+
+	result := make([]models.ContainerDiagnosis, 0, len(containers))
+	for _, container := range containers {
+		diagnosis := models.ContainerDiagnosis{
+			Name:              container.Name,
+			Image:             container.Image,
+			Init:              init,
+			Sidecar:           init && isSidecarContainer(container),
+			State:             "Unknown",
+			Requests:          resourceListToMap(container.Resources.Requests),
+			Limits:            resourceListToMap(container.Resources.Limits),
+			HasReadinessProbe: container.ReadinessProbe != nil,
+			HasLivenessProbe:  container.LivenessProbe != nil,
+		}
+
+		status, hasStatus := statusByName[container.Name]
+		if hasStatus {
+			diagnosis.Ready = status.Ready
+			diagnosis.RestartCount = status.RestartCount
+			switch {
+			case status.State.Running != nil:
+				diagnosis.State = "Running"
+			case status.State.Waiting != nil:
+				diagnosis.State = "Waiting"
+				diagnosis.Reason = status.State.Waiting.Reason
+				diagnosis.Message = status.State.Waiting.Message
+			case status.State.Terminated != nil:
+				diagnosis.State = "Terminated"
+				diagnosis.Reason = status.State.Terminated.Reason
+				diagnosis.Message = status.State.Terminated.Message
+				exitCode := status.State.Terminated.ExitCode
+				diagnosis.ExitCode = &exitCode
+			}
+			if last := status.LastTerminationState.Terminated; last != nil {
+				diagnosis.LastTerminationReason = last.Reason
+				exitCode := last.ExitCode
+				diagnosis.LastExitCode = &exitCode
+			}
+		}
+
+		// 容器从未启动时没有日志可取
+		if hasStatus && (status.State.Waiting == nil || status.RestartCount > 0) {
+			logs, err := h.readContainerLogs(ctx, pod, container.Name, false, tailLines)
+			if err != nil {
+				diagnosis.LogError = err.Error()
+			}
+			diagnosis.Logs = logs
+		}
+		if hasStatus && status.RestartCount > 0 {
+			previousLogs, err := h.readContainerLogs(ctx, pod, container.Name, true, tailLines)
+			if err != nil && diagnosis.LogError == "" {
+				diagnosis.LogError = err.Error()
+			}
+			diagnosis.PreviousLogs = previousLogs
+		}
+
+		// 使用日志分析器统计错误和警告
+		allLines := append(append([]string{}, diagnosis.PreviousLogs...), diagnosis.Logs...)
+		if len(allLines) > 0 {
+			analysis := utils.NewLogAnalyzer().AnalyzeLogs(allLines)
+			diagnosis.LogErrorCount = analysis.ErrorCount
+			diagnosis.LogWarningCount = analysis.WarningCount
+			diagnosis.TopLogErrors = topKeys(analysis.TopErrors, maxDiagnoseTopErrors)
+		}
+
+		result = append(result, diagnosis)
+	}
+	return result
+}
+
+// readContainerLogs 读取容器最后若干行日志
+func (h *ResourceHandlerImpl) readContainerLogs(
+	ctx context.Context,
+	pod *corev1.Pod,
+	container string,
+	previous bool,
+	tailLines int,
+) ([]string, error) {
+	tail := int64(tailLines)
+	stream, err := h.handler.Client.ClientSet().CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		Timestamps: true,
+		TailLines:  &tail,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := io.CopyN(buf, stream, maxDiagnoseLogBytes); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
+// warningEvents 筛选Warning事件并按时间排序
+func warningEvents(events []corev1.Event) []models.DiagnosisEvent {
+	result := []models.DiagnosisEvent{}
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		eventTime := utils.EventLastSeen(event)
+		result = append(result, models.DiagnosisEvent{
+			Time:    eventTime,
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Count:   event.Count,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
+
+// detectProbableCauses 根据收集到的事实按规则推断可能的故障原因，按置信度降序排列
+func detectProbableCauses(pod *corev1.Pod, report *models.PodDiagnosisReport) []models.ProbableCause {
+	causes := []models.ProbableCause{}
+	add := func(cause models.ProbableCause) {
+		causes = append(causes, cause)
+	}
+
+	if pod.Status.Reason == "Evicted" {
+		add(models.ProbableCause{
+			Cause:      "Pod被驱逐",
+			Confidence: 90,
+			Evidence:   []string{pod.Status.Message},
+			Suggestion: "检查节点资源压力（内存、磁盘），并为Pod设置合理的requests",
+		})
+	}
+
+	// Pod卡在初始化阶段时，阻塞的初始化容器是首要原因
+	if progress := report.InitProgress; progress != nil {
+		add(initBlockedCause(progress, report.InitContainers))
+	}
+
+	for _, c := range append(append([]models.ContainerDiagnosis{}, report.InitContainers...), report.Containers...) {
+		switch c.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			add(models.ProbableCause{
+				Cause:      "镜像拉取失败",
+				Confidence: 95,
+				Container:  c.Name,
+				Evidence:   []string{fmt.Sprintf("%s: %s", c.Reason, c.Message), "image: " + c.Image},
+				Suggestion: "确认镜像名称和标签存在，并检查imagePullSecrets和镜像仓库的网络连通性",
+			})
+		case "CreateContainerConfigError", "CreateContainerError":
+			add(models.ProbableCause{
+				Cause:      "容器配置错误",
+				Confidence: 90,
+				Container:  c.Name,
+				Evidence:   []string{fmt.Sprintf("%s: %s", c.Reason, c.Message)},
+				Suggestion: "检查引用的ConfigMap、Secret及其键是否存在",
+			})
+		case "CrashLoopBackOff":
+			evidence := []string{fmt.Sprintf("restartCount: %d", c.RestartCount)}
+			if c.LastTerminationReason != "" {
+				evidence = append(evidence, fmt.Sprintf("last termination: %s (exit code %d)", c.LastTerminationReason, derefInt32(c.LastExitCode)))
+			}
+			evidence = append(evidence, c.TopLogErrors...)
+			add(models.ProbableCause{
+				Cause:      "容器反复崩溃",
+				Confidence: 80,
+				Container:  c.Name,
+				Evidence:   evidence,
+				Suggestion: "查看previousLogs中崩溃前的日志，确认启动命令、配置和依赖服务",
+			})
+		}
+
+		if c.Reason == "OOMKilled" || c.LastTerminationReason == "OOMKilled" {
+			evidence := []string{"terminated with reason OOMKilled"}
+			if limit, ok := c.Limits[string(corev1.ResourceMemory)]; ok {
+				evidence = append(evidence, "memory limit: "+limit)
+			}
+			add(models.ProbableCause{
+				Cause:      "内存不足被OOMKilled",
+				Confidence: 95,
+				Container:  c.Name,
+				Evidence:   evidence,
+				Suggestion: "提高内存limit或排查应用内存泄漏",
+			})
+		} else if c.State == "Terminated" && c.ExitCode != nil && *c.ExitCode != 0 && !c.Init {
+			add(models.ProbableCause{
+				Cause:      "容器异常退出",
+				Confidence: 60,
+				Container:  c.Name,
+				Evidence:   []string{fmt.Sprintf("%s (exit code %d)", c.Reason, *c.ExitCode)},
+				Suggestion: "查看容器日志确认退出原因",
+			})
+		}
+
+		if c.LogErrorCount > 0 {
+			add(models.ProbableCause{
+				Cause:      "应用日志中存在错误",
+				Confidence: 40,
+				Container:  c.Name,
+				Evidence:   append([]string{fmt.Sprintf("%d error lines in recent logs", c.LogErrorCount)}, c.TopLogErrors...),
+				Suggestion: "使用ANALYZE_POD_LOGS对更多日志进行分析",
+			})
+		}
+	}
+
+	// 基于事件的规则
+	var insufficient, unschedulable, readiness, liveness, mount, quota []string
+	for _, event := range report.WarningEvents {
+		message := event.Message
+		switch {
+		case event.Reason == "FailedScheduling" && strings.Contains(message, "Insufficient"):
+			insufficient = append(insufficient, message)
+		case event.Reason == "FailedScheduling":
+			unschedulable = append(unschedulable, message)
+		case event.Reason == "Unhealthy" && strings.Contains(message, "Readiness probe"):
+			readiness = append(readiness, message)
+		case event.Reason == "Unhealthy" && strings.Contains(message, "Liveness probe"):
+			liveness = append(liveness, message)
+		case event.Reason == "FailedMount" || event.Reason == "FailedAttachVolume":
+			mount = append(mount, message)
+		case strings.Contains(message, "exceeded quota"):
+			quota = append(quota, message)
+		}
+	}
+
+	pending := pod.Status.Phase == corev1.PodPending
+	if len(insufficient) > 0 && pending {
+		add(models.ProbableCause{
+			Cause:      "集群资源不足导致Pod无法调度",
+			Confidence: 90,
+			Evidence:   lastN(insufficient, 3),
+			Suggestion: "降低Pod的资源requests，或扩容节点",
+		})
+	}
+	if len(unschedulable) > 0 && pending {
+		add(models.ProbableCause{
+			Cause:      "调度约束无法满足",
+			Confidence: 80,
+			Evidence:   lastN(unschedulable, 3),
+			Suggestion: "检查nodeSelector、亲和性、污点容忍以及PVC绑定情况",
+		})
+	}
+	if len(readiness) > 0 {
+		add(models.ProbableCause{
+			Cause:      "就绪探针失败",
+			Confidence: 65,
+			Evidence:   lastN(readiness, 3),
+			Suggestion: "确认探针路径、端口和initialDelaySeconds是否与应用启动时间匹配",
+		})
+	}
+	if len(liveness) > 0 {
+		add(models.ProbableCause{
+			Cause:      "存活探针失败导致容器重启",
+			Confidence: 75,
+			Evidence:   lastN(liveness, 3),
+			Suggestion: "检查应用健康检查接口，适当调整探针超时和失败阈值",
+		})
+	}
+	if len(mount) > 0 {
+		add(models.ProbableCause{
+			Cause:      "存储卷挂载失败",
+			Confidence: 80,
+			Evidence:   lastN(mount, 3),
+			Suggestion: "检查PVC状态、StorageClass以及引用的ConfigMap/Secret卷是否存在",
+		})
+	}
+	if len(quota) > 0 {
+		add(models.ProbableCause{
+			Cause:      "超出命名空间ResourceQuota",
+			Confidence: 85,
+			Evidence:   lastN(quota, 3),
+			Suggestion: "调整ResourceQuota或降低资源请求",
+		})
+	}
+
+	// 配额已耗尽时，新Pod可能无法创建
+	for _, q := range report.ResourceQuotas {
+		for resourceName, hard := range q.Hard {
+			used, ok := q.Used[resourceName]
+			if !ok {
+				continue
+			}
+			hardQuantity, err1 := resource.ParseQuantity(hard)
+			usedQuantity, err2 := resource.ParseQuantity(used)
+			if err1 == nil && err2 == nil && usedQuantity.Cmp(hardQuantity) >= 0 {
+				add(models.ProbableCause{
+					Cause:      "ResourceQuota已用尽",
+					Confidence: 50,
+					Evidence:   []string{fmt.Sprintf("%s %s: used %s / hard %s", q.Name, resourceName, used, hard)},
+					Suggestion: "释放命名空间内的资源或提高配额",
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(causes, func(i, j int) bool {
+		return causes[i].Confidence > causes[j].Confidence
+	})
+	return causes
+}
+
+// initBlockedCause 根据初始化进度生成首要原因，证据包含阻塞容器的状态和最后几行日志
+func initBlockedCause(progress *models.InitContainerProgress, initContainers []models.ContainerDiagnosis) models.ProbableCause {
+	cause := models.ProbableCause{
+		Cause:      fmt.Sprintf("Pod卡在初始化阶段（%s）", progress.Status),
+		Confidence: 99,
+		Container:  progress.Blocking,
+		Evidence: []string{
+			fmt.Sprintf("%d/%d init containers completed, blocked on %s (state %s)", progress.Completed, progress.Total, progress.Blocking, progress.State),
+		},
+		Suggestion: fmt.Sprintf("查看初始化容器%s的日志，确认它等待的依赖（服务、数据库、配置）是否就绪以及退出原因", progress.Blocking),
+	}
+	if progress.BlockingSidecar {
+		cause.Suggestion = fmt.Sprintf("边车容器%s尚未启动完成，检查其startupProbe和日志", progress.Blocking)
+	}
+	switch {
+	case progress.Reason != "" && progress.Message != "":
+		cause.Evidence = append(cause.Evidence, progress.Reason+": "+progress.Message)
+	case progress.Reason != "":
+		cause.Evidence = append(cause.Evidence, progress.Reason)
+	}
+	if progress.ExitCode != nil {
+		cause.Evidence = append(cause.Evidence, fmt.Sprintf("exit code %d, restartCount %d", *progress.ExitCode, progress.RestartCount))
+	}
+	for _, c := range initContainers {
+		if c.Name != progress.Blocking {
+			continue
+		}
+		// 等待重启的容器当前没有输出，最后一次运行的日志在previousLogs中
+		logs := c.Logs
+		if len(logs) == 0 || (progress.State == "Waiting" && len(c.PreviousLogs) > 0) {
+			logs = c.PreviousLogs
+		}
+		cause.Evidence = append(cause.Evidence, lastN(logs, initBlockedLogLines)...)
+	}
+	return cause
+}
+
+// quotaUsages 提取ResourceQuota的使用量和硬限制
+func quotaUsages(quotas []corev1.ResourceQuota) []models.QuotaUsage {
+	var result []models.QuotaUsage
+	for _, quota := range quotas {
+		result = append(result, models.QuotaUsage{
+			Name: quota.Name,
+			Hard: resourceListToMap(quota.Status.Hard),
+			Used: resourceListToMap(quota.Status.Used),
+		})
+	}
+	return result
+}
+
+// limitRangeInfos 提取LimitRange的默认值和上下限
+func limitRangeInfos(limitRanges []corev1.LimitRange) []models.LimitRangeInfo {
+	var result []models.LimitRangeInfo
+	for _, limitRange := range limitRanges {
+		info := models.LimitRangeInfo{Name: limitRange.Name}
+		for _, item := range limitRange.Spec.Limits {
+			info.Limits = append(info.Limits, models.LimitRangeItemInfo{
+				Type:           string(item.Type),
+				Default:        resourceListToMap(item.Default),
+				DefaultRequest: resourceListToMap(item.DefaultRequest),
+				Max:            resourceListToMap(item.Max),
+				Min:            resourceListToMap(item.Min),
+			})
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// resourceListToMap 将资源列表转换为字符串映射
+func resourceListToMap(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(list))
+	for name, quantity := range list {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
+
+// topKeys 返回计数最高的若干个键
+func topKeys(counts map[string]int, n int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// lastN 返回切片中最后n个元素
+func lastN(items []string, n int) []string {
+	if len(items) > n {
+		return items[len(items)-n:]
+	}
+	return items
+}
+
+// derefInt32 安全地解引用int32指针
+func derefInt32(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
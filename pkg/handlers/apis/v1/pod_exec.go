@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// busyboxPath 容器PATH中没有所需命令时尝试的busybox路径
+	busyboxPath = "/bin/busybox"
+	// maxExecStderrBytes 保留的标准错误输出上限
+	maxExecStderrBytes = 4096
+)
+
+// execResult 在容器中执行命令的输出
+type execResult struct {
+	stdout    []byte
+	stderr    string
+	exitCode  int
+	truncated bool
+	// usedBusybox 命令是否通过busybox执行
+	usedBusybox bool
+}
+
+// errCommandNotFound 容器中没有要执行的命令，也没有可用的busybox
+var errCommandNotFound = errors.New("command not found in container")
+
+// execPreflight 检查是否允许执行命令，并解析出目标容器（未指定时为第一个容器）
+func (h *ResourceHandlerImpl) execPreflight(
+	ctx context.Context,
+	namespace, name, container string,
+) (string, *mcp.CallToolResult) {
+	if !base.GetOptions().AllowExec {
+		return "", utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeRefused,
+			Message: "executing commands in containers is disabled on this server",
+			Hint:    "Start the server with --allow-exec to enable tools that run commands in containers.",
+		})
+	}
+	if name == "" {
+		return "", utils.NewErrorToolResult("missing required parameter: name")
+	}
+
+	pod, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get pod", "name", name, "namespace", namespace, "error", err)
+		return "", utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name))
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return "", utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("pod %s is %s; commands can only run in a running pod", name, pod.Status.Phase),
+		})
+	}
+
+	names := make([]string, 0, len(pod.Spec.Containers))
+	for _, item := range pod.Spec.Containers {
+		names = append(names, item.Name)
+	}
+	if container == "" {
+		return names[0], nil
+	}
+	for _, item := range names {
+		if item == container {
+			return container, nil
+		}
+	}
+	return "", utils.NewToolErrorResult(models.ToolError{
+		Code:    utils.ErrorCodeNotFound,
+		Message: fmt.Sprintf("container %s not found in pod %s, available containers: %s", container, name, strings.Join(names, ", ")),
+	})
+}
+
+// execInContainer 在容器中直接执行argv（不经过shell，参数不会被解释），stdin不为nil时作为标准输入，标准输出最多保留stdoutLimit字节
+// 容器中找不到命令时改为通过/bin/busybox执行，仍然失败时返回errCommandNotFound；命令以非零状态退出不视为错误
+func (h *ResourceHandlerImpl) execInContainer(
+	ctx context.Context,
+	namespace, pod, container string,
+	argv []string,
+	stdin []byte,
+	stdoutLimit int,
+) (*execResult, error) {
+	result, err := h.streamExec(ctx, namespace, pod, container, argv, stdin, stdoutLimit)
+	if !errors.Is(err, errCommandNotFound) {
+		return result, err
+	}
+
+	h.handler.Log.Debug("Command not found in container, retrying with busybox", "pod", pod, "container", container, "command", argv[0])
+	result, err = h.streamExec(ctx, namespace, pod, container, append([]string{busyboxPath}, argv...), stdin, stdoutLimit)
+	if err != nil {
+		return nil, err
+	}
+	result.usedBusybox = true
+	return result, nil
+}
+
+// streamExec 通过SPDY执行一次命令
+func (h *ResourceHandlerImpl) streamExec(
+	ctx context.Context,
+	namespace, pod, container string,
+	argv []string,
+	stdin []byte,
+	stdoutLimit int,
+) (*execResult, error) {
+	restConfig := h.handler.Client.GetRESTConfig()
+	if restConfig == nil {
+		return nil, fmt.Errorf("no REST config available for exec")
+	}
+
+	request := h.handler.Client.ClientSet().CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   argv,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", request.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	stdout := &limitedBuffer{limit: stdoutLimit}
+	stderr := &limitedBuffer{limit: maxExecStderrBytes}
+	options := remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr}
+	if stdin != nil {
+		// 每次执行使用新的Reader，以便通过busybox重试时重新发送完整的输入
+		options.Stdin = bytes.NewReader(stdin)
+	}
+	err = executor.StreamWithContext(ctx, options)
+	result := &execResult{
+		stdout:    stdout.Bytes(),
+		stderr:    strings.TrimSpace(stderr.String()),
+		truncated: stdout.truncated,
+	}
+	if err == nil {
+		return result, nil
+	}
+
+	// 126/127是shell约定的“无法执行/找不到命令”，容器运行时找不到可执行文件时返回的错误不带退出码
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		result.exitCode = exitErr.ExitStatus()
+		if result.exitCode == 126 || result.exitCode == 127 {
+			return nil, errCommandNotFound
+		}
+		return result, nil
+	}
+	message := err.Error()
+	if strings.Contains(message, "executable file not found") || strings.Contains(message, "no such file or directory") {
+		return nil, errCommandNotFound
+	}
+	return nil, err
+}
+
+// execErrorResult 将执行命令的错误转换为工具错误
+func execErrorResult(err error, pod, container, command string) *mcp.CallToolResult {
+	if errors.Is(err, errCommandNotFound) {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeUnsupported,
+			Message: fmt.Sprintf("container %s in pod %s has neither %s nor %s; it is probably a distroless or scratch image", container, pod, command, busyboxPath),
+			Hint:    "Attach an ephemeral debug container that shares the process namespace (kubectl debug --target) to inspect its filesystem.",
+		})
+	}
+	return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to run %s in container %s of pod %s", command, container, pod))
+}
+
+// limitedBuffer 最多保留limit字节的缓冲区，超出部分被丢弃并标记截断，不中断数据流
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write 实现io.Writer
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,325 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 列出文件时默认的递归深度和最大深度
+	defaultListFilesDepth = 1
+	maxListFilesDepth     = 5
+	// 列出文件时返回的最大条目数
+	maxListFilesEntries = 500
+	// listFilesOutputLimit ls和find输出的最大字节数
+	listFilesOutputLimit = 1024 * 1024
+	// 读取文件时默认和最大的字节数
+	defaultReadFileBytes = 64 * 1024
+	maxReadFileBytes     = 1024 * 1024
+)
+
+// isoDatePattern ls使用long-iso等时间格式时日期字段的形式
+var isoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// ListPodFiles 列出容器中路径下的文件，ls -la的输出被解析为结构化列表
+// 命令以参数数组执行而不经过shell，path不会被shell解释
+func (h *ResourceHandlerImpl) ListPodFiles(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	container, _ := arguments["container"].(string)
+	filePath, _ := arguments["path"].(string)
+	maxDepth := defaultListFilesDepth
+	if value, ok := arguments["maxDepth"].(float64); ok && value > 0 {
+		maxDepth = min(int(value), maxListFilesDepth)
+	}
+
+	h.handler.Log.Info("Listing pod files",
+		"name", name,
+		"namespace", namespace,
+		"container", container,
+		"path", filePath,
+		"maxDepth", maxDepth,
+	)
+
+	if err := validateContainerPath(filePath); err != nil {
+		return utils.NewToolErrorResult(models.ToolError{Code: utils.ErrorCodeInvalid, Message: err.Error()}), nil
+	}
+	filePath = path.Clean(filePath)
+	container, errResult := h.execPreflight(ctx, namespace, name, container)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	response := models.PodFileListing{
+		Pod:       name,
+		Namespace: namespace,
+		Container: container,
+		Path:      filePath,
+		MaxDepth:  maxDepth,
+		Entries:   []models.PodFileEntry{},
+	}
+
+	// 深度为1时直接ls目录；更深时先用find找出路径，再对这些路径执行ls -ld
+	argv := []string{"ls", "-la", "--", filePath}
+	if maxDepth > 1 {
+		found, err := h.execInContainer(ctx, namespace, name, container,
+			[]string{"find", filePath, "-mindepth", "1", "-maxdepth", strconv.Itoa(maxDepth)}, nil, listFilesOutputLimit)
+		if err != nil {
+			return execErrorResult(err, name, container, "find"), nil
+		}
+		if found.exitCode != 0 && len(found.stdout) == 0 {
+			return execCommandFailed(found, filePath), nil
+		}
+		paths := strings.Split(strings.TrimRight(string(found.stdout), "\n"), "\n")
+		if len(paths) == 1 && paths[0] == "" {
+			paths = nil
+		}
+		if len(paths) > maxListFilesEntries || found.truncated {
+			paths = paths[:min(len(paths), maxListFilesEntries)]
+			response.Truncated = true
+		}
+		response.UsedBusybox = found.usedBusybox
+		if len(paths) == 0 {
+			return utils.RenderResult(request, response), nil
+		}
+		argv = append([]string{"ls", "-ld", "--"}, paths...)
+	}
+
+	listed, err := h.execInContainer(ctx, namespace, name, container, argv, nil, listFilesOutputLimit)
+	if err != nil {
+		return execErrorResult(err, name, container, "ls"), nil
+	}
+	if listed.exitCode != 0 && len(listed.stdout) == 0 {
+		return execCommandFailed(listed, filePath), nil
+	}
+	response.UsedBusybox = response.UsedBusybox || listed.usedBusybox
+
+	for _, line := range strings.Split(string(listed.stdout), "\n") {
+		entry, ok := parseLsLine(line)
+		if !ok || (maxDepth == 1 && (entry.Name == "." || entry.Name == "..")) {
+			continue
+		}
+		// ls目录时输出的是相对名称，ls -ld和ls单个文件时输出的是完整路径
+		if strings.HasPrefix(entry.Name, "/") {
+			entry.Path = entry.Name
+			entry.Name = path.Base(entry.Name)
+		} else {
+			entry.Path = path.Join(filePath, entry.Name)
+		}
+		if len(response.Entries) >= maxListFilesEntries {
+			response.Truncated = true
+			break
+		}
+		response.Entries = append(response.Entries, entry)
+	}
+	response.Truncated = response.Truncated || listed.truncated
+	response.Count = len(response.Entries)
+	return utils.RenderResult(request, response), nil
+}
+
+// ReadPodFile 读取容器中的文件，最多返回maxBytes字节，二进制内容以base64编码返回
+func (h *ResourceHandlerImpl) ReadPodFile(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	container, _ := arguments["container"].(string)
+	filePath, _ := arguments["path"].(string)
+	maxBytes := defaultReadFileBytes
+	if value, ok := arguments["maxBytes"].(float64); ok && value > 0 {
+		maxBytes = min(int(value), maxReadFileBytes)
+	}
+
+	h.handler.Log.Info("Reading pod file",
+		"name", name,
+		"namespace", namespace,
+		"container", container,
+		"path", filePath,
+		"maxBytes", maxBytes,
+	)
+
+	if err := validateContainerPath(filePath); err != nil {
+		return utils.NewToolErrorResult(models.ToolError{Code: utils.ErrorCodeInvalid, Message: err.Error()}), nil
+	}
+	filePath = path.Clean(filePath)
+	container, errResult := h.execPreflight(ctx, namespace, name, container)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	// 多读取一个字节用于判断文件是否超过上限，避免传输整个大文件
+	read, err := h.execInContainer(ctx, namespace, name, container,
+		[]string{"head", "-c", strconv.Itoa(maxBytes + 1), "--", filePath}, nil, maxBytes+1)
+	if err != nil {
+		return execErrorResult(err, name, container, "head"), nil
+	}
+	if read.exitCode != 0 {
+		return execCommandFailed(read, filePath), nil
+	}
+
+	content := read.stdout
+	response := models.PodFileContent{
+		Pod:         name,
+		Namespace:   namespace,
+		Container:   container,
+		Path:        filePath,
+		Encoding:    "utf-8",
+		UsedBusybox: read.usedBusybox,
+	}
+	if len(content) > maxBytes {
+		content = content[:maxBytes]
+		response.Truncated = true
+	}
+	response.Bytes = len(content)
+	response.Binary = isBinaryContent(content, response.Truncated)
+	if response.Binary {
+		response.Encoding = "base64"
+		response.Content = base64.StdEncoding.EncodeToString(content)
+	} else {
+		response.Content = string(content)
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// validateContainerPath 要求绝对路径且不包含控制字符
+func validateContainerPath(filePath string) error {
+	if filePath == "" {
+		return fmt.Errorf("missing required parameter: path")
+	}
+	if !strings.HasPrefix(filePath, "/") {
+		return fmt.Errorf("path %q must be absolute", filePath)
+	}
+	for _, r := range filePath {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("path must not contain control characters")
+		}
+	}
+	return nil
+}
+
+// execCommandFailed 将命令的非零退出转换为工具错误，路径不存在时返回NotFound
+func execCommandFailed(result *execResult, filePath string) *mcp.CallToolResult {
+	code := utils.ErrorCodeToolError
+	lower := strings.ToLower(result.stderr)
+	switch {
+	case strings.Contains(lower, "no such file or directory"):
+		code = utils.ErrorCodeNotFound
+	case strings.Contains(lower, "permission denied"):
+		code = utils.ErrorCodeForbidden
+	case strings.Contains(lower, "is a directory"):
+		code = utils.ErrorCodeInvalid
+	}
+	message := result.stderr
+	if message == "" {
+		message = fmt.Sprintf("command exited with status %d", result.exitCode)
+	}
+	return utils.NewToolErrorResult(models.ToolError{
+		Code:    code,
+		Message: fmt.Sprintf("%s: %s", filePath, message),
+	})
+}
+
+// isBinaryContent 内容包含NUL字节或不是合法的UTF-8时视为二进制；被截断时忽略末尾不完整的多字节字符
+func isBinaryContent(content []byte, truncated bool) bool {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return true
+	}
+	if truncated {
+		for i := 0; i < utf8.UTFMax && len(content) > 0; i++ {
+			if utf8.Valid(content) {
+				return false
+			}
+			content = content[:len(content)-1]
+		}
+	}
+	return !utf8.Valid(content)
+}
+
+// parseLsLine 解析一行ls -l的输出，兼容GNU coreutils和busybox的默认时间格式以及long-iso格式
+func parseLsLine(line string) (models.PodFileEntry, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 8 || len(fields[0]) < 10 {
+		return models.PodFileEntry{}, false
+	}
+	entry := models.PodFileEntry{
+		Mode:  fields[0],
+		Type:  lsFileType(fields[0][0]),
+		Owner: fields[2],
+		Group: fields[3],
+	}
+	if entry.Type == "" {
+		return models.PodFileEntry{}, false
+	}
+
+	// 设备文件的大小字段为"主设备号, 次设备号"
+	next := 4
+	if strings.HasSuffix(fields[next], ",") {
+		next++
+	} else if size, err := strconv.ParseInt(fields[next], 10, 64); err == nil {
+		entry.Size = size
+	}
+	next++
+
+	// 默认格式的时间占三个字段（"Jan 2 15:04"或"Jan 2 2006"），long-iso格式占两个字段
+	timeFields := 3
+	if next < len(fields) && isoDatePattern.MatchString(fields[next]) {
+		timeFields = 2
+	}
+	if next+timeFields >= len(fields) {
+		return models.PodFileEntry{}, false
+	}
+	entry.Modified = strings.Join(fields[next:next+timeFields], " ")
+
+	// 名称可能包含空格，取时间字段之后的原始内容
+	rest := line
+	for i := 0; i < next+timeFields; i++ {
+		rest = strings.TrimLeft(rest, " \t")
+		rest = rest[len(fields[i]):]
+	}
+	entry.Name = strings.TrimLeft(rest, " \t")
+	if entry.Type == "symlink" {
+		if linkName, target, ok := strings.Cut(entry.Name, " -> "); ok {
+			entry.Name, entry.LinkTarget = linkName, target
+		}
+	}
+	return entry, entry.Name != ""
+}
+
+// lsFileType 将ls权限字段的首字符转换为文件类型
+func lsFileType(c byte) string {
+	switch c {
+	case '-':
+		return "file"
+	case 'd':
+		return "directory"
+	case 'l':
+		return "symlink"
+	case 'c', 'b':
+		return "device"
+	case 'p':
+		return "pipe"
+	case 's':
+		return "socket"
+	}
+	return ""
+}
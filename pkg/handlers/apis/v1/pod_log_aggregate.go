@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 按标签选择Pod时默认分析的Pod数量和数量上限
+	defaultLogAnalysisMaxPods = 10
+	maxLogAnalysisMaxPods     = 50
+	// 每个日志流和汇总结果中保留的高频错误数量
+	streamTopErrors  = 5
+	summaryTopErrors = 10
+)
+
+// logAnalysisOptions 多日志流分析时对每个日志流生效的选项
+type logAnalysisOptions struct {
+	container     string
+	allContainers bool
+	tailLines     int
+	previous      bool
+	errorPattern  string
+	fields        models.LogFieldMapping
+	prompt        string
+}
+
+// analyzeLogStreams 分析单个Pod的所有容器或标签选择的多个Pod的日志，每个日志流单独分析后合并为汇总结果
+// 单个日志流读取失败（如容器尚未启动、没有上一个实例）只记录在该日志流中，不影响其他日志流
+func (h *ResourceHandlerImpl) analyzeLogStreams(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	namespace, name, labelSelector string,
+	options logAnalysisOptions,
+) *mcp.CallToolResult {
+	maxPods := defaultLogAnalysisMaxPods
+	if value, ok := request.GetArguments()["maxPods"].(float64); ok && value > 0 {
+		maxPods = min(int(value), maxLogAnalysisMaxPods)
+	}
+
+	podClient := h.handler.Client.ClientSet().CoreV1().Pods(namespace)
+	var pods []corev1.Pod
+	if labelSelector != "" {
+		list, err := podClient.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			h.handler.Log.Error("Failed to list pods for log analysis", "namespace", namespace, "labelSelector", labelSelector, "error", err)
+			return utils.NewKubeErrorResult(err, "failed to list pods")
+		}
+		pods = list.Items
+		if len(pods) == 0 {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeNotFound,
+				Message: fmt.Sprintf("no pods match labelSelector %q in namespace %s", labelSelector, namespace),
+			})
+		}
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	} else {
+		pod, err := podClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			h.handler.Log.Error("Failed to get pod for log analysis", "name", name, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name))
+		}
+		pods = []corev1.Pod{*pod}
+	}
+
+	response := models.AggregatedLogAnalysisResponse{
+		LabelSelector: labelSelector,
+		Namespace:     namespace,
+		PodsMatched:   len(pods),
+		Previous:      options.previous,
+		ErrorPattern:  options.errorPattern,
+		Prompt:        options.prompt,
+		Streams:       []models.LogStreamAnalysis{},
+		RetrievedAt:   time.Now(),
+	}
+	if labelSelector == "" {
+		response.Pod = name
+	}
+	if len(pods) > maxPods {
+		pods = pods[:maxPods]
+		response.Truncated = true
+	}
+	response.PodsAnalyzed = len(pods)
+
+	var results []*models.LogAnalysisResult
+	// errorPods 记录每种错误出现在哪些Pod中
+	errorPods := make(map[string]map[string]bool)
+	for i := range pods {
+		pod := &pods[i]
+		for _, target := range logStreamTargets(pod, options) {
+			stream := models.LogStreamAnalysis{
+				Pod:           pod.Name,
+				Container:     target.name,
+				InitContainer: target.init,
+			}
+			lines, err := h.readLogLines(ctx, namespace, pod.Name, target.name, options.previous, options.tailLines)
+			if err != nil {
+				h.handler.Log.Warn("Failed to read logs for analysis", "pod", pod.Name, "container", target.name, "error", err)
+				stream.Error = err.Error()
+				response.Streams = append(response.Streams, stream)
+				continue
+			}
+
+			result := utils.NewLogAnalyzerWithFields(options.errorPattern, options.fields).AnalyzeLogsWithPrompt(lines, options.prompt)
+			results = append(results, result)
+			stream.LinesAnalyzed = len(lines)
+			stream.ErrorCount = result.ErrorCount
+			stream.WarningCount = result.WarningCount
+			stream.TopErrors = topLogErrors(result.TopErrors, streamTopErrors)
+			response.Streams = append(response.Streams, stream)
+			response.StreamsAnalyzed++
+			response.LinesAnalyzed += len(lines)
+
+			for message := range result.TopErrors {
+				if errorPods[message] == nil {
+					errorPods[message] = make(map[string]bool)
+				}
+				errorPods[message][pod.Name] = true
+			}
+		}
+	}
+
+	merged := utils.MergeLogAnalysisResults(results...)
+	response.ErrorCount = merged.ErrorCount
+	response.WarningCount = merged.WarningCount
+	response.LogFormat = merged.Format()
+	response.Summary = models.NewLogAnalysisResponseFromResult(
+		response.Pod, namespace, options.container,
+		merged,
+		response.LinesAnalyzed,
+		options.previous,
+		options.errorPattern,
+		options.prompt,
+	).Analysis
+	response.Summary.Errors = topLogErrors(merged.TopErrors, summaryTopErrors)
+	response.Summary.Summary = fmt.Sprintf("分析了%d个Pod的%d个日志流共%d行日志，发现%d个错误，%d个警告",
+		response.PodsAnalyzed, response.StreamsAnalyzed, response.LinesAnalyzed, merged.ErrorCount, merged.WarningCount)
+
+	// 多个Pod时按出现的Pod数量汇总错误，区分所有副本共有的问题和个别副本的问题
+	if response.PodsAnalyzed > 1 {
+		response.SharedErrors = sharedLogErrors(merged.TopErrors, errorPods, response.PodsAnalyzed)
+	}
+
+	h.handler.Log.Info("Pod logs analysis completed",
+		"namespace", namespace,
+		"pods", response.PodsAnalyzed,
+		"streams", response.StreamsAnalyzed,
+		"linesAnalyzed", response.LinesAnalyzed,
+	)
+	return utils.RenderResult(request, response)
+}
+
+// logStreamTarget 要分析日志的容器
+type logStreamTarget struct {
+	name string
+	init bool
+}
+
+// logStreamTargets 返回Pod中需要分析日志的容器：allContainers时包括初始化容器在内的全部容器，
+// 否则为指定的容器，未指定时为第一个容器
+func logStreamTargets(pod *corev1.Pod, options logAnalysisOptions) []logStreamTarget {
+	if !options.allContainers {
+		name := options.container
+		if name == "" && len(pod.Spec.Containers) > 0 {
+			name = pod.Spec.Containers[0].Name
+		}
+		return []logStreamTarget{{name: name}}
+	}
+	targets := make([]logStreamTarget, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, container := range pod.Spec.InitContainers {
+		targets = append(targets, logStreamTarget{name: container.Name, init: true})
+	}
+	for _, container := range pod.Spec.Containers {
+		targets = append(targets, logStreamTarget{name: container.Name})
+	}
+	return targets
+}
+
+// topLogErrors 按出现次数降序返回最多limit个错误
+func topLogErrors(topErrors map[string]int, limit int) []models.LogEvent {
+	events := make([]models.LogEvent, 0, len(topErrors))
+	for message, count := range topErrors {
+		events = append(events, models.LogEvent{Message: message, Count: count})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Count != events[j].Count {
+			return events[i].Count > events[j].Count
+		}
+		return events[i].Message < events[j].Message
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
+
+// sharedLogErrors 统计每种错误出现在多少个Pod中，按Pod数量和出现次数降序返回
+func sharedLogErrors(topErrors map[string]int, errorPods map[string]map[string]bool, podCount int) []models.SharedLogError {
+	shared := make([]models.SharedLogError, 0, len(errorPods))
+	for message, podSet := range errorPods {
+		pods := make([]string, 0, len(podSet))
+		for pod := range podSet {
+			pods = append(pods, pod)
+		}
+		sort.Strings(pods)
+		shared = append(shared, models.SharedLogError{
+			Message:    message,
+			Count:      topErrors[message],
+			PodCount:   len(pods),
+			Prevalence: fmt.Sprintf("%d个Pod中有%d个出现", podCount, len(pods)),
+			Pods:       pods,
+		})
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if shared[i].PodCount != shared[j].PodCount {
+			return shared[i].PodCount > shared[j].PodCount
+		}
+		if shared[i].Count != shared[j].Count {
+			return shared[i].Count > shared[j].Count
+		}
+		return shared[i].Message < shared[j].Message
+	})
+	if len(shared) > summaryTopErrors {
+		shared = shared[:summaryTopErrors]
+	}
+	return shared
+}
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+func TestLogStreamTargets(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "migrate"}},
+		Containers:     []corev1.Container{{Name: "web"}, {Name: "proxy"}},
+	}}
+
+	tests := []struct {
+		name    string
+		options logAnalysisOptions
+		want    []logStreamTarget
+	}{
+		{name: "first container by default", want: []logStreamTarget{{name: "web"}}},
+		{name: "named container", options: logAnalysisOptions{container: "proxy"}, want: []logStreamTarget{{name: "proxy"}}},
+		{
+			name:    "all containers",
+			options: logAnalysisOptions{allContainers: true},
+			want:    []logStreamTarget{{name: "migrate", init: true}, {name: "web"}, {name: "proxy"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logStreamTargets(pod, tt.options); !slices.Equal(got, tt.want) {
+				t.Fatalf("targets = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopLogErrors(t *testing.T) {
+	got := topLogErrors(map[string]int{"timeout": 2, "refused": 5, "eof": 2, "disk full": 1}, 3)
+	want := []models.LogEvent{{Message: "refused", Count: 5}, {Message: "eof", Count: 2}, {Message: "timeout", Count: 2}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("top errors = %+v, want %+v", got, want)
+	}
+}
+
+func TestSharedLogErrors(t *testing.T) {
+	topErrors := map[string]int{"refused": 6, "timeout": 9}
+	errorPods := map[string]map[string]bool{
+		"refused": {"web-1": true, "web-2": true, "web-3": true},
+		"timeout": {"web-2": true},
+	}
+
+	got := sharedLogErrors(topErrors, errorPods, 3)
+	want := []models.SharedLogError{
+		{Message: "refused", Count: 6, PodCount: 3, Prevalence: "3个Pod中有3个出现", Pods: []string{"web-1", "web-2", "web-3"}},
+		{Message: "timeout", Count: 9, PodCount: 1, Prevalence: "3个Pod中有1个出现", Pods: []string{"web-2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("shared errors = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,596 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认分析的最大Pending Pod数量
+	defaultPendingMaxPods = 20
+	// 每个Pod最多列出的可容纳节点数量
+	maxPendingFittingNodes = 10
+	// 每条结论最多列出的证据数量
+	maxPendingEvidence = 5
+)
+
+// 调度失败原因类别
+const (
+	schedulingInsufficient   = "insufficient-resource"
+	schedulingTooManyPods    = "too-many-pods"
+	schedulingTaint          = "taint"
+	schedulingNodeAffinity   = "node-affinity"
+	schedulingPodAffinity    = "pod-affinity"
+	schedulingVolumeAffinity = "volume-node-affinity"
+	schedulingUnboundPVC     = "unbound-pvc"
+	schedulingUnschedulable  = "node-unschedulable"
+	schedulingNodeNotReady   = "node-not-ready"
+	schedulingHostPort       = "host-port"
+	schedulingFitsNow        = "fits-now"
+	schedulingOther          = "other"
+)
+
+// schedulingReasonPatterns 调度器消息片段（小写）与原因类别的对应关系，按顺序匹配
+var schedulingReasonPatterns = []struct {
+	fragment string
+	category string
+}{
+	{"insufficient ", schedulingInsufficient},
+	{"too many pods", schedulingTooManyPods},
+	{"taint", schedulingTaint},
+	{"node affinity/selector", schedulingNodeAffinity},
+	{"volume node affinity conflict", schedulingVolumeAffinity},
+	{"persistentvolumeclaim", schedulingUnboundPVC},
+	{"pod affinity", schedulingPodAffinity},
+	{"pod anti-affinity", schedulingPodAffinity},
+	{"were unschedulable", schedulingUnschedulable},
+	{"not-ready", schedulingNodeNotReady},
+	{"free ports", schedulingHostPort},
+}
+
+// AnalyzePendingPods 分析Pending Pod无法调度的原因
+// 解析FailedScheduling事件中调度器给出的原因，并结合节点实时的可分配资源、污点和标签判断是否有节点能容纳该Pod以及需要做哪些调整
+func (h *ResourceHandlerImpl) AnalyzePendingPods(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	maxPods := defaultPendingMaxPods
+	if v, ok := arguments["maxPods"].(float64); ok && v > 0 {
+		maxPods = int(v)
+	}
+	if name != "" {
+		namespace = h.baseHandler.GetNamespaceWithDefault(namespace)
+	}
+
+	reqLogger := h.handler.Log.With("namespace", namespace, "pod", name)
+	reqLogger.Info("Analyzing pending pods", "maxPods", maxPods)
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	report := models.PendingPodsReport{
+		Namespace:   namespace,
+		Pods:        []models.PendingPodAnalysis{},
+		RetrievedAt: time.Now(),
+	}
+
+	var pending []corev1.Pod
+	if name != "" {
+		pod, err := coreClient.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			reqLogger.Error("Failed to get pod", "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name)), nil
+		}
+		if pod.Status.Phase != corev1.PodPending {
+			return utils.NewErrorToolResult(fmt.Sprintf("pod %s is in phase %s, not Pending; use DIAGNOSE_POD instead", name, pod.Status.Phase)), nil
+		}
+		pending = append(pending, *pod)
+	} else {
+		pods, err := coreClient.Pods(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodPending)).String(),
+		})
+		if err != nil {
+			reqLogger.Error("Failed to list pending pods", "error", err)
+			return utils.NewKubeErrorResult(err, "failed to list pending pods"), nil
+		}
+		pending = pods.Items
+		// 等待时间最长的Pod排在前面
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].CreationTimestamp.Before(&pending[j].CreationTimestamp)
+		})
+	}
+	report.Count = len(pending)
+	if len(pending) > maxPods {
+		pending = pending[:maxPods]
+		report.Truncated = true
+	}
+	if len(pending) == 0 {
+		return utils.RenderResult(request, report), nil
+	}
+
+	nodes, err := coreClient.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		reqLogger.Error("Failed to list nodes", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list nodes"), nil
+	}
+	scheduled, err := coreClient.Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed,spec.nodeName!=",
+	})
+	if err != nil {
+		reqLogger.Error("Failed to list scheduled pods", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+	allocations := utils.ComputeNodeAllocations(nodes.Items, scheduled.Items)
+
+	schedulerMessages := make(map[string]string)
+	events, err := coreClient.Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{"involvedObject.kind": "Pod", "reason": "FailedScheduling"}.String(),
+	})
+	if err != nil {
+		reqLogger.Warn("Failed to list FailedScheduling events", "error", err)
+	} else {
+		latest := make(map[string]time.Time)
+		for _, event := range events.Items {
+			key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+			eventTime := utils.EventLastSeen(event)
+			if eventTime.Before(latest[key]) {
+				continue
+			}
+			latest[key] = eventTime
+			schedulerMessages[key] = event.Message
+		}
+	}
+
+	for i := range pending {
+		pod := &pending[i]
+		analysis := analyzePendingPod(pod, schedulerMessages[pod.Namespace+"/"+pod.Name], allocations)
+		analysis.Findings = append(analysis.Findings, h.volumeFindings(ctx, pod)...)
+		report.Pods = append(report.Pods, analysis)
+	}
+
+	reqLogger.Info("Pending pod analysis completed", "count", report.Count)
+
+	return utils.RenderResult(request, report), nil
+}
+
+// analyzePendingPod 根据调度器消息和节点分配情况分析单个Pod
+func analyzePendingPod(
+	pod *corev1.Pod,
+	schedulerMessage string,
+	allocations map[string]*utils.NodeAllocation,
+) models.PendingPodAnalysis {
+	requests := utils.PodRequests(pod)
+	analysis := models.PendingPodAnalysis{
+		Name:             pod.Name,
+		Namespace:        pod.Namespace,
+		PendingFor:       utils.FormatDuration(time.Since(pod.CreationTimestamp.Time)),
+		Requests:         resourceListToMap(requests),
+		SchedulerMessage: schedulerMessage,
+		CandidateNodes:   len(allocations),
+		Findings:         []models.SchedulingFinding{},
+	}
+	if schedulerMessage != "" {
+		analysis.Reasons = parseSchedulerMessage(schedulerMessage)
+	}
+
+	nodeNames := make([]string, 0, len(allocations))
+	for nodeName := range allocations {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	// 通过非资源条件的节点，用于计算资源缺口
+	var eligible []*utils.NodeAllocation
+	blocked := make(map[string]int)
+	untolerated := make(map[string]bool)
+	var fitting []string
+	for _, nodeName := range nodeNames {
+		allocation := allocations[nodeName]
+		blockers, taints := nodeBlockers(pod, allocation.Node)
+		for _, taint := range taints {
+			untolerated[taint] = true
+		}
+		if len(blockers) > 0 {
+			for _, blocker := range blockers {
+				blocked[blocker]++
+			}
+			continue
+		}
+		eligible = append(eligible, allocation)
+		if len(insufficientResources(requests, allocation)) == 0 {
+			fitting = append(fitting, nodeName)
+		}
+	}
+
+	analysis.CanFit = len(fitting) > 0
+	if len(fitting) > maxPendingFittingNodes {
+		analysis.FittingNodes = fitting[:maxPendingFittingNodes]
+	} else {
+		analysis.FittingNodes = fitting
+	}
+
+	if analysis.CanFit {
+		analysis.Findings = append(analysis.Findings, models.SchedulingFinding{
+			Category:    schedulingFitsNow,
+			Message:     fmt.Sprintf("按当前的资源分配情况，有%d个节点可以容纳该Pod", len(fitting)),
+			Remediation: "资源可能刚刚释放，调度器会自动重试；若仍无法调度，原因可能是存储卷、主机端口或Pod亲和性，参考reasons中的其他原因",
+		})
+		return analysis
+	}
+
+	if len(eligible) == 0 {
+		analysis.Findings = append(analysis.Findings, constraintFindings(pod, blocked, untolerated)...)
+		return analysis
+	}
+	analysis.Findings = append(analysis.Findings, resourceFindings(requests, eligible)...)
+	return analysis
+}
+
+// constraintFindings 描述所有节点都因污点、节点亲和性或节点状态被排除的情况
+func constraintFindings(pod *corev1.Pod, blocked map[string]int, untolerated map[string]bool) []models.SchedulingFinding {
+	var findings []models.SchedulingFinding
+	if count := blocked[schedulingTaint]; count > 0 {
+		findings = append(findings, models.SchedulingFinding{
+			Category:    schedulingTaint,
+			Message:     fmt.Sprintf("%d个节点存在Pod未容忍的污点", count),
+			Evidence:    firstN(sortedSet(untolerated), maxPendingEvidence),
+			Remediation: "为Pod添加对应的tolerations，或使用NODE_UNTAINT移除节点上的污点",
+		})
+	}
+	if count := blocked[schedulingNodeAffinity]; count > 0 {
+		var evidence []string
+		if len(pod.Spec.NodeSelector) > 0 {
+			evidence = append(evidence, "nodeSelector: "+labels.SelectorFromSet(pod.Spec.NodeSelector).String())
+		}
+		if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil &&
+			pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+			evidence = append(evidence, "requiredDuringSchedulingIgnoredDuringExecution node affinity is set")
+		}
+		findings = append(findings, models.SchedulingFinding{
+			Category:    schedulingNodeAffinity,
+			Message:     fmt.Sprintf("%d个节点不满足nodeSelector或必需的节点亲和性", count),
+			Evidence:    evidence,
+			Remediation: "确认nodeSelector和亲和性中引用的标签确实存在于节点上，或为目标节点添加标签",
+		})
+	}
+	if count := blocked[schedulingUnschedulable]; count > 0 {
+		findings = append(findings, models.SchedulingFinding{
+			Category:    schedulingUnschedulable,
+			Message:     fmt.Sprintf("%d个节点被标记为不可调度（cordon）", count),
+			Remediation: "维护完成后uncordon节点",
+		})
+	}
+	if count := blocked[schedulingNodeNotReady]; count > 0 {
+		findings = append(findings, models.SchedulingFinding{
+			Category:    schedulingNodeNotReady,
+			Message:     fmt.Sprintf("%d个节点未就绪", count),
+			Remediation: "检查节点的kubelet和网络状态",
+		})
+	}
+	return findings
+}
+
+// resourceFindings 比较Pod的资源请求与满足其他调度条件的节点中剩余最多的资源
+func resourceFindings(requests corev1.ResourceList, eligible []*utils.NodeAllocation) []models.SchedulingFinding {
+	var findings []models.SchedulingFinding
+	resourceNames := make([]string, 0, len(requests))
+	for resourceName := range requests {
+		resourceNames = append(resourceNames, string(resourceName))
+	}
+	sort.Strings(resourceNames)
+
+	for _, resourceName := range append(resourceNames, string(corev1.ResourcePods)) {
+		name := corev1.ResourceName(resourceName)
+		request, ok := requests[name]
+		if name == corev1.ResourcePods {
+			request, ok = podSlot(), true
+		}
+		if !ok || request.IsZero() {
+			continue
+		}
+
+		var largestNode string
+		var largest *utils.NodeAllocation
+		for _, allocation := range eligible {
+			free := allocation.Free(name)
+			if largest == nil {
+				largest, largestNode = allocation, allocation.Node.Name
+				continue
+			}
+			largestFree := largest.Free(name)
+			if free.Cmp(largestFree) > 0 {
+				largest, largestNode = allocation, allocation.Node.Name
+			}
+		}
+		largestFree := largest.Free(name)
+		if largestFree.Cmp(request) >= 0 {
+			continue
+		}
+
+		if name == corev1.ResourcePods {
+			findings = append(findings, models.SchedulingFinding{
+				Category:    schedulingTooManyPods,
+				Message:     "满足调度条件的节点都已达到Pod数量上限",
+				Remediation: "扩容节点或提高kubelet的maxPods",
+			})
+			continue
+		}
+		findings = append(findings, models.SchedulingFinding{
+			Category: schedulingInsufficient,
+			Message: fmt.Sprintf("Pod请求%s %s，但满足其他调度条件的节点中剩余最多的%s只有%s",
+				utils.FormatResourceQuantity(name, request), name, largestNode,
+				utils.FormatResourceQuantity(name, largestFree)),
+			Evidence:    largestFreeEvidence(name, eligible),
+			Remediation: fmt.Sprintf("将%s请求降低到%s以下，或扩容节点、释放节点上的资源", name, utils.FormatResourceQuantity(name, largestFree)),
+		})
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, models.SchedulingFinding{
+			Category:    schedulingInsufficient,
+			Message:     "每种资源单独看都有节点能满足，但没有单个节点同时满足所有资源请求",
+			Remediation: "降低其中一项资源请求，或扩容更大规格的节点",
+		})
+	}
+	return findings
+}
+
+// largestFreeEvidence 列出剩余资源最多的若干个节点
+func largestFreeEvidence(name corev1.ResourceName, eligible []*utils.NodeAllocation) []string {
+	sorted := append([]*utils.NodeAllocation(nil), eligible...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Free(name), sorted[j].Free(name)
+		return a.Cmp(b) > 0
+	})
+	var evidence []string
+	for _, allocation := range sorted[:min(len(sorted), maxPendingEvidence)] {
+		allocatable := allocation.Allocatable[name]
+		evidence = append(evidence, fmt.Sprintf("%s: free %s of %s allocatable", allocation.Node.Name,
+			utils.FormatResourceQuantity(name, allocation.Free(name)), utils.FormatResourceQuantity(name, allocatable)))
+	}
+	return evidence
+}
+
+// volumeFindings 检查Pod引用的PVC是否存在并已绑定
+func (h *ResourceHandlerImpl) volumeFindings(ctx context.Context, pod *corev1.Pod) []models.SchedulingFinding {
+	var findings []models.SchedulingFinding
+	pvcClient := h.handler.Client.ClientSet().CoreV1().PersistentVolumeClaims(pod.Namespace)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		pvc, err := pvcClient.Get(ctx, claimName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			findings = append(findings, models.SchedulingFinding{
+				Category:    schedulingUnboundPVC,
+				Message:     fmt.Sprintf("PVC %s不存在", claimName),
+				Remediation: "创建该PVC，或修正Pod中引用的PVC名称",
+			})
+			continue
+		}
+		if err != nil {
+			h.handler.Log.Warn("Failed to get PVC", "pvc", claimName, "namespace", pod.Namespace, "error", err)
+			continue
+		}
+		if pvc.Status.Phase == corev1.ClaimPending {
+			var evidence []string
+			if pvc.Spec.StorageClassName != nil {
+				evidence = append(evidence, "storageClassName: "+*pvc.Spec.StorageClassName)
+			}
+			findings = append(findings, models.SchedulingFinding{
+				Category:    schedulingUnboundPVC,
+				Message:     fmt.Sprintf("PVC %s尚未绑定", claimName),
+				Evidence:    evidence,
+				Remediation: "检查StorageClass是否存在以及provisioner是否正常，或手动创建匹配的PV；WaitForFirstConsumer模式下PVC会在Pod调度后绑定",
+			})
+		}
+	}
+	return findings
+}
+
+// nodeBlockers 返回节点因非资源条件无法容纳Pod的原因类别，以及Pod未容忍的污点
+func nodeBlockers(pod *corev1.Pod, node *corev1.Node) ([]string, []string) {
+	var blockers, untolerated []string
+	if node.Spec.Unschedulable {
+		blockers = append(blockers, schedulingUnschedulable)
+	}
+	if !nodeReady(node) {
+		blockers = append(blockers, schedulingNodeNotReady)
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || toleratesTaint(pod.Spec.Tolerations, taint) {
+			continue
+		}
+		untolerated = append(untolerated, taint.ToString())
+	}
+	if len(untolerated) > 0 {
+		blockers = append(blockers, schedulingTaint)
+	}
+	if !nodeMatchesPod(pod, node) {
+		blockers = append(blockers, schedulingNodeAffinity)
+	}
+	return blockers, untolerated
+}
+
+// insufficientResources 返回节点剩余量不足的资源
+func insufficientResources(requests corev1.ResourceList, allocation *utils.NodeAllocation) []corev1.ResourceName {
+	var insufficient []corev1.ResourceName
+	for name, request := range requests {
+		if request.IsZero() {
+			continue
+		}
+		free := allocation.Free(name)
+		if free.Cmp(request) < 0 {
+			insufficient = append(insufficient, name)
+		}
+	}
+	podsFree := allocation.Free(corev1.ResourcePods)
+	if podsFree.Cmp(podSlot()) < 0 {
+		insufficient = append(insufficient, corev1.ResourcePods)
+	}
+	return insufficient
+}
+
+// podSlot 单个Pod占用的pods资源数量
+func podSlot() resource.Quantity {
+	return *resource.NewQuantity(1, resource.DecimalSI)
+}
+
+// toleratesTaint 判断容忍列表是否容忍指定污点
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeReady 判断节点的Ready条件是否为True
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeMatchesPod 判断节点是否满足Pod的nodeSelector和必需的节点亲和性
+func nodeMatchesPod(pod *corev1.Pod, node *corev1.Node) bool {
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// 多个term之间为或关系
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorOperators 节点选择器运算符与标签选择器运算符的对应关系
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// nodeSelectorTermMatches 判断节点是否满足一个节点选择器term，空term不匹配任何节点
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expression := range term.MatchExpressions {
+		operator, ok := nodeSelectorOperators[expression.Operator]
+		if !ok {
+			return false
+		}
+		requirement, err := labels.NewRequirement(expression.Key, operator, expression.Values)
+		if err != nil || !requirement.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	// matchFields只支持metadata.name
+	for _, field := range term.MatchFields {
+		if field.Key != "metadata.name" {
+			return false
+		}
+		matched := false
+		for _, value := range field.Values {
+			if value == node.Name {
+				matched = true
+			}
+		}
+		if (field.Operator == corev1.NodeSelectorOpIn) != matched {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSchedulerMessage 解析调度器消息，例如"0/5 nodes are available: 2 Insufficient cpu, 3 node(s) had untolerated taint {...}. preemption: ..."
+func parseSchedulerMessage(message string) []models.SchedulingReason {
+	_, detail, found := strings.Cut(message, "nodes are available: ")
+	if !found {
+		return []models.SchedulingReason{{Category: classifySchedulingReason(message), Message: message}}
+	}
+	if index := strings.Index(detail, ". preemption:"); index >= 0 {
+		detail = detail[:index]
+	}
+	detail = strings.TrimSuffix(strings.TrimSpace(detail), ".")
+
+	var reasons []models.SchedulingReason
+	for _, part := range strings.Split(detail, ", ") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		reason := models.SchedulingReason{Message: part}
+		if countStr, text, ok := strings.Cut(part, " "); ok {
+			if count, err := strconv.Atoi(countStr); err == nil {
+				reason.Nodes = count
+				reason.Message = text
+			}
+		}
+		reason.Category = classifySchedulingReason(reason.Message)
+		reasons = append(reasons, reason)
+	}
+	return reasons
+}
+
+// classifySchedulingReason 将调度器给出的原因归类
+func classifySchedulingReason(text string) string {
+	lower := strings.ToLower(text)
+	for _, pattern := range schedulingReasonPatterns {
+		if strings.Contains(lower, pattern.fragment) {
+			return pattern.category
+		}
+	}
+	return schedulingOther
+}
+
+// sortedSet 返回排序后的集合元素
+func sortedSet(set map[string]bool) []string {
+	items := make([]string, 0, len(set))
+	for item := range set {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return items
+}
+
+// firstN 返回切片中前n个元素
+func firstN(items []string, n int) []string {
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
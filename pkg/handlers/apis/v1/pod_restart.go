@@ -0,0 +1,240 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 等待替代Pod就绪的默认时长和最大时长（秒）
+	defaultReplacementWaitSeconds = 120
+	maxReplacementWaitSeconds     = 600
+	// mirrorPodAnnotation kubelet为静态Pod创建的镜像Pod带有的注解
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// RestartPod 删除或驱逐单个Pod，报告其控制器以及是否会被重建，可选等待替代Pod就绪
+// 没有控制器的裸Pod删除后不会恢复，需要confirm=true；静态Pod的镜像Pod拒绝删除
+func (h *ResourceHandlerImpl) RestartPod(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	evict, _ := arguments["evict"].(bool)
+	confirm, _ := arguments["confirm"].(bool)
+	dryRun, _ := arguments["dryRun"].(bool)
+	wait, _ := arguments["wait"].(bool)
+	waitSeconds := defaultReplacementWaitSeconds
+	if value, ok := arguments["waitTimeoutSeconds"].(float64); ok && value > 0 {
+		waitSeconds = min(int(value), maxReplacementWaitSeconds)
+	}
+
+	h.handler.Log.Info("Restarting pod",
+		"name", name,
+		"namespace", namespace,
+		"evict", evict,
+		"confirm", confirm,
+		"dryRun", dryRun,
+		"wait", wait,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+
+	podClient := h.handler.Client.ClientSet().CoreV1().Pods(namespace)
+	pod, err := podClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get pod", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name)), nil
+	}
+
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeRefused,
+			Message: fmt.Sprintf("pod %s is the mirror of a static pod on node %s; deleting it does not restart the containers", name, pod.Spec.NodeName),
+			Hint:    "Static pods are managed by the kubelet from its manifest directory; restart them on the node itself.",
+		}), nil
+	}
+
+	response := models.PodRestartResult{
+		Pod:       pod.Name,
+		Namespace: pod.Namespace,
+		Node:      pod.Spec.NodeName,
+		Action:    "deleted",
+		DryRun:    dryRun,
+	}
+	if evict {
+		response.Action = "evicted"
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	switch {
+	case owner == nil:
+		response.Warning = "pod has no controller and will not be recreated after it is removed"
+		if !confirm {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeRefused,
+				Message: fmt.Sprintf("pod %s has no controller; it will be gone for good once removed", name),
+				Hint:    "Export the pod first if you need to recreate it; pass confirm=true to remove it anyway.",
+				Details: response,
+			}), nil
+		}
+	case owner.Kind == "Job" && pod.Status.Phase == corev1.PodSucceeded:
+		response.Controller = owner.Kind + "/" + owner.Name
+		response.Workload = podWorkload(pod)
+		response.Warning = "pod already completed successfully; the Job will not create a replacement"
+	default:
+		response.Controller = owner.Kind + "/" + owner.Name
+		response.Workload = podWorkload(pod)
+		response.ReplacementExpected = true
+	}
+
+	// 删除前记录同一控制器下已有的Pod，之后新出现的Pod即为替代Pod
+	listOptions := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(pod.Labels).String()}
+	existing := map[types.UID]bool{pod.UID: true}
+	if wait && response.ReplacementExpected {
+		pods, err := podClient.List(ctx, listOptions)
+		if err != nil {
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list pods of %s", response.Controller)), nil
+		}
+		for _, item := range pods.Items {
+			existing[item.UID] = true
+		}
+	}
+
+	if evict {
+		// 驱逐遵守PodDisruptionBudget，无法满足时API Server返回429
+		eviction := &policyv1.Eviction{
+			ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			DeleteOptions: &metav1.DeleteOptions{},
+		}
+		if dryRun {
+			eviction.DeleteOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		if err := podClient.EvictV1(ctx, eviction); err != nil {
+			if apierrors.IsTooManyRequests(err) {
+				return utils.NewToolErrorResult(models.ToolError{
+					Code:    utils.ErrorCodeRefused,
+					Message: fmt.Sprintf("eviction of pod %s is blocked by a PodDisruptionBudget: %v", name, err),
+					Reason:  string(apierrors.ReasonForError(err)),
+					Hint:    "Wait until more replicas are ready, or use CHECK_AVAILABILITY to inspect the PDB; evict=false deletes the pod without honoring the PDB.",
+				}), nil
+			}
+			h.handler.Log.Error("Failed to evict pod", "name", name, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to evict pod %s", name)), nil
+		}
+	} else {
+		if denied := h.handler.PreflightCheck(ctx, "delete", podsGVR, namespace, name); denied != nil {
+			return denied, nil
+		}
+		deleteOptions := metav1.DeleteOptions{}
+		if dryRun {
+			deleteOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		if err := podClient.Delete(ctx, name, deleteOptions); err != nil {
+			h.handler.Log.Error("Failed to delete pod", "name", name, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to delete pod %s", name)), nil
+		}
+	}
+
+	if !wait || dryRun || !response.ReplacementExpected {
+		return utils.RenderResult(request, response), nil
+	}
+
+	// 轮询同一控制器下新出现的Pod，直到其中一个就绪、等待超时或调用超时；StatefulSet的替代Pod与原Pod同名但UID不同
+	start := time.Now()
+	deadline := time.NewTimer(time.Duration(waitSeconds) * time.Second)
+	defer deadline.Stop()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	progress := utils.ProgressFromContext(ctx)
+poll:
+	for {
+		pods, err := podClient.List(ctx, listOptions)
+		if err == nil {
+			if replacement := findReplacementPod(pods.Items, existing, owner); replacement != nil {
+				response.Replacement = replacement
+				if replacement.Ready {
+					break poll
+				}
+				progress.Report(ctx, fmt.Sprintf("replacement pod %s is %s, waiting for it to become ready", replacement.Name, replacement.Phase))
+			} else {
+				progress.Report(ctx, fmt.Sprintf("waiting for %s %s to create a replacement pod", owner.Kind, owner.Name))
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			response.TimedOut = true
+			break poll
+		case <-ctx.Done():
+			response.TimedOut = true
+			break poll
+		}
+	}
+
+	response.Waited = time.Since(start).Round(time.Second).String()
+	if response.TimedOut {
+		response.Hint = "No ready replacement yet; check it with DIAGNOSE_POD or ANALYZE_PENDING_PODS, or call WATCH_RESOURCE to keep observing."
+		h.handler.Log.Warn("Replacement pod not ready after wait", "name", name, "namespace", namespace, "waited", response.Waited)
+		progress.Done(ctx, fmt.Sprintf("no ready replacement pod after %s", response.Waited))
+	} else {
+		progress.Done(ctx, fmt.Sprintf("replacement pod %s is ready", response.Replacement.Name))
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// findReplacementPod 在同一控制器管理的Pod中查找删除前不存在的新Pod，优先返回已就绪的Pod
+func findReplacementPod(pods []corev1.Pod, existing map[types.UID]bool, owner *metav1.OwnerReference) *models.ReplacementPod {
+	var found *models.ReplacementPod
+	for i := range pods {
+		candidate := &pods[i]
+		controller := metav1.GetControllerOf(candidate)
+		if existing[candidate.UID] || candidate.DeletionTimestamp != nil || controller == nil || controller.UID != owner.UID {
+			continue
+		}
+		replacement := &models.ReplacementPod{
+			Name:  candidate.Name,
+			Node:  candidate.Spec.NodeName,
+			Phase: string(candidate.Status.Phase),
+			Ready: podReady(candidate),
+		}
+		if replacement.Ready {
+			return replacement
+		}
+		if found == nil {
+			found = replacement
+		}
+	}
+	return found
+}
+
+// podReady 判断Pod的Ready条件是否为True
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
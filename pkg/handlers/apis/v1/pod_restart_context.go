@@ -0,0 +1,229 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认读取的上一个实例末尾日志行数和当前实例开头日志行数
+	defaultRestartContextLines = 50
+	// 两段日志各自的行数上限
+	maxRestartContextLines = 500
+)
+
+// GetRestartContext 返回重启过的容器上一个实例结束前的日志、终止原因和退出码，以及当前实例启动时的日志，
+// 并对上一个实例的日志运行日志分析器。未指定容器时选择重启次数最多的容器
+func (h *ResourceHandlerImpl) GetRestartContext(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	containerName, _ := arguments["container"].(string)
+	tailLines := restartContextLines(arguments, "tailLines")
+	headLines := restartContextLines(arguments, "headLines")
+
+	h.handler.Log.Info("Getting restart context",
+		"name", name,
+		"namespace", namespace,
+		"container", containerName,
+		"tailLines", tailLines,
+		"headLines", headLines,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+
+	pod, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to get pod", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get pod %s", name)), nil
+	}
+
+	status, init := restartedContainerStatus(pod, containerName)
+	if status == nil {
+		if containerName == "" {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: fmt.Sprintf("no container in pod %s has restarted", name),
+				Hint:    "Use DIAGNOSE_POD to inspect a pod whose containers have not restarted.",
+			}), nil
+		}
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeNotFound,
+			Message: fmt.Sprintf("container %s not found in pod %s", containerName, name),
+		}), nil
+	}
+	if status.RestartCount == 0 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("container %s in pod %s has not restarted", status.Name, name),
+			Hint:    "Use GET_POD_LOGS for the logs of a container that has not restarted.",
+		}), nil
+	}
+
+	response := models.RestartContext{
+		Pod:          pod.Name,
+		Namespace:    pod.Namespace,
+		Container:    status.Name,
+		Init:         init,
+		RestartCount: status.RestartCount,
+		RetrievedAt:  time.Now(),
+	}
+	response.State, response.StateReason, _ = containerStateSummary(status)
+	if last := status.LastTerminationState.Terminated; last != nil {
+		response.Termination = restartTermination(last)
+	}
+
+	// 上一个实例的日志随容器被kubelet或容器运行时回收后不再可用，明确说明而不是返回空日志
+	previous, err := h.readContainerLogs(ctx, pod, status.Name, true, tailLines)
+	switch {
+	case apierrors.IsForbidden(err):
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to read logs of pod %s", name)), nil
+	case err != nil:
+		response.PreviousTail = models.RestartLogSection{
+			Lines:       []string{},
+			Unavailable: fmt.Sprintf("logs of the previous instance are no longer available, the terminated container has likely been garbage-collected by the kubelet or container runtime: %v", err),
+		}
+	case len(previous) == 0:
+		response.PreviousTail = models.RestartLogSection{
+			Available:   true,
+			Lines:       []string{},
+			Unavailable: "the previous instance wrote no log output",
+		}
+	default:
+		response.PreviousTail = models.RestartLogSection{Available: true, Lines: previous, Truncated: len(previous) >= tailLines}
+		analysis := utils.NewLogAnalyzer().AnalyzeLogs(previous)
+		response.PreviousAnalysis = &models.RestartLogAnalysis{
+			ErrorCount:   analysis.ErrorCount,
+			WarningCount: analysis.WarningCount,
+			TopErrors:    topKeys(analysis.TopErrors, maxDiagnoseTopErrors),
+		}
+	}
+
+	// 容器处于等待状态时新实例尚未启动，此时读取"当前"日志得到的仍是上一个实例
+	if status.State.Waiting != nil {
+		response.CurrentHead = models.RestartLogSection{
+			Lines:       []string{},
+			Unavailable: fmt.Sprintf("the container is waiting (%s) and no new instance has started yet; previousTail shows the instance that exited last", status.State.Waiting.Reason),
+		}
+	} else {
+		head, truncated, err := h.readContainerLogHead(ctx, pod, status.Name, headLines)
+		switch {
+		case err != nil:
+			response.CurrentHead = models.RestartLogSection{Lines: []string{}, Unavailable: fmt.Sprintf("failed to read logs of the current instance: %v", err)}
+		case len(head) == 0:
+			response.CurrentHead = models.RestartLogSection{Available: true, Lines: []string{}, Unavailable: "the current instance has written no log output yet"}
+		default:
+			response.CurrentHead = models.RestartLogSection{Available: true, Lines: head, Truncated: truncated}
+		}
+	}
+
+	switch {
+	case response.Termination != nil && response.Termination.Reason == "OOMKilled":
+		response.Hint = "The previous instance was OOMKilled; compare its memory limit with usage via GET_CONTAINER_TERMINATIONS."
+	case !response.PreviousTail.Available:
+		response.Hint = "Previous logs are gone; rely on the termination reason, exit code and GET_EVENTS, or ship container logs to a central store to keep them across restarts."
+	}
+	return utils.RenderResult(request, response), nil
+}
+
+// restartedContainerStatus 返回指定容器的状态，未指定时返回重启次数最多的容器（包括init容器）
+func restartedContainerStatus(pod *corev1.Pod, container string) (*corev1.ContainerStatus, bool) {
+	var (
+		found *corev1.ContainerStatus
+		init  bool
+	)
+	for _, group := range []struct {
+		statuses []corev1.ContainerStatus
+		init     bool
+	}{{pod.Status.InitContainerStatuses, true}, {pod.Status.ContainerStatuses, false}} {
+		for i := range group.statuses {
+			status := &group.statuses[i]
+			if container != "" {
+				if status.Name == container {
+					return status, group.init
+				}
+				continue
+			}
+			if status.RestartCount > 0 && (found == nil || status.RestartCount > found.RestartCount) {
+				found, init = status, group.init
+			}
+		}
+	}
+	return found, init
+}
+
+// restartTermination 将容器的终止状态转换为响应结构
+func restartTermination(state *corev1.ContainerStateTerminated) *models.RestartTermination {
+	termination := &models.RestartTermination{
+		Reason:   state.Reason,
+		ExitCode: state.ExitCode,
+		Signal:   state.Signal,
+		Message:  state.Message,
+	}
+	if !state.StartedAt.IsZero() {
+		startedAt := state.StartedAt.Time
+		termination.StartedAt = &startedAt
+	}
+	if !state.FinishedAt.IsZero() {
+		finishedAt := state.FinishedAt.Time
+		termination.FinishedAt = &finishedAt
+	}
+	if termination.StartedAt != nil && termination.FinishedAt != nil {
+		termination.RanFor = termination.FinishedAt.Sub(*termination.StartedAt).Round(time.Second).String()
+	}
+	return termination
+}
+
+// readContainerLogHead 读取容器当前实例最前面的若干行日志，返回的布尔值表示还有更多日志
+func (h *ResourceHandlerImpl) readContainerLogHead(
+	ctx context.Context,
+	pod *corev1.Pod,
+	container string,
+	lines int,
+) ([]string, bool, error) {
+	limitBytes := int64(maxDiagnoseLogBytes)
+	stream, err := h.handler.Client.ClientSet().CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+		LimitBytes: &limitBytes,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer stream.Close()
+
+	var head []string
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxDiagnoseLogBytes)
+	for scanner.Scan() {
+		if len(head) == lines {
+			return head, true, nil
+		}
+		head = append(head, scanner.Text())
+	}
+	return head, false, scanner.Err()
+}
+
+// restartContextLines 读取日志行数参数，未指定时使用默认值，超过上限时截断
+func restartContextLines(arguments map[string]interface{}, key string) int {
+	if value, ok := arguments[key].(float64); ok && value > 0 {
+		return min(int(value), maxRestartContextLines)
+	}
+	return defaultRestartContextLines
+}
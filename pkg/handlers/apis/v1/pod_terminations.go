@@ -0,0 +1,310 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认返回的最严重终止记录数量
+	defaultTerminationOffenders = 20
+	// 每条终止记录附带的事件数量
+	maxTerminationEvents = 3
+)
+
+// defaultTerminationReasons 默认统计的终止原因
+var defaultTerminationReasons = []string{"OOMKilled", "Error", "ContainerCannotRun"}
+
+// terminationEventReasons 与容器终止相关的事件原因
+var terminationEventReasons = map[string]bool{
+	"Killing": true,
+	"BackOff": true,
+}
+
+// GetContainerTerminations 统计容器因OOMKilled等原因异常终止的记录，并按工作负载汇总
+func (h *ResourceHandlerImpl) GetContainerTerminations(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	reasonsStr, _ := arguments["reasons"].(string)
+	maxOffenders := defaultTerminationOffenders
+	if v, ok := arguments["maxOffenders"].(float64); ok && v > 0 {
+		maxOffenders = int(v)
+	}
+
+	reqLogger := h.handler.Log.With("namespace", namespace, "labelSelector", labelSelector)
+	reqLogger.Info("Collecting container terminations", "reasons", reasonsStr)
+
+	report := models.ContainerTerminationReport{
+		Namespace:      namespace,
+		LabelSelector:  labelSelector,
+		Reasons:        defaultTerminationReasons,
+		ByReason:       make(map[string]int),
+		Workloads:      []models.WorkloadTerminations{},
+		WorstOffenders: []models.ContainerTermination{},
+		RetrievedAt:    time.Now(),
+	}
+	if reasonsStr != "" {
+		report.Reasons = nil
+		for _, reason := range strings.Split(reasonsStr, ",") {
+			if reason = strings.TrimSpace(reason); reason != "" {
+				report.Reasons = append(report.Reasons, reason)
+			}
+		}
+	}
+	reasons := make(map[string]bool, len(report.Reasons))
+	for _, reason := range report.Reasons {
+		reasons[reason] = true
+	}
+	if v, ok := arguments["sinceMinutes"].(float64); ok && v > 0 {
+		since := report.RetrievedAt.Add(-time.Duration(v) * time.Minute)
+		report.Since = &since
+	}
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	pods, err := coreClient.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		reqLogger.Error("Failed to list pods", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+	report.ScannedPods = len(pods.Items)
+
+	var terminations []models.ContainerTermination
+	for i := range pods.Items {
+		terminations = append(terminations, podTerminations(&pods.Items[i], reasons, report.Since)...)
+	}
+
+	if len(terminations) > 0 {
+		report.MetricsAvailable = h.attachMemoryUsage(ctx, namespace, labelSelector, memoryLimits(pods.Items), terminations)
+		backOffs := h.attachTerminationEvents(ctx, namespace, terminations)
+		report.Workloads = summarizeTerminations(terminations, backOffs)
+	}
+
+	report.Total = len(terminations)
+	for _, termination := range terminations {
+		report.ByReason[termination.Reason]++
+	}
+
+	sort.SliceStable(terminations, func(i, j int) bool {
+		if terminations[i].RestartCount != terminations[j].RestartCount {
+			return terminations[i].RestartCount > terminations[j].RestartCount
+		}
+		return terminations[i].FinishedAt.After(terminations[j].FinishedAt)
+	})
+	if len(terminations) > maxOffenders {
+		terminations = terminations[:maxOffenders]
+	}
+	report.WorstOffenders = append(report.WorstOffenders, terminations...)
+
+	reqLogger.Info("Container terminations collected", "total", report.Total, "workloads", len(report.Workloads))
+
+	return utils.RenderResult(request, report), nil
+}
+
+// podTerminations 提取Pod中终止原因匹配且在时间窗口内的容器终止记录
+// 优先使用上一个实例的终止状态；容器未被重启时（例如restartPolicy为Never）使用当前实例的终止状态
+func podTerminations(pod *corev1.Pod, reasons map[string]bool, since *time.Time) []models.ContainerTermination {
+	specs := make(map[string]corev1.Container, len(pod.Spec.Containers)+len(pod.Spec.InitContainers))
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		specs[container.Name] = container
+	}
+	workload := podWorkload(pod)
+
+	var result []models.ContainerTermination
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		terminated, current := status.LastTerminationState.Terminated, false
+		if terminated == nil && status.State.Terminated != nil {
+			terminated, current = status.State.Terminated, true
+		}
+		if terminated == nil || !reasons[terminated.Reason] {
+			continue
+		}
+		if since != nil && terminated.FinishedAt.Time.Before(*since) {
+			continue
+		}
+
+		termination := models.ContainerTermination{
+			Workload:     workload,
+			Namespace:    pod.Namespace,
+			Pod:          pod.Name,
+			Container:    status.Name,
+			Reason:       terminated.Reason,
+			ExitCode:     terminated.ExitCode,
+			Signal:       terminated.Signal,
+			FinishedAt:   terminated.FinishedAt.Time,
+			RestartCount: status.RestartCount,
+			Current:      current,
+		}
+		spec := specs[status.Name]
+		if request, ok := spec.Resources.Requests[corev1.ResourceMemory]; ok {
+			termination.MemoryRequest = utils.FormatResourceQuantity(corev1.ResourceMemory, request)
+		}
+		if limit, ok := spec.Resources.Limits[corev1.ResourceMemory]; ok {
+			termination.MemoryLimit = utils.FormatResourceQuantity(corev1.ResourceMemory, limit)
+		}
+		result = append(result, termination)
+	}
+	return result
+}
+
+// podWorkload 返回Pod所属的工作负载，ReplicaSet根据pod-template-hash标签还原为Deployment
+func podWorkload(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod/" + pod.Name
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment/" + strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return owner.Kind + "/" + owner.Name
+}
+
+// attachMemoryUsage 从metrics-server读取容器当前的内存用量，metrics不可用时返回false
+func (h *ResourceHandlerImpl) attachMemoryUsage(
+	ctx context.Context,
+	namespace, labelSelector string,
+	limits map[string]resource.Quantity,
+	terminations []models.ContainerTermination,
+) bool {
+	podMetrics, err := h.handler.Client.GetMetricsClient().MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		h.handler.Log.Debug("Pod metrics unavailable", "error", err)
+		return false
+	}
+
+	usage := make(map[string]resource.Quantity)
+	for _, metric := range podMetrics.Items {
+		for _, container := range metric.Containers {
+			usage[metric.Namespace+"/"+metric.Name+"/"+container.Name] = container.Usage[corev1.ResourceMemory]
+		}
+	}
+	for i := range terminations {
+		termination := &terminations[i]
+		key := termination.Namespace + "/" + termination.Pod + "/" + termination.Container
+		memory, ok := usage[key]
+		if !ok {
+			continue
+		}
+		termination.MemoryUsage = utils.FormatResourceQuantity(corev1.ResourceMemory, memory)
+		if limit, ok := limits[key]; ok && limit.Value() > 0 {
+			percent := float64(memory.Value()) / float64(limit.Value()) * 100
+			percent = float64(int(percent*10)) / 10
+			termination.MemoryUsagePercent = &percent
+		}
+	}
+	return true
+}
+
+// memoryLimits 返回各容器的内存limit，键为"namespace/pod/container"
+func memoryLimits(pods []corev1.Pod) map[string]resource.Quantity {
+	limits := make(map[string]resource.Quantity)
+	for _, pod := range pods {
+		for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+				limits[pod.Namespace+"/"+pod.Name+"/"+container.Name] = limit
+			}
+		}
+	}
+	return limits
+}
+
+// attachTerminationEvents 为终止记录附带Pod的Killing/BackOff事件，返回每个Pod的BackOff事件次数
+func (h *ResourceHandlerImpl) attachTerminationEvents(
+	ctx context.Context,
+	namespace string,
+	terminations []models.ContainerTermination,
+) map[string]int32 {
+	backOffs := make(map[string]int32)
+	events, err := h.handler.Client.ClientSet().CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.kind", "Pod").String(),
+	})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list pod events", "error", err)
+		return backOffs
+	}
+
+	byPod := make(map[string][]corev1.Event)
+	for _, event := range events.Items {
+		if !terminationEventReasons[event.Reason] {
+			continue
+		}
+		key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+		byPod[key] = append(byPod[key], event)
+		if event.Reason == "BackOff" {
+			backOffs[key] += max(event.Count, 1)
+		}
+	}
+	for i := range terminations {
+		podEvents := byPod[terminations[i].Namespace+"/"+terminations[i].Pod]
+		sort.Slice(podEvents, func(a, b int) bool {
+			return utils.EventLastSeen(podEvents[a]).After(utils.EventLastSeen(podEvents[b]))
+		})
+		for _, event := range podEvents[:min(len(podEvents), maxTerminationEvents)] {
+			terminations[i].Events = append(terminations[i].Events, fmt.Sprintf("%s %s(%d): %s",
+				utils.EventLastSeen(event).Format(time.RFC3339), event.Reason, max(event.Count, 1), event.Message))
+		}
+	}
+	return backOffs
+}
+
+// summarizeTerminations 按工作负载汇总终止记录，终止次数多的工作负载排在前面
+func summarizeTerminations(terminations []models.ContainerTermination, backOffs map[string]int32) []models.WorkloadTerminations {
+	summaries := make(map[string]*models.WorkloadTerminations)
+	pods := make(map[string]map[string]bool)
+	for _, termination := range terminations {
+		key := termination.Namespace + "/" + termination.Workload
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &models.WorkloadTerminations{
+				Workload:  termination.Workload,
+				Namespace: termination.Namespace,
+				ByReason:  make(map[string]int),
+			}
+			summaries[key] = summary
+			pods[key] = make(map[string]bool)
+		}
+		summary.ByReason[termination.Reason]++
+		summary.Restarts += termination.RestartCount
+		if termination.FinishedAt.After(summary.LastFinished) {
+			summary.LastFinished = termination.FinishedAt
+		}
+		if !pods[key][termination.Pod] {
+			pods[key][termination.Pod] = true
+			summary.Pods++
+			summary.BackOffEvents += backOffs[termination.Namespace+"/"+termination.Pod]
+		}
+	}
+
+	result := make([]models.WorkloadTerminations, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Restarts != result[j].Restarts {
+			return result[i].Restarts > result[j].Restarts
+		}
+		return result[i].Namespace+"/"+result[i].Workload < result[j].Namespace+"/"+result[j].Workload
+	})
+	return result
+}
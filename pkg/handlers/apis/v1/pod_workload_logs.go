@@ -0,0 +1,382 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 每个Pod读取的默认日志行数和上限
+	defaultWorkloadLogLines = 100
+	maxWorkloadLogLines     = 1000
+	// 默认读取的Pod数量和上限
+	defaultWorkloadLogPods = 20
+	maxWorkloadLogPods     = 100
+	// 所有Pod日志合计的默认字节预算和上限
+	defaultWorkloadLogBytes = 256 * 1024
+	maxWorkloadLogBytes     = 1024 * 1024
+	// workloadLogWorkers 同时读取日志的Pod数量
+	workloadLogWorkers = 5
+	// defaultContainerAnnotation kubectl用来选择默认容器的注解
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+)
+
+// workloadPodLogs 单个Pod的日志读取结果，lines为带时间戳的原始日志行
+type workloadPodLogs struct {
+	section models.WorkloadPodLogs
+	lines   []string
+}
+
+// GetWorkloadLogs 通过选择器找到工作负载的所有Pod，并发读取每个Pod末尾的日志，
+// 在字节预算内返回每个Pod的日志以及按时间戳合并的视图；单个Pod读取失败只记录在该Pod中
+func (h *ResourceHandlerImpl) GetWorkloadLogs(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	container, _ := arguments["container"].(string)
+	previous, _ := arguments["previous"].(bool)
+	timestamps := true
+	if value, ok := arguments["timestamps"].(bool); ok {
+		timestamps = value
+	}
+	tailLines := defaultWorkloadLogLines
+	if value, ok := arguments["tailLines"].(float64); ok && value > 0 {
+		tailLines = min(int(value), maxWorkloadLogLines)
+	}
+	maxPods := defaultWorkloadLogPods
+	if value, ok := arguments["maxPods"].(float64); ok && value > 0 {
+		maxPods = min(int(value), maxWorkloadLogPods)
+	}
+	maxBytes := defaultWorkloadLogBytes
+	if value, ok := arguments["maxBytes"].(float64); ok && value > 0 {
+		maxBytes = min(int(value), maxWorkloadLogBytes)
+	}
+
+	reqLogger := h.handler.Log.With("kind", kind, "name", name, "namespace", namespace)
+	reqLogger.Info("Getting workload logs",
+		"container", container,
+		"tailLines", tailLines,
+		"previous", previous,
+		"maxPods", maxPods,
+		"maxBytes", maxBytes,
+	)
+
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "Job":
+	default:
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("unsupported kind %q: must be one of Deployment, StatefulSet, DaemonSet, Job", kind),
+		}), nil
+	}
+	selector, err := h.workloadPodSelector(ctx, kind, namespace, name)
+	if err != nil {
+		reqLogger.Error("Failed to resolve workload selector", "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+	}
+
+	list, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		reqLogger.Error("Failed to list workload pods", "selector", selector, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list pods of %s %s", kind, name)), nil
+	}
+	pods := list.Items
+	if len(pods) == 0 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeNotFound,
+			Message: fmt.Sprintf("%s %s has no pods matching selector %q", kind, name, selector),
+			Hint:    "The workload may be scaled to zero or its pods may not have been created yet; check its status with DESCRIBE tools or GET_EVENTS.",
+		}), nil
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	response := models.WorkloadLogsResponse{
+		Kind:        kind,
+		Name:        name,
+		Namespace:   namespace,
+		Selector:    selector,
+		Container:   container,
+		TailLines:   tailLines,
+		Previous:    previous,
+		Timestamps:  timestamps,
+		PodsMatched: len(pods),
+		MaxBytes:    maxBytes,
+		Pods:        []models.WorkloadPodLogs{},
+		RetrievedAt: time.Now(),
+	}
+	if len(pods) > maxPods {
+		pods = pods[:maxPods]
+		response.Truncated = true
+	}
+
+	results := h.fetchWorkloadPodLogs(ctx, pods, container, previous, tailLines)
+	response.BudgetExceeded = fitLogBudget(results, maxBytes)
+
+	var merged []models.WorkloadLogEntry
+	for _, result := range results {
+		section := result.section
+		if section.Error != "" {
+			response.PodsFailed++
+			response.Pods = append(response.Pods, section)
+			continue
+		}
+		response.PodsFetched++
+		// 始终带时间戳读取以便合并，未启用时间戳时从返回内容中去掉
+		entries := utils.ParseLogEntries(result.lines, true, utils.LogOutputLines)
+		if timestamps {
+			section.Logs = strings.Join(result.lines, "\n")
+			merged = appendMergedEntries(merged, section.Pod, entries)
+		} else {
+			texts := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				texts = append(texts, entry.Line)
+			}
+			section.Logs = strings.Join(texts, "\n")
+		}
+		response.TotalBytes += section.Bytes
+		response.Pods = append(response.Pods, section)
+	}
+	if timestamps {
+		// 没有时间戳的条目排在最前，同一时间的条目保持各Pod内的顺序
+		sort.SliceStable(merged, func(i, j int) bool {
+			if merged[j].Timestamp == nil {
+				return false
+			}
+			return merged[i].Timestamp == nil || merged[i].Timestamp.Before(*merged[j].Timestamp)
+		})
+		response.Merged = merged
+	}
+
+	reqLogger.Info("Workload logs fetched",
+		"pods", len(pods),
+		"failed", response.PodsFailed,
+		"bytes", response.TotalBytes,
+		"budgetExceeded", response.BudgetExceeded,
+	)
+	return utils.RenderResult(request, response), nil
+}
+
+// workloadPodSelector 返回Deployment、StatefulSet、DaemonSet或Job的Pod选择器
+func (h *ResourceHandlerImpl) workloadPodSelector(ctx context.Context, kind, namespace, name string) (string, error) {
+	clientset := h.handler.Client.ClientSet()
+	var labelSelector *metav1.LabelSelector
+	switch kind {
+	case "Deployment":
+		workload, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		labelSelector = workload.Spec.Selector
+	case "StatefulSet":
+		workload, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		labelSelector = workload.Spec.Selector
+	case "DaemonSet":
+		workload, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		labelSelector = workload.Spec.Selector
+	case "Job":
+		workload, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		labelSelector = workload.Spec.Selector
+	default:
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector of %s %s: %w", kind, name, err)
+	}
+	if selector.Empty() {
+		return "", fmt.Errorf("%s %s has an empty selector", kind, name)
+	}
+	return selector.String(), nil
+}
+
+// fetchWorkloadPodLogs 由固定数量的worker并发读取每个Pod的日志，结果与pods的顺序一致
+func (h *ResourceHandlerImpl) fetchWorkloadPodLogs(
+	ctx context.Context,
+	pods []corev1.Pod,
+	container string,
+	previous bool,
+	tailLines int,
+) []workloadPodLogs {
+	results := make([]workloadPodLogs, len(pods))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workloadLogWorkers, len(pods)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = h.fetchPodLogs(ctx, &pods[i], container, previous, tailLines)
+			}
+		}()
+	}
+	for i := range pods {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// fetchPodLogs 读取单个Pod中容器的日志，容器尚未启动或没有上一个实例时不发起请求，直接记录原因
+func (h *ResourceHandlerImpl) fetchPodLogs(
+	ctx context.Context,
+	pod *corev1.Pod,
+	container string,
+	previous bool,
+	tailLines int,
+) workloadPodLogs {
+	if container == "" {
+		container = defaultLogContainer(pod)
+	}
+	result := workloadPodLogs{section: models.WorkloadPodLogs{
+		Pod:       pod.Name,
+		Container: container,
+		Node:      pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+	}}
+
+	var status *corev1.ContainerStatus
+	found := false
+	for _, spec := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		found = found || spec.Name == container
+	}
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == container {
+			status = &pod.Status.ContainerStatuses[i]
+		}
+	}
+	for i := range pod.Status.InitContainerStatuses {
+		if pod.Status.InitContainerStatuses[i].Name == container {
+			status = &pod.Status.InitContainerStatuses[i]
+		}
+	}
+	switch {
+	case !found:
+		result.section.Error = fmt.Sprintf("pod has no container %q", container)
+		return result
+	case previous && (status == nil || status.LastTerminationState.Terminated == nil):
+		result.section.Error = fmt.Sprintf("container %q has no previous instance", container)
+		return result
+	case !previous && (status == nil || (status.State.Running == nil && status.State.Terminated == nil)):
+		reason := "has not started"
+		if status != nil && status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			reason = "is waiting: " + status.State.Waiting.Reason
+		}
+		result.section.Error = fmt.Sprintf("pod is %s and container %q %s", pod.Status.Phase, container, reason)
+		return result
+	}
+
+	lines, err := h.readLogLines(ctx, pod.Namespace, pod.Name, container, previous, tailLines)
+	if err != nil {
+		h.handler.Log.Warn("Failed to read workload pod logs", "pod", pod.Name, "container", container, "error", err)
+		result.section.Error = err.Error()
+		return result
+	}
+	result.lines = lines
+	return result
+}
+
+// defaultLogContainer 返回kubectl默认的容器：default-container注解指定的容器，否则为第一个容器
+func defaultLogContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// selectLogContainer 未指定容器时为GET_POD_LOGS选择容器：default-container注解指定的容器，否则为唯一的应用容器。
+// 有多个应用容器时返回空字符串，由调用方要求指定容器；Pod中还有其他容器时返回选择的说明
+func selectLogContainer(pod *corev1.Pod) (string, string) {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" && podContainerRole(pod, name) != "" {
+		return name, fmt.Sprintf("container not specified; using %s from the %s annotation", name, defaultContainerAnnotation)
+	}
+	apps := appContainerNames(pod)
+	if len(apps) != 1 {
+		return "", ""
+	}
+	if len(pod.Spec.InitContainers)+len(pod.Spec.Containers)+len(pod.Spec.EphemeralContainers) == 1 {
+		return apps[0], ""
+	}
+	return apps[0], fmt.Sprintf("container not specified; selected %s, the only app container in the pod", apps[0])
+}
+
+// fitLogBudget 在所有Pod之间公平分配字节预算：日志较少的Pod保留全部，剩余预算由其余Pod均分，
+// 超出分配的Pod丢弃较早的日志行。返回是否丢弃了日志
+func fitLogBudget(results []workloadPodLogs, maxBytes int) bool {
+	sizes := make([]int, len(results))
+	order := make([]int, 0, len(results))
+	for i := range results {
+		for _, line := range results[i].lines {
+			sizes[i] += len(line) + 1
+		}
+		if results[i].section.Error == "" {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool { return sizes[order[a]] < sizes[order[b]] })
+
+	exceeded := false
+	remaining := maxBytes
+	for position, i := range order {
+		allowance := remaining / (len(order) - position)
+		lines := results[i].lines
+		size := sizes[i]
+		// 保留最新的日志，从最早的行开始丢弃
+		for len(lines) > 0 && size > allowance {
+			size -= len(lines[0]) + 1
+			lines = lines[1:]
+			results[i].section.DroppedLines++
+		}
+		if results[i].section.DroppedLines > 0 {
+			exceeded = true
+		}
+		results[i].lines = lines
+		results[i].section.LineCount = len(lines)
+		results[i].section.Bytes = size
+		remaining -= size
+	}
+	return exceeded
+}
+
+// appendMergedEntries 将Pod的日志条目加入合并视图，没有时间戳的条目沿用上一条的时间戳以保持相对顺序
+func appendMergedEntries(merged []models.WorkloadLogEntry, pod string, entries []models.LogEntry) []models.WorkloadLogEntry {
+	var last *time.Time
+	for _, entry := range entries {
+		if entry.Timestamp != nil {
+			last = entry.Timestamp
+		}
+		merged = append(merged, models.WorkloadLogEntry{Timestamp: last, Pod: pod, Line: entry.Line})
+	}
+	return merged
+}
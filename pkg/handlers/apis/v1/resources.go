@@ -3,7 +3,6 @@ package v1
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -12,9 +11,8 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
@@ -23,8 +21,26 @@ import (
 )
 
 const (
-	GET_POD_LOGS     = "GET_POD_LOGS"
-	ANALYZE_POD_LOGS = "ANALYZE_POD_LOGS"
+	GET_POD_LOGS               = "GET_POD_LOGS"
+	ANALYZE_POD_LOGS           = "ANALYZE_POD_LOGS"
+	DIAGNOSE_POD               = "DIAGNOSE_POD"
+	FIND_CRASHLOOPING_PODS     = "FIND_CRASHLOOPING_PODS"
+	ANALYZE_PENDING_PODS       = "ANALYZE_PENDING_PODS"
+	GET_CONTAINER_TERMINATIONS = "GET_CONTAINER_TERMINATIONS"
+	GET_SECRET_KEYS            = "GET_SECRET_KEYS"
+	GET_CONFIGMAP              = "GET_CONFIGMAP"
+	RESTART_POD                = "RESTART_POD"
+	CAN_SCHEDULE               = "CAN_SCHEDULE"
+	LIST_POD_FILES             = "LIST_POD_FILES"
+	READ_POD_FILE              = "READ_POD_FILE"
+	COPY_TO_POD                = "COPY_TO_POD"
+	COPY_FROM_POD              = "COPY_FROM_POD"
+	CHECK_DNS                  = "CHECK_DNS"
+	DEBUG_POD                  = "DEBUG_POD"
+	GET_RESTART_CONTEXT        = "GET_RESTART_CONTEXT"
+	GET_WORKLOAD_LOGS          = "GET_WORKLOAD_LOGS"
+	CHECK_CERTIFICATES         = "CHECK_CERTIFICATES"
+	GET_WORKLOAD_SPREAD        = "GET_WORKLOAD_SPREAD"
 )
 
 // ResourceHandlerImpl 核心资源处理程序实现
@@ -54,6 +70,42 @@ func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRe
 		return h.GetPodLogs(ctx, request)
 	case ANALYZE_POD_LOGS:
 		return h.AnalyzePodLogs(ctx, request)
+	case DIAGNOSE_POD:
+		return h.DiagnosePod(ctx, request)
+	case FIND_CRASHLOOPING_PODS:
+		return h.FindCrashLoopingPods(ctx, request)
+	case ANALYZE_PENDING_PODS:
+		return h.AnalyzePendingPods(ctx, request)
+	case GET_CONTAINER_TERMINATIONS:
+		return h.GetContainerTerminations(ctx, request)
+	case GET_SECRET_KEYS:
+		return h.GetSecretKeys(ctx, request)
+	case GET_CONFIGMAP:
+		return h.GetConfigMap(ctx, request)
+	case RESTART_POD:
+		return h.RestartPod(ctx, request)
+	case CAN_SCHEDULE:
+		return h.CanSchedule(ctx, request)
+	case LIST_POD_FILES:
+		return h.ListPodFiles(ctx, request)
+	case READ_POD_FILE:
+		return h.ReadPodFile(ctx, request)
+	case COPY_TO_POD:
+		return h.CopyToPod(ctx, request)
+	case COPY_FROM_POD:
+		return h.CopyFromPod(ctx, request)
+	case CHECK_DNS:
+		return h.CheckDNS(ctx, request)
+	case DEBUG_POD:
+		return h.DebugPod(ctx, request)
+	case GET_RESTART_CONTEXT:
+		return h.GetRestartContext(ctx, request)
+	case GET_WORKLOAD_LOGS:
+		return h.GetWorkloadLogs(ctx, request)
+	case CHECK_CERTIFICATES:
+		return h.CheckCertificates(ctx, request)
+	case GET_WORKLOAD_SPREAD:
+		return h.GetWorkloadSpread(ctx, request)
 	default:
 		// 其他方法使用父类的处理方法
 		return h.baseHandler.Handle(ctx, request)
@@ -61,7 +113,7 @@ func (h *ResourceHandlerImpl) Handle(ctx context.Context, request mcp.CallToolRe
 }
 
 // Register 实现接口方法
-func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
+func (h *ResourceHandlerImpl) Register(server interfaces.ToolRegistrar) {
 	// 注册父类的工具
 	h.baseHandler.Register(server)
 
@@ -77,7 +129,7 @@ func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
 			mcp.DefaultString("default"),
 		),
 		mcp.WithString("container",
-			mcp.Description("容器名称。当Pod中包含多个容器时使用，用于获取特定容器的日志。不指定时，对于单容器Pod返回该容器日志，多容器Pod返回第一个容器的日志。"),
+			mcp.Description("容器名称，可以是主容器、初始化容器、边车容器（restartPolicy为Always的初始化容器）或临时容器。不指定时返回kubectl.kubernetes.io/default-container注解指定的容器或唯一的应用容器（跳过istio-proxy等注入的边车容器）的日志，并在note中说明自动选择；有多个应用容器时返回错误并列出所有容器及其状态，需用container指定。Pod卡在初始化阶段时改为返回阻塞的初始化容器的日志。指定的容器不存在时返回所有容器的名称、角色和状态以及最相近的名称。"),
 		),
 		mcp.WithNumber("tailLines",
 			mcp.Description("返回的日志行数。从日志末尾开始计数，用于限制返回的日志量。默认返回最后500行。较大的值可能影响查询性能。"),
@@ -91,14 +143,32 @@ func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
 			mcp.Description("是否在每行日志前添加时间戳。帮助分析问题发生的具体时间点，适用于时序分析。默认为true。"),
 			mcp.DefaultBool(true),
 		),
+		mcp.WithString("outputMode",
+			mcp.Description("日志的返回形式。raw：整段日志作为logs字符串返回；lines：entries数组，每项为{timestamp, line}，timestamps为true时从行首解析时间戳；jsonl：与lines相同，内容本身是JSON对象的日志作为object嵌入。lines和jsonl会把以空白开头的续行（例如多行堆栈）合并到上一条。默认为raw。"),
+			mcp.Enum(utils.LogOutputRaw, utils.LogOutputLines, utils.LogOutputJSONL),
+			mcp.DefaultString(utils.LogOutputRaw),
+		),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetPodLogs)
 
 	// 注册Pod日志分析工具
 	server.AddTool(mcp.NewTool(ANALYZE_POD_LOGS,
-		mcp.WithDescription("智能分析Kubernetes Pod的日志内容。提供日志的深度分析，包括错误模式识别、异常检测、性能问题诊断等。支持自定义分析重点，适用于故障排查、性能优化、安全审计等场景。生成可操作的分析报告和优化建议。"),
+		mcp.WithDescription("智能分析Kubernetes Pod的日志内容。提供日志的深度分析，包括错误模式识别、异常检测、性能问题诊断等。支持自定义分析重点，适用于故障排查、性能优化、安全审计等场景。生成可操作的分析报告和优化建议。设置allContainers可分析Pod的全部容器（含初始化容器），提供labelSelector可一次分析工作负载的多个Pod，结果包含每个日志流的分析、合并后的汇总以及各错误出现在多少个Pod中。"),
 		mcp.WithString("name",
-			mcp.Description("Pod名称。必须提供准确的Pod名称，区分大小写。用于定位需要分析的特定Pod实例。"),
-			mcp.Required(),
+			mcp.Description("Pod名称。必须提供准确的Pod名称，区分大小写。用于定位需要分析的特定Pod实例。未提供labelSelector时必填。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器，例如'app=web'。提供时分析命名空间中匹配的所有Pod（按名称排序，最多maxPods个）并汇总结果，忽略name参数。"),
+		),
+		mcp.WithNumber("maxPods",
+			mcp.Description("使用labelSelector时最多分析的Pod数量。默认10，最大50。"),
+			mcp.DefaultNumber(10),
+		),
+		mcp.WithBoolean("allContainers",
+			mcp.Description("是否分析Pod中的全部容器（包括初始化容器）。为true时忽略container参数，每个容器单独分析后合并。默认为false。"),
+			mcp.DefaultBool(false),
 		),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes命名空间。指定Pod所在的命名空间，用于在正确的环境中进行日志分析。默认为'default'命名空间。"),
@@ -108,7 +178,7 @@ func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
 			mcp.Description("容器名称。当Pod中包含多个容器时使用，用于分析特定容器的日志。不指定时，对于单容器Pod分析该容器日志，多容器Pod分析第一个容器的日志。"),
 		),
 		mcp.WithNumber("tailLines",
-			mcp.Description("分析的日志行数。从日志末尾开始计数，用于控制分析范围。默认分析最后1000行。较大的值可提供更全面的分析，但会增加处理时间。"),
+			mcp.Description("分析的日志行数。从日志末尾开始计数，用于控制分析范围。默认分析最后1000行。分析多个容器或多个Pod时对每个日志流分别生效。较大的值可提供更全面的分析，但会增加处理时间。"),
 			mcp.DefaultNumber(1000),
 		),
 		mcp.WithBoolean("previous",
@@ -121,7 +191,497 @@ func (h *ResourceHandlerImpl) Register(server *server.MCPServer) {
 		mcp.WithString("prompt",
 			mcp.Description("自定义分析重点。指定特定的分析方向或关注点，如性能问题、安全问题、特定业务错误等。帮助生成更有针对性的分析报告。例如：'关注数据库连接相关的问题'。"),
 		),
+		mcp.WithObject("fieldMapping",
+			mcp.Description("JSON结构化日志的字段映射，覆盖默认字段名。JSON格式的日志行会自动按字段分析，其余行仍使用文本规则，两种格式可以混合。键为level、timestamp、message、error、status、duration之一，值为逗号分隔的字段名，按顺序取第一个存在的字段，支持用'.'访问嵌套字段。例如：{\"level\":\"severity\",\"duration\":\"elapsed_ms\"}。默认字段兼容zap、logrus、bunyan/pino等常见日志库。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.AnalyzePodLogs)
+
+	// 注册Pod诊断工具
+	server.AddTool(mcp.NewTool(DIAGNOSE_POD,
+		mcp.WithDescription("一站式诊断异常Pod。汇总Pod规格要点和容器状态（初始化容器和边车容器单独列在initContainers中）、按时间排序的Warning事件、各容器最近的日志（容器重启过时包含上一个实例的日志）、调度信息（节点和FailedScheduling原因）以及命名空间中的ResourceQuota/LimitRange约束，并基于规则给出按置信度排序的可能原因（镜像拉取失败、OOMKilled、探针失败、资源不足无法调度等）。Pod卡在初始化阶段（Init:N/M）时，以阻塞的初始化容器及其最后几行日志作为首要原因。排查Pod问题时建议首先调用此工具。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。必须提供准确的Pod名称，区分大小写。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes命名空间。指定Pod所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Description("每个容器获取的日志行数。默认为50行。"),
+			mcp.DefaultNumber(defaultDiagnoseTailLines),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DiagnosePod)
+
+	// 注册崩溃循环检测工具
+	server.AddTool(mcp.NewTool(FIND_CRASHLOOPING_PODS,
+		mcp.WithDescription("一次调用找出命名空间或整个集群中\"现在有什么坏了\"。列出重启次数达到阈值，或处于CrashLoopBackOff、ImagePullBackOff、ErrImagePull、CreateContainerConfigError等待状态的容器，按重启次数降序排列，初始化容器和边车容器分别以init、sidecar标出，包含上一个实例的退出码和终止原因、最近一次重启距今的时间以及所在节点。可选附带最严重的若干个容器上一个实例的最后20行日志。需要深入排查单个Pod时再调用DIAGNOSE_POD。"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes命名空间。不指定时检查所有命名空间。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），例如'app=nginx'。"),
+		),
+		mcp.WithNumber("restartThreshold",
+			mcp.Description("重启次数阈值，容器重启次数达到该值即被列出。设为0时只按等待原因筛选。默认为3。"),
+			mcp.DefaultNumber(defaultCrashLoopRestartThreshold),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("maxResults",
+			mcp.Description("最多返回的容器数量，超出时标记truncated。默认为50。"),
+			mcp.DefaultNumber(defaultCrashLoopMaxResults),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("includeLogsTail",
+			mcp.Description("是否为重启次数最多的若干个容器附带上一个实例的最后20行日志。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("logsTopN",
+			mcp.Description("附带日志的容器数量。默认为3，最大为10。"),
+			mcp.DefaultNumber(defaultCrashLoopLogsTopN),
+			mcp.Min(1),
+			mcp.Max(maxCrashLoopLogsTopN),
+		),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.FindCrashLoopingPods)
+
+	// 注册Pending Pod调度分析工具
+	server.AddTool(mcp.NewTool(ANALYZE_PENDING_PODS,
+		mcp.WithDescription("分析Pending Pod无法调度的原因。提取最近一次FailedScheduling事件并解析调度器给出的原因（资源不足、节点亲和性不匹配、污点未容忍、存储卷节点亲和性冲突、PVC未绑定等），再结合各节点实时的可分配资源与已请求资源、污点、标签和状态，判断当前是否有节点能容纳该Pod，并给出具体的差距和调整建议（例如\"Pod请求8.0Gi memory，但剩余最多的节点只有5.2Gi\"）。指定name时只分析该Pod，否则分析命名空间中所有Pending Pod，等待时间最长的排在前面。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称（可选）。不指定时分析所有Pending Pod。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes命名空间。指定name时默认为'default'；不指定name时留空表示所有命名空间。"),
+		),
+		mcp.WithNumber("maxPods",
+			mcp.Description("最多分析的Pod数量，超出时标记truncated。默认为20。"),
+			mcp.DefaultNumber(defaultPendingMaxPods),
+			mcp.Min(1),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.AnalyzePendingPods)
+
+	// 注册容器终止历史工具
+	server.AddTool(mcp.NewTool(GET_CONTAINER_TERMINATIONS,
+		mcp.WithDescription("统计容器异常终止（默认OOMKilled、Error、ContainerCannotRun）的情况。读取每个容器上一个实例的终止状态（未重启过的容器读取当前实例），返回退出码、信号、配置的内存request/limit、metrics-server提供的当前内存用量及其占limit的比例，以及相关的Killing/BackOff事件时间。按工作负载汇总各原因的容器数和重启次数（Pod由ReplicaSet管理时归到Deployment），便于发现\"某个Deployment的Pod在过去一小时内反复OOMKilled\"，并列出重启次数最多的终止记录。注意：Kubernetes只保留每个容器最后一次终止的状态，重启次数用于估计历史终止次数。"),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes命名空间。不指定时检查所有命名空间。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），例如'app=nginx'。"),
+		),
+		mcp.WithString("reasons",
+			mcp.Description("要统计的终止原因，多个用逗号分隔。默认为'OOMKilled,Error,ContainerCannotRun'。"),
+		),
+		mcp.WithNumber("sinceMinutes",
+			mcp.Description("时间窗口（分钟，可选）。只统计在此时间内结束的容器实例，例如60表示最近一小时。"),
+			mcp.Min(1),
+		),
+		mcp.WithNumber("maxOffenders",
+			mcp.Description("worstOffenders中最多返回的终止记录数量。默认为20。"),
+			mcp.DefaultNumber(defaultTerminationOffenders),
+			mcp.Min(1),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetContainerTerminations)
+
+	// 注册Secret和ConfigMap安全查看工具
+	server.AddTool(mcp.NewTool(GET_SECRET_KEYS,
+		mcp.WithDescription("安全地查看Secret。只返回键名、值长度和可选的SHA256指纹，不返回值，适用于确认Secret是否包含所需的键、比较两个Secret是否一致等场景。仅当服务器以--allow-secret-values启动且调用方传入revealValues=true时才返回值。"),
+		mcp.WithString("name",
+			mcp.Description("Secret名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Secret所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithBoolean("fingerprint",
+			mcp.Description("是否返回每个值的SHA256指纹，用于在不暴露值的情况下比较内容。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("revealValues",
+			mcp.Description("是否返回值。需要服务器启用--allow-secret-values，否则忽略。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetSecretKeys)
+
+	server.AddTool(mcp.NewTool(GET_CONFIGMAP,
+		mcp.WithDescription("查看ConfigMap的内容。超过大小阈值的值会被截断，binaryData只列出键名和大小，避免大文件占满上下文。"),
+		mcp.WithString("name",
+			mcp.Description("ConfigMap名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("ConfigMap所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("maxValueBytes",
+			mcp.Description("每个值返回的最大字节数，超出部分会被截断。默认为4096。"),
+			mcp.DefaultNumber(defaultConfigMapMaxValueBytes),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetConfigMap)
+
+	// 注册Pod重启工具
+	server.AddTool(mcp.NewTool(RESTART_POD,
+		mcp.WithDescription("删除或驱逐单个Pod以重启它，并说明后果：返回Pod的控制器和所属工作负载、控制器是否会创建替代Pod，可选等待替代Pod就绪并返回其名称。evict=true时使用Eviction API，遵守PodDisruptionBudget，被PDB阻止时返回说明而不会删除。没有控制器的裸Pod删除后不会恢复，需要confirm=true；静态Pod的镜像Pod会被拒绝。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithBoolean("evict",
+			mcp.Description("是否通过Eviction API驱逐而不是直接删除，驱逐遵守PodDisruptionBudget。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("确认删除没有控制器、不会被重建的裸Pod。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否只在服务端试运行而不实际删除。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("wait",
+			mcp.Description("是否等待控制器创建的替代Pod就绪。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("waitTimeoutSeconds",
+			mcp.Description("wait=true时的最长等待时间（秒）。默认为120秒，最大为600秒。"),
+			mcp.DefaultNumber(defaultReplacementWaitSeconds),
+			mcp.Min(1),
+			mcp.Max(maxReplacementWaitSeconds),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.RestartPod)
+
+	// 注册调度容量模拟工具
+	server.AddTool(mcp.NewTool(CAN_SCHEDULE,
+		mcp.WithDescription("回答\"集群还能否再容纳N个这样的Pod\"：使用工作负载的Pod模板，或内联的cpu/memory请求加nodeSelector和容忍，按节点实时的可分配资源减去现有Pod请求模拟放置，遵守污点与容忍、nodeSelector、必需的节点亲和性和每节点最大Pod数。返回能否容纳、最多还能容纳的副本数、放置后每个节点的剩余容量、被排除的节点及原因，以及限制副本数的主要约束（cpu、memory、pods、selectors、taints等）。这是近似模拟，不考虑Pod亲和性和拓扑分布约束。扩容前使用。只读操作。"),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型（可选）。指定时使用其Pod模板，忽略内联参数。"),
+			mcp.Enum("Deployment", "StatefulSet", "ReplicaSet", "Job", "CronJob", "Pod"),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称，指定kind时必填。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("工作负载所在的命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("replicas",
+			mcp.Description("要额外放置的副本数。默认为1，最大为10000。"),
+			mcp.DefaultNumber(defaultScheduleReplicas),
+			mcp.Min(1),
+			mcp.Max(maxScheduleReplicas),
+		),
+		mcp.WithString("cpu",
+			mcp.Description("内联的单个Pod CPU请求，例如'500m'。未指定kind时cpu和memory至少提供一个。"),
+		),
+		mcp.WithString("memory",
+			mcp.Description("内联的单个Pod内存请求，例如'1Gi'。"),
+		),
+		mcp.WithObject("nodeSelector",
+			mcp.Description("内联的nodeSelector，键值均为字符串。"),
+		),
+		mcp.WithArray("tolerations",
+			mcp.Description("内联的容忍列表，格式与Pod的spec.tolerations相同，例如[{\"key\": \"dedicated\", \"operator\": \"Equal\", \"value\": \"gpu\", \"effect\": \"NoSchedule\"}]。"),
+			mcp.Items(map[string]interface{}{"type": "object"}),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CanSchedule)
+
+	// 注册工作负载分布报告工具
+	server.AddTool(mcp.NewTool(GET_WORKLOAD_SPREAD,
+		mcp.WithDescription("报告工作负载的Pod在节点和可用区（节点标签topology.kubernetes.io/zone）上的具体分布：每个节点和可用区的Pod数量、节点能否容纳该工作负载的新Pod（污点、nodeSelector、节点亲和性、cordon）、节点剩余的cpu/memory/pods以及还能容纳的副本数，用于回答\"能否进一步分散\"。按调度器规则检查Pod模板中的topologySpreadConstraints（计算偏差）和podAntiAffinity在当前分布下是否被违反，并标出所有副本集中在单个节点或单个可用区的单点故障。与CHECK_AVAILABILITY配合使用，提供其结论背后的放置数据。只读操作。"),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型（可选）。指定时使用其选择器和Pod模板；未指定时按labelSelector匹配Pod。"),
+			mcp.Enum("Deployment", "StatefulSet"),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称，指定kind时必填。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("未指定kind时用于匹配Pod的标签选择器，例如'app=web'；第一个匹配的Pod作为容量和约束检查的Pod规格。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetWorkloadSpread)
+
+	// 注册容器文件列表工具
+	server.AddTool(mcp.NewTool(LIST_POD_FILES,
+		mcp.WithDescription("列出运行中容器内某个路径下的文件，返回名称、路径、类型、权限、大小、属主和修改时间的结构化列表。在容器中以参数数组执行ls/find而不经过shell；容器没有这些命令时尝试/bin/busybox，都没有时（如distroless镜像）返回Unsupported错误。需要服务器以--allow-exec启动。只读操作。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称。不指定时使用第一个容器。"),
+		),
+		mcp.WithString("path",
+			mcp.Description("要列出的绝对路径，例如'/etc/nginx'。为文件时只返回该文件。"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("递归深度。1表示只列出目录的直接内容，最大为5。最多返回500个条目。"),
+			mcp.DefaultNumber(defaultListFilesDepth),
+			mcp.Min(1),
+			mcp.Max(maxListFilesDepth),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListPodFiles)
+
+	// 注册容器文件读取工具
+	server.AddTool(mcp.NewTool(READ_POD_FILE,
+		mcp.WithDescription("读取运行中容器内的文件，最多返回maxBytes字节并标记是否截断。文本以UTF-8返回，包含NUL字节或不是合法UTF-8的二进制内容以base64返回。在容器中以参数数组执行head而不经过shell；容器没有head时尝试/bin/busybox。需要服务器以--allow-exec启动。只读操作。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称。不指定时使用第一个容器。"),
+		),
+		mcp.WithString("path",
+			mcp.Description("要读取的文件的绝对路径，例如'/etc/nginx/nginx.conf'。"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("maxBytes",
+			mcp.Description("最多返回的字节数。默认为65536，最大为1048576。"),
+			mcp.DefaultNumber(defaultReadFileBytes),
+			mcp.Min(1),
+			mcp.Max(maxReadFileBytes),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ReadPodFile)
+
+	// 注册复制文件到容器的工具
+	server.AddTool(mcp.NewTool(COPY_TO_POD,
+		mcp.WithDescription("将文件复制到运行中的容器内（相当于kubectl cp上传），例如放入调试脚本。内容以base64传入，通过exec会话向容器中的tar xf -发送tar归档，目标目录必须已存在，同名文件会被替换。文件大小受服务器--max-copy-bytes限制（默认1MB）。容器中没有tar时尝试/bin/busybox，都没有时返回Unsupported错误。需要服务器以--allow-exec启动。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称。不指定时使用第一个容器。"),
+		),
+		mcp.WithString("remotePath",
+			mcp.Description("容器中目标文件的绝对路径，例如'/tmp/debug.sh'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("content",
+			mcp.Description("文件内容，base64编码。"),
+			mcp.Required(),
+		),
+		mcp.WithString("mode",
+			mcp.Description("八进制文件权限，例如'0755'。默认为'0644'。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CopyToPod)
+
+	// 注册从容器复制文件的工具
+	server.AddTool(mcp.NewTool(COPY_FROM_POD,
+		mcp.WithDescription("从运行中的容器复制单个普通文件（相当于kubectl cp下载），内容以base64返回，同时返回权限和修改时间。通过exec会话读取容器中tar cf -输出的归档。文件大小受服务器--max-copy-bytes限制（默认1MB），超过时不会传输；目录和符号链接会被拒绝。容器中没有tar时尝试/bin/busybox。需要服务器以--allow-exec启动。只读操作。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称。不指定时使用第一个容器。"),
+		),
+		mcp.WithString("remotePath",
+			mcp.Description("容器中文件的绝对路径，例如'/tmp/heapdump.path'。"),
+			mcp.Required(),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CopyFromPod)
+
+	// 注册Service DNS解析检查工具
+	server.AddTool(mcp.NewTool(CHECK_DNS,
+		mcp.WithDescription("检查Service的DNS解析。控制平面视图：列出应解析的名称（svc、svc.ns、svc.ns.svc、svc.ns.svc.cluster.local，无头Service还包括Pod主机名），检查Service和EndpointSlice是否存在以及就绪地址，得出预期的解析地址。指定fromPod且服务器以--allow-exec启动时，在该Pod中执行getent hosts（没有时使用nslookup）实际解析，与预期并列返回。同时检测常见故障：无头Service没有就绪Pod、查询名称中的命名空间与Service不一致、kube-system中CoreDNS/kube-dns没有就绪副本。只读操作。"),
+		mcp.WithString("name",
+			mcp.Description("Service名称，也可以是要检查的DNS名称，例如'my-svc'、'my-svc.prod'或'my-svc.prod.svc.cluster.local'。名称中的命名空间优先于namespace参数。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Service所在的命名空间。默认为'default'命名空间。"),
+		),
+		mcp.WithString("fromPod",
+			mcp.Description("在该Pod中实际解析名称，需要服务器以--allow-exec启动。不指定时只返回控制平面视图。"),
+		),
+		mcp.WithString("fromNamespace",
+			mcp.Description("fromPod所在的命名空间。默认与Service相同。短名称会按该命名空间补全。"),
+		),
+		mcp.WithString("container",
+			mcp.Description("fromPod中执行解析的容器。不指定时使用第一个容器。"),
+		),
+		mcp.WithString("clusterDomain",
+			mcp.Description("集群域名。默认为'cluster.local'。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckDNS)
+
+	// 注册临时调试容器工具
+	server.AddTool(mcp.NewTool(DEBUG_POD,
+		mcp.WithDescription("向运行中的Pod添加临时调试容器（相当于kubectl debug），适用于distroless等没有shell的镜像，比直接exec更安全。通过ephemeralcontainers子资源添加容器，等待其启动后返回容器名称，之后可以在GET_POD_LOGS等工具中用container参数指定它。指定targetContainer时与该容器共享进程命名空间，可以看到其进程并通过/proc/<pid>/root访问其文件系统。可选command在容器启动后执行一条命令并一并返回输出。注意：临时容器添加后无法删除，会一直保留到Pod被删除。需要服务器以--allow-exec启动，集群需为Kubernetes 1.23及以上。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("image",
+			mcp.Description("调试容器使用的镜像。默认为'busybox'。"),
+		),
+		mcp.WithString("targetContainer",
+			mcp.Description("共享进程命名空间的目标容器名称。不指定时调试容器只共享Pod的网络和存储卷。"),
+		),
+		mcp.WithArray("command",
+			mcp.Description("调试容器启动后执行的命令及参数，直接执行而不经过shell，例如[\"ps\", \"aux\"]。需要shell语法时使用[\"sh\", \"-c\", \"...\"]。"),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("waitTimeoutSeconds",
+			mcp.Description(fmt.Sprintf("等待调试容器启动的最长时间（秒）。默认为%d，最大为%d。", defaultDebugWaitSeconds, maxDebugWaitSeconds)),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DebugPod)
+
+	// 注册容器重启上下文工具
+	server.AddTool(mcp.NewTool(GET_RESTART_CONTEXT,
+		mcp.WithDescription("排查容器重启原因：一次返回上一个容器实例结束前的日志、终止原因、退出码和信号，以及当前实例启动时的日志，并对上一个实例的日志做错误统计。未指定container时选择重启次数最多的容器（包括init容器）。容器没有重启过时返回错误。上一个实例的日志已被kubelet回收、或容器处于等待状态尚未启动新实例时会明确说明。"),
+		mcp.WithString("name",
+			mcp.Description("Pod名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称。不指定时使用重启次数最多的容器。"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Description(fmt.Sprintf("上一个实例末尾的日志行数。默认为%d，最大为%d。", defaultRestartContextLines, maxRestartContextLines)),
+		),
+		mcp.WithNumber("headLines",
+			mcp.Description(fmt.Sprintf("当前实例开头的日志行数。默认为%d，最大为%d。", defaultRestartContextLines, maxRestartContextLines)),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetRestartContext)
+
+	// 注册工作负载日志工具
+	server.AddTool(mcp.NewTool(GET_WORKLOAD_LOGS,
+		mcp.WithDescription("一次获取Deployment、StatefulSet、DaemonSet或Job所有Pod的日志，无需先列出Pod再逐个调用GET_POD_LOGS。按工作负载的选择器找到Pod，并发读取每个Pod所选容器末尾的日志，返回每个Pod的日志；启用时间戳时另外返回按时间戳交错合并的视图（merged），便于查看各副本在同一时刻的情况。所有Pod的日志合计不超过maxBytes，超出时在Pod之间公平分配并丢弃较早的行。无法读取日志的Pod（如Pending、容器尚未启动、没有上一个实例）会标出原因，不影响其他Pod。"),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型。"),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet", "Job"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("container",
+			mcp.Description("容器名称（可选）。默认使用每个Pod的默认容器（kubectl.kubernetes.io/default-container注解指定的容器或第一个容器）。"),
+		),
+		mcp.WithNumber("tailLines",
+			mcp.Description(fmt.Sprintf("每个Pod读取的末尾日志行数。默认为%d，最大为%d。", defaultWorkloadLogLines, maxWorkloadLogLines)),
+			mcp.DefaultNumber(defaultWorkloadLogLines),
+			mcp.Min(1),
+			mcp.Max(maxWorkloadLogLines),
+		),
+		mcp.WithBoolean("previous",
+			mcp.Description("是否读取容器上一个实例的日志。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("timestamps",
+			mcp.Description("是否在日志中包含时间戳并返回按时间戳合并的视图。默认为true。"),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithNumber("maxPods",
+			mcp.Description(fmt.Sprintf("最多读取的Pod数量，按名称排序。默认为%d，最大为%d。", defaultWorkloadLogPods, maxWorkloadLogPods)),
+			mcp.DefaultNumber(defaultWorkloadLogPods),
+			mcp.Min(1),
+			mcp.Max(maxWorkloadLogPods),
+		),
+		mcp.WithNumber("maxBytes",
+			mcp.Description(fmt.Sprintf("所有Pod日志合计的最大字节数。默认为%d，最大为%d。", defaultWorkloadLogBytes, maxWorkloadLogBytes)),
+			mcp.DefaultNumber(defaultWorkloadLogBytes),
+			mcp.Min(1),
+			mcp.Max(maxWorkloadLogBytes),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetWorkloadLogs)
+
+	// 注册证书到期检查工具
+	server.AddTool(mcp.NewTool(CHECK_CERTIFICATES,
+		mcp.WithDescription("检查kubernetes.io/tls类型Secret中的证书到期情况，只读取不修改。解析tls.crt中的x509证书链，返回叶子证书的主题、SAN、签发者、有效期和距离到期的天数，并检查引用该Secret的Ingress的主机名是否被证书覆盖。集群安装了cert-manager时同时关联Certificate资源的Ready状态和续期时间，并报告Secret尚未创建的Certificate。结果按到期时间从近到远排序。不会读取或返回私钥。"),
+		mcp.WithString("namespace",
+			mcp.Description("要检查的命名空间。为空时检查所有命名空间。"),
+		),
+		mcp.WithNumber("warnDays",
+			mcp.Description(fmt.Sprintf("剩余有效期少于该天数的证书标记为expiring。默认为%d。", defaultCertificateWarnDays)),
+			mcp.DefaultNumber(defaultCertificateWarnDays),
+			mcp.Min(0),
+		),
+		mcp.WithBoolean("expiringOnly",
+			mcp.Description("是否只返回已过期、即将过期、无法解析或缺失的证书。countsByStatus仍统计全部证书。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckCertificates)
 }
 
 // GetScope 实现ToolHandler接口
@@ -212,14 +772,74 @@ func (h *ResourceHandlerImpl) GetPodLogs(
 	tailLinesVal := arguments["tailLines"]          // tailLines is handled specially below
 	previous, _ := arguments["previous"].(bool)
 	timestamps, _ := arguments["timestamps"].(bool)
+	outputModeArg, _ := arguments["outputMode"].(string)
 
 	reqLogger := h.handler.Log.With("pod", name, "namespace", namespace, "container", container)
 	reqLogger.Info("Starting pod logs request", "options", map[string]interface{}{
 		"tailLines":  tailLinesVal,
 		"previous":   previous,
 		"timestamps": timestamps,
+		"outputMode": outputModeArg,
 	})
 
+	outputMode, err := utils.ParseLogOutputMode(outputModeArg)
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: err.Error(),
+		}), nil
+	}
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
+	}
+
+	// --- 确定容器 ---
+	var containerRole, note string
+	var availableContainers []models.ContainerStatusSummary
+	if pod, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		// 无法获取Pod时交给日志接口报告错误
+		reqLogger.Warn("Failed to get pod for container resolution", "error", err)
+	} else if container != "" {
+		// 在读取日志前校验容器名称，拼写错误时返回候选容器而不是kubelet的404
+		containerRole = podContainerRole(pod, container)
+		if containerRole == "" {
+			summaries := podContainerSummaries(pod)
+			hint := "Available containers: " + describeContainers(summaries)
+			names := make([]string, 0, len(summaries))
+			for _, summary := range summaries {
+				names = append(names, summary.Name)
+			}
+			if matches := utils.MatchNames(container, names, 1); len(matches) > 0 {
+				hint = fmt.Sprintf("Did you mean %q? %s", matches[0].Name, hint)
+			}
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeNotFound,
+				Message: fmt.Sprintf("pod %s/%s has no container %q", namespace, name, container),
+				Hint:    hint,
+				Details: summaries,
+			}), nil
+		}
+	} else {
+		availableContainers = podContainerSummaries(pod)
+		// Pod卡在初始化阶段时主容器尚未启动，阻塞的初始化容器的日志才有意义
+		if progress := podInitProgress(pod); progress != nil && progress.State != "NotStarted" {
+			note = fmt.Sprintf("pod is %s; showing logs of init container %s that is blocking startup", progress.Status, progress.Blocking)
+			container = progress.Blocking
+		} else {
+			container, note = selectLogContainer(pod)
+			if container == "" {
+				return utils.NewToolErrorResult(models.ToolError{
+					Code:    utils.ErrorCodeInvalid,
+					Message: fmt.Sprintf("pod %s/%s has %d app containers and no container was specified", namespace, name, len(appContainerNames(pod))),
+					Hint:    fmt.Sprintf("Retry with container=<name>, one of: %s.", describeContainers(availableContainers)),
+					Details: availableContainers,
+				}), nil
+			}
+		}
+		containerRole = podContainerRole(pod, container)
+	}
+
 	// --- 设置日志选项 ---
 	podLogOptions := &corev1.PodLogOptions{
 		Container:  container,
@@ -250,12 +870,18 @@ func (h *ResourceHandlerImpl) GetPodLogs(
 	// --- 获取和读取日志流 ---
 	logRESTRequest := h.handler.Client.ClientSet().CoreV1().Pods(namespace).GetLogs(name, podLogOptions)
 	podLogsStream, err := logRESTRequest.Stream(ctx)
+	if err != nil && strings.Contains(err.Error(), "a container name must be specified") {
+		// 无法预先获取Pod时，由API Server报告多容器Pod需要指定容器，错误信息中包含可选的容器
+		reqLogger.Warn("Pod logs need an explicit container", "error", err)
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: err.Error(),
+			Hint:    "Retry with container=<name> using one of the containers listed in the message.",
+		}), nil
+	}
 	if err != nil {
 		reqLogger.Error("Failed to get pod logs stream", "error", err)
-		if errors.IsNotFound(err) {
-			return utils.NewErrorToolResult(fmt.Sprintf("Pod '%s' not found in namespace '%s'", name, namespace)), nil
-		}
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to stream pod logs for pod %s: %v", name, err)), nil
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to stream pod logs for pod %s", name)), nil
 	}
 	defer podLogsStream.Close()
 
@@ -276,6 +902,7 @@ func (h *ResourceHandlerImpl) GetPodLogs(
 
 	// --- 处理日志截断显示 ---
 	displayLogs := logsContent
+	displayLines := logLines
 	truncated := false
 	displayLineCount := actualLineCount
 
@@ -295,7 +922,8 @@ func (h *ResourceHandlerImpl) GetPodLogs(
 
 	if tailLines <= 0 && actualLineCount > defaultDisplayTailLines {
 		startIndex := actualLineCount - defaultDisplayTailLines
-		displayLogs = strings.Join(logLines[startIndex:], "\n")
+		displayLines = logLines[startIndex:]
+		displayLogs = strings.Join(displayLines, "\n")
 		truncated = true
 		displayLineCount = defaultDisplayTailLines
 	} else if tailLines > 0 && actualLineCount > tailLines {
@@ -303,46 +931,51 @@ func (h *ResourceHandlerImpl) GetPodLogs(
 		if startIndex < 0 {
 			startIndex = 0
 		}
-		displayLogs = strings.Join(logLines[startIndex:], "\n")
+		displayLines = logLines[startIndex:]
+		displayLogs = strings.Join(displayLines, "\n")
 		displayLineCount = tailLines
 	}
 
 	// --- 构建JSON响应 ---
 	logResponse := models.PodLogsResponse{
-		Pod:          name,
-		Namespace:    namespace,
-		Container:    container,
-		Previous:     previous,
-		Timestamps:   timestamps,
-		TailLines:    tailLines,
-		LineCount:    displayLineCount,
-		TotalLines:   actualLineCount,
-		Truncated:    truncated,
-		LogSize:      uint64(logLengthBytes),
-		LogSizeHuman: humanize.Bytes(uint64(logLengthBytes)),
-		Logs:         displayLogs,
-		RetrievedAt:  time.Now(),
+		Pod:                 name,
+		Namespace:           namespace,
+		Container:           container,
+		ContainerRole:       containerRole,
+		AvailableContainers: availableContainers,
+		Note:                note,
+		Previous:            previous,
+		Timestamps:          timestamps,
+		TailLines:           tailLines,
+		LineCount:           displayLineCount,
+		TotalLines:          actualLineCount,
+		Truncated:           truncated,
+		LogSize:             uint64(logLengthBytes),
+		LogSizeHuman:        humanize.Bytes(uint64(logLengthBytes)),
+		OutputMode:          outputMode,
+		RetrievedAt:         time.Now().In(location),
+	}
+	if outputMode == utils.LogOutputRaw {
+		logResponse.Logs = displayLogs
+	} else {
+		logResponse.Entries = utils.ParseLogEntries(displayLines, timestamps, outputMode)
+		logResponse.EntryCount = len(logResponse.Entries)
+		// 解析出的时间戳按请求的时区返回，raw模式保留kubelet写入的原始UTC时间戳
+		for i := range logResponse.Entries {
+			if timestamp := logResponse.Entries[i].Timestamp; timestamp != nil {
+				*timestamp = timestamp.In(location)
+			}
+		}
 	}
 
 	// 序列化为JSON
-	jsonData, err := json.MarshalIndent(logResponse, "", "  ")
-	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON序列化失败: %v", err)), nil
-	}
 
 	reqLogger.Info("Pod logs retrieved successfully",
 		"bytes", humanize.Bytes(uint64(logLengthBytes)),
 		"linesRetrieved", humanize.Comma(int64(actualLineCount)),
 		"linesDisplayed", humanize.Comma(int64(displayLineCount)))
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, logResponse), nil
 }
 
 // AnalyzePodLogs 分析Pod日志并提供洞察
@@ -353,12 +986,14 @@ func (h *ResourceHandlerImpl) AnalyzePodLogs(
 	// --- 参数提取 ---
 	arguments := request.GetArguments()
 
-	// Type assertion with proper error handling
-	nameVal, ok := arguments["name"]
-	if !ok || nameVal == nil {
-		return utils.NewErrorToolResult("Pod name is required"), nil
+	name, _ := arguments["name"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	if name == "" && labelSelector == "" {
+		return utils.NewErrorToolResult("Pod name or labelSelector is required"), nil
 	}
-	name := nameVal.(string)
 
 	namespaceArg, _ := arguments["namespace"].(string) // namespace is optional with default
 
@@ -366,7 +1001,8 @@ func (h *ResourceHandlerImpl) AnalyzePodLogs(
 	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
 
 	container, _ := arguments["container"].(string) // container is optional
-	tailLinesVal := arguments["tailLines"]          // tailLines is handled specially below
+	allContainers, _ := arguments["allContainers"].(bool)
+	tailLinesVal := arguments["tailLines"] // tailLines is handled specially below
 
 	// 处理tailLines参数
 	var tailLines int
@@ -386,61 +1022,48 @@ func (h *ResourceHandlerImpl) AnalyzePodLogs(
 	previous, _ := arguments["previous"].(bool)
 	customErrorPattern, _ := arguments["errorPattern"].(string)
 	prompt, _ := arguments["prompt"].(string)
+	rawFieldMapping, err := utils.StringMapArgument(arguments, "fieldMapping")
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	fieldMapping, err := utils.ParseLogFieldMapping(rawFieldMapping)
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
 
 	reqLogger := h.handler.Log.With("pod", name, "namespace", namespace, "container", container)
 	reqLogger.Info("Starting pod logs analysis", "options", map[string]interface{}{
-		"tailLines":    tailLines,
-		"previous":     previous,
-		"errorPattern": customErrorPattern,
-		"prompt":       prompt,
+		"tailLines":     tailLines,
+		"previous":      previous,
+		"errorPattern":  customErrorPattern,
+		"prompt":        prompt,
+		"allContainers": allContainers,
+		"labelSelector": labelSelector,
 	})
 
-	// --- 设置日志选项 ---
-	podLogOptions := &corev1.PodLogOptions{
-		Container:  container,
-		Previous:   previous,
-		Timestamps: true, // 分析需要时间戳
-	}
-	if tailLines > 0 {
-		tailLinesInt64 := int64(tailLines)
-		podLogOptions.TailLines = &tailLinesInt64
+	// 多容器或按标签选择多个Pod时逐个日志流分析后汇总
+	if allContainers || labelSelector != "" {
+		options := logAnalysisOptions{
+			container:     container,
+			allContainers: allContainers,
+			tailLines:     tailLines,
+			previous:      previous,
+			errorPattern:  customErrorPattern,
+			fields:        fieldMapping,
+			prompt:        prompt,
+		}
+		return h.analyzeLogStreams(ctx, request, namespace, name, labelSelector, options), nil
 	}
 
-	// --- 获取和读取日志流 ---
-	logRESTRequest := h.handler.Client.ClientSet().CoreV1().Pods(namespace).GetLogs(name, podLogOptions)
-	podLogsStream, err := logRESTRequest.Stream(ctx)
+	logLines, err := h.readLogLines(ctx, namespace, name, container, previous, tailLines)
 	if err != nil {
 		reqLogger.Error("Failed to get pod logs stream for analysis", "error", err)
-		if errors.IsNotFound(err) {
-			return utils.NewErrorToolResult(fmt.Sprintf("Pod '%s' not found in namespace '%s'", name, namespace)), nil
-		}
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to stream pod logs for analysis, pod %s: %v", name, err)), nil
-	}
-	defer podLogsStream.Close()
-
-	// 读取日志内容
-	buf := new(bytes.Buffer)
-	_, err = io.CopyN(buf, podLogsStream, MAX_LOG_BYTES_LIMIT)
-	if err != nil && err != io.EOF {
-		reqLogger.Error("Failed to read pod logs stream fully for analysis", "error", err)
-	}
-
-	logsContent := buf.String()
-	logLines := strings.Split(logsContent, "\n")
-	if len(logLines) > 0 && logLines[len(logLines)-1] == "" {
-		logLines = logLines[:len(logLines)-1]
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to stream pod logs for analysis, pod %s", name)), nil
 	}
 	actualLineCount := len(logLines)
 
 	// --- 日志分析 ---
-	analyzer := utils.NewLogAnalyzer()
-
-	// 如果提供了自定义错误模式，则设置它
-	if customErrorPattern != "" {
-		analyzer = utils.NewLogAnalyzerWithPattern(customErrorPattern)
-	}
-
-	analysis := analyzer.AnalyzeLogsWithPrompt(logLines, prompt)
+	analysis := utils.NewLogAnalyzerWithFields(customErrorPattern, fieldMapping).AnalyzeLogsWithPrompt(logLines, prompt)
 
 	// --- 使用转换函数创建JSON响应 ---
 	analysisResponse := models.NewLogAnalysisResponseFromResult(
@@ -453,19 +1076,44 @@ func (h *ResourceHandlerImpl) AnalyzePodLogs(
 	)
 
 	// 序列化为JSON
-	jsonData, err := json.MarshalIndent(analysisResponse, "", "  ")
+
+	reqLogger.Info("Pod logs analysis completed", "linesAnalyzed", actualLineCount)
+
+	return utils.RenderResult(request, analysisResponse), nil
+}
+
+// readLogLines 读取容器日志的最后tailLines行（带时间戳），最多读取MAX_LOG_BYTES_LIMIT字节
+func (h *ResourceHandlerImpl) readLogLines(
+	ctx context.Context,
+	namespace, pod, container string,
+	previous bool,
+	tailLines int,
+) ([]string, error) {
+	podLogOptions := &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		Timestamps: true, // 分析需要时间戳
+	}
+	if tailLines > 0 {
+		tailLinesInt64 := int64(tailLines)
+		podLogOptions.TailLines = &tailLinesInt64
+	}
+
+	podLogsStream, err := h.handler.Client.ClientSet().CoreV1().Pods(namespace).GetLogs(pod, podLogOptions).Stream(ctx)
 	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON序列化失败: %v", err)), nil
+		return nil, err
 	}
+	defer podLogsStream.Close()
 
-	reqLogger.Info("Pod logs analysis completed", "linesAnalyzed", actualLineCount)
+	buf := new(bytes.Buffer)
+	_, err = io.CopyN(buf, podLogsStream, MAX_LOG_BYTES_LIMIT)
+	if err != nil && err != io.EOF {
+		h.handler.Log.Error("Failed to read pod logs stream fully for analysis", "pod", pod, "container", container, "error", err)
+	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	logLines := strings.Split(buf.String(), "\n")
+	if len(logLines) > 0 && logLines[len(logLines)-1] == "" {
+		logLines = logLines[:len(logLines)-1]
+	}
+	return logLines, nil
 }
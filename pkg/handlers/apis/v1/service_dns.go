@@ -0,0 +1,548 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// defaultClusterDomain 未指定时使用的集群域名
+	defaultClusterDomain = "cluster.local"
+	// clusterDNSService kube-dns和CoreDNS共用的Service名称
+	clusterDNSService = "kube-dns"
+	// dnsLookupOutputLimit 解析命令输出的最大字节数
+	dnsLookupOutputLimit = 16 * 1024
+)
+
+// clusterDNSDeployments 提供集群DNS的Deployment名称，按顺序查找
+var clusterDNSDeployments = []string{"coredns", "kube-dns"}
+
+// CheckDNS 检查Service应解析到的名称和地址，并可在指定Pod中实际解析，对比控制平面与数据平面的结果
+func (h *ResourceHandlerImpl) CheckDNS(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	queryName, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	fromPod, _ := arguments["fromPod"].(string)
+	fromNamespaceArg, _ := arguments["fromNamespace"].(string)
+	container, _ := arguments["container"].(string)
+	clusterDomain, _ := arguments["clusterDomain"].(string)
+	clusterDomain = strings.Trim(clusterDomain, ".")
+	if clusterDomain == "" {
+		clusterDomain = defaultClusterDomain
+	}
+
+	h.handler.Log.Info("Checking service DNS",
+		"name", queryName,
+		"namespace", namespaceArg,
+		"fromPod", fromPod,
+		"fromNamespace", fromNamespaceArg,
+		"clusterDomain", clusterDomain,
+	)
+
+	queryName = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(queryName)), ".")
+	if queryName == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	serviceName, nameNamespace := splitServiceDNSName(queryName, clusterDomain)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	fromNamespace := fromNamespaceArg
+	if fromNamespace == "" {
+		fromNamespace = namespace
+	}
+
+	report := &models.DNSCheckReport{
+		Service:       serviceName,
+		Namespace:     namespace,
+		ClusterDomain: clusterDomain,
+		Findings:      []models.Finding{},
+		RetrievedAt:   time.Now(),
+	}
+	if queryName != serviceName {
+		report.QueryName = queryName
+	}
+
+	// 名称中带有命名空间时DNS按名称中的命名空间解析，与namespace参数不一致时以名称为准
+	if nameNamespace != "" && nameNamespace != namespace {
+		if namespaceArg != "" {
+			report.Findings = append(report.Findings, models.Finding{
+				Severity: models.SeverityWarning,
+				Check:    "namespace",
+				Message: fmt.Sprintf("%s refers to namespace %s but namespace %s was requested; DNS resolves the name in %s",
+					queryName, nameNamespace, namespaceArg, nameNamespace),
+			})
+		}
+		namespace = nameNamespace
+		report.Namespace = namespace
+	}
+	// 短名称通过Pod的search路径补全为调用方所在的命名空间
+	if nameNamespace == "" && fromPod != "" && fromNamespace != namespace {
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "namespace",
+			Message: fmt.Sprintf("short name %s queried from namespace %s resolves to %s.%s, not to the Service in %s",
+				serviceName, fromNamespace, serviceName, fromNamespace, namespace),
+			Details: []string{fmt.Sprintf("use %s.%s or %s", serviceName, namespace, serviceFQDN(serviceName, namespace, clusterDomain))},
+		})
+	}
+
+	h.checkDNSControlPlane(ctx, serviceName, namespace, clusterDomain, report)
+	h.checkClusterDNS(ctx, report)
+
+	if fromPod != "" {
+		dataPlane, errResult := h.checkDNSDataPlane(ctx, fromNamespace, fromPod, container, queryName, report)
+		if errResult != nil {
+			return errResult, nil
+		}
+		report.DataPlane = dataPlane
+	}
+
+	h.handler.Log.Info("Service DNS check completed",
+		"service", serviceName,
+		"namespace", namespace,
+		"findings", len(report.Findings),
+	)
+	return utils.RenderResult(request, report), nil
+}
+
+// checkDNSControlPlane 根据Service和EndpointSlice推断应解析到的名称和地址
+func (h *ResourceHandlerImpl) checkDNSControlPlane(
+	ctx context.Context,
+	serviceName, namespace, clusterDomain string,
+	report *models.DNSCheckReport,
+) {
+	view := &report.ControlPlane
+	fqdn := serviceFQDN(serviceName, namespace, clusterDomain)
+	view.ExpectedNames = []string{
+		serviceName,
+		serviceName + "." + namespace,
+		serviceName + "." + namespace + ".svc",
+		fqdn,
+	}
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	service, err := coreClient.Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			h.handler.Log.Warn("Failed to get service", "name", serviceName, "namespace", namespace, "error", err)
+			report.Findings = append(report.Findings, models.Finding{
+				Severity: models.SeverityWarning,
+				Check:    "service",
+				Message:  fmt.Sprintf("failed to get service %s: %v", serviceName, err),
+			})
+			return
+		}
+		finding := models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "service",
+			Message:  fmt.Sprintf("Service %s does not exist in namespace %s, %s will not resolve", serviceName, namespace, fqdn),
+		}
+		// 其他命名空间中的同名Service通常意味着查询名称中的命名空间写错了
+		view.OtherNamespaces = h.findServiceNamespaces(ctx, serviceName, namespace)
+		for _, other := range view.OtherNamespaces {
+			finding.Details = append(finding.Details, fmt.Sprintf("a Service named %s exists in namespace %s: %s", serviceName, other, serviceFQDN(serviceName, other, clusterDomain)))
+		}
+		report.Findings = append(report.Findings, finding)
+		return
+	}
+
+	view.ServiceExists = true
+	view.Type = string(service.Spec.Type)
+	view.ClusterIP = service.Spec.ClusterIP
+	view.Headless = service.Spec.ClusterIP == corev1.ClusterIPNone
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		view.ExternalName = service.Spec.ExternalName
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityInfo,
+			Check:    "type",
+			Message:  fmt.Sprintf("ExternalName service resolves to a CNAME for %s, no endpoints are involved", service.Spec.ExternalName),
+		})
+		return
+	}
+	if !view.Headless {
+		for _, ip := range service.Spec.ClusterIPs {
+			if ip != "" && ip != corev1.ClusterIPNone {
+				view.ExpectedAddresses = append(view.ExpectedAddresses, ip)
+			}
+		}
+	}
+
+	slices, err := h.handler.Client.ClientSet().DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName}).String(),
+	})
+	if err != nil {
+		h.handler.Log.Warn("Failed to list endpoint slices", "service", serviceName, "error", err)
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "endpoints",
+			Message:  fmt.Sprintf("failed to list endpoint slices: %v", err),
+		})
+		return
+	}
+	view.EndpointSlices = len(slices.Items)
+
+	// 无头Service的A记录直接是Pod地址，publishNotReadyAddresses时也包括未就绪的地址
+	var published []string
+	hostnames := make(map[string]bool)
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			if ready {
+				view.ReadyEndpoints += len(endpoint.Addresses)
+			} else {
+				view.NotReadyEndpoints += len(endpoint.Addresses)
+			}
+			if !ready && !service.Spec.PublishNotReadyAddresses {
+				continue
+			}
+			published = append(published, endpoint.Addresses...)
+			if endpoint.Hostname != nil && *endpoint.Hostname != "" {
+				hostnames[*endpoint.Hostname+"."+fqdn] = true
+			}
+		}
+	}
+	if view.Headless {
+		view.ExpectedAddresses = uniqueSorted(published)
+		for hostname := range hostnames {
+			view.PodHostnames = append(view.PodHostnames, hostname)
+		}
+		sort.Strings(view.PodHostnames)
+	}
+
+	switch {
+	case view.Headless && len(view.ExpectedAddresses) == 0:
+		message := fmt.Sprintf("headless Service has no ready pods, %s returns NXDOMAIN", fqdn)
+		if view.NotReadyEndpoints > 0 {
+			message = fmt.Sprintf("headless Service has %d endpoint(s) but none are ready, %s returns NXDOMAIN", view.NotReadyEndpoints, fqdn)
+		}
+		finding := models.Finding{Severity: models.SeverityCritical, Check: "headless", Message: message}
+		if view.NotReadyEndpoints > 0 {
+			finding.Details = []string{"set publishNotReadyAddresses: true if peers must discover each other before becoming ready"}
+		}
+		report.Findings = append(report.Findings, finding)
+	case view.ReadyEndpoints == 0:
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "endpoints",
+			Message:  fmt.Sprintf("%s resolves to the ClusterIP but the Service has no ready endpoints, connections will fail", fqdn),
+		})
+	}
+}
+
+// findServiceNamespaces 查找其他命名空间中的同名Service，没有权限列出时返回空
+func (h *ResourceHandlerImpl) findServiceNamespaces(ctx context.Context, serviceName, exclude string) []string {
+	services, err := h.handler.Client.ClientSet().CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", serviceName).String(),
+	})
+	if err != nil {
+		h.handler.Log.Debug("Failed to search services in other namespaces", "name", serviceName, "error", err)
+		return nil
+	}
+	var namespaces []string
+	for _, service := range services.Items {
+		if service.Namespace != exclude {
+			namespaces = append(namespaces, service.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// checkClusterDNS 检查kube-system中CoreDNS/kube-dns的Deployment和Service
+func (h *ResourceHandlerImpl) checkClusterDNS(ctx context.Context, report *models.DNSCheckReport) {
+	status := &report.DNSServer
+	clientSet := h.handler.Client.ClientSet()
+
+	for _, name := range clusterDNSDeployments {
+		deployment, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			h.handler.Log.Warn("Failed to get cluster DNS deployment", "name", name, "error", err)
+			status.CheckFailedWith = err.Error()
+			report.Findings = append(report.Findings, models.Finding{
+				Severity: models.SeverityInfo,
+				Check:    "cluster-dns",
+				Message:  fmt.Sprintf("could not check the %s deployment in %s: %v", name, metav1.NamespaceSystem, err),
+			})
+			return
+		}
+		status.Deployment = deployment.Name
+		status.ReadyReplicas = deployment.Status.ReadyReplicas
+		if deployment.Spec.Replicas != nil {
+			status.Replicas = *deployment.Spec.Replicas
+		}
+		break
+	}
+
+	switch {
+	case status.Deployment == "":
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "cluster-dns",
+			Message:  fmt.Sprintf("no %s deployment found in %s, cluster DNS may be provided differently", strings.Join(clusterDNSDeployments, " or "), metav1.NamespaceSystem),
+		})
+	case status.ReadyReplicas == 0:
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "cluster-dns",
+			Message:  fmt.Sprintf("%s has no ready replicas, in-cluster name resolution will fail", status.Deployment),
+		})
+	case status.ReadyReplicas < status.Replicas:
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "cluster-dns",
+			Message:  fmt.Sprintf("%s has %d/%d ready replicas, some lookups may time out", status.Deployment, status.ReadyReplicas, status.Replicas),
+		})
+	}
+
+	service, err := clientSet.CoreV1().Services(metav1.NamespaceSystem).Get(ctx, clusterDNSService, metav1.GetOptions{})
+	if err != nil {
+		h.handler.Log.Debug("Failed to get cluster DNS service", "error", err)
+		return
+	}
+	status.ServiceIP = service.Spec.ClusterIP
+	slices, err := clientSet.DiscoveryV1().EndpointSlices(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: clusterDNSService}).String(),
+	})
+	if err != nil {
+		return
+	}
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				status.ReadyEndpoints += len(endpoint.Addresses)
+			}
+		}
+	}
+	if status.ReadyEndpoints == 0 && status.ReadyReplicas > 0 {
+		report.Findings = append(report.Findings, models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "cluster-dns",
+			Message:  fmt.Sprintf("Service %s/%s (%s) has no ready endpoints although %s has ready replicas", metav1.NamespaceSystem, clusterDNSService, status.ServiceIP, status.Deployment),
+		})
+	}
+}
+
+// checkDNSDataPlane 在指定Pod中解析查询名称和完整域名，与控制平面的预期地址对比
+// 服务器未启用exec时跳过并说明原因，指定的Pod不可用时返回错误
+func (h *ResourceHandlerImpl) checkDNSDataPlane(
+	ctx context.Context,
+	namespace, pod, container, queryName string,
+	report *models.DNSCheckReport,
+) (*models.DNSDataPlaneView, *mcp.CallToolResult) {
+	view := &models.DNSDataPlaneView{Pod: pod, Namespace: namespace}
+	if !base.GetOptions().AllowExec {
+		view.Skipped = "executing commands in containers is disabled on this server, start it with --allow-exec to resolve names from the pod"
+		return view, nil
+	}
+	container, errResult := h.execPreflight(ctx, namespace, pod, container)
+	if errResult != nil {
+		return nil, errResult
+	}
+	view.Container = container
+
+	names := []string{queryName}
+	if fqdn := serviceFQDN(report.Service, report.Namespace, report.ClusterDomain); fqdn != queryName {
+		names = append(names, fqdn)
+	}
+	expected := make(map[string]bool)
+	for _, address := range report.ControlPlane.ExpectedAddresses {
+		expected[address] = true
+	}
+
+	// getent使用与应用程序相同的libc解析路径；没有getent时（如busybox镜像）改用nslookup
+	command := "getent"
+	for _, name := range names {
+		lookup := models.DNSLookup{Name: name, Command: command}
+		result, err := h.runDNSLookup(ctx, namespace, pod, container, command, name)
+		if errors.Is(err, errCommandNotFound) && command == "getent" {
+			command = "nslookup"
+			lookup.Command = command
+			result, err = h.runDNSLookup(ctx, namespace, pod, container, command, name)
+		}
+		if err != nil {
+			if errors.Is(err, errCommandNotFound) {
+				view.Skipped = fmt.Sprintf("container %s has neither getent nor nslookup", container)
+				view.Lookups = nil
+				return view, nil
+			}
+			lookup.Error = err.Error()
+			view.Lookups = append(view.Lookups, lookup)
+			continue
+		}
+		view.UsedBusybox = view.UsedBusybox || result.usedBusybox
+
+		output := string(result.stdout)
+		if command == "getent" {
+			lookup.Addresses = parseGetentHosts(output)
+		} else {
+			lookup.Addresses = parseNslookupAddresses(output)
+		}
+		lookup.Resolved = result.exitCode == 0 && len(lookup.Addresses) > 0
+		lookup.MatchesExpected = lookup.Resolved && addressesExpected(lookup.Addresses, expected, report.ControlPlane.ExternalName != "")
+		if !lookup.Resolved {
+			lookup.Output = utils.TruncateString(strings.TrimSpace(output+"\n"+result.stderr), utils.MaxMessageLength)
+		}
+		view.Lookups = append(view.Lookups, lookup)
+		report.Findings = append(report.Findings, dnsLookupFinding(lookup, report, pod))
+	}
+	return view, nil
+}
+
+// runDNSLookup 在容器中执行一次getent hosts或nslookup
+func (h *ResourceHandlerImpl) runDNSLookup(
+	ctx context.Context,
+	namespace, pod, container, command, name string,
+) (*execResult, error) {
+	argv := []string{"nslookup", name}
+	if command == "getent" {
+		argv = []string{"getent", "hosts", name}
+	}
+	return h.execInContainer(ctx, namespace, pod, container, argv, nil, dnsLookupOutputLimit)
+}
+
+// dnsLookupFinding 比较数据平面解析结果与控制平面的预期
+func dnsLookupFinding(lookup models.DNSLookup, report *models.DNSCheckReport, pod string) models.Finding {
+	view := report.ControlPlane
+	shouldResolve := view.ServiceExists && (view.ExternalName != "" || len(view.ExpectedAddresses) > 0)
+	switch {
+	case lookup.Resolved && lookup.MatchesExpected:
+		return models.Finding{
+			Severity: models.SeverityInfo,
+			Check:    "resolution",
+			Message:  fmt.Sprintf("%s resolves from pod %s to %s as expected", lookup.Name, pod, strings.Join(lookup.Addresses, ", ")),
+		}
+	case lookup.Resolved && !shouldResolve:
+		return models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "resolution",
+			Message:  fmt.Sprintf("%s resolves from pod %s to %s although the control plane expects no records, the search path may have matched another name", lookup.Name, pod, strings.Join(lookup.Addresses, ", ")),
+		}
+	case lookup.Resolved:
+		return models.Finding{
+			Severity: models.SeverityWarning,
+			Check:    "resolution",
+			Message:  fmt.Sprintf("%s resolves from pod %s to %s, which does not match the expected %s", lookup.Name, pod, strings.Join(lookup.Addresses, ", "), strings.Join(view.ExpectedAddresses, ", ")),
+			Details:  []string{"the name may resolve in a different namespace through the search path, or the DNS answer is cached"},
+		}
+	case shouldResolve:
+		return models.Finding{
+			Severity: models.SeverityCritical,
+			Check:    "resolution",
+			Message:  fmt.Sprintf("%s does not resolve from pod %s although the Service has records, check cluster DNS and the pod's dnsPolicy", lookup.Name, pod),
+		}
+	default:
+		return models.Finding{
+			Severity: models.SeverityInfo,
+			Check:    "resolution",
+			Message:  fmt.Sprintf("%s does not resolve from pod %s, consistent with the control plane", lookup.Name, pod),
+		}
+	}
+}
+
+// addressesExpected 解析到的地址是否都在预期地址中；ExternalName解析到CNAME目标的地址，只要求能解析
+func addressesExpected(addresses []string, expected map[string]bool, externalName bool) bool {
+	if externalName {
+		return len(addresses) > 0
+	}
+	if len(expected) == 0 {
+		return false
+	}
+	for _, address := range addresses {
+		if !expected[address] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitServiceDNSName 将svc、svc.ns、svc.ns.svc或完整域名拆分为Service名称和命名空间
+func splitServiceDNSName(name, clusterDomain string) (string, string) {
+	name = strings.TrimSuffix(name, "."+clusterDomain)
+	name = strings.TrimSuffix(name, ".svc")
+	service, namespace, _ := strings.Cut(name, ".")
+	namespace, _, _ = strings.Cut(namespace, ".")
+	return service, namespace
+}
+
+// serviceFQDN 返回Service的完整域名
+func serviceFQDN(service, namespace, clusterDomain string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", service, namespace, clusterDomain)
+}
+
+// parseGetentHosts 解析getent hosts的输出，每行第一个字段是地址
+func parseGetentHosts(output string) []string {
+	var addresses []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && net.ParseIP(fields[0]) != nil {
+			addresses = append(addresses, fields[0])
+		}
+	}
+	return uniqueSorted(addresses)
+}
+
+// parseNslookupAddresses 解析nslookup的输出，只取"Name:"之后的地址，跳过开头DNS服务器自身的地址
+// 兼容bind-utils的"Address: 10.0.0.1"和旧版busybox的"Address 1: 10.0.0.1 name"
+func parseNslookupAddresses(output string) []string {
+	var addresses []string
+	answered := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Name:") {
+			answered = true
+			continue
+		}
+		if !answered || !strings.HasPrefix(line, "Address") {
+			continue
+		}
+		_, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		address, _, _ := strings.Cut(fields[0], "#")
+		if net.ParseIP(address) != nil {
+			addresses = append(addresses, address)
+		}
+	}
+	return uniqueSorted(addresses)
+}
+
+// uniqueSorted 去重并排序
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,525 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// maxSpreadTargetSample 最多列出的可扩展目标节点名称数量
+const maxSpreadTargetSample = 20
+
+// spreadSubject 分布报告的对象：工作负载或标签选择器匹配的Pod
+type spreadSubject struct {
+	kind     string
+	name     string
+	replicas *int32
+	selector labels.Selector
+	// pod 判断节点能否容纳新副本所用的Pod规格，来自工作负载的Pod模板或第一个匹配的Pod
+	pod *corev1.Pod
+}
+
+// GetWorkloadSpread 报告工作负载的Pod在节点和可用区上的分布、节点剩余容量、分布约束是否满足以及单点故障
+func (h *ResourceHandlerImpl) GetWorkloadSpread(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	namespaceArg, _ := arguments["namespace"].(string)
+	namespace := h.baseHandler.GetNamespaceWithDefault(namespaceArg)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.handler.Log.Info("Getting workload spread", "kind", kind, "name", name, "namespace", namespace, "labelSelector", labelSelector)
+
+	if kind == "" && labelSelector == "" {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: "either kind and name or labelSelector is required",
+			Hint:    "Pass kind=Deployment or StatefulSet with name, or a labelSelector such as 'app=web' that selects the pods.",
+		}), nil
+	}
+	if kind != "" && name == "" {
+		return utils.NewErrorToolResult("name is required when kind is given"), nil
+	}
+
+	subject := &spreadSubject{kind: kind, name: name}
+	if kind != "" {
+		subject, err = h.spreadWorkload(ctx, kind, namespace, name)
+		if err != nil {
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+		}
+	} else {
+		subject.selector, err = labels.Parse(labelSelector)
+		if err != nil {
+			return utils.NewSelectorErrorResult(err), nil
+		}
+	}
+
+	coreClient := h.handler.Client.ClientSet().CoreV1()
+	namespacePods, err := coreClient.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: subject.selector.String()})
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+	var workloadPods []corev1.Pod
+	for _, pod := range namespacePods.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			workloadPods = append(workloadPods, pod)
+		}
+	}
+	sort.Slice(workloadPods, func(i, j int) bool { return workloadPods[i].Name < workloadPods[j].Name })
+
+	report := &models.WorkloadSpreadReport{
+		Namespace:     namespace,
+		Kind:          subject.kind,
+		Name:          subject.name,
+		LabelSelector: subject.selector.String(),
+		Replicas:      subject.replicas,
+		Pods:          len(workloadPods),
+		PodRequests:   map[string]string{},
+		Nodes:         []models.NodePlacement{},
+		Zones:         []models.ZonePlacement{},
+		Constraints:   []models.SpreadConstraintCheck{},
+		Findings:      []models.AvailabilityFinding{},
+		RetrievedAt:   time.Now(),
+	}
+	if subject.pod == nil {
+		if len(workloadPods) == 0 {
+			report.Notes = append(report.Notes, "no running pods match the selector")
+			return utils.RenderResult(request, report), nil
+		}
+		subject.pod = workloadPods[0].DeepCopy()
+		report.Notes = append(report.Notes, fmt.Sprintf("pod %s is used as the pod spec for capacity and constraint checks", subject.pod.Name))
+	}
+
+	nodes, err := coreClient.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.handler.Log.Error("Failed to list nodes", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list nodes"), nil
+	}
+	scheduled, err := coreClient.Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed,spec.nodeName!=",
+	})
+	if err != nil {
+		h.handler.Log.Error("Failed to list scheduled pods", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+	allocations := utils.ComputeNodeAllocations(nodes.Items, scheduled.Items)
+
+	requests := utils.PodRequests(subject.pod)
+	for resourceName, quantity := range requests {
+		report.PodRequests[string(resourceName)] = utils.FormatResourceQuantity(resourceName, quantity)
+	}
+	podsByNode := make(map[string][]string)
+	for _, pod := range workloadPods {
+		if pod.Spec.NodeName == "" {
+			report.Unscheduled++
+			continue
+		}
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod.Name)
+	}
+
+	fillPlacements(report, subject.pod, requests, allocations, podsByNode)
+
+	for _, constraint := range subject.pod.Spec.TopologySpreadConstraints {
+		report.Constraints = append(report.Constraints, checkTopologySpread(constraint, subject.pod, allocations, scheduled.Items))
+	}
+	if affinity := subject.pod.Spec.Affinity; affinity != nil && affinity.PodAntiAffinity != nil {
+		for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			report.Constraints = append(report.Constraints, checkAntiAffinity(term, true, subject.pod, namespace, allocations, scheduled.Items, workloadPods))
+		}
+		for _, weighted := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			report.Constraints = append(report.Constraints, checkAntiAffinity(weighted.PodAffinityTerm, false, subject.pod, namespace, allocations, scheduled.Items, workloadPods))
+		}
+	}
+
+	spreadFindings(report, subject)
+
+	h.handler.Log.Info("Workload spread computed", "namespace", namespace, "pods", report.Pods, "nodes", len(podsByNode), "findings", len(report.Findings))
+	return utils.RenderResult(request, report), nil
+}
+
+// spreadWorkload 读取Deployment或StatefulSet的选择器、期望副本数和Pod模板
+func (h *ResourceHandlerImpl) spreadWorkload(ctx context.Context, kind, namespace, name string) (*spreadSubject, error) {
+	apps := h.handler.Client.ClientSet().AppsV1()
+	subject := &spreadSubject{kind: kind, name: name}
+	var selector *metav1.LabelSelector
+	var template corev1.PodTemplateSpec
+	switch kind {
+	case "Deployment":
+		deployment, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template, subject.replicas = deployment.Spec.Selector, deployment.Spec.Template, deployment.Spec.Replicas
+	case "StatefulSet":
+		statefulSet, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, template, subject.replicas = statefulSet.Spec.Selector, statefulSet.Spec.Template, statefulSet.Spec.Replicas
+	default:
+		return nil, fmt.Errorf("unsupported kind %q: must be Deployment or StatefulSet", kind)
+	}
+	if subject.replicas == nil {
+		one := int32(1)
+		subject.replicas = &one
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of %s %s: %w", kind, name, err)
+	}
+	if parsed.Empty() {
+		return nil, fmt.Errorf("%s %s has an empty selector", kind, name)
+	}
+	subject.selector = parsed
+	subject.pod = &corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}
+	subject.pod.Namespace = namespace
+	return subject, nil
+}
+
+// fillPlacements 汇总每个节点和可用区的Pod数量、能否容纳新副本以及剩余容量
+// 只列出运行该工作负载Pod或可以容纳其Pod的节点，其余节点只计数
+func fillPlacements(
+	report *models.WorkloadSpreadReport,
+	pod *corev1.Pod,
+	requests corev1.ResourceList,
+	allocations map[string]*utils.NodeAllocation,
+	podsByNode map[string][]string,
+) {
+	nodeNames := make([]string, 0, len(allocations))
+	for nodeName := range allocations {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	zones := make(map[string]*models.ZonePlacement)
+	for _, nodeName := range nodeNames {
+		allocation := allocations[nodeName]
+		placed := podsByNode[nodeName]
+		blockers, untolerated := nodeBlockers(pod, allocation.Node)
+		if len(placed) == 0 && len(blockers) > 0 {
+			report.ExcludedNodes++
+			continue
+		}
+		placement := models.NodePlacement{
+			Node:        nodeName,
+			Zone:        allocation.Node.Labels[corev1.LabelTopologyZone],
+			Pods:        len(placed),
+			PodNames:    placed,
+			Eligible:    len(blockers) == 0,
+			Blockers:    blockers,
+			Untolerated: untolerated,
+			Free:        remainingAfter(requests, allocation, 0),
+		}
+		if placement.Eligible {
+			placement.AdditionalReplicas, placement.LimitedBy = replicasThatFit(requests, allocation)
+		}
+		report.Nodes = append(report.Nodes, placement)
+
+		if placement.Eligible && placement.Pods == 0 && placement.AdditionalReplicas > 0 {
+			report.SpreadTargetNodes++
+			if len(report.SpreadTargetSample) < maxSpreadTargetSample {
+				report.SpreadTargetSample = append(report.SpreadTargetSample, nodeName)
+			}
+		}
+		if placement.Zone == "" {
+			continue
+		}
+		zone := zones[placement.Zone]
+		if zone == nil {
+			zone = &models.ZonePlacement{Zone: placement.Zone}
+			zones[placement.Zone] = zone
+		}
+		zone.Pods += placement.Pods
+		if placement.Pods > 0 {
+			zone.Nodes++
+		}
+		if placement.Eligible {
+			zone.EligibleNodes++
+			zone.AdditionalReplicas += placement.AdditionalReplicas
+		}
+	}
+	report.CanSpreadFurther = report.SpreadTargetNodes > 0
+
+	// 节点已被删除但Pod尚未清理时，Pod仍计入分布
+	for nodeName, placed := range podsByNode {
+		if _, ok := allocations[nodeName]; !ok {
+			report.Nodes = append(report.Nodes, models.NodePlacement{
+				Node:     nodeName,
+				Pods:     len(placed),
+				PodNames: placed,
+				Blockers: []string{"node-not-found"},
+			})
+		}
+	}
+	sort.SliceStable(report.Nodes, func(i, j int) bool {
+		if report.Nodes[i].Pods != report.Nodes[j].Pods {
+			return report.Nodes[i].Pods > report.Nodes[j].Pods
+		}
+		return report.Nodes[i].Node < report.Nodes[j].Node
+	})
+
+	for _, zone := range zones {
+		report.Zones = append(report.Zones, *zone)
+		if zone.Pods == 0 && zone.AdditionalReplicas > 0 {
+			report.SpreadTargetZones = append(report.SpreadTargetZones, zone.Zone)
+		}
+	}
+	sort.Slice(report.Zones, func(i, j int) bool { return report.Zones[i].Zone < report.Zones[j].Zone })
+	sort.Strings(report.SpreadTargetZones)
+	if len(zones) == 0 && len(report.Nodes) > 0 {
+		report.Notes = append(report.Notes, fmt.Sprintf("no node carries the %s label, zone distribution is not available", corev1.LabelTopologyZone))
+	}
+}
+
+// checkTopologySpread 按调度器的规则计算拓扑分布约束在当前分布下的偏差：
+// 拓扑域来自带有topologyKey标签的节点，默认只计入满足Pod的nodeSelector和节点亲和性的节点，
+// nodeTaintsPolicy为Honor时还要求污点已被容忍
+func checkTopologySpread(
+	constraint corev1.TopologySpreadConstraint,
+	pod *corev1.Pod,
+	allocations map[string]*utils.NodeAllocation,
+	scheduled []corev1.Pod,
+) models.SpreadConstraintCheck {
+	check := models.SpreadConstraintCheck{
+		Type:              models.SpreadCheckTopologySpread,
+		TopologyKey:       constraint.TopologyKey,
+		MaxSkew:           constraint.MaxSkew,
+		WhenUnsatisfiable: string(constraint.WhenUnsatisfiable),
+		Counts:            map[string]int{},
+	}
+
+	nodeDomains := make(map[string]string)
+	for nodeName, allocation := range allocations {
+		domain, ok := allocation.Node.Labels[constraint.TopologyKey]
+		if !ok {
+			continue
+		}
+		if constraint.NodeAffinityPolicy == nil || *constraint.NodeAffinityPolicy == corev1.NodeInclusionPolicyHonor {
+			if !nodeMatchesPod(pod, allocation.Node) {
+				continue
+			}
+		}
+		if constraint.NodeTaintsPolicy != nil && *constraint.NodeTaintsPolicy == corev1.NodeInclusionPolicyHonor {
+			if _, untolerated := nodeBlockers(pod, allocation.Node); len(untolerated) > 0 {
+				continue
+			}
+		}
+		nodeDomains[nodeName] = domain
+		check.Counts[domain] += 0
+	}
+	if len(check.Counts) == 0 {
+		check.Message = fmt.Sprintf("no eligible node carries the %s label, the constraint cannot be evaluated", constraint.TopologyKey)
+		return check
+	}
+
+	selector := termSelector(constraint.LabelSelector, constraint.MatchLabelKeys, pod)
+	for _, other := range scheduled {
+		domain, ok := nodeDomains[other.Spec.NodeName]
+		if ok && other.Namespace == pod.Namespace && selector.Matches(labels.Set(other.Labels)) {
+			check.Counts[domain]++
+		}
+	}
+
+	minCount, maxCount := -1, 0
+	for _, count := range check.Counts {
+		if minCount < 0 || count < minCount {
+			minCount = count
+		}
+		maxCount = max(maxCount, count)
+	}
+	// 拓扑域少于minDomains时，调度器把全局最小值视为0
+	if constraint.MinDomains != nil && int32(len(check.Counts)) < *constraint.MinDomains {
+		minCount = 0
+	}
+	skew := maxCount - minCount
+	check.Skew = &skew
+	check.Violated = skew > int(constraint.MaxSkew)
+	if check.Violated {
+		check.Message = fmt.Sprintf("skew %d across %d %s domains exceeds maxSkew %d", skew, len(check.Counts), constraint.TopologyKey, constraint.MaxSkew)
+	} else {
+		check.Message = fmt.Sprintf("skew %d across %d %s domains is within maxSkew %d", skew, len(check.Counts), constraint.TopologyKey, constraint.MaxSkew)
+	}
+	return check
+}
+
+// checkAntiAffinity 检查Pod反亲和性规则：该工作负载的Pod所在的拓扑域中不应有其他匹配规则选择器的Pod
+func checkAntiAffinity(
+	term corev1.PodAffinityTerm,
+	required bool,
+	pod *corev1.Pod,
+	namespace string,
+	allocations map[string]*utils.NodeAllocation,
+	scheduled []corev1.Pod,
+	workloadPods []corev1.Pod,
+) models.SpreadConstraintCheck {
+	check := models.SpreadConstraintCheck{
+		Type:        models.SpreadCheckPodAntiAffinity,
+		TopologyKey: term.TopologyKey,
+		Required:    required,
+		Counts:      map[string]int{},
+	}
+	namespaces := map[string]bool{namespace: true}
+	if len(term.Namespaces) > 0 {
+		namespaces = make(map[string]bool, len(term.Namespaces))
+		for _, name := range term.Namespaces {
+			namespaces[name] = true
+		}
+	}
+	domainOf := func(nodeName string) (string, bool) {
+		allocation, ok := allocations[nodeName]
+		if !ok {
+			return "", false
+		}
+		domain, ok := allocation.Node.Labels[term.TopologyKey]
+		return domain, ok
+	}
+
+	selector := termSelector(term.LabelSelector, term.MatchLabelKeys, pod)
+	for _, other := range scheduled {
+		if !namespaces[other.Namespace] || !selector.Matches(labels.Set(other.Labels)) {
+			continue
+		}
+		if domain, ok := domainOf(other.Spec.NodeName); ok {
+			check.Counts[domain]++
+		}
+	}
+
+	conflicts := make(map[string]bool)
+	for _, workloadPod := range workloadPods {
+		domain, ok := domainOf(workloadPod.Spec.NodeName)
+		if !ok {
+			continue
+		}
+		others := check.Counts[domain]
+		if namespaces[workloadPod.Namespace] && selector.Matches(labels.Set(workloadPod.Labels)) {
+			others--
+		}
+		if others > 0 {
+			conflicts[domain] = true
+		}
+	}
+	check.Violated = len(conflicts) > 0
+	if check.Violated {
+		check.Message = fmt.Sprintf("pods share %s domains with other matching pods: %s", term.TopologyKey, strings.Join(sortedSet(conflicts), ", "))
+	} else {
+		check.Message = fmt.Sprintf("no %s domain holds a pod of the workload together with another matching pod", term.TopologyKey)
+	}
+	if term.NamespaceSelector != nil {
+		check.Message += "; namespaceSelector is not evaluated, only the listed namespaces were counted"
+	}
+	return check
+}
+
+// termSelector 返回规则的标签选择器，并按matchLabelKeys加入Pod自身的标签值
+// 选择器为空时不匹配任何Pod，与调度器一致
+func termSelector(labelSelector *metav1.LabelSelector, matchLabelKeys []string, pod *corev1.Pod) labels.Selector {
+	if labelSelector == nil {
+		return labels.Nothing()
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return labels.Nothing()
+	}
+	for _, key := range matchLabelKeys {
+		value, ok := pod.Labels[key]
+		if !ok {
+			continue
+		}
+		requirement, err := labels.NewRequirement(key, selection.Equals, []string{value})
+		if err == nil {
+			selector = selector.Add(*requirement)
+		}
+	}
+	return selector
+}
+
+// spreadFindings 根据分布和约束检查结果生成单点故障和约束违反的发现
+func spreadFindings(report *models.WorkloadSpreadReport, subject *spreadSubject) {
+	object := subject.kind + "/" + subject.name
+	if subject.kind == "" {
+		object = "pods/" + report.LabelSelector
+	}
+	addFinding := func(severity, check, message, suggestion string) {
+		report.Findings = append(report.Findings, models.AvailabilityFinding{
+			Severity:   severity,
+			Check:      check,
+			Object:     object,
+			Message:    message,
+			Suggestion: suggestion,
+		})
+	}
+	capacityHint := ""
+	if !report.CanSpreadFurther {
+		capacityHint = " No other eligible node has room for another replica, so add capacity or relax the pod's nodeSelector, affinity or tolerations first."
+	}
+
+	scheduled := report.Pods - report.Unscheduled
+	nodesUsed, zonesUsed := 0, 0
+	for _, node := range report.Nodes {
+		if node.Pods > 0 {
+			nodesUsed++
+		}
+	}
+	for _, zone := range report.Zones {
+		if zone.Pods > 0 {
+			zonesUsed++
+		}
+	}
+	switch {
+	case scheduled == 1:
+		addFinding(models.SeverityWarning, "single-replica",
+			fmt.Sprintf("%s runs a single scheduled pod, losing its node causes downtime", object),
+			"Scale to at least 2 replicas and spread them across nodes."+capacityHint)
+	case scheduled > 1 && nodesUsed == 1:
+		suggestion := "Add pod anti-affinity or a topology spread constraint on kubernetes.io/hostname and restart the workload so its pods are rescheduled."
+		if len(report.Constraints) > 0 {
+			suggestion = "The pod template already has spread rules; restart the workload so its pods are rescheduled under them."
+		}
+		addFinding(models.SeverityCritical, "single-node",
+			fmt.Sprintf("all %d scheduled pods of %s are on node %s, losing that node takes the workload down", scheduled, object, report.Nodes[0].Node),
+			suggestion+capacityHint)
+	case scheduled > 1 && zonesUsed == 1 && len(report.Zones) > 1:
+		addFinding(models.SeverityWarning, "single-zone",
+			fmt.Sprintf("all %d scheduled pods of %s are in one zone although eligible nodes exist in %d zones", scheduled, object, len(report.Zones)),
+			fmt.Sprintf("Add a topology spread constraint on %s so replicas survive the loss of a zone.", corev1.LabelTopologyZone)+capacityHint)
+	}
+
+	for _, check := range report.Constraints {
+		if !check.Violated {
+			continue
+		}
+		severity := models.SeverityInfo
+		if check.Required || check.WhenUnsatisfiable == string(corev1.DoNotSchedule) {
+			severity = models.SeverityWarning
+		}
+		addFinding(severity, check.Type+"-violated",
+			fmt.Sprintf("%s on %s is not satisfied: %s", check.Type, check.TopologyKey, check.Message),
+			"The scheduler only enforces constraints when placing pods; restart the workload to rebalance existing pods."+capacityHint)
+	}
+	if report.Unscheduled > 0 {
+		addFinding(models.SeverityWarning, "unscheduled",
+			fmt.Sprintf("%d pods of %s are not scheduled", report.Unscheduled, object),
+			"Use ANALYZE_PENDING_PODS to see why they cannot be placed.")
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return models.SeverityRank(report.Findings[i].Severity) < models.SeverityRank(report.Findings[j].Severity)
+	})
+}
@@ -0,0 +1,329 @@
+package v1
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+// spreadNode 返回就绪的节点：4核8Gi，带有主机名和可用区标签
+func spreadNode(name, zone string, modify ...func(*corev1.Node)) *corev1.Node {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+			corev1.LabelHostname:     name,
+			corev1.LabelTopologyZone: zone,
+		}},
+		Status: corev1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	for _, fn := range modify {
+		fn(node)
+	}
+	return node
+}
+
+// spreadPod 返回web工作负载在node上运行的Pod，extra为额外的标签
+func spreadPod(name, node string, extra map[string]string) *corev1.Pod {
+	podLabels := map[string]string{"app": "web"}
+	maps.Copy(podLabels, extra)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testutil.DefaultNamespace, Labels: podLabels},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{Name: "web", Resources: webRequests()}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func webRequests() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}
+}
+
+// spreadDeployment 返回选择app=web的Deployment，modify修改Pod模板
+func spreadDeployment(replicas int32, modify func(*corev1.PodTemplateSpec)) *appsv1.Deployment {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Resources: webRequests()}}},
+	}
+	if modify != nil {
+		modify(&template)
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: testutil.DefaultNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Template: template,
+		},
+	}
+}
+
+// zoneSpread 返回可用区上maxSkew为1的拓扑分布约束，modify修改约束
+func zoneSpread(modify func(*corev1.TopologySpreadConstraint)) func(*corev1.PodTemplateSpec) {
+	return func(template *corev1.PodTemplateSpec) {
+		constraint := corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}
+		if modify != nil {
+			modify(&constraint)
+		}
+		template.Spec.TopologySpreadConstraints = append(template.Spec.TopologySpreadConstraints, constraint)
+	}
+}
+
+// zoneAntiAffinity 返回在可用区上排斥其他web Pod的必需反亲和性
+func zoneAntiAffinity(template *corev1.PodTemplateSpec) {
+	template.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+			TopologyKey:   corev1.LabelTopologyZone,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}},
+	}}
+}
+
+func gpuTaint(node *corev1.Node) {
+	node.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+}
+
+func ssd(node *corev1.Node) {
+	node.Labels["disk"] = "ssd"
+}
+
+func TestGetWorkloadSpread(t *testing.T) {
+	oldHash := map[string]string{"pod-template-hash": "old"}
+	newHash := map[string]string{"pod-template-hash": "new"}
+
+	tests := []struct {
+		name     string
+		nodes    []*corev1.Node
+		pods     []*corev1.Pod
+		template func(*corev1.PodTemplateSpec)
+		// counts 第一条约束每个拓扑域的Pod数量，nil表示模板中没有约束
+		counts   map[string]int
+		violated bool
+		// findings 按顺序的发现，格式为severity/check
+		findings  []string
+		excluded  int
+		canSpread bool
+	}{
+		{
+			name:      "all pods on one node",
+			nodes:     []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:      []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-1", nil)},
+			findings:  []string{"critical/single-node"},
+			canSpread: true,
+		},
+		{
+			name:      "all pods in one zone",
+			nodes:     []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("a-2", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:      []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-2", nil)},
+			findings:  []string{"warning/single-zone"},
+			canSpread: true,
+		},
+		{
+			name:      "spread across zones",
+			nodes:     []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:      []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "b-1", nil)},
+			template:  zoneSpread(nil),
+			counts:    map[string]int{"zone-a": 1, "zone-b": 1},
+			findings:  []string{},
+			canSpread: false,
+		},
+		{
+			name:      "skew violation",
+			nodes:     []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("a-2", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:      []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-1", nil), spreadPod("web-3", "a-2", nil)},
+			template:  zoneSpread(nil),
+			counts:    map[string]int{"zone-a": 3, "zone-b": 0},
+			violated:  true,
+			findings:  []string{"warning/single-zone", "warning/topologySpreadConstraint-violated"},
+			canSpread: true,
+		},
+		{
+			name:  "minDomains above the number of domains",
+			nodes: []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("a-2", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:  []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-2", nil), spreadPod("web-3", "b-1", nil)},
+			template: zoneSpread(func(constraint *corev1.TopologySpreadConstraint) {
+				constraint.MinDomains = ptr.To[int32](3)
+			}),
+			counts:   map[string]int{"zone-a": 2, "zone-b": 1},
+			violated: true,
+			findings: []string{"warning/topologySpreadConstraint-violated"},
+		},
+		{
+			name:  "tainted node counted when taints are ignored",
+			nodes: []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b", gpuTaint)},
+			pods:  []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-1", nil)},
+			template: zoneSpread(func(constraint *corev1.TopologySpreadConstraint) {
+				constraint.WhenUnsatisfiable = corev1.ScheduleAnyway
+			}),
+			counts:   map[string]int{"zone-a": 2, "zone-b": 0},
+			violated: true,
+			findings: []string{"critical/single-node", "info/topologySpreadConstraint-violated"},
+			excluded: 1,
+		},
+		{
+			name:  "tainted node excluded from domains",
+			nodes: []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b", gpuTaint)},
+			pods:  []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-1", nil)},
+			template: zoneSpread(func(constraint *corev1.TopologySpreadConstraint) {
+				constraint.NodeTaintsPolicy = ptr.To(corev1.NodeInclusionPolicyHonor)
+			}),
+			counts:   map[string]int{"zone-a": 2},
+			findings: []string{"critical/single-node"},
+			excluded: 1,
+		},
+		{
+			name:  "node outside the nodeSelector excluded from domains",
+			nodes: []*corev1.Node{spreadNode("a-1", "zone-a", ssd), spreadNode("b-1", "zone-b")},
+			pods:  []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-1", nil)},
+			template: func(template *corev1.PodTemplateSpec) {
+				template.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+				zoneSpread(nil)(template)
+			},
+			counts:   map[string]int{"zone-a": 2},
+			findings: []string{"critical/single-node"},
+			excluded: 1,
+		},
+		{
+			name: "cordoned node cannot take more replicas",
+			nodes: []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b", func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			})},
+			pods:     []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-1", nil)},
+			findings: []string{"critical/single-node"},
+			excluded: 1,
+		},
+		{
+			name:  "matchLabelKeys only counts the current revision",
+			nodes: []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods: []*corev1.Pod{
+				spreadPod("web-old-1", "a-1", oldHash), spreadPod("web-old-2", "a-1", oldHash),
+				spreadPod("web-old-3", "a-1", oldHash), spreadPod("web-new-1", "b-1", newHash),
+			},
+			template: func(template *corev1.PodTemplateSpec) {
+				template.Labels["pod-template-hash"] = "new"
+				zoneSpread(func(constraint *corev1.TopologySpreadConstraint) {
+					constraint.MatchLabelKeys = []string{"pod-template-hash"}
+				})(template)
+			},
+			counts:   map[string]int{"zone-a": 0, "zone-b": 1},
+			findings: []string{},
+		},
+		{
+			name:      "anti-affinity on a shared zone",
+			nodes:     []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("a-2", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:      []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "a-2", nil)},
+			template:  zoneAntiAffinity,
+			counts:    map[string]int{"zone-a": 2},
+			violated:  true,
+			findings:  []string{"warning/single-zone", "warning/podAntiAffinity-violated"},
+			canSpread: true,
+		},
+		{
+			name:      "anti-affinity satisfied",
+			nodes:     []*corev1.Node{spreadNode("a-1", "zone-a"), spreadNode("b-1", "zone-b")},
+			pods:      []*corev1.Pod{spreadPod("web-1", "a-1", nil), spreadPod("web-2", "b-1", nil)},
+			template:  zoneAntiAffinity,
+			counts:    map[string]int{"zone-a": 1, "zone-b": 1},
+			findings:  []string{},
+			canSpread: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{spreadDeployment(int32(len(tt.pods)), tt.template)}
+			for _, node := range tt.nodes {
+				objects = append(objects, node)
+			}
+			for _, pod := range tt.pods {
+				objects = append(objects, pod)
+			}
+
+			result, err := NewResourceHandler(testutil.NewFakeClient(objects...)).Handle(context.Background(),
+				testutil.NewToolRequest(GET_WORKLOAD_SPREAD, map[string]any{"kind": "Deployment", "name": "web"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var report models.WorkloadSpreadReport
+			if err := testutil.DecodeResult(result, &report); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.counts == nil {
+				if len(report.Constraints) != 0 {
+					t.Fatalf("constraints = %+v, want none", report.Constraints)
+				}
+			} else {
+				if len(report.Constraints) != 1 {
+					t.Fatalf("constraints = %+v, want one", report.Constraints)
+				}
+				check := report.Constraints[0]
+				if !maps.Equal(check.Counts, tt.counts) || check.Violated != tt.violated {
+					t.Fatalf("counts %v, violated %v (%s); want %v, %v", check.Counts, check.Violated, check.Message, tt.counts, tt.violated)
+				}
+			}
+
+			findings := []string{}
+			for _, finding := range report.Findings {
+				findings = append(findings, finding.Severity+"/"+finding.Check)
+			}
+			if tt.findings != nil && !slices.Equal(findings, tt.findings) {
+				t.Errorf("findings = %v, want %v", findings, tt.findings)
+			}
+			if report.ExcludedNodes != tt.excluded || report.CanSpreadFurther != tt.canSpread {
+				t.Errorf("excluded %d nodes, can spread %v; want %d, %v", report.ExcludedNodes, report.CanSpreadFurther, tt.excluded, tt.canSpread)
+			}
+		})
+	}
+}
+
+func TestGetWorkloadSpreadByLabelSelector(t *testing.T) {
+	client := testutil.NewFakeClient(
+		spreadNode("a-1", "zone-a"),
+		spreadNode("b-1", "zone-b"),
+		spreadPod("web-1", "a-1", nil),
+		spreadPod("web-2", "", nil),
+	)
+	result, err := NewResourceHandler(client).Handle(context.Background(),
+		testutil.NewToolRequest(GET_WORKLOAD_SPREAD, map[string]any{"labelSelector": "app=web"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report models.WorkloadSpreadReport
+	if err := testutil.DecodeResult(result, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []string{}
+	for _, finding := range report.Findings {
+		findings = append(findings, finding.Severity+"/"+finding.Check)
+	}
+	if want := []string{"warning/single-replica", "warning/unscheduled"}; !slices.Equal(findings, want) {
+		t.Fatalf("findings = %v, want %v", findings, want)
+	}
+	if report.Pods != 2 || report.Unscheduled != 1 || report.Findings[0].Object != "pods/app=web" {
+		t.Fatalf("report = %+v", report)
+	}
+}
@@ -0,0 +1,211 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// AccessReviewTTL SelfSubjectAccessReview 结果的缓存时间
+const AccessReviewTTL = 30 * time.Second
+
+// accessReviewCache 缓存权限检查结果，避免连续检查多个操作时频繁请求API Server
+var accessReviewCache = struct {
+	sync.Mutex
+	entries map[string]models.AccessCheckResult
+}{entries: make(map[string]models.AccessCheckResult)}
+
+// CheckAccess 使用 SelfSubjectAccessReview 检查当前身份是否拥有指定权限
+func (h *Handler) CheckAccess(ctx context.Context, attrs authorizationv1.ResourceAttributes) (*models.AccessCheckResult, error) {
+	key := strings.Join([]string{
+		accessReviewIdentity(h.Client.GetRESTConfig()),
+		attrs.Verb, attrs.Group, attrs.Version, attrs.Resource, attrs.Subresource, attrs.Namespace, attrs.Name,
+	}, "|")
+
+	accessReviewCache.Lock()
+	if cached, ok := accessReviewCache.entries[key]; ok && time.Since(cached.CheckedAt) < AccessReviewTTL {
+		accessReviewCache.Unlock()
+		cached.Cached = true
+		return &cached, nil
+	}
+	accessReviewCache.Unlock()
+
+	review, err := h.Client.ClientSet().AuthorizationV1().SelfSubjectAccessReviews().Create(ctx,
+		&authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &attrs,
+			},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.AccessCheckResult{
+		Verb:            attrs.Verb,
+		Group:           attrs.Group,
+		Resource:        attrs.Resource,
+		Subresource:     attrs.Subresource,
+		Namespace:       attrs.Namespace,
+		Name:            attrs.Name,
+		Allowed:         review.Status.Allowed,
+		Denied:          review.Status.Denied,
+		Reason:          review.Status.Reason,
+		EvaluationError: review.Status.EvaluationError,
+		CheckedAt:       time.Now(),
+	}
+
+	accessReviewCache.Lock()
+	accessReviewCache.entries[key] = result
+	accessReviewCache.Unlock()
+
+	h.Log.Debug("Access reviewed",
+		"verb", attrs.Verb,
+		"group", attrs.Group,
+		"resource", attrs.Resource,
+		"namespace", attrs.Namespace,
+		"allowed", result.Allowed,
+	)
+	return &result, nil
+}
+
+// accessReviewIdentity 返回发起SSAR的有效身份标识，包含集群地址、模拟身份和凭据指纹，
+// 不同身份的检查结果互不复用；凭据只以哈希形式出现在缓存键中
+func accessReviewIdentity(config *rest.Config) string {
+	if config == nil {
+		return ""
+	}
+	impersonate := config.Impersonate
+	groups := append([]string(nil), impersonate.Groups...)
+	sort.Strings(groups)
+	extraKeys := make([]string, 0, len(impersonate.Extra))
+	for k := range impersonate.Extra {
+		extraKeys = append(extraKeys, k)
+	}
+	sort.Strings(extraKeys)
+	extra := make([]string, 0, len(extraKeys))
+	for _, k := range extraKeys {
+		extra = append(extra, k+"="+strings.Join(impersonate.Extra[k], ","))
+	}
+
+	credentials := sha256.Sum256([]byte(strings.Join([]string{
+		config.Username,
+		config.Password,
+		config.BearerToken,
+		config.BearerTokenFile,
+		config.CertFile,
+		string(config.CertData),
+	}, "\x00")))
+
+	return strings.Join([]string{
+		config.Host,
+		impersonate.UserName,
+		impersonate.UID,
+		strings.Join(groups, ","),
+		strings.Join(extra, ";"),
+		hex.EncodeToString(credentials[:8]),
+	}, "|")
+}
+
+// PreflightCheck 在执行变更操作前检查当前身份是否拥有对应权限
+// 未开启预检、权限允许或SelfSubjectAccessReview本身不可用时返回nil，调用方继续执行；
+// 权限不足时返回描述缺失权限的错误结果
+func (h *Handler) PreflightCheck(
+	ctx context.Context,
+	verb string,
+	gvr schema.GroupVersionResource,
+	namespace string,
+	name string,
+) *mcp.CallToolResult {
+	if !GetOptions().PreflightAuthz {
+		return nil
+	}
+
+	result, err := h.CheckAccess(ctx, authorizationv1.ResourceAttributes{
+		Verb:      verb,
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: namespace,
+		Name:      name,
+	})
+	if err != nil {
+		// 某些集群限制了SSAR本身，此时不阻塞操作，由API Server做最终判断
+		h.Log.Warn("Preflight authorization check unavailable, skipping",
+			"verb", verb,
+			"resource", gvr.String(),
+			"namespace", namespace,
+			"error", err,
+		)
+		return nil
+	}
+
+	h.Log.Info("Preflight authorization check",
+		"verb", verb,
+		"group", gvr.Group,
+		"resource", gvr.Resource,
+		"namespace", namespace,
+		"name", name,
+		"allowed", result.Allowed,
+		"reason", result.Reason,
+		"cached", result.Cached,
+	)
+	if result.Allowed {
+		return nil
+	}
+	return utils.NewErrorToolResult(missingPermissionMessage(result))
+}
+
+// PreflightCheckObject 根据对象的GVK解析资源后执行权限预检
+func (h *Handler) PreflightCheckObject(ctx context.Context, verb string, obj *unstructured.Unstructured) *mcp.CallToolResult {
+	if !GetOptions().PreflightAuthz {
+		return nil
+	}
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := h.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		h.Log.Warn("Failed to resolve resource for preflight check, skipping", "gvk", gvk.String(), "error", err)
+		return nil
+	}
+	return h.PreflightCheck(ctx, verb, mapping.Resource, obj.GetNamespace(), obj.GetName())
+}
+
+// missingPermissionMessage 构建缺失权限的错误描述，例如 "missing permission: create deployments.apps in namespace foo"
+func missingPermissionMessage(result *models.AccessCheckResult) string {
+	resource := result.Resource
+	if result.Subresource != "" {
+		resource += "/" + result.Subresource
+	}
+	if result.Group != "" {
+		resource += "." + result.Group
+	}
+
+	msg := fmt.Sprintf("missing permission: %s %s", result.Verb, resource)
+	if result.Name != "" {
+		msg += fmt.Sprintf(" named %s", result.Name)
+	}
+	if result.Namespace != "" {
+		msg += fmt.Sprintf(" in namespace %s", result.Namespace)
+	} else {
+		msg += " at cluster scope"
+	}
+	if result.Reason != "" {
+		msg += fmt.Sprintf(" (%s)", result.Reason)
+	}
+	return msg
+}
@@ -0,0 +1,62 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func TestCheckAccessCacheKey(t *testing.T) {
+	accessReviewCache.Lock()
+	accessReviewCache.entries = make(map[string]models.AccessCheckResult)
+	accessReviewCache.Unlock()
+
+	client := testutil.NewFakeClient()
+	reviews := 0
+	client.FakeClientset().PrependReactor("create", "selfsubjectaccessreviews",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			reviews++
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = client.GetRESTConfig().Impersonate.UserName == ""
+			return true, review, nil
+		})
+	h := NewHandler(client, interfaces.NamespaceScope, interfaces.AppsAPIGroup)
+	attrs := authorizationv1.ResourceAttributes{
+		Verb: "create", Group: "apps", Version: "v1", Resource: "deployments", Namespace: "team-a",
+	}
+
+	check := func(name string, attrs authorizationv1.ResourceAttributes, wantReviews int, wantCached, wantAllowed bool) {
+		t.Helper()
+		result, err := h.CheckAccess(context.Background(), attrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reviews != wantReviews || result.Cached != wantCached || result.Allowed != wantAllowed {
+			t.Fatalf("%s: reviews=%d cached=%v allowed=%v, want %d %v %v",
+				name, reviews, result.Cached, result.Allowed, wantReviews, wantCached, wantAllowed)
+		}
+	}
+
+	check("first check", attrs, 1, false, true)
+	check("same attributes", attrs, 1, true, true)
+
+	beta := attrs
+	beta.Version = "v1beta1"
+	check("different version", beta, 2, false, true)
+
+	client.GetRESTConfig().Impersonate = rest.ImpersonationConfig{UserName: "alice"}
+	check("impersonated user", attrs, 3, false, false)
+	check("impersonated user again", attrs, 3, true, false)
+
+	client.GetRESTConfig().Impersonate = rest.ImpersonationConfig{}
+	client.GetRESTConfig().BearerToken = "other-token"
+	check("different credentials", attrs, 4, false, true)
+}
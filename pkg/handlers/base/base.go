@@ -1,12 +1,18 @@
 package base
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
 	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
 // Handler 提供公共功能
+// 处理工具调用时优先使用logger.FromContext(ctx)获取带有requestId的日志记录器，Log用于与调用无关的日志
 type Handler struct {
 	Client kubernetes.Client
 	Log    logger.Logger
@@ -33,3 +39,87 @@ func (h *Handler) GetScope() interfaces.ResourceScope {
 func (h *Handler) GetAPIGroup() interfaces.APIGroup {
 	return h.Group
 }
+
+// Options 处理程序的全局选项，由配置在启动时设置
+type Options struct {
+	// PreflightAuthz 变更操作前是否先通过SelfSubjectAccessReview检查权限
+	PreflightAuthz bool
+	// AllowSecretValues 是否允许在调用方明确要求时返回Secret的值
+	AllowSecretValues bool
+	// AllowExec 是否允许在容器中执行命令
+	AllowExec bool
+	// MaxCopyBytes 复制到容器或从容器复制的单个文件的最大字节数
+	MaxCopyBytes int
+	// BackupDir 备份清单超过内联阈值时写入的服务器本地目录，为空时始终内联返回
+	BackupDir string
+	// BackupInlineLimit 备份清单内联返回的最大字节数
+	BackupInlineLimit int
+	// MaxListItems 列表工具单次返回的最大资源数量，超过时截断并标记
+	MaxListItems int
+	// Retry 暂时性API错误与更新冲突的重试配置
+	Retry utils.RetryOptions
+	// KustomizeRoot 按目录构建kustomization时允许读取的服务器本地根目录，为空时禁止按目录构建
+	KustomizeRoot string
+	// ManifestURLPrefixes APPLY_FROM_URL允许获取的URL前缀，为空时禁止从URL获取清单
+	ManifestURLPrefixes []string
+	// MaxManifestURLBytes APPLY_FROM_URL获取的清单的最大字节数
+	MaxManifestURLBytes int
+	// ResourceKinds 以MCP资源形式公开的资源类型
+	ResourceKinds []string
+	// ResourceNamespaces 以MCP资源形式公开的命名空间，为空表示所有命名空间
+	ResourceNamespaces []string
+	// ResourceMaxBytes 读取单个MCP资源返回的最大字节数
+	ResourceMaxBytes int
+	// NamespacePresets CREATE_NAMESPACE可用的ResourceQuota/LimitRange模板
+	NamespacePresets map[string]models.NamespacePreset
+	// ToolNaming 通用资源工具的命名方式：ToolNamingFlat或ToolNamingPrefixed，为空时同样使用平铺名称
+	ToolNaming string
+	// LegacyToolAliases ToolNamingFlat时是否注册按API组命名的旧工具名作为别名
+	LegacyToolAliases bool
+	// Settings 供GET_SERVER_STATUS报告的服务器配置
+	Settings models.ServerSettings
+}
+
+// options 处理程序的全局选项，重新加载配置时整体替换，读取方始终得到一致的副本
+var options atomic.Pointer[Options]
+
+// SetOptions 设置处理程序的全局选项，需在注册处理程序前调用
+func SetOptions(o Options) {
+	options.Store(&o)
+}
+
+// UpdateOptions 在当前全局选项的副本上应用修改后整体替换，用于运行时重新加载配置
+func UpdateOptions(update func(*Options)) {
+	next := GetOptions()
+	update(&next)
+	options.Store(&next)
+}
+
+// GetOptions 返回处理程序的全局选项
+func GetOptions() Options {
+	if o := options.Load(); o != nil {
+		return *o
+	}
+	return Options{}
+}
+
+var registeredTools []string
+
+// SetRegisteredTools 记录按配置过滤后实际注册的工具，在注册处理程序后调用
+func SetRegisteredTools(tools []string) {
+	registeredTools = tools
+}
+
+// RegisteredTools 返回实际注册的工具
+func RegisteredTools() []string {
+	return registeredTools
+}
+
+// RetryOnTransient 按全局重试配置执行只读调用，遇到限流、超时等暂时性错误时退避重试，返回重试次数
+func (h *Handler) RetryOnTransient(ctx context.Context, fn func() error) (int, error) {
+	retries, err := utils.RetryOnTransient(ctx, GetOptions().Retry, fn)
+	if retries > 0 {
+		logger.FromContext(ctx).Warn("Retried transient API errors", "retries", retries, "error", err)
+	}
+	return retries, err
+}
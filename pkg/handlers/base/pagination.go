@@ -0,0 +1,147 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// 列表结果的排序方式
+const (
+	// SortByName 按资源名称排序
+	SortByName = "name"
+	// SortByCreationTimestamp 按创建时间排序
+	SortByCreationTimestamp = "creationTimestamp"
+	// SortByKind 按资源类型排序，同类型内按名称排序
+	SortByKind = "kind"
+	// DefaultMaxListItems 未配置时单次列表返回的最大资源数量
+	DefaultMaxListItems = 500
+)
+
+// ListPage 描述一次分页列表请求的参数
+type ListPage struct {
+	// Limit 实际传给API Server的单页数量
+	Limit int64
+	// Continue 上一页返回的continue令牌
+	Continue string
+	// SortBy 页内排序方式，为空时保持API Server返回的顺序
+	SortBy string
+	// Capped 调用方未指定limit或指定的limit超过上限，被截断为最大值
+	Capped bool
+}
+
+// ListPageOptions 返回LIST工具共用的分页与排序参数定义
+func ListPageOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("单页返回的最大资源数量。未指定或超过服务器上限（默认%d）时按上限截断，并在响应中标记truncated。", DefaultMaxListItems)),
+		),
+		mcp.WithString("continue",
+			mcp.Description("上一页响应中返回的continue令牌，用于获取下一页。需与上一次请求使用相同的过滤条件。"),
+		),
+		mcp.WithString("sortBy",
+			mcp.Description("页内排序方式：\n- name：按名称排序\n- creationTimestamp：按创建时间排序\n- kind：按资源类型排序，同类型内按名称排序\n不指定时保持API Server返回的顺序。注意：排序在服务器本地进行，仅作用于当前页。"),
+			mcp.Enum(SortByName, SortByCreationTimestamp, SortByKind),
+		),
+	}
+}
+
+// ParseListPage 从请求参数中读取分页与排序参数
+func ParseListPage(request mcp.CallToolRequest) (ListPage, error) {
+	arguments := request.GetArguments()
+	maxItems := int64(GetOptions().MaxListItems)
+	if maxItems <= 0 {
+		maxItems = DefaultMaxListItems
+	}
+
+	page := ListPage{Limit: maxItems, Capped: true}
+	if limit, ok := arguments["limit"].(float64); ok && limit > 0 {
+		if int64(limit) <= maxItems {
+			page.Limit = int64(limit)
+			page.Capped = false
+		}
+	}
+	page.Continue, _ = arguments["continue"].(string)
+
+	page.SortBy, _ = arguments["sortBy"].(string)
+	switch page.SortBy {
+	case "", SortByName, SortByCreationTimestamp, SortByKind:
+	default:
+		return ListPage{}, fmt.Errorf("unsupported sortBy %q, must be one of: %s, %s, %s", page.SortBy, SortByName, SortByCreationTimestamp, SortByKind)
+	}
+	return page, nil
+}
+
+// ApplyTo 将分页参数写入列表选项
+func (p ListPage) ApplyTo(listOptions *clientpkg.ListOptions) {
+	listOptions.Limit = p.Limit
+	listOptions.Continue = p.Continue
+}
+
+// Result 对API Server返回的列表进行截断与排序，并生成响应中的分页信息
+func (p ListPage) Result(list *unstructured.UnstructuredList) models.ListPagination {
+	pagination := models.ListPagination{
+		Continue:           list.GetContinue(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	}
+	// API Server未遵循limit时在本地截断，避免序列化无上限的结果
+	if int64(len(list.Items)) > p.Limit {
+		list.Items = list.Items[:p.Limit]
+		pagination.Truncated = true
+	}
+	if p.Capped && pagination.Continue != "" {
+		pagination.Truncated = true
+	}
+	if pagination.Truncated {
+		pagination.Limit = p.Limit
+	}
+
+	sortUnstructured(list.Items, p.SortBy)
+	return pagination
+}
+
+// sortUnstructured 按指定方式对资源进行页内排序
+func sortUnstructured(items []unstructured.Unstructured, sortBy string) {
+	byName := func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	}
+	switch sortBy {
+	case SortByName:
+		sort.SliceStable(items, byName)
+	case SortByKind:
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].GetKind() != items[j].GetKind() {
+				return items[i].GetKind() < items[j].GetKind()
+			}
+			return byName(i, j)
+		})
+	case SortByCreationTimestamp:
+		sort.SliceStable(items, func(i, j int) bool {
+			ti, tj := items[i].GetCreationTimestamp(), items[j].GetCreationTimestamp()
+			return ti.Before(&tj)
+		})
+	}
+}
+
+// groupByNamespace 将资源按命名空间稳定排序，使同一命名空间的资源相邻且保持页内顺序，返回各命名空间的资源数量
+func groupByNamespace(resources []models.ResourceInfo) []models.NamespaceItemCount {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].Namespace < resources[j].Namespace
+	})
+	var counts []models.NamespaceItemCount
+	for _, resource := range resources {
+		if len(counts) == 0 || counts[len(counts)-1].Namespace != resource.Namespace {
+			counts = append(counts, models.NamespaceItemCount{Namespace: resource.Namespace})
+		}
+		counts[len(counts)-1].Count++
+	}
+	return counts
+}
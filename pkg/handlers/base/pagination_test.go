@@ -0,0 +1,66 @@
+package base
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func TestSortUnstructured(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := func(kind, namespace, name string, age time.Duration) unstructured.Unstructured {
+		var u unstructured.Unstructured
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		u.SetCreationTimestamp(metav1.NewTime(created.Add(-age)))
+		return u
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{sortBy: "", want: []string{"Service/b/web", "Pod/a/web", "Pod/a/db", "ConfigMap/a/web"}},
+		{sortBy: SortByName, want: []string{"Pod/a/db", "Pod/a/web", "ConfigMap/a/web", "Service/b/web"}},
+		{sortBy: SortByKind, want: []string{"ConfigMap/a/web", "Pod/a/db", "Pod/a/web", "Service/b/web"}},
+		{sortBy: SortByCreationTimestamp, want: []string{"Pod/a/db", "ConfigMap/a/web", "Pod/a/web", "Service/b/web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			items := []unstructured.Unstructured{
+				item("Service", "b", "web", time.Minute),
+				item("Pod", "a", "web", 2*time.Minute),
+				item("Pod", "a", "db", 4*time.Minute),
+				item("ConfigMap", "a", "web", 3*time.Minute),
+			}
+			sortUnstructured(items, tt.sortBy)
+
+			got := make([]string, 0, len(items))
+			for _, u := range items {
+				got = append(got, u.GetKind()+"/"+u.GetNamespace()+"/"+u.GetName())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseListPageSortBy(t *testing.T) {
+	for _, sortBy := range []string{SortByName, SortByCreationTimestamp, SortByKind} {
+		page, err := ParseListPage(testutil.NewToolRequest("LIST_RESOURCES", map[string]any{"sortBy": sortBy}))
+		if err != nil || page.SortBy != sortBy {
+			t.Fatalf("sortBy %s: page %+v, error %v", sortBy, page, err)
+		}
+	}
+	if _, err := ParseListPage(testutil.NewToolRequest("LIST_RESOURCES", map[string]any{"sortBy": "size"})); err == nil {
+		t.Fatal("unsupported sortBy was accepted")
+	}
+}
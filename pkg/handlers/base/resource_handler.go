@@ -2,15 +2,17 @@ package base
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -22,6 +24,16 @@ import (
 	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
+// 更新资源的方式
+const (
+	// ApplyModeUpdate 使用完整对象替换更新资源
+	ApplyModeUpdate = "update"
+	// ApplyModeServerSideApply 使用服务端应用更新资源
+	ApplyModeServerSideApply = "serverSideApply"
+	// DefaultFieldManager 默认的字段管理器名称
+	DefaultFieldManager = "kubernetes-mcp"
+)
+
 // 确保ResourceHandler实现了BaseResourceHandler接口
 var _ interfaces.BaseResourceHandler = (*ResourceHandler)(nil)
 
@@ -45,25 +57,32 @@ func NewResourceHandlerPtr(h Handler, resourcePrefix string) *ResourceHandler {
 }
 
 // Register 注册通用资源处理工具
-func (h *ResourceHandler) Register(server *server.MCPServer) {
-	prefix := h.resourcePrefix
+func (h *ResourceHandler) Register(server interfaces.ToolRegistrar) {
 	h.Log.Info("Registering resource handlers",
 		"scope", h.Scope,
 		"apiGroup", h.Group,
-		"prefix", prefix,
+		"prefix", h.resourcePrefix,
+		"toolNaming", GetOptions().ToolNaming,
 	)
+	scope := h.toolScope()
 	// 注册列出资源工具
-	server.AddTool(mcp.NewTool(fmt.Sprintf("LIST_%s_RESOURCES", prefix),
-		mcp.WithDescription(fmt.Sprintf("列出指定API组的Kubernetes资源（作用域：%s）。支持按命名空间过滤和标签选择器过滤。适用于资源监控、状态检查、依赖分析等场景。返回资源的基本信息列表。注意：在大规模集群中，建议使用标签选择器限制返回数量。", h.Scope)),
+	listToolOptions := []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("列出%s。支持按命名空间、标签选择器和字段选择器过滤，选择器由API Server求值；sortBy排序在服务器本地对当前页进行。适用于资源监控、状态检查、依赖分析等场景。返回资源的基本信息列表。结果按limit分页，响应中的continue令牌可用于获取下一页。注意：在大规模集群中，建议使用标签选择器限制返回数量。", scope)),
 		mcp.WithString("kind",
 			mcp.Description("资源类型，例如：'Pod'、'Deployment'、'Service'等。区分大小写，必须是集群支持的资源类型。"),
 		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本（可选），例如：'v1'、'apps/v1'。未指定时按kind解析首选版本，kind属于多个API组时需要指定。"),
+		),
 		mcp.WithString("namespace",
-			mcp.Description("资源所在的命名空间。如果是集群级资源则忽略此参数。默认为'default'命名空间。"),
-			mcp.DefaultString("default"),
+			mcp.Description("资源所在的命名空间。不指定时使用kubeconfig中的当前命名空间，再为空则使用'default'。集群级资源（如Node、ClusterRole、StorageClass、CRD）不能指定此参数。"),
+		),
+		mcp.WithBoolean("allNamespaces",
+			mcp.Description("是否跨所有命名空间列出，不能与namespace同时指定。结果按命名空间分组，仍受limit上限约束并通过continue分页，响应中包含结果来自的命名空间数量。集群级资源忽略此参数。服务器配置了命名空间白名单时不可用。默认为false。"),
+			mcp.DefaultBool(false),
 		),
 		mcp.WithString("fieldSelector",
-			mcp.Description("Kubernetes字段选择器，用于按资源属性进行过滤。例如：'status.phase=Running'表示只显示运行中的资源。支持多个条件，使用逗号分隔。"),
+			mcp.Description("Kubernetes字段选择器，由API Server按资源属性过滤。例如：'status.phase=Running'表示只显示运行中的Pod，'metadata.name!=kube-root-ca.crt'排除指定名称。支持=、==、!=，多个条件使用逗号分隔。所有类型都支持metadata.name和metadata.namespace，其他字段因类型而异，不支持时返回错误。可与labelSelector同时使用。"),
 		),
 		mcp.WithString("labelSelector",
 			mcp.Description("Kubernetes标签选择器，用于按资源标签进行过滤。例如：'app=nginx'表示只显示带有app=nginx标签的资源。支持多个标签，使用逗号分隔。"),
@@ -72,11 +91,14 @@ func (h *ResourceHandler) Register(server *server.MCPServer) {
 			mcp.Description("是否显示资源的所有标签。启用后将在输出中包含完整的标签列表，有助于资源分类和管理。默认为false。"),
 			mcp.DefaultBool(false),
 		),
-	), h.ListResources)
+	}
+	listToolOptions = append(listToolOptions, ListPageOptions()...)
+	listToolOptions = append(listToolOptions, utils.WithFormat(), utils.WithTimeoutSeconds())
+	tools := []resourceTool{{operation: OperationList, options: listToolOptions, handler: h.ListResources}}
 
 	// 注册获取资源工具
-	server.AddTool(mcp.NewTool(fmt.Sprintf("GET_%s_RESOURCE", prefix),
-		mcp.WithDescription(fmt.Sprintf("获取指定API组中的资源详情（作用域：%s）。返回资源的完整定义，包括：元数据、规格配置、状态信息等。适用于资源检查、问题诊断、状态验证等场景。支持查看历史版本（如果启用了资源版本跟踪）。", h.Scope)),
+	tools = append(tools, resourceTool{operation: OperationGet, options: []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("获取%s的详情。返回资源的完整定义，包括：元数据、规格配置、状态信息等。适用于资源检查、问题诊断、状态验证等场景。支持查看历史版本（如果启用了资源版本跟踪）。", scope)),
 		mcp.WithString("kind",
 			mcp.Description("资源类型，例如：'Pod'、'Deployment'等。区分大小写，必须是集群中存在的资源类型。"),
 			mcp.Required(),
@@ -90,14 +112,26 @@ func (h *ResourceHandler) Register(server *server.MCPServer) {
 			mcp.Required(),
 		),
 		mcp.WithString("namespace",
-			mcp.Description("资源所在的命名空间。如果是集群级资源则忽略此参数。默认为'default'命名空间。"),
-			mcp.DefaultString("default"),
+			mcp.Description("资源所在的命名空间。不指定时使用kubeconfig中的当前命名空间，再为空则使用'default'。集群级资源（如Node、ClusterRole、StorageClass、CRD）不能指定此参数。"),
 		),
-	), h.GetResource)
+		mcp.WithBoolean("export",
+			mcp.Description("是否以导出格式返回。启用后移除status、uid、resourceVersion、managedFields等由服务端填充的字段，返回可直接提交到Git或重新应用的清单。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("maxValueBytes",
+			mcp.Description(fmt.Sprintf("ConfigMap和Secret中单个值的大小上限（字节），默认为%d。超过上限的值以及所有binaryData值会被替换为以'<summarized'开头、包含长度和sha256的占位值，并在注解%s中列出被替换的字段；这样的对象不能直接重新应用。", utils.DefaultMaxConfigValueBytes, utils.SummarizedKeysAnnotation)),
+		),
+		mcp.WithArray("fullKeys",
+			mcp.Description("需要完整返回的ConfigMap或Secret键，例如[\"app.js\"]。仍受服务器输出总大小上限的约束。"),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	}, handler: h.GetResource})
 
 	// 注册描述资源工具
-	server.AddTool(mcp.NewTool(fmt.Sprintf("DESCRIBE_%s_RESOURCE", prefix),
-		mcp.WithDescription(fmt.Sprintf("详细描述指定API组中的资源（作用域：%s）。提供比GET更丰富的信息，包括：事件历史、关联资源、运行状态、配置详情等。适用于深入排查问题、监控资源状态、分析资源关系等场景。自动关联显示相关的事件信息。", h.Scope)),
+	tools = append(tools, resourceTool{operation: OperationDescribe, options: []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("详细描述%s。提供比GET更丰富的信息，包括：事件历史、关联资源、运行状态、配置详情等。适用于深入排查问题、监控资源状态、分析资源关系等场景。自动关联显示相关的事件信息。", scope)),
 		mcp.WithString("kind",
 			mcp.Description("资源类型，例如：'Pod'、'Deployment'等。区分大小写，必须是集群中存在的资源类型。"),
 			mcp.Required(),
@@ -111,32 +145,65 @@ func (h *ResourceHandler) Register(server *server.MCPServer) {
 			mcp.Required(),
 		),
 		mcp.WithString("namespace",
-			mcp.Description("资源所在的命名空间。如果是集群级资源则忽略此参数。默认为'default'命名空间。"),
-			mcp.DefaultString("default"),
+			mcp.Description("资源所在的命名空间。不指定时使用kubeconfig中的当前命名空间，再为空则使用'default'。集群级资源（如Node、ClusterRole、StorageClass、CRD）不能指定此参数。"),
 		),
-	), h.DescribeResource)
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	}, handler: h.DescribeResource})
 
 	// 注册创建资源工具
-	server.AddTool(mcp.NewTool(fmt.Sprintf("CREATE_%s_RESOURCE", prefix),
+	tools = append(tools, resourceTool{operation: OperationCreate, options: []mcp.ToolOption{
 		mcp.WithDescription("创建新的API资源。支持从YAML定义创建资源，自动处理依赖关系。适用于部署应用、创建配置、初始化资源等场景。创建前会进行资源验证和冲突检查。注意：某些资源可能需要特定的权限才能创建。"),
 		mcp.WithString("yaml",
 			mcp.Description("资源的YAML定义。必须是有效的Kubernetes资源清单，包含：apiVersion、kind、metadata等必要字段。支持引用ConfigMap和Secret。注意处理敏感信息。"),
 			mcp.Required(),
 		),
-	), h.CreateResource)
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。启用后API Server会完整校验并模拟创建，但不会持久化任何变更。建议在正式创建前先试运行。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	}, handler: h.CreateResource})
 
 	// 注册更新资源工具
-	server.AddTool(mcp.NewTool(fmt.Sprintf("UPDATE_%s_RESOURCE", prefix),
-		mcp.WithDescription(fmt.Sprintf("更新指定API组中的资源（作用域：%s）。支持声明式更新，自动处理资源版本冲突。适用于配置变更、规格调整、状态更新等场景。建议先预览变更再应用。", h.Scope)),
+	tools = append(tools, resourceTool{operation: OperationUpdate, options: []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("更新%s。支持声明式更新，自动处理资源版本冲突。适用于配置变更、规格调整、状态更新等场景。建议先预览变更再应用。", scope)),
 		mcp.WithString("yaml",
 			mcp.Description("资源的YAML定义。必须是有效的Kubernetes资源清单，包含完整的资源定义。系统会根据资源名称和命名空间查找并更新目标资源。"),
 			mcp.Required(),
 		),
-	), h.UpdateResource)
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。启用后API Server会完整校验并模拟更新，但不会持久化任何变更。建议在正式更新前先试运行。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("expectedResourceVersion",
+			mcp.Description("期望的资源版本（metadata.resourceVersion）。指定后会先读取集群中的当前对象，若版本不一致则返回冲突错误而不是覆盖，用于避免基于过期YAML的修改。"),
+		),
+		mcp.WithString("applyMode",
+			mcp.Description("更新方式：\n- update：使用完整对象替换（默认），需要与集群中的resourceVersion一致\n- serverSideApply：使用服务端应用（Server-Side Apply），只修改YAML中声明的字段，保留由控制器管理的其他字段"),
+			mcp.DefaultString(ApplyModeUpdate),
+			mcp.Enum(ApplyModeUpdate, ApplyModeServerSideApply),
+		),
+		mcp.WithString("fieldManager",
+			mcp.Description("服务端应用时使用的字段管理器名称，用于跟踪字段所有权。仅在applyMode为serverSideApply时生效。"),
+			mcp.DefaultString(DefaultFieldManager),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("服务端应用时是否强制接管与其他字段管理器冲突的字段。未启用时如发生冲突，将返回冲突字段及其当前管理器列表，由调用方决定是否强制。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("retryOnConflict",
+			mcp.Description("发生资源版本冲突时是否自动重试。启用后会重新获取最新对象，以其resourceVersion重新提交YAML中的内容，最多重试服务器配置的次数。仅在applyMode为update时生效，不能与expectedResourceVersion同时使用。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	}, handler: h.UpdateResource})
 
 	// 注册删除资源工具
-	server.AddTool(mcp.NewTool(fmt.Sprintf("DELETE_%s_RESOURCE", prefix),
-		mcp.WithDescription(fmt.Sprintf("删除指定API组中的资源（作用域：%s）。支持级联删除关联资源。适用于资源清理、环境重置、应用卸载等场景。注意：某些资源可能有终结器（Finalizer）导致删除需要较长时间。", h.Scope)),
+	tools = append(tools, resourceTool{operation: OperationDelete, options: []mcp.ToolOption{
+		mcp.WithDescription(fmt.Sprintf("删除%s。支持级联删除关联资源。适用于资源清理、环境重置、应用卸载等场景。注意：某些资源可能有终结器（Finalizer）导致删除需要较长时间。", scope)),
 		mcp.WithString("kind",
 			mcp.Description("资源类型，例如：'Pod'、'Deployment'等。区分大小写，必须是集群中存在的资源类型。"),
 			mcp.Required(),
@@ -150,10 +217,13 @@ func (h *ResourceHandler) Register(server *server.MCPServer) {
 			mcp.Required(),
 		),
 		mcp.WithString("namespace",
-			mcp.Description("资源所在的命名空间。如果是集群级资源则忽略此参数。默认为'default'命名空间。"),
-			mcp.DefaultString("default"),
+			mcp.Description("资源所在的命名空间。不指定时使用kubeconfig中的当前命名空间，再为空则使用'default'。集群级资源（如Node、ClusterRole、StorageClass、CRD）不能指定此参数。"),
 		),
-	), h.DeleteResource)
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	}, handler: h.DeleteResource})
+
+	h.registerResourceTools(server, tools)
 }
 
 // GetNamespaceWithDefault 获取命名空间，如果为空则使用kubeconfig中的命名空间，再为空则使用default
@@ -174,6 +244,70 @@ func (h *ResourceHandler) GetNamespaceWithDefault(incomingNamespace string) stri
 	return "default"
 }
 
+// resolveScope 通过RESTMapper判断资源的作用域，返回实际使用的命名空间以及资源是否为集群级。
+// 集群级资源不使用命名空间，指定了命名空间时返回错误结果；无法确定作用域时按命名空间级资源处理，由API Server报告错误
+func (h *ResourceHandler) resolveScope(gvk schema.GroupVersionKind, namespaceArg string) (string, bool, *mcp.CallToolResult) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	namespaced, err := h.Client.IsObjectNamespaced(obj)
+	if err != nil {
+		h.Log.Debug("Failed to determine resource scope, treating as namespaced", "kind", gvk.Kind, "error", err)
+		return h.GetNamespaceWithDefault(namespaceArg), false, nil
+	}
+	if namespaced {
+		return h.GetNamespaceWithDefault(namespaceArg), false, nil
+	}
+	if namespaceArg != "" {
+		return "", true, clusterScopedNamespaceRefused(gvk.Kind, namespaceArg)
+	}
+	return "", true, nil
+}
+
+// clusterScopedNamespaceRefused 拒绝为集群级资源指定命名空间
+func clusterScopedNamespaceRefused(kind, namespace string) *mcp.CallToolResult {
+	return utils.NewToolErrorResult(models.ToolError{
+		Code:    utils.ErrorCodeInvalid,
+		Message: fmt.Sprintf("%s is cluster-scoped and does not belong to a namespace, but namespace %q was specified", kind, namespace),
+		Hint:    "Omit the namespace argument (and metadata.namespace in manifests) for cluster-scoped resources.",
+	})
+}
+
+// maxNameSuggestions 资源不存在时最多给出的相近名称数量
+const maxNameSuggestions = 5
+
+// nameSuggestions 列出同一命名空间中同类资源的名称，返回与name相近的名称。
+// 只获取元数据，列出失败时不影响原错误，返回空
+func (h *ResourceHandler) nameSuggestions(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) []string {
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	var opts []clientpkg.ListOption
+	if namespace != "" {
+		opts = append(opts, clientpkg.InNamespace(namespace))
+	}
+	if err := h.Client.List(ctx, list, opts...); err != nil {
+		h.Log.Debug("Failed to list names for suggestions", "kind", gvk.Kind, "namespace", namespace, "error", err)
+		return nil
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+	matches := utils.MatchNames(name, names, maxNameSuggestions)
+	suggestions := make([]string, 0, len(matches))
+	for _, match := range matches {
+		suggestions = append(suggestions, match.Name)
+	}
+	return suggestions
+}
+
+// notFoundResult 将获取或删除资源的错误转换为错误结果，资源不存在时附带相近名称
+func (h *ResourceHandler) notFoundResult(ctx context.Context, err error, gvk schema.GroupVersionKind, namespace, name, message string) *mcp.CallToolResult {
+	if !errors.IsNotFound(err) {
+		return utils.NewKubeErrorResult(err, message)
+	}
+	return utils.NewKubeErrorResultWithSuggestions(err, h.nameSuggestions(ctx, gvk, namespace, name), message)
+}
+
 // ListResources 实现通用的资源列表功能
 func (h *ResourceHandler) ListResources(
 	ctx context.Context,
@@ -183,22 +317,60 @@ func (h *ResourceHandler) ListResources(
 	kind, _ := arguments["kind"].(string)
 	apiVersion, _ := arguments["apiVersion"].(string)
 	namespaceArg, _ := arguments["namespace"].(string)
-	labelSelector, _ := arguments["labelSelector"].(string)
-
-	// 获取命名空间，使用合适的默认值
-	namespace := h.GetNamespaceWithDefault(namespaceArg)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	fieldSelector, err := utils.SelectorArgument(arguments, utils.FieldSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	showLabels, _ := arguments["showLabels"].(bool)
+	allNamespaces, _ := arguments["allNamespaces"].(bool)
+	page, err := ParseListPage(request)
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	if allNamespaces && namespaceArg != "" {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: "namespace and allNamespaces are mutually exclusive",
+			Hint:    "Omit namespace to list across all namespaces, or set allNamespaces=false to list a single namespace.",
+		}), nil
+	}
 
 	h.Log.Info("Listing resources",
 		"kind", kind,
 		"apiVersion", apiVersion,
-		"namespace", namespace,
+		"namespace", namespaceArg,
+		"allNamespaces", allNamespaces,
 		"labelSelector", labelSelector,
+		"fieldSelector", fieldSelector,
+		"sortBy", page.SortBy,
 		"group", h.Group,
 	)
 
+	// 未指定apiVersion时按kind解析首选版本
+	if apiVersion == "" {
+		apiVersion, err = h.resolveListAPIVersion(request.Params.Name, kind)
+		if err != nil {
+			return utils.NewKubeErrorResult(err), nil
+		}
+	}
+
 	// 解析GroupVersionKind
 	gvk := utils.ParseGVK(apiVersion, kind)
 
+	// 集群级资源不限定命名空间，并忽略allNamespaces
+	namespace, clusterScoped, refused := h.resolveScope(gvk, namespaceArg)
+	if refused != nil {
+		return refused, nil
+	}
+	if allNamespaces {
+		allNamespaces = !clusterScoped
+		namespace = ""
+	}
+
 	// 创建列表对象
 	list := &unstructured.UnstructuredList{}
 	list.SetGroupVersionKind(schema.GroupVersionKind{
@@ -223,35 +395,66 @@ func (h *ResourceHandler) ListResources(
 		// 为列表选项设置标签选择器
 		listOptions.LabelSelector = selector
 	}
+	if fieldSelector != "" {
+		// 字段选择器由API Server求值，不支持的字段在List时返回"field label not supported"
+		selector, err := fields.ParseSelector(fieldSelector)
+		if err != nil {
+			h.Log.Error("Failed to parse field selector",
+				"fieldSelector", fieldSelector,
+				"error", err,
+			)
+			return utils.NewErrorToolResult(fmt.Sprintf("failed to parse field selector: %v", err)), nil
+		}
+		clientpkg.MatchingFieldsSelector{Selector: selector}.ApplyToList(listOptions)
+	}
+	page.ApplyTo(listOptions)
 
-	// 列出资源
-	err := h.Client.List(ctx, list, listOptions)
+	// 列出资源，暂时性错误时退避重试
+	retries, err := h.RetryOnTransient(ctx, func() error {
+		return h.Client.List(ctx, list, listOptions)
+	})
 	if err != nil {
 		h.Log.Error("Failed to list resources",
 			"kind", kind,
 			"namespace", namespace,
 			"labelSelector", labelSelector,
+			"fieldSelector", fieldSelector,
 			"error", err,
 		)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to list resources: %v", err)), nil
+		return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "failed to list resources"), retries), nil
 	}
 
 	// 构建响应
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Found %d %s resources", len(list.Items), kind))
-
-	if namespace != "" {
-		result.WriteString(fmt.Sprintf(" in namespace %s", namespace))
+	pagination := page.Result(list)
+	response := models.ResourceListResponse{
+		Count:          len(list.Items),
+		Kind:           kind,
+		APIVersion:     apiVersion,
+		Namespace:      namespace,
+		ClusterScoped:  clusterScoped,
+		LabelSelector:  labelSelector,
+		FieldSelector:  fieldSelector,
+		Resources:      make([]models.ResourceInfo, 0, len(list.Items)),
+		ListPagination: pagination,
+		RetrievedAt:    time.Now(),
 	}
-
-	if labelSelector != "" {
-		result.WriteString(fmt.Sprintf(" with label selector '%s'", labelSelector))
-	}
-
-	result.WriteString(":\n\n")
-
 	for _, item := range list.Items {
-		result.WriteString(fmt.Sprintf("Name: %s\n", item.GetName()))
+		info := models.ResourceInfo{
+			Name:         item.GetName(),
+			Namespace:    item.GetNamespace(),
+			Kind:         item.GetKind(),
+			APIVersion:   item.GetAPIVersion(),
+			CreationTime: item.GetCreationTimestamp().Time,
+		}
+		if showLabels {
+			info.Labels = item.GetLabels()
+		}
+		response.Resources = append(response.Resources, info)
+	}
+	if allNamespaces {
+		response.AllNamespaces = true
+		response.Namespaces = groupByNamespace(response.Resources)
+		response.NamespaceCount = len(response.Namespaces)
 	}
 
 	h.Log.Info("Resources listed successfully",
@@ -259,16 +462,10 @@ func (h *ResourceHandler) ListResources(
 		"namespace", namespace,
 		"labelSelector", labelSelector,
 		"count", len(list.Items),
+		"truncated", pagination.Truncated,
 	)
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, response), retries), nil
 }
 
 // GetResource 实现通用的资源获取功能
@@ -281,27 +478,48 @@ func (h *ResourceHandler) GetResource(
 	apiVersion, _ := arguments["apiVersion"].(string)
 	name, _ := arguments["name"].(string)
 	namespaceArg, _ := arguments["namespace"].(string)
+	export, _ := arguments["export"].(bool)
+	maxValueBytes := utils.DefaultMaxConfigValueBytes
+	if value, ok := arguments["maxValueBytes"].(float64); ok && value > 0 {
+		maxValueBytes = int(value)
+	}
+	var fullKeys []string
+	if rawKeys, ok := arguments["fullKeys"].([]interface{}); ok {
+		for _, rawKey := range rawKeys {
+			if key, ok := rawKey.(string); ok {
+				fullKeys = append(fullKeys, key)
+			}
+		}
+	}
 
-	// 获取命名空间，使用合适的默认值
-	namespace := h.GetNamespaceWithDefault(namespaceArg)
+	// 解析GroupVersionKind
+	gvk := utils.ParseGVK(apiVersion, kind)
+
+	// 获取命名空间，集群级资源不使用命名空间
+	namespace, _, refused := h.resolveScope(gvk, namespaceArg)
+	if refused != nil {
+		return refused, nil
+	}
 
 	h.Log.Info("Getting resource",
 		"kind", kind,
 		"apiVersion", apiVersion,
 		"name", name,
 		"namespace", namespace,
+		"export", export,
+		"maxValueBytes", maxValueBytes,
+		"fullKeys", fullKeys,
 		"group", h.Group,
 	)
 
-	// 解析GroupVersionKind
-	gvk := utils.ParseGVK(apiVersion, kind)
-
 	// 创建对象
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(gvk)
 
-	// 获取资源
-	err := h.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	// 获取资源，暂时性错误时退避重试
+	retries, err := h.RetryOnTransient(ctx, func() error {
+		return h.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	})
 	if err != nil {
 		h.Log.Error("Failed to get resource",
 			"kind", kind,
@@ -309,21 +527,22 @@ func (h *ResourceHandler) GetResource(
 			"namespace", namespace,
 			"error", err,
 		)
-		if errors.IsNotFound(err) {
-			return utils.NewErrorToolResult(fmt.Sprintf("resource not found (Kind: %s, Name: %s, Namespace: %s)", kind, name, namespace)), nil
-		}
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to get resource: %v", err)), nil
+		return utils.WithRetryMeta(h.notFoundResult(ctx, err, gvk, namespace, name, fmt.Sprintf("failed to get %s %s %s", kind, name, describeLocation(namespace))), retries), nil
 	}
 
-	// 转换为YAML
-	yamlData, err := yaml.Marshal(obj.Object)
-	if err != nil {
-		h.Log.Error("Failed to marshal resource to YAML",
-			"kind", kind,
-			"name", name,
-			"error", err,
-		)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to marshal to YAML: %v", err)), nil
+	// Secret默认脱敏，避免将值直接带入模型上下文
+	if utils.RedactSecret(obj) {
+		h.Log.Debug("Secret values redacted", "name", name, "namespace", namespace)
+	}
+
+	// 导出模式移除服务端填充的字段
+	if export {
+		utils.CleanForExport(obj)
+	}
+
+	// ConfigMap和Secret中的大值替换为摘要，避免嵌入的大文件撑满上下文
+	if summarized := utils.SummarizeConfigData(obj, maxValueBytes, fullKeys); len(summarized) > 0 {
+		h.Log.Debug("Large values summarized", "name", name, "namespace", namespace, "keys", summarized)
 	}
 
 	h.Log.Info("Resource retrieved successfully",
@@ -332,14 +551,7 @@ func (h *ResourceHandler) GetResource(
 		"namespace", namespace,
 	)
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(yamlData),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, obj.Object), retries), nil
 }
 
 // DescribeResource 实现通用的资源详细描述功能
@@ -353,8 +565,14 @@ func (h *ResourceHandler) DescribeResource(
 	name, _ := arguments["name"].(string)
 	namespaceArg, _ := arguments["namespace"].(string)
 
-	// 获取命名空间，使用合适的默认值
-	namespace := h.GetNamespaceWithDefault(namespaceArg)
+	// 解析GroupVersionKind
+	gvk := utils.ParseGVK(apiVersion, kind)
+
+	// 获取命名空间，集群级资源不使用命名空间
+	namespace, _, refused := h.resolveScope(gvk, namespaceArg)
+	if refused != nil {
+		return refused, nil
+	}
 
 	h.Log.Info("Describing resource",
 		"kind", kind,
@@ -364,15 +582,14 @@ func (h *ResourceHandler) DescribeResource(
 		"group", h.Group,
 	)
 
-	// 解析GroupVersionKind
-	gvk := utils.ParseGVK(apiVersion, kind)
-
 	// 创建对象
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(gvk)
 
-	// 获取资源
-	err := h.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	// 获取资源，暂时性错误时退避重试
+	retries, err := h.RetryOnTransient(ctx, func() error {
+		return h.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj)
+	})
 	if err != nil {
 		h.Log.Error("Failed to get resource for description",
 			"kind", kind,
@@ -380,40 +597,22 @@ func (h *ResourceHandler) DescribeResource(
 			"namespace", namespace,
 			"error", err,
 		)
-		if errors.IsNotFound(err) {
-			return utils.NewErrorToolResult(fmt.Sprintf("resource not found (Kind: %s, Name: %s, Namespace: %s)", kind, name, namespace)), nil
-		}
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to describe resource: %v", err)), nil
+		return utils.WithRetryMeta(h.notFoundResult(ctx, err, gvk, namespace, name, fmt.Sprintf("failed to describe %s %s %s", kind, name, describeLocation(namespace))), retries), nil
 	}
 
+	// Secret默认脱敏
+	utils.RedactSecret(obj)
+
 	// 构建资源描述
 	description := models.NewResourceDescriptionFromUnstructured(obj)
 
-	// 序列化为JSON
-	jsonData, err := json.MarshalIndent(description, "", "  ")
-	if err != nil {
-		h.Log.Error("Failed to marshal resource description to JSON",
-			"kind", kind,
-			"name", name,
-			"error", err,
-		)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to marshal to JSON: %v", err)), nil
-	}
-
 	h.Log.Info("Resource described successfully",
 		"kind", kind,
 		"name", name,
 		"namespace", namespace,
 	)
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, description), retries), nil
 }
 
 // CreateResource 创建资源
@@ -428,6 +627,7 @@ func (h *ResourceHandler) CreateResource(ctx context.Context, request mcp.CallTo
 	obj := &unstructured.Unstructured{}
 	arguments := request.GetArguments()
 	yamlStr, _ := arguments["yaml"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
 	if err := yaml.Unmarshal([]byte(yamlStr), obj); err != nil {
 		h.Log.Error("Failed to parse YAML",
 			"error", err,
@@ -445,15 +645,27 @@ func (h *ResourceHandler) CreateResource(ctx context.Context, request mcp.CallTo
 		"expected_group", h.Group,
 	)
 
-	// 获取命名空间
-	if obj.GetNamespace() == "" {
-		defaultNs := h.GetNamespaceWithDefault("")
-		obj.SetNamespace(defaultNs)
-		h.Log.Debug("Empty namespace in resource, setting namespace", "namespace", defaultNs)
+	// 命名空间级资源未指定命名空间时使用默认命名空间，集群级资源不能指定命名空间
+	if refused := h.setDefaultNamespace(obj); refused != nil {
+		return refused, nil
+	}
+
+	// GET返回的摘要占位值不是真实内容，直接写回会覆盖原有数据
+	if utils.IsSummarizedConfigData(obj) {
+		return summarizedObjectRefused(obj), nil
+	}
+
+	// 权限预检
+	if denied := h.PreflightCheckObject(ctx, "create", obj); denied != nil {
+		return denied, nil
 	}
 
 	// 创建资源
-	if err := h.Client.Create(ctx, obj); err != nil {
+	var createOpts []clientpkg.CreateOption
+	if dryRun {
+		createOpts = append(createOpts, clientpkg.DryRunAll)
+	}
+	if err := h.Client.Create(ctx, obj, createOpts...); err != nil {
 		h.Log.Error("Failed to create resource",
 			"error", err,
 			"group", gvk.Group,
@@ -461,29 +673,28 @@ func (h *ResourceHandler) CreateResource(ctx context.Context, request mcp.CallTo
 			"kind", gvk.Kind,
 			"namespace", obj.GetNamespace(),
 		)
-		if errors.IsAlreadyExists(err) {
-			return utils.NewErrorToolResult(fmt.Sprintf("resource already exists: %v", err)), nil
-		}
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to create resource: %v", err)), nil
+		return utils.NewKubeErrorResult(err, "failed to create resource"), nil
 	}
 
+	h.Client.Cache().InvalidateKind(gvk.Kind)
 	h.Log.Info("Resource created successfully",
 		"group", gvk.Group,
 		"version", gvk.Version,
 		"kind", gvk.Kind,
 		"namespace", obj.GetNamespace(),
 		"name", obj.GetName(),
+		"dryRun", dryRun,
 	)
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("Successfully created %s/%s in namespace %s",
-					gvk.Kind, obj.GetName(), obj.GetNamespace()),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, models.ResourceOperationResult{
+		Operation: "create",
+		Kind:      gvk.Kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		DryRun:    dryRun,
+		Message: fmt.Sprintf("Successfully created %s/%s %s%s",
+			gvk.Kind, obj.GetName(), describeLocation(obj.GetNamespace()), dryRunSuffix(dryRun)),
+	}), nil
 }
 
 // UpdateResource 实现通用的资源更新功能
@@ -493,8 +704,36 @@ func (h *ResourceHandler) UpdateResource(
 ) (*mcp.CallToolResult, error) {
 	arguments := request.GetArguments()
 	yamlStr, _ := arguments["yaml"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
+	expectedResourceVersion, _ := arguments["expectedResourceVersion"].(string)
+	applyMode, _ := arguments["applyMode"].(string)
+	fieldManager, _ := arguments["fieldManager"].(string)
+	force, _ := arguments["force"].(bool)
+	retryOnConflict, _ := arguments["retryOnConflict"].(bool)
+
+	if applyMode == "" {
+		applyMode = ApplyModeUpdate
+	}
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
 
-	h.Log.Info("Updating resource from YAML", "group", h.Group)
+	h.Log.Info("Updating resource from YAML",
+		"group", h.Group,
+		"dryRun", dryRun,
+		"expectedResourceVersion", expectedResourceVersion,
+		"applyMode", applyMode,
+		"fieldManager", fieldManager,
+		"force", force,
+		"retryOnConflict", retryOnConflict,
+	)
+
+	if applyMode != ApplyModeUpdate && applyMode != ApplyModeServerSideApply {
+		return utils.NewErrorToolResult(fmt.Sprintf("unsupported applyMode: %s, supported modes are: %s, %s", applyMode, ApplyModeUpdate, ApplyModeServerSideApply)), nil
+	}
+	if retryOnConflict && expectedResourceVersion != "" {
+		return utils.NewErrorToolResult("retryOnConflict cannot be combined with expectedResourceVersion"), nil
+	}
 
 	// 解析YAML
 	obj := &unstructured.Unstructured{}
@@ -503,11 +742,9 @@ func (h *ResourceHandler) UpdateResource(
 		h.Log.Error("Failed to parse YAML", "error", err)
 		return utils.NewErrorToolResult(fmt.Sprintf("failed to parse YAML: %v", err)), nil
 	}
-	// 如果命名空间为空，使用default或kubeconfig中的
-	if obj.GetNamespace() == "" {
-		defaultNs := h.GetNamespaceWithDefault("")
-		obj.SetNamespace(defaultNs)
-		h.Log.Debug("Empty namespace in resource, setting namespace", "namespace", defaultNs)
+	// 命名空间资源未指定命名空间时，使用default或kubeconfig中的；集群级资源不能指定命名空间
+	if refused := h.setDefaultNamespace(obj); refused != nil {
+		return refused, nil
 	}
 
 	h.Log.Debug("Parsed resource from YAML",
@@ -516,33 +753,260 @@ func (h *ResourceHandler) UpdateResource(
 		"namespace", obj.GetNamespace(),
 	)
 
+	// GET返回的摘要占位值不是真实内容，直接写回会覆盖原有数据
+	if utils.IsSummarizedConfigData(obj) {
+		return summarizedObjectRefused(obj), nil
+	}
+
+	// 权限预检，服务端应用使用patch动词
+	preflightVerb := "update"
+	if applyMode == ApplyModeServerSideApply {
+		preflightVerb = "patch"
+	}
+	if denied := h.PreflightCheckObject(ctx, preflightVerb, obj); denied != nil {
+		return denied, nil
+	}
+
+	// 如果指定了期望的资源版本，先校验集群中的当前版本，避免覆盖他人的修改
+	if expectedResourceVersion != "" {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		err = h.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, live)
+		if err != nil {
+			h.Log.Error("Failed to get live resource for version check",
+				"kind", obj.GetKind(),
+				"name", obj.GetName(),
+				"namespace", obj.GetNamespace(),
+				"error", err,
+			)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get live %s/%s %s", obj.GetKind(), obj.GetName(), describeLocation(obj.GetNamespace()))), nil
+		}
+		if live.GetResourceVersion() != expectedResourceVersion {
+			h.Log.Warn("Resource version conflict",
+				"kind", obj.GetKind(),
+				"name", obj.GetName(),
+				"namespace", obj.GetNamespace(),
+				"expected", expectedResourceVersion,
+				"actual", live.GetResourceVersion(),
+			)
+			return utils.NewErrorToolResult(fmt.Sprintf("conflict: %s/%s %s has resourceVersion %s, expected %s; the object was modified since it was read, fetch the latest version and retry",
+				obj.GetKind(), obj.GetName(), describeLocation(obj.GetNamespace()), live.GetResourceVersion(), expectedResourceVersion)), nil
+		}
+		// 使用期望的版本进行更新，让API Server在并发修改时同样拒绝请求
+		obj.SetResourceVersion(expectedResourceVersion)
+	}
+
+	if applyMode == ApplyModeServerSideApply {
+		return h.serverSideApply(ctx, request, obj, fieldManager, force, dryRun)
+	}
+
 	// 更新资源
-	err = h.Client.Update(ctx, obj)
+	var updateOpts []clientpkg.UpdateOption
+	if dryRun {
+		updateOpts = append(updateOpts, clientpkg.DryRunAll)
+	}
+	var retries int
+	if retryOnConflict {
+		retries, err = h.updateWithConflictRetry(ctx, obj, updateOpts)
+	} else {
+		err = h.Client.Update(ctx, obj, updateOpts...)
+	}
 	if err != nil {
 		h.Log.Error("Failed to update resource",
 			"kind", obj.GetKind(),
 			"name", obj.GetName(),
 			"namespace", obj.GetNamespace(),
+			"retries", retries,
 			"error", err,
 		)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to update resource: %v", err)), nil
+		return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "failed to update resource"), retries), nil
 	}
 
+	h.Client.Cache().InvalidateKind(obj.GetKind())
 	h.Log.Info("Resource updated successfully",
 		"kind", obj.GetKind(),
 		"name", obj.GetName(),
 		"namespace", obj.GetNamespace(),
+		"dryRun", dryRun,
+	)
+
+	return utils.WithRetryMeta(utils.RenderResult(request, models.ResourceOperationResult{
+		Operation: "update",
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		DryRun:    dryRun,
+		Message: fmt.Sprintf("Successfully updated %s/%s %s%s",
+			obj.GetKind(), obj.GetName(), describeLocation(obj.GetNamespace()), dryRunSuffix(dryRun)),
+	}), retries), nil
+}
+
+// updateWithConflictRetry 更新资源，发生版本冲突时重新获取最新对象，并以其resourceVersion重新提交期望的内容
+func (h *ResourceHandler) updateWithConflictRetry(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+	updateOpts []clientpkg.UpdateOption,
+) (int, error) {
+	desired := obj.DeepCopy()
+	attempts := 0
+	retries, err := utils.RetryOnConflict(ctx, GetOptions().Retry, func() error {
+		attempts++
+		if attempts > 1 {
+			live := &unstructured.Unstructured{}
+			live.SetGroupVersionKind(desired.GroupVersionKind())
+			if err := h.Client.Get(ctx, clientpkg.ObjectKeyFromObject(desired), live); err != nil {
+				return err
+			}
+			desired.DeepCopyInto(obj)
+			obj.SetResourceVersion(live.GetResourceVersion())
+		}
+		return h.Client.Update(ctx, obj, updateOpts...)
+	})
+	if retries > 0 {
+		h.Log.Info("Retried update after resource version conflicts",
+			"kind", obj.GetKind(),
+			"name", obj.GetName(),
+			"namespace", obj.GetNamespace(),
+			"retries", retries,
+		)
+	}
+	return retries, err
+}
+
+// serverSideApply 使用服务端应用更新资源，字段冲突时返回结构化的冲突信息
+func (h *ResourceHandler) serverSideApply(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	obj *unstructured.Unstructured,
+	fieldManager string,
+	force bool,
+	dryRun bool,
+) (*mcp.CallToolResult, error) {
+	// 服务端应用不接受managedFields，由API Server负责维护
+	obj.SetManagedFields(nil)
+
+	patchOpts := []clientpkg.PatchOption{clientpkg.FieldOwner(fieldManager)}
+	if force {
+		patchOpts = append(patchOpts, clientpkg.ForceOwnership)
+	}
+	if dryRun {
+		patchOpts = append(patchOpts, clientpkg.DryRunAll)
+	}
+
+	err := h.Client.Patch(ctx, obj, clientpkg.Apply, patchOpts...)
+	if err != nil {
+		h.Log.Error("Failed to server-side apply resource",
+			"kind", obj.GetKind(),
+			"name", obj.GetName(),
+			"namespace", obj.GetNamespace(),
+			"fieldManager", fieldManager,
+			"force", force,
+			"error", err,
+		)
+		if conflicts := extractApplyConflicts(err); len(conflicts) > 0 {
+			response := models.ApplyConflictResponse{
+				Kind:         obj.GetKind(),
+				Name:         obj.GetName(),
+				Namespace:    obj.GetNamespace(),
+				FieldManager: fieldManager,
+				Conflicts:    conflicts,
+				Hint:         "Set force=true to take ownership of the conflicting fields, or remove them from the manifest to leave them to their current managers.",
+			}
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeConflict,
+				Message: fmt.Sprintf("server-side apply of %s/%s conflicts with fields owned by other managers", obj.GetKind(), obj.GetName()),
+				Reason:  string(errors.ReasonForError(err)),
+				Hint:    response.Hint,
+				Details: response,
+			}), nil
+		}
+		return utils.NewKubeErrorResult(err, "failed to apply resource"), nil
+	}
+
+	h.Client.Cache().InvalidateKind(obj.GetKind())
+	h.Log.Info("Resource applied successfully",
+		"kind", obj.GetKind(),
+		"name", obj.GetName(),
+		"namespace", obj.GetNamespace(),
+		"fieldManager", fieldManager,
+		"force", force,
+		"dryRun", dryRun,
 	)
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("Successfully updated %s/%s in namespace %s",
-					obj.GetKind(), obj.GetName(), obj.GetNamespace()),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, models.ResourceOperationResult{
+		Operation:    "apply",
+		Kind:         obj.GetKind(),
+		Name:         obj.GetName(),
+		Namespace:    obj.GetNamespace(),
+		FieldManager: fieldManager,
+		DryRun:       dryRun,
+		Message: fmt.Sprintf("Successfully applied %s/%s %s with field manager %s%s",
+			obj.GetKind(), obj.GetName(), describeLocation(obj.GetNamespace()), fieldManager, dryRunSuffix(dryRun)),
+	}), nil
+}
+
+// applyConflictManagerRegex 从冲突消息中提取字段管理器名称，例如：conflict with "kubectl" using apps/v1
+var applyConflictManagerRegex = regexp.MustCompile(`conflict with "([^"]+)"`)
+
+// extractApplyConflicts 从服务端应用的冲突错误中提取冲突字段及其当前管理器
+func extractApplyConflicts(err error) []models.ApplyConflict {
+	if !errors.IsConflict(err) {
+		return nil
+	}
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return nil
+	}
+
+	var conflicts []models.ApplyConflict
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflict := models.ApplyConflict{
+			Field:   cause.Field,
+			Message: cause.Message,
+		}
+		if matches := applyConflictManagerRegex.FindStringSubmatch(cause.Message); len(matches) > 1 {
+			conflict.Manager = matches[1]
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// setDefaultNamespace 为未指定命名空间的命名空间级资源设置默认命名空间，集群级资源保持为空。
+// 集群级资源在清单中指定了命名空间时返回错误结果
+func (h *ResourceHandler) setDefaultNamespace(obj *unstructured.Unstructured) *mcp.CallToolResult {
+	namespace, clusterScoped, refused := h.resolveScope(obj.GroupVersionKind(), obj.GetNamespace())
+	if refused != nil {
+		return refused
+	}
+	if clusterScoped {
+		h.Log.Debug("Cluster-scoped resource, leaving namespace empty", "kind", obj.GetKind())
+		return nil
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+		h.Log.Debug("Empty namespace in resource, setting namespace", "namespace", namespace)
+	}
+	return nil
+}
+
+// describeLocation 返回资源所在位置的文本描述
+func describeLocation(namespace string) string {
+	if namespace == "" {
+		return "(cluster-scoped)"
+	}
+	return fmt.Sprintf("in namespace %s", namespace)
+}
+
+// dryRunSuffix 返回试运行模式下追加到成功消息末尾的说明
+func dryRunSuffix(dryRun bool) string {
+	if dryRun {
+		return " (server dry-run, no changes persisted)"
+	}
+	return ""
 }
 
 // DeleteResource 实现通用的资源删除功能
@@ -556,8 +1020,14 @@ func (h *ResourceHandler) DeleteResource(
 	name, _ := arguments["name"].(string)
 	namespaceArg, _ := arguments["namespace"].(string)
 
-	// 获取命名空间，使用合适的默认值
-	namespace := h.GetNamespaceWithDefault(namespaceArg)
+	// 解析GroupVersionKind
+	gvk := utils.ParseGVK(apiVersion, kind)
+
+	// 获取命名空间，集群级资源不使用命名空间
+	namespace, _, refused := h.resolveScope(gvk, namespaceArg)
+	if refused != nil {
+		return refused, nil
+	}
 
 	h.Log.Info("Deleting resource",
 		"kind", kind,
@@ -567,15 +1037,17 @@ func (h *ResourceHandler) DeleteResource(
 		"group", h.Group,
 	)
 
-	// 解析GroupVersionKind
-	gvk := utils.ParseGVK(apiVersion, kind)
-
 	// 创建对象
 	obj := &unstructured.Unstructured{}
 	obj.SetGroupVersionKind(gvk)
 	obj.SetName(name)
 	obj.SetNamespace(namespace)
 
+	// 权限预检
+	if denied := h.PreflightCheckObject(ctx, "delete", obj); denied != nil {
+		return denied, nil
+	}
+
 	// 删除资源
 	err := h.Client.Delete(ctx, obj)
 	if err != nil {
@@ -585,47 +1057,43 @@ func (h *ResourceHandler) DeleteResource(
 			"namespace", namespace,
 			"error", err,
 		)
-		if errors.IsNotFound(err) {
-			return utils.NewErrorToolResult(fmt.Sprintf("resource not found (Kind: %s, Name: %s, Namespace: %s)", kind, name, namespace)), nil
-		}
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to delete resource: %v", err)), nil
+		return h.notFoundResult(ctx, err, gvk, namespace, name, fmt.Sprintf("failed to delete %s %s %s", kind, name, describeLocation(namespace))), nil
 	}
 
+	h.Client.Cache().InvalidateKind(kind)
 	h.Log.Info("Resource deleted successfully",
 		"kind", kind,
 		"name", name,
 		"namespace", namespace,
 	)
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("Successfully deleted %s/%s from namespace %s",
-					kind, name, namespace),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, models.ResourceOperationResult{
+		Operation: "delete",
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+		Message: fmt.Sprintf("Successfully deleted %s/%s %s",
+			kind, name, describeLocation(namespace)),
+	}), nil
 }
 
-// Handle 处理通用资源请求
+// Handle 处理通用资源请求，平铺名称和带本处理程序前缀的名称都能识别
 func (h *ResourceHandler) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	prefix := h.resourcePrefix
-	switch request.Method {
-	case fmt.Sprintf("LIST_%s_RESOURCES", prefix):
+	switch resourceToolOperation(request.Method, h.resourcePrefix) {
+	case OperationList:
 		return h.ListResources(ctx, request)
-	case fmt.Sprintf("GET_%s_RESOURCE", prefix):
+	case OperationGet:
 		return h.GetResource(ctx, request)
-	case fmt.Sprintf("DESCRIBE_%s_RESOURCE", prefix):
+	case OperationDescribe:
 		return h.DescribeResource(ctx, request)
-	case fmt.Sprintf("CREATE_%s_RESOURCE", prefix):
+	case OperationCreate:
 		return h.CreateResource(ctx, request)
-	case fmt.Sprintf("UPDATE_%s_RESOURCE", prefix):
+	case OperationUpdate:
 		return h.UpdateResource(ctx, request)
-	case fmt.Sprintf("DELETE_%s_RESOURCE", prefix):
+	case OperationDelete:
 		return h.DeleteResource(ctx, request)
 	default:
-		return utils.NewErrorToolResult(fmt.Sprintf("unknown %s resource method: %s", strings.ToLower(prefix), request.Method)), nil
+		return utils.NewErrorToolResult(fmt.Sprintf("unknown %s resource method: %s", strings.ToLower(h.resourcePrefix), request.Method)), nil
 	}
 }
 
@@ -633,3 +1101,12 @@ func (h *ResourceHandler) Handle(ctx context.Context, request mcp.CallToolReques
 func (h *ResourceHandler) GetResourcePrefix() string {
 	return h.resourcePrefix
 }
+
+// summarizedObjectRefused 拒绝写入包含摘要占位值的对象
+func summarizedObjectRefused(obj *unstructured.Unstructured) *mcp.CallToolResult {
+	return utils.NewToolErrorResult(models.ToolError{
+		Code:    utils.ErrorCodeRefused,
+		Message: fmt.Sprintf("%s %s contains summarized placeholder values (%s), not the real data", obj.GetKind(), obj.GetName(), obj.GetAnnotations()[utils.SummarizedKeysAnnotation]),
+		Hint:    fmt.Sprintf("Fetch the object again with those keys in fullKeys, or edit only the keys you need; remove the %s annotation once every placeholder is replaced.", utils.SummarizedKeysAnnotation),
+	})
+}
@@ -0,0 +1,426 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/middlewares"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+func newCoreResourceHandler(client *testutil.FakeClient) *ResourceHandler {
+	return NewResourceHandlerPtr(NewHandler(client, interfaces.NamespaceScope, interfaces.CoreAPIGroup), "CORE")
+}
+
+func callResourceTool(t *testing.T, h *ResourceHandler, operation string, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	tool := ResourceToolName(operation, "")
+	result, err := h.Handle(context.Background(), testutil.NewToolRequest(tool, arguments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func labeledPod(name, namespace string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+}
+
+func TestResourceHandlerCRUD(t *testing.T) {
+	h := newCoreResourceHandler(testutil.NewFakeClient())
+	const manifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast`
+	get := func() (map[string]any, *mcp.CallToolResult) {
+		result := callResourceTool(t, h, OperationGet, map[string]any{"kind": "ConfigMap", "apiVersion": "v1", "name": "settings"})
+		var object map[string]any
+		if !result.IsError {
+			if err := testutil.DecodeResult(result, &object); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return object, result
+	}
+
+	var created models.ResourceOperationResult
+	if err := testutil.DecodeResult(callResourceTool(t, h, OperationCreate, map[string]any{"yaml": manifest}), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Namespace != testutil.DefaultNamespace {
+		t.Fatalf("created in namespace %q, want the default namespace", created.Namespace)
+	}
+	object, _ := get()
+	if mode := object["data"].(map[string]any)["mode"]; mode != "fast" {
+		t.Fatalf("mode after create = %v", mode)
+	}
+
+	updated := manifest[:len(manifest)-len("fast")] + "slow"
+	if result := callResourceTool(t, h, OperationUpdate, map[string]any{"yaml": updated, "dryRun": true}); result.IsError {
+		t.Fatalf("dry-run update failed: %s", testutil.ResultText(result))
+	}
+	if object, _ = get(); object["data"].(map[string]any)["mode"] != "fast" {
+		t.Fatal("dry-run update changed the object")
+	}
+	if result := callResourceTool(t, h, OperationUpdate, map[string]any{"yaml": updated}); result.IsError {
+		t.Fatalf("update failed: %s", testutil.ResultText(result))
+	}
+	if object, _ = get(); object["data"].(map[string]any)["mode"] != "slow" {
+		t.Fatalf("mode after update = %v", object["data"])
+	}
+
+	if result := callResourceTool(t, h, OperationDelete, map[string]any{"kind": "ConfigMap", "apiVersion": "v1", "name": "settings"}); result.IsError {
+		t.Fatalf("delete failed: %s", testutil.ResultText(result))
+	}
+	if _, result := get(); !result.IsError {
+		t.Fatal("object still exists after delete")
+	}
+}
+
+func TestResourceHandlerRefusesNamespaceForClusterScoped(t *testing.T) {
+	h := newCoreResourceHandler(testutil.NewFakeClient(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}))
+
+	result := callResourceTool(t, h, OperationGet, map[string]any{"kind": "Node", "apiVersion": "v1", "name": "node-1", "namespace": "default"})
+	toolErr, err := testutil.DecodeToolError(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Hint == "" {
+		t.Fatalf("refusal has no hint: %+v", toolErr)
+	}
+	if result := callResourceTool(t, h, OperationGet, map[string]any{"kind": "Node", "apiVersion": "v1", "name": "node-1"}); result.IsError {
+		t.Fatalf("get without namespace failed: %s", testutil.ResultText(result))
+	}
+}
+
+func TestListResourcesSelectors(t *testing.T) {
+	client := testutil.NewFakeClient(
+		labeledPod("web-1", "default", map[string]string{"app": "web", "tier": "frontend"}),
+		labeledPod("web-2", "default", map[string]string{"app": "web", "tier": "backend"}),
+		labeledPod("db-1", "default", map[string]string{"app": "db"}),
+		labeledPod("web-3", "other", map[string]string{"app": "web"}),
+	)
+	h := newCoreResourceHandler(client)
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		want      []string
+		invalid   bool
+	}{
+		{name: "namespace", want: []string{"db-1", "web-1", "web-2"}},
+		{name: "label selector", arguments: map[string]any{"labelSelector": "app=web"}, want: []string{"web-1", "web-2"}},
+		{name: "set-based label selector", arguments: map[string]any{"labelSelector": "tier in (backend)"}, want: []string{"web-2"}},
+		{name: "field selector", arguments: map[string]any{"fieldSelector": "metadata.name=db-1"}, want: []string{"db-1"}},
+		{name: "both selectors", arguments: map[string]any{"labelSelector": "app=web", "fieldSelector": "metadata.name=web-2"}, want: []string{"web-2"}},
+		{name: "all namespaces", arguments: map[string]any{"labelSelector": "app=web", "allNamespaces": true}, want: []string{"web-1", "web-2", "web-3"}},
+		{name: "invalid label selector", arguments: map[string]any{"labelSelector": "app in (web"}, invalid: true},
+		{name: "namespace with all namespaces", arguments: map[string]any{"namespace": "other", "allNamespaces": true}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]any{"kind": "Pod", "apiVersion": "v1"}
+			for key, value := range tt.arguments {
+				arguments[key] = value
+			}
+			result := callResourceTool(t, h, OperationList, arguments)
+			if tt.invalid {
+				if !result.IsError {
+					t.Fatalf("invalid arguments were accepted: %s", testutil.ResultText(result))
+				}
+				return
+			}
+			var response models.ResourceListResponse
+			if err := testutil.DecodeResult(result, &response); err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(response.Resources))
+			for _, resource := range response.Resources {
+				names = append(names, resource.Name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.want) || response.Count != len(tt.want) {
+				t.Fatalf("listed %v (count %d), want %v", names, response.Count, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAllNamespacesUnderAllowlist(t *testing.T) {
+	client := testutil.NewFakeClient(
+		labeledPod("web-1", "team-a", map[string]string{"app": "web"}),
+		labeledPod("web-2", "prod", map[string]string{"app": "web"}),
+	)
+	h := newCoreResourceHandler(client)
+	listed := 0
+	handler := middlewares.AccessPolicy()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		listed++
+		return h.Handle(ctx, request)
+	})
+
+	cfg := config.NewDefaultConfig()
+	cfg.AllowedNamespaces = "team-a"
+	config.Store(cfg)
+	t.Cleanup(func() { config.Store(config.NewDefaultConfig()) })
+
+	call := func(arguments map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		arguments["kind"], arguments["apiVersion"] = "Pod", "v1"
+		result, err := handler(context.Background(), testutil.NewToolRequest(ResourceToolName(OperationList, ""), arguments))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	toolErr, err := testutil.DecodeToolError(call(map[string]any{"allNamespaces": true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Code != utils.ErrorCodeRefused || !strings.Contains(toolErr.Hint, "team-a") {
+		t.Fatalf("allNamespaces=true under an allowlist: %+v", toolErr)
+	}
+	if listed != 0 {
+		t.Fatal("refused call reached the LIST handler")
+	}
+
+	var response models.ResourceListResponse
+	if err := testutil.DecodeResult(call(map[string]any{"namespace": "team-a"}), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Count != 1 || response.Resources[0].Name != "web-1" {
+		t.Fatalf("listed %+v in the allowed namespace, want only web-1", response.Resources)
+	}
+	if listed != 1 {
+		t.Fatalf("allowed call reached the LIST handler %d times, want 1", listed)
+	}
+}
+
+func TestUpdateResourceServerSideApplyConflict(t *testing.T) {
+	h := newCoreResourceHandler(testutil.NewFakeClient())
+	const manifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: %s`
+	apply := func(mode, fieldManager string, force bool) *mcp.CallToolResult {
+		return callResourceTool(t, h, OperationUpdate, map[string]any{
+			"yaml":         fmt.Sprintf(manifest, mode),
+			"applyMode":    ApplyModeServerSideApply,
+			"fieldManager": fieldManager,
+			"force":        force,
+		})
+	}
+
+	if result := apply("fast", "kubectl", false); result.IsError {
+		t.Fatalf("initial apply failed: %s", testutil.ResultText(result))
+	}
+	if result := apply("fast", DefaultFieldManager, false); result.IsError {
+		t.Fatalf("applying the same value must not conflict: %s", testutil.ResultText(result))
+	}
+
+	toolErr, err := testutil.DecodeToolError(apply("slow", DefaultFieldManager, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Code != utils.ErrorCodeConflict || toolErr.Reason != string(metav1.StatusReasonConflict) {
+		t.Fatalf("error = %+v, want a conflict", toolErr)
+	}
+	details, err := json.Marshal(toolErr.Details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conflict models.ApplyConflictResponse
+	if err := json.Unmarshal(details, &conflict); err != nil {
+		t.Fatal(err)
+	}
+	if len(conflict.Conflicts) != 1 || conflict.Conflicts[0].Manager != "kubectl" || conflict.Conflicts[0].Field != ".data.mode" {
+		t.Fatalf("conflicts = %+v, want .data.mode owned by kubectl", conflict.Conflicts)
+	}
+
+	if result := apply("slow", DefaultFieldManager, true); result.IsError {
+		t.Fatalf("forced apply failed: %s", testutil.ResultText(result))
+	}
+}
+
+func TestGetResourceRedactsSecrets(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   testutil.DefaultNamespace,
+			Annotations: map[string]string{utils.LastAppliedConfigAnnotation: `{"stringData":{"password":"hunter2"}}`},
+		},
+		Data: map[string][]byte{"password": []byte("hunter2")},
+	}
+	h := newCoreResourceHandler(testutil.NewFakeClient(secret))
+
+	for _, operation := range []string{OperationGet, OperationDescribe} {
+		result := callResourceTool(t, h, operation, map[string]any{"kind": "Secret", "apiVersion": "v1", "name": "db"})
+		text := testutil.ResultText(result)
+		if result.IsError || strings.Contains(text, "hunter2") || strings.Contains(text, "aHVudGVyMg") {
+			t.Fatalf("%s returned the secret value: %s", operation, text)
+		}
+	}
+}
+
+func TestGetMissingResourceReturnsToolError(t *testing.T) {
+	h := newCoreResourceHandler(testutil.NewFakeClient(labeledPod("web", testutil.DefaultNamespace, nil)))
+
+	toolErr, err := testutil.DecodeToolError(callResourceTool(t, h, OperationGet, map[string]any{"kind": "Pod", "apiVersion": "v1", "name": "db"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Code != utils.ErrorCodeNotFound || toolErr.Reason != string(metav1.StatusReasonNotFound) || toolErr.Hint == "" {
+		t.Fatalf("error = %+v, want NotFound with a hint", toolErr)
+	}
+}
+
+func TestListResourcesFieldSelectorWithLabelSelector(t *testing.T) {
+	pod := func(name, app string, phase corev1.PodPhase) *corev1.Pod {
+		p := labeledPod(name, testutil.DefaultNamespace, map[string]string{"app": app})
+		p.Status.Phase = phase
+		return p
+	}
+	h := newCoreResourceHandler(testutil.NewFakeClient(
+		pod("web-1", "web", corev1.PodRunning),
+		pod("web-2", "web", corev1.PodPending),
+		pod("db-1", "db", corev1.PodRunning),
+	))
+
+	result := callResourceTool(t, h, OperationList, map[string]any{
+		"kind":          "Pod",
+		"apiVersion":    "v1",
+		"labelSelector": "app=web",
+		"fieldSelector": "status.phase=Running",
+	})
+	var response models.ResourceListResponse
+	if err := testutil.DecodeResult(result, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Count != 1 || response.Resources[0].Name != "web-1" {
+		t.Fatalf("listed %+v, want only web-1", response.Resources)
+	}
+	if response.FieldSelector != "status.phase=Running" || response.LabelSelector != "app=web" {
+		t.Fatalf("selectors in response = %q, %q", response.LabelSelector, response.FieldSelector)
+	}
+
+	result = callResourceTool(t, h, OperationList, map[string]any{"kind": "Pod", "apiVersion": "v1", "fieldSelector": "status.phase"})
+	if !result.IsError {
+		t.Fatalf("malformed field selector was accepted: %s", testutil.ResultText(result))
+	}
+}
+
+func TestClusterScopedRoundTrip(t *testing.T) {
+	h := newCoreResourceHandler(testutil.NewFakeClient(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "general"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"pool": "gpu"}}},
+	))
+	const clusterRole = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: pod-reader
+rules:
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: [%s]`
+	roleArguments := map[string]any{"kind": "ClusterRole", "apiVersion": "rbac.authorization.k8s.io/v1", "name": "pod-reader"}
+	verbs := func() []any {
+		var role map[string]any
+		if err := testutil.DecodeResult(callResourceTool(t, h, OperationGet, roleArguments), &role); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := role["metadata"].(map[string]any)["namespace"]; ok {
+			t.Fatalf("cluster-scoped object has a namespace: %v", role["metadata"])
+		}
+		return role["rules"].([]any)[0].(map[string]any)["verbs"].([]any)
+	}
+
+	var created models.ResourceOperationResult
+	if err := testutil.DecodeResult(callResourceTool(t, h, OperationCreate, map[string]any{"yaml": fmt.Sprintf(clusterRole, `"get"`)}), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Namespace != "" {
+		t.Fatalf("ClusterRole created in namespace %q", created.Namespace)
+	}
+	if got := verbs(); len(got) != 1 {
+		t.Fatalf("verbs after create = %v", got)
+	}
+	if result := callResourceTool(t, h, OperationUpdate, map[string]any{"yaml": fmt.Sprintf(clusterRole, `"get", "list"`)}); result.IsError {
+		t.Fatalf("update failed: %s", testutil.ResultText(result))
+	}
+	if got := verbs(); len(got) != 2 {
+		t.Fatalf("verbs after update = %v", got)
+	}
+
+	var list models.ResourceListResponse
+	if err := testutil.DecodeResult(callResourceTool(t, h, OperationList, map[string]any{"kind": "Node", "apiVersion": "v1", "labelSelector": "pool=gpu"}), &list); err != nil {
+		t.Fatal(err)
+	}
+	if !list.ClusterScoped || list.Namespace != "" || list.Count != 1 || list.Resources[0].Name != "node-2" {
+		t.Fatalf("node list = %+v, want node-2 without a namespace", list)
+	}
+	if result := callResourceTool(t, h, OperationDescribe, map[string]any{"kind": "Node", "apiVersion": "v1", "name": "node-1"}); result.IsError {
+		t.Fatalf("describe node failed: %s", testutil.ResultText(result))
+	}
+
+	namespaced := strings.Replace(fmt.Sprintf(clusterRole, `"get"`), "name: pod-reader", "name: pod-reader\n  namespace: default", 1)
+	toolErr, err := testutil.DecodeToolError(callResourceTool(t, h, OperationUpdate, map[string]any{"yaml": namespaced}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Code != utils.ErrorCodeInvalid {
+		t.Fatalf("error = %+v, want metadata.namespace on a cluster-scoped kind to be invalid", toolErr)
+	}
+
+	if result := callResourceTool(t, h, OperationDelete, roleArguments); result.IsError {
+		t.Fatalf("delete failed: %s", testutil.ResultText(result))
+	}
+	if result := callResourceTool(t, h, OperationGet, roleArguments); !result.IsError {
+		t.Fatal("ClusterRole still exists after delete")
+	}
+}
+
+func TestGetMissingResourceSuggestsSimilarNames(t *testing.T) {
+	h := newCoreResourceHandler(testutil.NewFakeClient(
+		labeledPod("api-7f9c8d5b6-x2k4q", testutil.DefaultNamespace, nil),
+		labeledPod("nginx", testutil.DefaultNamespace, nil),
+		labeledPod("nginx", "other", nil),
+	))
+
+	result := callResourceTool(t, h, OperationGet, map[string]any{"kind": "Pod", "apiVersion": "v1", "name": "ngnix"})
+	toolErr, err := testutil.DecodeToolError(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	details, err := json.Marshal(toolErr.Details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suggestions models.NameSuggestionDetails
+	if err := json.Unmarshal(details, &suggestions); err != nil {
+		t.Fatal(err)
+	}
+	if toolErr.Code != utils.ErrorCodeNotFound || !slices.Equal(suggestions.Suggestions, []string{"nginx"}) {
+		t.Fatalf("error = %+v, want nginx from the same namespace suggested", toolErr)
+	}
+	if !strings.Contains(toolErr.Message, "did you mean: nginx") {
+		t.Fatalf("message = %q", toolErr.Message)
+	}
+}
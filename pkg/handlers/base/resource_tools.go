@@ -0,0 +1,154 @@
+package base
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 通用资源工具的命名方式
+const (
+	// ToolNamingFlat 只注册一组不带前缀的工具，例如LIST_RESOURCES，由kind和apiVersion决定API组，为默认方式
+	ToolNamingFlat = "flat"
+	// ToolNamingPrefixed 每个API组的处理程序各注册一组带前缀的工具，例如LIST_CORE_RESOURCES、LIST_APPS_RESOURCES
+	ToolNamingPrefixed = "prefixed"
+)
+
+// 通用资源工具的操作
+const (
+	OperationList     = "LIST"
+	OperationGet      = "GET"
+	OperationDescribe = "DESCRIBE"
+	OperationCreate   = "CREATE"
+	OperationUpdate   = "UPDATE"
+	OperationDelete   = "DELETE"
+)
+
+// resourceOperations 通用资源工具的操作，按注册顺序排列
+var resourceOperations = []string{
+	OperationList,
+	OperationGet,
+	OperationDescribe,
+	OperationCreate,
+	OperationUpdate,
+	OperationDelete,
+}
+
+// flatToolOwner 平铺命名时注册通用资源工具的处理程序所属的API组，其他API组只注册旧工具名的别名
+const flatToolOwner = interfaces.CoreAPIGroup
+
+// ParseToolNaming 校验工具命名方式，为空时使用ToolNamingFlat；无法识别时返回ToolNamingFlat和错误
+func ParseToolNaming(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", ToolNamingFlat:
+		return ToolNamingFlat, nil
+	case ToolNamingPrefixed:
+		return ToolNamingPrefixed, nil
+	}
+	return ToolNamingFlat, fmt.Errorf("unsupported tool naming %q, must be %s or %s", value, ToolNamingFlat, ToolNamingPrefixed)
+}
+
+// FlatToolNaming 判断通用资源工具是否使用平铺名称，只有显式配置ToolNamingPrefixed时才按API组命名
+func FlatToolNaming() bool {
+	return GetOptions().ToolNaming != ToolNamingPrefixed
+}
+
+// ResourceToolName 返回通用资源工具的名称，prefix为空时为平铺名称（LIST_RESOURCES、GET_RESOURCE），
+// 否则为带API组前缀的名称（LIST_APPS_RESOURCES、GET_APPS_RESOURCE）
+func ResourceToolName(operation, prefix string) string {
+	suffix := "RESOURCE"
+	if operation == OperationList {
+		suffix = "RESOURCES"
+	}
+	if prefix == "" {
+		return operation + "_" + suffix
+	}
+	return operation + "_" + prefix + "_" + suffix
+}
+
+// resourceToolOperation 返回工具名对应的通用资源工具操作，平铺名称和带prefix前缀的名称都能识别，不是通用资源工具时返回空
+func resourceToolOperation(name, prefix string) string {
+	for _, operation := range resourceOperations {
+		if name == ResourceToolName(operation, prefix) || name == ResourceToolName(operation, "") {
+			return operation
+		}
+	}
+	return ""
+}
+
+// resourceTool 一个通用资源工具的定义，注册时再决定名称
+type resourceTool struct {
+	operation string
+	options   []mcp.ToolOption
+	handler   server.ToolHandlerFunc
+}
+
+// registerResourceTools 按命名方式注册通用资源工具
+// flat时只由flatToolOwner注册平铺名称，启用了旧工具名别名（--legacy-tool-aliases）时各处理程序再以原来的前缀名称注册已弃用的别名，
+// 别名与平铺工具使用相同的实现；prefixed时以本处理程序的前缀注册
+func (h *ResourceHandler) registerResourceTools(registrar interfaces.ToolRegistrar, tools []resourceTool) {
+	if !FlatToolNaming() {
+		for _, tool := range tools {
+			registrar.AddTool(mcp.NewTool(ResourceToolName(tool.operation, h.resourcePrefix), tool.options...), tool.handler)
+		}
+		return
+	}
+
+	for _, tool := range tools {
+		flatName := ResourceToolName(tool.operation, "")
+		if h.Group == flatToolOwner {
+			registrar.AddTool(mcp.NewTool(flatName, tool.options...), tool.handler)
+		}
+		if GetOptions().LegacyToolAliases {
+			aliasOptions := append(slices.Clone(tool.options),
+				mcp.WithDescription(fmt.Sprintf("已弃用的别名，与%s完全相同，保留用于兼容按API组命名的旧工具名，将在后续版本移除。请改用%s。", flatName, flatName)))
+			registrar.AddTool(mcp.NewTool(ResourceToolName(tool.operation, h.resourcePrefix), aliasOptions...), tool.handler)
+		}
+	}
+}
+
+// toolScope 返回工具描述中的作用域说明
+func (h *ResourceHandler) toolScope() string {
+	if FlatToolNaming() {
+		return "任意API组中的资源，API组由apiVersion决定"
+	}
+	return fmt.Sprintf("指定API组中的资源（作用域：%s）", h.Scope)
+}
+
+// resolveListAPIVersion 未指定apiVersion时按kind解析首选版本
+// 通过带前缀的工具调用时只在本处理程序的API组中查找，通过平铺工具调用时在所有API组中查找，匹配多个API组时要求指定apiVersion
+func (h *ResourceHandler) resolveListAPIVersion(toolName, kind string) (string, error) {
+	if kind == "" {
+		return "", fmt.Errorf("missing required parameter: kind")
+	}
+	mappings, err := utils.ResolveKind(h.Client, kind)
+	if err != nil {
+		return "", err
+	}
+	restrictGroup := toolName == ResourceToolName(OperationList, h.resourcePrefix)
+	group := string(h.Group)
+	if h.Group == interfaces.CoreAPIGroup {
+		group = ""
+	}
+
+	var candidates []string
+	for _, mapping := range mappings {
+		if restrictGroup && mapping.GroupVersionKind.Group != group {
+			continue
+		}
+		candidates = append(candidates, mapping.GroupVersionKind.GroupVersion().String())
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("kind %s is not served in API group %s, specify apiVersion", kind, h.Group)
+	case 1:
+		return candidates[0], nil
+	}
+	return "", fmt.Errorf("kind %s is served by several API groups (%s), specify apiVersion", kind, strings.Join(candidates, ", "))
+}
@@ -0,0 +1,96 @@
+package base
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+// recordingRegistrar 记录注册的工具
+type recordingRegistrar struct {
+	tools map[string]mcp.Tool
+}
+
+func (r *recordingRegistrar) AddTool(tool mcp.Tool, _ server.ToolHandlerFunc) {
+	r.tools[tool.Name] = tool
+}
+
+func (r *recordingRegistrar) AddPrompt(mcp.Prompt, server.PromptHandlerFunc) {}
+
+func (r *recordingRegistrar) AddResourceTemplate(mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc) {
+}
+
+func (r *recordingRegistrar) names() []string {
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestRegisteredResourceToolNames(t *testing.T) {
+	flat := []string{"CREATE_RESOURCE", "DELETE_RESOURCE", "DESCRIBE_RESOURCE", "GET_RESOURCE", "LIST_RESOURCES", "UPDATE_RESOURCE"}
+	legacy := []string{
+		"CREATE_APPS_RESOURCE", "CREATE_CORE_RESOURCE",
+		"DELETE_APPS_RESOURCE", "DELETE_CORE_RESOURCE",
+		"DESCRIBE_APPS_RESOURCE", "DESCRIBE_CORE_RESOURCE",
+		"GET_APPS_RESOURCE", "GET_CORE_RESOURCE",
+		"LIST_APPS_RESOURCES", "LIST_CORE_RESOURCES",
+		"UPDATE_APPS_RESOURCE", "UPDATE_CORE_RESOURCE",
+	}
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{name: "default", options: Options{}, want: flat},
+		{name: "flat", options: Options{ToolNaming: ToolNamingFlat}, want: flat},
+		{name: "flat with legacy aliases", options: Options{ToolNaming: ToolNamingFlat, LegacyToolAliases: true}, want: append(slices.Clone(flat), legacy...)},
+		{name: "prefixed", options: Options{ToolNaming: ToolNamingPrefixed}, want: legacy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := GetOptions()
+			SetOptions(tt.options)
+			t.Cleanup(func() { SetOptions(previous) })
+
+			client := testutil.NewFakeClient()
+			registrar := &recordingRegistrar{tools: map[string]mcp.Tool{}}
+			NewResourceHandlerPtr(NewHandler(client, interfaces.NamespaceScope, interfaces.CoreAPIGroup), "CORE").Register(registrar)
+			NewResourceHandlerPtr(NewHandler(client, interfaces.NamespaceScope, interfaces.AppsAPIGroup), "APPS").Register(registrar)
+
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if got := registrar.names(); !slices.Equal(got, want) {
+				t.Fatalf("registered tools = %v, want %v", got, want)
+			}
+			if !tt.options.LegacyToolAliases || !FlatToolNaming() {
+				return
+			}
+			for _, name := range legacy {
+				if !strings.Contains(registrar.tools[name].Description, "已弃用") {
+					t.Errorf("alias %s is not described as deprecated", name)
+				}
+			}
+		})
+	}
+}
+
+func TestParseToolNaming(t *testing.T) {
+	for value, want := range map[string]string{"": ToolNamingFlat, "flat": ToolNamingFlat, " Prefixed ": ToolNamingPrefixed} {
+		if got, err := ParseToolNaming(value); err != nil || got != want {
+			t.Errorf("ParseToolNaming(%q) = %s, %v, want %s", value, got, err, want)
+		}
+	}
+	if got, err := ParseToolNaming("grouped"); err == nil || got != ToolNamingFlat {
+		t.Errorf("ParseToolNaming(grouped) = %s, %v, want flat and an error", got, err)
+	}
+}
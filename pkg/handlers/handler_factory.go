@@ -14,6 +14,7 @@ import (
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
 	metricshandler "github.com/hsn0918/kubernetes-mcp/pkg/handlers/metrics"
 	prompthandler "github.com/hsn0918/kubernetes-mcp/pkg/handlers/prompt"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/resourceprovider"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/tool"
 )
 
@@ -101,3 +102,8 @@ func (f *HandlerFactoryImpl) CreatePromptHandler() interfaces.ToolHandler {
 func (f *HandlerFactoryImpl) CreateMetricsHandler() interfaces.ToolHandler {
 	return metricshandler.NewMetricsHandler(f.client)
 }
+
+// CreateResourceProviderHandler 创建MCP资源处理程序
+func (f *HandlerFactoryImpl) CreateResourceProviderHandler() interfaces.ToolHandler {
+	return resourceprovider.NewResourceProviderHandler(f.client)
+}
@@ -44,6 +44,8 @@ const (
 	Prompt  APIGroup = "prompt"
 	Metrics APIGroup = "metrics"
 	Tool    APIGroup = "tool"
+	// MCPResource 以MCP资源形式公开集群数据的处理程序
+	MCPResource APIGroup = "resource"
 )
 
 // ToolHandler 定义MCP工具处理接口
@@ -52,7 +54,7 @@ type ToolHandler interface {
 	Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
 
 	// Register 注册工具到MCP服务器
-	Register(server *server.MCPServer)
+	Register(server ToolRegistrar)
 
 	// GetScope 返回处理程序的作用域（集群或命名空间）
 	GetScope() ResourceScope
@@ -61,6 +63,25 @@ type ToolHandler interface {
 	GetAPIGroup() APIGroup
 }
 
+// ToolRegistrar 处理程序注册工具、提示词和资源模板的目标，*server.MCPServer实现了该接口
+// 处理程序提供者传入按配置过滤工具的包装，使单个工具也可以被禁用
+type ToolRegistrar interface {
+	// AddTool 注册工具
+	AddTool(tool mcp.Tool, handler server.ToolHandlerFunc)
+	// AddPrompt 注册提示词
+	AddPrompt(prompt mcp.Prompt, handler server.PromptHandlerFunc)
+	// AddResourceTemplate 注册资源模板
+	AddResourceTemplate(template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc)
+}
+
+var _ ToolRegistrar = (*server.MCPServer)(nil)
+
+// HookRegistrar 需要在服务器创建前注册钩子的处理程序
+type HookRegistrar interface {
+	// RegisterHooks 注册MCP服务器钩子
+	RegisterHooks(hooks *server.Hooks)
+}
+
 // ResourceHandler 定义资源处理接口
 type ResourceHandler interface {
 	ToolHandler
@@ -99,6 +120,9 @@ type HandlerProvider interface {
 
 	// RegisterAllHandlers 注册所有处理程序
 	RegisterAllHandlers(server *server.MCPServer)
+
+	// RegisterAllHooks 注册实现了HookRegistrar的处理程序的钩子，需在创建服务器前调用
+	RegisterAllHooks(hooks *server.Hooks)
 }
 
 // HandlerFactory 提供创建各种资源处理程序的工厂方法
@@ -144,6 +168,9 @@ type HandlerFactory interface {
 
 	// CreateMetricsHandler 创建指标处理程序
 	CreateMetricsHandler() ToolHandler
+
+	// CreateResourceProviderHandler 创建MCP资源处理程序
+	CreateResourceProviderHandler() ToolHandler
 }
 
 // BaseResourceHandler 定义资源处理器的基础实现
@@ -12,7 +12,6 @@ import (
 	"github.com/hsn0918/kubernetes-mcp/pkg/models"
 	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // Define metrics related tool constants
@@ -21,6 +20,7 @@ const (
 	GET_POD_METRICS      = "GET_POD_METRICS"
 	GET_RESOURCE_METRICS = "GET_RESOURCE_METRICS"
 	GET_TOP_CONSUMERS    = "GET_TOP_CONSUMERS"
+	RECOMMEND_RESOURCES  = "RECOMMEND_RESOURCES"
 )
 
 // MetricsHandler handles Kubernetes metrics related functions
@@ -51,13 +51,15 @@ func (h *MetricsHandler) Handle(ctx context.Context, request mcp.CallToolRequest
 		return h.GetResourceMetrics(ctx, request)
 	case GET_TOP_CONSUMERS:
 		return h.GetTopConsumers(ctx, request)
+	case RECOMMEND_RESOURCES:
+		return h.RecommendResources(ctx, request)
 	default:
 		return utils.NewErrorToolResult(fmt.Sprintf("unknown metrics method: %s", request.Method)), nil
 	}
 }
 
 // Register registers metric tools to the MCP server
-func (h *MetricsHandler) Register(server *server.MCPServer) {
+func (h *MetricsHandler) Register(server interfaces.ToolRegistrar) {
 	h.Log.Info("Registering metrics handlers")
 	// Register node metrics tool
 	server.AddTool(mcp.NewTool(GET_NODE_METRICS,
@@ -75,6 +77,10 @@ func (h *MetricsHandler) Register(server *server.MCPServer) {
 		mcp.WithString("labelSelector",
 			mcp.Description("Kubernetes标签选择器，用于按节点标签进行过滤。例如：'kubernetes.io/role=master'。支持多个标签，使用逗号分隔。"),
 		),
+		withUnitType(),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetNodeMetrics)
 
 	// Register pod metrics tool
@@ -100,11 +106,15 @@ func (h *MetricsHandler) Register(server *server.MCPServer) {
 		mcp.WithString("labelSelector",
 			mcp.Description("Kubernetes标签选择器，用于按Pod标签进行过滤。例如：'app=nginx,tier=frontend'。用于监控特定应用或组件的资源使用情况。"),
 		),
+		withUnitType(),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetPodMetrics)
 
 	// Register resource metrics tool
 	server.AddTool(mcp.NewTool(GET_RESOURCE_METRICS,
-		mcp.WithDescription("获取Kubernetes集群整体资源使用情况。提供集群级别的CPU、内存、存储和Pod数量统计，支持按命名空间和标签过滤。适用于集群容量规划、资源使用趋势分析、成本优化等场景。帮助了解资源使用效率和分布情况。"),
+		mcp.WithDescription("获取Kubernetes集群整体资源使用情况。提供集群级别的CPU、内存、存储和Pod数量统计，支持按命名空间和标签过滤。适用于集群容量规划、资源使用趋势分析、成本优化等场景。帮助了解资源使用效率和分布情况。CPU和内存同时返回可分配量、Pod规格中的请求和限制之和以及实际用量，便于对比“可分配-已请求-已使用”。指定命名空间且resource为pods时不查询节点数据，只返回该命名空间的Pod数量。"),
 		mcp.WithString("resource",
 			mcp.Description("资源类型，支持以下选项：\n- cpu：CPU使用情况\n- memory：内存使用情况\n- storage：存储使用情况\n- pods：Pod数量统计\n选择要分析的具体资源类型。"),
 			mcp.Required(),
@@ -118,6 +128,9 @@ func (h *MetricsHandler) Register(server *server.MCPServer) {
 		mcp.WithString("labelSelector",
 			mcp.Description("Kubernetes标签选择器，用于按资源标签进行过滤。例如：'app=nginx,tier=frontend'。用于分析特定应用或组件的资源使用情况。"),
 		),
+		withUnitType(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetResourceMetrics)
 
 	// Register top consumers tool
@@ -140,8 +153,64 @@ func (h *MetricsHandler) Register(server *server.MCPServer) {
 		mcp.WithString("labelSelector",
 			mcp.Description("Kubernetes标签选择器，用于按Pod标签进行过滤。例如：'app=nginx,tier=frontend'。用于分析特定应用或组件的资源消耗情况。"),
 		),
+		withUnitType(),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetTopConsumers)
 
+	// Register resource recommendation tool
+	server.AddTool(mcp.NewTool(RECOMMEND_RESOURCES,
+		mcp.WithDescription("根据实际用量为工作负载推荐容器的requests和limits。在采样窗口内多次采集Pod指标，按容器计算CPU和内存的p50/p95，以p95乘以余量系数得到requests，再按倍数得到limits，并给出下限保护。返回每个容器的计算说明、可直接应用的strategic merge patch和kubectl命令，以及对命名空间ResourceQuota用量的影响。结果只是采样窗口内的时间点估计，无法反映日/周峰值，应用前请结合长期监控确认。需要安装metrics-server。"),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型"),
+			mcp.Required(),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet"),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("工作负载所在的命名空间"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithNumber("samples",
+			mcp.Description("采样次数，均匀分布在采样窗口内，最大20。metrics-server约每15秒更新一次，重复的数据点会被去重"),
+			mcp.DefaultNumber(defaultRecommendSamples),
+		),
+		mcp.WithNumber("maxDurationSeconds",
+			mcp.Description("采样窗口（秒），最大300。工具超时至少为该时长；为了在超时前完成，窗口会缩短以留出约10秒余量，需要完整窗口时将timeoutSeconds设为比它多10秒以上"),
+			mcp.DefaultNumber(defaultRecommendWindow),
+		),
+		mcp.WithNumber("cpuRequestHeadroom",
+			mcp.Description("CPU request相对p95用量的余量倍数，不小于1"),
+			mcp.DefaultNumber(defaultCPURequestHeadroom),
+		),
+		mcp.WithNumber("memoryRequestHeadroom",
+			mcp.Description("内存request相对p95用量的余量倍数，不小于1"),
+			mcp.DefaultNumber(defaultMemoryRequestHeadroom),
+		),
+		mcp.WithNumber("cpuLimitFactor",
+			mcp.Description("CPU limit相对request的倍数，0表示不建议设置CPU limit"),
+			mcp.DefaultNumber(defaultCPULimitFactor),
+		),
+		mcp.WithNumber("memoryLimitFactor",
+			mcp.Description("内存limit相对request的倍数，0表示不建议设置内存limit。内存limit不会低于观察到的峰值"),
+			mcp.DefaultNumber(defaultMemoryLimitFactor),
+		),
+		mcp.WithString("minCpu",
+			mcp.Description("CPU request下限"),
+			mcp.DefaultString(defaultMinCPU),
+		),
+		mcp.WithString("minMemory",
+			mcp.Description("内存request下限"),
+			mcp.DefaultString(defaultMinMemory),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.RecommendResources)
+
 	// 注册集群资源使用情况提示词
 	server.AddPrompt(mcp.NewPrompt("CLUSTER_RESOURCE_USAGE",
 		mcp.WithPromptDescription("分析Kubernetes集群资源使用情况，包括CPU、内存、存储和Pod数量的使用统计。提供资源使用趋势、分布情况和优化建议。帮助进行容量规划和资源优化。"),
@@ -177,6 +246,15 @@ func (h *MetricsHandler) Register(server *server.MCPServer) {
 	), h.PodResourceUsagePrompt)
 }
 
+// withUnitType returns the unitType parameter shared by the metrics tools
+func withUnitType() mcp.ToolOption {
+	return mcp.WithString("unitType",
+		mcp.Description("数值单位格式：\n- human：在usage等字段中同时返回明确单位的数值（CPU为毫核milli，内存为字节bytes）和可读字符串（例如'2.31 cores'、'3.4 GiB'）\n- raw：只返回明确单位的数值\n旧的整数字段（CPU为毫核，内存为MiB）仍然保留以便兼容。"),
+		mcp.Enum(utils.UnitTypeHuman, utils.UnitTypeRaw),
+		mcp.DefaultString(utils.UnitTypeHuman),
+	)
+}
+
 // GetNodeMetrics retrieves node resource usage metrics
 func (h *MetricsHandler) GetNodeMetrics(
 	ctx context.Context,
@@ -185,56 +263,48 @@ func (h *MetricsHandler) GetNodeMetrics(
 	arguments := request.GetArguments()
 	nodeName, _ := arguments["nodeName"].(string)
 	sortByStr, _ := arguments["sortBy"].(string)
-	fieldSelector, _ := arguments["fieldSelector"].(string)
-	labelSelector, _ := arguments["labelSelector"].(string)
+	fieldSelector, err := utils.SelectorArgument(arguments, utils.FieldSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	unitTypeArg, _ := arguments["unitType"].(string)
+	unitType := utils.NormalizeUnitType(unitTypeArg)
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
+	}
 
 	h.Log.Info("Getting node metrics",
 		"nodeName", nodeName,
 		"sortBy", sortByStr,
 		"fieldSelector", fieldSelector,
 		"labelSelector", labelSelector,
+		"unitType", unitType,
 	)
 
-	var nodeMetrics []models.NodeMetricInfo
-	var err error
-
 	// If node name is specified, get metrics for that node only
 	if nodeName != "" {
-		nodeMetric, err := utils.GetNodeMetric(ctx, h.Client, nodeName)
+		var nodeMetric *models.NodeMetricInfo
+		retries, err := h.RetryOnTransient(ctx, func() (err error) {
+			nodeMetric, err = utils.GetNodeMetric(ctx, h.Client, nodeName)
+			return err
+		})
 		if err != nil {
-			return utils.NewErrorToolResult(fmt.Sprintf("Failed to get node metric: %v", err)), nil
+			return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "Failed to get node metric"), retries), nil
 		}
 
 		// Create NodeResponse object
-		result := models.NodeResponse{
-			Name:              nodeMetric.Name,
-			CPUUsage:          nodeMetric.CPUUsage,
-			CPUAllocatable:    nodeMetric.CPUAllocatable,
-			CPUPercent:        nodeMetric.CPUPercent,
-			MemoryUsage:       nodeMetric.MemoryUsage,
-			MemoryAllocatable: nodeMetric.MemoryAllocatable,
-			MemoryPercent:     nodeMetric.MemoryPercent,
-			Timestamp:         nodeMetric.Timestamp,
-			UpdatedAgo:        utils.FormatTimeAgo(nodeMetric.Timestamp),
-		}
+		result := utils.NewNodeResponse(*nodeMetric, unitType, location)
 
-		jsonData, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return utils.NewErrorToolResult(fmt.Sprintf("JSON formatting failed: %v", err)), nil
-		}
-
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: string(jsonData),
-				},
-			},
-		}, nil
+		return utils.WithRetryMeta(utils.RenderResult(request, result), retries), nil
 	}
 
 	// Prepare options for getting metrics for all nodes
-	options := []utils.MetricsOption{utils.WithSortByString(sortByStr)}
+	options := []utils.MetricsOption{utils.WithSortByString(sortByStr), utils.WithUnitType(unitType)}
 
 	// Add field selector if provided
 	if fieldSelector != "" {
@@ -247,13 +317,17 @@ func (h *MetricsHandler) GetNodeMetrics(
 	}
 
 	// Get metrics for all nodes using functional options pattern
-	nodeMetrics, err = utils.GetNodesMetrics(
-		ctx,
-		h.Client,
-		options...,
-	)
+	var nodeMetrics []models.NodeMetricInfo
+	retries, err := h.RetryOnTransient(ctx, func() (err error) {
+		nodeMetrics, err = utils.GetNodesMetrics(
+			ctx,
+			h.Client,
+			options...,
+		)
+		return err
+	})
 	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("Failed to get nodes metrics: %v", err)), nil
+		return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "Failed to get nodes metrics"), retries), nil
 	}
 
 	// Create NodesListResponse object
@@ -261,35 +335,14 @@ func (h *MetricsHandler) GetNodeMetrics(
 		Nodes:      make([]models.NodeResponse, 0, len(nodeMetrics)),
 		SortBy:     string(utils.ParseSortType(sortByStr)),
 		TotalCount: len(nodeMetrics),
+		UnitType:   unitType,
 	}
 
 	for _, metric := range nodeMetrics {
-		result.Nodes = append(result.Nodes, models.NodeResponse{
-			Name:              metric.Name,
-			CPUUsage:          metric.CPUUsage,
-			CPUAllocatable:    metric.CPUAllocatable,
-			CPUPercent:        metric.CPUPercent,
-			MemoryUsage:       metric.MemoryUsage,
-			MemoryAllocatable: metric.MemoryAllocatable,
-			MemoryPercent:     metric.MemoryPercent,
-			Timestamp:         metric.Timestamp,
-			UpdatedAgo:        utils.FormatTimeAgo(metric.Timestamp),
-		})
+		result.Nodes = append(result.Nodes, utils.NewNodeResponse(metric, unitType, location))
 	}
 
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON formatting failed: %v", err)), nil
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, result), retries), nil
 }
 
 // GetPodMetrics retrieves Pod resource usage metrics
@@ -302,8 +355,20 @@ func (h *MetricsHandler) GetPodMetrics(
 	podName, _ := arguments["podName"].(string)
 	sortByStr, _ := arguments["sortBy"].(string)
 	limit, _ := arguments["limit"].(float64)
-	fieldSelector, _ := arguments["fieldSelector"].(string)
-	labelSelector, _ := arguments["labelSelector"].(string)
+	fieldSelector, err := utils.SelectorArgument(arguments, utils.FieldSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	unitTypeArg, _ := arguments["unitType"].(string)
+	unitType := utils.NormalizeUnitType(unitTypeArg)
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
+	}
 
 	h.Log.Info("Getting pod metrics",
 		"namespace", namespace,
@@ -312,12 +377,14 @@ func (h *MetricsHandler) GetPodMetrics(
 		"limit", limit,
 		"fieldSelector", fieldSelector,
 		"labelSelector", labelSelector,
+		"unitType", unitType,
 	)
 
 	// Prepare options
 	var options []utils.MetricsOption
 	options = append(options, utils.WithSortByString(sortByStr))
 	options = append(options, utils.WithLimit(int(limit)))
+	options = append(options, utils.WithUnitType(unitType))
 
 	// If pod name is specified, add pod name filter
 	if podName != "" {
@@ -335,9 +402,13 @@ func (h *MetricsHandler) GetPodMetrics(
 	}
 
 	// Get Pod metrics using functional options pattern
-	podMetrics, err := utils.GetPodsMetrics(ctx, h.Client, namespace, options...)
+	var podMetrics []models.PodMetricInfo
+	retries, err := h.RetryOnTransient(ctx, func() (err error) {
+		podMetrics, err = utils.GetPodsMetrics(ctx, h.Client, namespace, options...)
+		return err
+	})
 	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("Failed to get pod metrics: %v", err)), nil
+		return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "Failed to get pod metrics"), retries), nil
 	}
 
 	// Create PodsListResponse object
@@ -348,46 +419,15 @@ func (h *MetricsHandler) GetPodMetrics(
 		Namespace:     namespace,
 		Limit:         int(limit),
 		IncludeDetail: podName != "", // Include details if pod name is specified
+		UnitType:      unitType,
 	}
 
 	for _, pod := range podMetrics {
-		podResp := models.PodResponse{
-			Name:        pod.Name,
-			Namespace:   pod.Namespace,
-			TotalCPU:    pod.TotalCPU,
-			TotalMemory: pod.TotalMemory,
-			Timestamp:   pod.Timestamp,
-			UpdatedAgo:  utils.FormatTimeAgo(pod.Timestamp),
-		}
-
 		// If pod name is specified, include container details
-		if podName != "" && pod.Name == podName {
-			podResp.Containers = make([]models.ContainerResponse, 0, len(pod.Containers))
-			for _, container := range pod.Containers {
-				podResp.Containers = append(podResp.Containers, models.ContainerResponse{
-					Name:        container.Name,
-					CPUUsage:    container.CPUUsage,
-					MemoryUsage: container.MemoryUsage,
-				})
-			}
-		}
-
-		result.Pods = append(result.Pods, podResp)
+		result.Pods = append(result.Pods, utils.NewPodResponse(pod, podName != "" && pod.Name == podName, unitType, location))
 	}
 
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON formatting failed: %v", err)), nil
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, result), retries), nil
 }
 
 // GetResourceMetrics retrieves overall resource usage
@@ -398,22 +438,31 @@ func (h *MetricsHandler) GetResourceMetrics(
 	arguments := request.GetArguments()
 	resourceType, _ := arguments["resource"].(string)
 	namespace, _ := arguments["namespace"].(string)
-	fieldSelector, _ := arguments["fieldSelector"].(string)
-	labelSelector, _ := arguments["labelSelector"].(string)
+	fieldSelector, err := utils.SelectorArgument(arguments, utils.FieldSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	unitTypeArg, _ := arguments["unitType"].(string)
+	unitType := utils.NormalizeUnitType(unitTypeArg)
 
 	h.Log.Info("Getting resource metrics",
 		"resourceType", resourceType,
 		"namespace", namespace,
 		"fieldSelector", fieldSelector,
 		"labelSelector", labelSelector,
+		"unitType", unitType,
 	)
 
 	// Prepare options
 	var options []utils.MetricsOption
 	options = append(options, utils.WithResourceFilter(resourceType))
 
-	// Add human-readable format option if needed
-	options = append(options, utils.WithUnitType("human"))
+	// Add human-readable format option unless raw values were requested
+	options = append(options, utils.WithUnitType(unitType))
 
 	// Include detailed information by default
 	options = append(options, utils.WithIncludeDetail(true))
@@ -429,9 +478,17 @@ func (h *MetricsHandler) GetResourceMetrics(
 	}
 
 	// Get cluster resource metrics using functional options pattern
-	metrics, err := utils.GetClusterResourceMetrics(ctx, h.Client, namespace, options...)
+	var metrics *models.ClusterResourceMetrics
+	retries, err := h.RetryOnTransient(ctx, func() (err error) {
+		metrics, err = utils.GetClusterResourceMetrics(ctx, h.Client, namespace, options...)
+		return err
+	})
 	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("Failed to get cluster resource metrics: %v", err)), nil
+		// 超时前已汇总的节点容量作为部分结果返回
+		if metrics != nil {
+			return utils.NewTimeoutResult(ctx, metrics), nil
+		}
+		return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "Failed to get cluster resource metrics"), retries), nil
 	}
 
 	// Create ResourceMetricsResponse object
@@ -464,7 +521,9 @@ func (h *MetricsHandler) GetResourceMetrics(
 		result.PodCapacity = metrics.PodCapacity
 		result.RunningPods = metrics.RunningPods
 		result.PodPercent = metrics.PodPercent
-		result.PodsAvailable = metrics.PodCapacity - int64(metrics.RunningPods)
+		if metrics.PodCapacity > 0 {
+			result.PodsAvailable = metrics.PodCapacity - int64(metrics.RunningPods)
+		}
 
 	default:
 		// Include information for all resource types
@@ -491,24 +550,50 @@ func (h *MetricsHandler) GetResourceMetrics(
 		result.PodsAvailable = metrics.PodCapacity - int64(metrics.RunningPods)
 	}
 
+	// Unit-explicit amounts for the resource types included above
+	includeCPU := result.ResourceType == "cpu" || result.ResourceType == "all"
+	includeMemory := result.ResourceType == "memory" || result.ResourceType == "all"
+	includeStorage := result.ResourceType == "storage" || result.ResourceType == "all"
+	if includeCPU || includeMemory || includeStorage {
+		result.Capacity = &models.ResourceQuantities{}
+		result.Allocatable = &models.ResourceQuantities{}
+	}
+	if includeCPU || includeMemory {
+		result.Usage = &models.ResourceQuantities{}
+		result.Available = &models.ResourceQuantities{}
+		result.Requested = &models.ResourceQuantities{}
+		result.Limits = &models.ResourceQuantities{}
+	}
+	if includeCPU {
+		result.Capacity.CPU = utils.NewCPUQuantity(metrics.CPUCapacity, metrics.UnitType)
+		result.Allocatable.CPU = utils.NewCPUQuantity(metrics.CPUAllocatable, metrics.UnitType)
+		result.Usage.CPU = utils.NewCPUQuantity(metrics.CPUUsage, metrics.UnitType)
+		result.Available.CPU = utils.NewCPUQuantity(metrics.CPUAllocatable-metrics.CPUUsage, metrics.UnitType)
+		result.Requested.CPU = utils.NewCPUQuantity(metrics.CPURequests, metrics.UnitType)
+		result.Limits.CPU = utils.NewCPUQuantity(metrics.CPULimits, metrics.UnitType)
+		result.CPURequestsPercent = metrics.CPURequestsPercent
+	}
+	if includeMemory {
+		result.Capacity.Memory = utils.NewByteQuantity(metrics.MemoryCapacityBytes, metrics.UnitType)
+		result.Allocatable.Memory = utils.NewByteQuantity(metrics.MemoryAllocatableBytes, metrics.UnitType)
+		result.Usage.Memory = utils.NewByteQuantity(metrics.MemoryUsageBytes, metrics.UnitType)
+		result.Available.Memory = utils.NewByteQuantity(metrics.MemoryAllocatableBytes-metrics.MemoryUsageBytes, metrics.UnitType)
+		result.Requested.Memory = utils.NewByteQuantity(metrics.MemoryRequestsBytes, metrics.UnitType)
+		result.Limits.Memory = utils.NewByteQuantity(metrics.MemoryLimitsBytes, metrics.UnitType)
+		result.MemoryRequestsPercent = metrics.MemoryRequestsPercent
+	}
+	if includeStorage {
+		result.Capacity.Storage = utils.NewByteQuantity(metrics.StorageCapacityBytes, metrics.UnitType)
+		result.Allocatable.Storage = utils.NewByteQuantity(metrics.StorageAllocatableBytes, metrics.UnitType)
+	}
+
 	// Add namespace information if specified
 	if namespace != "" {
 		result.Namespace = namespace
 	}
+	result.NodesSkipped = metrics.NodesSkipped
 
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON formatting failed: %v", err)), nil
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, result), retries), nil
 }
 
 // GetTopConsumers retrieves pods with highest resource consumption
@@ -520,8 +605,20 @@ func (h *MetricsHandler) GetTopConsumers(
 	resourceType, _ := arguments["resource"].(string)
 	namespace, _ := arguments["namespace"].(string)
 	limit, _ := arguments["limit"].(float64)
-	fieldSelector, _ := arguments["fieldSelector"].(string)
-	labelSelector, _ := arguments["labelSelector"].(string)
+	fieldSelector, err := utils.SelectorArgument(arguments, utils.FieldSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	unitTypeArg, _ := arguments["unitType"].(string)
+	unitType := utils.NormalizeUnitType(unitTypeArg)
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
+	}
 
 	h.Log.Info("Getting top consumers",
 		"resourceType", resourceType,
@@ -529,6 +626,7 @@ func (h *MetricsHandler) GetTopConsumers(
 		"limit", limit,
 		"fieldSelector", fieldSelector,
 		"labelSelector", labelSelector,
+		"unitType", unitType,
 	)
 
 	// Validate resource type
@@ -548,6 +646,7 @@ func (h *MetricsHandler) GetTopConsumers(
 	options := []utils.MetricsOption{
 		utils.WithSortType(sortType),
 		utils.WithLimit(int(limit)),
+		utils.WithUnitType(unitType),
 	}
 
 	// Add field selector if provided
@@ -561,14 +660,18 @@ func (h *MetricsHandler) GetTopConsumers(
 	}
 
 	// Get Pod metrics sorted by resource usage using functional options pattern
-	podMetrics, err := utils.GetPodsMetrics(
-		ctx,
-		h.Client,
-		namespace,
-		options...,
-	)
+	var podMetrics []models.PodMetricInfo
+	retries, err := h.RetryOnTransient(ctx, func() (err error) {
+		podMetrics, err = utils.GetPodsMetrics(
+			ctx,
+			h.Client,
+			namespace,
+			options...,
+		)
+		return err
+	})
 	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("Failed to get pod metrics: %v", err)), nil
+		return utils.WithRetryMeta(utils.NewKubeErrorResult(err, "Failed to get pod metrics"), retries), nil
 	}
 
 	// Create TopConsumersListResponse object
@@ -578,36 +681,28 @@ func (h *MetricsHandler) GetTopConsumers(
 		Limit:        int(limit),
 		Namespace:    namespace,
 		TotalCount:   len(podMetrics),
+		UnitType:     unitType,
 	}
 
 	for _, pod := range podMetrics {
 		usageValue := pod.TotalCPU
+		usageQuantity := models.ResourceQuantities{CPU: utils.NewCPUQuantity(pod.TotalCPU, unitType)}
 		if resourceType == "memory" {
 			usageValue = pod.TotalMemory
+			usageQuantity = models.ResourceQuantities{Memory: utils.NewByteQuantity(pod.TotalMemoryBytes, unitType)}
 		}
 
 		result.Consumers = append(result.Consumers, models.TopConsumerResponse{
-			Name:       pod.Name,
-			Namespace:  pod.Namespace,
-			Usage:      usageValue,
-			Timestamp:  pod.Timestamp,
-			UpdatedAgo: utils.FormatTimeAgo(pod.Timestamp),
+			Name:          pod.Name,
+			Namespace:     pod.Namespace,
+			Usage:         usageValue,
+			UsageQuantity: usageQuantity,
+			Timestamp:     pod.Timestamp.In(location),
+			UpdatedAgo:    utils.FormatTimeAgo(pod.Timestamp),
 		})
 	}
 
-	jsonData, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return utils.NewErrorToolResult(fmt.Sprintf("JSON formatting failed: %v", err)), nil
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: string(jsonData),
-			},
-		},
-	}, nil
+	return utils.WithRetryMeta(utils.RenderResult(request, result), retries), nil
 }
 
 // ClusterResourceUsagePrompt 处理集群资源使用情况提示词
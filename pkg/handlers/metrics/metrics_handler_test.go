@@ -0,0 +1,152 @@
+package base
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func usage(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func testNode(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{Capacity: usage(cpu, memory), Allocatable: usage(cpu, memory)},
+	}
+}
+
+func testNodeMetrics(name, cpu, memory string) *metricsv1beta1.NodeMetrics {
+	return &metricsv1beta1.NodeMetrics{ObjectMeta: metav1.ObjectMeta{Name: name}, Timestamp: metav1.Now(), Usage: usage(cpu, memory)}
+}
+
+func testPodMetrics(name, namespace, app, cpu, memory string) *metricsv1beta1.PodMetrics {
+	return &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Timestamp:  metav1.Now(),
+		Containers: []metricsv1beta1.ContainerMetrics{{Name: app, Usage: usage(cpu, memory)}},
+	}
+}
+
+// newMetricsTestHandler 两个节点各有2核4Gi可分配资源；default命名空间两个Pod，other命名空间一个Pod
+func newMetricsTestHandler() *MetricsHandler {
+	client := testutil.NewFakeClient(
+		testNode("node-a", "2", "4Gi"),
+		testNode("node-b", "2", "4Gi"),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: testutil.DefaultNamespace}},
+		testNodeMetrics("node-a", "500m", "1Gi"),
+		testNodeMetrics("node-b", "1500m", "2Gi"),
+		testPodMetrics("web-1", testutil.DefaultNamespace, "web", "300m", "128Mi"),
+		testPodMetrics("db-1", testutil.DefaultNamespace, "db", "100m", "512Mi"),
+		testPodMetrics("batch-1", "other", "batch", "700m", "64Mi"),
+	)
+	return NewMetricsHandler(client).(*MetricsHandler)
+}
+
+func callMetricsTool(t *testing.T, h *MetricsHandler, tool string, arguments map[string]any, v any) {
+	t.Helper()
+	result, err := h.Handle(context.Background(), testutil.NewToolRequest(tool, arguments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.DecodeResult(result, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetNodeMetrics(t *testing.T) {
+	h := newMetricsTestHandler()
+
+	var list models.NodesListResponse
+	callMetricsTool(t, h, GET_NODE_METRICS, map[string]any{"sortBy": "cpu"}, &list)
+	if list.TotalCount != 2 || list.Nodes[0].Name != "node-b" {
+		t.Fatalf("nodes = %+v, want node-b first by cpu", list.Nodes)
+	}
+
+	var node models.NodeResponse
+	callMetricsTool(t, h, GET_NODE_METRICS, map[string]any{"nodeName": "node-a"}, &node)
+	if node.Usage.CPU == nil || node.Usage.CPU.Milli != 500 || node.CPUPercent != 25 {
+		t.Fatalf("node-a usage = %+v, %.1f%%; want 500m, 25%%", node.Usage.CPU, node.CPUPercent)
+	}
+	if node.Usage.Memory == nil || node.Usage.Memory.Bytes != 1<<30 {
+		t.Fatalf("node-a memory = %+v, want 1Gi", node.Usage.Memory)
+	}
+}
+
+func TestGetPodMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		want      []string
+	}{
+		{name: "namespace sorted by cpu", arguments: map[string]any{"namespace": testutil.DefaultNamespace, "sortBy": "cpu"}, want: []string{"web-1", "db-1"}},
+		{name: "sorted by memory", arguments: map[string]any{"namespace": testutil.DefaultNamespace, "sortBy": "memory"}, want: []string{"db-1", "web-1"}},
+		{name: "all namespaces with limit", arguments: map[string]any{"sortBy": "cpu", "limit": 2}, want: []string{"batch-1", "web-1"}},
+		{name: "label selector", arguments: map[string]any{"labelSelector": "app=db"}, want: []string{"db-1"}},
+		{name: "pod name", arguments: map[string]any{"namespace": testutil.DefaultNamespace, "podName": "web-1"}, want: []string{"web-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response models.PodsListResponse
+			callMetricsTool(t, newMetricsTestHandler(), GET_POD_METRICS, tt.arguments, &response)
+
+			names := make([]string, 0, len(response.Pods))
+			for _, pod := range response.Pods {
+				names = append(names, pod.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Fatalf("pods = %v, want %v", names, tt.want)
+			}
+			if _, ok := tt.arguments["podName"]; ok && len(response.Pods[0].Containers) != 1 {
+				t.Fatalf("containers of the named pod = %+v, want one", response.Pods[0].Containers)
+			}
+		})
+	}
+}
+
+func TestGetTopConsumers(t *testing.T) {
+	h := newMetricsTestHandler()
+
+	var response models.TopConsumersListResponse
+	callMetricsTool(t, h, GET_TOP_CONSUMERS, map[string]any{"resource": "memory", "limit": 1}, &response)
+	if len(response.Consumers) != 1 || response.Consumers[0].Name != "db-1" {
+		t.Fatalf("consumers = %+v, want db-1", response.Consumers)
+	}
+	if quantity := response.Consumers[0].UsageQuantity.Memory; quantity == nil || quantity.Bytes != 512<<20 {
+		t.Fatalf("usage quantity = %+v, want 512Mi", quantity)
+	}
+
+	result, err := h.Handle(context.Background(), testutil.NewToolRequest(GET_TOP_CONSUMERS, map[string]any{"resource": "storage"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError {
+		t.Fatalf("unsupported resource type was accepted: %s", testutil.ResultText(result))
+	}
+}
+
+func TestGetResourceMetrics(t *testing.T) {
+	var response models.ResourceMetricsResponse
+	callMetricsTool(t, newMetricsTestHandler(), GET_RESOURCE_METRICS, map[string]any{"resource": "cpu"}, &response)
+
+	if response.CPUAllocatable != 4000 || response.CPUUsage != 2000 || response.CPUAvailable != 2000 || response.CPUPercent != 50 {
+		t.Fatalf("cpu = allocatable %d, usage %d, available %d, %.1f%%; want 4000m, 2000m, 2000m, 50%%",
+			response.CPUAllocatable, response.CPUUsage, response.CPUAvailable, response.CPUPercent)
+	}
+	if response.Usage == nil || response.Usage.CPU == nil || response.Usage.Memory != nil {
+		t.Fatalf("usage quantities = %+v, want cpu only", response.Usage)
+	}
+	if response.MemoryUsage != 0 {
+		t.Fatalf("memory usage %d reported for resource=cpu", response.MemoryUsage)
+	}
+}
@@ -0,0 +1,569 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// Defaults and bounds of RECOMMEND_RESOURCES
+const (
+	defaultRecommendSamples      = 5
+	maxRecommendSamples          = 20
+	defaultRecommendWindow       = 60
+	maxRecommendWindow           = 300
+	defaultCPURequestHeadroom    = 1.2
+	defaultMemoryRequestHeadroom = 1.2
+	defaultCPULimitFactor        = 2.0
+	defaultMemoryLimitFactor     = 1.5
+	defaultMinCPU                = "10m"
+	defaultMinMemory             = "32Mi"
+	mebibyte                     = 1024 * 1024
+	// recommendTimeoutMargin is kept free between the last poll and the end of the call for building the result
+	recommendTimeoutMargin = 10 * time.Second
+)
+
+// recommendEstimate labels every recommendation as a point-in-time estimate
+const recommendEstimate = "Point-in-time estimate from %d metrics samples over %s. It does not see daily or weekly peaks, " +
+	"startup spikes or traffic that did not happen during the window; review it against longer-term monitoring before applying."
+
+// recommendWorkload is the part of a workload the recommendation needs
+type recommendWorkload struct {
+	selector string
+	replicas int32
+	template corev1.PodSpec
+}
+
+// recommendOptions holds the parsed factors and floors
+type recommendOptions struct {
+	factors        models.RecommendationFactors
+	minCPUMilli    int64
+	minMemoryBytes int64
+}
+
+// containerUsage collects the deduplicated data points of one container across pods
+type containerUsage struct {
+	cpu    []int64
+	memory []int64
+}
+
+// RecommendResources samples the workload's pod metrics and recommends container requests and limits
+func (h *MetricsHandler) RecommendResources(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	samples := defaultRecommendSamples
+	if value, ok := arguments["samples"].(float64); ok && value > 0 {
+		samples = min(int(value), maxRecommendSamples)
+	}
+	windowSeconds := defaultRecommendWindow
+	if value, ok := arguments["maxDurationSeconds"].(float64); ok && value > 0 {
+		windowSeconds = min(int(value), maxRecommendWindow)
+	}
+
+	h.Log.Info("Recommending resources",
+		"kind", kind,
+		"name", name,
+		"namespace", namespace,
+		"samples", samples,
+		"windowSeconds", windowSeconds,
+	)
+
+	if kind == "" || name == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind and name"), nil
+	}
+	options, errs := parseRecommendOptions(arguments)
+	if len(errs) > 0 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: "invalid RECOMMEND_RESOURCES parameters",
+			Details: errs,
+		}), nil
+	}
+
+	workload, err := h.recommendWorkload(ctx, kind, namespace, name)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unsupported kind") {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeUnsupported,
+				Message: err.Error(),
+				Hint:    "RECOMMEND_RESOURCES supports Deployment, StatefulSet and DaemonSet.",
+			}), nil
+		}
+		h.Log.Error("Failed to get workload", "kind", kind, "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+	}
+
+	// The quotas are read before sampling so that the lookup cannot run into the end of the call
+	quotas, quotaErr := h.Client.ClientSet().CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+
+	// The call timeout is at least maxDurationSeconds and may equal it, so the window is shrunk to leave
+	// a margin for the last poll and the result before the deadline
+	window := time.Duration(windowSeconds) * time.Second
+	requestedWindow := window
+	if deadline, ok := ctx.Deadline(); ok {
+		if available := time.Until(deadline) - recommendTimeoutMargin; available < window {
+			window = max(available, 0).Truncate(time.Second)
+		}
+	}
+	polls, err := utils.SamplePodMetrics(ctx, h.Client, namespace, workload.selector, samples, window)
+	if err != nil {
+		h.Log.Error("Failed to sample pod metrics", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to get pod metrics; metrics-server must be installed"), nil
+	}
+
+	response := models.ResourceRecommendation{
+		Kind:       kind,
+		Name:       name,
+		Namespace:  namespace,
+		Replicas:   workload.replicas,
+		Window:     window.String(),
+		Samples:    len(polls),
+		Factors:    options.factors,
+		Containers: []models.ContainerRecommendation{},
+	}
+	response.Estimate = fmt.Sprintf(recommendEstimate, len(polls), window)
+	if window < requestedWindow {
+		response.Notes = append(response.Notes,
+			fmt.Sprintf("the sampling window was shortened from %s to %s to finish within the call timeout; raise timeoutSeconds for the full window", requestedWindow, window))
+	}
+	if len(polls) < samples {
+		response.Notes = append(response.Notes,
+			fmt.Sprintf("only %d of %d samples were collected before the call ended", len(polls), samples))
+	}
+
+	usage, pods, dataPoints := aggregateContainerUsage(polls)
+	response.Pods = pods
+	if pods > 0 {
+		response.DataPoints = dataPoints / pods
+	}
+	if pods == 0 {
+		response.Notes = append(response.Notes, "no metrics were found for the pods of this workload; check that its pods are running and metrics-server is healthy")
+		return utils.RenderResult(request, response), nil
+	}
+	if response.DataPoints < 3 {
+		response.Notes = append(response.Notes,
+			"fewer than 3 distinct data points per pod; metrics-server refreshes about every 15s, raise maxDurationSeconds for a steadier estimate")
+	}
+
+	sidecars := make(map[string]bool)
+	for _, container := range workload.template.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars[container.Name] = true
+		}
+	}
+	current := make(map[string]corev1.ResourceRequirements)
+	for _, container := range append(append([]corev1.Container{}, workload.template.InitContainers...), workload.template.Containers...) {
+		current[container.Name] = container.Resources
+	}
+
+	patchContainers := map[string][]map[string]any{}
+	changes := corev1.ResourceList{}
+	for _, container := range workload.template.Containers {
+		if _, ok := usage[container.Name]; !ok {
+			response.Notes = append(response.Notes, fmt.Sprintf("container %s reported no metrics and is left unchanged", container.Name))
+		}
+	}
+	for _, containerName := range sortedUsageNames(usage) {
+		requirements, inSpec := current[containerName]
+		if !inSpec {
+			continue
+		}
+		recommendation, recommended := recommendContainer(containerName, usage[containerName], requirements, options)
+		recommendation.Sidecar = sidecars[containerName]
+		response.Containers = append(response.Containers, recommendation)
+
+		field := "containers"
+		if recommendation.Sidecar {
+			field = "initContainers"
+		}
+		patchContainers[field] = append(patchContainers[field], map[string]any{
+			"name":      containerName,
+			"resources": resourcesPatch(recommended),
+		})
+		addResourceChanges(changes, requirements, recommended)
+	}
+
+	if len(patchContainers) > 0 {
+		podSpec := map[string]any{}
+		for field, containers := range patchContainers {
+			podSpec[field] = containers
+		}
+		data, err := json.Marshal(map[string]any{"spec": map[string]any{"template": map[string]any{"spec": podSpec}}})
+		if err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("failed to build patch: %v", err)), nil
+		}
+		response.Patch = string(data)
+		response.PatchType = "strategic"
+		response.Command = fmt.Sprintf("kubectl patch %s %s -n %s --type strategic -p '%s'",
+			strings.ToLower(kind), name, namespace, response.Patch)
+	}
+
+	if quotaErr != nil {
+		h.Log.Warn("Failed to compare with resource quotas", "namespace", namespace, "error", quotaErr)
+		response.Notes = append(response.Notes, fmt.Sprintf("could not read ResourceQuotas: %v", quotaErr))
+	} else {
+		response.QuotaImpact = quotaImpact(quotas.Items, changes, workload.replicas)
+	}
+	for _, item := range response.QuotaImpact {
+		if !item.Fits {
+			response.Notes = append(response.Notes,
+				fmt.Sprintf("applying the patch would exceed ResourceQuota %s for %s; new pods of the rollout would be rejected", item.Quota, item.Resource))
+		}
+	}
+
+	return utils.RenderResult(request, response), nil
+}
+
+// parseRecommendOptions reads the headroom factors and floors, returning every invalid argument
+func parseRecommendOptions(arguments map[string]any) (recommendOptions, []string) {
+	var errs []string
+	factor := func(key string, fallback float64, allowZero bool) float64 {
+		value, ok := arguments[key].(float64)
+		if !ok {
+			return fallback
+		}
+		if value == 0 && allowZero {
+			return 0
+		}
+		if value < 1 {
+			errs = append(errs, fmt.Sprintf("%s must be at least 1, got %g", key, value))
+			return fallback
+		}
+		return value
+	}
+	floor := func(key, fallback string) (string, resource.Quantity) {
+		text, _ := arguments[key].(string)
+		if strings.TrimSpace(text) == "" {
+			text = fallback
+		}
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(text))
+		if err != nil || quantity.Sign() <= 0 {
+			errs = append(errs, fmt.Sprintf("%s must be a positive quantity, got %q", key, text))
+			quantity = resource.MustParse(fallback)
+			text = fallback
+		}
+		return text, quantity
+	}
+
+	options := recommendOptions{}
+	options.factors.CPURequest = factor("cpuRequestHeadroom", defaultCPURequestHeadroom, false)
+	options.factors.MemoryRequest = factor("memoryRequestHeadroom", defaultMemoryRequestHeadroom, false)
+	options.factors.CPULimit = factor("cpuLimitFactor", defaultCPULimitFactor, true)
+	options.factors.MemoryLimit = factor("memoryLimitFactor", defaultMemoryLimitFactor, true)
+	minCPU, minCPUQuantity := floor("minCpu", defaultMinCPU)
+	minMemory, minMemoryQuantity := floor("minMemory", defaultMinMemory)
+	options.factors.MinCPU, options.factors.MinMemory = minCPU, minMemory
+	options.minCPUMilli = minCPUQuantity.MilliValue()
+	options.minMemoryBytes = minMemoryQuantity.Value()
+	return options, errs
+}
+
+// recommendWorkload reads the selector, replica count and pod template of a Deployment, StatefulSet or DaemonSet
+func (h *MetricsHandler) recommendWorkload(ctx context.Context, kind, namespace, name string) (*recommendWorkload, error) {
+	apps := h.Client.ClientSet().AppsV1()
+	var selector *metav1.LabelSelector
+	workload := &recommendWorkload{}
+	switch kind {
+	case "Deployment":
+		deployment, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, workload.template = deployment.Spec.Selector, deployment.Spec.Template.Spec
+		workload.replicas = 1
+		if deployment.Spec.Replicas != nil {
+			workload.replicas = *deployment.Spec.Replicas
+		}
+	case "StatefulSet":
+		statefulSet, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, workload.template = statefulSet.Spec.Selector, statefulSet.Spec.Template.Spec
+		workload.replicas = 1
+		if statefulSet.Spec.Replicas != nil {
+			workload.replicas = *statefulSet.Spec.Replicas
+		}
+	case "DaemonSet":
+		daemonSet, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, workload.template = daemonSet.Spec.Selector, daemonSet.Spec.Template.Spec
+		workload.replicas = daemonSet.Status.DesiredNumberScheduled
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of %s %s: %w", kind, name, err)
+	}
+	if labelSelector.Empty() {
+		return nil, fmt.Errorf("%s %s has an empty selector", kind, name)
+	}
+	workload.selector = labelSelector.String()
+	return workload, nil
+}
+
+// aggregateContainerUsage collects per-container data points across polls, skipping points metrics-server repeated.
+// Returns the usage by container name, the number of pods seen and the total number of distinct pod data points
+func aggregateContainerUsage(polls []utils.PodMetricsSample) (map[string]*containerUsage, int, int) {
+	usage := make(map[string]*containerUsage)
+	seen := make(map[string]bool)
+	pods := make(map[string]bool)
+	points := 0
+	for _, poll := range polls {
+		for _, pod := range poll.Pods {
+			key := pod.Name + "@" + pod.Timestamp.String()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			pods[pod.Name] = true
+			points++
+			for _, container := range pod.Containers {
+				entry := usage[container.Name]
+				if entry == nil {
+					entry = &containerUsage{}
+					usage[container.Name] = entry
+				}
+				entry.cpu = append(entry.cpu, container.CPUUsage)
+				entry.memory = append(entry.memory, container.MemoryUsageBytes)
+			}
+		}
+	}
+	return usage, len(pods), points
+}
+
+// sortedUsageNames returns the container names in stable order
+func sortedUsageNames(usage map[string]*containerUsage) []string {
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recommendContainer computes the recommendation of one container: requests are p95 times the headroom factor
+// and never below the floors, limits are the requests times the limit factor, and the memory limit never falls
+// below the highest observed usage
+func recommendContainer(
+	name string,
+	usage *containerUsage,
+	current corev1.ResourceRequirements,
+	options recommendOptions,
+) (models.ContainerRecommendation, corev1.ResourceRequirements) {
+	factors := options.factors
+	cpuP50, cpuP95 := utils.PercentileInt64(usage.cpu, 50), utils.PercentileInt64(usage.cpu, 95)
+	memoryP50, memoryP95 := utils.PercentileInt64(usage.memory, 50), utils.PercentileInt64(usage.memory, 95)
+	cpuMax, memoryMax := utils.PercentileInt64(usage.cpu, 100), utils.PercentileInt64(usage.memory, 100)
+
+	recommendation := models.ContainerRecommendation{
+		Name:       name,
+		DataPoints: len(usage.cpu),
+		CPU: models.UsagePercentiles{
+			P50: utils.FormatCPUMilli(cpuP50),
+			P95: utils.FormatCPUMilli(cpuP95),
+			Max: utils.FormatCPUMilli(cpuMax),
+		},
+		Memory: models.UsagePercentiles{
+			P50: utils.FormatBinaryBytes(memoryP50),
+			P95: utils.FormatBinaryBytes(memoryP95),
+			Max: utils.FormatBinaryBytes(memoryMax),
+		},
+		Current:     resourceSettings(current),
+		Explanation: []string{},
+	}
+	explain := func(format string, args ...any) {
+		recommendation.Explanation = append(recommendation.Explanation, fmt.Sprintf(format, args...))
+	}
+
+	cpuRequest := int64(math.Ceil(float64(cpuP95) * factors.CPURequest))
+	explain("cpu request %s = p95 %s x %g headroom", utils.FormatCPUMilli(cpuRequest), recommendation.CPU.P95, factors.CPURequest)
+	if cpuRequest < options.minCPUMilli {
+		cpuRequest = options.minCPUMilli
+		explain("cpu request raised to the floor %s", factors.MinCPU)
+	}
+	memoryRequest := roundUpMebibytes(int64(math.Ceil(float64(memoryP95) * factors.MemoryRequest)))
+	explain("memory request %s = p95 %s x %g headroom, rounded up to MiB", utils.FormatBinaryBytes(memoryRequest), recommendation.Memory.P95, factors.MemoryRequest)
+	if memoryRequest < options.minMemoryBytes {
+		memoryRequest = options.minMemoryBytes
+		explain("memory request raised to the floor %s", factors.MinMemory)
+	}
+
+	recommended := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewMilliQuantity(cpuRequest, resource.DecimalSI),
+			corev1.ResourceMemory: *resource.NewQuantity(memoryRequest, resource.BinarySI),
+		},
+		Limits: corev1.ResourceList{},
+	}
+	if factors.CPULimit > 0 {
+		cpuLimit := int64(math.Ceil(float64(cpuRequest) * factors.CPULimit))
+		recommended.Limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpuLimit, resource.DecimalSI)
+		explain("cpu limit %s = request x %g", utils.FormatCPUMilli(cpuLimit), factors.CPULimit)
+	}
+	if factors.MemoryLimit > 0 {
+		memoryLimit := roundUpMebibytes(int64(math.Ceil(float64(memoryRequest) * factors.MemoryLimit)))
+		explain("memory limit %s = request x %g", utils.FormatBinaryBytes(memoryLimit), factors.MemoryLimit)
+		if peak := roundUpMebibytes(memoryMax); memoryLimit < peak {
+			memoryLimit = peak
+			explain("memory limit raised to the observed peak %s to avoid OOM kills", utils.FormatBinaryBytes(peak))
+		}
+		recommended.Limits[corev1.ResourceMemory] = *resource.NewQuantity(memoryLimit, resource.BinarySI)
+	}
+	// Limits that are not recommended keep their current value, unless it is below the new request and would be rejected
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		limit, hasLimit := current.Limits[resourceName]
+		if _, recommendedLimit := recommended.Limits[resourceName]; recommendedLimit || !hasLimit {
+			continue
+		}
+		if request := recommended.Requests[resourceName]; limit.Cmp(request) < 0 {
+			recommended.Limits[resourceName] = request.DeepCopy()
+			explain("existing %s limit %s is below the new request and is raised to it", resourceName, limit.String())
+		}
+	}
+
+	if len(current.Requests) == 0 {
+		explain("the container has no requests; without them the scheduler cannot reserve capacity and it is evicted first under node pressure, add them")
+	} else {
+		for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := current.Requests[resourceName]; !ok {
+				explain("the container has no %s request, add it", resourceName)
+			}
+		}
+	}
+	if request, ok := current.Requests[corev1.ResourceMemory]; ok && memoryMax > request.Value() {
+		explain("observed memory peak %s is above the current request %s", recommendation.Memory.Max, request.String())
+	}
+	recommendation.Recommended = resourceSettings(recommended)
+	return recommendation, recommended
+}
+
+// roundUpMebibytes rounds bytes up to a whole MiB so that the patch contains readable quantities
+func roundUpMebibytes(bytes int64) int64 {
+	return (bytes + mebibyte - 1) / mebibyte * mebibyte
+}
+
+// resourceSettings formats requests and limits as quantity strings
+func resourceSettings(requirements corev1.ResourceRequirements) models.ResourceSettings {
+	settings := models.ResourceSettings{}
+	if len(requirements.Requests) > 0 {
+		settings.Requests = make(map[string]string, len(requirements.Requests))
+		for name, quantity := range requirements.Requests {
+			settings.Requests[string(name)] = quantity.String()
+		}
+	}
+	if len(requirements.Limits) > 0 {
+		settings.Limits = make(map[string]string, len(requirements.Limits))
+		for name, quantity := range requirements.Limits {
+			settings.Limits[string(name)] = quantity.String()
+		}
+	}
+	return settings
+}
+
+// resourcesPatch returns the resources field of the strategic merge patch
+func resourcesPatch(requirements corev1.ResourceRequirements) map[string]any {
+	patch := map[string]any{}
+	for field, list := range map[string]corev1.ResourceList{"requests": requirements.Requests, "limits": requirements.Limits} {
+		if len(list) == 0 {
+			continue
+		}
+		values := make(map[string]string, len(list))
+		for name, quantity := range list {
+			values[string(name)] = quantity.String()
+		}
+		patch[field] = values
+	}
+	return patch
+}
+
+// addResourceChanges adds the per-pod change of requests and limits to changes, keyed like ResourceQuota resources
+func addResourceChanges(changes corev1.ResourceList, current, recommended corev1.ResourceRequirements) {
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		for prefix, lists := range map[string][2]corev1.ResourceList{
+			"requests.": {current.Requests, recommended.Requests},
+			"limits.":   {current.Limits, recommended.Limits},
+		} {
+			next, ok := lists[1][resourceName]
+			if !ok {
+				continue
+			}
+			delta := next.DeepCopy()
+			if previous, ok := lists[0][resourceName]; ok {
+				delta.Sub(previous)
+			}
+			key := corev1.ResourceName(prefix + string(resourceName))
+			total := changes[key]
+			total.Add(delta)
+			changes[key] = total
+		}
+	}
+}
+
+// quotaImpact compares the change of the workload's requests and limits with the unscoped ResourceQuotas of the namespace.
+// A quota on "cpu" or "memory" counts requests, like requests.cpu and requests.memory
+func quotaImpact(quotas []corev1.ResourceQuota, changes corev1.ResourceList, replicas int32) []models.QuotaImpact {
+	if len(changes) == 0 {
+		return nil
+	}
+	var impact []models.QuotaImpact
+	for _, quota := range quotas {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, resourceName := range names {
+			key := corev1.ResourceName(resourceName)
+			if key == corev1.ResourceCPU || key == corev1.ResourceMemory {
+				key = corev1.ResourceName("requests." + resourceName)
+			}
+			perPod, ok := changes[key]
+			if !ok {
+				continue
+			}
+			change := perPod.DeepCopy()
+			change.Mul(int64(replicas))
+			hard := quota.Status.Hard[corev1.ResourceName(resourceName)]
+			used := quota.Status.Used[corev1.ResourceName(resourceName)]
+			after := used.DeepCopy()
+			after.Add(change)
+			impact = append(impact, models.QuotaImpact{
+				Quota:    quota.Name,
+				Resource: resourceName,
+				Hard:     hard.String(),
+				Used:     used.String(),
+				Change:   change.String(),
+				After:    after.String(),
+				Fits:     after.Cmp(hard) <= 0,
+			})
+		}
+	}
+	return impact
+}
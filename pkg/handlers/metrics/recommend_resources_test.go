@@ -0,0 +1,195 @@
+package base
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func defaultRecommendOptions(t *testing.T, arguments map[string]any) recommendOptions {
+	t.Helper()
+	options, errs := parseRecommendOptions(arguments)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected option errors: %v", errs)
+	}
+	return options
+}
+
+func TestRecommendContainer(t *testing.T) {
+	usage := &containerUsage{}
+	for i := 1; i <= 20; i++ {
+		usage.cpu = append(usage.cpu, int64(i*10))
+		usage.memory = append(usage.memory, 100*mebibyte)
+	}
+	// 一次内存峰值不影响p95，但memory limit不能低于它
+	usage.memory[7] = 200 * mebibyte
+
+	recommendation, recommended := recommendContainer("app", usage, corev1.ResourceRequirements{}, defaultRecommendOptions(t, nil))
+
+	if recommendation.CPU.P95 != "190m" || recommendation.CPU.P50 != "100m" || recommendation.CPU.Max != "200m" {
+		t.Fatalf("cpu percentiles = %+v", recommendation.CPU)
+	}
+	want := map[corev1.ResourceName]string{corev1.ResourceCPU: "228m", corev1.ResourceMemory: "120Mi"}
+	for name, value := range want {
+		if got := recommended.Requests[name]; got.String() != value {
+			t.Errorf("request %s = %s, want %s", name, got.String(), value)
+		}
+	}
+	// cpu limit = 228m x 2；memory limit = 120Mi x 1.5 = 180Mi，低于峰值200Mi时提高到峰值
+	want = map[corev1.ResourceName]string{corev1.ResourceCPU: "456m", corev1.ResourceMemory: "200Mi"}
+	for name, value := range want {
+		if got := recommended.Limits[name]; got.String() != value {
+			t.Errorf("limit %s = %s, want %s", name, got.String(), value)
+		}
+	}
+	if !slices.ContainsFunc(recommendation.Explanation, func(line string) bool { return strings.Contains(line, "observed peak") }) {
+		t.Errorf("explanation does not mention the memory peak: %v", recommendation.Explanation)
+	}
+}
+
+func TestRecommendContainerFloorsAndExistingLimits(t *testing.T) {
+	usage := &containerUsage{cpu: []int64{1, 2, 1}, memory: []int64{mebibyte, mebibyte, mebibyte}}
+	current := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("5m")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8m")},
+	}
+	options := defaultRecommendOptions(t, map[string]any{"cpuLimitFactor": float64(0), "memoryLimitFactor": float64(0)})
+
+	recommendation, recommended := recommendContainer("app", usage, current, options)
+
+	if got := recommended.Requests[corev1.ResourceCPU]; got.String() != defaultMinCPU {
+		t.Errorf("cpu request = %s, want the floor %s", got.String(), defaultMinCPU)
+	}
+	if got := recommended.Requests[corev1.ResourceMemory]; got.String() != defaultMinMemory {
+		t.Errorf("memory request = %s, want the floor %s", got.String(), defaultMinMemory)
+	}
+	// 不建议cpu limit时保留现有值，但现有值低于新的request时提高到request
+	if got := recommended.Limits[corev1.ResourceCPU]; got.String() != defaultMinCPU {
+		t.Errorf("cpu limit = %s, want it raised to the request", got.String())
+	}
+	if _, ok := recommended.Limits[corev1.ResourceMemory]; ok {
+		t.Error("memory limit must not be recommended with memoryLimitFactor=0")
+	}
+	if !slices.ContainsFunc(recommendation.Explanation, func(line string) bool { return strings.Contains(line, "no memory request") }) {
+		t.Errorf("explanation does not mention the missing memory request: %v", recommendation.Explanation)
+	}
+}
+
+func TestParseRecommendOptionsErrors(t *testing.T) {
+	_, errs := parseRecommendOptions(map[string]any{
+		"cpuRequestHeadroom": 0.5,
+		"cpuLimitFactor":     float64(0),
+		"minMemory":          "-1Mi",
+	})
+	if len(errs) != 2 {
+		t.Fatalf("errors = %v, want cpuRequestHeadroom and minMemory", errs)
+	}
+}
+
+func TestQuotaImpact(t *testing.T) {
+	changes := corev1.ResourceList{}
+	addResourceChanges(changes,
+		corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+		corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}},
+	)
+	quotas := []corev1.ResourceQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				Used: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("800m")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "best-effort"},
+			Spec:       corev1.ResourceQuotaSpec{Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+			},
+		},
+	}
+
+	impact := quotaImpact(quotas, changes, 3)
+
+	want := []models.QuotaImpact{{Quota: "compute", Resource: "cpu", Hard: "1", Used: "800m", Change: "300m", After: "1100m", Fits: false}}
+	if !slices.Equal(impact, want) {
+		t.Fatalf("impact = %+v, want %+v", impact, want)
+	}
+}
+
+// TestRecommendResourcesFitsWindowIntoTimeout 调用超时等于采样窗口时，窗口缩短以在超时前完成最后一次采样和配额比较
+func TestRecommendResourcesFitsWindowIntoTimeout(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	replicas := int32(2)
+	container := corev1.Container{
+		Name: "web",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+		},
+	}
+	client := testutil.NewFakeClient(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: testutil.DefaultNamespace},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{container}}},
+			},
+		},
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: testutil.DefaultNamespace},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("100m")},
+			},
+		},
+		&metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: testutil.DefaultNamespace, Labels: labels},
+			Timestamp:  metav1.Now(),
+			Containers: []metricsv1beta1.ContainerMetrics{{
+				Name:  "web",
+				Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+			}},
+		},
+	)
+	handler := NewMetricsHandler(client).(*MetricsHandler)
+	ctx, cancel := context.WithTimeout(context.Background(), recommendTimeoutMargin+1500*time.Millisecond)
+	defer cancel()
+
+	result, err := handler.RecommendResources(ctx, testutil.NewToolRequest(RECOMMEND_RESOURCES, map[string]any{
+		"kind":               "Deployment",
+		"name":               "web",
+		"samples":            2,
+		"maxDurationSeconds": 60,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response models.ResourceRecommendation
+	if err := testutil.DecodeResult(result, &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Window != "1s" || response.Samples != 2 {
+		t.Fatalf("window %s with %d samples, want both samples in a 1s window", response.Window, response.Samples)
+	}
+	if !slices.ContainsFunc(response.Notes, func(note string) bool { return strings.Contains(note, "shortened from 1m0s to 1s") }) {
+		t.Errorf("notes do not explain the shortened window: %v", response.Notes)
+	}
+	if len(response.QuotaImpact) != 1 || response.QuotaImpact[0].Change != "140m" {
+		t.Fatalf("quota impact = %+v, want +70m for each of 2 replicas", response.QuotaImpact)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("the call ran into its timeout")
+	}
+}
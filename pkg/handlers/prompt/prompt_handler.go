@@ -7,12 +7,12 @@ import (
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
 	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
 // 提示词类型常量
@@ -56,7 +56,7 @@ func (h *PromptHandler) Handle(ctx context.Context, request mcp.CallToolRequest)
 	case TROUBLESHOOT_NET_PROMPT:
 		return h.handleTroubleshootNetworkPrompt(ctx, request)
 	default:
-		return nil, nil
+		return utils.NewErrorToolResult(fmt.Sprintf("unknown prompt method: %s", request.Method)), nil
 	}
 }
 
@@ -191,7 +191,7 @@ func (h *PromptHandler) handleTroubleshootNetworkPrompt(ctx context.Context, req
 }
 
 // Register 注册提示词到MCP服务器
-func (h *PromptHandler) Register(s *server.MCPServer) {
+func (h *PromptHandler) Register(s interfaces.ToolRegistrar) {
 	h.Log.Info("Registering prompt handlers")
 
 	// Kubernetes YAML 生成提示词
@@ -288,26 +288,30 @@ func (h *PromptHandler) Register(s *server.MCPServer) {
 
 	// 节点问题排查提示词
 	s.AddPrompt(mcp.NewPrompt(TROUBLESHOOT_NODES_PROMPT,
-		mcp.WithPromptDescription("提供全面的Kubernetes节点问题排查指南。基于节点状态和条件信息，分析节点层面的问题，包括资源压力、系统故障、网络异常等。提供系统化的诊断步骤和解决方案。"),
+		mcp.WithPromptDescription("提供全面的Kubernetes节点问题排查指南。先通过GET_NODE_HEALTH获取节点的条件、资源、事件和使用量等实际数据，再分析节点层面的问题，包括资源压力、系统故障、网络异常等。提供系统化的诊断步骤和解决方案。"),
 		mcp.WithArgument("node_status",
-			mcp.ArgumentDescription("节点的当前状态。典型状态包括：\n- Ready：节点正常运行\n- NotReady：节点异常\n- MemoryPressure：内存压力\n- DiskPressure：磁盘压力\n- NetworkUnavailable：网络异常\n- PIDPressure：进程数量压力\n状态信息反映了节点的健康状况和可用性。"),
-			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("节点的当前状态。典型状态包括：\n- Ready：节点正常运行\n- NotReady：节点异常\n- MemoryPressure：内存压力\n- DiskPressure：磁盘压力\n- NetworkUnavailable：网络异常\n- PIDPressure：进程数量压力\n状态信息反映了节点的健康状况和可用性。可选，未提供时以GET_NODE_HEALTH的结果为准。"),
 		),
 		mcp.WithArgument("node_conditions",
 			mcp.ArgumentDescription("节点的详细状况信息。建议包含：\n- 各个条件的状态（True/False/Unknown）\n- 最后一次转换时间\n- 状态持续时间\n- 具体的错误信息或警告\n- 系统资源使用情况\n这些信息有助于深入分析节点问题。"),
 		),
+		mcp.WithArgument("node_name",
+			mcp.ArgumentDescription("出现问题的节点名称（可选）。指定后只检查该节点，未指定时检查所有节点并从判定不是healthy的节点开始排查。"),
+		),
 	), h.TroubleshootNodesPrompt)
 
 	// 同时将节点问题排查提示词作为工具注册
 	s.AddTool(mcp.NewTool(TROUBLESHOOT_NODES_PROMPT,
-		mcp.WithDescription("提供全面的Kubernetes节点问题排查指南。基于节点状态和条件信息，分析节点层面的问题，包括资源压力、系统故障、网络异常等。提供系统化的诊断步骤和解决方案。"),
+		mcp.WithDescription("提供全面的Kubernetes节点问题排查指南。先通过GET_NODE_HEALTH获取节点的条件、资源、事件和使用量等实际数据，再分析节点层面的问题，包括资源压力、系统故障、网络异常等。提供系统化的诊断步骤和解决方案。"),
 		mcp.WithString("node_status",
-			mcp.Description("节点的当前状态。典型状态包括：\n- Ready：节点正常运行\n- NotReady：节点异常\n- MemoryPressure：内存压力\n- DiskPressure：磁盘压力\n- NetworkUnavailable：网络异常\n- PIDPressure：进程数量压力\n状态信息反映了节点的健康状况和可用性。"),
-			mcp.Required(),
+			mcp.Description("节点的当前状态。典型状态包括：\n- Ready：节点正常运行\n- NotReady：节点异常\n- MemoryPressure：内存压力\n- DiskPressure：磁盘压力\n- NetworkUnavailable：网络异常\n- PIDPressure：进程数量压力\n状态信息反映了节点的健康状况和可用性。可选，未提供时以GET_NODE_HEALTH的结果为准。"),
 		),
 		mcp.WithString("node_conditions",
 			mcp.Description("节点的详细状况信息。建议包含：\n- 各个条件的状态（True/False/Unknown）\n- 最后一次转换时间\n- 状态持续时间\n- 具体的错误信息或警告\n- 系统资源使用情况\n这些信息有助于深入分析节点问题。"),
 		),
+		mcp.WithString("node_name",
+			mcp.Description("出现问题的节点名称（可选）。指定后只检查该节点，未指定时检查所有节点并从判定不是healthy的节点开始排查。"),
+		),
 	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		return h.handleTroubleshootNodesPrompt(ctx, request)
 	})
@@ -418,6 +422,11 @@ func (h *PromptHandler) TroubleshootPodsPrompt(ctx context.Context, request mcp.
 func (h *PromptHandler) TroubleshootNodesPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 	h.Log.Info("生成节点问题排查提示词")
 
+	userText := "我的Kubernetes节点出现问题，需要帮助诊断和修复。"
+	if nodeName := request.Params.Arguments["node_name"]; nodeName != "" {
+		userText = fmt.Sprintf("我的Kubernetes节点%s出现问题，需要帮助诊断和修复。", nodeName)
+	}
+
 	return mcp.NewGetPromptResult(
 		"Kubernetes节点问题排查",
 		[]mcp.PromptMessage{
@@ -427,11 +436,11 @@ func (h *PromptHandler) TroubleshootNodesPrompt(ctx context.Context, request mcp
 			),
 			mcp.NewPromptMessage(
 				"user",
-				mcp.NewTextContent("我的Kubernetes节点出现问题，需要帮助诊断和修复。"),
+				mcp.NewTextContent(userText),
 			),
 			mcp.NewPromptMessage(
 				"assistant",
-				mcp.NewTextContent("我会帮你诊断和解决节点问题。为了准确分析，请提供以下信息：\n\n1. 节点状态信息：\n   - 当前状态\n   - 资源使用情况\n   - 系统负载\n   - 磁盘使用\n2. 系统信息：\n   - 操作系统版本\n   - 内核版本\n   - 系统日志\n   - 组件状态\n3. 问题描述：\n   - 具体症状\n   - 发生时间\n   - 影响范围\n   - 最近的变更\n\n我会提供：\n- 系统性的排查方法\n- 具体的诊断步骤\n- 修复建议\n- 性能优化方案\n- 预防措施建议"),
+				mcp.NewTextContent("我会帮你诊断和解决节点问题。首先调用GET_NODE_HEALTH获取节点的实际数据，包括：\n\n1. 节点状态信息：\n   - Ready及各压力条件的状态和变化时间\n   - 资源容量、可分配量和已请求量\n   - 已调度Pod数与Pod容量\n   - metrics-server提供的CPU和内存使用量\n2. 系统信息：\n   - 操作系统镜像\n   - 内核版本\n   - kubelet和容器运行时版本\n   - 节点近期事件（kubelet重启、镜像回收、驱逐告警等）\n\n然后根据健康判定及其原因、警告定位问题。工具无法获取的信息需要你补充：\n\n1. 问题描述：\n   - 具体症状\n   - 发生时间\n   - 影响范围\n   - 最近的变更\n\n我会提供：\n- 系统性的排查方法\n- 具体的诊断步骤\n- 修复建议\n- 性能优化方案\n- 预防措施建议"),
 			),
 		},
 	), nil
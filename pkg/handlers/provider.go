@@ -1,16 +1,23 @@
 package handlers
 
 import (
+	"sort"
+
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
 	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
 // HandlerProviderImpl 实现HandlerProvider接口
 type HandlerProviderImpl struct {
 	handlers []interfaces.ToolHandler
+	filter   ToolFilter
 }
 
 // 确保实现了接口
@@ -25,23 +32,152 @@ func (p *HandlerProviderImpl) GetHandlers() []interfaces.ToolHandler {
 func (p *HandlerProviderImpl) RegisterAllHandlers(server *server.MCPServer) {
 	log := logger.GetLogger()
 
-	// 注册所有处理程序
+	// 注册启用的处理程序，处理程序内的单个工具由filteringRegistrar按配置过滤
+	registrar := &filteringRegistrar{server: server, filter: p.filter}
+	knownGroups := make(map[string]bool)
+	for _, handler := range p.handlers {
+		group := handler.GetAPIGroup()
+		knownGroups[string(group)] = true
+		if !p.filter.GroupEnabled(group) {
+			log.Info("Tool group disabled by configuration", "group", group)
+			continue
+		}
+		handler.Register(registrar)
+	}
+	for _, group := range p.filter.EnabledGroups {
+		if !knownGroups[group] {
+			log.Warn("Unknown tool group in enabled tool groups", "group", group)
+		}
+	}
+
+	sort.Strings(registrar.tools)
+	base.SetRegisteredTools(registrar.tools)
+	log.Info("All handlers registered",
+		"toolCount", len(registrar.tools),
+		"tools", registrar.tools,
+		"excluded", registrar.excluded,
+	)
+}
+
+// RegisterAllHooks 实现接口方法
+func (p *HandlerProviderImpl) RegisterAllHooks(hooks *server.Hooks) {
 	for _, handler := range p.handlers {
-		handler.Register(server)
+		if !p.filter.GroupEnabled(handler.GetAPIGroup()) {
+			continue
+		}
+		if registrar, ok := handler.(interfaces.HookRegistrar); ok {
+			registrar.RegisterHooks(hooks)
+		}
 	}
+}
 
-	log.Info("All handlers registered")
+// ApplyReloadedConfig 将重新加载后可在运行时修改的设置应用到处理程序：输出上限和GET_SERVER_STATUS报告的配置。
+// 日志级别由调用方设置，只读模式和命名空间白名单由中间件从配置快照读取
+func ApplyReloadedConfig(cfg *config.Config) {
+	utils.SetMaxResultBytes(cfg.MaxResultBytes)
+	base.UpdateOptions(func(o *base.Options) {
+		o.MaxListItems = cfg.MaxListItems
+		o.MaxCopyBytes = cfg.MaxCopyBytes
+		o.BackupInlineLimit = cfg.BackupInlineLimit
+		o.Settings.MaxListItems = cfg.MaxListItems
+		o.Settings.MaxCopyBytes = cfg.MaxCopyBytes
+		o.Settings.MaxResultBytes = cfg.MaxResultBytes
+		o.Settings.ReadOnly = cfg.ReadOnly
+		o.Settings.AllowedNamespaces = utils.SplitCommaList(cfg.AllowedNamespaces)
+	})
 }
 
 // NewHandlerProvider 创建新的处理程序提供者
-func NewHandlerProvider() interfaces.HandlerProvider {
+func NewHandlerProvider(cfg *config.Config) interfaces.HandlerProvider {
 	k8sClient := kubernetes.GetClient()
 
-	// 使用工厂创建所有处理程序
-	factory := NewHandlerFactory(k8sClient)
+	// 加载命名空间模板，读取失败时仅使用内置模板
+	namespacePresets, err := utils.LoadNamespacePresets(cfg.NamespacePresetsFile)
+	if err != nil {
+		logger.GetLogger().Warn("Failed to load namespace presets, using built-in presets", "error", err)
+	}
+
+	filter := ToolFilter{
+		EnabledGroups: utils.SplitCommaList(cfg.EnabledToolGroups),
+		DisabledTools: utils.SplitCommaList(cfg.DisabledTools),
+	}
+
+	// 设置工具响应的输出上限
+	utils.SetMaxResultBytes(cfg.MaxResultBytes)
 
-	// 按照API组和Version组织处理程序
-	handlers := []interfaces.ToolHandler{
+	// 设置会话笔记的容量和持久化文件
+	if err := utils.SetNoteStoreOptions(utils.NoteStoreOptions{
+		MaxNotes:     cfg.MaxNotes,
+		MaxNoteBytes: cfg.MaxNoteBytes,
+		File:         cfg.NotesFile,
+	}); err != nil {
+		logger.GetLogger().Warn("Failed to load session notes, starting with an empty note store", "error", err)
+	}
+
+	// 通用资源工具的命名方式，无法识别时使用平铺名称
+	toolNaming, err := base.ParseToolNaming(cfg.ToolNaming)
+	if err != nil {
+		logger.GetLogger().Warn("Invalid tool naming, using flat tool names", "error", err)
+	}
+
+	// 设置处理程序的全局选项
+	base.SetOptions(base.Options{
+		PreflightAuthz:      cfg.PreflightAuthz,
+		AllowSecretValues:   cfg.AllowSecretValues,
+		AllowExec:           cfg.AllowExec,
+		MaxCopyBytes:        cfg.MaxCopyBytes,
+		BackupDir:           cfg.BackupDir,
+		BackupInlineLimit:   cfg.BackupInlineLimit,
+		MaxListItems:        cfg.MaxListItems,
+		KustomizeRoot:       cfg.KustomizeRoot,
+		ManifestURLPrefixes: utils.SplitCommaList(cfg.ManifestURLPrefixes),
+		MaxManifestURLBytes: cfg.MaxManifestURLBytes,
+		ResourceKinds:       utils.SplitCommaList(cfg.ResourceKinds),
+		ResourceNamespaces:  utils.SplitCommaList(cfg.ResourceNamespaces),
+		ResourceMaxBytes:    cfg.ResourceMaxBytes,
+		NamespacePresets:    namespacePresets,
+		ToolNaming:          toolNaming,
+		LegacyToolAliases:   cfg.LegacyToolAliases,
+		Retry: utils.RetryOptions{
+			MaxRetries:     cfg.MaxRetries,
+			InitialBackoff: cfg.RetryInitialBackoff,
+			MaxBackoff:     cfg.RetryMaxBackoff,
+		},
+		Settings: models.ServerSettings{
+			Transport:             cfg.Transport,
+			PreflightAuthz:        cfg.PreflightAuthz,
+			AllowSecretValues:     cfg.AllowSecretValues,
+			AllowExec:             cfg.AllowExec,
+			MaxCopyBytes:          cfg.MaxCopyBytes,
+			MaxListItems:          cfg.MaxListItems,
+			MaxResultBytes:        cfg.MaxResultBytes,
+			MaxRetries:            cfg.MaxRetries,
+			ToolTimeoutSeconds:    cfg.ToolTimeoutSeconds,
+			MaxToolTimeoutSeconds: cfg.MaxToolTimeoutSeconds,
+			DiscoveryCacheTTL:     cfg.DiscoveryCacheTTL.String(),
+			MaxNotes:              utils.Notes().Limit(),
+			MaxNoteBytes:          cfg.MaxNoteBytes,
+			NotesPersisted:        cfg.NotesFile != "",
+			ToolHistorySize:       max(cfg.ToolHistorySize, 0),
+			EnabledToolGroups:     filter.EnabledGroups,
+			DisabledTools:         filter.DisabledTools,
+			ToolNaming:            toolNaming,
+			LegacyToolAliases:     toolNaming == base.ToolNamingFlat && cfg.LegacyToolAliases,
+			ConfigFile:            cfg.ConfigFile,
+			ReadOnly:              cfg.ReadOnly,
+			AllowedNamespaces:     utils.SplitCommaList(cfg.AllowedNamespaces),
+		},
+	})
+
+	return &HandlerProviderImpl{
+		handlers: newHandlers(NewHandlerFactory(k8sClient)),
+		filter:   filter,
+	}
+}
+
+// newHandlers 使用工厂创建所有处理程序，按照API组和Version组织
+func newHandlers(factory interfaces.HandlerFactory) []interfaces.ToolHandler {
+	return []interfaces.ToolHandler{
 		// 集群级别资源
 		factory.CreateNamespaceHandler(), // 集群作用域, v1 (core)
 		factory.CreateNodeHandler(),      // 集群作用域, v1 (core)
@@ -81,9 +217,8 @@ func NewHandlerProvider() interfaces.HandlerProvider {
 
 		// 指标处理程序
 		factory.CreateMetricsHandler(),
-	}
 
-	return &HandlerProviderImpl{
-		handlers: handlers,
+		// MCP资源处理程序
+		factory.CreateResourceProviderHandler(),
 	}
 }
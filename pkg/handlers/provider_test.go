@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// registerTools 使用fake客户端和默认配置的工具命名方式创建所有处理程序并按filter注册，返回MCP服务器和实际注册的工具
+func registerTools(t *testing.T, filter ToolFilter) (*server.MCPServer, []string) {
+	t.Helper()
+	previous := base.GetOptions()
+	t.Cleanup(func() {
+		base.SetOptions(previous)
+		base.SetRegisteredTools(nil)
+	})
+	defaults := config.NewDefaultConfig()
+	toolNaming, err := base.ParseToolNaming(defaults.ToolNaming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base.SetOptions(base.Options{ToolNaming: toolNaming, LegacyToolAliases: defaults.LegacyToolAliases})
+
+	mcpServer := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true), server.WithPromptCapabilities(true))
+	provider := &HandlerProviderImpl{handlers: newHandlers(NewHandlerFactory(testutil.NewFakeClient())), filter: filter}
+	provider.RegisterAllHandlers(mcpServer)
+	return mcpServer, base.RegisteredTools()
+}
+
+// TestToolInventory 注册的工具名称是客户端依赖的接口，增删或重命名工具时需同步更新testdata/tools.golden
+func TestToolInventory(t *testing.T) {
+	_, tools := registerTools(t, ToolFilter{})
+
+	if duplicates := len(tools) - len(slices.Compact(slices.Clone(tools))); duplicates > 0 {
+		t.Errorf("%d tool names are registered more than once", duplicates)
+	}
+	got := strings.Join(tools, "\n") + "\n"
+	path := filepath.Join("testdata", "tools.golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create the golden file)", err)
+	}
+	if got != string(want) {
+		gotSet, wantSet := strings.Fields(got), strings.Fields(string(want))
+		var added, removed []string
+		for _, name := range gotSet {
+			if !slices.Contains(wantSet, name) {
+				added = append(added, name)
+			}
+		}
+		for _, name := range wantSet {
+			if !slices.Contains(gotSet, name) {
+				removed = append(removed, name)
+			}
+		}
+		t.Fatalf("registered tools changed: added %v, removed %v", added, removed)
+	}
+}
+
+// mcpCall 向MCP服务器发送JSON-RPC请求并返回响应
+func mcpCall(t *testing.T, mcpServer *server.MCPServer, method string, params any) mcp.JSONRPCMessage {
+	t.Helper()
+	message, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mcpServer.HandleMessage(context.Background(), message)
+}
+
+func TestDisabledToolsAreNotListedOrCallable(t *testing.T) {
+	filter := ToolFilter{DisabledTools: []string{"DELETE_*", "GET_CURRENT_TIME", "batch"}}
+	mcpServer, tools := registerTools(t, filter)
+
+	response, ok := mcpCall(t, mcpServer, "tools/list", map[string]any{}).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatal("tools/list failed")
+	}
+	listed := make(map[string]bool)
+	for _, tool := range response.Result.(mcp.ListToolsResult).Tools {
+		listed[tool.Name] = true
+	}
+	if len(listed) != len(tools) {
+		t.Fatalf("tools/list returned %d tools, %d were registered", len(listed), len(tools))
+	}
+	for _, name := range []string{"DELETE_RESOURCE", "DELETE_NAMESPACE", "GET_CURRENT_TIME", "SUSPEND_CRONJOB"} {
+		if listed[name] {
+			t.Errorf("disabled tool %s is listed", name)
+		}
+	}
+	if !listed["LIST_RESOURCES"] {
+		t.Error("LIST_RESOURCES is not listed")
+	}
+
+	call := mcpCall(t, mcpServer, "tools/call", map[string]any{"name": "GET_CURRENT_TIME", "arguments": map[string]any{}})
+	if _, ok := call.(mcp.JSONRPCError); !ok {
+		t.Fatalf("disabled tool was callable: %+v", call)
+	}
+	call = mcpCall(t, mcpServer, "tools/call", map[string]any{"name": "GET_NOTES", "arguments": map[string]any{}})
+	if _, ok := call.(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("enabled tool was not callable: %+v", call)
+	}
+}
+
+func TestToolFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ToolFilter
+		group  interfaces.APIGroup
+		tool   string
+		want   bool
+	}{
+		{name: "no filter", group: interfaces.AppsAPIGroup, tool: "SCALE_DEPLOYMENT", want: true},
+		{name: "enabled group", filter: ToolFilter{EnabledGroups: []string{"apps"}}, group: interfaces.AppsAPIGroup, tool: "SCALE_DEPLOYMENT", want: true},
+		{name: "group not enabled", filter: ToolFilter{EnabledGroups: []string{"apps"}}, group: interfaces.BatchAPIGroup, tool: "LIST_JOBS"},
+		{name: "disabled group", filter: ToolFilter{DisabledTools: []string{"batch"}}, group: interfaces.BatchAPIGroup, tool: "LIST_JOBS"},
+		{name: "exact tool", filter: ToolFilter{DisabledTools: []string{"LIST_JOBS"}}, group: interfaces.BatchAPIGroup, tool: "LIST_JOBS"},
+		{name: "glob", filter: ToolFilter{DisabledTools: []string{"*_JOBS"}}, group: interfaces.BatchAPIGroup, tool: "LIST_JOBS"},
+		{name: "glob does not match", filter: ToolFilter{DisabledTools: []string{"DELETE_*"}}, group: interfaces.BatchAPIGroup, tool: "LIST_JOBS", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.GroupEnabled(tt.group) && tt.filter.ToolEnabled(tt.tool); got != tt.want {
+				t.Fatalf("enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
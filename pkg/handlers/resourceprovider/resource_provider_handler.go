@@ -0,0 +1,380 @@
+package resourceprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// ObjectURITemplate 资源对象的URI模板
+	ObjectURITemplate = "k8s://{namespace}/{kind}/{name}"
+	// PodLogsURITemplate Pod日志的URI模板
+	PodLogsURITemplate = "k8s-logs://{namespace}/{pod}{?container}"
+
+	objectScheme  = "k8s"
+	podLogsScheme = "k8s-logs"
+	yamlMIMEType  = "application/yaml"
+	textMIMEType  = "text/plain"
+
+	// listPageSize resources/list单页返回的最大资源数量
+	listPageSize = 100
+	// podLogTailLines 读取Pod日志时返回的最近行数
+	podLogTailLines int64 = 1000
+)
+
+// exposedKind 以MCP资源形式公开的资源类型
+type exposedKind struct {
+	// resource 资源复数名，用于URI中的kind段
+	resource string
+	kind     string
+	gvr      schema.GroupVersionResource
+}
+
+// listCursor resources/list的分页游标，按资源类型和命名空间依次遍历
+type listCursor struct {
+	Kind      int    `json:"k"`
+	Namespace int    `json:"n"`
+	Continue  string `json:"c,omitempty"`
+}
+
+// ResourceProviderHandler 以MCP资源形式公开只读的集群数据
+// 资源对象的URI为k8s://{namespace}/{kind}/{name}，Pod日志的URI为k8s-logs://{namespace}/{pod}?container=x，
+// 公开的资源类型和命名空间由--resource-kinds和--resource-namespaces限制
+type ResourceProviderHandler struct {
+	base.Handler
+}
+
+// 确保实现了接口
+var _ interfaces.ToolHandler = (*ResourceProviderHandler)(nil)
+var _ interfaces.HookRegistrar = (*ResourceProviderHandler)(nil)
+
+// NewResourceProviderHandler 创建新的MCP资源处理程序
+func NewResourceProviderHandler(client kubernetes.Client) *ResourceProviderHandler {
+	return &ResourceProviderHandler{
+		Handler: base.NewHandler(client, interfaces.NamespaceScope, interfaces.MCPResource),
+	}
+}
+
+// Handle 实现ToolHandler接口，MCP资源处理程序不提供工具
+func (h *ResourceProviderHandler) Handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return utils.NewErrorToolResult(fmt.Sprintf("unknown tool: %s", request.Params.Name)), nil
+}
+
+// Register 注册资源模板，resources/read按模板分发到对应的读取函数
+func (h *ResourceProviderHandler) Register(server interfaces.ToolRegistrar) {
+	server.AddResourceTemplate(mcp.NewResourceTemplate(ObjectURITemplate, "Kubernetes object",
+		mcp.WithTemplateDescription("以YAML格式读取命名空间中的资源对象，kind为资源复数名（例如pods、deployments）。Secret的值会被隐藏。"),
+		mcp.WithTemplateMIMEType(yamlMIMEType),
+	), h.ReadObject)
+
+	server.AddResourceTemplate(mcp.NewResourceTemplate(PodLogsURITemplate, "Pod logs",
+		mcp.WithTemplateDescription("读取Pod最近的日志，多容器Pod需通过container参数指定容器。"),
+		mcp.WithTemplateMIMEType(textMIMEType),
+	), h.ReadPodLogs)
+}
+
+// RegisterHooks 实现HookRegistrar接口
+// mcp-go的resources/list只返回静态注册的资源，这里在列表结果中追加集群中的资源对象
+func (h *ResourceProviderHandler) RegisterHooks(hooks *server.Hooks) {
+	hooks.AddAfterListResources(h.listResources)
+}
+
+// listResources 按资源类型和命名空间分页列出公开的资源对象，追加到resources/list的结果中
+func (h *ResourceProviderHandler) listResources(
+	ctx context.Context,
+	id any,
+	request *mcp.ListResourcesRequest,
+	result *mcp.ListResourcesResult,
+) {
+	var cursor listCursor
+	if request.Params.Cursor != "" {
+		data, err := base64.StdEncoding.DecodeString(string(request.Params.Cursor))
+		if err == nil {
+			err = json.Unmarshal(data, &cursor)
+		}
+		if err != nil {
+			h.Log.Warn("Invalid resources/list cursor", "id", id, "error", err)
+			return
+		}
+	}
+
+	kinds := h.exposedKinds()
+	namespaces, restricted := resourceNamespaces(ctx)
+	if !restricted {
+		// 空字符串表示所有命名空间
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	if len(namespaces) == 0 {
+		// --resource-namespaces中没有白名单允许的命名空间
+		return
+	}
+	if cursor.Namespace >= len(namespaces) {
+		cursor = listCursor{Kind: cursor.Kind + 1}
+	}
+
+	advance := func() {
+		cursor.Continue = ""
+		cursor.Namespace++
+		if cursor.Namespace == len(namespaces) {
+			cursor.Namespace = 0
+			cursor.Kind++
+		}
+	}
+
+	dynamicClient := h.Client.GetDynamicClient()
+	for cursor.Kind < len(kinds) && len(result.Resources) < listPageSize {
+		kind := kinds[cursor.Kind]
+		list, err := dynamicClient.Resource(kind.gvr).Namespace(namespaces[cursor.Namespace]).List(ctx, metav1.ListOptions{
+			Limit:    int64(listPageSize - len(result.Resources)),
+			Continue: cursor.Continue,
+		})
+		if err != nil {
+			h.Log.Warn("Failed to list resources, skipping",
+				"resource", kind.gvr.String(),
+				"namespace", namespaces[cursor.Namespace],
+				"error", err,
+			)
+			advance()
+			continue
+		}
+		for _, item := range list.Items {
+			uri := objectURI(item.GetNamespace(), kind.resource, item.GetName())
+			result.Resources = append(result.Resources, mcp.NewResource(uri,
+				fmt.Sprintf("%s %s/%s", kind.kind, item.GetNamespace(), item.GetName()),
+				mcp.WithMIMEType(yamlMIMEType),
+			))
+		}
+		cursor.Continue = list.GetContinue()
+		if cursor.Continue == "" {
+			advance()
+		}
+	}
+
+	result.NextCursor = ""
+	if cursor.Kind < len(kinds) {
+		data, _ := json.Marshal(cursor)
+		result.NextCursor = mcp.Cursor(base64.StdEncoding.EncodeToString(data))
+	}
+}
+
+// ReadObject 读取k8s://{namespace}/{kind}/{name}对应的资源对象并返回YAML
+func (h *ResourceProviderHandler) ReadObject(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	namespace, kindName, name, err := parseObjectURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
+	kind, err := h.lookupKind(kindName)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Log.Info("Reading resource", "uri", uri)
+	obj, err := h.Client.GetDynamicClient().Resource(kind.gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", kind.kind, namespace, name, err)
+	}
+	obj.SetManagedFields(nil)
+	// 与工具一致，Secret的值始终被隐藏
+	utils.RedactSecret(obj)
+
+	data, err := utils.MarshalManifest([]*unstructured.Unstructured{obj})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s %s/%s: %w", kind.kind, namespace, name, err)
+	}
+	if maxBytes := base.GetOptions().ResourceMaxBytes; maxBytes > 0 && len(data) > maxBytes {
+		return nil, fmt.Errorf("%s %s/%s is %d bytes, exceeding the resource size limit of %d bytes; read it with the corresponding GET tool instead",
+			kind.kind, namespace, name, len(data), maxBytes)
+	}
+
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: yamlMIMEType,
+		Text:     data,
+	}}, nil
+}
+
+// ReadPodLogs 读取k8s-logs://{namespace}/{pod}?container=x对应的Pod日志
+func (h *ResourceProviderHandler) ReadPodLogs(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	namespace, pod, container, err := parsePodLogsURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
+	// Pod日志跟随Pod的公开设置
+	if _, err := h.lookupKind("pods"); err != nil {
+		return nil, err
+	}
+
+	h.Log.Info("Reading pod logs resource", "uri", uri)
+	tailLines := podLogTailLines
+	options := &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}
+	maxBytes := int64(base.GetOptions().ResourceMaxBytes)
+	if maxBytes > 0 {
+		options.LimitBytes = &maxBytes
+	}
+	data, err := h.Client.ClientSet().CoreV1().Pods(namespace).GetLogs(pod, options).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of pod %s/%s: %w", namespace, pod, err)
+	}
+	text := string(data)
+	if maxBytes > 0 && int64(len(data)) >= maxBytes {
+		text += fmt.Sprintf("\n... (truncated at %d bytes)\n", maxBytes)
+	}
+
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: textMIMEType,
+		Text:     text,
+	}}, nil
+}
+
+// exposedKinds 解析--resource-kinds中的资源类型，跳过集群中不存在或非命名空间级别的类型
+func (h *ResourceProviderHandler) exposedKinds() []exposedKind {
+	var kinds []exposedKind
+	seen := make(map[schema.GroupVersionResource]bool)
+	for _, name := range base.GetOptions().ResourceKinds {
+		mappings, err := utils.ResolveKind(h.Client, name)
+		if err != nil {
+			h.Log.Warn("Failed to resolve resource kind, skipping", "kind", name, "error", err)
+			continue
+		}
+		for _, mapping := range mappings {
+			if !utils.IsNamespacedMapping(mapping) || seen[mapping.Resource] {
+				continue
+			}
+			seen[mapping.Resource] = true
+			kinds = append(kinds, exposedKind{
+				resource: mapping.Resource.Resource,
+				kind:     mapping.GroupVersionKind.Kind,
+				gvr:      mapping.Resource,
+			})
+			break
+		}
+	}
+	return kinds
+}
+
+// lookupKind 在公开的资源类型中查找URI中的kind段，接受资源复数名或类型名
+func (h *ResourceProviderHandler) lookupKind(name string) (exposedKind, error) {
+	kinds := h.exposedKinds()
+	var allowed []string
+	for _, kind := range kinds {
+		if kind.resource == name || strings.EqualFold(kind.kind, name) {
+			return kind, nil
+		}
+		allowed = append(allowed, kind.resource)
+	}
+	return exposedKind{}, fmt.Errorf("kind %q is not exposed as a resource, exposed kinds: %s", name, strings.Join(allowed, ", "))
+}
+
+// checkNamespace 检查命名空间是否在--resource-namespaces中并且被命名空间白名单允许
+func checkNamespace(ctx context.Context, namespace string) error {
+	namespaces, restricted := resourceNamespaces(ctx)
+	if restricted && !slices.Contains(namespaces, namespace) {
+		return fmt.Errorf("namespace %q is not exposed as a resource, exposed namespaces: %s", namespace, strings.Join(namespaces, ", "))
+	}
+	return nil
+}
+
+// resourceNamespaces 返回公开为资源的命名空间，即--resource-namespaces与命名空间白名单（--allowed-namespaces）的交集。
+// resources/list和resources/read不经过工具中间件，在这里执行白名单；restricted为false表示所有命名空间
+func resourceNamespaces(ctx context.Context) (namespaces []string, restricted bool) {
+	namespaces = base.GetOptions().ResourceNamespaces
+	allowed := config.FromContext(ctx).AllowedNamespaceList()
+	switch {
+	case len(allowed) == 0:
+		return namespaces, len(namespaces) > 0
+	case len(namespaces) == 0:
+		return allowed, true
+	}
+	var both []string
+	for _, namespace := range namespaces {
+		if slices.Contains(allowed, namespace) {
+			both = append(both, namespace)
+		}
+	}
+	return both, true
+}
+
+// objectURI 构建资源对象的URI
+func objectURI(namespace, resource, name string) string {
+	return fmt.Sprintf("%s://%s/%s/%s", objectScheme, namespace, resource, name)
+}
+
+// parseObjectURI 解析k8s://{namespace}/{kind}/{name}
+func parseObjectURI(uri string) (namespace, kind, name string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != objectScheme {
+		return "", "", "", fmt.Errorf("invalid resource URI %q, expected %s", uri, ObjectURITemplate)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", "", "", fmt.Errorf("invalid resource URI %q: query parameters are not supported", uri)
+	}
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		return "", "", "", fmt.Errorf("invalid resource URI %q, expected %s", uri, ObjectURITemplate)
+	}
+	if err := validateNamespace(u.Host); err != nil {
+		return "", "", "", err
+	}
+	return u.Host, segments[0], segments[1], nil
+}
+
+// parsePodLogsURI 解析k8s-logs://{namespace}/{pod}?container=x
+func parsePodLogsURI(uri string) (namespace, pod, container string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != podLogsScheme {
+		return "", "", "", fmt.Errorf("invalid pod logs URI %q, expected %s", uri, PodLogsURITemplate)
+	}
+	pod = strings.TrimPrefix(u.Path, "/")
+	if pod == "" || strings.Contains(pod, "/") {
+		return "", "", "", fmt.Errorf("invalid pod logs URI %q, expected %s", uri, PodLogsURITemplate)
+	}
+	if err := validateNamespace(u.Host); err != nil {
+		return "", "", "", err
+	}
+	query := u.Query()
+	for key := range query {
+		if key != "container" {
+			return "", "", "", fmt.Errorf("invalid pod logs URI %q: unsupported query parameter %q", uri, key)
+		}
+	}
+	return u.Host, pod, query.Get("container"), nil
+}
+
+// validateNamespace 检查URI中的命名空间是否为合法的DNS标签
+func validateNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q in resource URI: %s", namespace, strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package tool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// maxManifestRedirects 获取清单时最多跟随的重定向次数
+const maxManifestRedirects = 3
+
+// manifestContentTypes 可作为清单接受的Content-Type，未返回Content-Type时也接受
+var manifestContentTypes = map[string]bool{
+	"text/plain":               true,
+	"text/yaml":                true,
+	"text/x-yaml":              true,
+	"application/yaml":         true,
+	"application/x-yaml":       true,
+	"application/json":         true,
+	"application/octet-stream": true,
+}
+
+// manifestFetchError 获取清单失败，Code和Hint直接用于返回给调用方的结构化错误
+type manifestFetchError struct {
+	code    string
+	message string
+	hint    string
+}
+
+func (e *manifestFetchError) Error() string {
+	return e.message
+}
+
+// ApplyFromURL 从允许的https地址获取清单，可选校验sha256，然后像APPLY_MANIFEST一样应用
+func (h *UtilityHandler) ApplyFromURL(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	log := logger.FromContext(ctx)
+	arguments := request.GetArguments()
+	rawURL, _ := arguments["url"].(string)
+	ref, _ := arguments["ref"].(string)
+	filePath, _ := arguments["path"].(string)
+	checksum, _ := arguments["sha256"].(string)
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+
+	log.Info("Applying manifest from URL", "url", rawURL, "ref", ref, "path", filePath)
+
+	if rawURL == "" {
+		return utils.NewErrorToolResult("url is required"), nil
+	}
+	if checksum != "" {
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			return utils.NewErrorToolResult(fmt.Sprintf("invalid sha256 %q: must be 64 hexadecimal characters", checksum)), nil
+		}
+	}
+
+	options := base.GetOptions()
+	if len(options.ManifestURLPrefixes) == 0 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeRefused,
+			Message: "applying manifests from URLs is disabled on this server",
+			Hint:    "Start the server with --manifest-url-prefixes listing the allowed https URL prefixes, or pass the manifest inline to APPLY_MANIFEST.",
+		}), nil
+	}
+
+	target, err := manifestURL(rawURL, ref, filePath)
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	if err := checkManifestURL(target, options.ManifestURLPrefixes); err != nil {
+		return manifestFetchErrorResult(err), nil
+	}
+
+	content, source, err := fetchManifest(ctx, target, options.ManifestURLPrefixes, options.MaxManifestURLBytes)
+	if err != nil {
+		log.Warn("Failed to fetch manifest", "url", target, "error", err)
+		return manifestFetchErrorResult(err), nil
+	}
+	source.Ref = ref
+	source.Path = filePath
+
+	// 审计日志：记录实际应用的内容来源，使集群状态可以追溯到源版本
+	log.Info("Fetched manifest from URL",
+		"url", source.URL,
+		"finalURL", source.FinalURL,
+		"sha256", source.SHA256,
+		"bytes", source.Bytes,
+	)
+
+	if checksum != "" {
+		if checksum != source.SHA256 {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: fmt.Sprintf("checksum mismatch: expected sha256 %s, fetched content has %s", checksum, source.SHA256),
+				Hint:    "The content at the URL changed or the checksum is wrong; nothing was applied. Pin the URL to a commit instead of a branch to get stable content.",
+				Details: source,
+			}), nil
+		}
+		source.ChecksumVerified = true
+	}
+	if strings.TrimSpace(content) == "" {
+		return utils.NewErrorToolResult(fmt.Sprintf("manifest fetched from %s is empty", source.URL)), nil
+	}
+	return h.applyManifest(ctx, request, content, source), nil
+}
+
+// manifestURL 返回要获取的地址。指定ref和path时，将GitHub或GitLab仓库地址转换为原始文件地址
+func manifestURL(rawURL, ref, filePath string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %v", rawURL, err)
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("invalid url %q: only https URLs are supported", rawURL)
+	}
+	if parsed.User != nil {
+		return "", fmt.Errorf("invalid url %q: credentials in the URL are not supported", rawURL)
+	}
+	if ref == "" && filePath == "" {
+		return parsed.String(), nil
+	}
+	if ref == "" || filePath == "" {
+		return "", fmt.Errorf("ref and path must be specified together")
+	}
+
+	repo := strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+	rawPath := escapePathSegments(ref) + "/" + escapePathSegments(strings.TrimLeft(filePath, "/"))
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "github.com":
+		if strings.Count(repo, "/") != 1 {
+			return "", fmt.Errorf("url %q is not a GitHub repository URL of the form https://github.com/<owner>/<repo>", rawURL)
+		}
+		return "https://raw.githubusercontent.com/" + repo + "/" + rawPath, nil
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		if !strings.Contains(repo, "/") || strings.Contains(repo, "/-/") {
+			return "", fmt.Errorf("url %q is not a GitLab project URL of the form https://%s/<group>/<project>", rawURL, parsed.Host)
+		}
+		return "https://" + parsed.Host + "/" + repo + "/-/raw/" + rawPath, nil
+	}
+	return "", fmt.Errorf("ref and path are only supported for GitHub and GitLab repository URLs, pass the raw file URL for %s", parsed.Host)
+}
+
+// escapePathSegments 逐段转义路径，保留分隔符
+func escapePathSegments(value string) string {
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// checkManifestURL 确认地址使用https且匹配允许的前缀
+func checkManifestURL(target string, prefixes []string) error {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return &manifestFetchError{
+			code:    utils.ErrorCodeRefused,
+			message: fmt.Sprintf("refusing to fetch %s: only https URLs are allowed", target),
+		}
+	}
+	for _, prefix := range prefixes {
+		if matchesURLPrefix(parsed, prefix) {
+			return nil
+		}
+	}
+	return &manifestFetchError{
+		code:    utils.ErrorCodeRefused,
+		message: fmt.Sprintf("url %s does not match any allowed prefix", target),
+		hint:    fmt.Sprintf("Allowed prefixes: %s. A prefix matches URLs on the same host whose path is the prefix path or below it. Ask the operator to extend --manifest-url-prefixes, or pass the manifest inline to APPLY_MANIFEST.", strings.Join(prefixes, ", ")),
+	}
+}
+
+// matchesURLPrefix 按解析后的各部分匹配前缀：协议和主机（含端口）必须完全相同，
+// 规范化后的路径必须等于前缀路径或位于其下，前缀只在"/"分隔的路径段边界上匹配，
+// 因此"https://host/org"不匹配"https://host/org-evil/x"，"https://host.evil"也不匹配"https://host"。
+// 带凭据、编码的路径分隔符或反斜杠的地址无法可靠地判断服务端看到的路径，一律不匹配
+func matchesURLPrefix(target *url.URL, prefix string) bool {
+	allowed, err := url.Parse(strings.TrimSpace(prefix))
+	if err != nil || allowed.Scheme != "https" || allowed.Host == "" || allowed.User != nil {
+		return false
+	}
+	if target.Scheme != allowed.Scheme || !strings.EqualFold(target.Host, allowed.Host) || target.User != nil {
+		return false
+	}
+	escaped := strings.ToLower(target.EscapedPath())
+	if strings.Contains(escaped, "%2f") || strings.Contains(escaped, "%5c") || strings.Contains(target.Path, "\\") {
+		return false
+	}
+	allowedPath := path.Clean("/" + allowed.Path)
+	targetPath := path.Clean("/" + target.Path)
+	if allowedPath == "/" {
+		return true
+	}
+	return targetPath == allowedPath || strings.HasPrefix(targetPath, allowedPath+"/")
+}
+
+// fetchManifest 获取清单内容，重定向次数有限且每次重定向的目标也必须在允许的前缀内，
+// 非200响应、非YAML的Content-Type以及超过大小上限的内容都作为错误返回
+func fetchManifest(ctx context.Context, target string, prefixes []string, maxBytes int) (string, *models.ManifestSource, error) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxManifestRedirects {
+				return &manifestFetchError{
+					code:    utils.ErrorCodeRefused,
+					message: fmt.Sprintf("stopped after %d redirects", maxManifestRedirects),
+				}
+			}
+			return checkManifestURL(req.URL.String(), prefixes)
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Accept", "application/yaml, text/yaml, text/plain;q=0.9, */*;q=0.1")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var fetchErr *manifestFetchError
+		if errors.As(err, &fetchErr) {
+			return "", nil, fetchErr
+		}
+		return "", nil, &manifestFetchError{
+			code:    utils.ErrorCodeUnavailable,
+			message: fmt.Sprintf("failed to fetch %s: %v", target, err),
+		}
+	}
+	defer resp.Body.Close()
+
+	source := &models.ManifestSource{
+		URL:         target,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if finalURL := resp.Request.URL.String(); finalURL != target {
+		source.FinalURL = finalURL
+	}
+	if resp.StatusCode != http.StatusOK {
+		fetchErr := &manifestFetchError{
+			code:    utils.ErrorCodeUnavailable,
+			message: fmt.Sprintf("fetching %s returned HTTP %s", target, resp.Status),
+		}
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusGone:
+			fetchErr.code = utils.ErrorCodeNotFound
+			fetchErr.hint = "Check the URL, ref and path; private repositories are not supported."
+		case http.StatusUnauthorized, http.StatusForbidden:
+			fetchErr.code = utils.ErrorCodeForbidden
+			fetchErr.hint = "The server does not send credentials; only publicly readable URLs can be fetched."
+		}
+		return "", nil, fetchErr
+	}
+	if source.ContentType != "" {
+		mediaType, _, err := mime.ParseMediaType(source.ContentType)
+		if err != nil || !manifestContentTypes[mediaType] {
+			return "", nil, &manifestFetchError{
+				code:    utils.ErrorCodeUnsupported,
+				message: fmt.Sprintf("%s returned content type %q, which is not a YAML manifest", target, source.ContentType),
+				hint:    "The URL likely points to a web page; use the raw file URL, or the repository URL with ref and path.",
+			}
+		}
+	}
+	if resp.ContentLength > int64(maxBytes) {
+		return "", nil, manifestTooLarge(target, maxBytes)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+	if err != nil {
+		return "", nil, &manifestFetchError{
+			code:    utils.ErrorCodeUnavailable,
+			message: fmt.Sprintf("failed to read %s: %v", target, err),
+		}
+	}
+	if len(body) > maxBytes {
+		return "", nil, manifestTooLarge(target, maxBytes)
+	}
+	sum := sha256.Sum256(body)
+	source.SHA256 = hex.EncodeToString(sum[:])
+	source.Bytes = len(body)
+	return string(body), source, nil
+}
+
+// manifestTooLarge 返回清单超过大小上限的错误
+func manifestTooLarge(target string, maxBytes int) error {
+	return &manifestFetchError{
+		code:    utils.ErrorCodeRefused,
+		message: fmt.Sprintf("manifest at %s exceeds the limit of %d bytes", target, maxBytes),
+		hint:    "Split the manifest into smaller files, or ask the operator to raise --max-manifest-url-bytes.",
+	}
+}
+
+// manifestFetchErrorResult 将获取清单的错误转换为结构化错误结果
+func manifestFetchErrorResult(err error) *mcp.CallToolResult {
+	var fetchErr *manifestFetchError
+	if errors.As(err, &fetchErr) {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    fetchErr.code,
+			Message: fetchErr.message,
+			Hint:    fetchErr.hint,
+		})
+	}
+	return utils.NewErrorToolResult(err.Error())
+}
@@ -0,0 +1,53 @@
+package tool
+
+import (
+	"testing"
+)
+
+func TestCheckManifestURL(t *testing.T) {
+	prefixes := []string{
+		"https://raw.githubusercontent.com/my-org/",
+		"https://gitlab.example.com:8443/platform/manifests",
+	}
+	tests := []struct {
+		target  string
+		allowed bool
+	}{
+		{target: "https://raw.githubusercontent.com/my-org/app/main/deploy.yaml", allowed: true},
+		{target: "https://raw.githubusercontent.com/my-org", allowed: true},
+		{target: "https://RAW.githubusercontent.com/my-org/app/main/deploy.yaml", allowed: true},
+		{target: "https://gitlab.example.com:8443/platform/manifests/-/raw/main/app.yaml", allowed: true},
+		{target: "https://gitlab.example.com:8443/platform/manifests", allowed: true},
+		{target: "https://raw.githubusercontent.com/my-org-evil/app/main/deploy.yaml"},
+		{target: "https://gitlab.example.com:8443/platform/manifests-evil/app.yaml"},
+		{target: "https://raw.githubusercontent.com.evil.example/my-org/app.yaml"},
+		{target: "https://raw.githubusercontent.com@evil.example/my-org/app.yaml"},
+		{target: "https://user@raw.githubusercontent.com/my-org/app.yaml"},
+		{target: "https://gitlab.example.com/platform/manifests/app.yaml"},
+		{target: "http://raw.githubusercontent.com/my-org/app.yaml"},
+		{target: "https://raw.githubusercontent.com/my-org/../other-org/app.yaml"},
+		{target: "https://raw.githubusercontent.com/my-org/%2e%2e/other-org/app.yaml"},
+		{target: "https://raw.githubusercontent.com/my-org%2F..%2Fother-org/app.yaml"},
+		{target: "https://raw.githubusercontent.com/other-org/../my-org/app.yaml", allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			err := checkManifestURL(tt.target, prefixes)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected %s to be allowed, got %v", tt.target, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Fatalf("expected %s to be refused", tt.target)
+			}
+		})
+	}
+}
+
+func TestCheckManifestURLRootPrefix(t *testing.T) {
+	if err := checkManifestURL("https://manifests.example.com/any/path.yaml", []string{"https://manifests.example.com"}); err != nil {
+		t.Fatalf("host prefix should allow every path: %v", err)
+	}
+	if err := checkManifestURL("https://manifests.example.com/app.yaml", []string{"manifests.example.com/"}); err == nil {
+		t.Fatal("prefix without https scheme should not match")
+	}
+}
@@ -0,0 +1,453 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// backupDefaultSkipKinds 备份时默认跳过的资源类型，这些对象由集群自动生成或只具有临时意义
+var backupDefaultSkipKinds = map[string]bool{
+	"event":         true,
+	"endpointslice": true,
+}
+
+// restoreKindPriority 恢复时的应用顺序，数值越小越先应用，未列出的类型排在最后
+var restoreKindPriority = map[string]int{
+	"Namespace":                0,
+	"CustomResourceDefinition": 1,
+	"ServiceAccount":           2,
+	"Role":                     3,
+	"ClusterRole":              3,
+	"RoleBinding":              4,
+	"ClusterRoleBinding":       4,
+	"ConfigMap":                5,
+	"Secret":                   5,
+	"LimitRange":               5,
+	"ResourceQuota":            5,
+	"PersistentVolume":         6,
+	"PersistentVolumeClaim":    7,
+	"Service":                  8,
+}
+
+// 未在restoreKindPriority中列出的类型的应用顺序
+const restoreDefaultPriority = 10
+
+// BackupNamespace 将命名空间中可列出的资源导出为一个多文档清单
+func (h *UtilityHandler) BackupNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	includeKindsArg, _ := arguments["includeKinds"].(string)
+	excludeKindsArg, _ := arguments["excludeKinds"].(string)
+	includeOwned, _ := arguments["includeOwned"].(bool)
+	includeSecretValues, _ := arguments["includeSecretValues"].(bool)
+
+	options := base.GetOptions()
+	revealSecrets := includeSecretValues && options.AllowSecretValues
+
+	h.Log.Info("Backing up namespace",
+		"namespace", namespace,
+		"includeKinds", includeKindsArg,
+		"excludeKinds", excludeKindsArg,
+		"includeOwned", includeOwned,
+		"revealSecrets", revealSecrets,
+	)
+
+	if namespace == "" {
+		return utils.NewErrorToolResult("namespace is required"), nil
+	}
+
+	includeKinds := parseKindSet(includeKindsArg)
+	excludeKinds := parseKindSet(excludeKindsArg)
+
+	namespaceGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	namespaceObj, err := h.Client.GetDynamicClient().Resource(namespaceGVR).Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get namespace %s", namespace)), nil
+	}
+
+	resourceLists, err := h.Client.GetDiscoveryClient().ServerPreferredNamespacedResources()
+	if err != nil {
+		// 部分API组不可用时继续备份其余资源
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			h.Log.Error("Failed to discover namespaced resources", "error", err)
+			return utils.NewKubeErrorResult(err, "failed to discover namespaced resources"), nil
+		}
+		h.Log.Warn("Partial API discovery error", "error", err)
+	}
+
+	result := models.BackupResult{
+		Namespace:    namespace,
+		CreatedAt:    time.Now(),
+		CountsByKind: make(map[string]int),
+	}
+	var notes []string
+	if includeSecretValues && !options.AllowSecretValues {
+		notes = append(notes, "secret values are redacted because the server was started without --allow-secret-values")
+	}
+
+	objs := []*unstructured.Unstructured{namespaceObj}
+	result.CountsByKind[namespaceObj.GetKind()]++
+	skippedKinds := make(map[string]bool)
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			// 跳过子资源以及不能列出或重新创建的资源
+			if strings.Contains(resource.Name, "/") || !hasListVerb(resource.Verbs) || !hasVerb(resource.Verbs, "create") {
+				continue
+			}
+			kindKey := strings.ToLower(resource.Kind)
+			if len(includeKinds) > 0 && !includeKinds[kindKey] {
+				continue
+			}
+			if excludeKinds[kindKey] || (len(includeKinds) == 0 && backupDefaultSkipKinds[kindKey]) {
+				skippedKinds[resource.Kind] = true
+				continue
+			}
+
+			list, err := h.Client.GetDynamicClient().Resource(gv.WithResource(resource.Name)).
+				Namespace(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				h.Log.Warn("Failed to list resources for backup", "resource", resource.Name, "error", err)
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", resource.Name, err))
+				continue
+			}
+
+			for i := range list.Items {
+				obj := &list.Items[i]
+				if isGeneratedObject(obj, includeOwned) {
+					result.SkippedObjects++
+					continue
+				}
+				if !revealSecrets && utils.RedactSecret(obj) {
+					result.Redacted++
+				}
+				objs = append(objs, obj)
+				result.CountsByKind[obj.GetKind()]++
+			}
+		}
+	}
+
+	for kind := range skippedKinds {
+		result.SkippedKinds = append(result.SkippedKinds, kind)
+	}
+	sort.Strings(result.SkippedKinds)
+
+	for _, obj := range objs {
+		utils.CleanForExport(obj)
+	}
+	sortForRestore(objs)
+
+	manifest, err := utils.MarshalManifest(objs)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to marshal backup: %v", err)), nil
+	}
+	result.ObjectCount = len(objs)
+	result.Size = len(manifest)
+
+	if options.BackupInlineLimit > 0 && result.Size > options.BackupInlineLimit {
+		if options.BackupDir == "" {
+			notes = append(notes, "backup exceeds the inline limit but no --backup-dir is configured, returning it inline")
+			result.Manifest = manifest
+		} else {
+			path := filepath.Join(options.BackupDir, fmt.Sprintf("%s-%s.yaml", namespace, result.CreatedAt.Format("20060102-150405")))
+			if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+				h.Log.Error("Failed to write backup file", "path", path, "error", err)
+				return utils.NewErrorToolResult(fmt.Sprintf("failed to write backup file %s: %v", path, err)), nil
+			}
+			result.FilePath = path
+		}
+	} else {
+		result.Manifest = manifest
+	}
+	result.Note = strings.Join(notes, "; ")
+
+	h.Log.Info("Namespace backed up",
+		"namespace", namespace,
+		"objects", result.ObjectCount,
+		"size", result.Size,
+		"file", result.FilePath,
+	)
+
+	return utils.RenderResult(request, result), nil
+}
+
+// RestoreNamespace 使用服务端应用将备份清单恢复到集群，并按依赖顺序应用
+func (h *UtilityHandler) RestoreNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	yamlStr, _ := arguments["yaml"].(string)
+	file, _ := arguments["file"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
+	fieldManager, _ := arguments["fieldManager"].(string)
+	force, _ := arguments["force"].(bool)
+
+	if fieldManager == "" {
+		fieldManager = base.DefaultFieldManager
+	}
+
+	h.Log.Info("Restoring namespace",
+		"file", file,
+		"namespace", namespace,
+		"dryRun", dryRun,
+		"fieldManager", fieldManager,
+		"force", force,
+	)
+
+	if (yamlStr == "") == (file == "") {
+		return utils.NewErrorToolResult("exactly one of yaml or file is required"), nil
+	}
+
+	if file != "" {
+		data, err := readBackupFile(file)
+		if err != nil {
+			return utils.NewErrorToolResult(err.Error()), nil
+		}
+		yamlStr = string(data)
+	}
+
+	objs, err := decodeManifest(yamlStr)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to parse manifest: %v", err)), nil
+	}
+	sortForRestore(objs)
+
+	result := models.RestoreResult{
+		Namespace: namespace,
+		DryRun:    dryRun,
+		Items:     make([]models.ApplyResult, 0, len(objs)),
+	}
+
+	patchOptions := metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+	}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	for i, obj := range objs {
+		item := h.restoreObject(ctx, obj, namespace, patchOptions)
+		item.Document = i + 1
+		if item.Success {
+			result.SuccessCount++
+		} else {
+			result.ErrorCount++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	h.Log.Info("Namespace restored",
+		"namespace", namespace,
+		"success", result.SuccessCount,
+		"errors", result.ErrorCount,
+		"dryRun", dryRun,
+	)
+
+	return utils.RenderResult(request, result), nil
+}
+
+// restoreObject 将单个对象通过服务端应用恢复到集群，namespace不为空时改写对象的命名空间
+func (h *UtilityHandler) restoreObject(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+	namespace string,
+	options metav1.PatchOptions,
+) models.ApplyResult {
+	item := models.ApplyResult{
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		ApiVersion: obj.GetAPIVersion(),
+	}
+
+	if item.Kind == "" || item.ApiVersion == "" || item.Name == "" {
+		item.Error = "missing kind, apiVersion or metadata.name"
+		return item
+	}
+	if utils.IsRedactedSecret(obj) {
+		item.Error = "secret values were redacted in the backup, restore this Secret manually"
+		return item
+	}
+
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, item.ApiVersion, item.Kind)
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+
+	if namespace != "" {
+		if namespaced {
+			obj.SetNamespace(namespace)
+		} else if item.Kind == "Namespace" && gvr.Group == "" {
+			obj.SetName(namespace)
+			item.Name = namespace
+		}
+	}
+	item.ClusterScoped = !namespaced
+
+	resource := h.Client.GetDynamicClient().Resource(gvr)
+	if namespaced {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace("default")
+		}
+		item.Namespace = obj.GetNamespace()
+	}
+
+	if denied := h.PreflightCheck(ctx, "patch", gvr, item.Namespace, item.Name); denied != nil {
+		item.Error = deniedMessage(denied)
+		return item
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		item.Error = fmt.Sprintf("failed to marshal object: %v", err)
+		return item
+	}
+
+	if namespaced {
+		_, err = resource.Namespace(item.Namespace).Patch(ctx, item.Name, types.ApplyPatchType, data, options)
+	} else {
+		_, err = resource.Patch(ctx, item.Name, types.ApplyPatchType, data, options)
+	}
+	if err != nil {
+		h.Log.Error("Failed to restore resource",
+			"kind", item.Kind,
+			"name", item.Name,
+			"namespace", item.Namespace,
+			"error", err,
+		)
+		item.Error = err.Error()
+		return item
+	}
+
+	h.Client.Cache().InvalidateKind(item.Kind)
+	item.Success = true
+	return item
+}
+
+// readBackupFile 读取备份目录中的清单文件，拒绝访问备份目录以外的路径
+func readBackupFile(file string) ([]byte, error) {
+	backupDir := base.GetOptions().BackupDir
+	if backupDir == "" {
+		return nil, errors.New("restoring from a file requires the server to be started with --backup-dir")
+	}
+
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(backupDir, path)
+	}
+	rel, err := filepath.Rel(backupDir, filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("file %s is outside the backup directory", file)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file %s: %w", file, err)
+	}
+	return data, nil
+}
+
+// decodeManifest 将多文档YAML清单解析为对象列表，跳过空文档
+func decodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifest)), 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("document %d: %w", len(objs)+1, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
+
+// sortForRestore 按恢复顺序对对象排序，同类对象按名称排序
+func sortForRestore(objs []*unstructured.Unstructured) {
+	priority := func(obj *unstructured.Unstructured) int {
+		if p, ok := restoreKindPriority[obj.GetKind()]; ok {
+			return p
+		}
+		return restoreDefaultPriority
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		pi, pj := priority(objs[i]), priority(objs[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if objs[i].GetKind() != objs[j].GetKind() {
+			return objs[i].GetKind() < objs[j].GetKind()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+}
+
+// isGeneratedObject 判断对象是否由控制器或集群自动生成，这类对象在恢复时会被重新创建
+func isGeneratedObject(obj *unstructured.Unstructured, includeOwned bool) bool {
+	if !includeOwned && metav1.GetControllerOf(obj) != nil {
+		return true
+	}
+	switch obj.GetKind() {
+	case "ConfigMap":
+		return obj.GetName() == "kube-root-ca.crt"
+	case "Secret":
+		secretType, _, _ := unstructured.NestedString(obj.Object, "type")
+		return secretType == "kubernetes.io/service-account-token"
+	}
+	return false
+}
+
+// parseKindSet 将逗号分隔的资源类型列表解析为小写集合
+func parseKindSet(kinds string) map[string]bool {
+	set := make(map[string]bool)
+	for _, kind := range strings.Split(kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			set[strings.ToLower(kind)] = true
+		}
+	}
+	return set
+}
+
+// hasVerb 检查资源是否支持指定动词
+func hasVerb(verbs []string, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
@@ -2,13 +2,20 @@ package tool
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/hsn0918/kubernetes-mcp/pkg/cache"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
 	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 )
 
@@ -19,127 +26,341 @@ func (h *UtilityHandler) GetClusterInfo(
 ) (*mcp.CallToolResult, error) {
 	h.Log.Info("Getting cluster info")
 
-	// 构建响应
-	var result strings.Builder
-	result.WriteString("Kubernetes Cluster Information:\n\n")
-
 	// 获取服务器版本信息
 	versionInfo, err := h.Client.GetDiscoveryClient().ServerVersion()
 	if err != nil {
 		h.Log.Error("Failed to get server version", "error", err)
-		return utils.NewErrorToolResult(fmt.Sprintf("failed to get server version: %v", err)), nil
+		return utils.NewKubeErrorResult(err, "failed to get server version"), nil
 	}
 
-	// 添加版本信息
-	result.WriteString(fmt.Sprintf("Version:      %s\n", versionInfo.GitVersion))
-	result.WriteString(fmt.Sprintf("Build Date:   %s\n", versionInfo.BuildDate))
-	result.WriteString(fmt.Sprintf("Go Version:   %s\n", versionInfo.GoVersion))
-	result.WriteString(fmt.Sprintf("Platform:     %s\n", versionInfo.Platform))
-	result.WriteString(fmt.Sprintf("Git Commit:   %s\n", versionInfo.GitCommit))
-	result.WriteString(fmt.Sprintf("Git TreeState: %s\n", versionInfo.GitTreeState))
-	result.WriteString(fmt.Sprintf("Compiler:     %s\n", versionInfo.Compiler))
+	// 构建响应
+	info := models.ClusterInfo{
+		Version:      versionInfo.GitVersion,
+		BuildDate:    versionInfo.BuildDate,
+		GoVersion:    versionInfo.GoVersion,
+		Platform:     versionInfo.Platform,
+		GitCommit:    versionInfo.GitCommit,
+		GitTreeState: versionInfo.GitTreeState,
+		Compiler:     versionInfo.Compiler,
+	}
 
 	// 获取当前命名空间
 	currentNamespace, err := h.Client.GetCurrentNamespace()
 	if err == nil && currentNamespace != "" {
-		result.WriteString(fmt.Sprintf("\nCurrent Namespace: %s\n", currentNamespace))
+		info.Namespace = currentNamespace
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, info), nil
+}
+
+// apiResourceFilter GET_API_RESOURCES的过滤条件
+type apiResourceFilter struct {
+	// groupSet 为false时不按组版本过滤；核心组的group为空
+	groupSet            bool
+	group               string
+	version             string
+	namespacedOnly      bool
+	verb                string
+	search              string
+	includeSubresources bool
 }
 
-// GetAPIResources 获取API资源列表
+// GetAPIResources 获取API资源列表，同一组中多个版本提供的资源合并为一项并标记首选版本
 func (h *UtilityHandler) GetAPIResources(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	arguments := request.GetArguments()
-	group, _ := arguments["group"].(string)
+	groupArg, _ := arguments["group"].(string)
+	verb, _ := arguments["verb"].(string)
+	search, _ := arguments["search"].(string)
+	namespacedOnly, _ := arguments["namespacedOnly"].(bool)
+	includeSubresources, _ := arguments["includeSubresources"].(bool)
+	filter := apiResourceFilter{
+		verb:                strings.ToLower(strings.TrimSpace(verb)),
+		search:              strings.ToLower(strings.TrimSpace(search)),
+		namespacedOnly:      namespacedOnly,
+		includeSubresources: includeSubresources,
+	}
+	// group可以是组名（apps）或组版本（apps/v1），核心组写作core或v1
+	if groupArg = strings.TrimSpace(groupArg); groupArg != "" {
+		filter.groupSet = true
+		filter.group, filter.version, _ = strings.Cut(groupArg, "/")
+		switch {
+		case filter.group == "v1" && filter.version == "":
+			filter.group, filter.version = "", "v1"
+		case filter.group == "core":
+			filter.group = ""
+		}
+	}
 
-	h.Log.Info("Getting API resources", "group", group)
+	h.Log.Info("Getting API resources",
+		"group", groupArg,
+		"namespacedOnly", filter.namespacedOnly,
+		"verb", filter.verb,
+		"search", filter.search,
+		"includeSubresources", filter.includeSubresources,
+	)
 
-	// 构建响应
-	var result strings.Builder
-	result.WriteString("API Resources:\n\n")
+	response := models.APIResourceList{Resources: []models.APIResourceInfo{}}
+	groups, resourcesList, err := h.Client.GetDiscoveryClient().ServerGroupsAndResources()
+	if err != nil {
+		// 处理部分发现错误，继续使用已获取的资源
+		var failed *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &failed) {
+			h.Log.Error("Failed to get API resources", "error", err)
+			return utils.NewKubeErrorResult(err, "failed to get API resources"), nil
+		}
+		h.Log.Warn("Partial API discovery error", "error", err)
+		for groupVersion := range failed.Groups {
+			response.FailedGroupVersions = append(response.FailedGroupVersions, groupVersion.String())
+		}
+		sort.Strings(response.FailedGroupVersions)
+	}
+
+	if filter.groupSet && !apiGroupServed(groups, filter.group, filter.version) {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeNotFound,
+			Message: fmt.Sprintf("API group %s is not served by the cluster", groupArg),
+			Hint:    "Call GET_API_RESOURCES without group to list all served groups.",
+		}), nil
+	}
 
-	// 获取API资源
-	var resourcesList []*metav1.APIResourceList
-	var err error
+	for _, resource := range collectAPIResources(groups, resourcesList, filter) {
+		if filter.matches(resource) {
+			response.Resources = append(response.Resources, resource)
+		}
+	}
+	response.Count = len(response.Resources)
+	return utils.RenderResult(request, response), nil
+}
 
-	// 根据是否指定了group来获取资源
-	if group == "" {
-		// 获取所有API组的资源
-		_, resourcesList, err = h.Client.GetDiscoveryClient().ServerGroupsAndResources()
-		if err != nil {
-			// 处理部分发现错误，继续使用已获取的资源
-			if !discovery.IsGroupDiscoveryFailedError(err) {
-				h.Log.Error("Failed to get API resources", "error", err)
-				return utils.NewErrorToolResult(fmt.Sprintf("failed to get API resources: %v", err)), nil
+// matches 判断资源是否满足命名空间、动作和关键字过滤条件
+func (f apiResourceFilter) matches(resource models.APIResourceInfo) bool {
+	if f.namespacedOnly && !resource.Namespaced {
+		return false
+	}
+	if f.verb != "" && !slices.Contains(resource.Verbs, f.verb) {
+		return false
+	}
+	if f.search == "" {
+		return true
+	}
+	candidates := append([]string{resource.Kind, resource.Resource}, resource.ShortNames...)
+	for _, candidate := range candidates {
+		if strings.Contains(strings.ToLower(candidate), f.search) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiGroupServed 判断集群是否提供指定的组（及版本）
+func apiGroupServed(groups []*metav1.APIGroup, group, version string) bool {
+	for _, apiGroup := range groups {
+		if apiGroup.Name != group {
+			continue
+		}
+		if version == "" {
+			return true
+		}
+		for _, served := range apiGroup.Versions {
+			if served.Version == version {
+				return true
 			}
-			h.Log.Warn("Partial API discovery error", "error", err)
 		}
-	} else {
-		// 获取特定组的资源列表
-		apiGroup, err := h.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(group)
+	}
+	return false
+}
+
+// collectAPIResources 将Discovery结果按组和资源名合并，每个资源保留首选版本；
+// 首选版本不提供该资源时使用组中优先级最高的版本。结果按组（核心组在前）和资源名排序
+func collectAPIResources(
+	groups []*metav1.APIGroup,
+	resourcesList []*metav1.APIResourceList,
+	filter apiResourceFilter,
+) []models.APIResourceInfo {
+	// versionRank 组中版本的优先级，Discovery按优先级从高到低返回版本
+	versionRank := make(map[string]map[string]int, len(groups))
+	preferred := make(map[string]string, len(groups))
+	for _, group := range groups {
+		versionRank[group.Name] = make(map[string]int, len(group.Versions))
+		for i, version := range group.Versions {
+			versionRank[group.Name][version.Version] = i
+		}
+		preferred[group.Name] = group.PreferredVersion.Version
+	}
+	rank := func(group, version string) int {
+		if version == preferred[group] {
+			return -1
+		}
+		if value, ok := versionRank[group][version]; ok {
+			return value
+		}
+		return len(versionRank[group])
+	}
+
+	type resourceKey struct{ group, resource string }
+	merged := make(map[resourceKey]*models.APIResourceInfo)
+	for _, resourceList := range resourcesList {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
 		if err != nil {
-			h.Log.Error("Failed to get API resources for group", "group", group, "error", err)
-			return utils.NewErrorToolResult(fmt.Sprintf("failed to get API resources for group %s: %v", group, err)), nil
+			continue
+		}
+		group := groupVersion.Group
+		if filter.groupSet && group != filter.group {
+			continue
+		}
+		if filter.version != "" && groupVersion.Version != filter.version {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") && !filter.includeSubresources {
+				continue
+			}
+			key := resourceKey{group: group, resource: resource.Name}
+			info := models.APIResourceInfo{
+				Group:            group,
+				Version:          groupVersion.Version,
+				Resource:         resource.Name,
+				Kind:             resource.Kind,
+				Namespaced:       resource.Namespaced,
+				Verbs:            append([]string{}, resource.Verbs...),
+				ShortNames:       resource.ShortNames,
+				Categories:       resource.Categories,
+				PreferredVersion: groupVersion.Version == preferred[group],
+			}
+			existing, ok := merged[key]
+			if !ok {
+				merged[key] = &info
+				continue
+			}
+			// 保留优先级更高的版本，其余版本记录在OtherVersions中
+			if rank(group, info.Version) < rank(group, existing.Version) {
+				info.OtherVersions = append(existing.OtherVersions, existing.Version)
+				merged[key] = &info
+			} else {
+				existing.OtherVersions = append(existing.OtherVersions, info.Version)
+			}
 		}
-		resourcesList = []*metav1.APIResourceList{apiGroup}
 	}
 
-	// 格式化输出
-	if len(resourcesList) == 0 {
-		result.WriteString("No API resources found\n")
-	} else {
-		// 对API组进行排序
-		sort.Slice(resourcesList, func(i, j int) bool {
-			return resourcesList[i].GroupVersion < resourcesList[j].GroupVersion
+	result := make([]models.APIResourceInfo, 0, len(merged))
+	for _, info := range merged {
+		sort.Slice(info.OtherVersions, func(i, j int) bool {
+			return rank(info.Group, info.OtherVersions[i]) < rank(info.Group, info.OtherVersions[j])
 		})
+		sort.Strings(info.Verbs)
+		result = append(result, *info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Resource < result[j].Resource
+	})
+	return result
+}
+
+// RefreshDiscoveryCache 清空Discovery与命名空间缓存并立即重新加载
+func (h *UtilityHandler) RefreshDiscoveryCache(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	h.Log.Info("Refreshing discovery cache")
+
+	h.Client.InvalidateCaches()
+
+	groups, resourcesList, err := h.Client.GetDiscoveryClient().ServerGroupsAndResources()
+	if err != nil {
+		// 处理部分发现错误，继续使用已获取的资源
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			h.Log.Error("Failed to reload API resources", "error", err)
+			return utils.NewKubeErrorResult(err, "failed to reload API resources"), nil
+		}
+		h.Log.Warn("Partial API discovery error", "error", err)
+	}
+
+	namespaces, err := h.Client.ListNamespaceNames(ctx)
+	if err != nil {
+		h.Log.Error("Failed to reload namespaces", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to reload namespaces"), nil
+	}
 
-		// 遍历每个API组
-		for _, apiResourceList := range resourcesList {
-			gv := apiResourceList.GroupVersion
-			result.WriteString(fmt.Sprintf("GROUP VERSION: %s\n", gv))
-
-			// 对资源进行排序
-			resources := apiResourceList.APIResources
-			sort.Slice(resources, func(i, j int) bool {
-				return resources[i].Name < resources[j].Name
-			})
-
-			// 遍历每个资源
-			for _, resource := range resources {
-				// 跳过子资源
-				if strings.Contains(resource.Name, "/") {
-					continue
-				}
-
-				namespaced := "namespaced"
-				if !resource.Namespaced {
-					namespaced = "cluster-wide"
-				}
-
-				verbs := strings.Join(resource.Verbs, ",")
-				result.WriteString(fmt.Sprintf("  %-40s %-15s %-30s\n", resource.Name, namespaced, verbs))
+	stats := h.Client.CacheStats()
+	response := models.CacheRefreshResult{
+		RefreshedAt:     time.Now().Format(time.RFC3339),
+		APIGroups:       len(groups),
+		Namespaces:      len(namespaces),
+		DiscoveryHits:   stats.DiscoveryHits,
+		DiscoveryMisses: stats.DiscoveryMisses,
+		NamespaceHits:   stats.NamespaceHits,
+		NamespaceMisses: stats.NamespaceMisses,
+	}
+	for _, resourceList := range resourcesList {
+		for _, resource := range resourceList.APIResources {
+			// 跳过子资源
+			if !strings.Contains(resource.Name, "/") {
+				response.APIResources++
 			}
-			result.WriteString("\n")
 		}
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, response), nil
+}
+
+// discoveryCacheKey INVALIDATE_CACHE中表示API发现缓存的键
+const discoveryCacheKey = "discovery"
+
+// InvalidateCache 使名称匹配通配符的查询缓存项失效，匹配discovery时同时清空API发现缓存
+func (h *UtilityHandler) InvalidateCache(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	pattern, _ := request.GetArguments()["pattern"].(string)
+	if pattern == "" {
+		pattern = "*"
+	}
+	h.Log.Info("Invalidating cache", "pattern", pattern)
+
+	invalidated, err := h.Client.Cache().Invalidate(pattern)
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: err.Error(),
+			Hint:    "Use a glob such as '*', 'nodes' or 'n*'; see GET_SERVER_STATUS for the cache keys.",
+		}), nil
+	}
+	response := models.CacheInvalidationResult{
+		Pattern:       pattern,
+		Invalidated:   invalidated,
+		InvalidatedAt: time.Now().Format(time.RFC3339),
+	}
+	if matched, _ := path.Match(pattern, discoveryCacheKey); matched {
+		if cached, ok := h.Client.GetDiscoveryClient().(discovery.CachedDiscoveryInterface); ok {
+			cached.Invalidate()
+			response.Discovery = true
+		}
+	}
+	if response.Invalidated == nil {
+		response.Invalidated = []string{}
+	}
+	response.Entries = cacheEntryStatuses(h.Client.CacheStats().Entries)
+	return utils.RenderResult(request, response), nil
+}
+
+// cacheEntryStatuses 将查询缓存的统计转换为响应结构
+func cacheEntryStatuses(stats []cache.Stats) []models.CacheEntryStatus {
+	entries := make([]models.CacheEntryStatus, 0, len(stats))
+	for _, item := range stats {
+		entries = append(entries, models.CacheEntryStatus{
+			Key:    item.Key,
+			TTL:    item.TTL,
+			Cached: item.Cached,
+			Age:    item.Age,
+			Hits:   item.Hits,
+			Misses: item.Misses,
+		})
+	}
+	return entries
 }
@@ -0,0 +1,275 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// CompareResources 获取两个集群对象并输出字段级差异和统一格式的YAML差异
+func (h *UtilityHandler) CompareResources(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	sourceName, _ := arguments["sourceName"].(string)
+	sourceNamespace, _ := arguments["sourceNamespace"].(string)
+	targetKind, _ := arguments["targetKind"].(string)
+	targetAPIVersion, _ := arguments["targetApiVersion"].(string)
+	targetName, _ := arguments["targetName"].(string)
+	targetNamespace, _ := arguments["targetNamespace"].(string)
+	ignoreFieldsArg, _ := arguments["ignoreFields"].(string)
+	ignoreImageTags, _ := arguments["ignoreImageTags"].(bool)
+
+	// 目标对象未指定的部分沿用源对象
+	if targetKind == "" {
+		targetKind = kind
+	}
+	if targetAPIVersion == "" {
+		targetAPIVersion = apiVersion
+	}
+	if targetName == "" {
+		targetName = sourceName
+	}
+	if targetNamespace == "" {
+		targetNamespace = sourceNamespace
+	}
+
+	h.Log.Info("Comparing resources",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"source", sourceNamespace+"/"+sourceName,
+		"target", targetNamespace+"/"+targetName,
+		"ignoreFields", ignoreFieldsArg,
+		"ignoreImageTags", ignoreImageTags,
+	)
+
+	if kind == "" || apiVersion == "" || sourceName == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind, apiVersion and sourceName"), nil
+	}
+
+	var ignoreFields []string
+	for _, field := range strings.Split(ignoreFieldsArg, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			ignoreFields = append(ignoreFields, field)
+		}
+	}
+
+	source, sourceRef, err := h.fetchForCompare(ctx, apiVersion, kind, sourceName, sourceNamespace)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+	target, targetRef, err := h.fetchForCompare(ctx, targetAPIVersion, targetKind, targetName, targetNamespace)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+
+	result := models.CompareResult{
+		Source:          sourceRef,
+		Target:          targetRef,
+		IgnoredFields:   ignoreFields,
+		IgnoreImageTags: ignoreImageTags,
+	}
+
+	switch {
+	case source == nil && target == nil:
+		result.Note = "neither object exists"
+	case source == nil:
+		result.Note = "source object does not exist"
+	case target == nil:
+		result.Note = "target object does not exist"
+	default:
+		for _, obj := range []*unstructured.Unstructured{source, target} {
+			prepareForCompare(obj, ignoreFields, ignoreImageTags)
+		}
+
+		var changes []models.FieldChange
+		keys := make(map[string]struct{}, len(source.Object)+len(target.Object))
+		for k := range source.Object {
+			keys[k] = struct{}{}
+		}
+		for k := range target.Object {
+			keys[k] = struct{}{}
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+		for _, k := range sortedKeys {
+			diffFields(k, source.Object[k], target.Object[k], &changes)
+		}
+
+		sourceYAML, err := yaml.Marshal(source.Object)
+		if err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("failed to marshal source object: %v", err)), nil
+		}
+		targetYAML, err := yaml.Marshal(target.Object)
+		if err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("failed to marshal target object: %v", err)), nil
+		}
+
+		result.Changes = changes
+		result.DiffCount = len(changes)
+		result.Identical = len(changes) == 0
+		result.UnifiedDiff = utils.UnifiedDiff(
+			compareRefLabel(sourceRef),
+			compareRefLabel(targetRef),
+			string(sourceYAML),
+			string(targetYAML),
+		)
+	}
+
+	return utils.RenderResult(request, result), nil
+}
+
+// fetchForCompare 获取待比较的对象，对象不存在时返回nil而不是错误
+func (h *UtilityHandler) fetchForCompare(
+	ctx context.Context,
+	apiVersion, kind, name, namespace string,
+) (*unstructured.Unstructured, models.CompareObjectRef, error) {
+	ref := models.CompareObjectRef{
+		Kind:       kind,
+		APIVersion: apiVersion,
+		Name:       name,
+	}
+
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+	if err != nil {
+		return nil, ref, err
+	}
+
+	resource := h.Client.GetDynamicClient().Resource(gvr)
+	var obj *unstructured.Unstructured
+	if namespaced {
+		if namespace == "" {
+			namespace = "default"
+		}
+		ref.Namespace = namespace
+		obj, err = resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		obj, err = resource.Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ref, nil
+		}
+		h.Log.Error("Failed to get resource for comparison",
+			"kind", kind,
+			"name", name,
+			"namespace", namespace,
+			"error", err,
+		)
+		return nil, ref, fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+	}
+
+	ref.Exists = true
+	ref.ResourceVersion = obj.GetResourceVersion()
+	return obj, ref, nil
+}
+
+// prepareForCompare 清理对象中与比较无关的字段，并应用忽略规则
+func prepareForCompare(obj *unstructured.Unstructured, ignoreFields []string, ignoreImageTags bool) {
+	cleanObject(obj)
+	// 对象身份已在引用中给出，比较时不再重复报告
+	unstructured.RemoveNestedField(obj.Object, "metadata", "name")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "namespace")
+	// Secret只比较指纹，不暴露值
+	utils.FingerprintSecret(obj)
+
+	for _, field := range ignoreFields {
+		removeFieldPath(obj.Object, field)
+	}
+	if ignoreImageTags {
+		stripImageTags(obj.Object)
+	}
+}
+
+// removeFieldPath 按点号分隔的路径删除字段
+// 键本身可以包含点号（例如标签名），列表元素可用[N]或[*]指定，省略时作用于所有元素
+func removeFieldPath(value interface{}, path string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if path == key {
+				delete(v, key)
+			} else if rest, ok := strings.CutPrefix(path, key); ok && (strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "[")) {
+				removeFieldPath(child, strings.TrimPrefix(rest, "."))
+			}
+		}
+	case []interface{}:
+		if !strings.HasPrefix(path, "[") {
+			for _, item := range v {
+				removeFieldPath(item, path)
+			}
+			return
+		}
+		end := strings.Index(path, "]")
+		if end < 0 {
+			return
+		}
+		index, rest := path[1:end], strings.TrimPrefix(path[end+1:], ".")
+		for i, item := range v {
+			if index != "*" && index != strconv.Itoa(i) {
+				continue
+			}
+			if rest == "" {
+				// 删除列表元素会改变其余元素的下标，这里只清空元素内容
+				v[i] = nil
+				continue
+			}
+			removeFieldPath(item, rest)
+		}
+	}
+}
+
+// stripImageTags 递归去除容器定义中镜像的标签和摘要，只保留仓库名
+func stripImageTags(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if image, ok := child.(string); ok && key == "image" {
+				v[key] = imageRepository(image)
+				continue
+			}
+			stripImageTags(child)
+		}
+	case []interface{}:
+		for _, item := range v {
+			stripImageTags(item)
+		}
+	}
+}
+
+// imageRepository 返回镜像引用去除标签和摘要后的仓库部分
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// 只有最后一个'/'之后的冒号才是标签分隔符，前面的可能是仓库端口
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// compareRefLabel 返回统一差异头部中使用的对象标识
+func compareRefLabel(ref models.CompareObjectRef) string {
+	if ref.Namespace == "" {
+		return fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", ref.Namespace, ref.Kind, ref.Name)
+}
@@ -0,0 +1,264 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// readyConditionTypes 表示整体就绪的条件类型，按优先级排列，取资源上第一个存在的类型
+var readyConditionTypes = []string{"Ready", "Available", "Succeeded", "Healthy", "Reconciled", "Complete"}
+
+// failureConditionTypes 状态为True时表示异常的条件类型，优先于就绪类条件
+var failureConditionTypes = map[string]bool{
+	"Degraded":       true,
+	"Failed":         true,
+	"Stalled":        true,
+	"Error":          true,
+	"ReplicaFailure": true,
+}
+
+// GetResourceConditions 返回一个或一组资源规范化后的status.conditions及健康判定
+func (h *UtilityHandler) GetResourceConditions(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	readyCondition, _ := arguments["readyCondition"].(string)
+	unhealthyOnly, _ := arguments["unhealthyOnly"].(bool)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.Log.Info("Getting resource conditions",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"name", name,
+		"namespace", namespace,
+		"labelSelector", labelSelector,
+		"readyCondition", readyCondition,
+		"unhealthyOnly", unhealthyOnly,
+	)
+
+	if kind == "" {
+		return utils.NewErrorToolResult("missing required parameter: kind"), nil
+	}
+	if name != "" && labelSelector != "" {
+		return utils.NewErrorToolResult("specify either name or labelSelector, not both"), nil
+	}
+
+	gvr, namespaced, kind, apiVersion, err := h.resolveConditionKind(kind, apiVersion)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+	var resource dynamic.ResourceInterface
+	if namespaced {
+		// 按名称获取时默认使用default命名空间，列出时为空表示所有命名空间
+		if namespace == "" && name != "" {
+			namespace = "default"
+		}
+		resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace)
+	} else {
+		namespace = ""
+		resource = h.Client.GetDynamicClient().Resource(gvr)
+	}
+
+	response := models.ResourceConditionsList{
+		Kind:            kind,
+		APIVersion:      apiVersion,
+		Namespace:       namespace,
+		LabelSelector:   labelSelector,
+		CountsByVerdict: make(map[string]int),
+		Items:           []models.ResourceConditions{},
+	}
+
+	var objs []unstructured.Unstructured
+	if name != "" {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			h.Log.Error("Failed to get resource", "kind", kind, "name", name, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+		}
+		objs = append(objs, *obj)
+	} else {
+		page, err := base.ParseListPage(request)
+		if err != nil {
+			return utils.NewErrorToolResult(err.Error()), nil
+		}
+		if page.SortBy == "" {
+			page.SortBy = base.SortByName
+		}
+		list, err := resource.List(ctx, metav1.ListOptions{
+			LabelSelector: labelSelector,
+			Limit:         page.Limit,
+			Continue:      page.Continue,
+		})
+		if err != nil {
+			h.Log.Error("Failed to list resources", "kind", kind, "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list %s", kind)), nil
+		}
+		response.ListPagination = page.Result(list)
+		objs = list.Items
+	}
+
+	now := time.Now()
+	for i := range objs {
+		item := summarizeConditions(&objs[i], readyCondition, now)
+		response.Scanned++
+		response.CountsByVerdict[item.Verdict]++
+		if unhealthyOnly && item.Verdict == models.VerdictHealthy {
+			continue
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	return utils.RenderResult(request, response), nil
+}
+
+// resolveConditionKind 解析资源类型，未指定apiVersion时按类型名、复数名或简称查找，匹配多个API组时要求指定apiVersion
+func (h *UtilityHandler) resolveConditionKind(
+	kind, apiVersion string,
+) (schema.GroupVersionResource, bool, string, string, error) {
+	if apiVersion != "" {
+		gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+		return gvr, namespaced, kind, apiVersion, err
+	}
+	mappings, err := utils.ResolveKind(h.Client, kind)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, "", "", err
+	}
+	if len(mappings) > 1 {
+		var candidates []string
+		for _, mapping := range mappings {
+			candidates = append(candidates, mapping.GroupVersionKind.GroupVersion().String())
+		}
+		return schema.GroupVersionResource{}, false, "", "", fmt.Errorf("kind %s is served by several API groups (%s), specify apiVersion", kind, strings.Join(candidates, ", "))
+	}
+	mapping := mappings[0]
+	return mapping.Resource, utils.IsNamespacedMapping(mapping), mapping.GroupVersionKind.Kind,
+		mapping.GroupVersionKind.GroupVersion().String(), nil
+}
+
+// summarizeConditions 规范化资源的status.conditions并给出健康判定
+// 状态为True的失败类条件优先判定为degraded；其次按就绪类条件的状态判定；没有条件时比较observedGeneration与generation
+func summarizeConditions(obj *unstructured.Unstructured, readyCondition string, now time.Time) models.ResourceConditions {
+	item := models.ResourceConditions{
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Conditions: normalizeConditions(obj, now),
+		Generation: obj.GetGeneration(),
+	}
+	item.ObservedGeneration, _, _ = unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+
+	for i := range item.Conditions {
+		condition := &item.Conditions[i]
+		if failureConditionTypes[condition.Type] && condition.Status == "True" {
+			item.Verdict = models.VerdictDegraded
+			item.Blocking = condition
+			item.Summary = fmt.Sprintf("condition %s is True", condition.Type)
+			return item
+		}
+	}
+
+	candidates := readyConditionTypes
+	if readyCondition != "" {
+		candidates = []string{readyCondition}
+	}
+	for _, conditionType := range candidates {
+		for i := range item.Conditions {
+			condition := &item.Conditions[i]
+			if !strings.EqualFold(condition.Type, conditionType) {
+				continue
+			}
+			item.ReadyCondition = condition.Type
+			switch {
+			case condition.Status == "True" && condition.ObservedGeneration > 0 && condition.ObservedGeneration < item.Generation:
+				item.Verdict = models.VerdictUnknown
+				item.Blocking = condition
+				item.Summary = fmt.Sprintf("condition %s is True but reflects generation %d, the current generation is %d", condition.Type, condition.ObservedGeneration, item.Generation)
+			case condition.Status == "True":
+				item.Verdict = models.VerdictHealthy
+				item.Summary = fmt.Sprintf("condition %s is True", condition.Type)
+			case condition.Status == "False":
+				item.Verdict = models.VerdictDegraded
+				item.Blocking = condition
+				item.Summary = fmt.Sprintf("condition %s is False", condition.Type)
+			default:
+				item.Verdict = models.VerdictUnknown
+				item.Blocking = condition
+				item.Summary = fmt.Sprintf("condition %s is %s", condition.Type, condition.Status)
+			}
+			return item
+		}
+	}
+
+	if len(item.Conditions) > 0 {
+		item.Verdict = models.VerdictUnknown
+		item.Summary = fmt.Sprintf("none of the conditions is a known readiness condition (%s); pass readyCondition to choose one", strings.Join(candidates, ", "))
+		return item
+	}
+
+	// 没有状态条件时，控制器已处理最新的spec即视为正常
+	_, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	switch {
+	case !found || item.Generation == 0:
+		item.Verdict = models.VerdictUnknown
+		item.Summary = "resource has no status.conditions and no status.observedGeneration"
+	case item.ObservedGeneration >= item.Generation:
+		item.Verdict = models.VerdictHealthy
+		item.Summary = fmt.Sprintf("no status.conditions; the controller has observed the current generation %d", item.Generation)
+	default:
+		item.Verdict = models.VerdictUnknown
+		item.Summary = fmt.Sprintf("no status.conditions; the controller has observed generation %d, the current generation is %d", item.ObservedGeneration, item.Generation)
+	}
+	return item
+}
+
+// normalizeConditions 将status.conditions转换为统一结构，忽略格式不正确的条目
+func normalizeConditions(obj *unstructured.Unstructured, now time.Time) []models.ResourceCondition {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	conditions := make([]models.ResourceCondition, 0, len(raw))
+	for _, entry := range raw {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := models.ResourceCondition{}
+		condition.Type, _ = fields["type"].(string)
+		if condition.Type == "" {
+			continue
+		}
+		condition.Status, _ = fields["status"].(string)
+		condition.Reason, _ = fields["reason"].(string)
+		condition.Message, _ = fields["message"].(string)
+		condition.ObservedGeneration, _, _ = unstructured.NestedInt64(fields, "observedGeneration")
+		// 部分控制器只设置lastUpdateTime或lastProbeTime
+		for _, key := range []string{"lastTransitionTime", "lastUpdateTime", "lastProbeTime"} {
+			value, _ := fields[key].(string)
+			if transition, err := time.Parse(time.RFC3339, value); err == nil {
+				condition.LastTransitionTime = &transition
+				condition.Age = duration.HumanDuration(now.Sub(transition))
+				break
+			}
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
@@ -0,0 +1,313 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// lastAppliedSource 从kubectl.kubernetes.io/last-applied-configuration注解中发现的API版本
+const lastAppliedSource = "last-applied-configuration"
+
+// deprecatedAPICheck 在目标版本中已弃用或移除的一项及其状态
+type deprecatedAPICheck struct {
+	api    models.DeprecatedAPI
+	status string
+}
+
+// CheckDeprecatedAPIs 检查升级到目标版本前需要迁移的对象和节点
+// 对象本身总以存储版本返回，因此通过managedFields中各字段管理器使用的apiVersion和last-applied-configuration注解判断对象是否仍由弃用的API版本管理
+func (h *UtilityHandler) CheckDeprecatedAPIs(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	targetArg, _ := arguments["targetVersion"].(string)
+	namespacesStr, _ := arguments["namespaces"].(string)
+
+	h.Log.Info("Checking deprecated APIs", "targetVersion", targetArg, "namespaces", namespacesStr)
+
+	serverInfo, err := h.Client.GetDiscoveryClient().ServerVersion()
+	if err != nil {
+		h.Log.Error("Failed to get server version", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to get server version"), nil
+	}
+	server, err := utils.ParseKubernetesVersion(serverInfo.GitVersion)
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	// 未指定目标版本时检查升级到下一个次版本
+	target := version.MajorMinor(server.Major(), server.Minor()+1)
+	if targetArg != "" {
+		if target, err = utils.ParseKubernetesVersion(targetArg); err != nil {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeInvalid,
+				Message: err.Error(),
+				Hint:    "Specify targetVersion as a Kubernetes minor version such as 1.29.",
+			}), nil
+		}
+	}
+
+	report := models.DeprecatedAPIReport{
+		ServerVersion: serverInfo.GitVersion,
+		TargetVersion: fmt.Sprintf("%d.%d", target.Major(), target.Minor()),
+		Findings:      []models.DeprecatedAPIFinding{},
+		Nodes:         []models.NodeVersionSkew{},
+	}
+
+	var checks []deprecatedAPICheck
+	for _, api := range utils.DefaultDeprecatedAPIs() {
+		status, err := utils.DeprecatedAPIStatus(api, target)
+		if err != nil {
+			return utils.NewErrorToolResult(err.Error()), nil
+		}
+		if status != "" {
+			checks = append(checks, deprecatedAPICheck{api: api, status: status})
+		}
+	}
+	report.Checked = len(checks)
+	report.Served = h.servedDeprecatedAPIs(checks)
+
+	namespaces := utils.SplitCommaList(namespacesStr)
+	allNamespaces := len(namespaces) == 0
+	if allNamespaces {
+		// 空字符串表示所有命名空间
+		namespaces = []string{""}
+	}
+	for _, gvr := range h.deprecatedAPIResources(checks, &report) {
+		scopes := namespaces
+		if !gvr.namespaced {
+			// 集群级别资源只列出一次，限定命名空间时不扫描
+			if !allNamespaces {
+				continue
+			}
+			scopes = []string{""}
+		}
+		for _, namespace := range scopes {
+			list, err := h.Client.GetDynamicClient().Resource(gvr.resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+					report.Warnings = append(report.Warnings, fmt.Sprintf("skipped %s: %v", gvr.kind, err))
+					continue
+				}
+				h.Log.Error("Failed to list resources", "kind", gvr.kind, "namespace", namespace, "error", err)
+				return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list %s", gvr.kind)), nil
+			}
+			report.Scanned += len(list.Items)
+			for i := range list.Items {
+				report.Findings = append(report.Findings, deprecatedAPIFindings(&list.Items[i], gvr.kind, checks)...)
+			}
+		}
+	}
+	summarizeDeprecatedAPIFindings(&report)
+
+	nodes, err := h.Client.ListNodes(ctx)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("skipped node version skew: %v", err))
+	} else {
+		for _, node := range nodes {
+			skew := nodeVersionSkew(node.Name, node.Status.NodeInfo.KubeletVersion, server, target)
+			if skew.Status != models.SkewStatusOK {
+				report.SkewIssues++
+			}
+			report.Nodes = append(report.Nodes, skew)
+		}
+		sort.Slice(report.Nodes, func(i, j int) bool {
+			return report.Nodes[i].Node < report.Nodes[j].Node
+		})
+	}
+
+	switch {
+	case report.Removed > 0:
+		report.Hint = fmt.Sprintf("%d objects are managed through API versions removed in %s; re-apply them with the replacement apiVersion before upgrading.", report.Removed, report.TargetVersion)
+	case report.SkewIssues > 0:
+		report.Hint = "Upgrade the flagged nodes' kubelets before or together with the control plane to stay within the supported version skew."
+	}
+	return utils.RenderResult(request, report), nil
+}
+
+// servedDeprecatedAPIs 返回集群当前仍在提供的弃用API版本
+func (h *UtilityHandler) servedDeprecatedAPIs(checks []deprecatedAPICheck) []models.ServedDeprecatedAPI {
+	var served []models.ServedDeprecatedAPI
+	kindsByGroupVersion := make(map[string]map[string]bool)
+	for _, check := range checks {
+		kinds, ok := kindsByGroupVersion[check.api.APIVersion]
+		if !ok {
+			kinds = make(map[string]bool)
+			// 不再提供的组版本返回NotFound，当作没有资源处理
+			if resources, err := h.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(check.api.APIVersion); err == nil {
+				for _, resource := range resources.APIResources {
+					kinds[resource.Kind] = true
+				}
+			}
+			kindsByGroupVersion[check.api.APIVersion] = kinds
+		}
+		if kinds[check.api.Kind] {
+			served = append(served, models.ServedDeprecatedAPI{
+				APIVersion:  check.api.APIVersion,
+				Kind:        check.api.Kind,
+				Status:      check.status,
+				RemovedIn:   check.api.RemovedIn,
+				Replacement: check.api.Replacement,
+			})
+		}
+	}
+	return served
+}
+
+// deprecatedAPIResource 需要扫描的资源：弃用表中的类型在集群中的首选版本
+type deprecatedAPIResource struct {
+	kind       string
+	resource   schema.GroupVersionResource
+	namespaced bool
+}
+
+// deprecatedAPIResources 将弃用表中的类型解析为集群中提供的资源，只保留弃用版本或替代版本所在API组中的同名类型，避免扫描同名的CRD
+func (h *UtilityHandler) deprecatedAPIResources(checks []deprecatedAPICheck, report *models.DeprecatedAPIReport) []deprecatedAPIResource {
+	groupsByKind := make(map[string][]string)
+	var kinds []string
+	for _, check := range checks {
+		if _, ok := groupsByKind[check.api.Kind]; !ok {
+			kinds = append(kinds, check.api.Kind)
+		}
+		for _, apiVersion := range []string{check.api.APIVersion, check.api.Replacement} {
+			if gv, err := schema.ParseGroupVersion(apiVersion); err == nil && apiVersion != "" {
+				groupsByKind[check.api.Kind] = append(groupsByKind[check.api.Kind], gv.Group)
+			}
+		}
+	}
+
+	var resources []deprecatedAPIResource
+	seen := make(map[schema.GroupVersionResource]bool)
+	for _, kind := range kinds {
+		mappings, err := utils.ResolveKind(h.Client, kind)
+		if err != nil {
+			// 集群中已没有该类型，例如PodSecurityPolicy
+			continue
+		}
+		for _, mapping := range mappings {
+			if mapping.GroupVersionKind.Kind != kind || !slices.Contains(groupsByKind[kind], mapping.GroupVersionKind.Group) || seen[mapping.Resource] {
+				continue
+			}
+			seen[mapping.Resource] = true
+			resources = append(resources, deprecatedAPIResource{
+				kind:       kind,
+				resource:   mapping.Resource,
+				namespaced: utils.IsNamespacedMapping(mapping),
+			})
+		}
+	}
+	if len(resources) == 0 && len(checks) > 0 {
+		report.Warnings = append(report.Warnings, "none of the deprecated kinds are served by the cluster")
+	}
+	return resources
+}
+
+// deprecatedAPIFindings 检查对象的managedFields和last-applied-configuration注解中使用的弃用API版本
+func deprecatedAPIFindings(obj *unstructured.Unstructured, kind string, checks []deprecatedAPICheck) []models.DeprecatedAPIFinding {
+	sourcesByVersion := make(map[string][]string)
+	for _, entry := range obj.GetManagedFields() {
+		if entry.APIVersion != "" && !slices.Contains(sourcesByVersion[entry.APIVersion], entry.Manager) {
+			sourcesByVersion[entry.APIVersion] = append(sourcesByVersion[entry.APIVersion], entry.Manager)
+		}
+	}
+	if lastApplied := obj.GetAnnotations()["kubectl.kubernetes.io/last-applied-configuration"]; lastApplied != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err == nil && applied.APIVersion != "" {
+			sourcesByVersion[applied.APIVersion] = append(sourcesByVersion[applied.APIVersion], lastAppliedSource)
+		}
+	}
+
+	var findings []models.DeprecatedAPIFinding
+	for _, check := range checks {
+		sources, ok := sourcesByVersion[check.api.APIVersion]
+		if !ok || check.api.Kind != kind {
+			continue
+		}
+		findings = append(findings, models.DeprecatedAPIFinding{
+			Namespace:   obj.GetNamespace(),
+			Kind:        kind,
+			Name:        obj.GetName(),
+			APIVersion:  check.api.APIVersion,
+			Sources:     sources,
+			Status:      check.status,
+			RemovedIn:   check.api.RemovedIn,
+			Replacement: check.api.Replacement,
+			Note:        check.api.Note,
+		})
+	}
+	return findings
+}
+
+// summarizeDeprecatedAPIFindings 对发现排序，按状态计数并按命名空间汇总需要迁移的对象
+func summarizeDeprecatedAPIFindings(report *models.DeprecatedAPIReport) {
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Status != b.Status {
+			return a.Status == models.APIStatusRemoved
+		}
+		return strings.Join([]string{a.Namespace, a.Kind, a.Name}, "/") < strings.Join([]string{b.Namespace, b.Kind, b.Name}, "/")
+	})
+
+	counts := make(map[string]int)
+	for _, finding := range report.Findings {
+		switch finding.Status {
+		case models.APIStatusRemoved:
+			report.Removed++
+		case models.APIStatusDeprecated:
+			report.Deprecated++
+		}
+		if finding.Namespace != "" {
+			counts[finding.Namespace]++
+		}
+	}
+	for namespace, objects := range counts {
+		report.ByNamespace = append(report.ByNamespace, models.NamespaceMigration{Namespace: namespace, Objects: objects})
+	}
+	sort.Slice(report.ByNamespace, func(i, j int) bool {
+		if report.ByNamespace[i].Objects != report.ByNamespace[j].Objects {
+			return report.ByNamespace[i].Objects > report.ByNamespace[j].Objects
+		}
+		return report.ByNamespace[i].Namespace < report.ByNamespace[j].Namespace
+	})
+}
+
+// nodeVersionSkew 比较节点kubelet与当前控制面及目标版本的次版本偏差
+func nodeVersionSkew(node, kubeletVersion string, server, target *version.Version) models.NodeVersionSkew {
+	skew := models.NodeVersionSkew{Node: node, KubeletVersion: kubeletVersion, Status: models.SkewStatusOK}
+	kubelet, err := utils.ParseKubernetesVersion(kubeletVersion)
+	if err != nil {
+		skew.Status = models.SkewStatusUnsupported
+		skew.Message = err.Error()
+		return skew
+	}
+	skew.Skew = utils.MinorSkew(server, kubelet)
+	switch {
+	case skew.Skew < 0:
+		skew.Status = models.SkewStatusNewer
+		skew.Message = "kubelet must not be newer than kube-apiserver"
+	case skew.Skew > utils.MaxKubeletSkew:
+		skew.Status = models.SkewStatusUnsupported
+		skew.Message = fmt.Sprintf("kubelet is %d minor versions behind kube-apiserver, at most %d are supported", skew.Skew, utils.MaxKubeletSkew)
+	case utils.MinorSkew(target, kubelet) > utils.MaxKubeletSkew:
+		skew.Status = models.SkewStatusBlocksUpgrade
+		skew.Message = fmt.Sprintf("kubelet would be %d minor versions behind after upgrading to %d.%d; upgrade the node first",
+			utils.MinorSkew(target, kubelet), target.Major(), target.Minor())
+	}
+	return skew
+}
@@ -0,0 +1,96 @@
+package tool
+
+import (
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+func TestDeprecatedAPIFindings(t *testing.T) {
+	checks := []deprecatedAPICheck{
+		{api: models.DeprecatedAPI{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", RemovedIn: "1.26", Replacement: "autoscaling/v2"}, status: models.APIStatusRemoved},
+		{api: models.DeprecatedAPI{APIVersion: "batch/v1beta1", Kind: "CronJob", RemovedIn: "1.25", Replacement: "batch/v1"}, status: models.APIStatusRemoved},
+	}
+	hpa := &unstructured.Unstructured{}
+	hpa.SetNamespace("team-a")
+	hpa.SetName("web")
+	hpa.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "helm", APIVersion: "autoscaling/v2beta2"},
+		{Manager: "kube-controller-manager", APIVersion: "autoscaling/v2"},
+		{Manager: "helm", APIVersion: "autoscaling/v2beta2", Subresource: "status"},
+	})
+	hpa.SetAnnotations(map[string]string{utils.LastAppliedConfigAnnotation: `{"apiVersion":"autoscaling/v2beta2","kind":"HorizontalPodAutoscaler"}`})
+
+	findings := deprecatedAPIFindings(hpa, "HorizontalPodAutoscaler", checks)
+	if len(findings) != 1 {
+		t.Fatalf("findings = %+v, want one", findings)
+	}
+	if finding := findings[0]; finding.Name != "web" || finding.Replacement != "autoscaling/v2" ||
+		!slices.Equal(finding.Sources, []string{"helm", lastAppliedSource}) {
+		t.Fatalf("finding = %+v, want helm and the last applied configuration as sources", finding)
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl", APIVersion: "autoscaling/v2"}})
+	if findings := deprecatedAPIFindings(current, "HorizontalPodAutoscaler", checks); len(findings) != 0 {
+		t.Fatalf("object written with a current API has findings %+v", findings)
+	}
+}
+
+func TestSummarizeDeprecatedAPIFindings(t *testing.T) {
+	report := &models.DeprecatedAPIReport{Findings: []models.DeprecatedAPIFinding{
+		{Namespace: "b", Kind: "CronJob", Name: "nightly", Status: models.APIStatusDeprecated},
+		{Namespace: "a", Kind: "Ingress", Name: "web", Status: models.APIStatusRemoved},
+		{Namespace: "b", Kind: "Ingress", Name: "api", Status: models.APIStatusRemoved},
+	}}
+	summarizeDeprecatedAPIFindings(report)
+
+	var order []string
+	for _, finding := range report.Findings {
+		order = append(order, finding.Namespace+"/"+finding.Name)
+	}
+	if !slices.Equal(order, []string{"a/web", "b/api", "b/nightly"}) {
+		t.Fatalf("order = %v, want removed APIs first", order)
+	}
+	if report.Removed != 2 || report.Deprecated != 1 {
+		t.Fatalf("removed %d, deprecated %d", report.Removed, report.Deprecated)
+	}
+	if want := []models.NamespaceMigration{{Namespace: "b", Objects: 2}, {Namespace: "a", Objects: 1}}; !slices.Equal(report.ByNamespace, want) {
+		t.Fatalf("by namespace = %+v, want %+v", report.ByNamespace, want)
+	}
+}
+
+func TestNodeVersionSkew(t *testing.T) {
+	server, _ := utils.ParseKubernetesVersion("1.29.4")
+	target, _ := utils.ParseKubernetesVersion("1.30")
+
+	tests := []struct {
+		kubelet string
+		skew    int
+		status  string
+	}{
+		{kubelet: "v1.29.4", skew: 0, status: models.SkewStatusOK},
+		{kubelet: "v1.27.9", skew: 2, status: models.SkewStatusOK},
+		{kubelet: "v1.26.1", skew: 3, status: models.SkewStatusBlocksUpgrade},
+		{kubelet: "v1.25.0", skew: 4, status: models.SkewStatusUnsupported},
+		{kubelet: "v1.30.0", skew: -1, status: models.SkewStatusNewer},
+		{kubelet: "unknown", status: models.SkewStatusUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kubelet, func(t *testing.T) {
+			skew := nodeVersionSkew("node-1", tt.kubelet, server, target)
+			if skew.Skew != tt.skew || skew.Status != tt.status {
+				t.Fatalf("skew = %+v, want %d %s", skew, tt.skew, tt.status)
+			}
+			if tt.status != models.SkewStatusOK && skew.Message == "" {
+				t.Fatal("no message for a skew problem")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// ExportResource 导出单个资源或按标签批量导出资源，返回可重新应用的YAML
+func (h *UtilityHandler) ExportResource(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+
+	h.Log.Info("Exporting resources",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"name", name,
+		"namespace", namespace,
+		"labelSelector", labelSelector,
+	)
+
+	if kind == "" || apiVersion == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind and apiVersion"), nil
+	}
+
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+
+	var resource dynamic.ResourceInterface
+	if namespaced {
+		if namespace == "" {
+			namespace = "default"
+		}
+		resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace)
+	} else {
+		namespace = ""
+		resource = h.Client.GetDynamicClient().Resource(gvr)
+	}
+
+	var objs []*unstructured.Unstructured
+	if name != "" {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			h.Log.Error("Failed to get resource for export", "kind", kind, "name", name, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+		}
+		objs = append(objs, obj)
+	} else {
+		list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			h.Log.Error("Failed to list resources for export", "kind", kind, "error", err)
+			return utils.NewKubeErrorResult(err, "failed to list resources"), nil
+		}
+		sort.Slice(list.Items, func(i, j int) bool {
+			return list.Items[i].GetName() < list.Items[j].GetName()
+		})
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+	}
+
+	response := models.ExportList{
+		APIVersion:   "v1",
+		Kind:         "List",
+		Items:        make([]map[string]interface{}, 0, len(objs)),
+		ResourceKind: kind,
+	}
+	for _, obj := range objs {
+		if utils.RedactSecret(obj) {
+			response.RedactedSecrets++
+		}
+		utils.CleanForExport(obj)
+		response.Items = append(response.Items, obj.Object)
+	}
+
+	h.Log.Info("Resources exported", "kind", kind, "count", len(objs))
+
+	return utils.RenderResult(request, response), nil
+}
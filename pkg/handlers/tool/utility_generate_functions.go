@@ -0,0 +1,645 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// GENERATE_MANIFEST支持的资源类型
+const (
+	generateKindDeployment = "Deployment"
+	generateKindService    = "Service"
+	generateKindConfigMap  = "ConfigMap"
+	generateKindCronJob    = "CronJob"
+	generateKindIngress    = "Ingress"
+	generateKindPVC        = "PersistentVolumeClaim"
+)
+
+// generatedManagedBy 生成的资源中app.kubernetes.io/managed-by标签的值
+const generatedManagedBy = "kubernetes-mcp"
+
+// manifestSpec GENERATE_MANIFEST校验后的参数
+type manifestSpec struct {
+	kind           string
+	name           string
+	namespace      string
+	image          string
+	command        []string
+	replicas       int32
+	ports          []corev1.ContainerPort
+	env            []corev1.EnvVar
+	resources      corev1.ResourceRequirements
+	serviceAccount string
+	labels         map[string]string
+	// CronJob参数
+	schedule string
+	// ConfigMap参数
+	data map[string]string
+	// Service参数
+	serviceType corev1.ServiceType
+	// Ingress参数
+	host         string
+	path         string
+	serviceName  string
+	servicePort  string
+	ingressClass string
+	tlsSecret    string
+	// PersistentVolumeClaim参数
+	storage      resource.Quantity
+	storageClass string
+	accessMode   corev1.PersistentVolumeAccessMode
+}
+
+// GenerateManifest 根据结构化参数使用类型化的API结构体生成资源清单
+func (h *UtilityHandler) GenerateManifest(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+
+	h.Log.Info("Generating manifest", "kind", kind, "name", name)
+
+	spec, errs := parseManifestSpec(arguments)
+	if len(errs) > 0 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: fmt.Sprintf("invalid parameters for %s: %s", kind, strings.Join(errs, "; ")),
+			Details: errs,
+		}), nil
+	}
+
+	var obj runtime.Object
+	var notes []string
+	switch spec.kind {
+	case generateKindDeployment:
+		obj, notes = generateDeployment(spec)
+	case generateKindService:
+		obj = generateService(spec)
+	case generateKindConfigMap:
+		obj = generateConfigMap(spec)
+	case generateKindCronJob:
+		obj, notes = generateCronJob(spec)
+	case generateKindIngress:
+		obj = generateIngress(spec)
+	case generateKindPVC:
+		obj = generatePVC(spec)
+	}
+
+	manifest, err := marshalGenerated(obj)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to serialize %s: %v", spec.kind, err)), nil
+	}
+	return utils.RenderResult(request, models.GeneratedManifest{
+		Kind:      spec.kind,
+		Name:      spec.name,
+		Namespace: spec.namespace,
+		YAML:      manifest,
+		Notes:     notes,
+	}), nil
+}
+
+// parseManifestSpec 解析并校验参数，返回所有校验错误而不是只返回第一个
+func parseManifestSpec(arguments map[string]interface{}) (*manifestSpec, []string) {
+	var errs []string
+	spec := &manifestSpec{replicas: 1, path: "/", accessMode: corev1.ReadWriteOnce, serviceType: corev1.ServiceTypeClusterIP}
+
+	spec.kind, _ = arguments["kind"].(string)
+	switch spec.kind {
+	case generateKindDeployment, generateKindService, generateKindConfigMap,
+		generateKindCronJob, generateKindIngress, generateKindPVC:
+	default:
+		return nil, []string{fmt.Sprintf("kind %q is not supported, must be one of Deployment, Service, ConfigMap, CronJob, Ingress, PersistentVolumeClaim", spec.kind)}
+	}
+
+	spec.name, _ = arguments["name"].(string)
+	nameErrs := validation.IsDNS1123Subdomain(spec.name)
+	if spec.kind == generateKindService || spec.kind == generateKindCronJob {
+		// Service名称会用作DNS标签；CronJob名称还需为生成的Job名称留出后缀
+		nameErrs = validation.IsDNS1035Label(spec.name)
+		if spec.kind == generateKindCronJob && len(spec.name) > 52 {
+			nameErrs = append(nameErrs, "must be no more than 52 characters")
+		}
+	}
+	for _, e := range nameErrs {
+		errs = append(errs, fmt.Sprintf("name %q: %s", spec.name, e))
+	}
+
+	spec.namespace, _ = arguments["namespace"].(string)
+	if spec.namespace == "" {
+		spec.namespace = "default"
+	}
+	for _, e := range validation.IsDNS1123Label(spec.namespace) {
+		errs = append(errs, fmt.Sprintf("namespace %q: %s", spec.namespace, e))
+	}
+
+	needsPod := spec.kind == generateKindDeployment || spec.kind == generateKindCronJob
+	spec.image, _ = arguments["image"].(string)
+	if needsPod && strings.TrimSpace(spec.image) == "" {
+		errs = append(errs, fmt.Sprintf("image is required for %s", spec.kind))
+	}
+	if command, _ := arguments["command"].(string); command != "" {
+		spec.command = strings.Fields(command)
+	}
+
+	if value, ok := arguments["replicas"].(float64); ok {
+		if value < 0 || value != float64(int32(value)) {
+			errs = append(errs, fmt.Sprintf("replicas %v: must be a non-negative integer", value))
+		} else {
+			spec.replicas = int32(value)
+		}
+	}
+
+	portsArg, _ := arguments["ports"].(string)
+	ports, portErrs := parseGeneratePorts(portsArg)
+	spec.ports = ports
+	errs = append(errs, portErrs...)
+	if spec.kind == generateKindService && len(spec.ports) == 0 && len(portErrs) == 0 {
+		errs = append(errs, "ports is required for Service")
+	}
+
+	env, err := utils.StringMapArgument(arguments, "env")
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	envKeys := make([]string, 0, len(env))
+	for key := range env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+	for _, key := range envKeys {
+		for _, e := range validation.IsEnvVarName(key) {
+			errs = append(errs, fmt.Sprintf("env %q: %s", key, e))
+		}
+		spec.env = append(spec.env, corev1.EnvVar{Name: key, Value: env[key]})
+	}
+
+	var resourceErrs []string
+	spec.resources.Requests, resourceErrs = parseGenerateResources(arguments, "requests")
+	errs = append(errs, resourceErrs...)
+	spec.resources.Limits, resourceErrs = parseGenerateResources(arguments, "limits")
+	errs = append(errs, resourceErrs...)
+	for name, request := range spec.resources.Requests {
+		if limit, ok := spec.resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			errs = append(errs, fmt.Sprintf("requests.%s %s must be less than or equal to limits.%s %s", name, request.String(), name, limit.String()))
+		}
+	}
+
+	spec.serviceAccount, _ = arguments["serviceAccount"].(string)
+	if spec.serviceAccount != "" {
+		for _, e := range validation.IsDNS1123Subdomain(spec.serviceAccount) {
+			errs = append(errs, fmt.Sprintf("serviceAccount %q: %s", spec.serviceAccount, e))
+		}
+	}
+
+	labels, err := utils.StringMapArgument(arguments, "labels")
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	for key, value := range labels {
+		for _, e := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Sprintf("label key %q: %s", key, e))
+		}
+		for _, e := range validation.IsValidLabelValue(value) {
+			errs = append(errs, fmt.Sprintf("label %q value %q: %s", key, value, e))
+		}
+	}
+	spec.labels = labels
+
+	switch spec.kind {
+	case generateKindCronJob:
+		spec.schedule, _ = arguments["schedule"].(string)
+		if fields := strings.Fields(spec.schedule); len(fields) != 5 && !strings.HasPrefix(spec.schedule, "@") {
+			errs = append(errs, fmt.Sprintf("schedule %q: must be a cron expression with 5 fields, e.g. '*/5 * * * *', or a macro such as '@hourly'", spec.schedule))
+		}
+	case generateKindConfigMap:
+		spec.data, err = utils.StringMapArgument(arguments, "data")
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		for key := range spec.data {
+			for _, e := range validation.IsConfigMapKey(key) {
+				errs = append(errs, fmt.Sprintf("data key %q: %s", key, e))
+			}
+		}
+	case generateKindService:
+		if serviceType, _ := arguments["serviceType"].(string); serviceType != "" {
+			spec.serviceType = corev1.ServiceType(serviceType)
+		}
+		switch spec.serviceType {
+		case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		default:
+			errs = append(errs, fmt.Sprintf("serviceType %q: must be ClusterIP, NodePort or LoadBalancer", spec.serviceType))
+		}
+	case generateKindIngress:
+		errs = append(errs, parseIngressSpec(arguments, spec)...)
+	case generateKindPVC:
+		storage, _ := arguments["storage"].(string)
+		if storage == "" {
+			errs = append(errs, "storage is required for PersistentVolumeClaim, e.g. '10Gi'")
+		} else if quantity, err := resource.ParseQuantity(storage); err != nil {
+			errs = append(errs, fmt.Sprintf("storage %q: %v", storage, err))
+		} else if quantity.Sign() <= 0 {
+			errs = append(errs, fmt.Sprintf("storage %q: must be greater than zero", storage))
+		} else {
+			spec.storage = quantity
+		}
+		spec.storageClass, _ = arguments["storageClass"].(string)
+		if accessMode, _ := arguments["accessMode"].(string); accessMode != "" {
+			spec.accessMode = corev1.PersistentVolumeAccessMode(accessMode)
+		}
+		switch spec.accessMode {
+		case corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod:
+		default:
+			errs = append(errs, fmt.Sprintf("accessMode %q: must be ReadWriteOnce, ReadOnlyMany, ReadWriteMany or ReadWriteOncePod", spec.accessMode))
+		}
+	}
+	return spec, errs
+}
+
+// parseIngressSpec 解析Ingress的主机、路径和后端Service参数
+func parseIngressSpec(arguments map[string]interface{}, spec *manifestSpec) []string {
+	var errs []string
+	spec.host, _ = arguments["host"].(string)
+	if spec.host != "" {
+		// 允许"*.example.com"形式的通配符主机
+		for _, e := range validation.IsDNS1123Subdomain(strings.TrimPrefix(spec.host, "*.")) {
+			errs = append(errs, fmt.Sprintf("host %q: %s", spec.host, e))
+		}
+	}
+	if path, _ := arguments["path"].(string); path != "" {
+		spec.path = path
+	}
+	if !strings.HasPrefix(spec.path, "/") {
+		errs = append(errs, fmt.Sprintf("path %q: must start with '/'", spec.path))
+	}
+	spec.serviceName, _ = arguments["serviceName"].(string)
+	if spec.serviceName == "" {
+		spec.serviceName = spec.name
+	}
+	for _, e := range validation.IsDNS1035Label(spec.serviceName) {
+		errs = append(errs, fmt.Sprintf("serviceName %q: %s", spec.serviceName, e))
+	}
+	spec.servicePort, _ = arguments["servicePort"].(string)
+	if spec.servicePort == "" && len(spec.ports) > 0 {
+		spec.servicePort = strconv.Itoa(int(spec.ports[0].ContainerPort))
+	}
+	if spec.servicePort == "" {
+		errs = append(errs, "servicePort is required for Ingress, either a port number or a named Service port")
+	} else if number, err := strconv.Atoi(spec.servicePort); err == nil {
+		for _, e := range validation.IsValidPortNum(number) {
+			errs = append(errs, fmt.Sprintf("servicePort %d: %s", number, e))
+		}
+	} else {
+		for _, e := range validation.IsValidPortName(spec.servicePort) {
+			errs = append(errs, fmt.Sprintf("servicePort %q: %s", spec.servicePort, e))
+		}
+	}
+	spec.ingressClass, _ = arguments["ingressClass"].(string)
+	spec.tlsSecret, _ = arguments["tlsSecret"].(string)
+	if spec.tlsSecret != "" && spec.host == "" {
+		errs = append(errs, "tlsSecret requires host")
+	}
+	return errs
+}
+
+// parseGeneratePorts 解析逗号分隔的端口列表，每项格式为"[名称:]端口[/协议]"，例如"http:8080,53/UDP"
+func parseGeneratePorts(value string) ([]corev1.ContainerPort, []string) {
+	var ports []corev1.ContainerPort
+	var errs []string
+	seen := make(map[string]bool)
+	for _, entry := range utils.SplitCommaList(value) {
+		port := corev1.ContainerPort{Protocol: corev1.ProtocolTCP}
+		rest := entry
+		if name, number, ok := strings.Cut(rest, ":"); ok {
+			port.Name = name
+			rest = number
+			for _, e := range validation.IsValidPortName(name) {
+				errs = append(errs, fmt.Sprintf("port %q: name %s", entry, e))
+			}
+		}
+		if number, protocol, ok := strings.Cut(rest, "/"); ok {
+			rest = number
+			port.Protocol = corev1.Protocol(strings.ToUpper(protocol))
+			switch port.Protocol {
+			case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+			default:
+				errs = append(errs, fmt.Sprintf("port %q: protocol must be TCP, UDP or SCTP", entry))
+			}
+		}
+		number, err := strconv.Atoi(rest)
+		if err != nil || len(validation.IsValidPortNum(number)) > 0 {
+			errs = append(errs, fmt.Sprintf("port %q: port must be an integer between 1 and 65535", entry))
+			continue
+		}
+		port.ContainerPort = int32(number)
+		key := fmt.Sprintf("%d/%s", number, port.Protocol)
+		if seen[key] {
+			errs = append(errs, fmt.Sprintf("port %q: duplicate port %s", entry, key))
+			continue
+		}
+		seen[key] = true
+		ports = append(ports, port)
+	}
+	// 多个端口时Service要求每个端口都有名称
+	if len(ports) > 1 {
+		for i := range ports {
+			if ports[i].Name == "" {
+				ports[i].Name = fmt.Sprintf("%s-%d", strings.ToLower(string(ports[i].Protocol)), ports[i].ContainerPort)
+			}
+		}
+	}
+	return ports, errs
+}
+
+// parseGenerateResources 解析requests或limits对象，值通过resource.ParseQuantity校验
+func parseGenerateResources(arguments map[string]interface{}, key string) (corev1.ResourceList, []string) {
+	values, err := utils.StringMapArgument(arguments, key)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var errs []string
+	list := corev1.ResourceList{}
+	for name, value := range values {
+		switch corev1.ResourceName(name) {
+		case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		default:
+			errs = append(errs, fmt.Sprintf("%s.%s: unsupported resource, must be cpu, memory or ephemeral-storage", key, name))
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s.%s %q: %v", key, name, value, err))
+			continue
+		}
+		if quantity.Sign() < 0 {
+			errs = append(errs, fmt.Sprintf("%s.%s %q: must not be negative", key, name, value))
+			continue
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	sort.Strings(errs)
+	return list, errs
+}
+
+// generatedLabels 返回标准的app.kubernetes.io标签和用户指定的标签
+func generatedLabels(spec *manifestSpec) map[string]string {
+	labels := map[string]string{}
+	for key, value := range spec.labels {
+		labels[key] = value
+	}
+	for key, value := range generatedSelector(spec.name) {
+		labels[key] = value
+	}
+	labels["app.kubernetes.io/managed-by"] = generatedManagedBy
+	return labels
+}
+
+// generatedSelector 返回工作负载与Service使用的选择器标签
+func generatedSelector(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     name,
+		"app.kubernetes.io/instance": name,
+	}
+}
+
+// generatedObjectMeta 返回带有标准标签的对象元数据
+func generatedObjectMeta(spec *manifestSpec) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      spec.name,
+		Namespace: spec.namespace,
+		Labels:    generatedLabels(spec),
+	}
+}
+
+// generatedPodSpec 构建带有非root安全上下文的单容器Pod规格，有端口时添加TCP探针
+func generatedPodSpec(spec *manifestSpec) (corev1.PodSpec, []string) {
+	var notes []string
+	container := corev1.Container{
+		Name:            spec.name,
+		Image:           spec.image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         spec.command,
+		Ports:           spec.ports,
+		Env:             spec.env,
+		Resources:       spec.resources,
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+	if len(spec.resources.Requests) == 0 && len(spec.resources.Limits) == 0 {
+		notes = append(notes, "未指定requests和limits，建议设置以便调度和配额计算")
+	}
+	if spec.kind == generateKindDeployment {
+		if len(spec.ports) > 0 && spec.ports[0].Protocol == corev1.ProtocolTCP {
+			// 探针占位：默认检查第一个TCP端口，存在HTTP健康检查接口时应替换为httpGet
+			probePort := intstr.FromInt32(spec.ports[0].ContainerPort)
+			if spec.ports[0].Name != "" {
+				probePort = intstr.FromString(spec.ports[0].Name)
+			}
+			container.ReadinessProbe = &corev1.Probe{
+				ProbeHandler:        corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: probePort}},
+				InitialDelaySeconds: 5,
+				PeriodSeconds:       10,
+			}
+			container.LivenessProbe = &corev1.Probe{
+				ProbeHandler:        corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: probePort}},
+				InitialDelaySeconds: 15,
+				PeriodSeconds:       20,
+			}
+			notes = append(notes, "已添加检查第一个TCP端口的就绪与存活探针占位，应用提供HTTP健康检查接口时建议改为httpGet")
+		} else {
+			notes = append(notes, "未指定TCP端口，未生成探针")
+		}
+	}
+	notes = append(notes, "Pod以非root用户运行（runAsNonRoot），镜像需使用非0的USER或在securityContext中指定runAsUser")
+
+	podSpec := corev1.PodSpec{
+		ServiceAccountName: spec.serviceAccount,
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot: ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+		Containers: []corev1.Container{container},
+	}
+	return podSpec, notes
+}
+
+// generateDeployment 生成Deployment
+func generateDeployment(spec *manifestSpec) (runtime.Object, []string) {
+	podSpec, notes := generatedPodSpec(spec)
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: generateKindDeployment},
+		ObjectMeta: generatedObjectMeta(spec),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(spec.replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: generatedSelector(spec.name)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: generatedLabels(spec)},
+				Spec:       podSpec,
+			},
+		},
+	}, notes
+}
+
+// generateService 生成选择同名工作负载Pod的Service
+func generateService(spec *manifestSpec) runtime.Object {
+	ports := make([]corev1.ServicePort, 0, len(spec.ports))
+	for _, port := range spec.ports {
+		targetPort := intstr.FromInt32(port.ContainerPort)
+		if port.Name != "" {
+			targetPort = intstr.FromString(port.Name)
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   port.Protocol,
+			Port:       port.ContainerPort,
+			TargetPort: targetPort,
+		})
+	}
+	return &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: generateKindService},
+		ObjectMeta: generatedObjectMeta(spec),
+		Spec: corev1.ServiceSpec{
+			Type:     spec.serviceType,
+			Selector: generatedSelector(spec.name),
+			Ports:    ports,
+		},
+	}
+}
+
+// generateConfigMap 生成ConfigMap
+func generateConfigMap(spec *manifestSpec) runtime.Object {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: generateKindConfigMap},
+		ObjectMeta: generatedObjectMeta(spec),
+		Data:       spec.data,
+	}
+}
+
+// generateCronJob 生成禁止并发运行的CronJob
+func generateCronJob(spec *manifestSpec) (runtime.Object, []string) {
+	podSpec, notes := generatedPodSpec(spec)
+	podSpec.RestartPolicy = corev1.RestartPolicyOnFailure
+	return &batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: generateKindCronJob},
+		ObjectMeta: generatedObjectMeta(spec),
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   spec.schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](3),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](3),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: generatedLabels(spec)},
+						Spec:       podSpec,
+					},
+				},
+			},
+		},
+	}, notes
+}
+
+// generateIngress 生成将单个主机和路径转发到Service的Ingress
+func generateIngress(spec *manifestSpec) runtime.Object {
+	backend := networkingv1.IngressServiceBackend{Name: spec.serviceName}
+	if number, err := strconv.Atoi(spec.servicePort); err == nil {
+		backend.Port.Number = int32(number)
+	} else {
+		backend.Port.Name = spec.servicePort
+	}
+	ingress := &networkingv1.Ingress{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: generateKindIngress},
+		ObjectMeta: generatedObjectMeta(spec),
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: spec.host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     spec.path,
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend:  networkingv1.IngressBackend{Service: &backend},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if spec.ingressClass != "" {
+		ingress.Spec.IngressClassName = ptr.To(spec.ingressClass)
+	}
+	if spec.tlsSecret != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      []string{spec.host},
+			SecretName: spec.tlsSecret,
+		}}
+	}
+	return ingress
+}
+
+// generatePVC 生成PersistentVolumeClaim
+func generatePVC(spec *manifestSpec) runtime.Object {
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: generateKindPVC},
+		ObjectMeta: generatedObjectMeta(spec),
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{spec.accessMode},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: spec.storage},
+			},
+		},
+	}
+	if spec.storageClass != "" {
+		pvc.Spec.StorageClassName = ptr.To(spec.storageClass)
+	}
+	return pvc
+}
+
+// marshalGenerated 将类型化对象序列化为YAML，移除空的status和creationTimestamp字段
+func marshalGenerated(obj runtime.Object) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	utils.CleanForExport(u)
+	for _, path := range [][]string{
+		{"spec", "template", "metadata", "creationTimestamp"},
+		{"spec", "jobTemplate", "metadata", "creationTimestamp"},
+		{"spec", "jobTemplate", "spec", "template", "metadata", "creationTimestamp"},
+	} {
+		unstructured.RemoveNestedField(u.Object, path...)
+	}
+	return utils.MarshalManifest([]*unstructured.Unstructured{u})
+}
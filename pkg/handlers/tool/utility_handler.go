@@ -5,7 +5,7 @@ import (
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
@@ -25,6 +25,89 @@ const (
 	VALIDATE_MANIFEST = "VALIDATE_MANIFEST"
 	DIFF_MANIFEST     = "DIFF_MANIFEST"
 	GET_EVENTS        = "GET_EVENTS"
+	CHECK_QUOTA_FIT   = "CHECK_QUOTA_FIT"
+
+	// 清单生成工具
+	GENERATE_MANIFEST = "GENERATE_MANIFEST"
+
+	// kustomize构建工具
+	APPLY_KUSTOMIZATION  = "APPLY_KUSTOMIZATION"
+	RENDER_KUSTOMIZATION = "RENDER_KUSTOMIZATION"
+
+	// 从URL应用清单工具
+	APPLY_FROM_URL = "APPLY_FROM_URL"
+
+	// 缓存管理与服务器状态工具
+	REFRESH_DISCOVERY_CACHE = "REFRESH_DISCOVERY_CACHE"
+	INVALIDATE_CACHE        = "INVALIDATE_CACHE"
+	GET_SERVER_STATUS       = "GET_SERVER_STATUS"
+
+	// Helm发布查询工具
+	LIST_HELM_RELEASES = "LIST_HELM_RELEASES"
+	GET_HELM_RELEASE   = "GET_HELM_RELEASE"
+
+	// 资源监听工具
+	WATCH_RESOURCE = "WATCH_RESOURCE"
+
+	// 事件通知工具
+	NOTIFY_ON_EVENT = "NOTIFY_ON_EVENT"
+
+	// 条件等待工具
+	WAIT_FOR = "WAIT_FOR"
+
+	// 会话笔记工具
+	SAVE_NOTE   = "SAVE_NOTE"
+	GET_NOTES   = "GET_NOTES"
+	DELETE_NOTE = "DELETE_NOTE"
+
+	// 会话调用记录工具
+	GET_TOOL_HISTORY = "GET_TOOL_HISTORY"
+
+	// 资源比较工具
+	COMPARE_RESOURCES = "COMPARE_RESOURCES"
+
+	// 资源导出工具
+	EXPORT_RESOURCE = "EXPORT_RESOURCE"
+
+	// 资源所有权查询工具
+	GET_OWNERSHIP_GRAPH = "GET_OWNERSHIP_GRAPH"
+
+	// 镜像清单工具
+	LIST_IMAGES = "LIST_IMAGES"
+
+	// 镜像一致性检查工具
+	CHECK_IMAGE_CONSISTENCY = "CHECK_IMAGE_CONSISTENCY"
+
+	// 升级前弃用API检查工具
+	CHECK_DEPRECATED_APIS = "CHECK_DEPRECATED_APIS"
+
+	// 标签与注解编辑工具
+	LABEL_RESOURCE    = "LABEL_RESOURCE"
+	ANNOTATE_RESOURCE = "ANNOTATE_RESOURCE"
+
+	// 命名空间备份与恢复工具
+	BACKUP_NAMESPACE  = "BACKUP_NAMESPACE"
+	RESTORE_NAMESPACE = "RESTORE_NAMESPACE"
+
+	// 孤立资源查找工具
+	FIND_ORPHANED_RESOURCES = "FIND_ORPHANED_RESOURCES"
+
+	// 资源拓扑导出工具
+	EXPORT_TOPOLOGY = "EXPORT_TOPOLOGY"
+
+	// 按近似名称查找资源工具
+	FIND_RESOURCE_BY_NAME = "FIND_RESOURCE_BY_NAME"
+
+	// 资源状态条件汇总工具
+	GET_RESOURCE_CONDITIONS = "GET_RESOURCE_CONDITIONS"
+
+	// 命名空间快照与比较工具
+	SNAPSHOT_NAMESPACE = "SNAPSHOT_NAMESPACE"
+	DIFF_SNAPSHOT      = "DIFF_SNAPSHOT"
+
+	// 准入Webhook清单与失败诊断工具
+	LIST_WEBHOOKS              = "LIST_WEBHOOKS"
+	DIAGNOSE_ADMISSION_FAILURE = "DIAGNOSE_ADMISSION_FAILURE"
 )
 
 // UtilityHandler 提供通用工具功能
@@ -43,23 +126,72 @@ func NewUtilityHandler(client kubernetes.Client) interfaces.ToolHandler {
 }
 
 // Register 注册通用工具方法
-func (h *UtilityHandler) Register(server *server.MCPServer) {
+func (h *UtilityHandler) Register(server interfaces.ToolRegistrar) {
 	h.Log.Info("Registering utility handlers")
 	// 获取当前时间工具
 	server.AddTool(mcp.NewTool(GET_CURRENT_TIME,
 		mcp.WithDescription("获取系统当前时间。用于同步集群操作时间戳，确保操作记录的准确性。常用于日志记录、资源创建时间标记等场景。返回格式：RFC3339标准时间格式。"),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetCurrentTime)
 	// 获取集群信息工具
 	server.AddTool(mcp.NewTool(GET_CLUSTER_INFO,
 		mcp.WithDescription("获取Kubernetes集群详细信息。包括：集群版本、节点数量、命名空间列表、API Server地址等核心信息。用于集群状态检查、版本兼容性验证、集群资源概览等场景。建议在执行关键操作前先检查集群状态。"),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetClusterInfo)
 
+	// 刷新Discovery缓存工具
+	server.AddTool(mcp.NewTool(REFRESH_DISCOVERY_CACHE,
+		mcp.WithDescription("清空并重新加载API发现缓存和命名空间列表缓存。服务器默认缓存API组、资源类型和命名空间列表以减少对API Server的请求；安装或删除CRD、创建或删除命名空间后调用此工具可立即看到变化。返回刷新后的API组、资源和命名空间数量以及缓存命中统计。"),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.RefreshDiscoveryCache)
+
+	// 使缓存失效工具
+	server.AddTool(mcp.NewTool(INVALIDATE_CACHE,
+		mcp.WithDescription("使匹配的缓存项立即失效，下次访问时重新从API Server获取。服务器缓存命名空间列表（namespaces）、节点列表（nodes）和CRD列表（crds），供DESCRIBE_NAMESPACE、LIST_NODES、LIST_NODE_TAINTS、LIST_CRDS等工具复用；通过本服务器创建或删除这些对象时会自动失效，集群在外部发生变化后调用此工具可立即看到变化。返回失效的缓存项和各缓存项的命中统计。"),
+		mcp.WithString("pattern",
+			mcp.Description("缓存键的通配符，例如：'nodes'、'n*'、'*'。匹配'discovery'时同时清空API发现缓存。默认为'*'，即所有缓存。"),
+			mcp.DefaultString("*"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.InvalidateCache)
+
+	// 服务器状态工具
+	server.AddTool(mcp.NewTool(GET_SERVER_STATUS,
+		mcp.WithDescription("获取MCP服务器自身的运行状态。包括：版本与构建信息、运行时长、影响工具行为的配置项、当前kubeconfig上下文与API Server地址（不含凭据）、连通性检查结果与延迟、按类别统计的正在执行和排队的工具调用数、因服务器繁忙被拒绝的调用数、Discovery缓存时长与命中统计、启动以来各工具的调用次数，以及可选的当前会话笔记。用于确认当前连接的是哪个集群、排查写操作失败的原因，以及收到服务器繁忙错误时判断何时重试。"),
+		mcp.WithBoolean("includeNotes",
+			mcp.Description("是否附带当前会话通过SAVE_NOTE保存的笔记。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetServerStatus)
+
 	// 获取API资源工具
 	server.AddTool(mcp.NewTool(GET_API_RESOURCES,
-		mcp.WithDescription("获取集群中可用的API资源列表。可选择性地按API组过滤。返回资源的版本、种类、是否支持命名空间等信息。用于资源操作前的权限检查、API版本验证、自定义资源发现等场景。注意：某些资源可能需要特定的访问权限。"),
+		mcp.WithDescription("获取集群中可用的API资源列表。每个资源包含group、version、resource、kind、namespaced、verbs、shortNames和categories；同一组中多个版本提供的资源只返回一项，version为首选版本（preferredVersion标记），其他版本列在otherVersions中。结果按组和资源名排序，可按组、命名空间范围、支持的动作和关键字过滤。用于资源操作前的API版本验证、查找资源的简称、自定义资源发现等场景。"),
 		mcp.WithString("group",
-			mcp.Description("API组名称，例如：'apps'、'batch'等。留空则返回所有API组的资源。"),
+			mcp.Description("API组或组版本，例如：'apps'、'batch/v1'。核心组写作'core'或'v1'。留空则返回所有API组的资源。"),
+		),
+		mcp.WithBoolean("namespacedOnly",
+			mcp.Description("只返回命名空间级别的资源。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("verb",
+			mcp.Description("只返回支持该动作的资源，例如：'list'、'watch'、'patch'。"),
+		),
+		mcp.WithString("search",
+			mcp.Description("按子串匹配kind、资源名或简称（不区分大小写），例如：'deploy'。"),
 		),
+		mcp.WithBoolean("includeSubresources",
+			mcp.Description("是否包含子资源，例如'pods/log'、'deployments/scale'。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetAPIResources)
 
 	// 搜索资源工具
@@ -73,7 +205,7 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("要搜索的命名空间列表，多个用逗号分隔。例如：'default,kube-system'。留空表示搜索所有命名空间。"),
 		),
 		mcp.WithString("kinds",
-			mcp.Description("要搜索的资源类型列表，多个用逗号分隔。例如：'pods,deployments'。留空表示搜索所有类型。建议指定以提高搜索效率。"),
+			mcp.Description("要搜索的资源类型列表，多个用逗号分隔。支持类型名、资源名或简称，例如：'pods,deployments'；可用'名称.API组'限定API组，例如：'ingresses.networking.k8s.io'。留空表示搜索所有类型。建议指定以提高搜索效率。"),
 		),
 		mcp.WithBoolean("matchLabels",
 			mcp.Description("是否匹配标签。启用后将检查资源的所有标签。可能增加搜索时间。"),
@@ -83,6 +215,12 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("是否匹配注解。启用后将检查资源的所有注解。可能增加搜索时间。"),
 			mcp.DefaultBool(true),
 		),
+		mcp.WithNumber("limit",
+			mcp.Description("最多返回的匹配数量，达到后立即停止搜索并在结果中标记truncated，默认100，最大1000。每个资源最多返回一个匹配，依次按名称、标签、注解判断。"),
+			mcp.DefaultNumber(100),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.SearchResources)
 
 	// 解释资源结构工具
@@ -103,11 +241,13 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("是否递归解释字段。启用后将显示所有子字段的详细信息。可能产生大量输出。"),
 			mcp.DefaultBool(false),
 		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.ExplainResource)
 
 	// 应用清单工具
 	server.AddTool(mcp.NewTool(APPLY_MANIFEST,
-		mcp.WithDescription("应用Kubernetes资源清单。支持创建、更新操作，采用声明式API。可处理单个或多个资源清单。支持dry-run模式进行预检查。使用server-side apply确保安全的多方协作。适用于资源部署、配置更新、状态管理等场景。"),
+		mcp.WithDescription("应用Kubernetes资源清单。支持创建、更新操作，采用声明式API。可处理单个或多个资源清单。支持dry-run模式进行预检查。使用server-side apply确保安全的多方协作。适用于资源部署、配置更新、状态管理等场景。返回每个文档的操作（created、configured、unchanged）和服务器返回的原始错误消息（包括准入Webhook拒绝的原因）；可选先整体试运行（atomic）或失败时删除本次新建的资源（rollbackOnError）。"),
 		mcp.WithString("yaml",
 			mcp.Description("YAML格式的资源清单。支持多文档语法（使用'---'分隔）。必须是有效的Kubernetes资源定义。"),
 			mcp.Required(),
@@ -120,8 +260,207 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("字段管理器名称，用于跟踪字段所有权。在多方管理同一资源时很重要。建议使用有意义的名称以便跟踪。"),
 			mcp.DefaultString("kubernetes-mcp"),
 		),
+		mcp.WithBoolean("checkQuota",
+			mcp.Description("是否在应用前检查清单是否超出目标命名空间的ResourceQuota（计算方式同CHECK_QUOTA_FIT）。超出时拒绝应用并返回每项配额的计算明细。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("是否先对所有文档进行服务端试运行，任一文档被拒绝时不应用任何文档并返回试运行结果。注意：清单中新建的命名空间在试运行时并不存在，其中的资源会被拒绝。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("rollbackOnError",
+			mcp.Description("某个文档应用失败时是否停止应用其余文档，并删除本次调用中新建的资源（应用前不存在的资源）。已存在资源的修改不会撤销。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.ApplyManifest)
 
+	// 生成清单工具
+	server.AddTool(mcp.NewTool(GENERATE_MANIFEST,
+		mcp.WithDescription("根据结构化参数生成常见资源的YAML清单，使用类型化的API结构体构建，字段名称不会出错，输出可直接传给APPLY_MANIFEST。支持Deployment、Service、ConfigMap、CronJob、Ingress和PersistentVolumeClaim。默认添加app.kubernetes.io/name、instance和managed-by标签（Service按相同标签选择同名工作负载的Pod），工作负载默认以非root用户运行、禁止提权并丢弃全部capabilities，Deployment有TCP端口时添加检查第一个端口的探针占位。参数在生成前校验（端口范围、资源数量格式、名称规范等），校验失败时返回所有错误。不访问集群。"),
+		mcp.WithString("kind",
+			mcp.Description("要生成的资源类型。"),
+			mcp.Enum(generateKindDeployment, generateKindService, generateKindConfigMap, generateKindCronJob, generateKindIngress, generateKindPVC),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称，同时用作容器名称和app.kubernetes.io/name标签。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间，默认为'default'。"),
+		),
+		mcp.WithString("image",
+			mcp.Description("容器镜像，Deployment和CronJob必填，例如：'nginx:1.27'。"),
+		),
+		mcp.WithString("command",
+			mcp.Description("容器命令（可选），按空格拆分为数组，不支持引号。"),
+		),
+		mcp.WithNumber("replicas",
+			mcp.Description("Deployment副本数，默认为1。"),
+			mcp.Min(0),
+		),
+		mcp.WithString("ports",
+			mcp.Description("端口列表，多个用逗号分隔，每项格式为'[名称:]端口[/协议]'，例如：'http:8080,metrics:9090,53/UDP'。协议默认为TCP。用于容器端口、Service端口和Ingress默认后端端口，Service必填。"),
+		),
+		mcp.WithObject("env",
+			mcp.Description("容器环境变量，键值均为字符串。"),
+		),
+		mcp.WithObject("requests",
+			mcp.Description("容器资源请求，键为cpu、memory或ephemeral-storage，例如：{\"cpu\": \"100m\", \"memory\": \"128Mi\"}。"),
+		),
+		mcp.WithObject("limits",
+			mcp.Description("容器资源限制，键为cpu、memory或ephemeral-storage，不能小于对应的requests。"),
+		),
+		mcp.WithString("serviceAccount",
+			mcp.Description("Pod使用的ServiceAccount名称（可选）。"),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("附加到资源和Pod模板的额外标签，键值均为字符串。"),
+		),
+		mcp.WithString("schedule",
+			mcp.Description("CronJob的调度表达式，CronJob必填，例如：'*/5 * * * *'或'@hourly'。"),
+		),
+		mcp.WithObject("data",
+			mcp.Description("ConfigMap的数据，键值均为字符串。"),
+		),
+		mcp.WithString("serviceType",
+			mcp.Description("Service类型，默认为ClusterIP。"),
+			mcp.Enum(string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer)),
+		),
+		mcp.WithString("host",
+			mcp.Description("Ingress的主机名（可选），例如：'app.example.com'。"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Ingress的路径前缀，默认为'/'。"),
+		),
+		mcp.WithString("serviceName",
+			mcp.Description("Ingress后端Service名称，默认与name相同。"),
+		),
+		mcp.WithString("servicePort",
+			mcp.Description("Ingress后端Service端口号或端口名称，默认为ports中的第一个端口。"),
+		),
+		mcp.WithString("ingressClass",
+			mcp.Description("Ingress的ingressClassName（可选）。"),
+		),
+		mcp.WithString("tlsSecret",
+			mcp.Description("Ingress的TLS证书Secret名称（可选），需同时指定host。"),
+		),
+		mcp.WithString("storage",
+			mcp.Description("PersistentVolumeClaim请求的存储容量，PersistentVolumeClaim必填，例如：'10Gi'。"),
+		),
+		mcp.WithString("storageClass",
+			mcp.Description("PersistentVolumeClaim的StorageClass名称（可选），不指定时使用集群默认StorageClass。"),
+		),
+		mcp.WithString("accessMode",
+			mcp.Description("PersistentVolumeClaim的访问模式，默认为ReadWriteOnce。"),
+			mcp.Enum(string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany), string(corev1.ReadWriteMany), string(corev1.ReadWriteOncePod)),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GenerateManifest)
+
+	// 渲染kustomization工具
+	server.AddTool(mcp.NewTool(RENDER_KUSTOMIZATION,
+		mcp.WithDescription("在服务器进程内运行kustomize构建kustomization，返回生成的多文档YAML和资源列表，不修改集群。输入可以是内联文件（kustomization.yaml及其引用的文件），也可以是服务器本地目录（需服务器配置--kustomize-root）。构建失败时原样返回kustomize的错误，其中包含出错的文件名。适用于应用前审查overlay的渲染结果。"),
+		mcp.WithObject("files",
+			mcp.Description("内联的kustomization文件，键为相对路径文件名，值为文件内容。必须在顶层包含kustomization.yaml，以及它引用的资源、补丁和生成器文件，例如：{\"kustomization.yaml\": \"...\", \"deployment.yaml\": \"...\"}。与path二选一。"),
+		),
+		mcp.WithString("path",
+			mcp.Description("服务器本地的kustomization目录，相对路径相对于--kustomize-root解析。仅当服务器配置了--kustomize-root且目录位于其中时可用。与files二选一。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.RenderKustomization)
+
+	// 应用kustomization工具
+	server.AddTool(mcp.NewTool(APPLY_KUSTOMIZATION,
+		mcp.WithDescription("在服务器进程内运行kustomize构建kustomization，并通过与APPLY_MANIFEST相同的服务器端应用逐个应用生成的资源，返回每个资源的应用结果。输入可以是内联文件（kustomization.yaml及其引用的文件），也可以是服务器本地目录（需服务器配置--kustomize-root）。构建失败时原样返回kustomize的错误，其中包含出错的文件名，不会应用任何资源。建议先用RENDER_KUSTOMIZATION或dryRun检查结果。"),
+		mcp.WithObject("files",
+			mcp.Description("内联的kustomization文件，键为相对路径文件名，值为文件内容。必须在顶层包含kustomization.yaml，以及它引用的资源、补丁和生成器文件，例如：{\"kustomization.yaml\": \"...\", \"deployment.yaml\": \"...\"}。与path二选一。"),
+		),
+		mcp.WithString("path",
+			mcp.Description("服务器本地的kustomization目录，相对路径相对于--kustomize-root解析。仅当服务器配置了--kustomize-root且目录位于其中时可用。与files二选一。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行试运行。启用后只验证和模拟执行，不实际修改集群状态。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("fieldManager",
+			mcp.Description("字段管理器名称，用于跟踪字段所有权。"),
+			mcp.DefaultString("kubernetes-mcp"),
+		),
+		mcp.WithBoolean("checkQuota",
+			mcp.Description("是否在应用前检查生成的资源是否超出目标命名空间的ResourceQuota。超出时拒绝应用。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("是否先对所有文档进行服务端试运行，任一文档被拒绝时不应用任何文档并返回试运行结果。注意：清单中新建的命名空间在试运行时并不存在，其中的资源会被拒绝。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("rollbackOnError",
+			mcp.Description("某个文档应用失败时是否停止应用其余文档，并删除本次调用中新建的资源（应用前不存在的资源）。已存在资源的修改不会撤销。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ApplyKustomization)
+
+	// 从URL应用清单工具
+	server.AddTool(mcp.NewTool(APPLY_FROM_URL,
+		mcp.WithDescription("从https地址获取清单，并通过与APPLY_MANIFEST相同的服务器端应用逐个应用其中的资源。地址必须匹配服务器配置的允许前缀（--manifest-url-prefixes），协议和主机完全相同且路径按段匹配，重定向次数有限且目标同样受限，内容大小有上限。对于GitHub或GitLab仓库地址，可以用ref和path指定分支、标签或提交以及文件路径，自动转换为原始文件地址。可传入sha256校验内容完整性，不一致时不应用任何资源。结果和服务器日志中记录获取内容的sha256，使应用的状态可以追溯到源版本。非200响应和非YAML的Content-Type会明确报告。"),
+		mcp.WithString("url",
+			mcp.Description("清单的https地址，例如'https://raw.githubusercontent.com/org/repo/v1.2.0/deploy/app.yaml'；与ref和path一起使用时为仓库地址，例如'https://github.com/org/repo'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("ref",
+			mcp.Description("分支、标签或提交（可选），仅用于GitHub或GitLab仓库地址，需与path一起指定。建议使用提交或标签以获得稳定的内容。"),
+		),
+		mcp.WithString("path",
+			mcp.Description("仓库内的文件路径（可选），例如'deploy/app.yaml'，需与ref一起指定。"),
+		),
+		mcp.WithString("sha256",
+			mcp.Description("期望的内容sha256（十六进制，可选）。获取到的内容不一致时拒绝应用。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行试运行。启用后只验证和模拟执行，不实际修改集群状态。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("fieldManager",
+			mcp.Description("字段管理器名称，用于跟踪字段所有权。"),
+			mcp.DefaultString("kubernetes-mcp"),
+		),
+		mcp.WithBoolean("checkQuota",
+			mcp.Description("是否在应用前检查清单是否超出目标命名空间的ResourceQuota。超出时拒绝应用。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("atomic",
+			mcp.Description("是否先对所有文档进行服务端试运行，任一文档被拒绝时不应用任何文档并返回试运行结果。注意：清单中新建的命名空间在试运行时并不存在，其中的资源会被拒绝。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("rollbackOnError",
+			mcp.Description("某个文档应用失败时是否停止应用其余文档，并删除本次调用中新建的资源（应用前不存在的资源）。已存在资源的修改不会撤销。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ApplyFromURL)
+
+	// 配额检查工具
+	server.AddTool(mcp.NewTool(CHECK_QUOTA_FIT,
+		mcp.WithDescription("检查资源清单能否放入目标命名空间的ResourceQuota。按副本数（DaemonSet按节点数，Job按并行度）累计清单中Pod的CPU、内存、临时存储的requests和limits，缺少requests或limits的容器按命名空间LimitRange的默认值补齐，同时统计PVC存储（包括StatefulSet的volumeClaimTemplates）、按StorageClass的存储配额以及对象数量配额。返回每项配额的上限、已用量、本次请求量、应用后用量和是否超出，以及超出的配额和超出量。已存在的对象按新建估算；带作用域的配额不参与计算。只读操作。"),
+		mcp.WithString("yaml",
+			mcp.Description("YAML格式的资源清单。支持多文档语法（使用'---'分隔）。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("覆盖清单中命名空间级对象的命名空间（可选）。不指定时使用对象自身的命名空间，缺省为'default'。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckQuotaFit)
+
 	// 验证清单工具
 	server.AddTool(mcp.NewTool(VALIDATE_MANIFEST,
 		mcp.WithDescription("验证Kubernetes资源清单的合法性。检查包括：语法正确性、必填字段、字段类型、API版本兼容性等。支持验证单个或多个资源清单。适用于部署前的配置检查、CI/CD流程中的质量控制等场景。及早发现配置错误，避免部署失败。"),
@@ -129,6 +468,8 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("要验证的YAML格式资源清单。支持多文档语法。将进行完整的结构和语义验证。"),
 			mcp.Required(),
 		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.ValidateManifest)
 
 	// 比较清单工具
@@ -138,11 +479,13 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("要比较的YAML格式资源清单。将与集群中的同名资源进行比较。必须包含资源的名称和命名空间信息。"),
 			mcp.Required(),
 		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.DiffManifest)
 
 	// 获取事件工具
 	server.AddTool(mcp.NewTool(GET_EVENTS,
-		mcp.WithDescription("获取特定资源相关的事件信息。包括：警告、错误、状态变更等事件。支持按时间范围和事件类型过滤。适用于问题诊断、状态监控、变更追踪等场景。帮助理解资源的生命周期和运行状态。注意：事件默认保留时间有限。"),
+		mcp.WithDescription("获取特定资源相关的事件信息。包括：警告、错误、状态变更等事件，按最近发生时间降序排列。includeOwned=true时沿ownerReferences找出对象的后代（Deployment→ReplicaSet→Pod、Job→Pod、StatefulSet→Pod等），将它们的事件合并为一条时间线，每条事件标注产生它的对象，效果与kubectl describe一致。适用于问题诊断、状态监控、变更追踪等场景。注意：事件默认保留时间有限。"),
 		mcp.WithString("kind",
 			mcp.Description("资源类型，例如：'Pod'、'Deployment'等。必须是集群中存在的资源类型。"),
 			mcp.Required(),
@@ -159,7 +502,720 @@ func (h *UtilityHandler) Register(server *server.MCPServer) {
 			mcp.Description("资源所在的命名空间。如果资源类型是集群级别的，此参数将被忽略。"),
 			mcp.DefaultString("default"),
 		),
+		mcp.WithBoolean("includeOwned",
+			mcp.Description("是否同时返回后代对象（如Deployment的ReplicaSet和Pod）的事件。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("maxOwned",
+			mcp.Description(fmt.Sprintf("includeOwned时最多纳入的后代对象数量。默认为%d，最大为%d。", defaultEventsMaxOwned, maxEventsMaxOwned)),
+		),
+		mcp.WithNumber("maxEvents",
+			mcp.Description(fmt.Sprintf("最多返回的事件数量，超出时保留最近的事件并标记truncated。默认为%d，最大为%d。", defaultEventsMaxEvents, maxEventsMaxEvents)),
+		),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
 	), h.GetEvents)
+
+	// 列出Helm发布工具
+	server.AddTool(mcp.NewTool(LIST_HELM_RELEASES,
+		mcp.WithDescription("列出集群中由Helm 3管理的发布（Release）。通过读取类型为helm.sh/release.v1的Secret并解码发布数据，返回发布名称、命名空间、版本号、Chart、应用版本和状态。只读操作，无需安装Helm。支持命名空间过滤和分页，适用于了解集群中已部署的应用及其来源。"),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间（可选）。不指定时列出所有命名空间中的发布。"),
+		),
+		mcp.WithBoolean("allRevisions",
+			mcp.Description("是否返回每个发布的所有历史版本。默认为false，只返回当前页中每个发布的最新版本。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("每页读取的发布记录（Secret）数量上限。默认为50。发布记录较多时配合continue参数分页读取。"),
+			mcp.DefaultNumber(defaultHelmListLimit),
+		),
+		mcp.WithString("continue",
+			mcp.Description("分页令牌。使用上一次响应中返回的continue值获取下一页结果。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListHelmReleases)
+
+	// 获取Helm发布详情工具
+	server.AddTool(mcp.NewTool(GET_HELM_RELEASE,
+		mcp.WithDescription("获取指定Helm发布的详细信息，包括渲染后的资源清单（manifest）、用户提供的values、发布说明（notes）以及可用的历史版本列表。只读操作，适用于排查部署问题、审查配置和比较版本差异。"),
+		mcp.WithString("name",
+			mcp.Description("Helm发布名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("发布所在的命名空间。"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("revision",
+			mcp.Description("发布版本号（可选）。不指定时返回最新版本。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetHelmRelease)
+
+	// 资源监听工具
+	server.AddTool(mcp.NewTool(WATCH_RESOURCE,
+		mcp.WithDescription("在限定时长内监听资源变化，收集ADDED/MODIFIED/DELETED事件并以JSON时间线返回，MODIFIED事件会汇总spec和status中发生变化的字段。适用于\"应用变更后观察接下来一段时间发生了什么\"的场景，例如观察滚动更新、Pod重启或控制器调谐过程。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Pod'、'Deployment'等。必须是集群中存在的资源类型。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，必须与资源类型匹配。例如：'v1'、'apps/v1'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间（可选）。不指定时监听所有命名空间。集群级别资源忽略此参数。"),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称（可选）。指定时只监听该资源。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），例如'app=nginx'。"),
+		),
+		mcp.WithNumber("maxDurationSeconds",
+			mcp.Description("监听时长（秒）。默认为30秒，最大为300秒。"),
+			mcp.DefaultNumber(defaultWatchDurationSeconds),
+			mcp.Min(1),
+			mcp.Max(maxWatchDurationSeconds),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.WatchResource)
+
+	// 事件通知工具
+	server.AddTool(mcp.NewTool(NOTIFY_ON_EVENT,
+		mcp.WithDescription("阻塞等待直到出现第一个匹配条件的Kubernetes事件或Pod状态，然后立即返回该事件和相关对象，超时后返回timedOut=true。适用于\"等待Pod变为OOMKilled\"、\"等待出现FailedScheduling事件\"等场景，比反复轮询GET_EVENTS更高效。默认只匹配调用之后新出现的事件。"),
+		mcp.WithString("source",
+			mcp.Description("监听来源：'events'监听Kubernetes事件，'pods'监听Pod容器状态的原因（例如OOMKilled、CrashLoopBackOff）。"),
+			mcp.Enum("events", "pods"),
+			mcp.DefaultString("events"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间（可选）。不指定时监听所有命名空间。"),
+		),
+		mcp.WithString("involvedKind",
+			mcp.Description("相关对象的资源类型（可选，仅events），例如'Pod'、'Deployment'。"),
+		),
+		mcp.WithString("involvedName",
+			mcp.Description("相关对象的名称（可选）。source为pods时为Pod名称。"),
+		),
+		mcp.WithString("reason",
+			mcp.Description("原因的正则表达式，例如'OOMKilled'、'Failed.*'。source为pods时必填。"),
+		),
+		mcp.WithString("type",
+			mcp.Description("事件类型（可选，仅events）。"),
+			mcp.Enum("Normal", "Warning"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("Pod标签选择器（可选，仅pods），例如'app=nginx'。"),
+		),
+		mcp.WithBoolean("includeExisting",
+			mcp.Description("是否匹配调用前已存在的事件或Pod状态。默认为false，只等待新出现的匹配。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("maxWaitSeconds",
+			mcp.Description("最长等待时间（秒）。默认为60秒，最大为300秒。"),
+			mcp.DefaultNumber(defaultNotifyWaitSeconds),
+			mcp.Min(1),
+			mcp.Max(maxNotifyWaitSeconds),
+		),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.NotifyOnEvent)
+
+	// 条件等待工具
+	server.AddTool(mcp.NewTool(WAIT_FOR,
+		mcp.WithDescription("阻塞等待单个对象满足条件（相当于kubectl wait），满足、确定无法满足或超时后返回最后观察到的对象和等待时长，超时时message为仍在阻塞的条件。通过watch及时发现变化并定期重新获取对象。条件三选一：condition使用内置条件，conditionType/conditionStatus等待任意状态条件，jsonPath/value等待任意字段等于指定值。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Pod'、'Deployment'、'Job'、'CustomResourceDefinition'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，必须与资源类型匹配。例如：'v1'、'apps/v1'、'batch/v1'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间（可选）。命名空间级别资源默认为'default'，集群级别资源忽略此参数。"),
+		),
+		mcp.WithString("condition",
+			mcp.Description("内置条件：Ready（工作负载按滚动更新完成、Job按完成、其他按Ready状态条件判断）、Available、Complete（Job失败时立即返回）、Established（CRD）、Deleted（对象不存在）、Rollout（kubectl rollout status的规则）。"),
+			mcp.Enum(utils.WaitConditionReady, utils.WaitConditionAvailable, utils.WaitConditionComplete,
+				utils.WaitConditionEstablished, utils.WaitConditionDeleted, utils.WaitConditionRollout),
+		),
+		mcp.WithString("conditionType",
+			mcp.Description("等待status.conditions中的条件类型（可选），例如'PodScheduled'、'Progressing'。"),
+		),
+		mcp.WithString("conditionStatus",
+			mcp.Description("conditionType期望的状态。默认为'True'。"),
+			mcp.DefaultString("True"),
+		),
+		mcp.WithString("jsonPath",
+			mcp.Description("JSONPath表达式（可选），例如'{.status.phase}'或'.status.readyReplicas'，结果与value比较。"),
+		),
+		mcp.WithString("value",
+			mcp.Description("jsonPath结果期望等于的值，例如'Running'。"),
+		),
+		mcp.WithNumber("maxWaitSeconds",
+			mcp.Description("最长等待时间（秒）。默认为60秒，最大为600秒。"),
+			mcp.DefaultNumber(defaultWaitForSeconds),
+			mcp.Min(1),
+			mcp.Max(maxWaitForSeconds),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.WaitFor)
+
+	// 会话笔记工具
+	server.AddTool(mcp.NewTool(SAVE_NOTE,
+		mcp.WithDescription("在服务器端保存一条会话笔记，用于在较长的排障过程中记录已确认的发现、排除的假设和待办事项，之后通过GET_NOTES找回。笔记按MCP会话隔离，同名key会被覆盖；超过数量上限时移除最旧的笔记，可设置过期时间。"),
+		mcp.WithString("key",
+			mcp.Description("笔记的键，例如'root-cause'、'checked-nodes'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("text",
+			mcp.Description("笔记内容，大小受服务器配置限制。"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("ttlSeconds",
+			mcp.Description("过期时间（秒，可选）。不指定或为0时不过期。"),
+			mcp.Min(0),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SaveNote)
+
+	server.AddTool(mcp.NewTool(GET_NOTES,
+		mcp.WithDescription("获取当前会话中保存的未过期笔记，按创建时间排序。恢复中断的排障过程或总结结论前调用。"),
+		mcp.WithString("key",
+			mcp.Description("笔记的键（可选）。指定时只返回该笔记。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetNotes)
+
+	server.AddTool(mcp.NewTool(DELETE_NOTE,
+		mcp.WithDescription("删除当前会话中的一条笔记，例如已被推翻的假设。"),
+		mcp.WithString("key",
+			mcp.Description("要删除的笔记的键。"),
+			mcp.Required(),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DeleteNote)
+
+	// 会话调用记录工具
+	server.AddTool(mcp.NewTool(GET_TOOL_HISTORY,
+		mcp.WithDescription("返回当前MCP会话最近的工具调用记录（工具名、主要参数、结果、错误和耗时），按调用时间排序，用于在较长的排障过程中回顾已经检查过的内容。记录只保存在服务器内存中，包括只读操作；Secret值、清单和命令等参数只保留长度。每个会话保留的记录数由服务器配置，可以通过--tool-history-size=0禁用。本工具自身的调用不被记录。"),
+		mcp.WithString("tool",
+			mcp.Description("只返回这些工具的调用记录，多个用逗号分隔，例如：'GET_POD_LOGS,DESCRIBE_RESOURCE'。"),
+		),
+		mcp.WithBoolean("errorsOnly",
+			mcp.Description("是否只返回失败的调用。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("最多返回的记录数，只保留最新的记录。默认为20。"),
+			mcp.DefaultNumber(defaultToolHistoryLimit),
+			mcp.Min(1),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetToolHistory)
+
+	// 资源比较工具
+	server.AddTool(mcp.NewTool(COMPARE_RESOURCES,
+		mcp.WithDescription("比较集群中两个对象的差异，例如不同命名空间中的同名Deployment，或同一命名空间中名称不同的两个对象。比较前会移除status、resourceVersion、managedFields等服务端字段，返回字段级差异列表（路径、旧值、新值）以及统一格式的YAML差异。任一对象不存在时会在结果中说明而不是报错。Secret只比较值的长度和指纹，不返回值。"),
+		mcp.WithString("kind",
+			mcp.Description("源对象的资源类型，例如：'Deployment'、'ConfigMap'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("源对象的API版本，例如：'v1'、'apps/v1'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("sourceName",
+			mcp.Description("源对象名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("sourceNamespace",
+			mcp.Description("源对象所在的命名空间。默认为'default'，集群级别资源忽略此参数。"),
+		),
+		mcp.WithString("targetKind",
+			mcp.Description("目标对象的资源类型（可选）。默认与源对象相同。"),
+		),
+		mcp.WithString("targetApiVersion",
+			mcp.Description("目标对象的API版本（可选）。默认与源对象相同。"),
+		),
+		mcp.WithString("targetName",
+			mcp.Description("目标对象名称（可选）。默认与源对象相同。"),
+		),
+		mcp.WithString("targetNamespace",
+			mcp.Description("目标对象所在的命名空间（可选）。默认与源对象相同。"),
+		),
+		mcp.WithString("ignoreFields",
+			mcp.Description("比较时忽略的字段路径，多个用逗号分隔，例如：'metadata.labels.pod-template-hash,spec.replicas'。列表元素可用'[0]'或'[*]'指定，省略时作用于所有元素。"),
+		),
+		mcp.WithBoolean("ignoreImageTags",
+			mcp.Description("是否忽略容器镜像的标签和摘要，只比较镜像仓库。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CompareResources)
+
+	// 资源导出工具
+	server.AddTool(mcp.NewTool(EXPORT_RESOURCE,
+		mcp.WithDescription("以可重新应用的YAML导出资源。移除status、uid、resourceVersion、creationTimestamp、managedFields等服务端字段，以及Service的clusterIP、Pod的nodeName等按类型分配的字段，结果可直接提交到Git或应用到其他集群。指定name时导出单个资源，否则按命名空间和标签选择器批量导出。json和yaml格式返回可直接应用的v1 List，text格式返回以'---'分隔的多文档YAML。Secret的值会被脱敏。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Deployment'、'ConfigMap'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，必须与资源类型匹配。例如：'v1'、'apps/v1'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称（可选）。不指定时批量导出匹配的所有资源。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("资源所在的命名空间。默认为'default'，集群级别资源忽略此参数。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("批量导出时使用的标签选择器，例如'app=foo'。指定name时忽略。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ExportResource)
+
+	// 资源所有权查询工具
+	server.AddTool(mcp.NewTool(GET_OWNERSHIP_GRAPH,
+		mcp.WithDescription("查询资源的所有权关系树。从指定对象沿ownerReferences向上找到根对象（例如Pod → ReplicaSet → Deployment），再向下列出根对象拥有的全部后代（Deployment → ReplicaSet → Pod、CronJob → Job → Pod、StatefulSet/DaemonSet → Pod和ControllerRevision；自定义控制器的子类型根据向上遍历时经过的ownerReferences发现）。返回JSON树，每个节点包含类型、名称、就绪状态、状态摘要和创建时间，起始对象带有start标记。用于回答\"这个Pod是谁创建的，它的所有者还管理哪些对象\"，可代替多次GET和LIST调用。"),
+		mcp.WithString("kind",
+			mcp.Description("起始对象的资源类型，例如：'Pod'、'Job'、'ReplicaSet'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("起始对象的API版本，例如：'v1'、'apps/v1'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("起始对象名称。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("起始对象所在的命名空间。默认为'default'，集群级别资源忽略此参数。"),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("向上和向下遍历的最大层数。默认为4，最大为8。"),
+			mcp.DefaultNumber(defaultOwnershipDepth),
+			mcp.Min(1),
+			mcp.Max(maxOwnershipDepth),
+		),
+		mcp.WithNumber("maxNodes",
+			mcp.Description("树中最多返回的对象数量，超过时标记truncated。默认为200，最大为1000。"),
+			mcp.DefaultNumber(defaultOwnershipNodes),
+			mcp.Min(1),
+			mcp.Max(maxOwnershipNodes),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetOwnershipGraph)
+
+	// 镜像清单工具
+	server.AddTool(mcp.NewTool(LIST_IMAGES,
+		mcp.WithDescription("列出集群中使用的容器镜像。扫描运行中Pod的容器和初始化容器（source=pods，由控制器创建的Pod归到其控制器名下），或扫描Deployment、StatefulSet、DaemonSet、CronJob和独立Job的Pod模板（source=workloads，反映期望状态）。返回每个不同镜像的仓库地址、路径、标签或摘要、使用它的对象、拉取策略以及未配置imagePullSecrets的对象数量，并标记使用latest标签（latest-tag）、没有标签（no-tag）或来自允许列表以外仓库（untrusted-registry）的镜像。同时按仓库和命名空间汇总。只读操作，支持分页，汇总基于当前页。"),
+		mcp.WithString("source",
+			mcp.Description("镜像来源：\n- pods：运行中的Pod（默认）\n- workloads：工作负载的Pod模板"),
+			mcp.Enum(imageSourcePods, imageSourceWorkloads),
+			mcp.DefaultString(imageSourcePods),
+		),
+		mcp.WithString("namespaces",
+			mcp.Description("要扫描的命名空间列表，多个用逗号分隔。留空表示扫描所有命名空间。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），例如'app=nginx'。"),
+		),
+		mcp.WithString("allowedRegistries",
+			mcp.Description("允许的镜像仓库，多个用逗号分隔，可带路径前缀，例如：'registry.example.com,gcr.io/my-project'。未包含'/'的Docker Hub镜像属于'docker.io'。不指定时不检查仓库。"),
+		),
+		mcp.WithBoolean("flaggedOnly",
+			mcp.Description("是否只返回带有风险标记的镜像。汇总仍基于全部镜像。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("单页扫描的最大对象数量。未指定或超过服务器上限时按上限截断。"),
+		),
+		mcp.WithString("continue",
+			mcp.Description("上一页响应中返回的continue令牌，用于获取下一页。需与上一次请求使用相同的过滤条件。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListImages)
+
+	// 镜像一致性检查工具
+	server.AddTool(mcp.NewTool(CHECK_IMAGE_CONSISTENCY,
+		mcp.WithDescription("检查Deployment、StatefulSet和DaemonSet的Pod实际运行的镜像是否与当前Pod模板一致。逐个容器（包括初始化容器）对比模板镜像与各Pod规格中的镜像，列出仍在运行旧镜像的Pod（template-mismatch；滚动更新进行中时为info，更新已结束、OnDelete策略或StatefulSet分区导致的旧Pod为warning并给出对应建议），并根据容器状态中的imageID（摘要）找出同一标签在不同Pod中解析为不同摘要的情况（tag-multiple-digests，通常是标签被覆盖推送）。compareNewest=true时以使用模板镜像、最新创建的Pod拉取的摘要为准，标出运行较旧摘要的Pod（stale-digest）。同时在扫描的所有Pod中汇总解析为多个摘要的标签及涉及的工作负载。每个发现列出受影响的Pod。所有信息来自Pod状态，不访问镜像仓库。只读操作。"),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。默认为'default'命名空间。"),
+			mcp.DefaultString("default"),
+		),
+		mcp.WithString("kind",
+			mcp.Description("工作负载类型（可选）。与name一起指定时只检查该工作负载，否则检查命名空间中所有支持的工作负载。"),
+			mcp.Enum("Deployment", "StatefulSet", "DaemonSet"),
+		),
+		mcp.WithString("name",
+			mcp.Description("工作负载名称（可选），需要同时指定kind。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），只检查标签匹配的工作负载，例如'app=nginx'。指定工作负载或选择器时，标签汇总只包括这些工作负载的Pod。"),
+		),
+		mcp.WithBoolean("compareNewest",
+			mcp.Description("是否以最新Pod拉取的摘要为准标出运行较旧摘要的Pod。默认为false，此时只报告同一标签解析为多个摘要。注意imagePullPolicy不是Always时，最新Pod所在节点上缓存的旧镜像也可能被使用。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckImageConsistency)
+
+	// 升级前弃用API与版本偏差检查工具
+	server.AddTool(mcp.NewTool(CHECK_DEPRECATED_APIS,
+		mcp.WithDescription("升级集群前检查弃用和已移除的API。按内置的弃用API表（例如policy/v1beta1 PodSecurityPolicy、batch/v1beta1 CronJob）找出在目标版本中已弃用或移除的API版本，扫描这些类型的对象，根据managedFields中各字段管理器使用的apiVersion和last-applied-configuration注解找出仍通过旧版本管理的对象，按命名空间汇总需要迁移的对象，并列出集群仍在提供的弃用API版本。同时比较各节点kubelet与控制面的版本，标记超出支持偏差（kubelet最多落后3个次版本、不能高于控制面）或升级到目标版本后将超出偏差的节点。只读操作。"),
+		mcp.WithString("targetVersion",
+			mcp.Description("计划升级到的Kubernetes版本，例如'1.29'。默认为当前控制面版本的下一个次版本。"),
+		),
+		mcp.WithString("namespaces",
+			mcp.Description("要扫描的命名空间列表，多个用逗号分隔。留空表示扫描所有命名空间以及集群级别资源；指定时不扫描集群级别资源。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.CheckDeprecatedAPIs)
+
+	// 资源标签编辑工具
+	server.AddTool(mcp.NewTool(LABEL_RESOURCE,
+		mcp.WithDescription("添加、修改或删除现有资源的标签。使用只包含metadata.labels的JSON合并补丁，不触及资源的其他字段，含斜杠的键（例如'app.kubernetes.io/name'）无需转义。指定name时修改单个对象，否则修改labelSelector匹配的所有对象；匹配数量超过maxObjects时拒绝执行，可先用dryRun预览匹配的对象。返回每个对象修改前后的标签。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Deployment'、'ConfigMap'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，必须与资源类型匹配。例如：'v1'、'apps/v1'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称。与labelSelector二选一。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("资源所在的命名空间。默认为'default'，集群级别资源忽略此参数。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("批量修改时使用的标签选择器，例如'app=foo'。指定name时忽略。"),
+		),
+		mcp.WithObject("labels",
+			mcp.Description("要添加或覆盖的标签，键值均为字符串。"),
+		),
+		mcp.WithString("remove",
+			mcp.Description("要删除的标签键，多个用逗号分隔。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。启用后返回修改后的标签但不持久化，匹配数量超过maxObjects时只列出前maxObjects个。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("maxObjects",
+			mcp.Description("按labelSelector批量修改时允许的最大对象数量。默认为20，最大为100。"),
+			mcp.DefaultNumber(defaultMetadataEditObjects),
+			mcp.Min(1),
+			mcp.Max(maxMetadataEditObjects),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.LabelResource)
+
+	// 资源注解编辑工具
+	server.AddTool(mcp.NewTool(ANNOTATE_RESOURCE,
+		mcp.WithDescription("添加、修改或删除现有资源的注解。使用只包含metadata.annotations的JSON合并补丁，不触及资源的其他字段，含斜杠的键（例如'example.com/owner'）无需转义。指定name时修改单个对象，否则修改labelSelector匹配的所有对象；匹配数量超过maxObjects时拒绝执行，可先用dryRun预览匹配的对象。返回每个对象修改前后的注解。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Deployment'、'ConfigMap'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，必须与资源类型匹配。例如：'v1'、'apps/v1'等。"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称。与labelSelector二选一。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("资源所在的命名空间。默认为'default'，集群级别资源忽略此参数。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("批量修改时使用的标签选择器，例如'app=foo'。指定name时忽略。"),
+		),
+		mcp.WithObject("annotations",
+			mcp.Description("要添加或覆盖的注解，键值均为字符串。"),
+		),
+		mcp.WithString("remove",
+			mcp.Description("要删除的注解键，多个用逗号分隔。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。启用后返回修改后的注解但不持久化，匹配数量超过maxObjects时只列出前maxObjects个。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("maxObjects",
+			mcp.Description("按labelSelector批量修改时允许的最大对象数量。默认为20，最大为100。"),
+			mcp.DefaultNumber(defaultMetadataEditObjects),
+			mcp.Min(1),
+			mcp.Max(maxMetadataEditObjects),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.AnnotateResource)
+
+	// 命名空间备份工具
+	server.AddTool(mcp.NewTool(BACKUP_NAMESPACE,
+		mcp.WithDescription("备份命名空间。遍历命名空间中所有可列出且可创建的资源类型，以导出格式（移除服务端字段）返回一个多文档YAML清单，并按类型汇总对象数量。默认跳过Event、EndpointSlice、由控制器管理的对象（例如Deployment创建的ReplicaSet和Pod）以及集群自动生成的对象。Secret的值默认脱敏。清单超过内联大小阈值且服务器配置了--backup-dir时写入服务器本地文件并返回路径。"),
+		mcp.WithString("namespace",
+			mcp.Description("要备份的命名空间。"),
+			mcp.Required(),
+		),
+		mcp.WithString("includeKinds",
+			mcp.Description("只备份这些资源类型，多个用逗号分隔，例如：'Deployment,Service,ConfigMap'。指定后不再应用默认跳过列表。"),
+		),
+		mcp.WithString("excludeKinds",
+			mcp.Description("额外跳过的资源类型，多个用逗号分隔，例如：'Lease,PodDisruptionBudget'。"),
+		),
+		mcp.WithBoolean("includeOwned",
+			mcp.Description("是否包含由控制器管理的对象（存在controller ownerReference）。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("includeSecretValues",
+			mcp.Description("是否在备份中保留Secret的值。需要服务器启用--allow-secret-values，否则忽略。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.BackupNamespace)
+
+	// 命名空间恢复工具
+	server.AddTool(mcp.NewTool(RESTORE_NAMESPACE,
+		mcp.WithDescription("从BACKUP_NAMESPACE生成的多文档清单恢复资源。使用服务端应用（Server-Side Apply），并按依赖顺序应用：Namespace、CRD、ServiceAccount、RBAC、ConfigMap/Secret、存储、Service，最后是工作负载等其他资源。返回每个对象的应用结果。值已脱敏的Secret会被跳过并报告。"),
+		mcp.WithString("yaml",
+			mcp.Description("多文档YAML清单。与file二选一。"),
+		),
+		mcp.WithString("file",
+			mcp.Description("服务器本地备份目录（--backup-dir）中的备份文件路径。与yaml二选一。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("目标命名空间（可选）。指定时将所有命名空间级别对象以及Namespace对象改写到该命名空间，用于恢复到新的命名空间。"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("是否执行服务端试运行。启用后只校验不持久化。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("fieldManager",
+			mcp.Description("服务端应用使用的字段管理器名称。"),
+			mcp.DefaultString(base.DefaultFieldManager),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("是否强制接管与其他字段管理器冲突的字段。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.RestoreNamespace)
+
+	// 孤立资源查找工具
+	server.AddTool(mcp.NewTool(FIND_ORPHANED_RESOURCES,
+		mcp.WithDescription("查找命名空间中疑似孤立、可以清理的资源，只读取不修改。检查项：未被引用的ConfigMap和Secret（卷、投射卷、CSI及其他卷插件的secretRef、env、envFrom、imagePullSecrets、ServiceAccount、Ingress和PersistentVolume的引用都视为引用）、未被任何Pod或工作负载模板使用的PVC、选择器不匹配任何Pod或工作负载模板的Service、结束超过指定天数且不会被自动清理的Job，以及副本数为0的旧版本ReplicaSet。带有ownerReferences、由Helm或cert-manager管理、用于选主的对象以及ServiceAccount令牌等Secret会被跳过。返回每个候选资源的判定依据和PVC可回收的存储容量，可选为每个候选资源生成删除工具调用。"),
+		mcp.WithString("namespaces",
+			mcp.Description("要检查的命名空间，多个用逗号分隔。为空时检查除kube-system、kube-public、kube-node-lease外的所有命名空间。"),
+		),
+		mcp.WithString("checks",
+			mcp.Description("要执行的检查项，多个用逗号分隔：configmaps、secrets、pvcs、services、jobs、replicasets。为空时执行全部检查。"),
+		),
+		mcp.WithNumber("minAgeHours",
+			mcp.Description("候选资源的最小存在时间（小时），更新的对象不会被报告，避免误判刚创建、尚未被引用的对象。默认为24。"),
+			mcp.DefaultNumber(defaultOrphanMinAgeHours),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("jobMinAgeDays",
+			mcp.Description("Job结束后超过多少天才被报告。默认为7。"),
+			mcp.DefaultNumber(defaultOrphanJobMinAgeDays),
+			mcp.Min(0),
+		),
+		mcp.WithBoolean("emitDeleteCommands",
+			mcp.Description("是否为每个候选资源生成可直接执行的删除工具调用（工具名与参数）。只生成不执行。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.FindOrphanedResources)
+
+	// 资源拓扑导出工具
+	server.AddTool(mcp.NewTool(EXPORT_TOPOLOGY,
+		mcp.WithDescription("将命名空间中资源的归属和依赖关系导出为Mermaid或Graphviz DOT文本，用于文档和事故复盘。节点包括Ingress、Service、Deployment、StatefulSet、DaemonSet、CronJob、Job、按工作负载合并为计数的Pod，以及被引用的ConfigMap、Secret和PVC；边包括Ingress到后端Service和TLS Secret、Service选择器到工作负载、工作负载到其Pod、CronJob到Job、工作负载通过卷和环境变量引用的ConfigMap、Secret和PVC（引用判定与FIND_ORPHANED_RESOURCES一致）。节点标签包含就绪状态（例如2/3 ready、ready endpoints数量、PVC绑定状态），异常和不存在的节点以不同样式标出。labelSelector只筛选工作负载、Service、Ingress和没有控制器的Pod，被引用的资源始终随引用方出现。超过maxNodes时按Ingress、Service、工作负载、Pod、被引用资源的顺序保留节点。text格式只返回图文本，可直接渲染；json和yaml格式同时返回节点和边列表。"),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间，默认为'default'。"),
+		),
+		mcp.WithString("graphFormat",
+			mcp.Description("图的文本格式：mermaid（默认）或dot。"),
+			mcp.DefaultString(topologyFormatMermaid),
+			mcp.Enum(topologyFormatMermaid, topologyFormatDOT),
+		),
+		mcp.WithString("kinds",
+			mcp.Description("要包含的资源类型，多个用逗号分隔：ingresses、services、deployments、statefulsets、daemonsets、cronjobs、jobs、pods、configmaps、secrets、pvcs。为空时包含全部。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("标签选择器（可选），例如'app=nginx'。"),
+		),
+		mcp.WithNumber("maxNodes",
+			mcp.Description(fmt.Sprintf("图中节点数上限，默认为%d，最大为%d。", defaultTopologyMaxNodes, maxTopologyMaxNodes)),
+			mcp.DefaultNumber(defaultTopologyMaxNodes),
+			mcp.Min(1),
+			mcp.Max(maxTopologyMaxNodes),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ExportTopology)
+
+	// 按近似名称查找资源工具
+	server.AddTool(mcp.NewTool(FIND_RESOURCE_BY_NAME,
+		mcp.WithDescription("按近似名称查找资源，用于只记得名称大概、或名称带有控制器生成后缀（ReplicaSet哈希、Pod随机后缀、StatefulSet序号）的情况。依次按完全相同、忽略大小写和分隔符相同、去掉生成后缀后相同、前缀、包含和编辑距离匹配，返回按相似度排序的候选，包含命名空间、创建时间和按状态条件得出的健康判定。GET、DESCRIBE和DELETE资源不存在时也会在错误中给出最多5个相近名称。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Deployment'、'Pod'。未指定apiVersion时也可使用复数名、简称或'name.group'形式。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，例如：'apps/v1'。未指定时按kind查找，类型存在于多个API组时需要指定。"),
+		),
+		mcp.WithString("name",
+			mcp.Description("近似的资源名称，例如'api-server'可以匹配'apiserver'和'api-server-7f9c8d5b6-x2k4q'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。为空时在所有命名空间中查找，集群级资源忽略此参数。"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("返回的候选数量上限，默认为%d，最大为%d。", defaultNameSearchLimit, maxNameSearchLimit)),
+			mcp.DefaultNumber(defaultNameSearchLimit),
+			mcp.Min(1),
+			mcp.Max(maxNameSearchLimit),
+		),
+		utils.WithTimeZone(),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.FindResourceByName)
+
+	// 资源状态条件汇总工具
+	server.AddTool(mcp.NewTool(GET_RESOURCE_CONDITIONS,
+		mcp.WithDescription("读取任意资源（内置类型或CRD）的status.conditions，规范化为type、status、reason、message、lastTransitionTime和age，并给出健康判定：healthy、degraded或unknown。判定规则：Degraded、Failed、Stalled等失败类条件为True时为degraded；否则使用第一个存在的就绪类条件（Ready、Available、Succeeded、Healthy、Reconciled、Complete，可通过readyCondition指定），True为healthy，False为degraded，其他为unknown；没有条件时比较status.observedGeneration与metadata.generation。非healthy时返回导致该判定的条件。可按名称获取单个资源，也可按类型、命名空间和标签选择器批量检查，例如只列出所有不健康的Certificate。"),
+		mcp.WithString("kind",
+			mcp.Description("资源类型，例如：'Certificate'、'Deployment'。未指定apiVersion时也可使用复数名、简称或'name.group'形式，例如'certificates.cert-manager.io'。"),
+			mcp.Required(),
+		),
+		mcp.WithString("apiVersion",
+			mcp.Description("API版本，例如：'cert-manager.io/v1'。未指定时按kind查找，类型存在于多个API组时需要指定。"),
+		),
+		mcp.WithString("name",
+			mcp.Description("资源名称。不指定时列出匹配的所有资源。"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("命名空间。按名称获取时默认为'default'；批量检查时为空表示所有命名空间。集群级资源忽略此参数。"),
+		),
+		mcp.WithString("labelSelector",
+			mcp.Description("批量检查时的标签选择器，例如：'app=nginx'。不能与name同时使用。"),
+		),
+		mcp.WithString("readyCondition",
+			mcp.Description("用于判定健康的条件类型，覆盖内置的就绪类条件列表，例如：'Synced'。"),
+		),
+		mcp.WithBoolean("unhealthyOnly",
+			mcp.Description("是否只返回判定不是healthy的资源。countsByVerdict仍统计本页全部资源。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("批量检查时单页检查的最大资源数量。未指定或超过服务器上限时按上限截断。"),
+		),
+		mcp.WithString("continue",
+			mcp.Description("上一页响应中返回的continue令牌，用于获取下一页。需与上一次请求使用相同的过滤条件。"),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.GetResourceConditions)
+
+	// 命名空间快照与比较工具
+	server.AddTool(mcp.NewTool(SNAPSHOT_NAMESPACE,
+		mcp.WithDescription("记录命名空间中每个对象的内容哈希，保存为服务器内存中的快照并返回快照ID，用于在变更前后对比命名空间状态。哈希基于清理后的对象内容，忽略status、resourceVersion、uid、managedFields等服务端字段；Secret只记录值的指纹，不保存明文。默认跳过Event、EndpointSlice、Endpoints、Lease以及由控制器生成的对象。对象内容不超过大小上限时一并保存，用于字段级差异。快照在TTL到期或服务器重启后失效，最多同时保留16个，超过时移除最旧的快照。"),
+		mcp.WithString("namespace",
+			mcp.Description("要记录快照的命名空间。"),
+			mcp.Required(),
+		),
+		mcp.WithString("includeKinds",
+			mcp.Description("只记录这些资源类型，多个用逗号分隔，例如：'Deployment,ConfigMap'。指定时不再默认跳过Event等类型。"),
+		),
+		mcp.WithString("excludeKinds",
+			mcp.Description("不记录的资源类型，多个用逗号分隔。"),
+		),
+		mcp.WithBoolean("includeOwned",
+			mcp.Description("是否记录带有ownerReferences的对象，例如由Deployment创建的ReplicaSet和Pod。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("ttlSeconds",
+			mcp.Description("快照保留时间（秒）。默认为3600，最大为86400。"),
+			mcp.DefaultNumber(utils.DefaultSnapshotTTL.Seconds()),
+			mcp.Min(1),
+			mcp.Max(utils.MaxSnapshotTTL.Seconds()),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.SnapshotNamespace)
+	server.AddTool(mcp.NewTool(DIFF_SNAPSHOT,
+		mcp.WithDescription("按快照创建时的条件重新扫描命名空间，与SNAPSHOT_NAMESPACE创建的快照比较，返回新增、删除和内容发生变化的对象，以及未变化的对象数量。可选返回变化对象的字段级差异。重新扫描时无法列出的资源类型不会被报告为已删除。"),
+		mcp.WithString("snapshotId",
+			mcp.Description("SNAPSHOT_NAMESPACE返回的快照ID。"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("fieldDiffs",
+			mcp.Description("是否返回变化对象的字段级差异，快照未保存对象内容时不可用。默认为true。"),
+			mcp.DefaultBool(true),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DiffSnapshot)
+
+	// 注册准入Webhook清单工具
+	server.AddTool(mcp.NewTool(LIST_WEBHOOKS,
+		mcp.WithDescription("列出集群中的ValidatingWebhookConfiguration和MutatingWebhookConfiguration中的每个Webhook：拦截规则（操作、API组、版本、资源、作用域）、failurePolicy、timeoutSeconds、namespaceSelector/objectSelector/matchConditions，以及后端的可用性（Service是否存在、是否暴露Webhook端口、是否有就绪的端点、caBundle是否为空）。标出failurePolicy=Fail但后端没有就绪端点的Webhook，这是所有匹配请求都被拒绝、造成集群范围故障的常见原因。apply或create莫名失败时使用。只读操作。"),
+		mcp.WithString("type",
+			mcp.Description("Webhook类型：all、validating或mutating。默认为all。"),
+			mcp.Enum("all", "validating", "mutating"),
+			mcp.DefaultString("all"),
+		),
+		mcp.WithBoolean("unhealthyOnly",
+			mcp.Description("是否只列出后端不可用的Webhook。默认为false。"),
+			mcp.DefaultBool(false),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.ListWebhooks)
+
+	// 注册准入失败诊断工具
+	server.AddTool(mcp.NewTool(DIAGNOSE_ADMISSION_FAILURE,
+		mcp.WithDescription("根据apply/create等请求返回的错误消息诊断准入失败：识别错误中的Webhook名称或后端Service，判断失败类别（denied：Webhook按策略拒绝；unreachable/timeout：无法连接或超时；tls：证书与caBundle不匹配；dry-run-unsupported；admission-policy：被ValidatingAdmissionPolicy拒绝），找到对应的Webhook配置并报告其后端当前是否可用，给出修复建议。错误中无法识别Webhook时，列出failurePolicy=Fail且后端不可用的可疑Webhook。只读操作。"),
+		mcp.WithString("errorMessage",
+			mcp.Description("请求失败时API Server返回的完整错误消息，例如'Internal error occurred: failed calling webhook \"validate.nginx.ingress.kubernetes.io\": ... connection refused'。"),
+			mcp.Required(),
+		),
+		utils.WithFormat(),
+		utils.WithTimeoutSeconds(),
+	), h.DiagnoseAdmissionFailure)
 }
 
 // Handle 实现接口方法
@@ -168,6 +1224,12 @@ func (h *UtilityHandler) Handle(ctx context.Context, request mcp.CallToolRequest
 	switch request.Method {
 	case GET_CLUSTER_INFO:
 		return h.GetClusterInfo(ctx, request)
+	case GET_SERVER_STATUS:
+		return h.GetServerStatus(ctx, request)
+	case REFRESH_DISCOVERY_CACHE:
+		return h.RefreshDiscoveryCache(ctx, request)
+	case INVALIDATE_CACHE:
+		return h.InvalidateCache(ctx, request)
 	case GET_API_RESOURCES:
 		return h.GetAPIResources(ctx, request)
 	case SEARCH_RESOURCES:
@@ -178,10 +1240,74 @@ func (h *UtilityHandler) Handle(ctx context.Context, request mcp.CallToolRequest
 		return h.ApplyManifest(ctx, request)
 	case VALIDATE_MANIFEST:
 		return h.ValidateManifest(ctx, request)
+	case GENERATE_MANIFEST:
+		return h.GenerateManifest(ctx, request)
+	case RENDER_KUSTOMIZATION:
+		return h.RenderKustomization(ctx, request)
+	case APPLY_KUSTOMIZATION:
+		return h.ApplyKustomization(ctx, request)
+	case APPLY_FROM_URL:
+		return h.ApplyFromURL(ctx, request)
+	case CHECK_QUOTA_FIT:
+		return h.CheckQuotaFit(ctx, request)
 	case DIFF_MANIFEST:
 		return h.DiffManifest(ctx, request)
 	case GET_EVENTS:
 		return h.GetEvents(ctx, request)
+	case LIST_HELM_RELEASES:
+		return h.ListHelmReleases(ctx, request)
+	case GET_HELM_RELEASE:
+		return h.GetHelmRelease(ctx, request)
+	case WATCH_RESOURCE:
+		return h.WatchResource(ctx, request)
+	case NOTIFY_ON_EVENT:
+		return h.NotifyOnEvent(ctx, request)
+	case WAIT_FOR:
+		return h.WaitFor(ctx, request)
+	case SAVE_NOTE:
+		return h.SaveNote(ctx, request)
+	case GET_NOTES:
+		return h.GetNotes(ctx, request)
+	case DELETE_NOTE:
+		return h.DeleteNote(ctx, request)
+	case GET_TOOL_HISTORY:
+		return h.GetToolHistory(ctx, request)
+	case COMPARE_RESOURCES:
+		return h.CompareResources(ctx, request)
+	case EXPORT_RESOURCE:
+		return h.ExportResource(ctx, request)
+	case GET_OWNERSHIP_GRAPH:
+		return h.GetOwnershipGraph(ctx, request)
+	case LIST_IMAGES:
+		return h.ListImages(ctx, request)
+	case CHECK_IMAGE_CONSISTENCY:
+		return h.CheckImageConsistency(ctx, request)
+	case CHECK_DEPRECATED_APIS:
+		return h.CheckDeprecatedAPIs(ctx, request)
+	case LABEL_RESOURCE:
+		return h.LabelResource(ctx, request)
+	case ANNOTATE_RESOURCE:
+		return h.AnnotateResource(ctx, request)
+	case BACKUP_NAMESPACE:
+		return h.BackupNamespace(ctx, request)
+	case RESTORE_NAMESPACE:
+		return h.RestoreNamespace(ctx, request)
+	case FIND_ORPHANED_RESOURCES:
+		return h.FindOrphanedResources(ctx, request)
+	case EXPORT_TOPOLOGY:
+		return h.ExportTopology(ctx, request)
+	case FIND_RESOURCE_BY_NAME:
+		return h.FindResourceByName(ctx, request)
+	case GET_RESOURCE_CONDITIONS:
+		return h.GetResourceConditions(ctx, request)
+	case SNAPSHOT_NAMESPACE:
+		return h.SnapshotNamespace(ctx, request)
+	case DIFF_SNAPSHOT:
+		return h.DiffSnapshot(ctx, request)
+	case LIST_WEBHOOKS:
+		return h.ListWebhooks(ctx, request)
+	case DIAGNOSE_ADMISSION_FAILURE:
+		return h.DiagnoseAdmissionFailure(ctx, request)
 	default:
 		return utils.NewErrorToolResult(fmt.Sprintf("unknown utility method: %s", request.Method)), nil
 	}
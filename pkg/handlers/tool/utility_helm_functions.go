@@ -0,0 +1,262 @@
+package tool
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认每页返回的Helm发布记录数量
+	defaultHelmListLimit = 50
+	// gzip 魔数，用于判断发布数据是否经过压缩
+	gzipMagic = "\x1f\x8b\x08"
+)
+
+// ListHelmReleases 列出集群中的Helm发布
+func (h *UtilityHandler) ListHelmReleases(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	limit, _ := arguments["limit"].(float64)
+	continueToken, _ := arguments["continue"].(string)
+	allRevisions, _ := arguments["allRevisions"].(bool)
+
+	if limit <= 0 {
+		limit = defaultHelmListLimit
+	}
+
+	h.Log.Info("Listing helm releases",
+		"namespace", namespace,
+		"limit", limit,
+		"continue", continueToken,
+		"allRevisions", allRevisions,
+	)
+
+	secrets, err := h.Client.ClientSet().CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + models.HelmReleaseSecretType,
+		Limit:         int64(limit),
+		Continue:      continueToken,
+	})
+	if err != nil {
+		h.Log.Error("Failed to list helm release secrets", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list helm release secrets"), nil
+	}
+
+	releases := make([]models.HelmReleaseSummary, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		summary := newHelmReleaseSummaryFromLabels(secret)
+		release, err := decodeHelmRelease(secret)
+		if err != nil {
+			h.Log.Warn("Failed to decode helm release", "secret", secret.Name, "namespace", secret.Namespace, "error", err)
+			summary.DecodeError = err.Error()
+		} else {
+			summary = newHelmReleaseSummary(secret, release)
+		}
+		releases = append(releases, summary)
+	}
+
+	// 默认只保留每个发布的最新版本
+	if !allRevisions {
+		releases = latestHelmRevisions(releases)
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		if releases[i].Name != releases[j].Name {
+			return releases[i].Name < releases[j].Name
+		}
+		return releases[i].Revision > releases[j].Revision
+	})
+
+	response := models.HelmReleaseListResponse{
+		Count:       len(releases),
+		Namespace:   namespace,
+		Releases:    releases,
+		Continue:    secrets.Continue,
+		Remaining:   secrets.RemainingItemCount,
+		RetrievedAt: time.Now(),
+	}
+
+	h.Log.Info("Helm releases listed successfully", "count", len(releases))
+
+	return utils.RenderResult(request, response), nil
+}
+
+// GetHelmRelease 获取指定Helm发布的渲染清单和values
+func (h *UtilityHandler) GetHelmRelease(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	revision, _ := arguments["revision"].(float64)
+
+	h.Log.Info("Getting helm release",
+		"name", name,
+		"namespace", namespace,
+		"revision", revision,
+	)
+
+	if name == "" || namespace == "" {
+		return utils.NewErrorToolResult("missing required parameters: name and namespace"), nil
+	}
+
+	// Helm 3 为每个发布记录设置了 owner=helm 和 name=<release> 标签
+	secrets, err := h.Client.ClientSet().CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + models.HelmReleaseSecretType,
+		LabelSelector: fmt.Sprintf("owner=helm,name=%s", name),
+	})
+	if err != nil {
+		h.Log.Error("Failed to list helm release secrets", "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list helm release secrets"), nil
+	}
+	if len(secrets.Items) == 0 {
+		return utils.NewErrorToolResult(fmt.Sprintf("helm release '%s' not found in namespace '%s'", name, namespace)), nil
+	}
+
+	// 收集所有版本，并选出目标版本
+	revisions := make([]int, 0, len(secrets.Items))
+	var target *corev1.Secret
+	targetRevision := 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		rev := newHelmReleaseSummaryFromLabels(secret).Revision
+		revisions = append(revisions, rev)
+		if revision > 0 {
+			if rev == int(revision) {
+				target = secret
+				targetRevision = rev
+			}
+		} else if target == nil || rev > targetRevision {
+			target = secret
+			targetRevision = rev
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(revisions)))
+
+	if target == nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("revision %d of helm release '%s' not found in namespace '%s', available revisions: %v",
+			int(revision), name, namespace, revisions)), nil
+	}
+
+	release, err := decodeHelmRelease(target)
+	if err != nil {
+		h.Log.Error("Failed to decode helm release", "secret", target.Name, "error", err)
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to decode helm release %s: %v", target.Name, err)), nil
+	}
+
+	response := models.HelmReleaseDetailResponse{
+		HelmReleaseSummary: newHelmReleaseSummary(target, release),
+		Revisions:          revisions,
+		Notes:              release.Info.Notes,
+		Values:             release.Config,
+		Manifest:           release.Manifest,
+		RetrievedAt:        time.Now(),
+	}
+
+	h.Log.Info("Helm release retrieved successfully", "name", name, "namespace", namespace, "revision", targetRevision)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// decodeHelmRelease 解码Helm发布记录：base64 -> gzip -> JSON
+func decodeHelmRelease(secret *corev1.Secret) (*models.HelmRelease, error) {
+	raw, ok := secret.Data["release"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no release data", secret.Name)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 release data: %w", err)
+	}
+
+	// 旧版本Helm写入的数据可能未压缩
+	if bytes.HasPrefix(decoded, []byte(gzipMagic)) {
+		reader, err := gzip.NewReader(bytes.NewReader(decoded))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip release data: %w", err)
+		}
+		defer reader.Close()
+		decoded, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress release data: %w", err)
+		}
+	}
+
+	release := &models.HelmRelease{}
+	if err := json.Unmarshal(decoded, release); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal release data: %w", err)
+	}
+	return release, nil
+}
+
+// newHelmReleaseSummaryFromLabels 仅根据Secret标签构建发布摘要，用于无法解码发布数据的情况
+func newHelmReleaseSummaryFromLabels(secret *corev1.Secret) models.HelmReleaseSummary {
+	revision := 0
+	fmt.Sscanf(secret.Labels["version"], "%d", &revision)
+	return models.HelmReleaseSummary{
+		Name:       secret.Labels["name"],
+		Namespace:  secret.Namespace,
+		Revision:   revision,
+		Status:     secret.Labels["status"],
+		SecretName: secret.Name,
+	}
+}
+
+// newHelmReleaseSummary 根据解码后的发布数据构建发布摘要
+func newHelmReleaseSummary(secret *corev1.Secret, release *models.HelmRelease) models.HelmReleaseSummary {
+	namespace := release.Namespace
+	if namespace == "" {
+		namespace = secret.Namespace
+	}
+	return models.HelmReleaseSummary{
+		Name:         release.Name,
+		Namespace:    namespace,
+		Revision:     release.Version,
+		Chart:        release.Chart.Metadata.Name,
+		ChartVersion: release.Chart.Metadata.Version,
+		AppVersion:   release.Chart.Metadata.AppVersion,
+		Status:       release.Info.Status,
+		Updated:      release.Info.LastDeployed,
+		Description:  release.Info.Description,
+		SecretName:   secret.Name,
+	}
+}
+
+// latestHelmRevisions 只保留每个发布的最新版本
+func latestHelmRevisions(releases []models.HelmReleaseSummary) []models.HelmReleaseSummary {
+	latest := make(map[string]models.HelmReleaseSummary)
+	for _, release := range releases {
+		key := release.Namespace + "/" + release.Name
+		if current, ok := latest[key]; !ok || release.Revision > current.Revision {
+			latest[key] = release
+		}
+	}
+
+	result := make([]models.HelmReleaseSummary, 0, len(latest))
+	for _, release := range latest {
+		result = append(result, release)
+	}
+	return result
+}
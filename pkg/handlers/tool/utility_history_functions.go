@@ -0,0 +1,45 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/middlewares"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// defaultToolHistoryLimit 未指定limit时返回的记录数
+const defaultToolHistoryLimit = 20
+
+// GetToolHistory 返回当前会话最近的工具调用记录
+func (h *UtilityHandler) GetToolHistory(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	tools, _ := arguments["tool"].(string)
+	errorsOnly, _ := arguments["errorsOnly"].(bool)
+	limit := defaultToolHistoryLimit
+	if value, ok := arguments["limit"].(float64); ok && value > 0 {
+		limit = int(value)
+	}
+
+	h.Log.Info("Getting tool history", "tool", tools, "errorsOnly", errorsOnly, "limit", limit)
+
+	if !middlewares.ToolHistoryEnabled() {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeRefused,
+			Message: "tool call history is disabled on this server",
+			Hint:    "Start the server with --tool-history-size greater than 0 to record tool calls, or use GET_NOTES to recall saved findings.",
+		}), nil
+	}
+
+	history := middlewares.SessionToolHistory(ctx, middlewares.ToolHistoryFilter{
+		Tools:      utils.SplitCommaList(tools),
+		ErrorsOnly: errorsOnly,
+		Limit:      limit,
+	})
+	return utils.RenderResult(request, history), nil
+}
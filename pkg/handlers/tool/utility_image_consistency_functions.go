@@ -0,0 +1,578 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// imageConsistencyKinds 镜像一致性检查支持的工作负载类型
+var imageConsistencyKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// imageWorkload 镜像一致性检查的工作负载及其Pod
+type imageWorkload struct {
+	kind     string
+	name     string
+	uid      types.UID
+	template *corev1.PodSpec
+	// rolloutInProgress 控制器仍在用新模板替换Pod
+	rolloutInProgress bool
+	// staleHint 滚动更新已结束但仍有旧Pod时的处理建议，取决于工作负载的更新策略
+	staleHint string
+	pods      []*corev1.Pod
+}
+
+// imageContainerRef Pod中的一个容器及其规格镜像和状态中的摘要
+type imageContainerRef struct {
+	image  string
+	digest string
+}
+
+// CheckImageConsistency 对比工作负载Pod模板中的镜像与各Pod实际运行的镜像和摘要
+// 只使用Pod状态中的imageID，不访问镜像仓库
+func (h *UtilityHandler) CheckImageConsistency(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	kind, _ := arguments["kind"].(string)
+	name, _ := arguments["name"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	compareNewest, _ := arguments["compareNewest"].(bool)
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	h.Log.Info("Checking image consistency",
+		"namespace", namespace,
+		"kind", kind,
+		"name", name,
+		"labelSelector", labelSelector,
+		"compareNewest", compareNewest,
+	)
+
+	if kind != "" && !slices.Contains(imageConsistencyKinds, kind) {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeUnsupported,
+			Message: fmt.Sprintf("unsupported kind %q", kind),
+			Details: imageConsistencyKinds,
+		}), nil
+	}
+	if kind != "" && name == "" {
+		return utils.NewErrorToolResult("name is required when kind is given"), nil
+	}
+	if kind == "" && name != "" {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: "kind is required when name is given",
+			Details: imageConsistencyKinds,
+		}), nil
+	}
+
+	workloads, err := h.listImageWorkloads(ctx, namespace, kind, name, labelSelector)
+	if err != nil {
+		h.Log.Error("Failed to list workloads", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list workloads"), nil
+	}
+
+	clientSet := h.Client.ClientSet()
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.Log.Error("Failed to list pods", "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list pods"), nil
+	}
+
+	// Deployment的Pod由ReplicaSet创建，需要经ReplicaSet找到所属的Deployment
+	owners := make(map[types.UID]*imageWorkload, len(workloads))
+	hasDeployments := false
+	for _, workload := range workloads {
+		owners[workload.uid] = workload
+		hasDeployments = hasDeployments || workload.kind == "Deployment"
+	}
+	replicaSetOwners := make(map[types.UID]types.UID)
+	if hasDeployments {
+		replicaSets, err := clientSet.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			h.Log.Error("Failed to list replicasets", "namespace", namespace, "error", err)
+			return utils.NewKubeErrorResult(err, "failed to list replicasets"), nil
+		}
+		for _, replicaSet := range replicaSets.Items {
+			if owner := metav1.GetControllerOf(&replicaSet); owner != nil && owner.Kind == "Deployment" {
+				replicaSetOwners[replicaSet.UID] = owner.UID
+			}
+		}
+	}
+
+	report := &models.ImageConsistencyReport{
+		Namespace:   namespace,
+		Workloads:   []models.WorkloadImageConsistency{},
+		Tags:        []models.ImageTagDigests{},
+		RetrievedAt: time.Now(),
+	}
+	// 指定工作负载或选择器时只在这些工作负载的Pod中比较标签，否则包括命名空间中的所有Pod
+	wholeNamespace := kind == "" && labelSelector == ""
+	var fleet []*corev1.Pod
+	podWorkloads := make(map[string]string)
+	terminating := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			terminating++
+			continue
+		}
+		var workload *imageWorkload
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			ownerUID := owner.UID
+			if deploymentUID, ok := replicaSetOwners[ownerUID]; ok && owner.Kind == "ReplicaSet" {
+				ownerUID = deploymentUID
+			}
+			workload = owners[ownerUID]
+		}
+		if workload != nil {
+			workload.pods = append(workload.pods, pod)
+			podWorkloads[pod.Name] = workload.kind + "/" + workload.name
+		}
+		if workload != nil || wholeNamespace {
+			fleet = append(fleet, pod)
+		}
+	}
+	if terminating > 0 {
+		report.Notes = append(report.Notes, fmt.Sprintf("%d terminating pod(s) were skipped", terminating))
+	}
+
+	for _, workload := range workloads {
+		result := checkWorkloadImages(workload, compareNewest)
+		if result.Pods == 0 {
+			report.Notes = append(report.Notes, fmt.Sprintf("%s/%s has no running pods", workload.kind, workload.name))
+		}
+		report.Findings += len(result.Findings)
+		report.Workloads = append(report.Workloads, result)
+	}
+	report.Tags = tagDigests(fleet, podWorkloads)
+	report.Consistent = report.Findings == 0 && len(report.Tags) == 0
+
+	h.Log.Info("Image consistency checked", "namespace", namespace, "workloads", len(report.Workloads), "findings", report.Findings, "tags", len(report.Tags))
+	return utils.RenderResult(request, report), nil
+}
+
+// listImageWorkloads 读取指定的工作负载，未指定时列出命名空间中所有支持的工作负载，结果按类型分组并按名称排序
+func (h *UtilityHandler) listImageWorkloads(
+	ctx context.Context,
+	namespace, kind, name, labelSelector string,
+) ([]*imageWorkload, error) {
+	apps := h.Client.ClientSet().AppsV1()
+	kinds := imageConsistencyKinds
+	if kind != "" {
+		kinds = []string{kind}
+	}
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+
+	var workloads []*imageWorkload
+	for _, kind := range kinds {
+		start := len(workloads)
+		switch kind {
+		case "Deployment":
+			var items []appsv1.Deployment
+			if name != "" {
+				deployment, err := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, *deployment)
+			} else {
+				list, err := apps.Deployments(namespace).List(ctx, listOptions)
+				if err != nil {
+					return nil, err
+				}
+				items = list.Items
+			}
+			for i := range items {
+				workloads = append(workloads, deploymentImageWorkload(&items[i]))
+			}
+		case "StatefulSet":
+			var items []appsv1.StatefulSet
+			if name != "" {
+				statefulSet, err := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, *statefulSet)
+			} else {
+				list, err := apps.StatefulSets(namespace).List(ctx, listOptions)
+				if err != nil {
+					return nil, err
+				}
+				items = list.Items
+			}
+			for i := range items {
+				workloads = append(workloads, statefulSetImageWorkload(&items[i]))
+			}
+		case "DaemonSet":
+			var items []appsv1.DaemonSet
+			if name != "" {
+				daemonSet, err := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, *daemonSet)
+			} else {
+				list, err := apps.DaemonSets(namespace).List(ctx, listOptions)
+				if err != nil {
+					return nil, err
+				}
+				items = list.Items
+			}
+			for i := range items {
+				workloads = append(workloads, daemonSetImageWorkload(&items[i]))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported kind %q: must be Deployment, StatefulSet or DaemonSet", kind)
+		}
+		segment := workloads[start:]
+		sort.Slice(segment, func(i, j int) bool { return segment[i].name < segment[j].name })
+	}
+	return workloads, nil
+}
+
+// deploymentImageWorkload 副本尚未全部更新到当前模板时视为滚动更新中，暂停的Deployment除外
+func deploymentImageWorkload(deployment *appsv1.Deployment) *imageWorkload {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	workload := &imageWorkload{
+		kind:     "Deployment",
+		name:     deployment.Name,
+		uid:      deployment.UID,
+		template: &deployment.Spec.Template.Spec,
+		rolloutInProgress: !deployment.Spec.Paused && (status.ObservedGeneration < deployment.Generation ||
+			status.UpdatedReplicas < replicas || status.Replicas > status.UpdatedReplicas),
+		staleHint: "The Deployment is not rolling out; check the ReplicaSets for an old one that still owns these pods, then delete the pods (RESTART_POD) or run 'kubectl rollout restart'.",
+	}
+	if deployment.Spec.Paused {
+		workload.staleHint = "The Deployment is paused, so old pods are not replaced; resume it to finish the rollout."
+	}
+	return workload
+}
+
+// statefulSetImageWorkload 当前版本与更新版本不同时视为滚动更新中，OnDelete策略下控制器不会主动替换旧Pod
+func statefulSetImageWorkload(statefulSet *appsv1.StatefulSet) *imageWorkload {
+	status := statefulSet.Status
+	workload := &imageWorkload{
+		kind:     "StatefulSet",
+		name:     statefulSet.Name,
+		uid:      statefulSet.UID,
+		template: &statefulSet.Spec.Template.Spec,
+		rolloutInProgress: status.ObservedGeneration < statefulSet.Generation ||
+			(status.UpdateRevision != "" && status.CurrentRevision != status.UpdateRevision),
+		staleHint: "The StatefulSet is not rolling out; delete these pods (RESTART_POD) so the controller recreates them from the current template.",
+	}
+	strategy := statefulSet.Spec.UpdateStrategy
+	switch {
+	case strategy.Type == appsv1.OnDeleteStatefulSetStrategyType:
+		workload.rolloutInProgress = false
+		workload.staleHint = "The update strategy is OnDelete, so old pods keep running until deleted; delete them (RESTART_POD) to pick up the current template."
+	case strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil && *strategy.RollingUpdate.Partition > 0:
+		workload.rolloutInProgress = false
+		workload.staleHint = fmt.Sprintf("The rolling update partition is %d, so pods with a lower ordinal stay on the old template; lower it with SET_STATEFULSET_PARTITION to continue the rollout.", *strategy.RollingUpdate.Partition)
+	}
+	return workload
+}
+
+// daemonSetImageWorkload 已更新的节点数少于期望节点数时视为滚动更新中，OnDelete策略下控制器不会主动替换旧Pod
+func daemonSetImageWorkload(daemonSet *appsv1.DaemonSet) *imageWorkload {
+	status := daemonSet.Status
+	workload := &imageWorkload{
+		kind:     "DaemonSet",
+		name:     daemonSet.Name,
+		uid:      daemonSet.UID,
+		template: &daemonSet.Spec.Template.Spec,
+		rolloutInProgress: status.ObservedGeneration < daemonSet.Generation ||
+			status.UpdatedNumberScheduled < status.DesiredNumberScheduled,
+		staleHint: "The DaemonSet is not rolling out; delete these pods (RESTART_POD) so the controller recreates them from the current template.",
+	}
+	if daemonSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		workload.rolloutInProgress = false
+		workload.staleHint = "The update strategy is OnDelete, so old pods keep running until deleted; delete them (RESTART_POD) to pick up the current template."
+	}
+	return workload
+}
+
+// checkWorkloadImages 逐个容器对比模板镜像与各Pod规格中的镜像，并检查使用模板镜像的Pod是否运行相同的摘要
+func checkWorkloadImages(workload *imageWorkload, compareNewest bool) models.WorkloadImageConsistency {
+	result := models.WorkloadImageConsistency{
+		Kind:              workload.kind,
+		Name:              workload.name,
+		Pods:              len(workload.pods),
+		RolloutInProgress: workload.rolloutInProgress,
+		Containers:        []models.ContainerImageConsistency{},
+		Findings:          []models.ImageConsistencyFinding{},
+	}
+	sort.Slice(workload.pods, func(i, j int) bool { return workload.pods[i].Name < workload.pods[j].Name })
+
+	check := func(container corev1.Container, init bool) {
+		entry := models.ContainerImageConsistency{
+			Container:     container.Name,
+			Init:          init,
+			TemplateImage: container.Image,
+			Running:       []models.ImageDigestPods{},
+		}
+		running := make(map[imageContainerRef][]string)
+		staleImages := make(map[string]bool)
+		var stale []string
+		// current 使用模板镜像且已有摘要的Pod
+		var current []*corev1.Pod
+		currentDigests := make(map[string][]string)
+		for _, pod := range workload.pods {
+			ref, found := podContainerImage(pod, container.Name, init)
+			if !found {
+				stale = append(stale, pod.Name)
+				staleImages["<missing>"] = true
+				continue
+			}
+			if ref.image != container.Image {
+				stale = append(stale, pod.Name)
+				staleImages[ref.image] = true
+			}
+			if ref.digest == "" {
+				entry.Unresolved = append(entry.Unresolved, pod.Name)
+				continue
+			}
+			running[ref] = append(running[ref], pod.Name)
+			if ref.image == container.Image {
+				current = append(current, pod)
+				currentDigests[ref.digest] = append(currentDigests[ref.digest], pod.Name)
+			}
+		}
+		for ref, pods := range running {
+			entry.Running = append(entry.Running, models.ImageDigestPods{Image: ref.image, Digest: ref.digest, Pods: pods})
+		}
+		sortDigestPods(entry.Running)
+
+		if len(stale) > 0 {
+			finding := models.ImageConsistencyFinding{
+				Severity:   models.SeverityWarning,
+				Check:      models.ImageCheckTemplateMismatch,
+				Container:  container.Name,
+				Message:    fmt.Sprintf("%d of %d pod(s) run %s instead of the template image %s", len(stale), len(workload.pods), strings.Join(sortedKeys(staleImages), ", "), container.Image),
+				Pods:       stale,
+				Suggestion: workload.staleHint,
+			}
+			if workload.rolloutInProgress {
+				finding.Severity = models.SeverityInfo
+				finding.Message += " while a rollout is in progress"
+				finding.Suggestion = "The rollout is still replacing pods; check again after it completes, and investigate only if it stalls."
+			}
+			result.Findings = append(result.Findings, finding)
+		}
+
+		// 按摘要固定的模板镜像不会因标签被覆盖而漂移
+		if strings.Contains(container.Image, "@") || len(currentDigests) == 0 {
+			result.Containers = append(result.Containers, entry)
+			return
+		}
+		pullHint := ""
+		if pullPolicyOrDefault(container) != corev1.PullAlways {
+			pullHint = fmt.Sprintf(" With imagePullPolicy %s a node that cached an older digest keeps using it, so set Always or pin the image by digest.", pullPolicyOrDefault(container))
+		}
+		if compareNewest {
+			newest := current[0]
+			for _, pod := range current[1:] {
+				if pod.CreationTimestamp.After(newest.CreationTimestamp.Time) {
+					newest = pod
+				}
+			}
+			newestRef, _ := podContainerImage(newest, container.Name, init)
+			entry.ExpectedDigest = newestRef.digest
+			var behind []string
+			for digest, pods := range currentDigests {
+				if digest != entry.ExpectedDigest {
+					behind = append(behind, pods...)
+				}
+			}
+			if len(behind) > 0 {
+				sort.Strings(behind)
+				result.Findings = append(result.Findings, models.ImageConsistencyFinding{
+					Severity:   models.SeverityWarning,
+					Check:      models.ImageCheckStaleDigest,
+					Container:  container.Name,
+					Message:    fmt.Sprintf("%d pod(s) run an older digest of %s than %s, the digest pulled by the newest pod %s", len(behind), container.Image, entry.ExpectedDigest, newest.Name),
+					Pods:       behind,
+					Suggestion: "Delete these pods (RESTART_POD) so they pull the current digest." + pullHint,
+				})
+			}
+		} else if len(currentDigests) > 1 {
+			var pods []string
+			for _, names := range currentDigests {
+				pods = append(pods, names...)
+			}
+			sort.Strings(pods)
+			result.Findings = append(result.Findings, models.ImageConsistencyFinding{
+				Severity:   models.SeverityWarning,
+				Check:      models.ImageCheckTagMultipleDigests,
+				Container:  container.Name,
+				Message:    fmt.Sprintf("%s resolves to %d different digests across %d pod(s); the tag was probably re-pushed while pods were running", container.Image, len(currentDigests), len(pods)),
+				Pods:       pods,
+				Suggestion: "Pin the image by digest (image@sha256:...) so every replica runs the same build; use compareNewest=true to see which pods run an older digest." + pullHint,
+			})
+		}
+		result.Containers = append(result.Containers, entry)
+	}
+	for _, container := range workload.template.InitContainers {
+		check(container, true)
+	}
+	for _, container := range workload.template.Containers {
+		check(container, false)
+	}
+	return result
+}
+
+// podContainerImage 返回Pod规格中容器的镜像及容器状态中的摘要，容器不存在时found为false
+func podContainerImage(pod *corev1.Pod, containerName string, init bool) (imageContainerRef, bool) {
+	containers, statuses := pod.Spec.Containers, pod.Status.ContainerStatuses
+	if init {
+		containers, statuses = pod.Spec.InitContainers, pod.Status.InitContainerStatuses
+	}
+	var ref imageContainerRef
+	found := false
+	for _, container := range containers {
+		if container.Name == containerName {
+			ref.image, found = container.Image, true
+			break
+		}
+	}
+	for _, status := range statuses {
+		if status.Name == containerName {
+			ref.digest = imageDigest(status.ImageID)
+			break
+		}
+	}
+	return ref, found
+}
+
+// imageDigest 从容器状态的imageID中提取摘要
+// imageID的格式因容器运行时而异，例如"docker-pullable://nginx@sha256:..."、"docker.io/library/nginx@sha256:..."或"sha256:..."
+func imageDigest(imageID string) string {
+	if _, rest, found := strings.Cut(imageID, "://"); found {
+		imageID = rest
+	}
+	if at := strings.LastIndex(imageID, "@"); at >= 0 {
+		return imageID[at+1:]
+	}
+	return imageID
+}
+
+// normalizedImageTag 返回规范化的registry/repository:tag，按摘要固定的镜像返回空字符串
+func normalizedImageTag(image string) string {
+	registry, repository, tag, digest := parseImageReference(image)
+	if digest != "" {
+		return ""
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return registry + "/" + repository + ":" + tag
+}
+
+// tagDigests 在Pod中查找解析为多个摘要的镜像标签，"nginx"与"docker.io/library/nginx:latest"视为同一标签
+func tagDigests(pods []*corev1.Pod, podWorkloads map[string]string) []models.ImageTagDigests {
+	digests := make(map[string]map[string]map[string]bool)
+	images := make(map[string]map[string]bool)
+	workloads := make(map[string]map[string]bool)
+	for _, pod := range pods {
+		record := func(containers []corev1.Container, init bool) {
+			for _, container := range containers {
+				tag := normalizedImageTag(container.Image)
+				if tag == "" {
+					continue
+				}
+				ref, _ := podContainerImage(pod, container.Name, init)
+				if ref.digest == "" {
+					continue
+				}
+				if digests[tag] == nil {
+					digests[tag] = make(map[string]map[string]bool)
+					images[tag] = make(map[string]bool)
+					workloads[tag] = make(map[string]bool)
+				}
+				if digests[tag][ref.digest] == nil {
+					digests[tag][ref.digest] = make(map[string]bool)
+				}
+				digests[tag][ref.digest][pod.Name] = true
+				images[tag][container.Image] = true
+				if workload, ok := podWorkloads[pod.Name]; ok {
+					workloads[tag][workload] = true
+				}
+			}
+		}
+		record(pod.Spec.InitContainers, true)
+		record(pod.Spec.Containers, false)
+	}
+
+	result := []models.ImageTagDigests{}
+	for tag, byDigest := range digests {
+		if len(byDigest) < 2 {
+			continue
+		}
+		entry := models.ImageTagDigests{Image: tag, Workloads: sortedKeys(workloads[tag])}
+		// 同一标签可能以不同写法出现在Pod规格中，只有一种写法时直接使用它
+		written := tag
+		if names := sortedKeys(images[tag]); len(names) == 1 {
+			written = names[0]
+		}
+		for digest, pods := range byDigest {
+			entry.Digests = append(entry.Digests, models.ImageDigestPods{Image: written, Digest: digest, Pods: sortedKeys(pods)})
+		}
+		sortDigestPods(entry.Digests)
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Image < result[j].Image })
+	return result
+}
+
+// sortDigestPods 按Pod数量降序排列，数量相同时按镜像和摘要排序
+func sortDigestPods(groups []models.ImageDigestPods) {
+	for i := range groups {
+		sort.Strings(groups[i].Pods)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].Pods) != len(groups[j].Pods) {
+			return len(groups[i].Pods) > len(groups[j].Pods)
+		}
+		if groups[i].Image != groups[j].Image {
+			return groups[i].Image < groups[j].Image
+		}
+		return groups[i].Digest < groups[j].Digest
+	})
+}
+
+// pullPolicyOrDefault 返回容器的拉取策略，未设置时按API Server的默认规则推断
+func pullPolicyOrDefault(container corev1.Container) corev1.PullPolicy {
+	if container.ImagePullPolicy != "" {
+		return container.ImagePullPolicy
+	}
+	if _, _, tag, digest := parseImageReference(container.Image); digest == "" && (tag == "" || tag == "latest") {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
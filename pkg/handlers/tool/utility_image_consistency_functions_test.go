@@ -0,0 +1,287 @@
+package tool
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+const (
+	digestOld = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	digestNew = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// imagePod 返回容器app运行image的Pod，imageID为容器状态中运行时报告的镜像ID，age为Pod已创建的时长
+func imagePod(name, image, imageID string, age time.Duration) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testutil.DefaultNamespace,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Image: image, ImageID: imageID}}},
+	}
+}
+
+func appTemplate(image string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}}
+}
+
+func findingChecks(findings []models.ImageConsistencyFinding) []string {
+	checks := []string{}
+	for _, finding := range findings {
+		checks = append(checks, finding.Severity+"/"+finding.Check)
+	}
+	return checks
+}
+
+func TestImageDigest(t *testing.T) {
+	tests := []struct {
+		imageID string
+		want    string
+	}{
+		{imageID: "docker-pullable://nginx@" + digestOld, want: digestOld},
+		{imageID: "docker.io/library/nginx@" + digestOld, want: digestOld},
+		{imageID: "registry.example.com:5000/team/app@" + digestOld, want: digestOld},
+		{imageID: digestOld, want: digestOld},
+		{imageID: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := imageDigest(tt.imageID); got != tt.want {
+			t.Errorf("imageDigest(%q) = %q, want %q", tt.imageID, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWorkloadImagesTemplateMismatch(t *testing.T) {
+	deployment := func(modify func(*appsv1.Deployment)) *imageWorkload {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2), Template: appTemplate("app:v2")},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2},
+		}
+		modify(deployment)
+		return deploymentImageWorkload(deployment)
+	}
+	statefulSet := func(modify func(*appsv1.StatefulSet)) *imageWorkload {
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 2},
+			Spec:       appsv1.StatefulSetSpec{Template: appTemplate("app:v2")},
+			Status:     appsv1.StatefulSetStatus{ObservedGeneration: 2, CurrentRevision: "db-1", UpdateRevision: "db-2"},
+		}
+		modify(statefulSet)
+		return statefulSetImageWorkload(statefulSet)
+	}
+
+	tests := []struct {
+		name     string
+		workload *imageWorkload
+		rollout  bool
+		severity string
+		// suggestion 建议中应包含的文本
+		suggestion string
+	}{
+		{
+			name: "deployment rollout in progress",
+			workload: deployment(func(d *appsv1.Deployment) {
+				d.Status.UpdatedReplicas = 1
+			}),
+			rollout:    true,
+			severity:   models.SeverityInfo,
+			suggestion: "still replacing pods",
+		},
+		{
+			name:       "deployment rollout finished",
+			workload:   deployment(func(*appsv1.Deployment) {}),
+			severity:   models.SeverityWarning,
+			suggestion: "not rolling out",
+		},
+		{
+			name: "paused deployment",
+			workload: deployment(func(d *appsv1.Deployment) {
+				d.Spec.Paused = true
+				d.Status.UpdatedReplicas = 1
+			}),
+			severity:   models.SeverityWarning,
+			suggestion: "paused",
+		},
+		{
+			name:       "statefulset rolling update",
+			workload:   statefulSet(func(*appsv1.StatefulSet) {}),
+			rollout:    true,
+			severity:   models.SeverityInfo,
+			suggestion: "still replacing pods",
+		},
+		{
+			name: "statefulset OnDelete",
+			workload: statefulSet(func(s *appsv1.StatefulSet) {
+				s.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+			}),
+			severity:   models.SeverityWarning,
+			suggestion: "OnDelete",
+		},
+		{
+			name: "statefulset partition",
+			workload: statefulSet(func(s *appsv1.StatefulSet) {
+				s.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](1)}
+			}),
+			severity:   models.SeverityWarning,
+			suggestion: "partition is 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.workload.pods = []*corev1.Pod{
+				imagePod("app-0", "app:v1", "docker-pullable://app@"+digestOld, time.Hour),
+				imagePod("app-1", "app:v2", "docker-pullable://app@"+digestNew, time.Minute),
+			}
+			result := checkWorkloadImages(tt.workload, false)
+
+			if result.RolloutInProgress != tt.rollout {
+				t.Fatalf("rollout in progress = %v, want %v", result.RolloutInProgress, tt.rollout)
+			}
+			if checks := findingChecks(result.Findings); !slices.Equal(checks, []string{tt.severity + "/" + models.ImageCheckTemplateMismatch}) {
+				t.Fatalf("findings = %v, want one %s template mismatch", checks, tt.severity)
+			}
+			finding := result.Findings[0]
+			if !slices.Equal(finding.Pods, []string{"app-0"}) || !strings.Contains(finding.Suggestion, tt.suggestion) {
+				t.Fatalf("finding = %+v, want app-0 and a suggestion mentioning %q", finding, tt.suggestion)
+			}
+		})
+	}
+}
+
+func TestCheckWorkloadImagesDigests(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      string
+		pods          []*corev1.Pod
+		compareNewest bool
+		checks        []string
+		findingPods   []string
+		expected      string
+		unresolved    []string
+	}{
+		{
+			name:     "one digest per tag",
+			template: "app:v2",
+			pods: []*corev1.Pod{
+				imagePod("app-0", "app:v2", "docker-pullable://app@"+digestNew, time.Hour),
+				imagePod("app-1", "app:v2", digestNew, time.Minute),
+			},
+			checks: []string{},
+		},
+		{
+			name:     "tag resolves to several digests",
+			template: "app:v2",
+			pods: []*corev1.Pod{
+				imagePod("app-0", "app:v2", "docker-pullable://app@"+digestOld, time.Hour),
+				imagePod("app-1", "app:v2", "docker.io/library/app@"+digestNew, time.Minute),
+			},
+			checks:      []string{"warning/" + models.ImageCheckTagMultipleDigests},
+			findingPods: []string{"app-0", "app-1"},
+		},
+		{
+			name:     "older digest than the newest pod",
+			template: "app:v2",
+			pods: []*corev1.Pod{
+				imagePod("app-0", "app:v2", digestOld, time.Hour),
+				imagePod("app-1", "app:v2", digestNew, time.Minute),
+				imagePod("app-2", "app:v2", digestOld, 2*time.Hour),
+			},
+			compareNewest: true,
+			checks:        []string{"warning/" + models.ImageCheckStaleDigest},
+			findingPods:   []string{"app-0", "app-2"},
+			expected:      digestNew,
+		},
+		{
+			name:     "image pinned by digest",
+			template: "app@" + digestNew,
+			pods: []*corev1.Pod{
+				imagePod("app-0", "app@"+digestNew, digestNew, time.Hour),
+				imagePod("app-1", "app@"+digestNew, digestOld, time.Minute),
+			},
+			checks: []string{},
+		},
+		{
+			name:     "container not started yet",
+			template: "app:v2",
+			pods: []*corev1.Pod{
+				imagePod("app-0", "app:v2", digestNew, time.Hour),
+				imagePod("app-1", "app:v2", "", time.Minute),
+			},
+			compareNewest: true,
+			checks:        []string{},
+			expected:      digestNew,
+			unresolved:    []string{"app-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := &imageWorkload{kind: "Deployment", name: "web", template: &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: tt.template}}}, pods: tt.pods}
+			result := checkWorkloadImages(workload, tt.compareNewest)
+
+			if checks := findingChecks(result.Findings); !slices.Equal(checks, tt.checks) {
+				t.Fatalf("findings = %v, want %v", checks, tt.checks)
+			}
+			if len(tt.checks) > 0 {
+				finding := result.Findings[0]
+				if !slices.Equal(finding.Pods, tt.findingPods) || !strings.Contains(finding.Suggestion, "IfNotPresent") {
+					t.Errorf("finding = %+v, want pods %v and an imagePullPolicy hint", finding, tt.findingPods)
+				}
+			}
+			container := result.Containers[0]
+			if container.ExpectedDigest != tt.expected || !slices.Equal(container.Unresolved, tt.unresolved) {
+				t.Errorf("expected digest %q, unresolved %v; want %q, %v", container.ExpectedDigest, container.Unresolved, tt.expected, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestCheckImageConsistencyFollowsReplicaSets(t *testing.T) {
+	controller := func(kind, name string, uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: name, UID: uid, Controller: ptr.To(true)}}
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: testutil.DefaultNamespace, UID: "deployment-uid"},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2), Template: appTemplate("app:v2")},
+		Status:     appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2},
+	}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name: "web-5d4f8", Namespace: testutil.DefaultNamespace, UID: "replicaset-uid",
+		OwnerReferences: controller("Deployment", "web", "deployment-uid"),
+	}}
+	stale := imagePod("web-5d4f8-a", "app:v1", digestOld, time.Hour)
+	stale.OwnerReferences = controller("ReplicaSet", "web-5d4f8", "replicaset-uid")
+	current := imagePod("web-5d4f8-b", "app:v2", digestNew, time.Minute)
+	current.OwnerReferences = controller("ReplicaSet", "web-5d4f8", "replicaset-uid")
+	unowned := imagePod("debug", "app:v2", digestOld, time.Minute)
+
+	var report models.ImageConsistencyReport
+	callUtility(t, testutil.NewFakeClient(deployment, replicaSet, stale, current, unowned), CHECK_IMAGE_CONSISTENCY,
+		map[string]any{"kind": "Deployment", "name": "web"}, &report)
+
+	if len(report.Workloads) != 1 || report.Workloads[0].Pods != 2 {
+		t.Fatalf("workloads = %+v, want web with its two pods", report.Workloads)
+	}
+	if checks := findingChecks(report.Workloads[0].Findings); !slices.Equal(checks, []string{"warning/" + models.ImageCheckTemplateMismatch}) {
+		t.Fatalf("findings = %v, want a template mismatch", checks)
+	}
+	// 只在该工作负载的Pod中比较标签，不属于它的debug Pod不参与
+	if len(report.Tags) != 0 || report.Consistent {
+		t.Fatalf("tags = %+v, consistent = %v", report.Tags, report.Consistent)
+	}
+}
@@ -0,0 +1,401 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 镜像来源
+const (
+	imageSourcePods      = "pods"
+	imageSourceWorkloads = "workloads"
+)
+
+// defaultImageRegistry 镜像名未包含仓库地址时使用的默认仓库
+const defaultImageRegistry = "docker.io"
+
+// workloadImageKinds source=workloads时扫描的工作负载类型
+var workloadImageKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "CronJob", "Job"}
+
+// imageSegment 分页扫描的一个单元：一个命名空间中的一种资源类型
+type imageSegment struct {
+	namespace string
+	kind      string
+}
+
+// imageSourceObject 包含Pod模板的对象
+type imageSourceObject struct {
+	namespace string
+	kind      string
+	name      string
+	spec      *corev1.PodSpec
+}
+
+// ListImages 扫描Pod或工作负载模板中的容器镜像，按镜像汇总使用情况并标记有风险的镜像
+// 多个命名空间和资源类型依次扫描，continue令牌的格式为"<扫描单元序号>/<API Server令牌>"
+func (h *UtilityHandler) ListImages(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	source, _ := arguments["source"].(string)
+	namespacesStr, _ := arguments["namespaces"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	allowedStr, _ := arguments["allowedRegistries"].(string)
+	flaggedOnly, _ := arguments["flaggedOnly"].(bool)
+	if source == "" {
+		source = imageSourcePods
+	}
+
+	h.Log.Info("Listing images",
+		"source", source,
+		"namespaces", namespacesStr,
+		"labelSelector", labelSelector,
+		"allowedRegistries", allowedStr,
+	)
+
+	var kinds []string
+	switch source {
+	case imageSourcePods:
+		kinds = []string{"Pod"}
+	case imageSourceWorkloads:
+		kinds = workloadImageKinds
+	default:
+		return utils.NewErrorToolResult(fmt.Sprintf("unsupported source %q, must be one of: %s, %s", source, imageSourcePods, imageSourceWorkloads)), nil
+	}
+
+	page, err := base.ParseListPage(request)
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+
+	namespaces := utils.SplitCommaList(namespacesStr)
+	if len(namespaces) == 0 {
+		// 空字符串表示所有命名空间
+		namespaces = []string{""}
+	}
+	var segments []imageSegment
+	for _, namespace := range namespaces {
+		for _, kind := range kinds {
+			segments = append(segments, imageSegment{namespace: namespace, kind: kind})
+		}
+	}
+
+	index, token := 0, ""
+	if page.Continue != "" {
+		indexStr, rest, _ := strings.Cut(page.Continue, "/")
+		index, err = strconv.Atoi(indexStr)
+		if err != nil || index < 0 || index >= len(segments) {
+			return utils.NewErrorToolResult("invalid continue token; restart the listing without continue"), nil
+		}
+		token = rest
+	}
+
+	var objects []imageSourceObject
+	response := models.ImageInventory{Source: source}
+	remaining := page.Limit
+	for index < len(segments) && remaining > 0 {
+		segment := segments[index]
+		listOptions := metav1.ListOptions{LabelSelector: labelSelector, Limit: remaining, Continue: token}
+		items, next, err := h.listImageSources(ctx, segment, listOptions)
+		if err != nil {
+			h.Log.Error("Failed to list image sources", "kind", segment.kind, "namespace", segment.namespace, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list %s", segment.kind)), nil
+		}
+		objects = append(objects, items...)
+		remaining -= int64(len(items))
+		if next != "" {
+			token = next
+			break
+		}
+		index++
+		token = ""
+	}
+	if index < len(segments) {
+		response.Continue = fmt.Sprintf("%d/%s", index, token)
+		if page.Capped {
+			response.Truncated = true
+			response.Limit = page.Limit
+		}
+	}
+
+	response.Scanned = len(objects)
+	response.Images = aggregateImages(objects, utils.SplitCommaList(allowedStr))
+	response.ByRegistry = groupImages(response.Images, imageRegistryGroup)
+	response.ByNamespace = groupImages(response.Images, imageNamespaceGroup)
+	for _, image := range response.Images {
+		if len(image.Flags) > 0 {
+			response.Flagged++
+		}
+	}
+	if flaggedOnly {
+		flagged := make([]models.ImageUsage, 0, response.Flagged)
+		for _, image := range response.Images {
+			if len(image.Flags) > 0 {
+				flagged = append(flagged, image)
+			}
+		}
+		response.Images = flagged
+	}
+
+	return utils.RenderResult(request, response), nil
+}
+
+// listImageSources 列出一个扫描单元中的对象及其Pod模板，返回下一页的令牌
+func (h *UtilityHandler) listImageSources(
+	ctx context.Context,
+	segment imageSegment,
+	listOptions metav1.ListOptions,
+) ([]imageSourceObject, string, error) {
+	clientSet := h.Client.ClientSet()
+	namespace := segment.namespace
+	var objects []imageSourceObject
+	add := func(meta metav1.ObjectMeta, kind string, spec *corev1.PodSpec) {
+		objects = append(objects, imageSourceObject{namespace: meta.Namespace, kind: kind, name: meta.Name, spec: spec})
+	}
+
+	switch segment.kind {
+	case "Pod":
+		list, err := clientSet.CoreV1().Pods(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			// 由控制器创建的Pod归到其控制器名下，避免同一工作负载的副本分别列出
+			if owner := metav1.GetControllerOf(pod); owner != nil {
+				add(metav1.ObjectMeta{Namespace: pod.Namespace, Name: owner.Name}, owner.Kind, &pod.Spec)
+				continue
+			}
+			add(pod.ObjectMeta, "Pod", &pod.Spec)
+		}
+		return objects, list.Continue, nil
+	case "Deployment":
+		list, err := clientSet.AppsV1().Deployments(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range list.Items {
+			add(list.Items[i].ObjectMeta, segment.kind, &list.Items[i].Spec.Template.Spec)
+		}
+		return objects, list.Continue, nil
+	case "StatefulSet":
+		list, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range list.Items {
+			add(list.Items[i].ObjectMeta, segment.kind, &list.Items[i].Spec.Template.Spec)
+		}
+		return objects, list.Continue, nil
+	case "DaemonSet":
+		list, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range list.Items {
+			add(list.Items[i].ObjectMeta, segment.kind, &list.Items[i].Spec.Template.Spec)
+		}
+		return objects, list.Continue, nil
+	case "CronJob":
+		list, err := clientSet.BatchV1().CronJobs(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range list.Items {
+			add(list.Items[i].ObjectMeta, segment.kind, &list.Items[i].Spec.JobTemplate.Spec.Template.Spec)
+		}
+		return objects, list.Continue, nil
+	case "Job":
+		list, err := clientSet.BatchV1().Jobs(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, "", err
+		}
+		for i := range list.Items {
+			// CronJob创建的Job已通过CronJob模板统计
+			if metav1.GetControllerOf(&list.Items[i]) != nil {
+				continue
+			}
+			add(list.Items[i].ObjectMeta, segment.kind, &list.Items[i].Spec.Template.Spec)
+		}
+		return objects, list.Continue, nil
+	}
+	return nil, "", fmt.Errorf("unsupported kind %s", segment.kind)
+}
+
+// aggregateImages 按镜像汇总使用情况并标记风险，结果按镜像名排序
+func aggregateImages(objects []imageSourceObject, allowedRegistries []string) []models.ImageUsage {
+	usages := make(map[string]*models.ImageUsage)
+	workloads := make(map[string]map[string]bool)
+	namespaces := make(map[string]map[string]bool)
+	policies := make(map[string]map[string]bool)
+
+	for _, obj := range objects {
+		workload := obj.namespace + "/" + obj.kind + "/" + obj.name
+		hasPullSecrets := len(obj.spec.ImagePullSecrets) > 0
+
+		var containers []corev1.Container
+		containers = append(containers, obj.spec.InitContainers...)
+		containers = append(containers, obj.spec.Containers...)
+		for _, container := range containers {
+			usage, ok := usages[container.Image]
+			if !ok {
+				usage = newImageUsage(container.Image, allowedRegistries)
+				usages[container.Image] = usage
+				workloads[container.Image] = make(map[string]bool)
+				namespaces[container.Image] = make(map[string]bool)
+				policies[container.Image] = make(map[string]bool)
+			}
+			// 同一对象的多个容器使用相同镜像时只统计一次
+			if workloads[container.Image][workload] {
+				continue
+			}
+			workloads[container.Image][workload] = true
+			namespaces[container.Image][obj.namespace] = true
+			if container.ImagePullPolicy != "" {
+				policies[container.Image][string(container.ImagePullPolicy)] = true
+			}
+			if !hasPullSecrets {
+				usage.WithoutPullSecrets++
+			}
+		}
+	}
+
+	result := make([]models.ImageUsage, 0, len(usages))
+	for image, usage := range usages {
+		usage.Workloads = sortedKeys(workloads[image])
+		usage.Namespaces = sortedKeys(namespaces[image])
+		usage.PullPolicies = sortedKeys(policies[image])
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Image < result[j].Image
+	})
+	return result
+}
+
+// newImageUsage 解析镜像引用并计算风险标记
+func newImageUsage(image string, allowedRegistries []string) *models.ImageUsage {
+	registry, repository, tag, digest := parseImageReference(image)
+	usage := &models.ImageUsage{
+		Image:      image,
+		Registry:   registry,
+		Repository: repository,
+		Tag:        tag,
+		Digest:     digest,
+	}
+	switch {
+	case tag == "latest":
+		usage.Flags = append(usage.Flags, models.ImageFlagLatestTag)
+	case tag == "" && digest == "":
+		usage.Flags = append(usage.Flags, models.ImageFlagNoTag)
+	}
+	if len(allowedRegistries) > 0 && !registryAllowed(registry, repository, allowedRegistries) {
+		usage.Flags = append(usage.Flags, models.ImageFlagUntrustedRegistry)
+	}
+	return usage
+}
+
+// parseImageReference 将镜像引用拆分为仓库地址、镜像路径、标签和摘要
+// 第一段包含"."或":"或为localhost时视为仓库地址，否则使用docker.io
+func parseImageReference(image string) (registry, repository, tag, digest string) {
+	name, digest, _ := strings.Cut(image, "@")
+	// 最后一段路径中的冒号分隔标签，仓库地址中的端口号不受影响
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, tag = name[:colon], name[colon+1:]
+	}
+
+	registry = defaultImageRegistry
+	repository = name
+	if first, rest, found := strings.Cut(name, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, repository = first, rest
+	}
+	if registry == defaultImageRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry, repository, tag, digest
+}
+
+// registryAllowed 判断镜像是否来自允许的仓库，允许列表项可以是仓库地址或带路径前缀，例如"gcr.io/my-project"
+func registryAllowed(registry, repository string, allowed []string) bool {
+	full := registry + "/" + repository
+	for _, entry := range allowed {
+		entry = strings.TrimSuffix(entry, "/")
+		if registry == entry || strings.HasPrefix(full, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// groupImages 按keys返回的分组汇总镜像数量、对象数量和带风险标记的镜像数量，按镜像数量降序排列
+// keys返回镜像所属的分组及该分组中使用它的对象数量
+func groupImages(images []models.ImageUsage, keys func(models.ImageUsage) map[string]int) []models.ImageGroup {
+	groups := make(map[string]*models.ImageGroup)
+	for _, image := range images {
+		for key, workloads := range keys(image) {
+			group, ok := groups[key]
+			if !ok {
+				group = &models.ImageGroup{Name: key}
+				groups[key] = group
+			}
+			group.Images++
+			group.Workloads += workloads
+			if len(image.Flags) > 0 {
+				group.Flagged++
+			}
+		}
+	}
+
+	result := make([]models.ImageGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Images != result[j].Images {
+			return result[i].Images > result[j].Images
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// imageRegistryGroup 按仓库地址分组
+func imageRegistryGroup(image models.ImageUsage) map[string]int {
+	return map[string]int{image.Registry: len(image.Workloads)}
+}
+
+// imageNamespaceGroup 按命名空间分组，对象格式为"namespace/Kind/name"
+func imageNamespaceGroup(image models.ImageUsage) map[string]int {
+	groups := make(map[string]int, len(image.Namespaces))
+	for _, workload := range image.Workloads {
+		namespace, _, _ := strings.Cut(workload, "/")
+		groups[namespace]++
+	}
+	return groups
+}
+
+// sortedKeys 返回排序后的集合元素
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
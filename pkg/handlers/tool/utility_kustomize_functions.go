@@ -0,0 +1,176 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// kustomizationSourceInline 内联文件构建来源
+const kustomizationSourceInline = "inline"
+
+// kustomizationFileNames kustomize可识别的kustomization文件名
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// RenderKustomization 构建kustomization并返回生成的YAML，不修改集群
+func (h *UtilityHandler) RenderKustomization(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	h.Log.Info("Rendering kustomization")
+
+	render, failure := h.buildKustomization(request)
+	if failure != nil {
+		return failure, nil
+	}
+	return utils.RenderResult(request, render), nil
+}
+
+// ApplyKustomization 构建kustomization并像APPLY_MANIFEST一样应用生成的资源
+func (h *UtilityHandler) ApplyKustomization(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	h.Log.Info("Applying kustomization")
+
+	render, failure := h.buildKustomization(request)
+	if failure != nil {
+		return failure, nil
+	}
+	if len(render.Resources) == 0 {
+		return utils.NewErrorToolResult("kustomization built no resources"), nil
+	}
+	return h.applyManifest(ctx, request, render.YAML, nil), nil
+}
+
+// buildKustomization 在进程内运行kustomize构建内联文件或服务器本地目录，
+// 构建失败时原样返回kustomize的错误，其中包含出错的文件名
+func (h *UtilityHandler) buildKustomization(request mcp.CallToolRequest) (*models.KustomizationRender, *mcp.CallToolResult) {
+	arguments := request.GetArguments()
+	dir, _ := arguments["path"].(string)
+	files, err := utils.StringMapArgument(arguments, "files")
+	if err != nil {
+		return nil, utils.NewErrorToolResult(err.Error())
+	}
+
+	var fSys filesys.FileSystem
+	var root string
+	switch {
+	case len(files) > 0 && dir != "":
+		return nil, utils.NewErrorToolResult("specify either files or path, not both")
+	case len(files) > 0:
+		fSys, root, err = inlineKustomization(files)
+		if err != nil {
+			return nil, utils.NewErrorToolResult(err.Error())
+		}
+	case dir != "":
+		root, err = kustomizationDir(dir)
+		if err != nil {
+			return nil, utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeRefused,
+				Message: err.Error(),
+				Hint:    "Pass the kustomization as inline files, or start the server with --kustomize-root and use a path under it.",
+			})
+		}
+		fSys = filesys.MakeFsOnDisk()
+	default:
+		return nil, utils.NewErrorToolResult("either files or path is required")
+	}
+
+	// 与kubectl kustomize一致：按旧版顺序输出，使Namespace和CRD排在依赖它们的资源之前
+	options := krusty.MakeDefaultOptions()
+	options.Reorder = krusty.ReorderOptionLegacy
+	resMap, err := krusty.MakeKustomizer(options).Run(fSys, root)
+	if err != nil {
+		return nil, utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: err.Error(),
+			Hint:    "kustomize build failed; fix the file named in the message and retry. RENDER_KUSTOMIZATION builds without applying.",
+		})
+	}
+	out, err := resMap.AsYaml()
+	if err != nil {
+		return nil, utils.NewErrorToolResult(fmt.Sprintf("failed to serialize kustomize output: %v", err))
+	}
+
+	render := &models.KustomizationRender{
+		Source:    kustomizationSourceInline,
+		Resources: []string{},
+		YAML:      string(out),
+	}
+	if dir != "" {
+		render.Source = root
+	}
+	for _, res := range resMap.Resources() {
+		ref := res.GetName()
+		if namespace := res.GetNamespace(); namespace != "" {
+			ref = namespace + "/" + ref
+		}
+		render.Resources = append(render.Resources, res.GetKind()+" "+ref)
+	}
+	return render, nil
+}
+
+// inlineKustomization 将文件名到内容的映射写入内存文件系统，返回构建的根目录
+func inlineKustomization(files map[string]string) (filesys.FileSystem, string, error) {
+	const root = "/kustomization"
+	fSys := filesys.MakeFsInMemory()
+	for name, content := range files {
+		cleaned := path.Clean(name)
+		if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return nil, "", fmt.Errorf("invalid file name %q: must be a relative path inside the kustomization", name)
+		}
+		target := path.Join(root, cleaned)
+		if err := fSys.MkdirAll(path.Dir(target)); err != nil {
+			return nil, "", err
+		}
+		if err := fSys.WriteFile(target, []byte(content)); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, name := range kustomizationFileNames {
+		if _, ok := files[name]; ok {
+			return fSys, root, nil
+		}
+	}
+	return nil, "", fmt.Errorf("files must contain %s at the top level", strings.Join(kustomizationFileNames, ", "))
+}
+
+// kustomizationDir 将目录解析为绝对路径，并确认它位于服务器配置的kustomize根目录内
+func kustomizationDir(dir string) (string, error) {
+	allowedRoot := base.GetOptions().KustomizeRoot
+	if allowedRoot == "" {
+		return "", fmt.Errorf("building a kustomization from a server-local path is disabled")
+	}
+	allowedRoot, err := filepath.EvalSymlinks(allowedRoot)
+	if err != nil {
+		return "", fmt.Errorf("invalid kustomize root: %w", err)
+	}
+	allowedRoot, err = filepath.Abs(allowedRoot)
+	if err != nil {
+		return "", fmt.Errorf("invalid kustomize root: %w", err)
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(allowedRoot, dir)
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("cannot access path %q: %w", dir, err)
+	}
+	rel, err := filepath.Rel(allowedRoot, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside the kustomize root %s", dir, allowedRoot)
+	}
+	return resolved, nil
+}
@@ -10,14 +10,19 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
 // ExplainResource 解释资源结构
@@ -25,130 +30,106 @@ func (h *UtilityHandler) ExplainResource(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	log := logger.FromContext(ctx)
 	arguments := request.GetArguments()
 	kind, _ := arguments["kind"].(string)
 	apiVersion, _ := arguments["apiVersion"].(string)
 	field, _ := arguments["field"].(string)
 	recursive, _ := arguments["recursive"].(bool)
 
-	h.Log.Info("Explaining resource",
+	log.Info("Explaining resource",
 		"kind", kind,
 		"apiVersion", apiVersion,
 		"field", field,
 		"recursive", recursive,
 	)
 
-	// 构建参数
-	group, version := parseGroup(apiVersion), parseVersion(apiVersion)
-
 	// 构建响应
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Resource Structure for %s (%s):\n\n", kind, apiVersion))
+	response := models.ResourceExplanation{
+		Kind:       kind,
+		APIVersion: apiVersion,
+	}
 
-	// 从discovery客户端获取资源定义
-	_, resources, err := h.Client.GetDiscoveryClient().ServerGroupsAndResources()
+	// 通过RESTMapper解析资源类型，再从discovery中读取资源定义
+	gvr, _, err := utils.ResolveGVR(h.Client, apiVersion, kind)
 	if err != nil {
-		if !discovery.IsGroupDiscoveryFailedError(err) {
-			h.Log.Error("Failed to get API resources", "error", err)
-			return nil, fmt.Errorf("failed to get API resources: %w", err)
-		}
-		h.Log.Warn("Partial API discovery error", "error", err)
+		log.Error("Failed to resolve resource", "kind", kind, "apiVersion", apiVersion, "error", err)
+		return utils.NewKubeErrorResult(err), nil
+	}
+	resourceList, err := h.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		log.Error("Failed to get API resources", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to get API resources"), nil
 	}
 
 	// 查找特定的资源定义
 	var targetResource *metav1.APIResource
-	targetGroupVersion := ""
-	if group == "" {
-		targetGroupVersion = version
-	} else {
-		targetGroupVersion = group + "/" + version
-	}
-
-	for _, resList := range resources {
-		if resList.GroupVersion == targetGroupVersion {
-			for _, res := range resList.APIResources {
-				if strings.EqualFold(res.Kind, kind) {
-					targetResource = &res
-					break
-				}
-			}
-			if targetResource != nil {
-				break
-			}
+	for i := range resourceList.APIResources {
+		if resourceList.APIResources[i].Name == gvr.Resource {
+			targetResource = &resourceList.APIResources[i]
+			break
 		}
 	}
 
-	if targetResource == nil {
-		result.WriteString(fmt.Sprintf("Resource %s with apiVersion %s not found in the cluster.\n", kind, apiVersion))
-	} else {
+	if targetResource != nil {
 		// 显示资源基本信息
-		result.WriteString(fmt.Sprintf("KIND:         %s\n", targetResource.Kind))
-		result.WriteString(fmt.Sprintf("API VERSION:  %s\n", apiVersion))
-		result.WriteString(fmt.Sprintf("RESOURCE:     %s\n", targetResource.Name))
-		result.WriteString(fmt.Sprintf("SCOPE:        %s\n", getScopeText(targetResource.Namespaced)))
-		result.WriteString(fmt.Sprintf("VERBS:        %s\n", strings.Join(targetResource.Verbs, ", ")))
-
-		if len(targetResource.ShortNames) > 0 {
-			result.WriteString(fmt.Sprintf("SHORTNAMES:   %s\n", strings.Join(targetResource.ShortNames, ", ")))
-		}
-
-		// 如果指定了字段，显示该字段的详细信息
-		if field != "" {
-			result.WriteString(fmt.Sprintf("\nFIELD:        %s\n", field))
-		}
-
-		// 使用OpenAPI模式解释字段结构
-		result.WriteString("\nDESCRIPTION:\n")
+		response.Found = true
+		response.Kind = targetResource.Kind
+		response.Resource = targetResource.Name
+		response.Scope = getScopeText(targetResource.Namespaced)
+		response.Verbs = targetResource.Verbs
+		response.ShortNames = targetResource.ShortNames
+		response.Field = field
 
 		// 提供一些常见字段的说明
 		if field == "" || field == "metadata" {
-			result.WriteString("  metadata - 标准的Kubernetes对象元数据\n")
+			metadata := models.ExplainField{Name: "metadata", Description: "标准的Kubernetes对象元数据"}
 			if recursive {
-				result.WriteString("    name        - 对象的名称，在命名空间内必须唯一\n")
-				result.WriteString("    namespace   - 对象所属的命名空间\n")
-				result.WriteString("    labels      - 键值对标签，用于组织和分类对象\n")
-				result.WriteString("    annotations - 键值对注释，用于存储非识别性元数据\n")
+				metadata.Fields = []models.ExplainField{
+					{Name: "name", Description: "对象的名称，在命名空间内必须唯一"},
+					{Name: "namespace", Description: "对象所属的命名空间"},
+					{Name: "labels", Description: "键值对标签，用于组织和分类对象"},
+					{Name: "annotations", Description: "键值对注释，用于存储非识别性元数据"},
+				}
 			}
+			response.Fields = append(response.Fields, metadata)
 		}
 
 		if field == "" || field == "spec" {
-			result.WriteString("  spec - 期望状态的规格说明\n")
+			spec := models.ExplainField{Name: "spec", Description: "期望状态的规格说明"}
 			// 根据不同资源类型提供更具体的spec字段说明
-			if strings.EqualFold(kind, "Pod") {
-				if recursive {
-					result.WriteString("    containers  - Pod中的容器列表\n")
-					result.WriteString("    volumes     - Pod可以挂载的卷定义\n")
-					result.WriteString("    nodeSelector - 限制Pod调度到匹配标签的节点上\n")
-				}
-			} else if strings.EqualFold(kind, "Deployment") {
-				if recursive {
-					result.WriteString("    replicas    - 期望运行的Pod副本数\n")
-					result.WriteString("    selector    - 标签选择器，用于标识Pod\n")
-					result.WriteString("    template    - Pod模板，定义要创建的Pod\n")
-					result.WriteString("    strategy    - 部署策略，控制Pod更新方式\n")
-				}
-			} else if strings.EqualFold(kind, "Service") {
-				if recursive {
-					result.WriteString("    selector    - 标签选择器，选择服务后端Pod\n")
-					result.WriteString("    ports       - 服务暴露的端口列表\n")
-					result.WriteString("    type        - 服务类型 (ClusterIP, NodePort, LoadBalancer, ExternalName)\n")
+			if recursive {
+				switch {
+				case strings.EqualFold(kind, "Pod"):
+					spec.Fields = []models.ExplainField{
+						{Name: "containers", Description: "Pod中的容器列表"},
+						{Name: "volumes", Description: "Pod可以挂载的卷定义"},
+						{Name: "nodeSelector", Description: "限制Pod调度到匹配标签的节点上"},
+					}
+				case strings.EqualFold(kind, "Deployment"):
+					spec.Fields = []models.ExplainField{
+						{Name: "replicas", Description: "期望运行的Pod副本数"},
+						{Name: "selector", Description: "标签选择器，用于标识Pod"},
+						{Name: "template", Description: "Pod模板，定义要创建的Pod"},
+						{Name: "strategy", Description: "部署策略，控制Pod更新方式"},
+					}
+				case strings.EqualFold(kind, "Service"):
+					spec.Fields = []models.ExplainField{
+						{Name: "selector", Description: "标签选择器，选择服务后端Pod"},
+						{Name: "ports", Description: "服务暴露的端口列表"},
+						{Name: "type", Description: "服务类型 (ClusterIP, NodePort, LoadBalancer, ExternalName)"},
+					}
 				}
 			}
+			response.Fields = append(response.Fields, spec)
 		}
 
 		if field == "" || field == "status" {
-			result.WriteString("  status - 当前状态信息\n")
+			response.Fields = append(response.Fields, models.ExplainField{Name: "status", Description: "当前状态信息"})
 		}
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, response), nil
 }
 
 // getScopeText 返回资源作用域的文本描述
@@ -164,207 +145,372 @@ func (h *UtilityHandler) ApplyManifest(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	yamlStr, _ := request.GetArguments()["yaml"].(string)
+	if yamlStr == "" {
+		return utils.NewErrorToolResult("yaml manifest is required"), nil
+	}
+	return h.applyManifest(ctx, request, yamlStr, nil), nil
+}
+
+// 清单中单个文档的应用结果，与kubectl apply的输出一致
+const (
+	applyActionCreated    = "created"
+	applyActionConfigured = "configured"
+	applyActionUnchanged  = "unchanged"
+	// applyActionApplied 应用成功但无法读取应用前的对象，不能区分新建还是修改
+	applyActionApplied = "applied"
+	applyActionFailed  = "failed"
+	// applyActionSkipped rollbackOnError模式下前面的文档失败后不再应用
+	applyActionSkipped = "skipped"
+)
+
+// manifestDocument 解析和校验后待应用的清单文档，item.Error非空表示准备阶段已失败
+type manifestDocument struct {
+	item     models.ApplyResult
+	resource dynamic.ResourceInterface
+	data     []byte
+}
+
+// applyManifest 按请求中的dryRun、fieldManager和checkQuota参数通过服务器端应用逐个应用多文档清单。
+// atomic为true时先对所有文档进行服务端试运行，任一文档失败则不做任何修改；
+// rollbackOnError为true时某个文档失败后停止应用，并删除本次调用中新建的资源。
+// source非空时记录在结果中，表示清单从URL获取
+func (h *UtilityHandler) applyManifest(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	yamlStr string,
+	source *models.ManifestSource,
+) *mcp.CallToolResult {
+	log := logger.FromContext(ctx)
 	arguments := request.GetArguments()
-	yamlStr, _ := arguments["yaml"].(string)
 	dryRun, _ := arguments["dryRun"].(bool)
 	fieldManager, _ := arguments["fieldManager"].(string)
+	checkQuota, _ := arguments["checkQuota"].(bool)
+	atomic, _ := arguments["atomic"].(bool)
+	rollbackOnError, _ := arguments["rollbackOnError"].(bool)
+	if fieldManager == "" {
+		fieldManager = "kubernetes-mcp"
+	}
 
-	h.Log.Info("Applying manifest",
+	log.Info("Applying manifest",
 		"dryRun", dryRun,
 		"fieldManager", fieldManager,
+		"checkQuota", checkQuota,
+		"atomic", atomic,
+		"rollbackOnError", rollbackOnError,
 	)
 
-	if yamlStr == "" {
-		return nil, fmt.Errorf("yaml manifest is required")
+	// 应用前检查清单是否超出命名空间的ResourceQuota
+	if checkQuota {
+		fit, err := h.estimateQuotaFit(ctx, yamlStr, "")
+		if err != nil {
+			return utils.NewKubeErrorResult(err, "failed to check resource quotas")
+		}
+		if !fit.Fits {
+			exceeded := fit.Exceeded[0]
+			return utils.NewToolErrorResult(models.ToolError{
+				Code: utils.ErrorCodeRefused,
+				Message: fmt.Sprintf("manifest exceeds %d quota limit(s), first: %s in ResourceQuota %s/%s by %s",
+					len(fit.Exceeded), exceeded.Resource, exceeded.Namespace, exceeded.Quota, exceeded.ExceededBy),
+				Hint:    "Reduce replicas or resource requests, or raise the ResourceQuota; see details for the per-resource arithmetic, or run CHECK_QUOTA_FIT.",
+				Details: fit,
+			})
+		}
 	}
 
-	// 构建响应
-	var result strings.Builder
-	if dryRun {
-		result.WriteString("Dry Run: Resources that would be applied:\n\n")
-	} else {
-		result.WriteString("Applied Resources:\n\n")
+	docs := h.prepareManifest(ctx, yamlStr)
+	response := models.ApplyResults{
+		DryRun:          dryRun,
+		Atomic:          atomic,
+		RollbackOnError: rollbackOnError,
+		Source:          source,
 	}
 
-	// 将YAML拆分为多个文档
-	docs := strings.Split(yamlStr, "---")
-	appliedCount := 0
-	errorCount := 0
+	// 先试运行所有文档，有失败时返回试运行结果，不做任何实际修改
+	if atomic && !dryRun {
+		preview := h.applyDocuments(ctx, docs, fieldManager, true, false)
+		if countFailures(preview) > 0 {
+			response.Aborted = true
+			response.Items = preview
+			response.Hint = "Nothing was applied: the server dry-run rejected the documents marked failed. Fix them and apply again."
+			summarizeApplyResults(&response)
+			return utils.RenderResult(request, response)
+		}
+	}
 
-	for i, doc := range docs {
+	response.Items = h.applyDocuments(ctx, docs, fieldManager, dryRun, rollbackOnError && !dryRun)
+	summarizeApplyResults(&response)
+	if response.RolledBack > 0 {
+		response.Hint = "Resources created by this call were deleted after a document failed; resources that already existed keep the changes applied before the failure."
+	}
+	return utils.RenderResult(request, response)
+}
+
+// prepareManifest 拆分并解析多文档清单，解析GVR并进行权限预检，返回按文档顺序排列的待应用文档
+func (h *UtilityHandler) prepareManifest(ctx context.Context, yamlStr string) []*manifestDocument {
+	log := logger.FromContext(ctx)
+	var docs []*manifestDocument
+	for i, doc := range strings.Split(yamlStr, "---") {
 		doc = strings.TrimSpace(doc)
 		if doc == "" {
 			continue
 		}
+		prepared := &manifestDocument{item: models.ApplyResult{Document: i + 1}}
+		docs = append(docs, prepared)
+		item := &prepared.item
+		fail := func(message string) {
+			item.Action = applyActionFailed
+			item.Error = message
+		}
 
 		// 解析YAML为非结构化对象
 		obj := &unstructured.Unstructured{}
 		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
-			h.Log.Error("Failed to parse YAML document",
+			log.Error("Failed to parse YAML document",
 				"document", i+1,
 				"error", err,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: %v\n", i+1, err))
-			errorCount++
+			fail(err.Error())
 			continue
 		}
 
 		// 获取资源类型和名称
-		kind := obj.GetKind()
-		apiVersion := obj.GetAPIVersion()
-		name := obj.GetName()
-		namespace := obj.GetNamespace()
+		item.Kind = obj.GetKind()
+		item.ApiVersion = obj.GetAPIVersion()
+		item.Name = obj.GetName()
+		item.Namespace = obj.GetNamespace()
 
-		if kind == "" || apiVersion == "" {
-			h.Log.Error("Document is missing kind or apiVersion",
+		if item.Kind == "" || item.ApiVersion == "" {
+			log.Error("Document is missing kind or apiVersion",
 				"document", i+1,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: missing kind or apiVersion\n", i+1))
-			errorCount++
+			fail("missing kind or apiVersion")
 			continue
 		}
 
-		if name == "" {
-			h.Log.Error("Document is missing metadata.name",
+		if item.Name == "" {
+			log.Error("Document is missing metadata.name",
 				"document", i+1,
-				"kind", kind,
-				"apiVersion", apiVersion,
+				"kind", item.Kind,
+				"apiVersion", item.ApiVersion,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: missing metadata.name\n", i+1))
-			errorCount++
+			fail("missing metadata.name")
 			continue
 		}
 
-		h.Log.Info("Processing resource",
-			"document", i+1,
-			"kind", kind,
-			"apiVersion", apiVersion,
-			"name", name,
-			"namespace", namespace,
-		)
-
-		// 设置ServerSideApply选项
-		var options metav1.PatchOptions
-		if fieldManager != "" {
-			options.FieldManager = fieldManager
-		} else {
-			options.FieldManager = "kubernetes-mcp"
-		}
-
-		if dryRun {
-			options.DryRun = []string{"All"}
-		}
-
-		// 确定资源的组、版本和资源类型
-		group, version := parseGroup(apiVersion), parseVersion(apiVersion)
-		gvr, err := h.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(apiVersion)
+		// 通过RESTMapper确定资源的GVR和作用域
+		gvr, isNamespaced, err := utils.ResolveGVR(h.Client, item.ApiVersion, item.Kind)
 		if err != nil {
-			h.Log.Error("Failed to get resource for group version",
-				"apiVersion", apiVersion,
+			log.Error("Failed to resolve resource",
+				"kind", item.Kind,
+				"apiVersion", item.ApiVersion,
 				"error", err,
 			)
-			result.WriteString(fmt.Sprintf("Error: Failed to get resource for apiVersion %s: %v\n", apiVersion, err))
-			errorCount++
+			fail(err.Error())
 			continue
 		}
-
-		// 查找资源名称
-		var resourceName string
-		for _, r := range gvr.APIResources {
-			if strings.EqualFold(r.Kind, kind) {
-				resourceName = r.Name
-				break
-			}
-		}
-
-		if resourceName == "" {
-			h.Log.Error("Resource not found",
-				"kind", kind,
-				"apiVersion", apiVersion,
-			)
-			result.WriteString(fmt.Sprintf("Error: Resource not found for kind %s with apiVersion %s\n", kind, apiVersion))
-			errorCount++
-			continue
-		}
-
-		// 使用动态客户端应用资源
-		dynamicClient := h.Client.GetDynamicClient()
-		var dr dynamic.ResourceInterface
-
-		// 确定是命名空间资源还是集群资源
-		isNamespaced := false
-		for _, r := range gvr.APIResources {
-			if strings.EqualFold(r.Kind, kind) && r.Namespaced {
-				isNamespaced = true
-				break
-			}
-		}
+		item.ClusterScoped = !isNamespaced
 
 		// 获取适当的动态资源接口
+		preflightNamespace := ""
 		if isNamespaced {
-			ns := namespace
-			if ns == "" {
-				ns = "default"
+			preflightNamespace = item.Namespace
+			if preflightNamespace == "" {
+				preflightNamespace = "default"
 			}
-			dr = dynamicClient.Resource(schema.GroupVersionResource{
-				Group:    group,
-				Version:  version,
-				Resource: resourceName,
-			}).Namespace(ns)
+			prepared.resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(preflightNamespace)
 		} else {
-			dr = dynamicClient.Resource(schema.GroupVersionResource{
-				Group:    group,
-				Version:  version,
-				Resource: resourceName,
-			})
+			prepared.resource = h.Client.GetDynamicClient().Resource(gvr)
+		}
+
+		// 权限预检，服务端应用使用patch动词
+		if denied := h.PreflightCheck(ctx, "patch", gvr, preflightNamespace, item.Name); denied != nil {
+			fail(deniedMessage(denied))
+			continue
 		}
 
 		// 转换为JSON以应用
-		data, err := json.Marshal(obj)
+		prepared.data, err = json.Marshal(obj)
 		if err != nil {
-			h.Log.Error("Failed to marshal object to JSON",
-				"kind", kind,
-				"name", name,
+			log.Error("Failed to marshal object to JSON",
+				"kind", item.Kind,
+				"name", item.Name,
 				"error", err,
 			)
-			result.WriteString(fmt.Sprintf("Error: Failed to marshal %s/%s: %v\n", kind, name, err))
-			errorCount++
+			fail(fmt.Sprintf("failed to marshal %s/%s: %v", item.Kind, item.Name, err))
 			continue
 		}
+	}
+	return docs
+}
 
-		// 使用服务器端应用
-		_, err = dr.Patch(ctx, name, types.ApplyPatchType, data, options)
-		if err != nil {
-			h.Log.Error("Failed to apply resource",
-				"kind", kind,
-				"name", name,
-				"error", err,
-			)
-			result.WriteString(fmt.Sprintf("Error: Failed to apply %s/%s: %v\n", kind, name, err))
-			errorCount++
+// applyDocuments 按顺序应用文档并返回每个文档的结果。
+// rollback为true时某个文档失败后跳过其余文档，并按相反顺序删除本次新建的资源
+func (h *UtilityHandler) applyDocuments(
+	ctx context.Context,
+	docs []*manifestDocument,
+	fieldManager string,
+	dryRun, rollback bool,
+) []models.ApplyResult {
+	items := make([]models.ApplyResult, 0, len(docs))
+	var created []int
+	failed := false
+	for _, doc := range docs {
+		if failed && rollback {
+			item := doc.item
+			item.Action = applyActionSkipped
+			item.Error = ""
+			items = append(items, item)
 			continue
 		}
+		item := h.applyDocument(ctx, doc, fieldManager, dryRun)
+		if !item.Success {
+			failed = true
+		} else if item.Action == applyActionCreated {
+			created = append(created, len(items))
+		}
+		items = append(items, item)
+	}
 
-		// 记录成功
-		if namespace != "" {
-			result.WriteString(fmt.Sprintf("Success: Applied %s/%s in namespace %s\n", kind, name, namespace))
-		} else {
-			result.WriteString(fmt.Sprintf("Success: Applied %s/%s (cluster-scoped)\n", kind, name))
+	if failed && rollback {
+		for i := len(created) - 1; i >= 0; i-- {
+			index := created[i]
+			h.rollbackDocument(ctx, docs[index], &items[index])
 		}
-		appliedCount++
+	}
+	return items
+}
+
+// applyDocument 通过服务器端应用提交单个文档，应用前先读取对象以区分新建、修改和未变化
+func (h *UtilityHandler) applyDocument(
+	ctx context.Context,
+	doc *manifestDocument,
+	fieldManager string,
+	dryRun bool,
+) models.ApplyResult {
+	log := logger.FromContext(ctx)
+	item := doc.item
+	if item.Error != "" {
+		return item
+	}
+
+	log.Info("Processing resource",
+		"document", item.Document,
+		"kind", item.Kind,
+		"apiVersion", item.ApiVersion,
+		"name", item.Name,
+		"namespace", item.Namespace,
+		"dryRun", dryRun,
+	)
+
+	// 读取失败（例如没有get权限）时仍然应用，只是无法判断操作类型，也不会在回滚时删除
+	existing, getErr := doc.resource.Get(ctx, item.Name, metav1.GetOptions{})
+	found := getErr == nil
+	if getErr != nil && !apierrors.IsNotFound(getErr) {
+		log.Warn("Failed to get resource before apply", "kind", item.Kind, "name", item.Name, "error", getErr)
+	}
+
+	options := metav1.PatchOptions{FieldManager: fieldManager}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	applied, err := doc.resource.Patch(ctx, item.Name, types.ApplyPatchType, doc.data, options)
+	if err != nil {
+		log.Error("Failed to apply resource",
+			"kind", item.Kind,
+			"name", item.Name,
+			"error", err,
+		)
+		// 原样保留服务器返回的消息，其中包含准入Webhook拒绝的原因
+		item.Action = applyActionFailed
+		item.Error = err.Error()
+		item.Reason = string(apierrors.ReasonForError(err))
+		return item
 	}
 
-	// 添加摘要
-	result.WriteString(fmt.Sprintf("\nSummary: %d resource(s) applied, %d error(s)\n", appliedCount, errorCount))
+	item.Success = true
+	switch {
+	case found:
+		item.Action = applyActionConfigured
+		if unchangedByApply(existing, applied, dryRun) {
+			item.Action = applyActionUnchanged
+		}
+	case apierrors.IsNotFound(getErr):
+		item.Action = applyActionCreated
+	default:
+		item.Action = applyActionApplied
+	}
+	if !dryRun && item.Action != applyActionUnchanged {
+		// 命名空间、节点、CRD的缓存随之失效
+		h.Client.Cache().InvalidateKind(item.Kind)
+	}
+	return item
+}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+// rollbackDocument 删除本次调用中新建的资源，并在结果中记录回滚情况
+func (h *UtilityHandler) rollbackDocument(ctx context.Context, doc *manifestDocument, item *models.ApplyResult) {
+	log := logger.FromContext(ctx)
+	propagation := metav1.DeletePropagationBackground
+	err := doc.resource.Delete(ctx, item.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error("Failed to roll back created resource", "kind", item.Kind, "name", item.Name, "namespace", item.Namespace, "error", err)
+		item.RollbackError = err.Error()
+		return
+	}
+	log.Info("Rolled back created resource", "kind", item.Kind, "name", item.Name, "namespace", item.Namespace)
+	h.Client.Cache().InvalidateKind(item.Kind)
+	item.RolledBack = true
+}
+
+// unchangedByApply 判断服务器端应用是否没有改变对象。实际应用时resourceVersion不变即未改变；
+// 试运行不会写入，比较忽略managedFields、resourceVersion和generation后的对象
+func unchangedByApply(existing, applied *unstructured.Unstructured, dryRun bool) bool {
+	if !dryRun {
+		return existing.GetResourceVersion() == applied.GetResourceVersion()
+	}
+	before, after := existing.DeepCopy(), applied.DeepCopy()
+	for _, obj := range []*unstructured.Unstructured{before, after} {
+		obj.SetManagedFields(nil)
+		obj.SetResourceVersion("")
+		obj.SetGeneration(0)
+	}
+	return equality.Semantic.DeepEqual(before.Object, after.Object)
+}
+
+// countFailures 统计失败的文档数量
+func countFailures(items []models.ApplyResult) int {
+	failures := 0
+	for _, item := range items {
+		if item.Action == applyActionFailed {
+			failures++
+		}
+	}
+	return failures
+}
+
+// summarizeApplyResults 按操作类型统计应用结果
+func summarizeApplyResults(response *models.ApplyResults) {
+	for _, item := range response.Items {
+		switch item.Action {
+		case applyActionFailed:
+			response.ErrorCount++
+		case applyActionSkipped:
+			response.Skipped++
+		default:
+			response.SuccessCount++
+		}
+		switch item.Action {
+		case applyActionCreated:
+			response.Created++
+		case applyActionConfigured, applyActionApplied:
+			response.Configured++
+		case applyActionUnchanged:
+			response.Unchanged++
+		}
+		if item.RolledBack {
+			response.RolledBack++
+		}
+	}
 }
 
 // ValidateManifest 验证资源清单
@@ -372,23 +518,30 @@ func (h *UtilityHandler) ValidateManifest(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	log := logger.FromContext(ctx)
 	arguments := request.GetArguments()
 	yamlStr, _ := arguments["yaml"].(string)
 
-	h.Log.Info("Validating manifest")
+	log.Info("Validating manifest")
 
 	if yamlStr == "" {
-		return nil, fmt.Errorf("yaml manifest is required")
+		return utils.NewErrorToolResult("yaml manifest is required"), nil
 	}
 
 	// 构建响应
-	var result strings.Builder
-	result.WriteString("Validation Results:\n\n")
+	response := models.ValidationResults{Items: []models.ValidationResult{}}
+	record := func(item models.ValidationResult) {
+		if item.Valid {
+			response.ValidCount++
+		} else {
+			response.ErrorCount++
+		}
+		response.TotalCount++
+		response.Items = append(response.Items, item)
+	}
 
 	// 将YAML拆分为多个文档
 	docs := strings.Split(yamlStr, "---")
-	validCount := 0
-	errorCount := 0
 
 	for i, doc := range docs {
 		doc = strings.TrimSpace(doc)
@@ -399,12 +552,11 @@ func (h *UtilityHandler) ValidateManifest(
 		// 解析YAML为非结构化对象
 		obj := &unstructured.Unstructured{}
 		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
-			h.Log.Error("Failed to parse YAML document",
+			log.Error("Failed to parse YAML document",
 				"document", i+1,
 				"error", err,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: YAML parsing failed - %v\n", i+1, err))
-			errorCount++
+			record(models.ValidationResult{Document: i + 1, Error: fmt.Sprintf("YAML parsing failed - %v", err)})
 			continue
 		}
 
@@ -413,79 +565,52 @@ func (h *UtilityHandler) ValidateManifest(
 		apiVersion := obj.GetAPIVersion()
 		name := obj.GetName()
 		namespace := obj.GetNamespace()
+		item := models.ValidationResult{
+			Document:  i + 1,
+			Kind:      kind,
+			Name:      name,
+			Namespace: namespace,
+		}
 
 		// 验证基本字段
 		if kind == "" || apiVersion == "" {
-			h.Log.Error("Document is missing kind or apiVersion",
+			log.Error("Document is missing kind or apiVersion",
 				"document", i+1,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: missing kind or apiVersion\n", i+1))
-			errorCount++
+			item.Error = "missing kind or apiVersion"
+			record(item)
 			continue
 		}
 
 		if name == "" {
-			h.Log.Error("Document is missing metadata.name",
+			log.Error("Document is missing metadata.name",
 				"document", i+1,
 				"kind", kind,
 				"apiVersion", apiVersion,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: missing metadata.name\n", i+1))
-			errorCount++
+			item.Error = "missing metadata.name"
+			record(item)
 			continue
 		}
 
 		// 检查API资源是否存在
-		gvr, err := h.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(apiVersion)
-		if err != nil {
-			h.Log.Error("Failed to get resource for group version",
-				"apiVersion", apiVersion,
-				"error", err,
-			)
-			result.WriteString(fmt.Sprintf("Error in document %d: apiVersion '%s' not found in the cluster\n", i+1, apiVersion))
-			errorCount++
-			continue
-		}
-
-		// 查找资源类型
-		resourceFound := false
-		for _, r := range gvr.APIResources {
-			if strings.EqualFold(r.Kind, kind) {
-				resourceFound = true
-				break
-			}
-		}
-
-		if !resourceFound {
-			h.Log.Error("Resource not found",
+		if _, _, err := utils.ResolveGVR(h.Client, apiVersion, kind); err != nil {
+			log.Error("Failed to resolve resource",
 				"kind", kind,
 				"apiVersion", apiVersion,
+				"error", err,
 			)
-			result.WriteString(fmt.Sprintf("Error in document %d: kind '%s' with apiVersion '%s' not found in the cluster\n", i+1, kind, apiVersion))
-			errorCount++
+			item.Error = err.Error()
+			record(item)
 			continue
 		}
 
 		// 验证通过，记录
-		if namespace != "" {
-			result.WriteString(fmt.Sprintf("Valid: %s/%s in namespace %s (document %d)\n", kind, name, namespace, i+1))
-		} else {
-			result.WriteString(fmt.Sprintf("Valid: %s/%s (cluster-scoped) (document %d)\n", kind, name, i+1))
-		}
-		validCount++
+		item.Valid = true
+		record(item)
 	}
 
-	// 添加摘要
-	result.WriteString(fmt.Sprintf("\nSummary: %d valid, %d invalid out of %d documents\n", validCount, errorCount, validCount+errorCount))
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, response), nil
 }
 
 // DiffManifest 比较资源清单与集群中的资源
@@ -493,24 +618,21 @@ func (h *UtilityHandler) DiffManifest(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	log := logger.FromContext(ctx)
 	arguments := request.GetArguments()
 	yamlStr, _ := arguments["yaml"].(string)
 
-	h.Log.Info("Diffing manifest")
+	log.Info("Diffing manifest")
 
 	if yamlStr == "" {
-		return nil, fmt.Errorf("yaml manifest is required")
+		return utils.NewErrorToolResult("yaml manifest is required"), nil
 	}
 
-	// 构建响应
-	var result strings.Builder
-	result.WriteString("Diff Results:\n\n")
-
 	// 解析YAML
 	obj := &unstructured.Unstructured{}
 	if err := yaml.Unmarshal([]byte(yamlStr), &obj.Object); err != nil {
-		h.Log.Error("Failed to parse YAML", "error", err)
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		log.Error("Failed to parse YAML", "error", err)
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to parse YAML: %v", err)), nil
 	}
 
 	// 获取资源信息
@@ -520,41 +642,26 @@ func (h *UtilityHandler) DiffManifest(
 	namespace := obj.GetNamespace()
 
 	if kind == "" || apiVersion == "" || name == "" {
-		return nil, fmt.Errorf("YAML must include kind, apiVersion, and metadata.name")
+		return utils.NewErrorToolResult("YAML must include kind, apiVersion, and metadata.name"), nil
 	}
 
-	// 获取集群中的现有资源
-	liveObj := &unstructured.Unstructured{}
-	liveObj.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   parseGroup(apiVersion),
-		Version: parseVersion(apiVersion),
-		Kind:    kind,
-	})
+	// 构建响应
+	response := models.DiffResult{
+		Kind:       kind,
+		Name:       name,
+		Namespace:  namespace,
+		ApiVersion: apiVersion,
+	}
 
-	// 确定资源的组、版本和资源类型
-	group, version := parseGroup(apiVersion), parseVersion(apiVersion)
-	gvr, err := h.Client.GetDiscoveryClient().ServerResourcesForGroupVersion(apiVersion)
+	// 通过RESTMapper确定资源的GVR和作用域
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
 	if err != nil {
-		h.Log.Error("Failed to get resource for group version",
+		log.Error("Failed to resolve resource",
+			"kind", kind,
 			"apiVersion", apiVersion,
 			"error", err,
 		)
-		return nil, fmt.Errorf("failed to get resource definition: %w", err)
-	}
-
-	// 查找资源名称
-	var resourceName string
-	var namespaced bool
-	for _, r := range gvr.APIResources {
-		if strings.EqualFold(r.Kind, kind) {
-			resourceName = r.Name
-			namespaced = r.Namespaced
-			break
-		}
-	}
-
-	if resourceName == "" {
-		return nil, fmt.Errorf("resource kind %s with apiVersion %s not found in the cluster", kind, apiVersion)
+		return utils.NewKubeErrorResult(err, "failed to get resource definition"), nil
 	}
 
 	// 使用动态客户端获取现有资源
@@ -564,85 +671,40 @@ func (h *UtilityHandler) DiffManifest(
 		if ns == "" {
 			ns = "default" // 使用默认命名空间
 		}
-		dynamicResource = h.Client.GetDynamicClient().Resource(schema.GroupVersionResource{
-			Group:    group,
-			Version:  version,
-			Resource: resourceName,
-		}).Namespace(ns)
+		dynamicResource = h.Client.GetDynamicClient().Resource(gvr).Namespace(ns)
 	} else {
-		dynamicResource = h.Client.GetDynamicClient().Resource(schema.GroupVersionResource{
-			Group:    group,
-			Version:  version,
-			Resource: resourceName,
-		})
+		dynamicResource = h.Client.GetDynamicClient().Resource(gvr)
 	}
 
 	// 获取现有资源
 	existingObj, err := dynamicResource.Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		h.Log.Error("Failed to get existing resource",
+		log.Error("Failed to get existing resource",
 			"kind", kind,
 			"name", name,
 			"namespace", namespace,
 			"error", err,
 		)
-		result.WriteString(fmt.Sprintf("Resource %s/%s does not exist in the cluster. This would be a new resource.\n", kind, name))
 		// 显示将要创建的资源概要
-		result.WriteString("\nNew resource to be created:\n")
-		result.WriteString(fmt.Sprintf("Kind:       %s\n", kind))
-		result.WriteString(fmt.Sprintf("API Version: %s\n", apiVersion))
-		result.WriteString(fmt.Sprintf("Name:       %s\n", name))
-		if namespace != "" {
-			result.WriteString(fmt.Sprintf("Namespace:  %s\n", namespace))
-		} else {
-			result.WriteString("Namespace:  <cluster-scoped>\n")
-		}
-
-		// 显示标签和注释
-		labels := obj.GetLabels()
-		if len(labels) > 0 {
-			result.WriteString("\nLabels:\n")
-			for k, v := range labels {
-				result.WriteString(fmt.Sprintf("  %s: %s\n", k, v))
-			}
-		}
-
-		annotations := obj.GetAnnotations()
-		if len(annotations) > 0 {
-			result.WriteString("\nAnnotations:\n")
-			for k, v := range annotations {
-				result.WriteString(fmt.Sprintf("  %s: %s\n", k, v))
-			}
-		}
-
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: result.String(),
-				},
-			},
-		}, nil
+		response.IsNewResurce = true
+		response.Labels = obj.GetLabels()
+		response.Annotations = obj.GetAnnotations()
+		return utils.RenderResult(request, response), nil
 	}
 
 	// 存在的资源，比较差异
-	result.WriteString(fmt.Sprintf("Comparing %s/%s in %s:\n\n", kind, name, namespace))
+	response.Exists = true
 
 	// 移除比较时不需要的字段（如状态，资源版本等）
 	cleanObject(obj)
 	cleanObject(existingObj)
 
 	// 比较字段差异
-	result.WriteString("Field differences:\n")
-	diffCount := 0
-
 	// 转成JSON便于比较
 	newJSON, _ := json.MarshalIndent(obj.Object, "", "  ")
 	existingJSON, _ := json.MarshalIndent(existingObj.Object, "", "  ")
 
-	if string(newJSON) == string(existingJSON) {
-		result.WriteString("  No differences found. Resources are identical.\n")
-	} else {
+	if string(newJSON) != string(existingJSON) {
 		// 比较特定的关键字段
 		fieldsToCompare := map[string]string{
 			"apiVersion": "API Version",
@@ -701,39 +763,44 @@ func (h *UtilityHandler) DiffManifest(
 
 			// 比较值是否不同
 			if !reflect.DeepEqual(newValue, existingValue) || newFound != existingFound {
-				diffCount++
-				if !newFound && existingFound {
-					result.WriteString(fmt.Sprintf("  - %s: would be removed (currently: %v)\n", displayName, existingValue))
-				} else if newFound && !existingFound {
-					result.WriteString(fmt.Sprintf("  + %s: would be added (%v)\n", displayName, newValue))
-				} else {
-					result.WriteString(fmt.Sprintf("  ~ %s: would change from %v to %v\n", displayName, existingValue, newValue))
+				detail := models.DiffDetail{Field: displayName}
+				switch {
+				case !newFound && existingFound:
+					detail.Action = "remove"
+					detail.OldValue = fmt.Sprintf("%v", existingValue)
+				case newFound && !existingFound:
+					detail.Action = "add"
+					detail.NewValue = fmt.Sprintf("%v", newValue)
+				default:
+					detail.Action = "change"
+					detail.OldValue = fmt.Sprintf("%v", existingValue)
+					detail.NewValue = fmt.Sprintf("%v", newValue)
 				}
+				response.DiffDetails = append(response.DiffDetails, detail)
 			}
 		}
+		sort.Slice(response.DiffDetails, func(i, j int) bool {
+			return response.DiffDetails[i].Field < response.DiffDetails[j].Field
+		})
+		response.DiffCount = len(response.DiffDetails)
 
-		if diffCount == 0 {
+		if response.DiffCount == 0 {
 			// 如果没有检测到具体字段差异，但JSON不同，则提供一般性差异信息
-			result.WriteString("  Differences detected, but may be in fields not specifically compared.\n")
-			result.WriteString("  Consider using kubectl diff or a similar tool for a detailed comparison.\n")
+			response.Note = "Differences detected, but may be in fields not specifically compared. Consider using kubectl diff or a similar tool for a detailed comparison."
 		}
 	}
 
-	// 总结
-	if diffCount > 0 {
-		result.WriteString(fmt.Sprintf("\nSummary: Found %d differences between manifest and live resource.\n", diffCount))
-	} else {
-		result.WriteString("\nSummary: No significant differences found.\n")
-	}
+	return utils.RenderResult(request, response), nil
+}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+// deniedMessage 提取错误结果中的文本
+func deniedMessage(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "permission denied"
 }
 
 // cleanObject 清理对象，移除不相关的比较字段
@@ -750,16 +817,34 @@ func cleanObject(obj *unstructured.Unstructured) {
 	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
 }
 
-// GetEvents 获取资源的事件
+// GET_EVENTS的后代对象与事件数量限制
+const (
+	defaultEventsMaxOwned  = 50
+	maxEventsMaxOwned      = 200
+	defaultEventsMaxEvents = 100
+	maxEventsMaxEvents     = 500
+)
+
+// GetEvents 获取资源的事件，includeOwned时沿ownerReferences合并后代对象的事件
 func (h *UtilityHandler) GetEvents(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	log := logger.FromContext(ctx)
 	arguments := request.GetArguments()
 	kind, _ := arguments["kind"].(string)
 	apiVersion, _ := arguments["apiVersion"].(string)
 	name, _ := arguments["name"].(string)
 	namespaceArg, _ := arguments["namespace"].(string)
+	includeOwned, _ := arguments["includeOwned"].(bool)
+	maxOwned := defaultEventsMaxOwned
+	if value, ok := arguments["maxOwned"].(float64); ok && value > 0 {
+		maxOwned = min(int(value), maxEventsMaxOwned)
+	}
+	maxEvents := defaultEventsMaxEvents
+	if value, ok := arguments["maxEvents"].(float64); ok && value > 0 {
+		maxEvents = min(int(value), maxEventsMaxEvents)
+	}
 
 	// 获取命名空间
 	namespace := namespaceArg
@@ -767,90 +852,135 @@ func (h *UtilityHandler) GetEvents(
 		namespace = "default"
 	}
 
-	h.Log.Info("Getting resource events",
+	log.Info("Getting resource events",
 		"kind", kind,
 		"apiVersion", apiVersion,
 		"name", name,
 		"namespace", namespace,
+		"includeOwned", includeOwned,
+		"maxOwned", maxOwned,
+		"maxEvents", maxEvents,
 	)
 
 	if kind == "" || apiVersion == "" || name == "" {
-		return nil, fmt.Errorf("missing required parameters: kind, apiVersion, and name")
+		return utils.NewErrorToolResult("missing required parameters: kind, apiVersion, and name"), nil
+	}
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
 	}
 
-	// 构建完整的资源名称
-	resourceName := fmt.Sprintf("%s/%s", strings.ToLower(kind), name)
-
-	// 创建响应构建器
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Events for %s/%s in namespace %s:\n\n", kind, name, namespace))
+	// 构建响应
+	response := models.EventsResult{Items: []models.EventInfo{}}
+	response.ResourceRef.Kind = kind
+	response.ResourceRef.Name = name
+	response.ResourceRef.Namespace = namespace
+
+	// objects 需要收集事件的对象，键为"Kind/name"，值表示是否为后代对象
+	objects := map[string]bool{kind + "/" + name: false}
+	if includeOwned {
+		owned, err := h.ownedObjects(ctx, apiVersion, kind, name, namespace, maxOwned, &response)
+		if err != nil {
+			log.Error("Failed to get resource", "kind", kind, "name", name, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+		}
+		for _, ref := range owned {
+			objects[ref] = true
+		}
+	}
 
 	// 获取所有事件
 	eventsList := &corev1.EventList{}
-	err := h.Client.List(ctx, eventsList, &ctrlclient.ListOptions{
+	err = h.Client.List(ctx, eventsList, &ctrlclient.ListOptions{
 		Namespace: namespace,
 	})
 
 	if err != nil {
-		h.Log.Error("Failed to list events", "error", err)
-		return nil, fmt.Errorf("failed to list events: %w", err)
+		log.Error("Failed to list events", "error", err)
+		return utils.NewKubeErrorResult(err, "failed to list events"), nil
 	}
 
 	// 过滤与指定资源相关的事件
 	var relatedEvents []corev1.Event
 	for _, event := range eventsList.Items {
-		if event.InvolvedObject.Kind == kind && event.InvolvedObject.Name == name {
+		if _, ok := objects[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name]; ok {
 			relatedEvents = append(relatedEvents, event)
 		}
 	}
 
-	// 按照时间排序
-	sort.Slice(relatedEvents, func(i, j int) bool {
-		return relatedEvents[i].LastTimestamp.After(relatedEvents[j].LastTimestamp.Time)
+	// 按照时间排序，最近的事件在前
+	sort.SliceStable(relatedEvents, func(i, j int) bool {
+		return utils.EventLastSeen(relatedEvents[i]).After(utils.EventLastSeen(relatedEvents[j]))
 	})
+	if len(relatedEvents) > maxEvents {
+		relatedEvents = relatedEvents[:maxEvents]
+		response.Truncated = true
+	}
 
-	// 如果没有找到事件
-	if len(relatedEvents) == 0 {
-		result.WriteString(fmt.Sprintf("No events found for %s '%s' in namespace '%s'\n", kind, name, namespace))
-		result.WriteString("\nPossible reasons:\n")
-		result.WriteString(" - The resource is new and hasn't generated any events yet\n")
-		result.WriteString(" - The resource is operating normally without issues\n")
-		result.WriteString(" - The resource does not exist in the specified namespace\n")
-		result.WriteString(" - Events older than the retention period have been cleaned up\n")
-	} else {
-		// 写入标题
-		result.WriteString(fmt.Sprintf("Found %d events:\n\n", len(relatedEvents)))
-		result.WriteString(fmt.Sprintf("%-25s %-10s %-15s %-20s %s\n", "LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE"))
-		result.WriteString(strings.Repeat("-", 100) + "\n")
-
-		// 写入事件
-		for _, event := range relatedEvents {
-			// 格式化时间
-			lastSeen := formatTimeAgo(event.LastTimestamp.Time)
-
-			// 截断过长的消息
-			message := event.Message
-			if len(message) > 1024 {
-				message = message[:1021] + "..."
-			}
+	for _, event := range relatedEvents {
+		// 截断过长的消息
+		message := utils.TruncateString(event.Message, utils.MaxMessageLength)
+		lastSeen := utils.EventLastSeen(event)
+
+		info := models.EventInfo{
+			LastSeen:  formatTimeAgo(lastSeen),
+			Timestamp: lastSeen.In(location),
+			FirstSeen: utils.EventFirstSeen(event).In(location),
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Object:    fmt.Sprintf("%s/%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name),
+			Owned:     objects[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name],
+			Count:     event.Count,
+			Message:   message,
+		}
+		if event.Series != nil {
+			info.Count = event.Series.Count
+		}
+		if message != event.Message {
+			info.FullMessage = event.Message
+		}
+		response.Items = append(response.Items, info)
+	}
+	response.Count = len(response.Items)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// ownedObjects 沿ownerReferences列出对象的后代（如Deployment→ReplicaSet→Pod、Job→Pod、StatefulSet→Pod），
+// 返回"Kind/name"列表，最多maxOwned个；遍历中的错误和截断记录在response中
+func (h *UtilityHandler) ownedObjects(
+	ctx context.Context,
+	apiVersion, kind, name, namespace string,
+	maxOwned int,
+	response *models.EventsResult,
+) ([]string, error) {
+	start, err := h.getOwnershipObject(ctx, apiVersion, kind, name, namespace)
+	if err != nil {
+		return nil, err
+	}
 
-			// 写入事件信息
-			result.WriteString(fmt.Sprintf("%-25s %-10s %-15s %-20s %s\n",
-				lastSeen,
-				event.Type,
-				event.Reason,
-				resourceName,
-				message,
-			))
-		}
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+	walker := &ownershipWalker{
+		h:          h,
+		maxDepth:   defaultOwnershipDepth,
+		maxNodes:   maxOwned + 1,
+		childKinds: make(map[schema.GroupKind][]schema.GroupVersionKind),
+		lists:      make(map[string][]unstructured.Unstructured),
+		startUID:   start.GetUID(),
+		result:     &models.OwnershipGraph{},
+	}
+	root := walker.descendants(ctx, start, 0)
+	response.Errors = append(response.Errors, walker.result.Errors...)
+	response.Truncated = response.Truncated || walker.result.Truncated
+
+	var owned []string
+	var walk func(node *models.OwnershipNode)
+	walk = func(node *models.OwnershipNode) {
+		for _, child := range node.Children {
+			owned = append(owned, child.Kind+"/"+child.Name)
+			response.OwnedObjects = append(response.OwnedObjects, strings.ToLower(child.Kind)+"/"+child.Name)
+			walk(child)
+		}
+	}
+	walk(root)
+	return owned, nil
 }
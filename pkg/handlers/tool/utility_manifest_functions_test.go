@@ -0,0 +1,226 @@
+package tool
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+const (
+	configMapManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: fast`
+	changedConfigMapManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: slow`
+	serviceManifest = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web`
+	unnamedManifest = `apiVersion: v1
+kind: ConfigMap
+data:
+  mode: fast`
+	unknownKindManifest = `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gadget`
+)
+
+func existingSettings() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: testutil.DefaultNamespace},
+		Data:       map[string]string{"mode": "fast"},
+	}
+}
+
+func callUtility(t *testing.T, client *testutil.FakeClient, tool string, arguments map[string]any, v any) {
+	t.Helper()
+	handler := NewUtilityHandler(client).(*UtilityHandler)
+	result, err := handler.Handle(context.Background(), testutil.NewToolRequest(tool, arguments))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := testutil.DecodeResult(result, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  bool
+		yaml      string
+		arguments map[string]any
+		actions   []string
+		// stored 应用后集群中settings的data.mode，为空表示不存在
+		stored     string
+		rolledBack int
+	}{
+		{name: "create", yaml: configMapManifest, actions: []string{applyActionCreated}, stored: "fast"},
+		{name: "reapply unchanged", existing: true, yaml: configMapManifest, actions: []string{applyActionUnchanged}, stored: "fast"},
+		{name: "configure", existing: true, yaml: changedConfigMapManifest, actions: []string{applyActionConfigured}, stored: "slow"},
+		{name: "dry run create", yaml: configMapManifest, arguments: map[string]any{"dryRun": true}, actions: []string{applyActionCreated}},
+		{name: "dry run configure", existing: true, yaml: changedConfigMapManifest, arguments: map[string]any{"dryRun": true}, actions: []string{applyActionConfigured}, stored: "fast"},
+		{name: "dry run unchanged", existing: true, yaml: configMapManifest, arguments: map[string]any{"dryRun": true}, actions: []string{applyActionUnchanged}, stored: "fast"},
+		{name: "multiple documents", yaml: configMapManifest + "\n---\n" + serviceManifest, actions: []string{applyActionCreated, applyActionCreated}, stored: "fast"},
+		{name: "missing name", yaml: unnamedManifest, actions: []string{applyActionFailed}},
+		{name: "unknown kind", yaml: configMapManifest + "\n---\n" + unknownKindManifest, actions: []string{applyActionCreated, applyActionFailed}, stored: "fast"},
+		{
+			name:       "rollback on error",
+			yaml:       configMapManifest + "\n---\n" + unknownKindManifest + "\n---\n" + serviceManifest,
+			arguments:  map[string]any{"rollbackOnError": true},
+			actions:    []string{applyActionCreated, applyActionFailed, applyActionSkipped},
+			rolledBack: 1,
+		},
+		{
+			name:      "atomic aborts",
+			yaml:      configMapManifest + "\n---\n" + unknownKindManifest,
+			arguments: map[string]any{"atomic": true},
+			actions:   []string{applyActionCreated, applyActionFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := testutil.NewFakeClient()
+			if tt.existing {
+				client = testutil.NewFakeClient(existingSettings())
+			}
+			arguments := map[string]any{"yaml": tt.yaml}
+			for key, value := range tt.arguments {
+				arguments[key] = value
+			}
+
+			var response models.ApplyResults
+			callUtility(t, client, APPLY_MANIFEST, arguments, &response)
+
+			actions := make([]string, 0, len(response.Items))
+			for _, item := range response.Items {
+				actions = append(actions, item.Action)
+			}
+			if !slices.Equal(actions, tt.actions) {
+				t.Fatalf("actions = %v, want %v", actions, tt.actions)
+			}
+			if response.RolledBack != tt.rolledBack {
+				t.Errorf("rolled back %d documents, want %d", response.RolledBack, tt.rolledBack)
+			}
+
+			stored := ""
+			configMap, err := client.FakeDynamicClient().Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).
+				Namespace(testutil.DefaultNamespace).Get(context.Background(), "settings", metav1.GetOptions{})
+			if err == nil {
+				stored = configMap.Object["data"].(map[string]any)["mode"].(string)
+			}
+			if stored != tt.stored {
+				t.Fatalf("stored mode = %q, want %q", stored, tt.stored)
+			}
+		})
+	}
+}
+
+func TestApplyManifestRequiresYAML(t *testing.T) {
+	handler := NewUtilityHandler(testutil.NewFakeClient()).(*UtilityHandler)
+	result, err := handler.Handle(context.Background(), testutil.NewToolRequest(APPLY_MANIFEST, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError {
+		t.Fatalf("empty manifest was accepted: %s", testutil.ResultText(result))
+	}
+}
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		name   string
+		yaml   string
+		valid  int
+		errors []string
+	}{
+		{name: "valid documents", yaml: configMapManifest + "\n---\n" + serviceManifest, valid: 2},
+		{name: "missing name", yaml: unnamedManifest, errors: []string{"missing metadata.name"}},
+		{name: "missing kind", yaml: "apiVersion: v1\nmetadata:\n  name: x", errors: []string{"missing kind or apiVersion"}},
+		{name: "unknown kind", yaml: configMapManifest + "\n---\n" + unknownKindManifest, valid: 1, errors: []string{"Widget"}},
+		{name: "invalid yaml", yaml: "kind: [", errors: []string{"YAML parsing failed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response models.ValidationResults
+			callUtility(t, testutil.NewFakeClient(), VALIDATE_MANIFEST, map[string]any{"yaml": tt.yaml}, &response)
+
+			if response.ValidCount != tt.valid || response.ErrorCount != len(tt.errors) || response.TotalCount != tt.valid+len(tt.errors) {
+				t.Fatalf("valid %d, errors %d, total %d; want %d valid and %d errors",
+					response.ValidCount, response.ErrorCount, response.TotalCount, tt.valid, len(tt.errors))
+			}
+			var errors []string
+			for _, item := range response.Items {
+				if !item.Valid {
+					errors = append(errors, item.Error)
+				}
+			}
+			for i, want := range tt.errors {
+				if !strings.Contains(errors[i], want) {
+					t.Errorf("error %d = %q, want it to mention %q", i, errors[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		exists  bool
+		details []models.DiffDetail
+	}{
+		{name: "new resource", yaml: serviceManifest},
+		{name: "identical", yaml: configMapManifest, exists: true},
+		{
+			name:    "added spec field",
+			yaml:    configMapManifest + "\nspec:\n  paused: true",
+			exists:  true,
+			details: []models.DiffDetail{{Field: "Spec.paused", NewValue: "true", Action: "add"}},
+		},
+		{
+			name:    "changed labels",
+			yaml:    strings.Replace(configMapManifest, "namespace: default", "namespace: default\n  labels:\n    tier: cache", 1),
+			exists:  true,
+			details: []models.DiffDetail{{Field: "Metadata.labels", NewValue: "map[tier:cache]", Action: "add"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response models.DiffResult
+			callUtility(t, testutil.NewFakeClient(existingSettings()), DIFF_MANIFEST, map[string]any{"yaml": tt.yaml}, &response)
+
+			if response.Exists != tt.exists || response.IsNewResurce == tt.exists {
+				t.Fatalf("exists = %v, new = %v; want exists = %v", response.Exists, response.IsNewResurce, tt.exists)
+			}
+			if !slices.Equal(response.DiffDetails, tt.details) || response.DiffCount != len(tt.details) {
+				t.Fatalf("diff = %+v, want %+v", response.DiffDetails, tt.details)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 批量修改标签和注解的对象数量限制
+const (
+	// defaultMetadataEditObjects 按标签选择器批量修改时默认允许的最大对象数量
+	defaultMetadataEditObjects = 20
+	// maxMetadataEditObjects maxObjects参数允许的最大值
+	maxMetadataEditObjects = 100
+)
+
+// LabelResource 为一个或一组资源添加、修改或删除标签
+func (h *UtilityHandler) LabelResource(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return h.editMetadata(ctx, request, "labels")
+}
+
+// AnnotateResource 为一个或一组资源添加、修改或删除注解
+func (h *UtilityHandler) AnnotateResource(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return h.editMetadata(ctx, request, "annotations")
+}
+
+// editMetadata 通过JSON合并补丁修改资源的metadata.labels或metadata.annotations，不触及其他字段
+// 指定name时修改单个对象，否则修改labelSelector匹配的所有对象；匹配数量超过maxObjects时拒绝执行，试运行时只列出前maxObjects个
+func (h *UtilityHandler) editMetadata(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	field string,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	removeStr, _ := arguments["remove"].(string)
+	dryRun, _ := arguments["dryRun"].(bool)
+	maxObjects := defaultMetadataEditObjects
+	if value, ok := arguments["maxObjects"].(float64); ok && value > 0 {
+		maxObjects = min(int(value), maxMetadataEditObjects)
+	}
+
+	h.Log.Info("Editing resource metadata",
+		"field", field,
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"name", name,
+		"namespace", namespace,
+		"labelSelector", labelSelector,
+		"dryRun", dryRun,
+	)
+
+	if kind == "" || apiVersion == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind and apiVersion"), nil
+	}
+	if name == "" && labelSelector == "" {
+		return utils.NewErrorToolResult("one of name or labelSelector is required"), nil
+	}
+	set, err := utils.StringMapArgument(arguments, field)
+	if err != nil {
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	remove := utils.SplitCommaList(removeStr)
+	if len(set) == 0 && len(remove) == 0 {
+		return utils.NewErrorToolResult(fmt.Sprintf("at least one of %s or remove is required", field)), nil
+	}
+
+	patch, err := utils.MetadataMergePatch(field, set, remove)
+	if err != nil {
+		return utils.NewErrorToolResult(fmt.Sprintf("failed to build %s patch: %v", field, err)), nil
+	}
+
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+
+	var resource dynamic.ResourceInterface
+	if namespaced {
+		if namespace == "" {
+			namespace = "default"
+		}
+		resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace)
+	} else {
+		namespace = ""
+		resource = h.Client.GetDynamicClient().Resource(gvr)
+	}
+
+	response := models.MetadataEditResult{
+		Field:   field,
+		Set:     set,
+		Removed: remove,
+		DryRun:  dryRun,
+	}
+
+	var objs []unstructured.Unstructured
+	if name != "" {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			h.Log.Error("Failed to get resource", "kind", kind, "name", name, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+		}
+		objs = append(objs, *obj)
+	} else {
+		response.LabelSelector = labelSelector
+		list, err := resource.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			h.Log.Error("Failed to list resources", "kind", kind, "labelSelector", labelSelector, "error", err)
+			return utils.NewKubeErrorResult(err, "failed to list resources"), nil
+		}
+		objs = list.Items
+		sort.Slice(objs, func(i, j int) bool {
+			return objs[i].GetName() < objs[j].GetName()
+		})
+	}
+	response.Matched = len(objs)
+
+	if len(objs) > maxObjects {
+		if !dryRun {
+			return utils.NewToolErrorResult(models.ToolError{
+				Code:    utils.ErrorCodeRefused,
+				Message: fmt.Sprintf("labelSelector %q matches %d objects, more than the limit of %d", labelSelector, len(objs), maxObjects),
+				Hint:    fmt.Sprintf("Narrow the labelSelector, or raise maxObjects (at most %d); run with dryRun=true to preview the matched objects.", maxMetadataEditObjects),
+			}), nil
+		}
+		objs = objs[:maxObjects]
+		response.Truncated = true
+	}
+
+	patchOptions := metav1.PatchOptions{}
+	if dryRun {
+		patchOptions.DryRun = []string{metav1.DryRunAll}
+	}
+
+	response.Items = make([]models.MetadataEditItem, 0, len(objs))
+	for i := range objs {
+		obj := &objs[i]
+		item := models.MetadataEditItem{
+			Kind:      obj.GetKind(),
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Before:    metadataField(obj, field),
+		}
+		if item.Kind == "" {
+			item.Kind = kind
+		}
+
+		if denied := h.PreflightCheck(ctx, "patch", gvr, namespace, obj.GetName()); denied != nil {
+			if len(objs) == 1 {
+				return denied, nil
+			}
+			item.Error = fmt.Sprintf("missing permission: patch %s %s", gvr.Resource, obj.GetName())
+			response.Items = append(response.Items, item)
+			continue
+		}
+
+		patched, err := resource.Patch(ctx, obj.GetName(), types.MergePatchType, patch, patchOptions)
+		if err != nil {
+			h.Log.Error("Failed to patch resource metadata", "kind", kind, "name", obj.GetName(), "field", field, "error", err)
+			if len(objs) == 1 {
+				return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to update %s of %s %s", field, kind, obj.GetName())), nil
+			}
+			item.Error = err.Error()
+			response.Items = append(response.Items, item)
+			continue
+		}
+		item.After = metadataField(patched, field)
+		item.Changed = !maps.Equal(item.Before, item.After)
+		response.Items = append(response.Items, item)
+	}
+	h.Client.Cache().InvalidateKind(kind)
+
+	return utils.RenderResult(request, response), nil
+}
+
+// metadataField 返回对象的标签或注解，为空时返回空映射以便在结果中区分"无"与"未获取"
+func metadataField(obj *unstructured.Unstructured, field string) map[string]string {
+	var values map[string]string
+	if field == "labels" {
+		values = obj.GetLabels()
+	} else {
+		values = obj.GetAnnotations()
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	return values
+}
@@ -0,0 +1,135 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 按近似名称查找资源时返回的候选数量
+const (
+	defaultNameSearchLimit = 10
+	maxNameSearchLimit     = 50
+)
+
+// FindResourceByName 按近似名称查找资源，返回按相似度排序的候选及其创建时间和健康判定
+func (h *UtilityHandler) FindResourceByName(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	name, _ := arguments["name"].(string)
+	limit := defaultNameSearchLimit
+	if value, ok := arguments["limit"].(float64); ok && value > 0 {
+		limit = min(int(value), maxNameSearchLimit)
+	}
+
+	h.Log.Info("Finding resource by name",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"namespace", namespace,
+		"name", name,
+		"limit", limit,
+	)
+
+	if kind == "" {
+		return utils.NewErrorToolResult("missing required parameter: kind"), nil
+	}
+	if name == "" {
+		return utils.NewErrorToolResult("missing required parameter: name"), nil
+	}
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		return utils.NewTimeZoneErrorResult(err), nil
+	}
+
+	gvr, namespaced, kind, apiVersion, err := h.resolveConditionKind(kind, apiVersion)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+	var resource dynamic.ResourceInterface
+	if namespaced {
+		// 未指定命名空间时在所有命名空间中查找
+		resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace)
+	} else {
+		namespace = ""
+		resource = h.Client.GetDynamicClient().Resource(gvr)
+	}
+
+	list, err := resource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		h.Log.Error("Failed to list resources", "kind", kind, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list %s", kind)), nil
+	}
+
+	response := models.NameSearchResult{
+		Kind:       kind,
+		APIVersion: apiVersion,
+		Namespace:  namespace,
+		Query:      name,
+		Scanned:    len(list.Items),
+		Candidates: []models.NameCandidate{},
+	}
+	now := time.Now()
+	for i := range list.Items {
+		obj := &list.Items[i]
+		match, ok := utils.MatchName(name, obj.GetName())
+		if !ok {
+			continue
+		}
+		conditions := summarizeConditions(obj, "", now)
+		created := obj.GetCreationTimestamp().Time
+		response.Candidates = append(response.Candidates, models.NameCandidate{
+			Name:              obj.GetName(),
+			Namespace:         obj.GetNamespace(),
+			Score:             match.Score,
+			MatchedBy:         match.Reason,
+			Distance:          match.Distance,
+			CreationTimestamp: created.In(location),
+			Age:               utils.FormatTimeAgoEN(created),
+			Verdict:           conditions.Verdict,
+			Summary:           conditions.Summary,
+		})
+	}
+
+	// 相似度相同时较新的资源排在前面，通常是刚创建、名称最可能被引用的那个
+	sort.SliceStable(response.Candidates, func(i, j int) bool {
+		a, b := response.Candidates[i], response.Candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if !a.CreationTimestamp.Equal(b.CreationTimestamp) {
+			return a.CreationTimestamp.After(b.CreationTimestamp)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if len(response.Candidates) > limit {
+		response.Candidates = response.Candidates[:limit]
+		response.Truncated = true
+	}
+
+	h.Log.Info("Resource name search completed",
+		"kind", kind,
+		"name", name,
+		"scanned", response.Scanned,
+		"candidates", len(response.Candidates),
+	)
+	return utils.RenderResult(request, response), nil
+}
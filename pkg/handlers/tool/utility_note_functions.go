@@ -0,0 +1,101 @@
+package tool
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// defaultNoteSession 无法获取MCP会话时使用的笔记会话
+const defaultNoteSession = "default"
+
+// SaveNote 在当前会话中保存或覆盖一条笔记
+func (h *UtilityHandler) SaveNote(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	key, _ := arguments["key"].(string)
+	text, _ := arguments["text"].(string)
+	var ttl time.Duration
+	if value, ok := arguments["ttlSeconds"].(float64); ok && value > 0 {
+		ttl = time.Duration(value) * time.Second
+	}
+	session := noteSession(ctx)
+
+	h.Log.Info("Saving note", "session", session, "key", key, "bytes", len(text), "ttl", ttl)
+
+	store := utils.Notes()
+	note, replaced, evicted, err := store.Save(session, key, text, ttl)
+	if err != nil {
+		h.Log.Error("Failed to save note", "key", key, "error", err)
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	return utils.RenderResult(request, models.NoteSaveResult{
+		Note:     note,
+		Replaced: replaced,
+		Evicted:  evicted,
+		Count:    len(store.List(session)),
+		Limit:    store.Limit(),
+	}), nil
+}
+
+// GetNotes 返回当前会话中未过期的笔记，指定key时只返回该笔记
+func (h *UtilityHandler) GetNotes(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	key, _ := arguments["key"].(string)
+	session := noteSession(ctx)
+
+	h.Log.Info("Getting notes", "session", session, "key", key)
+
+	store := utils.Notes()
+	notes := store.List(session)
+	result := models.NoteList{Notes: notes, Count: len(notes), Limit: store.Limit()}
+	if key != "" {
+		result.Notes = []models.SessionNote{}
+		for _, note := range notes {
+			if note.Key == key {
+				result.Notes = append(result.Notes, note)
+			}
+		}
+	}
+	return utils.RenderResult(request, result), nil
+}
+
+// DeleteNote 删除当前会话中的一条笔记
+func (h *UtilityHandler) DeleteNote(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	key, _ := arguments["key"].(string)
+	session := noteSession(ctx)
+
+	h.Log.Info("Deleting note", "session", session, "key", key)
+
+	if key == "" {
+		return utils.NewErrorToolResult("missing required parameter: key"), nil
+	}
+	deleted, err := utils.Notes().Delete(session, key)
+	if err != nil {
+		h.Log.Error("Failed to delete note", "key", key, "error", err)
+		return utils.NewErrorToolResult(err.Error()), nil
+	}
+	return utils.RenderResult(request, models.NoteDeleteResult{Key: key, Deleted: deleted}), nil
+}
+
+// noteSession 返回当前MCP会话的ID，作为笔记的隔离范围
+func noteSession(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return session.SessionID()
+	}
+	return defaultNoteSession
+}
@@ -0,0 +1,356 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认最长等待时间（秒）
+	defaultNotifyWaitSeconds = 60
+	// 最长等待时间上限（秒）
+	maxNotifyWaitSeconds = 300
+	// NOTIFY_ON_EVENT监听的对象
+	notifySourceEvents = "events"
+	notifySourcePods   = "pods"
+)
+
+// eventMatcher 编译后的匹配条件
+type eventMatcher struct {
+	involvedKind string
+	involvedName string
+	reason       *regexp.Regexp
+	eventType    string
+}
+
+// NotifyOnEvent 在限定时间内等待匹配条件的事件或Pod状态出现，出现时立即返回，超时返回未匹配的结果
+func (h *UtilityHandler) NotifyOnEvent(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	source, _ := arguments["source"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	involvedKind, _ := arguments["involvedKind"].(string)
+	involvedName, _ := arguments["involvedName"].(string)
+	reason, _ := arguments["reason"].(string)
+	eventType, _ := arguments["type"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	includeExisting, _ := arguments["includeExisting"].(bool)
+	waitSeconds, _ := arguments["maxWaitSeconds"].(float64)
+
+	if source == "" {
+		source = notifySourceEvents
+	}
+	if waitSeconds <= 0 {
+		waitSeconds = defaultNotifyWaitSeconds
+	}
+	if waitSeconds > maxNotifyWaitSeconds {
+		waitSeconds = maxNotifyWaitSeconds
+	}
+
+	h.Log.Info("Waiting for event",
+		"source", source,
+		"namespace", namespace,
+		"involvedKind", involvedKind,
+		"involvedName", involvedName,
+		"reason", reason,
+		"type", eventType,
+		"labelSelector", labelSelector,
+		"waitSeconds", waitSeconds,
+	)
+
+	var errs []string
+	switch source {
+	case notifySourceEvents:
+		if labelSelector != "" {
+			errs = append(errs, "labelSelector is only supported with source=pods")
+		}
+		if eventType != "" && eventType != corev1.EventTypeNormal && eventType != corev1.EventTypeWarning {
+			errs = append(errs, fmt.Sprintf("invalid type %q, must be Normal or Warning", eventType))
+		}
+	case notifySourcePods:
+		if involvedKind != "" && involvedKind != "Pod" {
+			errs = append(errs, "involvedKind must be empty or Pod with source=pods")
+		}
+		if eventType != "" {
+			errs = append(errs, "type is only supported with source=events")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("invalid source %q, must be events or pods", source))
+	}
+	matcher := eventMatcher{involvedKind: involvedKind, involvedName: involvedName, eventType: eventType}
+	if reason != "" {
+		compiled, err := regexp.Compile(reason)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid reason regex %q: %v", reason, err))
+		}
+		matcher.reason = compiled
+	}
+	if source == notifySourcePods && matcher.reason == nil {
+		errs = append(errs, "reason is required with source=pods, e.g. CrashLoopBackOff or OOMKilled|Error")
+	}
+	location, err := utils.ParseTimeZone(request)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: "invalid NOTIFY_ON_EVENT parameters",
+			Details: errs,
+		}), nil
+	}
+
+	result := models.EventNotification{
+		Source:    source,
+		Namespace: namespace,
+		Matcher: models.EventMatcher{
+			InvolvedKind:  involvedKind,
+			InvolvedName:  involvedName,
+			Reason:        reason,
+			Type:          eventType,
+			LabelSelector: labelSelector,
+		},
+		StartedAt: time.Now(),
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(waitSeconds)*time.Second)
+	defer cancel()
+
+	if source == notifySourcePods {
+		result.Pod, err = h.waitForPod(waitCtx, namespace, labelSelector, matcher, includeExisting)
+	} else {
+		result.Event, err = h.waitForEvent(waitCtx, namespace, matcher, includeExisting)
+	}
+
+	result.EndedAt = time.Now()
+	result.Waited = result.EndedAt.Sub(result.StartedAt).Round(time.Millisecond).String()
+	result.StartedAt, result.EndedAt = result.StartedAt.In(location), result.EndedAt.In(location)
+	if result.Event != nil {
+		result.Event.FirstSeen, result.Event.LastSeen = result.Event.FirstSeen.In(location), result.Event.LastSeen.In(location)
+	}
+	result.Matched = result.Event != nil || result.Pod != nil
+	if err != nil && !result.Matched {
+		if waitCtx.Err() == nil || ctx.Err() != nil {
+			h.Log.Error("Failed to wait for event", "source", source, "error", err)
+			return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to watch %s", source)), nil
+		}
+		// 等待时间用尽，属于正常结果
+		result.TimedOut = true
+		result.Hint = "no matching occurrence within maxWaitSeconds; call again to keep waiting or check the current state with GET_EVENTS"
+	}
+
+	h.Log.Info("Wait for event finished", "source", source, "matched", result.Matched, "waited", result.Waited)
+	if result.Matched {
+		utils.ProgressFromContext(ctx).Done(ctx, fmt.Sprintf("matching %s found after %s", source, result.Waited))
+	} else {
+		utils.ProgressFromContext(ctx).Done(ctx, fmt.Sprintf("no matching %s after %s", source, result.Waited))
+	}
+	return utils.RenderResult(request, result), nil
+}
+
+// waitForEvent 监听事件直到出现匹配的事件，计数增加的已有事件视为新的发生
+func (h *UtilityHandler) waitForEvent(
+	ctx context.Context,
+	namespace string,
+	matcher eventMatcher,
+	includeExisting bool,
+) (*models.NotifiedEvent, error) {
+	// 类型、对象类型和名称在服务端过滤，原因的正则在本地匹配
+	var selectors []fields.Selector
+	if matcher.involvedKind != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.kind", matcher.involvedKind))
+	}
+	if matcher.involvedName != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("involvedObject.name", matcher.involvedName))
+	}
+	if matcher.eventType != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("type", matcher.eventType))
+	}
+	listWatch := cache.NewFilteredListWatchFromClient(h.Client.ClientSet().CoreV1().RESTClient(), "events", namespace,
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.AndSelectors(selectors...).String()
+		})
+
+	var matched *models.NotifiedEvent
+	match := func(obj runtime.Object) bool {
+		event, ok := obj.(*corev1.Event)
+		if !ok || (matcher.reason != nil && !matcher.reason.MatchString(event.Reason)) {
+			return false
+		}
+		matched = notifiedEvent(event)
+		return true
+	}
+	_, err := untilNewOccurrence(ctx, listWatch, &corev1.Event{}, includeExisting, match)
+	return matched, err
+}
+
+// waitForPod 监听Pod直到某个Pod的状态原因匹配，例如容器进入CrashLoopBackOff或被OOMKilled
+func (h *UtilityHandler) waitForPod(
+	ctx context.Context,
+	namespace string,
+	labelSelector string,
+	matcher eventMatcher,
+	includeExisting bool,
+) (*models.NotifiedPod, error) {
+	listWatch := cache.NewFilteredListWatchFromClient(h.Client.ClientSet().CoreV1().RESTClient(), "pods", namespace,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+			if matcher.involvedName != "" {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", matcher.involvedName).String()
+			}
+		})
+
+	var matched *models.NotifiedPod
+	match := func(obj runtime.Object) bool {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		matched = matchPodReason(pod, matcher.reason)
+		return matched != nil
+	}
+	_, err := untilNewOccurrence(ctx, listWatch, &corev1.Pod{}, includeExisting, match)
+	return matched, err
+}
+
+// untilNewOccurrence 列出并监听对象直到match返回true
+// includeExisting为false时开始监听前已存在的对象版本不参与匹配，只匹配之后新增或变化的对象
+func untilNewOccurrence(
+	ctx context.Context,
+	listWatch cache.ListerWatcher,
+	objType runtime.Object,
+	includeExisting bool,
+	match func(obj runtime.Object) bool,
+) (*watch.Event, error) {
+	existing := make(map[string]string)
+	matchedExisting := false
+	precondition := func(store cache.Store) (bool, error) {
+		for _, item := range store.List() {
+			obj, ok := item.(runtime.Object)
+			if !ok {
+				continue
+			}
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return false, err
+			}
+			if includeExisting && match(obj) {
+				matchedExisting = true
+				return true, nil
+			}
+			existing[accessor.GetNamespace()+"/"+accessor.GetName()] = accessor.GetResourceVersion()
+		}
+		return false, nil
+	}
+	// 客户端请求了进度时汇报已检查的变化数量
+	progress := utils.ProgressFromContext(ctx)
+	checked := 0
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type != watch.Added && event.Type != watch.Modified {
+			return false, nil
+		}
+		checked++
+		progress.Report(ctx, fmt.Sprintf("%d changes checked, no match yet", checked))
+		accessor, err := meta.Accessor(event.Object)
+		if err != nil {
+			return false, nil
+		}
+		if version, ok := existing[accessor.GetNamespace()+"/"+accessor.GetName()]; ok && version == accessor.GetResourceVersion() {
+			return false, nil
+		}
+		return match(event.Object), nil
+	}
+
+	event, err := watchtools.UntilWithSync(ctx, listWatch, objType, precondition, condition)
+	if matchedExisting {
+		return nil, nil
+	}
+	return event, err
+}
+
+// matchPodReason 返回Pod中第一个与正则匹配的状态原因：Pod原因、阶段、容器的等待或终止原因
+func matchPodReason(pod *corev1.Pod, reason *regexp.Regexp) *models.NotifiedPod {
+	notified := &models.NotifiedPod{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Phase:     string(pod.Status.Phase),
+		Node:      pod.Spec.NodeName,
+	}
+	if pod.Status.Reason != "" && reason.MatchString(pod.Status.Reason) {
+		notified.Reason = pod.Status.Reason
+		notified.Message = pod.Status.Message
+		return notified
+	}
+	if reason.MatchString(string(pod.Status.Phase)) {
+		notified.Reason = string(pod.Status.Phase)
+		notified.Message = pod.Status.Message
+		return notified
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		notified.Container = status.Name
+		notified.RestartCount = status.RestartCount
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" && reason.MatchString(waiting.Reason) {
+			notified.Reason = waiting.Reason
+			notified.Message = waiting.Message
+			return notified
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.Reason != "" && reason.MatchString(terminated.Reason) {
+			notified.Reason = terminated.Reason
+			notified.Message = terminated.Message
+			return notified
+		}
+		// 重启中的容器当前处于等待状态，上次终止原因（例如OOMKilled）记录在lastState中
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason != "" && reason.MatchString(terminated.Reason) {
+			notified.Reason = terminated.Reason
+			notified.Message = terminated.Message
+			return notified
+		}
+	}
+	return nil
+}
+
+// notifiedEvent 将事件转换为结果中的事件信息
+func notifiedEvent(event *corev1.Event) *models.NotifiedEvent {
+	notified := &models.NotifiedEvent{
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Object:    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+		Namespace: event.Namespace,
+		Message:   strings.TrimSpace(event.Message),
+		Count:     event.Count,
+		Source:    event.Source.Component,
+		FirstSeen: utils.EventFirstSeen(*event),
+		LastSeen:  utils.EventLastSeen(*event),
+	}
+	if notified.Source == "" {
+		notified.Source = event.ReportingController
+	}
+	// events.k8s.io/v1写入的事件没有count，使用series
+	if event.Series != nil && event.LastTimestamp.IsZero() {
+		notified.Count = event.Series.Count
+	}
+	return notified
+}
@@ -0,0 +1,693 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 孤立资源检查项
+const (
+	orphanCheckConfigMaps  = "configmaps"
+	orphanCheckSecrets     = "secrets"
+	orphanCheckPVCs        = "pvcs"
+	orphanCheckServices    = "services"
+	orphanCheckJobs        = "jobs"
+	orphanCheckReplicaSets = "replicasets"
+)
+
+// orphanChecks 支持的检查项，按执行顺序排列
+var orphanChecks = []string{
+	orphanCheckConfigMaps,
+	orphanCheckSecrets,
+	orphanCheckPVCs,
+	orphanCheckServices,
+	orphanCheckJobs,
+	orphanCheckReplicaSets,
+}
+
+const (
+	defaultOrphanMinAgeHours   = 24
+	defaultOrphanJobMinAgeDays = 7
+)
+
+// orphanSystemNamespaces 未指定命名空间时跳过的系统命名空间
+var orphanSystemNamespaces = map[string]bool{
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// orphanSkippedSecretTypes 由集群或Helm管理、不通过Pod引用的Secret类型
+var orphanSkippedSecretTypes = map[corev1.SecretType]bool{
+	corev1.SecretTypeServiceAccountToken: true,
+	corev1.SecretTypeBootstrapToken:      true,
+	"helm.sh/release.v1":                 true,
+}
+
+// orphanDeletePrefixes 各类型所属通用资源工具的前缀
+var orphanDeletePrefixes = map[string]string{
+	"ConfigMap":             "CORE",
+	"Secret":                "CORE",
+	"PersistentVolumeClaim": "CORE",
+	"Service":               "CORE",
+	"Job":                   "BATCH",
+	"ReplicaSet":            "APPS",
+}
+
+const (
+	orphanLeaderAnnotation       = "control-plane.alpha.kubernetes.io/leader"
+	orphanCertificateAnnotation  = "cert-manager.io/certificate-name"
+	orphanDeploymentRevisionAnno = "deployment.kubernetes.io/revision"
+	orphanRootCAConfigMap        = "kube-root-ca.crt"
+)
+
+// orphanNamespaceRefs 一个命名空间中Pod与工作负载模板引用的资源
+type orphanNamespaceRefs struct {
+	podSpecRefs
+	pods []corev1.Pod
+	// templateLabels 各工作负载Pod模板的标签
+	templateLabels []map[string]string
+	// deployments 按名称索引的Deployment
+	deployments  map[string]*appsv1.Deployment
+	replicaSets  []appsv1.ReplicaSet
+	statefulSets []appsv1.StatefulSet
+	jobs         []batchv1.Job
+}
+
+// orphanScan 一次孤立资源扫描的状态
+type orphanScan struct {
+	h          *UtilityHandler
+	checks     map[string]bool
+	minAge     time.Duration
+	jobMinAge  time.Duration
+	emitDelete bool
+	now        time.Time
+	report     *models.OrphanReport
+	// pvs 按名称索引的PersistentVolume，未能列出时为nil
+	pvs map[string]*corev1.PersistentVolume
+	// volumeSecrets PersistentVolume引用的Secret，键为namespace/name
+	volumeSecrets map[string]bool
+	reclaimable   resource.Quantity
+}
+
+// FindOrphanedResources 查找命名空间中未被引用的资源，只读取不修改
+func (h *UtilityHandler) FindOrphanedResources(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespacesStr, _ := arguments["namespaces"].(string)
+	checksStr, _ := arguments["checks"].(string)
+	emitDelete, _ := arguments["emitDeleteCommands"].(bool)
+	minAgeHours := defaultOrphanMinAgeHours
+	if value, ok := arguments["minAgeHours"].(float64); ok && value >= 0 {
+		minAgeHours = int(value)
+	}
+	jobMinAgeDays := defaultOrphanJobMinAgeDays
+	if value, ok := arguments["jobMinAgeDays"].(float64); ok && value >= 0 {
+		jobMinAgeDays = int(value)
+	}
+
+	h.Log.Info("Finding orphaned resources",
+		"namespaces", namespacesStr,
+		"checks", checksStr,
+		"minAgeHours", minAgeHours,
+		"jobMinAgeDays", jobMinAgeDays,
+		"emitDeleteCommands", emitDelete,
+	)
+
+	checks := make(map[string]bool)
+	for _, check := range utils.SplitCommaList(strings.ToLower(checksStr)) {
+		if !slices.Contains(orphanChecks, check) {
+			return utils.NewErrorToolResult(fmt.Sprintf("unsupported check %q, must be one of: %s", check, strings.Join(orphanChecks, ", "))), nil
+		}
+		checks[check] = true
+	}
+	if len(checks) == 0 {
+		for _, check := range orphanChecks {
+			checks[check] = true
+		}
+	}
+
+	report := &models.OrphanReport{
+		MinAgeHours:   minAgeHours,
+		JobMinAgeDays: jobMinAgeDays,
+		Candidates:    []models.OrphanCandidate{},
+		CountsByCheck: make(map[string]int),
+	}
+	for _, check := range orphanChecks {
+		if checks[check] {
+			report.Checks = append(report.Checks, check)
+		}
+	}
+
+	namespaces := utils.SplitCommaList(namespacesStr)
+	if len(namespaces) == 0 {
+		names, err := h.Client.ListNamespaceNames(ctx)
+		if err != nil {
+			return utils.NewKubeErrorResult(err, "failed to list namespaces"), nil
+		}
+		for _, name := range names {
+			if !orphanSystemNamespaces[name] {
+				namespaces = append(namespaces, name)
+			}
+		}
+	}
+	report.Namespaces = namespaces
+
+	scan := &orphanScan{
+		h:          h,
+		checks:     checks,
+		minAge:     time.Duration(minAgeHours) * time.Hour,
+		jobMinAge:  time.Duration(jobMinAgeDays) * 24 * time.Hour,
+		emitDelete: emitDelete,
+		now:        time.Now(),
+		report:     report,
+	}
+	if checks[orphanCheckSecrets] || checks[orphanCheckPVCs] {
+		scan.loadPersistentVolumes(ctx)
+	}
+
+	for _, namespace := range namespaces {
+		if err := ctx.Err(); err != nil {
+			return utils.NewKubeErrorResult(err, "orphaned resource scan interrupted"), nil
+		}
+		scan.scanNamespace(ctx, namespace)
+	}
+
+	sort.SliceStable(report.Candidates, func(i, j int) bool {
+		a, b := report.Candidates[i], report.Candidates[j]
+		if a.Check != b.Check {
+			return slices.Index(orphanChecks, a.Check) < slices.Index(orphanChecks, b.Check)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if checks[orphanCheckPVCs] {
+		report.ReclaimableStorage = scan.reclaimable.String()
+	}
+	report.Note = "Candidates are based on references from pods, workload templates, ServiceAccounts, Ingresses and PersistentVolumes only. " +
+		"References from custom resources, controllers or applications reading the API directly are not detected; review each candidate before deleting."
+	if emitDelete {
+		report.Note += " Delete commands are generated per object and are not executed by this tool."
+	}
+
+	return utils.RenderResult(request, report), nil
+}
+
+// loadPersistentVolumes 列出PersistentVolume，用于PVC的回收策略和PV引用的Secret
+func (s *orphanScan) loadPersistentVolumes(ctx context.Context) {
+	list, err := s.h.Client.ClientSet().CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		s.h.Log.Warn("Failed to list persistent volumes", "error", err)
+		s.report.Errors = append(s.report.Errors, fmt.Sprintf("persistentvolumes: %v", err))
+		if s.checks[orphanCheckSecrets] {
+			// 无法确认PV引用的Secret时不能判定任何Secret未被引用
+			delete(s.checks, orphanCheckSecrets)
+			s.report.Skipped = append(s.report.Skipped, "secrets: persistent volumes could not be listed to check CSI secret references")
+		}
+		return
+	}
+	s.pvs = make(map[string]*corev1.PersistentVolume, len(list.Items))
+	s.volumeSecrets = make(map[string]bool)
+	for i := range list.Items {
+		pv := &list.Items[i]
+		s.pvs[pv.Name] = pv
+		for _, ref := range persistentVolumeSecretRefs(pv) {
+			s.volumeSecrets[ref] = true
+		}
+	}
+}
+
+// scanNamespace 收集命名空间中的引用并执行各检查项
+func (s *orphanScan) scanNamespace(ctx context.Context, namespace string) {
+	refs, err := s.collectReferences(ctx, namespace)
+	if err != nil {
+		// 引用不完整时任何判定都可能误报，跳过整个命名空间
+		s.h.Log.Warn("Failed to collect references", "namespace", namespace, "error", err)
+		s.report.Errors = append(s.report.Errors, fmt.Sprintf("%s: %v", namespace, err))
+		s.report.Skipped = append(s.report.Skipped, fmt.Sprintf("namespace %s: workloads could not be listed", namespace))
+		return
+	}
+
+	type namespaceCheck struct {
+		name string
+		run  func(context.Context, string, *orphanNamespaceRefs) error
+	}
+	for _, check := range []namespaceCheck{
+		{orphanCheckConfigMaps, s.checkConfigMaps},
+		{orphanCheckSecrets, s.checkSecrets},
+		{orphanCheckPVCs, s.checkPVCs},
+		{orphanCheckServices, s.checkServices},
+		{orphanCheckJobs, s.checkJobs},
+		{orphanCheckReplicaSets, s.checkReplicaSets},
+	} {
+		if !s.checks[check.name] {
+			continue
+		}
+		if err := check.run(ctx, namespace, refs); err != nil {
+			s.h.Log.Warn("Orphan check failed", "check", check.name, "namespace", namespace, "error", err)
+			s.report.Errors = append(s.report.Errors, fmt.Sprintf("%s/%s: %v", namespace, check.name, err))
+		}
+	}
+}
+
+// collectReferences 列出命名空间中的Pod和工作负载，收集它们引用的ConfigMap、Secret和PVC
+func (s *orphanScan) collectReferences(ctx context.Context, namespace string) (*orphanNamespaceRefs, error) {
+	clientSet := s.h.Client.ClientSet()
+	refs := &orphanNamespaceRefs{
+		podSpecRefs: newPodSpecRefs(),
+		deployments: make(map[string]*appsv1.Deployment),
+	}
+	listOptions := metav1.ListOptions{}
+
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	refs.pods = pods.Items
+	for i := range pods.Items {
+		refs.addPodSpec(&pods.Items[i].Spec)
+	}
+
+	deployments, err := clientSet.AppsV1().Deployments(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		refs.deployments[deployment.Name] = deployment
+		refs.addTemplate(&deployment.Spec.Template)
+	}
+
+	statefulSets, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list statefulsets: %w", err)
+	}
+	refs.statefulSets = statefulSets.Items
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		refs.addTemplate(&statefulSet.Spec.Template)
+	}
+
+	daemonSets, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list daemonsets: %w", err)
+	}
+	for i := range daemonSets.Items {
+		refs.addTemplate(&daemonSets.Items[i].Spec.Template)
+	}
+
+	replicaSets, err := clientSet.AppsV1().ReplicaSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list replicasets: %w", err)
+	}
+	refs.replicaSets = replicaSets.Items
+	for i := range replicaSets.Items {
+		replicaSet := &replicaSets.Items[i]
+		refs.addTemplate(&replicaSet.Spec.Template)
+	}
+
+	controllers, err := clientSet.CoreV1().ReplicationControllers(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list replicationcontrollers: %w", err)
+	}
+	for i := range controllers.Items {
+		controller := &controllers.Items[i]
+		if controller.Spec.Template != nil {
+			refs.addTemplate(controller.Spec.Template)
+		}
+	}
+
+	jobs, err := clientSet.BatchV1().Jobs(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	refs.jobs = jobs.Items
+	for i := range jobs.Items {
+		refs.addTemplate(&jobs.Items[i].Spec.Template)
+	}
+
+	cronJobs, err := clientSet.BatchV1().CronJobs(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("list cronjobs: %w", err)
+	}
+	for i := range cronJobs.Items {
+		cronJob := &cronJobs.Items[i]
+		refs.addTemplate(&cronJob.Spec.JobTemplate.Spec.Template)
+	}
+
+	return refs, nil
+}
+
+// addTemplate 记录工作负载模板及其引用
+func (r *orphanNamespaceRefs) addTemplate(template *corev1.PodTemplateSpec) {
+	r.templateLabels = append(r.templateLabels, template.Labels)
+	r.addPodSpec(&template.Spec)
+}
+
+// scannedEvidence 说明引用扫描范围的依据
+func (r *orphanNamespaceRefs) scannedEvidence() string {
+	return fmt.Sprintf("scanned %d pods and %d workload templates in the namespace", len(r.pods), len(r.templateLabels))
+}
+
+// checkConfigMaps 查找未被任何Pod或工作负载模板引用的ConfigMap
+func (s *orphanScan) checkConfigMaps(ctx context.Context, namespace string, refs *orphanNamespaceRefs) error {
+	list, err := s.h.Client.ClientSet().CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		configMap := &list.Items[i]
+		if refs.configMaps[configMap.Name] || configMap.Name == orphanRootCAConfigMap ||
+			len(configMap.OwnerReferences) > 0 || configMap.Annotations[orphanLeaderAnnotation] != "" ||
+			configMap.Labels["owner"] == "helm" || !s.oldEnough(configMap.CreationTimestamp) {
+			continue
+		}
+		s.addCandidate(orphanCheckConfigMaps, "ConfigMap", "v1", configMap.ObjectMeta, "", []string{
+			"not referenced by any volume, projected volume, env or envFrom",
+			refs.scannedEvidence(),
+			"has no ownerReferences",
+		})
+	}
+	return nil
+}
+
+// checkSecrets 查找未被Pod、工作负载模板、ServiceAccount、Ingress或PersistentVolume引用的Secret
+func (s *orphanScan) checkSecrets(ctx context.Context, namespace string, refs *orphanNamespaceRefs) error {
+	clientSet := s.h.Client.ClientSet()
+	referenced := make(map[string]bool, len(refs.secrets))
+	for name := range refs.secrets {
+		referenced[name] = true
+	}
+
+	serviceAccounts, err := clientSet.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list serviceaccounts: %w", err)
+	}
+	for _, serviceAccount := range serviceAccounts.Items {
+		for _, secret := range serviceAccount.Secrets {
+			referenced[secret.Name] = true
+		}
+		for _, secret := range serviceAccount.ImagePullSecrets {
+			referenced[secret.Name] = true
+		}
+	}
+
+	ingresses, err := clientSet.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list ingresses: %w", err)
+	}
+	for i := range ingresses.Items {
+		for _, name := range ingressSecretRefs(&ingresses.Items[i]) {
+			referenced[name] = true
+		}
+	}
+
+	list, err := clientSet.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		secret := &list.Items[i]
+		if referenced[secret.Name] || s.volumeSecrets[namespace+"/"+secret.Name] ||
+			orphanSkippedSecretTypes[secret.Type] || len(secret.OwnerReferences) > 0 ||
+			secret.Annotations[orphanCertificateAnnotation] != "" || !s.oldEnough(secret.CreationTimestamp) {
+			continue
+		}
+		s.addCandidate(orphanCheckSecrets, "Secret", "v1", secret.ObjectMeta, "", []string{
+			"not referenced by any volume, projected volume, CSI or volume plugin secretRef, env, envFrom or imagePullSecrets",
+			"not referenced by any ServiceAccount, Ingress TLS or Ingress annotation, or PersistentVolume secretRef",
+			refs.scannedEvidence(),
+			fmt.Sprintf("type %s, has no ownerReferences", secret.Type),
+		})
+	}
+	return nil
+}
+
+// checkPVCs 查找未被任何Pod、工作负载模板或StatefulSet当前副本使用的PVC
+func (s *orphanScan) checkPVCs(ctx context.Context, namespace string, refs *orphanNamespaceRefs) error {
+	list, err := s.h.Client.ClientSet().CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		pvc := &list.Items[i]
+		if refs.pvcs[pvc.Name] || len(pvc.OwnerReferences) > 0 || pvc.DeletionTimestamp != nil ||
+			!s.oldEnough(pvc.CreationTimestamp) {
+			continue
+		}
+		evidence := []string{
+			"not mounted by any pod and not referenced by any workload template",
+			refs.scannedEvidence(),
+			fmt.Sprintf("phase %s", pvc.Status.Phase),
+		}
+		if statefulSet, inUse := statefulSetClaimOwner(pvc.Name, refs.statefulSets); statefulSet != "" {
+			if inUse {
+				// 序号在当前副本数内，Pod重建后会继续使用
+				continue
+			}
+			evidence = append(evidence, fmt.Sprintf("created by StatefulSet %s for an ordinal beyond its current replicas", statefulSet))
+		}
+
+		size, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+		if !ok {
+			size = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+		if pv := s.pvs[pvc.Spec.VolumeName]; pv != nil {
+			evidence = append(evidence, fmt.Sprintf("bound PersistentVolume %s has reclaimPolicy %s", pv.Name, pv.Spec.PersistentVolumeReclaimPolicy))
+		}
+		s.reclaimable.Add(size)
+		s.addCandidate(orphanCheckPVCs, "PersistentVolumeClaim", "v1", pvc.ObjectMeta, size.String(), evidence)
+	}
+	return nil
+}
+
+// checkServices 查找选择器不匹配任何Pod或工作负载模板的Service
+func (s *orphanScan) checkServices(ctx context.Context, namespace string, refs *orphanNamespaceRefs) error {
+	list, err := s.h.Client.ClientSet().CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		service := &list.Items[i]
+		// 没有选择器的Service使用手动维护的Endpoints，ExternalName不选择Pod
+		if len(service.Spec.Selector) == 0 || service.Spec.Type == corev1.ServiceTypeExternalName ||
+			len(service.OwnerReferences) > 0 || !s.oldEnough(service.CreationTimestamp) {
+			continue
+		}
+		selector := labels.SelectorFromSet(service.Spec.Selector)
+		matched := false
+		for j := range refs.pods {
+			if selector.Matches(labels.Set(refs.pods[j].Labels)) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		// 匹配工作负载模板时（包括缩容为0的工作负载），Service会在Pod创建后恢复使用
+		for _, templateLabels := range refs.templateLabels {
+			if selector.Matches(labels.Set(templateLabels)) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		s.addCandidate(orphanCheckServices, "Service", "v1", service.ObjectMeta, "", []string{
+			fmt.Sprintf("selector %s matches no pods", selector.String()),
+			"selector matches no workload pod template",
+			refs.scannedEvidence(),
+		})
+	}
+	return nil
+}
+
+// checkJobs 查找完成时间早于阈值、不会被自动清理的Job
+func (s *orphanScan) checkJobs(_ context.Context, _ string, refs *orphanNamespaceRefs) error {
+	for i := range refs.jobs {
+		job := &refs.jobs[i]
+		// CronJob按历史保留数量清理其Job，设置了ttlSecondsAfterFinished的Job由TTL控制器清理
+		if metav1.GetControllerOf(job) != nil || job.Spec.TTLSecondsAfterFinished != nil {
+			continue
+		}
+		finishedType, finishedAt := jobFinished(job)
+		if finishedType == "" || finishedAt.IsZero() || s.now.Sub(finishedAt) < s.jobMinAge {
+			continue
+		}
+		s.addCandidate(orphanCheckJobs, "Job", "batch/v1", job.ObjectMeta, "", []string{
+			fmt.Sprintf("finished with condition %s at %s (%s ago)", finishedType, finishedAt.UTC().Format(time.RFC3339),
+				duration.HumanDuration(s.now.Sub(finishedAt))),
+			"not owned by a CronJob",
+			"ttlSecondsAfterFinished is not set, so it is never cleaned up automatically",
+		})
+	}
+	return nil
+}
+
+// checkReplicaSets 查找副本数为0且不是Deployment当前版本的ReplicaSet
+func (s *orphanScan) checkReplicaSets(_ context.Context, _ string, refs *orphanNamespaceRefs) error {
+	for i := range refs.replicaSets {
+		replicaSet := &refs.replicaSets[i]
+		if !zeroReplicas(replicaSet.Spec.Replicas) || replicaSet.Status.Replicas > 0 ||
+			!s.oldEnough(replicaSet.CreationTimestamp) {
+			continue
+		}
+		revision := replicaSet.Annotations[orphanDeploymentRevisionAnno]
+		var evidence []string
+		owner := metav1.GetControllerOf(replicaSet)
+		switch {
+		case owner == nil:
+			evidence = []string{"scaled to 0 replicas", "has no owner Deployment"}
+		case owner.Kind != "Deployment":
+			continue
+		default:
+			deployment := refs.deployments[owner.Name]
+			if deployment == nil || deployment.UID != owner.UID {
+				evidence = []string{"scaled to 0 replicas", fmt.Sprintf("owner Deployment %s no longer exists", owner.Name)}
+				break
+			}
+			current := deployment.Annotations[orphanDeploymentRevisionAnno]
+			// 当前版本的ReplicaSet在Deployment缩容为0时副本数同样为0，不能删除
+			if revision == "" || revision == current {
+				continue
+			}
+			historyLimit := "10"
+			if deployment.Spec.RevisionHistoryLimit != nil {
+				historyLimit = fmt.Sprint(*deployment.Spec.RevisionHistoryLimit)
+			}
+			evidence = []string{
+				"scaled to 0 replicas",
+				fmt.Sprintf("old revision %s of Deployment %s (current revision %s)", revision, owner.Name, current),
+				fmt.Sprintf("deleting it removes the rollback target for revision %s; the Deployment keeps up to %s old ReplicaSets (revisionHistoryLimit)", revision, historyLimit),
+			}
+		}
+		s.addCandidate(orphanCheckReplicaSets, "ReplicaSet", "apps/v1", replicaSet.ObjectMeta, "", evidence)
+	}
+	return nil
+}
+
+// oldEnough 对象是否已存在超过最小年龄，避免把刚创建、尚未被引用的对象判定为孤立
+func (s *orphanScan) oldEnough(created metav1.Time) bool {
+	return s.now.Sub(created.Time) >= s.minAge
+}
+
+// addCandidate 记录一个候选资源
+func (s *orphanScan) addCandidate(check, kind, apiVersion string, meta metav1.ObjectMeta, storage string, evidence []string) {
+	candidate := models.OrphanCandidate{
+		Check:      check,
+		Kind:       kind,
+		APIVersion: apiVersion,
+		Namespace:  meta.Namespace,
+		Name:       meta.Name,
+		CreatedAt:  meta.CreationTimestamp.Time,
+		Age:        duration.HumanDuration(s.now.Sub(meta.CreationTimestamp.Time)),
+		Evidence:   evidence,
+		Storage:    storage,
+	}
+	if s.emitDelete {
+		prefix := orphanDeletePrefixes[kind]
+		if base.FlatToolNaming() {
+			prefix = ""
+		}
+		candidate.DeleteCommand = &models.ToolCommand{
+			Tool: base.ResourceToolName(base.OperationDelete, prefix),
+			Arguments: map[string]any{
+				"kind":       kind,
+				"apiVersion": apiVersion,
+				"name":       meta.Name,
+				"namespace":  meta.Namespace,
+			},
+		}
+	}
+	s.report.Candidates = append(s.report.Candidates, candidate)
+	s.report.CountsByCheck[check]++
+}
+
+// persistentVolumeSecretRefs 返回PersistentVolume引用的Secret，格式为namespace/name
+func persistentVolumeSecretRefs(pv *corev1.PersistentVolume) []string {
+	var refs []string
+	add := func(ref *corev1.SecretReference) {
+		if ref != nil && ref.Name != "" {
+			refs = append(refs, ref.Namespace+"/"+ref.Name)
+		}
+	}
+	source := pv.Spec.PersistentVolumeSource
+	if source.CSI != nil {
+		add(source.CSI.ControllerPublishSecretRef)
+		add(source.CSI.NodeStageSecretRef)
+		add(source.CSI.NodePublishSecretRef)
+		add(source.CSI.ControllerExpandSecretRef)
+		add(source.CSI.NodeExpandSecretRef)
+	}
+	if source.CephFS != nil {
+		add(source.CephFS.SecretRef)
+	}
+	if source.RBD != nil {
+		add(source.RBD.SecretRef)
+	}
+	if source.ISCSI != nil {
+		add(source.ISCSI.SecretRef)
+	}
+	if source.FlexVolume != nil {
+		add(source.FlexVolume.SecretRef)
+	}
+	if source.ScaleIO != nil {
+		add(source.ScaleIO.SecretRef)
+	}
+	if source.AzureFile != nil {
+		namespace := "default"
+		if source.AzureFile.SecretNamespace != nil {
+			namespace = *source.AzureFile.SecretNamespace
+		}
+		refs = append(refs, namespace+"/"+source.AzureFile.SecretName)
+	}
+	if source.StorageOS != nil && source.StorageOS.SecretRef != nil {
+		refs = append(refs, source.StorageOS.SecretRef.Namespace+"/"+source.StorageOS.SecretRef.Name)
+	}
+	return refs
+}
+
+// jobFinished 返回Job的结束条件类型和结束时间，未结束时类型为空
+func jobFinished(job *batchv1.Job) (string, time.Time) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type != batchv1.JobComplete && condition.Type != batchv1.JobFailed {
+			continue
+		}
+		if job.Status.CompletionTime != nil {
+			return string(condition.Type), job.Status.CompletionTime.Time
+		}
+		return string(condition.Type), condition.LastTransitionTime.Time
+	}
+	return "", time.Time{}
+}
+
+// zeroReplicas 副本数是否显式设置为0，未设置时默认为1
+func zeroReplicas(replicas *int32) bool {
+	return replicas != nil && *replicas == 0
+}
@@ -0,0 +1,335 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 所有权树的深度与规模限制
+const (
+	defaultOwnershipDepth = 4
+	maxOwnershipDepth     = 8
+	defaultOwnershipNodes = 200
+	maxOwnershipNodes     = 1000
+)
+
+// builtinChildKinds 内置控制器直接创建的子资源类型
+var builtinChildKinds = map[schema.GroupKind][]schema.GroupVersionKind{
+	{Group: "apps", Kind: "Deployment"}:  {{Group: "apps", Version: "v1", Kind: "ReplicaSet"}},
+	{Group: "apps", Kind: "ReplicaSet"}:  {{Version: "v1", Kind: "Pod"}},
+	{Group: "apps", Kind: "StatefulSet"}: {{Version: "v1", Kind: "Pod"}, {Group: "apps", Version: "v1", Kind: "ControllerRevision"}},
+	{Group: "apps", Kind: "DaemonSet"}:   {{Version: "v1", Kind: "Pod"}, {Group: "apps", Version: "v1", Kind: "ControllerRevision"}},
+	{Group: "batch", Kind: "CronJob"}:    {{Group: "batch", Version: "v1", Kind: "Job"}},
+	{Group: "batch", Kind: "Job"}:        {{Version: "v1", Kind: "Pod"}},
+}
+
+// ownershipWalker 遍历所有权树时的状态
+type ownershipWalker struct {
+	h          *UtilityHandler
+	maxDepth   int
+	maxNodes   int
+	childKinds map[schema.GroupKind][]schema.GroupVersionKind
+	// lists 按类型和命名空间缓存的列表结果，同一层级的多个父对象共用
+	lists map[string][]unstructured.Unstructured
+	// startUID 起始对象的UID，用于在树中标记起始对象
+	startUID types.UID
+	result   *models.OwnershipGraph
+}
+
+// GetOwnershipGraph 从指定对象沿ownerReferences向上找到根对象，再向下列出根对象的全部后代
+func (h *UtilityHandler) GetOwnershipGraph(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	maxDepth := defaultOwnershipDepth
+	if value, ok := arguments["maxDepth"].(float64); ok && value > 0 {
+		maxDepth = min(int(value), maxOwnershipDepth)
+	}
+	maxNodes := defaultOwnershipNodes
+	if value, ok := arguments["maxNodes"].(float64); ok && value > 0 {
+		maxNodes = min(int(value), maxOwnershipNodes)
+	}
+
+	h.Log.Info("Building ownership graph",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"name", name,
+		"namespace", namespace,
+		"maxDepth", maxDepth,
+		"maxNodes", maxNodes,
+	)
+
+	if kind == "" || apiVersion == "" || name == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind, apiVersion and name"), nil
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	start, err := h.getOwnershipObject(ctx, apiVersion, kind, name, namespace)
+	if err != nil {
+		h.Log.Error("Failed to get resource", "kind", kind, "name", name, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+	}
+
+	walker := &ownershipWalker{
+		h:          h,
+		maxDepth:   maxDepth,
+		maxNodes:   maxNodes,
+		childKinds: make(map[schema.GroupKind][]schema.GroupVersionKind),
+		lists:      make(map[string][]unstructured.Unstructured),
+		startUID:   start.GetUID(),
+		result:     &models.OwnershipGraph{},
+	}
+	root := walker.ancestors(ctx, start)
+	walker.result.Root = walker.descendants(ctx, root, 0)
+
+	return utils.RenderResult(request, walker.result), nil
+}
+
+// getOwnershipObject 获取对象，集群级别资源忽略namespace
+func (h *UtilityHandler) getOwnershipObject(
+	ctx context.Context,
+	apiVersion, kind, name, namespace string,
+) (*unstructured.Unstructured, error) {
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return h.Client.GetDynamicClient().Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	return h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ancestors 沿ownerReferences向上查找根对象，优先使用controller引用
+// 途经的每一对父子类型都会记录下来，向下遍历时据此列出自定义控制器创建的子对象
+func (w *ownershipWalker) ancestors(ctx context.Context, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	w.result.Ancestors = append(w.result.Ancestors, objectRef(obj))
+	for depth := 0; depth < w.maxDepth; depth++ {
+		ref := metav1.GetControllerOf(obj)
+		if ref == nil {
+			refs := obj.GetOwnerReferences()
+			if len(refs) == 0 {
+				return obj
+			}
+			ref = &refs[0]
+		}
+
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			w.result.Errors = append(w.result.Errors, fmt.Sprintf("invalid owner apiVersion %q on %s", ref.APIVersion, objectRef(obj)))
+			return obj
+		}
+		w.addChildKind(gv.WithKind(ref.Kind).GroupKind(), obj.GroupVersionKind())
+
+		owner, err := w.h.getOwnershipObject(ctx, ref.APIVersion, ref.Kind, ref.Name, obj.GetNamespace())
+		if err != nil {
+			w.result.Errors = append(w.result.Errors, fmt.Sprintf("failed to get owner %s/%s of %s: %v", ref.Kind, ref.Name, objectRef(obj), err))
+			return obj
+		}
+		if owner.GetUID() != ref.UID {
+			w.result.Errors = append(w.result.Errors, fmt.Sprintf("owner %s/%s of %s was recreated, the reference is stale", ref.Kind, ref.Name, objectRef(obj)))
+			return obj
+		}
+		obj = owner
+		w.result.Ancestors = append(w.result.Ancestors, objectRef(obj))
+	}
+	w.result.Truncated = true
+	return obj
+}
+
+// descendants 构建obj及其后代的所有权树
+func (w *ownershipWalker) descendants(ctx context.Context, obj *unstructured.Unstructured, depth int) *models.OwnershipNode {
+	node := ownershipNode(obj)
+	node.Start = obj.GetUID() == w.startUID
+	w.result.NodeCount++
+
+	childKinds := w.childKindsOf(obj.GroupVersionKind().GroupKind())
+	if len(childKinds) == 0 {
+		return node
+	}
+	if depth >= w.maxDepth {
+		node.Truncated = true
+		w.result.Truncated = true
+		return node
+	}
+
+	for _, gvk := range childKinds {
+		for _, child := range w.children(ctx, gvk, obj) {
+			if w.result.NodeCount >= w.maxNodes {
+				node.Truncated = true
+				w.result.Truncated = true
+				return node
+			}
+			node.Children = append(node.Children, w.descendants(ctx, child, depth+1))
+		}
+	}
+	return node
+}
+
+// children 列出类型为gvk且ownerReferences指向owner的对象，按名称排序
+func (w *ownershipWalker) children(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	owner *unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	// 集群级别的所有者可能拥有任意命名空间中的对象
+	namespace := owner.GetNamespace()
+	cacheKey := gvk.String() + "/" + namespace
+	items, ok := w.lists[cacheKey]
+	if !ok {
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		gvr, namespaced, err := utils.ResolveGVR(w.h.Client, apiVersion, kind)
+		if err != nil {
+			w.result.Errors = append(w.result.Errors, fmt.Sprintf("failed to resolve %s: %v", kind, err))
+			w.lists[cacheKey] = nil
+			return nil
+		}
+		resource := w.h.Client.GetDynamicClient().Resource(gvr)
+		var list *unstructured.UnstructuredList
+		if namespaced && namespace != "" {
+			list, err = resource.Namespace(namespace).List(ctx, metav1.ListOptions{})
+		} else {
+			list, err = resource.List(ctx, metav1.ListOptions{})
+		}
+		if err != nil {
+			w.result.Errors = append(w.result.Errors, fmt.Sprintf("failed to list %s: %v", kind, err))
+			w.lists[cacheKey] = nil
+			return nil
+		}
+		items = list.Items
+		w.lists[cacheKey] = items
+	}
+
+	var children []*unstructured.Unstructured
+	for i := range items {
+		for _, ref := range items[i].GetOwnerReferences() {
+			if ref.UID == owner.GetUID() {
+				children = append(children, &items[i])
+				break
+			}
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].GetName() < children[j].GetName()
+	})
+	return children
+}
+
+// addChildKind 记录parent类型的对象会创建child类型的对象
+func (w *ownershipWalker) addChildKind(parent schema.GroupKind, child schema.GroupVersionKind) {
+	for _, existing := range w.childKindsOf(parent) {
+		if existing.GroupKind() == child.GroupKind() {
+			return
+		}
+	}
+	w.childKinds[parent] = append(w.childKinds[parent], child)
+}
+
+// childKindsOf 返回parent类型可能拥有的子类型：内置控制器的子类型加上向上遍历时观察到的子类型
+func (w *ownershipWalker) childKindsOf(parent schema.GroupKind) []schema.GroupVersionKind {
+	return append(append([]schema.GroupVersionKind(nil), builtinChildKinds[parent]...), w.childKinds[parent]...)
+}
+
+// ownershipNode 将对象转换为所有权树节点
+func ownershipNode(obj *unstructured.Unstructured) *models.OwnershipNode {
+	readiness, status := objectReadiness(obj)
+	return &models.OwnershipNode{
+		Kind:         obj.GetKind(),
+		APIVersion:   obj.GetAPIVersion(),
+		Name:         obj.GetName(),
+		Namespace:    obj.GetNamespace(),
+		Readiness:    readiness,
+		Status:       status,
+		CreationTime: obj.GetCreationTimestamp().Time,
+	}
+}
+
+// objectRef 以"Kind/name"形式描述对象
+func objectRef(obj *unstructured.Unstructured) string {
+	return obj.GetKind() + "/" + obj.GetName()
+}
+
+// objectReadiness 根据常见工作负载的status字段判断就绪状态，其他类型使用Ready条件
+func objectReadiness(obj *unstructured.Unstructured) (string, string) {
+	switch obj.GetKind() {
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		switch phase {
+		case "Succeeded":
+			return "Completed", phase
+		case "Failed":
+			return "Failed", phase
+		}
+		if conditionStatus(obj, "Ready") == "True" {
+			return "Ready", phase
+		}
+		return "NotReady", phase
+	case "Deployment", "ReplicaSet", "StatefulSet":
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return readinessOf(ready >= replicas), fmt.Sprintf("%d/%d", ready, replicas)
+	case "DaemonSet":
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		return readinessOf(ready >= desired), fmt.Sprintf("%d/%d", ready, desired)
+	case "Job":
+		if conditionStatus(obj, "Complete") == "True" {
+			return "Completed", "Complete"
+		}
+		if conditionStatus(obj, "Failed") == "True" {
+			return "Failed", "Failed"
+		}
+		active, _, _ := unstructured.NestedInt64(obj.Object, "status", "active")
+		return "NotReady", fmt.Sprintf("%d active", active)
+	}
+
+	switch conditionStatus(obj, "Ready") {
+	case "True":
+		return "Ready", ""
+	case "False", "Unknown":
+		return "NotReady", ""
+	}
+	return "", ""
+}
+
+// readinessOf 将布尔就绪状态转换为字符串
+func readinessOf(ready bool) string {
+	if ready {
+		return "Ready"
+	}
+	return "NotReady"
+}
+
+// conditionStatus 返回status.conditions中指定类型条件的状态，不存在时返回空字符串
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		return status
+	}
+	return ""
+}
@@ -0,0 +1,483 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// storageClassQuotaSuffix 按StorageClass限制存储配额的资源名后缀
+const storageClassQuotaSuffix = ".storageclass.storage.k8s.io/"
+
+// CheckQuotaFit 估算清单中的对象需要的配额，并与目标命名空间的剩余配额比较
+func (h *UtilityHandler) CheckQuotaFit(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	yamlStr, _ := arguments["yaml"].(string)
+	namespace, _ := arguments["namespace"].(string)
+
+	h.Log.Info("Checking quota fit", "namespace", namespace)
+
+	if yamlStr == "" {
+		return utils.NewErrorToolResult("yaml manifest is required"), nil
+	}
+
+	result, err := h.estimateQuotaFit(ctx, yamlStr, namespace)
+	if err != nil {
+		return utils.NewKubeErrorResult(err, "failed to check resource quotas"), nil
+	}
+	return utils.RenderResult(request, result), nil
+}
+
+// quotaEstimator 累计清单中的对象按命名空间占用的配额资源
+type quotaEstimator struct {
+	h           *UtilityHandler
+	limitRanges map[string][]corev1.LimitRange
+	// nodeCount 集群节点数量，用于估算DaemonSet的Pod数量，-1表示尚未获取
+	nodeCount int64
+	usage     map[string]corev1.ResourceList
+	result    *models.QuotaFitResult
+}
+
+// estimateQuotaFit 解析清单并计算每个命名空间的配额占用和剩余配额
+func (h *UtilityHandler) estimateQuotaFit(ctx context.Context, yamlStr, namespace string) (*models.QuotaFitResult, error) {
+	estimator := &quotaEstimator{
+		h:           h,
+		limitRanges: make(map[string][]corev1.LimitRange),
+		nodeCount:   -1,
+		usage:       make(map[string]corev1.ResourceList),
+		result: &models.QuotaFitResult{
+			Fits:    true,
+			Objects: []models.QuotaFitObject{},
+			Checks:  []models.QuotaFitCheck{},
+		},
+	}
+
+	for i, doc := range strings.Split(yamlStr, "---") {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil || obj.GetKind() == "" {
+			estimator.result.Objects = append(estimator.result.Objects, models.QuotaFitObject{
+				Error: fmt.Sprintf("document %d is not a valid Kubernetes object", i+1),
+			})
+			continue
+		}
+		if namespace != "" {
+			obj.SetNamespace(namespace)
+		}
+		estimator.add(ctx, obj)
+	}
+
+	namespaces := make([]string, 0, len(estimator.usage))
+	for ns := range estimator.usage {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		if err := estimator.compare(ctx, ns); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, object := range estimator.result.Objects {
+		if object.Exists {
+			estimator.result.Notes = append(estimator.result.Notes,
+				"部分对象已存在，估算按新建计算；更新已存在的对象时实际增量为新旧用量之差，可能小于估算值")
+			break
+		}
+	}
+	return estimator.result, nil
+}
+
+// add 估算一个对象的配额占用并累加到其命名空间
+func (e *quotaEstimator) add(ctx context.Context, obj *unstructured.Unstructured) {
+	object := models.QuotaFitObject{Kind: obj.GetKind(), Name: obj.GetName()}
+	defer func() {
+		e.result.Objects = append(e.result.Objects, object)
+	}()
+
+	gvr, namespaced, err := utils.ResolveGVR(e.h.Client, obj.GetAPIVersion(), obj.GetKind())
+	if err != nil {
+		object.Error = err.Error()
+		return
+	}
+	if !namespaced {
+		// 集群级别资源不受命名空间配额限制
+		return
+	}
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	object.Namespace = namespace
+	if obj.GetName() != "" {
+		if _, err := e.h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace).Get(ctx, obj.GetName(), metav1.GetOptions{}); err == nil {
+			object.Exists = true
+		}
+	}
+
+	usage := corev1.ResourceList{}
+	// 对象数量配额，例如count/deployments.apps
+	countName := "count/" + gvr.Resource
+	if gvr.Group != "" {
+		countName += "." + gvr.Group
+	}
+	addQuantity(usage, corev1.ResourceName(countName), 1)
+
+	if err := e.objectUsage(ctx, obj, namespace, usage, &object); err != nil {
+		object.Error = err.Error()
+		return
+	}
+
+	object.Usage = make(map[string]string, len(usage))
+	for name, quantity := range usage {
+		object.Usage[string(name)] = formatQuotaQuantity(name, quantity)
+	}
+	if e.usage[namespace] == nil {
+		e.usage[namespace] = corev1.ResourceList{}
+	}
+	for name, quantity := range usage {
+		current := e.usage[namespace][name]
+		current.Add(quantity)
+		e.usage[namespace][name] = current
+	}
+}
+
+// objectUsage 按对象类型计算Pod、存储和旧式对象数量配额的占用
+func (e *quotaEstimator) objectUsage(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+	namespace string,
+	usage corev1.ResourceList,
+	object *models.QuotaFitObject,
+) error {
+	gk := obj.GroupVersionKind().GroupKind()
+	var template *corev1.PodSpec
+	replicas := int64(1)
+	var claimTemplates []corev1.PersistentVolumeClaim
+
+	switch {
+	case gk.Group == "" && gk.Kind == "Pod":
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+			return err
+		}
+		template = &pod.Spec
+	case gk.Group == "" && gk.Kind == "ReplicationController":
+		rc := &corev1.ReplicationController{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, rc); err != nil {
+			return err
+		}
+		if rc.Spec.Template != nil {
+			template = &rc.Spec.Template.Spec
+		}
+		replicas = int64(derefReplicas(rc.Spec.Replicas))
+		addQuantity(usage, corev1.ResourceReplicationControllers, 1)
+	case gk.Group == "apps" && gk.Kind == "Deployment":
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+			return err
+		}
+		template = &deployment.Spec.Template.Spec
+		replicas = int64(derefReplicas(deployment.Spec.Replicas))
+	case gk.Group == "apps" && gk.Kind == "ReplicaSet":
+		replicaSet := &appsv1.ReplicaSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, replicaSet); err != nil {
+			return err
+		}
+		template = &replicaSet.Spec.Template.Spec
+		replicas = int64(derefReplicas(replicaSet.Spec.Replicas))
+	case gk.Group == "apps" && gk.Kind == "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, statefulSet); err != nil {
+			return err
+		}
+		template = &statefulSet.Spec.Template.Spec
+		replicas = int64(derefReplicas(statefulSet.Spec.Replicas))
+		claimTemplates = statefulSet.Spec.VolumeClaimTemplates
+	case gk.Group == "apps" && gk.Kind == "DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonSet); err != nil {
+			return err
+		}
+		template = &daemonSet.Spec.Template.Spec
+		replicas = e.nodes(ctx)
+	case gk.Group == "batch" && gk.Kind == "Job":
+		job := &batchv1.Job{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, job); err != nil {
+			return err
+		}
+		template = &job.Spec.Template.Spec
+		replicas = jobConcurrency(job.Spec)
+	case gk.Group == "batch" && gk.Kind == "CronJob":
+		cronJob := &batchv1.CronJob{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cronJob); err != nil {
+			return err
+		}
+		template = &cronJob.Spec.JobTemplate.Spec.Template.Spec
+		replicas = jobConcurrency(cronJob.Spec.JobTemplate.Spec)
+	case gk.Group == "" && gk.Kind == "PersistentVolumeClaim":
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pvc); err != nil {
+			return err
+		}
+		addClaimUsage(usage, pvc, 1)
+	case gk.Group == "" && gk.Kind == "Service":
+		service := &corev1.Service{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, service); err != nil {
+			return err
+		}
+		addQuantity(usage, corev1.ResourceServices, 1)
+		switch service.Spec.Type {
+		case corev1.ServiceTypeLoadBalancer:
+			addQuantity(usage, corev1.ResourceServicesLoadBalancers, 1)
+			addQuantity(usage, corev1.ResourceServicesNodePorts, int64(len(service.Spec.Ports)))
+		case corev1.ServiceTypeNodePort:
+			addQuantity(usage, corev1.ResourceServicesNodePorts, int64(len(service.Spec.Ports)))
+		}
+	case gk.Group == "" && gk.Kind == "ConfigMap":
+		addQuantity(usage, corev1.ResourceConfigMaps, 1)
+	case gk.Group == "" && gk.Kind == "Secret":
+		addQuantity(usage, corev1.ResourceSecrets, 1)
+	}
+
+	if template != nil && replicas > 0 {
+		podUsage, defaulted, err := e.podUsage(ctx, namespace, template)
+		if err != nil {
+			return err
+		}
+		object.Pods = replicas
+		object.Defaulted = defaulted
+		for name, quantity := range podUsage {
+			quantity.Mul(replicas)
+			current := usage[name]
+			current.Add(quantity)
+			usage[name] = current
+		}
+	}
+	for i := range claimTemplates {
+		addClaimUsage(usage, &claimTemplates[i], replicas)
+	}
+	return nil
+}
+
+// podUsage 应用LimitRange默认值后计算单个Pod的配额占用，并返回应用了默认值的容器
+// 与LimitRanger准入控制器一致：缺少limit时使用default，缺少request时使用defaultRequest，仍缺少时使用limit
+func (e *quotaEstimator) podUsage(ctx context.Context, namespace string, spec *corev1.PodSpec) (corev1.ResourceList, []string, error) {
+	limitRanges, err := e.namespaceLimitRanges(ctx, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pod := &corev1.Pod{Spec: *spec.DeepCopy()}
+	var defaulted []string
+	applyDefaults := func(containers []corev1.Container) {
+		for i := range containers {
+			container := &containers[i]
+			for _, limitRange := range limitRanges {
+				for _, item := range limitRange.Spec.Limits {
+					if item.Type != corev1.LimitTypeContainer {
+						continue
+					}
+					for name, quantity := range item.Default {
+						if _, ok := container.Resources.Limits[name]; !ok {
+							if container.Resources.Limits == nil {
+								container.Resources.Limits = corev1.ResourceList{}
+							}
+							container.Resources.Limits[name] = quantity.DeepCopy()
+							defaulted = append(defaulted, fmt.Sprintf("%s: limits.%s=%s (LimitRange %s)", container.Name, name, quantity.String(), limitRange.Name))
+						}
+					}
+					for name, quantity := range item.DefaultRequest {
+						if _, ok := container.Resources.Requests[name]; !ok {
+							if container.Resources.Requests == nil {
+								container.Resources.Requests = corev1.ResourceList{}
+							}
+							container.Resources.Requests[name] = quantity.DeepCopy()
+							defaulted = append(defaulted, fmt.Sprintf("%s: requests.%s=%s (LimitRange %s)", container.Name, name, quantity.String(), limitRange.Name))
+						}
+					}
+				}
+			}
+			for name, limit := range container.Resources.Limits {
+				if _, ok := container.Resources.Requests[name]; !ok {
+					if container.Resources.Requests == nil {
+						container.Resources.Requests = corev1.ResourceList{}
+					}
+					container.Resources.Requests[name] = limit.DeepCopy()
+				}
+			}
+		}
+	}
+	applyDefaults(pod.Spec.InitContainers)
+	applyDefaults(pod.Spec.Containers)
+
+	usage := corev1.ResourceList{}
+	addQuantity(usage, corev1.ResourcePods, 1)
+	addQuantity(usage, "count/pods", 1)
+	for name, quantity := range utils.PodRequests(pod) {
+		usage[corev1.ResourceName("requests."+string(name))] = quantity.DeepCopy()
+		// cpu、memory等不带前缀的配额资源等同于requests
+		usage[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range utils.PodLimits(pod) {
+		usage[corev1.ResourceName("limits."+string(name))] = quantity.DeepCopy()
+	}
+	return usage, defaulted, nil
+}
+
+// compare 将命名空间的估算占用与其ResourceQuota逐项比较
+func (e *quotaEstimator) compare(ctx context.Context, namespace string) error {
+	quotas, err := e.h.Client.ClientSet().CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	requested := e.usage[namespace]
+	for _, quota := range quotas.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			e.result.Notes = append(e.result.Notes,
+				fmt.Sprintf("ResourceQuota %s/%s 带有作用域限制，未参与计算", namespace, quota.Name))
+			continue
+		}
+
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, resourceName := range names {
+			name := corev1.ResourceName(resourceName)
+			request, ok := requested[name]
+			if !ok || request.IsZero() {
+				continue
+			}
+			hard := quota.Status.Hard[name]
+			used := quota.Status.Used[name]
+			remaining := hard.DeepCopy()
+			remaining.Sub(used)
+			after := used.DeepCopy()
+			after.Add(request)
+
+			check := models.QuotaFitCheck{
+				Quota:     quota.Name,
+				Namespace: namespace,
+				Resource:  resourceName,
+				Hard:      formatQuotaQuantity(name, hard),
+				Used:      formatQuotaQuantity(name, used),
+				Requested: formatQuotaQuantity(name, request),
+				Remaining: formatQuotaQuantity(name, remaining),
+				After:     formatQuotaQuantity(name, after),
+				Fits:      after.Cmp(hard) <= 0,
+			}
+			if !check.Fits {
+				exceeded := after.DeepCopy()
+				exceeded.Sub(hard)
+				check.ExceededBy = formatQuotaQuantity(name, exceeded)
+				e.result.Fits = false
+				e.result.Exceeded = append(e.result.Exceeded, check)
+			}
+			e.result.Checks = append(e.result.Checks, check)
+		}
+	}
+	return nil
+}
+
+// namespaceLimitRanges 获取并缓存命名空间中的LimitRange
+func (e *quotaEstimator) namespaceLimitRanges(ctx context.Context, namespace string) ([]corev1.LimitRange, error) {
+	if limitRanges, ok := e.limitRanges[namespace]; ok {
+		return limitRanges, nil
+	}
+	list, err := e.h.Client.ClientSet().CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	e.limitRanges[namespace] = list.Items
+	return list.Items, nil
+}
+
+// nodes 返回集群节点数量，DaemonSet按每个节点一个Pod估算
+func (e *quotaEstimator) nodes(ctx context.Context) int64 {
+	if e.nodeCount >= 0 {
+		return e.nodeCount
+	}
+	e.nodeCount = 0
+	nodes, err := e.h.Client.ClientSet().CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		e.result.Notes = append(e.result.Notes, fmt.Sprintf("无法列出节点，DaemonSet的Pod数量未计入: %v", err))
+		return 0
+	}
+	e.nodeCount = int64(len(nodes.Items))
+	e.result.Notes = append(e.result.Notes, fmt.Sprintf("DaemonSet按集群中的%d个节点各一个Pod估算，未考虑nodeSelector和污点", e.nodeCount))
+	return e.nodeCount
+}
+
+// addClaimUsage 累加PVC的数量和存储请求，包括按StorageClass限制的配额
+func addClaimUsage(usage corev1.ResourceList, pvc *corev1.PersistentVolumeClaim, count int64) {
+	addQuantity(usage, corev1.ResourcePersistentVolumeClaims, count)
+	storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	storage.Mul(count)
+	addResource(usage, corev1.ResourceRequestsStorage, storage)
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		prefix := *pvc.Spec.StorageClassName + storageClassQuotaSuffix
+		addQuantity(usage, corev1.ResourceName(prefix+string(corev1.ResourcePersistentVolumeClaims)), count)
+		addResource(usage, corev1.ResourceName(prefix+string(corev1.ResourceRequestsStorage)), storage)
+	}
+}
+
+// addQuantity 累加整数数量
+func addQuantity(usage corev1.ResourceList, name corev1.ResourceName, count int64) {
+	addResource(usage, name, *resource.NewQuantity(count, resource.DecimalSI))
+}
+
+// addResource 累加资源数量
+func addResource(usage corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+	current := usage[name]
+	current.Add(quantity)
+	usage[name] = current
+}
+
+// formatQuotaQuantity 按配额资源对应的基础资源格式化数量
+func formatQuotaQuantity(name corev1.ResourceName, quantity resource.Quantity) string {
+	base := string(name)
+	if index := strings.Index(base, storageClassQuotaSuffix); index >= 0 {
+		base = base[index+len(storageClassQuotaSuffix):]
+	}
+	base = strings.TrimPrefix(strings.TrimPrefix(base, "requests."), "limits.")
+	return utils.FormatResourceQuantity(corev1.ResourceName(base), quantity)
+}
+
+// derefReplicas 返回副本数，未设置时为1
+func derefReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// jobConcurrency 返回Job同时运行的Pod数量：parallelism与completions中的较小值
+func jobConcurrency(spec batchv1.JobSpec) int64 {
+	parallelism := derefReplicas(spec.Parallelism)
+	if spec.Completions != nil && *spec.Completions < parallelism {
+		parallelism = *spec.Completions
+	}
+	return int64(parallelism)
+}
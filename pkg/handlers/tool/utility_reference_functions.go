@@ -0,0 +1,169 @@
+package tool
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// podSpecRefs Pod规格引用的ConfigMap、Secret和PVC名称。
+// 孤立资源查找和拓扑导出共用这里的引用解析，保证两者对"引用"的判定一致
+type podSpecRefs struct {
+	configMaps map[string]bool
+	secrets    map[string]bool
+	pvcs       map[string]bool
+}
+
+// newPodSpecRefs 创建空的引用集合
+func newPodSpecRefs() podSpecRefs {
+	return podSpecRefs{
+		configMaps: make(map[string]bool),
+		secrets:    make(map[string]bool),
+		pvcs:       make(map[string]bool),
+	}
+}
+
+// addPodSpec 记录Pod规格通过卷、投射卷、CSI及其他卷插件的Secret、环境变量和镜像拉取凭据引用的资源
+func (r podSpecRefs) addPodSpec(spec *corev1.PodSpec) {
+	for _, secret := range spec.ImagePullSecrets {
+		r.secrets[secret.Name] = true
+	}
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			r.configMaps[volume.ConfigMap.Name] = true
+		case volume.Secret != nil:
+			r.secrets[volume.Secret.SecretName] = true
+		case volume.PersistentVolumeClaim != nil:
+			r.pvcs[volume.PersistentVolumeClaim.ClaimName] = true
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					r.configMaps[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					r.secrets[source.Secret.Name] = true
+				}
+			}
+		case volume.CSI != nil && volume.CSI.NodePublishSecretRef != nil:
+			r.secrets[volume.CSI.NodePublishSecretRef.Name] = true
+		case volume.CephFS != nil && volume.CephFS.SecretRef != nil:
+			r.secrets[volume.CephFS.SecretRef.Name] = true
+		case volume.RBD != nil && volume.RBD.SecretRef != nil:
+			r.secrets[volume.RBD.SecretRef.Name] = true
+		case volume.ISCSI != nil && volume.ISCSI.SecretRef != nil:
+			r.secrets[volume.ISCSI.SecretRef.Name] = true
+		case volume.FlexVolume != nil && volume.FlexVolume.SecretRef != nil:
+			r.secrets[volume.FlexVolume.SecretRef.Name] = true
+		case volume.ScaleIO != nil && volume.ScaleIO.SecretRef != nil:
+			r.secrets[volume.ScaleIO.SecretRef.Name] = true
+		case volume.StorageOS != nil && volume.StorageOS.SecretRef != nil:
+			r.secrets[volume.StorageOS.SecretRef.Name] = true
+		case volume.AzureFile != nil:
+			r.secrets[volume.AzureFile.SecretName] = true
+		}
+	}
+	for _, container := range spec.InitContainers {
+		r.addEnv(container.EnvFrom, container.Env)
+	}
+	for _, container := range spec.Containers {
+		r.addEnv(container.EnvFrom, container.Env)
+	}
+	for _, container := range spec.EphemeralContainers {
+		r.addEnv(container.EnvFrom, container.Env)
+	}
+}
+
+// addEnv 记录envFrom和env.valueFrom引用的ConfigMap和Secret
+func (r podSpecRefs) addEnv(envFrom []corev1.EnvFromSource, env []corev1.EnvVar) {
+	for _, source := range envFrom {
+		if source.ConfigMapRef != nil {
+			r.configMaps[source.ConfigMapRef.Name] = true
+		}
+		if source.SecretRef != nil {
+			r.secrets[source.SecretRef.Name] = true
+		}
+	}
+	for _, variable := range env {
+		if variable.ValueFrom == nil {
+			continue
+		}
+		if variable.ValueFrom.ConfigMapKeyRef != nil {
+			r.configMaps[variable.ValueFrom.ConfigMapKeyRef.Name] = true
+		}
+		if variable.ValueFrom.SecretKeyRef != nil {
+			r.secrets[variable.ValueFrom.SecretKeyRef.Name] = true
+		}
+	}
+}
+
+// ingressSecretRefs 返回Ingress通过TLS和注解引用的Secret名称
+func ingressSecretRefs(ingress *networkingv1.Ingress) []string {
+	var names []string
+	for _, tls := range ingress.Spec.TLS {
+		if tls.SecretName != "" {
+			names = append(names, tls.SecretName)
+		}
+	}
+	// 入口控制器常通过注解引用认证或证书Secret，值可能带命名空间前缀
+	for key, value := range ingress.Annotations {
+		if strings.Contains(strings.ToLower(key), "secret") {
+			names = append(names, value, strings.TrimPrefix(value, ingress.Namespace+"/"))
+		}
+	}
+	return names
+}
+
+// ingressBackendServices 返回Ingress默认后端和各规则路径指向的Service名称，按出现顺序去重
+func ingressBackendServices(ingress *networkingv1.Ingress) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(backend *networkingv1.IngressBackend) {
+		if backend == nil || backend.Service == nil || seen[backend.Service.Name] {
+			return
+		}
+		seen[backend.Service.Name] = true
+		names = append(names, backend.Service.Name)
+	}
+	add(ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			add(&rule.HTTP.Paths[i].Backend)
+		}
+	}
+	return names
+}
+
+// statefulSetClaimOwner 返回由volumeClaimTemplates创建该PVC的StatefulSet，以及对应序号是否在当前副本范围内
+func statefulSetClaimOwner(claim string, statefulSets []appsv1.StatefulSet) (string, bool) {
+	for i := range statefulSets {
+		statefulSet := &statefulSets[i]
+		for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+			prefix := template.Name + "-" + statefulSet.Name + "-"
+			suffix, ok := strings.CutPrefix(claim, prefix)
+			if !ok {
+				continue
+			}
+			var ordinal int32
+			if _, err := fmt.Sscanf(suffix, "%d", &ordinal); err != nil || fmt.Sprint(ordinal) != suffix {
+				continue
+			}
+			start := int32(0)
+			if statefulSet.Spec.Ordinals != nil {
+				start = statefulSet.Spec.Ordinals.Start
+			}
+			replicas := int32(1)
+			if statefulSet.Spec.Replicas != nil {
+				replicas = *statefulSet.Spec.Replicas
+			}
+			return statefulSet.Name, ordinal >= start && ordinal < start+replicas
+		}
+	}
+	return "", false
+}
@@ -2,18 +2,54 @@ package tool
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 )
 
+const (
+	// defaultSearchLimit 未指定limit时返回的最大匹配数量
+	defaultSearchLimit = 100
+	// maxSearchLimit 调用方可指定的最大匹配数量
+	maxSearchLimit = 1000
+	// searchPageSize 搜索时每次列出的资源数量，使内存中同时只保留一页资源
+	searchPageSize = 500
+	// maxSearchValueBytes 结果中匹配的注解值保留的最大字节数
+	maxSearchValueBytes = 256
+)
+
+// searchTarget 一个要搜索的资源类型
+type searchTarget struct {
+	gvr      schema.GroupVersionResource
+	resource metav1.APIResource
+}
+
+// searchCollector 逐个收集匹配的资源，达到上限后拒绝新的匹配并标记截断
+type searchCollector struct {
+	limit     int
+	items     []models.SearchResult
+	truncated bool
+}
+
+// add 收集一个匹配，已达到上限时丢弃并返回false
+func (c *searchCollector) add(item models.SearchResult) bool {
+	if len(c.items) >= c.limit {
+		c.truncated = true
+		return false
+	}
+	c.items = append(c.items, item)
+	return true
+}
+
 // SearchResources 搜索资源
 func (h *UtilityHandler) SearchResources(
 	ctx context.Context,
@@ -23,8 +59,19 @@ func (h *UtilityHandler) SearchResources(
 	query, _ := arguments["query"].(string)
 	namespacesStr, _ := arguments["namespaces"].(string)
 	kindsStr, _ := arguments["kinds"].(string)
-	matchLabels, _ := arguments["matchLabels"].(bool)
-	matchAnnotations, _ := arguments["matchAnnotations"].(bool)
+	// 未传入时按工具描述的默认值匹配标签和注解
+	matchLabels, ok := arguments["matchLabels"].(bool)
+	if !ok {
+		matchLabels = true
+	}
+	matchAnnotations, ok := arguments["matchAnnotations"].(bool)
+	if !ok {
+		matchAnnotations = true
+	}
+	limit := defaultSearchLimit
+	if value, ok := arguments["limit"].(float64); ok && value > 0 {
+		limit = min(int(value), maxSearchLimit)
+	}
 
 	h.Log.Info("Searching resources",
 		"query", query,
@@ -32,207 +79,230 @@ func (h *UtilityHandler) SearchResources(
 		"kinds", kindsStr,
 		"matchLabels", matchLabels,
 		"matchAnnotations", matchAnnotations,
+		"limit", limit,
 	)
 
-	// 解析命名空间列表
-	var namespaces []string
-	if namespacesStr != "" {
-		namespaces = strings.Split(namespacesStr, ",")
-		for i := range namespaces {
-			namespaces[i] = strings.TrimSpace(namespaces[i])
-		}
+	// 未指定命名空间或指定all时在所有命名空间中列出，每种资源只需一次列表请求
+	namespaces := utils.SplitCommaList(namespacesStr)
+	if len(namespaces) == 1 && namespaces[0] == "all" {
+		namespaces = nil
 	}
+	kinds := utils.SplitCommaList(kindsStr)
 
-	// 解析资源类型列表
-	var kinds []string
-	if kindsStr != "" {
-		kinds = strings.Split(kindsStr, ",")
-		for i := range kinds {
-			kinds[i] = strings.TrimSpace(kinds[i])
-		}
+	targets, result := h.searchTargets(kinds)
+	if result != nil {
+		return result, nil
 	}
 
-	// 如果没有指定命名空间，获取所有命名空间
-	if len(namespaces) == 0 || (len(namespaces) == 1 && namespaces[0] == "all") {
-		nsList := &corev1.NamespaceList{}
-		err := h.Client.List(ctx, nsList)
-		if err != nil {
-			h.Log.Error("Failed to list namespaces", "error", err)
-			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	// 遍历所有资源类型和命名空间，达到上限或超时后停止并返回已找到的结果
+	collector := &searchCollector{limit: limit}
+	typesSearched := 0
+	timedOut := false
+search:
+	for _, target := range targets {
+		scopes := []string{""}
+		if target.resource.Namespaced && len(namespaces) > 0 {
+			scopes = namespaces
 		}
-		namespaces = make([]string, 0, len(nsList.Items))
-		for _, ns := range nsList.Items {
-			namespaces = append(namespaces, ns.Name)
+		for _, namespace := range scopes {
+			if ctx.Err() != nil {
+				timedOut = true
+				break search
+			}
+			if err := h.searchResource(ctx, target, namespace, query, matchLabels, matchAnnotations, collector); err != nil {
+				h.Log.Error("Failed to search resources", "error", err, "namespace", namespace, "groupVersion", target.gvr.GroupVersion().String(), "resource", target.gvr.Resource)
+			}
+			if collector.truncated {
+				break search
+			}
 		}
+		typesSearched++
 	}
 
-	// 获取API资源列表
-	_, resourcesList, err := h.Client.GetDiscoveryClient().ServerGroupsAndResources()
-	if err != nil {
-		// 处理部分发现错误，继续使用已获取的资源
-		if !discovery.IsGroupDiscoveryFailedError(err) {
-			h.Log.Error("Failed to get API resources", "error", err)
-			return nil, fmt.Errorf("failed to get API resources: %w", err)
+	results := collector.items
+	// 按照种类和名称排序
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
 		}
-		h.Log.Warn("Partial API discovery error", "error", err)
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	// 创建完整的搜索结果模型
+	searchResults := models.SearchResults{
+		Items:       results,
+		SearchQuery: query,
+		TotalCount:  len(results),
+		TypesCount:  typesSearched,
+		Limit:       limit,
+		Truncated:   collector.truncated,
+	}
+	if searchResults.Items == nil {
+		searchResults.Items = []models.SearchResult{}
+	}
+	if timedOut {
+		h.Log.Warn("Search timed out, returning partial results",
+			"query", query,
+			"typesSearched", typesSearched,
+			"matches", len(results),
+		)
+		return utils.NewTimeoutResult(ctx, searchResults), nil
 	}
 
-	// 根据请求筛选需要搜索的资源类型
-	matchingResourcesList := make(map[string][]metav1.APIResource)
-	for _, resList := range resourcesList {
-		for _, res := range resList.APIResources {
-			// 跳过子资源
-			if strings.Contains(res.Name, "/") {
-				continue
-			}
-			// 检查是否有list权限
-			if !hasListVerb(res.Verbs) {
-				continue
+	return utils.RenderResult(request, searchResults), nil
+}
+
+// searchTargets 返回要搜索的资源类型，按API组版本和资源名排序，使达到上限时的结果稳定。
+// 指定了kinds时通过RESTMapper解析，每个API组只搜索首选版本；否则搜索Discovery中所有可列出的资源
+func (h *UtilityHandler) searchTargets(kinds []string) ([]searchTarget, *mcp.CallToolResult) {
+	var targets []searchTarget
+	if len(kinds) > 0 {
+		seen := make(map[schema.GroupVersionResource]bool)
+		for _, k := range kinds {
+			mappings, err := utils.ResolveKind(h.Client, k)
+			if err != nil {
+				h.Log.Error("Failed to resolve kind", "kind", k, "error", err)
+				return nil, utils.NewKubeErrorResult(err)
 			}
-			// 如果指定了kinds，则只搜索指定的kinds
-			if len(kinds) > 0 {
-				found := false
-				for _, k := range kinds {
-					if strings.EqualFold(res.Kind, k) {
-						found = true
-						break
-					}
-				}
-				if !found {
+			for _, mapping := range mappings {
+				if seen[mapping.Resource] {
 					continue
 				}
+				seen[mapping.Resource] = true
+				targets = append(targets, searchTarget{
+					gvr: mapping.Resource,
+					resource: metav1.APIResource{
+						Name:       mapping.Resource.Resource,
+						Kind:       mapping.GroupVersionKind.Kind,
+						Namespaced: utils.IsNamespacedMapping(mapping),
+					},
+				})
 			}
-			matchingResourcesList[resList.GroupVersion] = append(matchingResourcesList[resList.GroupVersion], res)
 		}
-	}
-
-	// 使用models.SearchResult替代本地定义的结构体
-	var results []models.SearchResult
-
-	// 遍历所有资源类型和命名空间，查找匹配的资源
-	totalSearched := 0
-	for groupVersion, resources := range matchingResourcesList {
-		for _, resource := range resources {
-			// 检查资源作用域
-			isNamespaced := resource.Namespaced
-
-			// 对于非命名空间资源，只搜索全局范围
-			if !isNamespaced {
-				rs, err := searchResourcesInNamespace(ctx, h, groupVersion, resource, query, "", matchLabels, matchAnnotations)
-				if err != nil {
-					h.Log.Error("Failed to search resources", "error", err, "groupVersion", groupVersion, "resource", resource.Name)
-					continue
-				}
-				// 添加到结果中
-				for _, r := range rs {
-					results = append(results, models.SearchResult{
-						Kind:         r.Kind,
-						APIVersion:   r.APIVersion,
-						Name:         r.Name,
-						Namespace:    r.Namespace,
-						Labels:       r.Labels,
-						Annotations:  r.Annotations,
-						MatchedBy:    r.MatchedBy,
-						MatchedValue: r.MatchedValue,
-						CreationTime: r.CreationTime,
-					})
-				}
-				totalSearched++
+	} else {
+		_, resourcesList, err := h.Client.GetDiscoveryClient().ServerGroupsAndResources()
+		if err != nil {
+			// 处理部分发现错误，继续使用已获取的资源
+			if !discovery.IsGroupDiscoveryFailedError(err) {
+				h.Log.Error("Failed to get API resources", "error", err)
+				return nil, utils.NewKubeErrorResult(err, "failed to get API resources")
+			}
+			h.Log.Warn("Partial API discovery error", "error", err)
+		}
+		for _, resList := range resourcesList {
+			gv, err := schema.ParseGroupVersion(resList.GroupVersion)
+			if err != nil {
 				continue
 			}
-
-			// 对于命名空间资源，在所有指定的命名空间中搜索
-			for _, ns := range namespaces {
-				rs, err := searchResourcesInNamespace(ctx, h, groupVersion, resource, query, ns, matchLabels, matchAnnotations)
-				if err != nil {
-					h.Log.Error("Failed to search resources", "error", err, "namespace", ns, "groupVersion", groupVersion, "resource", resource.Name)
+			for _, res := range resList.APIResources {
+				// 跳过子资源和不能列出的资源
+				if strings.Contains(res.Name, "/") || !hasListVerb(res.Verbs) {
 					continue
 				}
-				// 添加到结果中
-				for _, r := range rs {
-					results = append(results, models.SearchResult{
-						Kind:         r.Kind,
-						APIVersion:   r.APIVersion,
-						Name:         r.Name,
-						Namespace:    r.Namespace,
-						Labels:       r.Labels,
-						Annotations:  r.Annotations,
-						MatchedBy:    r.MatchedBy,
-						MatchedValue: r.MatchedValue,
-						CreationTime: r.CreationTime,
-					})
-				}
-				totalSearched++
+				targets = append(targets, searchTarget{gvr: gv.WithResource(res.Name), resource: res})
 			}
 		}
 	}
-
-	// 构建响应
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Search Results for '%s':\n\n", query))
-	result.WriteString(fmt.Sprintf("Found %d matching resources across %d resource types\n\n", len(results), totalSearched))
-
-	// 按照种类和名称排序
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].Kind != results[j].Kind {
-			return results[i].Kind < results[j].Kind
+	sort.Slice(targets, func(i, j int) bool {
+		a, b := targets[i].gvr, targets[j].gvr
+		if a.Group != b.Group {
+			return a.Group < b.Group
 		}
-		if results[i].Namespace != results[j].Namespace {
-			return results[i].Namespace < results[j].Namespace
+		if a.Version != b.Version {
+			return a.Version < b.Version
 		}
-		return results[i].Name < results[j].Name
+		return a.Resource < b.Resource
 	})
+	return targets, nil
+}
 
-	// 按照资源类型分组显示结果
-	currentKind := ""
-	for _, res := range results {
-		if res.Kind != currentKind {
-			if currentKind != "" {
-				result.WriteString("\n")
+// searchResource 分页列出一种资源，将匹配的资源逐个交给collector，collector已满时停止
+func (h *UtilityHandler) searchResource(
+	ctx context.Context,
+	target searchTarget,
+	namespace string,
+	query string,
+	matchLabels bool,
+	matchAnnotations bool,
+	collector *searchCollector,
+) error {
+	resource := h.Client.GetDynamicClient().Resource(target.gvr).Namespace(namespace)
+	apiVersion := target.gvr.GroupVersion().String()
+	queryLower := strings.ToLower(query)
+	options := metav1.ListOptions{Limit: searchPageSize}
+	for {
+		list, err := resource.List(ctx, options)
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			item, ok := matchSearchItem(&list.Items[i], queryLower, matchLabels, matchAnnotations)
+			if !ok {
+				continue
+			}
+			item.Kind = target.resource.Kind
+			item.APIVersion = apiVersion
+			if !collector.add(item) {
+				return nil
 			}
-			result.WriteString(fmt.Sprintf("== %s ==\n", res.Kind))
-			currentKind = res.Kind
 		}
-
-		if res.Namespace != "" {
-			result.WriteString(fmt.Sprintf("- %s (namespace: %s)", res.Name, res.Namespace))
-		} else {
-			result.WriteString(fmt.Sprintf("- %s (cluster-scoped)", res.Name))
+		options.Continue = list.GetContinue()
+		if options.Continue == "" {
+			return nil
 		}
+	}
+}
 
-		result.WriteString(fmt.Sprintf(", matched by: %s", res.MatchedBy))
-		result.WriteString("\n")
+// matchSearchItem 依次按名称、标签和注解匹配资源，每个资源最多产生一个结果。
+// 只为匹配的资源复制标签或匹配的注解，避免为不匹配的资源分配内存
+func matchSearchItem(obj *unstructured.Unstructured, queryLower string, matchLabels, matchAnnotations bool) (models.SearchResult, bool) {
+	result := models.SearchResult{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+	if strings.Contains(strings.ToLower(result.Name), queryLower) {
+		result.MatchedBy = "name"
+		result.MatchedValue = result.Name
+		return result, true
 	}
 
-	if len(results) == 0 {
-		result.WriteString("No resources found matching the query.\n")
+	if matchLabels {
+		labels := obj.GetLabels()
+		for k, v := range labels {
+			if strings.Contains(strings.ToLower(k), queryLower) || strings.Contains(strings.ToLower(v), queryLower) {
+				result.Labels = labels
+				result.MatchedBy = "label"
+				result.MatchedValue = fmt.Sprintf("%s=%s", k, v)
+				return result, true
+			}
+		}
 	}
 
-	// 创建完整的搜索结果模型
-	searchResults := models.SearchResults{
-		Items:       results,
-		SearchQuery: query,
-		TotalCount:  len(results),
-		TypesCount:  totalSearched,
+	if matchAnnotations {
+		for k, v := range obj.GetAnnotations() {
+			if strings.Contains(strings.ToLower(k), queryLower) || strings.Contains(strings.ToLower(v), queryLower) {
+				v = truncateSearchValue(v)
+				result.Annotations = map[string]string{k: v}
+				result.MatchedBy = "annotation"
+				result.MatchedValue = fmt.Sprintf("%s=%s", k, v)
+				return result, true
+			}
+		}
 	}
+	return models.SearchResult{}, false
+}
 
-	// 序列化为JSON
-	resultsJSON, err := json.Marshal(searchResults)
-	if err != nil {
-		h.Log.Error("Failed to marshal search results", "error", err)
-		// 继续执行，只返回文本格式
-	} else {
-		// 添加JSON格式数据
-		result.WriteString("\nJSON格式数据:\n")
-		result.WriteString(string(resultsJSON))
-	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: result.String(),
-			},
-		},
-	}, nil
+// truncateSearchValue 截断过长的注解值，例如kubectl.kubernetes.io/last-applied-configuration
+func truncateSearchValue(value string) string {
+	if len(value) <= maxSearchValueBytes {
+		return value
+	}
+	cut := maxSearchValueBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + "..."
 }
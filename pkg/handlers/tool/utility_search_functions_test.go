@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"fmt"
+	goruntime "runtime"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+// searchConfigMaps 返回count个名称为settings-N的ConfigMap，每个带有size字节的注解
+func searchConfigMaps(count, size int) []runtime.Object {
+	objects := make([]runtime.Object, 0, count)
+	for i := range count {
+		objects = append(objects, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("settings-%05d", i),
+			Namespace:   testutil.DefaultNamespace,
+			Annotations: map[string]string{"example.com/blob": strings.Repeat("x", size)},
+		}})
+	}
+	return objects
+}
+
+func TestSearchResourcesLimit(t *testing.T) {
+	client := testutil.NewFakeClient(searchConfigMaps(150, 0)...)
+
+	tests := []struct {
+		name      string
+		limit     any
+		wantLimit int
+		wantCount int
+		truncated bool
+	}{
+		{name: "default limit", wantLimit: defaultSearchLimit, wantCount: defaultSearchLimit, truncated: true},
+		{name: "small limit", limit: 5, wantLimit: 5, wantCount: 5, truncated: true},
+		{name: "limit above the maximum", limit: 5000, wantLimit: maxSearchLimit, wantCount: 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]any{"query": "settings", "kinds": "ConfigMap"}
+			if tt.limit != nil {
+				arguments["limit"] = tt.limit
+			}
+			var response models.SearchResults
+			callUtility(t, client, SEARCH_RESOURCES, arguments, &response)
+
+			if response.Limit != tt.wantLimit || response.TotalCount != tt.wantCount || len(response.Items) != tt.wantCount || response.Truncated != tt.truncated {
+				t.Fatalf("limit %d, count %d, truncated %v; want %d, %d, %v",
+					response.Limit, response.TotalCount, response.Truncated, tt.wantLimit, tt.wantCount, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestSearchResourcesMatches(t *testing.T) {
+	client := testutil.NewFakeClient(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "default", Labels: map[string]string{"team": "checkout"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default", Labels: map[string]string{"app": "payments", "tier": "backend"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "routes", Namespace: "default", Annotations: map[string]string{
+			"owner": "team-a",
+			"notes": "payments " + strings.Repeat("é", maxSearchValueBytes),
+		}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"}},
+	)
+
+	var response models.SearchResults
+	callUtility(t, client, SEARCH_RESOURCES, map[string]any{"query": "PAYMENTS", "kinds": "ConfigMap"}, &response)
+	if response.TotalCount != 3 {
+		t.Fatalf("matches = %+v, want three", response.Items)
+	}
+	byName := make(map[string]models.SearchResult)
+	for _, item := range response.Items {
+		byName[item.Name] = item
+	}
+	if item := byName["payments"]; item.MatchedBy != "name" || item.Labels != nil {
+		t.Errorf("name match = %+v, want no labels copied", item)
+	}
+	if item := byName["settings"]; item.MatchedBy != "label" || item.MatchedValue != "app=payments" || len(item.Labels) != 2 {
+		t.Errorf("label match = %+v", item)
+	}
+	item := byName["routes"]
+	if item.MatchedBy != "annotation" || len(item.Annotations) != 1 {
+		t.Fatalf("annotation match = %+v, want only the matched annotation", item)
+	}
+	if value := item.Annotations["notes"]; len(value) > maxSearchValueBytes+len("...") || !utf8.ValidString(value) {
+		t.Errorf("annotation value is %d bytes or not valid UTF-8", len(value))
+	}
+
+	callUtility(t, client, SEARCH_RESOURCES, map[string]any{"query": "payments", "kinds": "ConfigMap", "matchLabels": false, "matchAnnotations": false}, &response)
+	if response.TotalCount != 1 || response.Items[0].Name != "payments" {
+		t.Fatalf("name-only matches = %+v", response.Items)
+	}
+}
+
+// BenchmarkSearchResources 在5万个带有2KB注解的ConfigMap中搜索：
+// name约1万个名称匹配，annotation每个对象的注解都匹配，两者都在达到上限后截断
+func BenchmarkSearchResources(b *testing.B) {
+	objects := append(searchConfigMaps(50000, 2048), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testutil.DefaultNamespace}})
+	handler := NewUtilityHandler(testutil.NewFakeClient(objects...)).(*UtilityHandler)
+
+	for _, query := range []struct{ name, query string }{
+		{name: "name", query: "settings-4"},
+		{name: "annotation", query: "xxxx"},
+	} {
+		b.Run(query.name, func(b *testing.B) {
+			request := testutil.NewToolRequest(SEARCH_RESOURCES, map[string]any{"query": query.query, "kinds": "ConfigMap"})
+			b.ReportAllocs()
+			defer trackPeakHeap(b)()
+			for b.Loop() {
+				result, err := handler.SearchResources(b.Context(), request)
+				if err != nil || result.IsError {
+					b.Fatalf("search failed: %v %s", err, testutil.ResultText(result))
+				}
+			}
+		})
+	}
+}
+
+// trackPeakHeap 定期采样堆大小，返回的函数停止采样并以peak-heap-B报告搜索期间堆相对开始时增长的峰值
+func trackPeakHeap(b *testing.B) func() {
+	goruntime.GC()
+	var stats goruntime.MemStats
+	goruntime.ReadMemStats(&stats)
+	base, peak := stats.HeapAlloc, stats.HeapAlloc
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				var stats goruntime.MemStats
+				goruntime.ReadMemStats(&stats)
+				peak = max(peak, stats.HeapAlloc)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-sampled
+		b.ReportMetric(float64(peak-base), "peak-heap-B")
+	}
+}
@@ -0,0 +1,339 @@
+package tool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// snapshotDefaultSkipKinds 快照时默认跳过的资源类型，这些对象由集群持续更新，会在每次比较时产生噪音
+var snapshotDefaultSkipKinds = map[string]bool{
+	"event":         true,
+	"endpointslice": true,
+	"endpoints":     true,
+	"lease":         true,
+}
+
+const (
+	// 快照对象哈希保留的十六进制字符数
+	snapshotHashLength = 16
+	// 比较快照时每个对象最多返回的字段级差异数量
+	maxSnapshotFieldChanges = 50
+)
+
+// snapshotScan 一次命名空间扫描的结果
+type snapshotScan struct {
+	objects      map[string]utils.SnapshotObject
+	countsByKind map[string]int
+	skippedKinds []string
+	// failedKinds 无法列出的资源类型，键为"group/Kind"
+	failedKinds   map[string]bool
+	errors        []string
+	contentStored bool
+}
+
+// SnapshotNamespace 记录命名空间中每个对象清理后内容的哈希，保存在服务器内存中供DIFF_SNAPSHOT比较
+func (h *UtilityHandler) SnapshotNamespace(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	includeKinds, _ := arguments["includeKinds"].(string)
+	excludeKinds, _ := arguments["excludeKinds"].(string)
+	includeOwned, _ := arguments["includeOwned"].(bool)
+	ttl := utils.DefaultSnapshotTTL
+	if value, ok := arguments["ttlSeconds"].(float64); ok && value > 0 {
+		ttl = min(time.Duration(value)*time.Second, utils.MaxSnapshotTTL)
+	}
+
+	h.Log.Info("Taking namespace snapshot",
+		"namespace", namespace,
+		"includeKinds", includeKinds,
+		"excludeKinds", excludeKinds,
+		"includeOwned", includeOwned,
+		"ttl", ttl,
+	)
+
+	if namespace == "" {
+		return utils.NewErrorToolResult("namespace is required"), nil
+	}
+
+	scan, result := h.scanForSnapshot(ctx, namespace, includeKinds, excludeKinds, includeOwned)
+	if result != nil {
+		return result, nil
+	}
+
+	now := time.Now()
+	snapshot := &utils.Snapshot{
+		Namespace:     namespace,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(ttl),
+		Objects:       scan.objects,
+		ContentStored: scan.contentStored,
+		IncludeKinds:  includeKinds,
+		ExcludeKinds:  excludeKinds,
+		IncludeOwned:  includeOwned,
+	}
+	evicted := utils.Snapshots().Save(snapshot)
+
+	response := models.NamespaceSnapshotResult{
+		SnapshotID:    snapshot.ID,
+		Namespace:     namespace,
+		CreatedAt:     snapshot.CreatedAt,
+		ExpiresAt:     snapshot.ExpiresAt,
+		ObjectCount:   len(scan.objects),
+		CountsByKind:  scan.countsByKind,
+		ContentStored: scan.contentStored,
+		SkippedKinds:  scan.skippedKinds,
+		Evicted:       evicted,
+		Errors:        scan.errors,
+	}
+	if !scan.contentStored {
+		response.Note = fmt.Sprintf("object contents exceed %d bytes and were not stored; DIFF_SNAPSHOT reports changed objects without field-level diffs", utils.MaxSnapshotContentBytes)
+	}
+
+	h.Log.Info("Namespace snapshot taken", "snapshotId", snapshot.ID, "namespace", namespace, "objects", response.ObjectCount)
+	return utils.RenderResult(request, response), nil
+}
+
+// DiffSnapshot 按快照的扫描条件重新扫描命名空间，报告新增、删除和内容变化的对象
+func (h *UtilityHandler) DiffSnapshot(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	snapshotID, _ := arguments["snapshotId"].(string)
+	fieldDiffs := true
+	if value, ok := arguments["fieldDiffs"].(bool); ok {
+		fieldDiffs = value
+	}
+
+	h.Log.Info("Diffing namespace snapshot", "snapshotId", snapshotID, "fieldDiffs", fieldDiffs)
+
+	if snapshotID == "" {
+		return utils.NewErrorToolResult("snapshotId is required"), nil
+	}
+	snapshot, err := utils.Snapshots().Get(snapshotID)
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeNotFound,
+			Message: err.Error(),
+			Hint:    "Snapshots are kept in server memory until their TTL expires or the server restarts; take a new snapshot with SNAPSHOT_NAMESPACE.",
+			Details: map[string]any{"snapshots": utils.Snapshots().IDs()},
+		}), nil
+	}
+
+	scan, result := h.scanForSnapshot(ctx, snapshot.Namespace, snapshot.IncludeKinds, snapshot.ExcludeKinds, snapshot.IncludeOwned)
+	if result != nil {
+		return result, nil
+	}
+
+	response := models.SnapshotDiffResult{
+		SnapshotID: snapshot.ID,
+		Namespace:  snapshot.Namespace,
+		SnapshotAt: snapshot.CreatedAt,
+		ComparedAt: time.Now(),
+		Added:      []models.SnapshotObjectRef{},
+		Removed:    []models.SnapshotObjectRef{},
+		Changed:    []models.SnapshotObjectChange{},
+		Errors:     scan.errors,
+	}
+	var notes []string
+	if fieldDiffs && !snapshot.ContentStored {
+		notes = append(notes, "the snapshot did not store object contents, field-level diffs are unavailable")
+	}
+
+	for key, current := range scan.objects {
+		previous, ok := snapshot.Objects[key]
+		if !ok {
+			response.Added = append(response.Added, snapshotObjectRef(current))
+			continue
+		}
+		if previous.Hash == current.Hash {
+			response.Unchanged++
+			continue
+		}
+		change := models.SnapshotObjectChange{
+			SnapshotObjectRef: snapshotObjectRef(current),
+			OldHash:           previous.Hash,
+			NewHash:           current.Hash,
+		}
+		if fieldDiffs && previous.Content != nil {
+			diffFields("", previous.Content, current.Content, &change.Changes)
+			if len(change.Changes) > maxSnapshotFieldChanges {
+				change.Changes = change.Changes[:maxSnapshotFieldChanges]
+				notes = append(notes, fmt.Sprintf("field-level diffs of %s/%s are limited to %d changes", change.Kind, change.Name, maxSnapshotFieldChanges))
+			}
+		}
+		response.Changed = append(response.Changed, change)
+	}
+	for key, previous := range snapshot.Objects {
+		if _, ok := scan.objects[key]; ok {
+			continue
+		}
+		// 本次无法列出的类型不能判断对象是否已删除
+		if scan.failedKinds[snapshotKindKey(key)] {
+			continue
+		}
+		response.Removed = append(response.Removed, snapshotObjectRef(previous))
+	}
+
+	sortSnapshotRefs(response.Added)
+	sortSnapshotRefs(response.Removed)
+	sort.Slice(response.Changed, func(i, j int) bool {
+		return snapshotRefLess(response.Changed[i].SnapshotObjectRef, response.Changed[j].SnapshotObjectRef)
+	})
+	response.Note = strings.Join(notes, "; ")
+
+	return utils.RenderResult(request, response), nil
+}
+
+// scanForSnapshot 列出命名空间中符合条件的对象并计算内容哈希，失败时返回错误结果
+func (h *UtilityHandler) scanForSnapshot(
+	ctx context.Context,
+	namespace, includeKindsArg, excludeKindsArg string,
+	includeOwned bool,
+) (*snapshotScan, *mcp.CallToolResult) {
+	includeKinds := parseKindSet(includeKindsArg)
+	excludeKinds := parseKindSet(excludeKindsArg)
+
+	resourceLists, err := h.Client.GetDiscoveryClient().ServerPreferredNamespacedResources()
+	if err != nil {
+		// 部分API组不可用时继续扫描其余资源
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			h.Log.Error("Failed to discover namespaced resources", "error", err)
+			return nil, utils.NewKubeErrorResult(err, "failed to discover namespaced resources")
+		}
+		h.Log.Warn("Partial API discovery error", "error", err)
+	}
+
+	scan := &snapshotScan{
+		objects:       make(map[string]utils.SnapshotObject),
+		countsByKind:  make(map[string]int),
+		failedKinds:   make(map[string]bool),
+		contentStored: true,
+	}
+	skippedKinds := make(map[string]bool)
+	contentBytes := 0
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !hasListVerb(resource.Verbs) {
+				continue
+			}
+			kindKey := strings.ToLower(resource.Kind)
+			if len(includeKinds) > 0 && !includeKinds[kindKey] {
+				continue
+			}
+			if excludeKinds[kindKey] || (len(includeKinds) == 0 && snapshotDefaultSkipKinds[kindKey]) {
+				skippedKinds[resource.Kind] = true
+				continue
+			}
+
+			list, err := h.Client.GetDynamicClient().Resource(gv.WithResource(resource.Name)).
+				Namespace(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, utils.NewKubeErrorResult(ctx.Err(), "namespace scan interrupted")
+				}
+				h.Log.Warn("Failed to list resources for snapshot", "resource", resource.Name, "error", err)
+				scan.errors = append(scan.errors, fmt.Sprintf("%s: %v", resource.Name, err))
+				scan.failedKinds[gv.Group+"/"+resource.Kind] = true
+				continue
+			}
+
+			for i := range list.Items {
+				obj := &list.Items[i]
+				if isGeneratedObject(obj, includeOwned) {
+					continue
+				}
+				if len(scan.objects) >= utils.MaxSnapshotObjects {
+					return nil, utils.NewToolErrorResult(models.ToolError{
+						Code:    utils.ErrorCodeRefused,
+						Message: fmt.Sprintf("namespace %s has more than %d objects", namespace, utils.MaxSnapshotObjects),
+						Hint:    "Narrow the snapshot with includeKinds or excludeKinds.",
+					})
+				}
+
+				cleanObject(obj)
+				// Secret只保存值的长度和指纹，值的变化仍会改变哈希
+				utils.FingerprintSecret(obj)
+				data, err := json.Marshal(obj.Object)
+				if err != nil {
+					scan.errors = append(scan.errors, fmt.Sprintf("%s/%s: %v", resource.Kind, obj.GetName(), err))
+					continue
+				}
+				sum := sha256.Sum256(data)
+				object := utils.SnapshotObject{
+					Kind:       resource.Kind,
+					APIVersion: gv.String(),
+					Name:       obj.GetName(),
+					Hash:       hex.EncodeToString(sum[:])[:snapshotHashLength],
+				}
+				contentBytes += len(data)
+				if contentBytes <= utils.MaxSnapshotContentBytes {
+					object.Content = obj.Object
+				} else {
+					scan.contentStored = false
+				}
+				scan.objects[gv.Group+"/"+resource.Kind+"/"+obj.GetName()] = object
+				scan.countsByKind[resource.Kind]++
+			}
+		}
+	}
+
+	// 内容只保存了一部分时全部丢弃，避免只对部分对象返回字段级差异
+	if !scan.contentStored {
+		for key, object := range scan.objects {
+			object.Content = nil
+			scan.objects[key] = object
+		}
+	}
+	for kind := range skippedKinds {
+		scan.skippedKinds = append(scan.skippedKinds, kind)
+	}
+	sort.Strings(scan.skippedKinds)
+	return scan, nil
+}
+
+// snapshotKindKey 从"group/Kind/name"形式的键中取出"group/Kind"
+func snapshotKindKey(key string) string {
+	return key[:strings.LastIndex(key, "/")]
+}
+
+// snapshotObjectRef 返回快照对象的引用
+func snapshotObjectRef(object utils.SnapshotObject) models.SnapshotObjectRef {
+	return models.SnapshotObjectRef{Kind: object.Kind, APIVersion: object.APIVersion, Name: object.Name}
+}
+
+// snapshotRefLess 按类型和名称排序
+func snapshotRefLess(a, b models.SnapshotObjectRef) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	return a.Name < b.Name
+}
+
+// sortSnapshotRefs 按类型和名称排序对象引用
+func sortSnapshotRefs(refs []models.SnapshotObjectRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		return snapshotRefLess(refs[i], refs[j])
+	})
+}
@@ -0,0 +1,115 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/base"
+	"github.com/hsn0918/kubernetes-mcp/pkg/middlewares"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+	"github.com/hsn0918/kubernetes-mcp/pkg/version"
+)
+
+// statusProbeTimeout 连通性检查的超时时间
+const statusProbeTimeout = 2 * time.Second
+
+// serverStartTime 服务器启动时间，用于计算运行时长
+var serverStartTime = time.Now()
+
+// GetServerStatus 获取服务器构建信息、配置、集群连通性、并发限制状态、缓存和调用统计
+func (h *UtilityHandler) GetServerStatus(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	h.Log.Info("Getting server status")
+
+	stats := h.Client.CacheStats()
+	status := models.ServerStatus{
+		Build: models.BuildInfo{
+			Version:   version.Version,
+			Commit:    version.Commit,
+			BuildDate: version.BuildDate,
+			GoVersion: version.GoVersion(),
+		},
+		StartedAt:   serverStartTime.Format(time.RFC3339),
+		Uptime:      time.Since(serverStartTime).Round(time.Second).String(),
+		Settings:    base.GetOptions().Settings,
+		Cluster:     h.clusterConnection(ctx),
+		Concurrency: middlewares.ConcurrencyStatus(),
+		Cache: models.CacheStatus{
+			DiscoveryHits:   stats.DiscoveryHits,
+			DiscoveryMisses: stats.DiscoveryMisses,
+			NamespaceHits:   stats.NamespaceHits,
+			NamespaceMisses: stats.NamespaceMisses,
+			Entries:         cacheEntryStatuses(stats.Entries),
+		},
+		ToolCalls:       middlewares.ToolCallStats(),
+		RegisteredTools: base.RegisteredTools(),
+	}
+	if !stats.DiscoveryLoadedAt.IsZero() {
+		status.Cache.DiscoveryAge = time.Since(stats.DiscoveryLoadedAt).Round(time.Second).String()
+	}
+	if includeNotes, _ := request.GetArguments()["includeNotes"].(bool); includeNotes {
+		status.Notes = utils.Notes().List(noteSession(ctx))
+	}
+
+	return utils.RenderResult(request, status), nil
+}
+
+// clusterConnection 返回当前连接的集群，并通过/version请求检查连通性
+func (h *UtilityHandler) clusterConnection(ctx context.Context) models.ClusterConnection {
+	connection := models.ClusterConnection{}
+
+	// 从kubeconfig读取当前上下文和集群地址，使用集群内配置时GetConfig返回nil
+	if clientConfig := h.Client.GetConfig(); clientConfig != nil {
+		if rawConfig, err := clientConfig.RawConfig(); err == nil {
+			connection.Context = rawConfig.CurrentContext
+			if kubeContext, ok := rawConfig.Contexts[rawConfig.CurrentContext]; ok {
+				connection.ClusterName = kubeContext.Cluster
+				if cluster, ok := rawConfig.Clusters[kubeContext.Cluster]; ok {
+					connection.Server = redactServerURL(cluster.Server)
+				}
+			}
+		}
+	} else {
+		connection.InCluster = true
+	}
+
+	restClient := h.Client.ClientSet().Discovery().RESTClient()
+	if connection.Server == "" {
+		connection.Server = redactServerURL(restClient.Get().URL().String())
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, statusProbeTimeout)
+	defer cancel()
+	start := time.Now()
+	body, err := restClient.Get().AbsPath("/version").Do(probeCtx).Raw()
+	connection.Latency = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		h.Log.Warn("Cluster connectivity check failed", "error", err)
+		connection.Error = err.Error()
+		return connection
+	}
+
+	var info k8sversion.Info
+	if err := json.Unmarshal(body, &info); err == nil {
+		connection.ServerVersion = info.GitVersion
+	}
+	connection.Reachable = true
+	return connection
+}
+
+// redactServerURL 去掉地址中的用户信息、路径和查询参数，只保留协议和主机
+func redactServerURL(server string) string {
+	parsed, err := url.Parse(server)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
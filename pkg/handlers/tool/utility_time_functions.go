@@ -2,10 +2,12 @@ package tool
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
 )
 
 func (h *UtilityHandler) GetCurrentTime(
@@ -15,15 +17,13 @@ func (h *UtilityHandler) GetCurrentTime(
 	h.Log.Info("Getting current time")
 
 	// 获取当前时间
-	currentTime := time.Now().Format(time.RFC3339)
+	now := time.Now()
+	zone, _ := now.Zone()
 
 	// 构建响应
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			mcp.TextContent{
-				Type: "text",
-				Text: fmt.Sprintf("Current Time: %s", currentTime),
-			},
-		},
-	}, nil
+	return utils.RenderResult(request, models.CurrentTime{
+		Time:     now.Format(time.RFC3339),
+		Timezone: zone,
+		Unix:     now.Unix(),
+	}), nil
 }
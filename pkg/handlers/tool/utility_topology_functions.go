@@ -0,0 +1,778 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 拓扑图的文本格式
+const (
+	topologyFormatMermaid = "mermaid"
+	topologyFormatDOT     = "dot"
+)
+
+// 拓扑导出支持的资源类型
+const (
+	topologyKindIngresses    = "ingresses"
+	topologyKindServices     = "services"
+	topologyKindDeployments  = "deployments"
+	topologyKindStatefulSets = "statefulsets"
+	topologyKindDaemonSets   = "daemonsets"
+	topologyKindCronJobs     = "cronjobs"
+	topologyKindJobs         = "jobs"
+	topologyKindPods         = "pods"
+	topologyKindConfigMaps   = "configmaps"
+	topologyKindSecrets      = "secrets"
+	topologyKindPVCs         = "pvcs"
+)
+
+// topologyKinds 支持的资源类型，顺序即超出节点上限时的保留优先级
+var topologyKinds = []string{
+	topologyKindIngresses,
+	topologyKindServices,
+	topologyKindDeployments,
+	topologyKindStatefulSets,
+	topologyKindDaemonSets,
+	topologyKindCronJobs,
+	topologyKindJobs,
+	topologyKindPods,
+	topologyKindConfigMaps,
+	topologyKindSecrets,
+	topologyKindPVCs,
+}
+
+const (
+	defaultTopologyMaxNodes = 100
+	maxTopologyMaxNodes     = 500
+)
+
+// topologyWorkload 拓扑中的一个工作负载
+type topologyWorkload struct {
+	kind     string
+	name     string
+	status   string
+	detail   string
+	labels   map[string]string
+	template *corev1.PodTemplateSpec
+	// cronJob 创建该Job的CronJob，仅用于Job
+	cronJob string
+	// statefulSet 用于匹配volumeClaimTemplates创建的PVC，仅用于StatefulSet
+	statefulSet *appsv1.StatefulSet
+	pods        int
+	readyPods   int
+}
+
+// topologyGraph 按节点上限构建的图
+type topologyGraph struct {
+	maxNodes int
+	nodes    []models.TopologyNode
+	edges    []models.TopologyEdge
+	ids      map[string]string
+	seen     map[string]bool
+	omitted  int
+}
+
+// node 添加节点并返回其ID，节点已存在时直接返回ID，超出上限时返回空字符串
+func (g *topologyGraph) node(kind, name, status, detail string) string {
+	key := kind + "/" + name
+	if id, ok := g.ids[key]; ok {
+		return id
+	}
+	if len(g.nodes) >= g.maxNodes {
+		if !g.seen[key] {
+			g.seen[key] = true
+			g.omitted++
+		}
+		return ""
+	}
+	id := fmt.Sprintf("n%d", len(g.nodes)+1)
+	g.ids[key] = id
+	g.nodes = append(g.nodes, models.TopologyNode{ID: id, Kind: kind, Name: name, Status: status, Detail: detail})
+	return id
+}
+
+// lookup 返回已在图中的节点ID，不存在时返回空字符串
+func (g *topologyGraph) lookup(kind, name string) string {
+	return g.ids[kind+"/"+name]
+}
+
+// edge 添加边，任一端点不在图中时忽略
+func (g *topologyGraph) edge(from, to, label string) {
+	if from == "" || to == "" {
+		return
+	}
+	key := from + "->" + to + ":" + label
+	if g.seen[key] {
+		return
+	}
+	g.seen[key] = true
+	g.edges = append(g.edges, models.TopologyEdge{From: from, To: to, Label: label})
+}
+
+// ExportTopology 构建命名空间中工作负载、Service、Ingress及其引用资源的关系图，以Mermaid或DOT文本导出
+func (h *UtilityHandler) ExportTopology(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	namespace, _ := arguments["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	graphFormat, _ := arguments["graphFormat"].(string)
+	graphFormat = strings.ToLower(strings.TrimSpace(graphFormat))
+	if graphFormat == "" {
+		graphFormat = topologyFormatMermaid
+	}
+	if graphFormat != topologyFormatMermaid && graphFormat != topologyFormatDOT {
+		return utils.NewErrorToolResult(fmt.Sprintf("unsupported graphFormat %q, must be one of: %s, %s", graphFormat, topologyFormatMermaid, topologyFormatDOT)), nil
+	}
+	kindsStr, _ := arguments["kinds"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	maxNodes := defaultTopologyMaxNodes
+	if value, ok := arguments["maxNodes"].(float64); ok && value > 0 {
+		maxNodes = min(int(value), maxTopologyMaxNodes)
+	}
+
+	h.Log.Info("Exporting topology",
+		"namespace", namespace,
+		"graphFormat", graphFormat,
+		"kinds", kindsStr,
+		"labelSelector", labelSelector,
+		"maxNodes", maxNodes,
+	)
+
+	kinds := make(map[string]bool)
+	for _, kind := range utils.SplitCommaList(strings.ToLower(kindsStr)) {
+		if !slices.Contains(topologyKinds, kind) {
+			return utils.NewErrorToolResult(fmt.Sprintf("unsupported kind %q, must be one of: %s", kind, strings.Join(topologyKinds, ", "))), nil
+		}
+		kinds[kind] = true
+	}
+	if len(kinds) == 0 {
+		for _, kind := range topologyKinds {
+			kinds[kind] = true
+		}
+	}
+	selector := labels.Everything()
+	if labelSelector != "" {
+		if selector, err = labels.Parse(labelSelector); err != nil {
+			return utils.NewErrorToolResult(fmt.Sprintf("invalid labelSelector %q: %v", labelSelector, err)), nil
+		}
+	}
+
+	export := &models.TopologyExport{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		GraphFormat:   graphFormat,
+		RetrievedAt:   time.Now(),
+	}
+	for _, kind := range topologyKinds {
+		if kinds[kind] {
+			export.Kinds = append(export.Kinds, kind)
+		}
+	}
+	// list 列出资源，失败时记录说明并继续，图中缺少该类型的节点
+	list := func(kind string, fn func() error) bool {
+		if err := fn(); err != nil {
+			h.Log.Warn("Failed to list resources for topology", "kind", kind, "namespace", namespace, "error", err)
+			export.Notes = append(export.Notes, fmt.Sprintf("%s could not be listed: %v", kind, err))
+			return false
+		}
+		return true
+	}
+	clientSet := h.Client.ClientSet()
+	listOptions := metav1.ListOptions{}
+
+	// --- 工作负载 ---
+	var workloads []*topologyWorkload
+	workloadByKey := make(map[string]*topologyWorkload)
+	addWorkload := func(workload *topologyWorkload) {
+		if !selector.Matches(labels.Set(workload.labels)) {
+			return
+		}
+		workloads = append(workloads, workload)
+		workloadByKey[workload.kind+"/"+workload.name] = workload
+	}
+	if kinds[topologyKindDeployments] {
+		list(topologyKindDeployments, func() error {
+			items, err := clientSet.AppsV1().Deployments(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for i := range items.Items {
+				deployment := &items.Items[i]
+				status, detail := replicaStatus(deployment.Spec.Replicas, deployment.Status.ReadyReplicas)
+				addWorkload(&topologyWorkload{kind: "Deployment", name: deployment.Name, status: status, detail: detail,
+					labels: deployment.Labels, template: &deployment.Spec.Template})
+			}
+			return nil
+		})
+	}
+	if kinds[topologyKindStatefulSets] {
+		list(topologyKindStatefulSets, func() error {
+			items, err := clientSet.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for i := range items.Items {
+				statefulSet := &items.Items[i]
+				status, detail := replicaStatus(statefulSet.Spec.Replicas, statefulSet.Status.ReadyReplicas)
+				addWorkload(&topologyWorkload{kind: "StatefulSet", name: statefulSet.Name, status: status, detail: detail,
+					labels: statefulSet.Labels, template: &statefulSet.Spec.Template, statefulSet: statefulSet})
+			}
+			return nil
+		})
+	}
+	if kinds[topologyKindDaemonSets] {
+		list(topologyKindDaemonSets, func() error {
+			items, err := clientSet.AppsV1().DaemonSets(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for i := range items.Items {
+				daemonSet := &items.Items[i]
+				desired := daemonSet.Status.DesiredNumberScheduled
+				status, detail := replicaStatus(&desired, daemonSet.Status.NumberReady)
+				addWorkload(&topologyWorkload{kind: "DaemonSet", name: daemonSet.Name, status: status, detail: detail,
+					labels: daemonSet.Labels, template: &daemonSet.Spec.Template})
+			}
+			return nil
+		})
+	}
+	if kinds[topologyKindCronJobs] {
+		list(topologyKindCronJobs, func() error {
+			items, err := clientSet.BatchV1().CronJobs(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for i := range items.Items {
+				cronJob := &items.Items[i]
+				workload := &topologyWorkload{kind: "CronJob", name: cronJob.Name, status: models.TopologyStatusReady,
+					detail: "schedule " + cronJob.Spec.Schedule, labels: cronJob.Labels, template: &cronJob.Spec.JobTemplate.Spec.Template}
+				if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+					workload.status, workload.detail = models.TopologyStatusUnknown, "suspended"
+				}
+				addWorkload(workload)
+			}
+			return nil
+		})
+	}
+	if kinds[topologyKindJobs] {
+		list(topologyKindJobs, func() error {
+			items, err := clientSet.BatchV1().Jobs(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for i := range items.Items {
+				job := &items.Items[i]
+				workload := &topologyWorkload{kind: "Job", name: job.Name, labels: job.Labels, template: &job.Spec.Template}
+				workload.status, workload.detail = jobTopologyStatus(job)
+				if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+					workload.cronJob = owner.Name
+				}
+				addWorkload(workload)
+			}
+			return nil
+		})
+	}
+
+	// --- Pod按所属工作负载合并计数 ---
+	var barePods []corev1.Pod
+	if kinds[topologyKindPods] || kinds[topologyKindServices] {
+		var replicaSetOwners map[string]string
+		if kinds[topologyKindDeployments] {
+			list("replicasets", func() error {
+				items, err := clientSet.AppsV1().ReplicaSets(namespace).List(ctx, listOptions)
+				if err != nil {
+					return err
+				}
+				replicaSetOwners = make(map[string]string, len(items.Items))
+				for i := range items.Items {
+					if owner := metav1.GetControllerOf(&items.Items[i]); owner != nil && owner.Kind == "Deployment" {
+						replicaSetOwners[items.Items[i].Name] = owner.Name
+					}
+				}
+				return nil
+			})
+		}
+		list(topologyKindPods, func() error {
+			items, err := clientSet.CoreV1().Pods(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for i := range items.Items {
+				pod := &items.Items[i]
+				owner := metav1.GetControllerOf(pod)
+				if owner == nil {
+					if selector.Matches(labels.Set(pod.Labels)) {
+						barePods = append(barePods, *pod)
+					}
+					continue
+				}
+				key := owner.Kind + "/" + owner.Name
+				if owner.Kind == "ReplicaSet" {
+					key = "Deployment/" + replicaSetOwners[owner.Name]
+				}
+				if workload := workloadByKey[key]; workload != nil {
+					workload.pods++
+					if topologyPodReady(pod) {
+						workload.readyPods++
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	// --- Service、Ingress和被引用的资源 ---
+	var services []corev1.Service
+	serviceExists := make(map[string]bool)
+	readyEndpoints := make(map[string]int)
+	if kinds[topologyKindServices] {
+		list(topologyKindServices, func() error {
+			items, err := clientSet.CoreV1().Services(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			services = items.Items
+			for _, service := range services {
+				serviceExists[service.Name] = true
+			}
+			return nil
+		})
+		list("endpointslices", func() error {
+			items, err := clientSet.DiscoveryV1().EndpointSlices(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, slice := range items.Items {
+				service := slice.Labels[discoveryv1.LabelServiceName]
+				for _, endpoint := range slice.Endpoints {
+					if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+						readyEndpoints[service] += len(endpoint.Addresses)
+					}
+				}
+			}
+			return nil
+		})
+	}
+	var ingresses []networkingv1.Ingress
+	if kinds[topologyKindIngresses] {
+		list(topologyKindIngresses, func() error {
+			items, err := clientSet.NetworkingV1().Ingresses(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			for _, ingress := range items.Items {
+				if selector.Matches(labels.Set(ingress.Labels)) {
+					ingresses = append(ingresses, ingress)
+				}
+			}
+			return nil
+		})
+	}
+	// existing 列出被引用类型的现有对象名称，未能列出时为nil，节点状态为unknown
+	existing := func(kind string, fn func() ([]string, error)) map[string]bool {
+		if !kinds[kind] {
+			return nil
+		}
+		var names map[string]bool
+		list(kind, func() error {
+			items, err := fn()
+			if err != nil {
+				return err
+			}
+			names = make(map[string]bool, len(items))
+			for _, name := range items {
+				names[name] = true
+			}
+			return nil
+		})
+		return names
+	}
+	configMaps := existing(topologyKindConfigMaps, func() ([]string, error) {
+		items, err := clientSet.CoreV1().ConfigMaps(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(items.Items))
+		for _, item := range items.Items {
+			names = append(names, item.Name)
+		}
+		return names, nil
+	})
+	secrets := existing(topologyKindSecrets, func() ([]string, error) {
+		items, err := clientSet.CoreV1().Secrets(namespace).List(ctx, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(items.Items))
+		for _, item := range items.Items {
+			names = append(names, item.Name)
+		}
+		return names, nil
+	})
+	var pvcs []corev1.PersistentVolumeClaim
+	if kinds[topologyKindPVCs] {
+		list(topologyKindPVCs, func() error {
+			items, err := clientSet.CoreV1().PersistentVolumeClaims(namespace).List(ctx, listOptions)
+			if err != nil {
+				return err
+			}
+			pvcs = items.Items
+			return nil
+		})
+	}
+
+	// --- 按优先级添加节点和边 ---
+	graph := &topologyGraph{maxNodes: maxNodes, ids: make(map[string]string), seen: make(map[string]bool)}
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		status, detail := models.TopologyStatusUnknown, "no address"
+		if addresses := ingressAddresses(ingress); len(addresses) > 0 {
+			status, detail = models.TopologyStatusReady, strings.Join(addresses, ", ")
+		}
+		graph.node("Ingress", ingress.Name, status, detail)
+	}
+	serviceIDs := make(map[string]string)
+	for i := range services {
+		service := &services[i]
+		if !selector.Matches(labels.Set(service.Labels)) {
+			continue
+		}
+		status, detail := serviceTopologyStatus(service, readyEndpoints)
+		serviceIDs[service.Name] = graph.node("Service", service.Name, status, detail)
+	}
+	// Ingress的后端Service即使不匹配标签选择器也加入，保证边完整
+	for i := range ingresses {
+		ingress := &ingresses[i]
+		ingressID := graph.lookup("Ingress", ingress.Name)
+		if !kinds[topologyKindServices] {
+			continue
+		}
+		for _, name := range ingressBackendServices(ingress) {
+			id, ok := serviceIDs[name]
+			if !ok {
+				status, detail := models.TopologyStatusMissing, "not found"
+				for j := range services {
+					if services[j].Name == name {
+						status, detail = serviceTopologyStatus(&services[j], readyEndpoints)
+					}
+				}
+				id = graph.node("Service", name, status, detail)
+				serviceIDs[name] = id
+			}
+			graph.edge(ingressID, id, "backend")
+		}
+	}
+	for _, workload := range workloads {
+		graph.node(workload.kind, workload.name, workload.status, workload.detail)
+	}
+	for _, workload := range workloads {
+		workloadID := graph.lookup(workload.kind, workload.name)
+		if workload.cronJob != "" {
+			graph.edge(graph.lookup("CronJob", workload.cronJob), workloadID, "owns")
+		}
+		if kinds[topologyKindPods] && workload.pods > 0 {
+			status := models.TopologyStatusReady
+			if workload.readyPods < workload.pods {
+				status = models.TopologyStatusDegraded
+			}
+			podsID := graph.node("Pods", workload.kind+"/"+workload.name, status,
+				fmt.Sprintf("%d pods, %d ready", workload.pods, workload.readyPods))
+			graph.edge(workloadID, podsID, "owns")
+		}
+	}
+	var barePodsID string
+	if kinds[topologyKindPods] && len(barePods) > 0 {
+		ready := 0
+		for i := range barePods {
+			if topologyPodReady(&barePods[i]) {
+				ready++
+			}
+		}
+		status := models.TopologyStatusReady
+		if ready < len(barePods) {
+			status = models.TopologyStatusDegraded
+		}
+		barePodsID = graph.node("Pods", "without controller", status, fmt.Sprintf("%d pods, %d ready", len(barePods), ready))
+	}
+
+	// Service选择器匹配工作负载的Pod模板或没有控制器的Pod
+	for i := range services {
+		service := &services[i]
+		serviceID := serviceIDs[service.Name]
+		if serviceID == "" || len(service.Spec.Selector) == 0 {
+			continue
+		}
+		serviceSelector := labels.SelectorFromSet(service.Spec.Selector)
+		for _, workload := range workloads {
+			if serviceSelector.Matches(labels.Set(workload.template.Labels)) {
+				graph.edge(serviceID, graph.lookup(workload.kind, workload.name), "selects")
+			}
+		}
+		for j := range barePods {
+			if serviceSelector.Matches(labels.Set(barePods[j].Labels)) {
+				graph.edge(serviceID, barePodsID, "selects")
+				break
+			}
+		}
+	}
+
+	// 工作负载引用的ConfigMap、Secret和PVC，引用解析与孤立资源查找共用
+	referenceStatus := func(names map[string]bool, name string) (string, string) {
+		switch {
+		case names == nil:
+			return models.TopologyStatusUnknown, ""
+		case names[name]:
+			return models.TopologyStatusReady, ""
+		}
+		return models.TopologyStatusMissing, "not found"
+	}
+	pvcByName := make(map[string]*corev1.PersistentVolumeClaim, len(pvcs))
+	for i := range pvcs {
+		pvcByName[pvcs[i].Name] = &pvcs[i]
+	}
+	pvcNode := func(name string) string {
+		pvc := pvcByName[name]
+		if pvc == nil {
+			return graph.node("PersistentVolumeClaim", name, models.TopologyStatusMissing, "not found")
+		}
+		status := models.TopologyStatusReady
+		if pvc.Status.Phase != corev1.ClaimBound {
+			status = models.TopologyStatusDegraded
+		}
+		detail := string(pvc.Status.Phase)
+		if size, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			detail += " " + size.String()
+		}
+		return graph.node("PersistentVolumeClaim", name, status, detail)
+	}
+	for _, workload := range workloads {
+		workloadID := graph.lookup(workload.kind, workload.name)
+		if workloadID == "" {
+			continue
+		}
+		refs := newPodSpecRefs()
+		refs.addPodSpec(&workload.template.Spec)
+		if kinds[topologyKindConfigMaps] {
+			for _, name := range sortedKeys(refs.configMaps) {
+				status, detail := referenceStatus(configMaps, name)
+				graph.edge(workloadID, graph.node("ConfigMap", name, status, detail), "uses")
+			}
+		}
+		if kinds[topologyKindSecrets] {
+			for _, name := range sortedKeys(refs.secrets) {
+				status, detail := referenceStatus(secrets, name)
+				graph.edge(workloadID, graph.node("Secret", name, status, detail), "uses")
+			}
+		}
+		if kinds[topologyKindPVCs] {
+			for _, name := range sortedKeys(refs.pvcs) {
+				graph.edge(workloadID, pvcNode(name), "mounts")
+			}
+			if workload.statefulSet != nil {
+				for _, pvc := range pvcs {
+					if owner, _ := statefulSetClaimOwner(pvc.Name, []appsv1.StatefulSet{*workload.statefulSet}); owner != "" {
+						graph.edge(workloadID, pvcNode(pvc.Name), "mounts")
+					}
+				}
+			}
+		}
+	}
+	if kinds[topologyKindSecrets] && secrets != nil {
+		for i := range ingresses {
+			ingressID := graph.lookup("Ingress", ingresses[i].Name)
+			if ingressID == "" {
+				continue
+			}
+			// 注解中的值不一定是Secret名称，只连接确实存在的Secret
+			for _, name := range ingressSecretRefs(&ingresses[i]) {
+				if secrets[name] {
+					graph.edge(ingressID, graph.node("Secret", name, models.TopologyStatusReady, ""), "tls")
+				}
+			}
+		}
+	}
+
+	export.Nodes = graph.nodes
+	export.Edges = graph.edges
+	export.NodeCount = len(graph.nodes)
+	export.EdgeCount = len(graph.edges)
+	if graph.omitted > 0 {
+		export.Truncated = true
+		export.Omitted = graph.omitted
+		export.Notes = append(export.Notes, fmt.Sprintf("node limit of %d reached, %d nodes and their edges were omitted; narrow the scope with labelSelector or kinds", maxNodes, graph.omitted))
+	}
+	if len(graph.nodes) == 0 {
+		export.Notes = append(export.Notes, "no resources matched the selected kinds and labelSelector")
+	}
+	if graphFormat == topologyFormatDOT {
+		export.Graph = renderTopologyDOT(export)
+	} else {
+		export.Graph = renderTopologyMermaid(export)
+	}
+
+	h.Log.Info("Topology exported", "namespace", namespace, "nodes", export.NodeCount, "edges", export.EdgeCount, "omitted", graph.omitted)
+
+	return utils.RenderResult(request, export), nil
+}
+
+// replicaStatus 根据期望副本数和就绪副本数返回状态和说明
+func replicaStatus(replicas *int32, ready int32) (string, string) {
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+	if desired == 0 {
+		return models.TopologyStatusReady, "scaled to 0"
+	}
+	status := models.TopologyStatusReady
+	if ready < desired {
+		status = models.TopologyStatusDegraded
+	}
+	return status, fmt.Sprintf("%d/%d ready", ready, desired)
+}
+
+// jobTopologyStatus 返回Job的状态和说明
+func jobTopologyStatus(job *batchv1.Job) (string, string) {
+	switch finished, _ := jobFinished(job); finished {
+	case string(batchv1.JobComplete):
+		return models.TopologyStatusReady, "complete"
+	case string(batchv1.JobFailed):
+		return models.TopologyStatusDegraded, "failed"
+	}
+	return models.TopologyStatusReady, fmt.Sprintf("running, %d active", job.Status.Active)
+}
+
+// serviceTopologyStatus 根据就绪端点数返回Service的状态和说明
+func serviceTopologyStatus(service *corev1.Service, readyEndpoints map[string]int) (string, string) {
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return models.TopologyStatusReady, "ExternalName " + service.Spec.ExternalName
+	}
+	ready := readyEndpoints[service.Name]
+	detail := fmt.Sprintf("%s, %d ready endpoints", service.Spec.Type, ready)
+	if ready == 0 && len(service.Spec.Selector) > 0 {
+		return models.TopologyStatusDegraded, detail
+	}
+	return models.TopologyStatusReady, detail
+}
+
+// ingressAddresses 返回Ingress负载均衡器的地址
+func ingressAddresses(ingress *networkingv1.Ingress) []string {
+	var addresses []string
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	return addresses
+}
+
+// topologyPodReady Pod的Ready条件是否为True
+func topologyPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// topologyNodeLabel 节点标签的各行：类型和名称，以及状态说明
+func topologyNodeLabel(node models.TopologyNode) []string {
+	lines := []string{node.Kind + " " + node.Name}
+	if node.Detail != "" {
+		lines = append(lines, node.Detail)
+	}
+	if node.Status == models.TopologyStatusMissing || node.Status == models.TopologyStatusDegraded {
+		lines = append(lines, "["+node.Status+"]")
+	}
+	return lines
+}
+
+// renderTopologyMermaid 以Mermaid flowchart呈现拓扑，异常节点使用单独的样式
+func renderTopologyMermaid(export *models.TopologyExport) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, note := range export.Notes {
+		b.WriteString("  %% " + note + "\n")
+	}
+	byStatus := make(map[string][]string)
+	for _, node := range export.Nodes {
+		lines := topologyNodeLabel(node)
+		for i, line := range lines {
+			lines[i] = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(line)
+		}
+		b.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", node.ID, strings.Join(lines, "<br/>")))
+		byStatus[node.Status] = append(byStatus[node.Status], node.ID)
+	}
+	for _, edge := range export.Edges {
+		b.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", edge.From, edge.Label, edge.To))
+	}
+	styles := []struct{ status, style string }{
+		{models.TopologyStatusDegraded, "stroke:#d9534f,stroke-width:2px"},
+		{models.TopologyStatusMissing, "stroke:#d9534f,stroke-dasharray:5 5"},
+		{models.TopologyStatusUnknown, "stroke:#999999"},
+	}
+	for _, style := range styles {
+		if ids := byStatus[style.status]; len(ids) > 0 {
+			b.WriteString(fmt.Sprintf("  classDef %s %s\n", style.status, style.style))
+			b.WriteString(fmt.Sprintf("  class %s %s\n", strings.Join(ids, ","), style.status))
+		}
+	}
+	return b.String()
+}
+
+// renderTopologyDOT 以Graphviz DOT呈现拓扑，异常节点以红色显示，不存在的节点使用虚线
+func renderTopologyDOT(export *models.TopologyExport) string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("digraph \"%s\" {\n", escape(export.Namespace)))
+	for _, note := range export.Notes {
+		b.WriteString("  // " + note + "\n")
+	}
+	b.WriteString("  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range export.Nodes {
+		lines := topologyNodeLabel(node)
+		for i, line := range lines {
+			lines[i] = escape(line)
+		}
+		attributes := ""
+		switch node.Status {
+		case models.TopologyStatusDegraded:
+			attributes = ", color=red"
+		case models.TopologyStatusMissing:
+			attributes = ", color=red, style=dashed"
+		case models.TopologyStatusUnknown:
+			attributes = ", color=gray"
+		}
+		b.WriteString(fmt.Sprintf("  %s [label=\"%s\"%s];\n", node.ID, strings.Join(lines, `\n`), attributes))
+	}
+	for _, edge := range export.Edges {
+		b.WriteString(fmt.Sprintf("  %s -> %s [label=\"%s\"];\n", edge.From, edge.To, escape(edge.Label)))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认最长等待时间（秒）
+	defaultWaitForSeconds = 60
+	// 最长等待时间上限（秒）
+	maxWaitForSeconds = 600
+)
+
+// WaitFor 阻塞等待单个对象满足内置条件、状态条件或JSONPath表达式，满足、失败或超时后返回最后观察到的对象
+func (h *UtilityHandler) WaitFor(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	name, _ := arguments["name"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	conditionName, _ := arguments["condition"].(string)
+	conditionType, _ := arguments["conditionType"].(string)
+	conditionStatus, _ := arguments["conditionStatus"].(string)
+	jsonPath, _ := arguments["jsonPath"].(string)
+	value, _ := arguments["value"].(string)
+	waitSeconds, _ := arguments["maxWaitSeconds"].(float64)
+
+	if waitSeconds <= 0 {
+		waitSeconds = defaultWaitForSeconds
+	}
+	if waitSeconds > maxWaitForSeconds {
+		waitSeconds = maxWaitForSeconds
+	}
+	if conditionStatus == "" {
+		conditionStatus = "True"
+	}
+
+	h.Log.Info("Waiting for condition",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"name", name,
+		"namespace", namespace,
+		"condition", conditionName,
+		"conditionType", conditionType,
+		"jsonPath", jsonPath,
+		"waitSeconds", waitSeconds,
+	)
+
+	if kind == "" || apiVersion == "" || name == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind, apiVersion and name"), nil
+	}
+
+	// condition、conditionType和jsonPath三者只能指定一个
+	specified := 0
+	for _, item := range []string{conditionName, conditionType, jsonPath} {
+		if item != "" {
+			specified++
+		}
+	}
+	if specified != 1 {
+		return utils.NewToolErrorResult(models.ToolError{
+			Code:    utils.ErrorCodeInvalid,
+			Message: "exactly one of condition, conditionType or jsonPath must be specified",
+			Hint:    "Use condition for built-in checks such as Ready or Deleted, conditionType/conditionStatus for any status condition, or jsonPath with value for an arbitrary field.",
+		}), nil
+	}
+
+	var (
+		condition   utils.WaitCondition
+		description string
+		err         error
+	)
+	switch {
+	case conditionName != "":
+		condition, err = utils.BuiltinWaitCondition(conditionName)
+		description = conditionName
+	case conditionType != "":
+		condition = utils.StatusConditionWait(conditionType, conditionStatus)
+		description = fmt.Sprintf("%s=%s", conditionType, conditionStatus)
+	default:
+		condition, err = utils.JSONPathWait(jsonPath, value)
+		description = fmt.Sprintf("%s=%s", jsonPath, value)
+	}
+	if err != nil {
+		return utils.NewToolErrorResult(models.ToolError{Code: utils.ErrorCodeInvalid, Message: err.Error()}), nil
+	}
+
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+	var resource dynamic.ResourceInterface
+	if namespaced {
+		if namespace == "" {
+			namespace = "default"
+		}
+		resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace)
+	} else {
+		namespace = ""
+		resource = h.Client.GetDynamicClient().Resource(gvr)
+	}
+
+	start := time.Now()
+	outcome, err := utils.WaitFor(ctx, time.Duration(waitSeconds)*time.Second, resource, name, condition)
+	if err != nil && !outcome.Failed {
+		// 获取对象本身失败（例如没有权限），不是条件判断的结果
+		h.Log.Error("Failed to wait for condition", "kind", kind, "name", name, "namespace", namespace, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to get %s %s", kind, name)), nil
+	}
+	response := models.WaitForResult{
+		Kind:       kind,
+		APIVersion: apiVersion,
+		Name:       name,
+		Namespace:  namespace,
+		Condition:  description,
+		Met:        outcome.Met,
+		TimedOut:   outcome.TimedOut,
+		Message:    outcome.Message,
+		Waited:     time.Since(start).Round(time.Second).String(),
+	}
+	if outcome.Object != nil {
+		obj := outcome.Object.DeepCopy()
+		obj.SetManagedFields(nil)
+		utils.RedactSecret(obj)
+		response.Object = obj.Object
+	}
+
+	switch {
+	case outcome.Failed:
+		response.Failed = true
+		response.Hint = "The condition can no longer be met; inspect the object with DIAGNOSE_POD or GET_EVENTS."
+	case outcome.TimedOut:
+		response.Hint = "The condition was not met in time; message shows what is still blocking. Call again with a longer maxWaitSeconds or investigate the object."
+	}
+	return utils.RenderResult(request, response), nil
+}
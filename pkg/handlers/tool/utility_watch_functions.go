@@ -0,0 +1,339 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// 默认监听时长（秒）
+	defaultWatchDurationSeconds = 30
+	// 最大监听时长（秒）
+	maxWatchDurationSeconds = 300
+	// 单次监听最多收集的事件数量，防止响应过大
+	maxWatchEvents = 500
+	// 每个MODIFIED事件最多输出的字段变更数量
+	maxWatchChangesPerEvent = 50
+	// 重新建立监听前的初始等待时间，之后连续失败时按指数退避
+	watchRestartBackoff = time.Second
+	// 重新建立监听前等待时间的上限
+	watchMaxRestartBackoff = 30 * time.Second
+)
+
+// WatchResource 在限定时长内监听资源变化
+func (h *UtilityHandler) WatchResource(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	kind, _ := arguments["kind"].(string)
+	apiVersion, _ := arguments["apiVersion"].(string)
+	namespace, _ := arguments["namespace"].(string)
+	name, _ := arguments["name"].(string)
+	labelSelector, err := utils.SelectorArgument(arguments, utils.LabelSelectorArgument)
+	if err != nil {
+		return utils.NewSelectorErrorResult(err), nil
+	}
+	durationSeconds, _ := arguments["maxDurationSeconds"].(float64)
+
+	if durationSeconds <= 0 {
+		durationSeconds = defaultWatchDurationSeconds
+	}
+	if durationSeconds > maxWatchDurationSeconds {
+		durationSeconds = maxWatchDurationSeconds
+	}
+
+	h.Log.Info("Watching resource",
+		"kind", kind,
+		"apiVersion", apiVersion,
+		"namespace", namespace,
+		"name", name,
+		"labelSelector", labelSelector,
+		"durationSeconds", durationSeconds,
+	)
+
+	if kind == "" || apiVersion == "" {
+		return utils.NewErrorToolResult("missing required parameters: kind and apiVersion"), nil
+	}
+
+	gvr, namespaced, err := utils.ResolveGVR(h.Client, apiVersion, kind)
+	if err != nil {
+		return utils.NewKubeErrorResult(err), nil
+	}
+
+	var resource dynamic.ResourceInterface
+	if namespaced && namespace != "" {
+		resource = h.Client.GetDynamicClient().Resource(gvr).Namespace(namespace)
+	} else {
+		resource = h.Client.GetDynamicClient().Resource(gvr)
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+	if name != "" {
+		listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+
+	watchCtx, cancel := context.WithTimeout(ctx, time.Duration(durationSeconds)*time.Second)
+	defer cancel()
+
+	result := models.WatchResult{
+		Kind:          kind,
+		APIVersion:    apiVersion,
+		Namespace:     namespace,
+		Name:          name,
+		LabelSelector: labelSelector,
+		StartedAt:     time.Now(),
+		Events:        []models.WatchEvent{},
+	}
+
+	// 先列出当前对象，作为后续MODIFIED事件的比较基线，同时获得起始resourceVersion
+	known := make(map[string]*unstructured.Unstructured)
+	resourceVersion, err := relistForWatch(watchCtx, resource, listOptions, known)
+	if err != nil {
+		h.Log.Error("Failed to list resources before watch", "kind", kind, "error", err)
+		return utils.NewKubeErrorResult(err, fmt.Sprintf("failed to list %s", kind)), nil
+	}
+
+	// 客户端请求了进度时汇报已观察到的事件数量，监听时长较长时让客户端知道调用仍在进行
+	progress := utils.ProgressFromContext(ctx)
+	// 每次重新建立监听前都按退避时间等待，收到事件后退避时间恢复为初始值；
+	// resourceVersion过期后必须先重新列出成功才能继续监听，避免从过期的版本反复重试
+	backoff := watchRestartBackoff
+	needsRelist := false
+watchLoop:
+	for attempt := 0; watchCtx.Err() == nil && !result.Truncated; attempt++ {
+		if attempt > 0 {
+			if !sleepWithContext(watchCtx, backoff) {
+				break
+			}
+			backoff = min(backoff*2, watchMaxRestartBackoff)
+		}
+		if needsRelist {
+			rv, err := relistForWatch(watchCtx, resource, listOptions, known)
+			if err != nil {
+				if watchCtx.Err() != nil {
+					break
+				}
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to relist after the resourceVersion expired: %v", err))
+				h.Log.Warn("Failed to relist before restarting watch, retrying", "kind", kind, "error", err)
+				continue
+			}
+			resourceVersion, needsRelist = rv, false
+		}
+
+		options := listOptions
+		options.ResourceVersion = resourceVersion
+		options.AllowWatchBookmarks = true
+
+		watcher, err := resource.Watch(watchCtx, options)
+		if err != nil {
+			if watchCtx.Err() != nil {
+				break
+			}
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				resourceVersion, needsRelist = "", true
+				result.Restarts++
+				continue
+			}
+			result.Errors = append(result.Errors, err.Error())
+			h.Log.Warn("Failed to start watch, retrying", "kind", kind, "error", err, "backoff", backoff)
+			continue
+		}
+
+		restart := false
+		for !restart {
+			select {
+			case <-watchCtx.Done():
+				watcher.Stop()
+				break watchLoop
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					// 服务端关闭了监听，从最后的resourceVersion继续
+					restart = true
+					break
+				}
+
+				switch event.Type {
+				case watch.Bookmark:
+					if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+						resourceVersion = obj.GetResourceVersion()
+					}
+					backoff = watchRestartBackoff
+				case watch.Error:
+					statusErr := apierrors.FromObject(event.Object)
+					if apierrors.IsResourceExpired(statusErr) || apierrors.IsGone(statusErr) {
+						// resourceVersion 过旧，重新列出后从最新版本继续
+						resourceVersion, needsRelist = "", true
+					} else {
+						result.Errors = append(result.Errors, statusErr.Error())
+					}
+					restart = true
+				case watch.Added, watch.Modified, watch.Deleted:
+					obj, ok := event.Object.(*unstructured.Unstructured)
+					if !ok {
+						continue
+					}
+					backoff = watchRestartBackoff
+					resourceVersion = obj.GetResourceVersion()
+					result.Events = append(result.Events, newWatchEvent(event.Type, obj, known))
+					progress.Report(ctx, fmt.Sprintf("%d events observed, last %s %s", len(result.Events), event.Type, watchObjectKey(obj)))
+					if len(result.Events) >= maxWatchEvents {
+						result.Truncated = true
+						restart = true
+					}
+				}
+			}
+		}
+		watcher.Stop()
+		if !result.Truncated {
+			result.Restarts++
+		}
+	}
+
+	result.EndedAt = time.Now()
+	result.Duration = result.EndedAt.Sub(result.StartedAt).Round(time.Millisecond).String()
+	result.EventCount = len(result.Events)
+
+	h.Log.Info("Watch finished", "kind", kind, "events", result.EventCount, "restarts", result.Restarts)
+	progress.Done(ctx, fmt.Sprintf("watch finished after %s with %d events", result.Duration, result.EventCount))
+
+	return utils.RenderResult(request, result), nil
+}
+
+// relistForWatch 重新列出资源，刷新比较基线并返回最新的resourceVersion
+func relistForWatch(
+	ctx context.Context,
+	resource dynamic.ResourceInterface,
+	options metav1.ListOptions,
+	known map[string]*unstructured.Unstructured,
+) (string, error) {
+	list, err := resource.List(ctx, options)
+	if err != nil {
+		return "", err
+	}
+	for i := range list.Items {
+		item := &list.Items[i]
+		known[watchObjectKey(item)] = item
+	}
+	return list.GetResourceVersion(), nil
+}
+
+// newWatchEvent 根据监听事件构建时间线条目，MODIFIED事件附带spec/status字段变更
+func newWatchEvent(
+	eventType watch.EventType,
+	obj *unstructured.Unstructured,
+	known map[string]*unstructured.Unstructured,
+) models.WatchEvent {
+	event := models.WatchEvent{
+		Type:            string(eventType),
+		Time:            time.Now(),
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Generation:      obj.GetGeneration(),
+	}
+
+	key := watchObjectKey(obj)
+	switch eventType {
+	case watch.Modified:
+		if previous, ok := known[key]; ok {
+			var changes []models.FieldChange
+			for _, field := range []string{"spec", "status"} {
+				diffFields(field, previous.Object[field], obj.Object[field], &changes)
+			}
+			if len(changes) > maxWatchChangesPerEvent {
+				event.ChangesOmitted = len(changes) - maxWatchChangesPerEvent
+				changes = changes[:maxWatchChangesPerEvent]
+			}
+			event.Changes = changes
+		}
+		known[key] = obj
+	case watch.Added:
+		known[key] = obj
+	case watch.Deleted:
+		delete(known, key)
+	}
+	return event
+}
+
+// watchObjectKey 返回对象在比较基线中的键
+func watchObjectKey(obj *unstructured.Unstructured) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// diffFields 递归比较两个值，记录叶子字段的变更
+func diffFields(path string, oldValue, newValue interface{}, changes *[]models.FieldChange) {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make(map[string]struct{}, len(oldMap)+len(newMap))
+		for k := range oldMap {
+			keys[k] = struct{}{}
+		}
+		for k := range newMap {
+			keys[k] = struct{}{}
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+		for _, k := range sortedKeys {
+			diffFields(path+"."+k, oldMap[k], newMap[k], changes)
+		}
+		return
+	}
+
+	// 长度相同的列表逐个元素比较，便于定位例如某个容器的镜像变更
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+	if oldIsList && newIsList && len(oldList) == len(newList) {
+		for i := range oldList {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), oldList[i], newList[i], changes)
+		}
+		return
+	}
+
+	change := models.FieldChange{
+		Field:    path,
+		OldValue: oldValue,
+		NewValue: newValue,
+		Action:   "change",
+	}
+	if oldValue == nil {
+		change.Action = "add"
+	} else if newValue == nil {
+		change.Action = "remove"
+	}
+	*changes = append(*changes, change)
+}
+
+// sleepWithContext 等待指定时间，上下文取消时返回false
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
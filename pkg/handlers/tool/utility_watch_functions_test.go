@@ -0,0 +1,127 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+// watchRecorder 记录每次Watch请求使用的resourceVersion
+type watchRecorder struct {
+	mu       sync.Mutex
+	versions []string
+}
+
+func (r *watchRecorder) record(action clienttesting.Action) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = append(r.versions, action.(clienttesting.WatchActionImpl).WatchRestrictions.ResourceVersion)
+}
+
+func (r *watchRecorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.versions...)
+}
+
+// errorWatcher 返回立即发送一个错误事件的监听
+func errorWatcher(code int32, reason metav1.StatusReason) watch.Interface {
+	watcher := watch.NewRaceFreeFake()
+	watcher.Error(&metav1.Status{Status: metav1.StatusFailure, Code: code, Reason: reason, Message: string(reason)})
+	return watcher
+}
+
+func deploymentList(resourceVersion string) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion("apps/v1")
+	list.SetKind("DeploymentList")
+	list.SetResourceVersion(resourceVersion)
+	return list
+}
+
+func runWatch(t *testing.T, client *testutil.FakeClient, seconds int) models.WatchResult {
+	t.Helper()
+	handler := NewUtilityHandler(client).(*UtilityHandler)
+	result, err := handler.Handle(context.Background(), testutil.NewToolRequest(WATCH_RESOURCE, map[string]any{
+		"kind":               "Deployment",
+		"apiVersion":         "apps/v1",
+		"namespace":          testutil.DefaultNamespace,
+		"maxDurationSeconds": seconds,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var watchResult models.WatchResult
+	if err := testutil.DecodeResult(result, &watchResult); err != nil {
+		t.Fatal(err)
+	}
+	return watchResult
+}
+
+// TestWatchResourceBacksOffOnErrors 非过期的错误事件不能使监听立即重启而空转
+func TestWatchResourceBacksOffOnErrors(t *testing.T) {
+	client := testutil.NewFakeClient()
+	recorder := &watchRecorder{}
+	client.FakeDynamicClient().PrependWatchReactor("deployments", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		recorder.record(action)
+		return true, errorWatcher(http.StatusInternalServerError, metav1.StatusReasonInternalError), nil
+	})
+
+	result := runWatch(t, client, 3)
+
+	// 0s开始，等待1s后第二次，再等待2s后已到达时长
+	if attempts := len(recorder.snapshot()); attempts < 2 || attempts > 3 {
+		t.Fatalf("watch was started %d times in 3s, want 2 or 3 with exponential backoff", attempts)
+	}
+	if len(result.Errors) == 0 {
+		t.Fatal("watch errors are not reported")
+	}
+}
+
+// TestWatchResourceRelistsAfterExpiry resourceVersion过期且重新列出失败时，不能继续使用过期的resourceVersion监听
+func TestWatchResourceRelistsAfterExpiry(t *testing.T) {
+	client := testutil.NewFakeClient()
+	var lists int
+	var mu sync.Mutex
+	client.FakeDynamicClient().PrependReactor("list", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lists++
+		switch lists {
+		case 1:
+			return true, deploymentList("10"), nil
+		case 2:
+			return true, nil, errors.New("apiserver unavailable")
+		}
+		return true, deploymentList("20"), nil
+	})
+	recorder := &watchRecorder{}
+	client.FakeDynamicClient().PrependWatchReactor("deployments", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		recorder.record(action)
+		if len(recorder.snapshot()) == 1 {
+			return true, errorWatcher(http.StatusGone, metav1.StatusReasonExpired), nil
+		}
+		return true, watch.NewRaceFreeFake(), nil
+	})
+
+	result := runWatch(t, client, 4)
+
+	versions := recorder.snapshot()
+	if len(versions) != 2 || versions[0] != "10" || versions[1] != "20" {
+		t.Fatalf("watch resourceVersions = %v, want [10 20]", versions)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("errors = %v, want the failed relist", result.Errors)
+	}
+}
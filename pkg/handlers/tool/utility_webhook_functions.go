@@ -0,0 +1,578 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 未设置时API Server使用的Webhook端口和超时
+const (
+	defaultWebhookPort    = 443
+	defaultWebhookTimeout = 10
+)
+
+// 从API Server返回的准入错误中识别Webhook、后端Service和拒绝原因
+var (
+	webhookNamePattern        = regexp.MustCompile(`webhook "([^"]+)"`)
+	webhookServicePattern     = regexp.MustCompile(`https://([a-z0-9][-a-z0-9]*)\.([a-z0-9][-a-z0-9]*)\.svc[.:/]`)
+	webhookNoEndpointsPattern = regexp.MustCompile(`no endpoints available for service "([^"]+)"`)
+	admissionPolicyPattern    = regexp.MustCompile(`ValidatingAdmissionPolicy '([^']+)'`)
+	webhookDeniedPattern      = regexp.MustCompile(`denied the request:\s*(.+)`)
+)
+
+// webhookSpec ValidatingWebhook和MutatingWebhook共有的字段
+type webhookSpec struct {
+	configuration     string
+	webhookType       string
+	name              string
+	rules             []admissionregistrationv1.RuleWithOperations
+	failurePolicy     *admissionregistrationv1.FailurePolicyType
+	timeoutSeconds    *int32
+	sideEffects       *admissionregistrationv1.SideEffectClass
+	matchPolicy       *admissionregistrationv1.MatchPolicyType
+	reinvocation      *admissionregistrationv1.ReinvocationPolicyType
+	namespaceSelector *metav1.LabelSelector
+	objectSelector    *metav1.LabelSelector
+	matchConditions   []admissionregistrationv1.MatchCondition
+	clientConfig      admissionregistrationv1.WebhookClientConfig
+}
+
+// webhookService Webhook后端Service的检查结果，同一Service只检查一次
+type webhookService struct {
+	exists       bool
+	externalName bool
+	ports        map[int32]bool
+	ready        int
+	notReady     int
+	checkFailed  string
+}
+
+// ListWebhooks 列出准入Webhook的拦截规则、失败策略、选择器以及后端Service的可用性
+func (h *UtilityHandler) ListWebhooks(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	webhookType, _ := arguments["type"].(string)
+	unhealthyOnly, _ := arguments["unhealthyOnly"].(bool)
+
+	h.Log.Info("Listing admission webhooks", "type", webhookType, "unhealthyOnly", unhealthyOnly)
+
+	webhooks, errs := h.collectWebhooks(ctx, webhookType)
+	if len(webhooks) == 0 && len(errs) > 0 {
+		return utils.NewKubeErrorResult(errs[0], "failed to list webhook configurations"), nil
+	}
+
+	inventory := &models.WebhookInventory{
+		Webhooks:    []models.WebhookInfo{},
+		Findings:    webhookFindings(webhooks),
+		RetrievedAt: time.Now(),
+	}
+	for _, err := range errs {
+		inventory.Errors = append(inventory.Errors, err.Error())
+	}
+	for _, webhook := range webhooks {
+		if unhealthyOnly && webhook.Backend.Status != models.WebhookBackendUnhealthy {
+			continue
+		}
+		inventory.Webhooks = append(inventory.Webhooks, webhook)
+	}
+	inventory.Count = len(inventory.Webhooks)
+	return utils.RenderResult(request, inventory), nil
+}
+
+// DiagnoseAdmissionFailure 把apply/create返回的错误消息对应到负责的Webhook，并报告其后端当前是否可用
+func (h *UtilityHandler) DiagnoseAdmissionFailure(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	arguments := request.GetArguments()
+	errorMessage, _ := arguments["errorMessage"].(string)
+	errorMessage = strings.TrimSpace(errorMessage)
+	if errorMessage == "" {
+		return utils.NewErrorToolResult("missing required parameter: errorMessage"), nil
+	}
+
+	h.Log.Info("Diagnosing admission failure", "length", len(errorMessage))
+
+	diagnosis := &models.AdmissionDiagnosis{
+		Category:    classifyAdmissionError(errorMessage),
+		Matched:     []models.WebhookInfo{},
+		Suggestions: []string{},
+		RetrievedAt: time.Now(),
+	}
+	for _, match := range webhookNamePattern.FindAllStringSubmatch(errorMessage, -1) {
+		if !slices.Contains(diagnosis.WebhookNames, match[1]) {
+			diagnosis.WebhookNames = append(diagnosis.WebhookNames, match[1])
+		}
+	}
+	serviceName := ""
+	if match := webhookServicePattern.FindStringSubmatch(errorMessage); match != nil {
+		diagnosis.Service = match[2] + "/" + match[1]
+		serviceName = match[1]
+	} else if match := webhookNoEndpointsPattern.FindStringSubmatch(errorMessage); match != nil {
+		diagnosis.Service = match[1]
+		serviceName = match[1]
+	}
+	if match := webhookDeniedPattern.FindStringSubmatch(errorMessage); match != nil {
+		diagnosis.Reason = strings.TrimSpace(match[1])
+	}
+
+	if diagnosis.Category == models.AdmissionFailurePolicy {
+		policy := admissionPolicyPattern.FindStringSubmatch(errorMessage)[1]
+		diagnosis.Explanation = fmt.Sprintf("The request was rejected by ValidatingAdmissionPolicy %s, which the API server evaluates itself; no webhook is involved.", policy)
+		diagnosis.Suggestions = append(diagnosis.Suggestions,
+			"Read the policy's validations and its ValidatingAdmissionPolicyBinding with GET_RESOURCE (admissionregistration.k8s.io), then change the object to satisfy them.")
+		return utils.RenderResult(request, diagnosis), nil
+	}
+
+	webhooks, errs := h.collectWebhooks(ctx, "")
+	if len(webhooks) == 0 && len(errs) > 0 {
+		return utils.NewKubeErrorResult(errs[0], "failed to list webhook configurations"), nil
+	}
+	for _, err := range errs {
+		diagnosis.Notes = append(diagnosis.Notes, err.Error())
+	}
+	for _, webhook := range webhooks {
+		if matchesAdmissionError(webhook, diagnosis.WebhookNames, diagnosis.Service, serviceName) {
+			diagnosis.Matched = append(diagnosis.Matched, webhook)
+			if webhook.Backend.Status == models.WebhookBackendUnhealthy {
+				diagnosis.BackendUnhealthy = true
+			}
+		}
+	}
+	if len(diagnosis.Matched) == 0 {
+		for _, name := range diagnosis.WebhookNames {
+			diagnosis.Notes = append(diagnosis.Notes,
+				fmt.Sprintf("webhook %q from the error is not registered now; its configuration may have been removed or renamed since the failure", name))
+		}
+		if diagnosis.Category != models.AdmissionFailureDenied {
+			for _, webhook := range webhooks {
+				if webhook.FailurePolicy == string(admissionregistrationv1.Fail) && webhook.Backend.Status == models.WebhookBackendUnhealthy {
+					diagnosis.Suspects = append(diagnosis.Suspects, webhook)
+				}
+			}
+		}
+	}
+
+	explainAdmissionFailure(diagnosis)
+	return utils.RenderResult(request, diagnosis), nil
+}
+
+// collectWebhooks 读取Webhook配置并检查每个Webhook的后端，webhookType为空时读取两种配置
+// 返回读取失败的配置类型的错误，另一种配置仍会返回
+func (h *UtilityHandler) collectWebhooks(ctx context.Context, webhookType string) ([]models.WebhookInfo, []error) {
+	admission := h.Client.ClientSet().AdmissionregistrationV1()
+	var specs []webhookSpec
+	var errs []error
+	if webhookType == "" || webhookType == "all" || webhookType == models.WebhookTypeValidating {
+		configurations, err := admission.ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list ValidatingWebhookConfigurations: %w", err))
+		} else {
+			for _, configuration := range configurations.Items {
+				for _, webhook := range configuration.Webhooks {
+					specs = append(specs, webhookSpec{
+						configuration:     configuration.Name,
+						webhookType:       models.WebhookTypeValidating,
+						name:              webhook.Name,
+						rules:             webhook.Rules,
+						failurePolicy:     webhook.FailurePolicy,
+						timeoutSeconds:    webhook.TimeoutSeconds,
+						sideEffects:       webhook.SideEffects,
+						matchPolicy:       webhook.MatchPolicy,
+						namespaceSelector: webhook.NamespaceSelector,
+						objectSelector:    webhook.ObjectSelector,
+						matchConditions:   webhook.MatchConditions,
+						clientConfig:      webhook.ClientConfig,
+					})
+				}
+			}
+		}
+	}
+	if webhookType == "" || webhookType == "all" || webhookType == models.WebhookTypeMutating {
+		configurations, err := admission.MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list MutatingWebhookConfigurations: %w", err))
+		} else {
+			for _, configuration := range configurations.Items {
+				for _, webhook := range configuration.Webhooks {
+					specs = append(specs, webhookSpec{
+						configuration:     configuration.Name,
+						webhookType:       models.WebhookTypeMutating,
+						name:              webhook.Name,
+						rules:             webhook.Rules,
+						failurePolicy:     webhook.FailurePolicy,
+						timeoutSeconds:    webhook.TimeoutSeconds,
+						sideEffects:       webhook.SideEffects,
+						matchPolicy:       webhook.MatchPolicy,
+						reinvocation:      webhook.ReinvocationPolicy,
+						namespaceSelector: webhook.NamespaceSelector,
+						objectSelector:    webhook.ObjectSelector,
+						matchConditions:   webhook.MatchConditions,
+						clientConfig:      webhook.ClientConfig,
+					})
+				}
+			}
+		}
+	}
+
+	services := make(map[string]*webhookService)
+	webhooks := make([]models.WebhookInfo, 0, len(specs))
+	for _, spec := range specs {
+		webhooks = append(webhooks, h.describeWebhook(ctx, spec, services))
+	}
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		if webhooks[i].Type != webhooks[j].Type {
+			return webhooks[i].Type < webhooks[j].Type
+		}
+		return webhooks[i].Configuration < webhooks[j].Configuration
+	})
+	return webhooks, errs
+}
+
+// describeWebhook 把Webhook配置转换为输出模型，并检查后端Service
+func (h *UtilityHandler) describeWebhook(ctx context.Context, spec webhookSpec, services map[string]*webhookService) models.WebhookInfo {
+	info := models.WebhookInfo{
+		Configuration:     spec.configuration,
+		Type:              spec.webhookType,
+		Name:              spec.name,
+		Rules:             make([]models.WebhookRule, 0, len(spec.rules)),
+		FailurePolicy:     string(admissionregistrationv1.Fail),
+		TimeoutSeconds:    defaultWebhookTimeout,
+		NamespaceSelector: formatWebhookSelector(spec.namespaceSelector),
+		ObjectSelector:    formatWebhookSelector(spec.objectSelector),
+	}
+	if spec.failurePolicy != nil {
+		info.FailurePolicy = string(*spec.failurePolicy)
+	}
+	if spec.timeoutSeconds != nil {
+		info.TimeoutSeconds = *spec.timeoutSeconds
+	}
+	if spec.sideEffects != nil {
+		info.SideEffects = string(*spec.sideEffects)
+	}
+	if spec.matchPolicy != nil {
+		info.MatchPolicy = string(*spec.matchPolicy)
+	}
+	if spec.reinvocation != nil {
+		info.ReinvocationPolicy = string(*spec.reinvocation)
+	}
+	for _, condition := range spec.matchConditions {
+		info.MatchConditions = append(info.MatchConditions, condition.Name)
+	}
+	info.ClusterWide = info.NamespaceSelector == "" && info.ObjectSelector == "" && len(info.MatchConditions) == 0
+	for _, rule := range spec.rules {
+		webhookRule := models.WebhookRule{
+			APIGroups:   rule.APIGroups,
+			APIVersions: rule.APIVersions,
+			Resources:   rule.Resources,
+			Scope:       string(admissionregistrationv1.AllScopes),
+		}
+		for _, operation := range rule.Operations {
+			webhookRule.Operations = append(webhookRule.Operations, string(operation))
+		}
+		if rule.Scope != nil {
+			webhookRule.Scope = string(*rule.Scope)
+		}
+		info.Rules = append(info.Rules, webhookRule)
+	}
+	info.Backend = h.webhookBackend(ctx, spec.clientConfig, services)
+	return info
+}
+
+// webhookBackend 检查Webhook后端：Service是否存在、是否暴露了Webhook端口、是否有就绪的端点以及caBundle是否为空
+// 使用外部URL或ExternalName Service的Webhook无法从集群内检查，状态为unknown
+func (h *UtilityHandler) webhookBackend(
+	ctx context.Context,
+	config admissionregistrationv1.WebhookClientConfig,
+	services map[string]*webhookService,
+) models.WebhookBackend {
+	backend := models.WebhookBackend{CABundle: len(config.CABundle) > 0}
+	if config.URL != nil {
+		backend.URL = *config.URL
+		backend.Status = models.WebhookBackendUnknown
+		backend.Problems = append(backend.Problems, "the webhook calls an external URL, its availability is not checked")
+		return backend
+	}
+	if config.Service == nil {
+		backend.Status = models.WebhookBackendUnhealthy
+		backend.Problems = append(backend.Problems, "neither service nor url is configured")
+		return backend
+	}
+
+	reference := config.Service
+	port := int32(defaultWebhookPort)
+	if reference.Port != nil {
+		port = *reference.Port
+	}
+	backend.Service = fmt.Sprintf("%s/%s:%d", reference.Namespace, reference.Name, port)
+	if reference.Path != nil {
+		backend.Path = *reference.Path
+	}
+
+	key := reference.Namespace + "/" + reference.Name
+	service, ok := services[key]
+	if !ok {
+		service = h.checkWebhookService(ctx, reference.Namespace, reference.Name)
+		services[key] = service
+	}
+	backend.ServiceExists = service.exists
+	backend.ReadyEndpoints = service.ready
+	backend.NotReadyEndpoints = service.notReady
+
+	switch {
+	case service.checkFailed != "":
+		backend.Status = models.WebhookBackendUnknown
+		backend.Problems = append(backend.Problems, service.checkFailed)
+		return backend
+	case !service.exists:
+		backend.Problems = append(backend.Problems, fmt.Sprintf("service %s does not exist", key))
+	case service.externalName:
+		backend.Status = models.WebhookBackendUnknown
+		backend.Problems = append(backend.Problems, fmt.Sprintf("service %s is an ExternalName service, its endpoints are not checked", key))
+		return backend
+	default:
+		if !service.ports[port] {
+			backend.Problems = append(backend.Problems, fmt.Sprintf("service %s does not expose port %d", key, port))
+		}
+		if service.ready == 0 {
+			backend.Problems = append(backend.Problems, fmt.Sprintf("service %s has no ready endpoints (%d not ready)", key, service.notReady))
+		}
+	}
+	if !backend.CABundle {
+		backend.Problems = append(backend.Problems, "caBundle is empty, the API server cannot verify the webhook's serving certificate")
+	}
+	backend.Status = models.WebhookBackendHealthy
+	if len(backend.Problems) > 0 {
+		backend.Status = models.WebhookBackendUnhealthy
+	}
+	return backend
+}
+
+// checkWebhookService 读取Service及其EndpointSlice，统计就绪与未就绪的地址
+func (h *UtilityHandler) checkWebhookService(ctx context.Context, namespace, name string) *webhookService {
+	result := &webhookService{ports: make(map[int32]bool)}
+	clientSet := h.Client.ClientSet()
+	service, err := clientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return result
+	}
+	if err != nil {
+		result.checkFailed = fmt.Sprintf("failed to get service %s/%s: %v", namespace, name, err)
+		return result
+	}
+	result.exists = true
+	if service.Spec.Type == "ExternalName" {
+		result.externalName = true
+		return result
+	}
+	for _, port := range service.Spec.Ports {
+		result.ports[port.Port] = true
+	}
+
+	slices, err := clientSet.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: name}).String(),
+	})
+	if err != nil {
+		result.checkFailed = fmt.Sprintf("failed to list endpoints of service %s/%s: %v", namespace, name, err)
+		return result
+	}
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// 未设置Ready时按就绪处理
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				result.ready += len(endpoint.Addresses)
+			} else {
+				result.notReady += len(endpoint.Addresses)
+			}
+		}
+	}
+	return result
+}
+
+// webhookFindings 标出后端不可用的Webhook：failurePolicy为Fail时匹配的请求全部被拒绝，这是集群范围故障的常见原因；
+// 为Ignore时请求会绕过Webhook的校验或修改
+func webhookFindings(webhooks []models.WebhookInfo) []models.AvailabilityFinding {
+	findings := []models.AvailabilityFinding{}
+	for _, webhook := range webhooks {
+		if webhook.Backend.Status != models.WebhookBackendUnhealthy {
+			continue
+		}
+		object := webhookConfigurationKind(webhook.Type) + "/" + webhook.Configuration
+		problems := strings.Join(webhook.Backend.Problems, "; ")
+		scope := ""
+		if webhook.ClusterWide {
+			scope = " in every namespace"
+		}
+		if webhook.FailurePolicy == string(admissionregistrationv1.Fail) {
+			findings = append(findings, models.AvailabilityFinding{
+				Severity: models.SeverityCritical,
+				Check:    "fail-closed-backend-down",
+				Object:   object,
+				Message: fmt.Sprintf("webhook %s has failurePolicy=Fail but its backend is unavailable (%s); every %s request it intercepts%s is rejected",
+					webhook.Name, problems, webhookOperations(webhook.Rules), scope),
+				Suggestion: fmt.Sprintf("Restore the backend %s (check its pods and certificate). If it is gone for good, set failurePolicy: Ignore on webhook %s or delete %s.",
+					webhook.Backend.Service, webhook.Name, object),
+			})
+			continue
+		}
+		findings = append(findings, models.AvailabilityFinding{
+			Severity: models.SeverityWarning,
+			Check:    "ignored-backend-down",
+			Object:   object,
+			Message: fmt.Sprintf("webhook %s has failurePolicy=Ignore and its backend is unavailable (%s); matching requests are admitted without its checks or mutations",
+				webhook.Name, problems),
+			Suggestion: fmt.Sprintf("Restore the backend %s, or delete %s if the webhook is no longer used.", webhook.Backend.Service, object),
+		})
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == models.SeverityCritical && findings[j].Severity != models.SeverityCritical
+	})
+	return findings
+}
+
+// classifyAdmissionError 按API Server错误消息的特征判断准入失败的类别
+func classifyAdmissionError(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case admissionPolicyPattern.MatchString(message):
+		return models.AdmissionFailurePolicy
+	case strings.Contains(lower, "does not support dry run"):
+		return models.AdmissionFailureDryRun
+	case strings.Contains(lower, "denied the request"):
+		return models.AdmissionFailureDenied
+	case strings.Contains(lower, "x509") || strings.Contains(lower, "tls: "):
+		return models.AdmissionFailureTLS
+	// URL中的"?timeout=10s"不代表超时，只匹配明确的超时消息
+	case strings.Contains(lower, "deadline exceeded") || strings.Contains(lower, "i/o timeout") ||
+		strings.Contains(lower, "timed out") || strings.Contains(lower, "client.timeout exceeded"):
+		return models.AdmissionFailureTimeout
+	case strings.Contains(lower, "connection refused") || strings.Contains(lower, "no endpoints available") ||
+		strings.Contains(lower, "no such host") || strings.Contains(lower, "no route to host") ||
+		strings.Contains(lower, "connection reset") || strings.HasSuffix(lower, ": eof") ||
+		strings.Contains(lower, "service unavailable") || strings.Contains(lower, "failed calling webhook"):
+		return models.AdmissionFailureUnreachable
+	default:
+		return models.AdmissionFailureOther
+	}
+}
+
+// matchesAdmissionError 判断Webhook是否为错误中提到的Webhook，名称无法识别时按后端Service匹配
+func matchesAdmissionError(webhook models.WebhookInfo, names []string, service, serviceName string) bool {
+	if len(names) > 0 {
+		return slices.Contains(names, webhook.Name)
+	}
+	if webhook.Backend.Service == "" {
+		return false
+	}
+	if strings.Contains(service, "/") {
+		return strings.HasPrefix(webhook.Backend.Service, service+":")
+	}
+	if serviceName == "" {
+		return false
+	}
+	_, nameAndPort, _ := strings.Cut(webhook.Backend.Service, "/")
+	return strings.HasPrefix(nameAndPort, serviceName+":")
+}
+
+// explainAdmissionFailure 按失败类别和对应Webhook的当前状态填写说明和建议
+func explainAdmissionFailure(diagnosis *models.AdmissionDiagnosis) {
+	suggest := func(format string, args ...any) {
+		diagnosis.Suggestions = append(diagnosis.Suggestions, fmt.Sprintf(format, args...))
+	}
+	var unhealthy []string
+	failClosed := false
+	for _, webhook := range diagnosis.Matched {
+		if webhook.Backend.Status == models.WebhookBackendUnhealthy {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", webhook.Name, strings.Join(webhook.Backend.Problems, "; ")))
+		}
+		if webhook.FailurePolicy == string(admissionregistrationv1.Fail) {
+			failClosed = true
+		}
+	}
+
+	switch diagnosis.Category {
+	case models.AdmissionFailureDenied:
+		diagnosis.Explanation = "The webhook is reachable and rejected the object by its own policy; the object has to change, not the cluster."
+		if diagnosis.Reason != "" {
+			suggest("Fix the object according to the webhook's reason: %s", diagnosis.Reason)
+		}
+		suggest("If the rejection looks wrong, read the policy behind the webhook (for example the Gatekeeper constraint or Kyverno policy it enforces).")
+	case models.AdmissionFailureUnreachable, models.AdmissionFailureTimeout:
+		diagnosis.Explanation = "The API server could not get an answer from the webhook backend, so the request failed before any policy was evaluated."
+		if diagnosis.Category == models.AdmissionFailureTimeout {
+			diagnosis.Explanation = "The webhook backend did not answer within timeoutSeconds, so the request failed before any policy was evaluated."
+		}
+		if len(unhealthy) > 0 {
+			suggest("Restore the backend; it is unhealthy right now: %s.", strings.Join(unhealthy, ", "))
+		} else if len(diagnosis.Matched) > 0 {
+			suggest("The backend looks healthy now; the failure may have been transient, retry the request.")
+		}
+		if diagnosis.Category == models.AdmissionFailureTimeout {
+			suggest("If the backend is slow rather than down, scale it up or raise the webhook's timeoutSeconds (maximum 30).")
+		}
+		if failClosed {
+			suggest("As a last resort while the backend is down, set failurePolicy: Ignore on the webhook or delete its configuration if the owning operator was uninstalled.")
+		}
+	case models.AdmissionFailureTLS:
+		diagnosis.Explanation = "The API server rejected the webhook's serving certificate; the caBundle in the configuration does not match the certificate the backend presents."
+		suggest("Check that the backend's certificate is current and that caBundle is the CA that signed it. If cert-manager injects it (annotation cert-manager.io/inject-ca-from), check that the Certificate is Ready.")
+	case models.AdmissionFailureDryRun:
+		diagnosis.Explanation = "A webhook with side effects intercepts this request and does not support dry-run."
+		suggest("Retry without dryRun, or ask the webhook owner to declare sideEffects None or NoneOnDryRun.")
+	default:
+		diagnosis.Explanation = "The error does not match a known admission failure pattern."
+		suggest("Compare the error with LIST_WEBHOOKS to see which webhooks intercept this resource and operation.")
+	}
+	if len(diagnosis.Matched) == 0 && len(diagnosis.Suspects) > 0 {
+		suggest("No webhook could be identified from the error, but %d webhook(s) with failurePolicy=Fail have an unavailable backend; see suspects.", len(diagnosis.Suspects))
+	}
+}
+
+// formatWebhookSelector 返回选择器的字符串形式，匹配所有对象时为空
+func formatWebhookSelector(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return fmt.Sprintf("<invalid: %v>", err)
+	}
+	return parsed.String()
+}
+
+// webhookConfigurationKind 返回Webhook类型对应的配置资源类型
+func webhookConfigurationKind(webhookType string) string {
+	if webhookType == models.WebhookTypeMutating {
+		return "MutatingWebhookConfiguration"
+	}
+	return "ValidatingWebhookConfiguration"
+}
+
+// webhookOperations 返回Webhook规则拦截的操作和资源的简短描述，例如"CREATE/UPDATE pods"
+func webhookOperations(rules []models.WebhookRule) string {
+	var parts []string
+	for _, rule := range rules {
+		parts = append(parts, strings.Join(rule.Operations, "/")+" "+strings.Join(rule.Resources, ","))
+	}
+	if len(parts) == 0 {
+		return "matching"
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,272 @@
+package tool
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func TestClassifyAdmissionError(t *testing.T) {
+	// 错误消息取自API Server在各类准入失败时的实际输出
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "denied by gatekeeper",
+			message: `admission webhook "validation.gatekeeper.sh" denied the request: [require-team-label] you must provide labels: {"team"}`,
+			want:    models.AdmissionFailureDenied,
+		},
+		{
+			name:    "connection refused with a timeout query parameter",
+			message: `Internal error occurred: failed calling webhook "validate.nginx.ingress.kubernetes.io": failed to call webhook: Post "https://ingress-nginx-controller-admission.ingress-nginx.svc:443/networking/v1/ingresses?timeout=10s": dial tcp 10.96.12.34:443: connect: connection refused`,
+			want:    models.AdmissionFailureUnreachable,
+		},
+		{
+			name:    "no endpoints",
+			message: `Internal error occurred: failed calling webhook "webhook.cert-manager.io": failed to call webhook: Post "https://cert-manager-webhook.cert-manager.svc:443/mutate?timeout=10s": no endpoints available for service "cert-manager-webhook"`,
+			want:    models.AdmissionFailureUnreachable,
+		},
+		{
+			name:    "connection closed",
+			message: `Internal error occurred: failed calling webhook "mutate.linkerd.io": failed to call webhook: Post "https://linkerd-proxy-injector.linkerd.svc:443/?timeout=10s": EOF`,
+			want:    models.AdmissionFailureUnreachable,
+		},
+		{
+			name:    "context deadline exceeded",
+			message: `Internal error occurred: failed calling webhook "mutate.kyverno.svc-fail": failed to call webhook: Post "https://kyverno-svc.kyverno.svc:443/mutate/fail?timeout=10s": context deadline exceeded`,
+			want:    models.AdmissionFailureTimeout,
+		},
+		{
+			name:    "client timeout",
+			message: `Internal error occurred: failed calling webhook "vpod.kb.io": failed to call webhook: Post "https://webhook-service.system.svc:443/validate-v1-pod?timeout=30s": net/http: request canceled (Client.Timeout exceeded while awaiting headers)`,
+			want:    models.AdmissionFailureTimeout,
+		},
+		{
+			name:    "unknown certificate authority",
+			message: `Internal error occurred: failed calling webhook "webhook.cert-manager.io": failed to call webhook: Post "https://cert-manager-webhook.cert-manager.svc:443/validate?timeout=30s": tls: failed to verify certificate: x509: certificate signed by unknown authority`,
+			want:    models.AdmissionFailureTLS,
+		},
+		{
+			name:    "dry run unsupported",
+			message: `admission webhook "sidecar-injector.istio.io" does not support dry run`,
+			want:    models.AdmissionFailureDryRun,
+		},
+		{
+			name:    "validating admission policy",
+			message: `deployments.apps "web" is forbidden: ValidatingAdmissionPolicy 'replica-limit.example.com' with binding 'replica-limit-binding.example.com' denied request: failed expression: object.spec.replicas <= 5`,
+			want:    models.AdmissionFailurePolicy,
+		},
+		{
+			name:    "quota is not an admission webhook failure",
+			message: `pods "web" is forbidden: exceeded quota: compute, requested: cpu=2, used: cpu=9, limited: cpu=10`,
+			want:    models.AdmissionFailureOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyAdmissionError(tt.message); got != tt.want {
+				t.Fatalf("category = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// webhookObjects 返回三个Webhook及其后端：
+// gatekeeper failurePolicy未设置（即Fail），后端只有未就绪的端点；
+// istio failurePolicy为Ignore，后端Service不存在；kyverno后端正常
+func webhookObjects() []runtime.Object {
+	service := func(namespace, name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 443}}},
+		}
+	}
+	endpoints := func(namespace, service string, ready bool) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      service + "-abcde",
+				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+			},
+			Endpoints: []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)}}},
+		}
+	}
+	clientConfig := func(namespace, name string) admissionregistrationv1.WebhookClientConfig {
+		return admissionregistrationv1.WebhookClientConfig{
+			Service:  &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: name, Path: ptr.To("/validate")},
+			CABundle: []byte("ca"),
+		}
+	}
+	podRules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+	}}
+
+	return []runtime.Object{
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "gatekeeper-validating-webhook-configuration"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name:         "validation.gatekeeper.sh",
+				Rules:        podRules,
+				ClientConfig: clientConfig("gatekeeper-system", "gatekeeper-webhook-service"),
+			}},
+		},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "kyverno-resource-validating-webhook-cfg"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name:         "validate.kyverno.svc-fail",
+				Rules:        podRules,
+				ClientConfig: clientConfig("kyverno", "kyverno-svc"),
+			}},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{{
+				Name:              "sidecar-injector.istio.io",
+				Rules:             podRules,
+				FailurePolicy:     ptr.To(admissionregistrationv1.Ignore),
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"istio-injection": "enabled"}},
+				ClientConfig:      clientConfig("istio-system", "istiod"),
+			}},
+		},
+		service("gatekeeper-system", "gatekeeper-webhook-service"),
+		endpoints("gatekeeper-system", "gatekeeper-webhook-service", false),
+		service("kyverno", "kyverno-svc"),
+		endpoints("kyverno", "kyverno-svc", true),
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	var inventory models.WebhookInventory
+	callUtility(t, testutil.NewFakeClient(webhookObjects()...), LIST_WEBHOOKS, nil, &inventory)
+
+	statuses := make(map[string]string)
+	for _, webhook := range inventory.Webhooks {
+		statuses[webhook.Name] = webhook.Backend.Status
+	}
+	want := map[string]string{
+		"validation.gatekeeper.sh":  models.WebhookBackendUnhealthy,
+		"validate.kyverno.svc-fail": models.WebhookBackendHealthy,
+		"sidecar-injector.istio.io": models.WebhookBackendUnhealthy,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("webhooks = %v, want %v", statuses, want)
+	}
+	for name, status := range want {
+		if statuses[name] != status {
+			t.Errorf("backend of %s = %q, want %q", name, statuses[name], status)
+		}
+	}
+
+	if len(inventory.Findings) != 2 {
+		t.Fatalf("findings = %+v, want two", inventory.Findings)
+	}
+	critical := inventory.Findings[0]
+	if critical.Severity != models.SeverityCritical || critical.Check != "fail-closed-backend-down" ||
+		critical.Object != "ValidatingWebhookConfiguration/gatekeeper-validating-webhook-configuration" {
+		t.Fatalf("first finding = %+v, want the fail-closed gatekeeper webhook", critical)
+	}
+	for _, want := range []string{"no ready endpoints (1 not ready)", "CREATE/UPDATE", "in every namespace"} {
+		if !strings.Contains(critical.Message, want) {
+			t.Errorf("message %q does not mention %q", critical.Message, want)
+		}
+	}
+	if warning := inventory.Findings[1]; warning.Severity != models.SeverityWarning || warning.Check != "ignored-backend-down" ||
+		!strings.Contains(warning.Message, "istio-system/istiod does not exist") {
+		t.Fatalf("second finding = %+v, want the ignored istio webhook", warning)
+	}
+
+	callUtility(t, testutil.NewFakeClient(webhookObjects()...), LIST_WEBHOOKS, map[string]any{"unhealthyOnly": true, "type": "validating"}, &inventory)
+	if inventory.Count != 1 || inventory.Webhooks[0].Name != "validation.gatekeeper.sh" {
+		t.Fatalf("unhealthy validating webhooks = %+v", inventory.Webhooks)
+	}
+}
+
+func TestDiagnoseAdmissionFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		category  string
+		matched   []string
+		unhealthy bool
+		suspects  int
+		// suggestion 某条建议应包含的文本
+		suggestion string
+	}{
+		{
+			name:       "fail-closed webhook with no ready endpoints",
+			message:    `Internal error occurred: failed calling webhook "validation.gatekeeper.sh": failed to call webhook: Post "https://gatekeeper-webhook-service.gatekeeper-system.svc:443/validate?timeout=3s": dial tcp 10.96.0.10:443: connect: connection refused`,
+			category:   models.AdmissionFailureUnreachable,
+			matched:    []string{"validation.gatekeeper.sh"},
+			unhealthy:  true,
+			suggestion: "failurePolicy: Ignore",
+		},
+		{
+			name:       "matched by service when the webhook name is missing",
+			message:    `Internal error occurred: failed calling webhook: Post "https://kyverno-svc.kyverno.svc:443/validate/fail?timeout=10s": context deadline exceeded`,
+			category:   models.AdmissionFailureTimeout,
+			matched:    []string{"validate.kyverno.svc-fail"},
+			suggestion: "looks healthy now",
+		},
+		{
+			name:       "denied by policy",
+			message:    `admission webhook "validation.gatekeeper.sh" denied the request: [require-team-label] you must provide labels: {"team"}`,
+			category:   models.AdmissionFailureDenied,
+			matched:    []string{"validation.gatekeeper.sh"},
+			unhealthy:  true,
+			suggestion: `you must provide labels: {"team"}`,
+		},
+		{
+			name:       "unknown webhook lists fail-closed suspects",
+			message:    `Internal error occurred: failed calling webhook "removed.example.com": failed to call webhook: Post "https://removed.example.svc:443/?timeout=10s": no endpoints available for service "removed"`,
+			category:   models.AdmissionFailureUnreachable,
+			suspects:   1,
+			suggestion: "see suspects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diagnosis models.AdmissionDiagnosis
+			callUtility(t, testutil.NewFakeClient(webhookObjects()...), DIAGNOSE_ADMISSION_FAILURE, map[string]any{"errorMessage": tt.message}, &diagnosis)
+
+			var matched []string
+			for _, webhook := range diagnosis.Matched {
+				matched = append(matched, webhook.Name)
+			}
+			if diagnosis.Category != tt.category || !slices.Equal(matched, tt.matched) ||
+				diagnosis.BackendUnhealthy != tt.unhealthy || len(diagnosis.Suspects) != tt.suspects {
+				t.Fatalf("category %q, matched %v, unhealthy %v, %d suspects; want %q, %v, %v, %d",
+					diagnosis.Category, matched, diagnosis.BackendUnhealthy, len(diagnosis.Suspects),
+					tt.category, tt.matched, tt.unhealthy, tt.suspects)
+			}
+			if !slices.ContainsFunc(diagnosis.Suggestions, func(s string) bool { return strings.Contains(s, tt.suggestion) }) {
+				t.Errorf("suggestions %q do not mention %q", diagnosis.Suggestions, tt.suggestion)
+			}
+		})
+	}
+}
+
+func TestDiagnoseAdmissionPolicyFailure(t *testing.T) {
+	var diagnosis models.AdmissionDiagnosis
+	callUtility(t, testutil.NewFakeClient(), DIAGNOSE_ADMISSION_FAILURE, map[string]any{
+		"errorMessage": `deployments.apps "web" is forbidden: ValidatingAdmissionPolicy 'replica-limit.example.com' with binding 'replica-limit-binding.example.com' denied request: failed expression: object.spec.replicas <= 5`,
+	}, &diagnosis)
+
+	if diagnosis.Category != models.AdmissionFailurePolicy || !strings.Contains(diagnosis.Explanation, "replica-limit.example.com") {
+		t.Fatalf("diagnosis = %+v, want the ValidatingAdmissionPolicy named", diagnosis)
+	}
+}
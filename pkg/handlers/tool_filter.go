@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"path"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/handlers/interfaces"
+)
+
+// ToolFilter 按配置决定注册哪些处理程序和工具
+type ToolFilter struct {
+	// EnabledGroups 只注册这些API组的处理程序，为空表示注册所有处理程序
+	EnabledGroups []string
+	// DisabledTools 不注册的工具或提示词，支持"GET_*"形式的通配符，也可以是API组名称
+	DisabledTools []string
+}
+
+// GroupEnabled 判断处理程序所属的API组是否启用
+func (f ToolFilter) GroupEnabled(group interfaces.APIGroup) bool {
+	if len(f.EnabledGroups) > 0 && !slices.Contains(f.EnabledGroups, string(group)) {
+		return false
+	}
+	return !slices.Contains(f.DisabledTools, string(group))
+}
+
+// ToolEnabled 判断工具或提示词是否启用
+func (f ToolFilter) ToolEnabled(name string) bool {
+	for _, pattern := range f.DisabledTools {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return false
+		}
+	}
+	return true
+}
+
+// filteringRegistrar 按ToolFilter过滤工具和提示词后注册到MCP服务器，并记录实际注册的工具
+type filteringRegistrar struct {
+	server   *server.MCPServer
+	filter   ToolFilter
+	tools    []string
+	excluded []string
+}
+
+// 确保实现了接口
+var _ interfaces.ToolRegistrar = &filteringRegistrar{}
+
+// AddTool 实现接口方法，禁用的工具不会注册，因此既不会出现在tools/list中也无法调用
+func (r *filteringRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if !r.filter.ToolEnabled(tool.Name) {
+		r.excluded = append(r.excluded, tool.Name)
+		return
+	}
+	r.tools = append(r.tools, tool.Name)
+	r.server.AddTool(tool, handler)
+}
+
+// AddPrompt 实现接口方法
+func (r *filteringRegistrar) AddPrompt(prompt mcp.Prompt, handler server.PromptHandlerFunc) {
+	if !r.filter.ToolEnabled(prompt.Name) {
+		r.excluded = append(r.excluded, prompt.Name)
+		return
+	}
+	r.server.AddPrompt(prompt, handler)
+}
+
+// AddResourceTemplate 实现接口方法，资源模板不受工具过滤影响
+func (r *filteringRegistrar) AddResourceTemplate(template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc) {
+	r.server.AddResourceTemplate(template, handler)
+}
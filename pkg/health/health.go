@@ -1,19 +1,39 @@
 package health
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
 )
 
+// 后台API探测的参数
+const (
+	// pingTimeout 单次探测的超时时间
+	pingTimeout = 5 * time.Second
+	// maxPingBackoff 探测连续失败时退避等待的上限
+	maxPingBackoff = 2 * time.Minute
+)
+
 var (
 	isReady int32 // Atomic boolean: 0 = not ready, 1 = ready
 	log     logger.Logger
+	// apiStatus 后台探测记录的Kubernetes API可达状态，未启动探测时为nil
+	apiStatus *pingStatus
 )
 
+// pingStatus 最近一次成功探测的时间和最近一次失败的错误
+type pingStatus struct {
+	mu          sync.Mutex
+	staleAfter  time.Duration
+	lastSuccess time.Time
+	lastErr     error
+}
+
 // SetReady marks the application as ready.
 func SetReady() {
 	atomic.StoreInt32(&isReady, 1)
@@ -38,43 +58,56 @@ func healthzHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // readyzHandler handles readiness probes.
-// Checks if the application is ready to serve requests.
-// Here we check our atomic 'isReady' flag.
-// A more complex check could verify dependencies like the K8s client.
+// The application must be marked ready and, when the API pinger is running,
+// the Kubernetes API must have been reachable within the configured window.
 func readyzHandler(w http.ResponseWriter, r *http.Request) {
-	if atomic.LoadInt32(&isReady) == 1 {
-		// Optional: Add checks for critical dependencies like Kubernetes client connection
-		// k8sClient := client.GetClient() // Get the initialized client
-		// if k8sClient == nil {
-		// 	http.Error(w, "Kubernetes client not initialized", http.StatusServiceUnavailable)
-		//  log.Warn("Readiness check failed: K8s client not initialized")
-		// 	return
-		// }
-		// Add a simple check, e.g., try listing namespaces with a timeout (be careful not to overload API server)
-
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintln(w, "OK")
-	} else {
+	if atomic.LoadInt32(&isReady) != 1 {
 		http.Error(w, "Service not ready", http.StatusServiceUnavailable)
 		if log != nil {
 			log.Warn("Readiness check failed: Service not marked as ready")
 		}
+		return
+	}
+	if status := apiStatus; status != nil {
+		if err := status.check(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "OK")
+}
+
+// check 返回Kubernetes API在staleAfter内不可达的原因，可达时返回nil
+func (s *pingStatus) check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastSuccess.IsZero() {
+		if s.lastErr != nil {
+			return fmt.Errorf("Kubernetes API not reachable yet: %v", s.lastErr)
+		}
+		return fmt.Errorf("Kubernetes API not checked yet")
+	}
+	if age := time.Since(s.lastSuccess); age > s.staleAfter {
+		return fmt.Errorf("Kubernetes API last reachable %s ago: %v", age.Round(time.Second), s.lastErr)
 	}
+	return nil
 }
 
-// StartHealthServer starts a simple HTTP server for health checks on a separate port.
-func StartHealthServer(port int, logger logger.Logger) {
+// StartHealthServer starts a simple HTTP server for health checks on a separate address such as ":8081".
+// The returned server should be shut down together with the MCP server.
+func StartHealthServer(addr string, logger logger.Logger) *http.Server {
 	log = logger // Store logger for handlers
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", healthzHandler)
 	mux.HandleFunc("/readyz", readyzHandler)
 
 	healthServer := &http.Server{
-		Addr:    ":" + strconv.Itoa(port),
+		Addr:    addr,
 		Handler: mux,
 	}
 
-	log.Info("Starting health check server", "port", port)
+	log.Info("Starting health check server", "addr", addr)
 	// Run the server in a separate goroutine so it doesn't block the main application
 	go func() {
 		if err := healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -84,4 +117,56 @@ func StartHealthServer(port int, logger logger.Logger) {
 
 	// Initially mark as not ready until main server components are up
 	SetNotReady()
+	return healthServer
+}
+
+// StartAPIPinger 在后台每隔interval调用一次ping探测Kubernetes API，直到ctx结束。
+// 之后/readyz只在最近staleAfter内探测成功过时返回成功。
+// 探测失败时等待时间按指数退避，最长maxPingBackoff，只在状态变化时记录日志，避免在API Server故障时加重负担
+func StartAPIPinger(ctx context.Context, ping func(context.Context) error, interval, staleAfter time.Duration, logger logger.Logger) {
+	status := &pingStatus{staleAfter: staleAfter}
+	apiStatus = status
+
+	go func() {
+		wait := interval
+		failures := 0
+		for {
+			pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+			err := ping(pingCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+
+			status.mu.Lock()
+			if err == nil {
+				status.lastSuccess = time.Now()
+			} else {
+				status.lastErr = err
+			}
+			status.mu.Unlock()
+
+			switch {
+			case err == nil && failures > 0:
+				logger.Info("Kubernetes API reachable again", "failedPings", failures)
+				failures, wait = 0, interval
+			case err == nil:
+				wait = interval
+			default:
+				failures++
+				if failures == 1 {
+					logger.Warn("Kubernetes API ping failed", "error", err)
+				} else {
+					logger.Debug("Kubernetes API ping failed", "error", err, "failedPings", failures)
+				}
+				wait = min(wait*2, maxPingBackoff)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
 }
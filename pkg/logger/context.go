@@ -0,0 +1,21 @@
+package logger
+
+import "context"
+
+// contextKey 在上下文中保存日志记录器的键
+type contextKey struct{}
+
+// NewContext 返回携带指定日志记录器的上下文
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 返回上下文中的请求级日志记录器，未设置时返回默认日志记录器
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+			return l
+		}
+	}
+	return GetLogger()
+}
@@ -18,6 +18,9 @@ var _ Logger = &zapLogger{}
 // 默认日志记录器
 var defaultLogger Logger
 
+// defaultLevel 默认日志记录器的级别，由其派生的日志记录器共享，可在运行时修改
+var defaultLevel = zap.NewAtomicLevel()
+
 // Debug 实现接口方法
 func (l *zapLogger) Debug(msg string, keysAndValues ...interface{}) {
 	l.logger.Debugw(msg, keysAndValues...)
@@ -48,22 +51,27 @@ func (l *zapLogger) Sync() error {
 	return l.logger.Sync()
 }
 
-// NewZapLogger 创建新的zap日志记录器
-func NewZapLogger(level, format string) Logger {
-	var zapLevel zapcore.Level
-
+// parseLevel 解析日志级别，无法识别时使用info
+func parseLevel(level string) zapcore.Level {
 	switch strings.ToLower(level) {
 	case "debug":
-		zapLevel = zapcore.DebugLevel
-	case "info":
-		zapLevel = zapcore.InfoLevel
+		return zapcore.DebugLevel
 	case "warn", "warning":
-		zapLevel = zapcore.WarnLevel
+		return zapcore.WarnLevel
 	case "error":
-		zapLevel = zapcore.ErrorLevel
+		return zapcore.ErrorLevel
 	default:
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	}
+}
+
+// NewZapLogger 创建新的zap日志记录器
+func NewZapLogger(level, format string) Logger {
+	return newZapLogger(zap.NewAtomicLevelAt(parseLevel(level)), format)
+}
+
+// newZapLogger 创建使用指定级别的zap日志记录器
+func newZapLogger(level zap.AtomicLevel, format string) Logger {
 
 	var encoding string
 	switch strings.ToLower(format) {
@@ -76,7 +84,7 @@ func NewZapLogger(level, format string) Logger {
 	}
 
 	config := zap.NewProductionConfig()
-	config.Level = zap.NewAtomicLevelAt(zapLevel)
+	config.Level = level
 	config.Encoding = encoding
 	config.OutputPaths = []string{"stdout"}
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -92,7 +100,13 @@ func NewZapLogger(level, format string) Logger {
 
 // InitializeDefaultLogger 初始化默认日志记录器
 func InitializeDefaultLogger(level, format string) {
-	defaultLogger = NewZapLogger(level, format)
+	defaultLevel.SetLevel(parseLevel(level))
+	defaultLogger = newZapLogger(defaultLevel, format)
+}
+
+// SetLevel 修改默认日志记录器及已由其派生的日志记录器的级别，用于运行时重新加载配置
+func SetLevel(level string) {
+	defaultLevel.SetLevel(parseLevel(level))
 }
 
 // GetLogger 获取默认日志记录器
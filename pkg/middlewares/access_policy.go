@@ -0,0 +1,151 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// mutatingToolPrefixes 工具名以这些前缀开头时视为变更集群的工具，只读模式下拒绝
+var mutatingToolPrefixes = []string{
+	"CREATE_",
+	"UPDATE_",
+	"DELETE_",
+	"APPLY_",
+	"LABEL_",
+	"ANNOTATE_",
+	"SET_",
+	"RESTART_",
+	"RESTORE_",
+	"SUSPEND_",
+	"RESUME_",
+	"TRIGGER_",
+	"NODE_TAINT",
+	"NODE_UNTAINT",
+	"COPY_TO_POD",
+	"DEBUG_POD",
+}
+
+// readOnlyExemptTools 名称像变更工具但只修改服务器本地状态的工具
+var readOnlyExemptTools = map[string]bool{
+	"DELETE_NOTE": true,
+}
+
+// dryRunTools 所有写操作都按dryRun参数以服务端试运行（DryRunAll）执行的工具，只读模式下只对这些工具放行dryRun=true的调用。
+// 通用资源的创建和更新工具另见honorsDryRun；DELETE_NAMESPACE、NODE_TAINT、DEBUG_POD、COPY_TO_POD和通用删除工具等没有试运行模式，不能加入
+var dryRunTools = map[string]bool{
+	"APPLY_MANIFEST":            true,
+	"APPLY_FROM_URL":            true,
+	"APPLY_KUSTOMIZATION":       true,
+	"CREATE_NAMESPACE":          true,
+	"LABEL_RESOURCE":            true,
+	"ANNOTATE_RESOURCE":         true,
+	"RESTORE_NAMESPACE":         true,
+	"RESTART_POD":               true,
+	"SET_IMAGE":                 true,
+	"SET_ENV":                   true,
+	"SET_HPA_BOUNDS":            true,
+	"SET_STATEFULSET_PARTITION": true,
+	"DELETE_STATEFULSET_PVC":    true,
+	"TRIGGER_CRONJOB":           true,
+	"SUSPEND_CRONJOB":           true,
+	"RESUME_CRONJOB":            true,
+}
+
+// namespaceArguments 包含命名空间的工具参数，namespaces为逗号分隔的列表
+var namespaceArguments = []string{
+	"namespace",
+	"namespaces",
+	"sourceNamespace",
+	"targetNamespace",
+	"destinationNamespace",
+	"fromNamespace",
+}
+
+// AccessPolicy 按当前配置快照执行只读模式和命名空间白名单，并将快照放入上下文，
+// 使一次调用中读取到的配置保持一致。每次调用读取最新快照，配置重新加载后立即生效。
+// 这里只检查显式的命名空间参数以便尽早给出明确的错误；省略namespace时的默认命名空间和跨命名空间扫描
+// 由客户端在发往API Server的请求上按同一快照检查
+func AccessPolicy() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			cfg := config.Current()
+			ctx = config.NewContext(ctx, cfg)
+			name := request.Params.Name
+			arguments := request.GetArguments()
+
+			if cfg.ReadOnly && IsMutatingTool(name) {
+				dryRun, _ := arguments["dryRun"].(bool)
+				if !dryRun || !honorsDryRun(name) {
+					logger.FromContext(ctx).Warn("Tool call refused in read-only mode", "tool", name, "dryRun", dryRun)
+					hint := "This tool has no dry-run mode, so it is refused in read-only mode. Ask the operator to turn off read-only mode if the change is intended."
+					if honorsDryRun(name) {
+						hint = "Only read tools and dryRun=true calls are allowed. Ask the operator to turn off read-only mode if the change is intended."
+					}
+					return utils.NewToolErrorResult(models.ToolError{
+						Code:    utils.ErrorCodeRefused,
+						Message: fmt.Sprintf("%s changes the cluster and the server is in read-only mode", name),
+						Hint:    hint,
+					}), nil
+				}
+			}
+
+			if allowed := cfg.AllowedNamespaceList(); len(allowed) > 0 {
+				if allNamespaces, _ := arguments["allNamespaces"].(bool); allNamespaces {
+					logger.FromContext(ctx).Warn("Tool call refused for listing across all namespaces", "tool", name)
+					return utils.NewToolErrorResult(models.ToolError{
+						Code:    utils.ErrorCodeRefused,
+						Message: "allNamespaces=true is not allowed, this server is restricted to a namespace allowlist",
+						Hint:    fmt.Sprintf("Call once per namespace with one of the allowed namespaces: %s.", strings.Join(allowed, ", ")),
+					}), nil
+				}
+				for _, argument := range namespaceArguments {
+					value, _ := arguments[argument].(string)
+					for _, namespace := range utils.SplitCommaList(value) {
+						if slices.Contains(allowed, namespace) {
+							continue
+						}
+						logger.FromContext(ctx).Warn("Tool call refused for namespace outside the allowlist", "tool", name, "namespace", namespace)
+						return utils.NewToolErrorResult(models.ToolError{
+							Code:    utils.ErrorCodeRefused,
+							Message: fmt.Sprintf("namespace %q is not in the allowed namespaces of this server", namespace),
+							Hint:    fmt.Sprintf("Allowed namespaces: %s.", strings.Join(allowed, ", ")),
+						}), nil
+					}
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// honorsDryRun 判断工具的dryRun=true调用是否不会修改集群
+func honorsDryRun(name string) bool {
+	if dryRunTools[name] {
+		return true
+	}
+	// 通用资源的创建和更新工具（CREATE_RESOURCE、UPDATE_APPS_RESOURCE等）按dryRun试运行，删除工具不支持
+	return (strings.HasPrefix(name, "CREATE_") || strings.HasPrefix(name, "UPDATE_")) && strings.HasSuffix(name, "_RESOURCE")
+}
+
+// IsMutatingTool 判断工具是否会变更集群
+func IsMutatingTool(name string) bool {
+	if readOnlyExemptTools[name] {
+		return false
+	}
+	for _, prefix := range mutatingToolPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/config"
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+func TestAccessPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		readOnly  bool
+		allowed   string
+		tool      string
+		arguments map[string]any
+		refused   bool
+	}{
+		{name: "read tool in read-only mode", readOnly: true, tool: "LIST_PODS"},
+		{name: "mutating tool in read-only mode", readOnly: true, tool: "SET_IMAGE", refused: true},
+		{name: "dry run of supported tool", readOnly: true, tool: "SET_IMAGE", arguments: map[string]any{"dryRun": true}},
+		{name: "dry run of generic create", readOnly: true, tool: "CREATE_APPS_RESOURCE", arguments: map[string]any{"dryRun": true}},
+		{name: "dry run of delete namespace", readOnly: true, tool: "DELETE_NAMESPACE", arguments: map[string]any{"dryRun": true}, refused: true},
+		{name: "dry run of label namespace", readOnly: true, tool: "LABEL_NAMESPACE", arguments: map[string]any{"dryRun": true}, refused: true},
+		{name: "dry run of node taint", readOnly: true, tool: "NODE_TAINT", arguments: map[string]any{"dryRun": true}, refused: true},
+		{name: "dry run of debug pod", readOnly: true, tool: "DEBUG_POD", arguments: map[string]any{"dryRun": true}, refused: true},
+		{name: "dry run of copy to pod", readOnly: true, tool: "COPY_TO_POD", arguments: map[string]any{"dryRun": true}, refused: true},
+		{name: "dry run of generic delete", readOnly: true, tool: "DELETE_APPS_RESOURCE", arguments: map[string]any{"dryRun": true}, refused: true},
+		{name: "local note in read-only mode", readOnly: true, tool: "DELETE_NOTE"},
+		{name: "allowed namespace", allowed: "team-a", tool: "LIST_PODS", arguments: map[string]any{"namespace": "team-a"}},
+		{name: "other namespace", allowed: "team-a", tool: "LIST_PODS", arguments: map[string]any{"namespace": "prod"}, refused: true},
+		{name: "namespace list", allowed: "team-a,team-b", tool: "NAMESPACE_HEALTH", arguments: map[string]any{"namespaces": "team-a, prod"}, refused: true},
+		{name: "all namespaces", allowed: "team-a", tool: "LIST_PODS", arguments: map[string]any{"allNamespaces": true}, refused: true},
+		{name: "all namespaces without allowlist", tool: "LIST_PODS", arguments: map[string]any{"allNamespaces": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cfg.ReadOnly = tt.readOnly
+			cfg.AllowedNamespaces = tt.allowed
+			config.Store(cfg)
+			t.Cleanup(func() { config.Store(config.NewDefaultConfig()) })
+
+			called := false
+			handler := AccessPolicy()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				if config.FromContext(ctx).AllowedNamespaces != tt.allowed {
+					t.Error("configuration snapshot is not in the context")
+				}
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			result, err := handler(context.Background(), testutil.NewToolRequest(tt.tool, tt.arguments))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.refused {
+				if !called || result.IsError {
+					t.Fatalf("call was refused: %s", testutil.ResultText(result))
+				}
+				return
+			}
+			if called {
+				t.Fatal("refused call reached the handler")
+			}
+			toolErr, err := testutil.DecodeToolError(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if toolErr.Code != utils.ErrorCodeRefused {
+				t.Fatalf("error code = %s, want %s", toolErr.Code, utils.ErrorCodeRefused)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// 工具调用的并发类别
+const (
+	// CategoryExpensive 跨命名空间搜索、备份恢复、监听、指标和日志等高开销工具
+	CategoryExpensive = "expensive"
+	// CategoryDefault 获取、列出等普通工具
+	CategoryDefault = "default"
+)
+
+// expensiveToolMarkers 工具名包含这些片段时归为高开销类别
+var expensiveToolMarkers = []string{
+	"SEARCH",
+	"BACKUP",
+	"RESTORE",
+	"WATCH",
+	"METRICS",
+	"LOGS",
+	"TOP_CONSUMERS",
+	"RECOMMEND_RESOURCES",
+	"OWNERSHIP_GRAPH",
+	"LIST_IMAGES",
+	"IMAGE_CONSISTENCY",
+	"CRASHLOOPING",
+	"PENDING_PODS",
+	"TERMINATIONS",
+	"WORKLOAD_SECURITY",
+	"NOTIFY_ON",
+	"WAIT_FOR",
+	"COPY_TO_POD",
+	"COPY_FROM_POD",
+	"DEBUG_POD",
+	"RESTART_CONTEXT",
+	"DEPRECATED_APIS",
+	"ORPHANED_RESOURCES",
+	"SNAPSHOT",
+	"CHECK_CERTIFICATES",
+	"NODE_HEALTH",
+	"AUDIT_SERVICE_ACCOUNTS",
+	"TOPOLOGY",
+	"WORKLOAD_SPREAD",
+}
+
+// concurrencyLimiter 按全局和类别限制同时执行的工具调用数
+type concurrencyLimiter struct {
+	global       chan struct{}
+	expensive    chan struct{}
+	queueTimeout time.Duration
+	inFlight     map[string]*atomic.Int64
+	queued       atomic.Int64
+	rejected     atomic.Int64
+}
+
+// defaultLimiter 由ConcurrencyLimit创建，供ConcurrencyStatus读取状态
+var defaultLimiter *concurrencyLimiter
+
+// ConcurrencyLimit 限制同时执行的工具调用数
+// maxConcurrent为全局上限，maxExpensive为高开销工具的上限，0表示不限制；
+// 超过限制的调用最多等待queueTimeout，之后返回服务器繁忙的错误结果，而不是继续堆积
+func ConcurrencyLimit(maxConcurrent, maxExpensive int, queueTimeout time.Duration) server.ToolHandlerMiddleware {
+	limiter := &concurrencyLimiter{
+		global:       newSemaphore(maxConcurrent),
+		expensive:    newSemaphore(maxExpensive),
+		queueTimeout: queueTimeout,
+		inFlight: map[string]*atomic.Int64{
+			CategoryExpensive: {},
+			CategoryDefault:   {},
+		},
+	}
+	defaultLimiter = limiter
+
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			category := ToolCategory(request.Params.Name)
+			release, ok := limiter.acquire(ctx, category)
+			if !ok {
+				limiter.rejected.Add(1)
+				logger.FromContext(ctx).Warn("Tool call rejected, server busy",
+					"category", category,
+					"inFlight", limiter.inFlight[category].Load(),
+				)
+				return utils.NewToolErrorResult(models.ToolError{
+					Code:    utils.ErrorCodeBusy,
+					Message: fmt.Sprintf("server busy: too many %s tool calls in flight", category),
+					Hint:    "Wait for running tool calls to finish and retry, or narrow the request to make it cheaper.",
+				}), nil
+			}
+			defer release()
+			return next(ctx, request)
+		}
+	}
+}
+
+// ToolCategory 返回工具所属的并发类别
+func ToolCategory(name string) string {
+	for _, marker := range expensiveToolMarkers {
+		if strings.Contains(name, marker) {
+			return CategoryExpensive
+		}
+	}
+	return CategoryDefault
+}
+
+// ConcurrencyStatus 返回并发限制的当前状态，未启用ConcurrencyLimit时返回零值
+func ConcurrencyStatus() models.ConcurrencyStatus {
+	l := defaultLimiter
+	if l == nil {
+		return models.ConcurrencyStatus{InFlight: map[string]int64{}}
+	}
+	status := models.ConcurrencyStatus{
+		MaxConcurrent:          cap(l.global),
+		MaxConcurrentExpensive: cap(l.expensive),
+		QueueTimeout:           l.queueTimeout.String(),
+		InFlight:               make(map[string]int64, len(l.inFlight)),
+		Queued:                 l.queued.Load(),
+		Rejected:               l.rejected.Load(),
+	}
+	for category, count := range l.inFlight {
+		status.InFlight[category] = count.Load()
+	}
+	return status
+}
+
+// acquire 依次获取类别和全局的执行名额，等待超过queueTimeout或ctx结束时返回false
+func (l *concurrencyLimiter) acquire(ctx context.Context, category string) (func(), bool) {
+	l.queued.Add(1)
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	var held []chan struct{}
+	release := func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			<-held[i]
+		}
+	}
+
+	semaphores := []chan struct{}{l.global}
+	if category == CategoryExpensive {
+		semaphores = []chan struct{}{l.expensive, l.global}
+	}
+	for _, sem := range semaphores {
+		if sem == nil {
+			continue
+		}
+		// 有空闲名额时直接获取，避免与已到期的计时器竞争
+		select {
+		case sem <- struct{}{}:
+			held = append(held, sem)
+			continue
+		default:
+		}
+		select {
+		case sem <- struct{}{}:
+			held = append(held, sem)
+		case <-timer.C:
+			release()
+			return nil, false
+		case <-ctx.Done():
+			release()
+			return nil, false
+		}
+	}
+
+	counter := l.inFlight[category]
+	counter.Add(1)
+	return func() {
+		counter.Add(-1)
+		release()
+	}, true
+}
+
+// newSemaphore 创建容量为n的信号量，n不大于0时返回nil表示不限制
+func newSemaphore(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// Drainer 支持优雅关闭：开始关闭后拒绝新的工具调用，并等待进行中的调用结束
+type Drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+// NewDrainer 创建Drainer
+func NewDrainer() *Drainer {
+	return &Drainer{}
+}
+
+// Middleware 返回跟踪进行中调用的中间件，开始关闭后新的调用直接返回服务不可用的错误结果
+func (d *Drainer) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			d.mu.Lock()
+			if d.draining {
+				d.mu.Unlock()
+				logger.FromContext(ctx).Warn("Tool call rejected, server shutting down")
+				return utils.NewToolErrorResult(models.ToolError{
+					Code:    utils.ErrorCodeUnavailable,
+					Message: "server is shutting down and no longer accepts tool calls",
+					Hint:    "Retry against another replica or after the server has restarted.",
+				}), nil
+			}
+			d.inFlight.Add(1)
+			d.mu.Unlock()
+			defer d.inFlight.Done()
+			return next(ctx, request)
+		}
+	}
+}
+
+// Drain 停止接受新的工具调用并等待进行中的调用结束，ctx结束时返回ctx的错误
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
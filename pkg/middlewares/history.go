@@ -0,0 +1,254 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+const (
+	// DefaultToolHistorySize 未配置时每个会话保留的工具调用记录数
+	DefaultToolHistorySize = 100
+	// maxHistorySessions 同时保留调用记录的最大会话数，超过时移除最久未使用的会话
+	maxHistorySessions = 64
+	// defaultHistorySession 无法获取MCP会话时使用的会话
+	defaultHistorySession = "default"
+	// maxHistoryValueBytes 记录的单个参数值和错误信息的最大字节数
+	maxHistoryValueBytes = 120
+	// historyToolName 读取调用记录的工具，本身不被记录
+	historyToolName = "GET_TOOL_HISTORY"
+)
+
+// historyContentArguments 可能包含清单、Secret值或命令的参数，只记录长度
+var historyContentArguments = map[string]bool{
+	"yaml":    true,
+	"value":   true,
+	"files":   true,
+	"text":    true,
+	"content": true,
+	"data":    true,
+	"env":     true,
+	"set":     true,
+	"command": true,
+}
+
+// ToolHistoryFilter 读取调用记录时的过滤条件
+type ToolHistoryFilter struct {
+	// Tools 只返回这些工具的记录，为空时返回全部
+	Tools []string
+	// ErrorsOnly 只返回失败的调用
+	ErrorsOnly bool
+	// Limit 最多返回的记录数，只保留最新的记录，0表示不限制
+	Limit int
+}
+
+// toolHistory 按会话保存最近的工具调用，每个会话是一个固定容量的环形缓冲区
+type toolHistory struct {
+	mu       sync.Mutex
+	size     int
+	sessions map[string]*historySession
+}
+
+// historySession 一个会话的调用记录
+type historySession struct {
+	entries  []models.ToolHistoryEntry
+	next     int
+	recorded int
+	lastUsed time.Time
+}
+
+// history 全局调用记录，size为0时不记录
+var history = &toolHistory{size: DefaultToolHistorySize, sessions: make(map[string]*historySession)}
+
+// ConfigureToolHistory 设置每个会话保留的调用记录数并清空已有记录，0表示禁用记录
+func ConfigureToolHistory(size int) {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	history.size = max(size, 0)
+	history.sessions = make(map[string]*historySession)
+}
+
+// ToolHistoryEnabled 是否记录工具调用
+func ToolHistoryEnabled() bool {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	return history.size > 0
+}
+
+// SessionToolHistory 返回当前会话中符合过滤条件的调用记录，按调用时间排序
+func SessionToolHistory(ctx context.Context, filter ToolHistoryFilter) models.ToolHistory {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	result := models.ToolHistory{Entries: []models.ToolHistoryEntry{}, Capacity: history.size}
+	current, ok := history.sessions[historySessionID(ctx)]
+	if !ok {
+		return result
+	}
+	result.Recorded = current.recorded
+
+	tools := make(map[string]bool, len(filter.Tools))
+	for _, tool := range filter.Tools {
+		tools[strings.ToUpper(tool)] = true
+	}
+	// 环形缓冲区已满时next指向最旧的记录
+	for i := range current.entries {
+		entry := current.entries[(current.next+i)%len(current.entries)]
+		if len(tools) > 0 && !tools[entry.Tool] {
+			continue
+		}
+		if filter.ErrorsOnly && entry.Outcome != historyOutcomeError {
+			continue
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	if filter.Limit > 0 && len(result.Entries) > filter.Limit {
+		result.Entries = result.Entries[len(result.Entries)-filter.Limit:]
+	}
+	return result
+}
+
+// 调用结果
+const (
+	historyOutcomeSuccess = "success"
+	historyOutcomeError   = "error"
+)
+
+// recordToolHistory 将一次调用追加到其会话的记录中
+func recordToolHistory(
+	ctx context.Context,
+	id string,
+	request mcp.CallToolRequest,
+	start time.Time,
+	elapsed time.Duration,
+	result *mcp.CallToolResult,
+	err error,
+) {
+	if request.Params.Name == historyToolName {
+		return
+	}
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	if history.size == 0 {
+		return
+	}
+
+	entry := models.ToolHistoryEntry{
+		RequestID:  id,
+		Tool:       request.Params.Name,
+		StartedAt:  start,
+		DurationMs: elapsed.Milliseconds(),
+		Outcome:    historyOutcomeSuccess,
+		Arguments:  historyArguments(request.GetArguments()),
+	}
+	switch {
+	case err != nil:
+		entry.Outcome = historyOutcomeError
+		entry.Error = truncateHistoryValue(err.Error())
+	case result != nil && result.IsError:
+		entry.Outcome = historyOutcomeError
+		entry.ErrorCode, entry.Error = resultError(result)
+		entry.Error = truncateHistoryValue(entry.Error)
+	}
+
+	current := history.session(historySessionID(ctx), start)
+	if len(current.entries) < history.size {
+		current.entries = append(current.entries, entry)
+	} else {
+		current.entries[current.next] = entry
+		current.next = (current.next + 1) % len(current.entries)
+	}
+	current.recorded++
+}
+
+// session 返回会话的调用记录，不存在时创建；调用方需持有锁
+func (h *toolHistory) session(session string, now time.Time) *historySession {
+	current, ok := h.sessions[session]
+	if !ok {
+		// 会话数超过上限时移除最久未使用的会话
+		if len(h.sessions) >= maxHistorySessions {
+			oldest := ""
+			for candidate, item := range h.sessions {
+				if oldest == "" || item.lastUsed.Before(h.sessions[oldest].lastUsed) {
+					oldest = candidate
+				}
+			}
+			delete(h.sessions, oldest)
+		}
+		current = &historySession{}
+		h.sessions[session] = current
+	}
+	current.lastUsed = now
+	return current
+}
+
+// historySessionID 返回上下文中的MCP会话ID
+func historySessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+		return session.SessionID()
+	}
+	return defaultHistorySession
+}
+
+// historyArguments 复制调用参数用于记录：敏感参数和内容参数只保留长度，对象和数组只保留元素数量，过长的字符串被截断
+func historyArguments(arguments map[string]any) map[string]any {
+	if len(arguments) == 0 {
+		return nil
+	}
+	recorded := make(map[string]any, len(arguments))
+	for key, value := range arguments {
+		if utils.IsSensitiveName(key) || historyContentArguments[key] {
+			recorded[key] = utils.RedactValue(fmt.Sprint(value))
+			continue
+		}
+		switch typed := value.(type) {
+		case string:
+			recorded[key] = truncateHistoryValue(typed)
+		case map[string]any:
+			recorded[key] = fmt.Sprintf("<%d keys>", len(typed))
+		case []any:
+			recorded[key] = fmt.Sprintf("<%d items>", len(typed))
+		default:
+			recorded[key] = value
+		}
+	}
+	return recorded
+}
+
+// truncateHistoryValue 将值截断到maxHistoryValueBytes，不拆分多字节字符
+func truncateHistoryValue(value string) string {
+	if len(value) <= maxHistoryValueBytes {
+		return value
+	}
+	cut := maxHistoryValueBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + "..."
+}
+
+// resultError 返回错误结果的错误分类和消息，文本不是结构化错误时整段作为消息
+func resultError(result *mcp.CallToolResult) (string, string) {
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var toolErr models.ToolError
+		if err := json.Unmarshal([]byte(text.Text), &toolErr); err == nil && toolErr.Message != "" {
+			return toolErr.Code, toolErr.Message
+		}
+		return "", text.Text
+	}
+	return "", ""
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// progressNotificationMethod MCP进度通知的方法名
+const progressNotificationMethod = "notifications/progress"
+
+// Progress 客户端在请求的_meta中提供progressToken时，为本次调用创建进度汇报器并放入上下文，
+// 等待、监听类工具通过它发送进度通知；未提供时处理程序照常静默运行
+func Progress() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+				return next(ctx, request)
+			}
+			mcpServer := server.ServerFromContext(ctx)
+			if mcpServer == nil {
+				return next(ctx, request)
+			}
+			reporter := utils.NewProgressReporter(sessionProgressNotifier{server: mcpServer}, request.Params.Meta.ProgressToken)
+			return next(utils.ContextWithProgress(ctx, reporter), request)
+		}
+	}
+}
+
+// sessionProgressNotifier 向发起调用的会话发送进度通知
+type sessionProgressNotifier struct {
+	server *server.MCPServer
+}
+
+// NotifyProgress 实现utils.ProgressNotifier，发送失败（如会话已断开）只记录日志，不影响工具调用
+func (n sessionProgressNotifier) NotifyProgress(ctx context.Context, token mcp.ProgressToken, progress, total float64, message string) error {
+	params := map[string]any{
+		"progressToken": token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	if err := n.server.SendNotificationToClient(ctx, progressNotificationMethod, params); err != nil {
+		logger.FromContext(ctx).Debug("Failed to send progress notification", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/logger"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// toolCalls 服务器启动以来的工具调用统计
+var toolCalls = struct {
+	sync.Mutex
+	total  int64
+	errors int64
+	byTool map[string]int64
+}{byTool: make(map[string]int64)}
+
+// RequestID 为每次工具调用生成请求ID
+// 请求ID和带有requestId、tool字段的日志记录器写入上下文，供处理程序通过logger.FromContext获取；
+// 调用结束后记录耗时并追加到会话的调用记录，并在结果的_meta.requestId中返回请求ID，便于将客户端反馈与服务器日志关联
+func RequestID() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := utils.NewRequestID()
+			log := logger.GetLogger().With("requestId", id, "tool", request.Params.Name)
+			ctx = logger.NewContext(utils.ContextWithRequestID(ctx, id), log)
+
+			start := time.Now()
+			log.Debug("Tool call started")
+			result, err := next(ctx, request)
+			duration := time.Since(start).Round(time.Millisecond)
+
+			recordToolCall(request.Params.Name, err != nil || (result != nil && result.IsError))
+			recordToolHistory(ctx, id, request, start, duration, result, err)
+			switch {
+			case err != nil:
+				log.Error("Tool call failed", "duration", duration, "error", err)
+			case result != nil && result.IsError:
+				log.Warn("Tool call returned an error result", "duration", duration)
+			default:
+				log.Info("Tool call finished", "duration", duration)
+			}
+			return utils.WithRequestIDMeta(result, id), err
+		}
+	}
+}
+
+// ToolCallStats 返回服务器启动以来的工具调用次数统计
+func ToolCallStats() models.ToolCallStats {
+	toolCalls.Lock()
+	defer toolCalls.Unlock()
+	stats := models.ToolCallStats{
+		Total:  toolCalls.total,
+		Errors: toolCalls.errors,
+		ByTool: make(map[string]int64, len(toolCalls.byTool)),
+	}
+	for name, count := range toolCalls.byTool {
+		stats.ByTool[name] = count
+	}
+	return stats
+}
+
+// recordToolCall 记录一次工具调用
+func recordToolCall(name string, failed bool) {
+	toolCalls.Lock()
+	defer toolCalls.Unlock()
+	toolCalls.total++
+	if failed {
+		toolCalls.errors++
+	}
+	toolCalls.byTool[name]++
+}
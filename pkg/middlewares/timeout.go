@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/utils"
+)
+
+// ToolTimeout 为每次工具调用派生带超时的上下文
+// 调用方可以通过timeoutSeconds参数覆盖默认值，但不超过maxSeconds；
+// 超时导致的失败统一转换为结构化的超时错误，处理程序已返回部分结果时保持不变
+func ToolTimeout(defaultSeconds, maxSeconds int) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			timeout := utils.ParseTimeoutSeconds(request, defaultSeconds, maxSeconds)
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := utils.ContextWithToolTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(ctx, request)
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) || utils.IsTimeoutResult(result) {
+				return result, err
+			}
+			// 处理程序在截止时间前已经完成
+			if err == nil && result != nil && !result.IsError {
+				return result, nil
+			}
+			return utils.NewTimeoutResult(ctx, nil), nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// AvailabilityFinding 可用性审计发现的风险
+type AvailabilityFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	// Object 相关对象，格式为"Kind/name"
+	Object  string `json:"object"`
+	Message string `json:"message"`
+	// Suggestion 具体的修复建议
+	Suggestion string `json:"suggestion"`
+	// Manifest 可直接应用的修复清单，例如按工作负载选择器生成的PodDisruptionBudget
+	Manifest string `json:"manifest,omitempty"`
+}
+
+// WorkloadAvailability 单个工作负载的可用性情况
+type WorkloadAvailability struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Replicas int32  `json:"replicas"`
+	// RunningPods 选择器匹配的未终止Pod数量
+	RunningPods int `json:"runningPods"`
+	// Nodes Pod所在的不同节点数量
+	Nodes int `json:"nodes"`
+	// Zones Pod所在的不同可用区数量，节点没有可用区标签时为0
+	Zones int `json:"zones"`
+	// PodDisruptionBudgets 覆盖该工作负载Pod的PDB
+	PodDisruptionBudgets []string `json:"podDisruptionBudgets,omitempty"`
+	// Spread Pod模板是否配置了Pod反亲和性或拓扑分布约束
+	Spread bool `json:"spread"`
+}
+
+// AvailabilityReport 命名空间可用性审计结果
+type AvailabilityReport struct {
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Score 可用性评分（0-100），每个critical扣15分、warning扣5分、info扣1分
+	Score     int                    `json:"score"`
+	Summary   SeverityCounts         `json:"summary"`
+	Workloads []WorkloadAvailability `json:"workloads"`
+	Findings  []AvailabilityFinding  `json:"findings"`
+	// Notes 审计过程中的说明，例如无法读取节点时可用区检查被跳过
+	Notes       []string  `json:"notes,omitempty"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// BackupResult 命名空间备份结果
+type BackupResult struct {
+	Namespace      string         `json:"namespace"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	ObjectCount    int            `json:"objectCount"`
+	CountsByKind   map[string]int `json:"countsByKind"`
+	SkippedKinds   []string       `json:"skippedKinds,omitempty"`
+	SkippedObjects int            `json:"skippedObjects,omitempty"`
+	Redacted       int            `json:"redactedSecrets,omitempty"`
+	Size           int            `json:"size"`
+	FilePath       string         `json:"filePath,omitempty"`
+	Manifest       string         `json:"manifest,omitempty"`
+	Errors         []string       `json:"errors,omitempty"`
+	Note           string         `json:"note,omitempty"`
+}
+
+// RestoreResult 命名空间恢复结果
+type RestoreResult struct {
+	Namespace    string        `json:"namespace,omitempty"`
+	DryRun       bool          `json:"dryRun"`
+	Items        []ApplyResult `json:"items"`
+	SuccessCount int           `json:"successCount"`
+	ErrorCount   int           `json:"errorCount"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// 证书到期检查的状态
+const (
+	CertificateExpired  = "expired"
+	CertificateExpiring = "expiring"
+	CertificateValid    = "valid"
+	// CertificateInvalid Secret中的证书无法解析
+	CertificateInvalid = "invalid"
+	// CertificateMissing cert-manager Certificate引用的Secret不存在
+	CertificateMissing = "missing"
+)
+
+// X509Summary 证书链中叶子证书的摘要，不包含私钥
+type X509Summary struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	DNSNames     []string  `json:"dnsNames,omitempty"`
+	IPAddresses  []string  `json:"ipAddresses,omitempty"`
+	SerialNumber string    `json:"serialNumber"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	SelfSigned   bool      `json:"selfSigned"`
+	IsCA         bool      `json:"isCA"`
+	// ChainLength tls.crt中的证书数量
+	ChainLength int `json:"chainLength"`
+}
+
+// CertificateIngressUsage 引用TLS Secret的Ingress及证书是否覆盖其主机名
+type CertificateIngressUsage struct {
+	Ingress string   `json:"ingress"`
+	Hosts   []string `json:"hosts,omitempty"`
+	// UnmatchedHosts 证书的SAN不覆盖的主机名
+	UnmatchedHosts []string `json:"unmatchedHosts,omitempty"`
+}
+
+// CertManagerStatus cert-manager Certificate的状态
+type CertManagerStatus struct {
+	Name        string     `json:"name"`
+	Issuer      string     `json:"issuer,omitempty"`
+	Ready       string     `json:"ready"`
+	Reason      string     `json:"reason,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	NotAfter    *time.Time `json:"notAfter,omitempty"`
+	RenewalTime *time.Time `json:"renewalTime,omitempty"`
+}
+
+// CertificateExpiry 一个TLS Secret的证书到期信息
+type CertificateExpiry struct {
+	Namespace  string `json:"namespace"`
+	SecretName string `json:"secretName"`
+	// Status 到期状态：expired、expiring、valid、invalid或missing
+	Status string `json:"status"`
+	// DaysUntilExpiry 距离到期的天数，已过期时为负数
+	DaysUntilExpiry *int                      `json:"daysUntilExpiry,omitempty"`
+	Certificate     *X509Summary              `json:"certificate,omitempty"`
+	Ingresses       []CertificateIngressUsage `json:"ingresses,omitempty"`
+	CertManager     *CertManagerStatus        `json:"certManager,omitempty"`
+	Problems        []string                  `json:"problems,omitempty"`
+}
+
+// CertificateReport TLS证书到期检查结果，按到期时间从近到远排序
+type CertificateReport struct {
+	Namespace string `json:"namespace,omitempty"`
+	WarnDays  int    `json:"warnDays"`
+	// CertManager 集群是否安装了cert-manager
+	CertManager    bool                `json:"certManager"`
+	Scanned        int                 `json:"scanned"`
+	CountsByStatus map[string]int      `json:"countsByStatus"`
+	Items          []CertificateExpiry `json:"items"`
+	Errors         []string            `json:"errors,omitempty"`
+	RetrievedAt    time.Time           `json:"retrievedAt"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// 资源健康判定
+const (
+	VerdictHealthy  = "healthy"
+	VerdictDegraded = "degraded"
+	VerdictUnknown  = "unknown"
+)
+
+// ResourceCondition 规范化后的status.conditions条目
+type ResourceCondition struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+	// Age 距离lastTransitionTime的时间
+	Age                string `json:"age,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+}
+
+// ResourceConditions 单个资源的状态条件与健康判定
+type ResourceConditions struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Verdict 健康判定：healthy、degraded或unknown
+	Verdict string `json:"verdict"`
+	// ReadyCondition 用于判定的就绪类条件类型，没有时为空
+	ReadyCondition string `json:"readyCondition,omitempty"`
+	// Summary 判定依据
+	Summary string `json:"summary"`
+	// Blocking 导致非healthy判定的条件
+	Blocking           *ResourceCondition  `json:"blocking,omitempty"`
+	Conditions         []ResourceCondition `json:"conditions"`
+	Generation         int64               `json:"generation,omitempty"`
+	ObservedGeneration int64               `json:"observedGeneration,omitempty"`
+}
+
+// ResourceConditionsList 一组资源的状态条件汇总
+type ResourceConditionsList struct {
+	Kind          string `json:"kind"`
+	APIVersion    string `json:"apiVersion"`
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Scanned 本页检查的资源数量
+	Scanned int `json:"scanned"`
+	// CountsByVerdict 本页各判定的资源数量，不受unhealthyOnly影响
+	CountsByVerdict map[string]int       `json:"countsByVerdict"`
+	Items           []ResourceConditions `json:"items"`
+	ListPagination
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// SecretKeyInfo 定义Secret中单个键的信息
+type SecretKeyInfo struct {
+	Key    string `json:"key"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// SecretKeysResponse 定义Secret键列表响应结构
+type SecretKeysResponse struct {
+	Name           string          `json:"name"`
+	Namespace      string          `json:"namespace"`
+	Type           string          `json:"type"`
+	Immutable      bool            `json:"immutable,omitempty"`
+	Count          int             `json:"count"`
+	Keys           []SecretKeyInfo `json:"keys"`
+	ValuesRevealed bool            `json:"valuesRevealed"`
+	Note           string          `json:"note,omitempty"`
+	RetrievedAt    time.Time       `json:"retrievedAt"`
+}
+
+// ConfigMapBinaryInfo 定义ConfigMap二进制数据键的信息
+type ConfigMapBinaryInfo struct {
+	Key  string `json:"key"`
+	Size int    `json:"size"`
+}
+
+// ConfigMapResponse 定义ConfigMap内容响应结构
+type ConfigMapResponse struct {
+	Name          string                `json:"name"`
+	Namespace     string                `json:"namespace"`
+	Immutable     bool                  `json:"immutable,omitempty"`
+	Data          map[string]string     `json:"data,omitempty"`
+	TruncatedKeys []string              `json:"truncatedKeys,omitempty"`
+	BinaryData    []ConfigMapBinaryInfo `json:"binaryData,omitempty"`
+	MaxValueBytes int                   `json:"maxValueBytes"`
+	RetrievedAt   time.Time             `json:"retrievedAt"`
+}
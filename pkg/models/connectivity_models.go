@@ -0,0 +1,152 @@
+package models
+
+import "time"
+
+// 分析工具发现问题的严重程度
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// SeverityRank 返回严重程度的排序权重，critical最小，未知的严重程度与info相同
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Finding 定义分析工具发现的问题
+type Finding struct {
+	Severity string   `json:"severity"`
+	Check    string   `json:"check"`
+	Message  string   `json:"message"`
+	Details  []string `json:"details,omitempty"`
+}
+
+// SeverityCounts 按严重程度统计的发现数量
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Info     int `json:"info"`
+}
+
+// ServicePortCheck 定义Service端口的检查结果
+type ServicePortCheck struct {
+	Name            string `json:"name,omitempty"`
+	Protocol        string `json:"protocol"`
+	Port            int32  `json:"port"`
+	TargetPort      string `json:"targetPort"`
+	NodePort        int32  `json:"nodePort,omitempty"`
+	ContainerPort   int32  `json:"containerPort,omitempty"`
+	DeclaredByPods  bool   `json:"declaredByPods"`
+	AllowedByPolicy bool   `json:"allowedByPolicy"`
+}
+
+// ServiceAnalysis 定义Service连通性分析结果
+type ServiceAnalysis struct {
+	Name              string             `json:"name"`
+	Namespace         string             `json:"namespace"`
+	Type              string             `json:"type"`
+	ClusterIP         string             `json:"clusterIP,omitempty"`
+	Selector          map[string]string  `json:"selector,omitempty"`
+	Ports             []ServicePortCheck `json:"ports"`
+	MatchingPods      int                `json:"matchingPods"`
+	ReadyPods         int                `json:"readyPods"`
+	ReadyEndpoints    int                `json:"readyEndpoints"`
+	NotReadyEndpoints int                `json:"notReadyEndpoints"`
+	ExternalAddresses []string           `json:"externalAddresses,omitempty"`
+	NetworkPolicies   []string           `json:"networkPolicies,omitempty"`
+	Findings          []Finding          `json:"findings"`
+	RetrievedAt       time.Time          `json:"retrievedAt"`
+}
+
+// IngressBackendCheck 定义Ingress后端的检查结果
+type IngressBackendCheck struct {
+	Host           string `json:"host,omitempty"`
+	Path           string `json:"path,omitempty"`
+	Service        string `json:"service,omitempty"`
+	Port           string `json:"port,omitempty"`
+	ServiceExists  bool   `json:"serviceExists"`
+	PortExists     bool   `json:"portExists"`
+	ReadyEndpoints int    `json:"readyEndpoints"`
+}
+
+// IngressAnalysis 定义Ingress连通性分析结果
+type IngressAnalysis struct {
+	Name              string                `json:"name"`
+	Namespace         string                `json:"namespace"`
+	IngressClass      string                `json:"ingressClass,omitempty"`
+	Controller        string                `json:"controller,omitempty"`
+	ExternalAddresses []string              `json:"externalAddresses,omitempty"`
+	Backends          []IngressBackendCheck `json:"backends"`
+	Findings          []Finding             `json:"findings"`
+	RetrievedAt       time.Time             `json:"retrievedAt"`
+}
+
+// DNSControlPlaneView 从API对象推断的Service应解析到的名称和地址
+type DNSControlPlaneView struct {
+	ServiceExists     bool     `json:"serviceExists"`
+	Type              string   `json:"type,omitempty"`
+	ClusterIP         string   `json:"clusterIP,omitempty"`
+	Headless          bool     `json:"headless"`
+	ExternalName      string   `json:"externalName,omitempty"`
+	ExpectedNames     []string `json:"expectedNames"`
+	ExpectedAddresses []string `json:"expectedAddresses,omitempty"`
+	PodHostnames      []string `json:"podHostnames,omitempty"`
+	EndpointSlices    int      `json:"endpointSlices"`
+	ReadyEndpoints    int      `json:"readyEndpoints"`
+	NotReadyEndpoints int      `json:"notReadyEndpoints"`
+	// OtherNamespaces 其他命名空间中存在的同名Service
+	OtherNamespaces []string `json:"otherNamespaces,omitempty"`
+}
+
+// DNSServerStatus 集群DNS（CoreDNS/kube-dns）的部署状态
+type DNSServerStatus struct {
+	Deployment      string `json:"deployment,omitempty"`
+	Replicas        int32  `json:"replicas"`
+	ReadyReplicas   int32  `json:"readyReplicas"`
+	ServiceIP       string `json:"serviceIP,omitempty"`
+	ReadyEndpoints  int    `json:"readyEndpoints"`
+	CheckFailedWith string `json:"checkFailedWith,omitempty"`
+}
+
+// DNSLookup 在Pod中解析一个名称的结果
+type DNSLookup struct {
+	Name      string   `json:"name"`
+	Command   string   `json:"command,omitempty"`
+	Resolved  bool     `json:"resolved"`
+	Addresses []string `json:"addresses,omitempty"`
+	// MatchesExpected 解析结果是否与控制平面的预期地址一致
+	MatchesExpected bool   `json:"matchesExpected"`
+	Output          string `json:"output,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// DNSDataPlaneView 从Pod内部实际解析的结果
+type DNSDataPlaneView struct {
+	Pod         string      `json:"pod"`
+	Namespace   string      `json:"namespace"`
+	Container   string      `json:"container,omitempty"`
+	Skipped     string      `json:"skipped,omitempty"`
+	UsedBusybox bool        `json:"usedBusybox,omitempty"`
+	Lookups     []DNSLookup `json:"lookups,omitempty"`
+}
+
+// DNSCheckReport 定义Service的DNS解析检查结果，并列控制平面视图和数据平面结果
+type DNSCheckReport struct {
+	Service       string              `json:"service"`
+	Namespace     string              `json:"namespace"`
+	QueryName     string              `json:"queryName,omitempty"`
+	ClusterDomain string              `json:"clusterDomain"`
+	ControlPlane  DNSControlPlaneView `json:"controlPlane"`
+	DNSServer     DNSServerStatus     `json:"dnsServer"`
+	DataPlane     *DNSDataPlaneView   `json:"dataPlane,omitempty"`
+	Findings      []Finding           `json:"findings"`
+	RetrievedAt   time.Time           `json:"retrievedAt"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// CRDVersionInfo 定义CRD版本信息
+type CRDVersionInfo struct {
+	Name       string `json:"name"`
+	Served     bool   `json:"served"`
+	Storage    bool   `json:"storage"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// CRDInfo 定义CRD摘要信息
+type CRDInfo struct {
+	Name          string           `json:"name"`
+	Group         string           `json:"group"`
+	Kind          string           `json:"kind"`
+	Plural        string           `json:"plural"`
+	Scope         string           `json:"scope"`
+	ShortNames    []string         `json:"shortNames,omitempty"`
+	Versions      []CRDVersionInfo `json:"versions"`
+	ResourceCount *int64           `json:"resourceCount,omitempty"`
+	CountError    string           `json:"countError,omitempty"`
+	CreationTime  time.Time        `json:"creationTime"`
+}
+
+// CRDListResponse 定义CRD列表响应结构
+type CRDListResponse struct {
+	Count       int       `json:"count"`
+	Filter      string    `json:"filter,omitempty"`
+	CRDs        []CRDInfo `json:"crds"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// CRDSchemaField 定义CRD模式中的单个字段
+type CRDSchemaField struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Required    bool             `json:"required,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Fields      []CRDSchemaField `json:"fields,omitempty"`
+}
+
+// CRDSchemaResponse 定义CRD模式说明的响应结构，文本格式与 kubectl explain 一致
+type CRDSchemaResponse struct {
+	Kind        string           `json:"kind"`
+	APIVersion  string           `json:"apiVersion"`
+	Resource    string           `json:"resource"`
+	Scope       string           `json:"scope"`
+	ShortNames  []string         `json:"shortNames,omitempty"`
+	Field       string           `json:"field,omitempty"`
+	FieldType   string           `json:"fieldType,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Recursive   bool             `json:"recursive"`
+	Fields      []CRDSchemaField `json:"fields,omitempty"`
+}
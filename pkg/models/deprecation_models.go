@@ -0,0 +1,95 @@
+package models
+
+// API版本相对于目标Kubernetes版本的状态
+const (
+	// APIStatusRemoved 目标版本已不再提供该API版本
+	APIStatusRemoved = "removed"
+	// APIStatusDeprecated 目标版本中该API版本已弃用但仍可用
+	APIStatusDeprecated = "deprecated"
+)
+
+// 节点kubelet与控制面的版本偏差状态
+const (
+	// SkewStatusOK 版本偏差在支持范围内
+	SkewStatusOK = "ok"
+	// SkewStatusNewer kubelet版本高于控制面
+	SkewStatusNewer = "newer-than-control-plane"
+	// SkewStatusUnsupported kubelet比当前控制面落后超过支持的次版本数
+	SkewStatusUnsupported = "unsupported-skew"
+	// SkewStatusBlocksUpgrade 控制面升级到目标版本后kubelet的偏差将超出支持范围，需要先升级节点
+	SkewStatusBlocksUpgrade = "blocks-upgrade"
+)
+
+// DeprecatedAPI 弃用API表中的一项：某个API版本中的一种资源类型
+type DeprecatedAPI struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// DeprecatedIn 开始弃用的Kubernetes次版本，例如"1.21"
+	DeprecatedIn string `json:"deprecatedIn"`
+	// RemovedIn 不再提供的Kubernetes次版本
+	RemovedIn string `json:"removedIn"`
+	// Replacement 替代的apiVersion，没有直接替代时为空
+	Replacement string `json:"replacement,omitempty"`
+	Note        string `json:"note,omitempty"`
+}
+
+// DeprecatedAPIFinding 仍在使用弃用API版本的对象
+type DeprecatedAPIFinding struct {
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	// APIVersion 对象的管理者使用的弃用API版本
+	APIVersion string `json:"apiVersion"`
+	// Sources 发现该版本的位置：managedFields中的字段管理器名称，或last-applied-configuration
+	Sources     []string `json:"sources"`
+	Status      string   `json:"status"`
+	RemovedIn   string   `json:"removedIn"`
+	Replacement string   `json:"replacement,omitempty"`
+	Note        string   `json:"note,omitempty"`
+}
+
+// ServedDeprecatedAPI 集群当前仍在提供的弃用API版本
+type ServedDeprecatedAPI struct {
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind"`
+	Status      string `json:"status"`
+	RemovedIn   string `json:"removedIn"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// NamespaceMigration 一个命名空间中需要迁移的对象数量
+type NamespaceMigration struct {
+	Namespace string `json:"namespace"`
+	Objects   int    `json:"objects"`
+}
+
+// NodeVersionSkew 节点kubelet与控制面的版本偏差
+type NodeVersionSkew struct {
+	Node           string `json:"node"`
+	KubeletVersion string `json:"kubeletVersion"`
+	// Skew 控制面比kubelet高出的次版本数，kubelet更新时为负数
+	Skew    int    `json:"skew"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// DeprecatedAPIReport CHECK_DEPRECATED_APIS的结果
+type DeprecatedAPIReport struct {
+	ServerVersion string `json:"serverVersion"`
+	TargetVersion string `json:"targetVersion"`
+	// Checked 检查的弃用API表项数量（在目标版本前已弃用或移除的项）
+	Checked int `json:"checked"`
+	// Scanned 扫描的对象数量
+	Scanned  int                    `json:"scanned"`
+	Findings []DeprecatedAPIFinding `json:"findings"`
+	// Removed 使用目标版本中已移除的API版本的对象数量，升级前必须迁移
+	Removed     int                   `json:"removed"`
+	Deprecated  int                   `json:"deprecated"`
+	ByNamespace []NamespaceMigration  `json:"byNamespace,omitempty"`
+	Served      []ServedDeprecatedAPI `json:"served,omitempty"`
+	Nodes       []NodeVersionSkew     `json:"nodes"`
+	// SkewIssues 版本偏差状态不是ok的节点数量
+	SkewIssues int      `json:"skewIssues"`
+	Warnings   []string `json:"warnings,omitempty"`
+	Hint       string   `json:"hint,omitempty"`
+}
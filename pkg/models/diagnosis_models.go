@@ -0,0 +1,369 @@
+package models
+
+import "time"
+
+// 容器在Pod中的角色
+const (
+	ContainerRoleMain = "main"
+	ContainerRoleInit = "init"
+	// ContainerRoleSidecar 原生边车容器，即restartPolicy为Always的初始化容器
+	ContainerRoleSidecar   = "sidecar"
+	ContainerRoleEphemeral = "ephemeral"
+)
+
+// ContainerStatusSummary 容器的角色和当前状态
+type ContainerStatusSummary struct {
+	Name string `json:"name"`
+	// Role 容器角色：main、init、sidecar或ephemeral
+	Role         string `json:"role"`
+	State        string `json:"state"`
+	Reason       string `json:"reason,omitempty"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+}
+
+// InitContainerProgress Pod尚未完成初始化时的进度
+type InitContainerProgress struct {
+	// Status 与kubectl get pods一致的状态，例如Init:1/3、Init:CrashLoopBackOff
+	Status    string `json:"status"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+	// Blocking 阻塞初始化的容器
+	Blocking string `json:"blocking"`
+	// BlockingSidecar 阻塞的容器是否为尚未启动完成的边车容器
+	BlockingSidecar bool   `json:"blockingSidecar,omitempty"`
+	State           string `json:"state"`
+	Reason          string `json:"reason,omitempty"`
+	Message         string `json:"message,omitempty"`
+	ExitCode        *int32 `json:"exitCode,omitempty"`
+	RestartCount    int32  `json:"restartCount"`
+}
+
+// ContainerDiagnosis 定义容器诊断信息
+type ContainerDiagnosis struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	Init  bool   `json:"init,omitempty"`
+	// Sidecar 是否为原生边车容器（restartPolicy为Always的初始化容器）
+	Sidecar               bool              `json:"sidecar,omitempty"`
+	Ready                 bool              `json:"ready"`
+	RestartCount          int32             `json:"restartCount"`
+	State                 string            `json:"state"`
+	Reason                string            `json:"reason,omitempty"`
+	Message               string            `json:"message,omitempty"`
+	ExitCode              *int32            `json:"exitCode,omitempty"`
+	LastTerminationReason string            `json:"lastTerminationReason,omitempty"`
+	LastExitCode          *int32            `json:"lastExitCode,omitempty"`
+	Requests              map[string]string `json:"requests,omitempty"`
+	Limits                map[string]string `json:"limits,omitempty"`
+	HasReadinessProbe     bool              `json:"hasReadinessProbe"`
+	HasLivenessProbe      bool              `json:"hasLivenessProbe"`
+	Logs                  []string          `json:"logs,omitempty"`
+	PreviousLogs          []string          `json:"previousLogs,omitempty"`
+	LogError              string            `json:"logError,omitempty"`
+	LogErrorCount         int               `json:"logErrorCount"`
+	LogWarningCount       int               `json:"logWarningCount"`
+	TopLogErrors          []string          `json:"topLogErrors,omitempty"`
+}
+
+// PodSchedulingInfo 定义Pod调度信息
+type PodSchedulingInfo struct {
+	NodeName             string            `json:"nodeName,omitempty"`
+	NodeSelector         map[string]string `json:"nodeSelector,omitempty"`
+	HasAffinity          bool              `json:"hasAffinity"`
+	TolerationCount      int               `json:"tolerationCount"`
+	PriorityClassName    string            `json:"priorityClassName,omitempty"`
+	UnschedulableReasons []string          `json:"unschedulableReasons,omitempty"`
+}
+
+// DiagnosisEvent 定义诊断中使用的事件信息
+type DiagnosisEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count"`
+}
+
+// QuotaUsage 定义ResourceQuota使用情况
+type QuotaUsage struct {
+	Name string            `json:"name"`
+	Hard map[string]string `json:"hard"`
+	Used map[string]string `json:"used"`
+}
+
+// LimitRangeItemInfo 定义LimitRange约束项
+type LimitRangeItemInfo struct {
+	Type           string            `json:"type"`
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+}
+
+// LimitRangeInfo 定义LimitRange信息
+type LimitRangeInfo struct {
+	Name   string               `json:"name"`
+	Limits []LimitRangeItemInfo `json:"limits"`
+}
+
+// ProbableCause 定义诊断规则推断出的可能原因
+type ProbableCause struct {
+	Cause      string   `json:"cause"`
+	Confidence int      `json:"confidence"` // 0-100
+	Container  string   `json:"container,omitempty"`
+	Evidence   []string `json:"evidence"`
+	Suggestion string   `json:"suggestion,omitempty"`
+}
+
+// PodDiagnosisReport 定义Pod诊断报告
+type PodDiagnosisReport struct {
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	Phase          string            `json:"phase"`
+	Reason         string            `json:"reason,omitempty"`
+	Message        string            `json:"message,omitempty"`
+	QOSClass       string            `json:"qosClass,omitempty"`
+	ServiceAccount string            `json:"serviceAccount,omitempty"`
+	StartTime      *time.Time        `json:"startTime,omitempty"`
+	Conditions     map[string]string `json:"conditions,omitempty"`
+	// InitProgress Pod卡在初始化阶段时的进度和阻塞的初始化容器
+	InitProgress *InitContainerProgress `json:"initProgress,omitempty"`
+	// InitContainers 初始化容器和边车容器，按定义顺序排列
+	InitContainers []ContainerDiagnosis `json:"initContainers,omitempty"`
+	Containers     []ContainerDiagnosis `json:"containers"`
+	Scheduling     PodSchedulingInfo    `json:"scheduling"`
+	WarningEvents  []DiagnosisEvent     `json:"warningEvents"`
+	ResourceQuotas []QuotaUsage         `json:"resourceQuotas,omitempty"`
+	LimitRanges    []LimitRangeInfo     `json:"limitRanges,omitempty"`
+	ProbableCauses []ProbableCause      `json:"probableCauses"`
+	RetrievedAt    time.Time            `json:"retrievedAt"`
+}
+
+// CrashLoopContainer 反复重启或无法启动的容器
+type CrashLoopContainer struct {
+	Pod          string `json:"pod"`
+	Namespace    string `json:"namespace"`
+	Container    string `json:"container"`
+	Init         bool   `json:"init,omitempty"`
+	Sidecar      bool   `json:"sidecar,omitempty"`
+	NodeName     string `json:"nodeName,omitempty"`
+	RestartCount int32  `json:"restartCount"`
+	// WaitingReason 容器当前处于等待状态的原因，例如CrashLoopBackOff
+	WaitingReason  string `json:"waitingReason,omitempty"`
+	WaitingMessage string `json:"waitingMessage,omitempty"`
+	// LastExitCode 上一个容器实例的退出码
+	LastExitCode *int32 `json:"lastExitCode,omitempty"`
+	LastReason   string `json:"lastReason,omitempty"`
+	// LastRestartAt 上一个容器实例结束的时间
+	LastRestartAt  *time.Time `json:"lastRestartAt,omitempty"`
+	LastRestartAgo string     `json:"lastRestartAgo,omitempty"`
+	PreviousLogs   []string   `json:"previousLogs,omitempty"`
+	LogError       string     `json:"logError,omitempty"`
+}
+
+// CrashLoopReport 崩溃循环检测结果
+type CrashLoopReport struct {
+	Namespace        string               `json:"namespace,omitempty"`
+	LabelSelector    string               `json:"labelSelector,omitempty"`
+	RestartThreshold int32                `json:"restartThreshold"`
+	ScannedPods      int                  `json:"scannedPods"`
+	Count            int                  `json:"count"`
+	Containers       []CrashLoopContainer `json:"containers"`
+	// Truncated 结果超过maxResults被截断
+	Truncated   bool      `json:"truncated,omitempty"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// SchedulingReason 从FailedScheduling事件中解析出的一类调度失败原因
+type SchedulingReason struct {
+	// Category 原因类别，例如insufficient-resource、taint、node-affinity
+	Category string `json:"category"`
+	// Nodes 因此原因被排除的节点数量
+	Nodes   int    `json:"nodes"`
+	Message string `json:"message"`
+}
+
+// SchedulingFinding 结合节点实时分配情况得出的结论
+type SchedulingFinding struct {
+	Category    string   `json:"category"`
+	Message     string   `json:"message"`
+	Evidence    []string `json:"evidence,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+}
+
+// PendingPodAnalysis 单个Pending Pod的调度分析
+type PendingPodAnalysis struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	PendingFor string            `json:"pendingFor"`
+	Requests   map[string]string `json:"requests,omitempty"`
+	// SchedulerMessage 最近一次FailedScheduling事件的消息
+	SchedulerMessage string             `json:"schedulerMessage,omitempty"`
+	Reasons          []SchedulingReason `json:"reasons,omitempty"`
+	// CandidateNodes 参与评估的节点数量
+	CandidateNodes int `json:"candidateNodes"`
+	// FittingNodes 按当前分配情况可以容纳该Pod的节点，最多列出10个
+	FittingNodes []string            `json:"fittingNodes,omitempty"`
+	CanFit       bool                `json:"canFit"`
+	Findings     []SchedulingFinding `json:"findings"`
+}
+
+// PendingPodsReport Pending Pod调度分析结果
+type PendingPodsReport struct {
+	Namespace   string               `json:"namespace,omitempty"`
+	Count       int                  `json:"count"`
+	Pods        []PendingPodAnalysis `json:"pods"`
+	Truncated   bool                 `json:"truncated,omitempty"`
+	RetrievedAt time.Time            `json:"retrievedAt"`
+}
+
+// ContainerTermination 容器的一次异常终止
+type ContainerTermination struct {
+	// Workload 容器所属的工作负载，格式为"Kind/name"
+	Workload     string    `json:"workload"`
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container"`
+	Reason       string    `json:"reason"`
+	ExitCode     int32     `json:"exitCode"`
+	Signal       int32     `json:"signal,omitempty"`
+	FinishedAt   time.Time `json:"finishedAt"`
+	RestartCount int32     `json:"restartCount"`
+	// Current 终止发生在当前实例（容器未被重启）而非上一个实例
+	Current       bool   `json:"current,omitempty"`
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty"`
+	// MemoryUsage 来自metrics-server的当前内存用量
+	MemoryUsage        string   `json:"memoryUsage,omitempty"`
+	MemoryUsagePercent *float64 `json:"memoryUsagePercent,omitempty"`
+	// Events 与该Pod相关的Killing/BackOff事件，格式为"时间 原因(次数): 消息"
+	Events []string `json:"events,omitempty"`
+}
+
+// WorkloadTerminations 按工作负载汇总的容器异常终止
+type WorkloadTerminations struct {
+	Workload  string `json:"workload"`
+	Namespace string `json:"namespace"`
+	Pods      int    `json:"pods"`
+	// ByReason 按终止原因统计的容器数量
+	ByReason map[string]int `json:"byReason"`
+	// Restarts 这些容器的重启次数之和
+	Restarts      int32     `json:"restarts"`
+	BackOffEvents int32     `json:"backOffEvents,omitempty"`
+	LastFinished  time.Time `json:"lastFinished"`
+}
+
+// ContainerTerminationReport 容器异常终止报告
+type ContainerTerminationReport struct {
+	Namespace     string   `json:"namespace,omitempty"`
+	LabelSelector string   `json:"labelSelector,omitempty"`
+	Reasons       []string `json:"reasons"`
+	// Since 时间窗口的起点，未指定时间窗口时为空
+	Since            *time.Time             `json:"since,omitempty"`
+	ScannedPods      int                    `json:"scannedPods"`
+	Total            int                    `json:"total"`
+	ByReason         map[string]int         `json:"byReason"`
+	Workloads        []WorkloadTerminations `json:"workloads"`
+	WorstOffenders   []ContainerTermination `json:"worstOffenders"`
+	MetricsAvailable bool                   `json:"metricsAvailable"`
+	RetrievedAt      time.Time              `json:"retrievedAt"`
+}
+
+// 调度容量模拟的约束类别
+const (
+	CapacityConstraintNone      = "none"
+	CapacityConstraintSelectors = "selectors"
+	CapacityConstraintTaints    = "taints"
+	CapacityConstraintNodes     = "unschedulable-nodes"
+	CapacityConstraintPods      = "pods"
+)
+
+// NodeCapacity 节点在模拟放置后的剩余容量
+type NodeCapacity struct {
+	Node string `json:"node"`
+	// Capacity 放置前该节点最多还能容纳的副本数
+	Capacity int `json:"capacity"`
+	// Placed 模拟中放置到该节点的副本数
+	Placed int `json:"placed"`
+	// LimitedBy 限制该节点容量的资源，例如cpu、memory或pods
+	LimitedBy string `json:"limitedBy,omitempty"`
+	// RemainingAfter 放置后剩余的可分配资源
+	RemainingAfter map[string]string `json:"remainingAfter"`
+}
+
+// ExcludedNode 因非资源条件无法放置Pod的节点
+type ExcludedNode struct {
+	Node        string   `json:"node"`
+	Reasons     []string `json:"reasons"`
+	Untolerated []string `json:"untolerated,omitempty"`
+}
+
+// ScheduleCapacityReport 模拟调度额外副本的结果
+type ScheduleCapacityReport struct {
+	// Source Pod规格的来源，格式为"Kind/namespace/name"，内联请求为"inline"
+	Source   string            `json:"source"`
+	Requests map[string]string `json:"requests"`
+	Replicas int               `json:"replicas"`
+	Fits     bool              `json:"fits"`
+	// MaxReplicas 集群最多还能容纳的副本数
+	MaxReplicas int `json:"maxReplicas"`
+	// BindingConstraint 限制副本数的主要约束：cpu、memory等资源名，或selectors、taints、unschedulable-nodes、pods、none
+	BindingConstraint string         `json:"bindingConstraint"`
+	EligibleNodes     int            `json:"eligibleNodes"`
+	Nodes             []NodeCapacity `json:"nodes"`
+	ExcludedNodes     []ExcludedNode `json:"excludedNodes,omitempty"`
+	// Note 模拟的近似说明
+	Note        string    `json:"note"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// RestartContext GET_RESTART_CONTEXT的结果：上一个容器实例结束前的日志、终止原因和当前实例启动时的日志
+type RestartContext struct {
+	Pod          string `json:"pod"`
+	Namespace    string `json:"namespace"`
+	Container    string `json:"container"`
+	Init         bool   `json:"init,omitempty"`
+	RestartCount int32  `json:"restartCount"`
+	// State 容器当前状态：running、waiting或terminated
+	State       string `json:"state"`
+	StateReason string `json:"stateReason,omitempty"`
+	// Termination 上一个实例的终止信息，kubelet未记录时为空
+	Termination *RestartTermination `json:"termination,omitempty"`
+	// PreviousTail 上一个实例最后的日志
+	PreviousTail RestartLogSection `json:"previousTail"`
+	// PreviousAnalysis 对PreviousTail运行日志分析器的结果
+	PreviousAnalysis *RestartLogAnalysis `json:"previousAnalysis,omitempty"`
+	// CurrentHead 当前实例最前面的日志
+	CurrentHead RestartLogSection `json:"currentHead"`
+	Hint        string            `json:"hint,omitempty"`
+	RetrievedAt time.Time         `json:"retrievedAt"`
+}
+
+// RestartTermination 上一个容器实例的终止信息
+type RestartTermination struct {
+	Reason     string     `json:"reason,omitempty"`
+	ExitCode   int32      `json:"exitCode"`
+	Signal     int32      `json:"signal,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// RanFor 上一个实例的运行时长
+	RanFor string `json:"ranFor,omitempty"`
+}
+
+// RestartLogSection 一段容器日志，Available为false时Unavailable说明日志不可用的原因
+type RestartLogSection struct {
+	Available   bool     `json:"available"`
+	Unavailable string   `json:"unavailable,omitempty"`
+	Lines       []string `json:"lines"`
+	// Truncated 日志超过请求的行数，只返回了部分
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RestartLogAnalysis 上一个实例日志的错误统计
+type RestartLogAnalysis struct {
+	ErrorCount   int      `json:"errorCount"`
+	WarningCount int      `json:"warningCount"`
+	TopErrors    []string `json:"topErrors,omitempty"`
+}
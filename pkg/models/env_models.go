@@ -0,0 +1,31 @@
+package models
+
+// EnvVarView 容器环境变量，敏感值已脱敏
+type EnvVarView struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	// ValueFrom 引用来源的说明，例如"secret db-credentials/password"
+	ValueFrom string `json:"valueFrom,omitempty"`
+	// Redacted 值是否因名称看起来敏感而被脱敏
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// SetEnvResult 修改工作负载容器环境变量的结果
+type SetEnvResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	// InitContainer 修改的是否为初始化容器
+	InitContainer bool     `json:"initContainer,omitempty"`
+	Added         []string `json:"added,omitempty"`
+	Updated       []string `json:"updated,omitempty"`
+	Removed       []string `json:"removed,omitempty"`
+	// NotFound 要删除但容器中不存在的变量
+	NotFound []string `json:"notFound,omitempty"`
+	// Changed 没有任何变化时为false，不会发起patch
+	Changed bool `json:"changed"`
+	DryRun  bool `json:"dryRun,omitempty"`
+	// Env 修改后容器的完整环境变量
+	Env []EnvVarView `json:"env"`
+}
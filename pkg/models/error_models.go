@@ -0,0 +1,29 @@
+package models
+
+// ToolError 定义工具执行失败时返回的结构化错误
+type ToolError struct {
+	// Code 错误分类，例如：NotFound、Forbidden、Conflict
+	Code string `json:"code"`
+	// Message 错误描述
+	Message string `json:"message"`
+	// Reason Kubernetes API返回的StatusReason，非API错误时为空
+	Reason string `json:"reason,omitempty"`
+	// Hint 针对该类错误的处理建议
+	Hint string `json:"hint,omitempty"`
+	// Details 与错误相关的附加信息，例如服务端应用的冲突字段
+	Details interface{} `json:"details,omitempty"`
+}
+
+// SelectorErrorDetails 选择器无法解析时附带的修正建议
+type SelectorErrorDetails struct {
+	// Suggestion 按常见写法错误修正后的选择器，仍无法解析，与原值相同时为空
+	Suggestion string `json:"suggestion,omitempty"`
+	// Examples 有效的选择器示例
+	Examples []string `json:"examples"`
+}
+
+// NameSuggestionDetails 资源不存在时附带的相近名称
+type NameSuggestionDetails struct {
+	// Suggestions 同一命名空间中名称相近的同类资源，按相似度从高到低排列
+	Suggestions []string `json:"suggestions"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// HelmReleaseSecretType Helm 3 存储发布记录时使用的Secret类型
+const HelmReleaseSecretType = "helm.sh/release.v1"
+
+// HelmRelease 定义从发布记录中解码出的Helm发布数据（仅包含需要的字段）
+type HelmRelease struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Version   int                    `json:"version"`
+	Info      HelmReleaseInfo        `json:"info"`
+	Chart     HelmChart              `json:"chart"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Manifest  string                 `json:"manifest,omitempty"`
+}
+
+// HelmReleaseInfo 定义Helm发布的状态信息
+type HelmReleaseInfo struct {
+	FirstDeployed time.Time `json:"first_deployed,omitempty"`
+	LastDeployed  time.Time `json:"last_deployed,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Status        string    `json:"status"`
+	Notes         string    `json:"notes,omitempty"`
+}
+
+// HelmChart 定义Helm Chart结构
+type HelmChart struct {
+	Metadata HelmChartMetadata `json:"metadata"`
+}
+
+// HelmChartMetadata 定义Helm Chart元数据
+type HelmChartMetadata struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+}
+
+// HelmReleaseSummary 定义Helm发布摘要信息
+type HelmReleaseSummary struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Revision     int       `json:"revision"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion,omitempty"`
+	Status       string    `json:"status"`
+	Updated      time.Time `json:"updated,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	SecretName   string    `json:"secretName"`
+	DecodeError  string    `json:"decodeError,omitempty"`
+}
+
+// HelmReleaseListResponse 定义Helm发布列表响应结构
+type HelmReleaseListResponse struct {
+	Count       int                  `json:"count"`
+	Namespace   string               `json:"namespace,omitempty"`
+	Releases    []HelmReleaseSummary `json:"releases"`
+	Continue    string               `json:"continue,omitempty"`
+	Remaining   *int64               `json:"remainingItemCount,omitempty"`
+	RetrievedAt time.Time            `json:"retrievedAt"`
+}
+
+// HelmReleaseDetailResponse 定义单个Helm发布详情响应结构
+type HelmReleaseDetailResponse struct {
+	HelmReleaseSummary
+	Revisions   []int                  `json:"revisions"`
+	Notes       string                 `json:"notes,omitempty"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+	Manifest    string                 `json:"manifest,omitempty"`
+	RetrievedAt time.Time              `json:"retrievedAt"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ToolHistoryEntry 一次工具调用的记录
+type ToolHistoryEntry struct {
+	RequestID string    `json:"requestId"`
+	Tool      string    `json:"tool"`
+	StartedAt time.Time `json:"startedAt"`
+	// DurationMs 调用耗时（毫秒）
+	DurationMs int64 `json:"durationMs"`
+	// Outcome 调用结果：success或error
+	Outcome string `json:"outcome"`
+	// ErrorCode 失败时的错误分类，例如：NotFound、Forbidden
+	ErrorCode string `json:"errorCode,omitempty"`
+	// Error 失败时错误信息的开头部分
+	Error string `json:"error,omitempty"`
+	// Arguments 调用参数，敏感值和大段内容只保留长度，过长的值被截断
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// ToolHistory 当前会话的工具调用记录
+type ToolHistory struct {
+	Entries []ToolHistoryEntry `json:"entries"`
+	// Recorded 当前会话记录过的调用总数，超过容量的旧记录已被移除
+	Recorded int `json:"recorded"`
+	Capacity int `json:"capacity"`
+}
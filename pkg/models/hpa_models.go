@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// HPAScaleTarget HPA的扩缩容目标
+type HPAScaleTarget struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// HPAMetric HPA的一个指标的目标值与当前值
+type HPAMetric struct {
+	// Type 指标类型：Resource、ContainerResource、Pods、Object或External
+	Type string `json:"type"`
+	// Name 资源名（cpu、memory）或自定义指标名
+	Name string `json:"name"`
+	// Container ContainerResource类型指标对应的容器
+	Container string `json:"container,omitempty"`
+	// Object Object类型指标描述的对象，格式为Kind/name
+	Object string `json:"object,omitempty"`
+	// Selector Pods、Object、External类型指标的标签选择器
+	Selector string `json:"selector,omitempty"`
+	// TargetType 目标类型：Utilization、Value或AverageValue
+	TargetType string `json:"targetType"`
+	Target     string `json:"target"`
+	// Current 当前值，HPA尚未计算出该指标时为空
+	Current string `json:"current,omitempty"`
+}
+
+// HPACondition HPA的状态条件
+type HPACondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// HPAEvent HPA的扩缩容事件
+type HPAEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count,omitempty"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// HPAStatus 一个HPA的扩缩容状态
+type HPAStatus struct {
+	Name            string         `json:"name"`
+	Namespace       string         `json:"namespace"`
+	ScaleTarget     HPAScaleTarget `json:"scaleTarget"`
+	MinReplicas     int32          `json:"minReplicas"`
+	MaxReplicas     int32          `json:"maxReplicas"`
+	CurrentReplicas int32          `json:"currentReplicas"`
+	DesiredReplicas int32          `json:"desiredReplicas"`
+	LastScaleTime   *time.Time     `json:"lastScaleTime,omitempty"`
+	Metrics         []HPAMetric    `json:"metrics"`
+	Conditions      []HPACondition `json:"conditions,omitempty"`
+	Events          []HPAEvent     `json:"events,omitempty"`
+	// Problems 检测到的配置问题，例如目标工作负载未设置资源请求、多个HPA指向同一工作负载
+	Problems []string `json:"problems,omitempty"`
+}
+
+// HPAStatusList HPA状态列表
+type HPAStatusList struct {
+	Namespace     string      `json:"namespace,omitempty"`
+	LabelSelector string      `json:"labelSelector,omitempty"`
+	Count         int         `json:"count"`
+	WithProblems  int         `json:"withProblems"`
+	Items         []HPAStatus `json:"items"`
+	Errors        []string    `json:"errors,omitempty"`
+	RetrievedAt   time.Time   `json:"retrievedAt"`
+}
+
+// SetHPABoundsResponse 修改HPA副本数上下限的结果
+type SetHPABoundsResponse struct {
+	Name                string `json:"name"`
+	Namespace           string `json:"namespace"`
+	PreviousMinReplicas int32  `json:"previousMinReplicas"`
+	PreviousMaxReplicas int32  `json:"previousMaxReplicas"`
+	MinReplicas         int32  `json:"minReplicas"`
+	MaxReplicas         int32  `json:"maxReplicas"`
+	CurrentReplicas     int32  `json:"currentReplicas"`
+	Changed             bool   `json:"changed"`
+	DryRun              bool   `json:"dryRun"`
+	// Warnings 例如当前副本数超出新的上下限，HPA将随之扩缩容
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import "time"
+
+// 镜像的风险标记
+const (
+	// ImageFlagLatestTag 镜像使用latest标签
+	ImageFlagLatestTag = "latest-tag"
+	// ImageFlagNoTag 镜像既没有标签也没有摘要，隐式使用latest
+	ImageFlagNoTag = "no-tag"
+	// ImageFlagUntrustedRegistry 镜像仓库不在允许列表中
+	ImageFlagUntrustedRegistry = "untrusted-registry"
+)
+
+// ImageUsage 一个镜像及使用它的工作负载
+type ImageUsage struct {
+	Image      string `json:"image"`
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	// Workloads 使用该镜像的对象，格式为"namespace/Kind/name"
+	Workloads    []string `json:"workloads"`
+	Namespaces   []string `json:"namespaces"`
+	PullPolicies []string `json:"pullPolicies,omitempty"`
+	// WithoutPullSecrets 未配置imagePullSecrets的对象数量
+	WithoutPullSecrets int      `json:"withoutPullSecrets"`
+	Flags              []string `json:"flags,omitempty"`
+}
+
+// ImageGroup 按仓库或命名空间汇总的镜像数量
+type ImageGroup struct {
+	Name string `json:"name"`
+	// Images 不同镜像的数量
+	Images int `json:"images"`
+	// Workloads 使用这些镜像的对象数量
+	Workloads int `json:"workloads"`
+	// Flagged 带有风险标记的镜像数量
+	Flagged int `json:"flagged"`
+}
+
+// ImageInventory 集群镜像清单
+type ImageInventory struct {
+	// Source 镜像来源："pods"为运行中的Pod，"workloads"为工作负载模板
+	Source      string       `json:"source"`
+	Scanned     int          `json:"scanned"`
+	Images      []ImageUsage `json:"images"`
+	ByRegistry  []ImageGroup `json:"byRegistry"`
+	ByNamespace []ImageGroup `json:"byNamespace"`
+	Flagged     int          `json:"flagged"`
+	ListPagination
+}
+
+// 镜像一致性检查的发现类型
+const (
+	// ImageCheckTemplateMismatch Pod运行的镜像与工作负载当前的Pod模板不同
+	ImageCheckTemplateMismatch = "template-mismatch"
+	// ImageCheckTagMultipleDigests 同一镜像标签在不同Pod中解析为不同摘要，通常是标签被覆盖推送
+	ImageCheckTagMultipleDigests = "tag-multiple-digests"
+	// ImageCheckStaleDigest Pod运行的摘要与最新Pod拉取的摘要不同，仅在compareNewest=true时检查
+	ImageCheckStaleDigest = "stale-digest"
+)
+
+// ImageConsistencyReport CHECK_IMAGE_CONSISTENCY的结果
+type ImageConsistencyReport struct {
+	Namespace string                     `json:"namespace"`
+	Workloads []WorkloadImageConsistency `json:"workloads"`
+	// Tags 在扫描的所有Pod中解析为多个摘要的镜像标签
+	Tags []ImageTagDigests `json:"tags"`
+	// Findings 所有工作负载的发现总数，Consistent 没有任何发现且没有标签解析为多个摘要
+	Findings    int       `json:"findings"`
+	Consistent  bool      `json:"consistent"`
+	Notes       []string  `json:"notes,omitempty"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// WorkloadImageConsistency 单个工作负载的Pod模板镜像与Pod实际运行镜像的对比
+type WorkloadImageConsistency struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Pods 属于该工作负载的未终止Pod数量
+	Pods int `json:"pods"`
+	// RolloutInProgress 工作负载是否仍在滚动更新，此时旧Pod运行旧镜像是正常的
+	RolloutInProgress bool                        `json:"rolloutInProgress"`
+	Containers        []ContainerImageConsistency `json:"containers"`
+	Findings          []ImageConsistencyFinding   `json:"findings"`
+}
+
+// ContainerImageConsistency 单个容器的模板镜像及各Pod实际运行的镜像和摘要
+type ContainerImageConsistency struct {
+	Container string `json:"container"`
+	Init      bool   `json:"init,omitempty"`
+	// TemplateImage 工作负载当前Pod模板中的镜像
+	TemplateImage string `json:"templateImage"`
+	// Running 按Pod规格中的镜像和容器状态中的摘要分组的Pod
+	Running []ImageDigestPods `json:"running"`
+	// ExpectedDigest compareNewest=true时使用模板镜像的最新Pod拉取的摘要
+	ExpectedDigest string `json:"expectedDigest,omitempty"`
+	// Unresolved 容器尚未启动、状态中还没有摘要的Pod
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// ImageDigestPods 使用同一镜像和摘要的Pod
+type ImageDigestPods struct {
+	Image  string   `json:"image"`
+	Digest string   `json:"digest"`
+	Pods   []string `json:"pods"`
+}
+
+// ImageConsistencyFinding 镜像一致性问题及受影响的Pod
+type ImageConsistencyFinding struct {
+	Severity   string   `json:"severity"`
+	Check      string   `json:"check"`
+	Container  string   `json:"container"`
+	Message    string   `json:"message"`
+	Pods       []string `json:"pods"`
+	Suggestion string   `json:"suggestion,omitempty"`
+}
+
+// ImageTagDigests 解析为多个摘要的镜像标签
+type ImageTagDigests struct {
+	// Image 规范化的镜像引用，格式为registry/repository:tag
+	Image   string            `json:"image"`
+	Digests []ImageDigestPods `json:"digests"`
+	// Workloads 运行该标签的工作负载，格式为"Kind/name"，不属于工作负载的Pod不列出
+	Workloads []string `json:"workloads,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// TriggerCronJobResponse 定义手动触发CronJob的响应结构
+type TriggerCronJobResponse struct {
+	CronJob     string            `json:"cronJob"`
+	Namespace   string            `json:"namespace"`
+	JobName     string            `json:"jobName"`
+	DryRun      bool              `json:"dryRun"`
+	Forced      bool              `json:"forced,omitempty"`
+	ActiveJobs  []string          `json:"activeJobs,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+// CronJobSuspendResponse 定义暂停或恢复CronJob的响应结构
+type CronJobSuspendResponse struct {
+	CronJob          string     `json:"cronJob"`
+	Namespace        string     `json:"namespace"`
+	Schedule         string     `json:"schedule"`
+	Suspend          bool       `json:"suspend"`
+	Changed          bool       `json:"changed"`
+	DryRun           bool       `json:"dryRun"`
+	ActiveJobs       []string   `json:"activeJobs,omitempty"`
+	LastScheduleTime *time.Time `json:"lastScheduleTime,omitempty"`
+}
+
+// JobCondition 定义Job状态条件
+type JobCondition struct {
+	Type    string    `json:"type"`
+	Status  string    `json:"status"`
+	Reason  string    `json:"reason,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time,omitempty"`
+}
+
+// JobPodInfo 定义Job创建的Pod概要
+type JobPodInfo struct {
+	Name         string    `json:"name"`
+	Phase        string    `json:"phase"`
+	NodeName     string    `json:"nodeName,omitempty"`
+	RestartCount int32     `json:"restartCount"`
+	Reason       string    `json:"reason,omitempty"`
+	ExitCode     *int32    `json:"exitCode,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// JobStatusResponse 定义Job状态汇总响应结构
+type JobStatusResponse struct {
+	Name           string         `json:"name"`
+	Namespace      string         `json:"namespace"`
+	CronJob        string         `json:"cronJob,omitempty"`
+	Status         string         `json:"status"`
+	Completions    int32          `json:"completions"`
+	Parallelism    int32          `json:"parallelism"`
+	BackoffLimit   int32          `json:"backoffLimit"`
+	Succeeded      int32          `json:"succeeded"`
+	Failed         int32          `json:"failed"`
+	Active         int32          `json:"active"`
+	Ready          int32          `json:"ready"`
+	StartTime      *time.Time     `json:"startTime,omitempty"`
+	CompletionTime *time.Time     `json:"completionTime,omitempty"`
+	Duration       string         `json:"duration,omitempty"`
+	Conditions     []JobCondition `json:"conditions,omitempty"`
+	Pods           []JobPodInfo   `json:"pods"`
+	LogPod         string         `json:"logPod,omitempty"`
+	LogContainer   string         `json:"logContainer,omitempty"`
+	Logs           []string       `json:"logs,omitempty"`
+	LogError       string         `json:"logError,omitempty"`
+	RetrievedAt    time.Time      `json:"retrievedAt"`
+}
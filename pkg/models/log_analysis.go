@@ -22,6 +22,20 @@ type LogAnalysisResult struct {
 	ResourceUsage      map[string][]int // 资源使用统计 (CPU/内存)
 	ProcessingDuration time.Duration
 	AnalysisPrompt     string // 用户提供的分析提示
+	LineCount          int    // 分析的日志行数
+	StructuredLines    int    // 按JSON结构化日志解析的行数
+}
+
+// Format 根据结构化日志行所占比例返回日志格式：json、mixed或text
+func (r *LogAnalysisResult) Format() string {
+	switch {
+	case r.StructuredLines == 0:
+		return "text"
+	case r.StructuredLines >= r.LineCount:
+		return "json"
+	default:
+		return "mixed"
+	}
 }
 
 // NewLogAnalysisResult 创建新的日志分析结果实例
@@ -53,6 +67,16 @@ type LogPattern struct {
 	TimestampPattern    string
 }
 
+// LogFieldMapping JSON结构化日志中各类信息所在的字段名，按顺序取第一个存在的字段，"."表示嵌套字段
+type LogFieldMapping struct {
+	Level     []string
+	Timestamp []string
+	Message   []string
+	Error     []string
+	Status    []string
+	Duration  []string
+}
+
 // TimeCategory 响应时间分类
 type TimeCategory struct {
 	Name      string
@@ -1,25 +1,47 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
 // PodLogsResponse 定义Pod日志响应结构
 type PodLogsResponse struct {
-	Pod          string    `json:"pod"`
-	Namespace    string    `json:"namespace"`
-	Container    string    `json:"container,omitempty"`
-	Previous     bool      `json:"previous"`
-	Timestamps   bool      `json:"timestamps"`
-	TailLines    int       `json:"tailLines"`
-	LineCount    int       `json:"lineCount"`
-	TotalLines   int       `json:"totalLines"`
-	Truncated    bool      `json:"truncated,omitempty"`
-	LogSize      uint64    `json:"logSize"`
-	LogSizeHuman string    `json:"logSizeHuman"`
-	Logs         string    `json:"logs"`
-	RetrievedAt  time.Time `json:"retrievedAt"`
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container,omitempty"`
+	// ContainerRole 所读取容器的角色：main、init、sidecar或ephemeral
+	ContainerRole string `json:"containerRole,omitempty"`
+	// AvailableContainers 未指定容器时列出Pod中所有容器及其状态，便于选择其他容器
+	AvailableContainers []ContainerStatusSummary `json:"availableContainers,omitempty"`
+	// Note 自动选择容器的说明，例如Pod卡在初始化阶段时改为读取阻塞的初始化容器
+	Note         string `json:"note,omitempty"`
+	Previous     bool   `json:"previous"`
+	Timestamps   bool   `json:"timestamps"`
+	TailLines    int    `json:"tailLines"`
+	LineCount    int    `json:"lineCount"`
+	TotalLines   int    `json:"totalLines"`
+	Truncated    bool   `json:"truncated,omitempty"`
+	LogSize      uint64 `json:"logSize"`
+	LogSizeHuman string `json:"logSizeHuman"`
+	// OutputMode 日志的返回形式：raw时日志在Logs中，lines和jsonl时在Entries中
+	OutputMode string `json:"outputMode"`
+	Logs       string `json:"logs,omitempty"`
+	// EntryCount Entries的条数，多行堆栈合并为一条，因此可能少于LineCount
+	EntryCount  int        `json:"entryCount,omitempty"`
+	Entries     []LogEntry `json:"entries,omitempty"`
+	RetrievedAt time.Time  `json:"retrievedAt"`
+}
+
+// LogEntry 定义一条日志，以空白开头的续行（例如堆栈）与上一行合并为一条
+type LogEntry struct {
+	// Timestamp kubelet添加的时间戳，未请求时间戳或该行没有时间戳时为空
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// Line 日志内容，多行时以换行符连接；jsonl模式下日志可解析为JSON对象时为空
+	Line string `json:"line,omitempty"`
+	// Object jsonl模式下可解析为JSON对象的日志内容
+	Object json.RawMessage `json:"object,omitempty"`
 }
 
 // LogAnalysisResponse 定义日志分析响应结构
@@ -31,12 +53,56 @@ type LogAnalysisResponse struct {
 	Previous      bool        `json:"previous"`
 	ErrorCount    int         `json:"errorCount"`
 	WarningCount  int         `json:"warningCount"`
+	LogFormat     string      `json:"logFormat"`
 	ErrorPattern  string      `json:"errorPattern,omitempty"`
 	Prompt        string      `json:"prompt,omitempty"`
 	Analysis      LogAnalysis `json:"analysis"`
 	RetrievedAt   time.Time   `json:"retrievedAt"`
 }
 
+// AggregatedLogAnalysisResponse 定义多个日志流（多个容器或多个Pod）的汇总分析响应结构
+type AggregatedLogAnalysisResponse struct {
+	Pod             string              `json:"pod,omitempty"`
+	LabelSelector   string              `json:"labelSelector,omitempty"`
+	Namespace       string              `json:"namespace"`
+	PodsMatched     int                 `json:"podsMatched"`
+	PodsAnalyzed    int                 `json:"podsAnalyzed"`
+	Truncated       bool                `json:"truncated,omitempty"`
+	StreamsAnalyzed int                 `json:"streamsAnalyzed"`
+	LinesAnalyzed   int                 `json:"linesAnalyzed"`
+	Previous        bool                `json:"previous"`
+	ErrorCount      int                 `json:"errorCount"`
+	WarningCount    int                 `json:"warningCount"`
+	LogFormat       string              `json:"logFormat"`
+	ErrorPattern    string              `json:"errorPattern,omitempty"`
+	Prompt          string              `json:"prompt,omitempty"`
+	Summary         LogAnalysis         `json:"summary"`
+	SharedErrors    []SharedLogError    `json:"sharedErrors,omitempty"`
+	Streams         []LogStreamAnalysis `json:"streams"`
+	RetrievedAt     time.Time           `json:"retrievedAt"`
+}
+
+// LogStreamAnalysis 定义单个容器日志流的分析结果
+type LogStreamAnalysis struct {
+	Pod           string     `json:"pod"`
+	Container     string     `json:"container"`
+	InitContainer bool       `json:"initContainer,omitempty"`
+	LinesAnalyzed int        `json:"linesAnalyzed"`
+	ErrorCount    int        `json:"errorCount"`
+	WarningCount  int        `json:"warningCount"`
+	TopErrors     []LogEvent `json:"topErrors,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// SharedLogError 定义在多个Pod中出现的同一错误
+type SharedLogError struct {
+	Message    string   `json:"message"`
+	Count      int      `json:"count"`
+	PodCount   int      `json:"podCount"`
+	Prevalence string   `json:"prevalence"`
+	Pods       []string `json:"pods"`
+}
+
 // LogAnalysis 定义日志分析结果结构
 type LogAnalysis struct {
 	Summary          string     `json:"summary"`
@@ -106,6 +172,10 @@ func NewLogAnalysisResponseFromResult(
 	insights := []string{
 		fmt.Sprintf("日志时间跨度：%s", result.TimeRange[1].Sub(result.TimeRange[0]).String()),
 	}
+	if result.StructuredLines > 0 {
+		insights = append(insights, fmt.Sprintf("%d行中有%d行为JSON结构化日志，已按字段提取级别、时间、状态码和耗时",
+			result.LineCount, result.StructuredLines))
+	}
 
 	// 根据错误数生成建议
 	recommendations := []string{}
@@ -131,6 +201,7 @@ func NewLogAnalysisResponseFromResult(
 		Previous:      previous,
 		ErrorCount:    result.ErrorCount,
 		WarningCount:  result.WarningCount,
+		LogFormat:     result.Format(),
 		ErrorPattern:  errorPattern,
 		Prompt:        prompt,
 		Analysis:      analysis,
@@ -139,3 +210,52 @@ func NewLogAnalysisResponseFromResult(
 
 	return response
 }
+
+// WorkloadLogsResponse 定义工作负载所有Pod日志的响应结构
+type WorkloadLogsResponse struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Selector  string `json:"selector"`
+	// Container 调用方指定的容器，未指定时每个Pod使用其默认容器
+	Container   string `json:"container,omitempty"`
+	TailLines   int    `json:"tailLines"`
+	Previous    bool   `json:"previous"`
+	Timestamps  bool   `json:"timestamps"`
+	PodsMatched int    `json:"podsMatched"`
+	PodsFetched int    `json:"podsFetched"`
+	PodsFailed  int    `json:"podsFailed"`
+	// Truncated 匹配的Pod超过maxPods，只读取了前maxPods个
+	Truncated bool `json:"truncated,omitempty"`
+	// MaxBytes 所有Pod日志合计的字节预算
+	MaxBytes   int `json:"maxBytes"`
+	TotalBytes int `json:"totalBytes"`
+	// BudgetExceeded 为了不超过字节预算丢弃了部分Pod较早的日志行
+	BudgetExceeded bool              `json:"budgetExceeded,omitempty"`
+	Pods           []WorkloadPodLogs `json:"pods"`
+	// Merged 所有Pod日志按时间戳交错合并后的视图，只在启用时间戳时返回
+	Merged      []WorkloadLogEntry `json:"merged,omitempty"`
+	RetrievedAt time.Time          `json:"retrievedAt"`
+}
+
+// WorkloadPodLogs 定义单个Pod的日志
+type WorkloadPodLogs struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	Node      string `json:"node,omitempty"`
+	Phase     string `json:"phase"`
+	LineCount int    `json:"lineCount"`
+	Bytes     int    `json:"bytes"`
+	// DroppedLines 为了不超过字节预算丢弃的较早日志行数
+	DroppedLines int    `json:"droppedLines,omitempty"`
+	Logs         string `json:"logs,omitempty"`
+	// Error 无法读取日志的原因（如Pod处于Pending、容器尚未启动），不影响其他Pod
+	Error string `json:"error,omitempty"`
+}
+
+// WorkloadLogEntry 定义合并视图中的一条日志
+type WorkloadLogEntry struct {
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Pod       string     `json:"pod"`
+	Line      string     `json:"line"`
+}
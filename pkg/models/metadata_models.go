@@ -0,0 +1,27 @@
+package models
+
+// MetadataEditItem 单个对象标签或注解修改前后的值
+type MetadataEditItem struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Before    map[string]string `json:"before"`
+	After     map[string]string `json:"after,omitempty"`
+	// Changed 修改后的值与修改前是否不同
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MetadataEditResult 修改资源标签或注解的结果
+type MetadataEditResult struct {
+	// Field 修改的元数据字段，"labels"或"annotations"
+	Field         string            `json:"field"`
+	Set           map[string]string `json:"set,omitempty"`
+	Removed       []string          `json:"removed,omitempty"`
+	LabelSelector string            `json:"labelSelector,omitempty"`
+	DryRun        bool              `json:"dryRun,omitempty"`
+	// Matched 选择器匹配的对象总数，超过上限时只处理前maxObjects个（仅试运行）
+	Matched   int                `json:"matched"`
+	Truncated bool               `json:"truncated,omitempty"`
+	Items     []MetadataEditItem `json:"items"`
+}
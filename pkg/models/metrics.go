@@ -53,6 +53,10 @@ type NodeMetricInfo struct {
 	MemoryUsage int64
 	// Memory allocatable in MB
 	MemoryAllocatable int64
+	// Memory usage in bytes
+	MemoryUsageBytes int64
+	// Memory allocatable in bytes
+	MemoryAllocatableBytes int64
 	// Memory usage percentage
 	MemoryPercent float64
 	// Metric timestamp
@@ -69,6 +73,8 @@ type PodMetricInfo struct {
 	TotalCPU int64
 	// Total memory usage in MB
 	TotalMemory int64
+	// Total memory usage in bytes
+	TotalMemoryBytes int64
 	// Container metrics
 	Containers []ContainerMetricInfo
 	// Metric timestamp
@@ -83,6 +89,8 @@ type ContainerMetricInfo struct {
 	CPUUsage int64
 	// Memory usage in MB
 	MemoryUsage int64
+	// Memory usage in bytes
+	MemoryUsageBytes int64
 }
 
 // ClusterResourceMetrics holds overall cluster resource usage
@@ -104,11 +112,27 @@ type ClusterResourceMetrics struct {
 	MemoryUsage int64
 	// Memory usage percentage
 	MemoryPercent float64
+	// Memory capacity, allocatable and usage in bytes
+	MemoryCapacityBytes    int64
+	MemoryAllocatableBytes int64
+	MemoryUsageBytes       int64
 
 	// Storage capacity in GB
 	StorageCapacity int64
 	// Storage allocatable in GB
 	StorageAllocatable int64
+	// Storage capacity and allocatable in bytes
+	StorageCapacityBytes    int64
+	StorageAllocatableBytes int64
+
+	// Effective requests and limits of pods that have not finished, in millicores and bytes
+	CPURequests         int64
+	CPULimits           int64
+	MemoryRequestsBytes int64
+	MemoryLimitsBytes   int64
+	// Requests as a percentage of allocatable
+	CPURequestsPercent    float64
+	MemoryRequestsPercent float64
 
 	// Maximum pods
 	PodCapacity int64
@@ -119,11 +143,13 @@ type ClusterResourceMetrics struct {
 
 	// Namespace filter (if specified)
 	Namespace string
+	// Whether node capacity and usage were skipped because only pods of a namespace were requested
+	NodesSkipped bool
 	// Whether to include detailed information
 	IncludeDetail bool
 	// Resource type being queried
 	ResourceType string
-	// Unit type (raw, percent, human)
+	// Unit type (raw, human)
 	UnitType string
 }
 
@@ -131,8 +157,10 @@ type ClusterResourceMetrics struct {
 func BuildNodeMetricInfoFromK8s(nodeMetric metricsv1beta1.NodeMetrics, allocatable corev1.ResourceList) NodeMetricInfo {
 	cpuUsage := nodeMetric.Usage.Cpu().MilliValue()
 	cpuAllocatable := allocatable.Cpu().MilliValue()
-	memoryUsage := nodeMetric.Usage.Memory().Value() / (1024 * 1024) // Convert to MB
-	memoryAllocatable := allocatable.Memory().Value() / (1024 * 1024)
+	memoryUsageBytes := nodeMetric.Usage.Memory().Value()
+	memoryAllocatableBytes := allocatable.Memory().Value()
+	memoryUsage := memoryUsageBytes / (1024 * 1024) // Convert to MB
+	memoryAllocatable := memoryAllocatableBytes / (1024 * 1024)
 
 	// Calculate usage percentages
 	cpuPercent := float64(0)
@@ -146,14 +174,16 @@ func BuildNodeMetricInfoFromK8s(nodeMetric metricsv1beta1.NodeMetrics, allocatab
 	}
 
 	return NodeMetricInfo{
-		Name:              nodeMetric.Name,
-		CPUUsage:          cpuUsage,
-		CPUAllocatable:    cpuAllocatable,
-		CPUPercent:        cpuPercent,
-		MemoryUsage:       memoryUsage,
-		MemoryAllocatable: memoryAllocatable,
-		MemoryPercent:     memoryPercent,
-		Timestamp:         nodeMetric.Timestamp.Time,
+		Name:                   nodeMetric.Name,
+		CPUUsage:               cpuUsage,
+		CPUAllocatable:         cpuAllocatable,
+		CPUPercent:             cpuPercent,
+		MemoryUsage:            memoryUsage,
+		MemoryAllocatable:      memoryAllocatable,
+		MemoryUsageBytes:       memoryUsageBytes,
+		MemoryAllocatableBytes: memoryAllocatableBytes,
+		MemoryPercent:          memoryPercent,
+		Timestamp:              nodeMetric.Timestamp.Time,
 	}
 }
 
@@ -169,15 +199,18 @@ func BuildPodMetricInfoFromK8s(podMetric metricsv1beta1.PodMetrics) PodMetricInf
 	// Aggregate container metrics
 	for _, container := range podMetric.Containers {
 		containerCPU := container.Usage.Cpu().MilliValue()
-		containerMemory := container.Usage.Memory().Value() / (1024 * 1024) // Convert to MB
+		containerMemoryBytes := container.Usage.Memory().Value()
+		containerMemory := containerMemoryBytes / (1024 * 1024) // Convert to MB
 
 		result.TotalCPU += containerCPU
 		result.TotalMemory += containerMemory
+		result.TotalMemoryBytes += containerMemoryBytes
 
 		result.Containers = append(result.Containers, ContainerMetricInfo{
-			Name:        container.Name,
-			CPUUsage:    containerCPU,
-			MemoryUsage: containerMemory,
+			Name:             container.Name,
+			CPUUsage:         containerCPU,
+			MemoryUsage:      containerMemory,
+			MemoryUsageBytes: containerMemoryBytes,
 		})
 	}
 
@@ -2,17 +2,43 @@ package models
 
 import "time"
 
+// CPUQuantity represents a CPU amount with an explicit unit
+type CPUQuantity struct {
+	// Milli is the amount in millicores
+	Milli int64 `json:"milli"`
+	// Human is a readable form such as "250m" or "2.31 cores", omitted when the unit type is raw
+	Human string `json:"human,omitempty"`
+}
+
+// ByteQuantity represents a memory or storage amount with an explicit unit
+type ByteQuantity struct {
+	Bytes int64 `json:"bytes"`
+	// Human is a readable form with binary units such as "3.4 GiB", omitted when the unit type is raw
+	Human string `json:"human,omitempty"`
+}
+
+// ResourceQuantities groups unit-explicit resource amounts, unset resources are omitted
+type ResourceQuantities struct {
+	CPU     *CPUQuantity  `json:"cpu,omitempty"`
+	Memory  *ByteQuantity `json:"memory,omitempty"`
+	Storage *ByteQuantity `json:"storage,omitempty"`
+}
+
 // NodeResponse represents the API response for node metrics
+// The integer CPU fields are millicores and the integer memory fields are MiB; they are kept for backward
+// compatibility, new consumers should read Usage and Allocatable
 type NodeResponse struct {
-	Name              string    `json:"name"`
-	CPUUsage          int64     `json:"cpuUsage"`
-	CPUAllocatable    int64     `json:"cpuAllocatable"`
-	CPUPercent        float64   `json:"cpuPercent"`
-	MemoryUsage       int64     `json:"memoryUsage"`
-	MemoryAllocatable int64     `json:"memoryAllocatable"`
-	MemoryPercent     float64   `json:"memoryPercent"`
-	Timestamp         time.Time `json:"timestamp"`
-	UpdatedAgo        string    `json:"updatedAgo"`
+	Name              string             `json:"name"`
+	CPUUsage          int64              `json:"cpuUsage"`
+	CPUAllocatable    int64              `json:"cpuAllocatable"`
+	CPUPercent        float64            `json:"cpuPercent"`
+	MemoryUsage       int64              `json:"memoryUsage"`
+	MemoryAllocatable int64              `json:"memoryAllocatable"`
+	MemoryPercent     float64            `json:"memoryPercent"`
+	Usage             ResourceQuantities `json:"usage"`
+	Allocatable       ResourceQuantities `json:"allocatable"`
+	Timestamp         time.Time          `json:"timestamp"`
+	UpdatedAgo        string             `json:"updatedAgo"`
 }
 
 // NodesListResponse represents the API response for a list of node metrics
@@ -20,21 +46,26 @@ type NodesListResponse struct {
 	Nodes      []NodeResponse `json:"nodes"`
 	SortBy     string         `json:"sortBy"`
 	TotalCount int            `json:"totalCount"`
+	UnitType   string         `json:"unitType"`
 }
 
 // ContainerResponse represents the API response for container metrics
+// CPUUsage is millicores and MemoryUsage is MiB, kept for backward compatibility
 type ContainerResponse struct {
-	Name        string `json:"name"`
-	CPUUsage    int64  `json:"cpuUsage"`
-	MemoryUsage int64  `json:"memoryUsage"`
+	Name        string             `json:"name"`
+	CPUUsage    int64              `json:"cpuUsage"`
+	MemoryUsage int64              `json:"memoryUsage"`
+	Usage       ResourceQuantities `json:"usage"`
 }
 
 // PodResponse represents the API response for pod metrics
+// TotalCPU is millicores and TotalMemory is MiB, kept for backward compatibility
 type PodResponse struct {
 	Name        string              `json:"name"`
 	Namespace   string              `json:"namespace"`
 	TotalCPU    int64               `json:"totalCpu"`
 	TotalMemory int64               `json:"totalMemory"`
+	Usage       ResourceQuantities  `json:"usage"`
 	Timestamp   time.Time           `json:"timestamp"`
 	UpdatedAgo  string              `json:"updatedAgo"`
 	Containers  []ContainerResponse `json:"containers,omitempty"`
@@ -48,9 +79,12 @@ type PodsListResponse struct {
 	Namespace     string        `json:"namespace,omitempty"`
 	Limit         int           `json:"limit"`
 	IncludeDetail bool          `json:"includeDetail"`
+	UnitType      string        `json:"unitType"`
 }
 
 // ResourceMetricsResponse represents the API response for resource metrics
+// The integer CPU fields are millicores, memory fields MiB and storage fields GiB; they are kept for backward
+// compatibility, new consumers should read Capacity, Allocatable, Usage and Available
 type ResourceMetricsResponse struct {
 	ResourceType   string  `json:"resourceType"`
 	CPUCapacity    int64   `json:"cpuCapacity,omitempty"`
@@ -73,17 +107,33 @@ type ResourceMetricsResponse struct {
 	PodPercent    float64 `json:"podPercent,omitempty"`
 	PodsAvailable int64   `json:"podsAvailable,omitempty"`
 
+	// Unit-explicit amounts for the requested resource types
+	Capacity    *ResourceQuantities `json:"capacity,omitempty"`
+	Allocatable *ResourceQuantities `json:"allocatable,omitempty"`
+	Usage       *ResourceQuantities `json:"usage,omitempty"`
+	Available   *ResourceQuantities `json:"available,omitempty"`
+	// Sum of the effective requests and limits of pods that have not finished, from the pod specs
+	Requested *ResourceQuantities `json:"requested,omitempty"`
+	Limits    *ResourceQuantities `json:"limits,omitempty"`
+	// Requests as a percentage of allocatable
+	CPURequestsPercent    float64 `json:"cpuRequestsPercent,omitempty"`
+	MemoryRequestsPercent float64 `json:"memoryRequestsPercent,omitempty"`
+
 	Namespace string `json:"namespace,omitempty"`
-	UnitType  string `json:"unitType"`
+	// NodesSkipped is true when node capacity was not collected because only pods of a namespace were requested
+	NodesSkipped bool   `json:"nodesSkipped,omitempty"`
+	UnitType     string `json:"unitType"`
 }
 
 // TopConsumerResponse represents the API response for top resource consumers
+// Usage is millicores for cpu and MiB for memory, kept for backward compatibility
 type TopConsumerResponse struct {
-	Name       string    `json:"name"`
-	Namespace  string    `json:"namespace"`
-	Usage      int64     `json:"usage"`
-	Timestamp  time.Time `json:"timestamp"`
-	UpdatedAgo string    `json:"updatedAgo"`
+	Name          string             `json:"name"`
+	Namespace     string             `json:"namespace"`
+	Usage         int64              `json:"usage"`
+	UsageQuantity ResourceQuantities `json:"usageQuantity"`
+	Timestamp     time.Time          `json:"timestamp"`
+	UpdatedAgo    string             `json:"updatedAgo"`
 }
 
 // TopConsumersListResponse represents the API response for a list of top resource consumers
@@ -93,4 +143,5 @@ type TopConsumersListResponse struct {
 	Limit        int                   `json:"limit"`
 	Namespace    string                `json:"namespace,omitempty"`
 	TotalCount   int                   `json:"totalCount"`
+	UnitType     string                `json:"unitType"`
 }
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// NameCandidate 一个名称与查询相近的资源
+type NameCandidate struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Score 相似度，0到100，越大越相近
+	Score int `json:"score"`
+	// MatchedBy 匹配方式：exact、normalized、generated-suffix、prefix、contains或edit-distance
+	MatchedBy string `json:"matchedBy"`
+	// Distance 忽略大小写和分隔符后与查询名称的编辑距离
+	Distance          int       `json:"distance"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+	Age               string    `json:"age"`
+	// Verdict 按状态条件得出的健康判定：healthy、degraded或unknown
+	Verdict string `json:"verdict"`
+	// Summary 判定依据
+	Summary string `json:"summary,omitempty"`
+}
+
+// NameSearchResult 按近似名称查找资源的结果
+type NameSearchResult struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	// Namespace 查找的命名空间，为空表示所有命名空间或集群级资源
+	Namespace string `json:"namespace,omitempty"`
+	Query     string `json:"query"`
+	// Scanned 检查的资源数量
+	Scanned    int             `json:"scanned"`
+	Candidates []NameCandidate `json:"candidates"`
+	// Truncated 相近的资源超过limit，只返回相似度最高的部分
+	Truncated bool `json:"truncated,omitempty"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// 命名空间健康检查的执行状态
+const (
+	HealthCheckOK      = "ok"
+	HealthCheckFailed  = "failed"
+	HealthCheckTimeout = "timeout"
+)
+
+// NamespaceHealthObjectRef 发现涉及的对象
+type NamespaceHealthObjectRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// SuggestedToolCall 建议的下一步工具调用
+type SuggestedToolCall struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+	// Description 可读的调用说明，例如"run DIAGNOSE_POD on payments/api-7f9c"
+	Description string `json:"description"`
+}
+
+// NamespaceHealthFinding 命名空间健康摘要中的一个问题
+type NamespaceHealthFinding struct {
+	Severity string                   `json:"severity"`
+	Check    string                   `json:"check"`
+	Object   NamespaceHealthObjectRef `json:"object"`
+	Message  string                   `json:"message"`
+	// Next 建议的下一步排查工具调用
+	Next *SuggestedToolCall `json:"next,omitempty"`
+}
+
+// NamespaceHealthCheck 一项检查的执行情况
+type NamespaceHealthCheck struct {
+	Name string `json:"name"`
+	// Status 执行状态：ok、failed或timeout，未完成的检查不贡献发现
+	Status   string `json:"status"`
+	Findings int    `json:"findings"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// NamespaceHealthSummary 命名空间当前问题的汇总
+type NamespaceHealthSummary struct {
+	Namespace string `json:"namespace"`
+	// Verdict 有critical或warning发现时为degraded，没有发现但有检查未完成时为unknown
+	Verdict string         `json:"verdict"`
+	Summary SeverityCounts `json:"summary"`
+	// Findings 按严重程度排序的问题列表
+	Findings []NamespaceHealthFinding `json:"findings"`
+	Checks   []NamespaceHealthCheck   `json:"checks"`
+	// Notes 说明，例如哪些检查超时、结果不完整
+	Notes       []string  `json:"notes,omitempty"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
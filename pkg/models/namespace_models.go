@@ -0,0 +1,64 @@
+package models
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NamespacePreset 创建命名空间时可选的ResourceQuota/LimitRange模板
+type NamespacePreset struct {
+	Description   string                    `json:"description,omitempty"`
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+	LimitRange    *corev1.LimitRangeSpec    `json:"limitRange,omitempty"`
+}
+
+// NamespaceCreateResult 创建命名空间的结果
+type NamespaceCreateResult struct {
+	Name          string            `json:"name"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+	Preset        string            `json:"preset,omitempty"`
+	ResourceQuota string            `json:"resourceQuota,omitempty"`
+	LimitRange    string            `json:"limitRange,omitempty"`
+	DryRun        bool              `json:"dryRun,omitempty"`
+	Errors        []string          `json:"errors,omitempty"`
+}
+
+// NamespaceLabelResult 修改命名空间标签的结果
+type NamespaceLabelResult struct {
+	Name    string            `json:"name"`
+	Set     map[string]string `json:"set,omitempty"`
+	Removed []string          `json:"removed,omitempty"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// NamespaceContents 命名空间中剩余的工作负载和存储
+type NamespaceContents struct {
+	// Counts 按类型统计的对象数量
+	Counts map[string]int `json:"counts"`
+	// Objects 对象名称，格式为"Kind/name"，超过上限时截断
+	Objects   []string `json:"objects,omitempty"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Total     int      `json:"total"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// NamespaceBlocker 阻止命名空间删除完成的条件或终结器
+type NamespaceBlocker struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// NamespaceDeleteResult 删除命名空间的结果
+type NamespaceDeleteResult struct {
+	Name     string            `json:"name"`
+	Contents NamespaceContents `json:"contents"`
+	Deleted  bool              `json:"deleted"`
+	// Phase 返回时命名空间所处的阶段，已完全删除时为"Deleted"
+	Phase string `json:"phase"`
+	// Stuck 等待超时后命名空间仍处于Terminating状态
+	Stuck      bool               `json:"stuck,omitempty"`
+	Finalizers []string           `json:"finalizers,omitempty"`
+	Blockers   []NamespaceBlocker `json:"blockers,omitempty"`
+	Waited     string             `json:"waited,omitempty"`
+}
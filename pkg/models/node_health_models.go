@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// NodeConditionStatus 节点的一个状态条件
+type NodeConditionStatus struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+	// Age 距离上次状态变化的时间
+	Age string `json:"age,omitempty"`
+	// Problem 该条件当前的状态是否表示异常
+	Problem bool `json:"problem"`
+}
+
+// NodeSystemInfo 节点的系统信息
+type NodeSystemInfo struct {
+	KubeletVersion          string `json:"kubeletVersion"`
+	KernelVersion           string `json:"kernelVersion"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+	OSImage                 string `json:"osImage"`
+	OperatingSystem         string `json:"operatingSystem"`
+	Architecture            string `json:"architecture"`
+}
+
+// NodePodCount 节点上已调度的Pod数量与Pod容量
+type NodePodCount struct {
+	// Scheduled 调度到该节点且未结束的Pod数量
+	Scheduled int64 `json:"scheduled"`
+	// Capacity 可分配的Pod数量
+	Capacity int64 `json:"capacity"`
+}
+
+// NodeUsage metrics-server报告的节点资源使用量
+type NodeUsage struct {
+	CPU           *CPUQuantity  `json:"cpu,omitempty"`
+	Memory        *ByteQuantity `json:"memory,omitempty"`
+	CPUPercent    float64       `json:"cpuPercent"`
+	MemoryPercent float64       `json:"memoryPercent"`
+	Timestamp     time.Time     `json:"timestamp"`
+}
+
+// NodeEvent 与节点相关的事件
+type NodeEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count,omitempty"`
+	LastSeen time.Time `json:"lastSeen"`
+	Age      string    `json:"age"`
+}
+
+// NodeHealth 节点的健康状态
+type NodeHealth struct {
+	Name string `json:"name"`
+	// Verdict 健康判定：healthy、degraded或unknown
+	Verdict string `json:"verdict"`
+	// Reasons 导致判定不是healthy的原因
+	Reasons []string `json:"reasons,omitempty"`
+	// Warnings 不影响判定但值得关注的情况，例如被cordon、Pod数量接近上限、近期的告警事件
+	Warnings      []string              `json:"warnings,omitempty"`
+	Unschedulable bool                  `json:"unschedulable"`
+	Conditions    []NodeConditionStatus `json:"conditions"`
+	SystemInfo    NodeSystemInfo        `json:"systemInfo"`
+	Capacity      map[string]string     `json:"capacity"`
+	Allocatable   map[string]string     `json:"allocatable"`
+	// Requested 节点上未结束的Pod请求的资源总和
+	Requested map[string]string `json:"requested,omitempty"`
+	Pods      NodePodCount      `json:"pods"`
+	Taints    []Taint           `json:"taints,omitempty"`
+	// Usage metrics-server不可用时为空
+	Usage  *NodeUsage  `json:"usage,omitempty"`
+	Events []NodeEvent `json:"events,omitempty"`
+}
+
+// NodeHealthReport 节点健康检查结果
+type NodeHealthReport struct {
+	Scanned          int            `json:"scanned"`
+	CountsByVerdict  map[string]int `json:"countsByVerdict"`
+	MetricsAvailable bool           `json:"metricsAvailable"`
+	Nodes            []NodeHealth   `json:"nodes"`
+	Errors           []string       `json:"errors,omitempty"`
+	RetrievedAt      time.Time      `json:"retrievedAt"`
+}
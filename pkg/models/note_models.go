@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// SessionNote 会话笔记
+type SessionNote struct {
+	Key       string     `json:"key"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// NoteSaveResult 保存笔记的结果
+type NoteSaveResult struct {
+	Note SessionNote `json:"note"`
+	// Replaced 是否覆盖了同名笔记
+	Replaced bool `json:"replaced"`
+	// Evicted 因超过数量上限被移除的最旧笔记
+	Evicted []string `json:"evicted,omitempty"`
+	Count   int      `json:"count"`
+	Limit   int      `json:"limit"`
+}
+
+// NoteList 当前会话的笔记
+type NoteList struct {
+	Notes []SessionNote `json:"notes"`
+	Count int           `json:"count"`
+	Limit int           `json:"limit"`
+}
+
+// NoteDeleteResult 删除笔记的结果
+type NoteDeleteResult struct {
+	Key     string `json:"key"`
+	Deleted bool   `json:"deleted"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// OrphanReport 孤立资源扫描结果
+type OrphanReport struct {
+	Namespaces    []string `json:"namespaces"`
+	Checks        []string `json:"checks"`
+	MinAgeHours   int      `json:"minAgeHours"`
+	JobMinAgeDays int      `json:"jobMinAgeDays"`
+	// Candidates 疑似孤立的资源，按检查项、命名空间和名称排序
+	Candidates    []OrphanCandidate `json:"candidates"`
+	CountsByCheck map[string]int    `json:"countsByCheck"`
+	// ReclaimableStorage 候选PVC的容量合计，未绑定的PVC按申请容量计算
+	ReclaimableStorage string `json:"reclaimableStorage,omitempty"`
+	// Skipped 因无法确认引用关系而跳过的检查项或命名空间
+	Skipped []string `json:"skipped,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+	Note    string   `json:"note"`
+}
+
+// OrphanCandidate 一个疑似孤立的资源
+type OrphanCandidate struct {
+	Check      string    `json:"check"`
+	Kind       string    `json:"kind"`
+	APIVersion string    `json:"apiVersion"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Age        string    `json:"age"`
+	// Evidence 判定为孤立的依据
+	Evidence []string `json:"evidence"`
+	// Storage PVC的容量，仅pvcs检查项设置
+	Storage string `json:"storage,omitempty"`
+	// DeleteCommand 删除该资源的工具调用，仅在请求emitDeleteCommands时设置
+	DeleteCommand *ToolCommand `json:"deleteCommand,omitempty"`
+}
+
+// ToolCommand 可以直接执行的工具调用
+type ToolCommand struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
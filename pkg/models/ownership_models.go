@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// OwnershipNode 所有权树中的一个对象
+type OwnershipNode struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	// Readiness 就绪状态："Ready"、"NotReady"、"Completed"、"Failed"，无法判断时为空
+	Readiness string `json:"readiness,omitempty"`
+	// Status 状态摘要，例如副本数"3/3"或Pod阶段
+	Status       string    `json:"status,omitempty"`
+	CreationTime time.Time `json:"creationTime"`
+	// Start 是否为查询的起始对象
+	Start    bool             `json:"start,omitempty"`
+	Children []*OwnershipNode `json:"children,omitempty"`
+	// Truncated 达到深度或数量上限，子对象未完整列出
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// OwnershipGraph 资源所有权查询结果
+type OwnershipGraph struct {
+	// Root 沿ownerReferences向上找到的根对象及其全部后代
+	Root *OwnershipNode `json:"root"`
+	// Ancestors 从起始对象到根对象的路径，格式为"Kind/name"
+	Ancestors []string `json:"ancestors"`
+	NodeCount int      `json:"nodeCount"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
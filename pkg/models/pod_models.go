@@ -0,0 +1,126 @@
+package models
+
+import "time"
+
+// PodRestartResult 删除或驱逐单个Pod的结果
+type PodRestartResult struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node,omitempty"`
+	// Action 执行的操作：deleted或evicted
+	Action string `json:"action"`
+	DryRun bool   `json:"dryRun,omitempty"`
+	// Controller 直接管理该Pod的控制器，格式为"Kind/name"，裸Pod为空
+	Controller string `json:"controller,omitempty"`
+	// Workload 该Pod所属的工作负载，ReplicaSet还原为Deployment
+	Workload string `json:"workload,omitempty"`
+	// ReplacementExpected 控制器是否会创建替代的Pod
+	ReplacementExpected bool   `json:"replacementExpected"`
+	Warning             string `json:"warning,omitempty"`
+	// Replacement 等待到的替代Pod，wait=false时为空
+	Replacement *ReplacementPod `json:"replacement,omitempty"`
+	Waited      string          `json:"waited,omitempty"`
+	// TimedOut 超过等待时间仍没有就绪的替代Pod
+	TimedOut bool   `json:"timedOut,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// ReplacementPod 控制器创建的替代Pod
+type ReplacementPod struct {
+	Name  string `json:"name"`
+	Node  string `json:"node,omitempty"`
+	Phase string `json:"phase"`
+	Ready bool   `json:"ready"`
+}
+
+// PodFileEntry 容器中的一个文件或目录
+type PodFileEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Type 文件类型：file、directory、symlink、device、pipe、socket
+	Type  string `json:"type"`
+	Mode  string `json:"mode"`
+	Size  int64  `json:"size"`
+	Owner string `json:"owner,omitempty"`
+	Group string `json:"group,omitempty"`
+	// Modified ls输出的修改时间，格式取决于容器中的ls实现
+	Modified   string `json:"modified,omitempty"`
+	LinkTarget string `json:"linkTarget,omitempty"`
+}
+
+// PodFileListing 列出容器中路径的结果
+type PodFileListing struct {
+	Pod       string         `json:"pod"`
+	Namespace string         `json:"namespace"`
+	Container string         `json:"container"`
+	Path      string         `json:"path"`
+	MaxDepth  int            `json:"maxDepth"`
+	Count     int            `json:"count"`
+	Truncated bool           `json:"truncated,omitempty"`
+	Entries   []PodFileEntry `json:"entries"`
+	// UsedBusybox 容器的PATH中没有所需命令，改为通过/bin/busybox执行
+	UsedBusybox bool `json:"usedBusybox,omitempty"`
+}
+
+// PodFileContent 读取容器中文件的结果
+type PodFileContent struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	Path      string `json:"path"`
+	// Bytes 返回的内容字节数
+	Bytes     int  `json:"bytes"`
+	Truncated bool `json:"truncated,omitempty"`
+	Binary    bool `json:"binary"`
+	// Encoding 内容的编码：文本为utf-8，二进制内容为base64
+	Encoding    string `json:"encoding"`
+	Content     string `json:"content"`
+	UsedBusybox bool   `json:"usedBusybox,omitempty"`
+}
+
+// PodFileUpload 复制文件到容器的结果
+type PodFileUpload struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	Path      string `json:"path"`
+	Bytes     int    `json:"bytes"`
+	// Mode 文件权限，八进制表示
+	Mode        string `json:"mode"`
+	UsedBusybox bool   `json:"usedBusybox,omitempty"`
+}
+
+// PodFileDownload 从容器复制文件的结果，内容以base64编码
+type PodFileDownload struct {
+	Pod         string    `json:"pod"`
+	Namespace   string    `json:"namespace"`
+	Container   string    `json:"container"`
+	Path        string    `json:"path"`
+	Bytes       int       `json:"bytes"`
+	Mode        string    `json:"mode"`
+	Modified    time.Time `json:"modified"`
+	Encoding    string    `json:"encoding"`
+	Content     string    `json:"content"`
+	UsedBusybox bool      `json:"usedBusybox,omitempty"`
+}
+
+// DebugContainerResult 添加临时调试容器的结果，可选包含在其中执行命令的输出
+type DebugContainerResult struct {
+	Pod             string `json:"pod"`
+	Namespace       string `json:"namespace"`
+	Container       string `json:"container"`
+	Image           string `json:"image"`
+	TargetContainer string `json:"targetContainer,omitempty"`
+	// State 临时容器的状态：running、waiting、terminated，等待超时仍没有状态时为pending
+	State        string   `json:"state"`
+	StateReason  string   `json:"stateReason,omitempty"`
+	StateMessage string   `json:"stateMessage,omitempty"`
+	Running      bool     `json:"running"`
+	Waited       string   `json:"waited"`
+	Command      []string `json:"command,omitempty"`
+	Stdout       string   `json:"stdout,omitempty"`
+	Stderr       string   `json:"stderr,omitempty"`
+	ExitCode     *int     `json:"exitCode,omitempty"`
+	Truncated    bool     `json:"truncated,omitempty"`
+	Hint         string   `json:"hint,omitempty"`
+}
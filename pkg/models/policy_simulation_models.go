@@ -0,0 +1,66 @@
+package models
+
+// NetworkPolicy模拟的判定结果
+const (
+	PolicyVerdictAllowed = "allowed"
+	PolicyVerdictDenied  = "denied"
+	// PolicyVerdictPartial Service的部分后端Pod允许、部分拒绝
+	PolicyVerdictPartial = "partial"
+)
+
+// PolicyEndpoint 模拟流量的源或目标
+type PolicyEndpoint struct {
+	Namespace string            `json:"namespace"`
+	Pod       string            `json:"pod,omitempty"`
+	Service   string            `json:"service,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// PolicyRuleTrace 一条规则的评估结果
+type PolicyRuleTrace struct {
+	// Index 规则在ingress或egress列表中的下标
+	Index   int    `json:"index"`
+	Matched bool   `json:"matched"`
+	Reason  string `json:"reason"`
+}
+
+// PolicyEvaluation 一个选中了相关Pod的NetworkPolicy的评估结果
+type PolicyEvaluation struct {
+	Policy    string `json:"policy"`
+	Namespace string `json:"namespace"`
+	Allowed   bool   `json:"allowed"`
+	// Rules 各条规则的评估过程，策略没有规则时为空，表示拒绝该方向的所有流量
+	Rules []PolicyRuleTrace `json:"rules,omitempty"`
+}
+
+// PolicyDirectionResult 入站或出站方向的评估结果
+type PolicyDirectionResult struct {
+	Allowed bool `json:"allowed"`
+	// Isolated 是否有NetworkPolicy在该方向选中了Pod，没有时默认允许所有流量
+	Isolated bool               `json:"isolated"`
+	Summary  string             `json:"summary"`
+	Policies []PolicyEvaluation `json:"policies,omitempty"`
+}
+
+// PolicyTargetEvaluation 到一个目标Pod的流量评估结果
+type PolicyTargetEvaluation struct {
+	Pod string `json:"pod"`
+	// Port 解析后的目标容器端口
+	Port     int32                 `json:"port,omitempty"`
+	PortName string                `json:"portName,omitempty"`
+	Verdict  string                `json:"verdict"`
+	Egress   PolicyDirectionResult `json:"egress"`
+	Ingress  PolicyDirectionResult `json:"ingress"`
+}
+
+// NetworkPolicySimulation NetworkPolicy模拟结果
+type NetworkPolicySimulation struct {
+	Source      PolicyEndpoint `json:"source"`
+	Destination PolicyEndpoint `json:"destination"`
+	Port        string         `json:"port"`
+	Protocol    string         `json:"protocol"`
+	// Verdict 所有目标Pod都允许时为allowed，都拒绝时为denied，否则为partial
+	Verdict string                   `json:"verdict"`
+	Targets []PolicyTargetEvaluation `json:"targets"`
+	Notes   []string                 `json:"notes,omitempty"`
+}
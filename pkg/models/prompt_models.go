@@ -140,7 +140,7 @@ var TroubleshootNodesPrompt = PromptTemplate{
 		},
 		{
 			Role:    "assistant",
-			Content: "我会帮你诊断节点问题并提供解决方案。以下是节点问题的排查步骤：",
+			Content: "我会先调用GET_NODE_HEALTH获取节点的条件、系统信息、资源容量、Pod数量、污点、近期事件和使用量，再根据健康判定及其原因给出排查步骤和解决方案：",
 		},
 	},
 }
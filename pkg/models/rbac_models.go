@@ -0,0 +1,153 @@
+package models
+
+import "time"
+
+// AccessCheckResult 定义权限检查结果
+type AccessCheckResult struct {
+	Verb            string    `json:"verb"`
+	Group           string    `json:"group,omitempty"`
+	Resource        string    `json:"resource"`
+	Subresource     string    `json:"subresource,omitempty"`
+	Namespace       string    `json:"namespace,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	Allowed         bool      `json:"allowed"`
+	Denied          bool      `json:"denied,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	EvaluationError string    `json:"evaluationError,omitempty"`
+	Cached          bool      `json:"cached"`
+	CheckedAt       time.Time `json:"checkedAt"`
+}
+
+// RoleGrant 定义主体获得权限的途径（绑定 -> 角色）
+type RoleGrant struct {
+	BindingKind      string `json:"bindingKind"`
+	BindingName      string `json:"bindingName"`
+	BindingNamespace string `json:"bindingNamespace,omitempty"`
+	RoleKind         string `json:"roleKind"`
+	RoleName         string `json:"roleName"`
+}
+
+// WhoCanSubject 定义拥有权限的主体
+type WhoCanSubject struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Grants    []RoleGrant `json:"grants"`
+}
+
+// WhoCanResponse 定义WHO_CAN查询响应结构
+type WhoCanResponse struct {
+	Verb        string          `json:"verb"`
+	Group       string          `json:"group,omitempty"`
+	Resource    string          `json:"resource"`
+	Subresource string          `json:"subresource,omitempty"`
+	Namespace   string          `json:"namespace,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Count       int             `json:"count"`
+	Subjects    []WhoCanSubject `json:"subjects"`
+	Warnings    []string        `json:"warnings,omitempty"`
+	RetrievedAt time.Time       `json:"retrievedAt"`
+}
+
+// SecurityFinding 工作负载安全扫描发现的问题
+type SecurityFinding struct {
+	// RuleID 规则编号，例如：SEC001
+	RuleID   string `json:"ruleId"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// Object 相关对象，格式为"Kind/name"
+	Object    string `json:"object"`
+	Namespace string `json:"namespace"`
+	// Path 问题所在的字段路径，例如：spec.template.spec.containers[0].securityContext.privileged
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// SecurityRuleInfo 安全扫描规则说明
+type SecurityRuleInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// SecurityScanReport 工作负载安全扫描结果
+type SecurityScanReport struct {
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	MinSeverity   string `json:"minSeverity"`
+	// ScannedObjects 扫描的工作负载和独立Pod数量
+	ScannedObjects int               `json:"scannedObjects"`
+	Summary        SeverityCounts    `json:"summary"`
+	ByRule         map[string]int    `json:"byRule"`
+	Findings       []SecurityFinding `json:"findings"`
+	// Rules 参与扫描的规则
+	Rules []SecurityRuleInfo `json:"rules"`
+	// Warnings 扫描过程中的问题，例如无权读取RBAC时跳过了令牌权限检查
+	Warnings    []string  `json:"warnings,omitempty"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// RoleRuleSummary 角色规则的权限范围摘要
+type RoleRuleSummary struct {
+	// Breadth 权限范围：full（通配动词和资源）、write（包含写操作）、read（只读）或none
+	Breadth           string   `json:"breadth"`
+	Verbs             []string `json:"verbs,omitempty"`
+	Resources         []string `json:"resources,omitempty"`
+	ResourceCount     int      `json:"resourceCount"`
+	WildcardVerbs     bool     `json:"wildcardVerbs"`
+	WildcardResources bool     `json:"wildcardResources"`
+	// HighRisk 规则授予的高风险权限，例如读取Secret、exec进入Pod
+	HighRisk []string `json:"highRisk,omitempty"`
+}
+
+// ServiceAccountBinding 授予ServiceAccount权限的绑定及其角色
+type ServiceAccountBinding struct {
+	BindingKind      string `json:"bindingKind"`
+	BindingName      string `json:"bindingName"`
+	BindingNamespace string `json:"bindingNamespace,omitempty"`
+	RoleKind         string `json:"roleKind"`
+	RoleName         string `json:"roleName"`
+	// RoleMissing 绑定引用的角色不存在
+	RoleMissing bool            `json:"roleMissing,omitempty"`
+	Summary     RoleRuleSummary `json:"summary"`
+}
+
+// ServiceAccountWorkload 使用ServiceAccount的工作负载
+type ServiceAccountWorkload struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// MountsToken 考虑Pod与ServiceAccount上的设置后是否挂载令牌
+	MountsToken bool `json:"mountsToken"`
+}
+
+// ImagePullSecretCheck ServiceAccount引用的镜像拉取Secret的检查结果
+type ImagePullSecretCheck struct {
+	Name   string `json:"name"`
+	Exists bool   `json:"exists"`
+	Type   string `json:"type,omitempty"`
+}
+
+// ServiceAccountAudit 一个ServiceAccount的审计结果
+type ServiceAccountAudit struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// AutomountToken ServiceAccount上的automountServiceAccountToken设置，未设置时为default（挂载）
+	AutomountToken   string                   `json:"automountToken"`
+	Workloads        []ServiceAccountWorkload `json:"workloads,omitempty"`
+	Bindings         []ServiceAccountBinding  `json:"bindings,omitempty"`
+	ImagePullSecrets []ImagePullSecretCheck   `json:"imagePullSecrets,omitempty"`
+	Findings         []Finding                `json:"findings,omitempty"`
+}
+
+// ServiceAccountAuditReport ServiceAccount审计结果
+type ServiceAccountAuditReport struct {
+	Namespace   string `json:"namespace,omitempty"`
+	MinSeverity string `json:"minSeverity"`
+	Scanned     int    `json:"scanned"`
+	// Summary 达到最低严重程度的发现数量
+	Summary         SeverityCounts        `json:"summary"`
+	ServiceAccounts []ServiceAccountAudit `json:"serviceAccounts"`
+	Warnings        []string              `json:"warnings,omitempty"`
+	RetrievedAt     time.Time             `json:"retrievedAt"`
+}
@@ -0,0 +1,75 @@
+package models
+
+// ResourceRecommendation RECOMMEND_RESOURCES的结果：按一段时间内采样的实际用量给出容器的requests和limits建议
+type ResourceRecommendation struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Replicas  int32  `json:"replicas"`
+	// Estimate 说明建议只是基于采样窗口的时间点估计
+	Estimate string `json:"estimate"`
+	Window   string `json:"window"`
+	// Samples 成功的采样次数，DataPoints 去掉metrics-server重复数据后每个Pod的平均数据点数
+	Samples    int                       `json:"samples"`
+	DataPoints int                       `json:"dataPoints"`
+	Pods       int                       `json:"pods"`
+	Factors    RecommendationFactors     `json:"factors"`
+	Containers []ContainerRecommendation `json:"containers"`
+	// Patch 可直接应用到工作负载的strategic merge patch，PatchType固定为strategic
+	Patch     string `json:"patch,omitempty"`
+	PatchType string `json:"patchType,omitempty"`
+	Command   string `json:"command,omitempty"`
+	// QuotaImpact 应用建议后命名空间ResourceQuota用量的变化
+	QuotaImpact []QuotaImpact `json:"quotaImpact,omitempty"`
+	Notes       []string      `json:"notes,omitempty"`
+}
+
+// RecommendationFactors 计算建议所用的系数和下限
+type RecommendationFactors struct {
+	// CPURequest、MemoryRequest 在p95用量上增加的余量倍数
+	CPURequest    float64 `json:"cpuRequest"`
+	MemoryRequest float64 `json:"memoryRequest"`
+	// CPULimit、MemoryLimit limits相对requests的倍数，0表示不建议设置该limit
+	CPULimit    float64 `json:"cpuLimit"`
+	MemoryLimit float64 `json:"memoryLimit"`
+	MinCPU      string  `json:"minCpu"`
+	MinMemory   string  `json:"minMemory"`
+}
+
+// ContainerRecommendation 单个容器的用量统计和建议
+type ContainerRecommendation struct {
+	Name string `json:"name"`
+	// Sidecar 容器是否为原生边车容器（位于initContainers中）
+	Sidecar     bool             `json:"sidecar,omitempty"`
+	DataPoints  int              `json:"dataPoints"`
+	CPU         UsagePercentiles `json:"cpu"`
+	Memory      UsagePercentiles `json:"memory"`
+	Current     ResourceSettings `json:"current"`
+	Recommended ResourceSettings `json:"recommended"`
+	// Explanation 建议的计算依据和需要注意的地方
+	Explanation []string `json:"explanation"`
+}
+
+// UsagePercentiles 采样窗口内用量的分位数，CPU为毫核，内存为字节
+type UsagePercentiles struct {
+	P50 string `json:"p50"`
+	P95 string `json:"p95"`
+	Max string `json:"max"`
+}
+
+// ResourceSettings 容器的requests和limits
+type ResourceSettings struct {
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+}
+
+// QuotaImpact 应用建议后一项ResourceQuota的用量变化
+type QuotaImpact struct {
+	Quota    string `json:"quota"`
+	Resource string `json:"resource"`
+	Hard     string `json:"hard"`
+	Used     string `json:"used"`
+	Change   string `json:"change"`
+	After    string `json:"after"`
+	Fits     bool   `json:"fits"`
+}
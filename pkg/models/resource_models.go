@@ -32,6 +32,35 @@ type Taint struct {
 	Effect string `json:"effect"`
 }
 
+// NodeTaintResult 添加或删除节点污点的结果
+type NodeTaintResult struct {
+	Node string `json:"node"`
+	// Action 执行的操作："added"、"replaced"、"removed"或"unchanged"
+	Action  string  `json:"action"`
+	Taint   *Taint  `json:"taint,omitempty"`
+	Removed []Taint `json:"removed,omitempty"`
+	// Taints 操作完成后节点上的全部污点
+	Taints []Taint `json:"taints"`
+}
+
+// NodeTaintGroup 带有相同污点的节点
+type NodeTaintGroup struct {
+	Taint Taint    `json:"taint"`
+	Nodes []string `json:"nodes"`
+	Count int      `json:"count"`
+}
+
+// NodeTaintSummary 集群中按污点分组的节点
+type NodeTaintSummary struct {
+	TotalNodes int              `json:"totalNodes"`
+	Groups     []NodeTaintGroup `json:"groups"`
+	// Cordoned 被标记为不可调度（spec.unschedulable）的节点
+	Cordoned []string `json:"cordoned,omitempty"`
+	// Untainted 没有任何污点的节点数量
+	Untainted   int       `json:"untainted"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
 // NodeListResponse 定义节点列表响应结构
 type NodeListResponse struct {
 	Count       int        `json:"count"`
@@ -66,14 +95,76 @@ type ResourceInfo struct {
 	CreationTime time.Time         `json:"creationTime"`
 }
 
+// ListPagination 定义列表响应中的分页信息
+type ListPagination struct {
+	// Continue 获取下一页时使用的令牌，为空表示已是最后一页
+	Continue string `json:"continue,omitempty"`
+	// RemainingItemCount API Server估算的剩余资源数量
+	RemainingItemCount *int64 `json:"remainingItemCount,omitempty"`
+	// Truncated 结果因超过服务器上限被截断
+	Truncated bool `json:"truncated,omitempty"`
+	// Limit 截断时使用的单页上限
+	Limit int64 `json:"limit,omitempty"`
+}
+
 // ResourceListResponse 定义通用资源列表响应结构
 type ResourceListResponse struct {
-	Count       int            `json:"count"`
-	Kind        string         `json:"kind"`
-	APIVersion  string         `json:"apiVersion"`
-	Namespace   string         `json:"namespace,omitempty"`
-	Resources   []ResourceInfo `json:"resources"`
-	RetrievedAt time.Time      `json:"retrievedAt"`
+	Count      int    `json:"count"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Namespace  string `json:"namespace,omitempty"`
+	// ClusterScoped 资源为集群级，不属于任何命名空间
+	ClusterScoped bool           `json:"clusterScoped,omitempty"`
+	LabelSelector string         `json:"labelSelector,omitempty"`
+	FieldSelector string         `json:"fieldSelector,omitempty"`
+	Resources     []ResourceInfo `json:"resources"`
+	// AllNamespaces 跨所有命名空间列出，Resources按命名空间分组排列
+	AllNamespaces bool `json:"allNamespaces,omitempty"`
+	// NamespaceCount 本页结果来自的命名空间数量
+	NamespaceCount int `json:"namespaceCount,omitempty"`
+	// Namespaces 本页结果中各命名空间的资源数量
+	Namespaces []NamespaceItemCount `json:"namespaces,omitempty"`
+	ListPagination
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// NamespaceItemCount 跨命名空间列表时单个命名空间的资源数量
+type NamespaceItemCount struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
+// WorkloadInfo 定义Apps工作负载的列表信息
+type WorkloadInfo struct {
+	Name         string            `json:"name"`
+	Replicas     *int64            `json:"replicas,omitempty"`
+	Available    *int64            `json:"available,omitempty"`
+	Ready        *int64            `json:"ready,omitempty"`
+	Desired      *int64            `json:"desired,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	CreationTime time.Time         `json:"creationTime"`
+}
+
+// WorkloadListResponse 定义Apps工作负载列表响应结构
+type WorkloadListResponse struct {
+	Count      int            `json:"count"`
+	Kind       string         `json:"kind"`
+	APIVersion string         `json:"apiVersion"`
+	Namespace  string         `json:"namespace,omitempty"`
+	Workloads  []WorkloadInfo `json:"workloads"`
+	ListPagination
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// ResourceOperationResult 定义创建、更新、应用、删除等写操作的响应结构
+type ResourceOperationResult struct {
+	Operation    string `json:"operation"`
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace,omitempty"`
+	FieldManager string `json:"fieldManager,omitempty"`
+	DryRun       bool   `json:"dryRun"`
+	Message      string `json:"message"`
 }
 
 // ResourceDescription 表示资源的详细描述信息
@@ -133,3 +224,66 @@ func NewResourceDescriptionFromUnstructured(obj *unstructured.Unstructured) Reso
 
 	return desc
 }
+
+// ApplyConflict 描述服务端应用时的单个字段所有权冲突
+type ApplyConflict struct {
+	Field   string `json:"field"`
+	Manager string `json:"manager,omitempty"`
+	Message string `json:"message"`
+}
+
+// ApplyConflictResponse 定义服务端应用冲突的响应结构
+type ApplyConflictResponse struct {
+	Kind         string          `json:"kind"`
+	Name         string          `json:"name"`
+	Namespace    string          `json:"namespace,omitempty"`
+	FieldManager string          `json:"fieldManager"`
+	Conflicts    []ApplyConflict `json:"conflicts"`
+	Hint         string          `json:"hint"`
+}
+
+// ObjectStatus 定义对象名称及其简要状态
+type ObjectStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+}
+
+// WorkloadSummary 定义某类工作负载的汇总信息
+type WorkloadSummary struct {
+	Kind     string         `json:"kind"`
+	Total    int            `json:"total"`
+	Ready    int            `json:"ready"`
+	NotReady int            `json:"notReady"`
+	Objects  []ObjectStatus `json:"objects,omitempty"`
+}
+
+// NamespaceUsage 定义命名空间的实际资源使用情况
+type NamespaceUsage struct {
+	CPUMillicores        int64   `json:"cpuMillicores"`
+	MemoryMB             int64   `json:"memoryMB"`
+	PodsWithMetrics      int     `json:"podsWithMetrics"`
+	ClusterCPUPercent    float64 `json:"clusterCPUPercent,omitempty"`
+	ClusterMemoryPercent float64 `json:"clusterMemoryPercent,omitempty"`
+}
+
+// NamespaceDescription 定义命名空间概览响应结构
+type NamespaceDescription struct {
+	Name           string            `json:"name"`
+	Status         string            `json:"status"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	CreationTime   time.Time         `json:"creationTime"`
+	Workloads      []WorkloadSummary `json:"workloads"`
+	PodsByPhase    map[string]int    `json:"podsByPhase"`
+	Pods           []ObjectStatus    `json:"pods,omitempty"`
+	ServiceCount   int               `json:"serviceCount"`
+	Services       []ObjectStatus    `json:"services,omitempty"`
+	IngressCount   int               `json:"ingressCount"`
+	Ingresses      []ObjectStatus    `json:"ingresses,omitempty"`
+	ResourceQuotas []QuotaUsage      `json:"resourceQuotas,omitempty"`
+	LimitRanges    []LimitRangeInfo  `json:"limitRanges,omitempty"`
+	WarningEvents  []DiagnosisEvent  `json:"warningEvents"`
+	Usage          *NamespaceUsage   `json:"usage,omitempty"`
+	MetricsError   string            `json:"metricsError,omitempty"`
+	Errors         []string          `json:"errors,omitempty"`
+	RetrievedAt    time.Time         `json:"retrievedAt"`
+}
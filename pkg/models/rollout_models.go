@@ -0,0 +1,30 @@
+package models
+
+// SetImageResult 更新工作负载容器镜像的结果
+type SetImageResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Container string `json:"container"`
+	// InitContainer 更新的是否为初始化容器
+	InitContainer bool   `json:"initContainer,omitempty"`
+	OldImage      string `json:"oldImage"`
+	NewImage      string `json:"newImage"`
+	// Changed 新镜像与原镜像相同时为false，不会发起patch
+	Changed bool `json:"changed"`
+	DryRun  bool `json:"dryRun,omitempty"`
+	// ChangeCause 写入kubernetes.io/change-cause注解的内容
+	ChangeCause string `json:"changeCause,omitempty"`
+	// Rollout 等待滚动更新的结果，wait=false时为空
+	Rollout *RolloutWaitResult `json:"rollout,omitempty"`
+}
+
+// RolloutWaitResult 等待滚动更新完成的结果
+type RolloutWaitResult struct {
+	Complete bool   `json:"complete"`
+	Message  string `json:"message,omitempty"`
+	Waited   string `json:"waited"`
+	// TimedOut 超过等待时间仍未完成
+	TimedOut bool   `json:"timedOut,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
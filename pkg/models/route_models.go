@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// RouteBackend 路由指向的后端Service
+type RouteBackend struct {
+	Service string `json:"service"`
+	// Namespace 后端所在的命名空间，与路由不同时设置
+	Namespace     string `json:"namespace,omitempty"`
+	Port          string `json:"port,omitempty"`
+	Weight        *int64 `json:"weight,omitempty"`
+	ServiceExists bool   `json:"serviceExists"`
+	PortExists    bool   `json:"portExists"`
+}
+
+// RouteEntry 一条主机名和路径到后端的映射，来自Ingress规则或HTTPRoute规则
+type RouteEntry struct {
+	// Kind 路由来源：Ingress或HTTPRoute
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	PathType  string `json:"pathType,omitempty"`
+	// Backends 后端Service，HTTPRoute规则可按权重指向多个后端
+	Backends     []RouteBackend `json:"backends"`
+	TLSSecret    string         `json:"tlsSecret,omitempty"`
+	IngressClass string         `json:"ingressClass,omitempty"`
+	// Gateways HTTPRoute挂载的Gateway，格式为namespace/name
+	Gateways []string `json:"gateways,omitempty"`
+	// Problems 不存在的后端Service、端口或TLS Secret
+	Problems []string `json:"problems,omitempty"`
+}
+
+// RouteHostGroup 同一主机名下的路由，未指定主机名的路由归入"*"
+type RouteHostGroup struct {
+	Host   string       `json:"host"`
+	Routes []RouteEntry `json:"routes"`
+}
+
+// RouteConflict 不同命名空间中的路由声明了相同的主机名和路径
+type RouteConflict struct {
+	Host string `json:"host"`
+	Path string `json:"path"`
+	// Claims 声明该主机名和路径的路由，格式为Kind namespace/name
+	Claims []string `json:"claims"`
+}
+
+// GatewayListener Gateway的监听器
+type GatewayListener struct {
+	Name       string   `json:"name"`
+	Hostname   string   `json:"hostname,omitempty"`
+	Port       int64    `json:"port"`
+	Protocol   string   `json:"protocol"`
+	TLSSecrets []string `json:"tlsSecrets,omitempty"`
+}
+
+// GatewaySummary Gateway API中的Gateway概要
+type GatewaySummary struct {
+	Namespace    string            `json:"namespace"`
+	Name         string            `json:"name"`
+	GatewayClass string            `json:"gatewayClass"`
+	Addresses    []string          `json:"addresses,omitempty"`
+	Listeners    []GatewayListener `json:"listeners"`
+	Problems     []string          `json:"problems,omitempty"`
+}
+
+// RoutingTable 集群HTTP路由表，汇总Ingress、Gateway和HTTPRoute
+type RoutingTable struct {
+	Namespace  string `json:"namespace,omitempty"`
+	HostFilter string `json:"hostFilter,omitempty"`
+	// GatewayAPIVersion 集群提供的Gateway API版本，未安装Gateway API时为空
+	GatewayAPIVersion string           `json:"gatewayAPIVersion,omitempty"`
+	RouteCount        int              `json:"routeCount"`
+	ProblemCount      int              `json:"problemCount"`
+	Hosts             []RouteHostGroup `json:"hosts"`
+	Gateways          []GatewaySummary `json:"gateways,omitempty"`
+	Conflicts         []RouteConflict  `json:"conflicts,omitempty"`
+	Errors            []string         `json:"errors,omitempty"`
+	RetrievedAt       time.Time        `json:"retrievedAt"`
+}
@@ -0,0 +1,137 @@
+package models
+
+// ConcurrencyStatus 工具调用并发限制的当前状态
+type ConcurrencyStatus struct {
+	// MaxConcurrent 全局最大并发工具调用数，0表示不限制
+	MaxConcurrent int `json:"maxConcurrent"`
+	// MaxConcurrentExpensive 高开销工具的最大并发调用数，0表示不限制
+	MaxConcurrentExpensive int `json:"maxConcurrentExpensive"`
+	// QueueTimeout 超过限制的调用最多等待的时间
+	QueueTimeout string `json:"queueTimeout"`
+	// InFlight 按类别统计的正在执行的调用数
+	InFlight map[string]int64 `json:"inFlight"`
+	// Queued 正在等待执行的调用数
+	Queued int64 `json:"queued"`
+	// Rejected 因等待超时被拒绝的调用总数
+	Rejected int64 `json:"rejected"`
+}
+
+// ServerStatus 服务器运行状态
+type ServerStatus struct {
+	Build       BuildInfo         `json:"build"`
+	StartedAt   string            `json:"startedAt"`
+	Uptime      string            `json:"uptime"`
+	Settings    ServerSettings    `json:"settings"`
+	Cluster     ClusterConnection `json:"cluster"`
+	Concurrency ConcurrencyStatus `json:"concurrency"`
+	Cache       CacheStatus       `json:"cache"`
+	ToolCalls   ToolCallStats     `json:"toolCalls"`
+	// RegisteredTools 按配置过滤后实际注册的工具
+	RegisteredTools []string `json:"registeredTools"`
+	// Notes 当前会话的笔记，仅在includeNotes=true时返回
+	Notes []SessionNote `json:"notes,omitempty"`
+}
+
+// BuildInfo 服务器构建信息
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// ServerSettings 影响工具行为的服务器配置
+type ServerSettings struct {
+	Transport             string `json:"transport"`
+	PreflightAuthz        bool   `json:"preflightAuthz"`
+	AllowSecretValues     bool   `json:"allowSecretValues"`
+	AllowExec             bool   `json:"allowExec"`
+	MaxCopyBytes          int    `json:"maxCopyBytes"`
+	MaxListItems          int    `json:"maxListItems"`
+	MaxResultBytes        int    `json:"maxResultBytes"`
+	MaxRetries            int    `json:"maxRetries"`
+	ToolTimeoutSeconds    int    `json:"toolTimeoutSeconds"`
+	MaxToolTimeoutSeconds int    `json:"maxToolTimeoutSeconds"`
+	DiscoveryCacheTTL     string `json:"discoveryCacheTTL"`
+	MaxNotes              int    `json:"maxNotes"`
+	MaxNoteBytes          int    `json:"maxNoteBytes"`
+	// NotesPersisted 笔记是否持久化到文件
+	NotesPersisted bool `json:"notesPersisted"`
+	// ToolHistorySize 每个会话保留的工具调用记录数，0表示不记录
+	ToolHistorySize int `json:"toolHistorySize"`
+	// EnabledToolGroups 启用的工具组，为空表示全部启用
+	EnabledToolGroups []string `json:"enabledToolGroups,omitempty"`
+	// DisabledTools 禁用的工具或工具组
+	DisabledTools []string `json:"disabledTools,omitempty"`
+	// ToolNaming 通用资源工具的命名方式：flat或prefixed
+	ToolNaming string `json:"toolNaming"`
+	// LegacyToolAliases flat命名时是否注册了按API组命名的旧工具名
+	LegacyToolAliases bool `json:"legacyToolAliases,omitempty"`
+	// ConfigFile 配置文件路径，未使用配置文件时为空
+	ConfigFile string `json:"configFile,omitempty"`
+	// ReadOnly 是否拒绝变更集群的工具调用
+	ReadOnly bool `json:"readOnly"`
+	// AllowedNamespaces 工具调用允许使用的命名空间，为空表示不限制
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// ClusterConnection 当前连接的集群及连通性检查结果
+type ClusterConnection struct {
+	// Context kubeconfig中的当前上下文，使用集群内配置时为空
+	Context string `json:"context,omitempty"`
+	// ClusterName kubeconfig中当前上下文对应的集群名称
+	ClusterName string `json:"clusterName,omitempty"`
+	// Server API Server地址，不包含凭据
+	Server string `json:"server,omitempty"`
+	// InCluster 是否使用集群内配置
+	InCluster bool `json:"inCluster"`
+	// Reachable 连通性检查是否成功
+	Reachable bool `json:"reachable"`
+	// Latency 连通性检查（/version请求）的耗时
+	Latency string `json:"latency,omitempty"`
+	// ServerVersion API Server版本
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// Error 连通性检查失败的原因
+	Error string `json:"error,omitempty"`
+}
+
+// CacheStatus Discovery与命名空间缓存的命中统计
+type CacheStatus struct {
+	// DiscoveryAge Discovery缓存自上次加载以来的时长，尚未加载时为空
+	DiscoveryAge    string `json:"discoveryAge,omitempty"`
+	DiscoveryHits   int64  `json:"discoveryHits"`
+	DiscoveryMisses int64  `json:"discoveryMisses"`
+	NamespaceHits   int64  `json:"namespaceHits"`
+	NamespaceMisses int64  `json:"namespaceMisses"`
+	// Entries 命名空间、节点、CRD等查询缓存各项的状态
+	Entries []CacheEntryStatus `json:"entries,omitempty"`
+}
+
+// CacheEntryStatus 单个查询缓存项的状态和命中统计
+type CacheEntryStatus struct {
+	Key    string `json:"key"`
+	TTL    string `json:"ttl"`
+	Cached bool   `json:"cached"`
+	// Age 缓存值自加载以来的时长，未缓存时为空
+	Age    string `json:"age,omitempty"`
+	Hits   int64  `json:"hits"`
+	Misses int64  `json:"misses"`
+}
+
+// CacheInvalidationResult INVALIDATE_CACHE的结果
+type CacheInvalidationResult struct {
+	Pattern string `json:"pattern"`
+	// Invalidated 失效前有缓存值的键
+	Invalidated []string `json:"invalidated"`
+	// Discovery 是否同时清空了API发现缓存
+	Discovery     bool               `json:"discovery,omitempty"`
+	Entries       []CacheEntryStatus `json:"entries"`
+	InvalidatedAt string             `json:"invalidatedAt"`
+}
+
+// ToolCallStats 服务器启动以来的工具调用统计
+type ToolCallStats struct {
+	Total  int64            `json:"total"`
+	Errors int64            `json:"errors"`
+	ByTool map[string]int64 `json:"byTool"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// NamespaceSnapshotResult 创建命名空间快照的结果
+type NamespaceSnapshotResult struct {
+	SnapshotID   string         `json:"snapshotId"`
+	Namespace    string         `json:"namespace"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	ExpiresAt    time.Time      `json:"expiresAt"`
+	ObjectCount  int            `json:"objectCount"`
+	CountsByKind map[string]int `json:"countsByKind"`
+	// ContentStored 是否保存了对象内容，未保存时DIFF_SNAPSHOT不能返回字段级差异
+	ContentStored bool     `json:"contentStored"`
+	SkippedKinds  []string `json:"skippedKinds,omitempty"`
+	// Evicted 因快照数量超过上限被移除的旧快照
+	Evicted []string `json:"evicted,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+	Note    string   `json:"note,omitempty"`
+}
+
+// SnapshotObjectRef 快照比较结果中的对象
+type SnapshotObjectRef struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+}
+
+// SnapshotObjectChange 内容哈希发生变化的对象
+type SnapshotObjectChange struct {
+	SnapshotObjectRef
+	OldHash string `json:"oldHash"`
+	NewHash string `json:"newHash"`
+	// Changes 字段级差异，仅在请求fieldDiffs且快照保存了对象内容时设置
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// SnapshotDiffResult 命名空间当前状态与快照的比较结果
+type SnapshotDiffResult struct {
+	SnapshotID string                 `json:"snapshotId"`
+	Namespace  string                 `json:"namespace"`
+	SnapshotAt time.Time              `json:"snapshotAt"`
+	ComparedAt time.Time              `json:"comparedAt"`
+	Added      []SnapshotObjectRef    `json:"added"`
+	Removed    []SnapshotObjectRef    `json:"removed"`
+	Changed    []SnapshotObjectChange `json:"changed"`
+	Unchanged  int                    `json:"unchanged"`
+	// Errors 重新扫描时无法列出的资源类型，这些类型的对象不会被报告为已删除
+	Errors []string `json:"errors,omitempty"`
+	Note   string   `json:"note,omitempty"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// WorkloadSpreadReport 工作负载Pod在节点和可用区上的分布，以及能否进一步分散
+type WorkloadSpreadReport struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// LabelSelector 用于匹配Pod的选择器，指定工作负载时为其spec.selector
+	LabelSelector string `json:"labelSelector"`
+	// Replicas 工作负载期望的副本数，按标签选择器查询时为空
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Pods 匹配的未终止Pod数量，Unscheduled 其中尚未调度到节点的数量
+	Pods        int `json:"pods"`
+	Unscheduled int `json:"unscheduled"`
+	// PodRequests 单个Pod的有效资源请求，用于计算节点还能容纳的副本数
+	PodRequests map[string]string `json:"podRequests"`
+	// Nodes 运行该工作负载Pod的节点以及可以容纳其Pod的节点
+	Nodes []NodePlacement `json:"nodes"`
+	// ExcludedNodes 既没有该工作负载的Pod、也无法容纳其Pod的节点数量
+	ExcludedNodes int             `json:"excludedNodes"`
+	Zones         []ZonePlacement `json:"zones"`
+	// Constraints Pod模板中拓扑分布约束和Pod反亲和性的检查结果
+	Constraints []SpreadConstraintCheck `json:"constraints"`
+	// CanSpreadFurther 是否存在还没有该工作负载Pod、可以容纳其Pod并且有剩余容量的节点
+	CanSpreadFurther bool `json:"canSpreadFurther"`
+	// SpreadTargetNodes 上述节点的数量，SpreadTargetSample 最多列出的节点名称
+	SpreadTargetNodes  int      `json:"spreadTargetNodes"`
+	SpreadTargetSample []string `json:"spreadTargetSample,omitempty"`
+	// SpreadTargetZones 还没有该工作负载Pod、但有可容纳节点的可用区
+	SpreadTargetZones []string `json:"spreadTargetZones,omitempty"`
+	// Findings 单点故障和约束违反等风险，与CHECK_AVAILABILITY的发现格式相同
+	Findings    []AvailabilityFinding `json:"findings"`
+	Notes       []string              `json:"notes,omitempty"`
+	RetrievedAt time.Time             `json:"retrievedAt"`
+}
+
+// NodePlacement 单个节点上的Pod分布和剩余容量
+type NodePlacement struct {
+	Node string `json:"node"`
+	Zone string `json:"zone,omitempty"`
+	// Pods 该工作负载在节点上的Pod数量
+	Pods     int      `json:"pods"`
+	PodNames []string `json:"podNames,omitempty"`
+	// Eligible 节点是否可以容纳该工作负载的新Pod（可调度、就绪、污点已容忍、满足nodeSelector和节点亲和性）
+	Eligible bool `json:"eligible"`
+	// Blockers 节点无法容纳新Pod的原因，Untolerated Pod未容忍的污点
+	Blockers    []string `json:"blockers,omitempty"`
+	Untolerated []string `json:"untolerated,omitempty"`
+	// Free 节点剩余的可分配资源（可分配量减去所有Pod的请求）
+	Free map[string]string `json:"free"`
+	// AdditionalReplicas 节点按剩余资源还能容纳的副本数，LimitedBy 限制该数量的资源
+	AdditionalReplicas int    `json:"additionalReplicas"`
+	LimitedBy          string `json:"limitedBy,omitempty"`
+}
+
+// ZonePlacement 单个可用区上的Pod分布
+type ZonePlacement struct {
+	Zone string `json:"zone"`
+	Pods int    `json:"pods"`
+	// Nodes 可用区内运行该工作负载Pod的节点数量，EligibleNodes 可以容纳其Pod的节点数量
+	Nodes              int `json:"nodes"`
+	EligibleNodes      int `json:"eligibleNodes"`
+	AdditionalReplicas int `json:"additionalReplicas"`
+}
+
+// SpreadConstraintCheck 单条拓扑分布约束或Pod反亲和性规则在当前分布下是否满足
+type SpreadConstraintCheck struct {
+	// Type 为topologySpreadConstraint或podAntiAffinity
+	Type        string `json:"type"`
+	TopologyKey string `json:"topologyKey"`
+	// MaxSkew、WhenUnsatisfiable、Skew 只用于拓扑分布约束
+	MaxSkew           int32  `json:"maxSkew,omitempty"`
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty"`
+	Skew              *int   `json:"skew,omitempty"`
+	// Required 反亲和性是否为requiredDuringScheduling规则
+	Required bool `json:"required,omitempty"`
+	// Counts 每个拓扑域中匹配规则选择器的Pod数量
+	Counts   map[string]int `json:"counts"`
+	Violated bool           `json:"violated"`
+	Message  string         `json:"message"`
+}
+
+// 分布约束检查类型
+const (
+	SpreadCheckTopologySpread  = "topologySpreadConstraint"
+	SpreadCheckPodAntiAffinity = "podAntiAffinity"
+)
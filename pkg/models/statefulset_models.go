@@ -0,0 +1,73 @@
+package models
+
+// StatefulSetPartitionResult 修改StatefulSet滚动更新分区的结果
+type StatefulSetPartitionResult struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Replicas  int32  `json:"replicas"`
+	// OldPartition 修改前的分区，未设置时为0
+	OldPartition int32 `json:"oldPartition"`
+	NewPartition int32 `json:"newPartition"`
+	Changed      bool  `json:"changed"`
+	DryRun       bool  `json:"dryRun,omitempty"`
+	// UpdatedOrdinals 序号不小于分区、会更新到新版本的Pod序号
+	UpdatedOrdinals []int32 `json:"updatedOrdinals"`
+	CurrentRevision string  `json:"currentRevision,omitempty"`
+	UpdateRevision  string  `json:"updateRevision,omitempty"`
+	UpdatedReplicas int32   `json:"updatedReplicas"`
+	Warning         string  `json:"warning,omitempty"`
+}
+
+// StatefulSetPVC 由volumeClaimTemplates为某个序号生成的PVC
+type StatefulSetPVC struct {
+	Ordinal  int32  `json:"ordinal"`
+	Template string `json:"template"`
+	Name     string `json:"name"`
+	// Exists PVC是否存在，Pod尚未创建或PVC已被删除时为false
+	Exists       bool     `json:"exists"`
+	Phase        string   `json:"phase,omitempty"`
+	Requested    string   `json:"requested,omitempty"`
+	Capacity     string   `json:"capacity,omitempty"`
+	StorageClass string   `json:"storageClass,omitempty"`
+	AccessModes  []string `json:"accessModes,omitempty"`
+	VolumeName   string   `json:"volumeName,omitempty"`
+	// Terminating PVC正在删除，通常在等待使用它的Pod退出
+	Terminating bool `json:"terminating,omitempty"`
+	// Orphaned 序号超出当前副本数，缩容后保留下来的PVC
+	Orphaned bool   `json:"orphaned,omitempty"`
+	Pod      string `json:"pod"`
+	// PodPhase 对应Pod的阶段，Pod不存在时为空
+	PodPhase string `json:"podPhase,omitempty"`
+}
+
+// StatefulSetPVCList StatefulSet各序号的PVC
+type StatefulSetPVCList struct {
+	StatefulSet string   `json:"statefulSet"`
+	Namespace   string   `json:"namespace"`
+	Replicas    int32    `json:"replicas"`
+	Templates   []string `json:"templates"`
+	// WhenDeleted、WhenScaled 来自spec.persistentVolumeClaimRetentionPolicy，未设置时为Retain
+	WhenDeleted string           `json:"whenDeleted"`
+	WhenScaled  string           `json:"whenScaled"`
+	PVCs        []StatefulSetPVC `json:"pvcs"`
+	Missing     int              `json:"missing"`
+	Orphaned    int              `json:"orphaned"`
+}
+
+// StatefulSetPVCDeleteResult 删除StatefulSet某个序号的PVC的结果
+type StatefulSetPVCDeleteResult struct {
+	StatefulSet string `json:"statefulSet"`
+	Namespace   string `json:"namespace"`
+	Ordinal     int32  `json:"ordinal"`
+	PVC         string `json:"pvc"`
+	Capacity    string `json:"capacity,omitempty"`
+	VolumeName  string `json:"volumeName,omitempty"`
+	// ReclaimPolicy 绑定PV的回收策略，Delete表示删除PVC后数据随PV一起被删除
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+	Pod           string `json:"pod"`
+	// PodState 删除时对应Pod的状态：absent、terminating、deleted（由本次调用删除）或Pod阶段的小写形式
+	PodState string `json:"podState"`
+	DryRun   bool   `json:"dryRun,omitempty"`
+	Message  string `json:"message"`
+	Warning  string `json:"warning,omitempty"`
+}
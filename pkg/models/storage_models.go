@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// PVCStatus 定义PersistentVolumeClaim的存储状态
+type PVCStatus struct {
+	Name         string           `json:"name"`
+	Namespace    string           `json:"namespace"`
+	Phase        string           `json:"phase"`
+	StorageClass string           `json:"storageClass,omitempty"`
+	VolumeMode   string           `json:"volumeMode,omitempty"`
+	AccessModes  []string         `json:"accessModes,omitempty"`
+	Requested    string           `json:"requested,omitempty"`
+	Capacity     string           `json:"capacity,omitempty"`
+	VolumeName   string           `json:"volumeName,omitempty"`
+	MountedBy    []string         `json:"mountedBy,omitempty"`
+	Events       []DiagnosisEvent `json:"events,omitempty"`
+}
+
+// PVStatus 定义PersistentVolume的存储状态
+type PVStatus struct {
+	Name                 string `json:"name"`
+	Phase                string `json:"phase"`
+	Capacity             string `json:"capacity,omitempty"`
+	StorageClass         string `json:"storageClass,omitempty"`
+	ReclaimPolicy        string `json:"reclaimPolicy,omitempty"`
+	ClaimRef             string `json:"claimRef,omitempty"`
+	Reason               string `json:"reason,omitempty"`
+	ReleasedNotReclaimed bool   `json:"releasedNotReclaimed"`
+}
+
+// StorageClassUsage 定义StorageClass的使用汇总
+type StorageClassUsage struct {
+	Name          string `json:"name"`
+	Provisioner   string `json:"provisioner,omitempty"`
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+	Default       bool   `json:"default"`
+	PVCCount      int    `json:"pvcCount"`
+	PVCount       int    `json:"pvCount"`
+	Requested     string `json:"requested"`
+	Bound         string `json:"bound"`
+}
+
+// StorageStatusResponse 定义存储健康状态的响应结构
+type StorageStatusResponse struct {
+	Namespace      string              `json:"namespace,omitempty"`
+	StorageClass   string              `json:"storageClass,omitempty"`
+	PVCs           []PVCStatus         `json:"pvcs"`
+	PVs            []PVStatus          `json:"pvs"`
+	StorageClasses []StorageClassUsage `json:"storageClasses"`
+	Findings       []Finding           `json:"findings"`
+	RetrievedAt    time.Time           `json:"retrievedAt"`
+}
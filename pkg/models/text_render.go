@@ -0,0 +1,547 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// textTimeLayout text格式中绝对时间的显示格式，时区为请求的timezone
+const textTimeLayout = "2006-01-02 15:04:05 Z07:00"
+
+// RenderText 以便于阅读的文本呈现资源列表
+func (r ResourceListResponse) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Found %d %s resources", r.Count, r.Kind))
+	if r.ClusterScoped {
+		b.WriteString(" (cluster-scoped)")
+	} else if r.AllNamespaces {
+		b.WriteString(fmt.Sprintf(" across %d namespaces", r.NamespaceCount))
+	} else if r.Namespace != "" {
+		b.WriteString(fmt.Sprintf(" in namespace %s", r.Namespace))
+	}
+	if r.LabelSelector != "" {
+		b.WriteString(fmt.Sprintf(" with label selector '%s'", r.LabelSelector))
+	}
+	if r.FieldSelector != "" {
+		b.WriteString(fmt.Sprintf(" with field selector '%s'", r.FieldSelector))
+	}
+	b.WriteString(":\n\n")
+
+	currentNamespace := ""
+	for i, item := range r.Resources {
+		if r.AllNamespaces && (i == 0 || item.Namespace != currentNamespace) {
+			currentNamespace = item.Namespace
+			b.WriteString(fmt.Sprintf("Namespace: %s\n", currentNamespace))
+		}
+		b.WriteString(fmt.Sprintf("Name: %s\n", item.Name))
+		if len(item.Labels) > 0 {
+			b.WriteString(fmt.Sprintf("  Labels: %s\n", formatLabels(item.Labels)))
+		}
+	}
+	r.ListPagination.writeText(&b)
+	return b.String()
+}
+
+// RenderText 以文本形式返回写操作的结果消息
+func (r ResourceOperationResult) RenderText() string {
+	return r.Message
+}
+
+// formatLabels 将标签按键排序后格式化为 key=value 列表
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// RenderText 以 kubectl explain 的格式呈现CRD模式说明
+func (r CRDSchemaResponse) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("KIND:         %s\n", r.Kind))
+	b.WriteString(fmt.Sprintf("API VERSION:  %s\n", r.APIVersion))
+	b.WriteString(fmt.Sprintf("RESOURCE:     %s\n", r.Resource))
+	b.WriteString(fmt.Sprintf("SCOPE:        %s\n", r.Scope))
+	if len(r.ShortNames) > 0 {
+		b.WriteString(fmt.Sprintf("SHORTNAMES:   %s\n", strings.Join(r.ShortNames, ", ")))
+	}
+	if r.Field != "" {
+		b.WriteString(fmt.Sprintf("\nFIELD:        %s <%s>\n", r.Field, r.FieldType))
+	}
+
+	b.WriteString("\nDESCRIPTION:\n")
+	if r.Description != "" {
+		b.WriteString(indentText(r.Description, "  "))
+	} else {
+		b.WriteString("  <empty>\n")
+	}
+
+	if len(r.Fields) > 0 {
+		b.WriteString("\nFIELDS:\n")
+		writeSchemaFields(&b, r.Fields, "  ", r.Recursive)
+	}
+	return b.String()
+}
+
+// writeSchemaFields 递归写入字段树，非递归模式在字段后写入说明
+func writeSchemaFields(b *strings.Builder, fields []CRDSchemaField, indent string, recursive bool) {
+	for _, field := range fields {
+		line := fmt.Sprintf("%s%s\t<%s>", indent, field.Name, field.Type)
+		if field.Required {
+			line += " -required-"
+		}
+		b.WriteString(line + "\n")
+
+		if recursive {
+			writeSchemaFields(b, field.Fields, indent+"  ", recursive)
+			continue
+		}
+		if field.Description != "" {
+			b.WriteString(indentText(field.Description, indent+"  "))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// indentText 为多行文本的每一行添加缩进
+func indentText(text, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString(indent + line + "\n")
+	}
+	return b.String()
+}
+
+// RenderText 以便于阅读的文本呈现Apps工作负载列表
+func (r WorkloadListResponse) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Found %d %s resources in namespace %s:\n\n", r.Count, r.Kind, r.Namespace))
+
+	for _, workload := range r.Workloads {
+		b.WriteString(fmt.Sprintf("- %s\n", workload.Name))
+		writeInt64(&b, "Replicas", workload.Replicas)
+		writeInt64(&b, "Available", workload.Available)
+		writeInt64(&b, "Ready", workload.Ready)
+		writeInt64(&b, "Desired", workload.Desired)
+		if len(workload.Labels) > 0 {
+			b.WriteString(fmt.Sprintf("  Labels: %s\n", formatLabels(workload.Labels)))
+		}
+		b.WriteString("\n")
+	}
+	r.ListPagination.writeText(&b)
+	return b.String()
+}
+
+// writeText 在列表文本末尾写入截断提示与下一页令牌
+func (p ListPagination) writeText(b *strings.Builder) {
+	if p.Truncated {
+		b.WriteString(fmt.Sprintf("\nResults truncated to %d items.", p.Limit))
+		if p.RemainingItemCount != nil {
+			b.WriteString(fmt.Sprintf(" About %d more items remain.", *p.RemainingItemCount))
+		}
+		b.WriteString("\n")
+	}
+	if p.Continue != "" {
+		b.WriteString(fmt.Sprintf("\nMore results available, pass continue=%s to fetch the next page.\n", p.Continue))
+	}
+}
+
+// writeInt64 在值存在时写入一行 "  名称: 值"
+func writeInt64(b *strings.Builder, name string, value *int64) {
+	if value != nil {
+		b.WriteString(fmt.Sprintf("  %s: %d\n", name, *value))
+	}
+}
+
+// RenderText 以便于阅读的文本呈现资源结构说明
+func (r ResourceExplanation) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Resource Structure for %s (%s):\n\n", r.Kind, r.APIVersion))
+	if !r.Found {
+		b.WriteString(fmt.Sprintf("Resource %s with apiVersion %s not found in the cluster.\n", r.Kind, r.APIVersion))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("KIND:         %s\n", r.Kind))
+	b.WriteString(fmt.Sprintf("API VERSION:  %s\n", r.APIVersion))
+	b.WriteString(fmt.Sprintf("RESOURCE:     %s\n", r.Resource))
+	b.WriteString(fmt.Sprintf("SCOPE:        %s\n", r.Scope))
+	b.WriteString(fmt.Sprintf("VERBS:        %s\n", strings.Join(r.Verbs, ", ")))
+	if len(r.ShortNames) > 0 {
+		b.WriteString(fmt.Sprintf("SHORTNAMES:   %s\n", strings.Join(r.ShortNames, ", ")))
+	}
+	if r.Field != "" {
+		b.WriteString(fmt.Sprintf("\nFIELD:        %s\n", r.Field))
+	}
+
+	b.WriteString("\nDESCRIPTION:\n")
+	for _, field := range r.Fields {
+		b.WriteString(fmt.Sprintf("  %s - %s\n", field.Name, field.Description))
+		for _, child := range field.Fields {
+			b.WriteString(fmt.Sprintf("    %-11s - %s\n", child.Name, child.Description))
+		}
+	}
+	return b.String()
+}
+
+// RenderText 以便于阅读的文本呈现清单应用结果
+func (r ApplyResults) RenderText() string {
+	var b strings.Builder
+	switch {
+	case r.Aborted:
+		b.WriteString("Aborted: server dry-run failed, nothing was applied:\n\n")
+	case r.DryRun:
+		b.WriteString("Dry Run: Resources that would be applied:\n\n")
+	default:
+		b.WriteString("Applied Resources:\n\n")
+	}
+
+	for _, item := range r.Items {
+		target := fmt.Sprintf("%s/%s", item.Kind, item.Name)
+		if !item.ClusterScoped && item.Namespace != "" {
+			target += fmt.Sprintf(" in namespace %s", item.Namespace)
+		}
+		switch {
+		case item.Action == "skipped":
+			b.WriteString(fmt.Sprintf("Skipped document %d: %s\n", item.Document, target))
+		case !item.Success:
+			b.WriteString(fmt.Sprintf("Error in document %d: %s\n", item.Document, item.Error))
+		case item.Action != "":
+			b.WriteString(fmt.Sprintf("%s %s\n", target, item.Action))
+		default:
+			b.WriteString(fmt.Sprintf("Success: Applied %s\n", target))
+		}
+		if item.RolledBack {
+			b.WriteString(fmt.Sprintf("  Rolled back: %s deleted\n", target))
+		}
+		if item.RollbackError != "" {
+			b.WriteString(fmt.Sprintf("  Rollback failed: %s\n", item.RollbackError))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("\nSummary: %d created, %d configured, %d unchanged, %d error(s)", r.Created, r.Configured, r.Unchanged, r.ErrorCount))
+	if r.Skipped > 0 {
+		b.WriteString(fmt.Sprintf(", %d skipped", r.Skipped))
+	}
+	if r.RolledBack > 0 {
+		b.WriteString(fmt.Sprintf(", %d rolled back", r.RolledBack))
+	}
+	b.WriteString("\n")
+	if r.Hint != "" {
+		b.WriteString(fmt.Sprintf("\nHint: %s\n", r.Hint))
+	}
+	return b.String()
+}
+
+// RenderText 以便于阅读的文本呈现清单验证结果
+func (r ValidationResults) RenderText() string {
+	var b strings.Builder
+	b.WriteString("Validation Results:\n\n")
+
+	for _, item := range r.Items {
+		switch {
+		case !item.Valid:
+			b.WriteString(fmt.Sprintf("Error in document %d: %s\n", item.Document, item.Error))
+		case item.Namespace != "":
+			b.WriteString(fmt.Sprintf("Valid: %s/%s in namespace %s (document %d)\n", item.Kind, item.Name, item.Namespace, item.Document))
+		default:
+			b.WriteString(fmt.Sprintf("Valid: %s/%s (cluster-scoped) (document %d)\n", item.Kind, item.Name, item.Document))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("\nSummary: %d valid, %d invalid out of %d documents\n", r.ValidCount, r.ErrorCount, r.TotalCount))
+	return b.String()
+}
+
+// RenderText 以便于阅读的文本呈现清单与集群资源的差异
+func (r DiffResult) RenderText() string {
+	var b strings.Builder
+	b.WriteString("Diff Results:\n\n")
+
+	if !r.Exists {
+		b.WriteString(fmt.Sprintf("Resource %s/%s does not exist in the cluster. This would be a new resource.\n", r.Kind, r.Name))
+		b.WriteString("\nNew resource to be created:\n")
+		b.WriteString(fmt.Sprintf("Kind:       %s\n", r.Kind))
+		b.WriteString(fmt.Sprintf("API Version: %s\n", r.ApiVersion))
+		b.WriteString(fmt.Sprintf("Name:       %s\n", r.Name))
+		if r.Namespace != "" {
+			b.WriteString(fmt.Sprintf("Namespace:  %s\n", r.Namespace))
+		} else {
+			b.WriteString("Namespace:  <cluster-scoped>\n")
+		}
+		if len(r.Labels) > 0 {
+			b.WriteString(fmt.Sprintf("\nLabels: %s\n", formatLabels(r.Labels)))
+		}
+		if len(r.Annotations) > 0 {
+			b.WriteString(fmt.Sprintf("\nAnnotations: %s\n", formatLabels(r.Annotations)))
+		}
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Comparing %s/%s in %s:\n\n", r.Kind, r.Name, r.Namespace))
+	b.WriteString("Field differences:\n")
+	for _, detail := range r.DiffDetails {
+		switch detail.Action {
+		case "remove":
+			b.WriteString(fmt.Sprintf("  - %s: would be removed (currently: %s)\n", detail.Field, detail.OldValue))
+		case "add":
+			b.WriteString(fmt.Sprintf("  + %s: would be added (%s)\n", detail.Field, detail.NewValue))
+		default:
+			b.WriteString(fmt.Sprintf("  ~ %s: would change from %s to %s\n", detail.Field, detail.OldValue, detail.NewValue))
+		}
+	}
+	switch {
+	case r.Note != "":
+		b.WriteString("  " + r.Note + "\n")
+	case r.DiffCount == 0:
+		b.WriteString("  No differences found. Resources are identical.\n")
+	}
+
+	if r.DiffCount > 0 {
+		b.WriteString(fmt.Sprintf("\nSummary: Found %d differences between manifest and live resource.\n", r.DiffCount))
+	} else {
+		b.WriteString("\nSummary: No significant differences found.\n")
+	}
+	return b.String()
+}
+
+// RenderText 以表格形式呈现资源的事件
+func (r EventsResult) RenderText() string {
+	ref := r.ResourceRef
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Events for %s/%s in namespace %s:\n\n", ref.Kind, ref.Name, ref.Namespace))
+
+	if len(r.Items) == 0 {
+		b.WriteString(fmt.Sprintf("No events found for %s '%s' in namespace '%s'\n", ref.Kind, ref.Name, ref.Namespace))
+		b.WriteString("\nPossible reasons:\n")
+		b.WriteString(" - The resource is new and hasn't generated any events yet\n")
+		b.WriteString(" - The resource is operating normally without issues\n")
+		b.WriteString(" - The resource does not exist in the specified namespace\n")
+		b.WriteString(" - Events older than the retention period have been cleaned up\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Found %d events", r.Count))
+	if len(r.OwnedObjects) > 0 {
+		b.WriteString(fmt.Sprintf(" (including %d owned objects)", len(r.OwnedObjects)))
+	}
+	b.WriteString(":\n\n")
+	b.WriteString(fmt.Sprintf("%-26s %-18s %-10s %-15s %-20s %s\n", "TIME", "LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE"))
+	b.WriteString(strings.Repeat("-", 120) + "\n")
+	for _, event := range r.Items {
+		b.WriteString(fmt.Sprintf("%-26s %-18s %-10s %-15s %-20s %s\n",
+			event.Timestamp.Format(textTimeLayout), event.LastSeen, event.Type, event.Reason, event.Object, event.Message))
+	}
+	if r.Truncated {
+		b.WriteString("\nResults truncated; raise maxEvents or maxOwned to see more.\n")
+	}
+	return b.String()
+}
+
+// RenderText 以便于阅读的文本呈现集群信息
+func (r ClusterInfo) RenderText() string {
+	var b strings.Builder
+	b.WriteString("Kubernetes Cluster Information:\n\n")
+	b.WriteString(fmt.Sprintf("Version:      %s\n", r.Version))
+	b.WriteString(fmt.Sprintf("Build Date:   %s\n", r.BuildDate))
+	b.WriteString(fmt.Sprintf("Go Version:   %s\n", r.GoVersion))
+	b.WriteString(fmt.Sprintf("Platform:     %s\n", r.Platform))
+	b.WriteString(fmt.Sprintf("Git Commit:   %s\n", r.GitCommit))
+	b.WriteString(fmt.Sprintf("Git TreeState: %s\n", r.GitTreeState))
+	b.WriteString(fmt.Sprintf("Compiler:     %s\n", r.Compiler))
+	if r.Namespace != "" {
+		b.WriteString(fmt.Sprintf("\nCurrent Namespace: %s\n", r.Namespace))
+	}
+	return b.String()
+}
+
+// RenderText 以kubectl api-resources的表格形式呈现API资源列表
+func (r APIResourceList) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("API Resources (%d):\n\n", r.Count))
+	if len(r.Resources) == 0 {
+		b.WriteString("No API resources found\n")
+	} else {
+		b.WriteString(fmt.Sprintf("%-40s %-12s %-40s %-10s %s\n", "NAME", "SHORTNAMES", "APIVERSION", "NAMESPACED", "KIND"))
+		for _, resource := range r.Resources {
+			apiVersion := resource.Version
+			if resource.Group != "" {
+				apiVersion = resource.Group + "/" + resource.Version
+			}
+			b.WriteString(fmt.Sprintf("%-40s %-12s %-40s %-10t %s\n",
+				resource.Resource, strings.Join(resource.ShortNames, ","), apiVersion, resource.Namespaced, resource.Kind))
+		}
+	}
+	if len(r.FailedGroupVersions) > 0 {
+		b.WriteString(fmt.Sprintf("\nDiscovery failed for: %s\n", strings.Join(r.FailedGroupVersions, ", ")))
+	}
+	return b.String()
+}
+
+// RenderText 以文本形式呈现当前时间
+func (r CurrentTime) RenderText() string {
+	return fmt.Sprintf("Current Time: %s", r.Time)
+}
+
+// RenderText 按资源类型分组呈现搜索结果
+func (r SearchResults) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Search Results for '%s':\n\n", r.SearchQuery))
+	b.WriteString(fmt.Sprintf("Found %d matching resources across %d resource types\n\n", r.TotalCount, r.TypesCount))
+
+	currentKind := ""
+	for _, item := range r.Items {
+		if item.Kind != currentKind {
+			if currentKind != "" {
+				b.WriteString("\n")
+			}
+			b.WriteString(fmt.Sprintf("== %s ==\n", item.Kind))
+			currentKind = item.Kind
+		}
+
+		if item.Namespace != "" {
+			b.WriteString(fmt.Sprintf("- %s (namespace: %s)", item.Name, item.Namespace))
+		} else {
+			b.WriteString(fmt.Sprintf("- %s (cluster-scoped)", item.Name))
+		}
+		b.WriteString(fmt.Sprintf(", matched by: %s\n", item.MatchedBy))
+	}
+
+	if len(r.Items) == 0 {
+		b.WriteString("No resources found matching the query.\n")
+	}
+	if r.Truncated {
+		b.WriteString(fmt.Sprintf("\nStopped after %d matches; narrow the query, namespaces or kinds, or raise limit\n", r.Limit))
+	}
+	return b.String()
+}
+
+// RenderText 以'---'分隔的多文档YAML呈现导出的资源清单
+func (r ExportList) RenderText() string {
+	if len(r.Items) == 0 {
+		return fmt.Sprintf("No %s resources found to export", r.ResourceKind)
+	}
+
+	var b strings.Builder
+	if r.RedactedSecrets > 0 {
+		b.WriteString(fmt.Sprintf("# %d Secret(s) exported with redacted values, fill in data before applying\n", r.RedactedSecrets))
+	}
+	for i, item := range r.Items {
+		data, err := yaml.Marshal(item)
+		if err != nil {
+			b.WriteString(fmt.Sprintf("# failed to marshal item %d: %v\n", i, err))
+			continue
+		}
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(data)
+	}
+	return b.String()
+}
+
+// RenderText 只返回图的Mermaid或DOT文本，便于直接渲染
+func (r TopologyExport) RenderText() string {
+	return r.Graph
+}
+
+// RenderText 按相似度从高到低呈现名称相近的资源
+func (r NameSearchResult) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s matching '%s' (%d scanned):\n\n", r.Kind, r.Query, r.Scanned))
+	if len(r.Candidates) == 0 {
+		b.WriteString("No resources with a similar name found\n")
+		return b.String()
+	}
+	b.WriteString(fmt.Sprintf("%-20s %-50s %-6s %-18s %-26s %-14s %s\n", "NAMESPACE", "NAME", "SCORE", "MATCHED BY", "CREATED", "AGE", "VERDICT"))
+	for _, candidate := range r.Candidates {
+		b.WriteString(fmt.Sprintf("%-20s %-50s %-6d %-18s %-26s %-14s %s\n",
+			candidate.Namespace, candidate.Name, candidate.Score, candidate.MatchedBy,
+			candidate.CreationTimestamp.Format(textTimeLayout), candidate.Age, candidate.Verdict))
+	}
+	if r.Truncated {
+		b.WriteString("\nMore resources matched; raise limit or refine the name to see them\n")
+	}
+	return b.String()
+}
+
+// RenderText 呈现每个容器的用量统计、建议及计算依据，最后给出可直接应用的patch
+func (r ResourceRecommendation) RenderText() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Resource recommendation for %s %s/%s (%d replicas)\n", r.Kind, r.Namespace, r.Name, r.Replicas))
+	b.WriteString(fmt.Sprintf("Estimate: %s\n", r.Estimate))
+	b.WriteString(fmt.Sprintf("Pods sampled: %d, data points per pod: %d\n", r.Pods, r.DataPoints))
+
+	for _, container := range r.Containers {
+		b.WriteString(fmt.Sprintf("\nContainer %s", container.Name))
+		if container.Sidecar {
+			b.WriteString(" (sidecar)")
+		}
+		b.WriteString(fmt.Sprintf(", %d data points:\n", container.DataPoints))
+		b.WriteString(fmt.Sprintf("  %-8s %-12s %-12s %s\n", "", "P50", "P95", "MAX"))
+		b.WriteString(fmt.Sprintf("  %-8s %-12s %-12s %s\n", "cpu", container.CPU.P50, container.CPU.P95, container.CPU.Max))
+		b.WriteString(fmt.Sprintf("  %-8s %-12s %-12s %s\n", "memory", container.Memory.P50, container.Memory.P95, container.Memory.Max))
+		b.WriteString(fmt.Sprintf("  current:     %s\n", container.Current.text()))
+		b.WriteString(fmt.Sprintf("  recommended: %s\n", container.Recommended.text()))
+		for _, line := range container.Explanation {
+			b.WriteString("  - " + line + "\n")
+		}
+	}
+
+	if len(r.QuotaImpact) > 0 {
+		b.WriteString("\nResourceQuota impact:\n")
+		b.WriteString(fmt.Sprintf("  %-24s %-18s %-12s %-12s %-12s %-12s %s\n", "QUOTA", "RESOURCE", "HARD", "USED", "CHANGE", "AFTER", "FITS"))
+		for _, item := range r.QuotaImpact {
+			b.WriteString(fmt.Sprintf("  %-24s %-18s %-12s %-12s %-12s %-12s %t\n",
+				item.Quota, item.Resource, item.Hard, item.Used, item.Change, item.After, item.Fits))
+		}
+	}
+	if len(r.Notes) > 0 {
+		b.WriteString("\nNotes:\n")
+		for _, note := range r.Notes {
+			b.WriteString("  - " + note + "\n")
+		}
+	}
+	if r.Patch != "" {
+		b.WriteString(fmt.Sprintf("\nPatch (%s merge):\n%s\n", r.PatchType, r.Patch))
+		b.WriteString(fmt.Sprintf("\nApply with:\n%s\n", r.Command))
+	}
+	return b.String()
+}
+
+// text 以 "requests cpu=100m memory=128Mi; limits ..." 的形式呈现requests和limits
+func (s ResourceSettings) text() string {
+	var parts []string
+	for _, group := range []struct {
+		name   string
+		values map[string]string
+	}{{"requests", s.Requests}, {"limits", s.Limits}} {
+		if len(group.values) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(group.values))
+		for key := range group.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, key+"="+group.values[key])
+		}
+		parts = append(parts, group.name+" "+strings.Join(pairs, " "))
+	}
+	if len(parts) == 0 {
+		return "<none>"
+	}
+	return strings.Join(parts, "; ")
+}
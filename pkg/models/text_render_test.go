@@ -0,0 +1,146 @@
+package models
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestRenderTextGolden(t *testing.T) {
+	remaining := int64(40)
+	tests := []struct {
+		name     string
+		renderer interface{ RenderText() string }
+	}{
+		{
+			name: "resource_list_all_namespaces",
+			renderer: ResourceListResponse{
+				Count:          3,
+				Kind:           "Pod",
+				LabelSelector:  "app=web",
+				AllNamespaces:  true,
+				NamespaceCount: 2,
+				Resources: []ResourceInfo{
+					{Name: "web-1", Namespace: "default", Labels: map[string]string{"tier": "frontend", "app": "web"}},
+					{Name: "web-2", Namespace: "default"},
+					{Name: "web-3", Namespace: "staging"},
+				},
+				ListPagination: ListPagination{Continue: "token-1", RemainingItemCount: &remaining, Truncated: true, Limit: 3},
+			},
+		},
+		{
+			name: "resource_list_cluster_scoped",
+			renderer: ResourceListResponse{
+				Count:         1,
+				Kind:          "Node",
+				ClusterScoped: true,
+				FieldSelector: "metadata.name=node-1",
+				Resources:     []ResourceInfo{{Name: "node-1"}},
+			},
+		},
+		{
+			name: "apply_results",
+			renderer: ApplyResults{
+				Items: []ApplyResult{
+					{Kind: "ConfigMap", Name: "settings", Namespace: "default", Success: true, Action: "created", Document: 1, RolledBack: true},
+					{Kind: "Widget", Name: "gadget", Document: 2, Action: "failed", Error: "no matches for kind \"Widget\""},
+					{Kind: "Service", Name: "web", Namespace: "default", Document: 3, Action: "skipped"},
+					{Kind: "ClusterRole", Name: "viewer", ClusterScoped: true, Success: true, Action: "unchanged", Document: 4},
+				},
+				SuccessCount: 2,
+				ErrorCount:   1,
+				Created:      1,
+				Unchanged:    1,
+				Skipped:      1,
+				RolledBack:   1,
+				Hint:         "Resources created by this call were deleted after a document failed.",
+			},
+		},
+		{
+			name: "apply_results_dry_run",
+			renderer: ApplyResults{
+				Items:        []ApplyResult{{Kind: "ConfigMap", Name: "settings", Namespace: "default", Success: true, Action: "configured", Document: 1}},
+				SuccessCount: 1,
+				Configured:   1,
+				DryRun:       true,
+			},
+		},
+		{
+			name: "validation_results",
+			renderer: ValidationResults{
+				Items: []ValidationResult{
+					{Valid: true, Kind: "ConfigMap", Name: "settings", Namespace: "default", Document: 1},
+					{Valid: true, Kind: "Namespace", Name: "team-a", Document: 2},
+					{Document: 3, Error: "missing metadata.name"},
+				},
+				ValidCount: 2,
+				ErrorCount: 1,
+				TotalCount: 3,
+			},
+		},
+		{
+			name: "diff_changed",
+			renderer: DiffResult{
+				Kind:      "Deployment",
+				Name:      "web",
+				Namespace: "default",
+				Exists:    true,
+				DiffCount: 3,
+				DiffDetails: []DiffDetail{
+					{Field: "Metadata.labels", NewValue: "map[tier:frontend]", Action: "add"},
+					{Field: "Spec.paused", OldValue: "true", Action: "remove"},
+					{Field: "Spec.replicas", OldValue: "2", NewValue: "3", Action: "change"},
+				},
+			},
+		},
+		{
+			name: "diff_new_resource",
+			renderer: DiffResult{
+				Kind:         "Namespace",
+				Name:         "team-a",
+				ApiVersion:   "v1",
+				IsNewResurce: true,
+				Labels:       map[string]string{"team": "a", "env": "dev"},
+			},
+		},
+		{
+			name: "crd_schema",
+			renderer: CRDSchemaResponse{
+				Kind:        "Widget",
+				APIVersion:  "example.com/v1",
+				Resource:    "widgets",
+				Scope:       "Namespaced",
+				ShortNames:  []string{"wg"},
+				Field:       "spec",
+				FieldType:   "object",
+				Description: "WidgetSpec defines the desired state.\nAll fields are optional.",
+				Fields: []CRDSchemaField{
+					{Name: "size", Type: "integer", Required: true, Description: "Number of parts."},
+					{Name: "color", Type: "string"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.renderer.RenderText()
+			path := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create the golden file)", err)
+			}
+			if got != string(want) {
+				t.Errorf("RenderText() mismatch with %s\ngot:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
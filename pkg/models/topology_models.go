@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// 拓扑节点的就绪状态
+const (
+	TopologyStatusReady    = "ready"
+	TopologyStatusDegraded = "degraded"
+	TopologyStatusMissing  = "missing"
+	TopologyStatusUnknown  = "unknown"
+)
+
+// TopologyNode 拓扑图中的一个节点，工作负载的Pod合并为一个计数节点
+type TopologyNode struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Status 就绪状态：ready、degraded、missing（被引用但不存在）或unknown
+	Status string `json:"status"`
+	// Detail 节点标签中的状态说明，例如"2/3 ready"
+	Detail string `json:"detail,omitempty"`
+}
+
+// TopologyEdge 拓扑图中的一条边
+type TopologyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Label 关系类型，例如owns、selects、backend、mounts
+	Label string `json:"label"`
+}
+
+// TopologyExport 命名空间资源拓扑的导出结果
+type TopologyExport struct {
+	Namespace     string   `json:"namespace"`
+	LabelSelector string   `json:"labelSelector,omitempty"`
+	Kinds         []string `json:"kinds"`
+	// GraphFormat 图的文本格式：mermaid或dot
+	GraphFormat string         `json:"graphFormat"`
+	NodeCount   int            `json:"nodeCount"`
+	EdgeCount   int            `json:"edgeCount"`
+	Truncated   bool           `json:"truncated,omitempty"`
+	Omitted     int            `json:"omittedNodes,omitempty"`
+	Nodes       []TopologyNode `json:"nodes"`
+	Edges       []TopologyEdge `json:"edges"`
+	// Graph Mermaid或Graphviz DOT文本
+	Graph       string    `json:"graph"`
+	Notes       []string  `json:"notes,omitempty"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// ResultTruncation 响应超过输出上限时的截断说明，提示调用方缩小查询范围
+type ResultTruncation struct {
+	Truncated bool `json:"truncated"`
+	// OriginalBytes 截断前的响应大小
+	OriginalBytes int `json:"originalBytes"`
+	// LimitBytes 服务器的输出上限
+	LimitBytes int `json:"limitBytes"`
+	// Fields 被截断的字段
+	Fields []TruncatedField `json:"fields,omitempty"`
+	Hint   string           `json:"hint"`
+}
+
+// TruncatedField 单个被截断的字段
+type TruncatedField struct {
+	// Path 字段路径，例如：items、logs、data.config.yaml
+	Path string `json:"path"`
+	// OmittedItems 列表中省略的元素数量
+	OmittedItems int `json:"omittedItems,omitempty"`
+	// OmittedLines 文本中省略的行数
+	OmittedLines int `json:"omittedLines,omitempty"`
+	// OmittedBytes 单行文本过长时按字节截断省略的字节数
+	OmittedBytes int `json:"omittedBytes,omitempty"`
+}
@@ -1,16 +1,20 @@
 package models
 
+import "time"
+
 // SearchResult 搜索结果数据
 type SearchResult struct {
-	Kind         string `json:"kind"`
-	APIVersion   string `json:"apiVersion"`
-	Name         string `json:"name"`
-	Namespace    string `json:"namespace"`
-	Labels       string `json:"labels,omitempty"`
-	Annotations  string `json:"annotations,omitempty"`
-	MatchedBy    string `json:"matchedBy,omitempty"`
-	MatchedValue string `json:"matchedValue,omitempty"`
-	CreationTime string `json:"creationTime,omitempty"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	// Labels 按标签匹配时资源的全部标签
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations 按注解匹配时只包含匹配的注解，过长的值被截断
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	MatchedBy    string            `json:"matchedBy,omitempty"`
+	MatchedValue string            `json:"matchedValue,omitempty"`
+	CreationTime string            `json:"creationTime,omitempty"`
 }
 
 // SearchResults 搜索结果列表
@@ -19,14 +23,25 @@ type SearchResults struct {
 	TotalCount  int            `json:"totalCount"`
 	SearchQuery string         `json:"searchQuery"`
 	TypesCount  int            `json:"typesCount"`
+	// Limit 返回的匹配数量上限
+	Limit int `json:"limit"`
+	// Truncated 匹配数量达到上限后停止了搜索，未搜索的资源类型可能还有匹配
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // EventInfo 事件信息
 type EventInfo struct {
-	LastSeen    string `json:"lastSeen"`
-	Type        string `json:"type"`
-	Reason      string `json:"reason"`
-	Object      string `json:"object"`
+	// LastSeen 最近一次发生的相对时间，例如"3 hours ago"，仅便于阅读
+	LastSeen string `json:"lastSeen"`
+	// Timestamp 最近一次发生的时间，FirstSeen 第一次发生的时间，均为请求时区的RFC3339时间
+	Timestamp time.Time `json:"timestamp"`
+	FirstSeen time.Time `json:"firstSeen"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Object    string    `json:"object"`
+	// Owned 事件是否来自查询对象的后代（如Deployment的ReplicaSet和Pod）
+	Owned       bool   `json:"owned,omitempty"`
+	Count       int32  `json:"count,omitempty"`
 	Message     string `json:"message"`
 	FullMessage string `json:"fullMessage,omitempty"`
 }
@@ -40,6 +55,11 @@ type EventsResult struct {
 		Namespace string `json:"namespace"`
 	} `json:"resourceRef"`
 	Count int `json:"count"`
+	// OwnedObjects includeOwned时纳入事件的后代对象，格式为"kind/name"
+	OwnedObjects []string `json:"ownedObjects,omitempty"`
+	// Truncated 后代对象或事件数量达到上限，结果不完整
+	Truncated bool     `json:"truncated,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
 }
 
 // DiffResult 差异比较结果
@@ -52,6 +72,10 @@ type DiffResult struct {
 	DiffCount    int          `json:"diffCount"`
 	DiffDetails  []DiffDetail `json:"diffDetails,omitempty"`
 	IsNewResurce bool         `json:"isNewResource"`
+	// 新资源的标签和注解，仅在资源不存在时返回
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Note        string            `json:"note,omitempty"`
 }
 
 // DiffDetail 差异详情
@@ -92,16 +116,36 @@ type ClusterInfo struct {
 	Namespace    string `json:"namespace,omitempty"`
 }
 
+// CacheRefreshResult 刷新Discovery缓存的结果
+type CacheRefreshResult struct {
+	RefreshedAt     string `json:"refreshedAt"`
+	APIGroups       int    `json:"apiGroups"`
+	APIResources    int    `json:"apiResources"`
+	Namespaces      int    `json:"namespaces"`
+	DiscoveryHits   int64  `json:"discoveryHits"`
+	DiscoveryMisses int64  `json:"discoveryMisses"`
+	NamespaceHits   int64  `json:"namespaceHits"`
+	NamespaceMisses int64  `json:"namespaceMisses"`
+}
+
 // ApplyResult 应用清单的结果
 type ApplyResult struct {
-	Kind          string `json:"kind"`
-	Name          string `json:"name"`
-	Namespace     string `json:"namespace,omitempty"`
-	ApiVersion    string `json:"apiVersion"`
-	Success       bool   `json:"success"`
-	Error         string `json:"error,omitempty"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	ApiVersion string `json:"apiVersion"`
+	Success    bool   `json:"success"`
+	// Action 执行的操作：created、configured、unchanged、applied（无法读取应用前的对象）、failed、skipped
+	Action string `json:"action,omitempty"`
+	// Error 服务器返回的原始错误消息，包括准入Webhook拒绝的原因
+	Error string `json:"error,omitempty"`
+	// Reason 服务器返回的错误原因，例如Invalid、Forbidden
+	Reason        string `json:"reason,omitempty"`
 	Document      int    `json:"document"`
 	ClusterScoped bool   `json:"clusterScoped"`
+	// RolledBack 本次新建的资源因后续文档失败被删除
+	RolledBack    bool   `json:"rolledBack,omitempty"`
+	RollbackError string `json:"rollbackError,omitempty"`
 }
 
 // ApplyResults 应用清单结果列表
@@ -109,26 +153,287 @@ type ApplyResults struct {
 	Items        []ApplyResult `json:"items"`
 	SuccessCount int           `json:"successCount"`
 	ErrorCount   int           `json:"errorCount"`
+	Created      int           `json:"created"`
+	Configured   int           `json:"configured"`
+	Unchanged    int           `json:"unchanged"`
+	Skipped      int           `json:"skipped,omitempty"`
+	RolledBack   int           `json:"rolledBack,omitempty"`
 	DryRun       bool          `json:"dryRun"`
+	// Atomic 应用前先对所有文档进行服务端试运行
+	Atomic bool `json:"atomic,omitempty"`
+	// Aborted 试运行有文档失败，没有应用任何文档，Items为试运行结果
+	Aborted         bool   `json:"aborted,omitempty"`
+	RollbackOnError bool   `json:"rollbackOnError,omitempty"`
+	Hint            string `json:"hint,omitempty"`
+	// Source 清单从URL获取时的来源，用于将应用的状态追溯到源版本
+	Source *ManifestSource `json:"source,omitempty"`
 }
 
-// ResourceDef API资源定义
-type ResourceDef struct {
-	Kind         string   `json:"kind"`
-	GroupVersion string   `json:"groupVersion"`
-	Name         string   `json:"name"`
-	Namespaced   bool     `json:"namespaced"`
-	Verbs        []string `json:"verbs"`
-	ShortNames   []string `json:"shortNames,omitempty"`
+// ManifestSource APPLY_FROM_URL获取的清单来源
+type ManifestSource struct {
+	// URL 请求的URL，指定ref和path时为据此生成的原始文件地址
+	URL string `json:"url"`
+	// FinalURL 跟随重定向后的地址，与URL相同时为空
+	FinalURL string `json:"finalURL,omitempty"`
+	Ref      string `json:"ref,omitempty"`
+	Path     string `json:"path,omitempty"`
+	// SHA256 获取到的清单内容的sha256
+	SHA256 string `json:"sha256"`
+	Bytes  int    `json:"bytes"`
+	// ContentType 服务器返回的Content-Type
+	ContentType string `json:"contentType,omitempty"`
+	// ChecksumVerified 是否与调用方提供的sha256校验一致
+	ChecksumVerified bool `json:"checksumVerified"`
 }
 
-// APIResourceGroup API资源组
-type APIResourceGroup struct {
-	GroupVersion string        `json:"groupVersion"`
-	Resources    []ResourceDef `json:"resources"`
+// GeneratedManifest GENERATE_MANIFEST生成的资源清单
+type GeneratedManifest struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// YAML 可直接传给APPLY_MANIFEST的清单
+	YAML string `json:"yaml"`
+	// Notes 生成时采用的默认值及需要注意的事项
+	Notes []string `json:"notes,omitempty"`
+}
+
+// KustomizationRender kustomization构建结果
+type KustomizationRender struct {
+	// Source 构建来源：inline表示内联文件，否则为服务器本地目录
+	Source string `json:"source"`
+	// Resources 构建出的资源，格式为"Kind namespace/name"，集群级别资源省略命名空间
+	Resources []string `json:"resources"`
+	// YAML 构建出的多文档YAML
+	YAML string `json:"yaml"`
+}
+
+// APIResourceInfo API资源定义，同一组中多个版本提供的资源只出现一次
+type APIResourceInfo struct {
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Resource   string   `json:"resource"`
+	Kind       string   `json:"kind"`
+	Namespaced bool     `json:"namespaced"`
+	Verbs      []string `json:"verbs"`
+	ShortNames []string `json:"shortNames,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	// PreferredVersion Version是否为该组的首选版本
+	PreferredVersion bool `json:"preferredVersion"`
+	// OtherVersions 同时提供该资源的其他版本
+	OtherVersions []string `json:"otherVersions,omitempty"`
 }
 
 // APIResourceList API资源列表
 type APIResourceList struct {
-	Groups []APIResourceGroup `json:"groups"`
+	Count     int               `json:"count"`
+	Resources []APIResourceInfo `json:"resources"`
+	// FailedGroupVersions 发现失败、结果中缺少其资源的组版本
+	FailedGroupVersions []string `json:"failedGroupVersions,omitempty"`
+}
+
+// FieldChange 字段变更
+type FieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+	Action   string      `json:"action"` // "add", "remove", "change"
+}
+
+// WatchEvent 监听到的资源事件
+type WatchEvent struct {
+	Type            string        `json:"type"`
+	Time            time.Time     `json:"time"`
+	Name            string        `json:"name"`
+	Namespace       string        `json:"namespace,omitempty"`
+	ResourceVersion string        `json:"resourceVersion,omitempty"`
+	Generation      int64         `json:"generation,omitempty"`
+	Changes         []FieldChange `json:"changes,omitempty"`
+	ChangesOmitted  int           `json:"changesOmitted,omitempty"`
+}
+
+// WatchResult 资源监听结果
+type WatchResult struct {
+	Kind          string       `json:"kind"`
+	APIVersion    string       `json:"apiVersion"`
+	Namespace     string       `json:"namespace,omitempty"`
+	Name          string       `json:"name,omitempty"`
+	LabelSelector string       `json:"labelSelector,omitempty"`
+	StartedAt     time.Time    `json:"startedAt"`
+	EndedAt       time.Time    `json:"endedAt"`
+	Duration      string       `json:"duration"`
+	EventCount    int          `json:"eventCount"`
+	Events        []WatchEvent `json:"events"`
+	Restarts      int          `json:"restarts,omitempty"`
+	Truncated     bool         `json:"truncated,omitempty"`
+	Errors        []string     `json:"errors,omitempty"`
+}
+
+// WaitForResult WAIT_FOR的等待结果，超时未满足时Met为false，Message为最后观察到的阻塞条件
+type WaitForResult struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	// Condition 等待的条件，例如Ready、Available=True、{.status.phase}=Running
+	Condition string `json:"condition"`
+	Met       bool   `json:"met"`
+	TimedOut  bool   `json:"timedOut"`
+	// Failed 条件已不可能满足（例如Job失败、Pod已终止）
+	Failed  bool   `json:"failed,omitempty"`
+	Message string `json:"message,omitempty"`
+	Waited  string `json:"waited"`
+	// Object 最后观察到的对象，对象不存在时为空
+	Object map[string]interface{} `json:"object,omitempty"`
+	Hint   string                 `json:"hint,omitempty"`
+}
+
+// CompareObjectRef 比较中的一侧对象引用
+type CompareObjectRef struct {
+	Kind            string `json:"kind"`
+	APIVersion      string `json:"apiVersion"`
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	Exists          bool   `json:"exists"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// CompareResult 两个集群对象的比较结果
+type CompareResult struct {
+	Source          CompareObjectRef `json:"source"`
+	Target          CompareObjectRef `json:"target"`
+	Identical       bool             `json:"identical"`
+	DiffCount       int              `json:"diffCount"`
+	Changes         []FieldChange    `json:"changes,omitempty"`
+	UnifiedDiff     string           `json:"unifiedDiff,omitempty"`
+	IgnoredFields   []string         `json:"ignoredFields,omitempty"`
+	IgnoreImageTags bool             `json:"ignoreImageTags,omitempty"`
+	Note            string           `json:"note,omitempty"`
+}
+
+// ExplainField 资源字段说明
+type ExplainField struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Fields      []ExplainField `json:"fields,omitempty"`
+}
+
+// ResourceExplanation 资源结构说明
+type ResourceExplanation struct {
+	Kind       string         `json:"kind"`
+	APIVersion string         `json:"apiVersion"`
+	Found      bool           `json:"found"`
+	Resource   string         `json:"resource,omitempty"`
+	Scope      string         `json:"scope,omitempty"`
+	Verbs      []string       `json:"verbs,omitempty"`
+	ShortNames []string       `json:"shortNames,omitempty"`
+	Field      string         `json:"field,omitempty"`
+	Fields     []ExplainField `json:"fields,omitempty"`
+}
+
+// CurrentTime 当前时间
+type CurrentTime struct {
+	Time     string `json:"time"`
+	Timezone string `json:"timezone"`
+	Unix     int64  `json:"unix"`
+}
+
+// ExportList 导出的资源清单，结构与Kubernetes的v1 List一致，JSON和YAML格式均可直接应用
+type ExportList struct {
+	APIVersion string                   `json:"apiVersion"`
+	Kind       string                   `json:"kind"`
+	Items      []map[string]interface{} `json:"items"`
+	// 导出的资源类型和被脱敏的Secret数量，仅用于文本格式
+	ResourceKind    string `json:"-"`
+	RedactedSecrets int    `json:"-"`
+}
+
+// QuotaFitObject 清单中一个对象对配额的估算占用
+type QuotaFitObject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Pods 对象会创建的Pod数量
+	Pods int64 `json:"pods,omitempty"`
+	// Usage 对象占用的配额资源，例如requests.cpu、count/deployments.apps
+	Usage map[string]string `json:"usage,omitempty"`
+	// Defaulted 应用了LimitRange默认值的容器，格式为"容器名: 资源"
+	Defaulted []string `json:"defaulted,omitempty"`
+	Exists    bool     `json:"exists,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// QuotaFitCheck 一个配额资源的计算过程
+type QuotaFitCheck struct {
+	Quota     string `json:"quota"`
+	Namespace string `json:"namespace"`
+	Resource  string `json:"resource"`
+	Hard      string `json:"hard"`
+	Used      string `json:"used"`
+	Requested string `json:"requested"`
+	Remaining string `json:"remaining"`
+	After     string `json:"after"`
+	Fits      bool   `json:"fits"`
+	// ExceededBy 超出配额的数量，未超出时为空
+	ExceededBy string `json:"exceededBy,omitempty"`
+}
+
+// QuotaFitResult 清单能否放入命名空间剩余配额的检查结果
+type QuotaFitResult struct {
+	Fits     bool             `json:"fits"`
+	Objects  []QuotaFitObject `json:"objects"`
+	Checks   []QuotaFitCheck  `json:"checks"`
+	Exceeded []QuotaFitCheck  `json:"exceeded,omitempty"`
+	Notes    []string         `json:"notes,omitempty"`
+}
+
+// EventMatcher NOTIFY_ON_EVENT的匹配条件
+type EventMatcher struct {
+	InvolvedKind  string `json:"involvedKind,omitempty"`
+	InvolvedName  string `json:"involvedName,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Type          string `json:"type,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// NotifiedEvent 匹配条件的事件
+type NotifiedEvent struct {
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Object    string    `json:"object"`
+	Namespace string    `json:"namespace,omitempty"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	FirstSeen time.Time `json:"firstSeen,omitempty"`
+	LastSeen  time.Time `json:"lastSeen,omitempty"`
+}
+
+// NotifiedPod 匹配条件的Pod状态
+type NotifiedPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Phase     string `json:"phase"`
+	// Reason 匹配的原因，例如：CrashLoopBackOff、OOMKilled、Evicted
+	Reason string `json:"reason"`
+	// Container 原因所在的容器，Pod级别的原因为空
+	Container    string `json:"container,omitempty"`
+	Message      string `json:"message,omitempty"`
+	RestartCount int32  `json:"restartCount,omitempty"`
+	Node         string `json:"node,omitempty"`
+}
+
+// EventNotification NOTIFY_ON_EVENT的等待结果，超时未匹配时Matched为false
+type EventNotification struct {
+	// Source 监听的对象：events或pods
+	Source    string         `json:"source"`
+	Namespace string         `json:"namespace,omitempty"`
+	Matcher   EventMatcher   `json:"matcher"`
+	Matched   bool           `json:"matched"`
+	TimedOut  bool           `json:"timedOut"`
+	StartedAt time.Time      `json:"startedAt"`
+	EndedAt   time.Time      `json:"endedAt"`
+	Waited    string         `json:"waited"`
+	Event     *NotifiedEvent `json:"event,omitempty"`
+	Pod       *NotifiedPod   `json:"pod,omitempty"`
+	Hint      string         `json:"hint,omitempty"`
 }
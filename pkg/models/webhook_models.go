@@ -0,0 +1,110 @@
+package models
+
+import "time"
+
+// 准入Webhook的类型
+const (
+	WebhookTypeValidating = "validating"
+	WebhookTypeMutating   = "mutating"
+)
+
+// Webhook后端的状态
+const (
+	WebhookBackendHealthy   = "healthy"
+	WebhookBackendUnhealthy = "unhealthy"
+	// WebhookBackendUnknown 使用外部URL或ExternalName Service，无法检查后端
+	WebhookBackendUnknown = "unknown"
+)
+
+// 准入失败的类别
+const (
+	// AdmissionFailureDenied Webhook正常工作并按策略拒绝了对象
+	AdmissionFailureDenied = "denied"
+	// AdmissionFailureUnreachable API Server无法连接Webhook后端
+	AdmissionFailureUnreachable = "unreachable"
+	AdmissionFailureTimeout     = "timeout"
+	// AdmissionFailureTLS 证书校验失败，通常是caBundle与后端证书不匹配
+	AdmissionFailureTLS    = "tls"
+	AdmissionFailureDryRun = "dry-run-unsupported"
+	// AdmissionFailurePolicy 被ValidatingAdmissionPolicy拒绝，不涉及Webhook
+	AdmissionFailurePolicy = "admission-policy"
+	AdmissionFailureOther  = "other"
+)
+
+// WebhookInventory LIST_WEBHOOKS的结果
+type WebhookInventory struct {
+	Count    int           `json:"count"`
+	Webhooks []WebhookInfo `json:"webhooks"`
+	// Findings failurePolicy为Fail但后端不可用等风险，与CHECK_AVAILABILITY的发现格式相同
+	Findings    []AvailabilityFinding `json:"findings"`
+	Errors      []string              `json:"errors,omitempty"`
+	RetrievedAt time.Time             `json:"retrievedAt"`
+}
+
+// WebhookInfo 单个准入Webhook的配置和后端状态
+type WebhookInfo struct {
+	// Configuration 所属的ValidatingWebhookConfiguration或MutatingWebhookConfiguration名称
+	Configuration string        `json:"configuration"`
+	Type          string        `json:"type"`
+	Name          string        `json:"name"`
+	Rules         []WebhookRule `json:"rules"`
+	// FailurePolicy 未设置时为Fail
+	FailurePolicy      string `json:"failurePolicy"`
+	TimeoutSeconds     int32  `json:"timeoutSeconds"`
+	SideEffects        string `json:"sideEffects,omitempty"`
+	MatchPolicy        string `json:"matchPolicy,omitempty"`
+	ReinvocationPolicy string `json:"reinvocationPolicy,omitempty"`
+	// NamespaceSelector、ObjectSelector 为空表示匹配所有命名空间或对象
+	NamespaceSelector string   `json:"namespaceSelector,omitempty"`
+	ObjectSelector    string   `json:"objectSelector,omitempty"`
+	MatchConditions   []string `json:"matchConditions,omitempty"`
+	// ClusterWide 是否不受命名空间和对象选择器限制
+	ClusterWide bool           `json:"clusterWide"`
+	Backend     WebhookBackend `json:"backend"`
+}
+
+// WebhookRule Webhook拦截的操作和资源
+type WebhookRule struct {
+	Operations  []string `json:"operations"`
+	APIGroups   []string `json:"apiGroups"`
+	APIVersions []string `json:"apiVersions"`
+	Resources   []string `json:"resources"`
+	// Scope Cluster、Namespaced或*
+	Scope string `json:"scope"`
+}
+
+// WebhookBackend Webhook后端Service或URL的可用性
+type WebhookBackend struct {
+	// Service 格式为namespace/name:port
+	Service string `json:"service,omitempty"`
+	Path    string `json:"path,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Status  string `json:"status"`
+	// ServiceExists、ReadyEndpoints、NotReadyEndpoints 只用于Service后端
+	ServiceExists     bool `json:"serviceExists,omitempty"`
+	ReadyEndpoints    int  `json:"readyEndpoints"`
+	NotReadyEndpoints int  `json:"notReadyEndpoints"`
+	CABundle          bool `json:"caBundle"`
+	// Problems 后端不可用或无法检查的原因
+	Problems []string `json:"problems,omitempty"`
+}
+
+// AdmissionDiagnosis DIAGNOSE_ADMISSION_FAILURE的结果
+type AdmissionDiagnosis struct {
+	Category string `json:"category"`
+	// WebhookNames、Service 从错误消息中识别出的Webhook名称和后端Service
+	WebhookNames []string `json:"webhookNames,omitempty"`
+	Service      string   `json:"service,omitempty"`
+	// Reason Webhook拒绝对象时给出的原因
+	Reason      string `json:"reason,omitempty"`
+	Explanation string `json:"explanation"`
+	// Matched 与错误对应的Webhook及其当前后端状态
+	Matched []WebhookInfo `json:"matched"`
+	// BackendUnhealthy 对应Webhook的后端当前是否不可用
+	BackendUnhealthy bool `json:"backendUnhealthy"`
+	// Suspects 错误中无法识别Webhook时，后端不可用且failurePolicy为Fail的Webhook
+	Suspects    []WebhookInfo `json:"suspects,omitempty"`
+	Suggestions []string      `json:"suggestions"`
+	Notes       []string      `json:"notes,omitempty"`
+	RetrievedAt time.Time     `json:"retrievedAt"`
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/hsn0918/kubernetes-mcp/pkg/config"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -10,8 +12,8 @@ type MCPServer interface {
 	// Start 启动服务器
 	Start() error
 
-	// Stop 停止服务器
-	Stop() error
+	// Stop 优雅停止服务器：拒绝新的工具调用，等待进行中的调用结束后关闭监听，ctx结束时不再等待
+	Stop(ctx context.Context) error
 
 	// GetServer 获取底层MCP服务器
 	GetServer() *server.MCPServer
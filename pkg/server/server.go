@@ -2,8 +2,10 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -18,13 +20,18 @@ import (
 // stdioServer 标准输入/输出模式服务器
 type stdioServer struct {
 	mcpServer *server.MCPServer
+	drainer   *middlewares.Drainer
 	log       logger.Logger
+	// cancel 结束stdio监听，由Stop在等待进行中的调用结束后调用
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // sseServer Server-Sent Events模式服务器
 type sseServer struct {
 	mcpServer    *server.MCPServer
 	sseServer    *server.SSEServer
+	drainer      *middlewares.Drainer
 	port         int
 	log          logger.Logger
 	allowOrigins string
@@ -34,6 +41,7 @@ type sseServer struct {
 type streamableHTTPServer struct {
 	mcpServer            *server.MCPServer
 	streamableHTTPServer *server.StreamableHTTPServer
+	drainer              *middlewares.Drainer
 	port                 int
 	log                  logger.Logger
 	allowOrigins         string
@@ -55,20 +63,22 @@ func (s *stdioServer) GetServer() *server.MCPServer {
 	return s.mcpServer
 }
 
-// Start 实现接口方法
+// Start 实现接口方法，标准输入关闭或Stop被调用后返回
+// 不使用server.ServeStdio，信号由调用方处理，以便在退出前等待进行中的调用
 func (s *stdioServer) Start() error {
 	s.log.Info("Starting stdio server")
-	if err := server.ServeStdio(s.mcpServer); err != nil {
+	err := server.NewStdioServer(s.mcpServer).Listen(s.ctx, os.Stdin, os.Stdout)
+	if err != nil && !errors.Is(err, context.Canceled) {
 		return fmt.Errorf("server error: %v", err)
 	}
 	return nil
 }
 
 // Stop 实现接口方法
-func (s *stdioServer) Stop() error {
+func (s *stdioServer) Stop(ctx context.Context) error {
 	s.log.Info("Stopping stdio server")
-	// stdio服务器不需要额外的停止逻辑
-	return nil
+	defer s.cancel()
+	return s.drainer.Drain(ctx)
 }
 
 // GetServer 实现接口方法
@@ -86,10 +96,10 @@ func (s *sseServer) Start() error {
 }
 
 // Stop 实现接口方法
-func (s *sseServer) Stop() error {
+func (s *sseServer) Stop(ctx context.Context) error {
 	s.log.Info("Stopping SSE server")
-	// 可以添加额外的SSE服务器清理逻辑
-	return nil
+	drainErr := s.drainer.Drain(ctx)
+	return errors.Join(drainErr, s.sseServer.Shutdown(ctx))
 }
 
 // GetServer 实现接口方法
@@ -107,22 +117,35 @@ func (s *streamableHTTPServer) Start() error {
 }
 
 // Stop 实现接口方法
-func (s *streamableHTTPServer) Stop() error {
+func (s *streamableHTTPServer) Stop(ctx context.Context) error {
 	s.log.Info("Stopping StreamableHTTP server")
-	// 可以添加额外的StreamableHTTP服务器清理逻辑
-	return nil
+	drainErr := s.drainer.Drain(ctx)
+	return errors.Join(drainErr, s.streamableHTTPServer.Shutdown(ctx))
 }
 
 // CreateServer 实现接口方法
 func (f *serverFactoryImpl) CreateServer(cfg *config.Config) (MCPServer, error) {
 	log := logger.GetLogger()
 
+	// 优雅关闭时拒绝新调用并等待进行中的调用，放在并发限制之前，使排队中的调用也被等待
+	drainer := middlewares.NewDrainer()
+	// 会话内的工具调用记录由RequestID中间件写入
+	middlewares.ConfigureToolHistory(cfg.ToolHistorySize)
+
 	// 准备服务器选项
 	serverOptions := []server.ServerOption{
 		server.WithResourceCapabilities(false, false),
 		server.WithPromptCapabilities(false),
 		server.WithToolCapabilities(true),
 		server.WithLogging(),
+		server.WithToolHandlerMiddleware(middlewares.RequestID()),
+		server.WithToolHandlerMiddleware(drainer.Middleware()),
+		// 只读模式和命名空间白名单在占用并发名额前检查，每次调用读取最新的配置快照
+		server.WithToolHandlerMiddleware(middlewares.AccessPolicy()),
+		server.WithToolHandlerMiddleware(middlewares.ConcurrencyLimit(cfg.MaxConcurrentTools, cfg.MaxConcurrentExpensiveTools, cfg.ConcurrencyQueueTimeout)),
+		server.WithToolHandlerMiddleware(middlewares.ToolTimeout(cfg.ToolTimeoutSeconds, cfg.MaxToolTimeoutSeconds)),
+		// 客户端提供progressToken时，等待和监听类工具通过上下文中的汇报器发送进度通知
+		server.WithToolHandlerMiddleware(middlewares.Progress()),
 	}
 	// 添加钩子选项
 	hooks := &server.Hooks{}
@@ -135,6 +158,8 @@ func (f *serverFactoryImpl) CreateServer(cfg *config.Config) (MCPServer, error)
 	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
 		log.Error("Request failed", "id", id, "method", method, "error", err)
 	})
+	// 注册处理程序的钩子
+	f.handlerProvider.RegisterAllHooks(hooks)
 	serverOptions = append(serverOptions, server.WithHooks(hooks))
 
 	// 创建基本MCP服务器
@@ -183,6 +208,7 @@ func (f *serverFactoryImpl) CreateServer(cfg *config.Config) (MCPServer, error)
 		return &sseServer{
 			mcpServer:    mcpServer,
 			sseServer:    mcpSseServer,
+			drainer:      drainer,
 			port:         port,
 			log:          log,
 			allowOrigins: cfg.AllowOrigins,
@@ -204,6 +230,7 @@ func (f *serverFactoryImpl) CreateServer(cfg *config.Config) (MCPServer, error)
 		return &streamableHTTPServer{
 			mcpServer:            mcpServer,
 			streamableHTTPServer: mcpStreamableServer,
+			drainer:              drainer,
 			port:                 port,
 			log:                  log,
 			allowOrigins:         cfg.AllowOrigins,
@@ -211,9 +238,13 @@ func (f *serverFactoryImpl) CreateServer(cfg *config.Config) (MCPServer, error)
 
 	default:
 		// 默认使用stdio服务器
+		ctx, cancel := context.WithCancel(context.Background())
 		return &stdioServer{
 			mcpServer: mcpServer,
+			drainer:   drainer,
 			log:       log,
+			ctx:       ctx,
+			cancel:    cancel,
 		}, nil
 	}
 }
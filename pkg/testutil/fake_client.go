@@ -0,0 +1,528 @@
+// Package testutil 提供在没有集群的情况下运行处理程序的fake客户端和请求构造工具。
+package testutil
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/cache"
+	k8sclient "github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
+)
+
+// DefaultNamespace fake客户端的当前命名空间
+const DefaultNamespace = "default"
+
+// 内置资源在fake Discovery中声明的动词
+var defaultVerbs = metav1.Verbs{"create", "delete", "deletecollection", "get", "list", "patch", "update", "watch"}
+
+// crdGVR CustomResourceDefinition 资源的 GVR。
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// FakeClient 基于fake客户端实现kubernetes.Client。
+// 类型化对象同时写入controller-runtime、clientset和动态客户端；非结构化对象只写入动态客户端；metrics.k8s.io对象只写入Metrics客户端。
+// 各客户端的数据彼此独立，通过一个客户端写入的对象不能通过其他客户端读到。
+type FakeClient struct {
+	client.Client
+	// Namespace GetCurrentNamespace返回的命名空间，默认为DefaultNamespace
+	Namespace string
+
+	clientset     *kubefake.Clientset
+	dynamicClient *dynamicfake.FakeDynamicClient
+	discovery     *fakeDiscovery
+	metricsClient *metricsfake.Clientset
+	apply         *fakeApply
+	cache         *cache.Cache
+	restConfig    *rest.Config
+}
+
+// 编译时断言，确保 FakeClient 实现了 Client 接口。
+var _ k8sclient.Client = &FakeClient{}
+
+// NewFakeClient 创建包含指定对象的fake客户端。
+// Discovery中预置client-go scheme注册的所有内置资源，自定义资源需通过AddAPIResources声明。
+func NewFakeClient(objects ...runtime.Object) *FakeClient {
+	scheme := clientgoscheme.Scheme
+
+	var typed, dynamicObjects, metrics []runtime.Object
+	for _, obj := range objects {
+		switch {
+		case isMetricsObject(obj):
+			metrics = append(metrics, obj)
+		case isUnstructured(obj):
+			dynamicObjects = append(dynamicObjects, obj)
+		default:
+			typed = append(typed, obj)
+			dynamicObjects = append(dynamicObjects, toUnstructured(scheme, obj))
+		}
+	}
+
+	clientset := kubefake.NewClientset(typed...)
+	mapper := testrestmapper.TestOnlyStaticRESTMapper(scheme)
+	builder := crfake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithRuntimeObjects(typed...)
+	crClient := withFieldIndexes(builder, scheme).Build()
+	fc := &FakeClient{
+		Client:        crClient,
+		Namespace:     DefaultNamespace,
+		clientset:     clientset,
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(scheme, dynamicObjects...),
+		metricsClient: newFakeMetricsClient(metrics...),
+		cache:         cache.New(0, nil),
+		restConfig:    &rest.Config{Host: "https://fake.cluster.local"},
+	}
+	fc.apply = &fakeApply{tracker: fc.dynamicClient.Tracker()}
+	fc.dynamicClient.PrependReactor("patch", "*", fc.apply.reaction)
+	fc.discovery = &fakeDiscovery{FakeDiscovery: clientset.Discovery().(*fakediscovery.FakeDiscovery)}
+	fc.discovery.Resources = builtinAPIResources(scheme, mapper)
+	return fc
+}
+
+// AddAPIResources 在fake Discovery中声明额外的资源，例如CRD提供的资源
+func (f *FakeClient) AddAPIResources(lists ...*metav1.APIResourceList) {
+	f.discovery.Resources = append(f.discovery.Resources, lists...)
+}
+
+// FakeClientset 返回底层的fake clientset，用于注入响应或检查请求
+func (f *FakeClient) FakeClientset() *kubefake.Clientset {
+	return f.clientset
+}
+
+// FakeDynamicClient 返回底层的fake动态客户端，用于注入响应或检查请求
+func (f *FakeClient) FakeDynamicClient() *dynamicfake.FakeDynamicClient {
+	return f.dynamicClient
+}
+
+// FakeMetricsClient 返回底层的fake Metrics客户端
+func (f *FakeClient) FakeMetricsClient() *metricsfake.Clientset {
+	return f.metricsClient
+}
+
+// ClientSet 返回fake clientset
+func (f *FakeClient) ClientSet() kubernetes.Interface {
+	return f.clientset
+}
+
+// GetCurrentNamespace 返回Namespace字段
+func (f *FakeClient) GetCurrentNamespace() (string, error) {
+	return f.Namespace, nil
+}
+
+// GetDynamicClient 返回fake动态客户端，服务端应用的试运行不写入对象
+func (f *FakeClient) GetDynamicClient() dynamic.Interface {
+	return &dryRunDynamicClient{FakeDynamicClient: f.dynamicClient, apply: f.apply}
+}
+
+// GetDiscoveryClient 返回fake Discovery客户端
+func (f *FakeClient) GetDiscoveryClient() discovery.DiscoveryInterface {
+	return f.discovery
+}
+
+// ListNamespaces 返回命名空间，按名称排序
+func (f *FakeClient) ListNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
+	list, err := f.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, nil
+}
+
+// ListNamespaceNames 返回命名空间名称
+func (f *FakeClient) ListNamespaceNames(ctx context.Context) ([]string, error) {
+	namespaces, err := f.ListNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+// ListNodes 返回节点，按名称排序
+func (f *FakeClient) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	list, err := f.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, nil
+}
+
+// ListCRDs 返回动态客户端中的CRD
+func (f *FakeClient) ListCRDs(ctx context.Context) ([]unstructured.Unstructured, error) {
+	list, err := f.dynamicClient.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Cache 返回不缓存任何键的查询缓存
+func (f *FakeClient) Cache() *cache.Cache {
+	return f.cache
+}
+
+// InvalidateCaches 清空查询缓存
+func (f *FakeClient) InvalidateCaches() {
+	_, _ = f.cache.Invalidate("")
+}
+
+// CacheStats 返回查询缓存的统计
+func (f *FakeClient) CacheStats() k8sclient.CacheStats {
+	return k8sclient.CacheStats{Entries: f.cache.Stats()}
+}
+
+// GetMetricsClient 返回fake Metrics客户端
+func (f *FakeClient) GetMetricsClient() metricsv.Interface {
+	return f.metricsClient
+}
+
+// GetConfig 返回只包含当前命名空间的kubeconfig
+func (f *FakeClient) GetConfig() clientcmd.ClientConfig {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["fake"] = &clientcmdapi.Cluster{Server: f.restConfig.Host}
+	config.AuthInfos["fake"] = &clientcmdapi.AuthInfo{}
+	config.Contexts["fake"] = &clientcmdapi.Context{Cluster: "fake", AuthInfo: "fake", Namespace: f.Namespace}
+	config.CurrentContext = "fake"
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{})
+}
+
+// GetRESTConfig 返回指向不存在地址的REST配置，需要直接建立连接的操作会失败
+func (f *FakeClient) GetRESTConfig() *rest.Config {
+	return f.restConfig
+}
+
+// fakeDiscovery 补充fake Discovery未实现的首选版本查询
+type fakeDiscovery struct {
+	*fakediscovery.FakeDiscovery
+}
+
+// ServerPreferredResources 返回每个资源第一个声明的版本
+func (d *fakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.preferred(false), nil
+}
+
+// ServerPreferredNamespacedResources 返回每个命名空间级资源第一个声明的版本
+func (d *fakeDiscovery) ServerPreferredNamespacedResources() ([]*metav1.APIResourceList, error) {
+	return d.preferred(true), nil
+}
+
+func (d *fakeDiscovery) preferred(namespacedOnly bool) []*metav1.APIResourceList {
+	seen := make(map[schema.GroupResource]bool)
+	var lists []*metav1.APIResourceList
+	for _, list := range d.Resources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		preferred := &metav1.APIResourceList{GroupVersion: list.GroupVersion}
+		for _, resource := range list.APIResources {
+			gr := schema.GroupResource{Group: gv.Group, Resource: resource.Name}
+			if seen[gr] || (namespacedOnly && !resource.Namespaced) {
+				continue
+			}
+			seen[gr] = true
+			preferred.APIResources = append(preferred.APIResources, resource)
+		}
+		if len(preferred.APIResources) > 0 {
+			lists = append(lists, preferred)
+		}
+	}
+	return lists
+}
+
+// builtinAPIResources 根据scheme中注册的类型生成Discovery资源列表，每个组按优先版本排序
+func builtinAPIResources(scheme *runtime.Scheme, mapper meta.RESTMapper) []*metav1.APIResourceList {
+	byVersion := make(map[schema.GroupVersion]*metav1.APIResourceList)
+	for gvk, typ := range scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		// 只有存在对应List类型且带有ObjectMeta的类型才是可列出的资源
+		if !scheme.Recognizes(gvk.GroupVersion().WithKind(gvk.Kind + "List")) {
+			continue
+		}
+		if _, ok := reflect.New(typ).Interface().(metav1.Object); !ok {
+			continue
+		}
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			continue
+		}
+		list := byVersion[gvk.GroupVersion()]
+		if list == nil {
+			list = &metav1.APIResourceList{GroupVersion: gvk.GroupVersion().String()}
+			byVersion[gvk.GroupVersion()] = list
+		}
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:         mapping.Resource.Resource,
+			SingularName: strings.ToLower(gvk.Kind),
+			Namespaced:   mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+			Kind:         gvk.Kind,
+			Verbs:        defaultVerbs,
+		})
+	}
+
+	var lists []*metav1.APIResourceList
+	groups := make(map[string]bool)
+	for gv := range byVersion {
+		groups[gv.Group] = true
+	}
+	groupNames := make([]string, 0, len(groups))
+	for group := range groups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+	for _, group := range groupNames {
+		for _, gv := range scheme.PrioritizedVersionsForGroup(group) {
+			if list, ok := byVersion[gv]; ok {
+				sort.Slice(list.APIResources, func(i, j int) bool {
+					return list.APIResources[i].Name < list.APIResources[j].Name
+				})
+				lists = append(lists, list)
+			}
+		}
+	}
+	return lists
+}
+
+// podFieldIndexes API Server为Pod额外支持的字段选择器
+var podFieldIndexes = map[string]client.IndexerFunc{
+	"spec.nodeName": podField("spec", "nodeName"),
+	"status.phase":  podField("status", "phase"),
+}
+
+// podField 返回读取Pod字段的索引函数，按非结构化列表查询时fake客户端传入的是Unstructured对象
+func podField(fields ...string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return nil
+			}
+			u = &unstructured.Unstructured{Object: content}
+		}
+		value, _, _ := unstructured.NestedString(u.Object, fields...)
+		return []string{value}
+	}
+}
+
+// withFieldIndexes 注册API Server支持的字段选择器：所有内置资源的metadata.name、metadata.namespace，以及Pod的spec.nodeName、status.phase。
+// controller-runtime的fake客户端只能按已注册的索引求值字段选择器
+func withFieldIndexes(builder *crfake.ClientBuilder, scheme *runtime.Scheme) *crfake.ClientBuilder {
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") ||
+			!scheme.Recognizes(gvk.GroupVersion().WithKind(gvk.Kind+"List")) {
+			continue
+		}
+		obj, err := scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+		if _, ok := obj.(client.Object); !ok {
+			continue
+		}
+		builder = builder.
+			WithIndex(obj, "metadata.name", func(obj client.Object) []string { return []string{obj.GetName()} }).
+			WithIndex(obj, "metadata.namespace", func(obj client.Object) []string { return []string{obj.GetNamespace()} })
+	}
+	for field, extract := range podFieldIndexes {
+		builder = builder.WithIndex(&corev1.Pod{}, field, extract)
+	}
+	return builder
+}
+
+// toUnstructured 将类型化对象转换为带apiVersion和kind的非结构化对象，fake动态客户端返回的对象与真实API Server一致
+func toUnstructured(scheme *runtime.Scheme, obj runtime.Object) runtime.Object {
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return obj
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return obj
+	}
+	converted := &unstructured.Unstructured{Object: content}
+	converted.SetGroupVersionKind(gvks[0])
+	return converted
+}
+
+// isUnstructured 检查对象是否为非结构化对象
+func isUnstructured(obj runtime.Object) bool {
+	_, ok := obj.(*unstructured.Unstructured)
+	return ok
+}
+
+// fakeApply 为fake动态客户端提供近似的服务端应用（fake动态客户端只能对已存在的对象应用）：
+// 对象不存在时创建，存在时将补丁中的字段递归合并到对象上，内容变化时递增resourceVersion，试运行时不写入。
+// 不跟踪字段所有权，因此不会报告冲突；需要冲突行为时使用controller-runtime客户端
+type fakeApply struct {
+	tracker clienttesting.ObjectTracker
+	version atomic.Int64
+}
+
+// reaction 处理实际写入的应用请求；fake动态客户端不向反应器传递PatchOptions，试运行由dryRunDynamicClient处理
+func (a *fakeApply) reaction(action clienttesting.Action) (bool, runtime.Object, error) {
+	patch, ok := action.(clienttesting.PatchActionImpl)
+	if !ok || patch.GetPatchType() != types.ApplyPatchType {
+		return false, nil, nil
+	}
+	obj, err := a.apply(patch.GetResource(), patch.GetNamespace(), patch.GetName(), patch.GetPatch(), false)
+	return true, obj, err
+}
+
+// apply 应用补丁并返回应用后的对象
+func (a *fakeApply) apply(gvr schema.GroupVersionResource, namespace, name string, data []byte, dryRun bool) (*unstructured.Unstructured, error) {
+	applied := &unstructured.Unstructured{}
+	if err := applied.UnmarshalJSON(data); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	applied.SetName(name)
+	applied.SetNamespace(namespace)
+
+	existing, err := a.tracker.Get(gvr, namespace, name)
+	if apierrors.IsNotFound(err) {
+		applied.SetResourceVersion(strconv.FormatInt(a.version.Add(1), 10))
+		if !dryRun {
+			if err := a.tracker.Create(gvr, applied, namespace); err != nil {
+				return nil, err
+			}
+		}
+		return applied, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return nil, err
+	}
+	merged := &unstructured.Unstructured{Object: mergeFields(runtime.DeepCopyJSON(current), applied.Object)}
+	if !equality.Semantic.DeepEqual(merged.Object, current) {
+		merged.SetResourceVersion(strconv.FormatInt(a.version.Add(1), 10))
+	}
+	if !dryRun {
+		if err := a.tracker.Update(gvr, merged, namespace); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// dryRunDynamicClient 在fake动态客户端之上处理服务端应用的试运行，其余请求原样交给fake动态客户端
+type dryRunDynamicClient struct {
+	*dynamicfake.FakeDynamicClient
+	apply *fakeApply
+}
+
+func (c *dryRunDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	base := c.FakeDynamicClient.Resource(gvr)
+	return &dryRunNamespaceableResource{
+		dryRunResource: dryRunResource{ResourceInterface: base, apply: c.apply, gvr: gvr},
+		base:           base,
+	}
+}
+
+type dryRunNamespaceableResource struct {
+	dryRunResource
+	base dynamic.NamespaceableResourceInterface
+}
+
+func (r *dryRunNamespaceableResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &dryRunResource{ResourceInterface: r.base.Namespace(namespace), apply: r.apply, gvr: r.gvr, namespace: namespace}
+}
+
+type dryRunResource struct {
+	dynamic.ResourceInterface
+	apply     *fakeApply
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func (r *dryRunResource) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if pt == types.ApplyPatchType && len(subresources) == 0 && slices.Contains(options.DryRun, metav1.DryRunAll) {
+		return r.apply.apply(r.gvr, r.namespace, name, data, true)
+	}
+	return r.ResourceInterface.Patch(ctx, name, pt, data, options, subresources...)
+}
+
+// mergeFields 将patch中的字段递归合并到target，映射逐键合并，其他值（包括列表）整体替换
+func mergeFields(target, patch map[string]any) map[string]any {
+	for key, value := range patch {
+		if patchMap, ok := value.(map[string]any); ok {
+			if targetMap, ok := target[key].(map[string]any); ok {
+				target[key] = mergeFields(targetMap, patchMap)
+				continue
+			}
+		}
+		target[key] = runtime.DeepCopyJSONValue(value)
+	}
+	return target
+}
+
+// newFakeMetricsClient 创建包含指定指标的fake Metrics客户端。
+// NewSimpleClientset按类型名推断资源（podmetricses），而客户端按API Server提供的资源（pods、nodes）查询，因此按实际资源写入
+func newFakeMetricsClient(objects ...runtime.Object) *metricsfake.Clientset {
+	clientset := metricsfake.NewSimpleClientset()
+	for _, obj := range objects {
+		var resource string
+		var namespace string
+		switch metric := obj.(type) {
+		case *metricsv1beta1.PodMetrics:
+			resource, namespace = "pods", metric.Namespace
+		case *metricsv1beta1.NodeMetrics:
+			resource = "nodes"
+		}
+		if err := clientset.Tracker().Create(metricsv1beta1.SchemeGroupVersion.WithResource(resource), obj, namespace); err != nil {
+			panic(err)
+		}
+	}
+	return clientset
+}
+
+// isMetricsObject 检查对象是否为metrics.k8s.io的类型化对象
+func isMetricsObject(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *metricsv1beta1.PodMetrics, *metricsv1beta1.NodeMetrics:
+		return true
+	}
+	return false
+}
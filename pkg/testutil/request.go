@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// NewToolRequest 构造调用指定工具的请求，arguments中的整数按JSON解码后的float64传入，与客户端实际发送的请求一致
+func NewToolRequest(tool string, arguments map[string]any) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Method = tool
+	request.Params.Name = tool
+	normalized := make(map[string]any, len(arguments))
+	for key, value := range arguments {
+		normalized[key] = normalizeArgument(value)
+	}
+	request.Params.Arguments = normalized
+	return request
+}
+
+// ResultText 返回工具结果中所有文本内容，按顺序以换行连接
+func ResultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// DecodeResult 将JSON格式的成功结果解码到v，结果为错误时返回错误
+func DecodeResult(result *mcp.CallToolResult, v any) error {
+	if result == nil {
+		return fmt.Errorf("tool returned no result")
+	}
+	if result.IsError {
+		return fmt.Errorf("tool returned an error: %s", ResultText(result))
+	}
+	return json.Unmarshal([]byte(ResultText(result)), v)
+}
+
+// DecodeToolError 解码工具返回的结构化错误，结果不是错误时返回错误
+func DecodeToolError(result *mcp.CallToolResult) (models.ToolError, error) {
+	var toolErr models.ToolError
+	if result == nil || !result.IsError {
+		return toolErr, fmt.Errorf("tool did not return an error")
+	}
+	if err := json.Unmarshal([]byte(ResultText(result)), &toolErr); err != nil {
+		return toolErr, fmt.Errorf("error result is not a structured tool error: %w", err)
+	}
+	return toolErr, nil
+}
+
+// normalizeArgument 将整数转换为float64，其余值原样返回
+func normalizeArgument(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+func TestDefaultDeprecatedAPIs(t *testing.T) {
+	seen := make(map[string]bool)
+	var previousRemoval string
+	for _, api := range DefaultDeprecatedAPIs() {
+		id := api.APIVersion + " " + api.Kind
+		if seen[id] {
+			t.Errorf("%s is listed twice", id)
+		}
+		seen[id] = true
+
+		deprecatedIn, err := ParseKubernetesVersion(api.DeprecatedIn)
+		if err != nil {
+			t.Errorf("%s: %v", id, err)
+			continue
+		}
+		removedIn, err := ParseKubernetesVersion(api.RemovedIn)
+		if err != nil {
+			t.Errorf("%s: %v", id, err)
+			continue
+		}
+		if !removedIn.GreaterThan(deprecatedIn) {
+			t.Errorf("%s is removed in %s before or when it is deprecated in %s", id, api.RemovedIn, api.DeprecatedIn)
+		}
+		if previousRemoval != "" && removedIn.LessThan(mustParseVersion(t, previousRemoval)) {
+			t.Errorf("%s (removed in %s) is out of order after an API removed in %s", id, api.RemovedIn, previousRemoval)
+		}
+		previousRemoval = api.RemovedIn
+		if api.Replacement == "" && api.Note == "" {
+			t.Errorf("%s has neither a replacement nor a note", id)
+		}
+		if api.Replacement == api.APIVersion {
+			t.Errorf("%s is its own replacement", id)
+		}
+	}
+}
+
+func TestDeprecatedAPIStatus(t *testing.T) {
+	ingress := models.DeprecatedAPI{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", DeprecatedIn: "1.19", RemovedIn: "1.22"}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{target: "1.18", want: ""},
+		{target: "v1.19.0", want: models.APIStatusDeprecated},
+		{target: "1.21.14-eks-1234", want: models.APIStatusDeprecated},
+		{target: "1.22", want: models.APIStatusRemoved},
+		{target: "1.30.2", want: models.APIStatusRemoved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			status, err := DeprecatedAPIStatus(ingress, mustParseVersion(t, tt.target))
+			if err != nil || status != tt.want {
+				t.Fatalf("status = %q, %v; want %q", status, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := DeprecatedAPIStatus(models.DeprecatedAPI{RemovedIn: "soon"}, mustParseVersion(t, "1.30")); err == nil {
+		t.Fatal("invalid removal version was accepted")
+	}
+}
+
+func TestParseKubernetesVersion(t *testing.T) {
+	for _, value := range []string{"1.29", "v1.29.3", "v1.29.3-eks-1234", "1.29.3+k3s1"} {
+		parsed, err := ParseKubernetesVersion(value)
+		if err != nil || parsed.Major() != 1 || parsed.Minor() != 29 {
+			t.Errorf("ParseKubernetesVersion(%q) = %v, %v", value, parsed, err)
+		}
+	}
+	if _, err := ParseKubernetesVersion("latest"); err == nil {
+		t.Error("invalid version was accepted")
+	}
+	if skew := MinorSkew(mustParseVersion(t, "1.30"), mustParseVersion(t, "1.27.4")); skew != 3 {
+		t.Errorf("MinorSkew = %d, want 3", skew)
+	}
+}
+
+func mustParseVersion(t *testing.T, value string) *version.Version {
+	t.Helper()
+	parsed, err := ParseKubernetesVersion(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
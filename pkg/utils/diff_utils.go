@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// 统一diff输出中每个变更块前后的上下文行数
+	unifiedDiffContext = 3
+	// 逐行比较的最大规模（行数乘积），超过时只输出整体替换
+	maxDiffMatrixSize = 4_000_000
+)
+
+// diffOp 单行差异操作
+type diffOp struct {
+	kind byte // ' ' 相同, '-' 删除, '+' 新增
+	line string
+}
+
+// UnifiedDiff 生成两段文本的统一格式差异，文本相同时返回空字符串
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+	ops := diffLines(oldLines, newLines)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName))
+
+	// 按上下文行数将操作分组为变更块
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// 向前包含上下文
+		start := i
+		for start > 0 && i-start < unifiedDiffContext && ops[start-1].kind == ' ' {
+			start--
+		}
+		hunkOld := oldLine - (i - start)
+		hunkNew := newLine - (i - start)
+
+		// 向后扩展，直到连续相同行超过两倍上下文
+		end := i
+		same := 0
+		for end < len(ops) {
+			if ops[end].kind == ' ' {
+				same++
+				if same > unifiedDiffContext*2 {
+					break
+				}
+			} else {
+				same = 0
+			}
+			end++
+		}
+		end -= max(0, same-unifiedDiffContext)
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		b.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", hunkOld, oldCount, hunkNew, newCount))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			b.WriteByte('\n')
+		}
+
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				oldLine++
+			}
+			if op.kind != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// diffLines 使用最长公共子序列计算逐行差异
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	if n*m > maxDiffMatrixSize {
+		ops := make([]diffOp, 0, n+m)
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] 表示 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines 将文本按行拆分，忽略末尾换行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
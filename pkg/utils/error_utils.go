@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// 结构化错误的分类
+const (
+	ErrorCodeToolError       = "ToolError"
+	ErrorCodeNotFound        = "NotFound"
+	ErrorCodeAlreadyExists   = "AlreadyExists"
+	ErrorCodeConflict        = "Conflict"
+	ErrorCodeForbidden       = "Forbidden"
+	ErrorCodeUnauthorized    = "Unauthorized"
+	ErrorCodeInvalid         = "Invalid"
+	ErrorCodeTimeout         = "Timeout"
+	ErrorCodeUnavailable     = "Unavailable"
+	ErrorCodeBusy            = "ServerBusy"
+	ErrorCodeRefused         = "Refused"
+	ErrorCodeUnsupported     = "Unsupported"
+	ErrorCodeKubernetesError = "KubernetesError"
+)
+
+// NewErrorToolResult 创建一个表示错误的CallToolResult
+// 这将IsError设置为true，并以结构化错误的形式返回错误消息，而不是返回error对象
+func NewErrorToolResult(errMsg string) *mcp.CallToolResult {
+	return NewToolErrorResult(models.ToolError{
+		Code:    ErrorCodeToolError,
+		Message: errMsg,
+	})
+}
+
+// NewKubeErrorResult 将Kubernetes API错误转换为带有分类和处理建议的错误结果
+// context描述失败的操作，依次以": "连接在错误消息之前
+func NewKubeErrorResult(err error, context ...string) *mcp.CallToolResult {
+	code, hint := classifyKubeError(err)
+	parts := append(append([]string{}, context...), err.Error())
+	return NewToolErrorResult(models.ToolError{
+		Code:    code,
+		Message: strings.Join(parts, ": "),
+		Reason:  string(apierrors.ReasonForError(err)),
+		Hint:    hint,
+	})
+}
+
+// NewKubeErrorResultWithSuggestions 与NewKubeErrorResult相同，资源不存在且有相近名称时
+// 将其附加在错误消息和Details中，没有相近名称时结果与NewKubeErrorResult一致
+func NewKubeErrorResultWithSuggestions(err error, suggestions []string, context ...string) *mcp.CallToolResult {
+	if len(suggestions) == 0 {
+		return NewKubeErrorResult(err, context...)
+	}
+	code, _ := classifyKubeError(err)
+	parts := append(append([]string{}, context...), err.Error())
+	return NewToolErrorResult(models.ToolError{
+		Code:    code,
+		Message: strings.Join(parts, ": ") + "; did you mean: " + strings.Join(suggestions, ", "),
+		Reason:  string(apierrors.ReasonForError(err)),
+		Hint:    "Retry with one of the suggested names, or use FIND_RESOURCE_BY_NAME to search by approximate name across namespaces.",
+		Details: models.NameSuggestionDetails{Suggestions: suggestions},
+	})
+}
+
+// NewToolErrorResult 将结构化错误序列化为IsError为true的CallToolResult
+func NewToolErrorResult(toolErr models.ToolError) *mcp.CallToolResult {
+	text := toolErr.Message
+	if data, err := json.MarshalIndent(toolErr, "", "  "); err == nil {
+		text = string(data)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+		IsError: true,
+	}
+}
+
+// classifyKubeError 返回Kubernetes API错误的分类及处理建议
+func classifyKubeError(err error) (string, string) {
+	var kindErr *KindNotFoundError
+	switch {
+	case errors.As(err, &kindErr), meta.IsNoMatchError(err):
+		return ErrorCodeNotFound, "Use GET_API_RESOURCES to list the kinds and apiVersions served by the cluster."
+	case apierrors.IsNotFound(err):
+		return ErrorCodeNotFound, "Check the kind, name and namespace, or use the LIST tools to see which resources exist."
+	case apierrors.IsAlreadyExists(err):
+		return ErrorCodeAlreadyExists, "The resource already exists; use the UPDATE tools to change it or choose a different name."
+	case apierrors.IsConflict(err):
+		return ErrorCodeConflict, "The object was modified concurrently; fetch the latest version and retry."
+	case apierrors.IsForbidden(err):
+		return ErrorCodeForbidden, "The server's credentials lack RBAC permission for this operation; use CHECK_PERMISSION to see which verbs are allowed."
+	case apierrors.IsUnauthorized(err):
+		return ErrorCodeUnauthorized, "The server's credentials were rejected; check that the kubeconfig or service account token is valid."
+	case apierrors.IsBadRequest(err) && strings.Contains(err.Error(), "field label not supported"):
+		return ErrorCodeInvalid, "The kind does not support this field selector. Every kind supports metadata.name and metadata.namespace; other fields are kind-specific (for example status.phase and spec.nodeName for Pods). Use labelSelector instead."
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ErrorCodeInvalid, "The request was rejected by validation; fix the fields listed in the message and retry."
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return ErrorCodeTimeout, "The API server did not respond in time; retry, or narrow the request with a namespace or selector."
+	case apierrors.IsServiceUnavailable(err), apierrors.IsTooManyRequests(err):
+		return ErrorCodeUnavailable, "The API server is unavailable or throttling requests; retry after a short delay."
+	case apierrors.ReasonForError(err) != "":
+		return ErrorCodeKubernetesError, ""
+	default:
+		return ErrorCodeToolError, ""
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+var podsResource = schema.GroupResource{Resource: "pods"}
+
+func decodeToolError(t *testing.T, result *mcp.CallToolResult) models.ToolError {
+	t.Helper()
+	if !result.IsError {
+		t.Fatal("result is not an error")
+	}
+	var toolErr models.ToolError
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &toolErr); err != nil {
+		t.Fatalf("error result is not a structured tool error: %v", err)
+	}
+	return toolErr
+}
+
+func TestNewKubeErrorResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    string
+		reason  string
+		hint    bool
+		message string
+	}{
+		{name: "not found", err: apierrors.NewNotFound(podsResource, "web"), code: ErrorCodeNotFound, reason: "NotFound", hint: true,
+			message: `get pod: pods "web" not found`},
+		{name: "kind not found", err: &KindNotFoundError{Kind: "Widget"}, code: ErrorCodeNotFound, hint: true},
+		{name: "already exists", err: apierrors.NewAlreadyExists(podsResource, "web"), code: ErrorCodeAlreadyExists, reason: "AlreadyExists", hint: true},
+		{name: "conflict", err: apierrors.NewConflict(podsResource, "web", errors.New("modified")), code: ErrorCodeConflict, reason: "Conflict", hint: true},
+		{name: "forbidden", err: apierrors.NewForbidden(podsResource, "web", errors.New("denied")), code: ErrorCodeForbidden, reason: "Forbidden", hint: true},
+		{name: "field selector", err: apierrors.NewBadRequest(`field label not supported: spec.foo`), code: ErrorCodeInvalid, reason: "BadRequest", hint: true},
+		{name: "throttled", err: apierrors.NewTooManyRequests("slow down", 1), code: ErrorCodeUnavailable, reason: "TooManyRequests", hint: true},
+		{name: "plain error", err: errors.New("boom"), code: ErrorCodeToolError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolErr := decodeToolError(t, NewKubeErrorResult(tt.err, "get pod"))
+			if toolErr.Code != tt.code || toolErr.Reason != tt.reason || (toolErr.Hint != "") != tt.hint {
+				t.Fatalf("error = %+v, want code %s, reason %q, hint %v", toolErr, tt.code, tt.reason, tt.hint)
+			}
+			if tt.message != "" && toolErr.Message != tt.message {
+				t.Fatalf("message = %q, want %q", toolErr.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestNewKubeErrorResultWithSuggestions(t *testing.T) {
+	notFound := apierrors.NewNotFound(podsResource, "wbe")
+
+	toolErr := decodeToolError(t, NewKubeErrorResultWithSuggestions(notFound, []string{"web"}))
+	if toolErr.Code != ErrorCodeNotFound || toolErr.Message != `pods "wbe" not found; did you mean: web` {
+		t.Fatalf("error = %+v", toolErr)
+	}
+	details, ok := toolErr.Details.(map[string]any)
+	if !ok || len(details["suggestions"].([]any)) != 1 {
+		t.Fatalf("details = %+v, want one suggestion", toolErr.Details)
+	}
+
+	if toolErr := decodeToolError(t, NewKubeErrorResultWithSuggestions(notFound, nil)); toolErr.Details != nil {
+		t.Fatalf("details without suggestions = %+v", toolErr.Details)
+	}
+}
+
+func TestNewErrorToolResult(t *testing.T) {
+	toolErr := decodeToolError(t, NewErrorToolResult("name is required"))
+	if toolErr.Code != ErrorCodeToolError || toolErr.Message != "name is required" || toolErr.Reason != "" {
+		t.Fatalf("error = %+v", toolErr)
+	}
+}
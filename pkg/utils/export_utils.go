@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// exportStripFields 所有对象导出时都需要移除的服务端字段
+var exportStripFields = [][]string{
+	{"status"},
+	{"metadata", "uid"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "deletionTimestamp"},
+	{"metadata", "deletionGracePeriodSeconds"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "selfLink"},
+	{"metadata", "ownerReferences"},
+}
+
+// exportStripAnnotations 导出时移除的由服务端或控制器写入的注解
+var exportStripAnnotations = []string{
+	LastAppliedConfigAnnotation,
+	"deployment.kubernetes.io/revision",
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"pv.kubernetes.io/provisioned-by",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+}
+
+// exportKindStripFields 按资源类型移除的由服务端分配的字段
+var exportKindStripFields = map[schema.GroupKind][][]string{
+	{Kind: "Service"}: {
+		{"spec", "clusterIP"},
+		{"spec", "clusterIPs"},
+		{"spec", "healthCheckNodePort"},
+	},
+	{Kind: "Pod"}: {
+		{"spec", "nodeName"},
+	},
+	{Kind: "PersistentVolumeClaim"}: {
+		{"spec", "volumeName"},
+	},
+	{Kind: "PersistentVolume"}: {
+		{"spec", "claimRef", "uid"},
+		{"spec", "claimRef", "resourceVersion"},
+	},
+	{Kind: "Namespace"}: {
+		{"spec", "finalizers"},
+	},
+	{Group: "batch", Kind: "Job"}: {
+		{"spec", "selector"},
+		{"spec", "template", "metadata", "labels", "controller-uid"},
+		{"spec", "template", "metadata", "labels", "batch.kubernetes.io/controller-uid"},
+	},
+}
+
+// CleanForExport 移除对象中由服务端填充的字段，使其可以直接提交到Git或在其他集群重新应用
+func CleanForExport(obj *unstructured.Unstructured) {
+	for _, field := range exportStripFields {
+		unstructured.RemoveNestedField(obj.Object, field...)
+	}
+
+	for _, field := range exportKindStripFields[obj.GroupVersionKind().GroupKind()] {
+		unstructured.RemoveNestedField(obj.Object, field...)
+	}
+
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		for _, key := range exportStripAnnotations {
+			delete(annotations, key)
+		}
+		if len(annotations) == 0 {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+		} else {
+			obj.SetAnnotations(annotations)
+		}
+	}
+}
+
+// MarshalManifest 将多个对象序列化为以'---'分隔的多文档YAML
+func MarshalManifest(objs []*unstructured.Unstructured) (string, error) {
+	docs := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(data))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
+)
+
+// maxKindSuggestions 找不到资源类型时最多给出的相近类型数量
+const maxKindSuggestions = 5
+
+// KindNotFoundError 表示集群中没有提供指定的资源类型
+type KindNotFoundError struct {
+	// APIVersion 请求的apiVersion，按类型名查找时为空
+	APIVersion string
+	// Kind 请求的资源类型
+	Kind string
+	// Suggestions 集群中名称相近的资源类型，格式为"apiVersion Kind"
+	Suggestions []string
+}
+
+// Error 实现error接口
+func (e *KindNotFoundError) Error() string {
+	message := fmt.Sprintf("kind %s is not served by the cluster", e.Kind)
+	if e.APIVersion != "" {
+		message = fmt.Sprintf("kind %s with apiVersion %s is not served by the cluster", e.Kind, e.APIVersion)
+	}
+	if len(e.Suggestions) > 0 {
+		message += "; did you mean: " + strings.Join(e.Suggestions, ", ")
+	}
+	return message
+}
+
+// ResolveGVR 通过客户端的RESTMapper将apiVersion和kind解析为GVR，并返回资源是否为命名空间级别
+// kind仅大小写不同时自动纠正；集群中没有该类型时返回带有相近类型建议的KindNotFoundError
+func ResolveGVR(c kubernetes.Client, apiVersion, kind string) (schema.GroupVersionResource, bool, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+
+	mapping, err := c.RESTMapper().RESTMapping(schema.GroupKind{Group: gv.Group, Kind: kind}, gv.Version)
+	if meta.IsNoMatchError(err) {
+		candidates := matchKinds(c, kind, "")
+		for _, candidate := range candidates {
+			if candidate.GroupVersion() == gv && candidate.Kind != kind && strings.EqualFold(candidate.Kind, kind) {
+				mapping, err = c.RESTMapper().RESTMapping(candidate.GroupKind(), gv.Version)
+				break
+			}
+		}
+		if meta.IsNoMatchError(err) {
+			return schema.GroupVersionResource{}, false, &KindNotFoundError{
+				APIVersion:  apiVersion,
+				Kind:        kind,
+				Suggestions: kindSuggestions(candidates),
+			}
+		}
+	}
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	return mapping.Resource, IsNamespacedMapping(mapping), nil
+}
+
+// ResolveKind 将不带apiVersion的资源类型解析为各API组首选版本的映射
+// name可以是类型名、资源复数名、单数名或简称，可用"name.group"的形式限定API组，例如"ingresses.networking.k8s.io"
+func ResolveKind(c kubernetes.Client, name string) ([]*meta.RESTMapping, error) {
+	kind, group, _ := strings.Cut(name, ".")
+
+	var mappings []*meta.RESTMapping
+	seen := make(map[schema.GroupKind]bool)
+	for _, gvk := range matchKinds(c, kind, group) {
+		gk := gvk.GroupKind()
+		if seen[gk] {
+			continue
+		}
+		seen[gk] = true
+
+		mapping, err := c.RESTMapper().RESTMapping(gk)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	if len(mappings) == 0 {
+		return nil, &KindNotFoundError{Kind: name}
+	}
+	return mappings, nil
+}
+
+// matchKinds 在Discovery缓存中查找类型名、资源名、单数名或简称与name匹配的资源类型，group非空时只在该API组中查找
+func matchKinds(c kubernetes.Client, name, group string) []schema.GroupVersionKind {
+	// 部分API组发现失败时仍使用已获取的资源
+	_, resourcesList, _ := c.GetDiscoveryClient().ServerGroupsAndResources()
+
+	var matches []schema.GroupVersionKind
+	for _, resList := range resourcesList {
+		gv, err := schema.ParseGroupVersion(resList.GroupVersion)
+		if err != nil || (group != "" && gv.Group != group) {
+			continue
+		}
+		for _, res := range resList.APIResources {
+			// 跳过子资源
+			if strings.Contains(res.Name, "/") {
+				continue
+			}
+			if !matchesResourceName(name, res.Kind, res.Name, res.SingularName, res.ShortNames) {
+				continue
+			}
+			matches = append(matches, gv.WithKind(res.Kind))
+		}
+	}
+	return matches
+}
+
+// matchesResourceName 判断name是否不区分大小写地等于类型名、资源名、单数名或任一简称
+func matchesResourceName(name, kind, plural, singular string, shortNames []string) bool {
+	for _, candidate := range append([]string{kind, plural, singular}, shortNames...) {
+		if candidate != "" && strings.EqualFold(name, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// kindSuggestions 将相近的资源类型格式化为去重排序后的建议列表
+func kindSuggestions(candidates []schema.GroupVersionKind) []string {
+	seen := make(map[string]bool)
+	var suggestions []string
+	for _, gvk := range candidates {
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		suggestion := apiVersion + " " + kind
+		if seen[suggestion] {
+			continue
+		}
+		seen[suggestion] = true
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Strings(suggestions)
+	if len(suggestions) > maxKindSuggestions {
+		suggestions = suggestions[:maxKindSuggestions]
+	}
+	return suggestions
+}
+
+// IsNamespacedMapping 判断映射的资源是否为命名空间级别
+func IsNamespacedMapping(mapping *meta.RESTMapping) bool {
+	return mapping.Scope != nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
@@ -12,23 +12,34 @@ import (
 // logAnalyzer 日志分析器结构体
 type logAnalyzer struct {
 	pattern models.LogPattern
+	fields  models.LogFieldMapping
+	// customErrors 为true时结构化日志即使级别不是error，消息匹配自定义错误模式也计为错误
+	customErrors bool
 }
 
 // NewLogAnalyzer 创建一个新的日志分析器
 func NewLogAnalyzer() *logAnalyzer {
 	return &logAnalyzer{
 		pattern: DefaultLogPattern(),
+		fields:  DefaultLogFieldMapping(),
 	}
 }
 
 // NewLogAnalyzerWithPattern 创建一个使用自定义错误模式的日志分析器
 func NewLogAnalyzerWithPattern(customErrorPattern string) *logAnalyzer {
+	return NewLogAnalyzerWithFields(customErrorPattern, models.LogFieldMapping{})
+}
+
+// NewLogAnalyzerWithFields 创建一个使用自定义错误模式和JSON字段映射的日志分析器，fields中为空的项使用默认字段名
+func NewLogAnalyzerWithFields(customErrorPattern string, fields models.LogFieldMapping) *logAnalyzer {
 	pattern := DefaultLogPattern()
 	if customErrorPattern != "" {
 		pattern.ErrorPattern = customErrorPattern
 	}
 	return &logAnalyzer{
-		pattern: pattern,
+		pattern:      pattern,
+		fields:       MergeLogFieldMapping(DefaultLogFieldMapping(), fields),
+		customErrors: customErrorPattern != "",
 	}
 }
 
@@ -57,55 +68,109 @@ func (a *logAnalyzer) AnalyzeLogs(logLines []string) *models.LogAnalysisResult {
 	// 响应时间分类
 	timeCategories := DefaultTimeCategories()
 
+	// observeTimestamp 更新时间范围，错误日志同时计入小时分布
+	observeTimestamp := func(parsedTime time.Time, isError bool) {
+		if !hasTimestamp {
+			firstTimestamp = parsedTime
+			lastTimestamp = parsedTime
+			hasTimestamp = true
+		} else {
+			if parsedTime.Before(firstTimestamp) {
+				firstTimestamp = parsedTime
+			}
+			if parsedTime.After(lastTimestamp) {
+				lastTimestamp = parsedTime
+			}
+		}
+
+		// 统计小时分布
+		if isError {
+			hourlyErrors[parsedTime.Format("2006-01-02 15")]++
+		}
+	}
+
+	// recordError 计数错误并记录错误消息和第i行周围的上下文
+	recordError := func(i int, errorMsg string) {
+		result.ErrorCount++
+		result.TopErrors[errorMsg]++
+
+		// 查找周围上下文
+		contextStart := Max(0, i-2)
+		contextEnd := Min(len(logLines), i+3)
+		context := strings.Join(logLines[contextStart:contextEnd], "\n")
+
+		// 只保存前10个不同类型的错误上下文
+		if len(result.TopPatterns) < 10 {
+			contextHash := GetContextHash(context)
+			if _, exists := result.TopPatterns[contextHash]; !exists {
+				result.TopPatterns[contextHash] = 1
+			} else {
+				result.TopPatterns[contextHash]++
+			}
+		}
+	}
+
+	// recordResponseTime 记录响应时间并分类
+	recordResponseTime := func(responseTime int) {
+		result.ResponseTimes = append(result.ResponseTimes, responseTime)
+
+		// 对响应时间进行分类
+		for _, category := range timeCategories {
+			if category.Threshold < 0 || responseTime < category.Threshold {
+				result.ResponseTimeStats[category.Name]++
+				break
+			}
+		}
+	}
+
+	result.LineCount = len(logLines)
 	for i, line := range logLines {
+		// JSON结构化日志按字段分析，其余行使用正则启发式分析；同一日志中两种格式可以混合出现
+		if entry, prefixTime, ok := parseJSONLogLine(line); ok {
+			result.StructuredLines++
+			fields := a.extractLogFields(entry)
+			if fields.timestamp.IsZero() {
+				fields.timestamp = prefixTime
+			}
+
+			isError := fields.level == "error" || fields.level == "fatal" ||
+				((fields.level == "" || a.customErrors) && errorRegex.MatchString(fields.message))
+			if !fields.timestamp.IsZero() {
+				observeTimestamp(fields.timestamp, isError)
+			}
+			if fields.level != "" {
+				result.LogLevels[fields.level]++
+			}
+			switch {
+			case isError:
+				recordError(i, structuredErrorMessage(fields))
+			case fields.level == "warn" || (fields.level == "" && warningRegex.MatchString(fields.message)):
+				result.WarningCount++
+			case fields.level == "info" || (fields.level == "" && infoRegex.MatchString(fields.message)):
+				result.InfoCount++
+			}
+			if fields.hasDuration {
+				recordResponseTime(fields.durationMillis)
+			}
+			if fields.status > 0 {
+				result.StatusCodes[fields.status]++
+			}
+			continue
+		}
+
 		// 提取时间戳
 		timestampMatch := timestampRegex.FindString(line)
 		if timestampMatch != "" {
 			parsedTime, err := time.Parse(time.RFC3339, timestampMatch)
 			if err == nil {
-				if !hasTimestamp {
-					firstTimestamp = parsedTime
-					lastTimestamp = parsedTime
-					hasTimestamp = true
-				} else {
-					if parsedTime.Before(firstTimestamp) {
-						firstTimestamp = parsedTime
-					}
-					if parsedTime.After(lastTimestamp) {
-						lastTimestamp = parsedTime
-					}
-				}
-
-				// 统计小时分布
-				hourKey := parsedTime.Format("2006-01-02 15")
-				if errorRegex.MatchString(line) {
-					hourlyErrors[hourKey]++
-				}
+				observeTimestamp(parsedTime, errorRegex.MatchString(line))
 			}
 		}
 
 		// 检测错误
 		if errorRegex.MatchString(line) {
-			result.ErrorCount++
-
 			// 提取错误消息（尝试找到错误的主要部分）
-			errorMsg := ExtractErrorMessage(line)
-			result.TopErrors[errorMsg]++
-
-			// 查找周围上下文
-			contextStart := Max(0, i-2)
-			contextEnd := Min(len(logLines), i+3)
-			context := strings.Join(logLines[contextStart:contextEnd], "\n")
-
-			// 只保存前10个不同类型的错误上下文
-			if len(result.TopPatterns) < 10 {
-				contextHash := GetContextHash(context)
-				if _, exists := result.TopPatterns[contextHash]; !exists {
-					result.TopPatterns[contextHash] = 1
-				} else {
-					result.TopPatterns[contextHash]++
-				}
-			}
+			recordError(i, ExtractErrorMessage(line))
 		}
 
 		// 检测警告
@@ -129,15 +194,7 @@ func (a *logAnalyzer) AnalyzeLogs(logLines []string) *models.LogAnalysisResult {
 		responseTimeMatches := responseTimeRegex.FindStringSubmatch(line)
 		if len(responseTimeMatches) > 2 {
 			if responseTime, err := strconv.Atoi(responseTimeMatches[2]); err == nil {
-				result.ResponseTimes = append(result.ResponseTimes, responseTime)
-
-				// 对响应时间进行分类
-				for _, category := range timeCategories {
-					if category.Threshold < 0 || responseTime < category.Threshold {
-						result.ResponseTimeStats[category.Name]++
-						break
-					}
-				}
+				recordResponseTime(responseTime)
 			}
 		}
 
@@ -199,3 +256,50 @@ func (a *logAnalyzer) AnalyzeLogsWithPrompt(logLines []string, prompt string) *m
 
 	return result
 }
+
+// MergeLogAnalysisResults 合并多个日志流的分析结果：计数与各项统计相加，时间范围取并集，nil结果被忽略
+func MergeLogAnalysisResults(results ...*models.LogAnalysisResult) *models.LogAnalysisResult {
+	merged := models.NewLogAnalysisResult()
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		merged.ErrorCount += result.ErrorCount
+		merged.WarningCount += result.WarningCount
+		merged.InfoCount += result.InfoCount
+		mergeCounts(merged.TopErrors, result.TopErrors)
+		mergeCounts(merged.TopPatterns, result.TopPatterns)
+		mergeCounts(merged.ErrorDistribution, result.ErrorDistribution)
+		mergeCounts(merged.TimeBased, result.TimeBased)
+		mergeCounts(merged.LogLevels, result.LogLevels)
+		mergeCounts(merged.ResponseTimeStats, result.ResponseTimeStats)
+		mergeCounts(merged.StatusCodes, result.StatusCodes)
+		mergeCounts(merged.UserAgents, result.UserAgents)
+		merged.ResponseTimes = append(merged.ResponseTimes, result.ResponseTimes...)
+		for resourceType, values := range result.ResourceUsage {
+			merged.ResourceUsage[resourceType] = append(merged.ResourceUsage[resourceType], values...)
+		}
+		merged.ProcessingDuration += result.ProcessingDuration
+		merged.LineCount += result.LineCount
+		merged.StructuredLines += result.StructuredLines
+		if merged.AnalysisPrompt == "" {
+			merged.AnalysisPrompt = result.AnalysisPrompt
+		}
+
+		// 没有时间戳的日志流时间范围为零值，不参与合并
+		if !result.TimeRange[0].IsZero() && (merged.TimeRange[0].IsZero() || result.TimeRange[0].Before(merged.TimeRange[0])) {
+			merged.TimeRange[0] = result.TimeRange[0]
+		}
+		if result.TimeRange[1].After(merged.TimeRange[1]) {
+			merged.TimeRange[1] = result.TimeRange[1]
+		}
+	}
+	return merged
+}
+
+// mergeCounts 将delta中的计数累加到total
+func mergeCounts[K comparable](total, delta map[K]int) {
+	for key, count := range delta {
+		total[key] += count
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+func logResult(errors map[string]int, lines, structured int, from, to time.Time) *models.LogAnalysisResult {
+	result := models.NewLogAnalysisResult()
+	for message, count := range errors {
+		result.TopErrors[message] = count
+		result.ErrorCount += count
+	}
+	result.LineCount = lines
+	result.StructuredLines = structured
+	result.StatusCodes[500] = len(errors)
+	result.TimeRange = [2]time.Time{from, to}
+	return result
+}
+
+func TestMergeLogAnalysisResults(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	web := logResult(map[string]int{"connection refused": 3, "timeout": 1}, 100, 100, start.Add(time.Minute), start.Add(5*time.Minute))
+	sidecar := logResult(map[string]int{"connection refused": 2}, 50, 0, start, start.Add(2*time.Minute))
+	// 没有时间戳的日志流不影响时间范围
+	untimed := logResult(map[string]int{"disk full": 1}, 10, 0, time.Time{}, time.Time{})
+
+	merged := MergeLogAnalysisResults(web, nil, sidecar, untimed)
+
+	if merged.ErrorCount != 7 || merged.LineCount != 160 || merged.StructuredLines != 100 {
+		t.Fatalf("errors %d, lines %d, structured %d; want 7, 160, 100", merged.ErrorCount, merged.LineCount, merged.StructuredLines)
+	}
+	if want := map[string]int{"connection refused": 5, "timeout": 1, "disk full": 1}; !reflect.DeepEqual(merged.TopErrors, want) {
+		t.Fatalf("top errors = %v, want %v", merged.TopErrors, want)
+	}
+	if merged.StatusCodes[500] != 4 {
+		t.Fatalf("status codes = %v", merged.StatusCodes)
+	}
+	if merged.TimeRange != [2]time.Time{start, start.Add(5 * time.Minute)} {
+		t.Fatalf("time range = %v, want the union of the timed streams", merged.TimeRange)
+	}
+	if merged.Format() != "mixed" {
+		t.Fatalf("format = %s, want mixed", merged.Format())
+	}
+	if web.TopErrors["connection refused"] != 3 {
+		t.Fatal("merging modified an input result")
+	}
+}
+
+func TestMergeLogAnalysisResultsEmpty(t *testing.T) {
+	merged := MergeLogAnalysisResults()
+	if merged.ErrorCount != 0 || !merged.TimeRange[0].IsZero() || merged.TopErrors == nil || merged.Format() != "text" {
+		t.Fatalf("merged empty input = %+v", merged)
+	}
+}
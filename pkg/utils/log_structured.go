@@ -0,0 +1,330 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// structuredLogTimeLayouts 结构化日志中字符串时间戳可能使用的格式
+var structuredLogTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700", // zap ISO8601
+	"2006-01-02 15:04:05.000Z0700",
+	"2006-01-02 15:04:05",
+}
+
+// structuredLogLevels 常见日志库的级别名称到统一级别的映射
+var structuredLogLevels = map[string]string{
+	"trace":     "trace",
+	"debug":     "debug",
+	"info":      "info",
+	"notice":    "info",
+	"warn":      "warn",
+	"warning":   "warn",
+	"error":     "error",
+	"err":       "error",
+	"fatal":     "fatal",
+	"panic":     "fatal",
+	"dpanic":    "fatal",
+	"critical":  "fatal",
+	"crit":      "fatal",
+	"alert":     "fatal",
+	"emerg":     "fatal",
+	"emergency": "fatal",
+}
+
+// DefaultLogFieldMapping 返回zap、logrus、bunyan/pino等常见日志库使用的字段名
+func DefaultLogFieldMapping() models.LogFieldMapping {
+	return models.LogFieldMapping{
+		Level:     []string{"level", "lvl", "severity", "levelname", "log.level"},
+		Timestamp: []string{"ts", "time", "timestamp", "@timestamp", "t"},
+		Message:   []string{"msg", "message", "@message"},
+		Error:     []string{"error", "err", "exception", "stacktrace"},
+		Status:    []string{"status", "status_code", "statusCode", "http.status_code", "http_status", "res.statusCode"},
+		Duration:  []string{"duration", "latency", "elapsed", "duration_ms", "latency_ms", "elapsed_ms", "response_time", "responseTime"},
+	}
+}
+
+// MergeLogFieldMapping 用override中非空的字段名列表替换base中对应的列表
+func MergeLogFieldMapping(base, override models.LogFieldMapping) models.LogFieldMapping {
+	if len(override.Level) > 0 {
+		base.Level = override.Level
+	}
+	if len(override.Timestamp) > 0 {
+		base.Timestamp = override.Timestamp
+	}
+	if len(override.Message) > 0 {
+		base.Message = override.Message
+	}
+	if len(override.Error) > 0 {
+		base.Error = override.Error
+	}
+	if len(override.Status) > 0 {
+		base.Status = override.Status
+	}
+	if len(override.Duration) > 0 {
+		base.Duration = override.Duration
+	}
+	return base
+}
+
+// ParseLogFieldMapping 解析工具参数中的字段映射，值为逗号分隔的字段名，例如{"level":"severity","duration":"elapsed_ms"}
+func ParseLogFieldMapping(raw map[string]string) (models.LogFieldMapping, error) {
+	var mapping models.LogFieldMapping
+	targets := map[string]*[]string{
+		"level":     &mapping.Level,
+		"timestamp": &mapping.Timestamp,
+		"message":   &mapping.Message,
+		"error":     &mapping.Error,
+		"status":    &mapping.Status,
+		"duration":  &mapping.Duration,
+	}
+	for key, value := range raw {
+		target, ok := targets[key]
+		if !ok {
+			valid := make([]string, 0, len(targets))
+			for name := range targets {
+				valid = append(valid, name)
+			}
+			sort.Strings(valid)
+			return mapping, fmt.Errorf("unknown field mapping key %q: must be one of %s", key, strings.Join(valid, ", "))
+		}
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				*target = append(*target, name)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// structuredLogFields 从一行JSON日志中提取的字段
+type structuredLogFields struct {
+	level          string
+	timestamp      time.Time
+	message        string
+	err            string
+	status         int
+	durationMillis int
+	hasDuration    bool
+}
+
+// parseJSONLogLine 将一行日志解析为JSON对象，允许行首带有kubelet添加的RFC3339时间戳，同时返回该时间戳
+func parseJSONLogLine(line string) (map[string]interface{}, time.Time, bool) {
+	var prefixTime time.Time
+	body := strings.TrimSpace(line)
+	if !strings.HasPrefix(body, "{") {
+		prefix, rest, found := strings.Cut(body, " ")
+		if !found {
+			return nil, prefixTime, false
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, prefix)
+		if err != nil {
+			return nil, prefixTime, false
+		}
+		prefixTime = parsed
+		body = strings.TrimSpace(rest)
+	}
+	if !strings.HasPrefix(body, "{") || !strings.HasSuffix(body, "}") {
+		return nil, prefixTime, false
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &entry); err != nil {
+		return nil, prefixTime, false
+	}
+	return entry, prefixTime, true
+}
+
+// extractLogFields 按字段映射从JSON日志中提取级别、时间、消息、状态码和耗时
+func (a *logAnalyzer) extractLogFields(entry map[string]interface{}) structuredLogFields {
+	var fields structuredLogFields
+	if value, _, ok := lookupLogField(entry, a.fields.Level); ok {
+		fields.level = normalizeLogLevel(value)
+	}
+	if value, _, ok := lookupLogField(entry, a.fields.Timestamp); ok {
+		fields.timestamp, _ = parseLogTimestamp(value)
+	}
+	if value, _, ok := lookupLogField(entry, a.fields.Message); ok {
+		fields.message = logFieldString(value)
+	}
+	if value, _, ok := lookupLogField(entry, a.fields.Error); ok {
+		fields.err = logFieldString(value)
+	}
+	if value, _, ok := lookupLogField(entry, a.fields.Status); ok {
+		fields.status = parseStatusCode(value)
+	}
+	if value, name, ok := lookupLogField(entry, a.fields.Duration); ok {
+		fields.durationMillis, fields.hasDuration = parseDurationMillis(name, value)
+	}
+	return fields
+}
+
+// lookupLogField 按顺序查找第一个存在的字段，返回其值和字段名；字段名不存在时按"."拆分查找嵌套对象
+func lookupLogField(entry map[string]interface{}, names []string) (interface{}, string, bool) {
+	for _, name := range names {
+		if value, ok := entry[name]; ok && value != nil {
+			return value, name, true
+		}
+		if !strings.Contains(name, ".") {
+			continue
+		}
+		var current interface{} = entry
+		for _, part := range strings.Split(name, ".") {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				current = nil
+				break
+			}
+			current = object[part]
+		}
+		if current != nil {
+			return current, name, true
+		}
+	}
+	return nil, "", false
+}
+
+// normalizeLogLevel 将字符串级别或bunyan/pino的数字级别转换为trace、debug、info、warn、error、fatal
+func normalizeLogLevel(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		switch {
+		case v >= 60:
+			return "fatal"
+		case v >= 50:
+			return "error"
+		case v >= 40:
+			return "warn"
+		case v >= 30:
+			return "info"
+		case v >= 20:
+			return "debug"
+		default:
+			return "trace"
+		}
+	case string:
+		level := strings.ToLower(strings.TrimSpace(v))
+		if normalized, ok := structuredLogLevels[level]; ok {
+			return normalized
+		}
+		return level
+	}
+	return ""
+}
+
+// parseLogTimestamp 解析字符串时间戳或Unix时间戳（按数值大小区分秒、毫秒、微秒和纳秒）
+func parseLogTimestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range structuredLogTimeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return parsed, true
+			}
+		}
+	case float64:
+		switch {
+		case v > 1e17:
+			return time.Unix(0, int64(v)).UTC(), true
+		case v > 1e14:
+			return time.UnixMicro(int64(v)).UTC(), true
+		case v > 1e11:
+			return time.UnixMilli(int64(v)).UTC(), true
+		case v > 0:
+			seconds, fraction := math.Modf(v)
+			return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseStatusCode 解析数字或字符串形式的HTTP状态码，不在100-599范围内时返回0
+func parseStatusCode(value interface{}) int {
+	var code int
+	switch v := value.(type) {
+	case float64:
+		code = int(v)
+	case string:
+		code, _ = strconv.Atoi(strings.TrimSpace(v))
+	}
+	if code < 100 || code > 599 {
+		return 0
+	}
+	return code
+}
+
+// parseDurationMillis 将耗时字段转换为毫秒
+// 字符串按Go时长格式解析（如"12.5ms"）；数字的单位由字段名后缀决定（ms、us、ns、s），
+// 没有后缀时整数视为毫秒、小数视为秒（zap默认的时长编码）
+func parseDurationMillis(name string, value interface{}) (int, bool) {
+	var number float64
+	switch v := value.(type) {
+	case string:
+		if duration, err := time.ParseDuration(strings.TrimSpace(v)); err == nil {
+			return int(duration.Milliseconds()), true
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	case float64:
+		number = v
+	default:
+		return 0, false
+	}
+	if number < 0 {
+		return 0, false
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ms"):
+		return int(number), true
+	case strings.HasSuffix(lower, "us"):
+		return int(number / 1e3), true
+	case strings.HasSuffix(lower, "ns"):
+		return int(number / 1e6), true
+	case strings.HasSuffix(lower, "_s"), strings.HasSuffix(lower, "sec"), strings.HasSuffix(lower, "seconds"):
+		return int(number * 1e3), true
+	case number != math.Trunc(number):
+		return int(number * 1e3), true
+	default:
+		return int(number), true
+	}
+}
+
+// logFieldString 将字段值转换为字符串，非字符串值使用JSON编码
+func logFieldString(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// structuredErrorMessage 组合消息和错误字段作为错误分组的键
+func structuredErrorMessage(fields structuredLogFields) string {
+	message := fields.message
+	// 堆栈等多行错误只取第一行
+	errText, _, _ := strings.Cut(fields.err, "\n")
+	switch {
+	case message != "" && errText != "":
+		message = message + ": " + errText
+	case message == "":
+		message = errText
+	}
+	if message == "" {
+		message = "(无消息)"
+	}
+	return TruncateString(message, MaxMessageLength)
+}
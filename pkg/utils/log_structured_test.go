@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+func readLogFixture(t *testing.T, name string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "logs", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestAnalyzeStructuredLogFixtures(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		fixture       string
+		errors        map[string]int
+		warnings      int
+		infos         int
+		levels        map[string]int
+		statusCodes   map[int]int
+		responseTimes []int
+		timeRange     [2]time.Time
+	}{
+		{
+			fixture:       "zap.log",
+			errors:        map[string]int{"database query failed: dial tcp 10.0.0.7:5432: connection refused": 2},
+			warnings:      1,
+			infos:         1,
+			levels:        map[string]int{"info": 1, "warn": 1, "error": 2, "debug": 1},
+			statusCodes:   map[int]int{200: 2},
+			responseTimes: []int{12, 1500},
+			timeRange:     [2]time.Time{at("2026-01-01T10:00:00.5Z"), at("2026-01-01T10:00:04Z")},
+		},
+		{
+			fixture:     "logrus.log",
+			errors:      map[string]int{"upstream failed: context deadline exceeded": 1, "shutting down": 1},
+			warnings:    1,
+			infos:       1,
+			levels:      map[string]int{"info": 1, "warn": 1, "error": 1, "fatal": 1},
+			statusCodes: map[int]int{504: 1},
+			timeRange:   [2]time.Time{at("2026-01-01T10:00:00Z"), at("2026-01-01T10:02:00Z")},
+		},
+		{
+			fixture:       "bunyan.log",
+			errors:        map[string]int{`request failed: {"message":"ECONNRESET","name":"Error"}`: 1, "out of memory": 1},
+			warnings:      1,
+			infos:         2,
+			levels:        map[string]int{"info": 2, "warn": 1, "error": 1, "fatal": 1},
+			statusCodes:   map[int]int{200: 1, 502: 1},
+			responseTimes: []int{23},
+			timeRange:     [2]time.Time{at("2026-01-01T10:00:00Z"), at("2026-01-01T10:00:04Z")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			lines := readLogFixture(t, tt.fixture)
+			result := NewLogAnalyzer().AnalyzeLogs(lines)
+
+			if result.Format() != "json" || result.StructuredLines != len(lines) {
+				t.Fatalf("format %s with %d of %d lines structured", result.Format(), result.StructuredLines, len(lines))
+			}
+			if !reflect.DeepEqual(result.TopErrors, tt.errors) {
+				t.Errorf("errors = %v, want %v", result.TopErrors, tt.errors)
+			}
+			if result.WarningCount != tt.warnings || result.InfoCount != tt.infos {
+				t.Errorf("warnings %d, infos %d; want %d, %d", result.WarningCount, result.InfoCount, tt.warnings, tt.infos)
+			}
+			if !reflect.DeepEqual(result.LogLevels, tt.levels) {
+				t.Errorf("levels = %v, want %v", result.LogLevels, tt.levels)
+			}
+			if !reflect.DeepEqual(result.StatusCodes, tt.statusCodes) {
+				t.Errorf("status codes = %v, want %v", result.StatusCodes, tt.statusCodes)
+			}
+			if !slices.Equal(result.ResponseTimes, tt.responseTimes) {
+				t.Errorf("response times = %v, want %v", result.ResponseTimes, tt.responseTimes)
+			}
+			if !result.TimeRange[0].Equal(tt.timeRange[0]) || !result.TimeRange[1].Equal(tt.timeRange[1]) {
+				t.Errorf("time range = %v, want %v", result.TimeRange, tt.timeRange)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMixedLogLines(t *testing.T) {
+	lines := []string{
+		`2026-01-01T10:00:00.000000000Z {"severity":"ERROR","message":"payment declined"}`,
+		`2026-01-01T10:00:01Z ERROR plain text failure`,
+		`{"lvl":"info","msg":"ok"}`,
+	}
+	result := NewLogAnalyzer().AnalyzeLogs(lines)
+
+	if result.Format() != "mixed" || result.StructuredLines != 2 || result.ErrorCount != 2 {
+		t.Fatalf("format %s, structured %d, errors %d; want mixed, 2, 2", result.Format(), result.StructuredLines, result.ErrorCount)
+	}
+	if result.TopErrors["payment declined"] != 1 {
+		t.Fatalf("errors = %v, want the JSON message", result.TopErrors)
+	}
+	if !result.TimeRange[0].Equal(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("kubelet timestamp prefix not used: %v", result.TimeRange)
+	}
+}
+
+func TestLogFieldMappingOverride(t *testing.T) {
+	mapping, err := ParseLogFieldMapping(map[string]string{"level": "sev", "duration": "took_us, elapsed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.LogFieldMapping{Level: []string{"sev"}, Duration: []string{"took_us", "elapsed"}}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Fatalf("mapping = %+v, want %+v", mapping, want)
+	}
+	if _, err := ParseLogFieldMapping(map[string]string{"lvl": "sev"}); err == nil {
+		t.Fatal("unknown mapping key was accepted")
+	}
+
+	result := NewLogAnalyzerWithFields("", mapping).AnalyzeLogs([]string{`{"sev":"error","msg":"disk full","took_us":2500}`})
+	if result.TopErrors["disk full"] != 1 || !reflect.DeepEqual(result.ResponseTimes, []int{2}) {
+		t.Fatalf("errors %v, response times %v; want the overridden fields", result.TopErrors, result.ResponseTimes)
+	}
+}
+
+func TestParseDurationMillis(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value any
+		want  int
+	}{
+		{name: "go duration string", field: "latency", value: "12.5ms", want: 12},
+		{name: "milliseconds suffix", field: "duration_ms", value: 250.0, want: 250},
+		{name: "microseconds suffix", field: "took_us", value: 4000.0, want: 4},
+		{name: "seconds suffix", field: "elapsed_s", value: 2.0, want: 2000},
+		{name: "zap float seconds", field: "duration", value: 0.25, want: 250},
+		{name: "integer milliseconds", field: "duration", value: 40.0, want: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := parseDurationMillis(tt.field, tt.value); !ok || got != tt.want {
+				t.Fatalf("parseDurationMillis(%q, %v) = %d, %v; want %d", tt.field, tt.value, got, ok, tt.want)
+			}
+		})
+	}
+	if _, ok := parseDurationMillis("duration", -1.0); ok {
+		t.Fatal("negative duration was accepted")
+	}
+}
@@ -1,9 +1,70 @@
 package utils
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/hsn0918/kubernetes-mcp/pkg/models"
 )
 
+// 日志的返回形式
+const (
+	// LogOutputRaw 整段日志作为一个字符串返回
+	LogOutputRaw = "raw"
+	// LogOutputLines 每条日志拆分为时间戳和内容
+	LogOutputLines = "lines"
+	// LogOutputJSONL 与lines相同，内容本身是JSON对象的日志作为对象嵌入
+	LogOutputJSONL = "jsonl"
+)
+
+// ParseLogOutputMode 校验日志的返回形式，为空时使用LogOutputRaw
+func ParseLogOutputMode(value string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(value)); mode {
+	case "":
+		return LogOutputRaw, nil
+	case LogOutputRaw, LogOutputLines, LogOutputJSONL:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unsupported outputMode %q, must be %s, %s or %s", value, LogOutputRaw, LogOutputLines, LogOutputJSONL)
+}
+
+// ParseLogEntries 将日志行拆分为条目。timestamps为true时解析kubelet在行首添加的RFC3339时间戳，没有时间戳的行照常处理；
+// 去掉时间戳后以空白开头的行视为上一条的续行（例如多行堆栈），以换行符合并到上一条。
+// mode为LogOutputJSONL时，内容本身是JSON对象的单行条目放入Object
+func ParseLogEntries(lines []string, timestamps bool, mode string) []models.LogEntry {
+	entries := make([]models.LogEntry, 0, len(lines))
+	for _, line := range lines {
+		var timestamp *time.Time
+		text := line
+		if timestamps {
+			if prefix, rest, found := strings.Cut(line, " "); found {
+				if parsed, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+					timestamp, text = &parsed, rest
+				}
+			}
+		}
+		if len(entries) > 0 && (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) {
+			last := &entries[len(entries)-1]
+			last.Line += "\n" + text
+			continue
+		}
+		entries = append(entries, models.LogEntry{Timestamp: timestamp, Line: text})
+	}
+
+	if mode == LogOutputJSONL {
+		for i := range entries {
+			body := strings.TrimSpace(entries[i].Line)
+			if strings.HasPrefix(body, "{") && !strings.Contains(body, "\n") && json.Valid([]byte(body)) {
+				entries[i].Object = json.RawMessage(body)
+				entries[i].Line = ""
+			}
+		}
+	}
+	return entries
+}
+
 // DefaultLogPattern 返回默认的日志分析模式
 func DefaultLogPattern() models.LogPattern {
 	return models.LogPattern{
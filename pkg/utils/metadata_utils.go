@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// StringMapArgument 读取值为字符串的对象参数，未提供时返回nil
+func StringMapArgument(arguments map[string]interface{}, key string) (map[string]string, error) {
+	raw, ok := arguments[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parameter %s must be an object of string values", key)
+	}
+	result := make(map[string]string, len(object))
+	for k, v := range object {
+		value, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s: value of %q must be a string", key, k)
+		}
+		result[k] = value
+	}
+	return result, nil
+}
+
+// MetadataMergePatch 构建只修改metadata.labels或metadata.annotations的JSON合并补丁
+// field为"labels"或"annotations"；remove中的键在补丁中置为null以删除，
+// 合并补丁按对象键匹配，因此"app.kubernetes.io/name"这类含斜杠的键无需转义
+func MetadataMergePatch(field string, set map[string]string, remove []string) ([]byte, error) {
+	values := make(map[string]interface{}, len(set)+len(remove))
+	for _, key := range remove {
+		values[key] = nil
+	}
+	for key, value := range set {
+		values[key] = value
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{field: values},
+	})
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// PodMetricsSample is one poll of the pod metrics API
+type PodMetricsSample struct {
+	Time time.Time
+	Pods []models.PodMetricInfo
+}
+
+// SamplePodMetrics polls the metrics of the pods matching labelSelector samples times, spread evenly over window.
+// Polls that fail after the first one are skipped; when ctx ends early the samples collected so far are returned.
+// metrics-server refreshes usage about every 15 seconds, so consecutive polls may repeat the same data point;
+// callers should deduplicate by pod and metric timestamp.
+func SamplePodMetrics(
+	ctx context.Context,
+	client kubernetes.Client,
+	namespace string,
+	labelSelector string,
+	samples int,
+	window time.Duration,
+) ([]PodMetricsSample, error) {
+	samples = max(samples, 1)
+	var interval time.Duration
+	if samples > 1 {
+		interval = window / time.Duration(samples-1)
+	}
+	progress := ProgressFromContext(ctx)
+
+	var result []PodMetricsSample
+	for i := range samples {
+		if i > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return result, nil
+			case <-timer.C:
+			}
+		}
+		pods, err := GetPodsMetrics(ctx, client, namespace, WithLabelSelector(labelSelector))
+		if err != nil {
+			if len(result) == 0 {
+				return nil, err
+			}
+			continue
+		}
+		result = append(result, PodMetricsSample{Time: time.Now(), Pods: pods})
+		progress.ReportCount(ctx, i+1, samples, fmt.Sprintf("collected metrics sample %d/%d", i+1, samples))
+	}
+	return result, nil
+}
+
+// PercentileInt64 returns the nearest-rank percentile (0-100) of values, or 0 when values is empty
+func PercentileInt64(values []int64, percentile float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestPercentileInt64(t *testing.T) {
+	values := []int64{50, 10, 40, 20, 30}
+	tests := []struct {
+		percentile float64
+		want       int64
+	}{
+		{percentile: 0, want: 10},
+		{percentile: 20, want: 10},
+		{percentile: 50, want: 30},
+		{percentile: 95, want: 50},
+		{percentile: 100, want: 50},
+	}
+	for _, tt := range tests {
+		if got := PercentileInt64(values, tt.percentile); got != tt.want {
+			t.Errorf("p%g = %d, want %d", tt.percentile, got, tt.want)
+		}
+	}
+	if values[0] != 50 {
+		t.Error("PercentileInt64 must not reorder its input")
+	}
+	if got := PercentileInt64(nil, 95); got != 0 {
+		t.Errorf("percentile of no values = %d, want 0", got)
+	}
+}
@@ -5,15 +5,29 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/hsn0918/kubernetes-mcp/pkg/client/kubernetes"
 	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
+// clusterMetricsConcurrency is the number of list calls GetClusterResourceMetrics runs at the same time
+const clusterMetricsConcurrency = 3
+
+// Unit types accepted by WithUnitType
+const (
+	// UnitTypeRaw returns numeric values with explicit units only
+	UnitTypeRaw = "raw"
+	// UnitTypeHuman also adds human-readable strings such as "2.31 cores" and "3.4 GiB"
+	UnitTypeHuman = "human"
+)
+
 // MetricsOption defines the function type for metrics query options
 type MetricsOption func(*MetricsOptions)
 
@@ -31,7 +45,7 @@ type MetricsOptions struct {
 	ResourceType string
 	// Whether to include detailed information
 	IncludeDetail bool
-	// Unit type (raw, percent, human)
+	// Unit type (raw, human)
 	UnitType string
 	// Kubernetes field selector
 	FieldSelector string
@@ -123,7 +137,7 @@ func WithIncludeDetail(include bool) MetricsOption {
 	}
 }
 
-// WithUnitType sets the unit type (e.g., raw value, percentage, etc.)
+// WithUnitType sets the unit type: raw returns numeric values only, human also adds human-readable strings
 func WithUnitType(unitType string) MetricsOption {
 	return func(options *MetricsOptions) {
 		options.UnitType = unitType
@@ -138,7 +152,7 @@ type ResourceMetricsOptions struct {
 	IncludeDetail bool
 	// Resource type (cpu, memory, storage, pods)
 	ResourceType string
-	// Unit type (raw, percent, human)
+	// Unit type (raw, human)
 	UnitType string
 }
 
@@ -342,12 +356,15 @@ func SortPodMetrics(metrics []models.PodMetricInfo, sortType models.SortType) {
 }
 
 // GetClusterResourceMetrics retrieves overall cluster resource usage
+// The node list, node metrics and pod list are fetched concurrently. Node data is skipped entirely when only pods
+// of a single namespace are requested, since the answer does not depend on it.
+// If ctx ends after the node capacity has been aggregated, the partially filled metrics are returned together with the error
 func GetClusterResourceMetrics(ctx context.Context, client kubernetes.Client, namespace string, opts ...MetricsOption) (*models.ClusterResourceMetrics, error) {
 	// Initialize default options
 	options := &MetricsOptions{
 		ResourceType:  "all",
 		IncludeDetail: true,
-		UnitType:      "raw",
+		UnitType:      UnitTypeRaw,
 	}
 
 	// Apply option functions
@@ -364,86 +381,143 @@ func GetClusterResourceMetrics(ctx context.Context, client kubernetes.Client, na
 		listOptions.LabelSelector = options.LabelSelector
 	}
 
-	// Get node list
-	nodes, err := client.ClientSet().CoreV1().Nodes().List(ctx, listOptions)
+	collectNodes := !(options.ResourceType == "pods" && namespace != "")
+	var (
+		nodes       *corev1.NodeList
+		nodeMetrics *metricsv1beta1.NodeMetricsList
+		pods        *corev1.PodList
+	)
+	// The context of the group is cancelled as soon as one fetch fails, so the others stop early
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(clusterMetricsConcurrency)
+	tasks := []func(context.Context) error{
+		func(ctx context.Context) (err error) {
+			pods, err = client.ClientSet().CoreV1().Pods(namespace).List(ctx, listOptions)
+			if err != nil && namespace != "" {
+				return fmt.Errorf("failed to get Pod list for namespace %s: %w", namespace, err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get Pod list: %w", err)
+			}
+			return nil
+		},
+	}
+	if collectNodes {
+		tasks = append(tasks,
+			func(ctx context.Context) (err error) {
+				if nodes, err = client.ClientSet().CoreV1().Nodes().List(ctx, listOptions); err != nil {
+					return fmt.Errorf("failed to get node list: %w", err)
+				}
+				return nil
+			},
+			func(ctx context.Context) (err error) {
+				if nodeMetrics, err = client.GetMetricsClient().MetricsV1beta1().NodeMetricses().List(ctx, listOptions); err != nil {
+					return fmt.Errorf("failed to get node metrics: %w", err)
+				}
+				return nil
+			},
+		)
+	}
+	for _, task := range tasks {
+		group.Go(func() error { return task(groupCtx) })
+	}
+	err := group.Wait()
+
+	metrics := &models.ClusterResourceMetrics{
+		Namespace:     namespace,
+		NodesSkipped:  !collectNodes,
+		ResourceType:  options.ResourceType,
+		IncludeDetail: options.IncludeDetail,
+		UnitType:      NormalizeUnitType(options.UnitType),
+	}
+	if nodes != nil {
+		addNodeCapacity(metrics, nodes.Items)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node list: %w", err)
+		if nodes == nil {
+			return nil, err
+		}
+		return partialMetrics(ctx, metrics), err
 	}
 
-	metrics := &models.ClusterResourceMetrics{}
+	if nodeMetrics != nil {
+		for _, metric := range nodeMetrics.Items {
+			metrics.CPUUsage += metric.Usage.Cpu().MilliValue()
+			metrics.MemoryUsage += metric.Usage.Memory().Value() / (1024 * 1024)
+			metrics.MemoryUsageBytes += metric.Usage.Memory().Value()
+		}
+	}
+	metrics.RunningPods = len(pods.Items)
+	addPodRequests(metrics, pods.Items)
 
-	// Calculate cluster total capacity and allocatable resources
-	for _, node := range nodes.Items {
+	// Calculate usage percentages
+	if metrics.CPUAllocatable > 0 {
+		metrics.CPUPercent = float64(metrics.CPUUsage) / float64(metrics.CPUAllocatable) * 100
+		metrics.CPURequestsPercent = float64(metrics.CPURequests) / float64(metrics.CPUAllocatable) * 100
+	}
+	if metrics.MemoryAllocatable > 0 {
+		metrics.MemoryPercent = float64(metrics.MemoryUsage) / float64(metrics.MemoryAllocatable) * 100
+		metrics.MemoryRequestsPercent = float64(metrics.MemoryRequestsBytes) / float64(metrics.MemoryAllocatableBytes) * 100
+	}
+	if metrics.PodCapacity > 0 {
+		metrics.PodPercent = float64(metrics.RunningPods) / float64(metrics.PodCapacity) * 100
+	}
+
+	return metrics, nil
+}
+
+// addNodeCapacity adds the capacity and allocatable resources of the nodes to the cluster totals
+func addNodeCapacity(metrics *models.ClusterResourceMetrics, nodes []corev1.Node) {
+	for _, node := range nodes {
 		metrics.CPUCapacity += node.Status.Capacity.Cpu().MilliValue()
 		metrics.CPUAllocatable += node.Status.Allocatable.Cpu().MilliValue()
 
 		metrics.MemoryCapacity += node.Status.Capacity.Memory().Value() / (1024 * 1024)
 		metrics.MemoryAllocatable += node.Status.Allocatable.Memory().Value() / (1024 * 1024)
+		metrics.MemoryCapacityBytes += node.Status.Capacity.Memory().Value()
+		metrics.MemoryAllocatableBytes += node.Status.Allocatable.Memory().Value()
 
 		// Try to get storage capacity - note that StorageEphemeral may not exist
 		storage := node.Status.Capacity.StorageEphemeral()
 		if !storage.IsZero() {
 			metrics.StorageCapacity += storage.Value() / (1024 * 1024 * 1024)
+			metrics.StorageCapacityBytes += storage.Value()
 		}
 
 		// Try to get allocatable storage - note that StorageEphemeral may not exist
 		storage = node.Status.Allocatable.StorageEphemeral()
 		if !storage.IsZero() {
 			metrics.StorageAllocatable += storage.Value() / (1024 * 1024 * 1024)
+			metrics.StorageAllocatableBytes += storage.Value()
 		}
 
 		metrics.PodCapacity += node.Status.Capacity.Pods().Value()
 	}
+}
 
-	// Get current resource usage
-	nodeMetrics, err := client.GetMetricsClient().MetricsV1beta1().NodeMetricses().List(ctx, listOptions)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get node metrics: %w", err)
-	}
-
-	for _, metric := range nodeMetrics.Items {
-		metrics.CPUUsage += metric.Usage.Cpu().MilliValue()
-		metrics.MemoryUsage += metric.Usage.Memory().Value() / (1024 * 1024)
-	}
-
-	// Calculate usage percentages
-	if metrics.CPUAllocatable > 0 {
-		metrics.CPUPercent = float64(metrics.CPUUsage) / float64(metrics.CPUAllocatable) * 100
-	}
-
-	if metrics.MemoryAllocatable > 0 {
-		metrics.MemoryPercent = float64(metrics.MemoryUsage) / float64(metrics.MemoryAllocatable) * 100
-	}
-
-	// Get current Pod count
-	var podOptions metav1.ListOptions = listOptions
-
-	if namespace != "" {
-		pods, err := client.ClientSet().CoreV1().Pods(namespace).List(ctx, podOptions)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get Pod list for namespace %s: %w", namespace, err)
-		}
-		metrics.RunningPods = len(pods.Items)
-		metrics.Namespace = namespace
-	} else {
-		pods, err := client.ClientSet().CoreV1().Pods(metav1.NamespaceAll).List(ctx, podOptions)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get Pod list: %w", err)
+// addPodRequests sums the effective CPU and memory requests and limits of pods that have not finished,
+// using the same rules as the scheduler (see PodRequests)
+func addPodRequests(metrics *models.ClusterResourceMetrics, pods []corev1.Pod) {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
 		}
-		metrics.RunningPods = len(pods.Items)
+		requests := PodRequests(pod)
+		limits := PodLimits(pod)
+		metrics.CPURequests += requests.Cpu().MilliValue()
+		metrics.MemoryRequestsBytes += requests.Memory().Value()
+		metrics.CPULimits += limits.Cpu().MilliValue()
+		metrics.MemoryLimitsBytes += limits.Memory().Value()
 	}
+}
 
-	// Calculate Pod usage percentage
-	if metrics.PodCapacity > 0 {
-		metrics.PodPercent = float64(metrics.RunningPods) / float64(metrics.PodCapacity) * 100
+// partialMetrics returns the metrics aggregated so far when ctx has ended, otherwise nil
+func partialMetrics(ctx context.Context, metrics *models.ClusterResourceMetrics) *models.ClusterResourceMetrics {
+	if ctx.Err() == nil {
+		return nil
 	}
-
-	// Set additional information
-	metrics.ResourceType = options.ResourceType
-	metrics.IncludeDetail = options.IncludeDetail
-	metrics.UnitType = options.UnitType
-
-	return metrics, nil
+	return metrics
 }
 
 // ParseSortType parses sort type from a string
@@ -490,3 +564,102 @@ func ParseQuantity(quantityStr string) (int64, error) {
 	}
 	return quantity.Value(), nil
 }
+
+// NormalizeUnitType returns UnitTypeRaw for "raw" and UnitTypeHuman for anything else
+func NormalizeUnitType(unitType string) string {
+	if strings.EqualFold(strings.TrimSpace(unitType), UnitTypeRaw) {
+		return UnitTypeRaw
+	}
+	return UnitTypeHuman
+}
+
+// NewCPUQuantity builds a CPU amount from millicores, adding a human-readable string unless unitType is raw
+func NewCPUQuantity(milli int64, unitType string) *models.CPUQuantity {
+	quantity := &models.CPUQuantity{Milli: milli}
+	if NormalizeUnitType(unitType) == UnitTypeHuman {
+		quantity.Human = FormatCPUMilli(milli)
+	}
+	return quantity
+}
+
+// NewByteQuantity builds a memory or storage amount from bytes, adding a human-readable string unless unitType is raw
+func NewByteQuantity(bytes int64, unitType string) *models.ByteQuantity {
+	quantity := &models.ByteQuantity{Bytes: bytes}
+	if NormalizeUnitType(unitType) == UnitTypeHuman {
+		quantity.Human = FormatBinaryBytes(bytes)
+	}
+	return quantity
+}
+
+// FormatCPUMilli formats millicores as "250m" below one core and as cores (e.g. "2.31 cores") otherwise
+func FormatCPUMilli(milli int64) string {
+	switch {
+	case milli < 1000:
+		return fmt.Sprintf("%dm", milli)
+	case milli == 1000:
+		return "1 core"
+	}
+	return humanize.FtoaWithDigits(float64(milli)/1000, 2) + " cores"
+}
+
+// FormatBinaryBytes formats bytes with binary units (e.g. "3.4 GiB"), negative values keep their sign
+func FormatBinaryBytes(bytes int64) string {
+	if bytes < 0 {
+		return "-" + humanize.IBytes(uint64(-bytes))
+	}
+	return humanize.IBytes(uint64(bytes))
+}
+
+// NewNodeResponse builds the node metrics response, emitting both the legacy integer fields and the unit-explicit quantities
+func NewNodeResponse(metric models.NodeMetricInfo, unitType string, location *time.Location) models.NodeResponse {
+	return models.NodeResponse{
+		Name:              metric.Name,
+		CPUUsage:          metric.CPUUsage,
+		CPUAllocatable:    metric.CPUAllocatable,
+		CPUPercent:        metric.CPUPercent,
+		MemoryUsage:       metric.MemoryUsage,
+		MemoryAllocatable: metric.MemoryAllocatable,
+		MemoryPercent:     metric.MemoryPercent,
+		Usage: models.ResourceQuantities{
+			CPU:    NewCPUQuantity(metric.CPUUsage, unitType),
+			Memory: NewByteQuantity(metric.MemoryUsageBytes, unitType),
+		},
+		Allocatable: models.ResourceQuantities{
+			CPU:    NewCPUQuantity(metric.CPUAllocatable, unitType),
+			Memory: NewByteQuantity(metric.MemoryAllocatableBytes, unitType),
+		},
+		Timestamp:  metric.Timestamp.In(location),
+		UpdatedAgo: FormatTimeAgo(metric.Timestamp),
+	}
+}
+
+// NewPodResponse builds the pod metrics response, including container metrics when includeContainers is true
+func NewPodResponse(pod models.PodMetricInfo, includeContainers bool, unitType string, location *time.Location) models.PodResponse {
+	response := models.PodResponse{
+		Name:        pod.Name,
+		Namespace:   pod.Namespace,
+		TotalCPU:    pod.TotalCPU,
+		TotalMemory: pod.TotalMemory,
+		Usage: models.ResourceQuantities{
+			CPU:    NewCPUQuantity(pod.TotalCPU, unitType),
+			Memory: NewByteQuantity(pod.TotalMemoryBytes, unitType),
+		},
+		Timestamp:  pod.Timestamp.In(location),
+		UpdatedAgo: FormatTimeAgo(pod.Timestamp),
+	}
+	if includeContainers {
+		response.Containers = make([]models.ContainerResponse, 0, len(pod.Containers))
+		for _, container := range pod.Containers {
+			response.Containers = append(response.Containers, models.ContainerResponse{
+				Name:        container.Name,
+				CPUUsage:    container.CPUUsage,
+				MemoryUsage: container.MemoryUsage,
+				Usage: models.ResourceQuantities{
+					CPU:    NewCPUQuantity(container.CPUUsage, unitType),
+					Memory: NewByteQuantity(container.MemoryUsageBytes, unitType),
+				},
+			})
+		}
+	}
+	return response
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/testutil"
+)
+
+func clusterMetricsObjects() []runtime.Object {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return []runtime.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status:     corev1.NodeStatus{Capacity: resources, Allocatable: resources},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:      "web",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&metricsv1beta1.NodeMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Usage:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+		},
+	}
+}
+
+func TestGetClusterResourceMetrics(t *testing.T) {
+	client := testutil.NewFakeClient(clusterMetricsObjects()...)
+
+	metrics, err := GetClusterResourceMetrics(context.Background(), client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.CPUAllocatable != 4000 || metrics.CPUUsage != 1000 || metrics.CPURequests != 500 || metrics.RunningPods != 1 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	if metrics.CPUPercent != 25 {
+		t.Errorf("cpu percent = %g, want 25", metrics.CPUPercent)
+	}
+}
+
+func TestGetClusterResourceMetricsSkipsNodesForNamespacedPods(t *testing.T) {
+	client := testutil.NewFakeClient(clusterMetricsObjects()...)
+
+	metrics, err := GetClusterResourceMetrics(context.Background(), client, "default", WithResourceFilter("pods"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !metrics.NodesSkipped || metrics.CPUAllocatable != 0 {
+		t.Fatalf("node data was collected: %+v", metrics)
+	}
+	for _, action := range client.FakeClientset().Actions() {
+		if action.GetResource().Resource == "nodes" {
+			t.Fatal("nodes were listed for a namespaced pod query")
+		}
+	}
+}
+
+func TestGetClusterResourceMetricsReturnsFirstError(t *testing.T) {
+	client := testutil.NewFakeClient(clusterMetricsObjects()...)
+	client.FakeClientset().PrependReactor("list", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("boom")
+	})
+
+	metrics, err := GetClusterResourceMetrics(context.Background(), client, "")
+	if err == nil || err.Error() != "failed to get Pod list: boom" {
+		t.Fatalf("err = %v, want the pod list error", err)
+	}
+	if metrics != nil {
+		t.Fatal("partial metrics must only be returned when the context ended")
+	}
+}
+
+func BenchmarkGetClusterResourceMetrics(b *testing.B) {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("16"),
+		corev1.ResourceMemory: resource.MustParse("64Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	var objects []runtime.Object
+	for i := range 30 {
+		name := fmt.Sprintf("node-%02d", i)
+		objects = append(objects,
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status:     corev1.NodeStatus{Capacity: resources, Allocatable: resources},
+			},
+			&metricsv1beta1.NodeMetrics{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Usage:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi")},
+			},
+		)
+	}
+	for i := range 3000 {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%04d", i), Namespace: fmt.Sprintf("team-%d", i%10)},
+			Spec: corev1.PodSpec{
+				NodeName: fmt.Sprintf("node-%02d", i%30),
+				Containers: []corev1.Container{{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		})
+	}
+	client := testutil.NewFakeClient(objects...)
+
+	benchmarks := []struct {
+		name      string
+		namespace string
+		opts      []MetricsOption
+	}{
+		{name: "cluster"},
+		{name: "namespaced pods", namespace: "team-1", opts: []MetricsOption{WithResourceFilter("pods")}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := GetClusterResourceMetrics(context.Background(), client, bm.namespace, bm.opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
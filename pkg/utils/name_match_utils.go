@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// 名称匹配方式，按相似度从高到低排列
+const (
+	NameMatchExact           = "exact"
+	NameMatchNormalized      = "normalized"
+	NameMatchGeneratedSuffix = "generated-suffix"
+	NameMatchPrefix          = "prefix"
+	NameMatchContains        = "contains"
+	NameMatchEditDistance    = "edit-distance"
+)
+
+// generatedSuffixAlphabet 控制器生成名称后缀使用的字符集（不含元音和易混淆字符），
+// 用于generateName随机后缀和pod-template-hash
+const generatedSuffixAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+
+// NameMatch 一个与查询名称相近的候选名称
+type NameMatch struct {
+	Name string
+	// Score 相似度，0到100，越大越相近
+	Score int
+	// Reason 匹配方式：exact、normalized、generated-suffix、prefix、contains或edit-distance
+	Reason string
+	// Distance 规范化后与查询名称的编辑距离
+	Distance int
+}
+
+// MatchNames 按相似度从高到低返回与query相近的名称，limit不大于0时不限制数量。
+// 依次尝试：完全相同；忽略大小写和分隔符后相同；去掉控制器生成的后缀（StatefulSet序号、
+// ReplicaSet哈希、generateName随机后缀）后相同；前缀；包含；编辑距离不超过查询长度的三分之一
+func MatchNames(query string, names []string, limit int) []NameMatch {
+	var matches []NameMatch
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if match, ok := MatchName(query, name); ok {
+			matches = append(matches, match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Name < b.Name
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// MatchName 判断name是否与query相近，返回匹配方式和相似度
+func MatchName(query, name string) (NameMatch, bool) {
+	q, n := normalizeName(query), normalizeName(name)
+	if q == "" || n == "" {
+		return NameMatch{}, false
+	}
+	match := NameMatch{Name: name, Distance: EditDistance(q, n)}
+
+	qBase := normalizeName(TrimGeneratedSuffix(strings.ToLower(query)))
+	nBase := normalizeName(TrimGeneratedSuffix(strings.ToLower(name)))
+	switch {
+	case name == query:
+		match.Score, match.Reason = 100, NameMatchExact
+	case q == n:
+		match.Score, match.Reason = 95, NameMatchNormalized
+	case qBase == nBase:
+		match.Score, match.Reason = 90, NameMatchGeneratedSuffix
+	case strings.HasPrefix(n, q):
+		// 查询越接近完整名称越可信
+		match.Score, match.Reason = max(85-(len(n)-len(q)), 65), NameMatchPrefix
+	case strings.HasPrefix(q, n):
+		match.Score, match.Reason = max(75-(len(q)-len(n)), 55), NameMatchPrefix
+	case len(q) >= 3 && strings.Contains(n, q):
+		match.Score, match.Reason = 60, NameMatchContains
+	default:
+		distance := min(match.Distance, EditDistance(qBase, nBase))
+		if distance > max(1, len(q)/3) {
+			return NameMatch{}, false
+		}
+		match.Distance = distance
+		match.Score, match.Reason = max(55-5*distance, 20), NameMatchEditDistance
+	}
+	return match, true
+}
+
+// TrimGeneratedSuffix 去掉名称末尾由控制器生成的后缀，最多两段：纯数字的StatefulSet序号，
+// 以及由生成字符集组成、含数字的5位随机后缀或8到10位哈希，例如"api-7f9c8d5b6-x2k4q"返回"api"
+func TrimGeneratedSuffix(name string) string {
+	for range 2 {
+		idx := strings.LastIndex(name, "-")
+		if idx <= 0 || !isGeneratedSegment(name[idx+1:]) {
+			break
+		}
+		name = name[:idx]
+	}
+	return name
+}
+
+// isGeneratedSegment 判断名称片段是否为控制器生成的序号、随机后缀或哈希
+func isGeneratedSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if strings.Trim(segment, "0123456789") == "" {
+		return true
+	}
+	if len(segment) != 5 && (len(segment) < 8 || len(segment) > 10) {
+		return false
+	}
+	// 要求含数字，避免把"pgsql"这类普通单词当作随机后缀
+	hasDigit := false
+	for _, r := range segment {
+		if !strings.ContainsRune(generatedSuffixAlphabet, r) {
+			return false
+		}
+		if r >= '0' && r <= '9' {
+			hasDigit = true
+		}
+	}
+	return hasDigit
+}
+
+// normalizeName 转换为小写并去掉'-'、'_'和'.'分隔符
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// EditDistance 返回两个字符串之间的编辑距离，插入、删除、替换以及相邻字符交换各计1，
+// 使"ngnix"与"nginx"这类手误的距离为1
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
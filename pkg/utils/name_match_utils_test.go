@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		query  string
+		name   string
+		reason string
+	}{
+		{query: "web", name: "web", reason: NameMatchExact},
+		{query: "My_App", name: "my-app", reason: NameMatchNormalized},
+		{query: "api", name: "api-7f9c8d5b6-x2k4q", reason: NameMatchGeneratedSuffix},
+		{query: "api-7f9c8d5b6-m8n2p", name: "api-7f9c8d5b6-x2k4q", reason: NameMatchGeneratedSuffix},
+		{query: "postgres-0", name: "postgres-2", reason: NameMatchGeneratedSuffix},
+		{query: "frontend", name: "frontend-canary", reason: NameMatchPrefix},
+		{query: "frontend-canary", name: "frontend", reason: NameMatchPrefix},
+		{query: "gateway", name: "public-gateway", reason: NameMatchContains},
+		{query: "ngnix", name: "nginx", reason: NameMatchEditDistance},
+		{query: "redsi", name: "redis-master-0", reason: ""},
+		{query: "web", name: "database", reason: ""},
+		{query: "ab", name: "abacus-service", reason: NameMatchPrefix},
+		{query: "ab", name: "slab", reason: ""},
+		{query: "", name: "web", reason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+"~"+tt.name, func(t *testing.T) {
+			match, ok := MatchName(tt.query, tt.name)
+			if ok != (tt.reason != "") || match.Reason != tt.reason {
+				t.Fatalf("MatchName = %+v, %v; want reason %q", match, ok, tt.reason)
+			}
+			if ok && (match.Score < 20 || match.Score > 100) {
+				t.Fatalf("score %d out of range", match.Score)
+			}
+		})
+	}
+}
+
+func TestMatchNamesRanking(t *testing.T) {
+	names := []string{"payments-api", "payment", "payments-worker-5d8f7b9c4-q7x2z", "Payments", "billing", "payment"}
+
+	var got []string
+	for _, match := range MatchNames("payments", names, 0) {
+		got = append(got, match.Name+"/"+match.Reason)
+	}
+	want := []string{
+		"Payments/" + NameMatchNormalized,
+		"payments-api/" + NameMatchPrefix,
+		"payment/" + NameMatchPrefix,
+		"payments-worker-5d8f7b9c4-q7x2z/" + NameMatchPrefix,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("matches = %v, want %v", got, want)
+	}
+	if matches := MatchNames("payments", names, 2); len(matches) != 2 {
+		t.Fatalf("limit 2 returned %d matches", len(matches))
+	}
+}
+
+func TestTrimGeneratedSuffix(t *testing.T) {
+	tests := map[string]string{
+		"api-7f9c8d5b6-x2k4q": "api",
+		"postgres-0":          "postgres",
+		"job-28391042-8kz2p":  "job",
+		"web-x2k4q":           "web",
+		"db-pgsql":            "db-pgsql",
+		"my-service":          "my-service",
+		"cache-redis-1-0":     "cache-redis",
+		"-12345":              "-12345",
+	}
+	for name, want := range tests {
+		if got := TrimGeneratedSuffix(name); got != want {
+			t.Errorf("TrimGeneratedSuffix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "nginx", b: "nginx", want: 0},
+		{a: "ngnix", b: "nginx", want: 1},
+		{a: "redis", b: "reds", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "", b: "abc", want: 3},
+	}
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want || EditDistance(tt.b, tt.a) != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// DefaultNamespacePresets 返回内置的命名空间资源模板
+func DefaultNamespacePresets() map[string]models.NamespacePreset {
+	return map[string]models.NamespacePreset{
+		"small":  newNamespacePreset("Small team or test namespace", "2", "4Gi", "4", "8Gi", "20"),
+		"medium": newNamespacePreset("Typical application namespace", "4", "8Gi", "8", "16Gi", "50"),
+		"large":  newNamespacePreset("Large or multi-service namespace", "16", "32Gi", "32", "64Gi", "200"),
+	}
+}
+
+// LoadNamespacePresets 读取YAML格式的命名空间模板文件，与内置模板合并后返回，同名模板以文件为准
+// 文件内容为模板名到模板的映射，每个模板可包含resourceQuota和limitRange两个spec
+func LoadNamespacePresets(path string) (map[string]models.NamespacePreset, error) {
+	presets := DefaultNamespacePresets()
+	if path == "" {
+		return presets, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return presets, fmt.Errorf("failed to read namespace presets %s: %w", path, err)
+	}
+	custom := make(map[string]models.NamespacePreset)
+	if err := yaml.UnmarshalStrict(data, &custom); err != nil {
+		return presets, fmt.Errorf("failed to parse namespace presets %s: %w", path, err)
+	}
+	for name, preset := range custom {
+		presets[name] = preset
+	}
+	return presets, nil
+}
+
+// newNamespacePreset 构建包含配额和容器默认值的模板
+func newNamespacePreset(description, requestsCPU, requestsMemory, limitsCPU, limitsMemory, pods string) models.NamespacePreset {
+	return models.NamespacePreset{
+		Description: description,
+		ResourceQuota: &corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse(requestsCPU),
+				corev1.ResourceRequestsMemory: resource.MustParse(requestsMemory),
+				corev1.ResourceLimitsCPU:      resource.MustParse(limitsCPU),
+				corev1.ResourceLimitsMemory:   resource.MustParse(limitsMemory),
+				corev1.ResourcePods:           resource.MustParse(pods),
+			},
+		},
+		LimitRange: &corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{
+				Type: corev1.LimitTypeContainer,
+				Default: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				DefaultRequest: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			}},
+		},
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// NodeAllocation 节点的可分配资源以及已调度到该节点的Pod请求的资源
+type NodeAllocation struct {
+	Node        *corev1.Node
+	Allocatable corev1.ResourceList
+	// Requested 节点上未结束的Pod的资源请求总和，pods资源为Pod数量
+	Requested corev1.ResourceList
+}
+
+// Free 返回节点上指定资源的剩余可分配量，节点未提供该资源时返回零
+func (a *NodeAllocation) Free(name corev1.ResourceName) resource.Quantity {
+	free, ok := a.Allocatable[name]
+	if !ok {
+		return resource.Quantity{}
+	}
+	free = free.DeepCopy()
+	if requested, ok := a.Requested[name]; ok {
+		free.Sub(requested)
+	}
+	return free
+}
+
+// PodRequests 计算Pod的有效资源请求，与调度器一致：
+// 普通容器请求之和与任一初始化容器请求取较大值，再加上Pod的overhead
+func PodRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResourceList(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResourceList(requests, pod.Spec.Overhead)
+	return requests
+}
+
+// PodLimits 计算Pod的有效资源限制，规则与PodRequests相同
+func PodLimits(pod *corev1.Pod) corev1.ResourceList {
+	limits := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResourceList(limits, container.Resources.Limits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Limits {
+			if current, ok := limits[name]; !ok || quantity.Cmp(current) > 0 {
+				limits[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResourceList(limits, pod.Spec.Overhead)
+	return limits
+}
+
+// ComputeNodeAllocations 汇总每个节点上已调度且未结束的Pod的资源请求，按节点名返回
+func ComputeNodeAllocations(nodes []corev1.Node, pods []corev1.Pod) map[string]*NodeAllocation {
+	allocations := make(map[string]*NodeAllocation, len(nodes))
+	for i := range nodes {
+		allocations[nodes[i].Name] = &NodeAllocation{
+			Node:        &nodes[i],
+			Allocatable: nodes[i].Status.Allocatable,
+			Requested:   corev1.ResourceList{},
+		}
+	}
+	podCounts := make(map[string]int64, len(nodes))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		allocation, ok := allocations[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		addResourceList(allocation.Requested, PodRequests(pod))
+		podCounts[pod.Spec.NodeName]++
+	}
+	for name, count := range podCounts {
+		allocations[name].Requested[corev1.ResourcePods] = *resource.NewQuantity(count, resource.DecimalSI)
+	}
+	return allocations
+}
+
+// FormatResourceQuantity 以便于阅读的单位格式化资源数量：CPU使用毫核，内存和存储使用Gi/Mi
+func FormatResourceQuantity(name corev1.ResourceName, quantity resource.Quantity) string {
+	switch name {
+	case corev1.ResourceCPU:
+		return fmt.Sprintf("%dm", quantity.MilliValue())
+	case corev1.ResourceMemory, corev1.ResourceEphemeralStorage, corev1.ResourceStorage:
+		bytes := float64(quantity.Value())
+		if bytes >= 1<<30 {
+			return fmt.Sprintf("%.1fGi", bytes/(1<<30))
+		}
+		return fmt.Sprintf("%.0fMi", bytes/(1<<20))
+	}
+	return quantity.String()
+}
+
+// addResourceList 将delta中的资源累加到total
+func addResourceList(total, delta corev1.ResourceList) {
+	for name, quantity := range delta {
+		if current, ok := total[name]; ok {
+			current.Add(quantity)
+			total[name] = current
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+const (
+	// DefaultMaxNotes 未配置时每个会话最多保存的笔记数量
+	DefaultMaxNotes = 50
+	// DefaultMaxNoteBytes 未配置时单条笔记的最大字节数
+	DefaultMaxNoteBytes = 4096
+	// maxNoteSessions 同时保留笔记的最大会话数，超过时移除最久未使用的会话
+	maxNoteSessions = 64
+)
+
+// NoteStoreOptions 笔记存储的容量与持久化配置
+type NoteStoreOptions struct {
+	// MaxNotes 每个会话最多保存的笔记数量，超过时移除最旧的笔记
+	MaxNotes int
+	// MaxNoteBytes 单条笔记的最大字节数
+	MaxNoteBytes int
+	// File 持久化笔记的文件路径，为空时只保存在内存中
+	File string
+}
+
+// NoteStore 按会话隔离、并发安全的笔记存储，过期或超过数量上限的笔记会被移除
+type NoteStore struct {
+	mu       sync.Mutex
+	options  NoteStoreOptions
+	sessions map[string]*noteSession
+	now      func() time.Time
+}
+
+// noteSession 一个会话的笔记
+type noteSession struct {
+	Notes    map[string]*models.SessionNote `json:"notes"`
+	LastUsed time.Time                      `json:"lastUsed"`
+}
+
+var notes = NewNoteStore(NoteStoreOptions{})
+
+// SetNoteStoreOptions 使用配置替换全局笔记存储，配置了文件时加载其中未过期的笔记，需在注册处理程序前调用
+func SetNoteStoreOptions(options NoteStoreOptions) error {
+	store := NewNoteStore(options)
+	err := store.load()
+	notes = store
+	return err
+}
+
+// Notes 返回全局笔记存储
+func Notes() *NoteStore {
+	return notes
+}
+
+// NewNoteStore 创建笔记存储，未设置的上限使用默认值
+func NewNoteStore(options NoteStoreOptions) *NoteStore {
+	if options.MaxNotes <= 0 {
+		options.MaxNotes = DefaultMaxNotes
+	}
+	if options.MaxNoteBytes <= 0 {
+		options.MaxNoteBytes = DefaultMaxNoteBytes
+	}
+	return &NoteStore{
+		options:  options,
+		sessions: make(map[string]*noteSession),
+		now:      time.Now,
+	}
+}
+
+// Limit 返回每个会话最多保存的笔记数量
+func (s *NoteStore) Limit() int {
+	return s.options.MaxNotes
+}
+
+// Save 保存或覆盖会话中的笔记，ttl为0表示不过期
+// 返回保存后的笔记、是否覆盖了同名笔记以及因超过数量上限被移除的笔记
+func (s *NoteStore) Save(session, key, text string, ttl time.Duration) (models.SessionNote, bool, []string, error) {
+	if key == "" {
+		return models.SessionNote{}, false, nil, fmt.Errorf("note key is required")
+	}
+	if len(text) > s.options.MaxNoteBytes {
+		return models.SessionNote{}, false, nil, fmt.Errorf("note is %d bytes, the limit is %d bytes", len(text), s.options.MaxNoteBytes)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	current := s.session(session, now)
+	note, replaced := current.Notes[key]
+	if !replaced {
+		note = &models.SessionNote{Key: key, CreatedAt: now}
+		current.Notes[key] = note
+	}
+	note.Text = text
+	note.UpdatedAt = now
+	note.ExpiresAt = nil
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		note.ExpiresAt = &expiresAt
+	}
+
+	// 超过数量上限时按更新时间移除最旧的笔记
+	var evicted []string
+	for len(current.Notes) > s.options.MaxNotes {
+		oldest := ""
+		for candidate, item := range current.Notes {
+			if candidate != key && (oldest == "" || item.UpdatedAt.Before(current.Notes[oldest].UpdatedAt)) {
+				oldest = candidate
+			}
+		}
+		delete(current.Notes, oldest)
+		evicted = append(evicted, oldest)
+	}
+
+	saved := *note
+	return saved, replaced, evicted, s.persist()
+}
+
+// List 返回会话中未过期的笔记，按创建时间排序
+func (s *NoteStore) List(session string) []models.SessionNote {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.session(session, s.now())
+	result := make([]models.SessionNote, 0, len(current.Notes))
+	for _, note := range current.Notes {
+		result = append(result, *note)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Delete 删除会话中的笔记，返回笔记是否存在
+func (s *NoteStore) Delete(session, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.session(session, s.now())
+	if _, ok := current.Notes[key]; !ok {
+		return false, nil
+	}
+	delete(current.Notes, key)
+	return true, s.persist()
+}
+
+// session 返回会话的笔记并移除其中已过期的笔记，会话不存在时创建；调用方需持有锁
+func (s *NoteStore) session(session string, now time.Time) *noteSession {
+	current, ok := s.sessions[session]
+	if !ok {
+		// 会话数超过上限时移除最久未使用的会话
+		if len(s.sessions) >= maxNoteSessions {
+			oldest := ""
+			for candidate, item := range s.sessions {
+				if oldest == "" || item.LastUsed.Before(s.sessions[oldest].LastUsed) {
+					oldest = candidate
+				}
+			}
+			delete(s.sessions, oldest)
+		}
+		current = &noteSession{Notes: make(map[string]*models.SessionNote)}
+		s.sessions[session] = current
+	}
+	current.LastUsed = now
+	for key, note := range current.Notes {
+		if note.ExpiresAt != nil && !now.Before(*note.ExpiresAt) {
+			delete(current.Notes, key)
+		}
+	}
+	return current
+}
+
+// persist 将所有会话的笔记写入配置的文件，先写临时文件再重命名以免留下不完整的文件；调用方需持有锁
+func (s *NoteStore) persist() error {
+	if s.options.File == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.sessions)
+	if err != nil {
+		return fmt.Errorf("failed to encode notes: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(s.options.File), ".notes-*")
+	if err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), s.options.File); err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	return nil
+}
+
+// load 从配置的文件加载笔记，文件不存在时不报错
+func (s *NoteStore) load() error {
+	if s.options.File == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.options.File)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read notes file: %w", err)
+	}
+	sessions := make(map[string]*noteSession)
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return fmt.Errorf("failed to parse notes file %s: %w", s.options.File, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, loaded := range sessions {
+		if loaded == nil || loaded.Notes == nil {
+			continue
+		}
+		s.sessions[name] = loaded
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// newTestNoteStore 返回使用可控时钟的笔记存储，advance将时钟向前推进
+func newTestNoteStore(options NoteStoreOptions) (*NoteStore, func(time.Duration)) {
+	store := NewNoteStore(options)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	return store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func noteKeys(notes []models.SessionNote) []string {
+	keys := make([]string, 0, len(notes))
+	for _, note := range notes {
+		keys = append(keys, note.Key)
+	}
+	return keys
+}
+
+func TestNoteStoreSaveAndList(t *testing.T) {
+	store, advance := newTestNoteStore(NoteStoreOptions{})
+
+	for _, key := range []string{"suspect", "plan"} {
+		if _, replaced, _, err := store.Save("a", key, "text", 0); err != nil || replaced {
+			t.Fatalf("save %s: replaced %v, error %v", key, replaced, err)
+		}
+		advance(time.Second)
+	}
+	note, replaced, _, err := store.Save("a", "suspect", "OOMKilled", 0)
+	if err != nil || !replaced {
+		t.Fatalf("overwrite: replaced %v, error %v", replaced, err)
+	}
+	if note.Text != "OOMKilled" || !note.UpdatedAt.After(note.CreatedAt) {
+		t.Fatalf("overwritten note = %+v, want new text and a later update time", note)
+	}
+
+	if keys := noteKeys(store.List("a")); !slices.Equal(keys, []string{"suspect", "plan"}) {
+		t.Fatalf("notes = %v, want creation order", keys)
+	}
+	if notes := store.List("b"); len(notes) != 0 {
+		t.Fatalf("other session sees %v", noteKeys(notes))
+	}
+
+	if deleted, err := store.Delete("a", "plan"); err != nil || !deleted {
+		t.Fatalf("delete: deleted %v, error %v", deleted, err)
+	}
+	if deleted, _ := store.Delete("a", "plan"); deleted {
+		t.Fatal("deleted a missing note")
+	}
+}
+
+func TestNoteStoreLimits(t *testing.T) {
+	store, advance := newTestNoteStore(NoteStoreOptions{MaxNotes: 2, MaxNoteBytes: 8})
+
+	if _, _, _, err := store.Save("a", "", "text", 0); err == nil {
+		t.Fatal("saved a note without a key")
+	}
+	if _, _, _, err := store.Save("a", "long", strings.Repeat("x", 9), 0); err == nil {
+		t.Fatal("saved a note over the size limit")
+	}
+
+	var evicted []string
+	for _, key := range []string{"one", "two", "three"} {
+		_, _, evicted, _ = store.Save("a", key, key, 0)
+		advance(time.Second)
+	}
+	if !slices.Equal(evicted, []string{"one"}) {
+		t.Fatalf("evicted %v, want the oldest note", evicted)
+	}
+	if keys := noteKeys(store.List("a")); !slices.Equal(keys, []string{"two", "three"}) {
+		t.Fatalf("notes = %v", keys)
+	}
+}
+
+func TestNoteStoreExpiry(t *testing.T) {
+	store, advance := newTestNoteStore(NoteStoreOptions{})
+
+	store.Save("a", "temporary", "text", time.Minute)
+	store.Save("a", "permanent", "text", 0)
+	advance(59 * time.Second)
+	if keys := noteKeys(store.List("a")); len(keys) != 2 {
+		t.Fatalf("notes before expiry = %v", keys)
+	}
+	advance(time.Second)
+	if keys := noteKeys(store.List("a")); !slices.Equal(keys, []string{"permanent"}) {
+		t.Fatalf("notes after expiry = %v", keys)
+	}
+}
+
+func TestNoteStorePersistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.json")
+	store := NewNoteStore(NoteStoreOptions{File: file})
+	if _, _, _, err := store.Save("a", "suspect", "OOMKilled", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewNoteStore(NoteStoreOptions{File: file})
+	if err := reloaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if notes := reloaded.List("a"); len(notes) != 1 || notes[0].Text != "OOMKilled" {
+		t.Fatalf("reloaded notes = %+v", notes)
+	}
+
+	if err := NewNoteStore(NoteStoreOptions{File: filepath.Join(t.TempDir(), "missing.json")}).load(); err != nil {
+		t.Fatalf("missing notes file: %v", err)
+	}
+}
+
+func TestNoteStoreConcurrentSessions(t *testing.T) {
+	store := NewNoteStore(NoteStoreOptions{})
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session := fmt.Sprintf("session-%d", i)
+			for j := range 10 {
+				store.Save(session, fmt.Sprintf("note-%d", j), "text", 0)
+				store.List(session)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 8 {
+		if notes := store.List(fmt.Sprintf("session-%d", i)); len(notes) != 10 {
+			t.Fatalf("session-%d has %d notes, want 10", i, len(notes))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// 进度通知的发送间隔
+const (
+	// progressMinInterval 状态变化时两次通知之间的最小间隔，避免状态频繁变化时刷屏
+	progressMinInterval = time.Second
+	// progressHeartbeatInterval 状态没有变化时重复发送通知的间隔，让客户端知道操作仍在进行
+	progressHeartbeatInterval = 10 * time.Second
+)
+
+// progressReporterKey 在上下文中保存本次调用进度汇报器的键
+type progressReporterKey struct{}
+
+// ProgressNotifier 向客户端发送一条进度通知，total为0表示总量未知
+type ProgressNotifier interface {
+	NotifyProgress(ctx context.Context, token mcp.ProgressToken, progress, total float64, message string) error
+}
+
+// ProgressReporter 汇报一次工具调用的进度，由中间件在客户端提供progressToken时创建并放入上下文。
+// 方法对nil接收者安全，等待类的辅助函数可以直接调用，客户端未请求进度时什么也不做
+type ProgressReporter struct {
+	notifier ProgressNotifier
+	token    mcp.ProgressToken
+	now      func() time.Time
+
+	mu       sync.Mutex
+	progress float64
+	total    float64
+	message  string
+	sentAt   time.Time
+	done     bool
+}
+
+// NewProgressReporter 创建进度汇报器，notifier或token为nil时返回nil
+func NewProgressReporter(notifier ProgressNotifier, token mcp.ProgressToken) *ProgressReporter {
+	if notifier == nil || token == nil {
+		return nil
+	}
+	return &ProgressReporter{notifier: notifier, token: token, now: time.Now}
+}
+
+// ContextWithProgress 将进度汇报器放入上下文
+func ContextWithProgress(ctx context.Context, reporter *ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, reporter)
+}
+
+// ProgressFromContext 返回上下文中的进度汇报器，客户端未请求进度时返回nil
+func ProgressFromContext(ctx context.Context) *ProgressReporter {
+	reporter, _ := ctx.Value(progressReporterKey{}).(*ProgressReporter)
+	return reporter
+}
+
+// Report 汇报总量未知的进度，例如等待条件时的最新状态，progress按发送次数递增
+func (r *ProgressReporter) Report(ctx context.Context, message string) {
+	r.ReportCount(ctx, 0, 0, message)
+}
+
+// ReportCount 汇报已完成current个、共total个的进度，例如"evicted 12/30 pods"，total为0表示总量未知。
+// 状态变化时最多每秒发送一次，状态不变时每10秒发送一次心跳；发送的progress不会减小
+func (r *ProgressReporter) ReportCount(ctx context.Context, current, total int, message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	elapsed := r.now().Sub(r.sentAt)
+	if message == r.message && elapsed < progressHeartbeatInterval || elapsed < progressMinInterval {
+		return
+	}
+	r.send(ctx, current, total, message)
+}
+
+// Done 发送最后一条通知，不受发送间隔限制，progress等于total使客户端显示为结束；之后的汇报被忽略。
+// 操作成功、失败或超时都应调用，message说明结果
+func (r *ProgressReporter) Done(ctx context.Context, message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.done = true
+	progress := r.progress + 1
+	if r.total > 0 {
+		progress = max(r.total, r.progress)
+	}
+	_ = r.notifier.NotifyProgress(ctx, r.token, progress, progress, message)
+}
+
+// send 在持有锁时发送通知并记录发送的状态
+func (r *ProgressReporter) send(ctx context.Context, current, total int, message string) {
+	progress := r.progress + 1
+	if total > 0 {
+		progress = max(float64(current), r.progress)
+	}
+	r.progress, r.total, r.message, r.sentAt = progress, float64(total), message, r.now()
+	_ = r.notifier.NotifyProgress(ctx, r.token, progress, r.total, message)
+}
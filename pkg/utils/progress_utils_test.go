@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// progressNotification 模拟通知器记录的一条进度通知
+type progressNotification struct {
+	progress, total float64
+	message         string
+}
+
+// mockNotifier 记录发送的进度通知
+type mockNotifier struct {
+	mu            sync.Mutex
+	notifications []progressNotification
+}
+
+func (n *mockNotifier) NotifyProgress(_ context.Context, token mcp.ProgressToken, progress, total float64, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, progressNotification{progress: progress, total: total, message: message})
+	return nil
+}
+
+func (n *mockNotifier) sent() []progressNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]progressNotification(nil), n.notifications...)
+}
+
+// newTestProgressReporter 返回使用可控时钟的进度汇报器，advance将时钟向前推进
+func newTestProgressReporter() (*ProgressReporter, *mockNotifier, func(time.Duration)) {
+	notifier := &mockNotifier{}
+	reporter := NewProgressReporter(notifier, mcp.ProgressToken("token"))
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reporter.now = func() time.Time { return now }
+	return reporter, notifier, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestProgressReporterCadence(t *testing.T) {
+	ctx := context.Background()
+	reporter, notifier, advance := newTestProgressReporter()
+
+	steps := []struct {
+		after   time.Duration
+		message string
+		sent    bool
+	}{
+		{message: "0 of 3 ready", sent: true},
+		{after: 500 * time.Millisecond, message: "1 of 3 ready"},
+		{after: 500 * time.Millisecond, message: "1 of 3 ready", sent: true},
+		{after: 5 * time.Second, message: "1 of 3 ready"},
+		{after: 5 * time.Second, message: "1 of 3 ready", sent: true},
+		{after: time.Second, message: "2 of 3 ready", sent: true},
+	}
+	want := 0
+	for i, step := range steps {
+		advance(step.after)
+		reporter.Report(ctx, step.message)
+		if step.sent {
+			want++
+		}
+		if sent := notifier.sent(); len(sent) != want {
+			t.Fatalf("step %d (%q after %s): %d notifications, want %d", i, step.message, step.after, len(sent), want)
+		}
+	}
+
+	reporter.Done(ctx, "condition met")
+	reporter.Report(ctx, "late report")
+	reporter.Done(ctx, "done again")
+
+	sent := notifier.sent()
+	if len(sent) != want+1 {
+		t.Fatalf("notifications after Done = %+v, want exactly one final message", sent)
+	}
+	for i, notification := range sent {
+		if notification.progress != float64(i+1) {
+			t.Errorf("notification %d progress = %v, want %d", i, notification.progress, i+1)
+		}
+	}
+	final := sent[len(sent)-1]
+	if final.message != "condition met" || final.progress != final.total {
+		t.Fatalf("final notification = %+v, want progress equal to total", final)
+	}
+}
+
+func TestProgressReporterCounts(t *testing.T) {
+	ctx := context.Background()
+	reporter, notifier, advance := newTestProgressReporter()
+
+	reporter.ReportCount(ctx, 3, 5, "evicted 3/5 pods")
+	advance(time.Second)
+	reporter.ReportCount(ctx, 2, 5, "evicted 2/5 pods")
+	reporter.Done(ctx, "drained")
+
+	want := []progressNotification{
+		{progress: 3, total: 5, message: "evicted 3/5 pods"},
+		{progress: 3, total: 5, message: "evicted 2/5 pods"},
+		{progress: 5, total: 5, message: "drained"},
+	}
+	sent := notifier.sent()
+	if len(sent) != len(want) {
+		t.Fatalf("notifications = %+v, want %+v", sent, want)
+	}
+	for i := range want {
+		if sent[i] != want[i] {
+			t.Errorf("notification %d = %+v, want %+v", i, sent[i], want[i])
+		}
+	}
+}
+
+func TestProgressReporterWithoutToken(t *testing.T) {
+	ctx := context.Background()
+	if reporter := NewProgressReporter(&mockNotifier{}, nil); reporter != nil {
+		t.Fatal("reporter created without a progress token")
+	}
+	reporter := ProgressFromContext(ctx)
+	if reporter != nil {
+		t.Fatal("reporter found in a context without one")
+	}
+	// nil汇报器的方法什么也不做
+	reporter.Report(ctx, "ignored")
+	reporter.ReportCount(ctx, 1, 2, "ignored")
+	reporter.Done(ctx, "ignored")
+}
+
+func TestWaitForReportsProgress(t *testing.T) {
+	configMap := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "settings", "namespace": "default"},
+	}}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), configMap)
+	resource := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace("default")
+
+	reporter, notifier, _ := newTestProgressReporter()
+	ctx := ContextWithProgress(context.Background(), reporter)
+	condition := func(obj *unstructured.Unstructured) (bool, string, error) {
+		return obj != nil, "settings exists", nil
+	}
+	outcome, err := WaitFor(ctx, time.Minute, resource, "settings", condition)
+	if err != nil || !outcome.Met {
+		t.Fatalf("outcome = %+v, error %v; want condition met", outcome, err)
+	}
+
+	sent := notifier.sent()
+	if len(sent) != 2 || sent[0].message != "settings exists" {
+		t.Fatalf("notifications = %+v, want the status then the final message", sent)
+	}
+	if final := sent[1]; final.message != "condition met: settings exists" || final.progress != final.total {
+		t.Fatalf("final notification = %+v", final)
+	}
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// LastAppliedConfigAnnotation kubectl apply 记录的上次应用配置注解，其中可能包含Secret明文
+const LastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+const (
+	// 指纹占位值中保留的SHA256十六进制字符数
+	fingerprintLength = 16
+	// 脱敏占位值的前缀
+	redactedPrefix = "<redacted"
+	// 大值摘要占位值的前缀
+	summarizedPrefix = "<summarized"
+)
+
+const (
+	// SummarizedKeysAnnotation 列出被摘要替换的键，提醒对象中的占位值不是真实内容
+	SummarizedKeysAnnotation = "kubernetes-mcp/summarized-keys"
+	// DefaultMaxConfigValueBytes ConfigMap和Secret中单个值的默认大小上限
+	DefaultMaxConfigValueBytes = 16 * 1024
+)
+
+// RedactSecret 将Secret对象中的data和stringData值替换为长度说明，并移除可能包含明文的注解
+// 非Secret对象保持不变，返回值表示是否进行了脱敏
+func RedactSecret(obj *unstructured.Unstructured) bool {
+	return replaceSecretValues(obj, func(value []byte) string {
+		return RedactValue(string(value))
+	})
+}
+
+// FingerprintSecret 将Secret对象中的值替换为长度和SHA256指纹，用于在不暴露值的情况下比较两个Secret
+// 非Secret对象保持不变，返回值表示是否进行了替换
+func FingerprintSecret(obj *unstructured.Unstructured) bool {
+	return replaceSecretValues(obj, func(value []byte) string {
+		sum := sha256.Sum256(value)
+		return fmt.Sprintf("%s: %d bytes, sha256:%s>", redactedPrefix, len(value), hex.EncodeToString(sum[:])[:fingerprintLength])
+	})
+}
+
+// replaceSecretValues 使用replace的结果替换Secret中的所有值，data中的值先进行base64解码
+func replaceSecretValues(obj *unstructured.Unstructured, replace func(value []byte) string) bool {
+	if obj.GetKind() != "Secret" || obj.GroupVersionKind().Group != "" {
+		return false
+	}
+
+	if data, found, _ := unstructured.NestedMap(obj.Object, "data"); found {
+		for key, value := range data {
+			encoded, _ := value.(string)
+			raw := []byte(encoded)
+			if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+				raw = decoded
+			}
+			data[key] = replace(raw)
+		}
+		_ = unstructured.SetNestedMap(obj.Object, data, "data")
+	}
+
+	if stringData, found, _ := unstructured.NestedMap(obj.Object, "stringData"); found {
+		for key, value := range stringData {
+			plain, _ := value.(string)
+			stringData[key] = replace([]byte(plain))
+		}
+		_ = unstructured.SetNestedMap(obj.Object, stringData, "stringData")
+	}
+
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[LastAppliedConfigAnnotation]; ok {
+			annotations[LastAppliedConfigAnnotation] = redactedPrefix + ">"
+			obj.SetAnnotations(annotations)
+		}
+	}
+	return true
+}
+
+// IsRedactedSecret 判断Secret对象中是否包含脱敏占位值，这样的对象不能直接应用回集群
+func IsRedactedSecret(obj *unstructured.Unstructured) bool {
+	if obj.GetKind() != "Secret" || obj.GroupVersionKind().Group != "" {
+		return false
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, _, _ := unstructured.NestedMap(obj.Object, field)
+		for _, value := range values {
+			if text, ok := value.(string); ok && strings.HasPrefix(text, redactedPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sensitiveNameFragments 名称中包含这些片段的环境变量视为敏感值
+var sensitiveNameFragments = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "CREDENTIAL", "PRIVATE", "APIKEY", "API_KEY", "ACCESS_KEY"}
+
+// IsSensitiveName 根据名称判断环境变量等键值是否可能包含敏感信息
+func IsSensitiveName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, fragment := range sensitiveNameFragments {
+		if strings.Contains(upper, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactValue 将敏感值替换为长度说明
+func RedactValue(value string) string {
+	return fmt.Sprintf("%s: %d bytes>", redactedPrefix, len(value))
+}
+
+// SummarizeConfigData 将ConfigMap和Secret中超过maxValueBytes的值替换为包含长度和SHA256指纹的占位值，
+// binaryData中的值总是被替换；fullKeys中的键保留原值。超长的last-applied-configuration注解同样被替换。
+// 被替换的字段（如"data.bundle.js"）记录在SummarizedKeysAnnotation注解中并返回，非这两种类型的对象保持不变
+func SummarizeConfigData(obj *unstructured.Unstructured, maxValueBytes int, fullKeys []string) []string {
+	kind := obj.GetKind()
+	if (kind != "ConfigMap" && kind != "Secret") || obj.GroupVersionKind().Group != "" {
+		return nil
+	}
+	full := make(map[string]bool, len(fullKeys))
+	for _, key := range fullKeys {
+		full[key] = true
+	}
+
+	var summarized []string
+	for _, field := range []string{"data", "binaryData", "stringData"} {
+		values, found, _ := unstructured.NestedMap(obj.Object, field)
+		if !found {
+			continue
+		}
+		changed := false
+		for key, value := range values {
+			text, _ := value.(string)
+			if full[key] {
+				continue
+			}
+			// binaryData和Secret的data是base64编码，按解码后的内容计算长度和指纹
+			raw := []byte(text)
+			if field == "binaryData" || (kind == "Secret" && field == "data") {
+				if decoded, err := base64.StdEncoding.DecodeString(text); err == nil {
+					raw = decoded
+				}
+			}
+			if field != "binaryData" && len(raw) <= maxValueBytes {
+				continue
+			}
+			if strings.HasPrefix(text, redactedPrefix) {
+				continue
+			}
+			values[key] = summarizedValue(raw)
+			summarized = append(summarized, field+"."+key)
+			changed = true
+		}
+		if changed {
+			_ = unstructured.SetNestedMap(obj.Object, values, field)
+		}
+	}
+
+	annotations := obj.GetAnnotations()
+	if applied, ok := annotations[LastAppliedConfigAnnotation]; ok && len(applied) > maxValueBytes {
+		annotations[LastAppliedConfigAnnotation] = summarizedValue([]byte(applied))
+		summarized = append(summarized, "metadata.annotations."+LastAppliedConfigAnnotation)
+	}
+	if len(summarized) == 0 {
+		return nil
+	}
+	sort.Strings(summarized)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[SummarizedKeysAnnotation] = strings.Join(summarized, ",")
+	obj.SetAnnotations(annotations)
+	return summarized
+}
+
+// IsSummarizedConfigData 判断对象中是否有被摘要替换的值，这样的对象不能直接应用回集群
+func IsSummarizedConfigData(obj *unstructured.Unstructured) bool {
+	_, ok := obj.GetAnnotations()[SummarizedKeysAnnotation]
+	return ok
+}
+
+// summarizedValue 返回包含长度和SHA256指纹的摘要占位值
+func summarizedValue(value []byte) string {
+	sum := sha256.Sum256(value)
+	return fmt.Sprintf("%s: %d bytes, sha256:%s, not the real value; pass its key in fullKeys to retrieve it>",
+		summarizedPrefix, len(value), hex.EncodeToString(sum[:])[:fingerprintLength])
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func secretObject(apiVersion string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name": "db",
+			"annotations": map[string]any{
+				LastAppliedConfigAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`,
+				"owner":                     "team-a",
+			},
+		},
+		"data":       map[string]any{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))},
+		"stringData": map[string]any{"token": "abc"},
+	}}
+}
+
+func TestRedactSecret(t *testing.T) {
+	obj := secretObject("v1")
+	if !RedactSecret(obj) {
+		t.Fatal("Secret was not redacted")
+	}
+
+	want := map[string]string{
+		"data.password":    "<redacted: 7 bytes>",
+		"stringData.token": "<redacted: 3 bytes>",
+	}
+	for path, value := range want {
+		got, _, _ := unstructured.NestedString(obj.Object, strings.Split(path, ".")...)
+		if got != value {
+			t.Errorf("%s = %q, want %q", path, got, value)
+		}
+	}
+	annotations := obj.GetAnnotations()
+	if annotations[LastAppliedConfigAnnotation] != "<redacted>" || annotations["owner"] != "team-a" {
+		t.Errorf("annotations = %v, want only the last-applied configuration redacted", annotations)
+	}
+	if !IsRedactedSecret(obj) {
+		t.Error("redacted Secret is not recognized as redacted")
+	}
+}
+
+func TestRedactSecretIgnoresOtherKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+	}{
+		{name: "secret in another group", obj: secretObject("example.com/v1")},
+		{name: "configmap", obj: &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"data":       map[string]any{"password": "visible"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.obj.DeepCopy()
+			if RedactSecret(tt.obj) || FingerprintSecret(tt.obj) {
+				t.Fatal("object was treated as a Secret")
+			}
+			if !reflect.DeepEqual(before.Object, tt.obj.Object) {
+				t.Fatalf("object changed: %v", tt.obj.Object)
+			}
+			if IsRedactedSecret(tt.obj) {
+				t.Fatal("object is reported as a redacted Secret")
+			}
+		})
+	}
+}
+
+func TestFingerprintSecret(t *testing.T) {
+	first, second := secretObject("v1"), secretObject("v1")
+	FingerprintSecret(first)
+	FingerprintSecret(second)
+
+	password, _, _ := unstructured.NestedString(first.Object, "data", "password")
+	if !strings.HasPrefix(password, "<redacted: 7 bytes, sha256:") || strings.Contains(password, "hunter2") {
+		t.Fatalf("fingerprint = %q", password)
+	}
+	other, _, _ := unstructured.NestedString(second.Object, "data", "password")
+	if password != other {
+		t.Fatalf("fingerprints of equal values differ: %q and %q", password, other)
+	}
+	if !IsRedactedSecret(first) {
+		t.Error("fingerprinted Secret is not recognized as redacted")
+	}
+}
+
+func TestIsSensitiveName(t *testing.T) {
+	tests := map[string]bool{
+		"DB_PASSWORD":     true,
+		"github_token":    true,
+		"AWS_ACCESS_KEY":  true,
+		"ClientSecret":    true,
+		"LOG_LEVEL":       false,
+		"DATABASE_HOST":   false,
+		"STRIPE_API_KEY":  true,
+		"PRIVATE_KEY_PEM": true,
+	}
+	for name, sensitive := range tests {
+		if got := IsSensitiveName(name); got != sensitive {
+			t.Errorf("IsSensitiveName(%q) = %v, want %v", name, got, sensitive)
+		}
+	}
+}
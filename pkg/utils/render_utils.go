@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"sigs.k8s.io/yaml"
+)
+
+// 工具响应支持的输出格式
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+	FormatYAML = "yaml"
+	// DefaultFormat 未指定format参数时使用的输出格式
+	DefaultFormat = FormatJSON
+)
+
+// TextRenderer 由需要自定义文本格式的响应模型实现
+// 未实现该接口的模型在text格式下以YAML呈现
+type TextRenderer interface {
+	RenderText() string
+}
+
+// WithFormat 返回所有工具共用的format参数定义
+func WithFormat() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("响应的输出格式：\n- json：结构化JSON（默认）\n- text：便于阅读的文本\n- yaml：YAML格式"),
+		mcp.DefaultString(DefaultFormat),
+		mcp.Enum(FormatJSON, FormatText, FormatYAML),
+	)
+}
+
+// ParseFormat 从请求参数中读取输出格式，未指定时返回默认格式
+func ParseFormat(request mcp.CallToolRequest) (string, error) {
+	format, _ := request.GetArguments()["format"].(string)
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		return DefaultFormat, nil
+	case FormatJSON, FormatText, FormatYAML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, must be one of: %s, %s, %s", format, FormatJSON, FormatText, FormatYAML)
+	}
+}
+
+// Render 按指定格式序列化响应模型
+func Render(v any, format string) (string, error) {
+	switch format {
+	case FormatText:
+		if renderer, ok := v.(TextRenderer); ok {
+			return renderer.RenderText(), nil
+		}
+		return renderYAML(v)
+	case FormatYAML:
+		return renderYAML(v)
+	default:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("JSON序列化失败: %w", err)
+		}
+		return string(data), nil
+	}
+}
+
+// RenderResult 按请求中的format参数渲染响应模型并构造工具结果
+// 结果超过服务器输出上限时按内容截断，并在结果中说明省略的内容
+func RenderResult(request mcp.CallToolRequest, v any) *mcp.CallToolResult {
+	format, err := ParseFormat(request)
+	if err != nil {
+		return NewErrorToolResult(err.Error())
+	}
+
+	text, err := Render(v, format)
+	if err != nil {
+		return NewErrorToolResult(err.Error())
+	}
+	text, err = truncateResultText(v, format, text)
+	if err != nil {
+		return NewErrorToolResult(err.Error())
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}
+}
+
+// renderYAML 经由JSON标签将响应模型序列化为YAML，保证字段名与JSON格式一致
+func renderYAML(v any) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("YAML序列化失败: %w", err)
+	}
+	return string(data), nil
+}
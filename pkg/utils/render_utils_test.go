@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type plainResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type textResponse struct {
+	Name string `json:"name"`
+}
+
+func (r textResponse) RenderText() string {
+	return "Name: " + r.Name
+}
+
+func renderRequest(format string) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	if format != "" {
+		request.Params.Arguments = map[string]any{"format": format}
+	}
+	return request
+}
+
+func TestRenderResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		value   any
+		want    string
+		invalid bool
+	}{
+		{name: "default json", value: plainResponse{Name: "web", Count: 2}, want: "{\n  \"name\": \"web\",\n  \"count\": 2\n}"},
+		{name: "yaml uses json names", format: "yaml", value: plainResponse{Name: "web"}, want: "name: web\n"},
+		{name: "text renderer", format: "text", value: textResponse{Name: "web"}, want: "Name: web"},
+		{name: "text falls back to yaml", format: "Text", value: plainResponse{Name: "web", Count: 1}, want: "count: 1\nname: web\n"},
+		{name: "unsupported format", format: "xml", value: plainResponse{Name: "web"}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderResult(renderRequest(tt.format), tt.value)
+			if result.IsError != tt.invalid {
+				t.Fatalf("IsError = %v, want %v", result.IsError, tt.invalid)
+			}
+			if tt.invalid {
+				return
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; text != tt.want {
+				t.Fatalf("rendered %q, want %q", text, tt.want)
+			}
+		})
+	}
+}
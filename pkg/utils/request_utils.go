@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// requestIDMetaKey 结果_meta中保存请求ID的字段
+const requestIDMetaKey = "requestId"
+
+// requestIDKey 在上下文中保存请求ID的键
+type requestIDKey struct{}
+
+// NewRequestID 生成用于关联工具调用与服务器日志的请求ID
+func NewRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// ContextWithRequestID 返回携带请求ID的上下文
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 返回上下文中的请求ID，未设置时返回空字符串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestIDMeta 将请求ID写入结果的_meta字段
+func WithRequestIDMeta(result *mcp.CallToolResult, id string) *mcp.CallToolResult {
+	if id == "" {
+		return result
+	}
+	return setMetaField(result, requestIDMetaKey, id)
+}
+
+// setMetaField 在结果的_meta中设置字段，按需创建_meta
+func setMetaField(result *mcp.CallToolResult, key string, value any) *mcp.CallToolResult {
+	if result == nil {
+		return result
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[key] = value
+	return result
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RetryOptions 重试次数与退避时间配置
+type RetryOptions struct {
+	// MaxRetries 首次调用失败后的最大重试次数，0表示不重试
+	MaxRetries int
+	// InitialBackoff 第一次重试前的等待时间，之后每次翻倍
+	InitialBackoff time.Duration
+	// MaxBackoff 单次等待时间的上限
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryOptions 默认的重试配置
+var DefaultRetryOptions = RetryOptions{
+	MaxRetries:     3,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// transientMessages etcd等后端返回的暂时性错误消息片段
+var transientMessages = []string{
+	"etcdserver: leader changed",
+	"etcdserver: request timed out",
+	"etcdserver: too many requests",
+}
+
+// IsTransientError 判断错误是否为可重试的暂时性API错误，例如限流、超时和etcd主节点切换
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	if _, ok := apierrors.SuggestsClientDelay(err); ok {
+		return true
+	}
+	message := err.Error()
+	for _, fragment := range transientMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryOnTransient 执行fn，遇到暂时性错误时按指数退避重试，返回重试次数和最后一次的错误
+// 服务端通过Retry-After建议等待时间时优先使用该值；ctx的截止时间不足以等待下一次重试时直接返回
+func RetryOnTransient(ctx context.Context, opts RetryOptions, fn func() error) (int, error) {
+	return retry(ctx, opts, IsTransientError, fn)
+}
+
+// RetryOnConflict 执行fn，遇到资源版本冲突时按指数退避重试，fn需要在每次调用时重新获取最新对象
+func RetryOnConflict(ctx context.Context, opts RetryOptions, fn func() error) (int, error) {
+	return retry(ctx, opts, apierrors.IsConflict, fn)
+}
+
+// WithRetryMeta 在发生过重试时将重试次数写入结果的_meta字段
+func WithRetryMeta(result *mcp.CallToolResult, retries int) *mcp.CallToolResult {
+	if retries == 0 {
+		return result
+	}
+	return setMetaField(result, "retries", retries)
+}
+
+// retry 按配置重试fn，直到成功、错误不可重试、达到最大次数或ctx结束
+func retry(ctx context.Context, opts RetryOptions, retriable func(error) bool, fn func() error) (int, error) {
+	backoff := opts.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !retriable(err) || attempt >= opts.MaxRetries {
+			return attempt, err
+		}
+
+		delay := backoff
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+		if opts.MaxBackoff > 0 && delay > opts.MaxBackoff {
+			delay = opts.MaxBackoff
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if opts.MaxBackoff > 0 && backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// RolloutStatus 按kubectl rollout status的规则判断Deployment、StatefulSet或DaemonSet的滚动更新是否完成
+// 返回是否完成和当前进度的说明；不支持滚动更新的资源类型返回错误
+func RolloutStatus(obj *unstructured.Unstructured) (bool, string, error) {
+	generation := obj.GetGeneration()
+	observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if generation > observed {
+		return false, "waiting for the controller to observe the latest spec", nil
+	}
+
+	switch obj.GetKind() {
+	case "Deployment":
+		if reason := deploymentConditionReason(obj, "Progressing"); reason == "ProgressDeadlineExceeded" {
+			return false, "", fmt.Errorf("deployment %s exceeded its progress deadline", obj.GetName())
+		}
+		replicas := nestedInt64Default(obj, 1, "spec", "replicas")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		total, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+		switch {
+		case updated < replicas:
+			return false, fmt.Sprintf("%d of %d updated replicas", updated, replicas), nil
+		case total > updated:
+			return false, fmt.Sprintf("%d old replicas pending termination", total-updated), nil
+		case available < updated:
+			return false, fmt.Sprintf("%d of %d updated replicas available", available, updated), nil
+		}
+		return true, fmt.Sprintf("%d/%d replicas updated and available", available, replicas), nil
+	case "StatefulSet":
+		strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+		if strategy == "OnDelete" {
+			return true, "updateStrategy is OnDelete, pods are only updated when deleted", nil
+		}
+		replicas := nestedInt64Default(obj, 1, "spec", "replicas")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		if ready < replicas {
+			return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas), nil
+		}
+		partition, _, _ := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		if partition > 0 {
+			if updated < replicas-partition {
+				return false, fmt.Sprintf("%d of %d replicas above partition updated", updated, replicas-partition), nil
+			}
+			return true, fmt.Sprintf("partitioned rollout complete: %d replicas updated", updated), nil
+		}
+		currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		if currentRevision != updateRevision {
+			return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas), nil
+		}
+		return true, fmt.Sprintf("%d/%d replicas updated and ready", ready, replicas), nil
+	case "DaemonSet":
+		strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+		if strategy == "OnDelete" {
+			return true, "updateStrategy is OnDelete, pods are only updated when deleted", nil
+		}
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberAvailable")
+		if updated < desired {
+			return false, fmt.Sprintf("%d of %d updated pods scheduled", updated, desired), nil
+		}
+		if available < desired {
+			return false, fmt.Sprintf("%d of %d updated pods available", available, desired), nil
+		}
+		return true, fmt.Sprintf("%d/%d pods updated and available", available, desired), nil
+	}
+	return false, "", fmt.Errorf("rollout status is not supported for kind %s", obj.GetKind())
+}
+
+// WaitForRollout 等待名为name的工作负载滚动更新完成、出错、超过timeout或ctx结束
+// 返回最后一次的完成状态和进度说明，超时不视为错误
+func WaitForRollout(
+	ctx context.Context,
+	timeout time.Duration,
+	resource dynamic.ResourceInterface,
+	name string,
+) (bool, string, error) {
+	outcome, err := WaitFor(ctx, timeout, resource, name, rolloutCondition)
+	return outcome.Met, outcome.Message, err
+}
+
+// deploymentConditionReason 返回指定类型的状态条件的原因
+func deploymentConditionReason(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		reason, _ := condition["reason"].(string)
+		return reason
+	}
+	return ""
+}
+
+// nestedInt64Default 读取整数字段，字段不存在时返回默认值
+func nestedInt64Default(obj *unstructured.Unstructured, fallback int64, fields ...string) int64 {
+	value, found, _ := unstructured.NestedInt64(obj.Object, fields...)
+	if !found {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// 选择器参数名称
+const (
+	LabelSelectorArgument = "labelSelector"
+	FieldSelectorArgument = "fieldSelector"
+)
+
+// 无法解析的选择器在错误结果中附带的有效示例
+var (
+	labelSelectorExamples = []string{"app=nginx,tier in (frontend,backend)", "environment notin (dev,test),!canary"}
+	fieldSelectorExamples = []string{"status.phase=Running", "metadata.name=my-pod,spec.nodeName!="}
+)
+
+var (
+	// selectorOperatorPattern 匹配两侧带空白的比较运算符
+	selectorOperatorPattern = regexp.MustCompile(`\s*(==|!=|=)\s*`)
+	// selectorConjunctionPattern 匹配模型常写成的逻辑与：&&、and和分号
+	selectorConjunctionPattern = regexp.MustCompile(`(?i)\s*(&&|;|\s+and\s+)\s*`)
+	// selectorColonPattern 匹配YAML风格的"key: value"，标签键值不能包含冒号
+	selectorColonPattern = regexp.MustCompile(`\s*:\s*`)
+	// selectorNotInPattern 匹配分开书写的"not in"
+	selectorNotInPattern = regexp.MustCompile(`(?i)\s+not\s+in\s+`)
+	// selectorSetPattern 匹配缺少括号的集合条件，如"env in prod"或"env not in dev"
+	selectorSetPattern = regexp.MustCompile(`(?i)^([^\s()!=]+)\s+(in|notin)\s+([^()]+)$`)
+	// fieldSingleValueSetPattern 匹配只有一个值的集合条件，字段选择器可改写为等值条件
+	fieldSingleValueSetPattern = regexp.MustCompile(`(?i)^(\S+)\s+in\s+\(?\s*([^\s,()]+)\s*\)?$`)
+	// selectorBareValuePattern 匹配不带运算符的单个值，跟在缺少括号的集合条件之后时视为集合的其余元素
+	selectorBareValuePattern = regexp.MustCompile(`^[A-Za-z0-9][-A-Za-z0-9_.]*$`)
+)
+
+// SelectorError 选择器无法解析，Suggestion为按常见错误修正后的形式，与原值相同时为空
+type SelectorError struct {
+	Argument   string
+	Selector   string
+	Suggestion string
+	Err        error
+}
+
+func (e *SelectorError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Argument, e.Selector, e.Err)
+}
+
+func (e *SelectorError) Unwrap() error {
+	return e.Err
+}
+
+// SelectorArgument 读取labelSelector或fieldSelector参数并通过ParseSelectorFriendly校验，未提供时返回空字符串
+func SelectorArgument(arguments map[string]interface{}, key string) (string, error) {
+	raw, _ := arguments[key].(string)
+	return ParseSelectorFriendly(key, raw)
+}
+
+// ParseSelectorFriendly 解析argument（labelSelector或fieldSelector）的值并返回规范形式。
+// 解析失败时修正模型常见的写法（运算符两侧的空白、"=="、"&&"、"key: value"、集合条件缺少括号等）后重试，
+// 仍然失败时返回*SelectorError，其中包含修正后的猜测，可通过NewSelectorErrorResult转换为错误结果
+func ParseSelectorFriendly(argument, selector string) (string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return "", nil
+	}
+	parse := parseLabelSelectorString
+	normalize := normalizeLabelSelector
+	if argument == FieldSelectorArgument {
+		parse = parseFieldSelectorString
+		normalize = normalizeFieldSelector
+	}
+
+	parsed, err := parse(selector)
+	// 字段选择器的解析器保留运算符两侧的空白，"status.phase = Running"可以解析但会被API Server拒绝，因此总是修正后再解析
+	if err == nil && argument != FieldSelectorArgument {
+		return parsed, nil
+	}
+	suggestion := normalize(selector)
+	if suggestion == selector && err == nil {
+		return parsed, nil
+	}
+	retried, retryErr := parse(suggestion)
+	if retryErr == nil {
+		return retried, nil
+	}
+	if err == nil {
+		err = retryErr
+	}
+	if suggestion == selector {
+		suggestion = ""
+	}
+	return "", &SelectorError{Argument: argument, Selector: selector, Suggestion: suggestion, Err: err}
+}
+
+// NewSelectorErrorResult 将选择器错误转换为附带修正猜测和有效示例的错误结果
+func NewSelectorErrorResult(err error) *mcp.CallToolResult {
+	var selectorErr *SelectorError
+	if !errors.As(err, &selectorErr) {
+		return NewErrorToolResult(err.Error())
+	}
+	details := models.SelectorErrorDetails{Suggestion: selectorErr.Suggestion, Examples: labelSelectorExamples}
+	hint := "Use key=value, key!=value, key in (a,b), key notin (a,b), key or !key, and separate requirements with commas."
+	if selectorErr.Argument == FieldSelectorArgument {
+		details.Examples = fieldSelectorExamples
+		hint = "Use field=value or field!=value separated by commas; field selectors do not support set-based operators."
+	}
+	return NewToolErrorResult(models.ToolError{
+		Code:    ErrorCodeInvalid,
+		Message: selectorErr.Error(),
+		Hint:    hint,
+		Details: details,
+	})
+}
+
+func parseLabelSelectorString(selector string) (string, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+func parseFieldSelectorString(selector string) (string, error) {
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+// normalizeLabelSelector 修正标签选择器的常见写法错误，不保证结果可以解析
+func normalizeLabelSelector(selector string) string {
+	selector = strings.TrimSpace(selector)
+	// {"app":"nginx"}形式的JSON对象按等值条件处理
+	if strings.HasPrefix(selector, "{") {
+		var set map[string]string
+		if err := json.Unmarshal([]byte(selector), &set); err == nil {
+			return labels.SelectorFromSet(set).String()
+		}
+	}
+	selector = strings.NewReplacer(`"`, "", "'", "", "`", "").Replace(selector)
+	selector = selectorConjunctionPattern.ReplaceAllString(selector, ",")
+	selector = selectorColonPattern.ReplaceAllString(selector, "=")
+	selector = selectorNotInPattern.ReplaceAllString(selector, " notin ")
+
+	var requirements []string
+	// openSet 上一个条件是缺少括号的集合条件时，其后不带运算符的值仍属于该集合
+	openSet := false
+	for _, term := range splitSelectorTerms(selector) {
+		if match := selectorSetPattern.FindStringSubmatch(term); match != nil {
+			operator := "in"
+			if !strings.EqualFold(match[2], "in") {
+				operator = "notin"
+			}
+			values := strings.Join(strings.Fields(match[3]), ",")
+			requirements = append(requirements, fmt.Sprintf("%s %s (%s", match[1], operator, values))
+			openSet = true
+			continue
+		}
+		if openSet && selectorBareValuePattern.MatchString(term) {
+			requirements[len(requirements)-1] += "," + term
+			continue
+		}
+		if openSet {
+			requirements[len(requirements)-1] += ")"
+			openSet = false
+		}
+		requirements = append(requirements, normalizeEqualityTerm(term))
+	}
+	if openSet {
+		requirements[len(requirements)-1] += ")"
+	}
+	return strings.Join(requirements, ",")
+}
+
+// normalizeFieldSelector 修正字段选择器的常见写法错误，字段值可能包含冒号（如system:开头的名称），因此不替换冒号
+func normalizeFieldSelector(selector string) string {
+	selector = strings.TrimSpace(selector)
+	selector = strings.NewReplacer(`"`, "", "'", "", "`", "").Replace(selector)
+	selector = selectorConjunctionPattern.ReplaceAllString(selector, ",")
+	var requirements []string
+	for _, term := range strings.Split(selector, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			term = fieldSingleValueSetPattern.ReplaceAllString(term, "$1=$2")
+			requirements = append(requirements, normalizeEqualityTerm(term))
+		}
+	}
+	return strings.Join(requirements, ",")
+}
+
+// normalizeEqualityTerm 去除运算符两侧的空白，并将"=="改写为"="
+func normalizeEqualityTerm(term string) string {
+	return selectorOperatorPattern.ReplaceAllStringFunc(term, func(operator string) string {
+		operator = strings.TrimSpace(operator)
+		if operator == "==" {
+			return "="
+		}
+		return operator
+	})
+}
+
+// splitSelectorTerms 按括号外的逗号拆分选择器，去除空白项
+func splitSelectorTerms(selector string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	terms = append(terms, selector[start:])
+
+	result := terms[:0]
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			result = append(result, term)
+		}
+	}
+	return result
+}
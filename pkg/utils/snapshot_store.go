@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSnapshotTTL 未指定时快照的保留时间
+	DefaultSnapshotTTL = time.Hour
+	// MaxSnapshotTTL 快照的最长保留时间
+	MaxSnapshotTTL = 24 * time.Hour
+	// MaxSnapshotObjects 单个快照最多记录的对象数量
+	MaxSnapshotObjects = 5000
+	// MaxSnapshotContentBytes 单个快照为字段级差异保存的对象内容的最大字节数，超过时只保存哈希
+	MaxSnapshotContentBytes = 8 * 1024 * 1024
+	// maxSnapshots 同时保留的最大快照数量，超过时移除最旧的快照
+	maxSnapshots = 16
+)
+
+// SnapshotObject 快照中的一个对象
+type SnapshotObject struct {
+	Kind       string
+	APIVersion string
+	Name       string
+	// Hash 清理服务端字段和status后的对象内容的SHA256
+	Hash string
+	// Content 清理后的对象内容，快照超过内容大小上限时为nil
+	Content map[string]interface{}
+}
+
+// Snapshot 命名空间在某一时刻的对象哈希
+type Snapshot struct {
+	ID        string
+	Namespace string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// Objects 按"group/Kind/name"索引的对象
+	Objects map[string]SnapshotObject
+	// ContentStored 是否保存了对象内容，未保存时比较只能报告哈希变化
+	ContentStored bool
+	// IncludeKinds、ExcludeKinds、IncludeOwned 创建快照时的扫描条件，比较时按相同条件重新扫描
+	IncludeKinds string
+	ExcludeKinds string
+	IncludeOwned bool
+}
+
+// SnapshotStore 并发安全的内存快照存储，过期或超过数量上限的快照会被移除
+type SnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]*Snapshot
+	now       func() time.Time
+}
+
+var snapshots = NewSnapshotStore()
+
+// Snapshots 返回全局快照存储
+func Snapshots() *SnapshotStore {
+	return snapshots
+}
+
+// NewSnapshotStore 创建快照存储
+func NewSnapshotStore() *SnapshotStore {
+	return &SnapshotStore{
+		snapshots: make(map[string]*Snapshot),
+		now:       time.Now,
+	}
+}
+
+// Save 保存快照并分配ID，返回因超过数量上限被移除的快照ID
+func (s *SnapshotStore) Save(snapshot *Snapshot) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	snapshot.ID = "snap-" + NewRequestID()
+	s.snapshots[snapshot.ID] = snapshot
+
+	var evicted []string
+	for len(s.snapshots) > maxSnapshots {
+		oldest := ""
+		for id, item := range s.snapshots {
+			if id != snapshot.ID && (oldest == "" || item.CreatedAt.Before(s.snapshots[oldest].CreatedAt)) {
+				oldest = id
+			}
+		}
+		delete(s.snapshots, oldest)
+		evicted = append(evicted, oldest)
+	}
+	return evicted
+}
+
+// Get 返回未过期的快照
+func (s *SnapshotStore) Get(id string) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	snapshot, ok := s.snapshots[id]
+	if !ok {
+		return nil, fmt.Errorf("snapshot %s not found or expired", id)
+	}
+	return snapshot, nil
+}
+
+// IDs 返回未过期的快照ID，按创建时间排序
+func (s *SnapshotStore) IDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeExpired()
+	ids := make([]string, 0, len(s.snapshots))
+	for id := range s.snapshots {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return s.snapshots[ids[i]].CreatedAt.Before(s.snapshots[ids[j]].CreatedAt)
+	})
+	return ids
+}
+
+// removeExpired 移除已过期的快照；调用方需持有锁
+func (s *SnapshotStore) removeExpired() {
+	now := s.now()
+	for id, snapshot := range s.snapshots {
+		if !now.Before(snapshot.ExpiresAt) {
+			delete(s.snapshots, id)
+		}
+	}
+}
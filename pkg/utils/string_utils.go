@@ -6,6 +6,28 @@ import (
 	"strings"
 )
 
+// MaxMessageLength 事件消息、日志摘要等文本在响应中保留的最大长度
+const MaxMessageLength = 1024
+
+// TruncateString 将超过maxLen的字符串截断并以"..."结尾
+func TruncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// SplitCommaList 拆分逗号分隔的列表并去除空白项
+func SplitCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // ExtractErrorMessage 从日志行中提取错误消息
 func ExtractErrorMessage(logLine string) string {
 	// 尝试提取: error: message 或 ERROR: message 格式
@@ -14,11 +36,7 @@ func ExtractErrorMessage(logLine string) string {
 
 	if len(matches) > 1 {
 		// 清理和限制长度
-		msg := strings.TrimSpace(matches[1])
-		if len(msg) > 1024 {
-			msg = msg[:1021] + "..."
-		}
-		return msg
+		return TruncateString(strings.TrimSpace(matches[1]), MaxMessageLength)
 	}
 
 	// 如果没有清晰的错误格式，返回截断的一部分日志
@@ -27,10 +45,7 @@ func ExtractErrorMessage(logLine string) string {
 	timestampRegex := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z\s*`)
 	logPart = timestampRegex.ReplaceAllString(logPart, "")
 
-	if len(logPart) > 1024 {
-		logPart = logPart[:1021] + "..."
-	}
-	return logPart
+	return TruncateString(logPart, MaxMessageLength)
 }
 
 // GetContextHash 为上下文生成一个简单的哈希值
@@ -2,14 +2,81 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/dustin/go-humanize/english"
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
 )
 
-// FormatTimeAgo 将时间格式化为人类可读的"多久以前"形式，使用中文
+// TimeZoneArg 指定渲染时间所用时区的参数
+const TimeZoneArg = "timezone"
+
+// WithTimeZone 返回返回时间的工具共用的timezone参数定义
+func WithTimeZone() mcp.ToolOption {
+	return mcp.WithString(TimeZoneArg,
+		mcp.Description("返回的时间所用的IANA时区，例如'Asia/Shanghai'、'America/New_York'。结构化结果中的时间均为带时区偏移的RFC3339格式，text格式按此时区显示绝对时间。默认为UTC。"),
+	)
+}
+
+// ParseTimeZone 从请求参数中读取时区，未指定时返回UTC
+func ParseTimeZone(request mcp.CallToolRequest) (*time.Location, error) {
+	name, _ := request.GetArguments()[TimeZoneArg].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q, must be an IANA name such as UTC or Europe/Berlin", name)
+	}
+	return location, nil
+}
+
+// NewTimeZoneErrorResult 创建时区参数无效的错误结果
+func NewTimeZoneErrorResult(err error) *mcp.CallToolResult {
+	return NewToolErrorResult(models.ToolError{
+		Code:    ErrorCodeInvalid,
+		Message: err.Error(),
+	})
+}
+
+// EventLastSeen 返回事件最近一次发生的时间。events.k8s.io/v1写入的事件没有lastTimestamp，
+// 依次使用series、eventTime和firstTimestamp，避免零值时间显示为几十年前
+func EventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// EventFirstSeen 返回事件第一次发生的时间，没有firstTimestamp时使用eventTime
+func EventFirstSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// FormatTimeAgo 将时间格式化为人类可读的"多久以前"形式，使用中文；零值时间返回空字符串
 func FormatTimeAgo(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
 	duration := time.Since(t)
 
 	if duration < time.Minute {
@@ -27,8 +94,11 @@ func FormatTimeAgo(t time.Time) string {
 	return fmt.Sprintf("%d天前", days)
 }
 
-// FormatTimeAgoEN 将时间格式化为人类可读的"多久以前"形式，使用英文
+// FormatTimeAgoEN 将时间格式化为人类可读的"多久以前"形式，使用英文；零值时间返回空字符串
 func FormatTimeAgoEN(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
 	return humanize.Time(t)
 }
 
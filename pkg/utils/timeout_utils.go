@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// 工具调用超时相关的参数名
+const (
+	// TimeoutSecondsArg 调用方指定本次调用超时时间的参数
+	TimeoutSecondsArg = "timeoutSeconds"
+	// maxDurationSecondsArg 长时间运行的工具（如WATCH_RESOURCES）声明的运行时长参数
+	maxDurationSecondsArg = "maxDurationSeconds"
+	// maxWaitSecondsArg 等待条件出现的工具（如NOTIFY_ON_EVENT）声明的最长等待时间参数
+	maxWaitSecondsArg = "maxWaitSeconds"
+	// waitTimeoutSecondsArg 带wait开关的变更工具（如SET_IMAGE）声明的等待时间参数，仅在wait=true时生效
+	waitTimeoutSecondsArg = "waitTimeoutSeconds"
+	// timedOutMetaKey 标记结果已由处理程序按超时处理的_meta字段
+	timedOutMetaKey = "timedOut"
+)
+
+// toolTimeoutKey 在上下文中保存本次调用超时时间的键
+type toolTimeoutKey struct{}
+
+// WithTimeoutSeconds 返回所有工具共用的timeoutSeconds参数定义
+func WithTimeoutSeconds() mcp.ToolOption {
+	return mcp.WithNumber(TimeoutSecondsArg,
+		mcp.Description("本次调用的超时时间（秒）。未指定时使用服务器默认值，超过服务器上限时按上限处理。超时后返回结构化的超时错误，支持的工具会附带已获取的部分结果。"),
+	)
+}
+
+// ParseTimeoutSeconds 计算本次调用的超时时间
+// 未指定timeoutSeconds时使用默认值；声明了maxDurationSeconds、maxWaitSeconds或wait=true时的waitTimeoutSeconds的工具
+// 至少获得该时长；结果不超过上限
+func ParseTimeoutSeconds(request mcp.CallToolRequest, defaultSeconds, maxSeconds int) time.Duration {
+	arguments := request.GetArguments()
+	seconds := defaultSeconds
+	if value, ok := arguments[TimeoutSecondsArg].(float64); ok && value > 0 {
+		seconds = int(value)
+	}
+	durationArgs := []string{maxDurationSecondsArg, maxWaitSecondsArg}
+	if wait, _ := arguments["wait"].(bool); wait {
+		durationArgs = append(durationArgs, waitTimeoutSecondsArg)
+	}
+	for _, arg := range durationArgs {
+		if duration, ok := arguments[arg].(float64); ok && int(duration) > seconds {
+			seconds = int(duration)
+		}
+	}
+	if maxSeconds > 0 && seconds > maxSeconds {
+		seconds = maxSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ContextWithToolTimeout 派生带有超时的上下文，并记录超时时间供超时结果使用
+func ContextWithToolTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, toolTimeoutKey{}, timeout)
+	return context.WithTimeout(ctx, timeout)
+}
+
+// ToolTimeout 返回上下文中记录的本次调用超时时间，未设置时返回0
+func ToolTimeout(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(toolTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// NewTimeoutResult 创建超时错误结果，partial为超时前已获取的部分结果，可以为nil
+func NewTimeoutResult(ctx context.Context, partial any) *mcp.CallToolResult {
+	message := "operation timed out"
+	if timeout := ToolTimeout(ctx); timeout > 0 {
+		message = fmt.Sprintf("operation timed out after %s", timeout)
+	}
+	if partial != nil {
+		message += "; partial results are included in details"
+	}
+
+	result := NewToolErrorResult(models.ToolError{
+		Code:    ErrorCodeTimeout,
+		Message: message,
+		Hint:    fmt.Sprintf("Narrow the request with a namespace, kind or selector, or raise %s up to the server limit.", TimeoutSecondsArg),
+		Details: partial,
+	})
+	result.Meta = &mcp.Meta{AdditionalFields: map[string]any{timedOutMetaKey: true}}
+	return result
+}
+
+// IsTimeoutResult 判断结果是否已由处理程序按超时处理
+func IsTimeoutResult(result *mcp.CallToolResult) bool {
+	if result == nil || result.Meta == nil {
+		return false
+	}
+	timedOut, _ := result.Meta.AdditionalFields[timedOutMetaKey].(bool)
+	return timedOut
+}
@@ -0,0 +1,350 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/hsn0918/kubernetes-mcp/pkg/models"
+)
+
+// DefaultMaxResultBytes 未配置时单个工具响应的最大字节数
+const DefaultMaxResultBytes = 100 * 1024
+
+// 文本内容的截断方式
+const (
+	// ContentText 普通文本和YAML，保留开头并在末尾标记省略的行数
+	ContentText = "text"
+	// ContentLogs 日志，保留末尾并在开头标记省略的行数
+	ContentLogs = "logs"
+)
+
+const (
+	// truncationHint 截断说明中提示调用方缩小查询范围
+	truncationHint = "the result exceeded the server output limit and was truncated; narrow the query (namespace, labelSelector, limit, tailLines, sinceSeconds) to see the omitted content"
+	// truncationReserve 为截断说明和省略标记预留的字节数
+	truncationReserve = 512
+	// minTruncatedString 短于该长度的字符串不参与截断
+	minTruncatedString = 256
+	// maxTruncationPasses 结构化截断的最大轮数
+	maxTruncationPasses = 64
+	// maxRenderAttempts 渲染后仍超过上限时按比例缩小目标重新截断的次数
+	maxRenderAttempts = 3
+)
+
+// logFields 按日志方式截断（保留末尾）的字段名
+var logFields = map[string]bool{
+	"logs":         true,
+	"log":          true,
+	"previousLogs": true,
+	"tail":         true,
+}
+
+// maxResultBytes 单个工具响应的最大字节数，可在运行时重新加载
+var maxResultBytes atomic.Int64
+
+func init() {
+	maxResultBytes.Store(DefaultMaxResultBytes)
+}
+
+// SetMaxResultBytes 设置单个工具响应的最大字节数，0表示不限制
+func SetMaxResultBytes(limit int) {
+	maxResultBytes.Store(int64(limit))
+}
+
+// MaxResultBytes 返回单个工具响应的最大字节数
+func MaxResultBytes() int {
+	return int(maxResultBytes.Load())
+}
+
+// TruncateText 将超过limit的文本按内容类型截断：ContentText保留开头，ContentLogs保留末尾
+// 未截断时返回的说明为nil
+func TruncateText(text, content string, limit int) (string, *models.ResultTruncation) {
+	if limit <= 0 || len(text) <= limit {
+		return text, nil
+	}
+	truncated, field := truncateString(text, content, limit-truncationReserve)
+	return truncated, &models.ResultTruncation{
+		Truncated:     true,
+		OriginalBytes: len(text),
+		LimitBytes:    limit,
+		Fields:        []models.TruncatedField{field},
+		Hint:          truncationHint,
+	}
+}
+
+// TruncateResult 将序列化后超过limit的响应模型转换为通用结构并按内容截断：
+// 列表丢弃末尾的元素（日志列表丢弃开头的元素），YAML等文本保留开头，日志文本保留末尾，
+// 截断说明写入顶层的truncation字段。未截断时原样返回v且说明为nil
+func TruncateResult(v any, limit int) (any, *models.ResultTruncation, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, fmt.Errorf("JSON序列化失败: %w", err)
+	}
+	if limit <= 0 || len(data) <= limit {
+		return v, nil, nil
+	}
+
+	var generic any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, nil, fmt.Errorf("JSON反序列化失败: %w", err)
+	}
+
+	info := &models.ResultTruncation{
+		Truncated:     true,
+		OriginalBytes: len(data),
+		LimitBytes:    limit,
+		Hint:          truncationHint,
+	}
+	budget := limit - truncationReserve
+	for pass := 0; pass < maxTruncationPasses; pass++ {
+		var target *truncationTarget
+		size := findTruncationTarget(generic, "", "", nil, &target)
+		if size <= budget || target == nil {
+			break
+		}
+		info.Fields = mergeTruncatedField(info.Fields, target.shrink(size-budget))
+	}
+
+	if object, ok := generic.(map[string]any); ok {
+		object["truncation"] = info
+		return object, info, nil
+	}
+	return map[string]any{"items": generic, "truncation": info}, info, nil
+}
+
+// truncateResultText 在渲染结果超过全局上限时截断，format为text且模型自定义了文本格式时按文本截断
+func truncateResultText(v any, format, text string) (string, error) {
+	limit := MaxResultBytes()
+	if limit <= 0 || len(text) <= limit {
+		return text, nil
+	}
+
+	if _, ok := v.(TextRenderer); ok && format == FormatText {
+		truncated, info := TruncateText(text, ContentText, limit)
+		return truncated + "\n" + info.Hint + "\n", nil
+	}
+
+	// JSON与YAML的大小不同，渲染后仍超过上限时按比例缩小目标
+	originalBytes := len(text)
+	target := limit
+	for attempt := 0; attempt < maxRenderAttempts; attempt++ {
+		truncated, info, err := TruncateResult(v, target)
+		if err != nil {
+			return "", err
+		}
+		if info != nil {
+			info.OriginalBytes = originalBytes
+			info.LimitBytes = limit
+		}
+		text, err = Render(truncated, format)
+		if err != nil {
+			return "", err
+		}
+		if len(text) <= limit {
+			break
+		}
+		target = target * limit / len(text)
+	}
+	return text, nil
+}
+
+// truncationTarget 一轮截断中体积最大的列表或字符串
+type truncationTarget struct {
+	path  string
+	field string
+	size  int
+	value any
+	set   func(value any)
+}
+
+// findTruncationTarget 返回节点序列化后的近似大小，并在best中记录体积最大的可截断节点
+// field为节点所属的字段名，列表中的元素继承列表的字段名
+func findTruncationTarget(node any, path, field string, set func(value any), best **truncationTarget) int {
+	consider := func(size int) {
+		if set != nil && (*best == nil || size > (*best).size) {
+			*best = &truncationTarget{path: path, field: field, size: size, value: node, set: set}
+		}
+	}
+
+	switch value := node.(type) {
+	case map[string]any:
+		size := 2
+		for key, child := range value {
+			size += len(key) + 4 + findTruncationTarget(child, joinTruncationPath(path, key), key, func(v any) {
+				value[key] = v
+			}, best)
+		}
+		return size
+	case []any:
+		size := 2
+		for i, child := range value {
+			size += 1 + findTruncationTarget(child, fmt.Sprintf("%s[%d]", path, i), field, func(v any) {
+				value[i] = v
+			}, best)
+		}
+		// 只有一个元素的列表截断其元素
+		if len(value) > 1 {
+			consider(size)
+		}
+		return size
+	case string:
+		// 按转义后的长度计算，换行等字符在JSON中占两个字节
+		data, _ := json.Marshal(value)
+		size := len(data)
+		if len(value) > minTruncatedString {
+			consider(size)
+		}
+		return size
+	default:
+		data, _ := json.Marshal(value)
+		return len(data)
+	}
+}
+
+// shrink 将节点缩小至少excess字节，返回截断记录
+func (t *truncationTarget) shrink(excess int) models.TruncatedField {
+	field := models.TruncatedField{Path: t.path}
+	switch value := t.value.(type) {
+	case []any:
+		// 日志列表保留末尾，其余列表保留开头，至少保留一个元素
+		keepTail := logFields[t.field]
+		removed, drop := 0, 0
+		for drop < len(value)-1 && removed < excess {
+			index := len(value) - 1 - drop
+			if keepTail {
+				index = drop
+			}
+			data, _ := json.Marshal(value[index])
+			removed += len(data) + 1
+			drop++
+		}
+		if keepTail {
+			t.set(value[drop:])
+		} else {
+			t.set(value[:len(value)-drop])
+		}
+		field.OmittedItems = drop
+	case string:
+		content := ContentText
+		if logFields[t.field] {
+			content = ContentLogs
+		}
+		// excess按转义后的长度计算，换算为原始字节数
+		truncated, stringField := truncateString(value, content, len(value)-excess*len(value)/t.size)
+		t.set(truncated)
+		// truncateString返回累计的省略数量，只记录本轮新增的部分
+		_, previous := stripTruncationMarker(value, content)
+		field.OmittedLines = stringField.OmittedLines - previous.OmittedLines
+		field.OmittedBytes = stringField.OmittedBytes - previous.OmittedBytes
+	}
+	return field
+}
+
+// truncateString 将字符串缩小到budget字节以内，按行截断；单行超过budget时按字节截断
+func truncateString(text, content string, budget int) (string, models.TruncatedField) {
+	var field models.TruncatedField
+	if budget < minTruncatedString {
+		budget = minTruncatedString
+	}
+	if len(text) <= budget {
+		return text, field
+	}
+
+	// 多轮截断同一字符串时去掉上一轮的省略标记，累计省略数量
+	text, field = stripTruncationMarker(text, content)
+
+	lines := strings.SplitAfter(text, "\n")
+	kept := 0
+	size := 0
+	for kept < len(lines) {
+		line := lines[kept]
+		if content == ContentLogs {
+			line = lines[len(lines)-1-kept]
+		}
+		if size+len(line) > budget {
+			break
+		}
+		size += len(line)
+		kept++
+	}
+
+	if kept == 0 {
+		// 单行过长，按字节截断
+		if content == ContentLogs {
+			cut := strings.ToValidUTF8(text[len(text)-budget:], "")
+			field.OmittedBytes += len(text) - len(cut)
+			return fmt.Sprintf("… %d earlier bytes omitted\n", field.OmittedBytes) + cut, field
+		}
+		cut := strings.ToValidUTF8(text[:budget], "")
+		field.OmittedBytes += len(text) - len(cut)
+		return cut + fmt.Sprintf("\n… %d more bytes\n", field.OmittedBytes), field
+	}
+
+	field.OmittedLines += len(lines) - kept
+	if content == ContentLogs {
+		return fmt.Sprintf("… %d earlier lines omitted\n", field.OmittedLines) + strings.Join(lines[len(lines)-kept:], ""), field
+	}
+	head := strings.Join(lines[:kept], "")
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return head + fmt.Sprintf("… %d more lines\n", field.OmittedLines), field
+}
+
+// stripTruncationMarker 去掉truncateString添加的省略标记，返回标记中记录的省略数量
+func stripTruncationMarker(text, content string) (string, models.TruncatedField) {
+	var field models.TruncatedField
+	if content == ContentLogs {
+		first, rest, ok := strings.Cut(text, "\n")
+		if !ok {
+			return text, field
+		}
+		if _, err := fmt.Sscanf(first, "… %d earlier lines omitted", &field.OmittedLines); err == nil {
+			return rest, field
+		}
+		if _, err := fmt.Sscanf(first, "… %d earlier bytes omitted", &field.OmittedBytes); err == nil {
+			return rest, field
+		}
+		return text, field
+	}
+
+	trimmed := strings.TrimSuffix(text, "\n")
+	index := strings.LastIndex(trimmed, "\n")
+	if index < 0 {
+		return text, field
+	}
+	last := trimmed[index+1:]
+	if _, err := fmt.Sscanf(last, "… %d more lines", &field.OmittedLines); err == nil {
+		return trimmed[:index+1], field
+	}
+	if _, err := fmt.Sscanf(last, "… %d more bytes", &field.OmittedBytes); err == nil {
+		return trimmed[:index], field
+	}
+	return text, field
+}
+
+// mergeTruncatedField 合并同一路径多轮截断的记录
+func mergeTruncatedField(fields []models.TruncatedField, field models.TruncatedField) []models.TruncatedField {
+	for i := range fields {
+		if fields[i].Path == field.Path {
+			fields[i].OmittedItems += field.OmittedItems
+			fields[i].OmittedLines += field.OmittedLines
+			fields[i].OmittedBytes += field.OmittedBytes
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// joinTruncationPath 拼接字段路径
+func joinTruncationPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,297 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// waitResyncInterval 等待条件时在watch之外重新获取对象的间隔，watch中断或漏掉事件时仍能发现变化
+const waitResyncInterval = 5 * time.Second
+
+// 内置的等待条件
+const (
+	WaitConditionReady       = "Ready"
+	WaitConditionAvailable   = "Available"
+	WaitConditionComplete    = "Complete"
+	WaitConditionEstablished = "Established"
+	WaitConditionDeleted     = "Deleted"
+	WaitConditionRollout     = "Rollout"
+)
+
+// WaitCondition 判断对象是否满足等待条件，obj为nil表示对象不存在
+// 返回是否满足和当前状态的说明；条件不可能再满足时（如Job失败）返回错误
+type WaitCondition func(obj *unstructured.Unstructured) (bool, string, error)
+
+// WaitOutcome 等待结束时的状态
+type WaitOutcome struct {
+	Met bool
+	// Message 最后一次判断的说明，未满足时即为阻塞条件
+	Message string
+	// Object 最后一次观察到的对象，对象不存在时为nil
+	Object   *unstructured.Unstructured
+	TimedOut bool
+	// Failed 条件判断返回了错误，条件已不可能满足；为false时返回的错误来自获取对象
+	Failed bool
+}
+
+// WaitFor 等待名为name的对象满足condition，直到超过timeout或ctx结束（超时不视为错误）
+// 通过watch及时发现变化，并定期重新获取对象作为watch中断时的后备
+func WaitFor(
+	ctx context.Context,
+	timeout time.Duration,
+	resource dynamic.ResourceInterface,
+	name string,
+	condition WaitCondition,
+) (*WaitOutcome, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(waitResyncInterval)
+	defer ticker.Stop()
+
+	// 客户端请求了进度时，每次判断后汇报当前状态，例如"3 of 5 updated replicas"，结束时汇报结果
+	progress := ProgressFromContext(ctx)
+	outcome := &WaitOutcome{}
+	defer func() { progress.Done(ctx, outcome.summary()) }()
+	evaluate := func(obj *unstructured.Unstructured) (bool, error) {
+		met, message, err := condition(obj)
+		outcome.Met, outcome.Message, outcome.Object = met, message, obj
+		if err != nil {
+			outcome.Failed, outcome.Message = true, err.Error()
+		}
+		progress.Report(ctx, outcome.Message)
+		return met || err != nil, err
+	}
+
+	var watcher watch.Interface
+	defer func() {
+		if watcher != nil {
+			watcher.Stop()
+		}
+	}()
+	for {
+		obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			obj, err = nil, nil
+		case err != nil && !IsTransientError(err) && ctx.Err() == nil:
+			return outcome, err
+		}
+		if err == nil {
+			if done, err := evaluate(obj); done {
+				return outcome, err
+			}
+		}
+
+		// watch建立失败（如没有watch权限）时只依靠定期获取
+		if watcher == nil {
+			listOptions := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+			if obj != nil {
+				listOptions.ResourceVersion = obj.GetResourceVersion()
+			}
+			watcher, _ = resource.Watch(ctx, listOptions)
+		}
+		var events <-chan watch.Event
+		if watcher != nil {
+			events = watcher.ResultChan()
+		}
+
+	receive:
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok || event.Type == watch.Error {
+					watcher.Stop()
+					watcher = nil
+					break receive
+				}
+				current, _ := event.Object.(*unstructured.Unstructured)
+				if event.Type == watch.Deleted {
+					current = nil
+				} else if current == nil {
+					continue
+				}
+				if done, err := evaluate(current); done {
+					return outcome, err
+				}
+			case <-ticker.C:
+				break receive
+			case <-deadline.C:
+				outcome.TimedOut = true
+				return outcome, nil
+			case <-ctx.Done():
+				outcome.TimedOut = true
+				return outcome, nil
+			}
+		}
+	}
+}
+
+// summary 描述等待结果，用作最后一条进度通知
+func (o *WaitOutcome) summary() string {
+	var state string
+	switch {
+	case o.Met:
+		state = "condition met"
+	case o.Failed:
+		state = "condition can no longer be met"
+	case o.TimedOut:
+		state = "timed out"
+	default:
+		state = "stopped"
+	}
+	if o.Message == "" {
+		return state
+	}
+	return state + ": " + o.Message
+}
+
+// BuiltinWaitCondition 返回内置等待条件：Ready、Available、Complete、Established、Deleted、Rollout
+func BuiltinWaitCondition(name string) (WaitCondition, error) {
+	switch strings.ToLower(name) {
+	case "ready":
+		return readyCondition, nil
+	case "available":
+		return StatusConditionWait(WaitConditionAvailable, "True"), nil
+	case "complete":
+		return completeCondition, nil
+	case "established":
+		return StatusConditionWait(WaitConditionEstablished, "True"), nil
+	case "deleted", "notfound":
+		return deletedCondition, nil
+	case "rollout":
+		return rolloutCondition, nil
+	}
+	return nil, fmt.Errorf("unsupported condition %q, must be one of: %s", name,
+		strings.Join([]string{WaitConditionReady, WaitConditionAvailable, WaitConditionComplete, WaitConditionEstablished, WaitConditionDeleted, WaitConditionRollout}, ", "))
+}
+
+// StatusConditionWait 等待status.conditions中指定类型的条件达到指定状态
+func StatusConditionWait(conditionType, status string) WaitCondition {
+	return func(obj *unstructured.Unstructured) (bool, string, error) {
+		if obj == nil {
+			return false, "object does not exist yet", nil
+		}
+		condition, found := findStatusCondition(obj, conditionType)
+		if !found {
+			return false, fmt.Sprintf("condition %s not reported yet", conditionType), nil
+		}
+		current, _ := condition["status"].(string)
+		message := describeStatusCondition(conditionType, condition)
+		return strings.EqualFold(current, status), message, nil
+	}
+}
+
+// JSONPathWait 等待JSONPath表达式（如"{.status.phase}"或".status.phase"）的结果等于value
+func JSONPathWait(expression, value string) (WaitCondition, error) {
+	expression = strings.TrimSpace(expression)
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	parser := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath expression %q: %w", expression, err)
+	}
+	return func(obj *unstructured.Unstructured) (bool, string, error) {
+		if obj == nil {
+			return false, "object does not exist yet", nil
+		}
+		var buf bytes.Buffer
+		if err := parser.Execute(&buf, obj.Object); err != nil {
+			return false, fmt.Sprintf("%s cannot be evaluated: %v", expression, err), nil
+		}
+		current := strings.TrimSpace(buf.String())
+		return current == value, fmt.Sprintf("%s is %q, waiting for %q", expression, current, value), nil
+	}, nil
+}
+
+// readyCondition 工作负载按滚动更新完成判断，Pod按Ready条件判断，Job按完成判断，其他类型按Ready条件判断
+func readyCondition(obj *unstructured.Unstructured) (bool, string, error) {
+	if obj == nil {
+		return false, "object does not exist yet", nil
+	}
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "DaemonSet":
+		return RolloutStatus(obj)
+	case "Job":
+		return completeCondition(obj)
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase == "Succeeded" || phase == "Failed" {
+			return false, "", fmt.Errorf("pod %s has phase %s and will never become ready", obj.GetName(), phase)
+		}
+	}
+	return StatusConditionWait(WaitConditionReady, "True")(obj)
+}
+
+// completeCondition Job的Complete条件为True时完成，Failed条件为True时返回错误
+func completeCondition(obj *unstructured.Unstructured) (bool, string, error) {
+	if obj == nil {
+		return false, "object does not exist yet", nil
+	}
+	if condition, found := findStatusCondition(obj, "Failed"); found && condition["status"] == "True" {
+		return false, "", fmt.Errorf("%s %s failed: %s", obj.GetKind(), obj.GetName(), describeStatusCondition("Failed", condition))
+	}
+	return StatusConditionWait(WaitConditionComplete, "True")(obj)
+}
+
+// deletedCondition 对象不存在时满足
+func deletedCondition(obj *unstructured.Unstructured) (bool, string, error) {
+	if obj == nil {
+		return true, "object no longer exists", nil
+	}
+	if obj.GetDeletionTimestamp() == nil {
+		return false, "object exists and is not being deleted", nil
+	}
+	if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+		return false, fmt.Sprintf("deletion pending on finalizers: %s", strings.Join(finalizers, ", ")), nil
+	}
+	return false, "deletion in progress", nil
+}
+
+// rolloutCondition 按kubectl rollout status的规则等待滚动更新完成
+func rolloutCondition(obj *unstructured.Unstructured) (bool, string, error) {
+	if obj == nil {
+		return false, "", fmt.Errorf("object was deleted while waiting for the rollout")
+	}
+	return RolloutStatus(obj)
+}
+
+// findStatusCondition 查找status.conditions中指定类型的条件
+func findStatusCondition(obj *unstructured.Unstructured, conditionType string) (map[string]interface{}, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if ok && condition["type"] == conditionType {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+// describeStatusCondition 以"Type=Status (Reason: message)"形式描述状态条件
+func describeStatusCondition(conditionType string, condition map[string]interface{}) string {
+	status, _ := condition["status"].(string)
+	reason, _ := condition["reason"].(string)
+	message, _ := condition["message"].(string)
+	text := fmt.Sprintf("%s=%s", conditionType, status)
+	switch {
+	case reason != "" && message != "":
+		text += fmt.Sprintf(" (%s: %s)", reason, message)
+	case reason != "":
+		text += fmt.Sprintf(" (%s)", reason)
+	case message != "":
+		text += fmt.Sprintf(" (%s)", message)
+	}
+	return text
+}
@@ -0,0 +1,15 @@
+package version
+
+import "runtime"
+
+// 构建信息，发布构建时通过 -ldflags "-X" 注入
+var (
+	Version   = "0.1.0"
+	Commit    = "none"
+	BuildDate = "unknown"
+)
+
+// GoVersion 返回构建使用的 Go 版本
+func GoVersion() string {
+	return runtime.Version()
+}